@@ -0,0 +1,116 @@
+package dongle
+
+import "slices"
+
+// AlgorithmType classifies the algorithms listed by Algorithms.
+type AlgorithmType string
+
+// Algorithm types.
+const (
+	HashAlgorithm      AlgorithmType = "hash"      // Hash functions, keyed as HMAC by Hasher.WithKey
+	CipherAlgorithm    AlgorithmType = "cipher"    // Symmetric and asymmetric encryption
+	SignatureAlgorithm AlgorithmType = "signature" // Digital signatures
+	EncodingAlgorithm  AlgorithmType = "encoding"  // Binary-to-text encodings, not cryptographic
+)
+
+// AlgorithmInfo describes an algorithm supported by this build of dongle.
+type AlgorithmInfo struct {
+	Name   string        // Display name, such as "AES"
+	Type   AlgorithmType // What the algorithm is used for
+	Method string        // Builder method selecting the algorithm, such as "ByAes"
+
+	// KeySizes lists the accepted key sizes in bits. Ciphers with variable
+	// length keys list the smallest and largest size.
+	KeySizes []int
+
+	// DigestSizes lists the supported output sizes in bits of hash functions.
+	DigestSizes []int
+
+	// Modes lists the supported block modes, paddings, ciphertext layouts or
+	// signature variants, as accepted by the cipher or key pair setters.
+	Modes []string
+
+	// SecurityLevel is the approximate classical security strength in bits of
+	// the weakest supported configuration, in the spirit of NIST SP 800-57, or
+	// zero when practical attacks are known or the algorithm is an encoding.
+	SecurityLevel int
+
+	// Deprecated reports whether the algorithm is only kept for compatibility
+	// with existing data and should not be used for new designs.
+	Deprecated bool
+}
+
+// blockModes are the modes of ciphers with 128-bit blocks.
+var blockModes = []string{"CBC", "ECB", "CTR", "GCM", "CFB", "OFB"}
+
+// smallBlockModes are the modes of ciphers with 64-bit blocks, which cannot be
+// used with GCM.
+var smallBlockModes = []string{"CBC", "ECB", "CTR", "CFB", "OFB"}
+
+// algorithms is the inventory returned by Algorithms. It lists one entry for
+// every algorithm and builder, so RSA and SM2 appear both as cipher and as
+// signature.
+var algorithms = []AlgorithmInfo{
+	{Name: "MD2", Type: HashAlgorithm, Method: "ByMd2", DigestSizes: []int{128}, Deprecated: true},
+	{Name: "MD4", Type: HashAlgorithm, Method: "ByMd4", DigestSizes: []int{128}, Deprecated: true},
+	{Name: "MD5", Type: HashAlgorithm, Method: "ByMd5", DigestSizes: []int{128}, Deprecated: true},
+	{Name: "SHA-1", Type: HashAlgorithm, Method: "BySha1", DigestSizes: []int{160}, Deprecated: true},
+	{Name: "SHA-2", Type: HashAlgorithm, Method: "BySha2", DigestSizes: []int{224, 256, 384, 512}, SecurityLevel: 112},
+	{Name: "SHA-3", Type: HashAlgorithm, Method: "BySha3", DigestSizes: []int{224, 256, 384, 512}, SecurityLevel: 112},
+	{Name: "SM3", Type: HashAlgorithm, Method: "BySm3", DigestSizes: []int{256}, SecurityLevel: 128},
+	{Name: "RIPEMD-160", Type: HashAlgorithm, Method: "ByRipemd160", DigestSizes: []int{160}, SecurityLevel: 80},
+	{Name: "BLAKE2b", Type: HashAlgorithm, Method: "ByBlake2b", DigestSizes: []int{256, 384, 512}, SecurityLevel: 128},
+	{Name: "BLAKE2s", Type: HashAlgorithm, Method: "ByBlake2s", DigestSizes: []int{128, 256}, SecurityLevel: 64},
+
+	{Name: "AES", Type: CipherAlgorithm, Method: "ByAes", KeySizes: []int{128, 192, 256}, Modes: blockModes, SecurityLevel: 128},
+	{Name: "SM4", Type: CipherAlgorithm, Method: "BySm4", KeySizes: []int{128}, Modes: blockModes, SecurityLevel: 128},
+	{Name: "Twofish", Type: CipherAlgorithm, Method: "ByTwofish", KeySizes: []int{128, 192, 256}, Modes: blockModes, SecurityLevel: 128},
+	{Name: "DES", Type: CipherAlgorithm, Method: "ByDes", KeySizes: []int{64}, Modes: smallBlockModes, Deprecated: true},
+	{Name: "3DES", Type: CipherAlgorithm, Method: "By3Des", KeySizes: []int{128, 192}, Modes: smallBlockModes, SecurityLevel: 80, Deprecated: true},
+	{Name: "Blowfish", Type: CipherAlgorithm, Method: "ByBlowfish", KeySizes: []int{8, 448}, Modes: smallBlockModes, Deprecated: true},
+	{Name: "TEA", Type: CipherAlgorithm, Method: "ByTea", KeySizes: []int{128}, Modes: smallBlockModes, Deprecated: true},
+	{Name: "XTEA", Type: CipherAlgorithm, Method: "ByXtea", KeySizes: []int{128}, Modes: smallBlockModes, Deprecated: true},
+	{Name: "RC4", Type: CipherAlgorithm, Method: "ByRc4", KeySizes: []int{8, 2048}, Deprecated: true},
+	{Name: "ChaCha20", Type: CipherAlgorithm, Method: "ByChaCha20", KeySizes: []int{256}, SecurityLevel: 256},
+	{Name: "ChaCha20-Poly1305", Type: CipherAlgorithm, Method: "ByChaCha20Poly1305", KeySizes: []int{256}, SecurityLevel: 256},
+	{Name: "XChaCha20-Poly1305", Type: CipherAlgorithm, Method: "ByXChaCha20Poly1305", KeySizes: []int{256}, SecurityLevel: 256},
+	{Name: "Salsa20", Type: CipherAlgorithm, Method: "BySalsa20", KeySizes: []int{256}, SecurityLevel: 256},
+	{Name: "RSA", Type: CipherAlgorithm, Method: "ByRsa", KeySizes: []int{2048, 3072, 4096}, Modes: []string{"pkcs1v15", "oaep"}, SecurityLevel: 112},
+	{Name: "SM2", Type: CipherAlgorithm, Method: "BySm2", KeySizes: []int{256}, Modes: []string{"c1c2c3", "c1c3c2", "asn1_c1c2c3", "asn1_c1c3c2"}, SecurityLevel: 128},
+
+	{Name: "RSA", Type: SignatureAlgorithm, Method: "ByRsa", KeySizes: []int{2048, 3072, 4096}, Modes: []string{"pkcs1v15", "pss"}, SecurityLevel: 112},
+	{Name: "SM2", Type: SignatureAlgorithm, Method: "BySm2", KeySizes: []int{256}, Modes: []string{"asn1", "bytes"}, SecurityLevel: 128},
+	{Name: "Ed25519", Type: SignatureAlgorithm, Method: "ByEd25519", KeySizes: []int{256}, Modes: []string{"Ed25519", "Ed25519ph", "Ed25519ctx"}, SecurityLevel: 128},
+	{Name: "ECDSA", Type: SignatureAlgorithm, Method: "ByEcdsa", KeySizes: []int{224, 256, 384, 521}, Modes: []string{"P-224", "P-256", "P-384", "P-521", "brainpoolP256r1", "brainpoolP384r1", "secp256k1"}, SecurityLevel: 112},
+
+	{Name: "Base32", Type: EncodingAlgorithm, Method: "ByBase32"},
+	{Name: "Base32Hex", Type: EncodingAlgorithm, Method: "ByBase32Hex"},
+	{Name: "Base45", Type: EncodingAlgorithm, Method: "ByBase45"},
+	{Name: "Base58", Type: EncodingAlgorithm, Method: "ByBase58"},
+	{Name: "Base62", Type: EncodingAlgorithm, Method: "ByBase62"},
+	{Name: "Base64", Type: EncodingAlgorithm, Method: "ByBase64"},
+	{Name: "Base64URL", Type: EncodingAlgorithm, Method: "ByBase64Url"},
+	{Name: "Base85", Type: EncodingAlgorithm, Method: "ByBase85"},
+	{Name: "Base91", Type: EncodingAlgorithm, Method: "ByBase91"},
+	{Name: "Base100", Type: EncodingAlgorithm, Method: "ByBase100"},
+	{Name: "Hex", Type: EncodingAlgorithm, Method: "ByHex"},
+	{Name: "Morse", Type: EncodingAlgorithm, Method: "ByMorse"},
+	{Name: "PGPWords", Type: EncodingAlgorithm, Method: "ByPgpWords"},
+	{Name: "BIP39", Type: EncodingAlgorithm, Method: "ByBip39"},
+	{Name: "Proquint", Type: EncodingAlgorithm, Method: "ByProquint"},
+	{Name: "Unicode", Type: EncodingAlgorithm, Method: "ByUnicode"},
+}
+
+// Algorithms returns the algorithms supported by this build of dongle, for
+// admin endpoints and documentation tooling. The result is a copy that the
+// caller may modify. Use Version for the library version.
+func Algorithms() []AlgorithmInfo {
+	out := make([]AlgorithmInfo, len(algorithms))
+	for i, a := range algorithms {
+		a.KeySizes = slices.Clone(a.KeySizes)
+		a.DigestSizes = slices.Clone(a.DigestSizes)
+		a.Modes = slices.Clone(a.Modes)
+		out[i] = a
+	}
+	return out
+}
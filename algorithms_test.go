@@ -0,0 +1,74 @@
+package dongle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/crypto"
+	"github.com/dromara/dongle/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlgorithms(t *testing.T) {
+	t.Run("covers every builder method", func(t *testing.T) {
+		builders := map[AlgorithmType]reflect.Type{
+			HashAlgorithm:      reflect.TypeOf(hash.Hasher{}),
+			CipherAlgorithm:    reflect.TypeOf(crypto.Encrypter{}),
+			SignatureAlgorithm: reflect.TypeOf(crypto.Signer{}),
+			EncodingAlgorithm:  reflect.TypeOf(coding.Encoder{}),
+		}
+		listed := map[AlgorithmType]map[string]bool{}
+		for _, a := range Algorithms() {
+			if listed[a.Type] == nil {
+				listed[a.Type] = map[string]bool{}
+			}
+			assert.False(t, listed[a.Type][a.Method], "duplicate %s %s", a.Type, a.Method)
+			listed[a.Type][a.Method] = true
+
+			_, ok := builders[a.Type].MethodByName(a.Method)
+			assert.True(t, ok, "%s has no method %s", builders[a.Type], a.Method)
+		}
+		for typ, builder := range builders {
+			for i := 0; i < builder.NumMethod(); i++ {
+				name := builder.Method(i).Name
+				if strings.HasPrefix(name, "By") {
+					assert.True(t, listed[typ][name], "%s.%s is not listed", builder, name)
+				}
+			}
+		}
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		for _, a := range Algorithms() {
+			assert.NotEmpty(t, a.Name)
+			switch a.Type {
+			case HashAlgorithm:
+				assert.NotEmpty(t, a.DigestSizes, a.Name)
+			case CipherAlgorithm, SignatureAlgorithm:
+				assert.NotEmpty(t, a.KeySizes, a.Name)
+			case EncodingAlgorithm:
+				assert.Zero(t, a.SecurityLevel, a.Name)
+			}
+			if a.Type != EncodingAlgorithm && !a.Deprecated {
+				assert.GreaterOrEqual(t, a.SecurityLevel, 64, a.Name)
+			}
+		}
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		list := Algorithms()
+		list[0].Name = "changed"
+		list[len(list)-1].Modes = append(list[len(list)-1].Modes, "changed")
+		for i := range list {
+			if len(list[i].Modes) > 0 {
+				list[i].Modes[0] = "changed"
+			}
+		}
+		for _, a := range Algorithms() {
+			assert.NotEqual(t, "changed", a.Name)
+			assert.NotContains(t, a.Modes, "changed")
+		}
+	})
+}
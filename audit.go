@@ -0,0 +1,20 @@
+//go:build dongle_debug
+
+package dongle
+
+import "github.com/dromara/dongle/internal/utils"
+
+// Debug builds panic when one of the shared instances is modified, see
+// utils.AuditShared.
+func init() {
+	utils.Share("dongle.Encode", &Encode)
+	utils.Share("dongle.Decode", &Decode)
+	utils.Share("dongle.Hash", &Hash)
+	utils.Share("dongle.Kdf", &Kdf)
+	utils.Share("dongle.Encrypt", &Encrypt)
+	utils.Share("dongle.Decrypt", &Decrypt)
+	utils.Share("dongle.Sign", &Sign)
+	utils.Share("dongle.Verify", &Verify)
+	utils.Share("dongle.Protect", &Protect)
+	utils.Share("dongle.Unprotect", &Unprotect)
+}
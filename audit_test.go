@@ -0,0 +1,28 @@
+//go:build dongle_debug
+
+package dongle
+
+import (
+	"testing"
+
+	"github.com/dromara/dongle/hash"
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedInstances(t *testing.T) {
+	assert.NotPanics(t, func() { Hash.FromString("hello").BySha2(256) })
+
+	Hash = Hash.WithKey([]byte("key"))
+	t.Cleanup(func() { Hash = hash.NewHasher() })
+
+	defer func() {
+		err, ok := recover().(utils.SharedInstanceError)
+		require.True(t, ok)
+		assert.Equal(t, "dongle.Hash", err.Name)
+		assert.Equal(t, []string{"key"}, err.Modified)
+		assert.Contains(t, err.Caller, "audit_test.go")
+	}()
+	Hash.FromString("hello").BySha2(256)
+}
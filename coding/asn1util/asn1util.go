@@ -0,0 +1,280 @@
+// Package asn1util builds and parses DER encoded ASN.1 structures element by
+// element, for formats that do not map cleanly onto Go structs with
+// encoding/asn1: SM2 envelopes, CMS fragments and the bank specific variants
+// of both. Builders and parsers nest like the structures they describe:
+//
+//	b := asn1util.NewBuilder()
+//	b.Sequence(func(b *asn1util.Builder) {
+//		b.OID(oid)
+//		b.Explicit(0, func(b *asn1util.Builder) {
+//			b.OctetString(content)
+//		})
+//	})
+//	der, err := b.Bytes()
+//
+//	p := asn1util.NewParser(der)
+//	seq := p.Sequence()
+//	oid := seq.OID()
+//	content := seq.Explicit(0).OctetString()
+//	if err := p.Finish(); err != nil { ... }
+//
+// Parsers record the first error and turn every later read into a no-op that
+// returns a zero value, so a whole structure can be read before checking the
+// error once on the outermost parser.
+//
+// The package also keeps a registry mapping algorithm and content type names to
+// their X.509 and CMS object identifiers, including the GM/T ones under
+// 1.2.156.10197, with LookupOID and LookupName for both directions and
+// RegisterOID for private identifiers.
+package asn1util
+
+import (
+	"encoding/asn1"
+	"math/big"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptoAsn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// Builder builds a DER encoded ASN.1 structure.
+type Builder struct {
+	b *cryptobyte.Builder
+}
+
+// NewBuilder returns a new empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{b: cryptobyte.NewBuilder(nil)}
+}
+
+// Sequence adds a SEQUENCE whose elements are added by fn.
+func (b *Builder) Sequence(fn func(b *Builder)) {
+	b.constructed(cryptoAsn1.SEQUENCE, fn)
+}
+
+// Set adds a SET whose elements are added by fn. The elements are written in
+// the order they are added, sort them beforehand for a DER SET OF.
+func (b *Builder) Set(fn func(b *Builder)) {
+	b.constructed(cryptoAsn1.SET, fn)
+}
+
+// Explicit adds an explicitly tagged [tag] context-specific element whose
+// content is added by fn.
+func (b *Builder) Explicit(tag int, fn func(b *Builder)) {
+	b.constructed(cryptoAsn1.Tag(tag).ContextSpecific().Constructed(), fn)
+}
+
+// Implicit adds an implicitly tagged [tag] context-specific primitive element
+// with the given content, such as an IMPLICIT OCTET STRING.
+func (b *Builder) Implicit(tag int, content []byte) {
+	b.b.AddASN1(cryptoAsn1.Tag(tag).ContextSpecific(), func(b *cryptobyte.Builder) {
+		b.AddBytes(content)
+	})
+}
+
+// OctetString adds an OCTET STRING.
+func (b *Builder) OctetString(data []byte) {
+	b.b.AddASN1OctetString(data)
+}
+
+// BitString adds a BIT STRING made of whole bytes.
+func (b *Builder) BitString(data []byte) {
+	b.b.AddASN1BitString(data)
+}
+
+// OID adds an OBJECT IDENTIFIER.
+func (b *Builder) OID(oid asn1.ObjectIdentifier) {
+	b.b.AddASN1ObjectIdentifier(oid)
+}
+
+// Integer adds an INTEGER.
+func (b *Builder) Integer(n *big.Int) {
+	b.b.AddASN1BigInt(n)
+}
+
+// Int64 adds an INTEGER.
+func (b *Builder) Int64(n int64) {
+	b.b.AddASN1Int64(n)
+}
+
+// Boolean adds a BOOLEAN.
+func (b *Builder) Boolean(v bool) {
+	b.b.AddASN1Boolean(v)
+}
+
+// Null adds a NULL, as used for absent algorithm parameters.
+func (b *Builder) Null() {
+	b.b.AddASN1NULL()
+}
+
+// UTF8String adds a UTF8String.
+func (b *Builder) UTF8String(s string) {
+	b.b.AddASN1(cryptoAsn1.UTF8String, func(b *cryptobyte.Builder) {
+		b.AddBytes([]byte(s))
+	})
+}
+
+// Raw adds a complete, already encoded element, such as a certificate or the
+// output of encoding/asn1.Marshal.
+func (b *Builder) Raw(der []byte) {
+	b.b.AddBytes(der)
+}
+
+// Bytes returns the encoded structure, or a BuildError if an element could not
+// be encoded, such as an invalid OID or a negative big integer.
+func (b *Builder) Bytes() ([]byte, error) {
+	der, err := b.b.Bytes()
+	if err != nil {
+		return nil, BuildError{Err: err}
+	}
+	return der, nil
+}
+
+// constructed adds a constructed element with the given tag whose content is
+// added by fn.
+func (b *Builder) constructed(tag cryptoAsn1.Tag, fn func(b *Builder)) {
+	b.b.AddASN1(tag, func(child *cryptobyte.Builder) {
+		fn(&Builder{b: child})
+	})
+}
+
+// Parser reads the elements of a DER encoded ASN.1 structure in order.
+type Parser struct {
+	s   cryptobyte.String
+	err *error // First error, shared with the parent and child parsers
+}
+
+// NewParser returns a new Parser reading der.
+func NewParser(der []byte) *Parser {
+	return &Parser{s: der, err: new(error)}
+}
+
+// Sequence reads a SEQUENCE and returns a parser of its elements.
+func (p *Parser) Sequence() *Parser {
+	return p.constructed(cryptoAsn1.SEQUENCE, "SEQUENCE")
+}
+
+// Set reads a SET and returns a parser of its elements.
+func (p *Parser) Set() *Parser {
+	return p.constructed(cryptoAsn1.SET, "SET")
+}
+
+// Explicit reads an explicitly tagged [tag] context-specific element and
+// returns a parser of its content.
+func (p *Parser) Explicit(tag int) *Parser {
+	return p.constructed(cryptoAsn1.Tag(tag).ContextSpecific().Constructed(), "explicit tag")
+}
+
+// OptionalExplicit reads an explicitly tagged [tag] context-specific element
+// if it is next, and reports whether it was present.
+func (p *Parser) OptionalExplicit(tag int) (*Parser, bool) {
+	if *p.err != nil || !p.s.PeekASN1Tag(cryptoAsn1.Tag(tag).ContextSpecific().Constructed()) {
+		return p.child(nil), false
+	}
+	return p.Explicit(tag), true
+}
+
+// Implicit reads an implicitly tagged [tag] context-specific primitive element
+// and returns its content.
+func (p *Parser) Implicit(tag int) []byte {
+	return read(p, "implicit tag", func(out *[]byte) bool { return p.s.ReadASN1Bytes(out, cryptoAsn1.Tag(tag).ContextSpecific()) })
+}
+
+// OctetString reads an OCTET STRING.
+func (p *Parser) OctetString() []byte {
+	return read(p, "OCTET STRING", func(out *[]byte) bool { return p.s.ReadASN1Bytes(out, cryptoAsn1.OCTET_STRING) })
+}
+
+// BitString reads a BIT STRING.
+func (p *Parser) BitString() asn1.BitString {
+	return read(p, "BIT STRING", p.s.ReadASN1BitString)
+}
+
+// OID reads an OBJECT IDENTIFIER.
+func (p *Parser) OID() asn1.ObjectIdentifier {
+	return read(p, "OBJECT IDENTIFIER", p.s.ReadASN1ObjectIdentifier)
+}
+
+// Integer reads an INTEGER. It returns nil after an error.
+func (p *Parser) Integer() *big.Int {
+	return read(p, "INTEGER", func(out **big.Int) bool {
+		*out = new(big.Int)
+		return p.s.ReadASN1Integer(*out)
+	})
+}
+
+// Int64 reads an INTEGER that fits in an int64.
+func (p *Parser) Int64() int64 {
+	return read(p, "INTEGER", func(out *int64) bool { return p.s.ReadASN1Integer(out) })
+}
+
+// Boolean reads a BOOLEAN.
+func (p *Parser) Boolean() bool {
+	return read(p, "BOOLEAN", p.s.ReadASN1Boolean)
+}
+
+// Null reads a NULL.
+func (p *Parser) Null() {
+	read(p, "NULL", func(out *[]byte) bool { return p.s.ReadASN1Bytes(out, cryptoAsn1.NULL) && len(*out) == 0 })
+}
+
+// UTF8String reads a UTF8String.
+func (p *Parser) UTF8String() string {
+	return string(read(p, "UTF8String", func(out *[]byte) bool { return p.s.ReadASN1Bytes(out, cryptoAsn1.UTF8String) }))
+}
+
+// Raw reads the next element of any type and returns its complete encoding,
+// for example to hand it to encoding/asn1.Unmarshal or to keep it for hashing.
+func (p *Parser) Raw() []byte {
+	var tag cryptoAsn1.Tag
+	return read(p, "element", func(out *cryptobyte.String) bool { return p.s.ReadAnyASN1Element(out, &tag) })
+}
+
+// Empty reports whether every element has been read.
+func (p *Parser) Empty() bool {
+	return p.s.Empty()
+}
+
+// Err returns the first error met by this parser, its parent or any of its
+// children, or nil.
+func (p *Parser) Err() error {
+	return *p.err
+}
+
+// Finish returns the first error as Err does, or a TrailingDataError when
+// elements remain unread.
+func (p *Parser) Finish() error {
+	if *p.err != nil {
+		return *p.err
+	}
+	if !p.s.Empty() {
+		return TrailingDataError{Size: len(p.s)}
+	}
+	return nil
+}
+
+// constructed reads a constructed element with the given tag and returns a
+// parser of its content.
+func (p *Parser) constructed(tag cryptoAsn1.Tag, name string) *Parser {
+	return p.child(read(p, name, func(out *cryptobyte.String) bool { return p.s.ReadASN1(out, tag) }))
+}
+
+// child returns a parser of s sharing the error of p.
+func (p *Parser) child(s cryptobyte.String) *Parser {
+	return &Parser{s: s, err: p.err}
+}
+
+// read runs fn unless an error was already met and returns the value it read.
+// If fn fails, read records a ParseError expecting name and returns the zero
+// value, since cryptobyte leaves partial results behind on failure.
+func read[T any](p *Parser, name string, fn func(out *T) bool) T {
+	var out T
+	if *p.err != nil {
+		return out
+	}
+	if !fn(&out) {
+		*p.err = ParseError{Expected: name}
+		var zero T
+		return zero
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+package asn1util
+
+import (
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var oidData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+
+// envelope mirrors the structure built in the tests for comparison with
+// encoding/asn1.
+type envelope struct {
+	Version int
+	Type    asn1.ObjectIdentifier
+	Serial  *big.Int
+	Content []byte `asn1:"explicit,tag:0"`
+	Note    string `asn1:"utf8"`
+	Signed  bool
+}
+
+func buildEnvelope(t *testing.T) []byte {
+	t.Helper()
+	b := NewBuilder()
+	b.Sequence(func(b *Builder) {
+		b.Int64(1)
+		b.OID(oidData)
+		b.Integer(big.NewInt(0x1234567890))
+		b.Explicit(0, func(b *Builder) {
+			b.OctetString([]byte("hello world"))
+		})
+		b.UTF8String("dongle")
+		b.Boolean(true)
+	})
+	der, err := b.Bytes()
+	assert.NoError(t, err)
+	return der
+}
+
+func TestBuilder(t *testing.T) {
+	t.Run("matches encoding/asn1", func(t *testing.T) {
+		want, err := asn1.Marshal(envelope{
+			Version: 1,
+			Type:    oidData,
+			Serial:  big.NewInt(0x1234567890),
+			Content: []byte("hello world"),
+			Note:    "dongle",
+			Signed:  true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, want, buildEnvelope(t))
+	})
+
+	t.Run("raw and null", func(t *testing.T) {
+		inner, _ := asn1.Marshal(oidData)
+		b := NewBuilder()
+		b.Sequence(func(b *Builder) {
+			b.Raw(inner)
+			b.Null()
+		})
+		der, err := b.Bytes()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x30, 0x0d, 0x06, 0x09, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x07, 0x01, 0x05, 0x00}, der)
+	})
+
+	t.Run("set bit string and implicit", func(t *testing.T) {
+		b := NewBuilder()
+		b.Set(func(b *Builder) {
+			b.BitString([]byte{0x04, 0x01})
+			b.Implicit(1, []byte{0xaa})
+		})
+		der, err := b.Bytes()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x31, 0x08, 0x03, 0x03, 0x00, 0x04, 0x01, 0x81, 0x01, 0xaa}, der)
+	})
+
+	t.Run("invalid oid", func(t *testing.T) {
+		b := NewBuilder()
+		b.OID(asn1.ObjectIdentifier{3})
+		der, err := b.Bytes()
+		assert.Nil(t, der)
+		var buildErr BuildError
+		assert.ErrorAs(t, err, &buildErr)
+		assert.NotNil(t, buildErr.Unwrap())
+		assert.Contains(t, err.Error(), "coding/asn1util: failed to build structure")
+	})
+}
+
+func TestParser(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		p := NewParser(buildEnvelope(t))
+		seq := p.Sequence()
+		assert.Equal(t, int64(1), seq.Int64())
+		assert.True(t, seq.OID().Equal(oidData))
+		assert.Equal(t, big.NewInt(0x1234567890), seq.Integer())
+		content, ok := seq.OptionalExplicit(0)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("hello world"), content.OctetString())
+		assert.True(t, content.Empty())
+		_, ok = seq.OptionalExplicit(1)
+		assert.False(t, ok)
+		assert.Equal(t, "dongle", seq.UTF8String())
+		assert.True(t, seq.Boolean())
+		assert.NoError(t, seq.Finish())
+		assert.NoError(t, p.Finish())
+	})
+
+	t.Run("set bit string implicit raw and null", func(t *testing.T) {
+		inner, _ := asn1.Marshal(oidData)
+		p := NewParser([]byte{0x31, 0x08, 0x03, 0x03, 0x00, 0x04, 0x01, 0x81, 0x01, 0xaa})
+		set := p.Set()
+		assert.Equal(t, []byte{0x04, 0x01}, set.BitString().Bytes)
+		assert.Equal(t, []byte{0xaa}, set.Implicit(1))
+		assert.NoError(t, p.Finish())
+
+		p = NewParser(append(append([]byte(nil), inner...), 0x05, 0x00))
+		assert.Equal(t, inner, p.Raw())
+		p.Null()
+		assert.NoError(t, p.Finish())
+	})
+
+	t.Run("first error sticks", func(t *testing.T) {
+		p := NewParser(buildEnvelope(t))
+		seq := p.Sequence()
+		assert.Nil(t, seq.OctetString())
+		assert.Nil(t, seq.Integer())
+		assert.Equal(t, int64(0), seq.Int64())
+		assert.Nil(t, seq.Explicit(0).OctetString())
+		_, ok := seq.OptionalExplicit(0)
+		assert.False(t, ok)
+		assert.Equal(t, ParseError{Expected: "OCTET STRING"}, p.Err())
+		assert.Equal(t, ParseError{Expected: "OCTET STRING"}, p.Finish())
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		p := NewParser(append(buildEnvelope(t), 0x05, 0x00))
+		p.Sequence()
+		assert.Equal(t, TrailingDataError{Size: 2}, p.Finish())
+	})
+
+	t.Run("truncated input", func(t *testing.T) {
+		der := buildEnvelope(t)
+		p := NewParser(der[:len(der)-1])
+		p.Sequence()
+		assert.Equal(t, ParseError{Expected: "SEQUENCE"}, p.Finish())
+	})
+
+	t.Run("non empty null", func(t *testing.T) {
+		p := NewParser([]byte{0x05, 0x01, 0x00})
+		p.Null()
+		assert.Equal(t, ParseError{Expected: "NULL"}, p.Err())
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("parse error", func(t *testing.T) {
+		err := ParseError{Expected: "OID"}
+		assert.Equal(t, "coding/asn1util: invalid or missing OID", err.Error())
+		assert.Equal(t, "DGL-ASN1UTIL-001", err.Code())
+		assert.Equal(t, "OID", err.Fields()["expected"])
+	})
+
+	t.Run("trailing data error", func(t *testing.T) {
+		err := TrailingDataError{Size: 3}
+		assert.Equal(t, "coding/asn1util: 3 bytes of trailing data", err.Error())
+		assert.Equal(t, "DGL-ASN1UTIL-002", err.Code())
+		assert.Equal(t, 3, err.Fields()["size"])
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		err := BuildError{Err: asn1.SyntaxError{Msg: "bad"}}
+		assert.Equal(t, "coding/asn1util: failed to build structure: asn1: syntax error: bad", err.Error())
+		assert.Equal(t, "DGL-ASN1UTIL-003", err.Code())
+		assert.Equal(t, "build", err.Fields()["operation"])
+	})
+
+	t.Run("unknown name error", func(t *testing.T) {
+		err := UnknownNameError{Name: "ROT13"}
+		assert.Equal(t, `coding/asn1util: unknown algorithm name "ROT13"`, err.Error())
+		assert.Equal(t, "DGL-ASN1UTIL-004", err.Code())
+		assert.Equal(t, "ROT13", err.Fields()["algorithm"])
+	})
+
+	t.Run("unknown oid error", func(t *testing.T) {
+		err := UnknownOIDError{OID: "1.2.3"}
+		assert.Equal(t, "coding/asn1util: unknown object identifier 1.2.3", err.Error())
+		assert.Equal(t, "DGL-ASN1UTIL-005", err.Code())
+		assert.Equal(t, "1.2.3", err.Fields()["oid"])
+	})
+
+	t.Run("conflict error", func(t *testing.T) {
+		err := ConflictError{Name: "SM3", OID: "1.2.3", Existing: "1.2.156.10197.1.401"}
+		assert.Equal(t, "coding/asn1util: cannot register SM3 as 1.2.3, already registered with 1.2.156.10197.1.401", err.Error())
+		assert.Equal(t, "DGL-ASN1UTIL-006", err.Code())
+		assert.Equal(t, "register", err.Fields()["operation"])
+	})
+}
@@ -0,0 +1,139 @@
+package asn1util
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// ParseError represents an error when the next element is not the expected
+// one or is malformed.
+type ParseError struct {
+	Expected string // Expected element, e.g. "OCTET STRING"
+}
+
+// Error returns a formatted error message naming the expected element.
+func (e ParseError) Error() string {
+	return fmt.Sprintf("coding/asn1util: invalid or missing %s", e.Expected)
+}
+
+// Code returns the stable error code DGL-ASN1UTIL-001.
+func (e ParseError) Code() string {
+	return "DGL-ASN1UTIL-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ParseError) Fields() map[string]any {
+	return errcode.NewFields("coding/asn1util", "ASN.1", "parse", "expected", e.Expected)
+}
+
+// TrailingDataError represents an error when bytes remain after the last
+// expected element.
+type TrailingDataError struct {
+	Size int // Number of unread bytes
+}
+
+// Error returns a formatted error message with the number of unread bytes.
+func (e TrailingDataError) Error() string {
+	return fmt.Sprintf("coding/asn1util: %d bytes of trailing data", e.Size)
+}
+
+// Code returns the stable error code DGL-ASN1UTIL-002.
+func (e TrailingDataError) Code() string {
+	return "DGL-ASN1UTIL-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e TrailingDataError) Fields() map[string]any {
+	return errcode.NewFields("coding/asn1util", "ASN.1", "parse", "size", e.Size)
+}
+
+// BuildError represents an error when an element cannot be encoded.
+type BuildError struct {
+	Err error // Underlying encoding error
+}
+
+// Error returns a formatted error message wrapping the encoding error.
+func (e BuildError) Error() string {
+	return fmt.Sprintf("coding/asn1util: failed to build structure: %v", e.Err)
+}
+
+// Unwrap returns the underlying encoding error.
+func (e BuildError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code DGL-ASN1UTIL-003.
+func (e BuildError) Code() string {
+	return "DGL-ASN1UTIL-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BuildError) Fields() map[string]any {
+	return errcode.NewFields("coding/asn1util", "ASN.1", "build")
+}
+
+// UnknownNameError represents an error when no object identifier is
+// registered for a name.
+type UnknownNameError struct {
+	Name string // Name looked up
+}
+
+// Error returns a formatted error message with the unknown name.
+func (e UnknownNameError) Error() string {
+	return fmt.Sprintf("coding/asn1util: unknown algorithm name %q", e.Name)
+}
+
+// Code returns the stable error code DGL-ASN1UTIL-004.
+func (e UnknownNameError) Code() string {
+	return "DGL-ASN1UTIL-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnknownNameError) Fields() map[string]any {
+	return errcode.NewFields("coding/asn1util", e.Name, "lookup")
+}
+
+// UnknownOIDError represents an error when no name is registered for an
+// object identifier.
+type UnknownOIDError struct {
+	OID string // Dotted object identifier looked up
+}
+
+// Error returns a formatted error message with the unknown object identifier.
+func (e UnknownOIDError) Error() string {
+	return fmt.Sprintf("coding/asn1util: unknown object identifier %s", e.OID)
+}
+
+// Code returns the stable error code DGL-ASN1UTIL-005.
+func (e UnknownOIDError) Code() string {
+	return "DGL-ASN1UTIL-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnknownOIDError) Fields() map[string]any {
+	return errcode.NewFields("coding/asn1util", "", "lookup", "oid", e.OID)
+}
+
+// ConflictError represents an error when registering a name or object
+// identifier already registered for something else.
+type ConflictError struct {
+	Name     string // Name being registered
+	OID      string // Dotted object identifier being registered
+	Existing string // Object identifier or name it conflicts with
+}
+
+// Error returns a formatted error message describing the conflict.
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("coding/asn1util: cannot register %s as %s, already registered with %s", e.Name, e.OID, e.Existing)
+}
+
+// Code returns the stable error code DGL-ASN1UTIL-006.
+func (e ConflictError) Code() string {
+	return "DGL-ASN1UTIL-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ConflictError) Fields() map[string]any {
+	return errcode.NewFields("coding/asn1util", e.Name, "register", "oid", e.OID, "existing", e.Existing)
+}
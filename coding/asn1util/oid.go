@@ -0,0 +1,179 @@
+package asn1util
+
+import (
+	"encoding/asn1"
+	"strings"
+	"sync"
+)
+
+// oidEntry maps an algorithm or content type name to its object identifier.
+type oidEntry struct {
+	name string
+	oid  asn1.ObjectIdentifier
+}
+
+// oidEntries are the object identifiers known out of the box. Names follow the
+// crypto/x509 spelling where it has one, and GM/T names are prefixed "SM" or
+// "GM" after the Chinese commercial cryptography standards.
+var oidEntries = []oidEntry{
+	// Digests
+	{"MD5", asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 5}},
+	{"SHA1", asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}},
+	{"SHA224", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4}},
+	{"SHA256", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}},
+	{"SHA384", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}},
+	{"SHA512", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}},
+	{"SHA3-224", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 7}},
+	{"SHA3-256", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 8}},
+	{"SHA3-384", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 9}},
+	{"SHA3-512", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 10}},
+	{"SM3", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401}},
+
+	// Message authentication codes
+	{"HMAC-SHA1", asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}},
+	{"HMAC-SHA224", asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}},
+	{"HMAC-SHA256", asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}},
+	{"HMAC-SHA384", asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}},
+	{"HMAC-SHA512", asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}},
+	{"HMAC-SM3", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 401, 2}},
+
+	// Public key algorithms and curves
+	{"RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}},
+	{"RSA-OAEP", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 7}},
+	{"RSA-PSS", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}},
+	{"EC", asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}},
+	{"P-224", asn1.ObjectIdentifier{1, 3, 132, 0, 33}},
+	{"P-256", asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}},
+	{"P-384", asn1.ObjectIdentifier{1, 3, 132, 0, 34}},
+	{"P-521", asn1.ObjectIdentifier{1, 3, 132, 0, 35}},
+	{"Ed25519", asn1.ObjectIdentifier{1, 3, 101, 112}},
+	{"X25519", asn1.ObjectIdentifier{1, 3, 101, 110}},
+	{"SM2", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}},
+	{"SM2-SIGN", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301, 1}},
+	{"SM2-EXCHANGE", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301, 2}},
+	{"SM2-ENCRYPT", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301, 3}},
+
+	// Signature algorithms
+	{"MD5-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 4}},
+	{"SHA1-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}},
+	{"SHA256-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}},
+	{"SHA384-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}},
+	{"SHA512-RSA", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}},
+	{"ECDSA-SHA1", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}},
+	{"ECDSA-SHA256", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+	{"ECDSA-SHA384", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}},
+	{"ECDSA-SHA512", asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}},
+	{"SM2-SM3", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 501}},
+
+	// Symmetric ciphers
+	{"DES-EDE3-CBC", asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}},
+	{"AES-128-CBC", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}},
+	{"AES-192-CBC", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}},
+	{"AES-256-CBC", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}},
+	{"AES-128-GCM", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 6}},
+	{"AES-192-GCM", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 26}},
+	{"AES-256-GCM", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 46}},
+	{"SM4", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104}},
+	{"SM4-ECB", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 1}},
+	{"SM4-CBC", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 2}},
+	{"SM4-OFB", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 3}},
+	{"SM4-CFB", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 4}},
+	{"SM4-CTR", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 7}},
+	{"SM4-GCM", asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 8}},
+
+	// Key derivation
+	{"PBKDF2", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}},
+	{"PBES2", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}},
+
+	// CMS content types (RFC 5652)
+	{"Data", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}},
+	{"SignedData", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}},
+	{"EnvelopedData", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}},
+	{"DigestedData", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 5}},
+	{"EncryptedData", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}},
+
+	// CMS content types (GM/T 0010)
+	{"GM-Data", asn1.ObjectIdentifier{1, 2, 156, 10197, 6, 1, 4, 2, 1}},
+	{"GM-SignedData", asn1.ObjectIdentifier{1, 2, 156, 10197, 6, 1, 4, 2, 2}},
+	{"GM-EnvelopedData", asn1.ObjectIdentifier{1, 2, 156, 10197, 6, 1, 4, 2, 3}},
+	{"GM-SignedAndEnvelopedData", asn1.ObjectIdentifier{1, 2, 156, 10197, 6, 1, 4, 2, 4}},
+	{"GM-EncryptedData", asn1.ObjectIdentifier{1, 2, 156, 10197, 6, 1, 4, 2, 5}},
+	{"GM-KeyAgreementInfo", asn1.ObjectIdentifier{1, 2, 156, 10197, 6, 1, 4, 2, 6}},
+
+	// CMS attributes (RFC 5652)
+	{"ContentType", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}},
+	{"MessageDigest", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}},
+	{"SigningTime", asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}},
+}
+
+var (
+	oidMu     sync.RWMutex
+	oidByName = map[string]oidEntry{}
+	nameByOID = map[string]oidEntry{}
+)
+
+func init() {
+	for _, e := range oidEntries {
+		oidByName[strings.ToUpper(e.name)] = e
+		nameByOID[e.oid.String()] = e
+	}
+}
+
+// LookupOID returns the object identifier of an algorithm or content type,
+// such as "SHA256", "SM2-SM3" or "SignedData". Names are matched case
+// insensitively.
+func LookupOID(name string) (asn1.ObjectIdentifier, error) {
+	oidMu.RLock()
+	defer oidMu.RUnlock()
+	e, ok := oidByName[strings.ToUpper(name)]
+	if !ok {
+		return nil, UnknownNameError{Name: name}
+	}
+	return append(asn1.ObjectIdentifier(nil), e.oid...), nil
+}
+
+// LookupName returns the name of the algorithm or content type identified by
+// oid, as spelled when it was registered.
+func LookupName(oid asn1.ObjectIdentifier) (string, error) {
+	oidMu.RLock()
+	defer oidMu.RUnlock()
+	e, ok := nameByOID[oid.String()]
+	if !ok {
+		return "", UnknownOIDError{OID: oid.String()}
+	}
+	return e.name, nil
+}
+
+// MustOID is like LookupOID but panics if the name is unknown. It simplifies
+// the initialization of package level variables.
+func MustOID(name string) asn1.ObjectIdentifier {
+	oid, err := LookupOID(name)
+	if err != nil {
+		panic(err)
+	}
+	return oid
+}
+
+// RegisterOID adds a name and object identifier pair to the registry, for
+// example the private algorithms of a bank specific format. Registering a pair
+// that is already known is a no-op, while reusing a known name or object
+// identifier for something else fails with a ConflictError.
+func RegisterOID(name string, oid asn1.ObjectIdentifier) error {
+	oidMu.Lock()
+	defer oidMu.Unlock()
+	byName, nameOK := oidByName[strings.ToUpper(name)]
+	byOID, oidOK := nameByOID[oid.String()]
+	if nameOK && byName.oid.Equal(oid) {
+		return nil
+	}
+	if nameOK {
+		return ConflictError{Name: name, OID: oid.String(), Existing: byName.oid.String()}
+	}
+	if oidOK {
+		return ConflictError{Name: name, OID: oid.String(), Existing: byOID.name}
+	}
+	e := oidEntry{name: name, oid: append(asn1.ObjectIdentifier(nil), oid...)}
+	oidByName[strings.ToUpper(name)] = e
+	nameByOID[oid.String()] = e
+	return nil
+}
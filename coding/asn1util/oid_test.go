@@ -0,0 +1,102 @@
+package asn1util
+
+import (
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupOID(t *testing.T) {
+	t.Run("known names", func(t *testing.T) {
+		oid, err := LookupOID("SM2-SM3")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.156.10197.1.501", oid.String())
+
+		oid, err = LookupOID("sha256")
+		assert.NoError(t, err)
+		assert.Equal(t, "2.16.840.1.101.3.4.2.1", oid.String())
+
+		oid, err = LookupOID("gm-signeddata")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.156.10197.6.1.4.2.2", oid.String())
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		oid, _ := LookupOID("SM3")
+		oid[0] = 9
+		assert.Equal(t, "1.2.156.10197.1.401", MustOID("SM3").String())
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		oid, err := LookupOID("ROT13")
+		assert.Nil(t, oid)
+		assert.Equal(t, UnknownNameError{Name: "ROT13"}, err)
+		assert.Panics(t, func() { MustOID("ROT13") })
+	})
+}
+
+func TestLookupName(t *testing.T) {
+	t.Run("known oids", func(t *testing.T) {
+		name, err := LookupName(asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 104, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, "SM4-CBC", name)
+
+		name, err = LookupName(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3})
+		assert.NoError(t, err)
+		assert.Equal(t, "EnvelopedData", name)
+	})
+
+	t.Run("unknown oid", func(t *testing.T) {
+		name, err := LookupName(asn1.ObjectIdentifier{1, 2, 3})
+		assert.Empty(t, name)
+		assert.Equal(t, UnknownOIDError{OID: "1.2.3"}, err)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, e := range oidEntries {
+			name, err := LookupName(MustOID(e.name))
+			assert.NoError(t, err)
+			assert.Equal(t, e.name, name)
+		}
+	})
+
+	t.Run("entries are unique", func(t *testing.T) {
+		assert.Len(t, oidByName, len(oidEntries))
+		assert.Len(t, nameByOID, len(oidEntries))
+	})
+}
+
+func TestRegisterOID(t *testing.T) {
+	oid := asn1.ObjectIdentifier{1, 2, 156, 99999, 1}
+	t.Cleanup(func() {
+		oidMu.Lock()
+		defer oidMu.Unlock()
+		delete(oidByName, "BANK-ENVELOPE")
+		delete(nameByOID, oid.String())
+	})
+
+	t.Run("new pair", func(t *testing.T) {
+		assert.NoError(t, RegisterOID("Bank-Envelope", oid))
+		got, err := LookupOID("bank-envelope")
+		assert.NoError(t, err)
+		assert.Equal(t, oid, got)
+		name, err := LookupName(oid)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bank-Envelope", name)
+	})
+
+	t.Run("same pair again", func(t *testing.T) {
+		assert.NoError(t, RegisterOID("BANK-ENVELOPE", oid))
+	})
+
+	t.Run("name conflict", func(t *testing.T) {
+		err := RegisterOID("SM3", asn1.ObjectIdentifier{1, 2, 3})
+		assert.Equal(t, ConflictError{Name: "SM3", OID: "1.2.3", Existing: "1.2.156.10197.1.401"}, err)
+	})
+
+	t.Run("oid conflict", func(t *testing.T) {
+		err := RegisterOID("MY-SM3", MustOID("SM3"))
+		assert.Equal(t, ConflictError{Name: "MY-SM3", OID: "1.2.156.10197.1.401", Existing: "SM3"}, err)
+	})
+}
@@ -92,7 +92,7 @@ func (d *StdDecoder) Decode(src []byte) (dst []byte, err error) {
 // immediately without buffering.
 type StreamEncoder struct {
 	writer    io.Writer // Underlying writer for encoded output
-	encodeBuf [4]byte   // Reusable buffer for encoding a single byte
+	encodeBuf []byte    // Reusable buffer for the encoding of a chunk
 	Error     error     // Error field for storing encoding errors
 }
 
@@ -105,25 +105,20 @@ func NewStreamEncoder(w io.Writer) io.WriteCloser {
 }
 
 // Write implements the io.Writer interface for streaming base100 encoding.
-// Processes data in chunks while maintaining minimal state for cross-Write calls.
-// This is true streaming - processes data immediately without accumulating large buffers.
+// Every byte maps to its own emoji, so the input is encoded and written in
+// chunks as it arrives without keeping state across calls.
 func (e *StreamEncoder) Write(p []byte) (n int, err error) {
 	if e.Error != nil {
 		return 0, e.Error
 	}
 
-	if len(p) == 0 {
-		return 0, nil
-	}
-
-	// Process each byte directly from input
-	for _, v := range p {
-		// Encode byte inline using base100 algorithm
-		e.encodeBuf[0] = 0xf0
-		e.encodeBuf[1] = 0x9f
-		e.encodeBuf[2] = byte((uint16(v)+55)/64 + 0x8f)
-		e.encodeBuf[3] = (v+55)%64 + 0x80
-		if _, err = e.writer.Write(e.encodeBuf[:]); err != nil {
+	for chunk := p; len(chunk) > 0; chunk = chunk[min(len(chunk), 1024):] {
+		e.encodeBuf = e.encodeBuf[:0]
+		for _, v := range chunk[:min(len(chunk), 1024)] {
+			// Encode byte inline using base100 algorithm
+			e.encodeBuf = append(e.encodeBuf, 0xf0, 0x9f, byte((uint16(v)+55)/64+0x8f), (v+55)%64+0x80)
+		}
+		if _, err = e.writer.Write(e.encodeBuf); err != nil {
 			return len(p), err
 		}
 	}
@@ -132,27 +127,24 @@ func (e *StreamEncoder) Write(p []byte) (n int, err error) {
 }
 
 // Close implements the io.Closer interface for streaming base100 encoding.
-// Encodes any remaining buffered bytes from the last Write call.
-// This is the only place where we handle cross-Write state.
+// Write holds nothing back, so there is nothing to flush.
 func (e *StreamEncoder) Close() error {
-	if e.Error != nil {
-		return e.Error
-	}
-
-	// No buffering needed for base100, all data is processed immediately
-
-	return nil
+	return e.Error
 }
 
 // StreamDecoder represents a streaming base100 decoder that implements io.Reader.
 // It provides efficient decoding for large data streams by processing data
-// in chunks and maintaining an internal buffer for partial reads.
+// in chunks and maintaining an internal buffer for partial reads. An emoji
+// split across reads is carried over to the next chunk.
 type StreamDecoder struct {
 	reader  io.Reader   // Underlying reader for encoded input
 	buffer  []byte      // Buffer for decoded data not yet read
 	pos     int         // Current position in the decoded buffer
 	decoder *StdDecoder // Reuse decoder instance to avoid repeated creation
 	readBuf [1024]byte  // Reusable buffer for reading encoded data
+	pending int         // Bytes of an incomplete emoji at the start of readBuf
+	offset  int64       // Input offset of readBuf
+	eof     bool        // Whether the underlying reader is exhausted
 	Error   error       // Error field for storing decoding errors
 }
 
@@ -169,42 +161,50 @@ func NewStreamDecoder(r io.Reader) io.Reader {
 // Reads and decodes base100 data from the underlying reader in chunks.
 // Maintains an internal buffer to handle partial reads efficiently.
 func (d *StreamDecoder) Read(p []byte) (n int, err error) {
-	if d.Error != nil {
-		return 0, d.Error
-	}
-
-	// Return buffered data if available
-	if d.pos < len(d.buffer) {
-		n = copy(p, d.buffer[d.pos:])
-		d.pos += n
-		return n, nil
-	}
-
-	// Read encoded data in chunks using reusable buffer
-	rn, err := d.reader.Read(d.readBuf[:])
-	if err != nil && err != io.EOF {
-		return 0, err
-	}
+	for {
+		if d.Error != nil {
+			return 0, d.Error
+		}
 
-	if rn == 0 {
-		return 0, io.EOF
-	}
+		// Return buffered data if available
+		if d.pos < len(d.buffer) {
+			n = copy(p, d.buffer[d.pos:])
+			d.pos += n
+			return n, nil
+		}
 
-	// Decode the data using the configured decoder
-	decoded, err := d.decoder.Decode(d.readBuf[:rn])
-	if err != nil {
-		return 0, err
-	}
+		if d.eof {
+			if d.pending > 0 {
+				// The input ends in the middle of an emoji
+				d.Error = InvalidLengthError(d.offset + int64(d.pending))
+				return 0, d.Error
+			}
+			return 0, io.EOF
+		}
 
-	// Copy decoded data to the provided buffer
-	copied := copy(p, decoded)
-	if copied < len(decoded) {
-		// Buffer remaining data for next read
-		d.buffer = decoded[copied:]
-		d.pos = 0
+		// Read encoded data in chunks using reusable buffer, after the bytes
+		// of an emoji split by the previous read
+		rn, err := d.reader.Read(d.readBuf[d.pending:])
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		d.eof = err == io.EOF
+
+		// Decode the complete emojis using the configured decoder
+		total := d.pending + rn
+		complete := total - total%4
+		decoded, err := d.decoder.Decode(d.readBuf[:complete])
+		if err != nil {
+			if e, ok := err.(CorruptInputError); ok {
+				err = CorruptInputError(d.offset + int64(e))
+			}
+			d.Error = err
+			return 0, err
+		}
+		d.pending = copy(d.readBuf[:], d.readBuf[complete:total])
+		d.offset += int64(complete)
+		d.buffer, d.pos = decoded, 0
 	}
-
-	return copied, nil
 }
 
 // Legacy functions for backward compatibility
@@ -622,3 +622,48 @@ func TestLegacyFunctions(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid length")
 	})
 }
+
+func TestStreamDecoder_ChunkBoundaries(t *testing.T) {
+	encoded := NewStdEncoder().Encode([]byte("hello world"))
+
+	t.Run("emojis split across reads", func(t *testing.T) {
+		decoded, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader(encoded)).Then(3).Then(1).Then(6).Repeat()))
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", string(decoded))
+	})
+
+	t.Run("truncated emoji", func(t *testing.T) {
+		decoded, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader(encoded[:len(encoded)-1])).Then(5).Repeat()))
+		assert.Equal(t, InvalidLengthError(len(encoded)-1), err)
+		assert.Equal(t, "hello worl", string(decoded))
+	})
+
+	t.Run("corrupt input offset", func(t *testing.T) {
+		corrupt := append([]byte{}, encoded...)
+		corrupt[21] = 0x00
+		_, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader(corrupt)).Then(3).Repeat()))
+		assert.Equal(t, CorruptInputError(20), err)
+	})
+}
+
+// FuzzStreamRoundTrip checks that streaming matches one-shot encoding and
+// round-trips whatever the write and read boundaries.
+func FuzzStreamRoundTrip(f *testing.F) {
+	f.Add([]byte("hello world"), uint8(0), uint8(2))
+	f.Add([]byte{}, uint8(0), uint8(0))
+	f.Add(bytes.Repeat([]byte{0xff}, 2000), uint8(6), uint8(1))
+	f.Fuzz(func(t *testing.T, data []byte, write, read uint8) {
+		var buf bytes.Buffer
+		encoder := NewStreamEncoder(&buf)
+		for chunk := data; len(chunk) > 0; chunk = chunk[min(len(chunk), int(write)+1):] {
+			_, err := encoder.Write(chunk[:min(len(chunk), int(write)+1)])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, encoder.Close())
+		assert.Equal(t, string(NewStdEncoder().Encode(data)), buf.String())
+
+		decoded, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader(buf.Bytes())).Then(int(read) + 1).Then(1).Then(max(int(write), 1)).Repeat()))
+		assert.NoError(t, err)
+		assert.Equal(t, string(data), string(decoded))
+	})
+}
@@ -1,6 +1,10 @@
 package base100
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // InvalidLengthError represents an error when the base100 input length is invalid.
 // Base100 encoding requires each input byte to be represented by exactly 4 bytes,
@@ -13,6 +17,16 @@ func (e InvalidLengthError) Error() string {
 	return fmt.Sprintf("coding/base100: invalid length, data length must be divisible by 4, got %d", int(e))
 }
 
+// Code returns the stable error code DGL-BASE100-001.
+func (e InvalidLengthError) Code() string {
+	return "DGL-BASE100-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidLengthError) Fields() map[string]any {
+	return errcode.NewFields("coding/base100", "Base100", "", "length", int(e))
+}
+
 // CorruptInputError represents an error when corrupted or invalid base100 data
 // is detected during decoding. This error occurs when an invalid character
 // is found in the input or when the input data is malformed.
@@ -23,3 +37,13 @@ type CorruptInputError int64
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/base100: illegal data at input byte %d", int64(e))
 }
+
+// Code returns the stable error code DGL-BASE100-002.
+func (e CorruptInputError) Code() string {
+	return "DGL-BASE100-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/base100", "Base100", "decode", "offset", int64(e))
+}
@@ -44,7 +44,7 @@ func (d Decoder) ByBase32() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = base32.NewStdDecoder(base32.StdAlphabet).Decode(d.src)
+		d.dst, d.corrections, d.Error = d.decode(base32.NewStdDecoder(base32.StdAlphabet))
 	}
 
 	return d
@@ -88,7 +88,7 @@ func (d Decoder) ByBase32Hex() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = base32.NewStdDecoder(base32.HexAlphabet).Decode(d.src)
+		d.dst, d.corrections, d.Error = d.decode(base32.NewStdDecoder(base32.HexAlphabet))
 	}
 
 	return d
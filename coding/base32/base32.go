@@ -6,6 +6,8 @@ package base32
 import (
 	"encoding/base32"
 	"io"
+
+	"github.com/dromara/dongle/coding/internal/tolerant"
 )
 
 // StdAlphabet is the standard base32 alphabet as defined in RFC 4648.
@@ -94,6 +96,48 @@ func (d *StdDecoder) Decode(src []byte) (dst []byte, err error) {
 	return buf[:n], nil
 }
 
+// DecodeTolerant decodes base32 typed or pasted by a person, such as a TOTP
+// secret read from a screen. It ignores whitespace, line breaks and the dashes
+// used to group characters, accepts input with or without padding and in either
+// case. With mapConfusables it also replaces the digits and letters that are not
+// in the alphabet by those they are easily mistaken for, '0' for 'O', '1' for
+// 'I' and '8' for 'B' with StdAlphabet, and returns the number of characters
+// replaced, so that callers can ask for confirmation before using a corrected
+// value.
+func (d *StdDecoder) DecodeTolerant(src []byte, mapConfusables bool) (dst []byte, corrections int, err error) {
+	if d.Error != nil {
+		return nil, 0, d.Error
+	}
+	clean, positions, corrections, offset := tolerant.Clean(src, d.alphabet, "-", mapConfusables)
+	if offset >= 0 {
+		d.Error = CorruptInputError(offset)
+		return nil, 0, d.Error
+	}
+	if len(clean) == 0 {
+		return nil, corrections, nil
+	}
+
+	// Without padding the decoder accepts a dangling character, which cannot
+	// come from an encoder.
+	if r := len(clean) % 8; r == 1 || r == 3 || r == 6 {
+		d.Error = CorruptInputError(positions[len(clean)-1])
+		return nil, 0, d.Error
+	}
+	encoding := d.encoding.WithPadding(base32.NoPadding)
+	buf := make([]byte, encoding.DecodedLen(len(clean)))
+	n, err := encoding.Decode(buf, clean)
+	if err != nil {
+		// Report the position in the original input.
+		pos := int64(len(src))
+		if e, ok := err.(base32.CorruptInputError); ok && int(e) < len(positions) {
+			pos = int64(positions[e])
+		}
+		d.Error = CorruptInputError(pos)
+		return nil, 0, d.Error
+	}
+	return buf[:n], corrections, nil
+}
+
 // StreamEncoder represents a streaming base32 encoder that implements io.WriteCloser.
 // It provides efficient encoding for large data streams by processing data
 // in chunks and writing encoded output immediately.
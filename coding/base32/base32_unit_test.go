@@ -263,6 +263,75 @@ func TestStdDecoder_Decode(t *testing.T) {
 	})
 }
 
+func TestStdDecoder_DecodeTolerant(t *testing.T) {
+	// "hello world" is NBSWY3DPEB3W64TMMQ====== in StdAlphabet
+	want := []byte("hello world")
+
+	t.Run("formatting", func(t *testing.T) {
+		for _, src := range []string{
+			"NBSWY3DPEB3W64TMMQ======",
+			"NBSWY3DPEB3W64TMMQ",
+			"nbswy3dpeb3w64tmmq",
+			"NBSW Y3DP EB3W 64TM MQ",
+			"NBSW-Y3DP-EB3W-64TM-MQ==\r\n",
+			"\tNBSWY3DP\nEB3W64TM\nMQ\n",
+		} {
+			dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte(src), false)
+			assert.NoError(t, err, src)
+			assert.Equal(t, want, dst, src)
+			assert.Zero(t, corrections, src)
+		}
+	})
+
+	t.Run("confusables", func(t *testing.T) {
+		dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte("N8SWY3DPEB3W64TMMQ"), true)
+		assert.NoError(t, err)
+		assert.Equal(t, want, dst)
+		assert.Equal(t, 1, corrections)
+
+		dst, corrections, err = NewStdDecoder(StdAlphabet).DecodeTolerant([]byte("0I1B"), true)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, corrections)
+		expected, _, _ := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte("OIIB"), false)
+		assert.Equal(t, expected, dst)
+
+		decoder := NewStdDecoder(StdAlphabet)
+		_, _, err = decoder.DecodeTolerant([]byte("N8SWY3DPEB3W64TMMQ"), false)
+		assert.Equal(t, CorruptInputError(1), err)
+		assert.Equal(t, CorruptInputError(1), decoder.Error)
+	})
+
+	t.Run("hex alphabet", func(t *testing.T) {
+		dst, _, err := NewStdDecoder(HexAlphabet).DecodeTolerant([]byte("d1imor3f41rmusjccg"), true)
+		assert.NoError(t, err)
+		assert.Equal(t, want, dst)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte(" \n== "), true)
+		assert.NoError(t, err)
+		assert.Empty(t, dst)
+		assert.Zero(t, corrections)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for src, pos := range map[string]CorruptInputError{
+			"NBSW=Y3DP":   4,
+			"NBSW Y3D!":   8,
+			"NBSWY3DPE":   8,
+			" NBSWY3DP9Q": 9,
+		} {
+			_, _, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte(src), true)
+			assert.Equal(t, pos, err, src)
+		}
+	})
+
+	t.Run("invalid alphabet", func(t *testing.T) {
+		_, _, err := NewStdDecoder("abc").DecodeTolerant([]byte("NBSW"), true)
+		assert.Equal(t, AlphabetSizeError(3), err)
+	})
+}
+
 func TestStreamEncoder_Write(t *testing.T) {
 	t.Run("write data", func(t *testing.T) {
 		file := mock.NewFile(nil, "test.txt")
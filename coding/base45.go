@@ -44,7 +44,7 @@ func (d Decoder) ByBase45() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = base45.NewStdDecoder().Decode(d.src)
+		d.dst, d.Error = d.limit(base45.NewStdDecoder().Decode(d.src))
 	}
 
 	return d
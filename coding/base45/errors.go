@@ -1,6 +1,10 @@
 package base45
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // InvalidLengthError represents an error when the base45 input length is invalid.
 // Base45 requires input length to be congruent to 0 or 2 modulo 3.
@@ -16,6 +20,16 @@ func (e InvalidLengthError) Error() string {
 	return fmt.Sprintf("coding/base45: invalid length n=%d. It should be n mod 3 = [0, 2] NOT n mod 3 = %d", e.Length, e.Mod)
 }
 
+// Code returns the stable error code DGL-BASE45-001.
+func (e InvalidLengthError) Code() string {
+	return "DGL-BASE45-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidLengthError) Fields() map[string]any {
+	return errcode.NewFields("coding/base45", "Base45", "", "length", e.Length, "mod", e.Mod)
+}
+
 // InvalidCharacterError represents an error when an invalid character is found
 // in base45 input. This error occurs when a character is not part of the
 // base45 alphabet or is outside the valid range.
@@ -30,6 +44,16 @@ func (e InvalidCharacterError) Error() string {
 	return fmt.Sprintf("coding/base45: invalid character %s at position: %d", string(e.Char), e.Position)
 }
 
+// Code returns the stable error code DGL-BASE45-002.
+func (e InvalidCharacterError) Code() string {
+	return "DGL-BASE45-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCharacterError) Fields() map[string]any {
+	return errcode.NewFields("coding/base45", "Base45", "", "char", e.Char, "position", e.Position)
+}
+
 // CorruptInputError represents an error when corrupted or invalid base45 data
 // is detected during decoding. This error occurs when the decoded value
 // exceeds the expected range or when the input data is malformed.
@@ -40,3 +64,13 @@ type CorruptInputError int64
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/base45: illegal data at input byte %d", int64(e))
 }
+
+// Code returns the stable error code DGL-BASE45-003.
+func (e CorruptInputError) Code() string {
+	return "DGL-BASE45-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/base45", "Base45", "decode", "offset", int64(e))
+}
@@ -1,6 +1,10 @@
 package base58
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // AlphabetSizeError represents an error when the base58 alphabet is invalid.
 // Base58 requires an alphabet of exactly 58 characters for proper encoding
@@ -14,6 +18,16 @@ func (e AlphabetSizeError) Error() string {
 	return fmt.Sprintf("coding/base58: invalid alphabet, the alphabet length must be 58, got %d", int(e))
 }
 
+// Code returns the stable error code DGL-BASE58-001.
+func (e AlphabetSizeError) Code() string {
+	return "DGL-BASE58-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AlphabetSizeError) Fields() map[string]any {
+	return errcode.NewFields("coding/base58", "Base58", "", "alphabet_size", int(e))
+}
+
 // CorruptInputError represents an error when corrupted or invalid base58 data
 // is detected during decoding. This error occurs when an invalid character
 // is found in the input or when the input data is malformed.
@@ -24,3 +38,13 @@ type CorruptInputError int64
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/base58: illegal data at input byte %d", int64(e))
 }
+
+// Code returns the stable error code DGL-BASE58-002.
+func (e CorruptInputError) Code() string {
+	return "DGL-BASE58-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/base58", "Base58", "decode", "offset", int64(e))
+}
@@ -1,6 +1,10 @@
 package base62
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // AlphabetSizeError represents an error when the base62 alphabet is invalid.
 // Base62 requires an alphabet of exactly 62 characters for proper encoding
@@ -14,6 +18,16 @@ func (e AlphabetSizeError) Error() string {
 	return fmt.Sprintf("coding/base62: invalid alphabet, the alphabet length must be 62, got %d", int(e))
 }
 
+// Code returns the stable error code DGL-BASE62-001.
+func (e AlphabetSizeError) Code() string {
+	return "DGL-BASE62-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AlphabetSizeError) Fields() map[string]any {
+	return errcode.NewFields("coding/base62", "Base62", "", "alphabet_size", int(e))
+}
+
 // CorruptInputError represents an error when corrupted or invalid base62 data
 // is detected during decoding. This error occurs when an invalid character
 // is found in the input or when the input data is malformed.
@@ -24,3 +38,13 @@ type CorruptInputError int64
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/base62: illegal data at input byte %d", int64(e))
 }
+
+// Code returns the stable error code DGL-BASE62-002.
+func (e CorruptInputError) Code() string {
+	return "DGL-BASE62-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/base62", "Base62", "decode", "offset", int64(e))
+}
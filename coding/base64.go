@@ -44,7 +44,7 @@ func (d Decoder) ByBase64() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = base64.NewStdDecoder(base64.StdAlphabet).Decode(d.src)
+		d.dst, d.corrections, d.Error = d.decode(base64.NewStdDecoder(base64.StdAlphabet))
 	}
 
 	return d
@@ -88,7 +88,7 @@ func (d Decoder) ByBase64Url() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = base64.NewStdDecoder(base64.URLAlphabet).Decode(d.src)
+		d.dst, d.corrections, d.Error = d.decode(base64.NewStdDecoder(base64.URLAlphabet))
 	}
 
 	return d
@@ -7,6 +7,8 @@ package base64
 import (
 	"encoding/base64"
 	"io"
+
+	"github.com/dromara/dongle/coding/internal/tolerant"
 )
 
 // StdAlphabet is the standard base64 alphabet as defined in RFC 4648.
@@ -112,6 +114,40 @@ func (d *StdDecoder) Decode(src []byte) (dst []byte, err error) {
 	return buf[:n], nil
 }
 
+// DecodeTolerant decodes base64 typed or pasted by a person, such as a key
+// copied from an email or a terminal. It ignores whitespace and line breaks and
+// accepts input with or without padding. With mapConfusables it also replaces
+// the characters of the other base64 alphabet, '-' and '_' for '+' and '/' or
+// the reverse, and returns the number of characters replaced, so that callers
+// can ask for confirmation before using a corrected value.
+func (d *StdDecoder) DecodeTolerant(src []byte, mapConfusables bool) (dst []byte, corrections int, err error) {
+	if d.Error != nil {
+		return nil, 0, d.Error
+	}
+	clean, positions, corrections, offset := tolerant.Clean(src, d.alphabet, "", mapConfusables)
+	if offset >= 0 {
+		d.Error = CorruptInputError(offset)
+		return nil, 0, d.Error
+	}
+	if len(clean) == 0 {
+		return nil, corrections, nil
+	}
+
+	encoding := d.encoding.WithPadding(base64.NoPadding)
+	buf := make([]byte, encoding.DecodedLen(len(clean)))
+	n, err := encoding.Decode(buf, clean)
+	if err != nil {
+		// Report the position in the original input.
+		pos := int64(len(src))
+		if e, ok := err.(base64.CorruptInputError); ok && int(e) < len(positions) {
+			pos = int64(positions[e])
+		}
+		d.Error = CorruptInputError(pos)
+		return nil, 0, d.Error
+	}
+	return buf[:n], corrections, nil
+}
+
 // StreamEncoder represents a streaming base64 encoder that implements io.WriteCloser.
 // It provides efficient encoding for large data streams by processing data
 // in chunks and writing encoded output immediately.
@@ -253,6 +253,73 @@ func TestStdDecoder_Decode(t *testing.T) {
 	})
 }
 
+func TestStdDecoder_DecodeTolerant(t *testing.T) {
+	// "+/+/aGVsbG8=" in StdAlphabet, "-_-_aGVsbG8=" in URLAlphabet
+	data := []byte{0xfb, 0xff, 0xbf, 0x68, 0x65, 0x6c, 0x6c, 0x6f}
+
+	t.Run("formatting", func(t *testing.T) {
+		for _, src := range []string{
+			"+/+/aGVsbG8=",
+			"+/+/aGVsbG8",
+			" +/+/\r\naGVs\r\nbG8=\r\n",
+			"+/+/aGVsbG8==",
+		} {
+			dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte(src), false)
+			assert.NoError(t, err, src)
+			assert.Equal(t, data, dst, src)
+			assert.Zero(t, corrections, src)
+		}
+	})
+
+	t.Run("other alphabet", func(t *testing.T) {
+		dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte("-_-_aGVsbG8"), true)
+		assert.NoError(t, err)
+		assert.Equal(t, data, dst)
+		assert.Equal(t, 4, corrections)
+
+		dst, corrections, err = NewStdDecoder(URLAlphabet).DecodeTolerant([]byte("+/-_aGVsbG8="), true)
+		assert.NoError(t, err)
+		assert.Equal(t, data, dst)
+		assert.Equal(t, 2, corrections)
+
+		decoder := NewStdDecoder(URLAlphabet)
+		_, _, err = decoder.DecodeTolerant([]byte("+/-_aGVsbG8="), false)
+		assert.Equal(t, CorruptInputError(0), err)
+		assert.Equal(t, CorruptInputError(0), decoder.Error)
+	})
+
+	t.Run("case is significant", func(t *testing.T) {
+		dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte("AGVSBG8"), true)
+		assert.NoError(t, err)
+		assert.Zero(t, corrections)
+		assert.NotEqual(t, []byte("hello"), dst)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		dst, corrections, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte("\n"), true)
+		assert.NoError(t, err)
+		assert.Empty(t, dst)
+		assert.Zero(t, corrections)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for src, pos := range map[string]CorruptInputError{
+			"aGVs=bG8": 4,
+			"aGVs bG!": 7,
+			"aGVsb":    4,
+			"a.Vs":     1,
+		} {
+			_, _, err := NewStdDecoder(StdAlphabet).DecodeTolerant([]byte(src), true)
+			assert.Equal(t, pos, err, src)
+		}
+	})
+
+	t.Run("invalid alphabet", func(t *testing.T) {
+		_, _, err := NewStdDecoder("abc").DecodeTolerant([]byte("aGVs"), true)
+		assert.Equal(t, AlphabetSizeError(3), err)
+	})
+}
+
 func TestNewStreamEncoder(t *testing.T) {
 	t.Run("new stream encoder", func(t *testing.T) {
 		file := mock.NewFile(nil, "test.txt")
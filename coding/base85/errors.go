@@ -1,6 +1,10 @@
 package base85
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // CorruptInputError represents an error when corrupted or invalid base85 data
 // is detected during decoding. This error occurs when an invalid character
@@ -12,3 +16,13 @@ type CorruptInputError int64
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/base85: illegal data at input byte %d", int64(e))
 }
+
+// Code returns the stable error code DGL-BASE85-001.
+func (e CorruptInputError) Code() string {
+	return "DGL-BASE85-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/base85", "Base85", "decode", "offset", int64(e))
+}
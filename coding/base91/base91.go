@@ -301,7 +301,9 @@ func (e *StreamEncoder) Close() error {
 
 // StreamDecoder represents a streaming base91 decoder that implements io.Reader.
 // It provides efficient decoding for large data streams by processing data
-// in chunks and maintaining an internal buffer for partial reads.
+// in chunks and maintaining an internal buffer for partial reads. The bit
+// queue and a value split across reads are carried over to the next chunk, so
+// the output does not depend on how the underlying reader splits the input.
 type StreamDecoder struct {
 	reader    io.Reader  // Underlying reader for encoded input
 	buffer    []byte     // Buffer for decoded data not yet read
@@ -309,20 +311,20 @@ type StreamDecoder struct {
 	readBuf   [1024]byte // Reusable buffer for reading encoded data
 	decodeMap [256]byte  // Lookup table for fast decoding of characters to values
 	alphabet  string     // The alphabet used for decoding
+	queue     uint       // Bit accumulator for decoding state
+	numBits   uint       // Number of bits in queue
+	value     int        // First character value of an incomplete pair, or -1
+	offset    int64      // Input offset of the chunk being decoded
+	eof       bool       // Whether the underlying reader is exhausted
 	Error     error      // Error field for storing decoding errors
 }
 
 // NewStreamDecoder creates a new streaming base91 decoder that reads encoded data
 // from the provided io.Reader. The decoder uses the standard base91 alphabet.
 func NewStreamDecoder(r io.Reader) io.Reader {
-	d := &StreamDecoder{reader: r, alphabet: StdAlphabet}
-	// Initialize decode map once
-	for i := range d.decodeMap {
-		d.decodeMap[i] = 0xFF
-	}
-	for i, char := range StdAlphabet {
-		d.decodeMap[byte(char)] = byte(i)
-	}
+	d := &StreamDecoder{reader: r, alphabet: StdAlphabet, value: -1}
+	// Copy the pre-initialized global decode map
+	d.decodeMap = stdDecodeMap
 	return d
 }
 
@@ -330,94 +332,107 @@ func NewStreamDecoder(r io.Reader) io.Reader {
 // Reads and decodes base91 data from the underlying reader in chunks.
 // Maintains an internal buffer to handle partial reads efficiently.
 func (d *StreamDecoder) Read(p []byte) (n int, err error) {
-	if d.Error != nil {
-		return 0, d.Error
-	}
+	for {
+		if d.Error != nil {
+			return 0, d.Error
+		}
 
-	// Return buffered data if available
-	if d.pos < len(d.buffer) {
-		n = copy(p, d.buffer[d.pos:])
-		d.pos += n
-		return n, nil
-	}
+		// Return buffered data if available
+		if d.pos < len(d.buffer) {
+			n = copy(p, d.buffer[d.pos:])
+			d.pos += n
+			return n, nil
+		}
 
-	// Read encoded data in chunks using reusable buffer
-	rn, err := d.reader.Read(d.readBuf[:])
-	if err != nil && err != io.EOF {
-		return 0, err
-	}
+		if d.eof {
+			return 0, io.EOF
+		}
 
-	if rn == 0 {
-		return 0, io.EOF
-	}
+		// Read encoded data in chunks using reusable buffer. A chunk ending in
+		// the middle of a pair is followed by the next one, or the end of the
+		// input, before returning, as only then is its last byte known.
+		d.buffer, d.pos = d.buffer[:0], 0
+		for {
+			rn, err := d.reader.Read(d.readBuf[:])
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
 
-	// Decode the data directly using internal decode map
-	decoded, err := d.decode(d.readBuf[:rn])
-	if err != nil {
-		return 0, err
-	}
+			decoded, derr := d.decode(d.readBuf[:rn])
+			if derr != nil {
+				d.Error = derr
+				return 0, derr
+			}
+			d.buffer = append(d.buffer, decoded...)
 
-	// Copy decoded data to the provided buffer
-	copied := copy(p, decoded)
-	if copied < len(decoded) {
-		// Buffer remaining data for next read
-		d.buffer = decoded[copied:]
-		d.pos = 0
+			if err == io.EOF {
+				// Flush the value of a trailing single character
+				d.eof = true
+				d.buffer = d.flush(d.buffer)
+				break
+			}
+			if d.value == -1 {
+				break
+			}
+		}
 	}
-
-	return copied, nil
 }
 
-// decode decodes base91 data using the internal decode map
+// decode decodes a chunk of base91 data, continuing from the state left by
+// the previous chunk.
 func (d *StreamDecoder) decode(src []byte) ([]byte, error) {
 	if len(src) == 0 {
 		return nil, nil
 	}
 
-	// Calculate the maximum output size for pre-allocation
-	maxLen := int(math.Ceil(float64(len(src)) * 14.0 / 16.0))
+	// Calculate the maximum output size for pre-allocation, a value carried
+	// over from the previous chunk can add up to two bytes
+	maxLen := int(math.Ceil(float64(len(src))*14.0/16.0)) + 2
 	dst := make([]byte, maxLen)
-
-	var queue, numBits uint
-	var v = -1
 	n := 0
 
 	for i := range src {
 		if d.decodeMap[src[i]] == 0xFF {
 			// The character is not in the encoding alphabet.
-			return nil, CorruptInputError(int64(i))
+			return nil, CorruptInputError(d.offset + int64(i))
 		}
 
-		if v == -1 {
+		if d.value == -1 {
 			// Start the next value.
-			v = int(d.decodeMap[src[i]])
+			d.value = int(d.decodeMap[src[i]])
 		} else {
-			v += int(d.decodeMap[src[i]]) * 91
-			queue |= uint(v) << numBits
+			v := d.value + int(d.decodeMap[src[i]])*91
+			d.queue |= uint(v) << d.numBits
 
 			if (v & 8191) > 88 {
-				numBits += 13
+				d.numBits += 13
 			} else {
-				numBits += 14
+				d.numBits += 14
 			}
 
-			for ok := true; ok; ok = numBits > 7 {
-				dst[n] = byte(queue)
+			for ok := true; ok; ok = d.numBits > 7 {
+				dst[n] = byte(d.queue)
 				n++
 
-				queue >>= 8
-				numBits -= 8
+				d.queue >>= 8
+				d.numBits -= 8
 			}
 
 			// Mark this value complete.
-			v = -1
+			d.value = -1
 		}
 	}
-
-	if v != -1 {
-		dst[n] = byte(queue | uint(v)<<numBits)
-		n++
-	}
+	d.offset += int64(len(src))
 
 	return dst[:n], nil
 }
+
+// flush appends the byte held by a trailing single character to dst.
+func (d *StreamDecoder) flush(dst []byte) []byte {
+	if d.value != -1 {
+		dst = append(dst, byte(d.queue|uint(d.value)<<d.numBits))
+		d.value = -1
+	}
+	d.queue, d.numBits = 0, 0
+	return dst
+}
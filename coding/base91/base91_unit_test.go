@@ -667,3 +667,51 @@ func TestStreamError(t *testing.T) {
 		assert.Equal(t, 2, errorWriter.WriteCount()) // Verify 2 writes were attempted
 	})
 }
+
+// TestStreamDecoder_ChunkBoundaries tests decoding with pairs split across reads.
+func TestStreamDecoder_ChunkBoundaries(t *testing.T) {
+	t.Run("one byte at a time", func(t *testing.T) {
+		decoded, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader([]byte("TPwJh>Io2Tv!lE"))).Then(1).Repeat()))
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", string(decoded))
+	})
+
+	t.Run("odd chunks", func(t *testing.T) {
+		encoded := NewStdEncoder().Encode(bytes.Repeat([]byte("base91 stream "), 200))
+		decoded, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader(encoded)).Then(3).Then(1).Then(1024).Then(7).Repeat()))
+		assert.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte("base91 stream "), 200), decoded)
+	})
+
+	t.Run("corrupt input offset", func(t *testing.T) {
+		decoder := NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader([]byte("TPwJh>I 2Tv!lE"))).Then(3).Repeat())
+		_, err := io.ReadAll(decoder)
+		assert.Equal(t, CorruptInputError(7), err)
+		// The error sticks.
+		_, err = decoder.Read(make([]byte, 1))
+		assert.Equal(t, CorruptInputError(7), err)
+	})
+}
+
+// FuzzStreamRoundTrip checks that streaming matches one-shot encoding and
+// round-trips whatever the write and read boundaries.
+func FuzzStreamRoundTrip(f *testing.F) {
+	f.Add([]byte("hello world"), uint8(0), uint8(2))
+	f.Add([]byte{}, uint8(0), uint8(0))
+	f.Add(bytes.Repeat([]byte{0xff}, 100), uint8(6), uint8(1))
+	f.Add(bytes.Repeat([]byte{0x00}, 100), uint8(12), uint8(4))
+	f.Fuzz(func(t *testing.T, data []byte, write, read uint8) {
+		var buf bytes.Buffer
+		encoder := NewStreamEncoder(&buf)
+		for chunk := data; len(chunk) > 0; chunk = chunk[min(len(chunk), int(write)+1):] {
+			_, err := encoder.Write(chunk[:min(len(chunk), int(write)+1)])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, encoder.Close())
+		assert.Equal(t, string(NewStdEncoder().Encode(data)), buf.String())
+
+		decoded, err := io.ReadAll(NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader(buf.Bytes())).Then(int(read) + 1).Then(1).Then(max(int(write), 1)).Repeat()))
+		assert.NoError(t, err)
+		assert.Equal(t, string(data), string(decoded))
+	})
+}
@@ -1,6 +1,10 @@
 package base91
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // AlphabetSizeError represents an error when the base91 alphabet is invalid.
 // Base91 requires an alphabet of exactly 91 characters for proper encoding
@@ -14,6 +18,16 @@ func (e AlphabetSizeError) Error() string {
 	return fmt.Sprintf("coding/base91: invalid alphabet, the alphabet length must be 91, got %d", int(e))
 }
 
+// Code returns the stable error code DGL-BASE91-001.
+func (e AlphabetSizeError) Code() string {
+	return "DGL-BASE91-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AlphabetSizeError) Fields() map[string]any {
+	return errcode.NewFields("coding/base91", "Base91", "", "alphabet_size", int(e))
+}
+
 // CorruptInputError represents an error when corrupted or invalid base91 data
 // is detected during decoding. This error occurs when an invalid character
 // is found in the input or when the input data is malformed.
@@ -24,3 +38,13 @@ type CorruptInputError int64
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/base91: illegal data at input byte %d", int64(e))
 }
+
+// Code returns the stable error code DGL-BASE91-002.
+func (e CorruptInputError) Code() string {
+	return "DGL-BASE91-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/base91", "Base91", "decode", "offset", int64(e))
+}
@@ -1,6 +1,8 @@
 // Package coding provides encoding and decoding utilities for various data formats.
 // It includes common constants and helper functions used across different encoding
 // implementations such as Base64, Hex, and other data transformation operations.
+// NewStreamEncoder and NewStreamDecoder expose every codec by name as a plain
+// io.WriteCloser or io.Reader, outside the fluent Encoder and Decoder chains.
 package coding
 
 // BufferSize buffer size for streaming (64KB is a good balance)
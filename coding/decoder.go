@@ -10,10 +10,15 @@ import (
 
 // Decoder defines a Decoder struct.
 type Decoder struct {
-	src    []byte
-	dst    []byte
-	reader io.Reader
-	Error  error
+	src     []byte
+	dst     []byte
+	reader  io.Reader
+	maxSize int64
+	// Tolerant decoding, see WithTolerance
+	tolerant    bool
+	confusables bool
+	corrections int
+	Error       error
 }
 
 // NewDecoder returns a new Decoder instance.
@@ -23,22 +28,66 @@ func NewDecoder() Decoder {
 
 // FromString decodes from string.
 func (d Decoder) FromString(s string) Decoder {
+	utils.AuditShared()
 	d.src = utils.String2Bytes(s)
 	return d
 }
 
 // FromBytes decodes from byte slice.
 func (d Decoder) FromBytes(b []byte) Decoder {
+	utils.AuditShared()
 	d.src = b
 	return d
 }
 
 // FromFile decodes from file.
 func (d Decoder) FromFile(f fs.File) Decoder {
+	utils.AuditShared()
 	d.reader = f
 	return d
 }
 
+// FromFS decodes from the named file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (d Decoder) FromFS(fsys fs.FS, name string) Decoder {
+	utils.AuditShared()
+	if d.Error != nil {
+		return d
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		d.Error = err
+		return d
+	}
+	return d.FromFile(f)
+}
+
+// WithMaxSize limits the decoded output to n bytes. Once the output would exceed
+// n bytes, streaming stops and a SizeLimitError is returned, which protects
+// services handling untrusted input from memory exhaustion. Zero means no limit.
+func (d Decoder) WithMaxSize(n int64) Decoder {
+	d.maxSize = n
+	return d
+}
+
+// WithTolerance makes ByBase32, ByBase32Hex, ByBase64 and ByBase64Url accept
+// string and byte slice input typed or pasted by a person: whitespace and line
+// breaks are ignored, padding is optional and base32 may be in either case.
+// With mapConfusables, characters outside the alphabet are also replaced by
+// the ones they are easily mistaken for, and Corrections reports how many were
+// replaced. File input is decoded as usual.
+func (d Decoder) WithTolerance(mapConfusables bool) Decoder {
+	d.tolerant = true
+	d.confusables = mapConfusables
+	return d
+}
+
+// Corrections returns the number of characters replaced by a tolerant decoding.
+func (d Decoder) Corrections() int {
+	return d.corrections
+}
+
 // ToString outputs as string.
 func (d Decoder) ToString() string {
 	if len(d.dst) == 0 || d.Error != nil {
@@ -55,7 +104,13 @@ func (d Decoder) ToBytes() []byte {
 	return d.dst
 }
 
+// To outputs as a Result exposing the decoded bytes in several encodings.
+func (d Decoder) To() Result {
+	return NewResult(d.ToBytes(), d.Error)
+}
+
 func (d Decoder) stream(fn func(io.Reader) io.Reader) ([]byte, error) {
+	defer utils.CloseFS(d.reader)
 	var buf bytes.Buffer
 	decoder := fn(d.reader)
 
@@ -63,7 +118,11 @@ func (d Decoder) stream(fn func(io.Reader) io.Reader) ([]byte, error) {
 	if seeker, ok := d.reader.(io.Seeker); ok {
 		seeker.Seek(0, io.SeekStart)
 	}
-	if _, err := io.CopyBuffer(&buf, decoder, make([]byte, BufferSize)); err != nil && err != io.EOF {
+	var w io.Writer = &buf
+	if d.maxSize > 0 {
+		w = utils.LimitWriter(&buf, d.maxSize, SizeLimitError{Limit: d.maxSize})
+	}
+	if _, err := io.CopyBuffer(w, decoder, make([]byte, BufferSize)); err != nil && err != io.EOF {
 		return []byte{}, err
 	}
 	if buf.Len() == 0 {
@@ -71,3 +130,29 @@ func (d Decoder) stream(fn func(io.Reader) io.Reader) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// limit enforces the maximum output size set by WithMaxSize.
+// tolerantDecoder is implemented by the decoders supporting WithTolerance.
+type tolerantDecoder interface {
+	Decode(src []byte) ([]byte, error)
+	DecodeTolerant(src []byte, mapConfusables bool) ([]byte, int, error)
+}
+
+// decode decodes the input with dec, tolerantly when requested by WithTolerance,
+// and returns the number of corrections.
+func (d Decoder) decode(dec tolerantDecoder) ([]byte, int, error) {
+	if !d.tolerant {
+		dst, err := d.limit(dec.Decode(d.src))
+		return dst, 0, err
+	}
+	dst, corrections, err := dec.DecodeTolerant(d.src, d.confusables)
+	dst, err = d.limit(dst, err)
+	return dst, corrections, err
+}
+
+func (d Decoder) limit(dst []byte, err error) ([]byte, error) {
+	if err == nil && d.maxSize > 0 && int64(len(dst)) > d.maxSize {
+		return []byte{}, SizeLimitError{Limit: d.maxSize}
+	}
+	return dst, err
+}
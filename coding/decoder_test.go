@@ -3,7 +3,10 @@ package coding
 import (
 	"errors"
 	"io"
+	"io/fs"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
@@ -229,3 +232,91 @@ func TestDecoder_stream(t *testing.T) {
 		assert.Equal(t, []byte{}, out)
 	})
 }
+
+func TestDecoder_WithMaxSize(t *testing.T) {
+	t.Run("standard decoding within limit", func(t *testing.T) {
+		decoder := NewDecoder().FromString("68656c6c6f").WithMaxSize(5).ByHex()
+		assert.Nil(t, decoder.Error)
+		assert.Equal(t, "hello", decoder.ToString())
+	})
+
+	t.Run("standard decoding exceeds limit", func(t *testing.T) {
+		decoder := NewDecoder().FromString("68656c6c6f").WithMaxSize(4).ByHex()
+		assert.Equal(t, SizeLimitError{Limit: 4}, decoder.Error)
+		assert.Equal(t, "", decoder.ToString())
+		assert.Equal(t, "coding: decoded output exceeds the limit of 4 bytes", decoder.Error.Error())
+	})
+
+	t.Run("streaming decoding exceeds limit", func(t *testing.T) {
+		file := mock.NewFile([]byte(strings.Repeat("41", 100000)), "test.txt")
+		decoder := NewDecoder().FromFile(file).WithMaxSize(1024).ByHex()
+		assert.Equal(t, SizeLimitError{Limit: 1024}, decoder.Error)
+		assert.Empty(t, decoder.ToBytes())
+	})
+
+	t.Run("streaming decoding within limit", func(t *testing.T) {
+		file := mock.NewFile([]byte(strings.Repeat("41", 1000)), "test.txt")
+		decoder := NewDecoder().FromFile(file).WithMaxSize(1000).ByHex()
+		assert.Nil(t, decoder.Error)
+		assert.Len(t, decoder.ToBytes(), 1000)
+	})
+
+	t.Run("zero means no limit", func(t *testing.T) {
+		decoder := NewDecoder().FromString("68656c6c6f").WithMaxSize(0).ByHex()
+		assert.Nil(t, decoder.Error)
+		assert.Equal(t, "hello", decoder.ToString())
+	})
+
+	t.Run("decoding error takes precedence", func(t *testing.T) {
+		decoder := NewDecoder().FromString("zz").WithMaxSize(1).ByHex()
+		assert.Error(t, decoder.Error)
+		assert.NotEqual(t, SizeLimitError{Limit: 1}, decoder.Error)
+	})
+}
+
+func TestDecoder_FromFS(t *testing.T) {
+	fsys := fstest.MapFS{"hello.txt": {Data: []byte("aGVsbG8gd29ybGQ=")}}
+
+	decoder := NewDecoder().FromFS(fsys, "hello.txt").ByBase64()
+	assert.Nil(t, decoder.Error)
+	assert.Equal(t, "hello world", decoder.ToString())
+
+	decoder = NewDecoder().FromFS(fsys, "missing.txt").ByBase64()
+	assert.ErrorIs(t, decoder.Error, fs.ErrNotExist)
+}
+
+func TestDecoder_WithTolerance(t *testing.T) {
+	t.Run("base32", func(t *testing.T) {
+		decoder := NewDecoder().FromString("nbsw-y3dp\r\neb3w-64tm-mq").WithTolerance(false).ByBase32()
+		assert.Nil(t, decoder.Error)
+		assert.Equal(t, "hello world", decoder.ToString())
+		assert.Zero(t, decoder.Corrections())
+	})
+
+	t.Run("base64", func(t *testing.T) {
+		decoder := NewDecoder().FromBytes([]byte("aGVsbG8g\nd29ybGQ")).WithTolerance(false).ByBase64()
+		assert.Nil(t, decoder.Error)
+		assert.Equal(t, "hello world", decoder.ToString())
+	})
+
+	t.Run("confusables", func(t *testing.T) {
+		decoder := NewDecoder().FromString("N8SW Y3DP").WithTolerance(true).ByBase32()
+		assert.Nil(t, decoder.Error)
+		assert.Equal(t, "hello", decoder.ToString())
+		assert.Equal(t, 1, decoder.Corrections())
+
+		decoder = NewDecoder().FromString("N8SW Y3DP").WithTolerance(false).ByBase32()
+		assert.Error(t, decoder.Error)
+		assert.Zero(t, decoder.Corrections())
+	})
+
+	t.Run("strict by default", func(t *testing.T) {
+		decoder := NewDecoder().FromString("nbsw y3dp").ByBase32()
+		assert.Error(t, decoder.Error)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		decoder := NewDecoder().FromString("NBSW Y3DP").WithTolerance(false).WithMaxSize(4).ByBase32()
+		assert.Equal(t, SizeLimitError{Limit: 4}, decoder.Error)
+	})
+}
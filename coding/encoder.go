@@ -23,22 +23,41 @@ func NewEncoder() Encoder {
 
 // FromString encodes from string.
 func (e Encoder) FromString(s string) Encoder {
+	utils.AuditShared()
 	e.src = utils.String2Bytes(s)
 	return e
 }
 
 // FromBytes encodes from byte slice.
 func (e Encoder) FromBytes(b []byte) Encoder {
+	utils.AuditShared()
 	e.src = b
 	return e
 }
 
 // FromFile encodes from file.
 func (e Encoder) FromFile(f fs.File) Encoder {
+	utils.AuditShared()
 	e.reader = f
 	return e
 }
 
+// FromFS encodes from the named file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (e Encoder) FromFS(fsys fs.FS, name string) Encoder {
+	utils.AuditShared()
+	if e.Error != nil {
+		return e
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		e.Error = err
+		return e
+	}
+	return e.FromFile(f)
+}
+
 // ToString outputs as string.
 func (e Encoder) ToString() string {
 	if len(e.dst) == 0 || e.Error != nil {
@@ -55,7 +74,13 @@ func (e Encoder) ToBytes() []byte {
 	return e.dst
 }
 
+// To outputs as a Result exposing the encoded bytes in several encodings.
+func (e Encoder) To() Result {
+	return NewResult(e.ToBytes(), e.Error)
+}
+
 func (e Encoder) stream(fn func(io.Writer) io.WriteCloser) ([]byte, error) {
+	defer utils.CloseFS(e.reader)
 	var buf bytes.Buffer
 	encoder := fn(&buf)
 
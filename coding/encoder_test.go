@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"testing"
+	"testing/fstest"
 
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
@@ -278,3 +279,15 @@ func TestEncoder_Error(t *testing.T) {
 		assert.Equal(t, []byte{}, result)
 	})
 }
+
+func TestEncoder_FromFS(t *testing.T) {
+	fsys := fstest.MapFS{"hello.txt": {Data: []byte("hello world")}}
+
+	encoder := NewEncoder().FromFS(fsys, "hello.txt").ByBase64()
+	assert.Nil(t, encoder.Error)
+	assert.Equal(t, "aGVsbG8gd29ybGQ=", encoder.ToString())
+
+	encoder = NewEncoder().FromFS(fsys, "missing.txt").ByBase64()
+	assert.Error(t, encoder.Error)
+	assert.Empty(t, encoder.ToString())
+}
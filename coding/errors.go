@@ -0,0 +1,49 @@
+package coding
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// SizeLimitError represents an error when the decoded output exceeds the
+// maximum size set by WithMaxSize.
+type SizeLimitError struct {
+	Limit int64 // The maximum output size in bytes
+}
+
+// Error returns a formatted error message describing the exceeded limit.
+func (e SizeLimitError) Error() string {
+	return fmt.Sprintf("coding: decoded output exceeds the limit of %d bytes", e.Limit)
+}
+
+// Code returns the stable error code DGL-CODING-001.
+func (e SizeLimitError) Code() string {
+	return "DGL-CODING-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SizeLimitError) Fields() map[string]any {
+	return errcode.NewFields("coding", "", "decode", "limit", e.Limit)
+}
+
+// UnknownCodecError represents an error when NewStreamEncoder or
+// NewStreamDecoder is given a codec name it does not know.
+type UnknownCodecError struct {
+	Name string // The unknown codec name
+}
+
+// Error returns a formatted error message describing the unknown codec.
+func (e UnknownCodecError) Error() string {
+	return fmt.Sprintf("coding: unknown codec %q", e.Name)
+}
+
+// Code returns the stable error code DGL-CODING-002.
+func (e UnknownCodecError) Code() string {
+	return "DGL-CODING-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnknownCodecError) Fields() map[string]any {
+	return errcode.NewFields("coding", e.Name, "")
+}
@@ -44,7 +44,7 @@ func (d Decoder) ByHex() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = hex.NewStdDecoder().Decode(d.src)
+		d.dst, d.Error = d.limit(hex.NewStdDecoder().Decode(d.src))
 	}
 
 	return d
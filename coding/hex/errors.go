@@ -1,6 +1,10 @@
 package hex
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // AlphabetSizeError represents an error when the hex alphabet is invalid.
 // Hex requires an alphabet of exactly 16 characters for proper encoding
@@ -14,6 +18,16 @@ func (e AlphabetSizeError) Error() string {
 	return fmt.Sprintf("coding/hex: invalid alphabet, the alphabet length must be 16, got %d", int(e))
 }
 
+// Code returns the stable error code DGL-HEX-001.
+func (e AlphabetSizeError) Code() string {
+	return "DGL-HEX-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AlphabetSizeError) Fields() map[string]any {
+	return errcode.NewFields("coding/hex", "Hex", "", "alphabet_size", int(e))
+}
+
 // CorruptInputError represents an error when corrupted or invalid hex data
 // is detected during decoding. This error occurs when an invalid character
 // is found in the input or when the input data is malformed.
@@ -24,3 +38,32 @@ type CorruptInputError int
 func (e CorruptInputError) Error() string {
 	return fmt.Sprintf("coding/hex: illegal data at input byte %d", int(e))
 }
+
+// Code returns the stable error code DGL-HEX-002.
+func (e CorruptInputError) Code() string {
+	return "DGL-HEX-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CorruptInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/hex", "Hex", "decode", "offset", int(e))
+}
+
+// InvalidSeparatorError represents an error when a Format uses a group
+// separator that ParseLenient would not skip, such as a hex digit.
+type InvalidSeparatorError byte
+
+// Error returns a formatted error message describing the invalid separator.
+func (e InvalidSeparatorError) Error() string {
+	return fmt.Sprintf("coding/hex: invalid group separator %q", byte(e))
+}
+
+// Code returns the stable error code DGL-HEX-003.
+func (e InvalidSeparatorError) Code() string {
+	return "DGL-HEX-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSeparatorError) Fields() map[string]any {
+	return errcode.NewFields("coding/hex", "Hex", "encode", "separator", string(rune(e)))
+}
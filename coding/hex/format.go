@@ -0,0 +1,98 @@
+package hex
+
+import "strings"
+
+// Format describes how hex strings are presented to users, for example
+// fingerprints or license-key-like codes derived from hashes.
+type Format struct {
+	Uppercase bool // Use A-F instead of a-f
+	GroupSize int  // Number of hex digits per group, no grouping when zero or less
+	Separator byte // Separator between groups, '-' when zero; one of "-:._" or whitespace
+}
+
+// Validate returns an InvalidSeparatorError if the separator is not one that
+// ParseLenient skips, which also rules out hex digits.
+func (f Format) Validate() error {
+	if f.Separator != 0 && !isSeparator(f.Separator) {
+		return InvalidSeparatorError(f.Separator)
+	}
+	return nil
+}
+
+// Encode returns src as a hex string in the format, or an empty string if the
+// format is invalid, see Validate.
+func (f Format) Encode(src []byte) string {
+	if f.Validate() != nil {
+		return ""
+	}
+	digits := "0123456789abcdef"
+	if f.Uppercase {
+		digits = "0123456789ABCDEF"
+	}
+	sep := f.Separator
+	if sep == 0 {
+		sep = '-'
+	}
+	var b strings.Builder
+	n := 2 * len(src)
+	if f.GroupSize > 0 && n > 0 {
+		n += (n - 1) / f.GroupSize
+	}
+	b.Grow(n)
+	written := 0
+	for _, c := range src {
+		for _, d := range [2]byte{digits[c>>4], digits[c&0x0f]} {
+			if f.GroupSize > 0 && written > 0 && written%f.GroupSize == 0 {
+				b.WriteByte(sep)
+			}
+			b.WriteByte(d)
+			written++
+		}
+	}
+	return b.String()
+}
+
+// ParseLenient decodes hex typed or pasted by users. Case is ignored, as are
+// whitespace and the separators '-', ':', '.' and '_', so it accepts the
+// output of any Format. The error reports the offset in s of an invalid
+// character, or the length of s for an odd number of digits.
+func ParseLenient(s string) ([]byte, error) {
+	dst := make([]byte, 0, len(s)/2)
+	var hi byte
+	odd := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		var v byte
+		switch {
+		case '0' <= c && c <= '9':
+			v = c - '0'
+		case 'a' <= c && c <= 'f':
+			v = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			v = c - 'A' + 10
+		case isSeparator(c):
+			continue
+		default:
+			return nil, CorruptInputError(i)
+		}
+		if odd {
+			dst = append(dst, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+	if odd {
+		return nil, CorruptInputError(len(s))
+	}
+	return dst, nil
+}
+
+// isSeparator reports whether c separates groups of hex digits.
+func isSeparator(c byte) bool {
+	switch c {
+	case '-', ':', '.', '_', ' ', '\t', '\r', '\n':
+		return true
+	}
+	return false
+}
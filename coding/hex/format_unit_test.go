@@ -0,0 +1,65 @@
+package hex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	src := []byte{0xde, 0xad, 0xbe, 0xef, 0x01, 0x23, 0x45}
+
+	t.Run("encode", func(t *testing.T) {
+		assert.Equal(t, "deadbeef012345", Format{}.Encode(src))
+		assert.Equal(t, "DEADBEEF012345", Format{Uppercase: true}.Encode(src))
+		assert.Equal(t, "DEAD-BEEF-0123-45", Format{Uppercase: true, GroupSize: 4}.Encode(src))
+		assert.Equal(t, "de:ad:be:ef:01:23:45", Format{GroupSize: 2, Separator: ':'}.Encode(src))
+		assert.Equal(t, "deadb eef01 2345", Format{GroupSize: 5, Separator: ' '}.Encode(src))
+		assert.Equal(t, "", Format{GroupSize: 4}.Encode(nil))
+	})
+
+	t.Run("invalid separator", func(t *testing.T) {
+		for _, sep := range []byte{'a', 'F', '0', '/', 'x'} {
+			f := Format{GroupSize: 4, Separator: sep}
+			assert.Equal(t, InvalidSeparatorError(sep), f.Validate())
+			assert.Empty(t, f.Encode(src))
+		}
+		assert.Equal(t, `coding/hex: invalid group separator 'a'`, InvalidSeparatorError('a').Error())
+		assert.Equal(t, "DGL-HEX-003", InvalidSeparatorError('a').Code())
+		assert.NoError(t, Format{}.Validate())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, sep := range []byte{0, '-', ':', '.', '_', ' ', '\t', '\n'} {
+			f := Format{Uppercase: true, GroupSize: 3, Separator: sep}
+			got, err := ParseLenient(f.Encode(src))
+			require.NoError(t, err)
+			assert.Equal(t, src, got)
+		}
+	})
+
+	t.Run("parse lenient", func(t *testing.T) {
+		for _, s := range []string{
+			"deadbeef012345",
+			"DEAD-BEEF-0123-45",
+			"de:ad:be:ef:01:23:45",
+			" DeAd BeEf\n0123_45 ",
+			"dead.beef.0123.45",
+		} {
+			got, err := ParseLenient(s)
+			require.NoError(t, err, s)
+			assert.Equal(t, src, got, s)
+		}
+		got, err := ParseLenient("")
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		_, err := ParseLenient("DEAD-BEEG")
+		assert.Equal(t, CorruptInputError(8), err)
+		_, err = ParseLenient("DEAD-BEE")
+		assert.Equal(t, CorruptInputError(8), err)
+	})
+}
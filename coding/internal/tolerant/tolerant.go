@@ -0,0 +1,95 @@
+// Package tolerant cleans up text encodings typed or pasted by people before
+// they are handed to a strict decoder.
+package tolerant
+
+import "strings"
+
+// confusables lists characters that are easily mistaken for one another when
+// read or typed, and the base64 characters that differ between the standard
+// and URL-safe alphabets. A character is only replaced by one in the alphabet
+// when it is not in the alphabet itself.
+var confusables = map[byte]string{
+	'0': "O",
+	'O': "0",
+	'o': "0",
+	'1': "IL",
+	'I': "1L",
+	'l': "1I",
+	'L': "1I",
+	'8': "B",
+	'B': "8",
+	'-': "+",
+	'_': "/",
+	'+': "-",
+	'/': "_",
+}
+
+// Clean returns src without whitespace, the characters of separators, trailing
+// padding and, for single case alphabets, with letters folded to the case of
+// the alphabet. With mapConfusables, characters outside the alphabet are also
+// replaced by a character of the alphabet they are easily confused with, and
+// corrections counts these replacements. The positions of the cleaned
+// characters in src are returned to translate decoder errors. Offset is the
+// position in src of the first character that cannot be cleaned, or -1.
+func Clean(src []byte, alphabet, separators string, mapConfusables bool) (clean []byte, positions []int, corrections, offset int) {
+	upper, lower := cases(alphabet)
+	clean = make([]byte, 0, len(src))
+	positions = make([]int, 0, len(src))
+	padding := -1
+	for i, c := range src {
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case strings.IndexByte(alphabet, c) < 0 && strings.IndexByte(separators, c) >= 0:
+			continue
+		case c == '=' && strings.IndexByte(alphabet, c) < 0:
+			if padding < 0 {
+				padding = i
+			}
+			continue
+		}
+		if padding >= 0 {
+			// Data after padding.
+			return nil, nil, 0, padding
+		}
+		switch {
+		case strings.IndexByte(alphabet, c) >= 0:
+		case upper && 'a' <= c && c <= 'z' && strings.IndexByte(alphabet, c-'a'+'A') >= 0:
+			c -= 'a' - 'A'
+		case lower && 'A' <= c && c <= 'Z' && strings.IndexByte(alphabet, c-'A'+'a') >= 0:
+			c += 'a' - 'A'
+		default:
+			r, found := replace(c, alphabet)
+			if !mapConfusables || !found {
+				return nil, nil, 0, i
+			}
+			c = r
+			corrections++
+		}
+		clean = append(clean, c)
+		positions = append(positions, i)
+	}
+	return clean, positions, corrections, -1
+}
+
+// replace returns the first character of the alphabet that c is confused with.
+func replace(c byte, alphabet string) (byte, bool) {
+	for _, r := range []byte(confusables[c]) {
+		if strings.IndexByte(alphabet, r) >= 0 {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// cases reports whether the letters of the alphabet are all upper case or all
+// lower case.
+func cases(alphabet string) (upper, lower bool) {
+	var hasUpper, hasLower bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		hasUpper = hasUpper || 'A' <= c && c <= 'Z'
+		hasLower = hasLower || 'a' <= c && c <= 'z'
+	}
+	return hasUpper && !hasLower, hasLower && !hasUpper
+}
@@ -0,0 +1,69 @@
+package jcs
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidJSONError represents an error when the input is not valid JSON.
+type InvalidJSONError struct {
+	Reason string // Why the input was rejected
+}
+
+// Error returns a formatted error message describing the invalid JSON.
+func (e InvalidJSONError) Error() string {
+	return fmt.Sprintf("coding/jcs: invalid JSON: %s", e.Reason)
+}
+
+// Code returns the stable error code DGL-JCS-001.
+func (e InvalidJSONError) Code() string {
+	return "DGL-JCS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidJSONError) Fields() map[string]any {
+	return errcode.NewFields("coding/jcs", "JCS", "encode", "reason", e.Reason)
+}
+
+// DuplicateKeyError represents an error when an object has two members with
+// the same name, which I-JSON forbids.
+type DuplicateKeyError struct {
+	Key string // The duplicated member name
+}
+
+// Error returns a formatted error message describing the duplicated member.
+func (e DuplicateKeyError) Error() string {
+	return fmt.Sprintf("coding/jcs: duplicate object member %q", e.Key)
+}
+
+// Code returns the stable error code DGL-JCS-002.
+func (e DuplicateKeyError) Code() string {
+	return "DGL-JCS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DuplicateKeyError) Fields() map[string]any {
+	return errcode.NewFields("coding/jcs", "JCS", "encode", "key", e.Key)
+}
+
+// InvalidNumberError represents an error when a number cannot be represented
+// as an IEEE 754 double, such as NaN, infinities or out of range literals.
+type InvalidNumberError struct {
+	Number string // The number that was rejected
+}
+
+// Error returns a formatted error message describing the invalid number.
+func (e InvalidNumberError) Error() string {
+	return fmt.Sprintf("coding/jcs: number %s cannot be canonicalized", e.Number)
+}
+
+// Code returns the stable error code DGL-JCS-003.
+func (e InvalidNumberError) Code() string {
+	return "DGL-JCS-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidNumberError) Fields() map[string]any {
+	return errcode.NewFields("coding/jcs", "JCS", "encode", "number", e.Number)
+}
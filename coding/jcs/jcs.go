@@ -0,0 +1,233 @@
+// Package jcs implements the JSON Canonicalization Scheme of RFC 8785.
+// Canonical JSON has no insignificant whitespace, object members sorted by their
+// UTF-16 encoded names, ECMAScript number formatting and minimal string escaping,
+// so that services serializing the same document differently still produce the
+// same bytes to hash or sign.
+package jcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonicalize returns the canonical form of the JSON document data. The input
+// must be valid UTF-8 I-JSON: duplicate member names, lone surrogate escapes and
+// numbers outside the range of IEEE 754 doubles are rejected.
+func Canonicalize(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, InvalidJSONError{Reason: "invalid UTF-8"}
+	}
+	if err := checkSurrogates(data); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := canonicalize(dec, &buf); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, InvalidJSONError{Reason: "trailing data"}
+	}
+	return buf.Bytes(), nil
+}
+
+// Marshal returns the canonical JSON encoding of v, marshaled with encoding/json.
+func Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, InvalidJSONError{Reason: err.Error()}
+	}
+	return Canonicalize(data)
+}
+
+// canonicalize writes the canonical form of the next value read from dec.
+func canonicalize(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return InvalidJSONError{Reason: err.Error()}
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			buf.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := canonicalize(dec, buf); err != nil {
+					return err
+				}
+			}
+			dec.Token()
+			buf.WriteByte(']')
+			return nil
+		}
+		return canonicalizeObject(dec, buf)
+	case string:
+		writeString(buf, t)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(t), 64)
+		if err != nil {
+			return InvalidNumberError{Number: string(t)}
+		}
+		s, err := FormatNumber(f)
+		if err != nil {
+			return InvalidNumberError{Number: string(t)}
+		}
+		buf.WriteString(s)
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case nil:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+// canonicalizeObject writes the members of an object, whose opening brace has
+// been read, sorted by the UTF-16 code units of their names.
+func canonicalizeObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	type member struct {
+		name  string
+		key   []uint16
+		value []byte
+	}
+	var members []member
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return InvalidJSONError{Reason: err.Error()}
+		}
+		name := tok.(string)
+		if seen[name] {
+			return DuplicateKeyError{Key: name}
+		}
+		seen[name] = true
+		var value bytes.Buffer
+		if err := canonicalize(dec, &value); err != nil {
+			return err
+		}
+		members = append(members, member{name: name, key: utf16.Encode([]rune(name)), value: value.Bytes()})
+	}
+	dec.Token()
+	slices.SortFunc(members, func(a, b member) int { return slices.Compare(a.key, b.key) })
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, m.name)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// checkSurrogates rejects \u escapes of surrogates that are not part of a
+// pair. encoding/json silently decodes them to U+FFFD, which would make
+// documents with different lone surrogates canonicalize to the same bytes.
+func checkSurrogates(data []byte) error {
+	inString, pending := false, false // pending follows a high surrogate escape
+	for i := 0; i < len(data); i++ {
+		r := rune(-1) // the code point escaped at i, if any
+		switch {
+		case data[i] == '"':
+			inString = !inString
+		case data[i] == '\\' && inString:
+			i++
+			if i+4 < len(data) && data[i] == 'u' {
+				if v, err := strconv.ParseUint(string(data[i+1:i+5]), 16, 16); err == nil {
+					r, i = rune(v), i+4
+				}
+			}
+		}
+		if pending != (0xdc00 <= r && r <= 0xdfff) {
+			return InvalidJSONError{Reason: "lone surrogate escape"}
+		}
+		pending = 0xd800 <= r && r <= 0xdbff
+	}
+	if pending {
+		return InvalidJSONError{Reason: "lone surrogate escape"}
+	}
+	return nil
+}
+
+// writeString writes s as a JSON string, escaping only quotes, backslashes and
+// control characters, the latter with the short forms where JSON has them.
+func writeString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// FormatNumber formats f the way ECMAScript's Number.prototype.toString does,
+// as RFC 8785 requires. NaN and infinities have no JSON representation.
+func FormatNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", InvalidNumberError{Number: strconv.FormatFloat(f, 'g', -1, 64)}
+	}
+	if f == 0 {
+		return "0", nil
+	}
+	var sign string
+	if f < 0 {
+		sign, f = "-", -f
+	}
+	// The shortest round-tripping digits and the exponent n with f = 0.digits × 10^n.
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	k, n := len(digits), e+1
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k), nil
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:], nil
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits, nil
+	}
+	out := sign + digits[:1]
+	if k > 1 {
+		out += "." + digits[1:]
+	}
+	if n-1 >= 0 {
+		return out + "e+" + strconv.Itoa(n-1), nil
+	}
+	return out + "e-" + strconv.Itoa(1-n), nil
+}
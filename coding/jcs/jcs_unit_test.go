@@ -0,0 +1,131 @@
+package jcs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	t.Run("rfc 8785 section 3.2.2", func(t *testing.T) {
+		in := `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`
+		out, err := Canonicalize([]byte(in))
+		require.NoError(t, err)
+		assert.Equal(t, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(out))
+	})
+
+	t.Run("rfc 8785 section 3.2.3 sorting", func(t *testing.T) {
+		in := `{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`
+		out, err := Canonicalize([]byte(in))
+		require.NoError(t, err)
+		assert.Equal(t, "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}", string(out))
+	})
+
+	t.Run("nested and empty values", func(t *testing.T) {
+		out, err := Canonicalize([]byte(` { "b" : [ ] , "a" : { "d" : { } , "c" : [ 1 , "x" ] } } `))
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":{"c":[1,"x"],"d":{}},"b":[]}`, string(out))
+	})
+
+	t.Run("control characters and html", func(t *testing.T) {
+		out, err := Canonicalize([]byte(`"\b\f\t\u0001\u001f<>&\u2028"`))
+		require.NoError(t, err)
+		assert.Equal(t, "\"\\b\\f\\t\\u0001\\u001f<>&\u2028\"", string(out))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, in := range []string{``, `{`, `[1,]`, `{"a":1}x`, `{"a" 1}`, "\"\xff\""} {
+			_, err := Canonicalize([]byte(in))
+			assert.IsType(t, InvalidJSONError{}, err, in)
+		}
+	})
+
+	t.Run("lone surrogates", func(t *testing.T) {
+		for _, in := range []string{`"\ud800"`, `"\udc00"`, `"\ud800x"`, `"\ud800\u0041"`, `"\udc00\ud800"`, `{"\ud83d":1}`, `["\uD83D"]`} {
+			_, err := Canonicalize([]byte(in))
+			assert.Equal(t, InvalidJSONError{Reason: "lone surrogate escape"}, err, in)
+		}
+
+		out, err := Canonicalize([]byte(`["\ud83d\ude00","\\ud800","\ufffd"]`))
+		require.NoError(t, err)
+		assert.Equal(t, "[\"\U0001f600\",\"\\\\ud800\",\"\ufffd\"]", string(out))
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		_, err := Canonicalize([]byte(`{"a":1,"b":{"c":1,"c":2}}`))
+		assert.Equal(t, DuplicateKeyError{Key: "c"}, err)
+		assert.Contains(t, err.Error(), `"c"`)
+	})
+
+	t.Run("number out of range", func(t *testing.T) {
+		_, err := Canonicalize([]byte(`[1e400]`))
+		assert.Equal(t, InvalidNumberError{Number: "1e400"}, err)
+		assert.Contains(t, err.Error(), "1e400")
+	})
+}
+
+func TestFormatNumber(t *testing.T) {
+	// RFC 8785 appendix B
+	vectors := []struct {
+		bits uint64
+		want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+	for _, v := range vectors {
+		got, err := FormatNumber(math.Float64frombits(v.bits))
+		require.NoError(t, err)
+		assert.Equal(t, v.want, got, "%016x", v.bits)
+	}
+
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := FormatNumber(f)
+		assert.IsType(t, InvalidNumberError{}, err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	out, err := Marshal(map[string]any{"z": 1.0, "a": []int{3, 2}, "m": "<tag>"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[3,2],"m":"<tag>","z":1}`, string(out))
+
+	_, err = Marshal(make(chan int))
+	assert.IsType(t, InvalidJSONError{}, err)
+}
@@ -46,7 +46,7 @@ func (d Decoder) ByMorse() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = morse.NewStdDecoder().Decode(d.src)
+		d.dst, d.Error = d.limit(morse.NewStdDecoder().Decode(d.src))
 	}
 
 	return d
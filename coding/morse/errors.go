@@ -1,6 +1,10 @@
 package morse
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // InvalidInputError represents an error when the morse input is invalid.
 // This error is now rarely used since most characters are supported.
@@ -13,6 +17,16 @@ func (e InvalidInputError) Error() string {
 	return fmt.Sprintf("coding/morse: invalid input")
 }
 
+// Code returns the stable error code DGL-MORSE-001.
+func (e InvalidInputError) Code() string {
+	return "DGL-MORSE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidInputError) Fields() map[string]any {
+	return errcode.NewFields("coding/morse", "Morse", "", "char", e.Char)
+}
+
 // InvalidCharacterError represents an error when an invalid morse character is found
 // during decoding. This error occurs when a morse code sequence is not recognized.
 type InvalidCharacterError struct {
@@ -23,3 +37,13 @@ type InvalidCharacterError struct {
 func (e InvalidCharacterError) Error() string {
 	return fmt.Sprintf("coding/morse: unsupported character %s", e.Char)
 }
+
+// Code returns the stable error code DGL-MORSE-002.
+func (e InvalidCharacterError) Code() string {
+	return "DGL-MORSE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCharacterError) Fields() map[string]any {
+	return errcode.NewFields("coding/morse", "Morse", "", "char", e.Char)
+}
@@ -0,0 +1,86 @@
+package pem
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidTypeError represents an error when a PEM block type is empty or
+// contains characters that cannot appear in an encapsulation boundary.
+type InvalidTypeError string
+
+// Error returns a formatted error message describing the invalid block type.
+func (e InvalidTypeError) Error() string {
+	return fmt.Sprintf("coding/pem: invalid block type %q", string(e))
+}
+
+// Code returns the stable error code DGL-PEM-001.
+func (e InvalidTypeError) Code() string {
+	return "DGL-PEM-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidTypeError) Fields() map[string]any {
+	return errcode.NewFields("coding/pem", "PEM", "", "type", string(e))
+}
+
+// InvalidHeaderError represents an error when a PEM header key or value cannot
+// be encoded, for example because it contains a colon or a line break.
+type InvalidHeaderError string
+
+// Error returns a formatted error message describing the invalid header.
+func (e InvalidHeaderError) Error() string {
+	return fmt.Sprintf("coding/pem: invalid header %q", string(e))
+}
+
+// Code returns the stable error code DGL-PEM-002.
+func (e InvalidHeaderError) Code() string {
+	return "DGL-PEM-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidHeaderError) Fields() map[string]any {
+	return errcode.NewFields("coding/pem", "PEM", "", "header", string(e))
+}
+
+// InvalidLineLengthError represents an error when the base64 line length is
+// not a positive multiple of 4.
+type InvalidLineLengthError int
+
+// Error returns a formatted error message describing the invalid line length.
+func (e InvalidLineLengthError) Error() string {
+	return fmt.Sprintf("coding/pem: invalid line length %d, must be a positive multiple of 4", int(e))
+}
+
+// Code returns the stable error code DGL-PEM-003.
+func (e InvalidLineLengthError) Code() string {
+	return "DGL-PEM-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidLineLengthError) Fields() map[string]any {
+	return errcode.NewFields("coding/pem", "PEM", "", "line_length", int(e))
+}
+
+// NotFoundError represents an error when no PEM block, or no block of the
+// expected type, is found in the input.
+type NotFoundError string
+
+// Error returns a formatted error message describing the missing block.
+func (e NotFoundError) Error() string {
+	if e == "" {
+		return "coding/pem: no PEM block found"
+	}
+	return fmt.Sprintf("coding/pem: no %q PEM block found", string(e))
+}
+
+// Code returns the stable error code DGL-PEM-004.
+func (e NotFoundError) Code() string {
+	return "DGL-PEM-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e NotFoundError) Fields() map[string]any {
+	return errcode.NewFields("coding/pem", "PEM", "", "type", string(e))
+}
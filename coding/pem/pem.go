@@ -0,0 +1,138 @@
+// Package pem implements PEM armor with custom block types, headers and line
+// lengths. It wraps binary data such as ciphertexts and signatures into text
+// that can be embedded safely in configuration files and emails, for example
+//
+//	-----BEGIN DONGLE MESSAGE-----
+//	Cipher: AES-256-GCM
+//
+//	c2VjcmV0IG1lc3NhZ2U=
+//	-----END DONGLE MESSAGE-----
+//
+// Output with the default line length is identical to that of encoding/pem, and
+// Decode accepts anything encoding/pem accepts.
+package pem
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"sort"
+	"strings"
+)
+
+// DefaultLineLength is the base64 line length of RFC 7468.
+const DefaultLineLength = 64
+
+// Default block types for dongle's armored outputs.
+const (
+	MessageType   = "DONGLE MESSAGE"
+	SignatureType = "DONGLE SIGNATURE"
+)
+
+// Block represents a PEM encoded block.
+type Block struct {
+	Type    string            // The type taken from the preamble, e.g. "DONGLE MESSAGE"
+	Headers map[string]string // Optional headers
+	Bytes   []byte            // The decoded contents
+}
+
+// Encode armors a block, wrapping the base64 body every lineLength characters.
+// A lineLength of zero selects DefaultLineLength. Headers are written sorted by
+// key with Proc-Type first, as encoding/pem does.
+func Encode(b *Block, lineLength int) ([]byte, error) {
+	if lineLength == 0 {
+		lineLength = DefaultLineLength
+	}
+	if lineLength < 0 || lineLength%4 != 0 {
+		return nil, InvalidLineLengthError(lineLength)
+	}
+	if !validType(b.Type) {
+		return nil, InvalidTypeError(b.Type)
+	}
+	keys := make([]string, 0, len(b.Headers))
+	for k, v := range b.Headers {
+		if k == "" || strings.ContainsAny(k, ":\r\n") || strings.ContainsAny(v, "\r\n") {
+			return nil, InvalidHeaderError(k)
+		}
+		if k != "Proc-Type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := b.Headers["Proc-Type"]; ok {
+		keys = append([]string{"Proc-Type"}, keys...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN " + b.Type + "-----\n")
+	for _, k := range keys {
+		buf.WriteString(k + ": " + b.Headers[k] + "\n")
+	}
+	if len(keys) > 0 {
+		buf.WriteByte('\n')
+	}
+	body := base64.StdEncoding.EncodeToString(b.Bytes)
+	for len(body) > lineLength {
+		buf.WriteString(body[:lineLength])
+		buf.WriteByte('\n')
+		body = body[lineLength:]
+	}
+	if len(body) > 0 {
+		buf.WriteString(body)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("-----END " + b.Type + "-----\n")
+	return buf.Bytes(), nil
+}
+
+// EncodeToString armors data as a block of the given type with the default
+// line length and no headers.
+func EncodeToString(blockType string, data []byte) (string, error) {
+	out, err := Encode(&Block{Type: blockType, Bytes: data}, 0)
+	return string(out), err
+}
+
+// Decode finds the next PEM block in data, whatever its type and line length,
+// and returns it with the remaining input. Text before the block is skipped.
+func Decode(data []byte) (*Block, []byte, error) {
+	p, rest := pem.Decode(data)
+	if p == nil {
+		return nil, data, NotFoundError("")
+	}
+	return &Block{Type: p.Type, Headers: p.Headers, Bytes: p.Bytes}, rest, nil
+}
+
+// DecodeType returns the first PEM block of the given type in data, skipping
+// blocks of other types.
+func DecodeType(data []byte, blockType string) (*Block, error) {
+	for {
+		p, rest := pem.Decode(data)
+		if p == nil {
+			return nil, NotFoundError(blockType)
+		}
+		if p.Type == blockType {
+			return &Block{Type: p.Type, Headers: p.Headers, Bytes: p.Bytes}, nil
+		}
+		data = rest
+	}
+}
+
+// validType reports whether a block type is a label of RFC 7468: printable
+// ASCII where single hyphens and spaces may only separate other characters.
+func validType(t string) bool {
+	if t == "" {
+		return false
+	}
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+		if c == ' ' || c == '-' {
+			if i == 0 || i == len(t)-1 || t[i-1] == ' ' || t[i-1] == '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
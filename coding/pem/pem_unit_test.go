@@ -0,0 +1,111 @@
+package pem
+
+import (
+	"bytes"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	t.Run("encode matches encoding/pem", func(t *testing.T) {
+		data := bytes.Repeat([]byte{0x01, 0x02, 0x03}, 70)
+		headers := map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00", "Comment": "x"}
+		out, err := Encode(&Block{Type: "DONGLE MESSAGE", Headers: headers, Bytes: data}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, pem.EncodeToMemory(&pem.Block{Type: "DONGLE MESSAGE", Headers: headers, Bytes: data}), out)
+	})
+
+	t.Run("encode custom line length", func(t *testing.T) {
+		out, err := Encode(&Block{Type: "DONGLE MESSAGE", Bytes: []byte("hello world")}, 8)
+		require.NoError(t, err)
+		assert.Equal(t, "-----BEGIN DONGLE MESSAGE-----\naGVsbG8g\nd29ybGQ=\n-----END DONGLE MESSAGE-----\n", string(out))
+
+		block, rest, err := Decode(out)
+		require.NoError(t, err)
+		assert.Empty(t, rest)
+		assert.Equal(t, []byte("hello world"), block.Bytes)
+	})
+
+	t.Run("encode empty bytes", func(t *testing.T) {
+		out, err := Encode(&Block{Type: "EMPTY"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "-----BEGIN EMPTY-----\n-----END EMPTY-----\n", string(out))
+	})
+
+	t.Run("encode invalid line length", func(t *testing.T) {
+		for _, n := range []int{-4, 3, 65} {
+			_, err := Encode(&Block{Type: "X", Bytes: []byte("x")}, n)
+			assert.Equal(t, InvalidLineLengthError(n), err)
+			assert.Contains(t, err.Error(), "multiple of 4")
+		}
+	})
+
+	t.Run("encode invalid type", func(t *testing.T) {
+		for _, typ := range []string{"", " X", "X ", "-X", "X-", "A--B", "A  B", "A\nB", "中文"} {
+			_, err := Encode(&Block{Type: typ}, 0)
+			assert.Equal(t, InvalidTypeError(typ), err, typ)
+		}
+		_, err := Encode(&Block{Type: "X509 CRL-V2"}, 0)
+		assert.NoError(t, err)
+		assert.Contains(t, InvalidTypeError("-").Error(), `"-"`)
+	})
+
+	t.Run("encode invalid header", func(t *testing.T) {
+		for _, h := range []map[string]string{{"": "v"}, {"a:b": "v"}, {"k\n": "v"}, {"k": "v\r\n"}} {
+			_, err := Encode(&Block{Type: "X", Headers: h}, 0)
+			assert.IsType(t, InvalidHeaderError(""), err)
+		}
+		assert.Contains(t, InvalidHeaderError("a:b").Error(), "a:b")
+	})
+}
+
+func TestEncodeToString(t *testing.T) {
+	s, err := EncodeToString(SignatureType, []byte("hello world"))
+	require.NoError(t, err)
+	assert.Equal(t, "-----BEGIN DONGLE SIGNATURE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE SIGNATURE-----\n", s)
+
+	_, err = EncodeToString("", []byte("hello world"))
+	assert.Error(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("decode with headers and rest", func(t *testing.T) {
+		in := "prefix\n-----BEGIN DONGLE MESSAGE-----\nCipher: AES-256-GCM\n\naGVsbG8g\nd29ybGQ=\n-----END DONGLE MESSAGE-----\ntrailer"
+		block, rest, err := Decode([]byte(in))
+		require.NoError(t, err)
+		assert.Equal(t, MessageType, block.Type)
+		assert.Equal(t, map[string]string{"Cipher": "AES-256-GCM"}, block.Headers)
+		assert.Equal(t, []byte("hello world"), block.Bytes)
+		assert.Equal(t, "trailer", string(rest))
+	})
+
+	t.Run("decode crlf", func(t *testing.T) {
+		in := strings.ReplaceAll("-----BEGIN X-----\naGVsbG8gd29ybGQ=\n-----END X-----\n", "\n", "\r\n")
+		block, _, err := Decode([]byte(in))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), block.Bytes)
+	})
+
+	t.Run("decode not found", func(t *testing.T) {
+		_, rest, err := Decode([]byte("no armor here"))
+		assert.Equal(t, NotFoundError(""), err)
+		assert.Equal(t, "no armor here", string(rest))
+		assert.Equal(t, "coding/pem: no PEM block found", err.Error())
+	})
+}
+
+func TestDecodeType(t *testing.T) {
+	in := []byte("-----BEGIN A-----\nYQ==\n-----END A-----\n-----BEGIN B-----\nYg==\n-----END B-----\n")
+
+	block, err := DecodeType(in, "B")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), block.Bytes)
+
+	_, err = DecodeType(in, "C")
+	assert.Equal(t, NotFoundError("C"), err)
+	assert.Equal(t, `coding/pem: no "C" PEM block found`, err.Error())
+}
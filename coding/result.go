@@ -0,0 +1,77 @@
+package coding
+
+import (
+	"github.com/dromara/dongle/coding/base32"
+	"github.com/dromara/dongle/coding/base64"
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Result defines the output of an encoding, hashing, encryption or signing
+// operation. It holds the raw bytes once so that callers needing several
+// encodings of the same output do not run the operation again for each of them.
+type Result struct {
+	data []byte
+	err  error
+}
+
+// NewResult returns a new Result holding data, or err if the operation failed.
+func NewResult(data []byte, err error) Result {
+	if err != nil {
+		return Result{err: err}
+	}
+	return Result{data: data}
+}
+
+// Bytes outputs as raw byte slice.
+func (r Result) Bytes() []byte {
+	if len(r.data) == 0 || r.err != nil {
+		return []byte{}
+	}
+	return r.data
+}
+
+// String outputs as raw string.
+func (r Result) String() string {
+	if len(r.data) == 0 || r.err != nil {
+		return ""
+	}
+	return utils.Bytes2String(r.data)
+}
+
+// Hex outputs as lowercase hex string.
+func (r Result) Hex() string {
+	if len(r.data) == 0 || r.err != nil {
+		return ""
+	}
+	return utils.Bytes2String(hex.NewStdEncoder().Encode(r.data))
+}
+
+// Base64 outputs as standard base64 string.
+func (r Result) Base64() string {
+	if len(r.data) == 0 || r.err != nil {
+		return ""
+	}
+	return utils.Bytes2String(base64.NewStdEncoder(base64.StdAlphabet).Encode(r.data))
+}
+
+// Base32 outputs as standard base32 string.
+func (r Result) Base32() string {
+	if len(r.data) == 0 || r.err != nil {
+		return ""
+	}
+	return utils.Bytes2String(base32.NewStdEncoder(base32.StdAlphabet).Encode(r.data))
+}
+
+// Len returns the length of the raw output in bytes.
+func (r Result) Len() int {
+	if r.err != nil {
+		return 0
+	}
+	return len(r.data)
+}
+
+// Error returns the error of the operation, if any.
+func (r Result) Error() error {
+	return r.err
+}
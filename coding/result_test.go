@@ -0,0 +1,49 @@
+package coding
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	t.Run("encodings", func(t *testing.T) {
+		r := NewEncoder().FromString("hello world").ByBase64().To()
+		assert.NoError(t, r.Error())
+		assert.Equal(t, "aGVsbG8gd29ybGQ=", r.String())
+		assert.Equal(t, []byte("aGVsbG8gd29ybGQ="), r.Bytes())
+		assert.Equal(t, 16, r.Len())
+
+		r = NewDecoder().FromString("aGVsbG8gd29ybGQ=").ByBase64().To()
+		assert.Equal(t, "hello world", r.String())
+		assert.Equal(t, "68656c6c6f20776f726c64", r.Hex())
+		assert.Equal(t, "aGVsbG8gd29ybGQ=", r.Base64())
+		assert.Equal(t, "NBSWY3DPEB3W64TMMQ======", r.Base32())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		r := NewEncoder().FromString("").ByBase64().To()
+		assert.NoError(t, r.Error())
+		assert.Equal(t, []byte{}, r.Bytes())
+		assert.Empty(t, r.String())
+		assert.Empty(t, r.Hex())
+		assert.Empty(t, r.Base64())
+		assert.Empty(t, r.Base32())
+		assert.Zero(t, r.Len())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := errors.New("boom")
+		r := NewResult([]byte("data"), err)
+		assert.Equal(t, err, r.Error())
+		assert.Equal(t, []byte{}, r.Bytes())
+		assert.Empty(t, r.String())
+		assert.Empty(t, r.Hex())
+		assert.Zero(t, r.Len())
+
+		r = NewDecoder().FromString("!!!").ByBase64().To()
+		assert.Error(t, r.Error())
+		assert.Empty(t, r.Base64())
+	})
+}
@@ -0,0 +1,98 @@
+package coding
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dromara/dongle/coding/base100"
+	"github.com/dromara/dongle/coding/base32"
+	"github.com/dromara/dongle/coding/base45"
+	"github.com/dromara/dongle/coding/base58"
+	"github.com/dromara/dongle/coding/base62"
+	"github.com/dromara/dongle/coding/base64"
+	"github.com/dromara/dongle/coding/base85"
+	"github.com/dromara/dongle/coding/base91"
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/morse"
+	"github.com/dromara/dongle/coding/unicode"
+	"github.com/dromara/dongle/coding/words"
+)
+
+// codec holds the stream constructors of one encoding.
+type codec struct {
+	encoder func(w io.Writer) io.WriteCloser
+	decoder func(r io.Reader) io.Reader
+}
+
+// codecs lists the encodings of NewStreamEncoder and NewStreamDecoder by lower
+// case name, one for every By method of Encoder and Decoder.
+var codecs = map[string]codec{
+	"base100": {base100.NewStreamEncoder, base100.NewStreamDecoder},
+	"base32": {
+		func(w io.Writer) io.WriteCloser { return base32.NewStreamEncoder(w, base32.StdAlphabet) },
+		func(r io.Reader) io.Reader { return base32.NewStreamDecoder(r, base32.StdAlphabet) },
+	},
+	"base32hex": {
+		func(w io.Writer) io.WriteCloser { return base32.NewStreamEncoder(w, base32.HexAlphabet) },
+		func(r io.Reader) io.Reader { return base32.NewStreamDecoder(r, base32.HexAlphabet) },
+	},
+	"base45": {base45.NewStreamEncoder, base45.NewStreamDecoder},
+	"base58": {base58.NewStreamEncoder, base58.NewStreamDecoder},
+	"base62": {base62.NewStreamEncoder, base62.NewStreamDecoder},
+	"base64": {
+		func(w io.Writer) io.WriteCloser { return base64.NewStreamEncoder(w, base64.StdAlphabet) },
+		func(r io.Reader) io.Reader { return base64.NewStreamDecoder(r, base64.StdAlphabet) },
+	},
+	"base64url": {
+		func(w io.Writer) io.WriteCloser { return base64.NewStreamEncoder(w, base64.URLAlphabet) },
+		func(r io.Reader) io.Reader { return base64.NewStreamDecoder(r, base64.URLAlphabet) },
+	},
+	"base85": {base85.NewStreamEncoder, base85.NewStreamDecoder},
+	"base91": {base91.NewStreamEncoder, base91.NewStreamDecoder},
+	"bip39": {
+		func(w io.Writer) io.WriteCloser { return words.NewStreamEncoder(w, words.BIP39) },
+		func(r io.Reader) io.Reader { return words.NewStreamDecoder(r, words.BIP39) },
+	},
+	"hex":   {hex.NewStreamEncoder, hex.NewStreamDecoder},
+	"morse": {morse.NewStreamEncoder, morse.NewStreamDecoder},
+	"pgpwords": {
+		func(w io.Writer) io.WriteCloser { return words.NewStreamEncoder(w, words.PGP) },
+		func(r io.Reader) io.Reader { return words.NewStreamDecoder(r, words.PGP) },
+	},
+	"proquint": {
+		func(w io.Writer) io.WriteCloser { return words.NewStreamEncoder(w, words.Proquint) },
+		func(r io.Reader) io.Reader { return words.NewStreamDecoder(r, words.Proquint) },
+	},
+	"unicode": {unicode.NewStreamEncoder, unicode.NewStreamDecoder},
+}
+
+// Codecs returns the names accepted by NewStreamEncoder and NewStreamDecoder
+// in sorted order.
+func Codecs() []string {
+	return slices.Sorted(maps.Keys(codecs))
+}
+
+// NewStreamEncoder returns a writer encoding the data written to it with the
+// named codec and writing the result to w, in the shape of
+// encoding/base64.NewEncoder: the caller must Close it to flush any partial
+// block. Names are case insensitive and listed by Codecs.
+func NewStreamEncoder(name string, w io.Writer) (io.WriteCloser, error) {
+	c, ok := codecs[strings.ToLower(name)]
+	if !ok {
+		return nil, UnknownCodecError{Name: name}
+	}
+	return c.encoder(w), nil
+}
+
+// NewStreamDecoder returns a reader decoding the data read from r with the
+// named codec, in the shape of encoding/base64.NewDecoder. Names are case
+// insensitive and listed by Codecs.
+func NewStreamDecoder(name string, r io.Reader) (io.Reader, error) {
+	c, ok := codecs[strings.ToLower(name)]
+	if !ok {
+		return nil, UnknownCodecError{Name: name}
+	}
+	return c.decoder(r), nil
+}
@@ -0,0 +1,81 @@
+package coding
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fluent maps the names of NewStreamEncoder to the matching Encoder and
+// Decoder methods.
+var fluent = map[string]struct {
+	encode func(e Encoder) Encoder
+	decode func(d Decoder) Decoder
+}{
+	"base100":   {Encoder.ByBase100, Decoder.ByBase100},
+	"base32":    {Encoder.ByBase32, Decoder.ByBase32},
+	"base32hex": {Encoder.ByBase32Hex, Decoder.ByBase32Hex},
+	"base45":    {Encoder.ByBase45, Decoder.ByBase45},
+	"base58":    {Encoder.ByBase58, Decoder.ByBase58},
+	"base62":    {Encoder.ByBase62, Decoder.ByBase62},
+	"base64":    {Encoder.ByBase64, Decoder.ByBase64},
+	"base64url": {Encoder.ByBase64Url, Decoder.ByBase64Url},
+	"base85":    {Encoder.ByBase85, Decoder.ByBase85},
+	"base91":    {Encoder.ByBase91, Decoder.ByBase91},
+	"bip39":     {Encoder.ByBip39, Decoder.ByBip39},
+	"hex":       {Encoder.ByHex, Decoder.ByHex},
+	"morse":     {Encoder.ByMorse, Decoder.ByMorse},
+	"pgpwords":  {Encoder.ByPgpWords, Decoder.ByPgpWords},
+	"proquint":  {Encoder.ByProquint, Decoder.ByProquint},
+	"unicode":   {Encoder.ByUnicode, Decoder.ByUnicode},
+}
+
+func TestNewStreamEncoder(t *testing.T) {
+	assert.Len(t, Codecs(), len(fluent))
+
+	for _, name := range Codecs() {
+		src := []byte("hello world, streaming codecs")
+		switch name {
+		case "morse":
+			src = []byte("sos")
+		case "bip39", "proquint":
+			// Word codecs take whole digests
+			src = []byte("hello world, streaming codecs!!!")
+		}
+		// The fluent chain streams when reading from a file, which chunks the
+		// whole-number codecs the same way.
+		fsys := fstest.MapFS{"src": {Data: src}}
+		want := fluent[name].encode(NewEncoder().FromFS(fsys, "src")).ToBytes()
+
+		var buf bytes.Buffer
+		w, err := NewStreamEncoder(strings.ToUpper(name), &buf)
+		require.NoError(t, err, name)
+		_, err = w.Write(src)
+		require.NoError(t, err, name)
+		require.NoError(t, w.Close(), name)
+		assert.Equal(t, string(want), buf.String(), name)
+
+		encoded := fluent[name].encode(NewEncoder().FromBytes(src)).ToBytes()
+		r, err := NewStreamDecoder(name, bytes.NewReader(encoded))
+		require.NoError(t, err, name)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err, name)
+		assert.Equal(t, src, got, name)
+	}
+}
+
+func TestNewStreamEncoder_UnknownCodec(t *testing.T) {
+	w, err := NewStreamEncoder("base16", io.Discard)
+	assert.Nil(t, w)
+	assert.Equal(t, UnknownCodecError{Name: "base16"}, err)
+	assert.Equal(t, `coding: unknown codec "base16"`, err.Error())
+
+	r, err := NewStreamDecoder("base16", strings.NewReader(""))
+	assert.Nil(t, r)
+	assert.IsType(t, UnknownCodecError{}, err)
+}
@@ -0,0 +1,204 @@
+// Package text canonicalizes text before it is hashed or signed, so that a file
+// checked out with CRLF line endings on Windows, saved with a byte order mark
+// by an editor or stripped of trailing blanks by another still verifies
+// against the signature made over the original.
+//
+// Options are flags that can be combined; Canonical enables all of them:
+//
+//	text.Canonicalize([]byte("\uFEFFline one  \r\nline two\r\n"), text.Canonical)
+//	// "line one\nline two\n"
+//
+// Line terminators are LF, CRLF and a lone CR. These options only affect line
+// terminators, spaces and tabs, every other byte is copied unchanged, so the
+// input does not have to be valid UTF-8.
+//
+// NFC and NFKC normalize the text to a Unicode normalization form first, so
+// that user entered text such as passwords and names hashes the same whether
+// the keyboard or platform produced precomposed or decomposed characters:
+//
+//	text.Canonicalize([]byte("Cafe\u0301"), text.NFC) // "Caf\u00e9"
+//
+// NFKC also folds compatibility characters, such as ligatures, full width
+// forms and the no-break space, into their plain equivalents.
+package text
+
+import (
+	"io"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Option selects a canonicalization step.
+type Option uint8
+
+// Canonicalization steps.
+const (
+	StripBOM          Option = 1 << iota // Drop a UTF-8 byte order mark at the start of the text
+	NormalizeNewlines                    // Replace CRLF and lone CR line terminators with LF
+	TrimTrailingSpace                    // Drop spaces and tabs before line terminators and at the end of the text
+	NFC                                  // Normalize to Unicode Normalization Form C
+	NFKC                                 // Normalize to Unicode Normalization Form KC, takes precedence over NFC
+
+	// Canonical enables every line step, leaving Unicode normalization off.
+	Canonical = StripBOM | NormalizeNewlines | TrimTrailingSpace
+
+	// Forms selects the Unicode normalization options.
+	Forms = NFC | NFKC
+)
+
+// bom is the UTF-8 encoding of U+FEFF.
+const bom = "\xef\xbb\xbf"
+
+// Canonicalize returns text with the selected steps applied, Unicode
+// normalization first. The input is not modified, and is returned as is when
+// opts is zero.
+func Canonicalize(text []byte, opts Option) []byte {
+	if form, ok := opts.form(); ok {
+		text = form.Bytes(text)
+	}
+	if opts&^Forms == 0 {
+		return text
+	}
+	t := transformer{opts: opts}
+	return t.flush(t.write(make([]byte, 0, len(text)), text))
+}
+
+// NewReader returns a reader yielding the text read from r with the selected
+// steps applied, for canonicalizing files as they are streamed. It returns r
+// itself when opts is zero or r is nil.
+func NewReader(r io.Reader, opts Option) io.Reader {
+	if r == nil {
+		return r
+	}
+	if form, ok := opts.form(); ok {
+		r = form.Reader(r)
+	}
+	if opts&^Forms == 0 {
+		return r
+	}
+	return &reader{r: r, t: transformer{opts: opts}, buf: make([]byte, 4096)}
+}
+
+// form returns the Unicode normalization form selected by the options.
+func (o Option) form() (norm.Form, bool) {
+	switch {
+	case o&NFKC != 0:
+		return norm.NFKC, true
+	case o&NFC != 0:
+		return norm.NFC, true
+	}
+	return 0, false
+}
+
+// reader canonicalizes a stream.
+type reader struct {
+	r   io.Reader
+	t   transformer
+	buf []byte // Read buffer
+	out []byte // Canonical text not yet returned
+	err error  // Error of the underlying reader
+}
+
+// Read implements the io.Reader interface.
+func (r *reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		n, err := r.r.Read(r.buf)
+		r.out = r.t.write(r.out[:0], r.buf[:n])
+		if err != nil {
+			if err == io.EOF {
+				r.out = r.t.flush(r.out)
+			}
+			r.err = err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// transformer applies the canonicalization steps to text fed in pieces.
+type transformer struct {
+	opts    Option
+	started bool   // Past the position of a byte order mark
+	head    int    // Number of byte order mark bytes held back
+	cr      bool   // A CR was seen and its terminator is not written yet
+	blanks  []byte // Spaces and tabs held back until the line goes on
+}
+
+// write appends the canonical form of src to dst, holding back the bytes
+// whose fate depends on what follows.
+func (t *transformer) write(dst, src []byte) []byte {
+	for _, c := range src {
+		if !t.started {
+			if t.opts&StripBOM != 0 && c == bom[t.head] {
+				if t.head++; t.head == len(bom) {
+					t.started, t.head = true, 0
+				}
+				continue
+			}
+			t.started = true
+			// Not a byte order mark after all, replay the bytes held back.
+			for i := 0; i < t.head; i++ {
+				dst = t.byte(dst, bom[i])
+			}
+			t.head = 0
+		}
+		dst = t.byte(dst, c)
+	}
+	return dst
+}
+
+// flush appends the bytes held back at the end of the text.
+func (t *transformer) flush(dst []byte) []byte {
+	for i := 0; i < t.head; i++ {
+		dst = t.byte(dst, bom[i])
+	}
+	t.head = 0
+	if t.cr {
+		dst = t.terminate(dst, false)
+	}
+	t.blanks = t.blanks[:0]
+	return dst
+}
+
+// byte processes one byte of text after the byte order mark.
+func (t *transformer) byte(dst []byte, c byte) []byte {
+	if t.cr {
+		if c == '\n' {
+			return t.terminate(dst, true)
+		}
+		dst = t.terminate(dst, false)
+	}
+	switch {
+	case c == '\r':
+		t.cr = true
+		return dst
+	case c == '\n':
+		t.blanks = t.blanks[:0]
+		return append(dst, '\n')
+	case (c == ' ' || c == '\t') && t.opts&TrimTrailingSpace != 0:
+		t.blanks = append(t.blanks, c)
+		return dst
+	}
+	dst = append(dst, t.blanks...)
+	t.blanks = t.blanks[:0]
+	return append(dst, c)
+}
+
+// terminate writes the line terminator started by a CR, followed by LF when
+// crlf is set. Blanks held back before a line terminator are dropped, they are
+// only held back when trimming.
+func (t *transformer) terminate(dst []byte, crlf bool) []byte {
+	t.cr = false
+	t.blanks = t.blanks[:0]
+	switch {
+	case t.opts&NormalizeNewlines != 0:
+		return append(dst, '\n')
+	case crlf:
+		return append(dst, '\r', '\n')
+	}
+	return append(dst, '\r')
+}
@@ -0,0 +1,89 @@
+package text
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var canonicalCases = []struct {
+	name string
+	in   string
+	opts Option
+	want string
+}{
+	{"crlf", "a\r\nb\r\n", NormalizeNewlines, "a\nb\n"},
+	{"lone cr", "a\rb\r", NormalizeNewlines, "a\nb\n"},
+	{"cr before crlf", "a\r\r\nb", NormalizeNewlines, "a\n\nb"},
+	{"keep newlines", "a \r\nb \rc \n", TrimTrailingSpace, "a\r\nb\rc\n"},
+	{"trailing blanks", "a \t\nb\t \r\n  c  ", TrimTrailingSpace | NormalizeNewlines, "a\nb\n  c"},
+	{"inner blanks", "a  b\tc\n", TrimTrailingSpace, "a  b\tc\n"},
+	{"blank lines", "  \n\t\n", TrimTrailingSpace, "\n\n"},
+	{"bom", "\xef\xbb\xbfa\r\n", StripBOM, "a\r\n"},
+	{"bom only at start", "a\xef\xbb\xbf", StripBOM, "a\xef\xbb\xbf"},
+	{"partial bom", "\xef\xbbx", StripBOM, "\xef\xbbx"},
+	{"partial bom at end", "\xef\xbb", StripBOM, "\xef\xbb"},
+	{"bom then partial bom", "\xef\xbb\xbf\xef\xbb", StripBOM, "\xef\xbb"},
+	{"canonical", "\xef\xbb\xbfline one  \r\nline two\r\n", Canonical, "line one\nline two\n"},
+	{"binary", "\x00\xff\r\n\x80 ", Canonical, "\x00\xff\n\x80"},
+	{"empty", "", Canonical, ""},
+	{"none", "a \r\n", 0, "a \r\n"},
+	{"nfc", "Cafe\u0301", NFC, "Caf\u00e9"},
+	{"nfc hangul", "\u1100\u1161", NFC, "\uac00"},
+	{"nfc keeps compatibility", "\ufb01\u00a0", NFC, "\ufb01\u00a0"},
+	{"nfkc", "\ufb01\u00a0\uff21", NFKC, "fi A"},
+	{"nfkc over nfc", "\ufb01", NFC | NFKC, "fi"},
+	{"nfkc before trim", "e\u0301\u00a0\r\n", NFKC | Canonical, "\u00e9\n"},
+	{"nfc invalid utf-8", "\xff\xfe", NFC, "\xff\xfe"},
+}
+
+func TestCanonicalize(t *testing.T) {
+	for _, c := range canonicalCases {
+		t.Run(c.name, func(t *testing.T) {
+			in := []byte(c.in)
+			assert.Equal(t, c.want, string(Canonicalize(in, c.opts)))
+			assert.Equal(t, c.in, string(in))
+			// Canonical text is a fixed point.
+			assert.Equal(t, c.want, string(Canonicalize([]byte(c.want), c.opts)))
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	for _, c := range canonicalCases {
+		t.Run(c.name, func(t *testing.T) {
+			// One byte at a time splits every terminator and byte order mark.
+			got, err := io.ReadAll(NewReader(iotest.OneByteReader(strings.NewReader(c.in)), c.opts))
+			require.NoError(t, err)
+			assert.Equal(t, c.want, string(got))
+
+			got, err = io.ReadAll(iotest.OneByteReader(NewReader(strings.NewReader(c.in), c.opts)))
+			require.NoError(t, err)
+			assert.Equal(t, c.want, string(got))
+		})
+	}
+
+	t.Run("large", func(t *testing.T) {
+		in := strings.Repeat("line  \r\n", 10000)
+		got, err := io.ReadAll(NewReader(strings.NewReader(in), Canonical))
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("line\n", 10000), string(got))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		r := strings.NewReader("a")
+		assert.Same(t, r, NewReader(r, 0))
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		failure := errors.New("read failed")
+		_, err := io.ReadAll(NewReader(mock.NewErrorFile(failure), Canonical))
+		assert.Equal(t, failure, err)
+	})
+}
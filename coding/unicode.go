@@ -44,7 +44,7 @@ func (d Decoder) ByUnicode() Decoder {
 
 	// Standard decoding mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = unicode.NewStdDecoder().Decode(d.src)
+		d.dst, d.Error = d.limit(unicode.NewStdDecoder().Decode(d.src))
 	}
 
 	return d
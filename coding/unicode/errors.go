@@ -1,6 +1,10 @@
 package unicode
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // DecodeFailedError represents an error when unicode decoding fails.
 // This error occurs when invalid unicode escape sequences are encountered
@@ -14,6 +18,16 @@ func (e DecodeFailedError) Error() string {
 	return fmt.Sprintf("coding/unicode: failed to decode data: %s", e.Input)
 }
 
+// Code returns the stable error code DGL-UNICODE-001.
+func (e DecodeFailedError) Code() string {
+	return "DGL-UNICODE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecodeFailedError) Fields() map[string]any {
+	return errcode.NewFields("coding/unicode", "Unicode", "decode")
+}
+
 // InvalidUnicodeError represents an error when invalid unicode data is encountered.
 // This error occurs when malformed unicode escape sequences are found.
 type InvalidUnicodeError struct {
@@ -25,6 +39,16 @@ func (e InvalidUnicodeError) Error() string {
 	return fmt.Sprintf("coding/unicode: invalid unicode character: %s", e.Char)
 }
 
+// Code returns the stable error code DGL-UNICODE-002.
+func (e InvalidUnicodeError) Code() string {
+	return "DGL-UNICODE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidUnicodeError) Fields() map[string]any {
+	return errcode.NewFields("coding/unicode", "Unicode", "", "char", e.Char)
+}
+
 // EncodeFailedError represents an error when unicode encoding fails.
 // This error is rarely used since strconv.QuoteToASCII rarely fails.
 type EncodeFailedError struct {
@@ -35,3 +59,13 @@ type EncodeFailedError struct {
 func (e EncodeFailedError) Error() string {
 	return fmt.Sprintf("coding/unicode: failed to encode data: %s", e.Input)
 }
+
+// Code returns the stable error code DGL-UNICODE-003.
+func (e EncodeFailedError) Code() string {
+	return "DGL-UNICODE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncodeFailedError) Fields() map[string]any {
+	return errcode.NewFields("coding/unicode", "Unicode", "encode")
+}
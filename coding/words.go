@@ -0,0 +1,145 @@
+package coding
+
+import (
+	"io"
+
+	"github.com/dromara/dongle/coding/words"
+)
+
+// ByPgpWords encodes by the PGP word list.
+func (e Encoder) ByPgpWords() Encoder {
+	if e.Error != nil {
+		return e
+	}
+
+	// Streaming encoding mode
+	if e.reader != nil {
+		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
+			return words.NewStreamEncoder(w, words.PGP)
+		})
+		return e
+	}
+
+	// Standard encoding mode
+	if len(e.src) > 0 {
+		encoder := words.NewStdEncoder(words.PGP)
+		e.dst = encoder.Encode(e.src)
+		e.Error = encoder.Error
+	}
+
+	return e
+}
+
+// ByPgpWords decodes by the PGP word list.
+func (d Decoder) ByPgpWords() Decoder {
+	if d.Error != nil {
+		return d
+	}
+
+	// Streaming decoding mode
+	if d.reader != nil {
+		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
+			return words.NewStreamDecoder(r, words.PGP)
+		})
+		return d
+	}
+
+	// Standard decoding mode
+	if len(d.src) > 0 {
+		d.dst, d.Error = d.limit(words.NewStdDecoder(words.PGP).Decode(d.src))
+	}
+
+	return d
+}
+
+// ByBip39 encodes by BIP-39 mnemonic words.
+func (e Encoder) ByBip39() Encoder {
+	if e.Error != nil {
+		return e
+	}
+
+	// Streaming encoding mode
+	if e.reader != nil {
+		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
+			return words.NewStreamEncoder(w, words.BIP39)
+		})
+		return e
+	}
+
+	// Standard encoding mode
+	if len(e.src) > 0 {
+		encoder := words.NewStdEncoder(words.BIP39)
+		e.dst = encoder.Encode(e.src)
+		e.Error = encoder.Error
+	}
+
+	return e
+}
+
+// ByBip39 decodes by BIP-39 mnemonic words.
+func (d Decoder) ByBip39() Decoder {
+	if d.Error != nil {
+		return d
+	}
+
+	// Streaming decoding mode
+	if d.reader != nil {
+		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
+			return words.NewStreamDecoder(r, words.BIP39)
+		})
+		return d
+	}
+
+	// Standard decoding mode
+	if len(d.src) > 0 {
+		d.dst, d.Error = d.limit(words.NewStdDecoder(words.BIP39).Decode(d.src))
+	}
+
+	return d
+}
+
+// ByProquint encodes by proquint.
+func (e Encoder) ByProquint() Encoder {
+	if e.Error != nil {
+		return e
+	}
+
+	// Streaming encoding mode
+	if e.reader != nil {
+		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
+			return words.NewStreamEncoder(w, words.Proquint)
+		})
+		return e
+	}
+
+	// Standard encoding mode
+	if len(e.src) > 0 {
+		encoder := words.NewStdEncoder(words.Proquint)
+		e.dst = encoder.Encode(e.src)
+		e.Error = encoder.Error
+	}
+
+	return e
+}
+
+// ByProquint decodes by proquint.
+func (d Decoder) ByProquint() Decoder {
+	if d.Error != nil {
+		return d
+	}
+
+	// Streaming decoding mode
+	if d.reader != nil {
+		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
+			return words.NewStreamDecoder(r, words.Proquint)
+		})
+		return d
+	}
+
+	// Standard decoding mode
+	if len(d.src) > 0 {
+		d.dst, d.Error = d.limit(words.NewStdDecoder(words.Proquint).Decode(d.src))
+	}
+
+	return d
+}
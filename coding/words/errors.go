@@ -0,0 +1,116 @@
+package words
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedSchemeError represents an error when an encoder or decoder is
+// created with a scheme other than PGP, BIP39 or Proquint.
+type UnsupportedSchemeError struct {
+	Scheme Scheme // The unsupported scheme
+}
+
+// Error returns a formatted error message describing the unsupported scheme.
+func (e UnsupportedSchemeError) Error() string {
+	return fmt.Sprintf("coding/words: unsupported scheme %d", int(e.Scheme))
+}
+
+// Code returns the stable error code DGL-WORDS-001.
+func (e UnsupportedSchemeError) Code() string {
+	return "DGL-WORDS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedSchemeError) Fields() map[string]any {
+	return errcode.NewFields("coding/words", "", "", "scheme", int(e.Scheme))
+}
+
+// InvalidLengthError represents an error when the input cannot be encoded by
+// the scheme, such as an odd number of bytes for Proquint, or when a word
+// sequence has a number of words the scheme never produces.
+type InvalidLengthError struct {
+	Scheme Scheme // The scheme in use
+	Length int    // The number of bytes to encode or of words to decode
+}
+
+// Error returns a formatted error message describing the invalid length.
+func (e InvalidLengthError) Error() string {
+	return fmt.Sprintf("coding/words: invalid length %d for %s", e.Length, e.Scheme)
+}
+
+// Code returns the stable error code DGL-WORDS-002.
+func (e InvalidLengthError) Code() string {
+	return "DGL-WORDS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidLengthError) Fields() map[string]any {
+	return errcode.NewFields("coding/words", e.Scheme.String(), "", "length", e.Length)
+}
+
+// InvalidWordError represents an error when a word is not part of the word
+// list of the scheme, which usually means it was misheard or misspelled.
+type InvalidWordError struct {
+	Scheme   Scheme // The scheme in use
+	Word     string // The unknown word
+	Position int    // The zero-based position of the word in the sequence
+}
+
+// Error returns a formatted error message describing the invalid word.
+func (e InvalidWordError) Error() string {
+	return fmt.Sprintf("coding/words: invalid %s word %q at position %d", e.Scheme, e.Word, e.Position)
+}
+
+// Code returns the stable error code DGL-WORDS-003.
+func (e InvalidWordError) Code() string {
+	return "DGL-WORDS-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidWordError) Fields() map[string]any {
+	return errcode.NewFields("coding/words", e.Scheme.String(), "", "word", e.Word, "position", e.Position)
+}
+
+// WordOrderError represents an error when a PGP word appears at a position of
+// the wrong parity, which means a word before it was dropped, repeated or two
+// words were swapped.
+type WordOrderError struct {
+	Word     string // The misplaced word
+	Position int    // The zero-based position of the word in the sequence
+}
+
+// Error returns a formatted error message describing the misplaced word.
+func (e WordOrderError) Error() string {
+	return fmt.Sprintf("coding/words: PGP word %q out of order at position %d", e.Word, e.Position)
+}
+
+// Code returns the stable error code DGL-WORDS-004.
+func (e WordOrderError) Code() string {
+	return "DGL-WORDS-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WordOrderError) Fields() map[string]any {
+	return errcode.NewFields("coding/words", PGP.String(), "", "word", e.Word, "position", e.Position)
+}
+
+// ChecksumError represents an error when the checksum of a BIP-39 mnemonic
+// does not match its words.
+type ChecksumError struct{}
+
+// Error returns a formatted error message describing the checksum mismatch.
+func (e ChecksumError) Error() string {
+	return "coding/words: BIP-39 checksum mismatch"
+}
+
+// Code returns the stable error code DGL-WORDS-005.
+func (e ChecksumError) Code() string {
+	return "DGL-WORDS-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ChecksumError) Fields() map[string]any {
+	return errcode.NewFields("coding/words", BIP39.String(), "")
+}
@@ -0,0 +1,424 @@
+// Package words implements encodings of binary data as sequences of words with
+// streaming support, for digests and key fingerprints that are read aloud or
+// typed by hand. It supports the PGP word list, BIP-39 mnemonics and Proquint
+// identifiers; decoding is case insensitive and accepts any mix of spaces,
+// line breaks and hyphens between words.
+package words
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Scheme selects how bytes are mapped to words.
+type Scheme int
+
+const (
+	// PGP encodes every byte as a word of the PGP word list, alternating
+	// two-syllable words at even positions with three-syllable words at odd
+	// positions, so that a dropped, repeated or swapped word is detected.
+	PGP Scheme = iota
+	// BIP39 encodes 16 to 32 bytes, in steps of 4, as a BIP-39 mnemonic of the
+	// English word list, 11 bits per word followed by a SHA-256 checksum.
+	BIP39
+	// Proquint encodes every 16 bits as a pronounceable quintuplet of
+	// alternating consonants and vowels, such as "lusab-babad" for 127.0.0.1.
+	Proquint
+)
+
+// String returns the name of the scheme.
+func (s Scheme) String() string {
+	switch s {
+	case PGP:
+		return "PGP"
+	case BIP39:
+		return "BIP-39"
+	case Proquint:
+		return "Proquint"
+	}
+	return fmt.Sprintf("Scheme(%d)", int(s))
+}
+
+// StdSeparator separates the words of PGP and BIP-39 sequences.
+var StdSeparator = " "
+
+// ProquintSeparator separates the quintuplets of Proquint sequences.
+var ProquintSeparator = "-"
+
+const (
+	proquintConsonants = "bdfghjklmnprstvz"
+	proquintVowels     = "aiou"
+)
+
+// pgpIndex maps the lower case PGP words to their byte, plus 256 for the
+// three-syllable words of odd positions.
+var pgpIndex = func() map[string]int {
+	m := make(map[string]int, 512)
+	for i := range 256 {
+		m[strings.ToLower(pgpEvenWords[i])] = i
+		m[strings.ToLower(pgpOddWords[i])] = 256 + i
+	}
+	return m
+}()
+
+// bip39Index maps the BIP-39 words to their 11-bit value.
+var bip39Index = func() map[string]int {
+	m := make(map[string]int, len(bip39Words))
+	for i, w := range bip39Words {
+		m[w] = i
+	}
+	return m
+}()
+
+// StdEncoder represents a word encoder for standard encoding operations.
+type StdEncoder struct {
+	scheme Scheme // The scheme used for encoding
+	Error  error  // Error field for storing encoding errors
+}
+
+// NewStdEncoder creates a new word encoder using the given scheme.
+func NewStdEncoder(scheme Scheme) *StdEncoder {
+	e := &StdEncoder{scheme: scheme}
+	if scheme < PGP || scheme > Proquint {
+		e.Error = UnsupportedSchemeError{Scheme: scheme}
+	}
+	return e
+}
+
+// Encode encodes the given byte slice as a sequence of words. Inputs the scheme
+// cannot encode, such as an odd number of bytes for Proquint, set the Error
+// field and return nil.
+func (e *StdEncoder) Encode(src []byte) (dst []byte) {
+	if e.Error != nil {
+		return
+	}
+	if len(src) == 0 {
+		return
+	}
+
+	switch e.scheme {
+	case PGP:
+		return appendPGP(nil, src, 0)
+	case BIP39:
+		dst, e.Error = encodeBIP39(src)
+		return dst
+	}
+	if len(src)%2 != 0 {
+		e.Error = InvalidLengthError{Scheme: Proquint, Length: len(src)}
+		return nil
+	}
+	return appendProquint(nil, src, 0)
+}
+
+// appendPGP appends the PGP words of src to dst, pos being the position of the
+// first byte of src in the whole input.
+func appendPGP(dst, src []byte, pos int) []byte {
+	for i, b := range src {
+		if pos+i > 0 {
+			dst = append(dst, StdSeparator...)
+		}
+		if (pos+i)%2 == 0 {
+			dst = append(dst, pgpEvenWords[b]...)
+		} else {
+			dst = append(dst, pgpOddWords[b]...)
+		}
+	}
+	return dst
+}
+
+// appendProquint appends the quintuplets of src, which has an even length, to
+// dst, pos being the number of quintuplets encoded before.
+func appendProquint(dst, src []byte, pos int) []byte {
+	for i := 0; i+1 < len(src); i += 2 {
+		if pos+i/2 > 0 {
+			dst = append(dst, ProquintSeparator...)
+		}
+		v := uint16(src[i])<<8 | uint16(src[i+1])
+		dst = append(dst,
+			proquintConsonants[v>>12], proquintVowels[v>>10&3],
+			proquintConsonants[v>>6&15], proquintVowels[v>>4&3],
+			proquintConsonants[v&15],
+		)
+	}
+	return dst
+}
+
+// encodeBIP39 returns the BIP-39 mnemonic of the entropy src.
+func encodeBIP39(src []byte) ([]byte, error) {
+	if len(src) < 16 || len(src) > 32 || len(src)%4 != 0 {
+		return nil, InvalidLengthError{Scheme: BIP39, Length: len(src)}
+	}
+	// The checksum is the first len(src)/4 bits of the SHA-256 of src
+	sum := sha256.Sum256(src)
+	bits := append(append(make([]byte, 0, len(src)+1), src...), sum[0])
+
+	var dst []byte
+	for w := range len(src) * 3 / 4 {
+		if w > 0 {
+			dst = append(dst, StdSeparator...)
+		}
+		index := 0
+		for j := range 11 {
+			bit := w*11 + j
+			index = index<<1 | int(bits[bit/8]>>(7-bit%8)&1)
+		}
+		dst = append(dst, bip39Words[index]...)
+	}
+	return dst, nil
+}
+
+// StdDecoder represents a word decoder for standard decoding operations.
+type StdDecoder struct {
+	scheme Scheme // The scheme used for decoding
+	Error  error  // Error field for storing decoding errors
+}
+
+// NewStdDecoder creates a new word decoder using the given scheme.
+func NewStdDecoder(scheme Scheme) *StdDecoder {
+	d := &StdDecoder{scheme: scheme}
+	if scheme < PGP || scheme > Proquint {
+		d.Error = UnsupportedSchemeError{Scheme: scheme}
+	}
+	return d
+}
+
+// Decode decodes the given sequence of words back to bytes. Words are matched
+// case insensitively and may be separated by any whitespace or hyphens.
+func (d *StdDecoder) Decode(src []byte) (dst []byte, err error) {
+	if d.Error != nil {
+		err = d.Error
+		return
+	}
+	if len(src) == 0 {
+		return
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(utils.Bytes2String(src)), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-'
+	})
+	if len(words) == 0 {
+		return
+	}
+
+	switch d.scheme {
+	case PGP:
+		return decodePGP(words)
+	case BIP39:
+		return decodeBIP39(words)
+	}
+	return decodeProquint(words)
+}
+
+// decodePGP returns the bytes of a sequence of PGP words.
+func decodePGP(words []string) ([]byte, error) {
+	dst := make([]byte, len(words))
+	for i, w := range words {
+		v, ok := pgpIndex[w]
+		if !ok {
+			return nil, InvalidWordError{Scheme: PGP, Word: w, Position: i}
+		}
+		if (v >= 256) != (i%2 == 1) {
+			return nil, WordOrderError{Word: w, Position: i}
+		}
+		dst[i] = byte(v)
+	}
+	return dst, nil
+}
+
+// decodeBIP39 returns the entropy of a BIP-39 mnemonic after verifying its
+// checksum.
+func decodeBIP39(words []string) ([]byte, error) {
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, InvalidLengthError{Scheme: BIP39, Length: len(words)}
+	}
+	size := len(words) * 4 / 3
+	bits := make([]byte, size+1)
+	for w, word := range words {
+		index, ok := bip39Index[word]
+		if !ok {
+			return nil, InvalidWordError{Scheme: BIP39, Word: word, Position: w}
+		}
+		for j := range 11 {
+			bit := w*11 + j
+			bits[bit/8] |= byte(index>>(10-j)&1) << (7 - bit%8)
+		}
+	}
+
+	sum := sha256.Sum256(bits[:size])
+	mask := byte(0xff) << (8 - size/4)
+	if bits[size] != sum[0]&mask {
+		return nil, ChecksumError{}
+	}
+	return bits[:size], nil
+}
+
+// decodeProquint returns the bytes of a sequence of Proquint quintuplets.
+func decodeProquint(words []string) ([]byte, error) {
+	dst := make([]byte, 0, len(words)*2)
+	for i, w := range words {
+		v, ok := proquintValue(w)
+		if !ok {
+			return nil, InvalidWordError{Scheme: Proquint, Word: w, Position: i}
+		}
+		dst = append(dst, byte(v>>8), byte(v))
+	}
+	return dst, nil
+}
+
+// proquintValue returns the 16 bits a quintuplet stands for.
+func proquintValue(w string) (v uint16, ok bool) {
+	if len(w) != 5 {
+		return 0, false
+	}
+	for i := range 5 {
+		alphabet, bits := proquintConsonants, 4
+		if i%2 == 1 {
+			alphabet, bits = proquintVowels, 2
+		}
+		k := strings.IndexByte(alphabet, w[i])
+		if k < 0 {
+			return 0, false
+		}
+		v = v<<bits | uint16(k)
+	}
+	return v, true
+}
+
+// StreamEncoder represents a streaming word encoder that implements
+// io.WriteCloser. PGP and Proquint words are written as soon as their bytes
+// are; a BIP-39 mnemonic is written on Close, as its checksum covers the whole
+// input.
+type StreamEncoder struct {
+	writer  io.Writer   // Underlying writer for encoded output
+	encoder *StdEncoder // Encoder holding the scheme
+	buffer  []byte      // Odd Proquint byte or whole BIP-39 input not encoded yet
+	count   int         // Number of words written so far
+	Error   error       // Error field for storing encoding errors
+}
+
+// NewStreamEncoder creates a new streaming word encoder that writes encoded
+// data to the provided io.Writer using the given scheme.
+func NewStreamEncoder(w io.Writer, scheme Scheme) io.WriteCloser {
+	return &StreamEncoder{
+		writer:  w,
+		encoder: NewStdEncoder(scheme),
+	}
+}
+
+// Write implements the io.Writer interface for streaming word encoding.
+func (e *StreamEncoder) Write(p []byte) (n int, err error) {
+	if e.Error != nil {
+		return 0, e.Error
+	}
+	if e.encoder.Error != nil {
+		return 0, e.encoder.Error
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var encoded []byte
+	switch e.encoder.scheme {
+	case PGP:
+		encoded = appendPGP(nil, p, e.count)
+		e.count += len(p)
+	case BIP39:
+		e.buffer = append(e.buffer, p...)
+		if len(e.buffer) > 32 {
+			e.Error = InvalidLengthError{Scheme: BIP39, Length: len(e.buffer)}
+			return len(p), e.Error
+		}
+		return len(p), nil
+	case Proquint:
+		data := append(e.buffer, p...)
+		even := len(data) &^ 1
+		encoded = appendProquint(nil, data[:even], e.count)
+		e.count += even / 2
+		e.buffer = append(e.buffer[:0], data[even:]...)
+	}
+
+	if _, err = e.writer.Write(encoded); err != nil {
+		e.Error = err
+		return len(p), err
+	}
+	return len(p), nil
+}
+
+// Close implements the io.Closer interface for streaming word encoding.
+// Writes the BIP-39 mnemonic, and fails if a Proquint byte is left unpaired.
+func (e *StreamEncoder) Close() error {
+	if e.Error != nil {
+		return e.Error
+	}
+	if e.encoder.Error != nil {
+		return e.encoder.Error
+	}
+	if len(e.buffer) == 0 {
+		return nil
+	}
+
+	if e.encoder.scheme == Proquint {
+		e.Error = InvalidLengthError{Scheme: Proquint, Length: e.count*2 + len(e.buffer)}
+		return e.Error
+	}
+	encoded, err := encodeBIP39(e.buffer)
+	if err == nil {
+		_, err = e.writer.Write(encoded)
+	}
+	e.Error = err
+	e.buffer = nil
+	return err
+}
+
+// StreamDecoder represents a streaming word decoder that implements io.Reader.
+// Word sequences are short, so the input is read whole on the first Read and
+// decoded at once, which also verifies a BIP-39 checksum before any byte is
+// returned.
+type StreamDecoder struct {
+	reader  io.Reader   // Underlying reader for encoded input
+	decoder *StdDecoder // Decoder holding the scheme
+	buffer  []byte      // Decoded data
+	pos     int         // Current position in the decoded data
+	decoded bool        // Whether the input was read and decoded
+	Error   error       // Error field for storing decoding errors
+}
+
+// NewStreamDecoder creates a new streaming word decoder that reads encoded data
+// from the provided io.Reader using the given scheme.
+func NewStreamDecoder(r io.Reader, scheme Scheme) io.Reader {
+	return &StreamDecoder{
+		reader:  r,
+		decoder: NewStdDecoder(scheme),
+	}
+}
+
+// Read implements the io.Reader interface for streaming word decoding.
+func (d *StreamDecoder) Read(p []byte) (n int, err error) {
+	if d.Error != nil {
+		return 0, d.Error
+	}
+
+	if !d.decoded {
+		d.decoded = true
+		src, err := io.ReadAll(d.reader)
+		if err != nil {
+			d.Error = err
+			return 0, err
+		}
+		if d.buffer, d.Error = d.decoder.Decode(src); d.Error != nil {
+			return 0, d.Error
+		}
+	}
+
+	if d.pos >= len(d.buffer) {
+		return 0, io.EOF
+	}
+	n = copy(p, d.buffer[d.pos:])
+	d.pos += n
+	return n, nil
+}
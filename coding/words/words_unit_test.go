@@ -0,0 +1,285 @@
+package words
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors from the PGP word list article, the BIP-39 reference
+// implementation and the Proquint specification.
+var (
+	pgpSrc     = "e58294f2e9a227486e8b061b31cc528fd7fa3f19"
+	pgpEncoded = "topmost Istanbul Pluto vagabond treadmill Pacific brackish dictator goldfish Medusa " +
+		"afflict bravado chatter revolver Dupont midsummer stopwatch whimsical cowbell bottomless"
+
+	bip39Vectors = map[string]string{
+		"00000000000000000000000000000000": "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f": "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"80808080808080808080808080808080": "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"ffffffffffffffffffffffffffffffff": "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"9e885d952ad362caeb4efe34a8e91bd2": "ozone drill grab fiber curtain grace pudding thank cruise elder eight picnic",
+		"0000000000000000000000000000000000000000000000000000000000000000": "abandon abandon abandon abandon abandon abandon " +
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon " +
+			"abandon abandon abandon art",
+		"f585c11aec520db57dd353c69554b21a89b20fb0650966fa0a9d6f74fd989d8f": "void come effort suffer camp survey warrior heavy " +
+			"shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+	}
+
+	proquintVectors = map[string]string{
+		"7f000001": "lusab-babad",
+		"3f54dcc1": "gutih-tugad",
+		"8c62c18d": "mudof-sakat",
+	}
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.Nil(t, err)
+	return b
+}
+
+func TestStdEncoder_Encode(t *testing.T) {
+	t.Run("pgp", func(t *testing.T) {
+		assert.Equal(t, pgpEncoded, string(NewStdEncoder(PGP).Encode(decodeHex(t, pgpSrc))))
+	})
+
+	t.Run("bip39", func(t *testing.T) {
+		for src, want := range bip39Vectors {
+			assert.Equal(t, want, string(NewStdEncoder(BIP39).Encode(decodeHex(t, src))), src)
+		}
+	})
+
+	t.Run("proquint", func(t *testing.T) {
+		for src, want := range proquintVectors {
+			assert.Equal(t, want, string(NewStdEncoder(Proquint).Encode(decodeHex(t, src))), src)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		for _, scheme := range []Scheme{PGP, BIP39, Proquint} {
+			assert.Nil(t, NewStdEncoder(scheme).Encode(nil))
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		encoder := NewStdEncoder(BIP39)
+		assert.Nil(t, encoder.Encode(make([]byte, 18)))
+		assert.Equal(t, InvalidLengthError{Scheme: BIP39, Length: 18}, encoder.Error)
+		assert.Equal(t, "coding/words: invalid length 18 for BIP-39", encoder.Error.Error())
+
+		encoder = NewStdEncoder(BIP39)
+		assert.Nil(t, encoder.Encode(make([]byte, 36)))
+		assert.IsType(t, InvalidLengthError{}, encoder.Error)
+
+		encoder = NewStdEncoder(Proquint)
+		assert.Nil(t, encoder.Encode([]byte{1, 2, 3}))
+		assert.Equal(t, InvalidLengthError{Scheme: Proquint, Length: 3}, encoder.Error)
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		encoder := NewStdEncoder(Scheme(9))
+		assert.Nil(t, encoder.Encode([]byte("hello")))
+		assert.Equal(t, UnsupportedSchemeError{Scheme: 9}, encoder.Error)
+		assert.Equal(t, "coding/words: unsupported scheme 9", encoder.Error.Error())
+	})
+}
+
+func TestStdDecoder_Decode(t *testing.T) {
+	t.Run("pgp", func(t *testing.T) {
+		dst, err := NewStdDecoder(PGP).Decode([]byte(pgpEncoded))
+		assert.Nil(t, err)
+		assert.Equal(t, decodeHex(t, pgpSrc), dst)
+	})
+
+	t.Run("bip39", func(t *testing.T) {
+		for want, src := range bip39Vectors {
+			dst, err := NewStdDecoder(BIP39).Decode([]byte(src))
+			assert.Nil(t, err, src)
+			assert.Equal(t, decodeHex(t, want), dst, src)
+		}
+	})
+
+	t.Run("proquint", func(t *testing.T) {
+		for want, src := range proquintVectors {
+			dst, err := NewStdDecoder(Proquint).Decode([]byte(src))
+			assert.Nil(t, err, src)
+			assert.Equal(t, decodeHex(t, want), dst, src)
+		}
+	})
+
+	t.Run("loose spelling", func(t *testing.T) {
+		dst, err := NewStdDecoder(PGP).Decode([]byte("  TOPMOST\tistanbul\n pluto-Vagabond "))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{0xe5, 0x82, 0x94, 0xf2}, dst)
+
+		dst, err = NewStdDecoder(Proquint).Decode([]byte("LUSAB babad"))
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{127, 0, 0, 1}, dst)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		for _, scheme := range []Scheme{PGP, BIP39, Proquint} {
+			dst, err := NewStdDecoder(scheme).Decode([]byte(" \n- "))
+			assert.Nil(t, err)
+			assert.Nil(t, dst)
+		}
+	})
+
+	t.Run("invalid word", func(t *testing.T) {
+		_, err := NewStdDecoder(PGP).Decode([]byte("topmost istanbul plato"))
+		assert.Equal(t, InvalidWordError{Scheme: PGP, Word: "plato", Position: 2}, err)
+		assert.Equal(t, `coding/words: invalid PGP word "plato" at position 2`, err.Error())
+
+		_, err = NewStdDecoder(BIP39).Decode([]byte(strings.Replace(bip39Vectors["7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f"], "wave", "wove", 1)))
+		assert.Equal(t, InvalidWordError{Scheme: BIP39, Word: "wove", Position: 4}, err)
+
+		for _, word := range []string{"lusa", "lusabb", "ulsab", "xusab"} {
+			_, err = NewStdDecoder(Proquint).Decode([]byte("babad-" + word))
+			assert.Equal(t, InvalidWordError{Scheme: Proquint, Word: word, Position: 1}, err, word)
+		}
+	})
+
+	t.Run("word order", func(t *testing.T) {
+		// A dropped word shifts every following word to the wrong parity
+		_, err := NewStdDecoder(PGP).Decode([]byte("topmost pluto vagabond"))
+		assert.Equal(t, WordOrderError{Word: "pluto", Position: 1}, err)
+		assert.Equal(t, `coding/words: PGP word "pluto" out of order at position 1`, err.Error())
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		_, err := NewStdDecoder(BIP39).Decode([]byte(strings.Replace(bip39Vectors["7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f"], "yellow", "year", 1)))
+		assert.Equal(t, ChecksumError{}, err)
+		assert.Equal(t, "coding/words: BIP-39 checksum mismatch", err.Error())
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		_, err := NewStdDecoder(BIP39).Decode([]byte("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"))
+		assert.Equal(t, InvalidLengthError{Scheme: BIP39, Length: 11}, err)
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, err := NewStdDecoder(Scheme(-1)).Decode([]byte("zoo"))
+		assert.Equal(t, UnsupportedSchemeError{Scheme: -1}, err)
+	})
+}
+
+func TestStreamEncoder(t *testing.T) {
+	t.Run("chunked writes", func(t *testing.T) {
+		src := decodeHex(t, pgpSrc)
+		for _, scheme := range []Scheme{PGP, BIP39, Proquint} {
+			var buf bytes.Buffer
+			encoder := NewStreamEncoder(&buf, scheme)
+			for _, chunk := range [][]byte{src[:3], src[3:4], nil, src[4:]} {
+				n, err := encoder.Write(chunk)
+				assert.Nil(t, err, scheme)
+				assert.Equal(t, len(chunk), n, scheme)
+			}
+			assert.Nil(t, encoder.Close(), scheme)
+			assert.Equal(t, string(NewStdEncoder(scheme).Encode(src)), buf.String(), scheme)
+		}
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		encoder := NewStreamEncoder(io.Discard, Proquint)
+		encoder.Write([]byte{1, 2, 3})
+		assert.Equal(t, InvalidLengthError{Scheme: Proquint, Length: 3}, encoder.Close())
+
+		encoder = NewStreamEncoder(io.Discard, BIP39)
+		_, err := encoder.Write(make([]byte, 33))
+		assert.Equal(t, InvalidLengthError{Scheme: BIP39, Length: 33}, err)
+		_, err = encoder.Write([]byte{1})
+		assert.IsType(t, InvalidLengthError{}, err)
+		assert.IsType(t, InvalidLengthError{}, encoder.Close())
+
+		encoder = NewStreamEncoder(io.Discard, BIP39)
+		encoder.Write(make([]byte, 17))
+		assert.Equal(t, InvalidLengthError{Scheme: BIP39, Length: 17}, encoder.Close())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		encoder := NewStreamEncoder(mock.NewErrorWriteCloser(writeErr), PGP)
+		n, err := encoder.Write([]byte("hello"))
+		assert.Equal(t, 5, n)
+		assert.Equal(t, writeErr, err)
+		assert.Equal(t, writeErr, encoder.Close())
+
+		encoder = NewStreamEncoder(mock.NewErrorWriteCloser(writeErr), BIP39)
+		encoder.Write(make([]byte, 16))
+		assert.Equal(t, writeErr, encoder.Close())
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		encoder := NewStreamEncoder(io.Discard, Scheme(3))
+		_, err := encoder.Write([]byte("hello"))
+		assert.Equal(t, UnsupportedSchemeError{Scheme: 3}, err)
+		assert.Equal(t, UnsupportedSchemeError{Scheme: 3}, encoder.Close())
+	})
+}
+
+func TestStreamDecoder(t *testing.T) {
+	t.Run("small reads", func(t *testing.T) {
+		for want, src := range bip39Vectors {
+			decoder := NewStreamDecoder(mock.NewScriptedReader(bytes.NewReader([]byte(src))).Then(5).Then(1).Then(7).Repeat(), BIP39)
+			var got []byte
+			buf := make([]byte, 3)
+			for {
+				n, err := decoder.Read(buf)
+				got = append(got, buf[:n]...)
+				if err == io.EOF {
+					break
+				}
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, decodeHex(t, want), got)
+		}
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		decoder := NewStreamDecoder(strings.NewReader("topmost topmost"), PGP)
+		_, err := decoder.Read(make([]byte, 8))
+		assert.Equal(t, WordOrderError{Word: "topmost", Position: 1}, err)
+		_, err = decoder.Read(make([]byte, 8))
+		assert.IsType(t, WordOrderError{}, err)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("read error")
+		decoder := NewStreamDecoder(mock.NewErrorFile(readErr), Proquint)
+		_, err := decoder.Read(make([]byte, 8))
+		assert.Equal(t, readErr, err)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		got, err := io.ReadAll(NewStreamDecoder(strings.NewReader(""), PGP))
+		assert.Nil(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestScheme_String(t *testing.T) {
+	assert.Equal(t, "PGP", PGP.String())
+	assert.Equal(t, "BIP-39", BIP39.String())
+	assert.Equal(t, "Proquint", Proquint.String())
+	assert.Equal(t, "Scheme(7)", Scheme(7).String())
+}
+
+func TestErrors(t *testing.T) {
+	assert.Equal(t, "DGL-WORDS-001", UnsupportedSchemeError{}.Code())
+	assert.Equal(t, "DGL-WORDS-002", InvalidLengthError{}.Code())
+	assert.Equal(t, "DGL-WORDS-003", InvalidWordError{}.Code())
+	assert.Equal(t, "DGL-WORDS-004", WordOrderError{}.Code())
+	assert.Equal(t, "DGL-WORDS-005", ChecksumError{}.Code())
+
+	fields := InvalidWordError{Scheme: BIP39, Word: "wove", Position: 4}.Fields()
+	assert.Equal(t, "BIP-39", fields["algorithm"])
+	assert.Equal(t, "wove", fields["word"])
+	assert.Equal(t, 4, fields["position"])
+}
@@ -0,0 +1,94 @@
+package coding
+
+import (
+	"testing"
+
+	"github.com/dromara/dongle/coding/words"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test data for word encodings (PGP word list article, BIP-39 reference
+// vectors and the Proquint specification)
+var (
+	pgpWordsSrc     = []byte{0xe5, 0x82, 0x94, 0xf2, 0xe9, 0xa2}
+	pgpWordsEncoded = "topmost Istanbul Pluto vagabond treadmill Pacific"
+
+	bip39Src     = []byte{0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f}
+	bip39Encoded = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+	proquintSrc     = []byte{127, 0, 0, 1}
+	proquintEncoded = "lusab-babad"
+)
+
+func TestEncoder_ByWords(t *testing.T) {
+	t.Run("encode", func(t *testing.T) {
+		assert.Equal(t, pgpWordsEncoded, NewEncoder().FromBytes(pgpWordsSrc).ByPgpWords().ToString())
+		assert.Equal(t, bip39Encoded, NewEncoder().FromBytes(bip39Src).ByBip39().ToString())
+		assert.Equal(t, proquintEncoded, NewEncoder().FromBytes(proquintSrc).ByProquint().ToString())
+	})
+
+	t.Run("encode file", func(t *testing.T) {
+		assert.Equal(t, pgpWordsEncoded, NewEncoder().FromFile(mock.NewFile(pgpWordsSrc, "src")).ByPgpWords().ToString())
+		assert.Equal(t, bip39Encoded, NewEncoder().FromFile(mock.NewFile(bip39Src, "src")).ByBip39().ToString())
+		assert.Equal(t, proquintEncoded, NewEncoder().FromFile(mock.NewFile(proquintSrc, "src")).ByProquint().ToString())
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		encoder := NewEncoder().FromString("").ByBip39()
+		assert.Nil(t, encoder.Error)
+		assert.Empty(t, encoder.ToString())
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		encoder := NewEncoder().FromString("hello").ByBip39()
+		assert.Equal(t, words.InvalidLengthError{Scheme: words.BIP39, Length: 5}, encoder.Error)
+
+		encoder = NewEncoder().FromString("hello").ByProquint()
+		assert.Equal(t, words.InvalidLengthError{Scheme: words.Proquint, Length: 5}, encoder.Error)
+
+		encoder = NewEncoder().FromFile(mock.NewFile([]byte("hello"), "src")).ByProquint()
+		assert.IsType(t, words.InvalidLengthError{}, encoder.Error)
+	})
+
+	t.Run("existing error", func(t *testing.T) {
+		encoder := NewEncoder()
+		encoder.Error = assert.AnError
+		assert.Equal(t, assert.AnError, encoder.ByPgpWords().Error)
+		assert.Equal(t, assert.AnError, encoder.ByBip39().Error)
+		assert.Equal(t, assert.AnError, encoder.ByProquint().Error)
+	})
+}
+
+func TestDecoder_ByWords(t *testing.T) {
+	t.Run("decode", func(t *testing.T) {
+		assert.Equal(t, pgpWordsSrc, NewDecoder().FromString(pgpWordsEncoded).ByPgpWords().ToBytes())
+		assert.Equal(t, bip39Src, NewDecoder().FromString(bip39Encoded).ByBip39().ToBytes())
+		assert.Equal(t, proquintSrc, NewDecoder().FromString(proquintEncoded).ByProquint().ToBytes())
+	})
+
+	t.Run("decode file", func(t *testing.T) {
+		assert.Equal(t, pgpWordsSrc, NewDecoder().FromFile(mock.NewFile([]byte(pgpWordsEncoded), "src")).ByPgpWords().ToBytes())
+		assert.Equal(t, bip39Src, NewDecoder().FromFile(mock.NewFile([]byte(bip39Encoded), "src")).ByBip39().ToBytes())
+		assert.Equal(t, proquintSrc, NewDecoder().FromFile(mock.NewFile([]byte(proquintEncoded), "src")).ByProquint().ToBytes())
+	})
+
+	t.Run("misheard word", func(t *testing.T) {
+		decoder := NewDecoder().FromString("topmost istanbul plato").ByPgpWords()
+		assert.Equal(t, words.InvalidWordError{Scheme: words.PGP, Word: "plato", Position: 2}, decoder.Error)
+		assert.Empty(t, decoder.ToBytes())
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		decoder := NewDecoder().FromString("legal winner thank year wave sausage worth useful legal winner thank year").ByBip39()
+		assert.Equal(t, words.ChecksumError{}, decoder.Error)
+	})
+
+	t.Run("existing error", func(t *testing.T) {
+		decoder := NewDecoder()
+		decoder.Error = assert.AnError
+		assert.Equal(t, assert.AnError, decoder.ByPgpWords().Error)
+		assert.Equal(t, assert.AnError, decoder.ByBip39().Error)
+		assert.Equal(t, assert.AnError, decoder.ByProquint().Error)
+	})
+}
@@ -13,6 +13,13 @@ func (e Encrypter) By3Des(c *cipher.TripleDesCipher) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "3DES", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("3DES", string(c.Block)); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -35,6 +42,13 @@ func (d Decrypter) By3Des(c *cipher.TripleDesCipher) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "3DES", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("3DES", string(c.Block)); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -45,7 +59,7 @@ func (d Decrypter) By3Des(c *cipher.TripleDesCipher) Decrypter {
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = tripledes.NewStdDecrypter(c).Decrypt(d.src)
+		d.dst, d.Error = d.limit(tripledes.NewStdDecrypter(c).Decrypt(d.src))
 	}
 
 	return d
@@ -38,6 +38,9 @@ func NewStdEncrypter(c *cipher.TripleDesCipher) *StdEncrypter {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckGCMOptions(); e.Error != nil {
+		return e
+	}
 
 	return e
 }
@@ -58,6 +61,11 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, newBlock)
+	}
+
 	// Prepare the key for Triple DES cipher block
 	key := expandKey(e.cipher.Key)
 
@@ -97,6 +105,9 @@ func NewStdDecrypter(c *cipher.TripleDesCipher) *StdDecrypter {
 		d.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return d
 	}
+	if d.Error = c.CheckGCMOptions(); d.Error != nil {
+		return d
+	}
 
 	return d
 }
@@ -117,6 +128,11 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, newBlock)
+	}
+
 	// Prepare the key for Triple DES cipher block
 	block, err := des.NewTripleDESCipher(expandKey(d.cipher.Key))
 	if err != nil {
@@ -159,6 +175,10 @@ func NewStreamEncrypter(w io.Writer, c *cipher.TripleDesCipher) io.WriteCloser {
 		return e
 	}
 
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
+
 	e.block, e.Error = des.NewTripleDESCipher(expandKey(c.Key))
 	return e
 }
@@ -246,6 +266,10 @@ func NewStreamDecrypter(r io.Reader, c *cipher.TripleDesCipher) io.Reader {
 		return d
 	}
 
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+
 	d.block, d.Error = des.NewTripleDESCipher(expandKey(c.Key))
 	return d
 }
@@ -305,3 +329,8 @@ func expandKey(key []byte) []byte {
 	}
 	return key
 }
+
+// newBlock creates a Triple DES cipher block from a 16 or 24-byte key.
+func newBlock(key []byte) (stdCipher.Block, error) {
+	return des.NewTripleDESCipher(expandKey(key))
+}
@@ -0,0 +1,55 @@
+package triple_des
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageKey(t *testing.T) {
+	newCipher := func(key []byte) *cipher.TripleDesCipher {
+		c := cipher.New3DesCipher(cipher.CBC)
+		c.SetKey(key)
+		c.SetIV([]byte("12345678"))
+		c.SetPadding(cipher.PKCS7)
+		c.SetMessageKey(true)
+		return c
+	}
+	src := []byte("hello world")
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, key := range [][]byte{[]byte("1234567890123456"), []byte("123456789012345678901234")} {
+			c := newCipher(key)
+			dst1, err := NewStdEncrypter(c).Encrypt(src)
+			assert.NoError(t, err)
+			assert.Len(t, dst1, cipher.MessageKeySaltSize+16)
+			dst2, err := NewStdEncrypter(c).Encrypt(src)
+			assert.NoError(t, err)
+			assert.NotEqual(t, dst1, dst2)
+
+			for _, dst := range [][]byte{dst1, dst2} {
+				got, err := NewStdDecrypter(c).Decrypt(dst)
+				assert.NoError(t, err)
+				assert.Equal(t, src, got)
+			}
+		}
+	})
+
+	t.Run("missing salt", func(t *testing.T) {
+		_, err := NewStdDecrypter(newCipher([]byte("1234567890123456"))).Decrypt([]byte("short"))
+		assert.IsType(t, cipher.MissingSaltError{}, err)
+	})
+
+	t.Run("streams unsupported", func(t *testing.T) {
+		c := newCipher([]byte("1234567890123456"))
+		var buf bytes.Buffer
+		_, err := NewStreamEncrypter(&buf, c).Write(src)
+		assert.IsType(t, cipher.UnsupportedMessageKeyError{}, err)
+
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(src), c))
+		assert.IsType(t, cipher.UnsupportedMessageKeyError{}, err)
+	})
+}
@@ -2,6 +2,8 @@ package triple_des
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the Triple DES key size is invalid.
@@ -17,6 +19,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/3des: invalid key size %d, must be 16 or 24 bytes", k)
 }
 
+// Code returns the stable error code DGL-3DES-001.
+func (k KeySizeError) Code() string {
+	return "DGL-3DES-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/3des", "3DES", "key", errcode.FieldKeySize, int(k))
+}
+
 // EncryptError represents an error when Triple DES encryption operation fails.
 // This error occurs when the encryption process fails due to various reasons.
 // The error includes the underlying error for detailed debugging.
@@ -30,6 +42,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/3des: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-3DES-002.
+func (e EncryptError) Code() string {
+	return "DGL-3DES-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/3des", "3DES", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when Triple DES decryption operation fails.
 // This error occurs when the decryption process fails due to various reasons.
 // The error includes the underlying error for detailed debugging.
@@ -43,6 +65,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/3des: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-3DES-003.
+func (e DecryptError) Code() string {
+	return "DGL-3DES-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/3des", "3DES", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -56,6 +88,16 @@ func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/3des: failed to read encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-3DES-004.
+func (e ReadError) Code() string {
+	return "DGL-3DES-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/3des", "3DES", "read", errcode.FieldCause, e.Err)
+}
+
 // BufferError represents an error when the buffer size is too small.
 // This error occurs when the provided buffer is too small to hold the decrypted data.
 // The error includes both buffer size and data size for detailed debugging.
@@ -70,6 +112,16 @@ func (e BufferError) Error() string {
 	return fmt.Sprintf("crypto/3des: buffer size %d is too small for data size %d", e.bufferSize, e.dataSize)
 }
 
+// Code returns the stable error code DGL-3DES-005.
+func (e BufferError) Code() string {
+	return "DGL-3DES-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BufferError) Fields() map[string]any {
+	return errcode.NewFields("crypto/3des", "3DES", "", "buffer_size", e.bufferSize, "data_size", e.dataSize)
+}
+
 // UnsupportedBlockModeError represents an error when an unsupported block mode is used.
 // This error occurs when trying to use cipher modes that are not supported by 3DES,
 // such as GCM mode which requires 128-bit block size while 3DES only has 64-bit block size.
@@ -82,3 +134,13 @@ type UnsupportedBlockModeError struct {
 func (e UnsupportedBlockModeError) Error() string {
 	return fmt.Sprintf("crypto/3des: unsupported block mode '%s', 3DES only supports CBC, CTR, ECB, CFB, and OFB modes", e.Mode)
 }
+
+// Code returns the stable error code DGL-3DES-006.
+func (e UnsupportedBlockModeError) Code() string {
+	return "DGL-3DES-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedBlockModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/3des", "3DES", "", "mode", e.Mode)
+}
@@ -6,6 +6,8 @@ package aes
 import (
 	"crypto/aes"
 	stdCipher "crypto/cipher"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/dromara/dongle/crypto/cipher"
@@ -48,11 +50,33 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Prefix the commitment to the configured key
+	if e.cipher.KeyCommitment {
+		if dst, err = e.encrypt(src); err != nil {
+			return
+		}
+		return e.cipher.CommitKey(dst)
+	}
+	return e.encrypt(src)
+}
+
+// encrypt encrypts non-empty src as configured, apart from the key
+// commitment.
+func (e *StdEncrypter) encrypt(src []byte) (dst []byte, err error) {
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, aes.NewCipher)
+	}
+
 	block, err := aes.NewCipher(e.cipher.Key)
 	if err != nil {
 		err = EncryptError{Err: err}
 		return
 	}
+	// Derive the nonce from the plaintext and AAD
+	if e.cipher.DeterministicNonce {
+		return e.cipher.EncryptWithDeterministicNonce(src, block)
+	}
 	return e.cipher.Encrypt(src, block)
 }
 
@@ -92,11 +116,27 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Check the commitment to the configured key
+	if d.cipher.KeyCommitment {
+		if src, err = d.cipher.OpenKeyCommitment(src); err != nil {
+			return
+		}
+	}
+
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, aes.NewCipher)
+	}
+
 	block, err := aes.NewCipher(d.cipher.Key)
 	if err != nil {
 		err = DecryptError{Err: err}
 		return
 	}
+	// Derive the nonce from the plaintext and AAD
+	if d.cipher.DeterministicNonce {
+		return d.cipher.DecryptWithDeterministicNonce(src, block)
+	}
 	return d.cipher.Decrypt(src, block)
 }
 
@@ -108,12 +148,18 @@ type StreamEncrypter struct {
 	cipher cipher.AesCipher // The cipher interface for encryption operations
 	buffer []byte           // Buffer for accumulating incomplete blocks
 	block  stdCipher.Block  // Reused cipher block for better performance
-	Error  error            // Error field for storing encryption errors
+	// Segmented GCM stream, see cipher.SetSegmentSize
+	segments *cipher.SegmentWriter
+	Error    error // Error field for storing encryption errors
 }
 
 // NewStreamEncrypter creates a new streaming AES encrypter that writes encrypted data
 // to the provided io.Writer. The encrypter uses the specified cipher interface
 // and validates the key length for proper AES encryption.
+//
+// GCM ciphers with a segment size, see SetSegmentSize, are encrypted segment by
+// segment without holding the stream in memory. Close must then be called to
+// seal the final segment.
 func NewStreamEncrypter(w io.Writer, c *cipher.AesCipher) io.WriteCloser {
 	e := &StreamEncrypter{
 		writer: w,
@@ -126,10 +172,26 @@ func NewStreamEncrypter(w io.Writer, c *cipher.AesCipher) io.WriteCloser {
 		return e
 	}
 
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
+
 	e.block, e.Error = aes.NewCipher(c.Key)
+	if e.Error == nil && c.Block == cipher.GCM && c.SegmentSize > 0 {
+		e.segments, e.Error = newSegmentWriter(w, c, e.block)
+	}
 	return e
 }
 
+// newSegmentWriter returns the segmented GCM stream writer of the cipher.
+func newSegmentWriter(w io.Writer, c *cipher.AesCipher, block stdCipher.Block) (*cipher.SegmentWriter, error) {
+	aead, err := c.NewAEAD(block)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewSegmentWriter(w, aead, c.Nonce, c.AAD, c.SegmentSize)
+}
+
 // Write implements the io.Writer interface for streaming AES encryption.
 // Provides improved performance through cipher block reuse while maintaining compatibility.
 // Accumulates data and processes it using the cipher interface for consistency.
@@ -143,6 +205,10 @@ func (e *StreamEncrypter) Write(p []byte) (n int, err error) {
 		return 0, nil
 	}
 
+	if e.segments != nil {
+		return e.segments.Write(p)
+	}
+
 	// Combine any leftover bytes from previous write with new data
 	data := append(e.buffer, p...)
 	e.buffer = nil // Clear buffer after combining
@@ -179,6 +245,13 @@ func (e *StreamEncrypter) Close() error {
 		return e.Error
 	}
 
+	// Seal the final segment
+	if e.segments != nil {
+		if err := e.segments.Close(); err != nil {
+			return err
+		}
+	}
+
 	// Close the underlying writer if it implements io.Closer
 	if closer, ok := e.writer.(io.Closer); ok {
 		return closer.Close()
@@ -186,6 +259,40 @@ func (e *StreamEncrypter) Close() error {
 	return nil
 }
 
+// Offset returns the number of ciphertext bytes of a segmented GCM stream
+// written so far, see cipher.SegmentWriter.Offset.
+func (e *StreamEncrypter) Offset() int64 {
+	if e.segments == nil {
+		return 0
+	}
+	return e.segments.Offset()
+}
+
+// MarshalBinary returns a checkpoint of a segmented GCM stream, see
+// cipher.SegmentWriter.MarshalBinary. It holds up to one segment of plaintext.
+func (e *StreamEncrypter) MarshalBinary() ([]byte, error) {
+	if e.Error != nil {
+		return nil, e.Error
+	}
+	if e.segments == nil {
+		return nil, EncryptError{Err: fmt.Errorf("checkpoints require a segmented GCM stream")}
+	}
+	return e.segments.MarshalBinary()
+}
+
+// UnmarshalBinary restores a checkpoint taken with MarshalBinary by an
+// encrypter with the same cipher. Subsequent writes continue at Offset, so w
+// should be positioned at that offset of the ciphertext.
+func (e *StreamEncrypter) UnmarshalBinary(data []byte) error {
+	if e.Error != nil {
+		return e.Error
+	}
+	if e.segments == nil {
+		return EncryptError{Err: fmt.Errorf("checkpoints require a segmented GCM stream")}
+	}
+	return e.segments.UnmarshalBinary(data)
+}
+
 // StreamDecrypter represents a streaming AES decrypter that implements io.Reader.
 // It provides efficient decryption for large data streams by processing data
 // in chunks and reading decrypted output from the underlying reader with proper state management.
@@ -195,12 +302,21 @@ type StreamDecrypter struct {
 	buffer   []byte           // Buffer for decrypted data
 	position int              // Current position in the buffer
 	block    stdCipher.Block  // Reused cipher block for better performance
-	Error    error            // Error field for storing decryption errors
+	// Segmented GCM stream, see cipher.SetSegmentSize
+	segments *cipher.SegmentReader
+	// GCM stream released before verification, see cipher.SetReleasePolicy
+	unverified *cipher.UnverifiedReader
+	Error      error // Error field for storing decryption errors
 }
 
 // NewStreamDecrypter creates a new streaming AES decrypter that reads encrypted data
 // from the provided io.Reader. The decrypter uses the specified cipher interface
 // and validates the key length for proper AES decryption.
+//
+// GCM ciphers with a segment size, see SetSegmentSize, are decrypted segment by
+// segment, and only authenticated plaintext is returned. GCM ciphers without
+// one return plaintext before the tag is verified only under the
+// ReleaseUnverified policy, see SetReleasePolicy.
 func NewStreamDecrypter(r io.Reader, c *cipher.AesCipher) io.Reader {
 	d := &StreamDecrypter{
 		reader:   r,
@@ -214,10 +330,29 @@ func NewStreamDecrypter(r io.Reader, c *cipher.AesCipher) io.Reader {
 		return d
 	}
 
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+
 	d.block, d.Error = aes.NewCipher(d.cipher.Key)
+	if d.Error == nil && c.Block == cipher.GCM && c.SegmentSize > 0 {
+		d.segments, d.Error = newSegmentReader(r, c, d.block)
+	}
+	if d.Error == nil {
+		d.unverified, d.Error = c.NewUnverifiedReader(r, d.block)
+	}
 	return d
 }
 
+// newSegmentReader returns the segmented GCM stream reader of the cipher.
+func newSegmentReader(r io.Reader, c *cipher.AesCipher, block stdCipher.Block) (*cipher.SegmentReader, error) {
+	aead, err := c.NewAEAD(block)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewSegmentReader(r, aead, c.Nonce, c.AAD, c.SegmentSize)
+}
+
 // Read implements the io.Reader interface for streaming AES decryption.
 // On the first call, reads all encrypted data from the underlying reader and decrypts it.
 // Subsequent calls return chunks of the decrypted data to maintain streaming interface.
@@ -227,6 +362,33 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 		return 0, d.Error
 	}
 
+	if d.segments != nil {
+		n, err = d.segments.Read(p)
+		var (
+			authErr    cipher.SegmentAuthError
+			segmentErr cipher.InvalidSegmentError
+		)
+		switch {
+		case errors.As(err, &authErr), errors.As(err, &segmentErr):
+			err = DecryptError{Err: err}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
+	if d.unverified != nil {
+		n, err = d.unverified.Read(p)
+		var authErr cipher.StreamAuthError
+		switch {
+		case errors.As(err, &authErr):
+			err = DecryptError{Err: err}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
 	// If we haven't decrypted the data yet, do it now
 	if d.buffer == nil {
 		// Read all encrypted data from the underlying reader
@@ -271,3 +433,87 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 
 	return copied, nil
 }
+
+// ResumableStreamEncrypter represents a streaming AES-CTR encrypter whose
+// position can be saved and restored, so an interrupted encrypted upload can
+// continue where it stopped instead of being encrypted again from the start.
+type ResumableStreamEncrypter struct {
+	writer io.Writer         // Underlying writer for encrypted output
+	stream *cipher.CTRStream // Key stream tracking the output offset
+	Error  error             // Error field for storing encryption errors
+}
+
+// NewResumableStreamEncrypter creates a new resumable AES encrypter writing to w.
+// The cipher must use CTR block mode and no padding is applied.
+func NewResumableStreamEncrypter(w io.Writer, c *cipher.AesCipher) *ResumableStreamEncrypter {
+	e := &ResumableStreamEncrypter{writer: w}
+	if len(c.Key) != 16 && len(c.Key) != 24 && len(c.Key) != 32 {
+		e.Error = KeySizeError(len(c.Key))
+		return e
+	}
+	if c.Block != cipher.CTR {
+		e.Error = EncryptError{Err: fmt.Errorf("resumable streams require CTR block mode, got '%s'", c.Block)}
+		return e
+	}
+	block, _ := aes.NewCipher(c.Key)
+	if e.stream, e.Error = cipher.NewCTRStream(block, c.IV); e.Error != nil {
+		e.Error = EncryptError{Err: e.Error}
+	}
+	return e
+}
+
+// Write encrypts p and writes it to the underlying writer.
+func (e *ResumableStreamEncrypter) Write(p []byte) (n int, err error) {
+	if e.Error != nil {
+		return 0, e.Error
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	encrypted := make([]byte, len(p))
+	e.stream.XORKeyStream(encrypted, p)
+	n, err = e.writer.Write(encrypted)
+	if n < len(p) {
+		// Rewind to what was actually written so a checkpoint stays consistent.
+		e.stream.Seek(e.stream.Offset() - uint64(len(p)-n))
+	}
+	return n, err
+}
+
+// Close closes the underlying writer if it implements io.Closer.
+func (e *ResumableStreamEncrypter) Close() error {
+	if e.Error != nil {
+		return e.Error
+	}
+	if closer, ok := e.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Offset returns the number of bytes encrypted and written so far.
+func (e *ResumableStreamEncrypter) Offset() int64 {
+	if e.stream == nil {
+		return 0
+	}
+	return int64(e.stream.Offset())
+}
+
+// MarshalBinary returns a checkpoint of the encrypter position. It holds no
+// key material, the key and IV must be supplied again when resuming.
+func (e *ResumableStreamEncrypter) MarshalBinary() ([]byte, error) {
+	if e.Error != nil {
+		return nil, e.Error
+	}
+	return e.stream.MarshalBinary()
+}
+
+// UnmarshalBinary restores a checkpoint taken with MarshalBinary by an
+// encrypter with the same key and IV. Subsequent writes continue at Offset,
+// so w should be positioned at that offset of the ciphertext.
+func (e *ResumableStreamEncrypter) UnmarshalBinary(data []byte) error {
+	if e.Error != nil {
+		return e.Error
+	}
+	return e.stream.UnmarshalBinary(data)
+}
@@ -182,3 +182,75 @@ func TestCTRStreamDecryption(t *testing.T) {
 		})
 	}
 }
+
+func TestResumableStreamEncrypter(t *testing.T) {
+	c := cipher.NewAesCipher(cipher.CTR)
+	c.SetKey([]byte("1234567890123456"))
+	c.SetIV([]byte("1234567890123456"))
+	plaintext := bytes.Repeat([]byte("resumable upload "), 500)
+	want, err := NewStdEncrypter(c).Encrypt(plaintext)
+	assert.NoError(t, err)
+
+	t.Run("checkpoint and resume", func(t *testing.T) {
+		var out bytes.Buffer
+		e := NewResumableStreamEncrypter(&out, c)
+		assert.NoError(t, e.Error)
+		e.Write(plaintext[:1000])
+		checkpoint, err := e.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), e.Offset())
+
+		// A new process restores the checkpoint and appends the remaining ciphertext.
+		resumed := NewResumableStreamEncrypter(&out, c)
+		assert.NoError(t, resumed.UnmarshalBinary(checkpoint))
+		n, err := resumed.Write(plaintext[resumed.Offset():])
+		assert.NoError(t, err)
+		assert.Equal(t, len(plaintext)-1000, n)
+		assert.NoError(t, resumed.Close())
+		assert.Equal(t, want, out.Bytes())
+
+		decrypted, err := NewStdDecrypter(c).Decrypt(out.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("short write", func(t *testing.T) {
+		e := NewResumableStreamEncrypter(mockShortWriter{n: 10}, c)
+		n, err := e.Write(plaintext[:100])
+		assert.Equal(t, 10, n)
+		assert.Equal(t, io.ErrShortWrite, err)
+		assert.Equal(t, int64(10), e.Offset())
+	})
+
+	t.Run("invalid cipher", func(t *testing.T) {
+		cbc := cipher.NewAesCipher(cipher.CBC)
+		cbc.SetKey([]byte("1234567890123456"))
+		e := NewResumableStreamEncrypter(&bytes.Buffer{}, cbc)
+		assert.IsType(t, EncryptError{}, e.Error)
+		_, err := e.Write([]byte("x"))
+		assert.Equal(t, e.Error, err)
+		_, err = e.MarshalBinary()
+		assert.Equal(t, e.Error, err)
+		assert.Equal(t, e.Error, e.UnmarshalBinary(nil))
+		assert.Equal(t, e.Error, e.Close())
+		assert.Zero(t, e.Offset())
+
+		short := cipher.NewAesCipher(cipher.CTR)
+		short.SetKey([]byte("short"))
+		assert.Equal(t, KeySizeError(5), NewResumableStreamEncrypter(&bytes.Buffer{}, short).Error)
+
+		noIV := cipher.NewAesCipher(cipher.CTR)
+		noIV.SetKey([]byte("1234567890123456"))
+		assert.IsType(t, EncryptError{}, NewResumableStreamEncrypter(&bytes.Buffer{}, noIV).Error)
+	})
+}
+
+// mockShortWriter accepts at most n bytes per write.
+type mockShortWriter struct{ n int }
+
+func (w mockShortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, io.ErrShortWrite
+	}
+	return len(p), nil
+}
@@ -199,55 +199,55 @@ func TestReadError(t *testing.T) {
 func TestBufferError(t *testing.T) {
 	t.Run("small buffer", func(t *testing.T) {
 		err := BufferError{bufferSize: 5, dataSize: 10}
-		expected := "crypto/aes: : buffer size 5 is too small for data size 10"
+		expected := "crypto/aes: buffer size 5 is too small for data size 10"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("zero buffer size", func(t *testing.T) {
 		err := BufferError{bufferSize: 0, dataSize: 10}
-		expected := "crypto/aes: : buffer size 0 is too small for data size 10"
+		expected := "crypto/aes: buffer size 0 is too small for data size 10"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("negative buffer size", func(t *testing.T) {
 		err := BufferError{bufferSize: -1, dataSize: 10}
-		expected := "crypto/aes: : buffer size -1 is too small for data size 10"
+		expected := "crypto/aes: buffer size -1 is too small for data size 10"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("zero data size", func(t *testing.T) {
 		err := BufferError{bufferSize: 5, dataSize: 0}
-		expected := "crypto/aes: : buffer size 5 is too small for data size 0"
+		expected := "crypto/aes: buffer size 5 is too small for data size 0"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("negative data size", func(t *testing.T) {
 		err := BufferError{bufferSize: 5, dataSize: -1}
-		expected := "crypto/aes: : buffer size 5 is too small for data size -1"
+		expected := "crypto/aes: buffer size 5 is too small for data size -1"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("large buffer size", func(t *testing.T) {
 		err := BufferError{bufferSize: 1000, dataSize: 2000}
-		expected := "crypto/aes: : buffer size 1000 is too small for data size 2000"
+		expected := "crypto/aes: buffer size 1000 is too small for data size 2000"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("equal sizes", func(t *testing.T) {
 		err := BufferError{bufferSize: 10, dataSize: 10}
-		expected := "crypto/aes: : buffer size 10 is too small for data size 10"
+		expected := "crypto/aes: buffer size 10 is too small for data size 10"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("both zero", func(t *testing.T) {
 		err := BufferError{bufferSize: 0, dataSize: 0}
-		expected := "crypto/aes: : buffer size 0 is too small for data size 0"
+		expected := "crypto/aes: buffer size 0 is too small for data size 0"
 		assert.Equal(t, expected, err.Error())
 	})
 
 	t.Run("both negative", func(t *testing.T) {
 		err := BufferError{bufferSize: -5, dataSize: -10}
-		expected := "crypto/aes: : buffer size -5 is too small for data size -10"
+		expected := "crypto/aes: buffer size -5 is too small for data size -10"
 		assert.Equal(t, expected, err.Error())
 	})
 }
@@ -344,6 +344,40 @@ func TestErrorIntegration(t *testing.T) {
 		assert.Equal(t, 0, n)
 		assert.IsType(t, ReadError{}, err)
 	})
+
+	t.Run("WriteError on second StreamEncrypter Write", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeErr := errors.New("write error")
+		c := cipher.NewAesCipher(cipher.CBC)
+		c.SetKey(key16Error)
+		c.SetIV(iv16Error)
+		c.SetPadding(cipher.PKCS7)
+
+		encrypter := NewStreamEncrypter(mock.NewErrorWriteOnCall(&buf, 2, writeErr), c)
+		n, err := encrypter.Write(testDataError)
+		assert.NoError(t, err)
+		assert.Equal(t, len(testDataError), n)
+		written := buf.Len()
+		n, err = encrypter.Write(testDataError)
+		assert.Equal(t, writeErr, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, written, buf.Len())
+	})
+
+	t.Run("ReadError mid stream in StreamDecrypter Read", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		c := cipher.NewAesCipher(cipher.CBC)
+		c.SetKey(key16Error)
+		c.SetIV(iv16Error)
+		c.SetPadding(cipher.PKCS7)
+		encrypted, err := NewStdEncrypter(c).Encrypt(testDataError)
+		assert.NoError(t, err)
+
+		decrypter := NewStreamDecrypter(mock.NewErrorReadAfterN(encrypted, len(encrypted)/2, readErr), c)
+		_, err = io.ReadAll(decrypter)
+		assert.IsType(t, ReadError{}, err)
+		assert.Equal(t, readErr, err.(ReadError).Err)
+	})
 }
 
 // TestErrorTypeAssertions tests type assertions for error types
@@ -1069,3 +1103,53 @@ func TestStreamDecrypter_Read_ErrorPaths(t *testing.T) {
 		}
 	})
 }
+
+// TestStreamCipherFaults tests the stream wrappers against a misbehaving block cipher
+func TestStreamCipherFaults(t *testing.T) {
+	newGCM := func() *cipher.AesCipher {
+		c := cipher.NewAesCipher(cipher.GCM)
+		c.SetKey(key16Error)
+		c.SetNonce(iv16Error[:12])
+		return c
+	}
+	encrypted, err := NewStdEncrypter(newGCM()).Encrypt(testDataError)
+	assert.NoError(t, err)
+
+	t.Run("corrupted block in StreamEncrypter Write", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newGCM())
+		streamEncrypter := encrypter.(*StreamEncrypter)
+		block := mock.NewBlock(streamEncrypter.block).CorruptAll()
+		streamEncrypter.block = block
+
+		n, err := encrypter.Write(testDataError)
+		assert.NoError(t, err)
+		assert.Equal(t, len(testDataError), n)
+		assert.Positive(t, block.Calls())
+		assert.Len(t, buf.Bytes(), len(encrypted))
+		assert.NotEqual(t, encrypted, buf.Bytes())
+
+		_, err = NewStdDecrypter(newGCM()).Decrypt(buf.Bytes())
+		assert.Error(t, err)
+	})
+
+	t.Run("corrupted block in StreamDecrypter Read", func(t *testing.T) {
+		decrypter := NewStreamDecrypter(bytes.NewReader(encrypted), newGCM())
+		streamDecrypter := decrypter.(*StreamDecrypter)
+		streamDecrypter.block = mock.NewBlock(streamDecrypter.block).CorruptAt(1)
+
+		n, err := decrypter.Read(make([]byte, 32))
+		assert.Equal(t, 0, n)
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("failing block in StreamEncrypter Write", func(t *testing.T) {
+		encrypter := NewStreamEncrypter(&bytes.Buffer{}, newGCM())
+		streamEncrypter := encrypter.(*StreamEncrypter)
+		streamEncrypter.block = mock.NewBlock(streamEncrypter.block).PanicAt(0, "block failure")
+
+		assert.PanicsWithValue(t, "block failure", func() {
+			_, _ = encrypter.Write(testDataError)
+		})
+	})
+}
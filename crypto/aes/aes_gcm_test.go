@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"testing/iotest"
 
 	"github.com/dromara/dongle/crypto/cipher"
 	"github.com/stretchr/testify/assert"
@@ -207,3 +208,188 @@ func TestGCMStreamDecryption(t *testing.T) {
 		})
 	}
 }
+
+func TestGCMSegmentedStream(t *testing.T) {
+	newCipher := func() *cipher.AesCipher {
+		c := cipher.NewAesCipher(cipher.GCM)
+		c.SetKey([]byte("1234567890123456"))
+		c.SetNonce([]byte("123456789012"))
+		c.SetAAD([]byte("file.bin"))
+		c.SetSegmentSize(1024)
+		return c
+	}
+	data := bytes.Repeat([]byte("segmented gcm stream "), 1000)
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		for chunk := data; len(chunk) > 0; chunk = chunk[min(777, len(chunk)):] {
+			_, err := encrypter.Write(chunk[:min(777, len(chunk))])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, encrypter.Close())
+		assert.Len(t, buf.Bytes(), len(data)+21*16)
+
+		decrypted, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(buf.Bytes()), newCipher()))
+		assert.NoError(t, err)
+		assert.Equal(t, data, decrypted)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		encrypter.Write(data)
+		encrypter.Close()
+
+		sealed := buf.Bytes()
+		sealed[5000] ^= 1
+		_, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed), newCipher()))
+		assert.IsType(t, DecryptError{}, err)
+
+		c := newCipher()
+		c.SetAAD([]byte("other.bin"))
+		sealed[5000] ^= 1
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed), c))
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("invalid nonce", func(t *testing.T) {
+		c := newCipher()
+		c.SetNonce(nil)
+		encrypter := NewStreamEncrypter(io.Discard, c)
+		_, err := encrypter.Write(data)
+		assert.IsType(t, cipher.EmptyNonceError{}, err)
+		_, err = NewStreamDecrypter(bytes.NewReader(nil), c).Read(make([]byte, 1))
+		assert.IsType(t, cipher.EmptyNonceError{}, err)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := io.ReadAll(NewStreamDecrypter(iotest.ErrReader(io.ErrClosedPipe), newCipher()))
+		assert.Equal(t, ReadError{Err: io.ErrClosedPipe}, err)
+	})
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher()).(*StreamEncrypter)
+		_, err := encrypter.Write(data[:5000])
+		assert.NoError(t, err)
+		state, err := encrypter.MarshalBinary()
+		assert.NoError(t, err)
+		offset := encrypter.Offset()
+
+		resumed := bytes.NewBuffer(bytes.Clone(buf.Bytes()[:offset]))
+		encrypter = NewStreamEncrypter(resumed, newCipher()).(*StreamEncrypter)
+		assert.NoError(t, encrypter.UnmarshalBinary(state))
+		_, err = encrypter.Write(data[5000:])
+		assert.NoError(t, err)
+		assert.NoError(t, encrypter.Close())
+
+		decrypted, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(resumed.Bytes()), newCipher()))
+		assert.NoError(t, err)
+		assert.Equal(t, data, decrypted)
+	})
+
+	t.Run("checkpoint without segments", func(t *testing.T) {
+		c := newCipher()
+		c.SetSegmentSize(0)
+		encrypter := NewStreamEncrypter(io.Discard, c).(*StreamEncrypter)
+		_, err := encrypter.MarshalBinary()
+		assert.IsType(t, EncryptError{}, err)
+		assert.IsType(t, EncryptError{}, encrypter.UnmarshalBinary(nil))
+		assert.Zero(t, encrypter.Offset())
+	})
+}
+
+func TestGCMReleasePolicy(t *testing.T) {
+	newCipher := func(policy cipher.ReleasePolicy) *cipher.AesCipher {
+		c := cipher.NewAesCipher(cipher.GCM)
+		c.SetKey([]byte("1234567890123456"))
+		c.SetNonce([]byte("123456789012"))
+		c.SetAAD([]byte("file.bin"))
+		c.SetReleasePolicy(policy)
+		return c
+	}
+	data := bytes.Repeat([]byte("release policy "), 100)
+	sealed, err := NewStdEncrypter(newCipher(cipher.ReleaseVerified)).Encrypt(data)
+	assert.NoError(t, err)
+
+	for _, policy := range []cipher.ReleasePolicy{cipher.ReleaseVerified, cipher.ReleaseUnverified} {
+		decrypted, err := io.ReadAll(iotest.HalfReader(NewStreamDecrypter(bytes.NewReader(sealed), newCipher(policy))))
+		assert.NoError(t, err)
+		assert.Equal(t, data, decrypted)
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		forged := append([]byte{}, sealed...)
+		forged[10] ^= 1
+
+		// The safe default returns nothing
+		d := NewStreamDecrypter(bytes.NewReader(forged), newCipher(cipher.ReleaseVerified))
+		got, err := io.ReadAll(d)
+		assert.IsType(t, DecryptError{}, err)
+		assert.Empty(t, got)
+
+		// The fast path returns the forged plaintext before failing
+		d = NewStreamDecrypter(bytes.NewReader(forged), newCipher(cipher.ReleaseUnverified))
+		got, err = io.ReadAll(d)
+		assert.Equal(t, DecryptError{Err: cipher.StreamAuthError{}}, err)
+		assert.Len(t, got, len(data))
+		assert.NotEqual(t, data, got)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		d := NewStreamDecrypter(iotest.ErrReader(io.ErrClosedPipe), newCipher(cipher.ReleaseUnverified))
+		_, err := io.ReadAll(d)
+		assert.Equal(t, ReadError{Err: io.ErrClosedPipe}, err)
+	})
+}
+
+func TestGCMKeyCommitment(t *testing.T) {
+	newCipher := func(key string) *cipher.AesCipher {
+		c := cipher.NewAesCipher(cipher.GCM)
+		c.SetKey([]byte(key))
+		c.SetNonce([]byte("123456789012"))
+		c.SetKeyCommitment(true)
+		return c
+	}
+	c := newCipher("1234567890123456")
+	src := []byte("hello world")
+
+	dst, err := NewStdEncrypter(c).Encrypt(src)
+	assert.NoError(t, err)
+	assert.Len(t, dst, cipher.KeyCommitmentSize+len(src)+16)
+	got, err := NewStdDecrypter(c).Decrypt(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, src, got)
+
+	// Without the commitment the ciphertext is a plain GCM ciphertext
+	plain := newCipher("1234567890123456")
+	plain.SetKeyCommitment(false)
+	got, err = NewStdDecrypter(plain).Decrypt(dst[cipher.KeyCommitmentSize:])
+	assert.NoError(t, err)
+	assert.Equal(t, src, got)
+
+	_, err = NewStdDecrypter(newCipher("6543210987654321")).Decrypt(dst)
+	assert.Equal(t, cipher.KeyCommitmentError{}, err)
+
+	// The commitment also covers message keys
+	mk := newCipher("1234567890123456")
+	mk.SetMessageKey(true)
+	dst, err = NewStdEncrypter(mk).Encrypt(src)
+	assert.NoError(t, err)
+	got, err = NewStdDecrypter(mk).Decrypt(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, src, got)
+
+	cbc := newCipher("1234567890123456")
+	cbc.Block = cipher.CBC
+	cbc.SetIV([]byte("1234567890123456"))
+	cbc.SetPadding(cipher.PKCS7)
+	_, err = NewStdEncrypter(cbc).Encrypt(src)
+	assert.IsType(t, cipher.UnsupportedKeyCommitmentError{}, err)
+
+	_, err = NewStreamEncrypter(io.Discard, c).Write(src)
+	assert.Equal(t, cipher.UnsupportedKeyCommitmentError{}, err)
+	_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(dst), c))
+	assert.Equal(t, cipher.UnsupportedKeyCommitmentError{}, err)
+}
@@ -0,0 +1,122 @@
+package crypto
+
+import "github.com/dromara/dongle/internal/utils"
+
+// BatchEncrypter encrypts a single item. The StdEncrypter types of the cipher
+// packages, such as aes.StdEncrypter, implement it.
+type BatchEncrypter interface {
+	Encrypt(src []byte) ([]byte, error)
+}
+
+// BatchDecrypter decrypts a single item. The StdDecrypter types of the cipher
+// packages, such as aes.StdDecrypter, implement it.
+type BatchDecrypter interface {
+	Decrypt(src []byte) ([]byte, error)
+}
+
+// BatchVerifier verifies a single signature. The StdVerifier types of the
+// signature packages, such as rsa.StdVerifier, implement it.
+type BatchVerifier interface {
+	Verify(src, sign []byte) (bool, error)
+}
+
+// VerifyItem is a message, its signature and the key to verify it with. The key
+// is any comparable value identifying the public key, typically the key pair
+// pointer such as *keypair.RsaKeyPair.
+type VerifyItem[K comparable] struct {
+	Key  K      // Key the signature is verified with
+	Data []byte // Signed message
+	Sign []byte // Raw signature
+}
+
+// VerifyBatch verifies every item with verifiers created by fn for the item
+// keys, for example
+//
+//	func(kp *keypair.RsaKeyPair) crypto.BatchVerifier { return rsa.NewStdVerifier(kp) }
+//
+// and returns one result per item: nil when its signature is valid, the error
+// of the verifier or a BatchSignatureError otherwise. Each worker creates one
+// verifier per key and reuses it for the items signed by that key, so keys are
+// parsed once per worker rather than once per item. With workers of 1 or less
+// the items are verified on the calling goroutine.
+//
+// Ed25519 signatures are faster to check with ed25519.VerifyBatch, which
+// verifies them together with a single multi-scalar multiplication.
+func VerifyBatch[K comparable](fn func(key K) BatchVerifier, items []VerifyItem[K], workers int) []error {
+	verifiers := make([]map[K]BatchVerifier, max(workers, 1))
+	errs := make([]error, len(items))
+	utils.Batch(len(items), workers, func(worker, i int) {
+		if verifiers[worker] == nil {
+			verifiers[worker] = make(map[K]BatchVerifier)
+		}
+		item := items[i]
+		v, ok := verifiers[worker][item.Key]
+		if !ok {
+			v = fn(item.Key)
+			verifiers[worker][item.Key] = v
+		}
+		valid, err := v.Verify(item.Data, item.Sign)
+		if valid && err == nil {
+			return
+		}
+		// Verifiers keep the error of a failed verification and fail every
+		// later call, so start the next item of this key with a fresh one.
+		delete(verifiers[worker], item.Key)
+		if err == nil {
+			err = BatchSignatureError{Index: i}
+		}
+		errs[i] = err
+	})
+	return errs
+}
+
+// EncryptBatch encrypts every item with encrypters created by fn, for example
+//
+//	func() crypto.BatchEncrypter { return aes.NewStdEncrypter(c) }
+//
+// Each worker creates one encrypter and reuses it for its items, so key
+// validation and setup are not repeated per item. With workers of 1 or less
+// the items are encrypted on the calling goroutine. The first error in item
+// order is returned.
+//
+// Every item is encrypted with the same cipher settings: modes with a fixed
+// IV or nonce, such as GCM, must not be used to encrypt more than one item,
+// and stateful ciphers such as RC4 continue their key stream across the items
+// of a worker.
+func EncryptBatch(fn func() BatchEncrypter, items [][]byte, workers int) ([][]byte, error) {
+	encrypters := make([]BatchEncrypter, max(workers, 1))
+	return batch(items, workers, func(worker int, item []byte) ([]byte, error) {
+		if encrypters[worker] == nil {
+			encrypters[worker] = fn()
+		}
+		return encrypters[worker].Encrypt(item)
+	})
+}
+
+// DecryptBatch decrypts every item with decrypters created by fn, reusing one
+// decrypter per worker like EncryptBatch.
+func DecryptBatch(fn func() BatchDecrypter, items [][]byte, workers int) ([][]byte, error) {
+	decrypters := make([]BatchDecrypter, max(workers, 1))
+	return batch(items, workers, func(worker int, item []byte) ([]byte, error) {
+		if decrypters[worker] == nil {
+			decrypters[worker] = fn()
+		}
+		return decrypters[worker].Decrypt(item)
+	})
+}
+
+// batch applies fn to every item and returns the results, or the error of the
+// first item that failed.
+func batch(items [][]byte, workers int, fn func(worker int, item []byte) ([]byte, error)) ([][]byte, error) {
+	out := make([][]byte, len(items))
+	errs := make([]error, len(items))
+	utils.Batch(len(items), workers, func(worker, i int) {
+		out[i], errs[i] = fn(worker, items[i])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package crypto
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/aes"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/ecdsa"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	c := newAesCipher()
+	items := make([][]byte, 64)
+	for i := range items {
+		items[i] = []byte(fmt.Sprintf("record-%d", i))
+	}
+
+	for _, workers := range []int{1, 4} {
+		encrypted, err := EncryptBatch(func() BatchEncrypter { return aes.NewStdEncrypter(c) }, items, workers)
+		require.NoError(t, err)
+		require.Len(t, encrypted, len(items))
+		for i, item := range items {
+			assert.Equal(t, NewEncrypter().FromBytes(item).ByAes(c).ToRawBytes(), encrypted[i])
+		}
+
+		decrypted, err := DecryptBatch(func() BatchDecrypter { return aes.NewStdDecrypter(c) }, encrypted, workers)
+		require.NoError(t, err)
+		assert.Equal(t, items, decrypted)
+	}
+
+	t.Run("reuses one encrypter per worker", func(t *testing.T) {
+		created := 0
+		_, err := EncryptBatch(func() BatchEncrypter {
+			created++
+			return aes.NewStdEncrypter(c)
+		}, items, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("first error", func(t *testing.T) {
+		bad := cipher.NewAesCipher(cipher.CBC)
+		bad.SetKey([]byte("short"))
+		_, err := EncryptBatch(func() BatchEncrypter { return aes.NewStdEncrypter(bad) }, items, 4)
+		assert.Equal(t, aes.KeySizeError(5), err)
+
+		_, err = DecryptBatch(func() BatchDecrypter { return aes.NewStdDecrypter(c) }, [][]byte{make([]byte, 16), []byte("odd")}, 1)
+		assert.Error(t, err)
+	})
+}
+
+// boolVerifier reports invalid signatures without an error.
+type boolVerifier struct{}
+
+func (boolVerifier) Verify(src, sign []byte) (bool, error) {
+	return string(src) == string(sign), nil
+}
+
+func TestVerifyBatch(t *testing.T) {
+	kps := make([]*keypair.EcdsaKeyPair, 3)
+	for i := range kps {
+		kps[i] = keypair.NewEcdsaKeyPair()
+		require.NoError(t, kps[i].GenKeyPair())
+	}
+	items := make([]VerifyItem[*keypair.EcdsaKeyPair], 40)
+	for i := range items {
+		data := []byte(fmt.Sprintf("webhook-%d", i))
+		kp := kps[i%len(kps)]
+		items[i] = VerifyItem[*keypair.EcdsaKeyPair]{Key: kp, Data: data, Sign: NewSigner().FromBytes(data).ByEcdsa(kp).ToRawBytes()}
+	}
+	newVerifier := func(kp *keypair.EcdsaKeyPair) BatchVerifier { return ecdsa.NewStdVerifier(kp) }
+
+	for _, workers := range []int{1, 4} {
+		for _, err := range VerifyBatch(newVerifier, items, workers) {
+			assert.NoError(t, err)
+		}
+	}
+
+	t.Run("per item results", func(t *testing.T) {
+		bad := append([]VerifyItem[*keypair.EcdsaKeyPair](nil), items...)
+		bad[3].Data = []byte("forged")
+		bad[10].Key = kps[0]
+		for _, workers := range []int{1, 4} {
+			errs := VerifyBatch(newVerifier, bad, workers)
+			for i, err := range errs {
+				if i == 3 || i == 10 {
+					assert.IsType(t, ecdsa.VerifyError{}, err, i)
+				} else {
+					// A failed item does not break the next items of its key
+					assert.NoError(t, err, i)
+				}
+			}
+		}
+	})
+
+	t.Run("reuses one verifier per key and worker", func(t *testing.T) {
+		created := 0
+		errs := VerifyBatch(func(kp *keypair.EcdsaKeyPair) BatchVerifier {
+			created++
+			return ecdsa.NewStdVerifier(kp)
+		}, items, 1)
+		assert.Len(t, errs, len(items))
+		assert.Equal(t, len(kps), created)
+	})
+
+	t.Run("invalid without error", func(t *testing.T) {
+		errs := VerifyBatch(func(string) BatchVerifier { return boolVerifier{} }, []VerifyItem[string]{
+			{Key: "k", Data: []byte("a"), Sign: []byte("a")},
+			{Key: "k", Data: []byte("a"), Sign: []byte("b")},
+		}, 1)
+		assert.NoError(t, errs[0])
+		assert.Equal(t, BatchSignatureError{Index: 1}, errs[1])
+		assert.Equal(t, "crypto: signature of batch item 1 is invalid", errs[1].Error())
+	})
+}
@@ -0,0 +1,303 @@
+// Package blindrsa implements RSA blind signatures as specified by RFC 9474.
+// A client blinds a message so that the signer learns nothing about it, the
+// signer signs the blinded message, and the client unblinds the result into a
+// standard RSASSA-PSS signature. This is the building block of privacy preserving
+// token issuance such as Privacy Pass.
+package blindrsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"io"
+	"math/big"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Variant is an RSABSSA variant, defined by its hash, PSS salt length and
+// whether messages are randomized before signing.
+type Variant struct {
+	Name       string
+	Hash       crypto.Hash
+	SaltLength int
+	Randomized bool
+}
+
+// The variants defined by RFC 9474 section 5.
+var (
+	SHA384PSSRandomized        = Variant{Name: "RSABSSA-SHA384-PSS-Randomized", Hash: crypto.SHA384, SaltLength: 48, Randomized: true}
+	SHA384PSSZeroRandomized    = Variant{Name: "RSABSSA-SHA384-PSSZERO-Randomized", Hash: crypto.SHA384, SaltLength: 0, Randomized: true}
+	SHA384PSSDeterministic     = Variant{Name: "RSABSSA-SHA384-PSS-Deterministic", Hash: crypto.SHA384, SaltLength: 48}
+	SHA384PSSZeroDeterministic = Variant{Name: "RSABSSA-SHA384-PSSZERO-Deterministic", Hash: crypto.SHA384, SaltLength: 0}
+)
+
+// PrefixSize is the size of the random prefix added by randomized variants.
+const PrefixSize = 32
+
+// Client blinds messages, finalizes blind signatures and verifies the results.
+type Client struct {
+	variant Variant
+	pub     *rsa.PublicKey
+	Error   error // Error field for storing key and variant errors
+}
+
+// NewClient returns a new Client for the public key of kp.
+func NewClient(kp *keypair.RsaKeyPair, v Variant) *Client {
+	c := &Client{variant: v}
+	if !v.Hash.Available() || v.SaltLength < 0 {
+		c.Error = UnsupportedVariantError{Name: v.Name}
+		return c
+	}
+	pub, err := kp.ParsePublicKey()
+	if err != nil {
+		c.Error = KeyError{Err: err}
+		return c
+	}
+	if emLen := (pub.N.BitLen() + 6) / 8; emLen < v.Hash.Size()+v.SaltLength+2 {
+		c.Error = KeySizeError{Size: pub.N.BitLen()}
+		return c
+	}
+	c.pub = pub
+	return c
+}
+
+// Prepare returns the message to blind. Randomized variants prepend PrefixSize
+// random bytes, deterministic variants return msg unchanged. The prepared message
+// is what Finalize and Verify operate on.
+func (c *Client) Prepare(msg []byte) ([]byte, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+	if !c.variant.Randomized {
+		return msg, nil
+	}
+	prepared := make([]byte, PrefixSize+len(msg))
+	if _, err := io.ReadFull(utils.Rand(), prepared[:PrefixSize]); err != nil {
+		return nil, err
+	}
+	copy(prepared[PrefixSize:], msg)
+	return prepared, nil
+}
+
+// Blind encodes and blinds a prepared message. The blinded message is sent to the
+// signer, the inverse must be kept secret until Finalize.
+func (c *Client) Blind(prepared []byte) (blinded, inv []byte, err error) {
+	if c.Error != nil {
+		return nil, nil, c.Error
+	}
+	encoded, err := emsaPSSEncode(c.variant.Hash, c.variant.SaltLength, prepared, c.pub.N.BitLen()-1)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := new(big.Int).SetBytes(encoded)
+	if new(big.Int).GCD(nil, nil, m, c.pub.N).Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, InvalidMessageError{}
+	}
+
+	var r, rInv *big.Int
+	for rInv == nil {
+		if r, err = rand.Int(utils.Rand(), c.pub.N); err != nil {
+			return nil, nil, err
+		}
+		if r.Sign() > 0 {
+			rInv = new(big.Int).ModInverse(r, c.pub.N)
+		}
+	}
+	x := r.Exp(r, big.NewInt(int64(c.pub.E)), c.pub.N)
+	z := x.Mul(m, x).Mod(x, c.pub.N)
+	k := c.pub.Size()
+	return z.FillBytes(make([]byte, k)), rInv.FillBytes(make([]byte, k)), nil
+}
+
+// Finalize unblinds the signer's blind signature with the inverse returned by
+// Blind and verifies the resulting signature over the prepared message.
+func (c *Client) Finalize(prepared, blindSig, inv []byte) ([]byte, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+	k := c.pub.Size()
+	z, ok := c.parse(blindSig)
+	if !ok {
+		return nil, InvalidInputError{Name: "blind signature"}
+	}
+	rInv, ok := c.parse(inv)
+	if !ok {
+		return nil, InvalidInputError{Name: "inverse"}
+	}
+	s := z.Mul(z, rInv).Mod(z, c.pub.N)
+	sig := s.FillBytes(make([]byte, k))
+	if err := c.Verify(prepared, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Verify verifies an RSASSA-PSS signature over a prepared message with the
+// variant salt length.
+func (c *Client) Verify(prepared, sig []byte) error {
+	if c.Error != nil {
+		return c.Error
+	}
+	s, ok := c.parse(sig)
+	if !ok {
+		return SignatureVerificationError{}
+	}
+	m := s.Exp(s, big.NewInt(int64(c.pub.E)), c.pub.N)
+	emBits := c.pub.N.BitLen() - 1
+	if m.BitLen() > emBits {
+		return SignatureVerificationError{}
+	}
+	em := m.FillBytes(make([]byte, (emBits+7)/8))
+	if !emsaPSSVerify(c.variant.Hash, c.variant.SaltLength, prepared, em, emBits) {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
+
+// parse decodes a modulus sized big-endian integer below the modulus.
+func (c *Client) parse(b []byte) (*big.Int, bool) {
+	if len(b) != c.pub.Size() {
+		return nil, false
+	}
+	v := new(big.Int).SetBytes(b)
+	return v, v.Cmp(c.pub.N) < 0
+}
+
+// Signer produces blind signatures with an RSA private key.
+type Signer struct {
+	key   *rsa.PrivateKey
+	Error error // Error field for storing key errors
+}
+
+// NewSigner returns a new Signer for the private key of kp.
+func NewSigner(kp *keypair.RsaKeyPair) *Signer {
+	s := &Signer{}
+	key, err := kp.ParsePrivateKey()
+	if err != nil {
+		s.Error = KeyError{Err: err}
+		return s
+	}
+	key.Precompute()
+	s.key = key
+	return s
+}
+
+// BlindSign signs a blinded message. The result is checked against the public key
+// before it is returned, so a faulty computation never leaks the private key.
+func (s *Signer) BlindSign(blinded []byte) ([]byte, error) {
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	k := s.key.Size()
+	if len(blinded) != k {
+		return nil, InvalidInputError{Name: "blinded message"}
+	}
+	m := new(big.Int).SetBytes(blinded)
+	if m.Cmp(s.key.N) >= 0 {
+		return nil, InvalidInputError{Name: "blinded message"}
+	}
+	sig := s.sign(m)
+	if new(big.Int).Exp(sig, big.NewInt(int64(s.key.E)), s.key.N).Cmp(m) != 0 {
+		return nil, SignError{}
+	}
+	return sig.FillBytes(make([]byte, k)), nil
+}
+
+// sign computes m^d mod n, using the CRT values of two prime keys.
+func (s *Signer) sign(m *big.Int) *big.Int {
+	pre := s.key.Precomputed
+	if len(s.key.Primes) != 2 || pre.Dp == nil || pre.Dq == nil || pre.Qinv == nil {
+		return new(big.Int).Exp(m, s.key.D, s.key.N)
+	}
+	p, q := s.key.Primes[0], s.key.Primes[1]
+	m1 := new(big.Int).Exp(m, pre.Dp, p)
+	m2 := new(big.Int).Exp(m, pre.Dq, q)
+	h := m1.Sub(m1, m2)
+	h.Mul(h, pre.Qinv).Mod(h, p)
+	return h.Mul(h, q).Add(h, m2)
+}
+
+// emsaPSSEncode implements EMSA-PSS-ENCODE from RFC 8017 section 9.1.1 with MGF1.
+func emsaPSSEncode(hash crypto.Hash, sLen int, msg []byte, emBits int) ([]byte, error) {
+	hLen := hash.Size()
+	emLen := (emBits + 7) / 8
+	if emLen < hLen+sLen+2 {
+		return nil, KeySizeError{Size: emBits + 1}
+	}
+	salt := make([]byte, sLen)
+	if _, err := io.ReadFull(utils.Rand(), salt); err != nil {
+		return nil, err
+	}
+	h := psHash(hash, msg, salt)
+
+	db := make([]byte, emLen-hLen-1)
+	db[len(db)-sLen-1] = 0x01
+	copy(db[len(db)-sLen:], salt)
+	mgf1XOR(db, hash, h)
+	db[0] &= 0xff >> (8*emLen - emBits)
+
+	em := make([]byte, 0, emLen)
+	em = append(em, db...)
+	em = append(em, h...)
+	return append(em, 0xbc), nil
+}
+
+// emsaPSSVerify implements EMSA-PSS-VERIFY from RFC 8017 section 9.1.2 with a fixed salt length.
+func emsaPSSVerify(hash crypto.Hash, sLen int, msg, em []byte, emBits int) bool {
+	hLen := hash.Size()
+	emLen := len(em)
+	if emLen < hLen+sLen+2 || em[emLen-1] != 0xbc {
+		return false
+	}
+	db := append([]byte{}, em[:emLen-hLen-1]...)
+	h := em[emLen-hLen-1 : emLen-1]
+	mask := byte(0xff >> (8*emLen - emBits))
+	if db[0]&^mask != 0 {
+		return false
+	}
+	mgf1XOR(db, hash, h)
+	db[0] &= mask
+	psLen := emLen - hLen - sLen - 2
+	for _, b := range db[:psLen] {
+		if b != 0 {
+			return false
+		}
+	}
+	if db[psLen] != 0x01 {
+		return false
+	}
+	return utils.ConstantTimeEqual(psHash(hash, msg, db[len(db)-sLen:]), h)
+}
+
+// psHash computes H(0x00 x 8 || H(msg) || salt).
+func psHash(hash crypto.Hash, msg, salt []byte) []byte {
+	h := hash.New()
+	h.Write(msg)
+	mHash := h.Sum(nil)
+	h.Reset()
+	h.Write(make([]byte, 8))
+	h.Write(mHash)
+	h.Write(salt)
+	return h.Sum(nil)
+}
+
+// mgf1XOR XORs out with the MGF1 mask generated from seed.
+func mgf1XOR(out []byte, hash crypto.Hash, seed []byte) {
+	h := hash.New()
+	var counter [4]byte
+	var digest []byte
+	for done := 0; done < len(out); {
+		h.Reset()
+		h.Write(seed)
+		h.Write(counter[:])
+		digest = h.Sum(digest[:0])
+		for i := 0; i < len(digest) && done < len(out); i++ {
+			out[done] ^= digest[i]
+			done++
+		}
+		binary.BigEndian.PutUint32(counter[:], binary.BigEndian.Uint32(counter[:])+1)
+	}
+}
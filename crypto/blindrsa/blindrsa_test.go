@@ -0,0 +1,308 @@
+package blindrsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var variants = []Variant{
+	SHA384PSSRandomized,
+	SHA384PSSZeroRandomized,
+	SHA384PSSDeterministic,
+	SHA384PSSZeroDeterministic,
+}
+
+func newKeyPair(t *testing.T, bits int) *keypair.RsaKeyPair {
+	kp := keypair.NewRsaKeyPair()
+	require.NoError(t, kp.GenKeyPair(bits))
+	return kp
+}
+
+func TestBlindSign(t *testing.T) {
+	kp := newKeyPair(t, 2048)
+	signer := NewSigner(kp)
+	require.NoError(t, signer.Error)
+	msg := []byte("privacy pass token")
+
+	for _, v := range variants {
+		t.Run(v.Name, func(t *testing.T) {
+			client := NewClient(kp, v)
+			require.NoError(t, client.Error)
+
+			prepared, err := client.Prepare(msg)
+			require.NoError(t, err)
+			if v.Randomized {
+				assert.Len(t, prepared, PrefixSize+len(msg))
+				assert.Equal(t, msg, prepared[PrefixSize:])
+			} else {
+				assert.Equal(t, msg, prepared)
+			}
+
+			blinded, inv, err := client.Blind(prepared)
+			require.NoError(t, err)
+			assert.Len(t, blinded, 256)
+			assert.Len(t, inv, 256)
+
+			blindSig, err := signer.BlindSign(blinded)
+			require.NoError(t, err)
+			sig, err := client.Finalize(prepared, blindSig, inv)
+			require.NoError(t, err)
+			assert.NoError(t, client.Verify(prepared, sig))
+
+			// The result is a standard RSASSA-PSS signature.
+			pub, err := kp.ParsePublicKey()
+			require.NoError(t, err)
+			digest := sha512.Sum384(prepared)
+			saltLength := v.SaltLength
+			if saltLength == 0 {
+				saltLength = rsa.PSSSaltLengthAuto
+			}
+			assert.NoError(t, rsa.VerifyPSS(pub, crypto.SHA384, digest[:], sig, &rsa.PSSOptions{SaltLength: saltLength}))
+
+			assert.Equal(t, SignatureVerificationError{}, client.Verify(append(prepared, 0), sig))
+		})
+	}
+
+	t.Run("unlinkable", func(t *testing.T) {
+		client := NewClient(kp, SHA384PSSZeroDeterministic)
+		a, _, err := client.Blind(msg)
+		require.NoError(t, err)
+		b, _, err := client.Blind(msg)
+		require.NoError(t, err)
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("salt length is enforced", func(t *testing.T) {
+		pss := NewClient(kp, SHA384PSSDeterministic)
+		zero := NewClient(kp, SHA384PSSZeroDeterministic)
+		blinded, inv, err := pss.Blind(msg)
+		require.NoError(t, err)
+		blindSig, err := signer.BlindSign(blinded)
+		require.NoError(t, err)
+		sig, err := pss.Finalize(msg, blindSig, inv)
+		require.NoError(t, err)
+		assert.Equal(t, SignatureVerificationError{}, zero.Verify(msg, sig))
+	})
+
+	t.Run("odd modulus size", func(t *testing.T) {
+		kp := newKeyPair(t, 1025)
+		client := NewClient(kp, SHA384PSSRandomized)
+		require.NoError(t, client.Error)
+		prepared, err := client.Prepare(msg)
+		require.NoError(t, err)
+		blinded, inv, err := client.Blind(prepared)
+		require.NoError(t, err)
+		blindSig, err := NewSigner(kp).BlindSign(blinded)
+		require.NoError(t, err)
+		_, err = client.Finalize(prepared, blindSig, inv)
+		assert.NoError(t, err)
+	})
+}
+
+// rfc9474Vector is a test vector of RFC 9474 Appendix A. Integers are 0x
+// prefixed hex, byte strings plain hex.
+type rfc9474Vector struct {
+	Name       string `json:"name"`
+	P          string `json:"p"`
+	Q          string `json:"q"`
+	N          string `json:"n"`
+	E          string `json:"e"`
+	D          string `json:"d"`
+	Msg        string `json:"msg"`
+	MsgPrefix  string `json:"msg_prefix"`
+	InputMsg   string `json:"input_msg"`
+	Salt       string `json:"salt"`
+	Inv        string `json:"inv"`
+	BlindedMsg string `json:"blinded_msg"`
+	BlindSig   string `json:"blind_sig"`
+	Sig        string `json:"sig"`
+}
+
+// vectorKeyPair returns the key pair of a vector.
+func vectorKeyPair(t *testing.T, v rfc9474Vector) *keypair.RsaKeyPair {
+	key := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: vectorInt(t, v.N), E: int(vectorInt(t, v.E).Int64())},
+		D:         vectorInt(t, v.D),
+		Primes:    []*big.Int{vectorInt(t, v.P), vectorInt(t, v.Q)},
+	}
+	require.NoError(t, key.Validate())
+	key.Precompute()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	kp := keypair.NewRsaKeyPair()
+	kp.PrivateKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	kp.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+	return kp
+}
+
+func vectorInt(t *testing.T, s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 0)
+	require.True(t, ok, s)
+	return v
+}
+
+func vectorBytes(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestRFC9474Vectors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "rfc9474.json"))
+	require.NoError(t, err)
+	var vectors []rfc9474Vector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.Len(t, vectors, len(variants))
+
+	for i, v := range vectors {
+		variant := variants[i]
+		require.Equal(t, variant.Name, v.Name)
+		t.Run(v.Name, func(t *testing.T) {
+			kp := vectorKeyPair(t, v)
+			client := NewClient(kp, variant)
+			require.NoError(t, client.Error)
+			signer := NewSigner(kp)
+			require.NoError(t, signer.Error)
+
+			// Blind draws r such that inv = r^-1 mod n, after the prefix and salt
+			n := vectorInt(t, v.N)
+			r := new(big.Int).ModInverse(vectorInt(t, v.Inv), n)
+			random := bytes.Join([][]byte{vectorBytes(t, v.MsgPrefix), vectorBytes(t, v.Salt), r.FillBytes(make([]byte, (n.BitLen()+7)/8))}, nil)
+			defer utils.SetRand(bytes.NewReader(random))()
+
+			prepared, err := client.Prepare(vectorBytes(t, v.Msg))
+			require.NoError(t, err)
+			assert.Equal(t, v.InputMsg, hex.EncodeToString(prepared))
+
+			blinded, inv, err := client.Blind(prepared)
+			require.NoError(t, err)
+			assert.Equal(t, v.BlindedMsg, hex.EncodeToString(blinded))
+			assert.Equal(t, vectorInt(t, v.Inv), new(big.Int).SetBytes(inv))
+
+			blindSig, err := signer.BlindSign(blinded)
+			require.NoError(t, err)
+			assert.Equal(t, v.BlindSig, hex.EncodeToString(blindSig))
+
+			sig, err := client.Finalize(prepared, blindSig, inv)
+			require.NoError(t, err)
+			assert.Equal(t, v.Sig, hex.EncodeToString(sig))
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	kp := newKeyPair(t, 1024)
+	client := NewClient(kp, SHA384PSSDeterministic)
+	require.NoError(t, client.Error)
+	signer := NewSigner(kp)
+	msg := []byte("msg")
+
+	t.Run("invalid key", func(t *testing.T) {
+		c := NewClient(keypair.NewRsaKeyPair(), SHA384PSSRandomized)
+		assert.IsType(t, KeyError{}, c.Error)
+		assert.Contains(t, c.Error.Error(), "crypto/blindrsa: invalid key: ")
+		_, err := c.Prepare(msg)
+		assert.Equal(t, c.Error, err)
+		_, _, err = c.Blind(msg)
+		assert.Equal(t, c.Error, err)
+		_, err = c.Finalize(msg, nil, nil)
+		assert.Equal(t, c.Error, err)
+		assert.Equal(t, c.Error, c.Verify(msg, nil))
+
+		s := NewSigner(keypair.NewRsaKeyPair())
+		assert.IsType(t, KeyError{}, s.Error)
+		_, err = s.BlindSign(nil)
+		assert.Equal(t, s.Error, err)
+	})
+
+	t.Run("unsupported variant", func(t *testing.T) {
+		c := NewClient(kp, Variant{Name: "custom", Hash: crypto.Hash(99)})
+		assert.Equal(t, UnsupportedVariantError{Name: "custom"}, c.Error)
+		assert.Equal(t, "crypto/blindrsa: unsupported variant 'custom'", c.Error.Error())
+		c = NewClient(kp, Variant{Name: "negative", Hash: crypto.SHA384, SaltLength: -1})
+		assert.Equal(t, UnsupportedVariantError{Name: "negative"}, c.Error)
+	})
+
+	t.Run("key size", func(t *testing.T) {
+		c := NewClient(newKeyPair(t, 512), SHA384PSSRandomized)
+		assert.Equal(t, KeySizeError{Size: 512}, c.Error)
+		assert.Equal(t, "crypto/blindrsa: modulus of 512 bits is too small for the variant", c.Error.Error())
+		_, err := emsaPSSEncode(crypto.SHA384, 48, msg, 511)
+		assert.Equal(t, KeySizeError{Size: 512}, err)
+	})
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		_, err := signer.BlindSign(msg)
+		assert.Equal(t, InvalidInputError{Name: "blinded message"}, err)
+		assert.Equal(t, "crypto/blindrsa: invalid blinded message", err.Error())
+		n := client.pub.N.FillBytes(make([]byte, 128))
+		_, err = signer.BlindSign(n)
+		assert.Equal(t, InvalidInputError{Name: "blinded message"}, err)
+
+		blinded, inv, err := client.Blind(msg)
+		require.NoError(t, err)
+		blindSig, err := signer.BlindSign(blinded)
+		require.NoError(t, err)
+		_, err = client.Finalize(msg, blindSig[1:], inv)
+		assert.Equal(t, InvalidInputError{Name: "blind signature"}, err)
+		_, err = client.Finalize(msg, blindSig, n)
+		assert.Equal(t, InvalidInputError{Name: "inverse"}, err)
+		_, err = client.Finalize([]byte("other"), blindSig, inv)
+		assert.Equal(t, SignatureVerificationError{}, err)
+		assert.Equal(t, "crypto/blindrsa: signature verification failed", err.Error())
+		assert.Equal(t, SignatureVerificationError{}, client.Verify(msg, n))
+	})
+
+	t.Run("invalid signatures", func(t *testing.T) {
+		em := make([]byte, 128)
+		assert.False(t, emsaPSSVerify(crypto.SHA384, 48, msg, em[:10], 1023))
+		assert.False(t, emsaPSSVerify(crypto.SHA384, 48, msg, em, 1023))
+		em[127] = 0xbc
+		em[0] = 0x80
+		assert.False(t, emsaPSSVerify(crypto.SHA384, 48, msg, em, 1023))
+
+		// A signature whose recovered message exceeds emBits is rejected.
+		large := make([]byte, 128)
+		large[0] = 0x7f
+		assert.Equal(t, SignatureVerificationError{}, client.Verify(msg, large))
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		assert.Equal(t, "crypto/blindrsa: encoded message is not invertible", InvalidMessageError{}.Error())
+		assert.Equal(t, "crypto/blindrsa: blind signature consistency check failed", SignError{}.Error())
+	})
+
+	t.Run("sign without crt", func(t *testing.T) {
+		s := NewSigner(kp)
+		s.key.Precomputed.Dp = nil
+		blinded, _, err := client.Blind(msg)
+		require.NoError(t, err)
+		a, err := s.BlindSign(blinded)
+		require.NoError(t, err)
+		b, err := signer.BlindSign(blinded)
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+
+		s.key.D = new(big.Int).SetInt64(1)
+		_, err = s.BlindSign(blinded)
+		assert.Equal(t, SignError{}, err)
+	})
+}
@@ -0,0 +1,142 @@
+package blindrsa
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// KeyError represents an error when the RSA key pair cannot be parsed.
+type KeyError struct {
+	Err error // Underlying error from key parsing
+}
+
+// Error returns a formatted error message describing the key failure.
+func (e KeyError) Error() string {
+	return fmt.Sprintf("crypto/blindrsa: invalid key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-BLINDRSA-001.
+func (e KeyError) Code() string {
+	return "DGL-BLINDRSA-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "key", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedVariantError represents an error when a variant has an unavailable hash or a negative salt length.
+type UnsupportedVariantError struct {
+	Name string // The variant name
+}
+
+// Error returns a formatted error message describing the unsupported variant.
+func (e UnsupportedVariantError) Error() string {
+	return fmt.Sprintf("crypto/blindrsa: unsupported variant '%s'", e.Name)
+}
+
+// Code returns the stable error code DGL-BLINDRSA-002.
+func (e UnsupportedVariantError) Code() string {
+	return "DGL-BLINDRSA-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedVariantError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "", "name", e.Name)
+}
+
+// KeySizeError represents an error when the modulus is too small for the variant hash and salt.
+type KeySizeError struct {
+	Size int // Modulus size in bits
+}
+
+// Error returns a formatted error message describing the small modulus.
+func (e KeySizeError) Error() string {
+	return fmt.Sprintf("crypto/blindrsa: modulus of %d bits is too small for the variant", e.Size)
+}
+
+// Code returns the stable error code DGL-BLINDRSA-003.
+func (e KeySizeError) Code() string {
+	return "DGL-BLINDRSA-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "key", errcode.FieldKeySize, e.Size)
+}
+
+// InvalidMessageError represents an error when the encoded message is not invertible modulo n.
+type InvalidMessageError struct{}
+
+// Error returns a formatted error message describing the invalid message.
+func (e InvalidMessageError) Error() string {
+	return "crypto/blindrsa: encoded message is not invertible"
+}
+
+// Code returns the stable error code DGL-BLINDRSA-004.
+func (e InvalidMessageError) Code() string {
+	return "DGL-BLINDRSA-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidMessageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "")
+}
+
+// InvalidInputError represents an error when a blinded message, blind signature or
+// inverse has the wrong size or is not below the modulus.
+type InvalidInputError struct {
+	Name string // Name of the invalid input
+}
+
+// Error returns a formatted error message describing the invalid input.
+func (e InvalidInputError) Error() string {
+	return fmt.Sprintf("crypto/blindrsa: invalid %s", e.Name)
+}
+
+// Code returns the stable error code DGL-BLINDRSA-005.
+func (e InvalidInputError) Code() string {
+	return "DGL-BLINDRSA-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidInputError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "", "name", e.Name)
+}
+
+// SignError represents an error when the blind signature fails its consistency check.
+type SignError struct{}
+
+// Error returns a formatted error message describing the signing failure.
+func (e SignError) Error() string {
+	return "crypto/blindrsa: blind signature consistency check failed"
+}
+
+// Code returns the stable error code DGL-BLINDRSA-006.
+func (e SignError) Code() string {
+	return "DGL-BLINDRSA-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "sign")
+}
+
+// SignatureVerificationError represents an error when a signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/blindrsa: signature verification failed"
+}
+
+// Code returns the stable error code DGL-BLINDRSA-007.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-BLINDRSA-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blindrsa", "RSABSSA", "verify")
+}
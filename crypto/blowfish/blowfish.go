@@ -38,6 +38,9 @@ func NewStdEncrypter(c *cipher.BlowfishCipher) *StdEncrypter {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckGCMOptions(); e.Error != nil {
+		return e
+	}
 
 	return e
 }
@@ -58,6 +61,11 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, newBlock)
+	}
+
 	// Create Blowfish cipher block using the provided key
 	block, err := blowfish.NewCipher(e.cipher.Key)
 	if err != nil {
@@ -94,6 +102,9 @@ func NewStdDecrypter(c *cipher.BlowfishCipher) *StdDecrypter {
 		d.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return d
 	}
+	if d.Error = c.CheckGCMOptions(); d.Error != nil {
+		return d
+	}
 
 	return d
 }
@@ -114,6 +125,11 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, newBlock)
+	}
+
 	// Create Blowfish cipher block using the provided key
 	block, err := blowfish.NewCipher(d.cipher.Key)
 	if err != nil {
@@ -157,6 +173,10 @@ func NewStreamEncrypter(w io.Writer, c *cipher.BlowfishCipher) io.WriteCloser {
 		return e
 	}
 
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
+
 	e.block, e.Error = blowfish.NewCipher(c.Key)
 	return e
 }
@@ -254,6 +274,10 @@ func NewStreamDecrypter(r io.Reader, c *cipher.BlowfishCipher) io.Reader {
 		return d
 	}
 
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+
 	d.block, d.Error = blowfish.NewCipher(c.Key)
 	return d
 }
@@ -311,3 +335,8 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 
 	return copied, nil
 }
+
+// newBlock creates a Blowfish cipher block from key.
+func newBlock(key []byte) (stdCipher.Block, error) {
+	return blowfish.NewCipher(key)
+}
@@ -48,7 +48,7 @@ func TestReadError(t *testing.T) {
 func TestBufferError(t *testing.T) {
 	t.Run("error message format", func(t *testing.T) {
 		err := BufferError{bufferSize: 10, dataSize: 20}
-		assert.Equal(t, "crypto/blowfish: : buffer size 10 is too small for data size 20", err.Error())
+		assert.Equal(t, "crypto/blowfish: buffer size 10 is too small for data size 20", err.Error())
 	})
 }
 
@@ -2,6 +2,8 @@ package blowfish
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the Blowfish key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/blowfish: invalid key size %d, must be between 1 and 56 bytes", k)
 }
 
+// Code returns the stable error code DGL-BLOWFISH-001.
+func (k KeySizeError) Code() string {
+	return "DGL-BLOWFISH-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blowfish", "Blowfish", "key", errcode.FieldKeySize, int(k))
+}
+
 // EncryptError represents an error when Blowfish encryption operation fails.
 // This error occurs when the encryption process fails due to various reasons.
 type EncryptError struct {
@@ -25,6 +37,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/blowfish: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-BLOWFISH-002.
+func (e EncryptError) Code() string {
+	return "DGL-BLOWFISH-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blowfish", "Blowfish", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when Blowfish decryption operation fails.
 // This error occurs when the decryption process fails due to various reasons.
 // The error includes the underlying error for detailed debugging.
@@ -38,6 +60,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/blowfish: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-BLOWFISH-003.
+func (e DecryptError) Code() string {
+	return "DGL-BLOWFISH-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blowfish", "Blowfish", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -51,6 +83,16 @@ func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/blowfish: failed to read encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-BLOWFISH-004.
+func (e ReadError) Code() string {
+	return "DGL-BLOWFISH-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blowfish", "Blowfish", "read", errcode.FieldCause, e.Err)
+}
+
 // BufferError represents an error when the buffer size is too small.
 // This error occurs when the provided buffer is too small to hold the decrypted data.
 // The error includes both buffer size and data size for detailed debugging.
@@ -62,7 +104,17 @@ type BufferError struct {
 // Error returns a formatted error message describing the buffer size issue.
 // The message includes both buffer size and data size for debugging.
 func (e BufferError) Error() string {
-	return fmt.Sprintf("crypto/blowfish: : buffer size %d is too small for data size %d", e.bufferSize, e.dataSize)
+	return fmt.Sprintf("crypto/blowfish: buffer size %d is too small for data size %d", e.bufferSize, e.dataSize)
+}
+
+// Code returns the stable error code DGL-BLOWFISH-005.
+func (e BufferError) Code() string {
+	return "DGL-BLOWFISH-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BufferError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blowfish", "Blowfish", "", "buffer_size", e.bufferSize, "data_size", e.dataSize)
 }
 
 // UnsupportedBlockModeError represents an error when an unsupported block mode is used.
@@ -75,3 +127,13 @@ type UnsupportedBlockModeError struct {
 func (e UnsupportedBlockModeError) Error() string {
 	return fmt.Sprintf("crypto/blowfish: unsupported block mode '%s', blowfish only supports CBC, CTR, ECB, CFB, and OFB modes", e.Mode)
 }
+
+// Code returns the stable error code DGL-BLOWFISH-006.
+func (e UnsupportedBlockModeError) Code() string {
+	return "DGL-BLOWFISH-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedBlockModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/blowfish", "Blowfish", "", "mode", e.Mode)
+}
@@ -0,0 +1,437 @@
+// Package bls implements BLS signatures over the BLS12-381 pairing curve following
+// draft-irtf-cfrg-bls-signature-05. Both the minimal-pubkey-size (min-pk) and the
+// minimal-signature-size (min-sig) variants are provided, each with the basic and the
+// proof of possession schemes, together with signature and public key aggregation for
+// multi-party attestations.
+package bls
+
+import (
+	"crypto/sha256"
+	"io"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Variant selects which pairing group holds the public keys.
+type Variant uint8
+
+const (
+	// MinPk places public keys in G1 (48 bytes) and signatures in G2 (96 bytes).
+	MinPk Variant = iota
+	// MinSig places public keys in G2 (96 bytes) and signatures in G1 (48 bytes).
+	MinSig
+)
+
+// Scheme selects how rogue key attacks are prevented when aggregating.
+type Scheme uint8
+
+const (
+	// Basic requires the messages of an aggregate signature to be distinct.
+	Basic Scheme = iota
+	// ProofOfPossession requires every public key to come with a verified proof,
+	// which allows aggregating public keys over a common message.
+	ProofOfPossession
+)
+
+const (
+	PrivateKeySize = bls12381.ScalarSize // Size of a serialized private key
+	MinIKMSize     = 32                  // Minimum size of the key generation input keying material
+)
+
+// Suite is a BLS ciphersuite, the combination of a variant and a scheme.
+type Suite struct {
+	Variant Variant
+	Scheme  Scheme
+}
+
+// The ciphersuites defined by the draft.
+var (
+	MinPkBasic  = Suite{Variant: MinPk, Scheme: Basic}
+	MinPkPop    = Suite{Variant: MinPk, Scheme: ProofOfPossession}
+	MinSigBasic = Suite{Variant: MinSig, Scheme: Basic}
+	MinSigPop   = Suite{Variant: MinSig, Scheme: ProofOfPossession}
+)
+
+// KeyPair holds a serialized private key and its compressed public key.
+type KeyPair struct {
+	PrivateKey []byte
+	PublicKey  []byte
+}
+
+// ID returns the ciphersuite identifier used as the signing domain separation tag.
+func (s Suite) ID() string {
+	id := "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_"
+	if s.Variant == MinSig {
+		id = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	}
+	if s.Scheme == ProofOfPossession {
+		return id + "POP_"
+	}
+	return id + "NUL_"
+}
+
+// popID returns the domain separation tag used for proofs of possession.
+func (s Suite) popID() string {
+	if s.Variant == MinSig {
+		return "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+	}
+	return "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+}
+
+// PublicKeySize returns the size of a compressed public key.
+func (s Suite) PublicKeySize() int {
+	if s.Variant == MinSig {
+		return bls12381.G2SizeCompressed
+	}
+	return bls12381.G1SizeCompressed
+}
+
+// SignatureSize returns the size of a compressed signature.
+func (s Suite) SignatureSize() int {
+	if s.Variant == MinSig {
+		return bls12381.G1SizeCompressed
+	}
+	return bls12381.G2SizeCompressed
+}
+
+// GenKeyPair generates a key pair from fresh random input keying material.
+func (s Suite) GenKeyPair() (*KeyPair, error) {
+	ikm := make([]byte, MinIKMSize)
+	if _, err := io.ReadFull(utils.Rand(), ikm); err != nil {
+		return nil, err
+	}
+	return s.DeriveKeyPair(ikm)
+}
+
+// DeriveKeyPair deterministically derives a key pair from ikm with the KeyGen
+// procedure of the draft. ikm must be at least MinIKMSize bytes and kept secret.
+func (s Suite) DeriveKeyPair(ikm []byte) (*KeyPair, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if len(ikm) < MinIKMSize {
+		return nil, InvalidIKMError{Size: len(ikm)}
+	}
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	input := append(append([]byte{}, ikm...), 0)
+	okm := make([]byte, 48)
+	sk := new(bls12381.Scalar)
+	for sk.IsZero() == 1 {
+		digest := sha256.Sum256(salt)
+		salt = digest[:]
+		r := hkdf.New(sha256.New, input, salt, []byte{0, byte(len(okm))})
+		if _, err := io.ReadFull(r, okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm)
+	}
+	priv, _ := sk.MarshalBinary()
+	return &KeyPair{PrivateKey: priv, PublicKey: s.publicKey(sk)}, nil
+}
+
+// PublicKey returns the compressed public key of the private key sk.
+func (s Suite) PublicKey(sk []byte) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	k, err := parsePrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return s.publicKey(k), nil
+}
+
+// Sign signs msg with the private key sk.
+func (s Suite) Sign(sk, msg []byte) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	k, err := parsePrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(k, msg, s.ID()), nil
+}
+
+// Verify verifies the signature sig of msg against the public key pk.
+func (s Suite) Verify(pk, msg, sig []byte) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	return s.verify([][]byte{pk}, [][]byte{msg}, sig, s.ID())
+}
+
+// AggregateSignatures combines signatures into a single signature of the same size.
+func (s Suite) AggregateSignatures(sigs ...[]byte) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if len(sigs) == 0 {
+		return nil, EmptyAggregateError{}
+	}
+	if s.Variant == MinSig {
+		var agg bls12381.G1
+		agg.SetIdentity()
+		for _, sig := range sigs {
+			p, err := parseG1(sig)
+			if err != nil {
+				return nil, InvalidSignatureError{Err: err}
+			}
+			agg.Add(&agg, p)
+		}
+		return agg.BytesCompressed(), nil
+	}
+	var agg bls12381.G2
+	agg.SetIdentity()
+	for _, sig := range sigs {
+		p, err := parseG2(sig)
+		if err != nil {
+			return nil, InvalidSignatureError{Err: err}
+		}
+		agg.Add(&agg, p)
+	}
+	return agg.BytesCompressed(), nil
+}
+
+// AggregatePublicKeys combines public keys into a single public key, for use with
+// signatures over a common message. The keys must have verified proofs of possession,
+// otherwise an attacker can forge aggregates with a rogue key.
+func (s Suite) AggregatePublicKeys(pks ...[]byte) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if len(pks) == 0 {
+		return nil, EmptyAggregateError{}
+	}
+	if s.Variant == MinSig {
+		var agg bls12381.G2
+		agg.SetIdentity()
+		for _, pk := range pks {
+			p, err := parseG2PublicKey(pk)
+			if err != nil {
+				return nil, err
+			}
+			agg.Add(&agg, p)
+		}
+		return agg.BytesCompressed(), nil
+	}
+	var agg bls12381.G1
+	agg.SetIdentity()
+	for _, pk := range pks {
+		p, err := parseG1PublicKey(pk)
+		if err != nil {
+			return nil, err
+		}
+		agg.Add(&agg, p)
+	}
+	return agg.BytesCompressed(), nil
+}
+
+// AggregateVerify verifies an aggregate signature where pks[i] signed msgs[i].
+// Under the basic scheme the messages must be distinct.
+func (s Suite) AggregateVerify(pks, msgs [][]byte, sig []byte) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if len(pks) != len(msgs) {
+		return LengthMismatchError{Keys: len(pks), Messages: len(msgs)}
+	}
+	if len(pks) == 0 {
+		return EmptyAggregateError{}
+	}
+	if s.Scheme == Basic {
+		seen := make(map[string]struct{}, len(msgs))
+		for i, msg := range msgs {
+			if _, ok := seen[string(msg)]; ok {
+				return DuplicateMessageError{Index: i}
+			}
+			seen[string(msg)] = struct{}{}
+		}
+	}
+	return s.verify(pks, msgs, sig, s.ID())
+}
+
+// FastAggregateVerify verifies an aggregate signature where every key in pks signed
+// the same msg. It is only available under the proof of possession scheme.
+func (s Suite) FastAggregateVerify(pks [][]byte, msg, sig []byte) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if s.Scheme != ProofOfPossession {
+		return UnsupportedSchemeError{Operation: "FastAggregateVerify"}
+	}
+	pk, err := s.AggregatePublicKeys(pks...)
+	if err != nil {
+		return err
+	}
+	return s.verify([][]byte{pk}, [][]byte{msg}, sig, s.ID())
+}
+
+// PopProve returns a proof of possession of the private key sk.
+func (s Suite) PopProve(sk []byte) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if s.Scheme != ProofOfPossession {
+		return nil, UnsupportedSchemeError{Operation: "PopProve"}
+	}
+	k, err := parsePrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(k, s.publicKey(k), s.popID()), nil
+}
+
+// PopVerify verifies a proof of possession for the public key pk.
+func (s Suite) PopVerify(pk, proof []byte) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if s.Scheme != ProofOfPossession {
+		return UnsupportedSchemeError{Operation: "PopVerify"}
+	}
+	return s.verify([][]byte{pk}, [][]byte{pk}, proof, s.popID())
+}
+
+// validate reports an InvalidSuiteError for unknown variants or schemes.
+func (s Suite) validate() error {
+	if s.Variant > MinSig || s.Scheme > ProofOfPossession {
+		return InvalidSuiteError{Suite: s}
+	}
+	return nil
+}
+
+// publicKey computes the compressed public key of sk.
+func (s Suite) publicKey(sk *bls12381.Scalar) []byte {
+	if s.Variant == MinSig {
+		var p bls12381.G2
+		p.ScalarMult(sk, bls12381.G2Generator())
+		return p.BytesCompressed()
+	}
+	var p bls12381.G1
+	p.ScalarMult(sk, bls12381.G1Generator())
+	return p.BytesCompressed()
+}
+
+// sign hashes msg to the signature group with dst and multiplies it by sk.
+func (s Suite) sign(sk *bls12381.Scalar, msg []byte, dst string) []byte {
+	if s.Variant == MinSig {
+		var p bls12381.G1
+		p.Hash(msg, []byte(dst))
+		p.ScalarMult(sk, &p)
+		return p.BytesCompressed()
+	}
+	var p bls12381.G2
+	p.Hash(msg, []byte(dst))
+	p.ScalarMult(sk, &p)
+	return p.BytesCompressed()
+}
+
+// verify checks that the product of e(pks[i], H(msgs[i])) equals e(g, sig), with the
+// pairing arguments swapped for the min-sig variant.
+func (s Suite) verify(pks, msgs [][]byte, sig []byte, dst string) error {
+	n := len(pks)
+	g1s, g2s, signs := make([]*bls12381.G1, n+1), make([]*bls12381.G2, n+1), make([]int, n+1)
+	for i := range pks {
+		signs[i] = 1
+		if s.Variant == MinSig {
+			pk, err := parseG2PublicKey(pks[i])
+			if err != nil {
+				return err
+			}
+			g1s[i], g2s[i] = new(bls12381.G1), pk
+			g1s[i].Hash(msgs[i], []byte(dst))
+			continue
+		}
+		pk, err := parseG1PublicKey(pks[i])
+		if err != nil {
+			return err
+		}
+		g1s[i], g2s[i] = pk, new(bls12381.G2)
+		g2s[i].Hash(msgs[i], []byte(dst))
+	}
+	signs[n] = -1
+	if s.Variant == MinSig {
+		p, err := parseG1(sig)
+		if err != nil {
+			return InvalidSignatureError{Err: err}
+		}
+		g1s[n], g2s[n] = p, bls12381.G2Generator()
+	} else {
+		p, err := parseG2(sig)
+		if err != nil {
+			return InvalidSignatureError{Err: err}
+		}
+		g1s[n], g2s[n] = bls12381.G1Generator(), p
+	}
+	if !bls12381.ProdPairFrac(g1s, g2s, signs).IsIdentity() {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
+
+// parsePrivateKey decodes a big-endian scalar in [1, r).
+func parsePrivateKey(b []byte) (*bls12381.Scalar, error) {
+	if len(b) != PrivateKeySize {
+		return nil, InvalidPrivateKeyError{}
+	}
+	k := new(bls12381.Scalar)
+	if err := k.UnmarshalBinary(b); err != nil {
+		return nil, InvalidPrivateKeyError{Err: err}
+	}
+	if k.IsZero() == 1 {
+		return nil, InvalidPrivateKeyError{}
+	}
+	return k, nil
+}
+
+// parseG1 decodes a compressed G1 point and checks subgroup membership.
+func parseG1(b []byte) (*bls12381.G1, error) {
+	if len(b) != bls12381.G1SizeCompressed {
+		return nil, InvalidPointSizeError{Size: len(b), Want: bls12381.G1SizeCompressed}
+	}
+	p := new(bls12381.G1)
+	if err := p.SetBytes(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// parseG2 decodes a compressed G2 point and checks subgroup membership.
+func parseG2(b []byte) (*bls12381.G2, error) {
+	if len(b) != bls12381.G2SizeCompressed {
+		return nil, InvalidPointSizeError{Size: len(b), Want: bls12381.G2SizeCompressed}
+	}
+	p := new(bls12381.G2)
+	if err := p.SetBytes(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// parseG1PublicKey decodes a min-pk public key, rejecting the identity point.
+func parseG1PublicKey(b []byte) (*bls12381.G1, error) {
+	p, err := parseG1(b)
+	if err != nil {
+		return nil, InvalidPublicKeyError{Err: err}
+	}
+	if p.IsIdentity() {
+		return nil, InvalidPublicKeyError{}
+	}
+	return p, nil
+}
+
+// parseG2PublicKey decodes a min-sig public key, rejecting the identity point.
+func parseG2PublicKey(b []byte) (*bls12381.G2, error) {
+	p, err := parseG2(b)
+	if err != nil {
+		return nil, InvalidPublicKeyError{Err: err}
+	}
+	if p.IsIdentity() {
+		return nil, InvalidPublicKeyError{}
+	}
+	return p, nil
+}
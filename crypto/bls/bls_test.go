@@ -0,0 +1,299 @@
+package bls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	circl "github.com/cloudflare/circl/sign/bls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var suites = map[string]Suite{
+	"MinPkBasic":  MinPkBasic,
+	"MinPkPop":    MinPkPop,
+	"MinSigBasic": MinSigBasic,
+	"MinSigPop":   MinSigPop,
+}
+
+func genKeyPairs(t *testing.T, s Suite, n int) []*KeyPair {
+	kps := make([]*KeyPair, n)
+	for i := range kps {
+		kp, err := s.GenKeyPair()
+		require.NoError(t, err)
+		kps[i] = kp
+	}
+	return kps
+}
+
+func TestSuite_ID(t *testing.T) {
+	assert.Equal(t, "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_", MinPkBasic.ID())
+	assert.Equal(t, "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_", MinPkPop.ID())
+	assert.Equal(t, "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_", MinSigBasic.ID())
+	assert.Equal(t, "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_", MinSigPop.ID())
+}
+
+func TestSuite_SignVerify(t *testing.T) {
+	for name, s := range suites {
+		t.Run(name, func(t *testing.T) {
+			kp, err := s.GenKeyPair()
+			require.NoError(t, err)
+			assert.Len(t, kp.PrivateKey, PrivateKeySize)
+			assert.Len(t, kp.PublicKey, s.PublicKeySize())
+
+			pk, err := s.PublicKey(kp.PrivateKey)
+			require.NoError(t, err)
+			assert.Equal(t, kp.PublicKey, pk)
+
+			msg := []byte("hello world")
+			sig, err := s.Sign(kp.PrivateKey, msg)
+			require.NoError(t, err)
+			assert.Len(t, sig, s.SignatureSize())
+			assert.NoError(t, s.Verify(kp.PublicKey, msg, sig))
+
+			assert.Equal(t, SignatureVerificationError{}, s.Verify(kp.PublicKey, []byte("hello dongle"), sig))
+			other, err := s.GenKeyPair()
+			require.NoError(t, err)
+			assert.Equal(t, SignatureVerificationError{}, s.Verify(other.PublicKey, msg, sig))
+		})
+	}
+
+	t.Run("domain separation", func(t *testing.T) {
+		kp, err := MinPkBasic.GenKeyPair()
+		require.NoError(t, err)
+		sig, err := MinPkBasic.Sign(kp.PrivateKey, []byte("msg"))
+		require.NoError(t, err)
+		assert.Equal(t, SignatureVerificationError{}, MinPkPop.Verify(kp.PublicKey, []byte("msg"), sig))
+	})
+}
+
+func TestSuite_DeriveKeyPair(t *testing.T) {
+	ikm := bytes.Repeat([]byte{0x42}, 32)
+	a, err := MinPkBasic.DeriveKeyPair(ikm)
+	require.NoError(t, err)
+	b, err := MinPkBasic.DeriveKeyPair(ikm)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	// Both variants share the private key, only the public key group differs.
+	c, err := MinSigBasic.DeriveKeyPair(ikm)
+	require.NoError(t, err)
+	assert.Equal(t, a.PrivateKey, c.PrivateKey)
+	assert.NotEqual(t, a.PublicKey, c.PublicKey)
+
+	_, err = MinPkBasic.DeriveKeyPair(ikm[:31])
+	assert.Equal(t, InvalidIKMError{Size: 31}, err)
+	assert.Equal(t, "crypto/bls: invalid ikm size 31, must be at least 32 bytes", err.Error())
+}
+
+func TestSuite_Interop(t *testing.T) {
+	// The basic min-pk and min-sig suites match the circl basic scheme byte for byte.
+	ikm := bytes.Repeat([]byte{0x07}, 32)
+	salt := sha256.Sum256([]byte("BLS-SIG-KEYGEN-SALT-"))
+	msg := []byte("interop")
+
+	ref1, err := circl.KeyGen[circl.G1](ikm, salt[:], nil)
+	require.NoError(t, err)
+	kp, err := MinPkBasic.DeriveKeyPair(ikm)
+	require.NoError(t, err)
+	refKey, _ := ref1.MarshalBinary()
+	assert.Equal(t, refKey, kp.PrivateKey)
+	refPub, _ := ref1.PublicKey().MarshalBinary()
+	assert.Equal(t, refPub, kp.PublicKey)
+	sig, err := MinPkBasic.Sign(kp.PrivateKey, msg)
+	require.NoError(t, err)
+	assert.Equal(t, circl.Sign(ref1, msg), sig)
+
+	ref2, err := circl.KeyGen[circl.G2](ikm, salt[:], nil)
+	require.NoError(t, err)
+	kp, err = MinSigBasic.DeriveKeyPair(ikm)
+	require.NoError(t, err)
+	refPub, _ = ref2.PublicKey().MarshalBinary()
+	assert.Equal(t, refPub, kp.PublicKey)
+	sig, err = MinSigBasic.Sign(kp.PrivateKey, msg)
+	require.NoError(t, err)
+	assert.Equal(t, circl.Sign(ref2, msg), sig)
+}
+
+func TestSuite_AggregateVerify(t *testing.T) {
+	for name, s := range suites {
+		t.Run(name, func(t *testing.T) {
+			kps := genKeyPairs(t, s, 3)
+			pks, msgs, sigs := make([][]byte, 3), make([][]byte, 3), make([][]byte, 3)
+			for i, kp := range kps {
+				pks[i] = kp.PublicKey
+				msgs[i] = []byte(fmt.Sprintf("attestation %d", i))
+				sig, err := s.Sign(kp.PrivateKey, msgs[i])
+				require.NoError(t, err)
+				sigs[i] = sig
+			}
+			agg, err := s.AggregateSignatures(sigs...)
+			require.NoError(t, err)
+			assert.Len(t, agg, s.SignatureSize())
+			assert.NoError(t, s.AggregateVerify(pks, msgs, agg))
+
+			msgs[2] = []byte("tampered")
+			assert.Equal(t, SignatureVerificationError{}, s.AggregateVerify(pks, msgs, agg))
+			assert.Equal(t, LengthMismatchError{Keys: 3, Messages: 2}, s.AggregateVerify(pks, msgs[:2], agg))
+			assert.Equal(t, EmptyAggregateError{}, s.AggregateVerify(nil, nil, agg))
+		})
+	}
+
+	t.Run("distinct messages", func(t *testing.T) {
+		kps := genKeyPairs(t, MinPkBasic, 2)
+		msg := []byte("same")
+		sig0, _ := MinPkBasic.Sign(kps[0].PrivateKey, msg)
+		sig1, _ := MinPkBasic.Sign(kps[1].PrivateKey, msg)
+		agg, err := MinPkBasic.AggregateSignatures(sig0, sig1)
+		require.NoError(t, err)
+		err = MinPkBasic.AggregateVerify([][]byte{kps[0].PublicKey, kps[1].PublicKey}, [][]byte{msg, msg}, agg)
+		assert.Equal(t, DuplicateMessageError{Index: 1}, err)
+		assert.Equal(t, "crypto/bls: message 1 is repeated, the basic scheme requires distinct messages", err.Error())
+	})
+}
+
+func TestSuite_FastAggregateVerify(t *testing.T) {
+	for _, s := range []Suite{MinPkPop, MinSigPop} {
+		kps := genKeyPairs(t, s, 4)
+		msg := []byte("block 42")
+		pks, sigs := make([][]byte, 4), make([][]byte, 4)
+		for i, kp := range kps {
+			proof, err := s.PopProve(kp.PrivateKey)
+			require.NoError(t, err)
+			require.NoError(t, s.PopVerify(kp.PublicKey, proof))
+			pks[i] = kp.PublicKey
+			sigs[i], err = s.Sign(kp.PrivateKey, msg)
+			require.NoError(t, err)
+		}
+		agg, err := s.AggregateSignatures(sigs...)
+		require.NoError(t, err)
+		assert.NoError(t, s.FastAggregateVerify(pks, msg, agg))
+		assert.Equal(t, SignatureVerificationError{}, s.FastAggregateVerify(pks[:3], msg, agg))
+
+		apk, err := s.AggregatePublicKeys(pks...)
+		require.NoError(t, err)
+		assert.Len(t, apk, s.PublicKeySize())
+		assert.NoError(t, s.Verify(apk, msg, agg))
+	}
+
+	t.Run("basic scheme", func(t *testing.T) {
+		kp, err := MinPkBasic.GenKeyPair()
+		require.NoError(t, err)
+		assert.Equal(t, UnsupportedSchemeError{Operation: "FastAggregateVerify"}, MinPkBasic.FastAggregateVerify([][]byte{kp.PublicKey}, nil, nil))
+		_, err = MinPkBasic.PopProve(kp.PrivateKey)
+		assert.Equal(t, UnsupportedSchemeError{Operation: "PopProve"}, err)
+		assert.Equal(t, UnsupportedSchemeError{Operation: "PopVerify"}, MinPkBasic.PopVerify(kp.PublicKey, nil))
+	})
+}
+
+func TestSuite_PopVerify(t *testing.T) {
+	kps := genKeyPairs(t, MinPkPop, 2)
+	proof, err := MinPkPop.PopProve(kps[0].PrivateKey)
+	require.NoError(t, err)
+	assert.Equal(t, SignatureVerificationError{}, MinPkPop.PopVerify(kps[1].PublicKey, proof))
+
+	// A proof is not a signature over the public key under the signing tag.
+	sig, err := MinPkPop.Sign(kps[0].PrivateKey, kps[0].PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, SignatureVerificationError{}, MinPkPop.PopVerify(kps[0].PublicKey, sig))
+}
+
+func TestSuite_Errors(t *testing.T) {
+	s := MinPkBasic
+	kp, err := s.GenKeyPair()
+	require.NoError(t, err)
+	sig, err := s.Sign(kp.PrivateKey, []byte("msg"))
+	require.NoError(t, err)
+
+	t.Run("invalid suite", func(t *testing.T) {
+		bad := Suite{Variant: 2}
+		_, err := bad.GenKeyPair()
+		assert.Equal(t, InvalidSuiteError{Suite: bad}, err)
+		assert.Equal(t, "crypto/bls: invalid suite, variant 2 scheme 0", err.Error())
+		_, err = bad.Sign(kp.PrivateKey, nil)
+		assert.Error(t, err)
+		_, err = bad.PublicKey(kp.PrivateKey)
+		assert.Error(t, err)
+		assert.Error(t, bad.Verify(kp.PublicKey, nil, sig))
+		_, err = bad.AggregateSignatures(sig)
+		assert.Error(t, err)
+		_, err = bad.AggregatePublicKeys(kp.PublicKey)
+		assert.Error(t, err)
+		assert.Error(t, bad.AggregateVerify(nil, nil, sig))
+		assert.Error(t, bad.FastAggregateVerify(nil, nil, sig))
+		_, err = bad.PopProve(kp.PrivateKey)
+		assert.Error(t, err)
+		assert.Error(t, bad.PopVerify(kp.PublicKey, sig))
+		_, err = bad.DeriveKeyPair(make([]byte, 32))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid private key", func(t *testing.T) {
+		_, err := s.Sign(kp.PrivateKey[:31], nil)
+		assert.Equal(t, InvalidPrivateKeyError{}, err)
+		assert.Equal(t, "crypto/bls: invalid private key", err.Error())
+		_, err = s.Sign(make([]byte, 32), nil)
+		assert.Equal(t, InvalidPrivateKeyError{}, err)
+		_, err = s.PublicKey(bytes.Repeat([]byte{0xff}, 32))
+		assert.IsType(t, InvalidPrivateKeyError{}, err)
+		assert.Contains(t, err.Error(), "crypto/bls: invalid private key: ")
+		_, err = MinPkPop.PopProve(nil)
+		assert.IsType(t, InvalidPrivateKeyError{}, err)
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		err := s.Verify(kp.PublicKey[:47], []byte("msg"), sig)
+		assert.IsType(t, InvalidPublicKeyError{}, err)
+		assert.Equal(t, InvalidPublicKeyError{Err: InvalidPointSizeError{Size: 47, Want: 48}}, err)
+		assert.Equal(t, "crypto/bls: invalid public key: invalid point size 47, must be 48 bytes", err.Error())
+		var size InvalidPointSizeError
+		require.ErrorAs(t, err, &size)
+		assert.Equal(t, "DGL-BLS-011", size.Code())
+		assert.Equal(t, 47, size.Fields()["size"])
+
+		identity := make([]byte, 48)
+		identity[0] = 0xc0
+		err = s.Verify(identity, []byte("msg"), sig)
+		assert.Equal(t, InvalidPublicKeyError{}, err)
+		assert.Equal(t, "crypto/bls: invalid public key", err.Error())
+
+		_, err = s.AggregatePublicKeys(kp.PublicKey, identity)
+		assert.Equal(t, InvalidPublicKeyError{}, err)
+		_, err = MinSigBasic.AggregatePublicKeys(kp.PublicKey)
+		assert.IsType(t, InvalidPublicKeyError{}, err)
+		_, err = s.AggregatePublicKeys()
+		assert.Equal(t, EmptyAggregateError{}, err)
+		assert.Equal(t, "crypto/bls: nothing to aggregate", err.Error())
+		assert.IsType(t, InvalidPublicKeyError{}, MinSigBasic.Verify(kp.PublicKey, nil, sig))
+		assert.Equal(t, EmptyAggregateError{}, MinPkPop.FastAggregateVerify(nil, nil, sig))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		err := s.Verify(kp.PublicKey, []byte("msg"), sig[:95])
+		assert.IsType(t, InvalidSignatureError{}, err)
+		assert.Equal(t, InvalidSignatureError{Err: InvalidPointSizeError{Size: 95, Want: 96}}, err)
+		assert.Equal(t, "crypto/bls: invalid signature: invalid point size 95, must be 96 bytes", err.Error())
+		assert.Equal(t, "crypto/bls: invalid signature", InvalidSignatureError{}.Error())
+
+		bad := bytes.Repeat([]byte{0xff}, 96)
+		assert.IsType(t, InvalidSignatureError{}, s.Verify(kp.PublicKey, []byte("msg"), bad))
+		_, err = s.AggregateSignatures(sig, bad)
+		assert.IsType(t, InvalidSignatureError{}, err)
+		_, err = MinSigBasic.AggregateSignatures(sig)
+		assert.IsType(t, InvalidSignatureError{}, err)
+		_, err = s.AggregateSignatures()
+		assert.Equal(t, EmptyAggregateError{}, err)
+
+		mkp, err := MinSigBasic.GenKeyPair()
+		require.NoError(t, err)
+		assert.IsType(t, InvalidSignatureError{}, MinSigBasic.Verify(mkp.PublicKey, nil, sig))
+	})
+
+	t.Run("messages", func(t *testing.T) {
+		assert.Equal(t, "crypto/bls: got 1 public keys for 2 messages", LengthMismatchError{Keys: 1, Messages: 2}.Error())
+		assert.Equal(t, "crypto/bls: PopProve requires the proof of possession scheme", UnsupportedSchemeError{Operation: "PopProve"}.Error())
+		assert.Equal(t, "crypto/bls: signature verification failed", SignatureVerificationError{}.Error())
+	})
+}
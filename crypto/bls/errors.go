@@ -0,0 +1,248 @@
+package bls
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidSuiteError represents an error when a suite has an unknown variant or scheme.
+type InvalidSuiteError struct {
+	Suite Suite // The invalid suite
+}
+
+// Error returns a formatted error message describing the invalid suite.
+func (e InvalidSuiteError) Error() string {
+	return fmt.Sprintf("crypto/bls: invalid suite, variant %d scheme %d", e.Suite.Variant, e.Suite.Scheme)
+}
+
+// Code returns the stable error code DGL-BLS-001.
+func (e InvalidSuiteError) Code() string {
+	return "DGL-BLS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSuiteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "")
+}
+
+// InvalidIKMError represents an error when the key generation input keying material is too short.
+type InvalidIKMError struct {
+	Size int // Size of the provided input keying material
+}
+
+// Error returns a formatted error message describing the invalid input keying material.
+func (e InvalidIKMError) Error() string {
+	return fmt.Sprintf("crypto/bls: invalid ikm size %d, must be at least %d bytes", e.Size, MinIKMSize)
+}
+
+// Code returns the stable error code DGL-BLS-002.
+func (e InvalidIKMError) Code() string {
+	return "DGL-BLS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidIKMError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "", "size", e.Size)
+}
+
+// InvalidPrivateKeyError represents an error when a private key is not a non-zero scalar.
+type InvalidPrivateKeyError struct {
+	Err error // Underlying error from scalar decoding
+}
+
+// Error returns a formatted error message describing the invalid private key.
+func (e InvalidPrivateKeyError) Error() string {
+	if e.Err == nil {
+		return "crypto/bls: invalid private key"
+	}
+	return fmt.Sprintf("crypto/bls: invalid private key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-BLS-003.
+func (e InvalidPrivateKeyError) Code() string {
+	return "DGL-BLS-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPrivateKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "key", errcode.FieldCause, e.Err)
+}
+
+// InvalidPublicKeyError represents an error when a public key is malformed, not in the
+// prime order subgroup or the identity point.
+type InvalidPublicKeyError struct {
+	Err error // Underlying error from point decoding
+}
+
+// Error returns a formatted error message describing the invalid public key.
+func (e InvalidPublicKeyError) Error() string {
+	if e.Err == nil {
+		return "crypto/bls: invalid public key"
+	}
+	return fmt.Sprintf("crypto/bls: invalid public key: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e InvalidPublicKeyError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code DGL-BLS-004.
+func (e InvalidPublicKeyError) Code() string {
+	return "DGL-BLS-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPublicKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "key", errcode.FieldCause, e.Err)
+}
+
+// InvalidSignatureError represents an error when a signature is malformed or not in the prime order subgroup.
+type InvalidSignatureError struct {
+	Err error // Underlying error from point decoding
+}
+
+// Error returns a formatted error message describing the invalid signature.
+func (e InvalidSignatureError) Error() string {
+	if e.Err == nil {
+		return "crypto/bls: invalid signature"
+	}
+	return fmt.Sprintf("crypto/bls: invalid signature: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e InvalidSignatureError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code DGL-BLS-005.
+func (e InvalidSignatureError) Code() string {
+	return "DGL-BLS-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "verify", errcode.FieldCause, e.Err)
+}
+
+// EmptyAggregateError represents an error when aggregating or verifying an empty set.
+type EmptyAggregateError struct{}
+
+// Error returns a formatted error message describing the empty aggregate.
+func (e EmptyAggregateError) Error() string {
+	return "crypto/bls: nothing to aggregate"
+}
+
+// Code returns the stable error code DGL-BLS-006.
+func (e EmptyAggregateError) Code() string {
+	return "DGL-BLS-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyAggregateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "")
+}
+
+// LengthMismatchError represents an error when the number of public keys and messages differ.
+type LengthMismatchError struct {
+	Keys     int // Number of public keys
+	Messages int // Number of messages
+}
+
+// Error returns a formatted error message describing the length mismatch.
+func (e LengthMismatchError) Error() string {
+	return fmt.Sprintf("crypto/bls: got %d public keys for %d messages", e.Keys, e.Messages)
+}
+
+// Code returns the stable error code DGL-BLS-007.
+func (e LengthMismatchError) Code() string {
+	return "DGL-BLS-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e LengthMismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "", "keys", e.Keys, "messages", e.Messages)
+}
+
+// DuplicateMessageError represents an error when the basic scheme is asked to verify
+// an aggregate over messages that are not distinct.
+type DuplicateMessageError struct {
+	Index int // Index of the repeated message
+}
+
+// Error returns a formatted error message describing the duplicate message.
+func (e DuplicateMessageError) Error() string {
+	return fmt.Sprintf("crypto/bls: message %d is repeated, the basic scheme requires distinct messages", e.Index)
+}
+
+// Code returns the stable error code DGL-BLS-008.
+func (e DuplicateMessageError) Code() string {
+	return "DGL-BLS-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DuplicateMessageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "", "index", e.Index)
+}
+
+// UnsupportedSchemeError represents an error when an operation requires the proof of possession scheme.
+type UnsupportedSchemeError struct {
+	Operation string // The unsupported operation
+}
+
+// Error returns a formatted error message describing the unsupported operation.
+func (e UnsupportedSchemeError) Error() string {
+	return fmt.Sprintf("crypto/bls: %s requires the proof of possession scheme", e.Operation)
+}
+
+// Code returns the stable error code DGL-BLS-009.
+func (e UnsupportedSchemeError) Code() string {
+	return "DGL-BLS-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedSchemeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "", errcode.FieldOperation, e.Operation)
+}
+
+// SignatureVerificationError represents an error when a signature or proof does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/bls: signature verification failed"
+}
+
+// Code returns the stable error code DGL-BLS-010.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-BLS-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "verify")
+}
+
+// InvalidPointSizeError represents an error when an encoded public key or signature
+// is not the size of a compressed point of its group. It is reported as the cause
+// of an InvalidPublicKeyError or InvalidSignatureError.
+type InvalidPointSizeError struct {
+	Size int // Size of the provided encoding
+	Want int // Size of a compressed point
+}
+
+// Error returns a formatted error message describing the invalid point size.
+func (e InvalidPointSizeError) Error() string {
+	return fmt.Sprintf("invalid point size %d, must be %d bytes", e.Size, e.Want)
+}
+
+// Code returns the stable error code DGL-BLS-011.
+func (e InvalidPointSizeError) Code() string {
+	return "DGL-BLS-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPointSizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/bls", "BLS", "", "size", e.Size, "want", e.Want)
+}
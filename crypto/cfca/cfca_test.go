@@ -0,0 +1,147 @@
+package cfca
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/crypto/gmtls"
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestKeyPair generates an SM2 key pair and returns it with its parsed private key.
+func newTestKeyPair(t *testing.T) (*keypair.Sm2KeyPair, *ecdsa.PrivateKey) {
+	t.Helper()
+	kp := keypair.NewSm2KeyPair()
+	require.NoError(t, kp.GenKeyPair())
+	pri, err := kp.ParsePrivateKey()
+	require.NoError(t, err)
+	return kp, pri
+}
+
+// newTestCert issues a self-signed SM3withSM2 certificate for pri.
+func newTestCert(t *testing.T, pri *ecdsa.PrivateKey, serial int64) *gmtls.Certificate {
+	t.Helper()
+	spki, err := sm2.MarshalSPKIPublicKey(&pri.PublicKey)
+	require.NoError(t, err)
+	name, err := asn1.Marshal(pkix.Name{CommonName: "cfca test", Organization: []string{"dongle"}}.ToRDNSequence())
+	require.NoError(t, err)
+	alg := pkix.AlgorithmIdentifier{Algorithm: gmtls.OIDSignatureSM2WithSM3}
+	tbs, err := asn1.Marshal(struct {
+		Version   int `asn1:"explicit,tag:0"`
+		Serial    *big.Int
+		Algorithm pkix.AlgorithmIdentifier
+		Issuer    asn1.RawValue
+		Validity  struct{ NotBefore, NotAfter time.Time }
+		Subject   asn1.RawValue
+		PublicKey asn1.RawValue
+	}{
+		Version:   2,
+		Serial:    big.NewInt(serial),
+		Algorithm: alg,
+		Issuer:    asn1.RawValue{FullBytes: name},
+		Validity: struct{ NotBefore, NotAfter time.Time }{
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Subject:   asn1.RawValue{FullBytes: name},
+		PublicKey: asn1.RawValue{FullBytes: spki},
+	})
+	require.NoError(t, err)
+	sig, err := sm2.SignWithPrivateKey(pri, tbs, nil, 0)
+	require.NoError(t, err)
+	der, err := asn1.Marshal(struct {
+		TBS       asn1.RawValue
+		Algorithm pkix.AlgorithmIdentifier
+		Signature asn1.BitString
+	}{asn1.RawValue{FullBytes: tbs}, alg, asn1.BitString{Bytes: sig, BitLength: len(sig) * 8}})
+	require.NoError(t, err)
+	cert, err := gmtls.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestDecodeInput(t *testing.T) {
+	t.Run("der", func(t *testing.T) {
+		der, err := decodeInput([]byte{0x30, 0x00})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x30, 0x00}, der)
+	})
+
+	t.Run("base64 with line breaks", func(t *testing.T) {
+		text := base64.StdEncoding.EncodeToString([]byte{0x30, 0x03, 0x02, 0x01, 0x01})
+		der, err := decodeInput([]byte(text[:4] + "\r\n" + text[4:] + "\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x30, 0x03, 0x02, 0x01, 0x01}, der)
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		_, err := decodeInput([]byte("!!!"))
+		assert.IsType(t, InvalidDataError{}, err)
+		assert.Contains(t, err.Error(), "crypto/cfca: invalid data: ")
+	})
+}
+
+func TestSm3KDF(t *testing.T) {
+	z := []byte("123456")
+	h := sm3.New()
+	h.Write(z)
+	h.Write([]byte{0, 0, 0, 1})
+	first := h.Sum(nil)
+	h.Reset()
+	h.Write(z)
+	h.Write([]byte{0, 0, 0, 2})
+	second := h.Sum(nil)
+
+	assert.Equal(t, first, sm3KDF(z, 32))
+	assert.Equal(t, first[:16], sm3KDF(z, 16))
+	assert.Equal(t, append(first, second[:8]...), sm3KDF(z, 40))
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	_, pri := newTestKeyPair(t)
+
+	got, ok := newPrivateKey(pri.D.FillBytes(make([]byte, scalarSize)))
+	assert.True(t, ok)
+	assert.True(t, equalPublicKeys(&pri.PublicKey, &got.PublicKey))
+
+	_, ok = newPrivateKey(make([]byte, scalarSize))
+	assert.False(t, ok)
+	_, ok = newPrivateKey(sm2.NewCurve().Params().N.Bytes())
+	assert.False(t, ok)
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Run("nil key pair", func(t *testing.T) {
+		_, err := parsePrivateKey(nil)
+		assert.IsType(t, InvalidKeyError{}, err)
+		_, err = parsePublicKey(nil)
+		assert.IsType(t, InvalidKeyError{}, err)
+	})
+
+	t.Run("empty key pair", func(t *testing.T) {
+		_, err := parsePrivateKey(keypair.NewSm2KeyPair())
+		assert.IsType(t, InvalidKeyError{}, err)
+		assert.Contains(t, err.Error(), "crypto/cfca: invalid key: ")
+		_, err = parsePublicKey(keypair.NewSm2KeyPair())
+		assert.IsType(t, InvalidKeyError{}, err)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		_, pri := newTestKeyPair(t)
+		kp := newKeyPair(pri)
+		got, err := parsePrivateKey(kp)
+		require.NoError(t, err)
+		assert.Equal(t, 0, pri.D.Cmp(got.D))
+		pub, err := parsePublicKey(kp)
+		require.NoError(t, err)
+		assert.True(t, equalPublicKeys(&pri.PublicKey, pub))
+	})
+}
@@ -0,0 +1,122 @@
+package cfca
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/dromara/dongle/crypto/internal/sm4"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// envelopedKey mirrors the SM2EnvelopedKey structure of GB/T 35276,
+// used by CAs to deliver the encryption key pair of a dual certificate.
+type envelopedKey struct {
+	SymAlgID               pkix.AlgorithmIdentifier
+	SymEncryptedKey        asn1.RawValue // SM2 ciphertext of the symmetric key
+	SM2PublicKey           asn1.BitString
+	SM2EncryptedPrivateKey asn1.BitString
+}
+
+// ParseEnvelopedPrivateKey opens an SM2 enveloped private key with the private
+// key of kp, usually the signing key the envelope was issued for, and returns
+// the delivered key pair after checking it against the enclosed public key.
+func ParseEnvelopedPrivateKey(kp *keypair.Sm2KeyPair, data []byte) (*keypair.Sm2KeyPair, error) {
+	pri, err := parsePrivateKey(kp)
+	if err != nil {
+		return nil, err
+	}
+	der, err := decodeInput(data)
+	if err != nil {
+		return nil, err
+	}
+	var env envelopedKey
+	rest, err := asn1.Unmarshal(der, &env)
+	if err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	if len(rest) > 0 {
+		return nil, InvalidDataError{Err: asn1.SyntaxError{Msg: "trailing data"}}
+	}
+	if alg := env.SymAlgID.Algorithm; !alg.Equal(OIDSM4ECB) && !alg.Equal(OIDSM4) {
+		return nil, UnsupportedAlgorithmError{Algorithm: alg}
+	}
+
+	// Decryption works in place, keep the caller's data intact.
+	encKey := append([]byte(nil), env.SymEncryptedKey.FullBytes...)
+	key, err := sm2.DecryptWithPrivateKey(pri, encKey, kp.Window, "asn1_c1c3c2")
+	if err != nil {
+		return nil, DecryptError{Err: err}
+	}
+	if len(key) != sm4.KeySize {
+		return nil, InvalidDataError{Err: asn1.StructuralError{Msg: "invalid symmetric key size"}}
+	}
+
+	ct := env.SM2EncryptedPrivateKey.RightAlign()
+	// Some toolkits store the scalar left padded to 64 bytes as in ECCrefPrivateKey.
+	if len(ct) != scalarSize && len(ct) != 2*scalarSize {
+		return nil, InvalidDataError{Err: asn1.StructuralError{Msg: "invalid encrypted private key size"}}
+	}
+	d := make([]byte, len(ct))
+	block := sm4.NewCipher(key)
+	for i := 0; i < len(ct); i += sm4.BlockSize {
+		block.Decrypt(d[i:], ct[i:])
+	}
+	delivered, ok := newPrivateKey(d[len(d)-scalarSize:])
+	if !ok {
+		return nil, InvalidDataError{Err: asn1.StructuralError{Msg: "invalid private key"}}
+	}
+
+	pub, err := sm2.ParseBitStringPublicKey(env.SM2PublicKey.RightAlign())
+	if err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	if !equalPublicKeys(&delivered.PublicKey, pub) {
+		return nil, KeyMismatchError{}
+	}
+	return newKeyPair(delivered), nil
+}
+
+// MarshalEnvelopedPrivateKey seals the private key of key into an SM2 enveloped
+// private key for the recipient public key of kp. The private key is encrypted
+// with a fresh SM4-ECB key which is in turn encrypted with SM2.
+func MarshalEnvelopedPrivateKey(kp *keypair.Sm2KeyPair, key *keypair.Sm2KeyPair) ([]byte, error) {
+	pub, err := parsePublicKey(kp)
+	if err != nil {
+		return nil, err
+	}
+	pri, err := parsePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	symKey := make([]byte, sm4.KeySize)
+	if _, err = io.ReadFull(utils.Rand(), symKey); err != nil {
+		return nil, EncryptError{Err: err}
+	}
+	encKey, err := sm2.EncryptWithPublicKey(pub, symKey, kp.Window, "asn1_c1c3c2")
+	if err != nil {
+		return nil, EncryptError{Err: err}
+	}
+
+	d := pri.D.FillBytes(make([]byte, scalarSize))
+	ct := make([]byte, len(d))
+	block := sm4.NewCipher(symKey)
+	for i := 0; i < len(d); i += sm4.BlockSize {
+		block.Encrypt(ct[i:], d[i:])
+	}
+
+	point := marshalPoint(&pri.PublicKey)
+	der, err := asn1.Marshal(envelopedKey{
+		SymAlgID:               pkix.AlgorithmIdentifier{Algorithm: OIDSM4ECB},
+		SymEncryptedKey:        asn1.RawValue{FullBytes: encKey},
+		SM2PublicKey:           asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+		SM2EncryptedPrivateKey: asn1.BitString{Bytes: ct, BitLength: len(ct) * 8},
+	})
+	if err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	return der, nil
+}
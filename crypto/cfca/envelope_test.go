@@ -0,0 +1,149 @@
+package cfca
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/dromara/dongle/crypto/internal/sm4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopedPrivateKey(t *testing.T) {
+	recipient, _ := newTestKeyPair(t)
+	delivered, deliveredPri := newTestKeyPair(t)
+
+	t.Run("round trip", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+
+		kp, err := ParseEnvelopedPrivateKey(recipient, der)
+		require.NoError(t, err)
+		pri, err := kp.ParsePrivateKey()
+		require.NoError(t, err)
+		assert.Equal(t, 0, deliveredPri.D.Cmp(pri.D))
+		assert.Equal(t, string(delivered.PublicKey), string(kp.PublicKey))
+	})
+
+	t.Run("layout", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+		var env envelopedKey
+		_, err = asn1.Unmarshal(der, &env)
+		require.NoError(t, err)
+		assert.True(t, env.SymAlgID.Algorithm.Equal(OIDSM4ECB))
+		assert.Equal(t, marshalPoint(&deliveredPri.PublicKey), env.SM2PublicKey.Bytes)
+		assert.Len(t, env.SM2EncryptedPrivateKey.Bytes, 32)
+		assert.Equal(t, byte(0x30), env.SymEncryptedKey.FullBytes[0])
+	})
+
+	t.Run("padded private key", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+		var env envelopedKey
+		_, err = asn1.Unmarshal(der, &env)
+		require.NoError(t, err)
+
+		// Re-encrypt the scalar left padded to 64 bytes, as ECCrefPrivateKey does.
+		recipientPri, err := recipient.ParsePrivateKey()
+		require.NoError(t, err)
+		key, err := sm2.DecryptWithPrivateKey(recipientPri, append([]byte(nil), env.SymEncryptedKey.FullBytes...), 4, "asn1_c1c3c2")
+		require.NoError(t, err)
+		padded := make([]byte, 64)
+		deliveredPri.D.FillBytes(padded[32:])
+		ct := make([]byte, 64)
+		block := sm4.NewCipher(key)
+		for i := 0; i < 64; i += 16 {
+			block.Encrypt(ct[i:], padded[i:])
+		}
+		env.SM2EncryptedPrivateKey = asn1.BitString{Bytes: ct, BitLength: 512}
+		der, err = asn1.Marshal(env)
+		require.NoError(t, err)
+
+		kp, err := ParseEnvelopedPrivateKey(recipient, der)
+		require.NoError(t, err)
+		assert.Equal(t, string(delivered.PublicKey), string(kp.PublicKey))
+	})
+
+	t.Run("wrong recipient", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+		_, err = ParseEnvelopedPrivateKey(delivered, der)
+		assert.IsType(t, DecryptError{}, err)
+		assert.Contains(t, err.Error(), "crypto/cfca: failed to decrypt: ")
+	})
+
+	t.Run("public key mismatch", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+		var env envelopedKey
+		_, err = asn1.Unmarshal(der, &env)
+		require.NoError(t, err)
+		_, otherPri := newTestKeyPair(t)
+		point := marshalPoint(&otherPri.PublicKey)
+		env.SM2PublicKey = asn1.BitString{Bytes: point, BitLength: len(point) * 8}
+		der, err = asn1.Marshal(env)
+		require.NoError(t, err)
+		_, err = ParseEnvelopedPrivateKey(recipient, der)
+		assert.Equal(t, KeyMismatchError{}, err)
+	})
+
+	t.Run("malformed envelope", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+		var env envelopedKey
+		_, err = asn1.Unmarshal(der, &env)
+		require.NoError(t, err)
+
+		bad := env
+		bad.SymAlgID = pkix.AlgorithmIdentifier{Algorithm: OIDSM4CBC}
+		out, _ := asn1.Marshal(bad)
+		_, err = ParseEnvelopedPrivateKey(recipient, out)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: OIDSM4CBC}, err)
+
+		bad = env
+		bad.SM2EncryptedPrivateKey = asn1.BitString{Bytes: make([]byte, 16), BitLength: 128}
+		out, _ = asn1.Marshal(bad)
+		_, err = ParseEnvelopedPrivateKey(recipient, out)
+		assert.IsType(t, InvalidDataError{}, err)
+
+		bad = env
+		bad.SM2PublicKey = asn1.BitString{Bytes: []byte{0x04, 0x01}, BitLength: 16}
+		out, _ = asn1.Marshal(bad)
+		_, err = ParseEnvelopedPrivateKey(recipient, out)
+		assert.IsType(t, InvalidDataError{}, err)
+
+		_, err = ParseEnvelopedPrivateKey(recipient, append(der, 0x00))
+		assert.IsType(t, InvalidDataError{}, err)
+		_, err = ParseEnvelopedPrivateKey(recipient, []byte{0x30, 0x01})
+		assert.IsType(t, InvalidDataError{}, err)
+		_, err = ParseEnvelopedPrivateKey(recipient, []byte("!!"))
+		assert.IsType(t, InvalidDataError{}, err)
+	})
+
+	t.Run("invalid symmetric key", func(t *testing.T) {
+		der, err := MarshalEnvelopedPrivateKey(recipient, delivered)
+		require.NoError(t, err)
+		var env envelopedKey
+		_, err = asn1.Unmarshal(der, &env)
+		require.NoError(t, err)
+		pub, err := recipient.ParsePublicKey()
+		require.NoError(t, err)
+		env.SymEncryptedKey.FullBytes, err = sm2.EncryptWithPublicKey(pub, make([]byte, 8), 4, "asn1_c1c3c2")
+		require.NoError(t, err)
+		out, _ := asn1.Marshal(env)
+		_, err = ParseEnvelopedPrivateKey(recipient, out)
+		assert.IsType(t, InvalidDataError{}, err)
+	})
+
+	t.Run("invalid key pairs", func(t *testing.T) {
+		_, err := MarshalEnvelopedPrivateKey(nil, delivered)
+		assert.IsType(t, InvalidKeyError{}, err)
+		_, err = MarshalEnvelopedPrivateKey(recipient, nil)
+		assert.IsType(t, InvalidKeyError{}, err)
+		_, err = ParseEnvelopedPrivateKey(nil, nil)
+		assert.IsType(t, InvalidKeyError{}, err)
+	})
+}
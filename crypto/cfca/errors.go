@@ -0,0 +1,221 @@
+package cfca
+
+import (
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidDataError represents an error when CFCA data cannot be decoded.
+type InvalidDataError struct {
+	Err error // Underlying error from base64 or ASN.1 decoding
+}
+
+// Error returns a formatted error message describing the invalid data.
+func (e InvalidDataError) Error() string {
+	if e.Err == nil {
+		return "crypto/cfca: invalid data"
+	}
+	return fmt.Sprintf("crypto/cfca: invalid data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-CFCA-001.
+func (e InvalidDataError) Code() string {
+	return "DGL-CFCA-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidDataError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "", errcode.FieldCause, e.Err)
+}
+
+// InvalidKeyError represents an error when the SM2 key pair cannot be parsed.
+type InvalidKeyError struct {
+	Err error // Underlying error from key parsing
+}
+
+// Error returns a formatted error message describing the invalid key.
+func (e InvalidKeyError) Error() string {
+	return fmt.Sprintf("crypto/cfca: invalid key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-CFCA-002.
+func (e InvalidKeyError) Code() string {
+	return "DGL-CFCA-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "key", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedAlgorithmError represents an error when data uses an unsupported algorithm or content type.
+type UnsupportedAlgorithmError struct {
+	Algorithm asn1.ObjectIdentifier // The unsupported object identifier
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/cfca: unsupported algorithm or content type %s", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-CFCA-003.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-CFCA-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "", errcode.FieldAlgorithm, e.Algorithm.String())
+}
+
+// IncorrectPasswordError represents an error when a ".sm2" file cannot be decrypted with the given password.
+type IncorrectPasswordError struct{}
+
+// Error returns a formatted error message describing the incorrect password.
+func (e IncorrectPasswordError) Error() string {
+	return "crypto/cfca: incorrect password"
+}
+
+// Code returns the stable error code DGL-CFCA-004.
+func (e IncorrectPasswordError) Code() string {
+	return "DGL-CFCA-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e IncorrectPasswordError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "key")
+}
+
+// KeyMismatchError represents an error when a private key does not match its certificate or public key.
+type KeyMismatchError struct{}
+
+// Error returns a formatted error message describing the key mismatch.
+func (e KeyMismatchError) Error() string {
+	return "crypto/cfca: private key does not match the certificate or public key"
+}
+
+// Code returns the stable error code DGL-CFCA-005.
+func (e KeyMismatchError) Code() string {
+	return "DGL-CFCA-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyMismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "key")
+}
+
+// EncryptError represents an error when sealing an enveloped private key fails.
+type EncryptError struct {
+	Err error // Underlying error from encryption
+}
+
+// Error returns a formatted error message describing the encryption failure.
+func (e EncryptError) Error() string {
+	return fmt.Sprintf("crypto/cfca: failed to encrypt: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-CFCA-006.
+func (e EncryptError) Code() string {
+	return "DGL-CFCA-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "encrypt", errcode.FieldCause, e.Err)
+}
+
+// DecryptError represents an error when opening an enveloped private key fails.
+type DecryptError struct {
+	Err error // Underlying error from decryption
+}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("crypto/cfca: failed to decrypt: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-CFCA-007.
+func (e DecryptError) Code() string {
+	return "DGL-CFCA-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "decrypt", errcode.FieldCause, e.Err)
+}
+
+// SignError represents an error when creating an SM2 signature fails.
+type SignError struct {
+	Err error // Underlying error from SM2 signing
+}
+
+// Error returns a formatted error message describing the signing failure.
+func (e SignError) Error() string {
+	return fmt.Sprintf("crypto/cfca: failed to sign: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-CFCA-008.
+func (e SignError) Code() string {
+	return "DGL-CFCA-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "sign", errcode.FieldCause, e.Err)
+}
+
+// SignatureVerificationError represents an error when a signed data signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/cfca: signature verification failed"
+}
+
+// Code returns the stable error code DGL-CFCA-009.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-CFCA-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "verify")
+}
+
+// SignerNotFoundError represents an error when no certificate matches a signer.
+type SignerNotFoundError struct{}
+
+// Error returns a formatted error message describing the missing signer certificate.
+func (e SignerNotFoundError) Error() string {
+	return "crypto/cfca: signer certificate not found"
+}
+
+// Code returns the stable error code DGL-CFCA-010.
+func (e SignerNotFoundError) Code() string {
+	return "DGL-CFCA-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignerNotFoundError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "sign")
+}
+
+// MissingContentError represents an error when verifying a detached signature without content.
+type MissingContentError struct{}
+
+// Error returns a formatted error message describing the missing content.
+func (e MissingContentError) Error() string {
+	return "crypto/cfca: signed data has no content, use VerifyDetached"
+}
+
+// Code returns the stable error code DGL-CFCA-011.
+func (e MissingContentError) Code() string {
+	return "DGL-CFCA-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MissingContentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cfca", "SM2", "")
+}
@@ -0,0 +1,252 @@
+package cfca
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/dromara/dongle/crypto/gmtls"
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// oidAttributeMessageDigest identifies the PKCS#9 message digest attribute.
+var oidAttributeMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+// contentInfo mirrors the PKCS#7 ContentInfo structure, Content holds the
+// explicit [0] wrapper so its Bytes are the encoded inner content.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// signedData mirrors the PKCS#7 SignedData structure.
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// signerInfo mirrors the PKCS#7 SignerInfo structure.
+type signerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerial
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+	UnauthenticatedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// issuerAndSerial identifies a certificate by its issuer and serial number.
+type issuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// attribute mirrors the PKCS#9 Attribute structure.
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// Signer describes one signer of an SM2 signed data message.
+type Signer struct {
+	Issuer       []byte   // DER encoded issuer of the signer certificate
+	SerialNumber *big.Int // Serial number of the signer certificate
+	Signature    []byte   // ASN.1 encoded SM2 signature
+
+	attributes []byte // DER encoded authenticated attributes, re-tagged as a SET
+}
+
+// SignedData represents a parsed SM2 PKCS#7 signed data message.
+type SignedData struct {
+	Content      []byte               // Signed content, nil for detached signatures
+	Certificates []*gmtls.Certificate // Certificates bundled with the message
+	Signers      []Signer             // Signer information
+}
+
+// Sign creates an SM2 PKCS#7 signed data message over content with the private
+// key of kp, embedding cert so the message can be verified on its own.
+// When detached is true the content is left out of the message.
+func Sign(content []byte, kp *keypair.Sm2KeyPair, cert *gmtls.Certificate, detached bool) ([]byte, error) {
+	pri, err := parsePrivateKey(kp)
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil || !equalPublicKeys(&pri.PublicKey, cert.PublicKey) {
+		return nil, KeyMismatchError{}
+	}
+	sig, err := sm2.SignWithPrivateKey(pri, content, nil, 0)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+
+	inner := contentInfo{ContentType: OIDData}
+	if !detached {
+		octets, _ := asn1.Marshal(content)
+		inner.Content = explicit(octets)
+	}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{{Algorithm: OIDSM3}},
+		ContentInfo:      inner,
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: cert.Raw},
+		SignerInfos: []signerInfo{{
+			Version:                   1,
+			IssuerAndSerialNumber:     issuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber},
+			DigestAlgorithm:           pkix.AlgorithmIdentifier{Algorithm: OIDSM3},
+			DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: OIDSM2Sign},
+			EncryptedDigest:           sig,
+		}},
+	}
+	body, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	der, err := asn1.Marshal(contentInfo{ContentType: OIDSignedData, Content: explicit(body)})
+	if err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	return der, nil
+}
+
+// ParseSignedData parses an SM2 PKCS#7 signed data message, raw DER or base64 text.
+func ParseSignedData(data []byte) (*SignedData, error) {
+	der, err := decodeInput(data)
+	if err != nil {
+		return nil, err
+	}
+	var outer contentInfo
+	if rest, err := asn1.Unmarshal(der, &outer); err != nil || len(rest) > 0 {
+		return nil, InvalidDataError{Err: err}
+	}
+	if !outer.ContentType.Equal(OIDSignedData) {
+		return nil, UnsupportedAlgorithmError{Algorithm: outer.ContentType}
+	}
+	var sd signedData
+	if _, err = asn1.Unmarshal(outer.Content.Bytes, &sd); err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	if !sd.ContentInfo.ContentType.Equal(OIDData) {
+		return nil, UnsupportedAlgorithmError{Algorithm: sd.ContentInfo.ContentType}
+	}
+
+	out := new(SignedData)
+	if len(sd.ContentInfo.Content.FullBytes) > 0 {
+		if _, err = asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &out.Content); err != nil {
+			return nil, InvalidDataError{Err: err}
+		}
+	}
+	for rest := sd.Certificates.Bytes; len(rest) > 0; {
+		var raw asn1.RawValue
+		if rest, err = asn1.Unmarshal(rest, &raw); err != nil {
+			return nil, InvalidDataError{Err: err}
+		}
+		cert, err := gmtls.ParseCertificate(raw.FullBytes)
+		if err != nil {
+			return nil, err
+		}
+		out.Certificates = append(out.Certificates, cert)
+	}
+	for _, si := range sd.SignerInfos {
+		if !si.DigestAlgorithm.Algorithm.Equal(OIDSM3) {
+			return nil, UnsupportedAlgorithmError{Algorithm: si.DigestAlgorithm.Algorithm}
+		}
+		if !si.DigestEncryptionAlgorithm.Algorithm.Equal(OIDSM2Sign) {
+			return nil, UnsupportedAlgorithmError{Algorithm: si.DigestEncryptionAlgorithm.Algorithm}
+		}
+		signer := Signer{
+			Issuer:       si.IssuerAndSerialNumber.Issuer.FullBytes,
+			SerialNumber: si.IssuerAndSerialNumber.SerialNumber,
+			Signature:    si.EncryptedDigest,
+		}
+		if len(si.AuthenticatedAttributes.FullBytes) > 0 {
+			// The attributes are signed as an explicit SET OF, not with the implicit [0] tag.
+			signer.attributes = append([]byte{0x31}, si.AuthenticatedAttributes.FullBytes[1:]...)
+		}
+		out.Signers = append(out.Signers, signer)
+	}
+	return out, nil
+}
+
+// Verify checks every signature over the embedded content against the
+// embedded signer certificates.
+func (s *SignedData) Verify() error {
+	if s.Content == nil {
+		return MissingContentError{}
+	}
+	return s.VerifyDetached(s.Content)
+}
+
+// VerifyDetached checks every signature over content, as used for messages
+// created without embedded content.
+func (s *SignedData) VerifyDetached(content []byte) error {
+	if len(s.Signers) == 0 {
+		return SignerNotFoundError{}
+	}
+	for _, signer := range s.Signers {
+		cert := s.certificate(signer)
+		if cert == nil {
+			return SignerNotFoundError{}
+		}
+		if err := signer.verify(cert, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// explicit wraps der in an explicit [0] tag.
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// certificate returns the bundled certificate matching signer, if any.
+func (s *SignedData) certificate(signer Signer) *gmtls.Certificate {
+	for _, cert := range s.Certificates {
+		if bytes.Equal(cert.RawIssuer, signer.Issuer) && cert.SerialNumber.Cmp(signer.SerialNumber) == 0 {
+			return cert
+		}
+	}
+	return nil
+}
+
+// verify checks the signature of signer over content with cert.
+func (signer Signer) verify(cert *gmtls.Certificate, content []byte) error {
+	if signer.attributes == nil {
+		if cert.CheckSignature(content, signer.Signature) != nil {
+			return SignatureVerificationError{}
+		}
+		return nil
+	}
+
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(signer.attributes, &attrs, "set"); err != nil {
+		return InvalidDataError{Err: err}
+	}
+	h := sm3.New()
+	h.Write(content)
+	digest := h.Sum(nil)
+	matched := false
+	for _, attr := range attrs {
+		if !attr.Type.Equal(oidAttributeMessageDigest) {
+			continue
+		}
+		var value []byte
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+			return InvalidDataError{Err: err}
+		}
+		matched = utils.ConstantTimeEqual(value, digest)
+	}
+	if !matched || cert.CheckSignature(signer.attributes, signer.Signature) != nil {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package cfca
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedData(t *testing.T) {
+	kp, pri := newTestKeyPair(t)
+	cert := newTestCert(t, pri, 42)
+	content := []byte("hello dongle")
+
+	t.Run("attached", func(t *testing.T) {
+		der, err := Sign(content, kp, cert, false)
+		require.NoError(t, err)
+
+		sd, err := ParseSignedData(der)
+		require.NoError(t, err)
+		assert.Equal(t, content, sd.Content)
+		require.Len(t, sd.Certificates, 1)
+		assert.Equal(t, cert.Raw, sd.Certificates[0].Raw)
+		require.Len(t, sd.Signers, 1)
+		assert.Equal(t, int64(42), sd.Signers[0].SerialNumber.Int64())
+		assert.Equal(t, cert.RawIssuer, sd.Signers[0].Issuer)
+		assert.NoError(t, sd.Verify())
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		der, err := Sign(content, kp, cert, true)
+		require.NoError(t, err)
+
+		sd, err := ParseSignedData([]byte(base64.StdEncoding.EncodeToString(der)))
+		require.NoError(t, err)
+		assert.Nil(t, sd.Content)
+		assert.Equal(t, MissingContentError{}, sd.Verify())
+		assert.Equal(t, "crypto/cfca: signed data has no content, use VerifyDetached", sd.Verify().Error())
+		assert.NoError(t, sd.VerifyDetached(content))
+		assert.Equal(t, SignatureVerificationError{}, sd.VerifyDetached([]byte("tampered")))
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		der, err := Sign([]byte{}, kp, cert, false)
+		require.NoError(t, err)
+		sd, err := ParseSignedData(der)
+		require.NoError(t, err)
+		assert.NoError(t, sd.Verify())
+	})
+
+	t.Run("tampered signature", func(t *testing.T) {
+		der, err := Sign(content, kp, cert, false)
+		require.NoError(t, err)
+		sd, err := ParseSignedData(der)
+		require.NoError(t, err)
+		sd.Content = []byte("tampered")
+		err = sd.Verify()
+		assert.Equal(t, SignatureVerificationError{}, err)
+		assert.Equal(t, "crypto/cfca: signature verification failed", err.Error())
+	})
+
+	t.Run("signer not found", func(t *testing.T) {
+		der, err := Sign(content, kp, cert, false)
+		require.NoError(t, err)
+		sd, err := ParseSignedData(der)
+		require.NoError(t, err)
+
+		sd.Signers[0].SerialNumber = big.NewInt(1)
+		err = sd.Verify()
+		assert.Equal(t, SignerNotFoundError{}, err)
+		assert.Equal(t, "crypto/cfca: signer certificate not found", err.Error())
+
+		sd.Signers = nil
+		assert.Equal(t, SignerNotFoundError{}, sd.Verify())
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		other, _ := newTestKeyPair(t)
+		_, err := Sign(content, other, cert, false)
+		assert.Equal(t, KeyMismatchError{}, err)
+		_, err = Sign(content, kp, nil, false)
+		assert.Equal(t, KeyMismatchError{}, err)
+		_, err = Sign(content, nil, cert, false)
+		assert.IsType(t, InvalidKeyError{}, err)
+	})
+
+	t.Run("authenticated attributes", func(t *testing.T) {
+		h := sm3.New()
+		h.Write(content)
+		digestValue, _ := asn1.Marshal(h.Sum(nil))
+		typeValue, _ := asn1.Marshal(OIDData)
+		attrs := []attribute{
+			{Type: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}, Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: typeValue}},
+			{Type: oidAttributeMessageDigest, Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: digestValue}},
+		}
+		set, err := asn1.MarshalWithParams(attrs, "set")
+		require.NoError(t, err)
+		sig, err := sm2.SignWithPrivateKey(pri, set, nil, 0)
+		require.NoError(t, err)
+
+		der := buildSignedData(t, content, cert.Raw, signerInfo{
+			Version:                   1,
+			IssuerAndSerialNumber:     issuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber},
+			DigestAlgorithm:           pkix.AlgorithmIdentifier{Algorithm: OIDSM3},
+			AuthenticatedAttributes:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: set[2:]},
+			DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: OIDSM2Sign},
+			EncryptedDigest:           sig,
+		})
+		sd, err := ParseSignedData(der)
+		require.NoError(t, err)
+		assert.NoError(t, sd.Verify())
+		assert.Equal(t, SignatureVerificationError{}, sd.VerifyDetached([]byte("tampered")))
+	})
+
+	t.Run("unsupported algorithms", func(t *testing.T) {
+		other := asn1.ObjectIdentifier{1, 2, 3}
+		si := signerInfo{
+			Version:                   1,
+			IssuerAndSerialNumber:     issuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber},
+			DigestAlgorithm:           pkix.AlgorithmIdentifier{Algorithm: other},
+			DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: OIDSM2Sign},
+			EncryptedDigest:           []byte{0x00},
+		}
+		_, err := ParseSignedData(buildSignedData(t, content, cert.Raw, si))
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: other}, err)
+
+		si.DigestAlgorithm.Algorithm = OIDSM3
+		si.DigestEncryptionAlgorithm.Algorithm = other
+		_, err = ParseSignedData(buildSignedData(t, content, cert.Raw, si))
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: other}, err)
+
+		outer, _ := asn1.Marshal(contentInfo{ContentType: other})
+		_, err = ParseSignedData(outer)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: other}, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := ParseSignedData([]byte("!!"))
+		assert.IsType(t, InvalidDataError{}, err)
+		_, err = ParseSignedData([]byte{0x30, 0x01})
+		assert.IsType(t, InvalidDataError{}, err)
+
+		der, err := Sign(content, kp, cert, false)
+		require.NoError(t, err)
+		_, err = ParseSignedData(append(der, 0x00))
+		assert.Equal(t, InvalidDataError{}, err)
+		assert.Equal(t, "crypto/cfca: invalid data", err.Error())
+
+		body, _ := asn1.Marshal(contentInfo{ContentType: OIDSignedData, Content: explicit([]byte{0x02, 0x01, 0x01})})
+		_, err = ParseSignedData(body)
+		assert.IsType(t, InvalidDataError{}, err)
+	})
+}
+
+// buildSignedData assembles an attached signed data message around si.
+func buildSignedData(t *testing.T, content, cert []byte, si signerInfo) []byte {
+	t.Helper()
+	octets, _ := asn1.Marshal(content)
+	body, err := asn1.Marshal(signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{{Algorithm: OIDSM3}},
+		ContentInfo:      contentInfo{ContentType: OIDData, Content: explicit(octets)},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: cert},
+		SignerInfos:      []signerInfo{si},
+	})
+	require.NoError(t, err)
+	der, err := asn1.Marshal(contentInfo{ContentType: OIDSignedData, Content: explicit(body)})
+	require.NoError(t, err)
+	return der
+}
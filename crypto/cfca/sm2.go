@@ -0,0 +1,119 @@
+package cfca
+
+import (
+	stdCipher "crypto/cipher"
+	"encoding/asn1"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/gmtls"
+	"github.com/dromara/dongle/crypto/internal/sm4"
+	"github.com/dromara/dongle/crypto/keypair"
+)
+
+// sm2File mirrors the CFCA ".sm2" key pair file structure.
+type sm2File struct {
+	Version      int `asn1:"default:1"`
+	EncryptedKey sm2FileKey
+	Certificate  sm2FileCert
+}
+
+// sm2FileKey holds the password encrypted private scalar.
+type sm2FileKey struct {
+	ContentType      asn1.ObjectIdentifier
+	Algorithm        asn1.ObjectIdentifier
+	EncryptedContent []byte
+}
+
+// sm2FileCert holds the DER encoded certificate.
+type sm2FileCert struct {
+	ContentType asn1.ObjectIdentifier
+	Content     []byte
+}
+
+// ParseSM2 parses a CFCA ".sm2" key pair file, raw DER or base64 text, and
+// decrypts its private key with password. The private key is checked against
+// the bundled certificate before both are returned.
+func ParseSM2(password, data []byte) (*keypair.Sm2KeyPair, *gmtls.Certificate, error) {
+	der, err := decodeInput(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var file sm2File
+	rest, err := asn1.Unmarshal(der, &file)
+	if err != nil {
+		return nil, nil, InvalidDataError{Err: err}
+	}
+	if len(rest) > 0 {
+		return nil, nil, InvalidDataError{Err: asn1.SyntaxError{Msg: "trailing data"}}
+	}
+	if !file.EncryptedKey.ContentType.Equal(OIDData) {
+		return nil, nil, UnsupportedAlgorithmError{Algorithm: file.EncryptedKey.ContentType}
+	}
+	if !file.Certificate.ContentType.Equal(OIDData) {
+		return nil, nil, UnsupportedAlgorithmError{Algorithm: file.Certificate.ContentType}
+	}
+	alg := file.EncryptedKey.Algorithm
+	if !alg.Equal(OIDSM4) && !alg.Equal(OIDSM4CBC) {
+		return nil, nil, UnsupportedAlgorithmError{Algorithm: alg}
+	}
+
+	cert, err := gmtls.ParseCertificate(file.Certificate.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ct := file.EncryptedKey.EncryptedContent
+	if len(ct) == 0 || len(ct)%sm4.BlockSize != 0 {
+		return nil, nil, InvalidDataError{Err: asn1.StructuralError{Msg: "encrypted key is not a multiple of the block size"}}
+	}
+	key, iv := passwordKey(password)
+	pt := make([]byte, len(ct))
+	stdCipher.NewCBCDecrypter(sm4.NewCipher(key), iv).CryptBlocks(pt, ct)
+	d := cipher.NewPKCS7UnPadding(pt)
+	if len(d) != scalarSize {
+		return nil, nil, IncorrectPasswordError{}
+	}
+	pri, ok := newPrivateKey(d)
+	if !ok {
+		return nil, nil, IncorrectPasswordError{}
+	}
+	if !equalPublicKeys(&pri.PublicKey, cert.PublicKey) {
+		return nil, nil, KeyMismatchError{}
+	}
+	return newKeyPair(pri), cert, nil
+}
+
+// MarshalSM2 builds a DER encoded CFCA ".sm2" key pair file holding the private
+// key of kp encrypted with password and the matching certificate.
+// Encode the result with base64 to obtain the text form CFCA tools emit.
+func MarshalSM2(password []byte, kp *keypair.Sm2KeyPair, cert *gmtls.Certificate) ([]byte, error) {
+	pri, err := parsePrivateKey(kp)
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil || !equalPublicKeys(&pri.PublicKey, cert.PublicKey) {
+		return nil, KeyMismatchError{}
+	}
+
+	pt := cipher.NewPKCS7Padding(pri.D.FillBytes(make([]byte, scalarSize)), sm4.BlockSize)
+	key, iv := passwordKey(password)
+	ct := make([]byte, len(pt))
+	stdCipher.NewCBCEncrypter(sm4.NewCipher(key), iv).CryptBlocks(ct, pt)
+
+	der, err := asn1.Marshal(sm2File{
+		Version:      1,
+		EncryptedKey: sm2FileKey{ContentType: OIDData, Algorithm: OIDSM4, EncryptedContent: ct},
+		Certificate:  sm2FileCert{ContentType: OIDData, Content: cert.Raw},
+	})
+	if err != nil {
+		return nil, InvalidDataError{Err: err}
+	}
+	return der, nil
+}
+
+// passwordKey derives the SM4 key and IV protecting a ".sm2" private key,
+// the IV is the first half and the key the second half of KDF(password, 32).
+func passwordKey(password []byte) (key, iv []byte) {
+	ivKey := sm3KDF(password, 2*sm4.BlockSize)
+	return ivKey[sm4.BlockSize:], ivKey[:sm4.BlockSize]
+}
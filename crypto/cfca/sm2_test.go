@@ -0,0 +1,157 @@
+package cfca
+
+import (
+	"encoding/asn1"
+	"encoding/base64"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/gmtls"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSM2File(t *testing.T) {
+	kp, pri := newTestKeyPair(t)
+	cert := newTestCert(t, pri, 7)
+	password := []byte("123456")
+
+	t.Run("round trip der", func(t *testing.T) {
+		der, err := MarshalSM2(password, kp, cert)
+		require.NoError(t, err)
+
+		got, gotCert, err := ParseSM2(password, der)
+		require.NoError(t, err)
+		assert.Equal(t, cert.Raw, gotCert.Raw)
+		gotPri, err := got.ParsePrivateKey()
+		require.NoError(t, err)
+		assert.Equal(t, 0, pri.D.Cmp(gotPri.D))
+	})
+
+	t.Run("round trip base64", func(t *testing.T) {
+		der, err := MarshalSM2(password, kp, cert)
+		require.NoError(t, err)
+		text := base64.StdEncoding.EncodeToString(der)
+		_, gotCert, err := ParseSM2(password, []byte(text))
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), gotCert.SerialNumber.Int64())
+	})
+
+	t.Run("layout", func(t *testing.T) {
+		der, err := MarshalSM2(password, kp, cert)
+		require.NoError(t, err)
+		var file sm2File
+		_, err = asn1.Unmarshal(der, &file)
+		require.NoError(t, err)
+		assert.Equal(t, 1, file.Version)
+		assert.True(t, file.EncryptedKey.ContentType.Equal(OIDData))
+		assert.True(t, file.EncryptedKey.Algorithm.Equal(OIDSM4))
+		assert.Len(t, file.EncryptedKey.EncryptedContent, 48)
+		assert.Equal(t, cert.Raw, file.Certificate.Content)
+	})
+
+	t.Run("incorrect password", func(t *testing.T) {
+		der, err := MarshalSM2(password, kp, cert)
+		require.NoError(t, err)
+		_, _, err = ParseSM2([]byte("654321"), der)
+		assert.Equal(t, IncorrectPasswordError{}, err)
+		assert.Equal(t, "crypto/cfca: incorrect password", err.Error())
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		other, _ := newTestKeyPair(t)
+		_, err := MarshalSM2(password, other, cert)
+		assert.Equal(t, KeyMismatchError{}, err)
+		assert.Equal(t, "crypto/cfca: private key does not match the certificate or public key", err.Error())
+		_, err = MarshalSM2(password, kp, nil)
+		assert.Equal(t, KeyMismatchError{}, err)
+
+		// A file bundling someone else's certificate is rejected on parse.
+		_, otherPri := newTestKeyPair(t)
+		der, err := asn1.Marshal(sm2File{
+			Version:      1,
+			EncryptedKey: encryptedKeyFor(t, password, kp, cert),
+			Certificate:  sm2FileCert{ContentType: OIDData, Content: newTestCert(t, otherPri, 8).Raw},
+		})
+		require.NoError(t, err)
+		_, _, err = ParseSM2(password, der)
+		assert.Equal(t, KeyMismatchError{}, err)
+	})
+
+	t.Run("invalid key pair", func(t *testing.T) {
+		_, err := MarshalSM2(password, nil, cert)
+		assert.IsType(t, InvalidKeyError{}, err)
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		_, _, err := ParseSM2(password, []byte("!!"))
+		assert.IsType(t, InvalidDataError{}, err)
+		_, _, err = ParseSM2(password, []byte{0x30, 0x01})
+		assert.IsType(t, InvalidDataError{}, err)
+
+		der, err := MarshalSM2(password, kp, cert)
+		require.NoError(t, err)
+		_, _, err = ParseSM2(password, append(der, 0x00))
+		assert.IsType(t, InvalidDataError{}, err)
+	})
+
+	t.Run("unsupported types", func(t *testing.T) {
+		key := encryptedKeyFor(t, password, kp, cert)
+		certData := sm2FileCert{ContentType: OIDData, Content: cert.Raw}
+		other := asn1.ObjectIdentifier{1, 2, 3}
+
+		bad := key
+		bad.ContentType = other
+		der, _ := asn1.Marshal(sm2File{Version: 1, EncryptedKey: bad, Certificate: certData})
+		_, _, err := ParseSM2(password, der)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: other}, err)
+		assert.Equal(t, "crypto/cfca: unsupported algorithm or content type 1.2.3", err.Error())
+
+		bad = key
+		bad.Algorithm = other
+		der, _ = asn1.Marshal(sm2File{Version: 1, EncryptedKey: bad, Certificate: certData})
+		_, _, err = ParseSM2(password, der)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: other}, err)
+
+		badCert := certData
+		badCert.ContentType = other
+		der, _ = asn1.Marshal(sm2File{Version: 1, EncryptedKey: key, Certificate: badCert})
+		_, _, err = ParseSM2(password, der)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: other}, err)
+
+		cbc := key
+		cbc.Algorithm = OIDSM4CBC
+		der, _ = asn1.Marshal(sm2File{Version: 1, EncryptedKey: cbc, Certificate: certData})
+		_, _, err = ParseSM2(password, der)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid certificate", func(t *testing.T) {
+		der, _ := asn1.Marshal(sm2File{
+			Version:      1,
+			EncryptedKey: encryptedKeyFor(t, password, kp, cert),
+			Certificate:  sm2FileCert{ContentType: OIDData, Content: []byte{0x30, 0x00}},
+		})
+		_, _, err := ParseSM2(password, der)
+		assert.IsType(t, gmtls.InvalidCertificateError{}, err)
+	})
+
+	t.Run("invalid block size", func(t *testing.T) {
+		key := encryptedKeyFor(t, password, kp, cert)
+		key.EncryptedContent = key.EncryptedContent[:47]
+		der, _ := asn1.Marshal(sm2File{Version: 1, EncryptedKey: key, Certificate: sm2FileCert{ContentType: OIDData, Content: cert.Raw}})
+		_, _, err := ParseSM2(password, der)
+		assert.IsType(t, InvalidDataError{}, err)
+	})
+}
+
+// encryptedKeyFor returns the encrypted key part of a ".sm2" file for kp.
+func encryptedKeyFor(t *testing.T, password []byte, kp *keypair.Sm2KeyPair, cert *gmtls.Certificate) sm2FileKey {
+	t.Helper()
+	der, err := MarshalSM2(password, kp, cert)
+	require.NoError(t, err)
+	var file sm2File
+	_, err = asn1.Unmarshal(der, &file)
+	require.NoError(t, err)
+	return file.EncryptedKey
+}
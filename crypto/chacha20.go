@@ -13,6 +13,13 @@ func (e Encrypter) ByChaCha20(c *cipher.ChaCha20Cipher) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "ChaCha20", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("ChaCha20"); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -35,6 +42,13 @@ func (d Decrypter) ByChaCha20(c *cipher.ChaCha20Cipher) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "ChaCha20", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("ChaCha20"); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -45,7 +59,7 @@ func (d Decrypter) ByChaCha20(c *cipher.ChaCha20Cipher) Decrypter {
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = chacha20.NewStdDecrypter(c).Decrypt(d.src)
+		d.dst, d.Error = d.limit(chacha20.NewStdDecrypter(c).Decrypt(d.src))
 	}
 
 	return d
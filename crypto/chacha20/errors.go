@@ -2,6 +2,8 @@ package chacha20
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the ChaCha20 key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/chacha20: invalid key size %d, must be exactly 32 bytes", k)
 }
 
+// Code returns the stable error code DGL-CHACHA20-001.
+func (k KeySizeError) Code() string {
+	return "DGL-CHACHA20-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/chacha20", "ChaCha20", "key", errcode.FieldKeySize, int(k))
+}
+
 // InvalidNonceSizeError represents an error when the ChaCha20 nonce size is invalid.
 // ChaCha20 nonces must be exactly 12 bytes long.
 // This error occurs when the provided nonce does not meet this size requirement.
@@ -28,6 +40,16 @@ func (e InvalidNonceSizeError) Error() string {
 	return fmt.Sprintf("crypto/chacha20: invalid nonce size %d, must be exactly 12 bytes", e.Size)
 }
 
+// Code returns the stable error code DGL-CHACHA20-002.
+func (e InvalidNonceSizeError) Code() string {
+	return "DGL-CHACHA20-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidNonceSizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/chacha20", "ChaCha20", "", "nonce_size", e.Size)
+}
+
 // EncryptError represents an error when ChaCha20 encryption fails.
 // This error occurs when the underlying ChaCha20 encryption operation fails.
 // The error includes the underlying error for detailed debugging.
@@ -41,6 +63,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/chacha20: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-CHACHA20-003.
+func (e EncryptError) Code() string {
+	return "DGL-CHACHA20-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/chacha20", "ChaCha20", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when ChaCha20 decryption fails.
 // This error occurs when the underlying ChaCha20 decryption operation fails.
 // The error includes the underlying error for detailed debugging.
@@ -54,6 +86,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/chacha20: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-CHACHA20-004.
+func (e DecryptError) Code() string {
+	return "DGL-CHACHA20-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/chacha20", "ChaCha20", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // WriteError represents an error when writing encrypted data fails.
 // This error occurs when writing encrypted data to the underlying writer fails.
 // The error includes the underlying error for detailed debugging.
@@ -67,6 +109,16 @@ func (e WriteError) Error() string {
 	return fmt.Sprintf("crypto/chacha20: failed to write encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-CHACHA20-005.
+func (e WriteError) Code() string {
+	return "DGL-CHACHA20-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WriteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/chacha20", "ChaCha20", "write", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -79,3 +131,13 @@ type ReadError struct {
 func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/chacha20: failed to read encrypted data: %v", e.Err)
 }
+
+// Code returns the stable error code DGL-CHACHA20-006.
+func (e ReadError) Code() string {
+	return "DGL-CHACHA20-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/chacha20", "ChaCha20", "read", errcode.FieldCause, e.Err)
+}
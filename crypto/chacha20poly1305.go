@@ -13,6 +13,13 @@ func (e Encrypter) ByChaCha20Poly1305(c *cipher.ChaCha20Poly1305Cipher) Encrypte
 		return e
 	}
 
+	o := observe(OperationEncrypt, "ChaCha20-Poly1305", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("ChaCha20-Poly1305"); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -35,6 +42,13 @@ func (d Decrypter) ByChaCha20Poly1305(c *cipher.ChaCha20Poly1305Cipher) Decrypte
 		return d
 	}
 
+	o := observe(OperationDecrypt, "ChaCha20-Poly1305", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("ChaCha20-Poly1305"); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -45,7 +59,7 @@ func (d Decrypter) ByChaCha20Poly1305(c *cipher.ChaCha20Poly1305Cipher) Decrypte
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = chacha20poly1305.NewStdDecrypter(c).Decrypt(d.src)
+		d.dst, d.Error = d.limit(chacha20poly1305.NewStdDecrypter(c).Decrypt(d.src))
 	}
 
 	return d
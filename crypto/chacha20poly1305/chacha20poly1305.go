@@ -5,6 +5,7 @@ package chacha20poly1305
 
 import (
 	stdCipher "crypto/cipher"
+	"errors"
 	"io"
 
 	"github.com/dromara/dongle/crypto/cipher"
@@ -62,6 +63,11 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 	}
 
 	dst = aead.Seal(nil, e.cipher.Nonce, src, e.cipher.AAD)
+
+	// Prefix the commitment to the key
+	if e.cipher.KeyCommitment {
+		dst = append(cipher.KeyCommitment(e.cipher.Key, e.cipher.Nonce), dst...)
+	}
 	return dst, nil
 }
 
@@ -110,6 +116,13 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Check the commitment to the key
+	if d.cipher.KeyCommitment {
+		if src, err = cipher.OpenKeyCommitment(src, d.cipher.Key, d.cipher.Nonce); err != nil {
+			return nil, DecryptError{Err: err}
+		}
+	}
+
 	aead, err := chacha20poly1305.New(d.cipher.Key)
 	if err != nil {
 		return nil, DecryptError{Err: err}
@@ -128,7 +141,9 @@ type StreamEncrypter struct {
 	cipher    cipher.ChaCha20Poly1305Cipher // The cipher interface for encryption operations
 	aead      stdCipher.AEAD                // Reused AEAD cipher for better performance
 	chunkSize int                           // Chunk size for streaming operations
-	Error     error                         // Error field for storing encryption errors
+	// Segmented stream, see cipher.SetSegmentSize
+	segments *cipher.SegmentWriter
+	Error    error // Error field for storing encryption errors
 }
 
 // NewStreamEncrypter creates a new streaming ChaCha20-Poly1305 encrypter that writes encrypted data
@@ -136,6 +151,10 @@ type StreamEncrypter struct {
 // and validates the key and nonce lengths for proper ChaCha20-Poly1305 encryption.
 // Each chunk is encrypted independently with authentication for true stream processing.
 // The key must be exactly 32 bytes (256 bits) and nonce must be 12 bytes (96 bits).
+//
+// Ciphers with a segment size, see SetSegmentSize, are encrypted segment by
+// segment with a nonce per segment. Close must then be called to seal the final
+// segment.
 func NewStreamEncrypter(w io.Writer, c *cipher.ChaCha20Poly1305Cipher) io.WriteCloser {
 	e := &StreamEncrypter{
 		writer:    w,
@@ -151,7 +170,13 @@ func NewStreamEncrypter(w io.Writer, c *cipher.ChaCha20Poly1305Cipher) io.WriteC
 		e.Error = InvalidNonceSizeError{Size: len(c.Nonce)}
 		return e
 	}
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
 	e.aead, e.Error = chacha20poly1305.New(c.Key)
+	if e.Error == nil && c.SegmentSize > 0 {
+		e.segments, e.Error = cipher.NewSegmentWriter(w, e.aead, c.Nonce, c.AAD, c.SegmentSize)
+	}
 	return e
 }
 
@@ -167,6 +192,15 @@ func (e *StreamEncrypter) Write(p []byte) (n int, err error) {
 		return 0, nil
 	}
 
+	if e.segments != nil {
+		n, err = e.segments.Write(p)
+		if err != nil {
+			e.Error = WriteError{Err: err}
+			return n, e.Error
+		}
+		return n, nil
+	}
+
 	// Initialize AEAD if not already done (handles direct struct creation)
 	if e.aead == nil {
 		if len(e.cipher.Key) != chacha20poly1305.KeySize {
@@ -198,6 +232,14 @@ func (e *StreamEncrypter) Close() error {
 		return e.Error
 	}
 
+	// Seal the final segment
+	if e.segments != nil {
+		if err := e.segments.Close(); err != nil {
+			e.Error = WriteError{Err: err}
+			return e.Error
+		}
+	}
+
 	if closer, ok := e.writer.(io.Closer); ok {
 		return closer.Close()
 	}
@@ -214,13 +256,22 @@ type StreamDecrypter struct {
 	reader io.Reader                     // Underlying reader for encrypted input
 	cipher cipher.ChaCha20Poly1305Cipher // The cipher interface for decryption operations
 	aead   stdCipher.AEAD                // Reused AEAD cipher for better performance
-	Error  error                         // Error field for storing decryption errors
+	// Segmented stream, see cipher.SetSegmentSize
+	segments *cipher.SegmentReader
+	// Stream released before verification, see cipher.SetReleasePolicy
+	unverified *cipher.UnverifiedReader
+	Error      error // Error field for storing decryption errors
 }
 
 // NewStreamDecrypter creates a new streaming ChaCha20-Poly1305 decrypter that reads encrypted data
 // from the provided io.Reader. The decrypter uses the specified cipher interface
 // and validates the key and nonce lengths for proper ChaCha20-Poly1305 decryption.
 // The key must be exactly 32 bytes (256 bits) and nonce must be 12 bytes (96 bits).
+//
+// Ciphers with a segment size, see SetSegmentSize, are decrypted segment by
+// segment, and only authenticated plaintext is returned. Ciphers without one
+// return plaintext before the tag is verified only under the ReleaseUnverified
+// policy, see SetReleasePolicy.
 func NewStreamDecrypter(r io.Reader, c *cipher.ChaCha20Poly1305Cipher) io.Reader {
 	d := &StreamDecrypter{
 		reader: r,
@@ -236,7 +287,16 @@ func NewStreamDecrypter(r io.Reader, c *cipher.ChaCha20Poly1305Cipher) io.Reader
 		d.Error = InvalidNonceSizeError{Size: len(c.Nonce)}
 		return d
 	}
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
 	d.aead, d.Error = chacha20poly1305.New(c.Key)
+	if d.Error == nil && c.SegmentSize > 0 {
+		d.segments, d.Error = cipher.NewSegmentReader(r, d.aead, c.Nonce, c.AAD, c.SegmentSize)
+	}
+	if d.Error == nil {
+		d.unverified, d.Error = c.NewUnverifiedReader(r, d.aead)
+	}
 	return d
 }
 
@@ -255,6 +315,33 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 		return 0, nil
 	}
 
+	if d.segments != nil {
+		n, err = d.segments.Read(p)
+		var (
+			authErr    cipher.SegmentAuthError
+			segmentErr cipher.InvalidSegmentError
+		)
+		switch {
+		case errors.As(err, &authErr), errors.As(err, &segmentErr):
+			err = AuthenticationError{}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
+	if d.unverified != nil {
+		n, err = d.unverified.Read(p)
+		var authErr cipher.StreamAuthError
+		switch {
+		case errors.As(err, &authErr):
+			err = AuthenticationError{}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
 	// Initialize AEAD if not already done (handles direct struct creation)
 	if d.aead == nil {
 		if len(d.cipher.Key) != chacha20poly1305.KeySize {
@@ -1060,3 +1060,127 @@ func TestDirectErrorBranchManipulation(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestSegmentedStream(t *testing.T) {
+	newCipher := func() *cipher.ChaCha20Poly1305Cipher {
+		c := cipher.NewChaCha20Poly1305Cipher()
+		c.SetKey(key32ChaCha20Poly1305)
+		c.SetNonce(nonce12ChaCha20Poly1305)
+		c.SetAAD(aadChaCha20Poly1305)
+		c.SetSegmentSize(1024)
+		return c
+	}
+	data := bytes.Repeat([]byte("segmented chacha20 stream "), 1000)
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		for chunk := data; len(chunk) > 0; chunk = chunk[min(777, len(chunk)):] {
+			n, err := encrypter.Write(chunk[:min(777, len(chunk))])
+			assert.Nil(t, err)
+			assert.Equal(t, min(777, len(chunk)), n)
+		}
+		assert.Nil(t, encrypter.Close())
+		assert.Len(t, buf.Bytes(), len(data)+26*16)
+
+		decrypted, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(buf.Bytes()), newCipher()))
+		assert.Nil(t, err)
+		assert.Equal(t, data, decrypted)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		assert.Nil(t, encrypter.Close())
+		assert.Len(t, buf.Bytes(), 16)
+
+		decrypted, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(buf.Bytes()), newCipher()))
+		assert.Nil(t, err)
+		assert.Empty(t, decrypted)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		encrypter.Write(data)
+		encrypter.Close()
+
+		sealed := buf.Bytes()
+		sealed[5000] ^= 1
+		_, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed), newCipher()))
+		assert.Equal(t, AuthenticationError{}, err)
+
+		sealed[5000] ^= 1
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed[:len(sealed)-1040]), newCipher()))
+		assert.Equal(t, AuthenticationError{}, err)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		encrypter := NewStreamEncrypter(mock.NewErrorWriteAfterN(0, io.ErrShortWrite), newCipher())
+		_, err := encrypter.Write(data)
+		assert.Equal(t, WriteError{Err: io.ErrShortWrite}, err)
+		assert.Equal(t, WriteError{Err: io.ErrShortWrite}, encrypter.Close())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := io.ReadAll(NewStreamDecrypter(mock.NewErrorFile(io.ErrClosedPipe), newCipher()))
+		assert.Equal(t, ReadError{Err: io.ErrClosedPipe}, err)
+	})
+}
+
+func TestStreamDecrypter_ReleasePolicy(t *testing.T) {
+	newCipher := func(policy cipher.ReleasePolicy) *cipher.ChaCha20Poly1305Cipher {
+		c := cipher.NewChaCha20Poly1305Cipher()
+		c.SetKey(key32ChaCha20Poly1305)
+		c.SetNonce(nonce12ChaCha20Poly1305)
+		c.SetAAD(aadChaCha20Poly1305)
+		c.SetReleasePolicy(policy)
+		return c
+	}
+	sealed, err := NewStdEncrypter(newCipher(cipher.ReleaseVerified)).Encrypt(testdataChaCha20Poly1305)
+	assert.NoError(t, err)
+
+	d := NewStreamDecrypter(mock.NewScriptedReader(bytes.NewReader(sealed)).Then(20).Repeat(), newCipher(cipher.ReleaseUnverified))
+	buf := make([]byte, 64)
+	n, err := d.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, testdataChaCha20Poly1305[:4], buf[:n])
+	rest, err := io.ReadAll(d)
+	assert.NoError(t, err)
+	assert.Equal(t, testdataChaCha20Poly1305, append(buf[:n], rest...))
+
+	forged := append([]byte{}, sealed...)
+	forged[0] ^= 1
+	_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(forged), newCipher(cipher.ReleaseUnverified)))
+	assert.Equal(t, AuthenticationError{}, err)
+
+	_, err = io.ReadAll(NewStreamDecrypter(mock.NewErrorFile(io.ErrClosedPipe), newCipher(cipher.ReleaseUnverified)))
+	assert.IsType(t, ReadError{}, err)
+}
+
+func TestStdEncrypter_KeyCommitment(t *testing.T) {
+	newCipher := func(key []byte) *cipher.ChaCha20Poly1305Cipher {
+		c := cipher.NewChaCha20Poly1305Cipher()
+		c.SetKey(key)
+		c.SetNonce(nonce12ChaCha20Poly1305)
+		c.SetAAD(aadChaCha20Poly1305)
+		c.SetKeyCommitment(true)
+		return c
+	}
+	c := newCipher(key32ChaCha20Poly1305)
+	dst, err := NewStdEncrypter(c).Encrypt(testdataChaCha20Poly1305)
+	assert.NoError(t, err)
+	assert.Equal(t, cipher.KeyCommitment(c.Key, c.Nonce), dst[:cipher.KeyCommitmentSize])
+
+	got, err := NewStdDecrypter(c).Decrypt(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, testdataChaCha20Poly1305, got)
+
+	_, err = NewStdDecrypter(newCipher(bytes.Repeat([]byte{1}, 32))).Decrypt(dst)
+	assert.Equal(t, DecryptError{Err: cipher.KeyCommitmentError{}}, err)
+
+	_, err = NewStreamEncrypter(io.Discard, c).Write(testdataChaCha20Poly1305)
+	assert.Equal(t, cipher.UnsupportedKeyCommitmentError{}, err)
+	_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(dst), c))
+	assert.Equal(t, cipher.UnsupportedKeyCommitmentError{}, err)
+}
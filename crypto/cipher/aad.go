@@ -0,0 +1,80 @@
+package cipher
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// AADFields is a set of named fields, such as a format version, a user ID and
+// a purpose, bound to an AEAD ciphertext as additional authenticated data:
+//
+//	c.SetAADFields(cipher.AADFields{"version": "2", "user": id, "purpose": "backup"})
+//
+// The fields are encoded canonically, so the encrypting and decrypting sides
+// agree on the additional data whatever order they list the fields in, and
+// decryption fails authentication when any name or value differs.
+type AADFields map[string]string
+
+// Bytes returns the canonical encoding of the fields: sorted by name, each
+// written as the uvarint length and bytes of its name followed by those of its
+// value. No two different sets of fields share an encoding.
+func (f AADFields) Bytes() []byte {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var dst []byte
+	for _, name := range names {
+		dst = binary.AppendUvarint(dst, uint64(len(name)))
+		dst = append(dst, name...)
+		dst = binary.AppendUvarint(dst, uint64(len(f[name])))
+		dst = append(dst, f[name]...)
+	}
+	return dst
+}
+
+// ParseAADFields parses additional data encoded by AADFields.Bytes, for
+// example to read the fields back from a stored record before decrypting it.
+// It returns an InvalidAADFieldsError unless aad is a canonical encoding.
+func ParseAADFields(aad []byte) (AADFields, error) {
+	f := AADFields{}
+	var prev string
+	for len(aad) > 0 {
+		name, rest, ok := readAADField(aad)
+		if !ok {
+			return nil, InvalidAADFieldsError{reason: "truncated field name"}
+		}
+		value, rest, ok := readAADField(rest)
+		if !ok {
+			return nil, InvalidAADFieldsError{reason: "truncated field value"}
+		}
+		if len(f) > 0 && name <= prev {
+			return nil, InvalidAADFieldsError{reason: "fields not sorted by name"}
+		}
+		f[name], prev, aad = value, name, rest
+	}
+	return f, nil
+}
+
+// readAADField reads a uvarint length prefixed string from src.
+func readAADField(src []byte) (field string, rest []byte, ok bool) {
+	size, n := binary.Uvarint(src)
+	if n <= 0 || size > uint64(len(src)-n) {
+		return "", nil, false
+	}
+	return string(src[n : n+int(size)]), src[n+int(size):], true
+}
+
+// SetAADFields sets the additional authenticated data (AAD) to the canonical
+// encoding of fields.
+func (c *blockCipher) SetAADFields(fields AADFields) {
+	c.AAD = fields.Bytes()
+}
+
+// SetAADFields sets the additional authenticated data (AAD) to the canonical
+// encoding of fields.
+func (c *ChaCha20Poly1305Cipher) SetAADFields(fields AADFields) {
+	c.AAD = fields.Bytes()
+}
@@ -0,0 +1,86 @@
+package cipher
+
+import (
+	"crypto/aes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAADFields_Bytes(t *testing.T) {
+	t.Run("order independent", func(t *testing.T) {
+		f1 := AADFields{}
+		f1["version"], f1["user"], f1["purpose"] = "2", "alice", "backup"
+		f2 := AADFields{}
+		f2["purpose"], f2["user"], f2["version"] = "backup", "alice", "2"
+		assert.Equal(t, f1.Bytes(), f2.Bytes())
+	})
+
+	t.Run("unambiguous", func(t *testing.T) {
+		assert.NotEqual(t, AADFields{"a": "bc"}.Bytes(), AADFields{"ab": "c"}.Bytes())
+		assert.NotEqual(t, AADFields{"a": "", "b": ""}.Bytes(), AADFields{"a": "\x01b\x00"}.Bytes())
+	})
+
+	t.Run("encoding", func(t *testing.T) {
+		assert.Equal(t, []byte("\x01a\x01x\x02id\x0242"), AADFields{"id": "42", "a": "x"}.Bytes())
+		assert.Empty(t, AADFields{}.Bytes())
+	})
+}
+
+func TestParseAADFields(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		f := AADFields{"version": "2", "user": "alice", "empty": ""}
+		got, err := ParseAADFields(f.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, f, got)
+
+		got, err = ParseAADFields(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, aad := range map[string][]byte{
+			"truncated name":  []byte("\x05ab"),
+			"truncated value": []byte("\x01a\x05x"),
+			"missing value":   []byte("\x01a"),
+			"bad length":      []byte("\xff"),
+			"unsorted":        []byte("\x01b\x00\x01a\x00"),
+			"duplicate":       []byte("\x01a\x00\x01a\x00"),
+		} {
+			_, err := ParseAADFields(aad)
+			assert.IsType(t, InvalidAADFieldsError{}, err, name)
+		}
+		_, err := ParseAADFields([]byte("\x01a"))
+		assert.Contains(t, err.Error(), "truncated field value")
+	})
+}
+
+func TestSetAADFields(t *testing.T) {
+	fields := AADFields{"version": "2", "user": "alice"}
+
+	t.Run("chacha20poly1305", func(t *testing.T) {
+		c := NewChaCha20Poly1305Cipher()
+		c.SetAADFields(fields)
+		assert.Equal(t, fields.Bytes(), c.AAD)
+	})
+
+	t.Run("gcm", func(t *testing.T) {
+		block, _ := aes.NewCipher([]byte("1234567890123456"))
+		newCipher := func(f AADFields) *AesCipher {
+			c := NewAesCipher(GCM)
+			c.SetNonce([]byte("123456789012"))
+			c.SetAADFields(f)
+			return c
+		}
+		dst, err := newCipher(fields).Encrypt([]byte("hello world"), block)
+		assert.NoError(t, err)
+
+		got, err := newCipher(AADFields{"user": "alice", "version": "2"}).Decrypt(dst, block)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), got)
+
+		_, err = newCipher(AADFields{"user": "bob", "version": "2"}).Decrypt(dst, block)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package cipher
+
+import (
+	"crypto/cipher"
+)
+
+// NewAEAD returns block wrapped in the GCM mode configured on the cipher, as a
+// standard cipher.AEAD for protocols written against the stdlib interfaces. The
+// nonce size follows the configured nonce, or the standard 12 bytes when none
+// is set, so that Seal and Open accept c.Nonce as is.
+func (c *blockCipher) NewAEAD(block cipher.Block) (cipher.AEAD, error) {
+	if c.Block != GCM {
+		return nil, UnsupportedBlockModeError{mode: c.Block}
+	}
+	var (
+		aead cipher.AEAD
+		err  error
+	)
+	if len(c.Nonce) == 0 || len(c.Nonce) == 12 {
+		aead, err = cipher.NewGCM(block)
+	} else {
+		aead, err = cipher.NewGCMWithNonceSize(block, len(c.Nonce))
+	}
+	if err != nil {
+		return nil, CreateCipherError{mode: GCM, err: err}
+	}
+	return aead, nil
+}
+
+// NewEncryptStream returns block wrapped in the CTR, CFB or OFB mode configured
+// on the cipher and started from c.IV, as a standard cipher.Stream encrypting
+// the data passed to XORKeyStream.
+func (c *blockCipher) NewEncryptStream(block cipher.Block) (cipher.Stream, error) {
+	return c.newStream(block, false)
+}
+
+// NewDecryptStream returns block wrapped in the CTR, CFB or OFB mode configured
+// on the cipher and started from c.IV, as a standard cipher.Stream decrypting
+// the data passed to XORKeyStream. Only CFB differs from NewEncryptStream.
+func (c *blockCipher) NewDecryptStream(block cipher.Block) (cipher.Stream, error) {
+	return c.newStream(block, true)
+}
+
+// newStream returns the stream mode configured on the cipher.
+func (c *blockCipher) newStream(block cipher.Block, decrypt bool) (cipher.Stream, error) {
+	switch c.Block {
+	case CTR, CFB, OFB:
+	default:
+		return nil, UnsupportedBlockModeError{mode: c.Block}
+	}
+	if len(c.IV) == 0 {
+		return nil, EmptyIVError{mode: c.Block}
+	}
+	if size := block.BlockSize(); len(c.IV) != size {
+		return nil, InvalidIVError{mode: c.Block, iv: c.IV, size: size}
+	}
+	switch {
+	case c.Block == CTR:
+		return cipher.NewCTR(block, c.IV), nil
+	case c.Block == OFB:
+		return cipher.NewOFB(block, c.IV), nil
+	case decrypt:
+		return cipher.NewCFBDecrypter(block, c.IV), nil
+	default:
+		return cipher.NewCFBEncrypter(block, c.IV), nil
+	}
+}
@@ -0,0 +1,100 @@
+package cipher
+
+import (
+	"crypto/aes"
+	"crypto/des"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockCipher_NewAEAD(t *testing.T) {
+	block, _ := aes.NewCipher([]byte("1234567890123456"))
+
+	t.Run("matches Encrypt", func(t *testing.T) {
+		for _, nonce := range [][]byte{[]byte("123456789012"), []byte("1234567890123456")} {
+			c := NewAesCipher(GCM)
+			c.SetNonce(nonce)
+			c.SetAAD(testAAD)
+			aead, err := c.NewAEAD(block)
+			assert.NoError(t, err)
+			assert.Equal(t, len(nonce), aead.NonceSize())
+
+			want, err := c.Encrypt(testData, block)
+			assert.NoError(t, err)
+			assert.Equal(t, want, aead.Seal(nil, nonce, testData, testAAD))
+
+			got, err := aead.Open(nil, nonce, want, testAAD)
+			assert.NoError(t, err)
+			assert.Equal(t, testData, got)
+		}
+	})
+
+	t.Run("default nonce size", func(t *testing.T) {
+		aead, err := NewAesCipher(GCM).NewAEAD(block)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, aead.NonceSize())
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		aead, err := NewAesCipher(CBC).NewAEAD(block)
+		assert.Nil(t, aead)
+		assert.IsType(t, UnsupportedBlockModeError{}, err)
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		// GCM requires a 16-byte block
+		block, _ := des.NewCipher([]byte("12345678"))
+		aead, err := NewDesCipher(GCM).NewAEAD(block)
+		assert.Nil(t, aead)
+		assert.IsType(t, CreateCipherError{}, err)
+	})
+}
+
+func TestBlockCipher_NewStream(t *testing.T) {
+	block, _ := aes.NewCipher([]byte("1234567890123456"))
+
+	t.Run("matches Encrypt and Decrypt", func(t *testing.T) {
+		for _, mode := range []BlockMode{CTR, CFB, OFB} {
+			c := NewAesCipher(mode)
+			c.SetIV(testIV)
+			want, err := c.Encrypt(testData, block)
+			assert.NoError(t, err)
+
+			enc, err := c.NewEncryptStream(block)
+			assert.NoError(t, err)
+			got := make([]byte, len(testData))
+			enc.XORKeyStream(got, testData)
+			assert.Equal(t, want, got, mode)
+
+			dec, err := c.NewDecryptStream(block)
+			assert.NoError(t, err)
+			dec.XORKeyStream(got, want)
+			assert.Equal(t, testData, got, mode)
+		}
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		for _, mode := range []BlockMode{CBC, ECB, GCM} {
+			c := NewAesCipher(mode)
+			c.SetIV(testIV)
+			stream, err := c.NewEncryptStream(block)
+			assert.Nil(t, stream)
+			assert.IsType(t, UnsupportedBlockModeError{}, err)
+		}
+	})
+
+	t.Run("empty iv", func(t *testing.T) {
+		stream, err := NewAesCipher(CTR).NewDecryptStream(block)
+		assert.Nil(t, stream)
+		assert.IsType(t, EmptyIVError{}, err)
+	})
+
+	t.Run("invalid iv", func(t *testing.T) {
+		c := NewAesCipher(OFB)
+		c.SetIV(testIV8)
+		stream, err := c.NewEncryptStream(block)
+		assert.Nil(t, stream)
+		assert.IsType(t, InvalidIVError{}, err)
+	})
+}
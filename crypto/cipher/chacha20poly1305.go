@@ -3,8 +3,11 @@ package cipher
 // ChaCha20Poly1305Cipher defines a ChaCha20Poly1305Cipher struct.
 type ChaCha20Poly1305Cipher struct {
 	baseCipher
-	Nonce []byte
-	AAD   []byte
+	Nonce         []byte
+	AAD           []byte
+	SegmentSize   int
+	Release       ReleasePolicy
+	KeyCommitment bool
 }
 
 // NewChaCha20Poly1305Cipher returns a new ChaCha20Poly1305Cipher instance.
@@ -21,3 +24,13 @@ func (c *ChaCha20Poly1305Cipher) SetNonce(nonce []byte) {
 func (c *ChaCha20Poly1305Cipher) SetAAD(aad []byte) {
 	c.AAD = aad
 }
+
+// SetSegmentSize makes the stream encrypters seal the stream in segments of
+// size plaintext bytes, in the same format as the segmented GCM streams of the
+// block ciphers, so that streams of any size are encrypted and decrypted with a
+// constant amount of memory. Without a segment size every Write is sealed
+// under the same nonce, so only streams written in a single Write can be
+// decrypted. Zero disables segments.
+func (c *ChaCha20Poly1305Cipher) SetSegmentSize(size int) {
+	c.SegmentSize = size
+}
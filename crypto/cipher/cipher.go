@@ -3,7 +3,11 @@
 // padding modes, and streaming capabilities for secure data encryption and decryption.
 package cipher
 
-import "crypto/cipher"
+import (
+	"crypto/cipher"
+
+	"github.com/dromara/dongle/crypto/padding"
+)
 
 type baseCipher struct {
 	Key []byte
@@ -16,11 +20,16 @@ func (c *baseCipher) SetKey(key []byte) {
 
 type blockCipher struct {
 	baseCipher
-	IV      []byte
-	Nonce   []byte
-	AAD     []byte
-	Block   BlockMode
-	Padding PaddingMode
+	IV                 []byte
+	Nonce              []byte
+	AAD                []byte
+	Block              BlockMode
+	Padding            PaddingMode
+	MessageKey         bool
+	DeterministicNonce bool
+	KeyCommitment      bool
+	SegmentSize        int
+	Release            ReleasePolicy
 }
 
 // SetPadding sets the padding mode for the cipher.
@@ -43,6 +52,35 @@ func (c *blockCipher) SetAAD(aad []byte) {
 	c.AAD = aad
 }
 
+// CheckStreamOptions returns an error if the cipher enables an option that
+// stream encrypters and decrypters cannot honor. Message keys, deterministic
+// nonces and key commitments need a per-call header, which streams do not have.
+func (c *blockCipher) CheckStreamOptions() error {
+	if c.MessageKey {
+		return UnsupportedMessageKeyError{}
+	}
+	if c.DeterministicNonce {
+		return UnsupportedDeterministicNonceError{}
+	}
+	if c.KeyCommitment {
+		return UnsupportedKeyCommitmentError{}
+	}
+	return nil
+}
+
+// CheckGCMOptions returns an error if the cipher enables an option that only
+// GCM supports while using another block mode. Ciphers whose block size rules
+// out GCM call it to reject such options instead of ignoring them.
+func (c *blockCipher) CheckGCMOptions() error {
+	if c.DeterministicNonce && c.Block != GCM {
+		return c.checkDeterministicNonce()
+	}
+	if c.KeyCommitment && c.Block != GCM {
+		return UnsupportedKeyCommitmentError{mode: c.Block}
+	}
+	return nil
+}
+
 // Encrypt encrypts the source data using the specified cipher.
 func (c *blockCipher) Encrypt(src []byte, block cipher.Block) (dst []byte, err error) {
 	if len(src) == 0 {
@@ -97,61 +135,23 @@ func (c *blockCipher) Decrypt(src []byte, block cipher.Block) (dst []byte, err e
 	if err != nil {
 		return
 	}
-	return c.unpadding(dst)
+	return c.unpadding(dst, block.BlockSize())
 }
 
 // padding adds padding to the source data.
 func (c *blockCipher) padding(src []byte, blockSize int) (dst []byte, err error) {
-	switch c.Padding {
-	case No:
-		return NewNoPadding(src), nil
-	case Zero:
-		return NewZeroPadding(src, blockSize), nil
-	case PKCS5:
-		return NewPKCS5Padding(src), nil
-	case PKCS7:
-		return NewPKCS7Padding(src, blockSize), nil
-	case AnsiX923:
-		return NewAnsiX923Padding(src, blockSize), nil
-	case ISO97971:
-		return NewISO97971Padding(src, blockSize), nil
-	case ISO10126:
-		return NewISO10126Padding(src, blockSize), nil
-	case ISO78164:
-		return NewISO78164Padding(src, blockSize), nil
-	case Bit:
-		return NewBitPadding(src, blockSize), nil
-	case TBC:
-		return NewTBCPadding(src, blockSize), nil
-	default:
-		return dst, UnsupportedPaddingModeError{mode: c.Padding}
+	dst, err = padding.Pad(c.Padding, src, blockSize)
+	if _, ok := err.(padding.UnsupportedModeError); ok {
+		err = UnsupportedPaddingModeError{mode: c.Padding}
 	}
+	return
 }
 
 // unpadding removes padding from the source data.
-func (c *blockCipher) unpadding(src []byte) (dst []byte, err error) {
-	switch c.Padding {
-	case No:
-		return NewNoUnPadding(src), nil
-	case Zero:
-		return NewZeroUnPadding(src), nil
-	case PKCS5:
-		return NewPKCS5UnPadding(src), nil
-	case PKCS7:
-		return NewPKCS7UnPadding(src), nil
-	case AnsiX923:
-		return NewAnsiX923UnPadding(src), nil
-	case ISO97971:
-		return NewISO97971UnPadding(src), nil
-	case ISO10126:
-		return NewISO10126UnPadding(src), nil
-	case ISO78164:
-		return NewISO78164UnPadding(src), nil
-	case Bit:
-		return NewBitUnPadding(src), nil
-	case TBC:
-		return NewTBCUnPadding(src), nil
-	default:
-		return dst, UnsupportedPaddingModeError{mode: c.Padding}
+func (c *blockCipher) unpadding(src []byte, blockSize int) (dst []byte, err error) {
+	dst, err = padding.Unpad(c.Padding, src, blockSize)
+	if _, ok := err.(padding.UnsupportedModeError); ok {
+		err = UnsupportedPaddingModeError{mode: c.Padding}
 	}
+	return
 }
@@ -1,8 +1,10 @@
 package cipher
 
 import (
+	"crypto/aes"
 	"testing"
 
+	"github.com/dromara/dongle/crypto/padding"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -120,6 +122,52 @@ func TestBlockCipher_SetAAD(t *testing.T) {
 	})
 }
 
+func TestBlockCipher_CheckStreamOptions(t *testing.T) {
+	t.Run("no options", func(t *testing.T) {
+		cipher := &blockCipher{}
+		assert.NoError(t, cipher.CheckStreamOptions())
+	})
+
+	t.Run("message key", func(t *testing.T) {
+		cipher := &blockCipher{}
+		cipher.SetMessageKey(true)
+		assert.Equal(t, UnsupportedMessageKeyError{}, cipher.CheckStreamOptions())
+	})
+
+	t.Run("deterministic nonce", func(t *testing.T) {
+		cipher := &blockCipher{}
+		cipher.SetDeterministicNonce(true)
+		assert.Equal(t, UnsupportedDeterministicNonceError{}, cipher.CheckStreamOptions())
+	})
+
+	t.Run("key commitment", func(t *testing.T) {
+		cipher := &blockCipher{}
+		cipher.SetKeyCommitment(true)
+		assert.Equal(t, UnsupportedKeyCommitmentError{}, cipher.CheckStreamOptions())
+	})
+}
+
+func TestBlockCipher_CheckGCMOptions(t *testing.T) {
+	t.Run("GCM", func(t *testing.T) {
+		cipher := &blockCipher{Block: GCM}
+		cipher.SetDeterministicNonce(true)
+		cipher.SetKeyCommitment(true)
+		assert.NoError(t, cipher.CheckGCMOptions())
+	})
+
+	t.Run("deterministic nonce without GCM", func(t *testing.T) {
+		cipher := &blockCipher{Block: CBC}
+		cipher.SetDeterministicNonce(true)
+		assert.IsType(t, UnsupportedDeterministicNonceError{}, cipher.CheckGCMOptions())
+	})
+
+	t.Run("key commitment without GCM", func(t *testing.T) {
+		cipher := &blockCipher{Block: CBC}
+		cipher.SetKeyCommitment(true)
+		assert.Equal(t, UnsupportedKeyCommitmentError{mode: CBC}, cipher.CheckGCMOptions())
+	})
+}
+
 func TestBlockCipher_Encrypt(t *testing.T) {
 	t.Run("encrypt with different modes", func(t *testing.T) {
 		modes := []BlockMode{CBC, ECB, CTR, CFB, OFB}
@@ -1205,7 +1253,7 @@ func TestBlockCipher_UnPadding(t *testing.T) {
 		for _, mode := range paddingModes {
 			t.Run(string(mode), func(t *testing.T) {
 				cipher.Padding = mode
-				result, err := cipher.unpadding(testData)
+				result, err := cipher.unpadding(testData, 16)
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 			})
@@ -1218,9 +1266,28 @@ func TestBlockCipher_UnPadding(t *testing.T) {
 		}
 		testData := []byte("test data")
 
-		result, err := cipher.unpadding(testData)
+		result, err := cipher.unpadding(testData, 16)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.IsType(t, UnsupportedPaddingModeError{}, err)
 	})
 }
+
+func TestBlockCipher_PKCS5WithWideBlocks(t *testing.T) {
+	block, _ := aes.NewCipher([]byte("1234567890123456"))
+	c := NewAesCipher(CBC)
+	c.SetIV(testIV)
+	c.SetPadding(PKCS5)
+
+	// A whole block of input is padded with a whole 16-byte block, not 8 bytes.
+	dst, err := c.Encrypt(testData16, block)
+	assert.NoError(t, err)
+	assert.Len(t, dst, 32)
+	src, err := c.Decrypt(dst, block)
+	assert.NoError(t, err)
+	assert.Equal(t, testData16, src)
+
+	c.SetPadding(PKCS5Strict)
+	_, err = c.Encrypt(testData16, block)
+	assert.IsType(t, padding.BlockSizeError{}, err)
+}
@@ -0,0 +1,103 @@
+package cipher
+
+import (
+	"crypto/sha256"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// KeyCommitmentSize is the size of the key commitment prefixed to every
+// ciphertext made with key commitment.
+const KeyCommitmentSize = sha256.Size
+
+// keyCommitmentLabel separates key commitments from other SHA-256 digests.
+const keyCommitmentLabel = "dongle/cipher/key-commitment"
+
+// KeyCommitment returns the commitment to key and nonce, the SHA-256 digest of
+// a label, the length prefixed key and the nonce. Finding two keys with the
+// same commitment means finding a SHA-256 collision, so a ciphertext carrying
+// it decrypts under one key only. The nonce keeps the commitments of messages
+// under one key from being linked.
+func KeyCommitment(key, nonce []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(keyCommitmentLabel))
+	h.Write([]byte{byte(len(key))})
+	h.Write(key)
+	h.Write(nonce)
+	return h.Sum(nil)
+}
+
+// OpenKeyCommitment splits the key commitment off src, checks it against key
+// and nonce in constant time and returns the ciphertext that follows.
+func OpenKeyCommitment(src, key, nonce []byte) ([]byte, error) {
+	if len(src) < KeyCommitmentSize {
+		return nil, KeyCommitmentError{}
+	}
+	if !utils.ConstantTimeEqual(src[:KeyCommitmentSize], KeyCommitment(key, nonce)) {
+		return nil, KeyCommitmentError{}
+	}
+	return src[KeyCommitmentSize:], nil
+}
+
+// SetKeyCommitment enables key commitment for GCM. Each encryption then
+// prefixes the ciphertext with KeyCommitment(c.Key, c.Nonce), and decryption
+// rejects ciphertexts whose commitment does not match the key before
+// decrypting them. GCM alone does not commit to its key: a ciphertext can be
+// crafted to decrypt and authenticate under two different keys, which lets a
+// sender show different plaintexts to different recipients, as in the
+// invisible salamanders attack on multi-tenant systems. Key commitment costs
+// KeyCommitmentSize bytes per ciphertext and is supported by the standard
+// encrypters in GCM mode only.
+func (c *blockCipher) SetKeyCommitment(enabled bool) {
+	c.KeyCommitment = enabled
+}
+
+// CommitKey prefixes dst, a ciphertext made under c.Key, with its key
+// commitment.
+func (c *blockCipher) CommitKey(dst []byte) ([]byte, error) {
+	if c.Block != GCM {
+		return nil, UnsupportedKeyCommitmentError{mode: c.Block}
+	}
+	return append(KeyCommitment(c.Key, c.Nonce), dst...), nil
+}
+
+// OpenKeyCommitment splits the key commitment off src, checks it against c.Key
+// and returns the ciphertext that follows.
+func (c *blockCipher) OpenKeyCommitment(src []byte) ([]byte, error) {
+	if c.Block != GCM {
+		return nil, UnsupportedKeyCommitmentError{mode: c.Block}
+	}
+	return OpenKeyCommitment(src, c.Key, c.Nonce)
+}
+
+// SetKeyCommitment enables key commitment as for AesCipher.
+// ChaCha20-Poly1305 does not commit to its key either. It is supported by the
+// standard encrypters only.
+func (c *ChaCha20Poly1305Cipher) SetKeyCommitment(enabled bool) {
+	c.KeyCommitment = enabled
+}
+
+// CheckStreamOptions returns an error if the cipher enables an option that
+// stream encrypters and decrypters cannot honor, as for AesCipher.
+func (c *ChaCha20Poly1305Cipher) CheckStreamOptions() error {
+	if c.KeyCommitment {
+		return UnsupportedKeyCommitmentError{}
+	}
+	return nil
+}
+
+// SetKeyCommitment enables key commitment as for AesCipher.
+// XChaCha20-Poly1305 does not commit to its key either. It is supported by the
+// standard encrypters only.
+func (c *XChaCha20Poly1305Cipher) SetKeyCommitment(enabled bool) {
+	c.KeyCommitment = enabled
+}
+
+// CheckStreamOptions returns an error if the cipher enables an option that
+// stream encrypters and decrypters cannot honor, as for AesCipher.
+func (c *XChaCha20Poly1305Cipher) CheckStreamOptions() error {
+	if c.KeyCommitment {
+		return UnsupportedKeyCommitmentError{}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCommitment(t *testing.T) {
+	key := []byte("1234567890123456")
+	nonce := []byte("123456789012")
+
+	commitment := KeyCommitment(key, nonce)
+	assert.Len(t, commitment, KeyCommitmentSize)
+	assert.Equal(t, commitment, KeyCommitment(key, nonce))
+	assert.NotEqual(t, commitment, KeyCommitment([]byte("6543210987654321"), nonce))
+	assert.NotEqual(t, commitment, KeyCommitment(key, []byte("210987654321")))
+	// The length prefix keeps a longer key from sharing the input
+	assert.NotEqual(t, KeyCommitment(key, append(bytes.Repeat([]byte{0}, 16), nonce...)), KeyCommitment(append(append([]byte{}, key...), make([]byte, 16)...), nonce))
+
+	src := append(append([]byte{}, commitment...), "ciphertext"...)
+	got, err := OpenKeyCommitment(src, key, nonce)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ciphertext"), got)
+
+	_, err = OpenKeyCommitment(src, []byte("6543210987654321"), nonce)
+	assert.Equal(t, KeyCommitmentError{}, err)
+	_, err = OpenKeyCommitment(src[:KeyCommitmentSize-1], key, nonce)
+	assert.Equal(t, KeyCommitmentError{}, err)
+	assert.Equal(t, "DGL-CIPHER-020", KeyCommitmentError{}.Code())
+	assert.NotEmpty(t, KeyCommitmentError{}.Fields())
+}
+
+func TestBlockCipher_KeyCommitment(t *testing.T) {
+	c := NewAesCipher(GCM)
+	c.SetKey([]byte("1234567890123456"))
+	c.SetNonce([]byte("123456789012"))
+	c.SetKeyCommitment(true)
+	assert.True(t, c.KeyCommitment)
+	block, _ := aes.NewCipher(c.Key)
+
+	sealed, err := c.Encrypt([]byte("hello world"), block)
+	require.NoError(t, err)
+	dst, err := c.CommitKey(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, KeyCommitment(c.Key, c.Nonce), dst[:KeyCommitmentSize])
+
+	src, err := c.OpenKeyCommitment(dst)
+	require.NoError(t, err)
+	assert.Equal(t, sealed, src)
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		cbc := NewAesCipher(CBC)
+		cbc.SetKey(c.Key)
+		_, err := cbc.CommitKey(sealed)
+		assert.Equal(t, UnsupportedKeyCommitmentError{mode: CBC}, err)
+		assert.Contains(t, err.Error(), "CBC")
+		_, err = cbc.OpenKeyCommitment(dst)
+		assert.Equal(t, UnsupportedKeyCommitmentError{mode: CBC}, err)
+
+		assert.Contains(t, UnsupportedKeyCommitmentError{}.Error(), "streaming")
+		assert.Equal(t, "DGL-CIPHER-019", UnsupportedKeyCommitmentError{}.Code())
+		assert.NotEmpty(t, UnsupportedKeyCommitmentError{}.Fields())
+	})
+
+	chacha := NewChaCha20Poly1305Cipher()
+	chacha.SetKeyCommitment(true)
+	assert.True(t, chacha.KeyCommitment)
+	xchacha := NewXChaCha20Poly1305Cipher()
+	xchacha.SetKeyCommitment(true)
+	assert.True(t, xchacha.KeyCommitment)
+}
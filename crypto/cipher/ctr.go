@@ -0,0 +1,99 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// ctrStateMagic identifies serialized CTR stream states.
+const ctrStateMagic = "dgc\x01"
+
+// ctrStateSize is the size of a serialized CTR stream state: the magic, the
+// offset and the check value.
+const ctrStateSize = len(ctrStateMagic) + 8 + 8
+
+// CTRStream is a CTR mode key stream that tracks its offset, so that the
+// encryption of a large upload can be checkpointed with MarshalBinary and
+// resumed later with UnmarshalBinary without re-encrypting from byte zero.
+// It implements cipher.Stream.
+type CTRStream struct {
+	block  cipher.Block
+	iv     []byte
+	offset uint64
+	stream cipher.Stream
+}
+
+// NewCTRStream returns a CTRStream for block with the initial counter iv.
+func NewCTRStream(block cipher.Block, iv []byte) (*CTRStream, error) {
+	if len(iv) == 0 {
+		return nil, EmptyIVError{mode: CTR}
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, InvalidIVError{mode: CTR, iv: iv, size: block.BlockSize()}
+	}
+	s := &CTRStream{block: block, iv: append([]byte{}, iv...)}
+	s.Seek(0)
+	return s, nil
+}
+
+// XORKeyStream XORs each byte in src with the key stream at the current offset
+// and advances the offset.
+func (s *CTRStream) XORKeyStream(dst, src []byte) {
+	s.stream.XORKeyStream(dst, src)
+	s.offset += uint64(len(src))
+}
+
+// Offset returns the number of key stream bytes consumed.
+func (s *CTRStream) Offset() uint64 {
+	return s.offset
+}
+
+// Seek positions the key stream at offset bytes from the start.
+func (s *CTRStream) Seek(offset uint64) {
+	size := uint64(s.block.BlockSize())
+	counter := append([]byte{}, s.iv...)
+	// Add the number of whole blocks to the big-endian counter.
+	carry := offset / size
+	for i := len(counter) - 1; i >= 0 && carry > 0; i-- {
+		sum := uint64(counter[i]) + carry&0xff
+		counter[i] = byte(sum)
+		carry = carry>>8 + sum>>8
+	}
+	s.stream = cipher.NewCTR(s.block, counter)
+	if skip := offset % size; skip > 0 {
+		discard := make([]byte, skip)
+		s.stream.XORKeyStream(discard, discard)
+	}
+	s.offset = offset
+}
+
+// MarshalBinary returns the state of the stream: its offset and a check value
+// that lets UnmarshalBinary detect a different key or IV. The key and IV are
+// not included and must be supplied again when resuming.
+func (s *CTRStream) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, ctrStateSize)
+	b = append(b, ctrStateMagic...)
+	b = binary.BigEndian.AppendUint64(b, s.offset)
+	return append(b, s.check()...), nil
+}
+
+// UnmarshalBinary restores a state saved with MarshalBinary by a stream with
+// the same key and IV, positioning the stream at the saved offset.
+func (s *CTRStream) UnmarshalBinary(data []byte) error {
+	if len(data) != ctrStateSize || string(data[:len(ctrStateMagic)]) != ctrStateMagic ||
+		string(data[len(ctrStateMagic)+8:]) != string(s.check()) {
+		return InvalidStreamStateError{mode: CTR}
+	}
+	s.Seek(binary.BigEndian.Uint64(data[len(ctrStateMagic):]))
+	return nil
+}
+
+// check returns a check value of the key and IV: the truncated hash of the
+// first key stream block, which reveals neither.
+func (s *CTRStream) check() []byte {
+	first := make([]byte, s.block.BlockSize())
+	s.block.Encrypt(first, s.iv)
+	sum := sha256.Sum256(first)
+	return sum[:8]
+}
@@ -0,0 +1,70 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	stdCipher "crypto/cipher"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCTRStream(t *testing.T) {
+	block, _ := aes.NewCipher([]byte("1234567890123456"))
+	iv := bytes.Repeat([]byte{0xff}, 16) // exercises the counter carry
+	plaintext := bytes.Repeat([]byte("0123456789abcdef-"), 100)
+	want := make([]byte, len(plaintext))
+	stdCipher.NewCTR(block, iv).XORKeyStream(want, plaintext)
+
+	t.Run("matches ctr", func(t *testing.T) {
+		s, err := NewCTRStream(block, iv)
+		require.NoError(t, err)
+		got := make([]byte, len(plaintext))
+		s.XORKeyStream(got[:7], plaintext[:7])
+		s.XORKeyStream(got[7:], plaintext[7:])
+		assert.Equal(t, want, got)
+		assert.Equal(t, uint64(len(plaintext)), s.Offset())
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		s, _ := NewCTRStream(block, iv)
+		for _, offset := range []uint64{0, 1, 15, 16, 17, 300, 1000} {
+			s.Seek(offset)
+			got := make([]byte, len(plaintext)-int(offset))
+			s.XORKeyStream(got, plaintext[offset:])
+			assert.Equal(t, want[offset:], got, "offset %d", offset)
+		}
+	})
+
+	t.Run("resume from state", func(t *testing.T) {
+		s, _ := NewCTRStream(block, iv)
+		got := make([]byte, len(plaintext))
+		s.XORKeyStream(got[:333], plaintext[:333])
+		state, err := s.MarshalBinary()
+		require.NoError(t, err)
+
+		resumed, _ := NewCTRStream(block, iv)
+		require.NoError(t, resumed.UnmarshalBinary(state))
+		assert.Equal(t, uint64(333), resumed.Offset())
+		resumed.XORKeyStream(got[333:], plaintext[333:])
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("mismatched state", func(t *testing.T) {
+		s, _ := NewCTRStream(block, iv)
+		state, _ := s.MarshalBinary()
+		otherIV, _ := NewCTRStream(block, make([]byte, 16))
+		err := otherIV.UnmarshalBinary(state)
+		assert.Equal(t, InvalidStreamStateError{mode: CTR}, err)
+		assert.Equal(t, "invalid or mismatched stream state in 'CTR' block mode", err.Error())
+		assert.Error(t, s.UnmarshalBinary(state[:10]))
+	})
+
+	t.Run("invalid iv", func(t *testing.T) {
+		_, err := NewCTRStream(block, nil)
+		assert.Equal(t, EmptyIVError{mode: CTR}, err)
+		_, err = NewCTRStream(block, []byte("short"))
+		assert.IsType(t, InvalidIVError{}, err)
+	})
+}
@@ -0,0 +1,107 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/hkdf"
+)
+
+// DeterministicNonceSize is the size of the derived nonce prefixed to every
+// ciphertext made with deterministic nonces.
+const DeterministicNonceSize = 12
+
+// deterministicNonceInfo binds the nonce key to its use.
+const deterministicNonceInfo = "dongle/cipher/deterministic-nonce"
+
+// SetDeterministicNonce enables deterministic nonces for GCM. Each encryption
+// then derives the nonce as HMAC-SHA256 over the AAD and the plaintext, under
+// a key derived from c.Key with HKDF-SHA256, truncates it to 12 bytes and
+// prefixes it to the ciphertext in place of any nonce set with SetNonce.
+// Encrypting the same plaintext and AAD under the same key twice yields the
+// same ciphertext, which suits deduplication and idempotent writes.
+//
+// The trade-offs are deliberate: an observer learns when two ciphertexts hold
+// the same plaintext and AAD, and the 96-bit nonce is a truncated MAC rather
+// than the full synthetic IV of AES-GCM-SIV, so the usual GCM limits on the
+// number of messages per key still apply. Leave it disabled unless identical
+// ciphertexts are the goal. Decryption reads the nonce back and checks that it
+// matches the recovered plaintext. Deterministic nonces are supported by the
+// standard encrypters in GCM mode only and cannot be combined with message
+// keys.
+func (c *blockCipher) SetDeterministicNonce(enabled bool) {
+	c.DeterministicNonce = enabled
+}
+
+// DeriveNonce derives the deterministic nonce of src under c.Key and c.AAD.
+func (c *blockCipher) DeriveNonce(src []byte) ([]byte, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, c.Key, nil, []byte(deterministicNonceInfo)), key); err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	// Length prefix the AAD so that no AAD and plaintext pair shares its input
+	_ = binary.Write(mac, binary.BigEndian, uint64(len(c.AAD)))
+	mac.Write(c.AAD)
+	mac.Write(src)
+	return mac.Sum(nil)[:DeterministicNonceSize], nil
+}
+
+// EncryptWithDeterministicNonce encrypts src as Encrypt does, under the nonce
+// derived from src, and returns the nonce followed by the ciphertext.
+func (c *blockCipher) EncryptWithDeterministicNonce(src []byte, block cipher.Block) (dst []byte, err error) {
+	if err = c.checkDeterministicNonce(); err != nil {
+		return
+	}
+	nonce, err := c.DeriveNonce(src)
+	if err != nil {
+		return
+	}
+	nc := *c
+	nc.Nonce = nonce
+	ciphertext, err := nc.Encrypt(src, block)
+	if err != nil {
+		return
+	}
+	return append(nonce, ciphertext...), nil
+}
+
+// DecryptWithDeterministicNonce splits the nonce off src, decrypts the rest as
+// Decrypt does and checks the nonce against the recovered plaintext.
+func (c *blockCipher) DecryptWithDeterministicNonce(src []byte, block cipher.Block) (dst []byte, err error) {
+	if err = c.checkDeterministicNonce(); err != nil {
+		return
+	}
+	if len(src) <= DeterministicNonceSize {
+		return nil, InvalidDeterministicNonceError{reason: "ciphertext is too short to hold the nonce"}
+	}
+	nc := *c
+	nc.Nonce = src[:DeterministicNonceSize]
+	if dst, err = nc.Decrypt(src[DeterministicNonceSize:], block); err != nil {
+		return nil, err
+	}
+	nonce, err := c.DeriveNonce(dst)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.ConstantTimeEqual(nonce, nc.Nonce) {
+		return nil, InvalidDeterministicNonceError{reason: "nonce does not match the plaintext"}
+	}
+	return dst, nil
+}
+
+// checkDeterministicNonce reports whether the cipher can use deterministic
+// nonces.
+func (c *blockCipher) checkDeterministicNonce() error {
+	if c.Block != GCM {
+		return UnsupportedDeterministicNonceError{reason: "block mode " + string(c.Block) + " is not GCM"}
+	}
+	if c.MessageKey {
+		return UnsupportedDeterministicNonceError{reason: "message keys are enabled"}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cipher
+
+import (
+	"crypto/aes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockCipher_DeterministicNonce(t *testing.T) {
+	newCipher := func() *AesCipher {
+		c := NewAesCipher(GCM)
+		c.SetKey([]byte("1234567890123456"))
+		c.SetAAD([]byte("record-1"))
+		c.SetDeterministicNonce(true)
+		return c
+	}
+	c := newCipher()
+	assert.True(t, c.DeterministicNonce)
+	block, _ := aes.NewCipher(c.Key)
+	src := []byte("hello world")
+
+	t.Run("round trip", func(t *testing.T) {
+		dst, err := c.EncryptWithDeterministicNonce(src, block)
+		require.NoError(t, err)
+		assert.Len(t, dst, DeterministicNonceSize+len(src)+16)
+
+		got, err := c.DecryptWithDeterministicNonce(dst, block)
+		require.NoError(t, err)
+		assert.Equal(t, src, got)
+	})
+
+	t.Run("same input same ciphertext", func(t *testing.T) {
+		dst1, err := c.EncryptWithDeterministicNonce(src, block)
+		require.NoError(t, err)
+		dst2, err := c.EncryptWithDeterministicNonce(src, block)
+		require.NoError(t, err)
+		assert.Equal(t, dst1, dst2)
+
+		// A set nonce is ignored
+		other := newCipher()
+		other.SetNonce([]byte("123456789012"))
+		dst3, err := other.EncryptWithDeterministicNonce(src, block)
+		require.NoError(t, err)
+		assert.Equal(t, dst1, dst3)
+	})
+
+	t.Run("nonce depends on key, aad and plaintext", func(t *testing.T) {
+		nonce, err := c.DeriveNonce(src)
+		require.NoError(t, err)
+		assert.Len(t, nonce, DeterministicNonceSize)
+
+		other, _ := c.DeriveNonce([]byte("hello world!"))
+		assert.NotEqual(t, nonce, other)
+
+		aad := newCipher()
+		aad.SetAAD([]byte("record-2"))
+		other, _ = aad.DeriveNonce(src)
+		assert.NotEqual(t, nonce, other)
+
+		// Moving bytes between AAD and plaintext changes the nonce
+		shifted := newCipher()
+		shifted.SetAAD([]byte("record-1h"))
+		other, _ = shifted.DeriveNonce(src[1:])
+		assert.NotEqual(t, nonce, other)
+
+		key := newCipher()
+		key.SetKey([]byte("6543210987654321"))
+		other, _ = key.DeriveNonce(src)
+		assert.NotEqual(t, nonce, other)
+	})
+
+	t.Run("invalid nonce", func(t *testing.T) {
+		_, err := c.DecryptWithDeterministicNonce(make([]byte, DeterministicNonceSize), block)
+		assert.IsType(t, InvalidDeterministicNonceError{}, err)
+		assert.Contains(t, err.Error(), "too short")
+
+		// A valid GCM ciphertext under a nonce that was not derived
+		gcm := NewAesCipher(GCM)
+		gcm.SetKey(c.Key)
+		gcm.SetAAD(c.AAD)
+		gcm.SetNonce([]byte("123456789012"))
+		dst, err := gcm.Encrypt(src, block)
+		require.NoError(t, err)
+		_, err = c.DecryptWithDeterministicNonce(append([]byte("123456789012"), dst...), block)
+		assert.Equal(t, InvalidDeterministicNonceError{reason: "nonce does not match the plaintext"}, err)
+		assert.Equal(t, "DGL-CIPHER-017", err.(InvalidDeterministicNonceError).Code())
+
+		dst, err = c.EncryptWithDeterministicNonce(src, block)
+		require.NoError(t, err)
+		dst[len(dst)-1] ^= 1
+		_, err = c.DecryptWithDeterministicNonce(dst, block)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		cbc := NewAesCipher(CBC)
+		cbc.SetKey(c.Key)
+		cbc.SetDeterministicNonce(true)
+		_, err := cbc.EncryptWithDeterministicNonce(src, block)
+		assert.IsType(t, UnsupportedDeterministicNonceError{}, err)
+		assert.Contains(t, err.Error(), "CBC")
+		_, err = cbc.DecryptWithDeterministicNonce(src, block)
+		assert.IsType(t, UnsupportedDeterministicNonceError{}, err)
+
+		mk := newCipher()
+		mk.SetMessageKey(true)
+		_, err = mk.EncryptWithDeterministicNonce(src, block)
+		assert.Contains(t, err.Error(), "message keys")
+		_, err = mk.EncryptWithMessageKey(src, aes.NewCipher)
+		assert.Contains(t, err.Error(), "message keys")
+		_, err = mk.DecryptWithMessageKey(src, aes.NewCipher)
+		assert.Equal(t, "DGL-CIPHER-016", err.(UnsupportedDeterministicNonceError).Code())
+
+		assert.Contains(t, UnsupportedDeterministicNonceError{}.Error(), "streaming")
+		assert.NotEmpty(t, UnsupportedDeterministicNonceError{}.Fields())
+		assert.NotEmpty(t, InvalidDeterministicNonceError{}.Fields())
+	})
+}
@@ -1,6 +1,10 @@
 package cipher
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // EmptySrcError represents an error when the source data is empty.
 type EmptySrcError struct {
@@ -13,6 +17,16 @@ func (e EmptySrcError) Error() string {
 	return fmt.Sprintf("src cannot be empty in '%s' block mode", e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-001.
+func (e EmptySrcError) Code() string {
+	return "DGL-CIPHER-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptySrcError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
 // EmptyIVError represents an error when the initialization vector (IV) is empty
 // for cipher modes that require an IV. This error occurs when the IV is nil
 // or has zero length, which is not allowed for secure cipher operations.
@@ -26,6 +40,16 @@ func (e EmptyIVError) Error() string {
 	return fmt.Sprintf("iv cannot be empty in '%s' block mode", e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-002.
+func (e EmptyIVError) Code() string {
+	return "DGL-CIPHER-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyIVError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
 // EmptyNonceError represents an error when the nonce (number used once) is empty
 // for cipher modes that require a nonce, such as GCM mode. This error occurs
 // when the nonce is nil or has zero length, which is required for secure
@@ -40,6 +64,16 @@ func (e EmptyNonceError) Error() string {
 	return fmt.Sprintf("nonce cannot be empty in '%s' block mode", e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-003.
+func (e EmptyNonceError) Code() string {
+	return "DGL-CIPHER-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyNonceError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
 // InvalidPlaintextError represents an error when the plaintext length is invalid
 // for the specified block cipher mode. This error occurs when the plaintext
 // length is not a multiple of the block size, which is required for most
@@ -56,6 +90,16 @@ func (e InvalidPlaintextError) Error() string {
 	return fmt.Sprintf("plaintext length %d must be a multiple of block size %d in '%s' block mode", len(e.src), e.size, e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-004.
+func (e InvalidPlaintextError) Code() string {
+	return "DGL-CIPHER-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPlaintextError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode, "size", e.size)
+}
+
 // InvalidCiphertextError represents an error when the ciphertext length is invalid
 // for the specified block cipher mode. This error occurs when the ciphertext
 // length is not a multiple of the block size, which is required for most
@@ -72,6 +116,16 @@ func (e InvalidCiphertextError) Error() string {
 	return fmt.Sprintf("raw ciphertext by decoding length %d must be a multiple of block size %d in '%s' block mode", len(e.src), e.size, e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-005.
+func (e InvalidCiphertextError) Code() string {
+	return "DGL-CIPHER-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCiphertextError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode, "size", e.size)
+}
+
 // InvalidIVError represents an error when the initialization vector (IV) length
 // is invalid for the specified block cipher. This error occurs when the IV
 // length does not match the required block size for the cipher.
@@ -87,6 +141,16 @@ func (e InvalidIVError) Error() string {
 	return fmt.Sprintf("iv length %d must equal block size %d in '%s' block mode", len(e.iv), e.size, e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-006.
+func (e InvalidIVError) Code() string {
+	return "DGL-CIPHER-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidIVError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode, "size", e.size)
+}
+
 // CreateCipherError represents an error that occurs during cipher creation.
 // This error wraps the underlying error that prevented the cipher from
 // being created successfully, such as invalid key length or unsupported
@@ -102,6 +166,16 @@ func (e CreateCipherError) Error() string {
 	return fmt.Sprintf("failed to create cipher in '%s' block mode: %v", e.mode, e.err)
 }
 
+// Code returns the stable error code DGL-CIPHER-007.
+func (e CreateCipherError) Code() string {
+	return "DGL-CIPHER-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CreateCipherError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode, errcode.FieldCause, e.err)
+}
+
 // UnsupportedBlockModeError represents an error when an unsupported block mode is used.
 type UnsupportedBlockModeError struct {
 	mode BlockMode
@@ -113,6 +187,16 @@ func (e UnsupportedBlockModeError) Error() string {
 	return fmt.Sprintf("unsupported block mode '%s'", e.mode)
 }
 
+// Code returns the stable error code DGL-CIPHER-008.
+func (e UnsupportedBlockModeError) Code() string {
+	return "DGL-CIPHER-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedBlockModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
 // UnsupportedPaddingModeError represents an error when an unsupported padding mode is used.
 type UnsupportedPaddingModeError struct {
 	mode PaddingMode
@@ -123,3 +207,250 @@ type UnsupportedPaddingModeError struct {
 func (e UnsupportedPaddingModeError) Error() string {
 	return fmt.Sprintf("unsupported padding mode '%s'", e.mode)
 }
+
+// Code returns the stable error code DGL-CIPHER-009.
+func (e UnsupportedPaddingModeError) Code() string {
+	return "DGL-CIPHER-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedPaddingModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
+// InvalidStreamStateError represents an error when a saved key stream state is
+// malformed or was saved with a different key or IV.
+type InvalidStreamStateError struct {
+	mode BlockMode
+}
+
+// Error returns a formatted error message describing the invalid stream state.
+func (e InvalidStreamStateError) Error() string {
+	return fmt.Sprintf("invalid or mismatched stream state in '%s' block mode", e.mode)
+}
+
+// Code returns the stable error code DGL-CIPHER-010.
+func (e InvalidStreamStateError) Code() string {
+	return "DGL-CIPHER-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidStreamStateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
+// MissingSaltError represents an error when a ciphertext made with message
+// keys is too short to hold the salt its key is derived from.
+type MissingSaltError struct {
+	src []byte
+}
+
+// Error returns a formatted error message describing the missing salt.
+func (e MissingSaltError) Error() string {
+	return fmt.Sprintf("raw ciphertext by decoding length %d is too short for the %d byte message key salt", len(e.src), MessageKeySaltSize)
+}
+
+// Code returns the stable error code DGL-CIPHER-011.
+func (e MissingSaltError) Code() string {
+	return "DGL-CIPHER-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MissingSaltError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "size", len(e.src))
+}
+
+// UnsupportedMessageKeyError represents an error when message keys are enabled
+// on a cipher used for streaming, which has no per-call header to carry a salt.
+type UnsupportedMessageKeyError struct{}
+
+// Error returns a formatted error message describing the unsupported message keys.
+func (e UnsupportedMessageKeyError) Error() string {
+	return "message keys are not supported by streaming encryption, use the standard encrypter"
+}
+
+// Code returns the stable error code DGL-CIPHER-012.
+func (e UnsupportedMessageKeyError) Code() string {
+	return "DGL-CIPHER-012"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedMessageKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "")
+}
+
+// InvalidAADFieldsError represents an error when additional data is not a
+// canonical encoding of AADFields.
+type InvalidAADFieldsError struct {
+	reason string
+}
+
+// Error returns a formatted error message describing the invalid fields.
+func (e InvalidAADFieldsError) Error() string {
+	return fmt.Sprintf("invalid additional data fields: %s", e.reason)
+}
+
+// Code returns the stable error code DGL-CIPHER-013.
+func (e InvalidAADFieldsError) Code() string {
+	return "DGL-CIPHER-013"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidAADFieldsError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "reason", e.reason)
+}
+
+// InvalidSegmentError represents an error when a segmented stream is
+// misconfigured or cannot go on.
+type InvalidSegmentError struct {
+	reason string
+}
+
+// Error returns a formatted error message describing the invalid segmented stream.
+func (e InvalidSegmentError) Error() string {
+	return fmt.Sprintf("invalid segmented stream: %s", e.reason)
+}
+
+// Code returns the stable error code DGL-CIPHER-014.
+func (e InvalidSegmentError) Code() string {
+	return "DGL-CIPHER-014"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSegmentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "reason", e.reason)
+}
+
+// SegmentAuthError represents an error when a segment of a segmented stream
+// fails authentication, because it was modified, reordered or the stream was
+// truncated.
+type SegmentAuthError struct {
+	index uint64
+}
+
+// Error returns a formatted error message describing the forged segment.
+func (e SegmentAuthError) Error() string {
+	return fmt.Sprintf("segment %d failed authentication, the stream was modified or truncated", e.index)
+}
+
+// Code returns the stable error code DGL-CIPHER-015.
+func (e SegmentAuthError) Code() string {
+	return "DGL-CIPHER-015"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SegmentAuthError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "decrypt", "segment", e.index)
+}
+
+// UnsupportedDeterministicNonceError represents an error when deterministic
+// nonces are enabled on a cipher that cannot use them. The zero value reports
+// a cipher used for streaming, which has no per-call header to carry a nonce.
+type UnsupportedDeterministicNonceError struct {
+	reason string
+}
+
+// Error returns a formatted error message describing the unsupported deterministic nonces.
+func (e UnsupportedDeterministicNonceError) Error() string {
+	if e.reason == "" {
+		return "deterministic nonces are not supported by streaming encryption, use the standard encrypter"
+	}
+	return fmt.Sprintf("deterministic nonces are not supported: %s", e.reason)
+}
+
+// Code returns the stable error code DGL-CIPHER-016.
+func (e UnsupportedDeterministicNonceError) Code() string {
+	return "DGL-CIPHER-016"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedDeterministicNonceError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "reason", e.reason)
+}
+
+// InvalidDeterministicNonceError represents an error when the nonce prefixed
+// to a ciphertext made with deterministic nonces is missing or does not match
+// the decrypted plaintext.
+type InvalidDeterministicNonceError struct {
+	reason string
+}
+
+// Error returns a formatted error message describing the invalid nonce.
+func (e InvalidDeterministicNonceError) Error() string {
+	return fmt.Sprintf("invalid deterministic nonce: %s", e.reason)
+}
+
+// Code returns the stable error code DGL-CIPHER-017.
+func (e InvalidDeterministicNonceError) Code() string {
+	return "DGL-CIPHER-017"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidDeterministicNonceError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "decrypt", "reason", e.reason)
+}
+
+// StreamAuthError represents an error when a stream decrypted under the
+// ReleaseUnverified policy fails authentication at its end, after its
+// plaintext has been returned.
+type StreamAuthError struct{}
+
+// Error returns a formatted error message describing the forged stream.
+func (e StreamAuthError) Error() string {
+	return "stream failed authentication after its plaintext was returned, discard everything read from it"
+}
+
+// Code returns the stable error code DGL-CIPHER-018.
+func (e StreamAuthError) Code() string {
+	return "DGL-CIPHER-018"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e StreamAuthError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "decrypt")
+}
+
+// UnsupportedKeyCommitmentError represents an error when key commitment is
+// enabled on a cipher that cannot use it. The zero value reports a cipher used
+// for streaming, which has no per-call header to carry the commitment.
+type UnsupportedKeyCommitmentError struct {
+	mode BlockMode
+}
+
+// Error returns a formatted error message describing the unsupported key commitment.
+func (e UnsupportedKeyCommitmentError) Error() string {
+	if e.mode == "" {
+		return "key commitment is not supported by streaming encryption, use the standard encrypter"
+	}
+	return fmt.Sprintf("key commitment is not supported in '%s' block mode, use GCM", e.mode)
+}
+
+// Code returns the stable error code DGL-CIPHER-019.
+func (e UnsupportedKeyCommitmentError) Code() string {
+	return "DGL-CIPHER-019"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedKeyCommitmentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "", "mode", e.mode)
+}
+
+// KeyCommitmentError represents an error when a ciphertext made with key
+// commitment is missing its commitment or was not made under the key used to
+// decrypt it.
+type KeyCommitmentError struct{}
+
+// Error returns a formatted error message describing the failed key commitment.
+func (e KeyCommitmentError) Error() string {
+	return "ciphertext does not commit to the decryption key"
+}
+
+// Code returns the stable error code DGL-CIPHER-020.
+func (e KeyCommitmentError) Code() string {
+	return "DGL-CIPHER-020"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyCommitmentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cipher", "", "decrypt")
+}
@@ -0,0 +1,82 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/hkdf"
+)
+
+// MessageKeySaltSize is the size of the random salt prefixed to every
+// ciphertext made with message keys.
+const MessageKeySaltSize = 16
+
+// messageKeyInfo binds derived keys to their use.
+const messageKeyInfo = "dongle/cipher/message-key"
+
+// SetMessageKey enables per-message keys. Each encryption then draws a random
+// salt, derives a one-time key of the same length as c.Key with
+// HKDF-SHA256(c.Key, salt) and prefixes the salt to the ciphertext, so that
+// high volumes of data encrypted under one configured key, or under a fixed IV
+// or nonce, never reuse a cipher key. Decryption reads the salt back from the
+// ciphertext. Message keys are supported by the standard encrypters only.
+func (c *blockCipher) SetMessageKey(enabled bool) {
+	c.MessageKey = enabled
+}
+
+// DeriveMessageKey derives the one-time key for salt from c.Key.
+func (c *blockCipher) DeriveMessageKey(salt []byte) ([]byte, error) {
+	key := make([]byte, len(c.Key))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, c.Key, salt, []byte(messageKeyInfo)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EncryptWithMessageKey encrypts src as Encrypt does, under a message key
+// passed to newBlock, and returns the salt followed by the ciphertext.
+func (c *blockCipher) EncryptWithMessageKey(src []byte, newBlock func(key []byte) (cipher.Block, error)) (dst []byte, err error) {
+	if c.DeterministicNonce {
+		return nil, UnsupportedDeterministicNonceError{reason: "message keys are enabled"}
+	}
+	salt := make([]byte, MessageKeySaltSize)
+	if _, err = io.ReadFull(utils.Rand(), salt); err != nil {
+		return
+	}
+	block, err := c.messageBlock(salt, newBlock)
+	if err != nil {
+		return
+	}
+	ciphertext, err := c.Encrypt(src, block)
+	if err != nil {
+		return
+	}
+	return append(salt, ciphertext...), nil
+}
+
+// DecryptWithMessageKey splits the salt off src and decrypts the rest as
+// Decrypt does, under the message key passed to newBlock.
+func (c *blockCipher) DecryptWithMessageKey(src []byte, newBlock func(key []byte) (cipher.Block, error)) (dst []byte, err error) {
+	if c.DeterministicNonce {
+		return nil, UnsupportedDeterministicNonceError{reason: "message keys are enabled"}
+	}
+	if len(src) <= MessageKeySaltSize {
+		return nil, MissingSaltError{src: src}
+	}
+	block, err := c.messageBlock(src[:MessageKeySaltSize], newBlock)
+	if err != nil {
+		return
+	}
+	return c.Decrypt(src[MessageKeySaltSize:], block)
+}
+
+// messageBlock returns the block keyed with the message key for salt.
+func (c *blockCipher) messageBlock(salt []byte, newBlock func(key []byte) (cipher.Block, error)) (cipher.Block, error) {
+	key, err := c.DeriveMessageKey(salt)
+	if err != nil {
+		return nil, err
+	}
+	return newBlock(key)
+}
@@ -0,0 +1,79 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockCipher_MessageKey(t *testing.T) {
+	c := NewAesCipher(CBC)
+	c.SetKey([]byte("1234567890123456"))
+	c.SetIV([]byte("1234567890123456"))
+	c.SetPadding(PKCS7)
+	c.SetMessageKey(true)
+	assert.True(t, c.MessageKey)
+	src := []byte("hello world")
+
+	t.Run("round trip", func(t *testing.T) {
+		dst, err := c.EncryptWithMessageKey(src, aes.NewCipher)
+		assert.NoError(t, err)
+		assert.Len(t, dst, MessageKeySaltSize+16)
+
+		got, err := c.DecryptWithMessageKey(dst, aes.NewCipher)
+		assert.NoError(t, err)
+		assert.Equal(t, src, got)
+	})
+
+	t.Run("fresh key per call", func(t *testing.T) {
+		dst1, err := c.EncryptWithMessageKey(src, aes.NewCipher)
+		assert.NoError(t, err)
+		dst2, err := c.EncryptWithMessageKey(src, aes.NewCipher)
+		assert.NoError(t, err)
+		assert.NotEqual(t, dst1[:MessageKeySaltSize], dst2[:MessageKeySaltSize])
+		assert.NotEqual(t, dst1[MessageKeySaltSize:], dst2[MessageKeySaltSize:])
+
+		// The configured key alone does not decrypt the ciphertext
+		block, _ := aes.NewCipher(c.Key)
+		plain, err := c.Decrypt(dst1[MessageKeySaltSize:], block)
+		assert.False(t, err == nil && bytes.Equal(plain, src))
+	})
+
+	t.Run("derived key", func(t *testing.T) {
+		salt := bytes.Repeat([]byte{1}, MessageKeySaltSize)
+		key1, err := c.DeriveMessageKey(salt)
+		assert.NoError(t, err)
+		assert.Len(t, key1, len(c.Key))
+		key2, _ := c.DeriveMessageKey(salt)
+		assert.Equal(t, key1, key2)
+		key3, _ := c.DeriveMessageKey(bytes.Repeat([]byte{2}, MessageKeySaltSize))
+		assert.NotEqual(t, key1, key3)
+		assert.NotEqual(t, c.Key, key1)
+	})
+
+	t.Run("missing salt", func(t *testing.T) {
+		_, err := c.DecryptWithMessageKey(make([]byte, MessageKeySaltSize), aes.NewCipher)
+		assert.IsType(t, MissingSaltError{}, err)
+		assert.Contains(t, err.Error(), "message key salt")
+	})
+
+	t.Run("random source error", func(t *testing.T) {
+		t.Cleanup(utils.SetRand(bytes.NewReader(nil)))
+		_, err := c.EncryptWithMessageKey(src, aes.NewCipher)
+		assert.Error(t, err)
+	})
+
+	t.Run("block error", func(t *testing.T) {
+		want := errors.New("block error")
+		failing := func(key []byte) (cipher.Block, error) { return nil, want }
+		_, err := c.EncryptWithMessageKey(src, failing)
+		assert.Equal(t, want, err)
+		_, err = c.DecryptWithMessageKey(make([]byte, MessageKeySaltSize+16), failing)
+		assert.Equal(t, want, err)
+	})
+}
@@ -0,0 +1,171 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"io"
+	"slices"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// ReleasePolicy controls when the stream decrypters of AEAD ciphers, GCM and
+// ChaCha20-Poly1305, return plaintext.
+type ReleasePolicy int
+
+const (
+	// ReleaseVerified returns plaintext only once the tag covering it has been
+	// verified, and is the default. Streams without a segment size are read and
+	// verified whole before the first byte is returned; segmented streams, see
+	// SetSegmentSize, return each segment once its own tag has been verified.
+	ReleaseVerified ReleasePolicy = iota
+	// ReleaseUnverified returns plaintext as soon as it is decrypted, before the
+	// tag at the end of the stream has been checked, which lowers the latency to
+	// the first byte of slow or large streams. A forged or truncated stream is
+	// only reported by the Read reaching its end, after its plaintext has been
+	// returned: callers must not act on anything read before io.EOF and must
+	// discard all of it if Read fails. The ciphertext is still held until the
+	// end to verify the tag, so memory use does not change.
+	ReleaseUnverified
+)
+
+// gcmNonceSize is the only GCM nonce size whose key stream is a plain counter
+// starting at the nonce followed by the 32-bit counter 2.
+const gcmNonceSize = 12
+
+// SetReleasePolicy sets when the stream decrypters return plaintext,
+// ReleaseVerified by default. ReleaseUnverified applies to GCM streams without
+// a segment size, a 12-byte nonce and no padding; other streams always release
+// verified plaintext.
+func (c *blockCipher) SetReleasePolicy(policy ReleasePolicy) {
+	c.Release = policy
+}
+
+// NewUnverifiedReader returns the reader decrypting the GCM stream r under the
+// ReleaseUnverified policy, or nil when the cipher releases verified plaintext
+// only.
+func (c *blockCipher) NewUnverifiedReader(r io.Reader, block cipher.Block) (*UnverifiedReader, error) {
+	if c.Release != ReleaseUnverified || c.Block != GCM || c.SegmentSize > 0 || c.Padding != No || len(c.Nonce) != gcmNonceSize {
+		return nil, nil
+	}
+	aead, err := c.NewAEAD(block)
+	if err != nil {
+		return nil, err
+	}
+	iv := append(append([]byte{}, c.Nonce...), 0, 0, 0, 2)
+	return NewUnverifiedReader(r, cipher.NewCTR(block, iv), aead, c.Nonce, c.AAD), nil
+}
+
+// SetReleasePolicy sets when the stream decrypters return plaintext,
+// ReleaseVerified by default. ReleaseUnverified applies to streams without a
+// segment size.
+func (c *ChaCha20Poly1305Cipher) SetReleasePolicy(policy ReleasePolicy) {
+	c.Release = policy
+}
+
+// NewUnverifiedReader returns the reader decrypting the stream r with aead
+// under the ReleaseUnverified policy, or nil when the cipher releases verified
+// plaintext only.
+func (c *ChaCha20Poly1305Cipher) NewUnverifiedReader(r io.Reader, aead cipher.AEAD) (*UnverifiedReader, error) {
+	if c.Release != ReleaseUnverified || c.SegmentSize > 0 {
+		return nil, nil
+	}
+	return newChaChaUnverifiedReader(r, aead, c.Key, c.Nonce, c.AAD)
+}
+
+// SetReleasePolicy sets when the stream decrypters return plaintext,
+// ReleaseVerified by default. ReleaseUnverified applies to streams without a
+// segment size.
+func (c *XChaCha20Poly1305Cipher) SetReleasePolicy(policy ReleasePolicy) {
+	c.Release = policy
+}
+
+// NewUnverifiedReader returns the reader decrypting the stream r with aead
+// under the ReleaseUnverified policy, or nil when the cipher releases verified
+// plaintext only.
+func (c *XChaCha20Poly1305Cipher) NewUnverifiedReader(r io.Reader, aead cipher.AEAD) (*UnverifiedReader, error) {
+	if c.Release != ReleaseUnverified || c.SegmentSize > 0 {
+		return nil, nil
+	}
+	return newChaChaUnverifiedReader(r, aead, c.Key, c.Nonce, c.AAD)
+}
+
+// newChaChaUnverifiedReader returns the UnverifiedReader of a ChaCha20-Poly1305
+// or XChaCha20-Poly1305 stream, whose key stream starts at block counter 1.
+func newChaChaUnverifiedReader(r io.Reader, aead cipher.AEAD, key, nonce, aad []byte) (*UnverifiedReader, error) {
+	stream, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	stream.SetCounter(1)
+	return NewUnverifiedReader(r, stream, aead, nonce, aad), nil
+}
+
+// UnverifiedReader decrypts a ciphertext sealed by an AEAD as it is read, see
+// ReleaseUnverified. It implements io.Reader and returns a StreamAuthError
+// from the Read reaching the end of a stream that fails authentication.
+type UnverifiedReader struct {
+	reader   io.Reader
+	stream   cipher.Stream // Key stream of the AEAD
+	aead     cipher.AEAD
+	nonce    []byte
+	aad      []byte
+	held     []byte // Ciphertext read so far, ending with what may be the tag
+	released int    // Length of held already decrypted and returned
+	done     bool
+	err      error
+}
+
+// NewUnverifiedReader returns an UnverifiedReader reading the ciphertext that
+// aead sealed under nonce and aad from r, decrypting it with stream, the key
+// stream aead encrypts with.
+func NewUnverifiedReader(r io.Reader, stream cipher.Stream, aead cipher.AEAD, nonce, aad []byte) *UnverifiedReader {
+	return &UnverifiedReader{
+		reader: r,
+		stream: stream,
+		aead:   aead,
+		nonce:  nonce,
+		aad:    aad,
+	}
+}
+
+// Read returns the plaintext of the ciphertext read so far, except for the
+// bytes that may still turn out to be the tag.
+func (r *UnverifiedReader) Read(p []byte) (n int, err error) {
+	for {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if n = len(r.held) - r.aead.Overhead() - r.released; n > 0 {
+			n = min(n, len(p))
+			r.stream.XORKeyStream(p[:n], r.held[r.released:r.released+n])
+			r.released += n
+			return n, nil
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.fill()
+	}
+}
+
+// fill reads more ciphertext and verifies the tag at the end of the stream.
+func (r *UnverifiedReader) fill() {
+	if len(r.held) == cap(r.held) {
+		r.held = slices.Grow(r.held, 4096)
+	}
+	k, err := r.reader.Read(r.held[len(r.held):cap(r.held)])
+	r.held = r.held[:len(r.held)+k]
+	switch err {
+	case nil:
+	case io.EOF:
+		r.done = true
+		if len(r.held) == 0 {
+			return
+		}
+		if _, err = r.aead.Open(nil, r.nonce, r.held, r.aad); err != nil {
+			r.err = StreamAuthError{}
+		}
+	default:
+		r.err = err
+	}
+}
@@ -0,0 +1,143 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+func TestUnverifiedReader(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	aad := []byte("header")
+	data := bytes.Repeat([]byte("release policy "), 100)
+
+	gcm := NewAesCipher(GCM)
+	gcm.SetKey(key[:16])
+	gcm.SetNonce([]byte("123456789012"))
+	gcm.SetAAD(aad)
+	gcm.SetReleasePolicy(ReleaseUnverified)
+	block, _ := aes.NewCipher(gcm.Key)
+	gcmAEAD, _ := cipher.NewGCM(block)
+
+	chacha := NewChaCha20Poly1305Cipher()
+	chacha.SetKey(key)
+	chacha.SetNonce([]byte("123456789012"))
+	chacha.SetAAD(aad)
+	chacha.SetReleasePolicy(ReleaseUnverified)
+	chachaAEAD, _ := chacha20poly1305.New(key)
+
+	xchacha := NewXChaCha20Poly1305Cipher()
+	xchacha.SetKey(key)
+	xchacha.SetNonce([]byte("123456789012345678901234"))
+	xchacha.SetAAD(aad)
+	xchacha.SetReleasePolicy(ReleaseUnverified)
+	xchachaAEAD, _ := chacha20poly1305.NewX(key)
+
+	for name, tc := range map[string]struct {
+		aead  cipher.AEAD
+		nonce []byte
+		new   func(r io.Reader) (*UnverifiedReader, error)
+	}{
+		"GCM":                {gcmAEAD, gcm.Nonce, func(r io.Reader) (*UnverifiedReader, error) { return gcm.NewUnverifiedReader(r, block) }},
+		"ChaCha20-Poly1305":  {chachaAEAD, chacha.Nonce, func(r io.Reader) (*UnverifiedReader, error) { return chacha.NewUnverifiedReader(r, chachaAEAD) }},
+		"XChaCha20-Poly1305": {xchachaAEAD, xchacha.Nonce, func(r io.Reader) (*UnverifiedReader, error) { return xchacha.NewUnverifiedReader(r, xchachaAEAD) }},
+	} {
+		sealed := tc.aead.Seal(nil, tc.nonce, data, aad)
+
+		t.Run(name, func(t *testing.T) {
+			src := mock.NewScriptedReader(bytes.NewReader(sealed)).Then(100).Repeat()
+			r, err := tc.new(src)
+			require.NoError(t, err)
+			require.NotNil(t, r)
+
+			// Plaintext is released before the end of the stream is read
+			buf := make([]byte, 1000)
+			n, err := r.Read(buf)
+			require.NoError(t, err)
+			assert.Equal(t, 100-tc.aead.Overhead(), n)
+			assert.Equal(t, data[:n], buf[:n])
+
+			rest, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, data, append(buf[:n], rest...))
+		})
+
+		t.Run(name+" tampered", func(t *testing.T) {
+			forged := append([]byte{}, sealed...)
+			forged[len(forged)-1] ^= 1
+			r, _ := tc.new(bytes.NewReader(forged))
+			got, err := io.ReadAll(r)
+			assert.Equal(t, StreamAuthError{}, err)
+			assert.Equal(t, data, got)
+
+			// The error is sticky
+			_, err = r.Read(make([]byte, 1))
+			assert.Equal(t, StreamAuthError{}, err)
+		})
+
+		t.Run(name+" truncated", func(t *testing.T) {
+			r, _ := tc.new(bytes.NewReader(sealed[:10]))
+			_, err := io.ReadAll(r)
+			assert.Equal(t, StreamAuthError{}, err)
+		})
+	}
+
+	t.Run("empty stream", func(t *testing.T) {
+		r, _ := gcm.NewUnverifiedReader(bytes.NewReader(nil), block)
+		n, err := r.Read(make([]byte, 16))
+		assert.Equal(t, 0, n)
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("read error")
+		r, _ := gcm.NewUnverifiedReader(mock.NewErrorReadAfterN(make([]byte, 64), 64, readErr), block)
+		_, err := io.ReadAll(r)
+		assert.Equal(t, readErr, err)
+	})
+
+	t.Run("verified policy", func(t *testing.T) {
+		for _, c := range []func(c *AesCipher){
+			func(c *AesCipher) { c.SetReleasePolicy(ReleaseVerified) },
+			func(c *AesCipher) { c.SetSegmentSize(1024) },
+			func(c *AesCipher) { c.SetPadding(PKCS7) },
+			func(c *AesCipher) { c.SetNonce([]byte("1234567890123456")) },
+			func(c *AesCipher) { c.Block = CBC },
+		} {
+			other := *gcm
+			c(&other)
+			r, err := other.NewUnverifiedReader(bytes.NewReader(nil), block)
+			assert.NoError(t, err)
+			assert.Nil(t, r)
+		}
+
+		other := *chacha
+		other.SetSegmentSize(1024)
+		r, err := other.NewUnverifiedReader(bytes.NewReader(nil), chachaAEAD)
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+		otherX := *xchacha
+		otherX.SetReleasePolicy(ReleaseVerified)
+		r, err = otherX.NewUnverifiedReader(bytes.NewReader(nil), xchachaAEAD)
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		other := *chacha
+		other.SetKey(key[:5])
+		_, err := other.NewUnverifiedReader(bytes.NewReader(nil), chachaAEAD)
+		assert.Error(t, err)
+	})
+
+	assert.Equal(t, "DGL-CIPHER-018", StreamAuthError{}.Code())
+	assert.NotEmpty(t, StreamAuthError{}.Fields())
+}
@@ -0,0 +1,291 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// segmentNonceSize is the number of trailing nonce bytes the segment index and
+// the final segment flag are mixed into.
+const segmentNonceSize = 5
+
+// segmentCheckFlag is mixed into the last nonce byte in place of the final
+// segment flag to derive the nonce of checkpoint check values. Segments only
+// use the flags 0 and 1, so the check never shares a nonce with a segment.
+const segmentCheckFlag = 2
+
+// segmentStateMagic identifies serialized segment writer states.
+const segmentStateMagic = "dgs\x01"
+
+// segmentStateSize is the size of a serialized segment writer state without
+// the buffered plaintext: the magic, the segment index and the check value.
+const segmentStateSize = len(segmentStateMagic) + 4 + 8
+
+// SetSegmentSize makes the stream encrypters of GCM ciphers seal the stream in
+// segments of size plaintext bytes instead of holding the whole stream in
+// memory, so that files of any size can be encrypted and decrypted with a
+// constant amount of memory. Each segment carries its own tag, and its nonce is
+// c.Nonce with the segment index and a final segment flag mixed into the last
+// five bytes, so that segments cannot be reordered, dropped or the stream
+// truncated without decryption failing. c.AAD authenticates every segment.
+//
+// Segmented streams are a different format from GCM streams without segments,
+// both sides must use the same segment size. Zero disables segments.
+func (c *blockCipher) SetSegmentSize(size int) {
+	c.SegmentSize = size
+}
+
+// segmentNonce returns the nonce of segment index of a stream with nonce base.
+func segmentNonce(base []byte, index uint32, final bool) []byte {
+	nonce := append([]byte{}, base...)
+	tail := nonce[len(nonce)-segmentNonceSize:]
+	var mix [segmentNonceSize]byte
+	binary.BigEndian.PutUint32(mix[:], index)
+	if final {
+		mix[4] = 1
+	}
+	for i := range tail {
+		tail[i] ^= mix[i]
+	}
+	return nonce
+}
+
+// checkSegments validates the parameters of a segmented stream.
+func checkSegments(aead cipher.AEAD, nonce []byte, size int) error {
+	switch {
+	case len(nonce) == 0:
+		return EmptyNonceError{mode: GCM}
+	case len(nonce) != aead.NonceSize() || len(nonce) < segmentNonceSize+1:
+		return InvalidSegmentError{reason: "nonce must match the AEAD nonce size and be longer than 5 bytes"}
+	case size <= 0:
+		return InvalidSegmentError{reason: "segment size must be positive"}
+	}
+	return nil
+}
+
+// SegmentWriter encrypts a stream in segments sealed by an AEAD, see
+// SetSegmentSize. It implements io.WriteCloser; Close seals the final segment
+// and must be called for the stream to be decryptable.
+type SegmentWriter struct {
+	writer io.Writer
+	aead   cipher.AEAD
+	nonce  []byte
+	aad    []byte
+	buffer []byte // Plaintext of the segment being filled
+	sealed []byte // Reused ciphertext buffer
+	index  uint32
+	closed bool
+	err    error
+}
+
+// NewSegmentWriter returns a SegmentWriter writing segments of size plaintext
+// bytes, sealed with aead under nonce and aad, to w.
+func NewSegmentWriter(w io.Writer, aead cipher.AEAD, nonce, aad []byte, size int) (*SegmentWriter, error) {
+	if err := checkSegments(aead, nonce, size); err != nil {
+		return nil, err
+	}
+	return &SegmentWriter{
+		writer: w,
+		aead:   aead,
+		nonce:  append([]byte{}, nonce...),
+		aad:    aad,
+		buffer: make([]byte, 0, size),
+		sealed: make([]byte, 0, size+aead.Overhead()),
+	}, nil
+}
+
+// Write buffers p and seals every segment known not to be the last one.
+func (w *SegmentWriter) Write(p []byte) (n int, err error) {
+	if w.closed {
+		return 0, InvalidSegmentError{reason: "write after close"}
+	}
+	for len(p) > 0 {
+		if w.err != nil {
+			return n, w.err
+		}
+		// A full segment is only sealed once more data shows it is not the last.
+		if len(w.buffer) == cap(w.buffer) {
+			w.seal(false)
+			continue
+		}
+		k := copy(w.buffer[len(w.buffer):cap(w.buffer)], p)
+		w.buffer = w.buffer[:len(w.buffer)+k]
+		p = p[k:]
+		n += k
+	}
+	return n, w.err
+}
+
+// Close seals the final segment, which may be empty. It does not close the
+// underlying writer.
+func (w *SegmentWriter) Close() error {
+	if w.err != nil || w.closed {
+		return w.err
+	}
+	w.seal(true)
+	w.closed = true
+	return w.err
+}
+
+// Offset returns the number of ciphertext bytes written so far, the length the
+// output must be truncated to before resuming from a checkpoint taken now.
+func (w *SegmentWriter) Offset() int64 {
+	return int64(w.index) * int64(cap(w.buffer)+w.aead.Overhead())
+}
+
+// MarshalBinary returns a checkpoint of the writer: the index of the next
+// segment, a check value of the nonce, AAD and segment size, and the plaintext
+// buffered for the next segment, at most one segment. The checkpoint therefore
+// holds plaintext and must be stored as carefully as the data itself. The key
+// is not included and must be supplied again when resuming.
+func (w *SegmentWriter) MarshalBinary() ([]byte, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	if w.closed {
+		return nil, InvalidSegmentError{reason: "checkpoint after close"}
+	}
+	b := make([]byte, 0, segmentStateSize+len(w.buffer))
+	b = append(b, segmentStateMagic...)
+	b = binary.BigEndian.AppendUint32(b, w.index)
+	b = append(b, w.check()...)
+	return append(b, w.buffer...), nil
+}
+
+// UnmarshalBinary restores a checkpoint taken with MarshalBinary by a writer
+// with the same key, nonce, AAD and segment size. The underlying writer must
+// be positioned at Offset of the ciphertext, any bytes written after the
+// checkpoint are to be discarded.
+func (w *SegmentWriter) UnmarshalBinary(data []byte) error {
+	if w.closed {
+		return InvalidSegmentError{reason: "checkpoint after close"}
+	}
+	if len(data) < segmentStateSize || len(data) > segmentStateSize+cap(w.buffer) ||
+		string(data[:len(segmentStateMagic)]) != segmentStateMagic ||
+		string(data[len(segmentStateMagic)+4:segmentStateSize]) != string(w.check()) {
+		return InvalidStreamStateError{mode: GCM}
+	}
+	w.index = binary.BigEndian.Uint32(data[len(segmentStateMagic):])
+	w.buffer = append(w.buffer[:0], data[segmentStateSize:]...)
+	w.err = nil
+	return nil
+}
+
+// check returns a check value of the key and stream parameters, so that a
+// checkpoint is not restored into a writer with a different key, nonce, AAD or
+// segment size. The key enters through the tag of an empty message sealed
+// under a nonce no segment uses.
+func (w *SegmentWriter) check() []byte {
+	nonce := segmentNonce(w.nonce, 0, false)
+	nonce[len(nonce)-1] ^= segmentCheckFlag
+	h := sha256.New()
+	h.Write(binary.BigEndian.AppendUint32(nil, uint32(cap(w.buffer))))
+	h.Write(w.nonce)
+	h.Write(w.aead.Seal(nil, nonce, nil, w.aad))
+	return h.Sum(nil)[:8]
+}
+
+// seal encrypts and writes the buffered segment.
+func (w *SegmentWriter) seal(final bool) {
+	nonce := segmentNonce(w.nonce, w.index, final)
+	w.sealed = w.aead.Seal(w.sealed[:0], nonce, w.buffer, w.aad)
+	if _, err := w.writer.Write(w.sealed); err != nil {
+		w.err = err
+		return
+	}
+	w.buffer = w.buffer[:0]
+	if w.index == math.MaxUint32 && !final {
+		w.err = InvalidSegmentError{reason: "too many segments"}
+		return
+	}
+	w.index++
+}
+
+// SegmentReader decrypts a stream written by a SegmentWriter. It implements
+// io.Reader and returns a SegmentAuthError as soon as a segment fails
+// authentication, so that no forged plaintext is ever returned.
+type SegmentReader struct {
+	reader io.Reader
+	aead   cipher.AEAD
+	nonce  []byte
+	aad    []byte
+	buffer []byte // Ciphertext read ahead, a segment and one more byte
+	plain  []byte // Reused plaintext buffer
+	opened []byte // Plaintext of the current segment not returned yet
+	index  uint32
+	done   bool
+	err    error
+}
+
+// NewSegmentReader returns a SegmentReader reading segments of size plaintext
+// bytes, sealed with aead under nonce and aad, from r.
+func NewSegmentReader(r io.Reader, aead cipher.AEAD, nonce, aad []byte, size int) (*SegmentReader, error) {
+	if err := checkSegments(aead, nonce, size); err != nil {
+		return nil, err
+	}
+	return &SegmentReader{
+		reader: r,
+		aead:   aead,
+		nonce:  append([]byte{}, nonce...),
+		aad:    aad,
+		// One byte more than a sealed segment tells whether it is the last.
+		buffer: make([]byte, 0, size+aead.Overhead()+1),
+		plain:  make([]byte, 0, size),
+	}, nil
+}
+
+// Read returns the plaintext of the segments read so far.
+func (r *SegmentReader) Read(p []byte) (n int, err error) {
+	for len(r.opened) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.open()
+	}
+	n = copy(p, r.opened)
+	r.opened = r.opened[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next segment.
+func (r *SegmentReader) open() {
+	k, err := io.ReadFull(r.reader, r.buffer[len(r.buffer):cap(r.buffer)])
+	r.buffer = r.buffer[:len(r.buffer)+k]
+	final := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		final = true
+	default:
+		r.err = err
+		return
+	}
+
+	segment := r.buffer
+	if !final {
+		segment = r.buffer[:len(r.buffer)-1]
+	}
+	nonce := segmentNonce(r.nonce, r.index, final)
+	r.opened, err = r.aead.Open(r.plain[:0], nonce, segment, r.aad)
+	if err != nil {
+		r.err = SegmentAuthError{index: uint64(r.index)}
+		return
+	}
+	if final {
+		r.done = true
+		return
+	}
+	if r.index == math.MaxUint32 {
+		r.err = InvalidSegmentError{reason: "too many segments"}
+		return
+	}
+	// Keep the byte read ahead as the start of the next segment.
+	r.buffer = append(r.buffer[:0], r.buffer[len(r.buffer)-1])
+	r.index++
+}
@@ -0,0 +1,223 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	stdCipher "crypto/cipher"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSegmentAEAD(t *testing.T) stdCipher.AEAD {
+	t.Helper()
+	block, err := aes.NewCipher([]byte("1234567890123456"))
+	require.NoError(t, err)
+	aead, err := stdCipher.NewGCM(block)
+	require.NoError(t, err)
+	return aead
+}
+
+// sealSegments encrypts data in segments of size bytes, written in chunks.
+func sealSegments(t *testing.T, aead stdCipher.AEAD, data []byte, size, chunk int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewSegmentWriter(&buf, aead, []byte("123456789012"), []byte("aad"), size)
+	require.NoError(t, err)
+	for len(data) > 0 {
+		n := min(chunk, len(data))
+		written, err := w.Write(data[:n])
+		require.NoError(t, err)
+		require.Equal(t, n, written)
+		data = data[n:]
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// openSegments decrypts a segmented stream read one byte at a time.
+func openSegments(aead stdCipher.AEAD, sealed []byte, size int) ([]byte, error) {
+	r, err := NewSegmentReader(iotest.OneByteReader(bytes.NewReader(sealed)), aead, []byte("123456789012"), []byte("aad"), size)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestSegments(t *testing.T) {
+	aead := newSegmentAEAD(t)
+	data := bytes.Repeat([]byte("0123456789abcdef-"), 20) // 340 bytes
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, n := range []int{0, 1, 63, 64, 65, 128, 340} {
+			for _, chunk := range []int{1, 7, 64, 1000} {
+				sealed := sealSegments(t, aead, data[:n], 64, chunk)
+				segments := max(1, (n+63)/64)
+				assert.Len(t, sealed, n+segments*aead.Overhead(), "size %d chunk %d", n, chunk)
+
+				got, err := openSegments(aead, sealed, 64)
+				require.NoError(t, err)
+				assert.Equal(t, data[:n], append([]byte{}, got...), "size %d chunk %d", n, chunk)
+			}
+		}
+	})
+
+	t.Run("single segment", func(t *testing.T) {
+		// The only segment is the final one: index 0 and the final flag.
+		nonce := []byte("123456789012")
+		nonce[11] ^= 1
+		assert.Equal(t, aead.Seal(nil, nonce, []byte("hello"), []byte("aad")), sealSegments(t, aead, []byte("hello"), 64, 64))
+	})
+
+	t.Run("tampering", func(t *testing.T) {
+		sealed := sealSegments(t, aead, data, 64, 100)
+		segment := 64 + aead.Overhead()
+
+		flipped := append([]byte{}, sealed...)
+		flipped[segment+3] ^= 1
+		got, err := openSegments(aead, flipped, 64)
+		assert.Equal(t, SegmentAuthError{index: 1}, err)
+		// Only the authenticated first segment was returned.
+		assert.Equal(t, data[:64], got)
+
+		_, err = openSegments(aead, sealed[:2*segment], 64)
+		assert.Equal(t, SegmentAuthError{index: 1}, err, "truncated at a segment boundary")
+
+		_, err = openSegments(aead, sealed[:len(sealed)-1], 64)
+		assert.Equal(t, SegmentAuthError{index: 5}, err, "truncated final segment")
+
+		reordered := append(append(append([]byte{}, sealed[segment:2*segment]...), sealed[:segment]...), sealed[2*segment:]...)
+		_, err = openSegments(aead, reordered, 64)
+		assert.Equal(t, SegmentAuthError{index: 0}, err)
+
+		_, err = openSegments(aead, append(append([]byte{}, sealed...), 0), 64)
+		assert.Equal(t, SegmentAuthError{index: 5}, err, "trailing data")
+
+		_, err = openSegments(aead, nil, 64)
+		assert.Equal(t, SegmentAuthError{index: 0}, err, "empty stream")
+
+		_, err = openSegments(aead, sealed, 32)
+		assert.IsType(t, SegmentAuthError{}, err, "different segment size")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, err := NewSegmentWriter(io.Discard, aead, nil, nil, 64)
+		assert.Equal(t, EmptyNonceError{mode: GCM}, err)
+		_, err = NewSegmentWriter(io.Discard, aead, []byte("1234"), nil, 64)
+		assert.IsType(t, InvalidSegmentError{}, err)
+		_, err = NewSegmentReader(bytes.NewReader(nil), aead, []byte("123456789012"), nil, 0)
+		assert.EqualError(t, err, "invalid segmented stream: segment size must be positive")
+		assert.Equal(t, "DGL-CIPHER-014", err.(InvalidSegmentError).Code())
+	})
+
+	t.Run("write after close", func(t *testing.T) {
+		w, err := NewSegmentWriter(io.Discard, aead, []byte("123456789012"), nil, 64)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		assert.NoError(t, w.Close())
+		_, err = w.Write([]byte("x"))
+		assert.IsType(t, InvalidSegmentError{}, err)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		failure := errors.New("write failed")
+		w, err := NewSegmentWriter(failingWriter{failure}, aead, []byte("123456789012"), nil, 4)
+		require.NoError(t, err)
+		n, err := w.Write([]byte("0123456789"))
+		assert.Equal(t, failure, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, failure, w.Close())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		failure := errors.New("read failed")
+		r, err := NewSegmentReader(iotest.ErrReader(failure), aead, []byte("123456789012"), nil, 64)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.Equal(t, failure, err)
+	})
+
+	t.Run("auth error", func(t *testing.T) {
+		err := SegmentAuthError{index: 3}
+		assert.Equal(t, "segment 3 failed authentication, the stream was modified or truncated", err.Error())
+		assert.Equal(t, "DGL-CIPHER-015", err.Code())
+	})
+}
+
+// failingWriter fails every write.
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestSegmentWriter_Checkpoint(t *testing.T) {
+	aead := newSegmentAEAD(t)
+	data := bytes.Repeat([]byte("0123456789abcdef-"), 20) // 340 bytes
+	nonce, aad := []byte("123456789012"), []byte("aad")
+	want := sealSegments(t, aead, data, 64, 1000)
+
+	for _, at := range []int{0, 1, 64, 150, 340} {
+		var buf bytes.Buffer
+		w, err := NewSegmentWriter(&buf, aead, nonce, aad, 64)
+		require.NoError(t, err)
+		_, err = w.Write(data[:at])
+		require.NoError(t, err)
+		state, err := w.MarshalBinary()
+		require.NoError(t, err)
+		offset := w.Offset()
+		// Output written after the checkpoint is lost
+		_, err = w.Write(data[at:])
+		require.NoError(t, err)
+
+		resumed := bytes.NewBuffer(bytes.Clone(buf.Bytes()[:offset]))
+		r, err := NewSegmentWriter(resumed, aead, nonce, aad, 64)
+		require.NoError(t, err)
+		require.NoError(t, r.UnmarshalBinary(state))
+		assert.Equal(t, offset, r.Offset())
+		_, err = r.Write(data[at:])
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Equal(t, want, resumed.Bytes(), "checkpoint at %d", at)
+	}
+
+	t.Run("mismatched or malformed", func(t *testing.T) {
+		w, err := NewSegmentWriter(io.Discard, aead, nonce, aad, 64)
+		require.NoError(t, err)
+		_, err = w.Write(data[:100])
+		require.NoError(t, err)
+		state, err := w.MarshalBinary()
+		require.NoError(t, err)
+
+		other, err := NewSegmentWriter(io.Discard, aead, []byte("210987654321"), aad, 64)
+		require.NoError(t, err)
+		assert.IsType(t, InvalidStreamStateError{}, other.UnmarshalBinary(state))
+		other, err = NewSegmentWriter(io.Discard, aead, nonce, aad, 32)
+		require.NoError(t, err)
+		assert.IsType(t, InvalidStreamStateError{}, other.UnmarshalBinary(state))
+		other, err = NewSegmentWriter(io.Discard, aead, nonce, []byte("other"), 64)
+		require.NoError(t, err)
+		assert.IsType(t, InvalidStreamStateError{}, other.UnmarshalBinary(state))
+
+		block, err := aes.NewCipher([]byte("6543210987654321"))
+		require.NoError(t, err)
+		otherAEAD, err := stdCipher.NewGCM(block)
+		require.NoError(t, err)
+		other, err = NewSegmentWriter(io.Discard, otherAEAD, nonce, aad, 64)
+		require.NoError(t, err)
+		assert.IsType(t, InvalidStreamStateError{}, other.UnmarshalBinary(state))
+		assert.IsType(t, InvalidStreamStateError{}, w.UnmarshalBinary(state[:5]))
+		assert.IsType(t, InvalidStreamStateError{}, w.UnmarshalBinary(append(state, make([]byte, 64)...)))
+	})
+
+	t.Run("after close", func(t *testing.T) {
+		w, err := NewSegmentWriter(io.Discard, aead, nonce, aad, 64)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		_, err = w.MarshalBinary()
+		assert.IsType(t, InvalidSegmentError{}, err)
+		assert.IsType(t, InvalidSegmentError{}, w.UnmarshalBinary(nil))
+	})
+}
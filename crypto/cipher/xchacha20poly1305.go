@@ -0,0 +1,45 @@
+package cipher
+
+// XChaCha20Poly1305Cipher defines a XChaCha20Poly1305Cipher struct.
+//
+// XChaCha20-Poly1305 takes a 24-byte nonce, long enough to be drawn at random
+// from crypto/rand for every message without keeping a counter: random 12-byte
+// ChaCha20-Poly1305 nonces risk repeating after a few billion messages under
+// one key, 24-byte ones never do in practice.
+type XChaCha20Poly1305Cipher struct {
+	baseCipher
+	Nonce         []byte
+	AAD           []byte
+	SegmentSize   int
+	Release       ReleasePolicy
+	KeyCommitment bool
+}
+
+// NewXChaCha20Poly1305Cipher returns a new XChaCha20Poly1305Cipher instance.
+func NewXChaCha20Poly1305Cipher() (c *XChaCha20Poly1305Cipher) {
+	return &XChaCha20Poly1305Cipher{}
+}
+
+// SetNonce sets the 24-byte nonce for the cipher.
+func (c *XChaCha20Poly1305Cipher) SetNonce(nonce []byte) {
+	c.Nonce = nonce
+}
+
+// SetAAD sets the additional authenticated data (AAD) for the cipher.
+func (c *XChaCha20Poly1305Cipher) SetAAD(aad []byte) {
+	c.AAD = aad
+}
+
+// SetAADFields sets the additional authenticated data (AAD) to the canonical
+// encoding of fields.
+func (c *XChaCha20Poly1305Cipher) SetAADFields(fields AADFields) {
+	c.AAD = fields.Bytes()
+}
+
+// SetSegmentSize makes the stream encrypters seal the stream in segments of
+// size plaintext bytes, as for ChaCha20Poly1305Cipher. Without a segment size
+// the stream encrypters hold the whole stream in memory. Zero disables
+// segments.
+func (c *XChaCha20Poly1305Cipher) SetSegmentSize(size int) {
+	c.SegmentSize = size
+}
@@ -0,0 +1,144 @@
+package commitment
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedHashError represents an error when a hash committer has no hash constructor.
+type UnsupportedHashError struct{}
+
+// Error returns a formatted error message describing the missing hash.
+func (e UnsupportedHashError) Error() string {
+	return "crypto/commitment: hash constructor cannot be nil"
+}
+
+// Code returns the stable error code DGL-COMMITMENT-001.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-COMMITMENT-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "")
+}
+
+// UnsupportedCurveError represents an error when a Pedersen curve name is unknown.
+type UnsupportedCurveError struct {
+	Name string // The unknown curve name
+}
+
+// Error returns a formatted error message describing the unknown curve.
+func (e UnsupportedCurveError) Error() string {
+	return fmt.Sprintf("crypto/commitment: unsupported curve '%s'", e.Name)
+}
+
+// Code returns the stable error code DGL-COMMITMENT-002.
+func (e UnsupportedCurveError) Code() string {
+	return "DGL-COMMITMENT-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedCurveError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "", "name", e.Name)
+}
+
+// InvalidSaltError represents an error when a hash commitment salt is too short.
+type InvalidSaltError struct {
+	Size int // Size of the provided salt
+}
+
+// Error returns a formatted error message describing the short salt.
+func (e InvalidSaltError) Error() string {
+	return fmt.Sprintf("crypto/commitment: invalid salt size %d, must be at least %d bytes", e.Size, MinSaltSize)
+}
+
+// Code returns the stable error code DGL-COMMITMENT-003.
+func (e InvalidSaltError) Code() string {
+	return "DGL-COMMITMENT-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSaltError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "", "size", e.Size)
+}
+
+// InvalidCommitmentError represents an error when a Pedersen commitment is not a valid curve point.
+type InvalidCommitmentError struct{}
+
+// Error returns a formatted error message describing the malformed commitment.
+func (e InvalidCommitmentError) Error() string {
+	return "crypto/commitment: invalid commitment"
+}
+
+// Code returns the stable error code DGL-COMMITMENT-004.
+func (e InvalidCommitmentError) Code() string {
+	return "DGL-COMMITMENT-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCommitmentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "")
+}
+
+// InvalidOpeningError represents an error when a value or blinding factor is missing or out of range.
+type InvalidOpeningError struct{}
+
+// Error returns a formatted error message describing the invalid opening.
+func (e InvalidOpeningError) Error() string {
+	return "crypto/commitment: value and blinding factor must be non-negative and below the group order"
+}
+
+// Code returns the stable error code DGL-COMMITMENT-005.
+func (e InvalidOpeningError) Code() string {
+	return "DGL-COMMITMENT-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidOpeningError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "")
+}
+
+// LengthMismatchError represents an error when batch verification inputs have different lengths.
+type LengthMismatchError struct{}
+
+// Error returns a formatted error message describing the length mismatch.
+func (e LengthMismatchError) Error() string {
+	return "crypto/commitment: commitments, values and blinding factors must have the same length"
+}
+
+// Code returns the stable error code DGL-COMMITMENT-006.
+func (e LengthMismatchError) Code() string {
+	return "DGL-COMMITMENT-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e LengthMismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "")
+}
+
+// VerificationError represents an error when a commitment does not match its opening.
+// In batch verification Index is the first mismatching opening, or -1 when the
+// combined Pedersen check fails without identifying a single commitment.
+type VerificationError struct {
+	Index int // Index of the failing opening
+}
+
+// Error returns a formatted error message describing the mismatching opening.
+func (e VerificationError) Error() string {
+	if e.Index < 0 {
+		return "crypto/commitment: batch verification failed"
+	}
+	return fmt.Sprintf("crypto/commitment: commitment %d does not match its opening", e.Index)
+}
+
+// Code returns the stable error code DGL-COMMITMENT-007.
+func (e VerificationError) Code() string {
+	return "DGL-COMMITMENT-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e VerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/commitment", "Pedersen", "verify", "index", e.Index)
+}
@@ -0,0 +1,98 @@
+// Package commitment implements commit/reveal schemes for fairness protocols such as
+// sealed-bid auctions and lotteries. Hash commitments bind a message with a random
+// salt under any hash, Pedersen commitments hide integer values over elliptic curves
+// and are additively homomorphic. Both support batch verification of many openings.
+package commitment
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+const (
+	DefaultSaltSize = 32 // Salt size used by HashCommitter.Commit
+	MinSaltSize     = 16 // Minimum salt size accepted for hiding commitments
+)
+
+// HashOpening holds a hash commitment with the message and salt that open it.
+type HashOpening struct {
+	Commitment []byte
+	Message    []byte
+	Salt       []byte
+}
+
+// HashCommitter creates and verifies salted hash commitments.
+// A commitment is H(len(salt) || salt || message) with a 4-byte big-endian length.
+type HashCommitter struct {
+	hash  func() hash.Hash
+	Error error // Error field for storing configuration errors
+}
+
+// NewHashCommitter returns a new HashCommitter using the hash constructor h,
+// for example sha256.New or sm3.New.
+func NewHashCommitter(h func() hash.Hash) *HashCommitter {
+	c := &HashCommitter{hash: h}
+	if h == nil {
+		c.Error = UnsupportedHashError{}
+	}
+	return c
+}
+
+// Commit commits to msg with a fresh random salt, which must be kept until reveal.
+func (c *HashCommitter) Commit(msg []byte) (commitment, salt []byte, err error) {
+	if c.Error != nil {
+		return nil, nil, c.Error
+	}
+	salt = make([]byte, DefaultSaltSize)
+	if _, err = io.ReadFull(utils.Rand(), salt); err != nil {
+		return nil, nil, err
+	}
+	commitment, err = c.CommitWithSalt(msg, salt)
+	return commitment, salt, err
+}
+
+// CommitWithSalt commits to msg with a caller provided salt of at least MinSaltSize bytes.
+func (c *HashCommitter) CommitWithSalt(msg, salt []byte) ([]byte, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+	if len(salt) < MinSaltSize {
+		return nil, InvalidSaltError{Size: len(salt)}
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(salt)))
+	h := c.hash()
+	h.Write(size[:])
+	h.Write(salt)
+	h.Write(msg)
+	return h.Sum(nil), nil
+}
+
+// Verify checks in constant time that msg and salt open commitment.
+func (c *HashCommitter) Verify(commitment, msg, salt []byte) error {
+	expected, err := c.CommitWithSalt(msg, salt)
+	if err != nil {
+		return err
+	}
+	if !utils.ConstantTimeEqual(expected, commitment) {
+		return VerificationError{Index: 0}
+	}
+	return nil
+}
+
+// BatchVerify checks every opening and reports the index of the first one that fails.
+func (c *HashCommitter) BatchVerify(openings []HashOpening) error {
+	for i, o := range openings {
+		err := c.Verify(o.Commitment, o.Message, o.Salt)
+		if _, ok := err.(VerificationError); ok {
+			return VerificationError{Index: i}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package commitment
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashCommitter(t *testing.T) {
+	c := NewHashCommitter(sha256.New)
+	require.NoError(t, c.Error)
+
+	bid := []byte("bid: 1500")
+	commitment, salt, err := c.Commit(bid)
+	require.NoError(t, err)
+	assert.Len(t, commitment, sha256.Size)
+	assert.Len(t, salt, DefaultSaltSize)
+	assert.NoError(t, c.Verify(commitment, bid, salt))
+
+	assert.Equal(t, VerificationError{Index: 0}, c.Verify(commitment, []byte("bid: 1501"), salt))
+	salt[0] ^= 1
+	assert.Equal(t, VerificationError{Index: 0}, c.Verify(commitment, bid, salt))
+
+	t.Run("hiding", func(t *testing.T) {
+		a, _, err := c.Commit(bid)
+		require.NoError(t, err)
+		b, _, err := c.Commit(bid)
+		require.NoError(t, err)
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("with salt", func(t *testing.T) {
+		salt := make([]byte, MinSaltSize)
+		a, err := c.CommitWithSalt(bid, salt)
+		require.NoError(t, err)
+		b, err := c.CommitWithSalt(bid, salt)
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+
+		// The salt length is bound, so salt/message boundaries cannot shift.
+		shifted, err := c.CommitWithSalt(bid[1:], append(salt, bid[0]))
+		require.NoError(t, err)
+		assert.NotEqual(t, a, shifted)
+
+		_, err = c.CommitWithSalt(bid, salt[:15])
+		assert.Equal(t, InvalidSaltError{Size: 15}, err)
+		assert.Equal(t, "crypto/commitment: invalid salt size 15, must be at least 16 bytes", err.Error())
+		assert.Equal(t, InvalidSaltError{Size: 0}, c.Verify(a, bid, nil))
+	})
+
+	t.Run("sm3", func(t *testing.T) {
+		c := NewHashCommitter(sm3.New)
+		commitment, salt, err := c.Commit(bid)
+		require.NoError(t, err)
+		assert.NoError(t, c.Verify(commitment, bid, salt))
+	})
+
+	t.Run("nil hash", func(t *testing.T) {
+		c := NewHashCommitter(nil)
+		assert.Equal(t, UnsupportedHashError{}, c.Error)
+		assert.Equal(t, "crypto/commitment: hash constructor cannot be nil", c.Error.Error())
+		_, _, err := c.Commit(bid)
+		assert.Equal(t, c.Error, err)
+		_, err = c.CommitWithSalt(bid, make([]byte, 16))
+		assert.Equal(t, c.Error, err)
+	})
+}
+
+func TestHashCommitter_BatchVerify(t *testing.T) {
+	c := NewHashCommitter(sha256.New)
+	openings := make([]HashOpening, 5)
+	for i := range openings {
+		msg := []byte{byte(i)}
+		commitment, salt, err := c.Commit(msg)
+		require.NoError(t, err)
+		openings[i] = HashOpening{Commitment: commitment, Message: msg, Salt: salt}
+	}
+	assert.NoError(t, c.BatchVerify(openings))
+	assert.NoError(t, c.BatchVerify(nil))
+
+	openings[3].Message = []byte{9}
+	err := c.BatchVerify(openings)
+	assert.Equal(t, VerificationError{Index: 3}, err)
+	assert.Equal(t, "crypto/commitment: commitment 3 does not match its opening", err.Error())
+
+	openings[1].Salt = nil
+	assert.Equal(t, InvalidSaltError{Size: 0}, c.BatchVerify(openings))
+}
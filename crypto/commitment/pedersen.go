@@ -0,0 +1,263 @@
+package commitment
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Curve names accepted by NewPedersen.
+const (
+	P256 = "P-256"
+	P384 = "P-384"
+	P521 = "P-521"
+	SM2  = "SM2-P-256"
+)
+
+// curves maps curve names to their constructors.
+var curves = map[string]func() elliptic.Curve{
+	P256: elliptic.P256,
+	P384: elliptic.P384,
+	P521: elliptic.P521,
+	SM2:  sm2.NewCurve,
+}
+
+// batchBits is the size of the random weights used by batch verification.
+const batchBits = 128
+
+// Pedersen creates and verifies Pedersen commitments C = vG + rH, where G is the
+// curve base point and H is a second generator derived by hashing, so that nobody
+// knows its discrete logarithm with respect to G.
+type Pedersen struct {
+	curve  elliptic.Curve
+	hx, hy *big.Int
+	Error  error // Error field for storing configuration errors
+}
+
+// NewPedersen returns a new Pedersen instance over the named curve.
+func NewPedersen(name string) *Pedersen {
+	p := &Pedersen{}
+	newCurve, ok := curves[name]
+	if !ok {
+		p.Error = UnsupportedCurveError{Name: name}
+		return p
+	}
+	p.curve = newCurve()
+	p.hx, p.hy = generator(p.curve, name)
+	return p
+}
+
+// H returns the compressed encoding of the second generator.
+func (p *Pedersen) H() []byte {
+	if p.Error != nil {
+		return nil
+	}
+	return elliptic.MarshalCompressed(p.curve, p.hx, p.hy)
+}
+
+// Commit commits to value with a fresh random blinding factor, which must be kept until reveal.
+func (p *Pedersen) Commit(value *big.Int) (commitment []byte, blinding *big.Int, err error) {
+	if p.Error != nil {
+		return nil, nil, p.Error
+	}
+	blinding, err = rand.Int(utils.Rand(), p.curve.Params().N)
+	if err != nil {
+		return nil, nil, err
+	}
+	commitment, err = p.CommitWithBlinding(value, blinding)
+	return commitment, blinding, err
+}
+
+// CommitWithBlinding commits to value with the blinding factor r.
+// Both must lie in [0, N) where N is the curve order.
+func (p *Pedersen) CommitWithBlinding(value, r *big.Int) ([]byte, error) {
+	if p.Error != nil {
+		return nil, p.Error
+	}
+	if !p.inRange(value) || !p.inRange(r) {
+		return nil, InvalidOpeningError{}
+	}
+	x, y := p.combine(value, r)
+	if isInfinity(x, y) {
+		return nil, InvalidOpeningError{}
+	}
+	return elliptic.MarshalCompressed(p.curve, x, y), nil
+}
+
+// Verify checks that value and r open commitment.
+func (p *Pedersen) Verify(commitment []byte, value, r *big.Int) error {
+	expected, err := p.CommitWithBlinding(value, r)
+	if err != nil {
+		return err
+	}
+	if _, _, err = p.parse(commitment); err != nil {
+		return err
+	}
+	if string(expected) != string(commitment) {
+		return VerificationError{Index: 0}
+	}
+	return nil
+}
+
+// Add returns the commitment to the sum of the values committed in a and b,
+// opened by the sum of their blinding factors modulo N.
+func (p *Pedersen) Add(a, b []byte) ([]byte, error) {
+	if p.Error != nil {
+		return nil, p.Error
+	}
+	ax, ay, err := p.parse(a)
+	if err != nil {
+		return nil, err
+	}
+	bx, by, err := p.parse(b)
+	if err != nil {
+		return nil, err
+	}
+	x, y := p.add(ax, ay, bx, by)
+	if isInfinity(x, y) {
+		return nil, InvalidCommitmentError{}
+	}
+	return elliptic.MarshalCompressed(p.curve, x, y), nil
+}
+
+// BatchVerify checks many openings at once with a random linear combination,
+// which costs one scalar multiplication per commitment instead of two.
+// A failure is reported as a VerificationError with Index -1.
+func (p *Pedersen) BatchVerify(commitments [][]byte, values, blindings []*big.Int) error {
+	if p.Error != nil {
+		return p.Error
+	}
+	if len(commitments) != len(values) || len(commitments) != len(blindings) {
+		return LengthMismatchError{}
+	}
+	n := p.curve.Params().N
+	bound := new(big.Int).Lsh(big.NewInt(1), batchBits)
+	sumV, sumR := new(big.Int), new(big.Int)
+	var cx, cy *big.Int
+	for i, c := range commitments {
+		if !p.inRange(values[i]) || !p.inRange(blindings[i]) {
+			return InvalidOpeningError{}
+		}
+		x, y, err := p.parse(c)
+		if err != nil {
+			return err
+		}
+		w, err := rand.Int(utils.Rand(), bound)
+		if err != nil {
+			return err
+		}
+		w.Add(w, big.NewInt(1))
+		sumV.Add(sumV, new(big.Int).Mul(w, values[i])).Mod(sumV, n)
+		sumR.Add(sumR, new(big.Int).Mul(w, blindings[i])).Mod(sumR, n)
+		x, y = p.scalarMult(x, y, w)
+		cx, cy = p.add(cx, cy, x, y)
+	}
+	ex, ey := p.combine(sumV, sumR)
+	if !equal(cx, cy, ex, ey) {
+		return VerificationError{Index: -1}
+	}
+	return nil
+}
+
+// combine computes vG + rH.
+func (p *Pedersen) combine(v, r *big.Int) (*big.Int, *big.Int) {
+	vx, vy := p.scalarBaseMult(v)
+	rx, ry := p.scalarMult(p.hx, p.hy, r)
+	return p.add(vx, vy, rx, ry)
+}
+
+// parse decodes a compressed commitment.
+func (p *Pedersen) parse(b []byte) (*big.Int, *big.Int, error) {
+	x, y := elliptic.UnmarshalCompressed(p.curve, b)
+	if x == nil {
+		return nil, nil, InvalidCommitmentError{}
+	}
+	return x, y, nil
+}
+
+// inRange reports whether k lies in [0, N).
+func (p *Pedersen) inRange(k *big.Int) bool {
+	return k != nil && k.Sign() >= 0 && k.Cmp(p.curve.Params().N) < 0
+}
+
+// scalarBaseMult computes kG, returning nil coordinates for the point at infinity.
+func (p *Pedersen) scalarBaseMult(k *big.Int) (*big.Int, *big.Int) {
+	if k.Sign() == 0 {
+		return nil, nil
+	}
+	return normalize(p.curve.ScalarBaseMult(p.scalarBytes(k)))
+}
+
+// scalarMult computes k(x, y), returning nil coordinates for the point at infinity.
+func (p *Pedersen) scalarMult(x, y, k *big.Int) (*big.Int, *big.Int) {
+	if k.Sign() == 0 || isInfinity(x, y) {
+		return nil, nil
+	}
+	return normalize(p.curve.ScalarMult(x, y, p.scalarBytes(k)))
+}
+
+// add computes the sum of two points, either of which may be the point at infinity.
+func (p *Pedersen) add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if isInfinity(x1, y1) {
+		return x2, y2
+	}
+	if isInfinity(x2, y2) {
+		return x1, y1
+	}
+	return normalize(p.curve.Add(x1, y1, x2, y2))
+}
+
+// scalarBytes encodes k reduced modulo N as a fixed size big-endian scalar.
+func (p *Pedersen) scalarBytes(k *big.Int) []byte {
+	n := p.curve.Params().N
+	return new(big.Int).Mod(k, n).FillBytes(make([]byte, (n.BitLen()+7)/8))
+}
+
+// generator derives the second generator by hashing the curve name with a counter
+// until the result is the x-coordinate of a curve point with even y.
+func generator(curve elliptic.Curve, name string) (*big.Int, *big.Int) {
+	size := (curve.Params().BitSize + 7) / 8
+	for counter := uint32(0); ; counter++ {
+		x := make([]byte, 0, size+sha256.Size)
+		for block := uint32(0); len(x) < size; block++ {
+			h := sha256.New()
+			h.Write([]byte("dongle/commitment/pedersen/" + name))
+			binary.Write(h, binary.BigEndian, counter)
+			binary.Write(h, binary.BigEndian, block)
+			x = h.Sum(x)
+		}
+		x = x[:size]
+		// Clear the excess high bits so the candidate stays within the field size.
+		x[0] &= 0xff >> (size*8 - curve.Params().BitSize)
+		if hx, hy := elliptic.UnmarshalCompressed(curve, append([]byte{2}, x...)); hx != nil {
+			return hx, hy
+		}
+	}
+}
+
+// normalize maps the (0, 0) encoding of the point at infinity used by the
+// standard library curves to nil coordinates.
+func normalize(x, y *big.Int) (*big.Int, *big.Int) {
+	if isInfinity(x, y) {
+		return nil, nil
+	}
+	return x, y
+}
+
+// isInfinity reports whether (x, y) encodes the point at infinity.
+func isInfinity(x, y *big.Int) bool {
+	return x == nil || y == nil || (x.Sign() == 0 && y.Sign() == 0)
+}
+
+// equal reports whether two points, either of which may be infinity, are equal.
+func equal(x1, y1, x2, y2 *big.Int) bool {
+	if isInfinity(x1, y1) || isInfinity(x2, y2) {
+		return isInfinity(x1, y1) && isInfinity(x2, y2)
+	}
+	return x1.Cmp(x2) == 0 && y1.Cmp(y2) == 0
+}
@@ -0,0 +1,134 @@
+package commitment
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPedersen(t *testing.T) {
+	for _, name := range []string{P256, P384, P521, SM2} {
+		t.Run(name, func(t *testing.T) {
+			p := NewPedersen(name)
+			require.NoError(t, p.Error)
+			h := p.H()
+			assert.Equal(t, h, NewPedersen(name).H())
+			assert.Len(t, h, 1+(p.curve.Params().BitSize+7)/8)
+
+			value := big.NewInt(1500)
+			commitment, r, err := p.Commit(value)
+			require.NoError(t, err)
+			assert.NoError(t, p.Verify(commitment, value, r))
+			assert.Equal(t, VerificationError{Index: 0}, p.Verify(commitment, big.NewInt(1501), r))
+			assert.Equal(t, VerificationError{Index: 0}, p.Verify(commitment, value, new(big.Int).Add(r, big.NewInt(1))))
+
+			// Commitments are additively homomorphic.
+			other := big.NewInt(250)
+			c2, r2, err := p.Commit(other)
+			require.NoError(t, err)
+			sum, err := p.Add(commitment, c2)
+			require.NoError(t, err)
+			rSum := new(big.Int).Add(r, r2)
+			rSum.Mod(rSum, p.curve.Params().N)
+			assert.NoError(t, p.Verify(sum, big.NewInt(1750), rSum))
+		})
+	}
+}
+
+func TestPedersen_Edges(t *testing.T) {
+	p := NewPedersen(SM2)
+	n := p.curve.Params().N
+
+	t.Run("zero value", func(t *testing.T) {
+		c, err := p.CommitWithBlinding(big.NewInt(0), big.NewInt(7))
+		require.NoError(t, err)
+		assert.NoError(t, p.Verify(c, big.NewInt(0), big.NewInt(7)))
+		c, err = p.CommitWithBlinding(big.NewInt(7), big.NewInt(0))
+		require.NoError(t, err)
+		assert.NoError(t, p.Verify(c, big.NewInt(7), big.NewInt(0)))
+	})
+
+	t.Run("invalid opening", func(t *testing.T) {
+		for _, tc := range [][2]*big.Int{
+			{nil, big.NewInt(1)},
+			{big.NewInt(1), nil},
+			{big.NewInt(-1), big.NewInt(1)},
+			{n, big.NewInt(1)},
+			{big.NewInt(0), big.NewInt(0)},
+		} {
+			_, err := p.CommitWithBlinding(tc[0], tc[1])
+			assert.Equal(t, InvalidOpeningError{}, err)
+		}
+		assert.Equal(t, "crypto/commitment: value and blinding factor must be non-negative and below the group order", InvalidOpeningError{}.Error())
+		_, _, err := p.Commit(n)
+		assert.Equal(t, InvalidOpeningError{}, err)
+	})
+
+	t.Run("invalid commitment", func(t *testing.T) {
+		err := p.Verify([]byte{2, 1}, big.NewInt(1), big.NewInt(1))
+		assert.Equal(t, InvalidCommitmentError{}, err)
+		assert.Equal(t, "crypto/commitment: invalid commitment", err.Error())
+		assert.Equal(t, InvalidOpeningError{}, p.Verify(nil, nil, nil))
+
+		c, err := p.CommitWithBlinding(big.NewInt(1), big.NewInt(1))
+		require.NoError(t, err)
+		_, err = p.Add([]byte{2}, c)
+		assert.Equal(t, InvalidCommitmentError{}, err)
+		_, err = p.Add(c, []byte{2})
+		assert.Equal(t, InvalidCommitmentError{}, err)
+
+		// Adding a commitment to its negation gives the point at infinity.
+		neg, err := p.CommitWithBlinding(new(big.Int).Sub(n, big.NewInt(1)), new(big.Int).Sub(n, big.NewInt(1)))
+		require.NoError(t, err)
+		_, err = p.Add(c, neg)
+		assert.Equal(t, InvalidCommitmentError{}, err)
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		p := NewPedersen("P-192")
+		assert.Equal(t, UnsupportedCurveError{Name: "P-192"}, p.Error)
+		assert.Equal(t, "crypto/commitment: unsupported curve 'P-192'", p.Error.Error())
+		assert.Nil(t, p.H())
+		_, _, err := p.Commit(big.NewInt(1))
+		assert.Equal(t, p.Error, err)
+		_, err = p.CommitWithBlinding(big.NewInt(1), big.NewInt(1))
+		assert.Equal(t, p.Error, err)
+		_, err = p.Add(nil, nil)
+		assert.Equal(t, p.Error, err)
+		assert.Equal(t, p.Error, p.BatchVerify(nil, nil, nil))
+	})
+}
+
+func TestPedersen_BatchVerify(t *testing.T) {
+	for _, name := range []string{P256, SM2} {
+		t.Run(name, func(t *testing.T) {
+			p := NewPedersen(name)
+			commitments := make([][]byte, 8)
+			values := make([]*big.Int, 8)
+			blindings := make([]*big.Int, 8)
+			for i := range commitments {
+				values[i] = big.NewInt(int64(i * 100))
+				c, r, err := p.Commit(values[i])
+				require.NoError(t, err)
+				commitments[i], blindings[i] = c, r
+			}
+			assert.NoError(t, p.BatchVerify(commitments, values, blindings))
+			assert.NoError(t, p.BatchVerify(nil, nil, nil))
+
+			values[5] = big.NewInt(501)
+			err := p.BatchVerify(commitments, values, blindings)
+			assert.Equal(t, VerificationError{Index: -1}, err)
+			assert.Equal(t, "crypto/commitment: batch verification failed", err.Error())
+
+			assert.Equal(t, LengthMismatchError{}, p.BatchVerify(commitments, values[:7], blindings))
+			assert.Equal(t, "crypto/commitment: commitments, values and blinding factors must have the same length", LengthMismatchError{}.Error())
+			values[5] = big.NewInt(-1)
+			assert.Equal(t, InvalidOpeningError{}, p.BatchVerify(commitments, values, blindings))
+			values[5] = big.NewInt(500)
+			commitments[2] = []byte{0}
+			assert.Equal(t, InvalidCommitmentError{}, p.BatchVerify(commitments, values, blindings))
+		})
+	}
+}
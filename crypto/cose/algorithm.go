@@ -0,0 +1,166 @@
+package cose
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"math/big"
+	"strconv"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Algorithm is a COSE algorithm identifier from the IANA COSE Algorithms registry.
+type Algorithm int64
+
+// The supported algorithms.
+const (
+	ES256   Algorithm = -7  // ECDSA with P-256 and SHA-256
+	ES384   Algorithm = -35 // ECDSA with P-384 and SHA-384
+	ES512   Algorithm = -36 // ECDSA with P-521 and SHA-512
+	EdDSA   Algorithm = -8  // Ed25519
+	A128GCM Algorithm = 1   // AES-GCM with a 128-bit key and 128-bit tag
+	A192GCM Algorithm = 2   // AES-GCM with a 192-bit key and 128-bit tag
+	A256GCM Algorithm = 3   // AES-GCM with a 256-bit key and 128-bit tag
+)
+
+// NonceSize is the size of the AES-GCM IV.
+const NonceSize = 12
+
+// String returns the registered name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case ES256:
+		return "ES256"
+	case ES384:
+		return "ES384"
+	case ES512:
+		return "ES512"
+	case EdDSA:
+		return "EdDSA"
+	case A128GCM:
+		return "A128GCM"
+	case A192GCM:
+		return "A192GCM"
+	case A256GCM:
+		return "A256GCM"
+	}
+	return strconv.FormatInt(int64(a), 10)
+}
+
+// ecdsaParams maps each ECDSA algorithm to its curve and hash.
+var ecdsaParams = map[Algorithm]struct {
+	curve elliptic.Curve
+	hash  crypto.Hash
+}{
+	ES256: {elliptic.P256(), crypto.SHA256},
+	ES384: {elliptic.P384(), crypto.SHA384},
+	ES512: {elliptic.P521(), crypto.SHA512},
+}
+
+// signatureAlgorithm returns the signature algorithm for a public key.
+func signatureAlgorithm(pub crypto.PublicKey) (Algorithm, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		for alg, p := range ecdsaParams {
+			if p.curve == k.Curve {
+				return alg, nil
+			}
+		}
+	case ed25519.PublicKey:
+		return EdDSA, nil
+	}
+	return 0, InvalidKeyError{Key: pub}
+}
+
+// sign signs toBeSigned with key, producing the fixed size COSE signature encoding.
+func (a Algorithm) sign(key crypto.Signer, toBeSigned []byte) ([]byte, error) {
+	if a == EdDSA {
+		sig, err := key.Sign(utils.Rand(), toBeSigned, crypto.Hash(0))
+		if err != nil {
+			return nil, SignError{Err: err}
+		}
+		return sig, nil
+	}
+	p := ecdsaParams[a]
+	h := p.hash.New()
+	h.Write(toBeSigned)
+	der, err := key.Sign(utils.Rand(), h.Sum(nil), p.hash)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(der, &rs); err != nil {
+		return nil, SignError{Err: err}
+	}
+	size := (p.curve.Params().BitSize + 7) / 8
+	sig := make([]byte, 2*size)
+	rs.R.FillBytes(sig[:size])
+	rs.S.FillBytes(sig[size:])
+	return sig, nil
+}
+
+// verify reports whether sig is a valid signature over toBeSigned. A key of the
+// wrong type or curve for the algorithm is an error rather than a failed check.
+func (a Algorithm) verify(pub crypto.PublicKey, toBeSigned, sig []byte) error {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		if a != EdDSA || len(k) != ed25519.PublicKeySize {
+			break
+		}
+		if !ed25519.Verify(k, toBeSigned, sig) {
+			return SignatureVerificationError{}
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		p, ok := ecdsaParams[a]
+		if !ok || p.curve != k.Curve {
+			break
+		}
+		size := (p.curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return SignatureVerificationError{}
+		}
+		h := p.hash.New()
+		h.Write(toBeSigned)
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, h.Sum(nil), r, s) {
+			return SignatureVerificationError{}
+		}
+		return nil
+	}
+	if _, ok := ecdsaParams[a]; !ok && a != EdDSA {
+		return UnsupportedAlgorithmError{Algorithm: a}
+	}
+	return InvalidKeyError{Algorithm: a, Key: pub}
+}
+
+// aeadAlgorithm returns the AES-GCM algorithm for a key size.
+func aeadAlgorithm(key []byte) (Algorithm, error) {
+	switch len(key) {
+	case 16:
+		return A128GCM, nil
+	case 24:
+		return A192GCM, nil
+	case 32:
+		return A256GCM, nil
+	}
+	return 0, InvalidKeyError{Key: key}
+}
+
+// aead returns the AES-GCM cipher for a key of the algorithm.
+func (a Algorithm) aead(key []byte) (cipher.AEAD, error) {
+	if a != A128GCM && a != A192GCM && a != A256GCM {
+		return nil, UnsupportedAlgorithmError{Algorithm: a}
+	}
+	if alg, _ := aeadAlgorithm(key); alg != a {
+		return nil, InvalidKeyError{Algorithm: a, Key: key}
+	}
+	block, _ := aes.NewCipher(key)
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,135 @@
+// Package cose implements CBOR Object Signing and Encryption (RFC 9052) single
+// signer and single recipient messages, COSE_Sign1 and COSE_Encrypt0, and CBOR Web
+// Tokens (RFC 8392) on top of them. Signatures use ES256, ES384, ES512 or EdDSA
+// through any crypto.Signer, such as the keys parsed from a dongle key pair, and
+// encryption uses AES-GCM. It targets IoT and WebAuthn adjacent protocols.
+package cose
+
+import (
+	"errors"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+)
+
+// Header parameter labels registered by RFC 9052.
+const (
+	HeaderAlgorithm   int64 = 1 // Algorithm identifier
+	HeaderCritical    int64 = 2 // Labels of critical header parameters
+	HeaderContentType int64 = 3 // Content type of the payload
+	HeaderKeyID       int64 = 4 // Key identifier, a byte string
+	HeaderIV          int64 = 5 // Full initialization vector
+)
+
+// CBOR tags of the supported structures.
+const (
+	tagEncrypt0 = 16
+	tagSign1    = 18
+	tagCWT      = 61
+)
+
+// Headers holds the protected and unprotected header parameters of a message.
+// Labels are int64 or string and values are the CBOR values of package cbor:
+// int64, []byte, string, []any, map[any]any, bool, nil and float64.
+type Headers struct {
+	Protected   map[any]any // Parameters covered by the signature or authentication tag
+	Unprotected map[any]any // Parameters outside the cryptographic protection
+}
+
+// Algorithm returns the algorithm header parameter, preferring the protected header.
+func (h Headers) Algorithm() Algorithm {
+	v, ok := h.Protected[HeaderAlgorithm]
+	if !ok {
+		v = h.Unprotected[HeaderAlgorithm]
+	}
+	alg, _ := v.(int64)
+	return Algorithm(alg)
+}
+
+// KeyID returns the key identifier header parameter, preferring the protected header.
+func (h Headers) KeyID() []byte {
+	v, ok := h.Protected[HeaderKeyID]
+	if !ok {
+		v = h.Unprotected[HeaderKeyID]
+	}
+	kid, _ := v.([]byte)
+	return kid
+}
+
+// protect returns the protected header with alg set and its encoding as a byte string.
+func (h Headers) protect(alg Algorithm) (map[any]any, []byte, error) {
+	protected := make(map[any]any, len(h.Protected)+1)
+	for k, v := range h.Protected {
+		protected[k] = v
+	}
+	protected[HeaderAlgorithm] = int64(alg)
+	if _, ok := h.Unprotected[HeaderAlgorithm]; ok {
+		return nil, nil, InvalidMessageError{Err: errors.New("algorithm must not be an unprotected header parameter")}
+	}
+	raw, err := cbor.Marshal(protected)
+	if err != nil {
+		return nil, nil, InvalidMessageError{Err: err}
+	}
+	return protected, raw, nil
+}
+
+// parseHeaders decodes and checks the headers of a received message.
+func parseHeaders(rawProtected, unprotected any) (Headers, []byte, error) {
+	raw, ok := rawProtected.([]byte)
+	if !ok {
+		return Headers{}, nil, InvalidMessageError{Err: errors.New("protected header must be a byte string")}
+	}
+	h := Headers{Protected: map[any]any{}}
+	if h.Unprotected, ok = unprotected.(map[any]any); !ok {
+		return Headers{}, nil, InvalidMessageError{Err: errors.New("unprotected header must be a map")}
+	}
+	if len(raw) > 0 {
+		v, err := cbor.Unmarshal(raw)
+		if err != nil {
+			return Headers{}, nil, InvalidMessageError{Err: err}
+		}
+		if h.Protected, ok = v.(map[any]any); !ok {
+			return Headers{}, nil, InvalidMessageError{Err: errors.New("protected header must be a map")}
+		}
+	}
+	for label := range h.Unprotected {
+		if _, dup := h.Protected[label]; dup {
+			return Headers{}, nil, InvalidMessageError{Err: errors.New("header parameter is both protected and unprotected")}
+		}
+	}
+	if _, ok := h.Unprotected[HeaderCritical]; ok {
+		return Headers{}, nil, InvalidMessageError{Err: errors.New("critical header parameters must be protected")}
+	}
+	if crit, ok := h.Protected[HeaderCritical]; ok {
+		labels, _ := crit.([]any)
+		if len(labels) == 0 {
+			return Headers{}, nil, InvalidMessageError{Err: errors.New("critical header parameters must be a non-empty array")}
+		}
+		// No extension header parameters are understood.
+		return Headers{}, nil, UnsupportedCriticalHeaderError{Label: labels[0]}
+	}
+	return h, raw, nil
+}
+
+// parseMessage decodes a message of the expected tag, which is optional, into its array items.
+func parseMessage(data []byte, tag uint64, size int) ([]any, error) {
+	v, err := cbor.Unmarshal(data)
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	return untag(v, tag, size)
+}
+
+// untag strips an optional tag and returns the array items of a message.
+func untag(v any, tag uint64, size int) ([]any, error) {
+	if t, ok := v.(cbor.Tag); ok {
+		if t.Number != tag {
+			return nil, InvalidMessageError{Err: errors.New("unexpected CBOR tag")}
+		}
+		v = t.Content
+	}
+	items, ok := v.([]any)
+	if !ok || len(items) != size {
+		return nil, InvalidMessageError{Err: errors.New("malformed message array")}
+	}
+	return items, nil
+}
@@ -0,0 +1,355 @@
+package cose
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// rfc8392Key is the ECDSA P-256 key of RFC 8392 appendix A.2.3.
+var rfc8392Key = &ecdsa.PublicKey{
+	Curve: elliptic.P256(),
+	X:     new(big.Int).SetBytes(mustHex("143329cce7868e416927599cf65a34f3ce2ffda55a7eca69ed8919a394d42f0f")),
+	Y:     new(big.Int).SetBytes(mustHex("60f7f1a780d8a783bfb7a2dd6b2796e8128dbbcef9d3d168db9529971a36e7b9")),
+}
+
+func TestRFC8392(t *testing.T) {
+	// The signed CWT of RFC 8392 appendix A.3.
+	token := mustHex("d28443a10126a104524173796d6d657472696345434453413235365850a70175636f61703a2f2f61732e6578616d706c652e636f6d02656572696b77037818636f61703a2f2f6c696768742e6578616d706c652e636f6d041a5612aeb0051a5610d9f0061a5610d9f007420b7158405427c1ff28d23fbad1f29c4c7c6a555e601d6fa29f9179bc3d7438bacaca5acd08c8d4d4f96131680c429a01f85951ecee743a52b9b63632c57209120e1c9e30")
+
+	claims, err := VerifyCWT(token, rfc8392Key, time.Unix(1444000000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "coap://as.example.com", claims.Issuer)
+	assert.Equal(t, "erikw", claims.Subject)
+	assert.Equal(t, "coap://light.example.com", claims.Audience)
+	assert.Equal(t, int64(1444064944), claims.Expiration.Unix())
+	assert.Equal(t, int64(1443944944), claims.NotBefore.Unix())
+	assert.Equal(t, []byte{0x0b, 0x71}, claims.ID)
+
+	_, err = VerifyCWT(token, rfc8392Key, time.Unix(1444064944, 0))
+	assert.Equal(t, ExpiredError{Expiration: time.Unix(1444064944, 0)}, err)
+	assert.Equal(t, "crypto/cose: token expired at 2015-10-05T17:09:04Z", err.Error())
+	_, err = VerifyCWT(token, rfc8392Key, time.Unix(1443944943, 0))
+	assert.Equal(t, "crypto/cose: token not valid before 2015-10-04T07:49:04Z", err.Error())
+
+	m, err := ParseSign1(token)
+	require.NoError(t, err)
+	assert.Equal(t, ES256, m.Algorithm())
+	assert.Equal(t, []byte("AsymmetricECDSA256"), m.KeyID())
+	bad := bytes.Clone(token)
+	bad[len(bad)-1] ^= 1
+	_, err = VerifyCWT(bad, rfc8392Key, time.Unix(1444000000, 0))
+	assert.Equal(t, SignatureVerificationError{}, err)
+}
+
+func TestSign1(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, ed, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		alg Algorithm
+		key crypto.Signer
+	}{
+		{ES256, p256},
+		{ES384, p384},
+		{ES512, p521},
+		{EdDSA, ed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg.String(), func(t *testing.T) {
+			m := &Sign1Message{
+				Payload: []byte("hello dongle"),
+				Headers: Headers{
+					Protected:   map[any]any{HeaderContentType: "text/plain"},
+					Unprotected: map[any]any{HeaderKeyID: []byte("k1")},
+				},
+			}
+			require.NoError(t, m.Sign(tt.key, []byte("aad")))
+			data, err := m.MarshalCBOR()
+			require.NoError(t, err)
+
+			got, err := ParseSign1(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.alg, got.Algorithm())
+			assert.Equal(t, []byte("k1"), got.KeyID())
+			assert.Equal(t, "text/plain", got.Protected[HeaderContentType])
+			assert.Equal(t, []byte("hello dongle"), got.Payload)
+			assert.NoError(t, got.Verify(tt.key.Public(), []byte("aad")))
+			assert.Equal(t, SignatureVerificationError{}, got.Verify(tt.key.Public(), nil))
+
+			got.Payload = []byte("hello world!")
+			assert.Equal(t, SignatureVerificationError{}, got.Verify(tt.key.Public(), []byte("aad")))
+		})
+	}
+
+	t.Run("detached payload", func(t *testing.T) {
+		m := &Sign1Message{Payload: []byte("detached"), Detached: true}
+		require.NoError(t, m.Sign(p256, nil))
+		data, err := m.MarshalCBOR()
+		require.NoError(t, err)
+
+		got, err := ParseSign1(data)
+		require.NoError(t, err)
+		assert.True(t, got.Detached)
+		assert.Nil(t, got.Payload)
+		assert.Equal(t, SignatureVerificationError{}, got.Verify(p256.Public(), nil))
+		got.Payload = []byte("detached")
+		assert.NoError(t, got.Verify(p256.Public(), nil))
+	})
+
+	t.Run("untagged message", func(t *testing.T) {
+		m := &Sign1Message{Payload: []byte("untagged")}
+		require.NoError(t, m.Sign(ed, nil))
+		data, err := cbor.Marshal(m.items())
+		require.NoError(t, err)
+		got, err := ParseSign1(data)
+		require.NoError(t, err)
+		assert.NoError(t, got.Verify(ed.Public(), nil))
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		m := &Sign1Message{Payload: []byte("hello")}
+		require.NoError(t, m.Sign(p256, nil))
+		other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.Equal(t, SignatureVerificationError{}, m.Verify(other.Public(), nil))
+		assert.Equal(t, InvalidKeyError{Algorithm: ES256, Key: p384.Public()}, m.Verify(p384.Public(), nil))
+		assert.Equal(t, InvalidKeyError{Algorithm: ES256, Key: ed.Public()}, m.Verify(ed.Public(), nil))
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+		m := &Sign1Message{Payload: []byte("hello")}
+		err := m.Sign(rsaKey, nil)
+		assert.Equal(t, InvalidKeyError{Key: rsaKey.Public()}, err)
+		assert.Equal(t, "crypto/cose: unsupported key type *rsa.PublicKey", err.Error())
+	})
+
+	t.Run("unprotected algorithm", func(t *testing.T) {
+		m := &Sign1Message{Headers: Headers{Unprotected: map[any]any{HeaderAlgorithm: int64(ES256)}}}
+		assert.IsType(t, InvalidMessageError{}, m.Sign(p256, nil))
+	})
+
+	t.Run("unsigned message", func(t *testing.T) {
+		m := &Sign1Message{Payload: []byte("hello")}
+		_, err := m.MarshalCBOR()
+		assert.IsType(t, InvalidMessageError{}, err)
+		assert.Equal(t, SignatureVerificationError{}, m.Verify(p256.Public(), nil))
+	})
+}
+
+func TestEncrypt0(t *testing.T) {
+	for _, size := range []int{16, 24, 32} {
+		key := make([]byte, size)
+		_, _ = rand.Read(key)
+		m := &Encrypt0Message{Headers: Headers{Unprotected: map[any]any{HeaderKeyID: []byte("k1")}}}
+		require.NoError(t, m.Encrypt(key, []byte("hello dongle"), []byte("aad")))
+		data, err := m.MarshalCBOR()
+		require.NoError(t, err)
+
+		got, err := ParseEncrypt0(data)
+		require.NoError(t, err)
+		assert.Equal(t, Algorithm(size/8-1), got.Algorithm())
+		assert.Equal(t, []byte("k1"), got.KeyID())
+		plaintext, err := got.Decrypt(key, []byte("aad"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello dongle"), plaintext)
+
+		_, err = got.Decrypt(key, nil)
+		assert.Equal(t, DecryptError{}, err)
+		got.Ciphertext[0] ^= 1
+		_, err = got.Decrypt(key, []byte("aad"))
+		assert.Equal(t, DecryptError{}, err)
+	}
+
+	key := make([]byte, 16)
+	m := &Encrypt0Message{}
+	require.NoError(t, m.Encrypt(key, []byte("hello"), nil))
+
+	t.Run("wrong key size", func(t *testing.T) {
+		_, err := m.Decrypt(make([]byte, 32), nil)
+		assert.Equal(t, InvalidKeyError{Algorithm: A128GCM, Key: make([]byte, 32)}, err)
+		assert.Equal(t, InvalidKeyError{Key: make([]byte, 15)}, (&Encrypt0Message{}).Encrypt(make([]byte, 15), nil, nil))
+	})
+
+	t.Run("missing iv", func(t *testing.T) {
+		got := &Encrypt0Message{Headers: m.Headers, Ciphertext: m.Ciphertext, rawProtected: m.rawProtected}
+		got.Unprotected = map[any]any{}
+		_, err := got.Decrypt(key, nil)
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		raw, _ := cbor.Marshal(map[any]any{HeaderAlgorithm: int64(ES256)})
+		data, _ := cbor.Marshal(cbor.Tag{Number: tagEncrypt0, Content: []any{raw, map[any]any{}, []byte("x")}})
+		got, err := ParseEncrypt0(data)
+		require.NoError(t, err)
+		_, err = got.Decrypt(key, nil)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: ES256}, err)
+		assert.Equal(t, "crypto/cose: unsupported algorithm ES256", err.Error())
+	})
+}
+
+func TestCWT(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	claims := Claims{
+		Issuer:     "dongle",
+		Subject:    "device-1",
+		Audience:   "coap://light.example.com",
+		Expiration: now.Add(time.Hour),
+		NotBefore:  now.Add(-time.Minute),
+		IssuedAt:   now,
+		ID:         []byte{1, 2, 3},
+		Extra:      map[any]any{int64(-70000): "scope", "role": "admin"},
+	}
+
+	t.Run("signed", func(t *testing.T) {
+		_, key, _ := ed25519.GenerateKey(rand.Reader)
+		token, err := SignCWT(claims, key, []byte("kid"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0xd8, 0x3d, 0xd2}, token[:3])
+
+		got, err := VerifyCWT(token, key.Public(), now)
+		require.NoError(t, err)
+		assert.Equal(t, claims.Issuer, got.Issuer)
+		assert.Equal(t, claims.Subject, got.Subject)
+		assert.Equal(t, claims.Audience, got.Audience)
+		assert.True(t, claims.Expiration.Equal(got.Expiration))
+		assert.True(t, claims.NotBefore.Equal(got.NotBefore))
+		assert.True(t, claims.IssuedAt.Equal(got.IssuedAt))
+		assert.Equal(t, claims.ID, got.ID)
+		assert.Equal(t, claims.Extra, got.Extra)
+
+		_, err = VerifyCWT(token, key.Public(), now.Add(2*time.Hour))
+		assert.IsType(t, ExpiredError{}, err)
+		_, err = VerifyCWT(token, key.Public(), now.Add(-time.Hour))
+		assert.IsType(t, NotYetValidError{}, err)
+
+		_, other, _ := ed25519.GenerateKey(rand.Reader)
+		_, err = VerifyCWT(token, other.Public(), now)
+		assert.Equal(t, SignatureVerificationError{}, err)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		key := make([]byte, 32)
+		_, _ = rand.Read(key)
+		token, err := EncryptCWT(claims, key, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0xd8, 0x3d, 0xd0}, token[:3])
+
+		got, err := DecryptCWT(token, key, now)
+		require.NoError(t, err)
+		assert.Equal(t, claims.Issuer, got.Issuer)
+		assert.Equal(t, claims.Extra, got.Extra)
+
+		_, err = DecryptCWT(token, make([]byte, 32), now)
+		assert.Equal(t, DecryptError{}, err)
+		_, err = DecryptCWT(token, key, now.Add(2*time.Hour))
+		assert.IsType(t, ExpiredError{}, err)
+	})
+
+	t.Run("invalid claims", func(t *testing.T) {
+		_, key, _ := ed25519.GenerateKey(rand.Reader)
+		for _, payload := range []any{
+			[]any{int64(1)},
+			map[any]any{ClaimIssuer: int64(1)},
+			map[any]any{ClaimExpiration: "tomorrow"},
+		} {
+			data, _ := cbor.Marshal(payload)
+			m := &Sign1Message{Payload: data}
+			require.NoError(t, m.Sign(key, nil))
+			token, _ := m.MarshalCBOR()
+			_, err := VerifyCWT(token, key.Public(), now)
+			assert.IsType(t, InvalidMessageError{}, err)
+		}
+	})
+
+	t.Run("fractional date", func(t *testing.T) {
+		date, ok := numericDate(1.5)
+		assert.True(t, ok)
+		assert.Equal(t, time.Unix(1, 5e8), date)
+	})
+}
+
+func TestParse_Invalid(t *testing.T) {
+	raw, _ := cbor.Marshal(map[any]any{HeaderAlgorithm: int64(EdDSA)})
+	tests := []struct {
+		name string
+		msg  any
+	}{
+		{"wrong tag", cbor.Tag{Number: tagEncrypt0, Content: []any{raw, map[any]any{}, []byte{}, []byte{}}}},
+		{"not an array", map[any]any{}},
+		{"short array", []any{raw, map[any]any{}, []byte{}}},
+		{"protected not bytes", []any{"x", map[any]any{}, []byte{}, []byte{}}},
+		{"protected not a map", []any{[]byte{0x01}, map[any]any{}, []byte{}, []byte{}}},
+		{"unprotected not a map", []any{raw, []any{}, []byte{}, []byte{}}},
+		{"duplicate label", []any{raw, map[any]any{HeaderAlgorithm: int64(EdDSA)}, []byte{}, []byte{}}},
+		{"unprotected crit", []any{raw, map[any]any{HeaderCritical: []any{int64(99)}}, []byte{}, []byte{}}},
+		{"payload not bytes", []any{raw, map[any]any{}, "x", []byte{}}},
+		{"signature not bytes", []any{raw, map[any]any{}, []byte{}, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := cbor.Marshal(tt.msg)
+			require.NoError(t, err)
+			m, err := ParseSign1(data)
+			assert.Nil(t, m)
+			assert.IsType(t, InvalidMessageError{}, err)
+		})
+	}
+
+	t.Run("malformed cbor", func(t *testing.T) {
+		_, err := ParseSign1([]byte{0x84, 0x40})
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = ParseEncrypt0(nil)
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = VerifyCWT([]byte{0xff}, nil, time.Now())
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = DecryptCWT([]byte{0xff}, nil, time.Now())
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+
+	t.Run("critical header", func(t *testing.T) {
+		crit, _ := cbor.Marshal(map[any]any{HeaderAlgorithm: int64(EdDSA), HeaderCritical: []any{int64(99)}})
+		data, _ := cbor.Marshal([]any{crit, map[any]any{}, []byte{}, []byte{}})
+		_, err := ParseSign1(data)
+		assert.Equal(t, UnsupportedCriticalHeaderError{Label: int64(99)}, err)
+		assert.Equal(t, "crypto/cose: unsupported critical header parameter 99", err.Error())
+	})
+
+	t.Run("detached ciphertext", func(t *testing.T) {
+		data, _ := cbor.Marshal([]any{raw, map[any]any{}, nil})
+		_, err := ParseEncrypt0(data)
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+
+	t.Run("detached token", func(t *testing.T) {
+		_, key, _ := ed25519.GenerateKey(rand.Reader)
+		m := &Sign1Message{Detached: true}
+		require.NoError(t, m.Sign(key, nil))
+		data, _ := m.MarshalCBOR()
+		_, err := VerifyCWT(data, key.Public(), time.Now())
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+}
@@ -0,0 +1,205 @@
+package cose
+
+import (
+	"crypto"
+	"errors"
+	"time"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+)
+
+// Claim keys registered by RFC 8392.
+const (
+	ClaimIssuer     int64 = 1 // iss, a text string
+	ClaimSubject    int64 = 2 // sub, a text string
+	ClaimAudience   int64 = 3 // aud, a text string
+	ClaimExpiration int64 = 4 // exp, a NumericDate
+	ClaimNotBefore  int64 = 5 // nbf, a NumericDate
+	ClaimIssuedAt   int64 = 6 // iat, a NumericDate
+	ClaimID         int64 = 7 // cti, a byte string
+)
+
+// Claims is the claims set of a CBOR Web Token. Zero values are left out.
+type Claims struct {
+	Issuer     string
+	Subject    string
+	Audience   string
+	Expiration time.Time
+	NotBefore  time.Time
+	IssuedAt   time.Time
+	ID         []byte
+	Extra      map[any]any // Other claims keyed by int64 or string
+}
+
+// Valid checks the expiration and not before claims against now.
+func (c *Claims) Valid(now time.Time) error {
+	if !c.Expiration.IsZero() && !now.Before(c.Expiration) {
+		return ExpiredError{Expiration: c.Expiration}
+	}
+	if !c.NotBefore.IsZero() && now.Before(c.NotBefore) {
+		return NotYetValidError{NotBefore: c.NotBefore}
+	}
+	return nil
+}
+
+// SignCWT creates a CWT of claims signed with key as a tagged COSE_Sign1 message.
+// A non-empty kid is recorded in the protected header to help verifiers pick the key.
+func SignCWT(claims Claims, key crypto.Signer, kid []byte) ([]byte, error) {
+	payload, err := claims.marshal()
+	if err != nil {
+		return nil, err
+	}
+	m := &Sign1Message{Payload: payload, Headers: kidHeaders(kid)}
+	if err = m.Sign(key, nil); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(cbor.Tag{Number: tagCWT, Content: cbor.Tag{Number: tagSign1, Content: m.items()}})
+}
+
+// VerifyCWT verifies a signed CWT with key and checks its time claims against now.
+// Both the CWT tag and the COSE_Sign1 tag are optional.
+func VerifyCWT(data []byte, key crypto.PublicKey, now time.Time) (*Claims, error) {
+	items, err := parseToken(data, tagSign1, 4)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseSign1(items)
+	if err != nil {
+		return nil, err
+	}
+	if m.Detached {
+		return nil, InvalidMessageError{Err: errors.New("token has no claims")}
+	}
+	if err = m.Verify(key, nil); err != nil {
+		return nil, err
+	}
+	return parseClaims(m.Payload, now)
+}
+
+// EncryptCWT creates a CWT of claims encrypted with an AES-GCM key as a tagged COSE_Encrypt0 message.
+func EncryptCWT(claims Claims, key, kid []byte) ([]byte, error) {
+	payload, err := claims.marshal()
+	if err != nil {
+		return nil, err
+	}
+	m := &Encrypt0Message{Headers: kidHeaders(kid)}
+	if err = m.Encrypt(key, payload, nil); err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(cbor.Tag{Number: tagCWT, Content: cbor.Tag{Number: tagEncrypt0, Content: m.items()}})
+}
+
+// DecryptCWT decrypts an encrypted CWT with key and checks its time claims against now.
+func DecryptCWT(data, key []byte, now time.Time) (*Claims, error) {
+	items, err := parseToken(data, tagEncrypt0, 3)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseEncrypt0(items)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := m.Decrypt(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseClaims(payload, now)
+}
+
+// kidHeaders returns headers carrying a protected key identifier, if any.
+func kidHeaders(kid []byte) Headers {
+	if len(kid) == 0 {
+		return Headers{}
+	}
+	return Headers{Protected: map[any]any{HeaderKeyID: kid}}
+}
+
+// parseToken strips the optional CWT tag and decodes the inner message.
+func parseToken(data []byte, tag uint64, size int) ([]any, error) {
+	v, err := cbor.Unmarshal(data)
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	if t, ok := v.(cbor.Tag); ok && t.Number == tagCWT {
+		v = t.Content
+	}
+	return untag(v, tag, size)
+}
+
+// marshal encodes the claims set as a CBOR map.
+func (c Claims) marshal() ([]byte, error) {
+	m := make(map[any]any, len(c.Extra)+7)
+	for k, v := range c.Extra {
+		m[k] = v
+	}
+	set := func(key int64, v any, ok bool) {
+		if ok {
+			m[key] = v
+		}
+	}
+	set(ClaimIssuer, c.Issuer, c.Issuer != "")
+	set(ClaimSubject, c.Subject, c.Subject != "")
+	set(ClaimAudience, c.Audience, c.Audience != "")
+	set(ClaimExpiration, c.Expiration.Unix(), !c.Expiration.IsZero())
+	set(ClaimNotBefore, c.NotBefore.Unix(), !c.NotBefore.IsZero())
+	set(ClaimIssuedAt, c.IssuedAt.Unix(), !c.IssuedAt.IsZero())
+	set(ClaimID, c.ID, len(c.ID) > 0)
+	out, err := cbor.Marshal(m)
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	return out, nil
+}
+
+// parseClaims decodes a claims set and checks its time claims against now.
+func parseClaims(payload []byte, now time.Time) (*Claims, error) {
+	v, err := cbor.Unmarshal(payload)
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil, InvalidMessageError{Err: errors.New("claims must be a map")}
+	}
+	c := &Claims{Extra: map[any]any{}}
+	for k, v := range m {
+		var ok bool
+		switch k {
+		case ClaimIssuer:
+			c.Issuer, ok = v.(string)
+		case ClaimSubject:
+			c.Subject, ok = v.(string)
+		case ClaimAudience:
+			c.Audience, ok = v.(string)
+		case ClaimExpiration:
+			c.Expiration, ok = numericDate(v)
+		case ClaimNotBefore:
+			c.NotBefore, ok = numericDate(v)
+		case ClaimIssuedAt:
+			c.IssuedAt, ok = numericDate(v)
+		case ClaimID:
+			c.ID, ok = v.([]byte)
+		default:
+			c.Extra[k], ok = v, true
+		}
+		if !ok {
+			return nil, InvalidMessageError{Err: errors.New("claim has an invalid type")}
+		}
+	}
+	if err = c.Valid(now); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// numericDate decodes an integer or floating point NumericDate.
+func numericDate(v any) (time.Time, bool) {
+	switch n := v.(type) {
+	case int64:
+		return time.Unix(n, 0), true
+	case float64:
+		sec := int64(n)
+		return time.Unix(sec, int64((n-float64(sec))*1e9)), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,113 @@
+package cose
+
+import (
+	"errors"
+	"io"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Encrypt0Message is a COSE_Encrypt0 message, a payload encrypted with a key
+// the recipient already holds.
+type Encrypt0Message struct {
+	Headers
+	Ciphertext []byte // Ciphertext including the authentication tag
+
+	rawProtected []byte // Protected header exactly as authenticated
+}
+
+// Encrypt encrypts plaintext with an AES-GCM key of 16, 24 or 32 bytes. The
+// algorithm follows from the key size and is recorded in the protected header, a
+// random IV is recorded in the unprotected header. External is additional
+// authenticated data that is not transmitted.
+func (m *Encrypt0Message) Encrypt(key, plaintext, external []byte) error {
+	alg, err := aeadAlgorithm(key)
+	if err != nil {
+		return err
+	}
+	protected, raw, err := m.protect(alg)
+	if err != nil {
+		return err
+	}
+	aead, err := alg.aead(key)
+	if err != nil {
+		return err
+	}
+	iv := make([]byte, NonceSize)
+	if _, err = io.ReadFull(utils.Rand(), iv); err != nil {
+		return err
+	}
+	unprotected := make(map[any]any, len(m.Unprotected)+1)
+	for k, v := range m.Unprotected {
+		unprotected[k] = v
+	}
+	unprotected[HeaderIV] = iv
+	m.Protected, m.Unprotected, m.rawProtected = protected, unprotected, raw
+	m.Ciphertext = aead.Seal(nil, iv, plaintext, encStructure(raw, external))
+	return nil
+}
+
+// Decrypt authenticates and decrypts the ciphertext with key.
+func (m *Encrypt0Message) Decrypt(key, external []byte) ([]byte, error) {
+	aead, err := m.Algorithm().aead(key)
+	if err != nil {
+		return nil, err
+	}
+	iv, _ := m.Unprotected[HeaderIV].([]byte)
+	if iv == nil {
+		iv, _ = m.Protected[HeaderIV].([]byte)
+	}
+	if len(iv) != NonceSize || m.rawProtected == nil {
+		return nil, InvalidMessageError{Err: errors.New("missing or invalid IV")}
+	}
+	plaintext, err := aead.Open(nil, iv, m.Ciphertext, encStructure(m.rawProtected, external))
+	if err != nil {
+		return nil, DecryptError{}
+	}
+	return plaintext, nil
+}
+
+// MarshalCBOR encodes the message as a tagged COSE_Encrypt0 structure.
+func (m *Encrypt0Message) MarshalCBOR() ([]byte, error) {
+	if m.rawProtected == nil {
+		return nil, InvalidMessageError{Err: errors.New("message is not encrypted")}
+	}
+	return cbor.Marshal(cbor.Tag{Number: tagEncrypt0, Content: m.items()})
+}
+
+// items returns the COSE_Encrypt0 array.
+func (m *Encrypt0Message) items() []any {
+	return []any{m.rawProtected, m.Unprotected, m.Ciphertext}
+}
+
+// ParseEncrypt0 decodes a tagged or untagged COSE_Encrypt0 message.
+func ParseEncrypt0(data []byte) (*Encrypt0Message, error) {
+	items, err := parseMessage(data, tagEncrypt0, 3)
+	if err != nil {
+		return nil, err
+	}
+	return parseEncrypt0(items)
+}
+
+// parseEncrypt0 decodes the items of a COSE_Encrypt0 array.
+func parseEncrypt0(items []any) (*Encrypt0Message, error) {
+	headers, raw, err := parseHeaders(items[0], items[1])
+	if err != nil {
+		return nil, err
+	}
+	m := &Encrypt0Message{Headers: headers, rawProtected: raw}
+	if m.Ciphertext, _ = items[2].([]byte); m.Ciphertext == nil {
+		return nil, InvalidMessageError{Err: errors.New("ciphertext must be a byte string, detached ciphertext is not supported")}
+	}
+	return m, nil
+}
+
+// encStructure returns the encoded Enc_structure authenticated by COSE_Encrypt0.
+func encStructure(protected, external []byte) []byte {
+	if external == nil {
+		external = []byte{}
+	}
+	out, _ := cbor.Marshal([]any{"Encrypt0", protected, external})
+	return out
+}
@@ -0,0 +1,188 @@
+package cose
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidMessageError represents an error when a COSE message or CWT cannot be decoded.
+type InvalidMessageError struct {
+	Err error // Underlying error from CBOR decoding or structure checks
+}
+
+// Error returns a formatted error message describing the invalid message.
+func (e InvalidMessageError) Error() string {
+	return fmt.Sprintf("crypto/cose: invalid message: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-COSE-001.
+func (e InvalidMessageError) Code() string {
+	return "DGL-COSE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidMessageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedAlgorithmError represents an error when a COSE algorithm is not supported.
+type UnsupportedAlgorithmError struct {
+	Algorithm Algorithm // The unsupported algorithm identifier
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/cose: unsupported algorithm %s", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-COSE-002.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-COSE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// InvalidKeyError represents an error when a key cannot be used with an algorithm.
+type InvalidKeyError struct {
+	Algorithm Algorithm // The algorithm the key was used with, 0 when none applies
+	Key       any       // The rejected key
+}
+
+// Error returns a formatted error message describing the invalid key.
+func (e InvalidKeyError) Error() string {
+	if e.Algorithm == 0 {
+		return fmt.Sprintf("crypto/cose: unsupported key type %T", e.Key)
+	}
+	return fmt.Sprintf("crypto/cose: invalid key %T for algorithm %s", e.Key, e.Algorithm)
+}
+
+// Code returns the stable error code DGL-COSE-003.
+func (e InvalidKeyError) Code() string {
+	return "DGL-COSE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "key", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// UnsupportedCriticalHeaderError represents an error when a message marks a header parameter critical that is not understood.
+type UnsupportedCriticalHeaderError struct {
+	Label any // The critical header label
+}
+
+// Error returns a formatted error message describing the unsupported critical header.
+func (e UnsupportedCriticalHeaderError) Error() string {
+	return fmt.Sprintf("crypto/cose: unsupported critical header parameter %v", e.Label)
+}
+
+// Code returns the stable error code DGL-COSE-004.
+func (e UnsupportedCriticalHeaderError) Code() string {
+	return "DGL-COSE-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedCriticalHeaderError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "")
+}
+
+// SignError represents an error when creating a signature fails.
+type SignError struct {
+	Err error // Underlying error from the signer
+}
+
+// Error returns a formatted error message describing the signing failure.
+func (e SignError) Error() string {
+	return fmt.Sprintf("crypto/cose: failed to sign: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-COSE-005.
+func (e SignError) Code() string {
+	return "DGL-COSE-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "sign", errcode.FieldCause, e.Err)
+}
+
+// SignatureVerificationError represents an error when a signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/cose: signature verification failed"
+}
+
+// Code returns the stable error code DGL-COSE-006.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-COSE-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "verify")
+}
+
+// DecryptError represents an error when a ciphertext fails authentication.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return "crypto/cose: failed to decrypt message"
+}
+
+// Code returns the stable error code DGL-COSE-007.
+func (e DecryptError) Code() string {
+	return "DGL-COSE-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "decrypt")
+}
+
+// ExpiredError represents an error when a CWT is used after its expiration time.
+type ExpiredError struct {
+	Expiration time.Time // The token expiration time
+}
+
+// Error returns a formatted error message describing the expired token.
+func (e ExpiredError) Error() string {
+	return fmt.Sprintf("crypto/cose: token expired at %s", e.Expiration.UTC().Format(time.RFC3339))
+}
+
+// Code returns the stable error code DGL-COSE-008.
+func (e ExpiredError) Code() string {
+	return "DGL-COSE-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ExpiredError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "", "expiration", e.Expiration)
+}
+
+// NotYetValidError represents an error when a CWT is used before its not before time.
+type NotYetValidError struct {
+	NotBefore time.Time // The token not before time
+}
+
+// Error returns a formatted error message describing the premature token.
+func (e NotYetValidError) Error() string {
+	return fmt.Sprintf("crypto/cose: token not valid before %s", e.NotBefore.UTC().Format(time.RFC3339))
+}
+
+// Code returns the stable error code DGL-COSE-009.
+func (e NotYetValidError) Code() string {
+	return "DGL-COSE-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e NotYetValidError) Fields() map[string]any {
+	return errcode.NewFields("crypto/cose", "COSE", "", "not_before", e.NotBefore)
+}
@@ -0,0 +1,113 @@
+package cose
+
+import (
+	"crypto"
+	"errors"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+)
+
+// Sign1Message is a COSE_Sign1 message, a payload signed by a single signer.
+type Sign1Message struct {
+	Headers
+	Payload   []byte // Signed payload, set it before Verify for detached messages
+	Detached  bool   // Leave the payload out of the encoded message
+	Signature []byte // Signature value
+
+	rawProtected []byte // Protected header exactly as signed
+}
+
+// Sign signs the payload with key, which must hold an ECDSA P-256, P-384, P-521
+// or Ed25519 private key. The algorithm follows from the key and is recorded in the
+// protected header. External is additional authenticated data that is not
+// transmitted, the verifier must supply the same bytes.
+func (m *Sign1Message) Sign(key crypto.Signer, external []byte) error {
+	alg, err := signatureAlgorithm(key.Public())
+	if err != nil {
+		return err
+	}
+	protected, raw, err := m.protect(alg)
+	if err != nil {
+		return err
+	}
+	sig, err := alg.sign(key, sigStructure(raw, external, m.Payload))
+	if err != nil {
+		return err
+	}
+	m.Protected, m.rawProtected, m.Signature = protected, raw, sig
+	return nil
+}
+
+// Verify verifies the signature with the public key for the algorithm in the headers.
+func (m *Sign1Message) Verify(key crypto.PublicKey, external []byte) error {
+	if m.rawProtected == nil {
+		return SignatureVerificationError{}
+	}
+	return m.Algorithm().verify(key, sigStructure(m.rawProtected, external, m.Payload), m.Signature)
+}
+
+// MarshalCBOR encodes the message as a tagged COSE_Sign1 structure.
+func (m *Sign1Message) MarshalCBOR() ([]byte, error) {
+	if m.rawProtected == nil {
+		return nil, InvalidMessageError{Err: errors.New("message is not signed")}
+	}
+	return cbor.Marshal(cbor.Tag{Number: tagSign1, Content: m.items()})
+}
+
+// items returns the COSE_Sign1 array.
+func (m *Sign1Message) items() []any {
+	var payload any = m.Payload
+	if m.Detached {
+		payload = nil
+	} else if m.Payload == nil {
+		payload = []byte{}
+	}
+	unprotected := m.Unprotected
+	if unprotected == nil {
+		unprotected = map[any]any{}
+	}
+	return []any{m.rawProtected, unprotected, payload, m.Signature}
+}
+
+// ParseSign1 decodes a tagged or untagged COSE_Sign1 message. A nil payload
+// marks detached content, set Payload before calling Verify.
+func ParseSign1(data []byte) (*Sign1Message, error) {
+	items, err := parseMessage(data, tagSign1, 4)
+	if err != nil {
+		return nil, err
+	}
+	return parseSign1(items)
+}
+
+// parseSign1 decodes the items of a COSE_Sign1 array.
+func parseSign1(items []any) (*Sign1Message, error) {
+	headers, raw, err := parseHeaders(items[0], items[1])
+	if err != nil {
+		return nil, err
+	}
+	m := &Sign1Message{Headers: headers, rawProtected: raw}
+	switch payload := items[2].(type) {
+	case nil:
+		m.Detached = true
+	case []byte:
+		m.Payload = payload
+	default:
+		return nil, InvalidMessageError{Err: errors.New("payload must be a byte string or nil")}
+	}
+	if m.Signature, _ = items[3].([]byte); m.Signature == nil {
+		return nil, InvalidMessageError{Err: errors.New("signature must be a byte string")}
+	}
+	return m, nil
+}
+
+// sigStructure returns the encoded Sig_structure signed by COSE_Sign1.
+func sigStructure(protected, external, payload []byte) []byte {
+	if external == nil {
+		external = []byte{}
+	}
+	if payload == nil {
+		payload = []byte{}
+	}
+	out, _ := cbor.Marshal([]any{"Signature1", protected, external, payload})
+	return out
+}
@@ -8,15 +8,19 @@ import (
 	"github.com/dromara/dongle/coding"
 	"github.com/dromara/dongle/coding/base64"
 	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/pem"
 	"github.com/dromara/dongle/internal/utils"
 )
 
 // Decrypter defines a Decrypter struct.
 type Decrypter struct {
-	src    []byte
-	dst    []byte
-	reader io.Reader
-	Error  error
+	src        []byte
+	dst        []byte
+	reader     io.Reader
+	maxSize    int64
+	bufferSize int
+	progress   func(done, total int64)
+	Error      error
 }
 
 // NewDecrypter returns a new Decrypter instance.
@@ -26,24 +30,44 @@ func NewDecrypter() Decrypter {
 
 // FromRawString decrypts from raw string.
 func (d Decrypter) FromRawString(s string) Decrypter {
+	utils.AuditShared()
 	d.src = utils.String2Bytes(s)
 	return d
 }
 
 // FromRawBytes decrypts from raw bytes.
 func (d Decrypter) FromRawBytes(b []byte) Decrypter {
+	utils.AuditShared()
 	d.src = b
 	return d
 }
 
 // FromRawFile decrypts from raw file.
 func (d Decrypter) FromRawFile(f fs.File) Decrypter {
+	utils.AuditShared()
 	d.reader = f
 	return d
 }
 
+// FromRawFS decrypts from the named raw file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (d Decrypter) FromRawFS(fsys fs.FS, name string) Decrypter {
+	utils.AuditShared()
+	if d.Error != nil {
+		return d
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		d.Error = err
+		return d
+	}
+	return d.FromRawFile(f)
+}
+
 // FromBase64String decrypts from base64 string.
 func (d Decrypter) FromBase64String(s string) Decrypter {
+	utils.AuditShared()
 	decode := coding.NewDecoder().FromString(s).ByBase64()
 	if decode.Error != nil {
 		d.Error = decode.Error
@@ -55,6 +79,7 @@ func (d Decrypter) FromBase64String(s string) Decrypter {
 
 // FromBase64Bytes decrypts from base64 bytes.
 func (d Decrypter) FromBase64Bytes(b []byte) Decrypter {
+	utils.AuditShared()
 	decode := coding.NewDecoder().FromBytes(b).ByBase64()
 	if decode.Error != nil {
 		d.Error = decode.Error
@@ -66,6 +91,7 @@ func (d Decrypter) FromBase64Bytes(b []byte) Decrypter {
 
 // FromBase64File decrypts from base64 file.
 func (d Decrypter) FromBase64File(f fs.File) Decrypter {
+	utils.AuditShared()
 	if d.Error != nil {
 		return d
 	}
@@ -80,8 +106,25 @@ func (d Decrypter) FromBase64File(f fs.File) Decrypter {
 	return d
 }
 
+// FromBase64FS decrypts from the named base64 file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (d Decrypter) FromBase64FS(fsys fs.FS, name string) Decrypter {
+	utils.AuditShared()
+	if d.Error != nil {
+		return d
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		d.Error = err
+		return d
+	}
+	return d.FromBase64File(f)
+}
+
 // FromHexString decrypts from hex string.
 func (d Decrypter) FromHexString(s string) Decrypter {
+	utils.AuditShared()
 	decode := coding.NewDecoder().FromString(s).ByHex()
 	if decode.Error != nil {
 		d.Error = decode.Error
@@ -91,8 +134,22 @@ func (d Decrypter) FromHexString(s string) Decrypter {
 	return d
 }
 
+// FromHexFormatString decrypts from hex string typed or pasted by users,
+// ignoring case, whitespace and group separators.
+func (d Decrypter) FromHexFormatString(s string) Decrypter {
+	utils.AuditShared()
+	src, err := hex.ParseLenient(s)
+	if err != nil {
+		d.Error = err
+		return d
+	}
+	d.src = src
+	return d
+}
+
 // FromHexBytes decrypts from hex bytes.
 func (d Decrypter) FromHexBytes(b []byte) Decrypter {
+	utils.AuditShared()
 	decode := coding.NewDecoder().FromBytes(b).ByHex()
 	if decode.Error != nil {
 		d.Error = decode.Error
@@ -104,6 +161,7 @@ func (d Decrypter) FromHexBytes(b []byte) Decrypter {
 
 // FromHexFile decrypts from hex file.
 func (d Decrypter) FromHexFile(f fs.File) Decrypter {
+	utils.AuditShared()
 	if d.Error != nil {
 		return d
 	}
@@ -118,6 +176,63 @@ func (d Decrypter) FromHexFile(f fs.File) Decrypter {
 	return d
 }
 
+// FromHexFS decrypts from the named hex file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (d Decrypter) FromHexFS(fsys fs.FS, name string) Decrypter {
+	utils.AuditShared()
+	if d.Error != nil {
+		return d
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		d.Error = err
+		return d
+	}
+	return d.FromHexFile(f)
+}
+
+// FromPemString decrypts from the first PEM block in string, whatever its type.
+func (d Decrypter) FromPemString(s string) Decrypter {
+	utils.AuditShared()
+	return d.FromPemBytes(utils.String2Bytes(s))
+}
+
+// FromPemBytes decrypts from the first PEM block in bytes, whatever its type.
+func (d Decrypter) FromPemBytes(b []byte) Decrypter {
+	utils.AuditShared()
+	block, _, err := pem.Decode(b)
+	if err != nil {
+		d.Error = err
+		return d
+	}
+	d.src = block.Bytes
+	return d
+}
+
+// WithMaxSize limits the decrypted output to n bytes. Once the output would exceed
+// n bytes, streaming stops and a SizeLimitError is returned, which protects
+// services handling untrusted input from memory exhaustion. Zero means no limit.
+func (d Decrypter) WithMaxSize(n int64) Decrypter {
+	d.maxSize = n
+	return d
+}
+
+// WithProgress sets a callback receiving the number of ciphertext bytes read
+// so far and the total size of the file, or -1 when unknown, while decrypting
+// from a file.
+func (d Decrypter) WithProgress(fn func(done, total int64)) Decrypter {
+	d.progress = fn
+	return d
+}
+
+// WithBufferSize sets the copy buffer size used when decrypting from a file,
+// BufferSize by default.
+func (d Decrypter) WithBufferSize(n int) Decrypter {
+	d.bufferSize = n
+	return d
+}
+
 // ToString outputs as string.
 func (d Decrypter) ToString() string {
 	if len(d.dst) == 0 || d.Error != nil {
@@ -134,15 +249,24 @@ func (d Decrypter) ToBytes() []byte {
 	return d.dst
 }
 
+// To outputs as a Result exposing the plaintext in several encodings.
+func (d Decrypter) To() coding.Result {
+	return coding.NewResult(d.ToBytes(), d.Error)
+}
+
 func (d Decrypter) stream(fn func(io.Reader) io.Reader) ([]byte, error) {
+	defer utils.CloseFS(d.reader)
 	var buf bytes.Buffer
-	decrypter := fn(d.reader)
-
 	// Try to reset the reader position if it's a seeker
 	if seeker, ok := d.reader.(io.Seeker); ok {
 		seeker.Seek(0, io.SeekStart)
 	}
-	if _, err := io.CopyBuffer(&buf, decrypter, make([]byte, BufferSize)); err != nil && err != io.EOF {
+	decrypter := fn(utils.ProgressReader(d.reader, d.progress))
+	var w io.Writer = &buf
+	if d.maxSize > 0 {
+		w = utils.LimitWriter(&buf, d.maxSize, SizeLimitError{Limit: d.maxSize})
+	}
+	if _, err := io.CopyBuffer(w, decrypter, utils.Buffer(d.bufferSize, BufferSize)); err != nil && err != io.EOF {
 		return []byte{}, err
 	}
 	if buf.Len() == 0 {
@@ -150,3 +274,11 @@ func (d Decrypter) stream(fn func(io.Reader) io.Reader) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// limit enforces the maximum output size set by WithMaxSize.
+func (d Decrypter) limit(dst []byte, err error) ([]byte, error) {
+	if err == nil && d.maxSize > 0 && int64(len(dst)) > d.maxSize {
+		return []byte{}, SizeLimitError{Limit: d.maxSize}
+	}
+	return dst, err
+}
@@ -1,9 +1,12 @@
 package crypto
 
 import (
+	"bytes"
 	"io"
 	"testing"
+	"testing/fstest"
 
+	"github.com/dromara/dongle/coding/hex"
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -289,6 +292,39 @@ func TestDecrypter_FromHexBytes(t *testing.T) {
 	})
 }
 
+func TestDecrypter_FromPemString(t *testing.T) {
+	t.Run("from pem string", func(t *testing.T) {
+		decrypter := NewDecrypter().FromPemString("-----BEGIN DONGLE MESSAGE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE MESSAGE-----\n")
+		assert.Nil(t, decrypter.Error)
+		assert.Equal(t, []byte("hello world"), decrypter.src)
+	})
+
+	t.Run("from pem string with leading text", func(t *testing.T) {
+		decrypter := NewDecrypter().FromPemString("note\n-----BEGIN AES MESSAGE-----\r\naGVsbG8g\r\nd29ybGQ=\r\n-----END AES MESSAGE-----")
+		assert.Nil(t, decrypter.Error)
+		assert.Equal(t, []byte("hello world"), decrypter.src)
+	})
+
+	t.Run("from invalid pem string", func(t *testing.T) {
+		decrypter := NewDecrypter().FromPemString("aGVsbG8gd29ybGQ=")
+		assert.Error(t, decrypter.Error)
+		assert.Nil(t, decrypter.src)
+	})
+}
+
+func TestDecrypter_FromPemBytes(t *testing.T) {
+	t.Run("from pem bytes", func(t *testing.T) {
+		decrypter := NewDecrypter().FromPemBytes([]byte("-----BEGIN DONGLE MESSAGE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE MESSAGE-----\n"))
+		assert.Nil(t, decrypter.Error)
+		assert.Equal(t, []byte("hello world"), decrypter.src)
+	})
+
+	t.Run("from empty pem bytes", func(t *testing.T) {
+		decrypter := NewDecrypter().FromPemBytes(nil)
+		assert.Error(t, decrypter.Error)
+	})
+}
+
 func TestDecrypter_ToString(t *testing.T) {
 	t.Run("to string", func(t *testing.T) {
 		decrypter := NewDecrypter()
@@ -684,3 +720,97 @@ func TestDecrypter_FromHexFile(t *testing.T) {
 		assert.Nil(t, result.reader)
 	})
 }
+
+func TestDecrypter_WithMaxSize(t *testing.T) {
+	c := newAesCipher()
+	ciphertext := NewEncrypter().FromBytes(bytes.Repeat([]byte("a"), 100)).ByAes(c).ToRawBytes()
+
+	t.Run("standard decryption within limit", func(t *testing.T) {
+		decrypter := NewDecrypter().FromRawBytes(ciphertext).WithMaxSize(100).ByAes(c)
+		assert.Nil(t, decrypter.Error)
+		assert.Len(t, decrypter.ToBytes(), 100)
+	})
+
+	t.Run("standard decryption exceeds limit", func(t *testing.T) {
+		decrypter := NewDecrypter().FromRawBytes(ciphertext).WithMaxSize(99).ByAes(c)
+		assert.Equal(t, SizeLimitError{Limit: 99}, decrypter.Error)
+		assert.Empty(t, decrypter.ToBytes())
+		assert.Equal(t, "crypto: decrypted output exceeds the limit of 99 bytes", decrypter.Error.Error())
+	})
+
+	t.Run("streaming decryption exceeds limit", func(t *testing.T) {
+		file := mock.NewFile(ciphertext, "test.txt")
+		decrypter := NewDecrypter().FromRawFile(file).WithMaxSize(10).ByAes(c)
+		assert.Equal(t, SizeLimitError{Limit: 10}, decrypter.Error)
+		assert.Empty(t, decrypter.ToBytes())
+	})
+
+	t.Run("streaming decryption within limit", func(t *testing.T) {
+		file := mock.NewFile(ciphertext, "test.txt")
+		decrypter := NewDecrypter().FromRawFile(file).WithMaxSize(100).ByAes(c)
+		assert.Nil(t, decrypter.Error)
+		assert.Len(t, decrypter.ToBytes(), 100)
+	})
+}
+
+func TestDecrypter_WithProgress(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world"), 100)
+	ciphertext := NewEncrypter().FromBytes(data).ByAes(newAesCipher()).ToRawBytes()
+	file := mock.NewFile(ciphertext, "test.txt")
+
+	var done, total int64
+	decrypter := NewDecrypter().FromRawFile(file).WithBufferSize(64).WithProgress(func(d, t int64) {
+		done, total = d, t
+	}).ByAes(newAesCipher())
+	assert.Nil(t, decrypter.Error)
+	assert.Equal(t, data, decrypter.ToBytes())
+	assert.Equal(t, int64(len(ciphertext)), done)
+	assert.Equal(t, int64(len(ciphertext)), total)
+}
+
+func TestDecrypter_FromFS(t *testing.T) {
+	encrypter := NewEncrypter().FromString("hello world").ByAes(newAesCipher())
+	fsys := fstest.MapFS{
+		"raw.bin":    {Data: encrypter.ToRawBytes()},
+		"base64.txt": {Data: encrypter.ToBase64Bytes()},
+		"hex.txt":    {Data: encrypter.ToHexBytes()},
+	}
+
+	assert.Equal(t, "hello world", NewDecrypter().FromRawFS(fsys, "raw.bin").ByAes(newAesCipher()).ToString())
+	assert.Equal(t, "hello world", NewDecrypter().FromBase64FS(fsys, "base64.txt").ByAes(newAesCipher()).ToString())
+	assert.Equal(t, "hello world", NewDecrypter().FromHexFS(fsys, "hex.txt").ByAes(newAesCipher()).ToString())
+
+	for _, d := range []Decrypter{
+		NewDecrypter().FromRawFS(fsys, "missing"),
+		NewDecrypter().FromBase64FS(fsys, "missing"),
+		NewDecrypter().FromHexFS(fsys, "missing"),
+	} {
+		assert.Error(t, d.ByAes(newAesCipher()).Error)
+	}
+}
+
+func TestDecrypter_FromHexFormatString(t *testing.T) {
+	encrypter := NewEncrypter().FromString("hello world").ByAes(newAesCipher())
+	code := encrypter.ToHexFormat(hex.Format{Uppercase: true, GroupSize: 4, Separator: ':'})
+	decrypter := NewDecrypter().FromHexFormatString(code).ByAes(newAesCipher())
+	assert.NoError(t, decrypter.Error)
+	assert.Equal(t, "hello world", decrypter.ToString())
+
+	decrypter = NewDecrypter().FromHexFormatString("ABC").ByAes(newAesCipher())
+	assert.Equal(t, hex.CorruptInputError(3), decrypter.Error)
+}
+
+func TestDecrypter_To(t *testing.T) {
+	r := NewEncrypter().FromString("hello world").ByAes(newAesCipher()).To()
+	assert.NoError(t, r.Error())
+	assert.Equal(t, 16, r.Len())
+
+	decrypted := NewDecrypter().FromRawBytes(r.Bytes()).ByAes(newAesCipher()).To()
+	assert.NoError(t, decrypted.Error())
+	assert.Equal(t, "hello world", decrypted.String())
+	assert.Equal(t, "68656c6c6f20776f726c64", decrypted.Hex())
+
+	decrypted = NewDecrypter().FromHexString(r.Base64()).ByAes(newAesCipher()).To()
+	assert.Error(t, decrypted.Error())
+	assert.Zero(t, decrypted.Len())
+}
@@ -0,0 +1,10 @@
+package crypto
+
+import "github.com/dromara/dongle/deprecation"
+
+// deprecated reports the use of the named algorithm and, for block ciphers,
+// its block mode to the deprecation package. It returns a non-nil error only
+// in strict mode.
+func deprecated(algorithm string, modes ...string) error {
+	return deprecation.Check(append([]string{algorithm}, modes...)...)
+}
@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/deprecation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	t.Cleanup(deprecation.Reset)
+	var warnings []string
+	deprecation.SetHandler(func(w deprecation.Warning) { warnings = append(warnings, w.Algorithm) })
+
+	t.Run("algorithm", func(t *testing.T) {
+		warnings = nil
+		c := cipher.NewDesCipher(cipher.CBC)
+		c.SetKey(desKey8)
+		c.SetIV(desIv8)
+		c.SetPadding(cipher.PKCS7)
+		encrypted := NewEncrypter().FromBytes(desTestData).ByDes(c)
+		assert.NoError(t, encrypted.Error)
+		decrypted := NewDecrypter().FromRawBytes(encrypted.ToRawBytes()).ByDes(c)
+		assert.Equal(t, desTestData, decrypted.ToBytes())
+		assert.Equal(t, []string{"DES", "DES"}, warnings)
+	})
+
+	t.Run("block mode", func(t *testing.T) {
+		warnings = nil
+		c := cipher.NewAesCipher(cipher.ECB)
+		c.SetKey(key16)
+		c.SetPadding(cipher.PKCS7)
+		assert.NoError(t, NewEncrypter().FromString("hello").ByAes(c).Error)
+		assert.Equal(t, []string{"ECB"}, warnings)
+
+		warnings = nil
+		assert.NoError(t, NewEncrypter().FromString("hello").ByAes(newAesCipher()).Error)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("user marked", func(t *testing.T) {
+		t.Cleanup(func() { deprecation.Allow("RC4") })
+		warnings = nil
+		deprecation.Deprecate(deprecation.Warning{Algorithm: "RC4", Replacement: "ChaCha20-Poly1305"})
+		c := cipher.NewRc4Cipher()
+		c.SetKey(key16)
+		assert.NoError(t, NewEncrypter().FromString("hello").ByRc4(c).Error)
+		assert.Equal(t, []string{"RC4"}, warnings)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		t.Cleanup(func() { deprecation.SetStrict(false) })
+		deprecation.SetStrict(true)
+		c := cipher.NewAesCipher(cipher.ECB)
+		c.SetKey(key16)
+		c.SetPadding(cipher.PKCS7)
+		encrypter := NewEncrypter().FromString("hello").ByAes(c)
+		assert.Equal(t, "ECB", encrypter.Error.(deprecation.Warning).Algorithm)
+		assert.Empty(t, encrypter.ToRawBytes())
+		decrypter := NewDecrypter().FromRawString("hello").ByAes(c)
+		assert.IsType(t, deprecation.Warning{}, decrypter.Error)
+		assert.NoError(t, NewEncrypter().FromString("hello").ByAes(newAesCipher()).Error)
+	})
+}
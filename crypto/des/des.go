@@ -38,6 +38,9 @@ func NewStdEncrypter(c *cipher.DesCipher) *StdEncrypter {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckGCMOptions(); e.Error != nil {
+		return e
+	}
 
 	return e
 }
@@ -58,6 +61,11 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, des.NewCipher)
+	}
+
 	block, err := des.NewCipher(e.cipher.Key)
 	if err != nil {
 		err = EncryptError{Err: err}
@@ -93,6 +101,9 @@ func NewStdDecrypter(c *cipher.DesCipher) *StdDecrypter {
 		d.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return d
 	}
+	if d.Error = c.CheckGCMOptions(); d.Error != nil {
+		return d
+	}
 
 	return d
 }
@@ -113,6 +124,11 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, des.NewCipher)
+	}
+
 	block, err := des.NewCipher(d.cipher.Key)
 	if err != nil {
 		err = DecryptError{Err: err}
@@ -154,6 +170,10 @@ func NewStreamEncrypter(w io.Writer, c *cipher.DesCipher) io.WriteCloser {
 		return e
 	}
 
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
+
 	e.block, e.Error = des.NewCipher(c.Key)
 	return e
 }
@@ -249,6 +269,10 @@ func NewStreamDecrypter(r io.Reader, c *cipher.DesCipher) io.Reader {
 		return d
 	}
 
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+
 	d.block, d.Error = des.NewCipher(c.Key)
 	return d
 }
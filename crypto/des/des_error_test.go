@@ -1118,3 +1118,30 @@ func TestStreamEncrypter_Write_ErrorPaths(t *testing.T) {
 		assert.Equal(t, len(testDataError), n)
 	})
 }
+
+// TestUnsupportedOptions tests that options needing GCM are rejected
+func TestUnsupportedOptions(t *testing.T) {
+	t.Run("deterministic nonce", func(t *testing.T) {
+		c := cipher.NewDesCipher(cipher.CBC)
+		c.SetKey(key8Error)
+		c.SetIV(iv8Error)
+		c.SetDeterministicNonce(true)
+
+		encrypter := NewStdEncrypter(c)
+		assert.IsType(t, cipher.UnsupportedDeterministicNonceError{}, encrypter.Error)
+		decrypter := NewStdDecrypter(c)
+		assert.IsType(t, cipher.UnsupportedDeterministicNonceError{}, decrypter.Error)
+	})
+
+	t.Run("key commitment", func(t *testing.T) {
+		c := cipher.NewDesCipher(cipher.CBC)
+		c.SetKey(key8Error)
+		c.SetIV(iv8Error)
+		c.SetKeyCommitment(true)
+
+		encrypter := NewStdEncrypter(c)
+		assert.IsType(t, cipher.UnsupportedKeyCommitmentError{}, encrypter.Error)
+		decrypter := NewStdDecrypter(c)
+		assert.IsType(t, cipher.UnsupportedKeyCommitmentError{}, decrypter.Error)
+	})
+}
@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"io"
+
+	"github.com/dromara/dongle/crypto/ecdsa"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// ByEcdsa signs by ecdsa.
+func (s Signer) ByEcdsa(kp *keypair.EcdsaKeyPair) Signer {
+	if s.Error != nil {
+		return s
+	}
+
+	o := observe(OperationSign, "ECDSA", s.data, s.reader)
+	defer o.stop(&s.sign, &s.Error)
+
+	if s.Error = deprecated("ECDSA"); s.Error != nil {
+		return s
+	}
+
+	// Streaming signing mode
+	if s.reader != nil {
+		s.sign, s.Error = s.stream(func(w io.Writer) io.WriteCloser {
+			return ecdsa.NewStreamSigner(w, kp)
+		})
+		return s
+	}
+
+	// Standard signing mode
+	if len(s.data) > 0 {
+		s.sign, s.Error = ecdsa.NewStdSigner(kp).Sign(s.data)
+	}
+
+	return s
+}
+
+// ByEcdsa verifies by ecdsa.
+func (v Verifier) ByEcdsa(kp *keypair.EcdsaKeyPair) Verifier {
+	if v.Error != nil {
+		return v
+	}
+
+	o := observe(OperationVerify, "ECDSA", v.data, v.reader)
+	defer o.stop(nil, &v.Error)
+
+	if v.Error = deprecated("ECDSA"); v.Error != nil {
+		return v
+	}
+
+	// Streaming verification mode
+	if v.reader != nil {
+		defer utils.CloseFS(v.reader)
+
+		// Create a stream verifier
+		verifier := ecdsa.NewStreamVerifier(v.reader, kp)
+		defer verifier.Close()
+
+		// Write data to the stream verifier
+		if len(v.data) > 0 {
+			_, v.Error = verifier.Write(v.data)
+		}
+
+		// Close the verifier to perform verification
+		v.Error = verifier.Close()
+		if v.Error != nil {
+			return v
+		}
+
+		v.verify = true
+		return v
+	}
+
+	// Standard verification mode
+	if len(v.data) > 0 {
+		signature := v.sign
+		if len(signature) == 0 {
+			v.Error = &keypair.EmptySignatureError{}
+			return v
+		}
+
+		valid, err := ecdsa.NewStdVerifier(kp).Verify(v.data, signature)
+		if err != nil {
+			v.Error = err
+			return v
+		}
+		if valid {
+			v.verify = true
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,36 @@
+// Package ecdsa implements ECDSA digital signature generation and verification with streaming support.
+// It signs the digest of the data with the hash configured on the key pair and encodes
+// signatures in ASN.1 DER, on NIST, Brainpool and secp256k1 curves.
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"hash"
+
+	"github.com/dromara/dongle/crypto/keypair"
+)
+
+type cache struct {
+	pubKey *ecdsa.PublicKey // Cached public key for better performance
+	signer crypto.Signer    // Private key or external signer
+}
+
+// hashFunc returns the hash configured on the key pair, SHA-256 by default.
+func hashFunc(kp *keypair.EcdsaKeyPair) crypto.Hash {
+	if kp.Hash == 0 {
+		return crypto.SHA256
+	}
+	return kp.Hash
+}
+
+// newHash returns a new instance of the hash configured on the key pair.
+func newHash(kp *keypair.EcdsaKeyPair) (hash.Hash, error) {
+	h := hashFunc(kp)
+	if !h.Available() {
+		return nil, UnsupportedHashError{Hash: h}
+	}
+	return h.New(), nil
+}
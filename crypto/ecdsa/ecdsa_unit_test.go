@@ -0,0 +1,192 @@
+package ecdsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func genEcdsaKeyPair(t *testing.T, curve elliptic.Curve) *keypair.EcdsaKeyPair {
+	t.Helper()
+
+	kp := keypair.NewEcdsaKeyPair()
+	kp.SetCurve(curve)
+	require.NoError(t, kp.GenKeyPair())
+	return kp
+}
+
+func TestErrorMessages(t *testing.T) {
+	assert.Equal(t, "crypto/ecdsa: failed to sign data: boom", SignError{Err: errors.New("boom")}.Error())
+	assert.Equal(t, "crypto/ecdsa: failed to verify signature: oops", VerifyError{Err: errors.New("oops")}.Error())
+	assert.Equal(t, "crypto/ecdsa: failed to read data: nope", ReadError{Err: errors.New("nope")}.Error())
+	assert.Equal(t, "crypto/ecdsa: unsupported hash function MD4", UnsupportedHashError{Hash: crypto.MD4}.Error())
+}
+
+func TestStdSigner(t *testing.T) {
+	curves := []elliptic.Curve{elliptic.P224(), elliptic.P256(), keypair.BrainpoolP256r1(), keypair.BrainpoolP384r1()}
+	for _, curve := range curves {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			kp := genEcdsaKeyPair(t, curve)
+			signature, err := NewStdSigner(kp).Sign([]byte("hello world"))
+			require.NoError(t, err)
+
+			pub, err := kp.ParsePublicKey()
+			require.NoError(t, err)
+			digest := sha256.Sum256([]byte("hello world"))
+			assert.True(t, ecdsa.VerifyASN1(pub, digest[:], signature))
+
+			valid, err := NewStdVerifier(kp).Verify([]byte("hello world"), signature)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = NewStdVerifier(kp).Verify([]byte("hello World"), signature)
+			assert.IsType(t, VerifyError{}, err)
+			assert.False(t, valid)
+		})
+	}
+
+	t.Run("openssl brainpoolP384r1 signature", func(t *testing.T) {
+		// Produced with: openssl dgst -sha384 -sign key.pem, on "hello world".
+		kp := keypair.NewEcdsaKeyPair()
+		kp.SetHash(crypto.SHA384)
+		kp.PublicKey = []byte(`-----BEGIN PUBLIC KEY-----
+MHowFAYHKoZIzj0CAQYJKyQDAwIIAQELA2IABBex/YbIDqjn1eML9C1fSo3fLq3B
+M16tPqGtRZpPzJ1ZiAubmxmh14nlfhg+ZPQFKm4Er4+DItHG/TCTM28Inql6ZMWk
+DG8iPJWgcLuuG/wYaAcHa9b0GfHrI/fO00NCOA==
+-----END PUBLIC KEY-----
+`)
+		signature, _ := hex.DecodeString("306402302b772be7c4dff6e242384fe8da7a8ff7f60c697f587e64fd65d95482a04ad054253f3edb38aad53815a006d0bf2493ed0230211ab32f6a7b82c01ea47988f18a33481257e754be34b06e882624be6e9783599d6a7c5b8b86ea1290b1cb60a44158fc")
+		valid, err := NewStdVerifier(kp).Verify([]byte("hello world"), signature)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		signer := NewStdSigner(keypair.NewEcdsaKeyPair())
+		assert.Equal(t, SignError{Err: keypair.EmptyPrivateKeyError{}}, signer.Error)
+		_, err := signer.Sign([]byte("data"))
+		assert.Error(t, err)
+
+		verifier := NewStdVerifier(keypair.NewEcdsaKeyPair())
+		assert.Equal(t, VerifyError{Err: keypair.EmptyPublicKeyError{}}, verifier.Error)
+
+		kp := genEcdsaKeyPair(t, elliptic.P256())
+		sign, err := NewStdSigner(kp).Sign(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, sign)
+		_, err = NewStdVerifier(kp).Verify([]byte("data"), nil)
+		assert.Equal(t, VerifyError{Err: keypair.EmptySignatureError{}}, err)
+
+		kp.SetHash(crypto.MD4)
+		_, err = NewStdSigner(kp).Sign([]byte("data"))
+		assert.Equal(t, SignError{Err: UnsupportedHashError{Hash: crypto.MD4}}, err)
+		_, err = NewStdVerifier(kp).Verify([]byte("data"), []byte{0x30})
+		assert.Equal(t, VerifyError{Err: UnsupportedHashError{Hash: crypto.MD4}}, err)
+
+		bad := keypair.NewEcdsaKeyPair()
+		bad.PrivateKey = []byte("invalid")
+		bad.PublicKey = []byte("-----BEGIN PUBLIC KEY-----\nMAA=\n-----END PUBLIC KEY-----\n")
+		assert.Error(t, NewStdSigner(bad).Error)
+		assert.Error(t, NewStdVerifier(bad).Error)
+	})
+}
+
+func TestStreamSigner(t *testing.T) {
+	kp := genEcdsaKeyPair(t, keypair.BrainpoolP256r1())
+	kp.SetHash(crypto.SHA512)
+
+	var buf bytes.Buffer
+	signer := NewStreamSigner(&buf, kp)
+	_, err := signer.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = signer.Write([]byte("world"))
+	require.NoError(t, err)
+	require.NoError(t, signer.Close())
+
+	valid, err := NewStdVerifier(kp).Verify([]byte("hello world"), buf.Bytes())
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	t.Run("stream verifier", func(t *testing.T) {
+		verifier := NewStreamVerifier(bytes.NewReader(buf.Bytes()), kp)
+		_, err := verifier.Write([]byte("hello world"))
+		require.NoError(t, err)
+		assert.NoError(t, verifier.Close())
+
+		verifier = NewStreamVerifier(bytes.NewReader(buf.Bytes()), kp)
+		_, _ = verifier.Write([]byte("hello World"))
+		assert.IsType(t, VerifyError{}, verifier.Close())
+
+		verifier = NewStreamVerifier(mock.NewErrorFile(errors.New("read error")), kp)
+		_, _ = verifier.Write([]byte("hello world"))
+		assert.Equal(t, ReadError{Err: errors.New("read error")}, verifier.Close())
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		var out bytes.Buffer
+		signer := NewStreamSigner(&out, kp)
+		n, err := signer.Write(nil)
+		assert.Zero(t, n)
+		assert.NoError(t, err)
+		assert.NoError(t, signer.Close())
+		assert.Zero(t, out.Len())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		signer := NewStreamSigner(&bytes.Buffer{}, keypair.NewEcdsaKeyPair())
+		_, err := signer.Write([]byte("data"))
+		assert.Error(t, err)
+		assert.Error(t, signer.Close())
+
+		verifier := NewStreamVerifier(&bytes.Buffer{}, keypair.NewEcdsaKeyPair())
+		_, err = verifier.Write([]byte("data"))
+		assert.Error(t, err)
+		assert.Error(t, verifier.Close())
+
+		signer = NewStreamSigner(mock.NewErrorWriteCloser(errors.New("write error")), kp)
+		_, _ = signer.Write([]byte("data"))
+		assert.Equal(t, errors.New("write error"), signer.Close())
+	})
+}
+
+func TestExternalSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer := mock.NewSigner(key)
+	kp := keypair.NewEcdsaKeyPair()
+	require.NoError(t, kp.SetSigner(signer))
+	data := []byte("hello world")
+
+	sign, err := NewStdSigner(kp).Sign(data)
+	require.NoError(t, err)
+	valid, err := NewStdVerifier(kp).Verify(data, sign)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	var out bytes.Buffer
+	ss := NewStreamSigner(&out, kp)
+	_, err = ss.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, ss.Close())
+	valid, err = NewStdVerifier(kp).Verify(data, out.Bytes())
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	signs, _ := signer.Calls()
+	assert.Equal(t, 2, signs)
+
+	signer.FailWith(errors.New("hsm unavailable"))
+	_, err = NewStdSigner(kp).Sign(data)
+	assert.Equal(t, SignError{Err: errors.New("hsm unavailable")}, err)
+}
@@ -0,0 +1,82 @@
+package ecdsa
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+type SignError struct {
+	Err error
+}
+
+func (e SignError) Error() string {
+	return fmt.Sprintf("crypto/ecdsa: failed to sign data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ECDSA-001.
+func (e SignError) Code() string {
+	return "DGL-ECDSA-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ecdsa", "ECDSA", "sign", errcode.FieldCause, e.Err)
+}
+
+type VerifyError struct {
+	Err error
+}
+
+func (e VerifyError) Error() string {
+	return fmt.Sprintf("crypto/ecdsa: failed to verify signature: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ECDSA-002.
+func (e VerifyError) Code() string {
+	return "DGL-ECDSA-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e VerifyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ecdsa", "ECDSA", "verify", errcode.FieldCause, e.Err)
+}
+
+type ReadError struct {
+	Err error
+}
+
+func (e ReadError) Error() string {
+	return fmt.Sprintf("crypto/ecdsa: failed to read data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ECDSA-003.
+func (e ReadError) Code() string {
+	return "DGL-ECDSA-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ecdsa", "ECDSA", "read", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedHashError represents an error when the hash configured on the key
+// pair is not linked into the binary.
+type UnsupportedHashError struct {
+	Hash crypto.Hash
+}
+
+func (e UnsupportedHashError) Error() string {
+	return fmt.Sprintf("crypto/ecdsa: unsupported hash function %s", e.Hash)
+}
+
+// Code returns the stable error code DGL-ECDSA-004.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-ECDSA-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ecdsa", "ECDSA", "", "hash", e.Hash.String())
+}
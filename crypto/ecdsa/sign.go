@@ -0,0 +1,126 @@
+package ecdsa
+
+import (
+	"hash"
+	"io"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// StdSigner represents a standard ECDSA signer.
+type StdSigner struct {
+	keypair keypair.EcdsaKeyPair // The key pair containing private key
+	cache   cache                // Cached keys for better performance
+	Error   error                // Error field for storing signature errors
+}
+
+// NewStdSigner creates a new standard ECDSA signer.
+func NewStdSigner(kp *keypair.EcdsaKeyPair) *StdSigner {
+	s := &StdSigner{
+		keypair: *kp,
+	}
+	signer, err := kp.Signer()
+	if err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
+	s.cache.signer = signer
+
+	return s
+}
+
+// Sign generates an ASN.1 DER signature for the given data using the ECDSA private key
+// or the external signer of the key pair.
+func (s *StdSigner) Sign(src []byte) (sign []byte, err error) {
+	if s.Error != nil {
+		err = s.Error
+		return
+	}
+
+	if len(src) == 0 {
+		return
+	}
+
+	h, err := newHash(&s.keypair)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+	h.Write(src)
+	sign, err = s.cache.signer.Sign(utils.Rand(), h.Sum(nil), hashFunc(&s.keypair))
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+	return
+}
+
+// StreamSigner represents a streaming ECDSA signer that hashes data in chunks.
+type StreamSigner struct {
+	keypair keypair.EcdsaKeyPair // Key pair containing private key
+	cache   cache                // Cached keys for better performance
+	writer  io.Writer            // Underlying writer for signature output
+	hasher  hash.Hash            // Running hash of the written data
+	written bool                 // Whether any data has been written
+	Error   error                // Error field for storing signature errors
+}
+
+// NewStreamSigner creates a new streaming ECDSA signer.
+func NewStreamSigner(w io.Writer, kp *keypair.EcdsaKeyPair) io.WriteCloser {
+	s := &StreamSigner{
+		writer:  w,
+		keypair: *kp,
+	}
+	signer, err := kp.Signer()
+	if err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
+	s.cache.signer = signer
+
+	if s.hasher, err = newHash(kp); err != nil {
+		s.Error = SignError{Err: err}
+	}
+	return s
+}
+
+// Write hashes data for signing.
+func (s *StreamSigner) Write(p []byte) (n int, err error) {
+	if s.Error != nil {
+		err = s.Error
+		return
+	}
+
+	if len(p) == 0 {
+		return
+	}
+
+	s.written = true
+	return s.hasher.Write(p)
+}
+
+// Close generates the signature and writes it to the underlying writer.
+func (s *StreamSigner) Close() error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if !s.written {
+		return nil
+	}
+
+	signature, err := s.cache.signer.Sign(utils.Rand(), s.hasher.Sum(nil), hashFunc(&s.keypair))
+	if err != nil {
+		return SignError{Err: err}
+	}
+
+	// Write signature to the underlying writer
+	if _, err = s.writer.Write(signature); err != nil {
+		return err
+	}
+
+	// Close the underlying writer if it implements io.Closer
+	if closer, ok := s.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"hash"
+	"io"
+
+	"github.com/dromara/dongle/crypto/keypair"
+)
+
+// StdVerifier represents a standard ECDSA verifier.
+type StdVerifier struct {
+	keypair keypair.EcdsaKeyPair
+	cache   cache // Cached keys for better performance
+	Error   error // Error field for storing verification errors
+}
+
+// NewStdVerifier creates a new standard ECDSA verifier.
+func NewStdVerifier(kp *keypair.EcdsaKeyPair) *StdVerifier {
+	v := &StdVerifier{
+		keypair: *kp,
+	}
+	if len(kp.PublicKey) == 0 {
+		v.Error = VerifyError{Err: keypair.EmptyPublicKeyError{}}
+		return v
+	}
+
+	pubKey, err := kp.ParsePublicKey()
+	if err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
+	v.cache.pubKey = pubKey
+
+	return v
+}
+
+// Verify verifies the ASN.1 DER signature for the given data using the ECDSA public key.
+func (v *StdVerifier) Verify(src, sign []byte) (valid bool, err error) {
+	// Check for existing errors from initialization
+	if v.Error != nil {
+		err = v.Error
+		return
+	}
+	if len(src) == 0 {
+		return
+	}
+	if len(sign) == 0 {
+		err = VerifyError{Err: keypair.EmptySignatureError{}}
+		return
+	}
+
+	h, err := newHash(&v.keypair)
+	if err != nil {
+		return false, VerifyError{Err: err}
+	}
+	h.Write(src)
+	valid = ecdsa.VerifyASN1(v.cache.pubKey, h.Sum(nil), sign)
+	if !valid {
+		v.Error = VerifyError{Err: nil}
+		return false, v.Error
+	}
+	return
+}
+
+// StreamVerifier represents a streaming ECDSA verifier that hashes data in chunks.
+type StreamVerifier struct {
+	keypair keypair.EcdsaKeyPair // Key pair containing public key
+	cache   cache                // Cached keys for better performance
+	reader  io.Reader            // Underlying reader for signature input
+	hasher  hash.Hash            // Running hash of the written data
+	written bool                 // Whether any data has been written
+	Error   error                // Error field for storing verification errors
+}
+
+// NewStreamVerifier creates a new streaming ECDSA verifier that reads the
+// signature from r.
+func NewStreamVerifier(r io.Reader, kp *keypair.EcdsaKeyPair) io.WriteCloser {
+	v := &StreamVerifier{
+		reader:  r,
+		keypair: *kp,
+	}
+	if len(kp.PublicKey) == 0 {
+		v.Error = VerifyError{Err: keypair.EmptyPublicKeyError{}}
+		return v
+	}
+
+	pubKey, err := kp.ParsePublicKey()
+	if err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
+	v.cache.pubKey = pubKey
+
+	if v.hasher, err = newHash(kp); err != nil {
+		v.Error = VerifyError{Err: err}
+	}
+	return v
+}
+
+// Write hashes data for verification.
+func (v *StreamVerifier) Write(p []byte) (n int, err error) {
+	if v.Error != nil {
+		err = v.Error
+		return
+	}
+
+	if len(p) == 0 {
+		return
+	}
+
+	v.written = true
+	return v.hasher.Write(p)
+}
+
+// Close performs the final verification.
+func (v *StreamVerifier) Close() error {
+	if v.Error != nil {
+		return v.Error
+	}
+
+	// Read signature data from the underlying reader
+	signature, err := io.ReadAll(v.reader)
+	if err != nil {
+		return ReadError{Err: err}
+	}
+	if len(signature) == 0 || !v.written {
+		return nil
+	}
+
+	if !ecdsa.VerifyASN1(v.cache.pubKey, v.hasher.Sum(nil), signature) {
+		return VerifyError{Err: nil}
+	}
+
+	// Close the underlying reader if it implements io.Closer
+	if closer, ok := v.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
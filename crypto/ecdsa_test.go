@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/ecdsa"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSignerByEcdsa tests the Signer.ByEcdsa method
+func TestSignerByEcdsa(t *testing.T) {
+	t.Run("standard signing mode", func(t *testing.T) {
+		kp := keypair.NewEcdsaKeyPair()
+		kp.SetCurve(keypair.BrainpoolP256r1())
+		require.NoError(t, kp.GenKeyPair())
+
+		signer := NewSigner().FromString("hello world").ByEcdsa(kp)
+		assert.Nil(t, signer.Error)
+		assert.NotEmpty(t, signer.sign)
+
+		verifier := NewVerifier().FromString("hello world").WithRawSign(signer.sign).ByEcdsa(kp)
+		assert.Nil(t, verifier.Error)
+		assert.True(t, verifier.ToBool())
+	})
+
+	t.Run("streaming signing mode", func(t *testing.T) {
+		kp := keypair.NewEcdsaKeyPair()
+		require.NoError(t, kp.GenKeyPair())
+
+		file := mock.NewFile([]byte("hello world"), "test.txt")
+		defer file.Close()
+
+		signer := NewSigner().FromFile(file).ByEcdsa(kp)
+		assert.Nil(t, signer.Error)
+		assert.NotEmpty(t, signer.sign)
+	})
+
+	t.Run("with existing error", func(t *testing.T) {
+		signer := Signer{Error: errors.New("existing error")}
+		result := signer.FromString("hello world").ByEcdsa(keypair.NewEcdsaKeyPair())
+		assert.Equal(t, errors.New("existing error"), result.Error)
+		assert.Nil(t, result.sign)
+	})
+
+	t.Run("empty key pair", func(t *testing.T) {
+		signer := NewSigner().FromString("hello world").ByEcdsa(keypair.NewEcdsaKeyPair())
+		assert.IsType(t, ecdsa.SignError{}, signer.Error)
+	})
+}
+
+// TestVerifierByEcdsa tests the Verifier.ByEcdsa method
+func TestVerifierByEcdsa(t *testing.T) {
+	kp := keypair.NewEcdsaKeyPair()
+	kp.SetCurve(keypair.BrainpoolP384r1())
+	require.NoError(t, kp.GenKeyPair())
+	signer := NewSigner().FromString("test data").ByEcdsa(kp)
+	require.NoError(t, signer.Error)
+
+	t.Run("standard verification mode", func(t *testing.T) {
+		verifier := NewVerifier().FromString("test data").WithRawSign(signer.sign).ByEcdsa(kp)
+		assert.Nil(t, verifier.Error)
+		assert.True(t, verifier.verify)
+	})
+
+	t.Run("streaming verification mode", func(t *testing.T) {
+		file := mock.NewFile(signer.sign, "test.sig")
+		defer file.Close()
+
+		verifier := NewVerifier().FromFile(file)
+		verifier.data = []byte("test data")
+		verifier = verifier.ByEcdsa(kp)
+		assert.Nil(t, verifier.Error)
+		assert.True(t, verifier.verify)
+	})
+
+	t.Run("with existing error", func(t *testing.T) {
+		verifier := Verifier{Error: errors.New("existing error")}
+		result := verifier.FromString("test data").ByEcdsa(kp)
+		assert.Equal(t, errors.New("existing error"), result.Error)
+		assert.False(t, result.verify)
+	})
+
+	t.Run("no signature", func(t *testing.T) {
+		verifier := NewVerifier().FromString("test data").ByEcdsa(kp)
+		assert.IsType(t, &keypair.EmptySignatureError{}, verifier.Error)
+	})
+
+	t.Run("wrong key pair", func(t *testing.T) {
+		other := keypair.NewEcdsaKeyPair()
+		other.SetCurve(keypair.BrainpoolP384r1())
+		require.NoError(t, other.GenKeyPair())
+
+		verifier := NewVerifier().FromString("test data").WithRawSign(signer.sign).ByEcdsa(other)
+		assert.IsType(t, ecdsa.VerifyError{}, verifier.Error)
+		assert.False(t, verifier.verify)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dromara/dongle/crypto/ed25519"
 	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 // ByEd25519 signs by ed25519.
@@ -13,6 +14,13 @@ func (s Signer) ByEd25519(kp *keypair.Ed25519KeyPair) Signer {
 		return s
 	}
 
+	o := observe(OperationSign, "Ed25519", s.data, s.reader)
+	defer o.stop(&s.sign, &s.Error)
+
+	if s.Error = deprecated("Ed25519"); s.Error != nil {
+		return s
+	}
+
 	// Streaming signing mode
 	if s.reader != nil {
 		s.sign, s.Error = s.stream(func(w io.Writer) io.WriteCloser {
@@ -35,8 +43,17 @@ func (v Verifier) ByEd25519(kp *keypair.Ed25519KeyPair) Verifier {
 		return v
 	}
 
+	o := observe(OperationVerify, "Ed25519", v.data, v.reader)
+	defer o.stop(nil, &v.Error)
+
+	if v.Error = deprecated("Ed25519"); v.Error != nil {
+		return v
+	}
+
 	// Streaming verification mode
 	if v.reader != nil {
+		defer utils.CloseFS(v.reader)
+
 		// Create a stream verifier
 		verifier := ed25519.NewStreamVerifier(v.reader, kp)
 		defer verifier.Close()
@@ -0,0 +1,179 @@
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+
+	"filippo.io/edwards25519"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// batchSize is the number of signatures checked by one multi-scalar
+// multiplication. Larger batches save little more and cost more to bisect.
+const batchSize = 64
+
+// BatchItem is a message, its signature and the key pair holding the public
+// key and variant to verify it with.
+type BatchItem struct {
+	KeyPair *keypair.Ed25519KeyPair // Public key, variant and context
+	Data    []byte                  // Signed message
+	Sign    []byte                  // Raw 64-byte signature
+}
+
+// VerifyBatch verifies the signatures of many items and returns one result per
+// item: nil when its signature is valid, a VerifyError otherwise. The items are
+// split into batches of up to 64 signatures, each checked at once by a single
+// multi-scalar multiplication over random linear combinations of the
+// verification equations, in less than half the time of verifying them one
+// by one. Only the items of a batch that fails are checked individually.
+// Batches run on up to workers goroutines, on the calling goroutine with
+// workers of 1 or less.
+//
+// Every item is checked with the cofactored equation [8]sB = [8]R + [8]kA of
+// RFC 8032, section 5.1.7, whether its batch passes or not, so the result of an
+// item never depends on the other items. Verify and the Verifier API use the
+// stricter cofactorless equation of crypto/ed25519, which additionally rejects
+// signatures crafted by the key holder with small order components; both agree
+// on every signature produced by a conforming signer.
+func VerifyBatch(items []BatchItem, workers int) []error {
+	errs := make([]error, len(items))
+	sigs := make([]*batchSignature, len(items))
+	keys := make(map[*keypair.Ed25519KeyPair]*batchKey)
+	for i, item := range items {
+		key, ok := keys[item.KeyPair]
+		if !ok {
+			key = newBatchKey(item.KeyPair)
+			keys[item.KeyPair] = key
+		}
+		sigs[i], errs[i] = key.signature(item.Data, item.Sign)
+	}
+
+	utils.Batch((len(items)+batchSize-1)/batchSize, workers, func(_, n int) {
+		lo, hi := n*batchSize, min((n+1)*batchSize, len(items))
+		batch := make([]*batchSignature, 0, hi-lo)
+		for _, sig := range sigs[lo:hi] {
+			if sig != nil {
+				batch = append(batch, sig)
+			}
+		}
+		if verifyBatch(batch) {
+			return
+		}
+		for i := lo; i < hi; i++ {
+			if sigs[i] != nil && !sigs[i].verify() {
+				errs[i] = VerifyError{Err: nil}
+			}
+		}
+	})
+	return errs
+}
+
+// batchKey is a public key decoded once for all the items it verifies.
+type batchKey struct {
+	kp    *keypair.Ed25519KeyPair
+	raw   ed25519.PublicKey
+	point *edwards25519.Point
+	err   error
+}
+
+// newBatchKey parses the public key of the key pair.
+func newBatchKey(kp *keypair.Ed25519KeyPair) *batchKey {
+	k := &batchKey{kp: kp}
+	if kp == nil || len(kp.PublicKey) == 0 {
+		k.err = VerifyError{Err: keypair.EmptyPublicKeyError{}}
+		return k
+	}
+	if k.raw, k.err = kp.ParsePublicKey(); k.err != nil {
+		k.err = VerifyError{Err: k.err}
+		return k
+	}
+	if k.point, k.err = new(edwards25519.Point).SetBytes(k.raw); k.err != nil {
+		k.err = VerifyError{Err: nil}
+	}
+	return k
+}
+
+// batchSignature is a decoded signature with its challenge scalar.
+type batchSignature struct {
+	a *edwards25519.Point  // Public key A
+	r *edwards25519.Point  // Commitment R
+	s *edwards25519.Scalar // Response S
+	k *edwards25519.Scalar // Challenge k = SHA-512(dom2(F, C) || R || A || M)
+}
+
+// signature decodes sign and computes the challenge of src under the key.
+func (k *batchKey) signature(src, sign []byte) (*batchSignature, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	if len(sign) == 0 {
+		return nil, VerifyError{Err: keypair.EmptySignatureError{}}
+	}
+	opts, msg, err := prepare(k.kp, src)
+	if err != nil {
+		return nil, VerifyError{Err: err}
+	}
+	if len(sign) != ed25519.SignatureSize {
+		return nil, VerifyError{Err: nil}
+	}
+	r, err := new(edwards25519.Point).SetBytes(sign[:32])
+	if err != nil {
+		return nil, VerifyError{Err: nil}
+	}
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(sign[32:])
+	if err != nil {
+		return nil, VerifyError{Err: nil}
+	}
+
+	h := sha512.New()
+	if opts.Hash != 0 || opts.Context != "" {
+		flag := byte(0)
+		if opts.Hash != 0 {
+			flag = 1
+		}
+		h.Write([]byte("SigEd25519 no Ed25519 collisions"))
+		h.Write([]byte{flag, byte(len(opts.Context))})
+		h.Write([]byte(opts.Context))
+	}
+	h.Write(sign[:32])
+	h.Write(k.raw)
+	h.Write(msg)
+	challenge, _ := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	return &batchSignature{a: k.point, r: r, s: s, k: challenge}, nil
+}
+
+// verify checks the signature alone with the cofactored equation.
+func (sig *batchSignature) verify() bool {
+	// [8](sB - kA - R) must be the identity
+	minusA := new(edwards25519.Point).Negate(sig.a)
+	p := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(sig.k, minusA, sig.s)
+	p.Subtract(p, sig.r)
+	return p.MultByCofactor(p).Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// verifyBatch checks the signatures at once: with random 128-bit scalars z_i,
+// [8](-(Σ z_i s_i)B + Σ z_i R_i + Σ (z_i k_i)A_i) is the identity when every
+// signature is valid, and only with negligible probability otherwise.
+func verifyBatch(sigs []*batchSignature) bool {
+	if len(sigs) == 0 {
+		return true
+	}
+	scalars := make([]*edwards25519.Scalar, 0, 2*len(sigs)+1)
+	points := make([]*edwards25519.Point, 0, 2*len(sigs)+1)
+	sum := edwards25519.NewScalar()
+	buf := make([]byte, 32)
+	for _, sig := range sigs {
+		if _, err := utils.Rand().Read(buf[:16]); err != nil {
+			return false
+		}
+		z, _ := edwards25519.NewScalar().SetCanonicalBytes(buf)
+		sum.MultiplyAdd(z, sig.s, sum)
+		scalars = append(scalars, z, edwards25519.NewScalar().Multiply(z, sig.k))
+		points = append(points, sig.r, sig.a)
+	}
+	scalars = append(scalars, sum.Negate(sum))
+	points = append(points, edwards25519.NewGeneratorPoint())
+	p := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	return p.MultByCofactor(p).Equal(edwards25519.NewIdentityPoint()) == 1
+}
@@ -0,0 +1,140 @@
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchItems returns n valid items signed by keys of every variant.
+func batchItems(t *testing.T, n int) []BatchItem {
+	plain := genEd25519KeyPair(t)
+	ctx := genEd25519KeyPair(t)
+	ctx.SetVariant(keypair.Ed25519ctx)
+	ctx.SetContext([]byte("batch"))
+	ph := genEd25519KeyPair(t)
+	ph.SetVariant(keypair.Ed25519ph)
+	kps := []*keypair.Ed25519KeyPair{plain, ctx, ph}
+
+	items := make([]BatchItem, n)
+	for i := range items {
+		kp := kps[i%len(kps)]
+		data := []byte(fmt.Sprintf("message %d", i))
+		sign, err := NewStdSigner(kp).Sign(data)
+		require.NoError(t, err)
+		items[i] = BatchItem{KeyPair: kp, Data: data, Sign: sign}
+	}
+	return items
+}
+
+func TestVerifyBatch(t *testing.T) {
+	items := batchItems(t, 150)
+
+	t.Run("valid", func(t *testing.T) {
+		for _, workers := range []int{1, 4} {
+			for _, err := range VerifyBatch(items, workers) {
+				assert.NoError(t, err)
+			}
+		}
+		assert.Empty(t, VerifyBatch(nil, 4))
+	})
+
+	t.Run("per item results", func(t *testing.T) {
+		bad := append([]BatchItem(nil), items...)
+		tampered := append([]byte(nil), bad[3].Sign...)
+		tampered[10] ^= 1
+		bad[3].Sign = tampered
+		bad[70].Data = []byte("forged")
+		bad[71].KeyPair = bad[72].KeyPair
+		bad[100].Sign = bad[100].Sign[:63]
+		highS := append([]byte(nil), bad[101].Sign...)
+		highS[63] = 0xff
+		bad[101].Sign = highS
+		bad[102].Sign = nil
+		bad[103].KeyPair = keypair.NewEd25519KeyPair()
+		invalidKey := keypair.NewEd25519KeyPair()
+		invalidKey.PublicKey = []byte("not a key")
+		bad[104].KeyPair = invalidKey
+		invalidContext := genEd25519KeyPair(t)
+		invalidContext.SetVariant(keypair.Ed25519ctx)
+		bad[105].KeyPair = invalidContext
+
+		failed := map[int]bool{3: true, 70: true, 71: true, 100: true, 101: true, 102: true, 103: true, 104: true, 105: true}
+		for _, workers := range []int{1, 4} {
+			errs := VerifyBatch(bad, workers)
+			require.Len(t, errs, len(bad))
+			for i, err := range errs {
+				if failed[i] {
+					assert.ErrorAs(t, err, &VerifyError{}, i)
+				} else {
+					assert.NoError(t, err, i)
+				}
+			}
+			assert.Equal(t, VerifyError{Err: keypair.EmptySignatureError{}}, errs[102])
+			assert.Equal(t, VerifyError{Err: keypair.EmptyPublicKeyError{}}, errs[103])
+			assert.Equal(t, VerifyError{Err: InvalidContextError{}}, errs[105])
+		}
+	})
+
+	t.Run("agrees with std verifier", func(t *testing.T) {
+		for _, item := range items[:6] {
+			valid, err := NewStdVerifier(item.KeyPair).Verify(item.Data, item.Sign)
+			require.NoError(t, err)
+			assert.True(t, valid)
+			assert.NoError(t, VerifyBatch([]BatchItem{item}, 1)[0])
+		}
+	})
+
+	t.Run("cofactored equation", func(t *testing.T) {
+		kp := genEd25519KeyPair(t)
+		_, pri := parseEd25519Keys(t, kp)
+		data := []byte("small order commitment")
+		sign := torsionSign(t, pri, data)
+
+		// The key holder can add a small order point to R: the cofactorless
+		// equation rejects the signature while the cofactored one accepts it.
+		valid, _ := NewStdVerifier(kp).Verify(data, sign)
+		assert.False(t, valid)
+		item := BatchItem{KeyPair: kp, Data: data, Sign: sign}
+		assert.NoError(t, VerifyBatch([]BatchItem{item}, 1)[0])
+		assert.NoError(t, VerifyBatch(append([]BatchItem{item}, items...), 1)[0])
+	})
+}
+
+// torsionSign signs data with a commitment R = rB + T, where T is the point of
+// order 2, so that sB = R - T + kA.
+func torsionSign(t *testing.T, pri ed25519.PrivateKey, data []byte) []byte {
+	digest := sha512.Sum512(pri.Seed())
+	a, err := edwards25519.NewScalar().SetBytesWithClamping(digest[:32])
+	require.NoError(t, err)
+	nonce := sha512.Sum512(append(digest[32:], data...))
+	r, err := edwards25519.NewScalar().SetUniformBytes(nonce[:])
+	require.NoError(t, err)
+
+	// (0, -1) has order 2
+	order2 := make([]byte, 32)
+	order2[0] = 0xec
+	for i := 1; i < 31; i++ {
+		order2[i] = 0xff
+	}
+	order2[31] = 0x7f
+	torsion, err := new(edwards25519.Point).SetBytes(order2)
+	require.NoError(t, err)
+	R := new(edwards25519.Point).ScalarBaseMult(r)
+	R.Add(R, torsion)
+
+	h := sha512.New()
+	h.Write(R.Bytes())
+	h.Write(pri.Public().(ed25519.PublicKey))
+	h.Write(data)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	require.NoError(t, err)
+	s := edwards25519.NewScalar().MultiplyAdd(k, a, r)
+	return append(R.Bytes(), s.Bytes()...)
+}
@@ -1,13 +1,62 @@
 // Package ed25519 implements ED25519 digital signature generation and verification with streaming support.
 // It provides ED25519 operations using the standard ED25519 algorithm with support
-// for high-performance digital signatures and verification.
+// for high-performance digital signatures and verification, as well as the
+// pre-hashed Ed25519ph and context Ed25519ctx variants of RFC 8032.
 package ed25519
 
 import (
+	"crypto"
 	"crypto/ed25519"
+	"crypto/sha512"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 type cache struct {
-	pubKey ed25519.PublicKey  // Cached public key for better performance
-	priKey ed25519.PrivateKey // Cached private key for better performance
+	pubKey ed25519.PublicKey // Cached public key for better performance
+	signer crypto.Signer     // Private key or external signer
+}
+
+// prepare returns the signing options selected by the key pair and the message
+// to sign, which is the SHA-512 digest of src for Ed25519ph.
+func prepare(kp *keypair.Ed25519KeyPair, src []byte) (*ed25519.Options, []byte, error) {
+	if len(kp.Context) > 255 {
+		return nil, nil, InvalidContextError{Size: len(kp.Context)}
+	}
+	switch kp.Variant {
+	case "", keypair.Ed25519:
+		return &ed25519.Options{}, src, nil
+	case keypair.Ed25519ph:
+		digest := sha512.Sum512(src)
+		return &ed25519.Options{Hash: crypto.SHA512, Context: string(kp.Context)}, digest[:], nil
+	case keypair.Ed25519ctx:
+		if len(kp.Context) == 0 {
+			return nil, nil, InvalidContextError{}
+		}
+		return &ed25519.Options{Context: string(kp.Context)}, src, nil
+	}
+	return nil, nil, UnsupportedVariantError{Variant: kp.Variant}
+}
+
+// signVariant signs src with the variant selected by the key pair.
+func signVariant(signer crypto.Signer, kp *keypair.Ed25519KeyPair, src []byte) ([]byte, error) {
+	opts, msg, err := prepare(kp, src)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Hash == 0 && opts.Context == "" {
+		// Plain Ed25519, which external signers only know as crypto.Hash(0)
+		return signer.Sign(utils.Rand(), msg, crypto.Hash(0))
+	}
+	return signer.Sign(utils.Rand(), msg, opts)
+}
+
+// verifyVariant checks the signature of src with the variant selected by the key pair.
+func verifyVariant(key ed25519.PublicKey, kp *keypair.Ed25519KeyPair, src, sig []byte) (bool, error) {
+	opts, msg, err := prepare(kp, src)
+	if err != nil {
+		return false, err
+	}
+	return ed25519.VerifyWithOptions(key, msg, sig, opts) == nil, nil
 }
@@ -230,3 +230,34 @@ func BenchmarkStreamingMemoryUsage(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkVerifyBatch compares batch verification with verifying one by one
+func BenchmarkVerifyBatch(b *testing.B) {
+	kp := keypair.NewEd25519KeyPair()
+	kp.GenKeyPair()
+	signer := NewStdSigner(kp)
+	items := make([]BatchItem, 64)
+	for i := range items {
+		data := generateBenchmarkData(256)
+		sign, _ := signer.Sign(data)
+		items[i] = BatchItem{KeyPair: kp, Data: data, Sign: sign}
+	}
+
+	b.Run("Batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			VerifyBatch(items, 1)
+		}
+	})
+	b.Run("OneByOne", func(b *testing.B) {
+		b.ReportAllocs()
+		verifier := NewStdVerifier(kp)
+		for i := 0; i < b.N; i++ {
+			for _, item := range items {
+				if valid, err := verifier.Verify(item.Data, item.Sign); !valid {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -3,7 +3,12 @@ package ed25519
 import (
 	"bytes"
 	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dromara/dongle/crypto/keypair"
@@ -36,6 +41,132 @@ func TestErrorMessages(t *testing.T) {
 	assert.Equal(t, "crypto/ed25519: failed to sign data: boom", SignError{Err: errors.New("boom")}.Error())
 	assert.Equal(t, "crypto/ed25519: failed to verify signature: oops", VerifyError{Err: errors.New("oops")}.Error())
 	assert.Equal(t, "crypto/ed25519: failed to read data: nope", ReadError{Err: errors.New("nope")}.Error())
+	assert.Equal(t, "crypto/ed25519: invalid context size 256, must be 1-255 bytes for Ed25519ctx and at most 255 bytes otherwise", InvalidContextError{Size: 256}.Error())
+	assert.Equal(t, `crypto/ed25519: unsupported variant "Ed448"`, UnsupportedVariantError{Variant: "Ed448"}.Error())
+}
+
+// seedKeyPair returns a key pair holding the PKCS8/PKIX encoding of the
+// Ed25519 key derived from a hex encoded RFC 8032 secret key.
+func seedKeyPair(t *testing.T, seed string) *keypair.Ed25519KeyPair {
+	t.Helper()
+
+	raw, err := hex.DecodeString(seed)
+	require.NoError(t, err)
+	pri := ed25519.NewKeyFromSeed(raw)
+
+	kp := keypair.NewEd25519KeyPair()
+	der, err := x509.MarshalPKCS8PrivateKey(pri)
+	require.NoError(t, err)
+	kp.PrivateKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	der, err = x509.MarshalPKIXPublicKey(pri.Public())
+	require.NoError(t, err)
+	kp.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return kp
+}
+
+func TestVariants(t *testing.T) {
+	// Test vectors from RFC 8032 sections 7.1, 7.2 and 7.3.
+	vectors := []struct {
+		name    string
+		variant keypair.Ed25519Variant
+		seed    string
+		msg     string
+		context string
+		sign    string
+	}{
+		{"Ed25519", keypair.Ed25519,
+			"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb", "72", "",
+			"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"},
+		{"Ed25519ctx foo", keypair.Ed25519ctx,
+			"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6", "f726936d19c800494e3fdaff20b276a8", "666f6f",
+			"55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d"},
+		{"Ed25519ctx bar", keypair.Ed25519ctx,
+			"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6", "f726936d19c800494e3fdaff20b276a8", "626172",
+			"fc60d5872fc46b3aa69f8b5b4351d5808f92bcc044606db097abab6dbcb1aee3216c48e8b3b66431b5b186d1d28f8ee15a5ca2df6668346291c2043d4eb3e90d"},
+		{"Ed25519ctx other key", keypair.Ed25519ctx,
+			"ab9c2853ce297ddab85c993b3ae14bcad39b2c682beabc27d6d4eb20711d6560", "f726936d19c800494e3fdaff20b276a8", "666f6f",
+			"21655b5f1aa965996b3f97b3c849eafba922a0a62992f73b3d1b73106a84ad85e9b86a7b6005ea868337ff2d20a7f5fbd4cd10b0be49a68da2b2e0dc0ad8960f"},
+		{"Ed25519ph", keypair.Ed25519ph,
+			"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42", "616263", "",
+			"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406"},
+	}
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			kp := seedKeyPair(t, v.seed)
+			kp.SetVariant(v.variant)
+			context, _ := hex.DecodeString(v.context)
+			kp.SetContext(context)
+			msg, _ := hex.DecodeString(v.msg)
+
+			signature, err := NewStdSigner(kp).Sign(msg)
+			require.NoError(t, err)
+			assert.Equal(t, v.sign, hex.EncodeToString(signature))
+
+			var buf bytes.Buffer
+			signer := NewStreamSigner(&buf, kp)
+			_, err = signer.Write(msg)
+			require.NoError(t, err)
+			require.NoError(t, signer.Close())
+			assert.Equal(t, signature, buf.Bytes())
+
+			valid, err := NewStdVerifier(kp).Verify(msg, signature)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			verifier := NewStreamVerifier(bytes.NewReader(signature), kp)
+			_, err = verifier.Write(msg)
+			require.NoError(t, err)
+			assert.NoError(t, verifier.Close())
+		})
+	}
+
+	t.Run("variants are not interchangeable", func(t *testing.T) {
+		kp := genEd25519KeyPair(t)
+		kp.SetVariant(keypair.Ed25519ph)
+		kp.SetContext([]byte("context"))
+		signature, err := NewStdSigner(kp).Sign([]byte("hello world"))
+		require.NoError(t, err)
+
+		for _, variant := range []keypair.Ed25519Variant{keypair.Ed25519, keypair.Ed25519ctx} {
+			kp.SetVariant(variant)
+			valid, err := NewStdVerifier(kp).Verify([]byte("hello world"), signature)
+			assert.False(t, valid)
+			assert.IsType(t, VerifyError{}, err)
+		}
+
+		kp.SetVariant(keypair.Ed25519ph)
+		kp.SetContext([]byte("other"))
+		valid, _ := NewStdVerifier(kp).Verify([]byte("hello world"), signature)
+		assert.False(t, valid)
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		kp := genEd25519KeyPair(t)
+		kp.SetVariant(keypair.Ed25519ctx)
+		_, err := NewStdSigner(kp).Sign([]byte("data"))
+		assert.Equal(t, SignError{Err: InvalidContextError{}}, err)
+		_, err = NewStdVerifier(kp).Verify([]byte("data"), make([]byte, ed25519.SignatureSize))
+		assert.Equal(t, VerifyError{Err: InvalidContextError{}}, err)
+
+		kp.SetContext([]byte(strings.Repeat("x", 256)))
+		_, err = NewStdSigner(kp).Sign([]byte("data"))
+		assert.Equal(t, SignError{Err: InvalidContextError{Size: 256}}, err)
+
+		signer := NewStreamSigner(&bytes.Buffer{}, kp)
+		_, _ = signer.Write([]byte("data"))
+		assert.Equal(t, SignError{Err: InvalidContextError{Size: 256}}, signer.Close())
+
+		verifier := NewStreamVerifier(bytes.NewReader(make([]byte, ed25519.SignatureSize)), kp)
+		_, _ = verifier.Write([]byte("data"))
+		assert.Equal(t, VerifyError{Err: InvalidContextError{Size: 256}}, verifier.Close())
+	})
+
+	t.Run("unsupported variant", func(t *testing.T) {
+		kp := genEd25519KeyPair(t)
+		kp.SetVariant("Ed448")
+		_, err := NewStdSigner(kp).Sign([]byte("data"))
+		assert.Equal(t, SignError{Err: UnsupportedVariantError{Variant: "Ed448"}}, err)
+	})
 }
 
 func TestStdSigner(t *testing.T) {
@@ -453,3 +584,35 @@ func TestStreamVerifier(t *testing.T) {
 		assert.True(t, ed25519.Verify(pub, data, signature))
 	})
 }
+
+func TestExternalSigner(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	data := []byte("hello world")
+
+	for _, variant := range []keypair.Ed25519Variant{keypair.Ed25519, keypair.Ed25519ph, keypair.Ed25519ctx} {
+		t.Run(string(variant), func(t *testing.T) {
+			signer := mock.NewSigner(key)
+			kp := keypair.NewEd25519KeyPair()
+			kp.SetVariant(variant)
+			kp.SetContext([]byte("dongle"))
+			require.NoError(t, kp.SetSigner(signer))
+
+			sign, err := NewStdSigner(kp).Sign(data)
+			require.NoError(t, err)
+			valid, err := NewStdVerifier(kp).Verify(data, sign)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			var out bytes.Buffer
+			ss := NewStreamSigner(&out, kp)
+			_, err = ss.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, ss.Close())
+			assert.Equal(t, sign, out.Bytes())
+
+			signs, _ := signer.Calls()
+			assert.Equal(t, 2, signs)
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package ed25519
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/errcode"
+)
 
 type SignError struct {
 	Err error
@@ -10,6 +15,16 @@ func (e SignError) Error() string {
 	return fmt.Sprintf("crypto/ed25519: failed to sign data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-ED25519-001.
+func (e SignError) Code() string {
+	return "DGL-ED25519-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ed25519", "Ed25519", "sign", errcode.FieldCause, e.Err)
+}
+
 type VerifyError struct {
 	Err error
 }
@@ -18,6 +33,16 @@ func (e VerifyError) Error() string {
 	return fmt.Sprintf("crypto/ed25519: failed to verify signature: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-ED25519-002.
+func (e VerifyError) Code() string {
+	return "DGL-ED25519-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e VerifyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ed25519", "Ed25519", "verify", errcode.FieldCause, e.Err)
+}
+
 type ReadError struct {
 	Err error
 }
@@ -25,3 +50,53 @@ type ReadError struct {
 func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/ed25519: failed to read data: %v", e.Err)
 }
+
+// Code returns the stable error code DGL-ED25519-003.
+func (e ReadError) Code() string {
+	return "DGL-ED25519-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ed25519", "Ed25519", "read", errcode.FieldCause, e.Err)
+}
+
+// InvalidContextError represents an error when the context is longer than 255
+// bytes, or empty while Ed25519ctx is selected.
+type InvalidContextError struct {
+	Size int
+}
+
+func (e InvalidContextError) Error() string {
+	return fmt.Sprintf("crypto/ed25519: invalid context size %d, must be 1-255 bytes for Ed25519ctx and at most 255 bytes otherwise", e.Size)
+}
+
+// Code returns the stable error code DGL-ED25519-004.
+func (e InvalidContextError) Code() string {
+	return "DGL-ED25519-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidContextError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ed25519", "Ed25519", "", "size", e.Size)
+}
+
+// UnsupportedVariantError represents an error when the key pair selects an
+// unknown signature variant.
+type UnsupportedVariantError struct {
+	Variant keypair.Ed25519Variant
+}
+
+func (e UnsupportedVariantError) Error() string {
+	return fmt.Sprintf("crypto/ed25519: unsupported variant %q", string(e.Variant))
+}
+
+// Code returns the stable error code DGL-ED25519-005.
+func (e UnsupportedVariantError) Code() string {
+	return "DGL-ED25519-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedVariantError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ed25519", "Ed25519", "", "variant", string(e.Variant))
+}
@@ -1,7 +1,6 @@
 package ed25519
 
 import (
-	"crypto/ed25519"
 	"io"
 
 	"github.com/dromara/dongle/crypto/keypair"
@@ -19,17 +18,12 @@ func NewStdSigner(kp *keypair.Ed25519KeyPair) *StdSigner {
 	s := &StdSigner{
 		keypair: *kp,
 	}
-	if len(kp.PrivateKey) == 0 {
-		s.Error = SignError{Err: keypair.EmptyPrivateKeyError{}}
-		return s
-	}
-
-	priKey, err := kp.ParsePrivateKey()
+	signer, err := kp.Signer()
 	if err != nil {
 		s.Error = SignError{Err: err}
 		return s
 	}
-	s.cache.priKey = priKey
+	s.cache.signer = signer
 
 	return s
 }
@@ -45,7 +39,10 @@ func (s *StdSigner) Sign(src []byte) (sign []byte, err error) {
 		return
 	}
 
-	sign = ed25519.Sign(s.cache.priKey, src)
+	sign, err = signVariant(s.cache.signer, &s.keypair, src)
+	if err != nil {
+		err = SignError{Err: err}
+	}
 	return
 }
 
@@ -64,17 +61,12 @@ func NewStreamSigner(w io.Writer, kp *keypair.Ed25519KeyPair) io.WriteCloser {
 		writer:  w,
 		keypair: *kp,
 	}
-	if len(kp.PrivateKey) == 0 {
-		s.Error = SignError{Err: keypair.EmptyPrivateKeyError{}}
-		return s
-	}
-
-	priKey, err := kp.ParsePrivateKey()
+	signer, err := kp.Signer()
 	if err != nil {
 		s.Error = SignError{Err: err}
 		return s
 	}
-	s.cache.priKey = priKey
+	s.cache.signer = signer
 
 	return s
 }
@@ -89,8 +81,11 @@ func (s *StreamSigner) sign(data []byte) (signature []byte, err error) {
 		return
 	}
 
-	// ED25519 signing does not require hashing as it handles hashing internally
-	signature = ed25519.Sign(s.cache.priKey, data)
+	// Plain ED25519 hashes the message internally, only Ed25519ph pre-hashes it
+	signature, err = signVariant(s.cache.signer, &s.keypair, data)
+	if err != nil {
+		err = SignError{Err: err}
+	}
 	return
 }
 
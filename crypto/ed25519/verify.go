@@ -1,7 +1,6 @@
 package ed25519
 
 import (
-	"crypto/ed25519"
 	"io"
 
 	"github.com/dromara/dongle/crypto/keypair"
@@ -49,8 +48,11 @@ func (v *StdVerifier) Verify(src, sign []byte) (valid bool, err error) {
 		return
 	}
 
-	// ED25519 verification does not require hashing as it handles hashing internally
-	valid = ed25519.Verify(v.cache.pubKey, src, sign)
+	// Plain ED25519 hashes the message internally, only Ed25519ph pre-hashes it
+	valid, err = verifyVariant(v.cache.pubKey, &v.keypair, src, sign)
+	if err != nil {
+		return false, VerifyError{Err: err}
+	}
 	if !valid {
 		v.Error = VerifyError{Err: nil}
 		return false, v.Error
@@ -105,7 +107,10 @@ func (v *StreamVerifier) verify(data, sign []byte) (valid bool, err error) {
 		return
 	}
 
-	valid = ed25519.Verify(v.cache.pubKey, data, sign)
+	valid, err = verifyVariant(v.cache.pubKey, &v.keypair, data, sign)
+	if err != nil {
+		return false, VerifyError{Err: err}
+	}
 	if !valid {
 		v.Error = VerifyError{Err: nil}
 		return false, v.Error
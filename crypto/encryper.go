@@ -6,15 +6,19 @@ import (
 	"io/fs"
 
 	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/pem"
 	"github.com/dromara/dongle/internal/utils"
 )
 
 // Encrypter defines a Encrypter struct.
 type Encrypter struct {
-	src    []byte
-	dst    []byte
-	reader io.Reader
-	Error  error
+	src        []byte
+	dst        []byte
+	reader     io.Reader
+	bufferSize int
+	progress   func(done, total int64)
+	Error      error
 }
 
 // NewEncrypter returns a new Encrypter instance.
@@ -24,22 +28,55 @@ func NewEncrypter() Encrypter {
 
 // FromString encrypts from string.
 func (e Encrypter) FromString(s string) Encrypter {
+	utils.AuditShared()
 	e.src = utils.String2Bytes(s)
 	return e
 }
 
 // FromBytes encrypts from byte slice.
 func (e Encrypter) FromBytes(b []byte) Encrypter {
+	utils.AuditShared()
 	e.src = b
 	return e
 }
 
 // FromFile encrypts from file.
 func (e Encrypter) FromFile(f fs.File) Encrypter {
+	utils.AuditShared()
 	e.reader = f
 	return e
 }
 
+// FromFS encrypts from the named file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (e Encrypter) FromFS(fsys fs.FS, name string) Encrypter {
+	utils.AuditShared()
+	if e.Error != nil {
+		return e
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		e.Error = err
+		return e
+	}
+	return e.FromFile(f)
+}
+
+// WithProgress sets a callback receiving the number of bytes encrypted so far
+// and the total size of the file, or -1 when unknown, while encrypting from a file.
+func (e Encrypter) WithProgress(fn func(done, total int64)) Encrypter {
+	e.progress = fn
+	return e
+}
+
+// WithBufferSize sets the read buffer size used when encrypting from a file,
+// BufferSize by default.
+func (e Encrypter) WithBufferSize(n int) Encrypter {
+	e.bufferSize = n
+	return e
+}
+
 // ToRawString outputs as raw string.
 func (e Encrypter) ToRawString() string {
 	return utils.Bytes2String(e.dst)
@@ -53,6 +90,11 @@ func (e Encrypter) ToRawBytes() []byte {
 	return e.dst
 }
 
+// To outputs as a Result exposing the ciphertext in several encodings.
+func (e Encrypter) To() coding.Result {
+	return coding.NewResult(e.ToRawBytes(), e.Error)
+}
+
 // ToBase64String outputs as base64 string.
 func (e Encrypter) ToBase64String() string {
 	return coding.NewEncoder().FromBytes(e.dst).ByBase64().ToString()
@@ -73,7 +115,25 @@ func (e Encrypter) ToHexBytes() []byte {
 	return coding.NewEncoder().FromBytes(e.dst).ByHex().ToBytes()
 }
 
+// ToHexFormat outputs as hex string in the given format.
+func (e Encrypter) ToHexFormat(f hex.Format) string {
+	return f.Encode(e.dst)
+}
+
+// ToPemString outputs as PEM armored string with the given block type,
+// "DONGLE MESSAGE" when empty. It returns an empty string for an invalid type.
+func (e Encrypter) ToPemString(blockType string) string {
+	return utils.Bytes2String(e.ToPemBytes(blockType))
+}
+
+// ToPemBytes outputs as PEM armored byte slice with the given block type,
+// "DONGLE MESSAGE" when empty. It returns an empty slice for an invalid type.
+func (e Encrypter) ToPemBytes(blockType string) []byte {
+	return armor(e.dst, blockType, pem.MessageType)
+}
+
 func (e Encrypter) stream(fn func(io.Writer) io.WriteCloser) ([]byte, error) {
+	defer utils.CloseFS(e.reader)
 	var buf bytes.Buffer
 	encrypter := fn(&buf)
 
@@ -81,7 +141,7 @@ func (e Encrypter) stream(fn func(io.Writer) io.WriteCloser) ([]byte, error) {
 	if seeker, ok := e.reader.(io.Seeker); ok {
 		seeker.Seek(0, io.SeekStart)
 	}
-	if _, err := io.CopyBuffer(encrypter, e.reader, make([]byte, BufferSize)); err != nil && err != io.EOF {
+	if _, err := io.CopyBuffer(encrypter, utils.ProgressReader(e.reader, e.progress), utils.Buffer(e.bufferSize, BufferSize)); err != nil && err != io.EOF {
 		encrypter.Close()
 		return []byte{}, err
 	}
@@ -93,3 +153,18 @@ func (e Encrypter) stream(fn func(io.Writer) io.WriteCloser) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// armor PEM encodes data, falling back to defaultType when blockType is empty.
+func armor(data []byte, blockType, defaultType string) []byte {
+	if len(data) == 0 {
+		return []byte{}
+	}
+	if blockType == "" {
+		blockType = defaultType
+	}
+	out, err := pem.Encode(&pem.Block{Type: blockType, Bytes: data}, 0)
+	if err != nil {
+		return []byte{}
+	}
+	return out
+}
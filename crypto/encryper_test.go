@@ -1,9 +1,14 @@
 package crypto
 
 import (
+	"bytes"
 	"io"
+	"io/fs"
 	"testing"
+	"testing/fstest"
 
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/deprecation"
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -339,6 +344,47 @@ func TestEncrypter_ToHexBytes(t *testing.T) {
 	})
 }
 
+func TestEncrypter_ToPemString(t *testing.T) {
+	t.Run("to pem string", func(t *testing.T) {
+		encrypter := NewEncrypter()
+		encrypter.dst = []byte("hello world")
+		result := encrypter.ToPemString("")
+		assert.Equal(t, "-----BEGIN DONGLE MESSAGE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE MESSAGE-----\n", result)
+	})
+
+	t.Run("to pem string custom type", func(t *testing.T) {
+		encrypter := NewEncrypter()
+		encrypter.dst = []byte("hello world")
+		result := encrypter.ToPemString("AES MESSAGE")
+		assert.Equal(t, "-----BEGIN AES MESSAGE-----\naGVsbG8gd29ybGQ=\n-----END AES MESSAGE-----\n", result)
+	})
+
+	t.Run("to pem string empty", func(t *testing.T) {
+		encrypter := NewEncrypter()
+		assert.Equal(t, "", encrypter.ToPemString(""))
+	})
+
+	t.Run("to pem string invalid type", func(t *testing.T) {
+		encrypter := NewEncrypter()
+		encrypter.dst = []byte("hello world")
+		assert.Equal(t, "", encrypter.ToPemString("-----"))
+	})
+}
+
+func TestEncrypter_ToPemBytes(t *testing.T) {
+	t.Run("to pem bytes", func(t *testing.T) {
+		encrypter := NewEncrypter()
+		encrypter.dst = []byte("hello world")
+		result := encrypter.ToPemBytes("")
+		assert.Equal(t, []byte("-----BEGIN DONGLE MESSAGE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE MESSAGE-----\n"), result)
+	})
+
+	t.Run("to pem bytes empty", func(t *testing.T) {
+		encrypter := NewEncrypter()
+		assert.Equal(t, []byte{}, encrypter.ToPemBytes(""))
+	})
+}
+
 func TestEncrypter_Stream(t *testing.T) {
 	t.Run("stream with success", func(t *testing.T) {
 		encrypter := NewEncrypter()
@@ -428,3 +474,106 @@ func TestEncrypter_Stream(t *testing.T) {
 		assert.Equal(t, []byte{}, result)
 	})
 }
+
+func TestEncrypter_WithProgress(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world"), 100)
+	file := mock.NewFile(data, "test.txt")
+
+	var calls int
+	var done, total int64
+	encrypter := NewEncrypter().FromFile(file).WithBufferSize(256).WithProgress(func(d, t int64) {
+		calls++
+		done, total = d, t
+	}).ByAes(newAesCipher())
+	assert.Nil(t, encrypter.Error)
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, int64(len(data)), done)
+	assert.Equal(t, int64(len(data)), total)
+	assert.NotEmpty(t, encrypter.ToRawBytes())
+
+	// A single read keeps the output identical to standard encryption
+	file = mock.NewFile(data, "test.txt")
+	encrypter = NewEncrypter().FromFile(file).WithBufferSize(len(data)).ByAes(newAesCipher())
+	assert.Equal(t, NewEncrypter().FromBytes(data).ByAes(newAesCipher()).ToRawBytes(), encrypter.ToRawBytes())
+}
+
+func TestEncrypter_FromFS(t *testing.T) {
+	fsys := fstest.MapFS{"hello.txt": {Data: []byte("hello world")}}
+
+	encrypter := NewEncrypter().FromFS(fsys, "hello.txt").ByAes(newAesCipher())
+	assert.Nil(t, encrypter.Error)
+	assert.Equal(t, NewEncrypter().FromString("hello world").ByAes(newAesCipher()).ToRawBytes(), encrypter.ToRawBytes())
+
+	encrypter = NewEncrypter().FromFS(fsys, "missing.txt").ByAes(newAesCipher())
+	assert.Error(t, encrypter.Error)
+	assert.Empty(t, encrypter.ToRawBytes())
+}
+
+// closeCountFS counts how many times files are opened from it and closed.
+type closeCountFS struct {
+	fstest.MapFS
+	opens  int
+	closes int
+}
+
+type closeCountFile struct {
+	fs.File
+	fsys *closeCountFS
+}
+
+func (c *closeCountFS) Open(name string) (fs.File, error) {
+	f, err := c.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	c.opens++
+	return &closeCountFile{File: f, fsys: c}, nil
+}
+
+func (f *closeCountFile) Close() error {
+	f.fsys.closes++
+	return f.File.Close()
+}
+
+func TestEncrypter_FromFS_Close(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 3*BufferSize)
+
+	t.Run("closed after success", func(t *testing.T) {
+		fsys := &closeCountFS{MapFS: fstest.MapFS{"a.txt": {Data: data}}}
+		encrypter := NewEncrypter().FromFS(fsys, "a.txt").ByAes(newAesCipher())
+		assert.Nil(t, encrypter.Error)
+		assert.Equal(t, 1, fsys.opens)
+		assert.Equal(t, 1, fsys.closes)
+	})
+
+	t.Run("closed after failing halfway", func(t *testing.T) {
+		fsys := &closeCountFS{MapFS: fstest.MapFS{"a.txt": {Data: data}}}
+		c := newAesCipher()
+		c.SetKey([]byte("short"))
+		encrypter := NewEncrypter().FromFS(fsys, "a.txt").ByAes(c)
+		assert.Error(t, encrypter.Error)
+		assert.Equal(t, fsys.opens, fsys.closes)
+	})
+
+	t.Run("never opened when the chain fails first", func(t *testing.T) {
+		t.Cleanup(deprecation.Reset)
+		deprecation.SetStrict(true)
+		c := cipher.NewAesCipher(cipher.ECB)
+		c.SetKey(key16)
+		c.SetPadding(cipher.PKCS7)
+		fsys := &closeCountFS{MapFS: fstest.MapFS{"a.txt": {Data: data}}}
+		encrypter := NewEncrypter().FromFS(fsys, "a.txt").ByAes(c)
+		assert.Error(t, encrypter.Error)
+		assert.Zero(t, fsys.opens)
+		assert.Zero(t, fsys.closes)
+	})
+
+	t.Run("closed after decrypting past the limit", func(t *testing.T) {
+		ciphertext := NewEncrypter().FromBytes(data).ByAes(newAesCipher()).ToRawBytes()
+		fsys := &closeCountFS{MapFS: fstest.MapFS{"a.bin": {Data: ciphertext}}}
+		decrypter := NewDecrypter().FromRawFS(fsys, "a.bin").WithMaxSize(10).ByAes(newAesCipher())
+		assert.Equal(t, SizeLimitError{Limit: 10}, decrypter.Error)
+		assert.Equal(t, 1, fsys.opens)
+		assert.Equal(t, 1, fsys.closes)
+	})
+}
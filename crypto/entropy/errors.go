@@ -0,0 +1,94 @@
+package entropy
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidConfigError represents an error when a health test parameter is out of range.
+type InvalidConfigError struct {
+	Field string // Name of the invalid Config field
+}
+
+// Error returns a formatted error message describing the invalid parameter.
+func (e InvalidConfigError) Error() string {
+	return fmt.Sprintf("crypto/entropy: invalid config field %s", e.Field)
+}
+
+// Code returns the stable error code DGL-ENTROPY-001.
+func (e InvalidConfigError) Code() string {
+	return "DGL-ENTROPY-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidConfigError) Fields() map[string]any {
+	return errcode.NewFields("crypto/entropy", "", "", "field", e.Field)
+}
+
+// RepetitionCountError represents a failure of the repetition count test.
+// The source produced the same sample too many times in a row.
+type RepetitionCountError struct {
+	Sample byte // The repeated sample
+	Count  int  // Number of consecutive repetitions observed
+	Cutoff int  // Repetition count cutoff
+}
+
+// Error returns a formatted error message describing the repetition count failure.
+func (e RepetitionCountError) Error() string {
+	return fmt.Sprintf("crypto/entropy: repetition count test failed, sample 0x%02x repeated %d times (cutoff %d)", e.Sample, e.Count, e.Cutoff)
+}
+
+// Code returns the stable error code DGL-ENTROPY-002.
+func (e RepetitionCountError) Code() string {
+	return "DGL-ENTROPY-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RepetitionCountError) Fields() map[string]any {
+	return errcode.NewFields("crypto/entropy", "", "", "count", e.Count, "cutoff", e.Cutoff)
+}
+
+// AdaptiveProportionError represents a failure of the adaptive proportion test.
+// A single sample value occurred too often within one window.
+type AdaptiveProportionError struct {
+	Sample byte // The over-represented sample
+	Count  int  // Occurrences observed in the window
+	Cutoff int  // Adaptive proportion cutoff
+	Window int  // Window size in samples
+}
+
+// Error returns a formatted error message describing the adaptive proportion failure.
+func (e AdaptiveProportionError) Error() string {
+	return fmt.Sprintf("crypto/entropy: adaptive proportion test failed, sample 0x%02x occurred %d times in a window of %d (cutoff %d)", e.Sample, e.Count, e.Window, e.Cutoff)
+}
+
+// Code returns the stable error code DGL-ENTROPY-003.
+func (e AdaptiveProportionError) Code() string {
+	return "DGL-ENTROPY-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AdaptiveProportionError) Fields() map[string]any {
+	return errcode.NewFields("crypto/entropy", "", "", "count", e.Count, "cutoff", e.Cutoff, "window", e.Window)
+}
+
+// ReadError represents an error when reading from the underlying source fails.
+type ReadError struct {
+	Err error // Underlying error from the source
+}
+
+// Error returns a formatted error message describing the read failure.
+func (e ReadError) Error() string {
+	return fmt.Sprintf("crypto/entropy: failed to read from source: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ENTROPY-004.
+func (e ReadError) Code() string {
+	return "DGL-ENTROPY-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/entropy", "", "read", errcode.FieldCause, e.Err)
+}
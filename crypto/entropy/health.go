@@ -0,0 +1,155 @@
+// Package entropy implements continuous health tests for random byte sources.
+// It follows the repetition count and adaptive proportion tests of NIST SP 800-90B
+// (section 4.4) and provides a Reader wrapper that fails closed, so deployments in
+// regulated environments can refuse to use a random source once it misbehaves.
+// Install makes such a source, for example a hardware TRNG, the randomness
+// source of every dongle package.
+package entropy
+
+import "math"
+
+// Config describes the parameters of the health tests.
+// Each byte read from the source is treated as one sample.
+type Config struct {
+	// MinEntropy is the claimed min-entropy per sample in bits, in (0, 8].
+	// Lower claims produce more tolerant cutoffs.
+	MinEntropy float64
+	// WindowSize is the adaptive proportion test window in samples.
+	WindowSize int
+	// StartupSamples is the number of samples tested before the source is first used.
+	StartupSamples int
+	// FalsePositive is the log2 of the accepted false positive probability per test,
+	// SP 800-90B recommends -20.
+	FalsePositive float64
+}
+
+// DefaultConfig returns a conservative configuration for crypto/rand:
+// a claimed min-entropy of 2 bits per byte, a 512 sample window and
+// 1024 startup samples with a false positive probability of 2^-20.
+func DefaultConfig() Config {
+	return Config{
+		MinEntropy:     2,
+		WindowSize:     512,
+		StartupSamples: 1024,
+		FalsePositive:  -20,
+	}
+}
+
+// validate reports an InvalidConfigError for unusable parameters.
+func (c Config) validate() error {
+	if !(c.MinEntropy > 0 && c.MinEntropy <= 8) {
+		return InvalidConfigError{Field: "MinEntropy"}
+	}
+	if c.WindowSize < 2 {
+		return InvalidConfigError{Field: "WindowSize"}
+	}
+	if c.StartupSamples < 0 {
+		return InvalidConfigError{Field: "StartupSamples"}
+	}
+	if !(c.FalsePositive < 0) {
+		return InvalidConfigError{Field: "FalsePositive"}
+	}
+	return nil
+}
+
+// HealthTest runs the repetition count and adaptive proportion tests over a
+// stream of samples. It is not safe for concurrent use.
+type HealthTest struct {
+	rctCutoff int // Repetition count test cutoff
+	aptCutoff int // Adaptive proportion test cutoff
+	window    int // Adaptive proportion test window size
+
+	rctSample byte // Sample currently repeating
+	rctCount  int  // Number of consecutive repetitions of rctSample
+	aptSample byte // Reference sample of the current window
+	aptCount  int  // Occurrences of aptSample in the current window
+	aptSeen   int  // Samples seen in the current window
+	started   bool // Whether any sample has been fed yet
+
+	Error error // Error field for storing configuration errors
+}
+
+// NewHealthTest returns a new HealthTest with cutoffs derived from c.
+func NewHealthTest(c Config) *HealthTest {
+	h := &HealthTest{}
+	if err := c.validate(); err != nil {
+		h.Error = err
+		return h
+	}
+	h.rctCutoff, h.aptCutoff = Cutoffs(c)
+	h.window = c.WindowSize
+	return h
+}
+
+// Cutoffs returns the repetition count and adaptive proportion test cutoffs for c,
+// computed as in SP 800-90B sections 4.4.1 and 4.4.2.
+func Cutoffs(c Config) (rct, apt int) {
+	rct = 1 + int(math.Ceil(-c.FalsePositive/c.MinEntropy))
+	apt = 1 + critBinom(c.WindowSize, math.Exp2(-c.MinEntropy), 1-math.Exp2(c.FalsePositive))
+	if apt > c.WindowSize {
+		apt = c.WindowSize
+	}
+	return rct, apt
+}
+
+// Feed runs both tests on a single sample.
+func (h *HealthTest) Feed(sample byte) error {
+	if h.Error != nil {
+		return h.Error
+	}
+	if !h.started || sample != h.rctSample {
+		h.rctSample, h.rctCount = sample, 1
+	} else {
+		h.rctCount++
+		if h.rctCount >= h.rctCutoff {
+			return RepetitionCountError{Sample: sample, Count: h.rctCount, Cutoff: h.rctCutoff}
+		}
+	}
+	h.started = true
+
+	if h.aptSeen == 0 || h.aptSeen == h.window {
+		h.aptSample, h.aptCount, h.aptSeen = sample, 1, 1
+		return nil
+	}
+	h.aptSeen++
+	if sample == h.aptSample {
+		h.aptCount++
+		if h.aptCount >= h.aptCutoff {
+			return AdaptiveProportionError{Sample: sample, Count: h.aptCount, Cutoff: h.aptCutoff, Window: h.window}
+		}
+	}
+	return nil
+}
+
+// Check runs both tests on every sample of p, stopping at the first failure.
+func (h *HealthTest) Check(p []byte) error {
+	for _, b := range p {
+		if err := h.Feed(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset clears the test state so the next sample starts fresh windows.
+func (h *HealthTest) Reset() {
+	h.rctSample, h.rctCount = 0, 0
+	h.aptSample, h.aptCount, h.aptSeen = 0, 0, 0
+	h.started = false
+}
+
+// critBinom returns the smallest k such that the binomial CDF of (n, p) at k is at least q.
+func critBinom(n int, p, q float64) int {
+	lp, lq := math.Log(p), math.Log1p(-p)
+	ln, _ := math.Lgamma(float64(n + 1))
+	cdf := 0.0
+	for k := 0; k <= n; k++ {
+		lk, _ := math.Lgamma(float64(k + 1))
+		lnk, _ := math.Lgamma(float64(n - k + 1))
+		cdf += math.Exp(ln - lk - lnk + float64(k)*lp + float64(n-k)*lq)
+		if cdf >= q {
+			return k
+		}
+	}
+	return n
+}
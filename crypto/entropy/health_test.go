@@ -0,0 +1,110 @@
+package entropy
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCutoffs(t *testing.T) {
+	// Reference values from SP 800-90B tables for alpha = 2^-20.
+	for _, tc := range []struct {
+		h      float64
+		window int
+		rct    int
+		apt    int
+	}{
+		{0.5, 512, 41, 410},
+		{1, 512, 21, 311},
+		{2, 512, 11, 177},
+		{4, 512, 6, 62},
+		{8, 512, 4, 13},
+		{1, 1024, 21, 589},
+	} {
+		rct, apt := Cutoffs(Config{MinEntropy: tc.h, WindowSize: tc.window, FalsePositive: -20})
+		assert.Equal(t, tc.rct, rct, "rct for H=%v", tc.h)
+		assert.Equal(t, tc.apt, apt, "apt for H=%v W=%d", tc.h, tc.window)
+	}
+
+	t.Run("capped at window", func(t *testing.T) {
+		_, apt := Cutoffs(Config{MinEntropy: 0.01, WindowSize: 8, FalsePositive: -20})
+		assert.Equal(t, 8, apt)
+	})
+}
+
+func TestNewHealthTest(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		h := NewHealthTest(DefaultConfig())
+		assert.Nil(t, h.Error)
+		assert.Equal(t, 11, h.rctCutoff)
+		assert.Equal(t, 177, h.aptCutoff)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		for field, c := range map[string]Config{
+			"MinEntropy":     {MinEntropy: 9, WindowSize: 512, FalsePositive: -20},
+			"WindowSize":     {MinEntropy: 2, WindowSize: 1, FalsePositive: -20},
+			"StartupSamples": {MinEntropy: 2, WindowSize: 512, StartupSamples: -1, FalsePositive: -20},
+			"FalsePositive":  {MinEntropy: 2, WindowSize: 512, FalsePositive: 0},
+		} {
+			h := NewHealthTest(c)
+			assert.Equal(t, InvalidConfigError{Field: field}, h.Error)
+			assert.Equal(t, h.Error, h.Feed(0))
+		}
+		assert.Equal(t, "crypto/entropy: invalid config field MinEntropy", InvalidConfigError{Field: "MinEntropy"}.Error())
+	})
+}
+
+func TestHealthTest_RepetitionCount(t *testing.T) {
+	h := NewHealthTest(DefaultConfig())
+	stuck := make([]byte, 10)
+	assert.NoError(t, h.Check(stuck))
+
+	err := h.Feed(0)
+	assert.Equal(t, RepetitionCountError{Sample: 0, Count: 11, Cutoff: 11}, err)
+	assert.Equal(t, "crypto/entropy: repetition count test failed, sample 0x00 repeated 11 times (cutoff 11)", err.Error())
+
+	h.Reset()
+	assert.NoError(t, h.Check(stuck))
+	assert.NoError(t, h.Feed(1))
+	assert.NoError(t, h.Check(stuck))
+}
+
+func TestHealthTest_AdaptiveProportion(t *testing.T) {
+	c := DefaultConfig()
+	h := NewHealthTest(c)
+
+	// Alternate the biased sample with distinct values so the repetition count never trips.
+	var err error
+	for i := 0; err == nil && i < c.WindowSize; i++ {
+		sample := byte(0xaa)
+		if i%2 == 1 {
+			sample = byte(i % 97)
+		}
+		err = h.Feed(sample)
+	}
+	assert.Equal(t, AdaptiveProportionError{Sample: 0xaa, Count: 177, Cutoff: 177, Window: 512}, err)
+	assert.Equal(t, "crypto/entropy: adaptive proportion test failed, sample 0xaa occurred 177 times in a window of 512 (cutoff 177)", err.Error())
+
+	t.Run("window restarts", func(t *testing.T) {
+		h := NewHealthTest(Config{MinEntropy: 2, WindowSize: 8, FalsePositive: -20})
+		// A biased sample below the cutoff in every window never fails.
+		for i := 0; i < 100; i++ {
+			sample := byte(i)
+			if i%8 < 2 {
+				sample = 0x55
+			}
+			require.NoError(t, h.Feed(sample))
+		}
+	})
+}
+
+func TestHealthTest_Random(t *testing.T) {
+	h := NewHealthTest(DefaultConfig())
+	buf := make([]byte, 1<<16)
+	_, err := rand.Read(buf)
+	require.NoError(t, err)
+	assert.NoError(t, h.Check(buf))
+}
@@ -0,0 +1,83 @@
+package entropy
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Reader wraps a random source and runs the health tests on every byte it returns.
+// Once a test fails the Reader fails closed: the offending read is wiped and every
+// later read returns the same error. It is safe for concurrent use.
+type Reader struct {
+	mu   sync.Mutex
+	src  io.Reader
+	test *HealthTest
+	err  error // Sticky health test or source failure
+
+	Error error // Error field for storing configuration and startup errors
+}
+
+// NewReader returns a new Reader over src configured by c.
+// The startup test reads c.StartupSamples bytes from src and discards them;
+// its failure is stored in the Error field and makes every read fail.
+func NewReader(src io.Reader, c Config) *Reader {
+	r := &Reader{src: src, test: NewHealthTest(c)}
+	if r.test.Error != nil {
+		r.Error = r.test.Error
+		return r
+	}
+	if c.StartupSamples > 0 {
+		buf := make([]byte, c.StartupSamples)
+		if _, err := io.ReadFull(src, buf); err != nil {
+			r.Error = ReadError{Err: err}
+			return r
+		}
+		r.Error = r.test.Check(buf)
+		utils.SecureWipe(buf)
+	}
+	return r
+}
+
+// Read fills p from the source after running the health tests over it.
+// On failure p is zeroed and the error is returned by all subsequent reads.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	if r.Error != nil {
+		return 0, r.Error
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err = r.src.Read(p)
+	if testErr := r.test.Check(p[:n]); testErr != nil {
+		utils.SecureWipe(p[:n])
+		r.err = testErr
+		return 0, testErr
+	}
+	if err != nil && err != io.EOF {
+		r.err = ReadError{Err: err}
+		return n, r.err
+	}
+	return n, err
+}
+
+// Err returns the failure that closed the Reader, or nil while it is healthy.
+func (r *Reader) Err() error {
+	if r.Error != nil {
+		return r.Error
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// CheckStartup runs the startup health test against crypto/rand with the
+// default configuration, suitable for calling once during program initialization.
+func CheckStartup() error {
+	return NewReader(rand.Reader, DefaultConfig()).Error
+}
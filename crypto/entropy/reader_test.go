@@ -0,0 +1,124 @@
+package entropy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stuckAfter returns n random bytes followed by an endless run of zeros.
+type stuckAfter struct {
+	n int
+}
+
+func (s *stuckAfter) Read(p []byte) (int, error) {
+	for i := range p {
+		if s.n > 0 {
+			rand.Read(p[i : i+1])
+			p[i] |= 1
+			s.n--
+		} else {
+			p[i] = 0
+		}
+	}
+	return len(p), nil
+}
+
+func TestReader(t *testing.T) {
+	t.Run("healthy source", func(t *testing.T) {
+		r := NewReader(rand.Reader, DefaultConfig())
+		require.NoError(t, r.Error)
+		buf := make([]byte, 4096)
+		n, err := io.ReadFull(r, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 4096, n)
+		assert.NoError(t, r.Err())
+	})
+
+	t.Run("startup failure", func(t *testing.T) {
+		r := NewReader(bytes.NewReader(make([]byte, 2048)), DefaultConfig())
+		assert.IsType(t, RepetitionCountError{}, r.Error)
+		assert.Equal(t, r.Error, r.Err())
+		n, err := r.Read(make([]byte, 8))
+		assert.Equal(t, 0, n)
+		assert.Equal(t, r.Error, err)
+	})
+
+	t.Run("startup read error", func(t *testing.T) {
+		r := NewReader(mock.NewErrorFile(errors.New("read error")), DefaultConfig())
+		assert.IsType(t, ReadError{}, r.Error)
+		assert.Equal(t, "crypto/entropy: failed to read from source: read error", r.Error.Error())
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		r := NewReader(rand.Reader, Config{})
+		assert.Equal(t, InvalidConfigError{Field: "MinEntropy"}, r.Error)
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		c := DefaultConfig()
+		r := NewReader(&stuckAfter{n: c.StartupSamples + 100}, c)
+		require.NoError(t, r.Error)
+
+		buf := make([]byte, 64)
+		_, err := r.Read(buf)
+		require.NoError(t, err)
+
+		buf = bytes.Repeat([]byte{0xff}, 64)
+		n, err := r.Read(buf)
+		assert.Equal(t, 0, n)
+		assert.IsType(t, RepetitionCountError{}, err)
+		assert.Equal(t, make([]byte, 64), buf)
+
+		// The failure is sticky even if the source would recover.
+		_, err2 := r.Read(buf)
+		assert.Equal(t, err, err2)
+		assert.Equal(t, err, r.Err())
+	})
+
+	t.Run("source error", func(t *testing.T) {
+		r := NewReader(mock.NewErrorFile(errors.New("read error")), Config{MinEntropy: 2, WindowSize: 512, FalsePositive: -20})
+		require.NoError(t, r.Error)
+		_, err := r.Read(make([]byte, 8))
+		assert.IsType(t, ReadError{}, err)
+		_, err = r.Read(make([]byte, 8))
+		assert.IsType(t, ReadError{}, err)
+	})
+
+	t.Run("eof", func(t *testing.T) {
+		r := NewReader(bytes.NewReader([]byte{1, 2, 3}), Config{MinEntropy: 2, WindowSize: 512, FalsePositive: -20})
+		buf := make([]byte, 8)
+		n, err := r.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		_, err = r.Read(buf)
+		assert.Equal(t, io.EOF, err)
+		assert.NoError(t, r.Err())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		r := NewReader(rand.Reader, DefaultConfig())
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				buf := make([]byte, 256)
+				_, err := io.ReadFull(r, buf)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestCheckStartup(t *testing.T) {
+	assert.NoError(t, CheckStartup())
+}
@@ -0,0 +1,68 @@
+package entropy
+
+import (
+	"crypto/subtle"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Install makes r the randomness source of every dongle package, used for key
+// generation, nonces, IVs, salts and random padding, and returns a function
+// restoring the previous source. A nil r restores crypto/rand.Reader.
+//
+// Air-gapped signing appliances use it to draw from a hardware TRNG, guarded
+// by the health tests so that a failing device stops key generation instead of
+// producing weak keys:
+//
+//	dev, err := os.Open("/dev/hwrng")
+//	...
+//	trng := entropy.NewReader(dev, entropy.DefaultConfig())
+//	restore, err := entropy.Install(entropy.Combine(trng, rand.Reader))
+//
+// Combining with crypto/rand keeps keys safe should either source be flawed.
+// A user-supplied pool, such as a file of bytes gathered offline, is consumed
+// as it is read: once exhausted, every operation needing randomness fails.
+//
+// Install returns the Error of a *Reader whose startup test failed, without
+// installing it. From Go 1.26, the standard library ignores custom sources for
+// RSA, ECDSA and Ed25519 key generation and RSA encryption unless the program
+// runs with GODEBUG=cryptocustomrand=1; without it those operations keep using
+// the operating system source.
+func Install(r io.Reader) (restore func(), err error) {
+	if reader, ok := r.(*Reader); ok && reader.Error != nil {
+		return func() {}, reader.Error
+	}
+	return utils.SetRand(r), nil
+}
+
+// Combine returns a reader filling every read with the XOR of equally long
+// reads from all sources, so that its output is unpredictable as long as one
+// source is. A read fails as soon as one source fails or runs out; with no
+// sources, reads return io.EOF.
+func Combine(sources ...io.Reader) io.Reader {
+	return combined(sources)
+}
+
+// combined XORs the output of several sources.
+type combined []io.Reader
+
+func (c combined) Read(p []byte) (int, error) {
+	if len(c) == 0 {
+		return 0, io.EOF
+	}
+	if _, err := io.ReadFull(c[0], p); err != nil {
+		utils.SecureWipe(p)
+		return 0, err
+	}
+	buf := make([]byte, len(p))
+	defer utils.SecureWipe(buf)
+	for _, src := range c[1:] {
+		if _, err := io.ReadFull(src, buf); err != nil {
+			utils.SecureWipe(p)
+			return 0, err
+		}
+		subtle.XORBytes(p, p, buf)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,123 @@
+package entropy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstall(t *testing.T) {
+	genSm2 := func() (*keypair.Sm2KeyPair, error) {
+		kp := keypair.NewSm2KeyPair()
+		return kp, kp.GenKeyPair()
+	}
+
+	t.Run("key generation", func(t *testing.T) {
+		restore, err := Install(mock.NewRand([]byte("seed")))
+		require.NoError(t, err)
+		first, err := genSm2()
+		require.NoError(t, err)
+		restore()
+
+		restore, err = Install(mock.NewRand([]byte("seed")))
+		require.NoError(t, err)
+		second, err := genSm2()
+		require.NoError(t, err)
+		restore()
+		assert.Equal(t, first.PrivateKey, second.PrivateKey)
+
+		third, err := genSm2()
+		require.NoError(t, err)
+		assert.NotEqual(t, first.PrivateKey, third.PrivateKey)
+	})
+
+	t.Run("health tested source", func(t *testing.T) {
+		r := NewReader(rand.Reader, DefaultConfig())
+		restore, err := Install(r)
+		require.NoError(t, err)
+		defer restore()
+		buf := make([]byte, 32)
+		_, err = io.ReadFull(utils.Rand(), buf)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failing source", func(t *testing.T) {
+		c := DefaultConfig()
+		restore, err := Install(NewReader(&stuckAfter{n: c.StartupSamples}, c))
+		require.NoError(t, err)
+		defer restore()
+		_, err = genSm2()
+		assert.IsType(t, RepetitionCountError{}, err)
+	})
+
+	t.Run("startup failure", func(t *testing.T) {
+		r := NewReader(bytes.NewReader(make([]byte, 2048)), DefaultConfig())
+		restore, err := Install(r)
+		assert.Equal(t, r.Error, err)
+		restore()
+		_, err = genSm2()
+		assert.NoError(t, err)
+	})
+
+	t.Run("exhausted pool", func(t *testing.T) {
+		restore, err := Install(bytes.NewReader(make([]byte, 8)))
+		require.NoError(t, err)
+		defer restore()
+		_, err = genSm2()
+		assert.Error(t, err)
+	})
+}
+
+func TestCombine(t *testing.T) {
+	a, b := mock.NewRand([]byte("a")), mock.NewRand([]byte("b"))
+	want := make([]byte, 64)
+	_, _ = io.ReadFull(mock.NewRand([]byte("a")), want)
+	other := make([]byte, 64)
+	_, _ = io.ReadFull(mock.NewRand([]byte("b")), other)
+	for i := range want {
+		want[i] ^= other[i]
+	}
+
+	got := make([]byte, 64)
+	n, err := Combine(a, b).Read(got)
+	require.NoError(t, err)
+	assert.Equal(t, 64, n)
+	assert.Equal(t, want, got)
+
+	t.Run("single source", func(t *testing.T) {
+		got := make([]byte, 16)
+		_, err := Combine(bytes.NewReader(bytes.Repeat([]byte{7}, 16))).Read(got)
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte{7}, 16), got)
+	})
+
+	t.Run("failing source", func(t *testing.T) {
+		readErr := errors.New("read error")
+		for _, c := range []io.Reader{
+			Combine(mock.NewErrorFile(readErr), rand.Reader),
+			Combine(rand.Reader, mock.NewErrorFile(readErr)),
+		} {
+			buf := make([]byte, 16)
+			n, err := c.Read(buf)
+			assert.Equal(t, 0, n)
+			assert.Equal(t, readErr, err)
+			assert.Equal(t, make([]byte, 16), buf)
+		}
+
+		_, err := Combine(rand.Reader, bytes.NewReader(make([]byte, 4))).Read(make([]byte, 16))
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		_, err := Combine().Read(make([]byte, 16))
+		assert.Equal(t, io.EOF, err)
+	})
+}
@@ -0,0 +1,122 @@
+package crypto
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// SizeLimitError represents an error when the decrypted output exceeds the
+// maximum size set by WithMaxSize.
+type SizeLimitError struct {
+	Limit int64 // The maximum output size in bytes
+}
+
+// Error returns a formatted error message describing the exceeded limit.
+func (e SizeLimitError) Error() string {
+	return fmt.Sprintf("crypto: decrypted output exceeds the limit of %d bytes", e.Limit)
+}
+
+// Code returns the stable error code DGL-CRYPTO-001.
+func (e SizeLimitError) Code() string {
+	return "DGL-CRYPTO-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SizeLimitError) Fields() map[string]any {
+	return errcode.NewFields("crypto", "", "decrypt", "limit", e.Limit)
+}
+
+// OrderError represents an error when a step of a Protector or Unprotector
+// chain is called before the step it depends on, such as decrypting a
+// protected message before verifying its signature.
+type OrderError struct {
+	Step   string // The step that was called
+	Before string // The step that must come first
+}
+
+// Error returns a formatted error message naming both steps.
+func (e OrderError) Error() string {
+	return fmt.Sprintf("crypto: %s must be called before %s", e.Before, e.Step)
+}
+
+// Code returns the stable error code DGL-CRYPTO-002.
+func (e OrderError) Code() string {
+	return "DGL-CRYPTO-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e OrderError) Fields() map[string]any {
+	return errcode.NewFields("crypto", "", e.Step, "before", e.Before)
+}
+
+// InvalidProtectedError represents an error when the input of an Unprotector
+// is not a protected message.
+type InvalidProtectedError struct {
+	Reason string // What is wrong with the message
+}
+
+// Error returns a formatted error message describing the malformed message.
+func (e InvalidProtectedError) Error() string {
+	return fmt.Sprintf("crypto: invalid protected message: %s", e.Reason)
+}
+
+// Code returns the stable error code DGL-CRYPTO-003.
+func (e InvalidProtectedError) Code() string {
+	return "DGL-CRYPTO-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidProtectedError) Fields() map[string]any {
+	return errcode.NewFields("crypto", "", "unprotect", "reason", e.Reason)
+}
+
+// SignatureError represents an error when the signature of a protected
+// message does not verify.
+type SignatureError struct {
+	Err error // Underlying verification error, if any
+}
+
+// Error returns a formatted error message describing the failed verification.
+func (e SignatureError) Error() string {
+	if e.Err == nil {
+		return "crypto: protected message signature verification failed"
+	}
+	return fmt.Sprintf("crypto: protected message signature verification failed: %v", e.Err)
+}
+
+// Unwrap returns the underlying verification error.
+func (e SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code DGL-CRYPTO-004.
+func (e SignatureError) Code() string {
+	return "DGL-CRYPTO-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto", "", "verify", errcode.FieldCause, e.Err)
+}
+
+// BatchSignatureError represents an error when the signature of a batch item
+// does not verify and the verifier gave no further detail.
+type BatchSignatureError struct {
+	Index int // Index of the item in the batch
+}
+
+// Error returns a formatted error message describing the invalid signature.
+func (e BatchSignatureError) Error() string {
+	return fmt.Sprintf("crypto: signature of batch item %d is invalid", e.Index)
+}
+
+// Code returns the stable error code DGL-CRYPTO-005.
+func (e BatchSignatureError) Code() string {
+	return "DGL-CRYPTO-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BatchSignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto", "", "verify", "index", e.Index)
+}
@@ -0,0 +1,175 @@
+package escrow
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidThresholdError represents an error when the threshold is not between
+// one and the number of custodians, or there are more than 255 custodians.
+type InvalidThresholdError struct {
+	Threshold  int // Number of custodians required to recover the key
+	Custodians int // Total number of custodians
+}
+
+// Error returns a formatted error message describing the invalid threshold.
+func (e InvalidThresholdError) Error() string {
+	return fmt.Sprintf("crypto/escrow: invalid threshold %d of %d custodians", e.Threshold, e.Custodians)
+}
+
+// Code returns the stable error code DGL-ESCROW-001.
+func (e InvalidThresholdError) Code() string {
+	return "DGL-ESCROW-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidThresholdError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "Shamir", "", "threshold", e.Threshold, "custodians", e.Custodians)
+}
+
+// InvalidCustodianError represents an error when a custodian has no name, is
+// listed twice, is not part of a bundle or holds an unsupported key.
+type InvalidCustodianError struct {
+	Name   string // The custodian name
+	Reason string // What is wrong with the custodian
+}
+
+// Error returns a formatted error message describing the invalid custodian.
+func (e InvalidCustodianError) Error() string {
+	return fmt.Sprintf("crypto/escrow: invalid custodian %q: %s", e.Name, e.Reason)
+}
+
+// Code returns the stable error code DGL-ESCROW-002.
+func (e InvalidCustodianError) Code() string {
+	return "DGL-ESCROW-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCustodianError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "", "", "custodian", e.Name, "reason", e.Reason)
+}
+
+// UnsupportedKeyError represents an error when the escrowed private key cannot
+// be encoded in PKCS #8.
+type UnsupportedKeyError struct {
+	Err error // Underlying error from encoding the key
+}
+
+// Error returns a formatted error message describing the unsupported key.
+func (e UnsupportedKeyError) Error() string {
+	return fmt.Sprintf("crypto/escrow: unsupported private key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ESCROW-003.
+func (e UnsupportedKeyError) Code() string {
+	return "DGL-ESCROW-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "", "seal", errcode.FieldCause, e.Err)
+}
+
+// SealError represents an error when encrypting the key or its shares fails.
+type SealError struct {
+	Err error // Underlying error from the randomness source or encryption
+}
+
+// Error returns a formatted error message describing the sealing failure.
+func (e SealError) Error() string {
+	return fmt.Sprintf("crypto/escrow: failed to seal key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ESCROW-004.
+func (e SealError) Code() string {
+	return "DGL-ESCROW-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SealError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "AES-256-GCM", "seal", errcode.FieldCause, e.Err)
+}
+
+// InvalidBundleError represents an error when a bundle is malformed.
+type InvalidBundleError struct {
+	Err error // Underlying error from parsing
+}
+
+// Error returns a formatted error message describing the invalid bundle.
+func (e InvalidBundleError) Error() string {
+	return fmt.Sprintf("crypto/escrow: invalid bundle: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ESCROW-005.
+func (e InvalidBundleError) Code() string {
+	return "DGL-ESCROW-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidBundleError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "", "parse", errcode.FieldCause, e.Err)
+}
+
+// UnwrapShareError represents an error when a custodian key does not open its
+// share, because it is not the key the share was encrypted to or the share
+// was modified.
+type UnwrapShareError struct {
+	Custodian string // The custodian name
+}
+
+// Error returns a formatted error message describing the unwrap failure.
+func (e UnwrapShareError) Error() string {
+	return fmt.Sprintf("crypto/escrow: cannot unwrap the share of custodian %q", e.Custodian)
+}
+
+// Code returns the stable error code DGL-ESCROW-006.
+func (e UnwrapShareError) Code() string {
+	return "DGL-ESCROW-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnwrapShareError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "", "unwrap", "custodian", e.Custodian)
+}
+
+// InsufficientSharesError represents an error when fewer distinct shares than
+// the threshold are given for recovery.
+type InsufficientSharesError struct {
+	Shares    int // Number of distinct shares given
+	Threshold int // Number of shares required
+}
+
+// Error returns a formatted error message describing the missing shares.
+func (e InsufficientSharesError) Error() string {
+	return fmt.Sprintf("crypto/escrow: %d of %d required shares", e.Shares, e.Threshold)
+}
+
+// Code returns the stable error code DGL-ESCROW-007.
+func (e InsufficientSharesError) Code() string {
+	return "DGL-ESCROW-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InsufficientSharesError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "Shamir", "recover", "shares", e.Shares, "threshold", e.Threshold)
+}
+
+// RecoverError represents an error when the shares do not decrypt the key,
+// because a share is wrong or the bundle was modified.
+type RecoverError struct{}
+
+// Error returns a formatted error message describing the recovery failure.
+func (e RecoverError) Error() string {
+	return "crypto/escrow: shares do not recover the key or the bundle was modified"
+}
+
+// Code returns the stable error code DGL-ESCROW-008.
+func (e RecoverError) Code() string {
+	return "DGL-ESCROW-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RecoverError) Fields() map[string]any {
+	return errcode.NewFields("crypto/escrow", "AES-256-GCM", "recover")
+}
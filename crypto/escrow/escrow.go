@@ -0,0 +1,289 @@
+// Package escrow exports a private key to a group of custodians so that any
+// threshold of them can recover it together while fewer learn nothing about
+// it, replacing the manual combination of a key wrapping tool and a secret
+// sharing tool that key escrow and break-glass procedures usually rely on.
+//
+// Seal encrypts the key in PKCS #8 under a random AES-256-GCM data key, splits
+// the data key with Shamir's secret sharing and encrypts each share to the
+// public key of one custodian, with RSA-OAEP for RSA keys and ephemeral ECDH
+// for NIST curve and X25519 keys:
+//
+//	b, err := escrow.Seal(pri, 2,
+//		escrow.Custodian{Name: "alice", PublicKey: alicePub},
+//		escrow.Custodian{Name: "bob", PublicKey: bobPub},
+//		escrow.Custodian{Name: "carol", PublicKey: carolPub},
+//	)
+//	data, err := json.Marshal(b)
+//
+// Each custodian then unwraps their own share with their private key, and the
+// shares of any two of them recover the key:
+//
+//	b, err := escrow.Parse(data)
+//	share, err := b.Unwrap("alice", alicePri)
+//	pri, err := b.Recover(aliceShare, carolShare)
+//
+// Bundles are JSON documents listing the creation time, the threshold, the
+// fingerprint of the escrowed public key and the name and key fingerprint of
+// every custodian, so they can be audited without any secret. This metadata
+// is authenticated by the encryption of the key and cannot be changed without
+// making recovery fail, and every share is bound to its bundle and custodian.
+package escrow
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/dromara/dongle/coding/jcs"
+	"github.com/dromara/dongle/crypto/pinning"
+	"github.com/dromara/dongle/hash/domain"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Version is the bundle format version written by this package.
+const Version = 1
+
+// dataKeySize is the size of the AES-256 data key that is split into shares.
+const dataKeySize = 32
+
+// Custodian is a holder of a share, identified by a unique name.
+type Custodian struct {
+	Name      string           // Unique name, such as an email address
+	PublicKey crypto.PublicKey // *rsa.PublicKey, *ecdsa.PublicKey or *ecdh.PublicKey
+}
+
+// Recipient records a custodian in a bundle along with their encrypted share.
+type Recipient struct {
+	Name        string `json:"name"`        // Custodian name
+	Fingerprint string `json:"fingerprint"` // Fingerprint of the custodian public key, see pinning.KeyFingerprint
+	Algorithm   string `json:"algorithm"`   // Share encryption algorithm, RSAOAEP256 or ECDHES
+	Share       []byte `json:"share"`       // Share encrypted to the custodian
+}
+
+// Bundle is an escrowed private key.
+type Bundle struct {
+	Version     int         `json:"version"`     // Format version, see Version
+	Created     time.Time   `json:"created"`     // When the bundle was sealed
+	Fingerprint string      `json:"fingerprint"` // Fingerprint of the escrowed public key
+	Threshold   int         `json:"threshold"`   // Number of shares required for recovery
+	Custodians  []Recipient `json:"custodians"`  // Custodians in share order
+	Nonce       []byte      `json:"nonce"`       // AES-256-GCM nonce
+	Ciphertext  []byte      `json:"ciphertext"`  // AES-256-GCM encryption of the PKCS #8 key
+}
+
+// Share is the decrypted share of a custodian. Shares are secret and should be
+// handed to whoever performs the recovery over a confidential channel.
+type Share struct {
+	Custodian string `json:"custodian"` // Custodian name
+	Data      []byte `json:"data"`      // Share of the data key
+}
+
+// Seal escrows a private key supported by x509.MarshalPKCS8PrivateKey to the
+// custodians, any threshold of whom can recover it.
+func Seal(key crypto.PrivateKey, threshold int, custodians ...Custodian) (*Bundle, error) {
+	if threshold < 1 || threshold > len(custodians) || len(custodians) > 255 {
+		return nil, InvalidThresholdError{Threshold: threshold, Custodians: len(custodians)}
+	}
+	signer, ok := key.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return nil, UnsupportedKeyError{Err: errors.New("no public key")}
+	}
+	fingerprint, err := pinning.KeyFingerprint(signer.Public())
+	if err != nil {
+		return nil, UnsupportedKeyError{Err: err}
+	}
+	plaintext, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, UnsupportedKeyError{Err: err}
+	}
+	defer utils.SecureWipe(plaintext)
+
+	b := &Bundle{
+		Version:     Version,
+		Created:     utils.Now().UTC().Truncate(time.Second),
+		Fingerprint: fingerprint,
+		Threshold:   threshold,
+		Custodians:  make([]Recipient, len(custodians)),
+	}
+	names := make(map[string]bool, len(custodians))
+	for i, c := range custodians {
+		if c.Name == "" || names[c.Name] {
+			return nil, InvalidCustodianError{Name: c.Name, Reason: "empty or duplicate name"}
+		}
+		names[c.Name] = true
+		algorithm, ok := wrapAlgorithm(c.PublicKey)
+		if !ok {
+			return nil, InvalidCustodianError{Name: c.Name, Reason: "unsupported public key"}
+		}
+		fp, err := pinning.KeyFingerprint(c.PublicKey)
+		if err != nil {
+			return nil, InvalidCustodianError{Name: c.Name, Reason: "unsupported public key"}
+		}
+		b.Custodians[i] = Recipient{Name: c.Name, Fingerprint: fp, Algorithm: algorithm}
+	}
+	aad, err := b.header()
+	if err != nil {
+		return nil, SealError{Err: err}
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	defer utils.SecureWipe(dataKey)
+	b.Nonce = make([]byte, 12)
+	if _, err = io.ReadFull(utils.Rand(), dataKey); err != nil {
+		return nil, SealError{Err: err}
+	}
+	if _, err = io.ReadFull(utils.Rand(), b.Nonce); err != nil {
+		return nil, SealError{Err: err}
+	}
+	b.Ciphertext = newGCM(dataKey).Seal(nil, b.Nonce, plaintext, aad)
+
+	shares, err := split(utils.Rand(), dataKey, threshold, len(custodians))
+	if err != nil {
+		return nil, SealError{Err: err}
+	}
+	for i, c := range custodians {
+		b.Custodians[i].Share, err = wrap(c.PublicKey, shares[i], shareContext(aad, c.Name))
+		utils.SecureWipe(shares[i])
+		if err != nil {
+			return nil, SealError{Err: err}
+		}
+	}
+	return b, nil
+}
+
+// Parse parses a bundle encoded as JSON.
+func Parse(data []byte) (*Bundle, error) {
+	b := new(Bundle)
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, InvalidBundleError{Err: err}
+	}
+	if b.Version != Version {
+		return nil, InvalidBundleError{Err: errors.New("unsupported version")}
+	}
+	if b.Threshold < 1 || b.Threshold > len(b.Custodians) || len(b.Custodians) > 255 {
+		return nil, InvalidBundleError{Err: InvalidThresholdError{Threshold: b.Threshold, Custodians: len(b.Custodians)}}
+	}
+	if len(b.Nonce) != 12 {
+		return nil, InvalidBundleError{Err: errors.New("invalid nonce size")}
+	}
+	names := make(map[string]bool, len(b.Custodians))
+	for _, r := range b.Custodians {
+		if r.Name == "" || names[r.Name] {
+			return nil, InvalidBundleError{Err: InvalidCustodianError{Name: r.Name, Reason: "empty or duplicate name"}}
+		}
+		names[r.Name] = true
+	}
+	return b, nil
+}
+
+// Custodian returns the record of the named custodian.
+func (b *Bundle) Custodian(name string) (Recipient, bool) {
+	i := b.index(name)
+	if i < 0 {
+		return Recipient{}, false
+	}
+	return b.Custodians[i], true
+}
+
+// Unwrap decrypts the share of the named custodian with their private key,
+// an *rsa.PrivateKey, *ecdsa.PrivateKey or *ecdh.PrivateKey.
+func (b *Bundle) Unwrap(name string, key crypto.PrivateKey) (Share, error) {
+	i := b.index(name)
+	if i < 0 {
+		return Share{}, InvalidCustodianError{Name: name, Reason: "not in bundle"}
+	}
+	aad, err := b.header()
+	if err != nil {
+		return Share{}, InvalidBundleError{Err: err}
+	}
+	data, err := unwrap(key, b.Custodians[i].Share, shareContext(aad, name))
+	if err != nil || len(data) != dataKeySize {
+		return Share{}, UnwrapShareError{Custodian: name}
+	}
+	return Share{Custodian: name, Data: data}, nil
+}
+
+// Recover combines the shares of at least threshold distinct custodians and
+// returns the escrowed private key.
+func (b *Bundle) Recover(shares ...Share) (crypto.PrivateKey, error) {
+	xs := make([]byte, 0, len(shares))
+	ys := make([][]byte, 0, len(shares))
+	for _, s := range shares {
+		i := b.index(s.Custodian)
+		if i < 0 {
+			return nil, InvalidCustodianError{Name: s.Custodian, Reason: "not in bundle"}
+		}
+		if len(s.Data) != dataKeySize {
+			return nil, RecoverError{}
+		}
+		x := byte(i + 1)
+		if !slices.Contains(xs, x) {
+			xs = append(xs, x)
+			ys = append(ys, s.Data)
+		}
+	}
+	if len(xs) < b.Threshold {
+		return nil, InsufficientSharesError{Shares: len(xs), Threshold: b.Threshold}
+	}
+	aad, err := b.header()
+	if err != nil {
+		return nil, InvalidBundleError{Err: err}
+	}
+	dataKey := combine(xs, ys)
+	defer utils.SecureWipe(dataKey)
+	plaintext, err := newGCM(dataKey).Open(nil, b.Nonce, b.Ciphertext, aad)
+	if err != nil {
+		return nil, RecoverError{}
+	}
+	defer utils.SecureWipe(plaintext)
+	key, err := x509.ParsePKCS8PrivateKey(plaintext)
+	if err != nil {
+		return nil, RecoverError{}
+	}
+	return key, nil
+}
+
+// header returns the canonical JSON of the bundle metadata, authenticated as
+// additional data of the key encryption.
+func (b *Bundle) header() ([]byte, error) {
+	type custodian struct {
+		Name        string `json:"name"`
+		Fingerprint string `json:"fingerprint"`
+		Algorithm   string `json:"algorithm"`
+	}
+	h := struct {
+		Version     int         `json:"version"`
+		Created     time.Time   `json:"created"`
+		Fingerprint string      `json:"fingerprint"`
+		Threshold   int         `json:"threshold"`
+		Custodians  []custodian `json:"custodians"`
+	}{b.Version, b.Created, b.Fingerprint, b.Threshold, make([]custodian, len(b.Custodians))}
+	for i, r := range b.Custodians {
+		h.Custodians[i] = custodian{r.Name, r.Fingerprint, r.Algorithm}
+	}
+	return jcs.Marshal(h)
+}
+
+// index returns the position of the named custodian, or -1.
+func (b *Bundle) index(name string) int {
+	return slices.IndexFunc(b.Custodians, func(r Recipient) bool { return r.Name == name })
+}
+
+// shareContext binds an encrypted share to its bundle and custodian.
+func shareContext(aad []byte, name string) []byte {
+	return domain.Sum(sha256.New, "dongle/escrow/share", aad, []byte(name))
+}
+
+// newGCM returns AES-256-GCM under key, which always has a valid size.
+func newGCM(key []byte) cipher.AEAD {
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
@@ -0,0 +1,260 @@
+package escrow
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// custodian is a test custodian with its private key.
+type custodian struct {
+	name string
+	pri  crypto.PrivateKey
+	pub  crypto.PublicKey
+}
+
+// opaqueKey is a private key that cannot be exported, like a key held in an HSM.
+type opaqueKey struct{ pub crypto.PublicKey }
+
+func (k opaqueKey) Public() crypto.PublicKey { return k.pub }
+
+func newCustodians(t *testing.T) []custodian {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	xKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return []custodian{
+		{"alice", rsaKey, &rsaKey.PublicKey},
+		{"bob", ecKey, &ecKey.PublicKey},
+		{"carol", xKey, xKey.PublicKey()},
+	}
+}
+
+func sealTo(t *testing.T, key crypto.PrivateKey, threshold int, cs []custodian) *Bundle {
+	list := make([]Custodian, len(cs))
+	for i, c := range cs {
+		list[i] = Custodian{Name: c.name, PublicKey: c.pub}
+	}
+	b, err := Seal(key, threshold, list...)
+	require.NoError(t, err)
+	return b
+}
+
+func TestSealRecover(t *testing.T) {
+	cs := newCustodians(t)
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	b := sealTo(t, key, 2, cs)
+
+	data, err := json.Marshal(b)
+	require.NoError(t, err)
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, 2, parsed.Threshold)
+	assert.Equal(t, []string{RSAOAEP256, ECDHES, ECDHES}, []string{parsed.Custodians[0].Algorithm, parsed.Custodians[1].Algorithm, parsed.Custodians[2].Algorithm})
+	r, ok := parsed.Custodian("bob")
+	assert.True(t, ok)
+	assert.Contains(t, r.Fingerprint, "SHA256:")
+	_, ok = parsed.Custodian("mallory")
+	assert.False(t, ok)
+
+	shares := make([]Share, len(cs))
+	for i, c := range cs {
+		shares[i], err = parsed.Unwrap(c.name, c.pri)
+		require.NoError(t, err, c.name)
+	}
+	for _, pair := range [][2]int{{0, 1}, {0, 2}, {1, 2}, {2, 0}} {
+		got, err := parsed.Recover(shares[pair[0]], shares[pair[1]])
+		require.NoError(t, err)
+		assert.Equal(t, key, got)
+	}
+	got, err := parsed.Recover(shares...)
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	t.Run("insufficient shares", func(t *testing.T) {
+		_, err := parsed.Recover(shares[0])
+		assert.Equal(t, InsufficientSharesError{Shares: 1, Threshold: 2}, err)
+		_, err = parsed.Recover(shares[1], shares[1])
+		assert.Equal(t, InsufficientSharesError{Shares: 1, Threshold: 2}, err)
+	})
+
+	t.Run("wrong share", func(t *testing.T) {
+		forged := Share{Custodian: "bob", Data: bytes.Clone(shares[1].Data)}
+		forged.Data[0] ^= 1
+		_, err := parsed.Recover(shares[0], forged)
+		assert.Equal(t, RecoverError{}, err)
+		_, err = parsed.Recover(shares[0], Share{Custodian: "bob", Data: []byte("short")})
+		assert.Equal(t, RecoverError{}, err)
+		_, err = parsed.Recover(shares[0], Share{Custodian: "mallory", Data: shares[1].Data})
+		assert.IsType(t, InvalidCustodianError{}, err)
+	})
+
+	t.Run("wrong custodian key", func(t *testing.T) {
+		_, err := parsed.Unwrap("alice", cs[1].pri)
+		assert.Equal(t, UnwrapShareError{Custodian: "alice"}, err)
+		_, err = parsed.Unwrap("bob", cs[0].pri)
+		assert.Equal(t, UnwrapShareError{Custodian: "bob"}, err)
+		_, err = parsed.Unwrap("carol", "not a key")
+		assert.Equal(t, UnwrapShareError{Custodian: "carol"}, err)
+		_, err = parsed.Unwrap("mallory", cs[0].pri)
+		assert.Equal(t, InvalidCustodianError{Name: "mallory", Reason: "not in bundle"}, err)
+	})
+
+	t.Run("tampered metadata", func(t *testing.T) {
+		tampered := *parsed
+		tampered.Created = tampered.Created.Add(time.Hour)
+		_, err := tampered.Recover(shares[0], shares[1])
+		assert.Equal(t, RecoverError{}, err)
+		// Shares are bound to the bundle they were sealed in.
+		_, err = tampered.Unwrap("alice", cs[0].pri)
+		assert.Equal(t, UnwrapShareError{Custodian: "alice"}, err)
+
+		swapped := *parsed
+		swapped.Custodians = []Recipient{parsed.Custodians[0], parsed.Custodians[2], parsed.Custodians[1]}
+		_, err = swapped.Unwrap("bob", cs[1].pri)
+		assert.Equal(t, UnwrapShareError{Custodian: "bob"}, err)
+	})
+}
+
+func TestSeal(t *testing.T) {
+	cs := newCustodians(t)
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("one of one", func(t *testing.T) {
+		b := sealTo(t, key, 1, cs[1:2])
+		share, err := b.Unwrap("bob", cs[1].pri)
+		require.NoError(t, err)
+		got, err := b.Recover(share)
+		require.NoError(t, err)
+		assert.True(t, key.Equal(got))
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		pubs := []Custodian{{Name: "alice", PublicKey: cs[0].pub}, {Name: "bob", PublicKey: cs[1].pub}}
+		for _, threshold := range []int{0, 3} {
+			_, err := Seal(key, threshold, pubs...)
+			assert.Equal(t, InvalidThresholdError{Threshold: threshold, Custodians: 2}, err)
+		}
+		_, err := Seal(key, 1)
+		assert.IsType(t, InvalidThresholdError{}, err)
+	})
+
+	t.Run("invalid custodians", func(t *testing.T) {
+		edPub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		require.NoError(t, err)
+		for _, list := range [][]Custodian{
+			{{Name: "", PublicKey: cs[0].pub}},
+			{{Name: "alice", PublicKey: cs[0].pub}, {Name: "alice", PublicKey: cs[1].pub}},
+			{{Name: "dave", PublicKey: edPub}},
+			{{Name: "dave", PublicKey: &p224.PublicKey}},
+			{{Name: "dave"}},
+		} {
+			_, err := Seal(key, 1, list...)
+			assert.IsType(t, InvalidCustodianError{}, err)
+		}
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		_, err := Seal("not a key", 1, Custodian{Name: "alice", PublicKey: cs[0].pub})
+		assert.IsType(t, UnsupportedKeyError{}, err)
+		_, err = Seal(opaqueKey{cs[0].pub}, 1, Custodian{Name: "alice", PublicKey: cs[0].pub})
+		assert.IsType(t, UnsupportedKeyError{}, err)
+	})
+
+	t.Run("random failure", func(t *testing.T) {
+		t.Cleanup(utils.SetRand(bytes.NewReader(nil)))
+		_, err := Seal(key, 1, Custodian{Name: "alice", PublicKey: cs[0].pub})
+		assert.IsType(t, SealError{}, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	cs := newCustodians(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	data, err := json.Marshal(sealTo(t, key, 2, cs))
+	require.NoError(t, err)
+
+	edit := func(fn func(m map[string]any)) []byte {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(data, &m))
+		fn(m)
+		out, err := json.Marshal(m)
+		require.NoError(t, err)
+		return out
+	}
+	for name, input := range map[string][]byte{
+		"not json":  []byte("{"),
+		"version":   edit(func(m map[string]any) { m["version"] = 2 }),
+		"threshold": edit(func(m map[string]any) { m["threshold"] = 4 }),
+		"nonce":     edit(func(m map[string]any) { m["nonce"] = "AAAA" }),
+		"duplicate": edit(func(m map[string]any) {
+			list := m["custodians"].([]any)
+			m["custodians"] = append(list, list[0])
+		}),
+	} {
+		_, err := Parse(input)
+		assert.IsType(t, InvalidBundleError{}, err, name)
+	}
+
+	// Metadata edits that still parse no longer open any share.
+	b, err := Parse(edit(func(m map[string]any) { m["threshold"] = 3 }))
+	require.NoError(t, err)
+	var shares []Share
+	for _, c := range cs {
+		s, err := b.Unwrap(c.name, c.pri)
+		if err == nil {
+			shares = append(shares, s)
+		}
+	}
+	assert.Empty(t, shares)
+}
+
+func TestShamir(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, err := split(rand.Reader, secret, 3, 5)
+	require.NoError(t, err)
+	for a := 0; a < 5; a++ {
+		for b := a + 1; b < 5; b++ {
+			for c := b + 1; c < 5; c++ {
+				xs := []byte{byte(a + 1), byte(b + 1), byte(c + 1)}
+				assert.Equal(t, secret, combine(xs, [][]byte{shares[a], shares[b], shares[c]}))
+			}
+			assert.NotEqual(t, secret, combine([]byte{byte(a + 1), byte(b + 1)}, [][]byte{shares[a], shares[b]}))
+		}
+	}
+
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), gfMul(byte(a), gfInv(byte(a))), a)
+	}
+	assert.Equal(t, byte(0xc1), gfMul(0x57, 0x83))
+
+	_, err = split(bytes.NewReader(nil), secret, 2, 3)
+	assert.Error(t, err)
+}
+
+func TestErrors(t *testing.T) {
+	assert.Equal(t, "crypto/escrow: invalid threshold 3 of 2 custodians", InvalidThresholdError{Threshold: 3, Custodians: 2}.Error())
+	assert.Equal(t, `crypto/escrow: invalid custodian "bob": not in bundle`, InvalidCustodianError{Name: "bob", Reason: "not in bundle"}.Error())
+	assert.Equal(t, "crypto/escrow: 1 of 2 required shares", InsufficientSharesError{Shares: 1, Threshold: 2}.Error())
+	assert.Equal(t, `crypto/escrow: cannot unwrap the share of custodian "bob"`, UnwrapShareError{Custodian: "bob"}.Error())
+	assert.Contains(t, RecoverError{}.Error(), "do not recover")
+}
@@ -0,0 +1,73 @@
+package escrow
+
+import (
+	"io"
+)
+
+// Shamir's secret sharing over GF(2^8) with the AES polynomial
+// x^8 + x^4 + x^3 + x + 1, applied to each byte of the secret independently.
+// The share of custodian i is the value of the polynomials at x = i+1; the
+// secret is their value at zero.
+
+// split returns n shares of secret, any threshold of which recover it.
+func split(random io.Reader, secret []byte, threshold, n int) ([][]byte, error) {
+	coeffs := make([]byte, len(secret)*(threshold-1))
+	if _, err := io.ReadFull(random, coeffs); err != nil {
+		return nil, err
+	}
+	shares := make([][]byte, n)
+	for i := range shares {
+		x := byte(i + 1)
+		share := make([]byte, len(secret))
+		for j, s := range secret {
+			// Horner's rule from the highest coefficient down to the secret.
+			var y byte
+			for k := threshold - 2; k >= 0; k-- {
+				y = gfMul(y, x) ^ coeffs[j*(threshold-1)+k]
+			}
+			share[j] = gfMul(y, x) ^ s
+		}
+		shares[i] = share
+	}
+	return shares, nil
+}
+
+// combine interpolates the shares at zero. xs holds the distinct non-zero
+// coordinates of the shares, which must all have the same length.
+func combine(xs []byte, shares [][]byte) []byte {
+	secret := make([]byte, len(shares[0]))
+	for i, xi := range xs {
+		// Lagrange basis polynomial of xi evaluated at zero.
+		basis := byte(1)
+		for j, xj := range xs {
+			if i != j {
+				basis = gfMul(basis, gfMul(xj, gfInv(xi^xj)))
+			}
+		}
+		for k, y := range shares[i] {
+			secret[k] ^= gfMul(y, basis)
+		}
+	}
+	return secret
+}
+
+// gfMul multiplies in GF(2^8) without data dependent branches.
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		a = a<<1 ^ 0x1b&-(a>>7)
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns the multiplicative inverse a^254 of a non-zero element.
+func gfInv(a byte) byte {
+	r := a
+	for i := 0; i < 6; i++ {
+		a = gfMul(a, a)
+		r = gfMul(r, a)
+	}
+	return gfMul(r, r)
+}
@@ -0,0 +1,135 @@
+package escrow
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Share encryption algorithms recorded for each custodian.
+const (
+	RSAOAEP256 = "RSA-OAEP-256"    // RSAES-OAEP with SHA-256 and MGF1-SHA-256
+	ECDHES     = "ECDH-ES+A256GCM" // Ephemeral ECDH, HKDF-SHA256 and AES-256-GCM
+)
+
+// errUnwrap is returned by the unwrap functions, the details are not exposed
+// so that failures cannot be told apart.
+var errUnwrap = errors.New("unwrap failed")
+
+// wrapAlgorithm returns the share encryption algorithm for a custodian key.
+func wrapAlgorithm(pub crypto.PublicKey) (string, bool) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return RSAOAEP256, true
+	case *ecdsa.PublicKey:
+		_, err := key.ECDH()
+		return ECDHES, err == nil
+	case *ecdh.PublicKey:
+		return ECDHES, true
+	}
+	return "", false
+}
+
+// wrap encrypts a share to a custodian public key. The context binds the
+// ciphertext to the bundle and custodian it was made for.
+func wrap(pub crypto.PublicKey, share, context []byte) ([]byte, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.EncryptOAEP(sha256.New(), utils.Rand(), key, share, context)
+	case *ecdsa.PublicKey:
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		return wrapECDH(ecdhKey, share, context)
+	case *ecdh.PublicKey:
+		return wrapECDH(key, share, context)
+	}
+	return nil, errors.New("unsupported custodian key")
+}
+
+// wrapECDH encrypts a share under a key agreed with an ephemeral key pair and
+// returns the ephemeral public key followed by the ciphertext.
+func wrapECDH(pub *ecdh.PublicKey, share, context []byte) ([]byte, error) {
+	ephemeral, err := pub.Curve().GenerateKey(utils.Rand())
+	if err != nil {
+		return nil, err
+	}
+	secret, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.SecureWipe(secret)
+	aead, err := wrapAEAD(secret, ephemeral.PublicKey(), pub, context)
+	if err != nil {
+		return nil, err
+	}
+	// The key is used only once, so the nonce can be fixed.
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ephemeral.PublicKey().Bytes(), nonce, share, nil), nil
+}
+
+// unwrap decrypts a share with a custodian private key.
+func unwrap(pri crypto.PrivateKey, data, context []byte) ([]byte, error) {
+	switch key := pri.(type) {
+	case *rsa.PrivateKey:
+		return rsa.DecryptOAEP(sha256.New(), nil, key, data, context)
+	case *ecdsa.PrivateKey:
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		return unwrapECDH(ecdhKey, data, context)
+	case *ecdh.PrivateKey:
+		return unwrapECDH(key, data, context)
+	}
+	return nil, errUnwrap
+}
+
+// unwrapECDH reverses wrapECDH.
+func unwrapECDH(pri *ecdh.PrivateKey, data, context []byte) ([]byte, error) {
+	size := len(pri.PublicKey().Bytes())
+	if len(data) < size {
+		return nil, errUnwrap
+	}
+	ephemeral, err := pri.Curve().NewPublicKey(data[:size])
+	if err != nil {
+		return nil, errUnwrap
+	}
+	secret, err := pri.ECDH(ephemeral)
+	if err != nil {
+		return nil, errUnwrap
+	}
+	defer utils.SecureWipe(secret)
+	aead, err := wrapAEAD(secret, ephemeral, pri.PublicKey(), context)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Open(nil, nonce, data[size:], nil)
+}
+
+// wrapAEAD derives the AES-256-GCM key of a share from the shared secret, both
+// public keys and the context.
+func wrapAEAD(secret []byte, ephemeral, recipient *ecdh.PublicKey, context []byte) (cipher.AEAD, error) {
+	info := append(append(append([]byte(nil), context...), ephemeral.Bytes()...), recipient.Bytes()...)
+	key := make([]byte, 32)
+	defer utils.SecureWipe(key)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
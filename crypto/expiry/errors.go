@@ -0,0 +1,106 @@
+package expiry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidAEADError represents an error when the wrapped AEAD is missing or
+// does not use nonces.
+type InvalidAEADError struct{}
+
+// Error returns a formatted error message describing the unusable AEAD.
+func (e InvalidAEADError) Error() string {
+	return "crypto/expiry: AEAD must be set and use a nonce"
+}
+
+// Code returns the stable error code DGL-EXPIRY-001.
+func (e InvalidAEADError) Code() string {
+	return "DGL-EXPIRY-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidAEADError) Fields() map[string]any {
+	return errcode.NewFields("crypto/expiry", "", "")
+}
+
+// InvalidTTLError represents an error when a ciphertext would already be
+// expired when sealed.
+type InvalidTTLError struct {
+	TTL time.Duration // The rejected time to live
+}
+
+// Error returns a formatted error message describing the invalid time to live.
+func (e InvalidTTLError) Error() string {
+	return fmt.Sprintf("crypto/expiry: invalid ttl %s, must be positive", e.TTL)
+}
+
+// Code returns the stable error code DGL-EXPIRY-002.
+func (e InvalidTTLError) Code() string {
+	return "DGL-EXPIRY-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidTTLError) Fields() map[string]any {
+	return errcode.NewFields("crypto/expiry", "", "encrypt", "ttl", e.TTL.String())
+}
+
+// InvalidCiphertextError represents an error when a ciphertext is too short or
+// has an unknown version.
+type InvalidCiphertextError struct{}
+
+// Error returns a formatted error message describing the malformed ciphertext.
+func (e InvalidCiphertextError) Error() string {
+	return "crypto/expiry: invalid ciphertext"
+}
+
+// Code returns the stable error code DGL-EXPIRY-003.
+func (e InvalidCiphertextError) Code() string {
+	return "DGL-EXPIRY-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCiphertextError) Fields() map[string]any {
+	return errcode.NewFields("crypto/expiry", "", "decrypt")
+}
+
+// ExpiredError represents an error when a ciphertext is opened after its expiry.
+type ExpiredError struct {
+	Expiry time.Time // When the ciphertext expired
+}
+
+// Error returns a formatted error message describing the expired ciphertext.
+func (e ExpiredError) Error() string {
+	return fmt.Sprintf("crypto/expiry: ciphertext expired at %s", e.Expiry.UTC().Format(time.RFC3339))
+}
+
+// Code returns the stable error code DGL-EXPIRY-004.
+func (e ExpiredError) Code() string {
+	return "DGL-EXPIRY-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ExpiredError) Fields() map[string]any {
+	return errcode.NewFields("crypto/expiry", "", "decrypt", "expiry", e.Expiry.UTC().Format(time.RFC3339))
+}
+
+// DecryptError represents an error when a ciphertext fails authentication,
+// including when its expiry was tampered with.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the authentication failure.
+func (e DecryptError) Error() string {
+	return "crypto/expiry: failed to decrypt ciphertext"
+}
+
+// Code returns the stable error code DGL-EXPIRY-005.
+func (e DecryptError) Code() string {
+	return "DGL-EXPIRY-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/expiry", "", "decrypt")
+}
@@ -0,0 +1,111 @@
+// Package expiry wraps an AEAD so that ciphertexts carry an expiry time and
+// cannot be decrypted once it has passed. The expiry is stored in clear in a
+// small header and bound to the ciphertext as additional data, so moving it
+// forward breaks authentication. Use it for tokens and cached secrets that must
+// not outlive a TTL even when they leak.
+package expiry
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// version is the ciphertext format version.
+const version = 1
+
+// headerSize is the size of the version byte and the expiry in Unix seconds.
+const headerSize = 1 + 8
+
+// Cipher seals and opens ciphertexts with an expiry using a wrapped AEAD.
+type Cipher struct {
+	aead  cipher.AEAD      // Wrapped AEAD, such as AES-GCM or ChaCha20-Poly1305
+	now   func() time.Time // Clock returning the current time
+	Error error            // Error field for storing configuration errors
+}
+
+// NewCipher returns a new Cipher wrapping aead. A fresh random nonce is used
+// for every ciphertext.
+func NewCipher(aead cipher.AEAD) *Cipher {
+	c := &Cipher{aead: aead, now: utils.Now}
+	if aead == nil || aead.NonceSize() == 0 {
+		c.Error = InvalidAEADError{}
+	}
+	return c
+}
+
+// SetClock sets the function returning the current time.
+func (c *Cipher) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// Seal encrypts plaintext so that it expires ttl from now. The optional
+// additional data must be passed to Open unchanged.
+func (c *Cipher) Seal(plaintext, additionalData []byte, ttl time.Duration) ([]byte, error) {
+	if ttl <= 0 {
+		return nil, InvalidTTLError{TTL: ttl}
+	}
+	return c.SealUntil(plaintext, additionalData, c.now().Add(ttl))
+}
+
+// SealUntil encrypts plaintext so that it expires at expiry, truncated to the
+// second.
+func (c *Cipher) SealUntil(plaintext, additionalData []byte, expiry time.Time) ([]byte, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+	if !expiry.After(c.now()) {
+		return nil, InvalidTTLError{TTL: expiry.Sub(c.now())}
+	}
+	nonceSize := c.aead.NonceSize()
+	out := make([]byte, headerSize+nonceSize, headerSize+nonceSize+len(plaintext)+c.aead.Overhead())
+	out[0] = version
+	binary.BigEndian.PutUint64(out[1:headerSize], uint64(expiry.Unix()))
+	if _, err := io.ReadFull(utils.Rand(), out[headerSize:]); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(out, out[headerSize:], plaintext, bind(out[:headerSize], additionalData)), nil
+}
+
+// Open decrypts a ciphertext produced by Seal. Expired ciphertexts are refused
+// with an ExpiredError before anything is decrypted.
+func (c *Cipher) Open(ciphertext, additionalData []byte) ([]byte, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+	expiry, err := Expiry(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if !c.now().Before(expiry) {
+		return nil, ExpiredError{Expiry: expiry}
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < headerSize+nonceSize+c.aead.Overhead() {
+		return nil, InvalidCiphertextError{}
+	}
+	nonce := ciphertext[headerSize : headerSize+nonceSize]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext[headerSize+nonceSize:], bind(ciphertext[:headerSize], additionalData))
+	if err != nil {
+		return nil, DecryptError{}
+	}
+	return plaintext, nil
+}
+
+// Expiry returns the expiry of a ciphertext without decrypting it. The value
+// is not authenticated until the ciphertext is opened.
+func Expiry(ciphertext []byte) (time.Time, error) {
+	if len(ciphertext) < headerSize || ciphertext[0] != version {
+		return time.Time{}, InvalidCiphertextError{}
+	}
+	return time.Unix(int64(binary.BigEndian.Uint64(ciphertext[1:headerSize])), 0).UTC(), nil
+}
+
+// bind returns the AEAD additional data made of the header followed by the
+// caller's additional data.
+func bind(header, additionalData []byte) []byte {
+	return append(append(make([]byte, 0, len(header)+len(additionalData)), header...), additionalData...)
+}
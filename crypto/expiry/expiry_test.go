@@ -0,0 +1,131 @@
+package expiry
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var key = bytes.Repeat([]byte{0x5a}, 32)
+
+func newGCM() cipher.AEAD {
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestSealOpen(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	c := NewCipher(newGCM())
+	require.NoError(t, c.Error)
+	c.SetClock(fixedClock(now))
+
+	token := []byte("session token")
+	ct, err := c.Seal(token, []byte("user-42"), time.Hour)
+	require.NoError(t, err)
+	expiry, err := Expiry(ct)
+	require.NoError(t, err)
+	assert.True(t, now.Add(time.Hour).Equal(expiry))
+
+	t.Run("before expiry", func(t *testing.T) {
+		c.SetClock(fixedClock(now.Add(59 * time.Minute)))
+		pt, err := c.Open(ct, []byte("user-42"))
+		require.NoError(t, err)
+		assert.Equal(t, token, pt)
+	})
+
+	t.Run("after expiry", func(t *testing.T) {
+		c.SetClock(fixedClock(now.Add(time.Hour)))
+		_, err := c.Open(ct, []byte("user-42"))
+		assert.Equal(t, ExpiredError{Expiry: expiry}, err)
+		assert.Equal(t, "crypto/expiry: ciphertext expired at 2026-10-16T13:00:00Z", err.Error())
+	})
+
+	t.Run("extended expiry", func(t *testing.T) {
+		c.SetClock(fixedClock(now.Add(2 * time.Hour)))
+		forged := append([]byte{}, ct...)
+		binary.BigEndian.PutUint64(forged[1:headerSize], uint64(now.Add(3*time.Hour).Unix()))
+		_, err := c.Open(forged, []byte("user-42"))
+		assert.Equal(t, DecryptError{}, err)
+	})
+
+	t.Run("wrong additional data", func(t *testing.T) {
+		c.SetClock(fixedClock(now))
+		_, err := c.Open(ct, []byte("user-43"))
+		assert.Equal(t, DecryptError{}, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		c.SetClock(fixedClock(now))
+		for _, in := range [][]byte{nil, ct[:headerSize], append([]byte{2}, ct[1:]...)} {
+			_, err := c.Open(in, nil)
+			assert.Equal(t, InvalidCiphertextError{}, err)
+		}
+	})
+}
+
+func TestSealUntil(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	aead, _ := chacha20poly1305.NewX(key)
+	c := NewCipher(aead)
+	c.SetClock(fixedClock(now))
+
+	ct, err := c.SealUntil([]byte("secret"), nil, now.Add(90*time.Second+500*time.Millisecond))
+	require.NoError(t, err)
+	expiry, _ := Expiry(ct)
+	assert.True(t, now.Add(90*time.Second).Equal(expiry))
+	pt, err := c.Open(ct, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), pt)
+
+	_, err = c.SealUntil([]byte("secret"), nil, now)
+	assert.Equal(t, InvalidTTLError{TTL: 0}, err)
+	_, err = c.Seal([]byte("secret"), nil, -time.Second)
+	assert.Equal(t, InvalidTTLError{TTL: -time.Second}, err)
+	assert.Equal(t, "crypto/expiry: invalid ttl -1s, must be positive", err.Error())
+}
+
+func TestInvalidAEAD(t *testing.T) {
+	c := NewCipher(nil)
+	assert.Equal(t, InvalidAEADError{}, c.Error)
+	_, err := c.Seal([]byte("x"), nil, time.Hour)
+	assert.Equal(t, InvalidAEADError{}, err)
+	_, err = c.Open([]byte("x"), nil)
+	assert.Equal(t, InvalidAEADError{}, err)
+}
+
+func TestDeterministic(t *testing.T) {
+	clock := mock.NewClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(utils.SetClock(clock.Now))
+
+	seal := func() []byte {
+		restore := utils.SetRand(mock.NewRand([]byte("nonce")))
+		defer restore()
+		ct, err := NewCipher(newGCM()).Seal([]byte("secret"), nil, time.Minute)
+		require.NoError(t, err)
+		return ct
+	}
+	ct := seal()
+	assert.Equal(t, ct, seal())
+
+	c := NewCipher(newGCM())
+	clock.Advance(59 * time.Second)
+	_, err := c.Open(ct, nil)
+	assert.NoError(t, err)
+	clock.Advance(time.Second)
+	_, err = c.Open(ct, nil)
+	assert.IsType(t, ExpiredError{}, err)
+}
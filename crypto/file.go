@@ -0,0 +1,35 @@
+package crypto
+
+import (
+	"io/fs"
+	"os"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// WriteFileEncrypted encrypts data and writes it to the named file atomically.
+// The ciphertext is written to a temporary file in the same directory, synced
+// to disk and renamed over path, so readers and crashes see either the old
+// file or the complete new one, never a partial ciphertext. The file is
+// created with perm, and replaces any existing file.
+//
+// Any StdEncrypter of the cipher packages can be used, for example
+//
+//	crypto.WriteFileEncrypted("secret.bin", data, aes.NewStdEncrypter(c), 0o600)
+func WriteFileEncrypted(path string, data []byte, encrypter BatchEncrypter, perm fs.FileMode) error {
+	dst, err := encrypter.Encrypt(data)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(path, dst, perm)
+}
+
+// ReadFileDecrypted reads the named file and decrypts its content, as written
+// by WriteFileEncrypted.
+func ReadFileDecrypted(path string, decrypter BatchDecrypter) ([]byte, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decrypter.Decrypt(src)
+}
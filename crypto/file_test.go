@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/aes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingEncrypter always fails to encrypt.
+type failingEncrypter struct{}
+
+func (failingEncrypter) Encrypt([]byte) ([]byte, error) { return nil, errors.New("encrypt error") }
+
+// tempFiles returns the names of the temporary files left in dir.
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestWriteFileEncrypted(t *testing.T) {
+	c := newAesCipher()
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.bin")
+		require.NoError(t, WriteFileEncrypted(path, []byte("hello world"), aes.NewStdEncrypter(c), 0o600))
+
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, NewEncrypter().FromString("hello world").ByAes(c).ToRawBytes(), raw)
+
+		data, err := ReadFileDecrypted(path, aes.NewStdDecrypter(c))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), data)
+		assert.Empty(t, tempFiles(t, filepath.Dir(path)))
+
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		}
+	})
+
+	t.Run("replaces existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.bin")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+		require.NoError(t, WriteFileEncrypted(path, []byte("new"), aes.NewStdEncrypter(c), 0o600))
+
+		data, err := ReadFileDecrypted(path, aes.NewStdDecrypter(c))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("new"), data)
+	})
+
+	t.Run("encryption error keeps existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.bin")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+
+		assert.EqualError(t, WriteFileEncrypted(path, []byte("new"), failingEncrypter{}, 0o600), "encrypt error")
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("old"), raw)
+	})
+
+	t.Run("rename error removes temp file", func(t *testing.T) {
+		// A non-empty directory cannot be replaced by a file
+		dir := t.TempDir()
+		path := filepath.Join(dir, "secret.bin")
+		require.NoError(t, os.MkdirAll(filepath.Join(path, "child"), 0o700))
+
+		err := WriteFileEncrypted(path, []byte("hello"), aes.NewStdEncrypter(c), 0o600)
+		assert.Error(t, err)
+		assert.Empty(t, tempFiles(t, dir))
+		assert.DirExists(t, path)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "secret.bin")
+		assert.ErrorIs(t, WriteFileEncrypted(path, []byte("hello"), aes.NewStdEncrypter(c), 0o600), os.ErrNotExist)
+	})
+}
+
+func TestReadFileDecrypted(t *testing.T) {
+	c := newAesCipher()
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := ReadFileDecrypted(filepath.Join(t.TempDir(), "missing.bin"), aes.NewStdDecrypter(c))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("invalid ciphertext", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.bin")
+		require.NoError(t, os.WriteFile(path, []byte("short"), 0o600))
+		_, err := ReadFileDecrypted(path, aes.NewStdDecrypter(c))
+		assert.Error(t, err)
+	})
+}
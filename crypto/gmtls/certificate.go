@@ -0,0 +1,213 @@
+package gmtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/dromara/dongle/coding/asn1util"
+	"github.com/dromara/dongle/crypto/internal/sm2"
+)
+
+var (
+	// OIDSignatureSM2WithSM3 identifies the SM3withSM2 signature algorithm (GM/T 0006-2012).
+	OIDSignatureSM2WithSM3 = asn1util.MustOID("SM2-SM3")
+
+	// oidExtensionKeyUsage identifies the X.509 key usage extension.
+	oidExtensionKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 15}
+)
+
+// encUsage is the set of key usages that mark an encryption certificate.
+const encUsage = x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageKeyAgreement
+
+// certificate mirrors the outer X.509 Certificate structure.
+type certificate struct {
+	TBSCertificate     tbsCertificate
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+// tbsCertificate mirrors the X.509 TBSCertificate structure.
+type tbsCertificate struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           validity
+	Subject            asn1.RawValue
+	PublicKey          publicKeyInfo
+	UniqueId           asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueId    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"omitempty,optional,explicit,tag:3"`
+}
+
+// validity mirrors the X.509 Validity structure.
+type validity struct {
+	NotBefore, NotAfter time.Time
+}
+
+// publicKeyInfo mirrors the SubjectPublicKeyInfo structure.
+type publicKeyInfo struct {
+	Raw       asn1.RawContent
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// Certificate represents an X.509 certificate carrying an SM2 public key.
+// The standard library refuses certificates on the SM2 curve, so the fields
+// needed by GMTLS handshakes are decoded here instead.
+type Certificate struct {
+	Raw                     []byte // Complete ASN.1 DER content
+	RawTBSCertificate       []byte // Certificate part of raw ASN.1 DER content
+	RawSubjectPublicKeyInfo []byte // DER encoded SubjectPublicKeyInfo
+	RawSubject              []byte // DER encoded Subject
+	RawIssuer               []byte // DER encoded Issuer
+
+	Version            int
+	SerialNumber       *big.Int
+	Issuer             pkix.Name
+	Subject            pkix.Name
+	NotBefore          time.Time
+	NotAfter           time.Time
+	KeyUsage           x509.KeyUsage
+	PublicKey          *ecdsa.PublicKey
+	SignatureAlgorithm asn1.ObjectIdentifier
+	Signature          []byte
+}
+
+// ParseCertificate parses a single DER encoded SM2 certificate.
+func ParseCertificate(der []byte) (*Certificate, error) {
+	var cert certificate
+	rest, err := asn1.Unmarshal(der, &cert)
+	if err != nil {
+		return nil, InvalidCertificateError{Err: err}
+	}
+	if len(rest) > 0 {
+		return nil, InvalidCertificateError{Err: asn1.SyntaxError{Msg: "trailing data"}}
+	}
+
+	tbs := cert.TBSCertificate
+	pub, err := sm2.ParseSPKIPublicKey(tbs.PublicKey.Raw)
+	if err != nil {
+		return nil, UnsupportedPublicKeyError{Err: err}
+	}
+
+	c := &Certificate{
+		Raw:                     der,
+		RawTBSCertificate:       tbs.Raw,
+		RawSubjectPublicKeyInfo: tbs.PublicKey.Raw,
+		RawSubject:              tbs.Subject.FullBytes,
+		RawIssuer:               tbs.Issuer.FullBytes,
+		Version:                 tbs.Version + 1,
+		SerialNumber:            tbs.SerialNumber,
+		NotBefore:               tbs.Validity.NotBefore,
+		NotAfter:                tbs.Validity.NotAfter,
+		PublicKey:               pub,
+		SignatureAlgorithm:      cert.SignatureAlgorithm.Algorithm,
+		Signature:               cert.SignatureValue.RightAlign(),
+	}
+	if err = parseName(tbs.Issuer.FullBytes, &c.Issuer); err != nil {
+		return nil, InvalidCertificateError{Err: err}
+	}
+	if err = parseName(tbs.Subject.FullBytes, &c.Subject); err != nil {
+		return nil, InvalidCertificateError{Err: err}
+	}
+	for _, ext := range tbs.Extensions {
+		if !ext.Id.Equal(oidExtensionKeyUsage) {
+			continue
+		}
+		var bits asn1.BitString
+		if _, err = asn1.Unmarshal(ext.Value, &bits); err != nil {
+			return nil, InvalidCertificateError{Err: err}
+		}
+		for i := 0; i < 9; i++ {
+			if bits.At(i) != 0 {
+				c.KeyUsage |= 1 << uint(i)
+			}
+		}
+	}
+	return c, nil
+}
+
+// ParseCertificatePEM parses the first "CERTIFICATE" block of PEM encoded data.
+func ParseCertificatePEM(data []byte) (*Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, InvalidCertificateError{}
+	}
+	return ParseCertificate(block.Bytes)
+}
+
+// IsSignCert reports whether the certificate is meant for signing,
+// i.e. it allows digital signatures and no key encipherment.
+func (c *Certificate) IsSignCert() bool {
+	return c.KeyUsage&x509.KeyUsageDigitalSignature != 0 && c.KeyUsage&encUsage == 0
+}
+
+// IsEncCert reports whether the certificate is meant for key exchange,
+// i.e. it allows key or data encipherment or key agreement.
+func (c *Certificate) IsEncCert() bool {
+	return c.KeyUsage&encUsage != 0
+}
+
+// CheckSignature verifies signature over signed with the certificate public key
+// using SM3withSM2 and the default user identifier.
+func (c *Certificate) CheckSignature(signed, signature []byte) error {
+	if !sm2.VerifyWithPublicKey(c.PublicKey, signed, nil, signature, 0) {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
+
+// CheckSignatureFrom verifies that the signature on c is a valid signature from parent.
+func (c *Certificate) CheckSignatureFrom(parent *Certificate) error {
+	if !c.SignatureAlgorithm.Equal(OIDSignatureSM2WithSM3) {
+		return UnsupportedSignatureAlgorithmError{Algorithm: c.SignatureAlgorithm}
+	}
+	return parent.CheckSignature(c.RawTBSCertificate, c.Signature)
+}
+
+// CertificatePair represents the dual certificates a GMTLS server presents:
+// one certificate for signing and one for key exchange.
+type CertificatePair struct {
+	Sign *Certificate // Signing certificate
+	Enc  *Certificate // Encryption certificate
+}
+
+// NewCertificatePair classifies two certificates into a signing and an
+// encryption certificate by their key usage, in either order.
+func NewCertificatePair(a, b *Certificate) (*CertificatePair, error) {
+	if a == nil || b == nil {
+		return nil, InvalidCertificatePairError{}
+	}
+	switch {
+	case a.IsSignCert() && b.IsEncCert():
+		return &CertificatePair{Sign: a, Enc: b}, nil
+	case b.IsSignCert() && a.IsEncCert():
+		return &CertificatePair{Sign: b, Enc: a}, nil
+	}
+	return nil, InvalidCertificatePairError{}
+}
+
+// CheckSignatureFrom verifies that both certificates are issued by parent.
+func (p *CertificatePair) CheckSignatureFrom(parent *Certificate) error {
+	if err := p.Sign.CheckSignatureFrom(parent); err != nil {
+		return err
+	}
+	return p.Enc.CheckSignatureFrom(parent)
+}
+
+// parseName decodes a DER encoded RDNSequence into name.
+func parseName(der []byte, name *pkix.Name) error {
+	var rdn pkix.RDNSequence
+	if _, err := asn1.Unmarshal(der, &rdn); err != nil {
+		return err
+	}
+	name.FillFromRDNSequence(&rdn)
+	return nil
+}
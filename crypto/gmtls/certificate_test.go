@@ -0,0 +1,238 @@
+package gmtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKey generates a fresh SM2 private key.
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	c := sm2.NewCurve()
+	d, err := sm2.RandScalar(c, rand.Reader)
+	require.NoError(t, err)
+	x, y := c.ScalarBaseMult(d.Bytes())
+	return &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: c, X: x, Y: y}, D: d}
+}
+
+// newCert issues an SM3withSM2 certificate for pub signed by signer.
+func newCert(t *testing.T, cn string, usage x509.KeyUsage, pub *ecdsa.PublicKey, issuer string, signer *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	spki, err := sm2.MarshalSPKIPublicKey(pub)
+	require.NoError(t, err)
+	subject, err := asn1.Marshal(pkix.Name{CommonName: cn, Country: []string{"CN"}}.ToRDNSequence())
+	require.NoError(t, err)
+	issuerName, err := asn1.Marshal(pkix.Name{CommonName: issuer, Country: []string{"CN"}}.ToRDNSequence())
+	require.NoError(t, err)
+
+	var exts []pkix.Extension
+	if usage != 0 {
+		var bits []byte
+		for i := 0; i < 9; i++ {
+			if usage&(1<<uint(i)) != 0 {
+				for len(bits) <= i/8 {
+					bits = append(bits, 0)
+				}
+				bits[i/8] |= 0x80 >> uint(i%8)
+			}
+		}
+		value, err := asn1.Marshal(asn1.BitString{Bytes: bits, BitLength: len(bits) * 8})
+		require.NoError(t, err)
+		exts = append(exts, pkix.Extension{Id: oidExtensionKeyUsage, Critical: true, Value: value})
+	}
+
+	alg := pkix.AlgorithmIdentifier{Algorithm: OIDSignatureSM2WithSM3}
+	tbs, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       big.NewInt(1024),
+		SignatureAlgorithm: alg,
+		Issuer:             asn1.RawValue{FullBytes: issuerName},
+		Validity: validity{
+			NotBefore: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			NotAfter:  time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Subject:    asn1.RawValue{FullBytes: subject},
+		PublicKey:  publicKeyInfo{Raw: spki},
+		Extensions: exts,
+	})
+	require.NoError(t, err)
+
+	sig, err := sm2.SignWithPrivateKey(signer, tbs, nil, 0)
+	require.NoError(t, err)
+	der, err := asn1.Marshal(struct {
+		TBS       asn1.RawValue
+		Algorithm pkix.AlgorithmIdentifier
+		Signature asn1.BitString
+	}{asn1.RawValue{FullBytes: tbs}, alg, asn1.BitString{Bytes: sig, BitLength: len(sig) * 8}})
+	require.NoError(t, err)
+	return der
+}
+
+func TestParseCertificate(t *testing.T) {
+	caKey := newKey(t)
+	key := newKey(t)
+	der := newCert(t, "server", x509.KeyUsageDigitalSignature, &key.PublicKey, "root", caKey)
+
+	t.Run("fields", func(t *testing.T) {
+		cert, err := ParseCertificate(der)
+		require.NoError(t, err)
+		assert.Equal(t, der, cert.Raw)
+		assert.Equal(t, 3, cert.Version)
+		assert.Equal(t, int64(1024), cert.SerialNumber.Int64())
+		assert.Equal(t, "server", cert.Subject.CommonName)
+		assert.Equal(t, "root", cert.Issuer.CommonName)
+		assert.Equal(t, []string{"CN"}, cert.Subject.Country)
+		assert.Equal(t, 2024, cert.NotBefore.Year())
+		assert.Equal(t, 2034, cert.NotAfter.Year())
+		assert.Equal(t, x509.KeyUsageDigitalSignature, cert.KeyUsage)
+		assert.True(t, cert.SignatureAlgorithm.Equal(OIDSignatureSM2WithSM3))
+		assert.Equal(t, 0, key.PublicKey.X.Cmp(cert.PublicKey.X))
+		assert.Equal(t, 0, key.PublicKey.Y.Cmp(cert.PublicKey.Y))
+		assert.NotEmpty(t, cert.RawTBSCertificate)
+		assert.NotEmpty(t, cert.RawSubjectPublicKeyInfo)
+	})
+
+	t.Run("pem", func(t *testing.T) {
+		cert, err := ParseCertificatePEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		require.NoError(t, err)
+		assert.Equal(t, "server", cert.Subject.CommonName)
+	})
+
+	t.Run("invalid pem", func(t *testing.T) {
+		_, err := ParseCertificatePEM([]byte("not pem"))
+		assert.IsType(t, InvalidCertificateError{}, err)
+		assert.Equal(t, "crypto/gmtls: invalid certificate", err.Error())
+	})
+
+	t.Run("invalid der", func(t *testing.T) {
+		_, err := ParseCertificate([]byte{0x30, 0x01})
+		assert.IsType(t, InvalidCertificateError{}, err)
+		assert.Contains(t, err.Error(), "crypto/gmtls: invalid certificate: ")
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		_, err := ParseCertificate(append(append([]byte{}, der...), 0x00))
+		assert.IsType(t, InvalidCertificateError{}, err)
+	})
+
+	t.Run("non sm2 key", func(t *testing.T) {
+		std, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		spki, err := x509.MarshalPKIXPublicKey(&std.PublicKey)
+		require.NoError(t, err)
+		tbs, err := asn1.Marshal(tbsCertificate{
+			SerialNumber:       big.NewInt(1),
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: OIDSignatureSM2WithSM3},
+			Issuer:             asn1.RawValue{FullBytes: []byte{0x30, 0x00}},
+			Validity:           validity{NotBefore: time.Unix(0, 0).UTC(), NotAfter: time.Unix(0, 0).UTC()},
+			Subject:            asn1.RawValue{FullBytes: []byte{0x30, 0x00}},
+			PublicKey:          publicKeyInfo{Raw: spki},
+		})
+		require.NoError(t, err)
+		der, err := asn1.Marshal(struct {
+			TBS       asn1.RawValue
+			Algorithm pkix.AlgorithmIdentifier
+			Signature asn1.BitString
+		}{asn1.RawValue{FullBytes: tbs}, pkix.AlgorithmIdentifier{Algorithm: OIDSignatureSM2WithSM3}, asn1.BitString{}})
+		require.NoError(t, err)
+
+		_, err = ParseCertificate(der)
+		assert.IsType(t, UnsupportedPublicKeyError{}, err)
+		assert.Contains(t, err.Error(), "crypto/gmtls: certificate public key is not an SM2 key")
+	})
+}
+
+func TestCertificate_CheckSignatureFrom(t *testing.T) {
+	caKey := newKey(t)
+	ca, err := ParseCertificate(newCert(t, "root", x509.KeyUsageCertSign, &caKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+	key := newKey(t)
+	cert, err := ParseCertificate(newCert(t, "server", x509.KeyUsageDigitalSignature, &key.PublicKey, "root", caKey))
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, ca.CheckSignatureFrom(ca))
+		assert.NoError(t, cert.CheckSignatureFrom(ca))
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		err := ca.CheckSignatureFrom(cert)
+		assert.Equal(t, SignatureVerificationError{}, err)
+		assert.Equal(t, "crypto/gmtls: signature verification failed", err.Error())
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		c := *cert
+		c.SignatureAlgorithm = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+		err := c.CheckSignatureFrom(ca)
+		assert.IsType(t, UnsupportedSignatureAlgorithmError{}, err)
+		assert.Equal(t, "crypto/gmtls: unsupported signature algorithm 1.2.840.10045.4.3.2, only SM3withSM2 is supported", err.Error())
+	})
+}
+
+func TestNewCertificatePair(t *testing.T) {
+	caKey := newKey(t)
+	ca, err := ParseCertificate(newCert(t, "root", x509.KeyUsageCertSign, &caKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+	signKey, encKey := newKey(t), newKey(t)
+	sign, err := ParseCertificate(newCert(t, "sign", x509.KeyUsageDigitalSignature|x509.KeyUsageContentCommitment, &signKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+	enc, err := ParseCertificate(newCert(t, "enc", x509.KeyUsageKeyEncipherment|x509.KeyUsageDataEncipherment|x509.KeyUsageKeyAgreement, &encKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+
+	t.Run("classify", func(t *testing.T) {
+		assert.True(t, sign.IsSignCert())
+		assert.False(t, sign.IsEncCert())
+		assert.True(t, enc.IsEncCert())
+		assert.False(t, enc.IsSignCert())
+	})
+
+	t.Run("either order", func(t *testing.T) {
+		pair, err := NewCertificatePair(sign, enc)
+		require.NoError(t, err)
+		assert.Same(t, sign, pair.Sign)
+		assert.Same(t, enc, pair.Enc)
+
+		pair, err = NewCertificatePair(enc, sign)
+		require.NoError(t, err)
+		assert.Same(t, sign, pair.Sign)
+		assert.Same(t, enc, pair.Enc)
+		assert.NoError(t, pair.CheckSignatureFrom(ca))
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		pair, err := NewCertificatePair(sign, enc)
+		require.NoError(t, err)
+		assert.Equal(t, SignatureVerificationError{}, pair.CheckSignatureFrom(sign))
+
+		other, err := ParseCertificate(newCert(t, "enc", x509.KeyUsageKeyEncipherment, &encKey.PublicKey, "root", signKey))
+		require.NoError(t, err)
+		pair, err = NewCertificatePair(sign, other)
+		require.NoError(t, err)
+		assert.Equal(t, SignatureVerificationError{}, pair.CheckSignatureFrom(ca))
+	})
+
+	t.Run("invalid pair", func(t *testing.T) {
+		_, err := NewCertificatePair(sign, sign)
+		assert.Equal(t, InvalidCertificatePairError{}, err)
+		assert.Equal(t, "crypto/gmtls: certificates must be one signing and one encryption certificate", err.Error())
+		_, err = NewCertificatePair(enc, enc)
+		assert.Equal(t, InvalidCertificatePairError{}, err)
+		_, err = NewCertificatePair(sign, nil)
+		assert.Equal(t, InvalidCertificatePairError{}, err)
+		_, err = NewCertificatePair(ca, enc)
+		assert.Equal(t, InvalidCertificatePairError{}, err)
+	})
+}
@@ -0,0 +1,295 @@
+package gmtls
+
+import (
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidCertificateError represents an error when a certificate cannot be decoded.
+type InvalidCertificateError struct {
+	Err error // Underlying error from ASN.1 or PEM decoding
+}
+
+// Error returns a formatted error message describing the invalid certificate.
+func (e InvalidCertificateError) Error() string {
+	if e.Err == nil {
+		return "crypto/gmtls: invalid certificate"
+	}
+	return fmt.Sprintf("crypto/gmtls: invalid certificate: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-GMTLS-001.
+func (e InvalidCertificateError) Code() string {
+	return "DGL-GMTLS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCertificateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedPublicKeyError represents an error when a certificate does not carry an SM2 public key.
+type UnsupportedPublicKeyError struct {
+	Err error // Underlying error from public key parsing
+}
+
+// Error returns a formatted error message describing the unsupported public key.
+func (e UnsupportedPublicKeyError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: certificate public key is not an SM2 key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-GMTLS-002.
+func (e UnsupportedPublicKeyError) Code() string {
+	return "DGL-GMTLS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedPublicKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "key", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedSignatureAlgorithmError represents an error when a certificate is not signed with SM3withSM2.
+type UnsupportedSignatureAlgorithmError struct {
+	Algorithm asn1.ObjectIdentifier // The certificate signature algorithm
+}
+
+// Error returns a formatted error message describing the unsupported signature algorithm.
+func (e UnsupportedSignatureAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: unsupported signature algorithm %s, only SM3withSM2 is supported", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-GMTLS-003.
+func (e UnsupportedSignatureAlgorithmError) Code() string {
+	return "DGL-GMTLS-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedSignatureAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "verify", errcode.FieldAlgorithm, e.Algorithm.String())
+}
+
+// SignatureVerificationError represents an error when an SM2 signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the signature verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/gmtls: signature verification failed"
+}
+
+// Code returns the stable error code DGL-GMTLS-004.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-GMTLS-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "verify")
+}
+
+// InvalidCertificatePairError represents an error when two certificates do not form a sign/enc pair.
+type InvalidCertificatePairError struct{}
+
+// Error returns a formatted error message describing the invalid certificate pair.
+func (e InvalidCertificatePairError) Error() string {
+	return "crypto/gmtls: certificates must be one signing and one encryption certificate"
+}
+
+// Code returns the stable error code DGL-GMTLS-005.
+func (e InvalidCertificatePairError) Code() string {
+	return "DGL-GMTLS-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCertificatePairError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "")
+}
+
+// InvalidPreMasterSecretError represents an error when a pre-master secret has an invalid size or version.
+type InvalidPreMasterSecretError struct {
+	Size    int    // The pre-master secret size
+	Version uint16 // The pre-master secret version, zero when the size is invalid
+}
+
+// Error returns a formatted error message describing the invalid pre-master secret.
+func (e InvalidPreMasterSecretError) Error() string {
+	if e.Size != PreMasterSecretSize {
+		return fmt.Sprintf("crypto/gmtls: invalid pre-master secret size %d, must be %d", e.Size, PreMasterSecretSize)
+	}
+	return fmt.Sprintf("crypto/gmtls: invalid pre-master secret version 0x%04x, must be 0x%04x", e.Version, VersionGMTLS)
+}
+
+// Code returns the stable error code DGL-GMTLS-006.
+func (e InvalidPreMasterSecretError) Code() string {
+	return "DGL-GMTLS-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPreMasterSecretError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "", "size", e.Size, "version", e.Version)
+}
+
+// InvalidMasterSecretError represents an error when a master secret has an invalid size.
+type InvalidMasterSecretError struct {
+	Size int // The master secret size
+}
+
+// Error returns a formatted error message describing the invalid master secret size.
+func (e InvalidMasterSecretError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: invalid master secret size %d, must be %d", e.Size, MasterSecretSize)
+}
+
+// Code returns the stable error code DGL-GMTLS-007.
+func (e InvalidMasterSecretError) Code() string {
+	return "DGL-GMTLS-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidMasterSecretError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "", "size", e.Size)
+}
+
+// InvalidRandomError represents an error when a client or server random has an invalid size.
+type InvalidRandomError struct {
+	Size int // The random value size
+}
+
+// Error returns a formatted error message describing the invalid random size.
+func (e InvalidRandomError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: invalid random size %d, must be %d", e.Size, RandomSize)
+}
+
+// Code returns the stable error code DGL-GMTLS-008.
+func (e InvalidRandomError) Code() string {
+	return "DGL-GMTLS-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidRandomError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "random", "size", e.Size)
+}
+
+// RandomError represents an error when reading from the random source fails.
+type RandomError struct {
+	Err error // Underlying error from the random source
+}
+
+// Error returns a formatted error message describing the random source failure.
+func (e RandomError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: failed to read random bytes: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-GMTLS-009.
+func (e RandomError) Code() string {
+	return "DGL-GMTLS-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RandomError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "random", errcode.FieldCause, e.Err)
+}
+
+// EncryptError represents an error when SM2 encryption of the pre-master secret fails.
+type EncryptError struct {
+	Err error // Underlying error from SM2 encryption
+}
+
+// Error returns a formatted error message describing the encryption failure.
+func (e EncryptError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: failed to encrypt pre-master secret: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-GMTLS-010.
+func (e EncryptError) Code() string {
+	return "DGL-GMTLS-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "encrypt", errcode.FieldCause, e.Err)
+}
+
+// DecryptError represents an error when SM2 decryption of the pre-master secret fails.
+type DecryptError struct {
+	Err error // Underlying error from SM2 decryption
+}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: failed to decrypt pre-master secret: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-GMTLS-011.
+func (e DecryptError) Code() string {
+	return "DGL-GMTLS-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "decrypt", errcode.FieldCause, e.Err)
+}
+
+// SignError represents an error when SM2 signing fails.
+type SignError struct {
+	Err error // Underlying error from SM2 signing
+}
+
+// Error returns a formatted error message describing the signing failure.
+func (e SignError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: failed to sign: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-GMTLS-012.
+func (e SignError) Code() string {
+	return "DGL-GMTLS-012"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "sign", errcode.FieldCause, e.Err)
+}
+
+// InvalidLengthError represents an error when a PRF output length is negative.
+type InvalidLengthError struct {
+	Length int // The requested output length
+}
+
+// Error returns a formatted error message describing the invalid length.
+func (e InvalidLengthError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: invalid PRF output length %d, must not be negative", e.Length)
+}
+
+// Code returns the stable error code DGL-GMTLS-013.
+func (e InvalidLengthError) Code() string {
+	return "DGL-GMTLS-013"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidLengthError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "prf", "length", e.Length)
+}
+
+// InvalidCipherSuiteError represents an error when a cipher suite has a
+// negative key material length, or lengths whose key block overflows.
+type InvalidCipherSuiteError struct {
+	Name   string // The cipher suite name
+	MACLen int    // The MAC key length
+	KeyLen int    // The encryption key length
+	IVLen  int    // The IV length
+}
+
+// Error returns a formatted error message describing the invalid cipher suite.
+func (e InvalidCipherSuiteError) Error() string {
+	return fmt.Sprintf("crypto/gmtls: invalid key material lengths of cipher suite %q: mac %d, key %d, iv %d", e.Name, e.MACLen, e.KeyLen, e.IVLen)
+}
+
+// Code returns the stable error code DGL-GMTLS-014.
+func (e InvalidCipherSuiteError) Code() string {
+	return "DGL-GMTLS-014"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCipherSuiteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/gmtls", "TLCP", "prf", "suite", e.Name, "mac_len", e.MACLen, "key_len", e.KeyLen, "iv_len", e.IVLen)
+}
@@ -0,0 +1,125 @@
+// Package gmtls provides the building blocks needed for GMTLS (GM/T 0024-2014) integrations.
+// It covers SM2 dual certificate parsing, ECC_SM4_SM3 pre-master secret encryption,
+// ServerKeyExchange signatures and SM3 based key material derivation, so custom
+// GMTLS handshakes can be assembled on top of dongle primitives.
+package gmtls
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"io"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+)
+
+// VersionGMTLS is the protocol version of GMTLS 1.1.
+const VersionGMTLS uint16 = 0x0101
+
+const (
+	// RandomSize is the size of the client and server random values.
+	RandomSize = 32
+	// PreMasterSecretSize is the size of the ECC pre-master secret.
+	PreMasterSecretSize = 48
+	// MasterSecretSize is the size of the master secret.
+	MasterSecretSize = 48
+	// VerifyDataSize is the size of the Finished verify data.
+	VerifyDataSize = 12
+)
+
+// GeneratePreMasterSecret generates an ECC pre-master secret made of the
+// client version followed by 46 random bytes read from random.
+func GeneratePreMasterSecret(random io.Reader) ([]byte, error) {
+	pms := make([]byte, PreMasterSecretSize)
+	binary.BigEndian.PutUint16(pms, VersionGMTLS)
+	if _, err := io.ReadFull(random, pms[2:]); err != nil {
+		return nil, RandomError{Err: err}
+	}
+	return pms, nil
+}
+
+// EncryptPreMasterSecret encrypts the pre-master secret with the public key of
+// the server encryption certificate, producing the ASN.1 encoded SM2 ciphertext
+// carried by the ClientKeyExchange message.
+func EncryptPreMasterSecret(enc *Certificate, pms []byte) ([]byte, error) {
+	if err := checkPreMasterSecret(pms); err != nil {
+		return nil, err
+	}
+	ct, err := sm2.EncryptWithPublicKey(enc.PublicKey, pms, 4, "asn1_c1c3c2")
+	if err != nil {
+		return nil, EncryptError{Err: err}
+	}
+	return ct, nil
+}
+
+// DecryptPreMasterSecret decrypts the ClientKeyExchange ciphertext with the
+// private key matching the server encryption certificate.
+func DecryptPreMasterSecret(enc *ecdsa.PrivateKey, ct []byte) ([]byte, error) {
+	pms, err := sm2.DecryptWithPrivateKey(enc, ct, 4, "asn1_c1c3c2")
+	if err != nil {
+		return nil, DecryptError{Err: err}
+	}
+	if err = checkPreMasterSecret(pms); err != nil {
+		return nil, err
+	}
+	return pms, nil
+}
+
+// SignServerKeyExchange signs the ECC ServerKeyExchange parameters with the
+// private key of the server signing certificate. The signed content is the
+// client random, the server random and the length prefixed encryption certificate.
+func SignServerKeyExchange(sign *ecdsa.PrivateKey, clientRandom, serverRandom []byte, enc *Certificate) ([]byte, error) {
+	params, err := serverKeyExchangeParams(clientRandom, serverRandom, enc)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sm2.SignWithPrivateKey(sign, params, nil, 0)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+	return sig, nil
+}
+
+// VerifyServerKeyExchange verifies a ServerKeyExchange signature against the
+// server signing certificate.
+func VerifyServerKeyExchange(sign *Certificate, clientRandom, serverRandom []byte, enc *Certificate, sig []byte) error {
+	params, err := serverKeyExchangeParams(clientRandom, serverRandom, enc)
+	if err != nil {
+		return err
+	}
+	return sign.CheckSignature(params, sig)
+}
+
+// serverKeyExchangeParams assembles the signed ServerKeyExchange parameters.
+func serverKeyExchangeParams(clientRandom, serverRandom []byte, enc *Certificate) ([]byte, error) {
+	if err := checkRandoms(clientRandom, serverRandom); err != nil {
+		return nil, err
+	}
+	n := len(enc.Raw)
+	params := make([]byte, 0, 2*RandomSize+3+n)
+	params = append(params, clientRandom...)
+	params = append(params, serverRandom...)
+	params = append(params, byte(n>>16), byte(n>>8), byte(n))
+	return append(params, enc.Raw...), nil
+}
+
+// checkPreMasterSecret validates the size and version of a pre-master secret.
+func checkPreMasterSecret(pms []byte) error {
+	if len(pms) != PreMasterSecretSize {
+		return InvalidPreMasterSecretError{Size: len(pms)}
+	}
+	if v := binary.BigEndian.Uint16(pms); v != VersionGMTLS {
+		return InvalidPreMasterSecretError{Size: len(pms), Version: v}
+	}
+	return nil
+}
+
+// checkRandoms validates the size of the client and server random values.
+func checkRandoms(clientRandom, serverRandom []byte) error {
+	if len(clientRandom) != RandomSize {
+		return InvalidRandomError{Size: len(clientRandom)}
+	}
+	if len(serverRandom) != RandomSize {
+		return InvalidRandomError{Size: len(serverRandom)}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package gmtls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreMasterSecret(t *testing.T) {
+	caKey := newKey(t)
+	encKey := newKey(t)
+	enc, err := ParseCertificate(newCert(t, "enc", x509.KeyUsageKeyEncipherment, &encKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		pms, err := GeneratePreMasterSecret(rand.Reader)
+		require.NoError(t, err)
+		assert.Len(t, pms, PreMasterSecretSize)
+		assert.Equal(t, []byte{0x01, 0x01}, pms[:2])
+
+		ct, err := EncryptPreMasterSecret(enc, pms)
+		require.NoError(t, err)
+		assert.Equal(t, byte(0x30), ct[0])
+
+		got, err := DecryptPreMasterSecret(encKey, ct)
+		require.NoError(t, err)
+		assert.Equal(t, pms, got)
+	})
+
+	t.Run("random error", func(t *testing.T) {
+		_, err := GeneratePreMasterSecret(bytes.NewReader(make([]byte, 10)))
+		assert.IsType(t, RandomError{}, err)
+		assert.Contains(t, err.Error(), "crypto/gmtls: failed to read random bytes")
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := EncryptPreMasterSecret(enc, make([]byte, 47))
+		assert.Equal(t, InvalidPreMasterSecretError{Size: 47}, err)
+		assert.Equal(t, "crypto/gmtls: invalid pre-master secret size 47, must be 48", err.Error())
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		pms := make([]byte, PreMasterSecretSize)
+		pms[0], pms[1] = 0x03, 0x03
+		_, err := EncryptPreMasterSecret(enc, pms)
+		assert.Equal(t, InvalidPreMasterSecretError{Size: 48, Version: 0x0303}, err)
+		assert.Equal(t, "crypto/gmtls: invalid pre-master secret version 0x0303, must be 0x0101", err.Error())
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		pms, err := GeneratePreMasterSecret(rand.Reader)
+		require.NoError(t, err)
+		ct, err := EncryptPreMasterSecret(enc, pms)
+		require.NoError(t, err)
+		_, err = DecryptPreMasterSecret(caKey, ct)
+		assert.IsType(t, DecryptError{}, err)
+		assert.Contains(t, err.Error(), "crypto/gmtls: failed to decrypt pre-master secret")
+	})
+
+	t.Run("error reader", func(t *testing.T) {
+		_, err := GeneratePreMasterSecret(mock.NewErrorFile(errors.New("read error")))
+		assert.IsType(t, RandomError{}, err)
+	})
+}
+
+func TestServerKeyExchange(t *testing.T) {
+	caKey := newKey(t)
+	signKey, encKey := newKey(t), newKey(t)
+	sign, err := ParseCertificate(newCert(t, "sign", x509.KeyUsageDigitalSignature, &signKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+	enc, err := ParseCertificate(newCert(t, "enc", x509.KeyUsageKeyEncipherment, &encKey.PublicKey, "root", caKey))
+	require.NoError(t, err)
+	clientRandom := bytes.Repeat([]byte{0xc1}, RandomSize)
+	serverRandom := bytes.Repeat([]byte{0x5e}, RandomSize)
+
+	t.Run("sign and verify", func(t *testing.T) {
+		sig, err := SignServerKeyExchange(signKey, clientRandom, serverRandom, enc)
+		require.NoError(t, err)
+		assert.NoError(t, VerifyServerKeyExchange(sign, clientRandom, serverRandom, enc, sig))
+	})
+
+	t.Run("tampered params", func(t *testing.T) {
+		sig, err := SignServerKeyExchange(signKey, clientRandom, serverRandom, enc)
+		require.NoError(t, err)
+		err = VerifyServerKeyExchange(sign, serverRandom, clientRandom, enc, sig)
+		assert.Equal(t, SignatureVerificationError{}, err)
+		err = VerifyServerKeyExchange(sign, clientRandom, serverRandom, sign, sig)
+		assert.Equal(t, SignatureVerificationError{}, err)
+	})
+
+	t.Run("invalid random", func(t *testing.T) {
+		_, err := SignServerKeyExchange(signKey, clientRandom[:31], serverRandom, enc)
+		assert.Equal(t, InvalidRandomError{Size: 31}, err)
+		assert.Equal(t, "crypto/gmtls: invalid random size 31, must be 32", err.Error())
+		err = VerifyServerKeyExchange(sign, clientRandom, serverRandom[:1], enc, nil)
+		assert.Equal(t, InvalidRandomError{Size: 1}, err)
+	})
+
+	t.Run("invalid private key", func(t *testing.T) {
+		bad := *signKey
+		bad.D = bad.Params().N
+		_, err := SignServerKeyExchange(&bad, clientRandom, serverRandom, enc)
+		assert.IsType(t, SignError{}, err)
+		assert.Contains(t, err.Error(), "crypto/gmtls: failed to sign")
+	})
+}
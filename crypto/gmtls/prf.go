@@ -0,0 +1,143 @@
+package gmtls
+
+import (
+	"crypto/hmac"
+	"math"
+
+	"github.com/dromara/dongle/hash/sm3"
+)
+
+// CipherSuite describes the key material layout of a GMTLS cipher suite.
+type CipherSuite struct {
+	ID     uint16 // Cipher suite identifier
+	Name   string // Cipher suite name
+	MACLen int    // Size of each MAC key, zero for AEAD suites
+	KeyLen int    // Size of each encryption key
+	IVLen  int    // Size of each IV, the implicit nonce part for AEAD suites
+}
+
+var (
+	// ECC_SM4_CBC_SM3 is the ECC key exchange suite with SM4-CBC and HMAC-SM3.
+	ECC_SM4_CBC_SM3 = CipherSuite{ID: 0xe013, Name: "ECC_SM4_CBC_SM3", MACLen: 32, KeyLen: 16, IVLen: 16}
+	// ECC_SM4_GCM_SM3 is the ECC key exchange suite with SM4-GCM.
+	ECC_SM4_GCM_SM3 = CipherSuite{ID: 0xe053, Name: "ECC_SM4_GCM_SM3", MACLen: 0, KeyLen: 16, IVLen: 4}
+)
+
+// KeyMaterial holds the connection keys expanded from the master secret.
+type KeyMaterial struct {
+	ClientMACKey []byte
+	ServerMACKey []byte
+	ClientKey    []byte
+	ServerKey    []byte
+	ClientIV     []byte
+	ServerIV     []byte
+}
+
+// PRF implements the GMTLS pseudo random function P_SM3(secret, label + seed),
+// the TLS 1.2 P_hash construction instantiated with HMAC-SM3.
+func PRF(secret []byte, label string, seed []byte, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, InvalidLengthError{Length: length}
+	}
+	labelSeed := make([]byte, 0, len(label)+len(seed))
+	labelSeed = append(labelSeed, label...)
+	labelSeed = append(labelSeed, seed...)
+
+	out := make([]byte, 0, length+sm3.Size)
+	h := hmac.New(sm3.New, secret)
+	h.Write(labelSeed)
+	a := h.Sum(nil)
+	for len(out) < length {
+		h.Reset()
+		h.Write(a)
+		h.Write(labelSeed)
+		out = h.Sum(out)
+
+		h.Reset()
+		h.Write(a)
+		a = h.Sum(a[:0])
+	}
+	return out[:length], nil
+}
+
+// MasterSecret derives the master secret from the pre-master secret and the
+// client and server random values.
+func MasterSecret(pms, clientRandom, serverRandom []byte) ([]byte, error) {
+	if err := checkPreMasterSecret(pms); err != nil {
+		return nil, err
+	}
+	if err := checkRandoms(clientRandom, serverRandom); err != nil {
+		return nil, err
+	}
+	return PRF(pms, "master secret", concat(clientRandom, serverRandom), MasterSecretSize)
+}
+
+// DeriveKeyMaterial expands the master secret into the key block of suite and
+// splits it into MAC keys, encryption keys and IVs for both directions.
+func DeriveKeyMaterial(suite CipherSuite, masterSecret, clientRandom, serverRandom []byte) (KeyMaterial, error) {
+	if len(masterSecret) != MasterSecretSize {
+		return KeyMaterial{}, InvalidMasterSecretError{Size: len(masterSecret)}
+	}
+	if err := checkRandoms(clientRandom, serverRandom); err != nil {
+		return KeyMaterial{}, err
+	}
+
+	n, err := suite.keyBlockSize()
+	if err != nil {
+		return KeyMaterial{}, err
+	}
+	block, err := PRF(masterSecret, "key expansion", concat(serverRandom, clientRandom), n)
+	if err != nil {
+		return KeyMaterial{}, err
+	}
+	next := func(size int) []byte {
+		b := block[:size:size]
+		block = block[size:]
+		return b
+	}
+
+	var km KeyMaterial
+	km.ClientMACKey = next(suite.MACLen)
+	km.ServerMACKey = next(suite.MACLen)
+	km.ClientKey = next(suite.KeyLen)
+	km.ServerKey = next(suite.KeyLen)
+	km.ClientIV = next(suite.IVLen)
+	km.ServerIV = next(suite.IVLen)
+	return km, nil
+}
+
+// keyBlockSize returns the size of the key block of the suite, two of each
+// MAC key, encryption key and IV.
+func (suite CipherSuite) keyBlockSize() (int, error) {
+	n := 0
+	for _, size := range []int{suite.MACLen, suite.KeyLen, suite.IVLen} {
+		if size < 0 || size > (math.MaxInt/2-n)/2 {
+			return 0, InvalidCipherSuiteError{Name: suite.Name, MACLen: suite.MACLen, KeyLen: suite.KeyLen, IVLen: suite.IVLen}
+		}
+		n += 2 * size
+	}
+	return n, nil
+}
+
+// ClientFinished computes the verify data of the client Finished message,
+// handshakeHash is the SM3 digest of all handshake messages so far.
+func ClientFinished(masterSecret, handshakeHash []byte) []byte {
+	// PRF only fails on a negative length
+	out, _ := PRF(masterSecret, "client finished", handshakeHash, VerifyDataSize)
+	return out
+}
+
+// ServerFinished computes the verify data of the server Finished message,
+// handshakeHash is the SM3 digest of all handshake messages so far.
+func ServerFinished(masterSecret, handshakeHash []byte) []byte {
+	// PRF only fails on a negative length
+	out, _ := PRF(masterSecret, "server finished", handshakeHash, VerifyDataSize)
+	return out
+}
+
+// concat returns a new slice holding a followed by b.
+func concat(a, b []byte) []byte {
+	out := make([]byte, 0, len(a)+len(b))
+	out = append(out, a...)
+	return append(out, b...)
+}
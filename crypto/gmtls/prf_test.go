@@ -0,0 +1,168 @@
+package gmtls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRF(t *testing.T) {
+	secret := []byte("secret")
+	seed := []byte("seed")
+
+	t.Run("known answer", func(t *testing.T) {
+		// The inputs of the TLS 1.2 SHA-256 PRF test vector, with the expected
+		// output of P_SM3 computed with the HMAC-SM3 of OpenSSL 3.0.
+		secret, _ := hex.DecodeString("9bbe436ba940f017b17652849a71db35")
+		seed, _ := hex.DecodeString("a0ba9f936cda311827a6f796ffd5198c")
+		want := "c51bd59022a9886cc166e1745c7fbd98e4751864c4655ff51718c2d1d0b75823" +
+			"36f3bf2cb3ac4b19fdb5f7980504fb6ec9d6089a6d8c85a020fb180945bfad08" +
+			"8c87cde4cd2fd38a2c8acb36a728307d08b2d952b5643823a332835466af0074" +
+			"fea094b3"
+		out, err := PRF(secret, "test label", seed, 100)
+		require.NoError(t, err)
+		assert.Equal(t, want, hex.EncodeToString(out))
+	})
+
+	t.Run("prefix stable", func(t *testing.T) {
+		long, err := PRF(secret, "label", seed, 100)
+		require.NoError(t, err)
+		assert.Len(t, long, 100)
+		short, err := PRF(secret, "label", seed, 13)
+		require.NoError(t, err)
+		assert.Equal(t, long[:13], short)
+		short, err = PRF(secret, "label", seed, 32)
+		require.NoError(t, err)
+		assert.Equal(t, long[:32], short)
+	})
+
+	t.Run("label separation", func(t *testing.T) {
+		a, err := PRF(secret, "a", seed, 32)
+		require.NoError(t, err)
+		b, err := PRF(secret, "b", seed, 32)
+		require.NoError(t, err)
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		out, err := PRF(secret, "label", seed, 0)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("negative length", func(t *testing.T) {
+		out, err := PRF(secret, "label", seed, -1)
+		assert.Nil(t, out)
+		assert.Equal(t, InvalidLengthError{Length: -1}, err)
+		assert.Equal(t, "DGL-GMTLS-013", InvalidLengthError{}.Code())
+	})
+}
+
+func TestMasterSecret(t *testing.T) {
+	pms := append([]byte{0x01, 0x01}, bytes.Repeat([]byte{0xab}, 46)...)
+	clientRandom := bytes.Repeat([]byte{0x01}, RandomSize)
+	serverRandom := bytes.Repeat([]byte{0x02}, RandomSize)
+
+	t.Run("derive", func(t *testing.T) {
+		ms, err := MasterSecret(pms, clientRandom, serverRandom)
+		require.NoError(t, err)
+		assert.Len(t, ms, MasterSecretSize)
+		want, err := PRF(pms, "master secret", append(append([]byte{}, clientRandom...), serverRandom...), 48)
+		require.NoError(t, err)
+		assert.Equal(t, want, ms)
+	})
+
+	t.Run("invalid pre-master secret", func(t *testing.T) {
+		_, err := MasterSecret(pms[:40], clientRandom, serverRandom)
+		assert.Equal(t, InvalidPreMasterSecretError{Size: 40}, err)
+	})
+
+	t.Run("invalid random", func(t *testing.T) {
+		_, err := MasterSecret(pms, clientRandom, nil)
+		assert.Equal(t, InvalidRandomError{Size: 0}, err)
+	})
+}
+
+func TestDeriveKeyMaterial(t *testing.T) {
+	ms := bytes.Repeat([]byte{0x33}, MasterSecretSize)
+	clientRandom := bytes.Repeat([]byte{0x01}, RandomSize)
+	serverRandom := bytes.Repeat([]byte{0x02}, RandomSize)
+
+	t.Run("cbc suite", func(t *testing.T) {
+		km, err := DeriveKeyMaterial(ECC_SM4_CBC_SM3, ms, clientRandom, serverRandom)
+		require.NoError(t, err)
+		block, err := PRF(ms, "key expansion", append(append([]byte{}, serverRandom...), clientRandom...), 128)
+		require.NoError(t, err)
+		assert.Equal(t, block[0:32], km.ClientMACKey)
+		assert.Equal(t, block[32:64], km.ServerMACKey)
+		assert.Equal(t, block[64:80], km.ClientKey)
+		assert.Equal(t, block[80:96], km.ServerKey)
+		assert.Equal(t, block[96:112], km.ClientIV)
+		assert.Equal(t, block[112:128], km.ServerIV)
+
+		// Appending to one key must not overwrite the next one.
+		before := append([]byte{}, km.ServerMACKey...)
+		_ = append(km.ClientMACKey, 0xff)
+		assert.Equal(t, before, km.ServerMACKey)
+	})
+
+	t.Run("gcm suite", func(t *testing.T) {
+		km, err := DeriveKeyMaterial(ECC_SM4_GCM_SM3, ms, clientRandom, serverRandom)
+		require.NoError(t, err)
+		assert.Empty(t, km.ClientMACKey)
+		assert.Empty(t, km.ServerMACKey)
+		assert.Len(t, km.ClientKey, 16)
+		assert.Len(t, km.ServerKey, 16)
+		assert.Len(t, km.ClientIV, 4)
+		assert.Len(t, km.ServerIV, 4)
+		assert.NotEqual(t, km.ClientKey, km.ServerKey)
+	})
+
+	t.Run("invalid master secret", func(t *testing.T) {
+		_, err := DeriveKeyMaterial(ECC_SM4_CBC_SM3, ms[:47], clientRandom, serverRandom)
+		assert.Equal(t, InvalidMasterSecretError{Size: 47}, err)
+		assert.Equal(t, "crypto/gmtls: invalid master secret size 47, must be 48", err.Error())
+	})
+
+	t.Run("invalid random", func(t *testing.T) {
+		_, err := DeriveKeyMaterial(ECC_SM4_CBC_SM3, ms, nil, serverRandom)
+		assert.Equal(t, InvalidRandomError{Size: 0}, err)
+	})
+
+	t.Run("invalid cipher suite", func(t *testing.T) {
+		for _, suite := range []CipherSuite{
+			{Name: "negative mac", MACLen: -1, KeyLen: 20},
+			{Name: "negative key", KeyLen: -16, IVLen: 16},
+			{Name: "negative iv", MACLen: 32, KeyLen: 16, IVLen: -4},
+			{Name: "overflow", MACLen: math.MaxInt / 4, KeyLen: math.MaxInt / 4},
+			{Name: "wrap around", MACLen: math.MaxInt/2 + 1, KeyLen: math.MaxInt/2 + 1},
+		} {
+			_, err := DeriveKeyMaterial(suite, ms, clientRandom, serverRandom)
+			assert.Equal(t, InvalidCipherSuiteError{Name: suite.Name, MACLen: suite.MACLen, KeyLen: suite.KeyLen, IVLen: suite.IVLen}, err, suite.Name)
+		}
+		err := InvalidCipherSuiteError{Name: "custom", MACLen: -1, KeyLen: 20}
+		assert.Equal(t, `crypto/gmtls: invalid key material lengths of cipher suite "custom": mac -1, key 20, iv 0`, err.Error())
+		assert.Equal(t, "DGL-GMTLS-014", err.Code())
+	})
+}
+
+func TestFinished(t *testing.T) {
+	ms := bytes.Repeat([]byte{0x33}, MasterSecretSize)
+	h := sm3.New()
+	h.Write([]byte("handshake messages"))
+	sum := h.Sum(nil)
+
+	client := ClientFinished(ms, sum)
+	server := ServerFinished(ms, sum)
+	assert.Len(t, client, VerifyDataSize)
+	assert.Len(t, server, VerifyDataSize)
+	assert.NotEqual(t, client, server)
+	want, err := PRF(ms, "client finished", sum, 12)
+	require.NoError(t, err)
+	assert.Equal(t, want, client)
+}
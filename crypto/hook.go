@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Operation is the kind of operation reported to hooks.
+type Operation string
+
+// The operations reported to hooks.
+const (
+	OperationEncrypt Operation = "encrypt"
+	OperationDecrypt Operation = "decrypt"
+	OperationSign    Operation = "sign"
+	OperationVerify  Operation = "verify"
+)
+
+// Event describes an encryption, decryption, signing or verification run by an
+// Encrypter, Decrypter, Signer or Verifier.
+type Event struct {
+	Operation  Operation     // The operation, e.g. OperationEncrypt
+	Algorithm  string        // The algorithm name, e.g. "AES" or "RSA"
+	Streaming  bool          // Whether the input is read from a file
+	InputSize  int           // Input size in bytes, -1 when streaming
+	OutputSize int           // Output size in bytes, set when stopped
+	Start      time.Time     // When the operation started
+	Duration   time.Duration // How long the operation took, set when stopped
+	Error      error         // The error the operation failed with, set when stopped
+}
+
+// Hook receives start and stop events of crypto operations, for example to
+// record OpenTelemetry spans or Prometheus metrics. Hooks are called
+// synchronously on the calling goroutine and must be safe for concurrent use.
+type Hook interface {
+	// OnStart is called before an operation runs. The returned state, such as a
+	// tracing span, is passed back to OnStop.
+	OnStart(e Event) any
+	// OnStop is called after the operation finished.
+	OnStop(e Event, state any)
+}
+
+// HookFuncs adapts a pair of functions to a Hook. Either may be nil.
+type HookFuncs struct {
+	Start func(e Event) any
+	Stop  func(e Event, state any)
+}
+
+// OnStart calls h.Start if set.
+func (h HookFuncs) OnStart(e Event) any {
+	if h.Start == nil {
+		return nil
+	}
+	return h.Start(e)
+}
+
+// OnStop calls h.Stop if set.
+func (h HookFuncs) OnStop(e Event, state any) {
+	if h.Stop != nil {
+		h.Stop(e, state)
+	}
+}
+
+var (
+	hooksMu sync.Mutex
+	hooks   atomic.Pointer[[]Hook]
+)
+
+// AddHook registers a hook for all subsequent operations.
+func AddHook(h Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	var list []Hook
+	if p := hooks.Load(); p != nil {
+		list = append(list, *p...)
+	}
+	list = append(list, h)
+	hooks.Store(&list)
+}
+
+// ResetHooks removes all registered hooks.
+func ResetHooks() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks.Store(nil)
+}
+
+// observation is an operation in progress.
+type observation struct {
+	event  Event
+	hooks  []Hook
+	states []any
+}
+
+// observe reports the start of an operation on src or reader to the registered
+// hooks. It returns nil when there are no hooks or nothing to process.
+func observe(op Operation, algorithm string, src []byte, reader io.Reader) *observation {
+	p := hooks.Load()
+	if p == nil || (reader == nil && len(src) == 0) {
+		return nil
+	}
+	o := &observation{
+		event: Event{Operation: op, Algorithm: algorithm, Streaming: reader != nil, InputSize: len(src), Start: time.Now()},
+		hooks: *p,
+	}
+	if reader != nil {
+		o.event.InputSize = -1
+	}
+	o.states = make([]any, len(o.hooks))
+	for i, h := range o.hooks {
+		o.states[i] = h.OnStart(o.event)
+	}
+	return o
+}
+
+// stop reports the end of the operation, reading the output and error through
+// pointers so it can be deferred before they are set.
+func (o *observation) stop(dst *[]byte, err *error) {
+	if o == nil {
+		return
+	}
+	o.event.Duration = time.Since(o.event.Start)
+	if dst != nil {
+		o.event.OutputSize = len(*dst)
+	}
+	o.event.Error = *err
+	for i, h := range o.hooks {
+		h.OnStop(o.event, o.states[i])
+	}
+}
@@ -0,0 +1,147 @@
+package crypto
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a hook recording the events it receives.
+type recorder struct {
+	mu     sync.Mutex
+	starts []Event
+	stops  []Event
+	states []any
+}
+
+func (r *recorder) OnStart(e Event) any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starts = append(r.starts, e)
+	return len(r.starts)
+}
+
+func (r *recorder) OnStop(e Event, state any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stops = append(r.stops, e)
+	r.states = append(r.states, state)
+}
+
+func newAesCipher() *cipher.AesCipher {
+	c := cipher.NewAesCipher(cipher.CBC)
+	c.SetKey(key16)
+	c.SetIV(iv16)
+	c.SetPadding(cipher.PKCS7)
+	return c
+}
+
+func TestHook(t *testing.T) {
+	t.Cleanup(ResetHooks)
+
+	t.Run("standard encryption", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		AddHook(r)
+		encrypter := NewEncrypter().FromBytes(testData).ByAes(newAesCipher())
+		require.NoError(t, encrypter.Error)
+
+		require.Len(t, r.starts, 1)
+		require.Len(t, r.stops, 1)
+		start, stop := r.starts[0], r.stops[0]
+		assert.Equal(t, OperationEncrypt, start.Operation)
+		assert.Equal(t, "AES", start.Algorithm)
+		assert.False(t, start.Streaming)
+		assert.Equal(t, len(testData), start.InputSize)
+		assert.Zero(t, start.OutputSize)
+		assert.Zero(t, start.Duration)
+		assert.Equal(t, len(encrypter.dst), stop.OutputSize)
+		assert.Equal(t, start.Start, stop.Start)
+		assert.GreaterOrEqual(t, stop.Duration, time.Duration(0))
+		assert.Nil(t, stop.Error)
+		assert.Equal(t, 1, r.states[0])
+	})
+
+	t.Run("decryption error", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		AddHook(r)
+		decrypter := NewDecrypter().FromRawBytes([]byte("not a multiple of 16")).ByAes(newAesCipher())
+		require.Error(t, decrypter.Error)
+
+		require.Len(t, r.stops, 1)
+		assert.Equal(t, OperationDecrypt, r.stops[0].Operation)
+		assert.Equal(t, decrypter.Error, r.stops[0].Error)
+	})
+
+	t.Run("streaming encryption", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		AddHook(r)
+		file := mock.NewFile(testData, "test.txt")
+		encrypter := NewEncrypter().FromFile(file).ByAes(newAesCipher())
+		require.NoError(t, encrypter.Error)
+
+		require.Len(t, r.stops, 1)
+		assert.True(t, r.stops[0].Streaming)
+		assert.Equal(t, -1, r.stops[0].InputSize)
+		assert.Equal(t, len(encrypter.dst), r.stops[0].OutputSize)
+	})
+
+	t.Run("sign and verify", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		AddHook(r)
+		kp := keypair.NewEd25519KeyPair()
+		kp.GenKeyPair()
+		signer := NewSigner().FromBytes(testData).ByEd25519(kp)
+		require.NoError(t, signer.Error)
+		verifier := NewVerifier().FromBytes(testData).WithRawSign(signer.ToRawBytes()).ByEd25519(kp)
+		require.True(t, verifier.ToBool())
+
+		require.Len(t, r.stops, 2)
+		assert.Equal(t, OperationSign, r.stops[0].Operation)
+		assert.Equal(t, "Ed25519", r.stops[0].Algorithm)
+		assert.Equal(t, len(signer.sign), r.stops[0].OutputSize)
+		assert.Equal(t, OperationVerify, r.stops[1].Operation)
+		assert.Zero(t, r.stops[1].OutputSize)
+	})
+
+	t.Run("multiple hooks", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		var stopped []any
+		AddHook(r)
+		AddHook(HookFuncs{
+			Start: func(e Event) any { return "span" },
+			Stop:  func(e Event, state any) { stopped = append(stopped, state) },
+		})
+		AddHook(HookFuncs{})
+		NewEncrypter().FromBytes(testData).ByAes(newAesCipher())
+		assert.Len(t, r.stops, 1)
+		assert.Equal(t, []any{"span"}, stopped)
+	})
+
+	t.Run("no input", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		AddHook(r)
+		NewEncrypter().FromBytes(nil).ByAes(newAesCipher())
+		assert.Empty(t, r.starts)
+	})
+
+	t.Run("reset hooks", func(t *testing.T) {
+		ResetHooks()
+		r := &recorder{}
+		AddHook(r)
+		ResetHooks()
+		NewEncrypter().FromBytes(testData).ByAes(newAesCipher())
+		assert.Empty(t, r.starts)
+	})
+}
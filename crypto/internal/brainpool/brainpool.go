@@ -0,0 +1,60 @@
+// Package brainpool provides the brainpoolP256r1 and brainpoolP384r1 curves
+// from RFC 5639 as elliptic.Curve values. Their scalar multiplication is
+// constant time, point addition and doubling are not and suit public points only.
+package brainpool
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+
+	"github.com/dromara/dongle/crypto/internal/weierstrass"
+)
+
+var (
+	once   sync.Once
+	p256r1 elliptic.Curve
+	p384r1 elliptic.Curve
+)
+
+// initCurves builds the curves from their RFC 5639 parameters.
+func initCurves() {
+	p256r1 = newCurve("brainpoolP256r1", 256,
+		"A9FB57DBA1EEA9BC3E660A909D838D726E3BF623D52620282013481D1F6E5377",
+		"7D5A0975FC2C3057EEF67530417AFFE7FB8055C126DC5C6CE94A4B44F330B5D9",
+		"26DC5C6CE94A4B44F330B5D9BBD77CBF958416295CF7E1CE6BCCDC18FF8C07B6",
+		"8BD2AEB9CB7E57CB2C4B482FFC81B7AFB9DE27E1E3BD23C23A4453BD9ACE3262",
+		"547EF835C3DAC4FD97F8461A14611DC9C27745132DED8E545C1D54C72F046997",
+		"A9FB57DBA1EEA9BC3E660A909D838D718C397AA3B561A6F7901E0E82974856A7")
+	p384r1 = newCurve("brainpoolP384r1", 384,
+		"8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC53",
+		"7BC382C63D8C150C3C72080ACE05AFA0C2BEA28E4FB22787139165EFBA91F90F8AA5814A503AD4EB04A8C7DD22CE2826",
+		"04A8C7DD22CE28268B39B55416F0447C2FB77DE107DCD2A62E880EA53EEB62D57CB4390295DBC9943AB78696FA504C11",
+		"1D1C64F068CF45FFA2A63A81B7C13F6B8847A3E77EF14FE3DB7FCAFE0CBD10E8E826E03436D646AAEF87B2E247D4AF1E",
+		"8ABE1D7520F9C2A45CB1EB8E95CFD55262B70B29FEEC5864E19C054FF99129280E4646217791811142820341263C5315",
+		"8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B31F166E6CAC0425A7CF3AB6AF6B7FC3103B883202E9046565")
+}
+
+// newCurve returns a curve from hex encoded p, a, b, Gx, Gy and n.
+func newCurve(name string, bits int, p, a, b, gx, gy, n string) elliptic.Curve {
+	params := &elliptic.CurveParams{Name: name, BitSize: bits}
+	params.P, _ = new(big.Int).SetString(p, 16)
+	params.B, _ = new(big.Int).SetString(b, 16)
+	params.Gx, _ = new(big.Int).SetString(gx, 16)
+	params.Gy, _ = new(big.Int).SetString(gy, 16)
+	params.N, _ = new(big.Int).SetString(n, 16)
+	coefficient, _ := new(big.Int).SetString(a, 16)
+	return weierstrass.New(params, coefficient)
+}
+
+// P256r1 returns the brainpoolP256r1 curve.
+func P256r1() elliptic.Curve {
+	once.Do(initCurves)
+	return p256r1
+}
+
+// P384r1 returns the brainpoolP384r1 curve.
+func P384r1() elliptic.Curve {
+	once.Do(initCurves)
+	return p384r1
+}
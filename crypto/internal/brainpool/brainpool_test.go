@@ -0,0 +1,46 @@
+package brainpool
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurves(t *testing.T) {
+	// Key pairs generated with: openssl ecparam -name <curve> -genkey
+	vectors := []struct {
+		curve   func() elliptic.Curve
+		name    string
+		priv    string
+		pub     string
+		bitSize int
+	}{
+		{P256r1, "brainpoolP256r1",
+			"2e58392754f19727114a25271a5883483326e0acdfa3926879a6ea17621be19a",
+			"04453233885560a3724f963899321120a9d59dc9c570ef2141488d770757e27bc198356831a5175448bbc303b934711d32224ae0abffa8742677ea6b4573ddb7d8",
+			256},
+		{P384r1, "brainpoolP384r1",
+			"0c8b048e094b1084842af1344bebacbe06937c1cd114a82ecd9396f832f33e8be4b4aabfc943c6ad0941123813c7c4ec",
+			"0417b1fd86c80ea8e7d5e30bf42d5f4a8ddf2eadc1335ead3ea1ad459a4fcc9d59880b9b9b19a1d789e57e183e64f4052a6e04af8f8322d1c6fd3093336f089ea97a64c5a40c6f223c95a070bbae1bfc186807076bd6f419f1eb23f7ced3434238",
+			384},
+	}
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			curve := v.curve()
+			assert.Same(t, curve, v.curve())
+			params := curve.Params()
+			assert.Equal(t, v.name, params.Name)
+			assert.Equal(t, v.bitSize, params.BitSize)
+			assert.True(t, curve.IsOnCurve(params.Gx, params.Gy))
+
+			x, y := curve.ScalarBaseMult(params.N.Bytes())
+			assert.Zero(t, x.Sign()+y.Sign())
+
+			priv, _ := hex.DecodeString(v.priv)
+			x, y = curve.ScalarBaseMult(priv)
+			assert.Equal(t, v.pub, hex.EncodeToString(elliptic.Marshal(curve, x, y)))
+		})
+	}
+}
@@ -0,0 +1,303 @@
+// Package cbor implements the subset of CBOR (RFC 8949) needed by COSE and WebAuthn.
+// Encoding is deterministic: integers use the shortest form and map keys are sorted
+// by their encoded bytes. Decoding accepts definite length items only.
+//
+// Decoded values are int64, []byte, string, []any, map[any]any with int64 or string
+// keys, Tag, bool, nil and float64.
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxDepth bounds the nesting of decoded arrays, maps and tags.
+const maxDepth = 32
+
+// Major types.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Tag is a tagged data item.
+type Tag struct {
+	Number  uint64
+	Content any
+}
+
+// Marshal encodes v deterministically.
+func Marshal(v any) ([]byte, error) {
+	return appendValue(nil, v)
+}
+
+// Unmarshal decodes a single data item that must span all of data.
+func Unmarshal(data []byte) (any, error) {
+	v, rest, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("cbor: trailing data")
+	}
+	return v, nil
+}
+
+// Decode decodes the first data item of data and returns the remaining bytes.
+func Decode(data []byte) (v any, rest []byte, err error) {
+	d := &decoder{data: data}
+	if v, err = d.value(0); err != nil {
+		return nil, nil, err
+	}
+	return v, d.data, nil
+}
+
+// appendValue appends the encoding of v to out.
+func appendValue(out []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(out, 0xf6), nil
+	case bool:
+		if v {
+			return append(out, 0xf5), nil
+		}
+		return append(out, 0xf4), nil
+	case int:
+		return appendInt(out, int64(v)), nil
+	case int64:
+		return appendInt(out, v), nil
+	case uint64:
+		return appendHead(out, majorUint, v), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(out, 0xfb), math.Float64bits(v)), nil
+	case []byte:
+		return append(appendHead(out, majorBytes, uint64(len(v))), v...), nil
+	case string:
+		return append(appendHead(out, majorText, uint64(len(v))), v...), nil
+	case []any:
+		out = appendHead(out, majorArray, uint64(len(v)))
+		for _, item := range v {
+			var err error
+			if out, err = appendValue(out, item); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[any]any:
+		return appendMap(out, v)
+	case Tag:
+		return appendValue(appendHead(out, majorTag, v.Number), v.Content)
+	}
+	return nil, fmt.Errorf("cbor: unsupported type %T", v)
+}
+
+// appendMap appends a map with its entries sorted by encoded key.
+func appendMap(out []byte, m map[any]any) ([]byte, error) {
+	type entry struct{ key, value []byte }
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		key, err := appendValue(nil, k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := appendValue(nil, v)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{key, value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	out = appendHead(out, majorMap, uint64(len(entries)))
+	for _, e := range entries {
+		out = append(append(out, e.key...), e.value...)
+	}
+	return out, nil
+}
+
+// appendInt appends a signed integer.
+func appendInt(out []byte, v int64) []byte {
+	if v < 0 {
+		return appendHead(out, majorNegInt, uint64(-(v + 1)))
+	}
+	return appendHead(out, majorUint, uint64(v))
+}
+
+// appendHead appends the shortest head for a major type and argument.
+func appendHead(out []byte, major byte, arg uint64) []byte {
+	m := major << 5
+	switch {
+	case arg < 24:
+		return append(out, m|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(out, m|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, m|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(out, m|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(out, m|27), arg)
+}
+
+// decoder reads data items from data.
+type decoder struct {
+	data []byte
+}
+
+// head reads an item head and returns its major type and argument.
+func (d *decoder) head() (major byte, arg uint64, err error) {
+	if len(d.data) == 0 {
+		return 0, 0, errors.New("cbor: unexpected end of data")
+	}
+	major, info := d.data[0]>>5, d.data[0]&0x1f
+	d.data = d.data[1:]
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	size := 0
+	switch info {
+	case 24:
+		size = 1
+	case 25:
+		size = 2
+	case 26:
+		size = 4
+	case 27:
+		size = 8
+	default:
+		return 0, 0, errors.New("cbor: indefinite length and reserved items are not supported")
+	}
+	if len(d.data) < size {
+		return 0, 0, errors.New("cbor: unexpected end of data")
+	}
+	for _, b := range d.data[:size] {
+		arg = arg<<8 | uint64(b)
+	}
+	d.data = d.data[size:]
+	return major, arg, nil
+}
+
+// value decodes one data item at the given nesting depth.
+func (d *decoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errors.New("cbor: nesting too deep")
+	}
+	start := d.data
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case majorUint:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return int64(arg), nil
+	case majorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(arg), nil
+	case majorBytes, majorText:
+		if arg > uint64(len(d.data)) {
+			return nil, errors.New("cbor: unexpected end of data")
+		}
+		b := d.data[:arg:arg]
+		d.data = d.data[arg:]
+		if major == majorText {
+			return string(b), nil
+		}
+		return append([]byte{}, b...), nil
+	case majorArray:
+		// Every item takes at least one byte, which bounds the allocation.
+		if arg > uint64(len(d.data)) {
+			return nil, errors.New("cbor: unexpected end of data")
+		}
+		items := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case majorMap:
+		if arg > uint64(len(d.data))/2 {
+			return nil, errors.New("cbor: unexpected end of data")
+		}
+		m := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			if _, dup := m[key]; dup {
+				return nil, errors.New("cbor: duplicate map key")
+			}
+			if m[key], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case majorTag:
+		content, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Tag{Number: arg, Content: content}, nil
+	}
+
+	switch info := start[0] & 0x1f; {
+	case info == 20:
+		return false, nil
+	case info == 21:
+		return true, nil
+	case info == 22, info == 23:
+		return nil, nil
+	case info == 25:
+		return float16(uint16(arg)), nil
+	case info == 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case info == 27:
+		return math.Float64frombits(arg), nil
+	}
+	return nil, errors.New("cbor: unsupported simple value")
+}
+
+// float16 converts an IEEE 754 half precision value.
+func float16(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
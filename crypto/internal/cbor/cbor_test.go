@@ -0,0 +1,132 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	// Examples from RFC 8949 appendix A.
+	tests := []struct {
+		value any
+		hex   string
+	}{
+		{int64(0), "00"},
+		{int64(23), "17"},
+		{int64(24), "1818"},
+		{int64(1000), "1903e8"},
+		{int64(1000000), "1a000f4240"},
+		{int64(1000000000000), "1b000000e8d4a51000"},
+		{int64(-1), "20"},
+		{int64(-1000), "3903e7"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"IETF", "6449455446"},
+		{"ü", "62c3bc"},
+		{[]any{}, "80"},
+		{[]any{int64(1), []any{int64(2), int64(3)}}, "8201820203"},
+		{map[any]any{}, "a0"},
+		{map[any]any{int64(1): int64(2), int64(3): int64(4)}, "a201020304"},
+		{map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}, "a26161016162820203"},
+		{Tag{Number: 1, Content: int64(1363896240)}, "c11a514b67b0"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{1.1, "fb3ff199999999999a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			out, err := Marshal(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.hex, hex.EncodeToString(out))
+			v, err := Unmarshal(out)
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, v)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	// Deterministic encoding sorts keys by their encoded bytes, so short keys come first.
+	out, err := Marshal(map[any]any{"aa": 1, 10: 2, -1: 3, "b": 4})
+	require.NoError(t, err)
+	assert.Equal(t, "a40a02200361620462616101", hex.EncodeToString(out))
+
+	out, err = Marshal(uint64(math.MaxUint64))
+	require.NoError(t, err)
+	assert.Equal(t, "1bffffffffffffffff", hex.EncodeToString(out))
+
+	_, err = Marshal(struct{}{})
+	assert.EqualError(t, err, "cbor: unsupported type struct {}")
+	_, err = Marshal([]any{1, struct{}{}})
+	assert.Error(t, err)
+	_, err = Marshal(map[any]any{1: struct{}{}})
+	assert.Error(t, err)
+	_, err = Marshal(map[any]any{struct{}{}: 1})
+	assert.Error(t, err)
+}
+
+func TestUnmarshal(t *testing.T) {
+	floats := map[string]float64{
+		"f90000": 0, "f93c00": 1, "f97bff": 65504, "f90001": 5.960464477539063e-8,
+		"f9c400": -4, "f97c00": math.Inf(1), "fa47c35000": 100000,
+	}
+	for in, want := range floats {
+		data, _ := hex.DecodeString(in)
+		v, err := Unmarshal(data)
+		require.NoError(t, err)
+		assert.Equal(t, want, v, in)
+	}
+	data, _ := hex.DecodeString("f97e00")
+	v, err := Unmarshal(data)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(v.(float64)))
+
+	data, _ = hex.DecodeString("f7")
+	v, err = Unmarshal(data)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, rest, err := Decode([]byte{0x01, 0x02})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), v)
+	assert.Equal(t, []byte{0x02}, rest)
+}
+
+func TestUnmarshal_Error(t *testing.T) {
+	tests := map[string]string{
+		"":                   "cbor: unexpected end of data",
+		"0102":               "cbor: trailing data",
+		"19":                 "cbor: unexpected end of data",
+		"5f":                 "cbor: indefinite length and reserved items are not supported",
+		"1c":                 "cbor: indefinite length and reserved items are not supported",
+		"1b8000000000000000": "cbor: integer overflow",
+		"3b8000000000000000": "cbor: integer overflow",
+		"4401":               "cbor: unexpected end of data",
+		"8501":               "cbor: unexpected end of data",
+		"8201":               "cbor: unexpected end of data",
+		"a3":                 "cbor: unexpected end of data",
+		"a20102":             "cbor: unexpected end of data",
+		"a1f401":             "cbor: unsupported map key type bool",
+		"a201010102":         "cbor: duplicate map key",
+		"a101":               "cbor: unexpected end of data",
+		"c1":                 "cbor: unexpected end of data",
+		"f820":               "cbor: unsupported simple value",
+	}
+	for in, msg := range tests {
+		data, _ := hex.DecodeString(in)
+		_, err := Unmarshal(data)
+		assert.EqualError(t, err, msg, in)
+	}
+
+	deep := make([]byte, maxDepth+2)
+	for i := range deep {
+		deep[i] = 0x81
+	}
+	_, err := Unmarshal(append(deep, 0x00))
+	assert.EqualError(t, err, "cbor: nesting too deep")
+}
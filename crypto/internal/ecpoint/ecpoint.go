@@ -0,0 +1,100 @@
+// Package ecpoint encodes and decodes elliptic curve points in the SEC 1
+// uncompressed (04) and compressed (02/03) forms. It works with curves of the
+// form y² = x³ - 3x + b, which covers the NIST curves and SM2, and with curves
+// reporting another coefficient a through an A method, such as secp256k1.
+package ecpoint
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// Point format prefixes defined by SEC 1, section 2.3.3.
+const (
+	compressedEven = 0x02
+	compressedOdd  = 0x03
+	uncompressed   = 0x04
+)
+
+var (
+	errInvalidPoint = errors.New("invalid point encoding")
+	errNotOnCurve   = errors.New("point not on curve")
+)
+
+// Unmarshal decodes a compressed or uncompressed point and checks that it
+// lies on the curve. Coordinates must be reduced modulo p and the point at
+// infinity is rejected.
+func Unmarshal(curve elliptic.Curve, data []byte) (x, y *big.Int, err error) {
+	params := curve.Params()
+	size := (params.BitSize + 7) / 8
+	switch {
+	case len(data) == 1+2*size && data[0] == uncompressed:
+		x = new(big.Int).SetBytes(data[1 : 1+size])
+		y = new(big.Int).SetBytes(data[1+size:])
+	case len(data) == 1+size && (data[0] == compressedEven || data[0] == compressedOdd):
+		x = new(big.Int).SetBytes(data[1:])
+		if y = decompress(curve, x, data[0] == compressedOdd); y == nil {
+			return nil, nil, errNotOnCurve
+		}
+	default:
+		return nil, nil, errInvalidPoint
+	}
+	if x.Cmp(params.P) >= 0 || y.Cmp(params.P) >= 0 {
+		return nil, nil, errInvalidPoint
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, nil, errNotOnCurve
+	}
+	return x, y, nil
+}
+
+// Marshal encodes a point in the compressed form when compressed is true and
+// in the uncompressed form otherwise.
+func Marshal(curve elliptic.Curve, x, y *big.Int, compressed bool) []byte {
+	size := (curve.Params().BitSize + 7) / 8
+	if compressed {
+		out := make([]byte, 1+size)
+		out[0] = compressedEven | byte(y.Bit(0))
+		x.FillBytes(out[1:])
+		return out
+	}
+	out := make([]byte, 1+2*size)
+	out[0] = uncompressed
+	x.FillBytes(out[1 : 1+size])
+	y.FillBytes(out[1+size:])
+	return out
+}
+
+// decompress returns the y coordinate with the given parity for x, or nil if
+// x is not the abscissa of a point on the curve.
+func decompress(curve elliptic.Curve, x *big.Int, odd bool) *big.Int {
+	params := curve.Params()
+	p := params.P
+	if x.Cmp(p) >= 0 {
+		return nil
+	}
+	// y² = x³ + ax + b
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	y2.Add(y2, new(big.Int).Mul(coefficient(curve), x))
+	y2.Add(y2, params.B)
+	y2.Mod(y2, p)
+	y := new(big.Int).ModSqrt(y2, p)
+	if y == nil {
+		return nil
+	}
+	if y.Bit(0) != 0 != odd {
+		y.Sub(p, y)
+	}
+	return y
+}
+
+// coefficient returns the curve coefficient a, -3 unless the curve reports
+// another one.
+func coefficient(curve elliptic.Curve) *big.Int {
+	if c, ok := curve.(interface{ A() *big.Int }); ok {
+		return c.A()
+	}
+	return big.NewInt(-3)
+}
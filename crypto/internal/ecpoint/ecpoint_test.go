@@ -0,0 +1,76 @@
+package ecpoint
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/internal/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521(), secp256k1.Curve()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			for i := 0; i < 8; i++ {
+				d, err := rand.Int(rand.Reader, curve.Params().N)
+				require.NoError(t, err)
+				key := new(ecdsa.PrivateKey)
+				key.X, key.Y = curve.ScalarBaseMult(d.Bytes())
+				for _, compressed := range []bool{true, false} {
+					data := Marshal(curve, key.X, key.Y, compressed)
+					x, y, err := Unmarshal(curve, data)
+					require.NoError(t, err)
+					assert.Equal(t, key.X, x)
+					assert.Equal(t, key.Y, y)
+				}
+				assert.Equal(t, elliptic.MarshalCompressed(curve, key.X, key.Y), Marshal(curve, key.X, key.Y, true))
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	curve := elliptic.P256()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("bad prefix and length", func(t *testing.T) {
+		data := Marshal(curve, key.X, key.Y, true)
+		for _, bad := range [][]byte{nil, {0x00}, append([]byte{0x05}, data[1:]...), data[:10], append([]byte{0x04}, data[1:]...)} {
+			_, _, err := Unmarshal(curve, bad)
+			assert.Equal(t, errInvalidPoint, err)
+		}
+	})
+
+	t.Run("not on curve", func(t *testing.T) {
+		data := Marshal(curve, key.X, key.Y, false)
+		data[len(data)-1] ^= 1
+		_, _, err := Unmarshal(curve, data)
+		assert.Equal(t, errNotOnCurve, err)
+	})
+
+	t.Run("no square root", func(t *testing.T) {
+		// Find a small x that is not the abscissa of any point on P-256.
+		data := make([]byte, 33)
+		data[0] = compressedEven
+		x := int64(1)
+		for decompress(curve, big.NewInt(x), false) != nil {
+			x++
+		}
+		data[32] = byte(x)
+		_, _, err := Unmarshal(curve, data)
+		assert.Equal(t, errNotOnCurve, err)
+	})
+
+	t.Run("unreduced coordinate", func(t *testing.T) {
+		data := make([]byte, 33)
+		data[0] = compressedEven
+		curve.Params().P.FillBytes(data[1:])
+		_, _, err := Unmarshal(curve, data)
+		assert.Equal(t, errNotOnCurve, err)
+	})
+}
@@ -10,14 +10,26 @@ import (
 	"math/big"
 )
 
+// ErrFault is returned when a signature fails its consistency check, which
+// points at a fault during the CRT computation. The faulty signature is never
+// returned, since it can reveal a factor of the modulus.
+var ErrFault = errors.New("private key operation failed its fault check")
+
 // EncryptPKCS1v15WithPublicKey encrypts data with a public key using PKCS#1 v1.5 padding.
 func EncryptPKCS1v15WithPublicKey(random io.Reader, pub *rsa.PublicKey, msg []byte) ([]byte, error) {
 	return rsa.EncryptPKCS1v15(random, pub, msg)
 }
 
-// EncryptOAEPWithPublicKey encrypts data with a public key using OAEP padding.
-func EncryptOAEPWithPublicKey(hash hash.Hash, random io.Reader, pub *rsa.PublicKey, msg []byte) ([]byte, error) {
-	return rsa.EncryptOAEP(hash, random, pub, msg, nil)
+// EncryptOAEPWithPublicKey encrypts data with a public key using OAEP padding
+// with the hash, MGF1 hash and label of opts.
+func EncryptOAEPWithPublicKey(opts *rsa.OAEPOptions, random io.Reader, pub *rsa.PublicKey, msg []byte) ([]byte, error) {
+	if opts == nil {
+		return nil, errors.New("oaep options are nil")
+	}
+	if opts.MGFHash == 0 || opts.MGFHash == opts.Hash {
+		return rsa.EncryptOAEP(opts.Hash.New(), random, pub, msg, opts.Label)
+	}
+	return encryptOAEP(opts, random, pub, msg)
 }
 
 // EncryptPKCS1v15WithPrivateKey encrypts data with a private key using PKCS#1 v1.5 padding.
@@ -25,9 +37,44 @@ func EncryptPKCS1v15WithPrivateKey(random io.Reader, pri *rsa.PrivateKey, msg []
 	return rsa.EncryptPKCS1v15(random, &pri.PublicKey, msg)
 }
 
-// EncryptOAEPWithPrivateKey encrypts data with a private key using OAEP padding.
-func EncryptOAEPWithPrivateKey(hash hash.Hash, random io.Reader, pri *rsa.PrivateKey, msg []byte) ([]byte, error) {
-	return rsa.EncryptOAEP(hash, random, &pri.PublicKey, msg, nil)
+// EncryptOAEPWithPrivateKey encrypts data with a private key using OAEP padding
+// with the hash, MGF1 hash and label of opts.
+func EncryptOAEPWithPrivateKey(opts *rsa.OAEPOptions, random io.Reader, pri *rsa.PrivateKey, msg []byte) ([]byte, error) {
+	return EncryptOAEPWithPublicKey(opts, random, &pri.PublicKey, msg)
+}
+
+// encryptOAEP encrypts data with a public key using OAEP padding whose MGF1
+// hash differs from its label hash, as Java's OAEPWithSHA-256AndMGF1Padding
+// does with its default MGF1 SHA-1. The standard library only covers that
+// combination when decrypting.
+func encryptOAEP(opts *rsa.OAEPOptions, random io.Reader, pub *rsa.PublicKey, msg []byte) ([]byte, error) {
+	if pub == nil || pub.N == nil || pub.E == 0 {
+		return nil, errors.New("invalid public key")
+	}
+	hash, mgfHash := opts.Hash.New(), opts.MGFHash.New()
+	k := pub.Size()
+	if len(msg) > k-2*hash.Size()-2 {
+		return nil, rsa.ErrMessageTooLong
+	}
+
+	hash.Write(opts.Label)
+	lHash := hash.Sum(nil)
+
+	// EM = 0x00 || maskedSeed || maskedDB, DB = lHash || PS || 0x01 || M
+	em := make([]byte, k)
+	seed := em[1 : 1+hash.Size()]
+	db := em[1+hash.Size():]
+	copy(db, lHash)
+	db[len(db)-len(msg)-1] = 0x01
+	copy(db[len(db)-len(msg):], msg)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, err
+	}
+	mgf1(db, mgfHash, seed)
+	mgf1(seed, mgfHash, db)
+
+	c := new(big.Int).Exp(new(big.Int).SetBytes(em), big.NewInt(int64(pub.E)), pub.N)
+	return c.FillBytes(make([]byte, k)), nil
 }
 
 // DecryptPKCS1v15WithPublicKey decrypts data with a public key using PKCS#1 v1.5 padding.
@@ -85,16 +132,21 @@ func DecryptPKCS1v15WithPublicKey(pub *rsa.PublicKey, ciphertext []byte) ([]byte
 	return em[sepIndex+1:], nil
 }
 
-// DecryptOAEPWithPublicKey decrypts data with a public key using OAEP padding.
-func DecryptOAEPWithPublicKey(hash hash.Hash, pub *rsa.PublicKey, ciphertext []byte) ([]byte, error) {
+// DecryptOAEPWithPublicKey decrypts data with a public key using OAEP padding
+// with the hash, MGF1 hash and label of opts.
+func DecryptOAEPWithPublicKey(opts *rsa.OAEPOptions, pub *rsa.PublicKey, ciphertext []byte) ([]byte, error) {
 	if pub == nil {
 		return nil, errors.New("public key is nil")
 	}
 	if pub.N == nil || pub.E == 0 {
 		return nil, errors.New("invalid public key")
 	}
-	if hash == nil {
-		return nil, errors.New("hash function is nil")
+	if opts == nil {
+		return nil, errors.New("oaep options are nil")
+	}
+	hash, mgfHash := opts.Hash.New(), opts.Hash.New()
+	if opts.MGFHash != 0 {
+		mgfHash = opts.MGFHash.New()
 	}
 
 	k := pub.Size()
@@ -137,17 +189,17 @@ func DecryptOAEPWithPublicKey(hash hash.Hash, pub *rsa.PublicKey, ciphertext []b
 	// Recover seed via MGF1
 	seed := make([]byte, hashSize)
 	copy(seed, maskedSeed)
-	mgf1(seed, hash, maskedDB)
+	mgf1(seed, mgfHash, maskedDB)
 
 	// Recover DB via MGF1
 	db := make([]byte, len(maskedDB))
 	copy(db, maskedDB)
-	mgf1(db, hash, seed)
+	mgf1(db, mgfHash, seed)
 
 	// Validate lHash
 	lHash := db[:hashSize]
 	hash.Reset()
-	hash.Write(nil) // empty label
+	hash.Write(opts.Label)
 	expectedLHash := hash.Sum(nil)
 
 	if !equalBytes(lHash, expectedLHash) {
@@ -181,13 +233,20 @@ func DecryptOAEPWithPublicKey(hash hash.Hash, pub *rsa.PublicKey, ciphertext []b
 }
 
 // DecryptPKCS1v15WithPrivateKey decrypts data with a private key using PKCS#1 v1.5 padding.
+// The standard library re-encrypts the CRT result with the public exponent and
+// compares it with the ciphertext, so a faulty plaintext is never returned.
 func DecryptPKCS1v15WithPrivateKey(random io.Reader, pri *rsa.PrivateKey, msg []byte) ([]byte, error) {
 	return rsa.DecryptPKCS1v15(random, pri, msg)
 }
 
-// DecryptOAEPWithPrivateKey decrypts data with a private key using OAEP padding.
-func DecryptOAEPWithPrivateKey(hash hash.Hash, random io.Reader, pri *rsa.PrivateKey, msg []byte) ([]byte, error) {
-	return rsa.DecryptOAEP(hash, random, pri, msg, nil)
+// DecryptOAEPWithPrivateKey decrypts data with a private key using OAEP padding
+// with the hash, MGF1 hash and label of opts.
+// It is checked for faults as DecryptPKCS1v15WithPrivateKey is.
+func DecryptOAEPWithPrivateKey(opts *rsa.OAEPOptions, random io.Reader, pri *rsa.PrivateKey, msg []byte) ([]byte, error) {
+	if opts == nil {
+		return nil, errors.New("oaep options are nil")
+	}
+	return pri.Decrypt(random, msg, opts)
 }
 
 // pkcs1v15HashPrefixes holds ASN.1 DigestInfo prefixes for supported hashes.
@@ -317,13 +376,40 @@ func SignPSSWithPublicKey(random io.Reader, pub *rsa.PublicKey, hash crypto.Hash
 }
 
 // SignPKCS1v15WithPrivateKey signs data with a private key using PKCS#1 v1.5 padding.
+// The signature is verified against the public key before it is returned.
 func SignPKCS1v15WithPrivateKey(random io.Reader, pri *rsa.PrivateKey, hash crypto.Hash, hashed []byte) ([]byte, error) {
-	return rsa.SignPKCS1v15(random, pri, hash, hashed)
+	sign, err := rsa.SignPKCS1v15(random, pri, hash, hashed)
+	if err != nil {
+		return nil, err
+	}
+	return CheckSign(&pri.PublicKey, false, hash, hashed, sign)
 }
 
 // SignPSSWithPrivateKey signs data with a private key using PSS padding.
+// The signature is verified against the public key before it is returned.
 func SignPSSWithPrivateKey(random io.Reader, pri *rsa.PrivateKey, hash crypto.Hash, digest []byte) ([]byte, error) {
-	return rsa.SignPSS(random, pri, hash, digest, nil)
+	sign, err := rsa.SignPSS(random, pri, hash, digest, nil)
+	if err != nil {
+		return nil, err
+	}
+	return CheckSign(&pri.PublicKey, true, hash, digest, sign)
+}
+
+// CheckSign re-encrypts a signature made with a private key, by verifying it
+// against the public key, and returns it unless the check fails with ErrFault.
+// A signature from a faulty CRT computation reveals a factor of the modulus,
+// so it must not leave the signer. pss selects PSS padding over PKCS#1 v1.5.
+func CheckSign(pub *rsa.PublicKey, pss bool, hash crypto.Hash, hashed, sign []byte) ([]byte, error) {
+	var err error
+	if pss {
+		err = rsa.VerifyPSS(pub, hash, hashed, sign, nil)
+	} else {
+		err = rsa.VerifyPKCS1v15(pub, hash, hashed, sign)
+	}
+	if err != nil {
+		return nil, ErrFault
+	}
+	return sign, nil
 }
 
 // VerifyPKCS1v15WithPublicKey verifies a PKCS#1 v1.5 signature with a public key.
@@ -129,11 +129,12 @@ func TestEncryptDecryptWrappers(t *testing.T) {
 		t.Fatalf("pkcs1 mismatch")
 	}
 
-	c2, err := EncryptOAEPWithPublicKey(sha256.New(), rand.Reader, &key.PublicKey, msg)
+	opts := &rsa.OAEPOptions{Hash: crypto.SHA256}
+	c2, err := EncryptOAEPWithPublicKey(opts, rand.Reader, &key.PublicKey, msg)
 	if err != nil {
 		t.Fatalf("encrypt oaep: %v", err)
 	}
-	p2, err := DecryptOAEPWithPrivateKey(sha256.New(), rand.Reader, key, c2)
+	p2, err := DecryptOAEPWithPrivateKey(opts, rand.Reader, key, c2)
 	if err != nil {
 		t.Fatalf("decrypt oaep: %v", err)
 	}
@@ -153,11 +154,11 @@ func TestEncryptDecryptWrappers(t *testing.T) {
 		t.Fatalf("pkcs1 private mismatch")
 	}
 
-	c4, err := EncryptOAEPWithPrivateKey(sha256.New(), rand.Reader, key, msg)
+	c4, err := EncryptOAEPWithPrivateKey(opts, rand.Reader, key, msg)
 	if err != nil {
 		t.Fatalf("encrypt oaep private: %v", err)
 	}
-	p4, err := DecryptOAEPWithPrivateKey(sha256.New(), rand.Reader, key, c4)
+	p4, err := DecryptOAEPWithPrivateKey(opts, rand.Reader, key, c4)
 	if err != nil {
 		t.Fatalf("decrypt oaep private: %v", err)
 	}
@@ -281,7 +282,7 @@ func TestDecryptOAEPWithPublicKey(t *testing.T) {
 	key := mustKey(t, 1024)
 	msg := []byte("oaep plaintext")
 	cipher, _ := oaepCiphertext(t, key, sha256.New(), msg)
-	plain, err := DecryptOAEPWithPublicKey(sha256.New(), &key.PublicKey, cipher)
+	plain, err := DecryptOAEPWithPublicKey(&rsa.OAEPOptions{Hash: crypto.SHA256}, &key.PublicKey, cipher)
 	if err != nil {
 		t.Fatalf("decrypt: %v", err)
 	}
@@ -295,20 +296,21 @@ func TestDecryptOAEPWithPublicKeyErrors(t *testing.T) {
 	smallPub := &rsa.PublicKey{N: big.NewInt(257), E: 3}
 	k := key.PublicKey.Size()
 	h := sha256.New()
+	opts := &rsa.OAEPOptions{Hash: crypto.SHA256}
 
-	if _, err := DecryptOAEPWithPublicKey(h, nil, nil); err == nil {
+	if _, err := DecryptOAEPWithPublicKey(opts, nil, nil); err == nil {
 		t.Fatalf("expected nil pub error")
 	}
 
-	if _, err := DecryptOAEPWithPublicKey(h, &rsa.PublicKey{}, nil); err == nil {
+	if _, err := DecryptOAEPWithPublicKey(opts, &rsa.PublicKey{}, nil); err == nil {
 		t.Fatalf("expected invalid pub error")
 	}
 
 	if _, err := DecryptOAEPWithPublicKey(nil, &key.PublicKey, nil); err == nil {
-		t.Fatalf("expected nil hash error")
+		t.Fatalf("expected nil options error")
 	}
 
-	if _, err := DecryptOAEPWithPublicKey(h, &key.PublicKey, []byte{1, 2, 3}); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, []byte{1, 2, 3}); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected length error")
 	}
 
@@ -318,19 +320,19 @@ func TestDecryptOAEPWithPublicKeyErrors(t *testing.T) {
 		copy(pad[k-len(nBytes):], nBytes)
 		nBytes = pad
 	}
-	if _, err := DecryptOAEPWithPublicKey(h, &key.PublicKey, nBytes); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, nBytes); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected modulus compare error")
 	}
 
 	shortCipher := make([]byte, smallPub.Size())
-	if _, err := DecryptOAEPWithPublicKey(h, smallPub, shortCipher); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, smallPub, shortCipher); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected em length error")
 	}
 
 	emBadFirst := make([]byte, k)
 	emBadFirst[0] = 0x01
 	badCipher := pkcs1CiphertextFromEM(key, emBadFirst)
-	if _, err := DecryptOAEPWithPublicKey(h, &key.PublicKey, badCipher); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, badCipher); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected leading byte error")
 	}
 
@@ -338,7 +340,7 @@ func TestDecryptOAEPWithPublicKeyErrors(t *testing.T) {
 	db, seed := oaepDB(t, h, k, msg)
 	db[0] ^= 0x01
 	badLHash := oaepCiphertextFromDB(key, h, db, seed)
-	if _, err := DecryptOAEPWithPublicKey(h, &key.PublicKey, badLHash); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, badLHash); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected lhash error")
 	}
 
@@ -351,18 +353,89 @@ func TestDecryptOAEPWithPublicKeyErrors(t *testing.T) {
 		t.Fatalf("seed: %v", err)
 	}
 	badSep := oaepCiphertextFromDB(key, h, dbNoSep, seedNoSep)
-	if _, err := DecryptOAEPWithPublicKey(h, &key.PublicKey, badSep); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, badSep); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected separator missing error")
 	}
 
 	dbBadPadding, seedBadPadding := oaepDB(t, h, k, msg)
 	dbBadPadding[h.Size()] = 0x02
 	badPadding := oaepCiphertextFromDB(key, h, dbBadPadding, seedBadPadding)
-	if _, err := DecryptOAEPWithPublicKey(h, &key.PublicKey, badPadding); !errors.Is(err, rsa.ErrDecryption) {
+	if _, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, badPadding); !errors.Is(err, rsa.ErrDecryption) {
 		t.Fatalf("expected padding error")
 	}
 }
 
+func TestOAEPOptions(t *testing.T) {
+	key := mustKey(t, 1024)
+	msg := []byte("oaep options")
+	// Java's OAEPWithSHA-256AndMGF1Padding with a label
+	opts := &rsa.OAEPOptions{Hash: crypto.SHA256, MGFHash: crypto.SHA1, Label: []byte("label")}
+
+	c1, err := EncryptOAEPWithPublicKey(opts, rand.Reader, &key.PublicKey, msg)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	p1, err := key.Decrypt(nil, c1, opts)
+	if err != nil || !bytes.Equal(p1, msg) {
+		t.Fatalf("standard library decrypt: %v", err)
+	}
+	p2, err := DecryptOAEPWithPrivateKey(opts, rand.Reader, key, c1)
+	if err != nil || !bytes.Equal(p2, msg) {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if _, err := DecryptOAEPWithPrivateKey(&rsa.OAEPOptions{Hash: crypto.SHA256, MGFHash: crypto.SHA1}, rand.Reader, key, c1); err == nil {
+		t.Fatalf("expected label mismatch error")
+	}
+	if _, err := DecryptOAEPWithPrivateKey(&rsa.OAEPOptions{Hash: crypto.SHA256, Label: []byte("label")}, rand.Reader, key, c1); err == nil {
+		t.Fatalf("expected mgf hash mismatch error")
+	}
+	if _, err := DecryptOAEPWithPrivateKey(nil, rand.Reader, key, c1); err == nil {
+		t.Fatalf("expected nil options error")
+	}
+
+	// Labels with a single hash go through the standard library
+	c2, err := EncryptOAEPWithPrivateKey(&rsa.OAEPOptions{Hash: crypto.SHA256, Label: []byte("label")}, rand.Reader, key, msg)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if p, err := rsa.DecryptOAEP(sha256.New(), nil, key, c2, []byte("label")); err != nil || !bytes.Equal(p, msg) {
+		t.Fatalf("standard library decrypt: %v", err)
+	}
+
+	// Public key decryption of a ciphertext made with the private exponent
+	h, mgfHash := sha256.New(), sha1.New()
+	k := key.PublicKey.Size()
+	db := make([]byte, k-h.Size()-1)
+	h.Write(opts.Label)
+	copy(db, h.Sum(nil))
+	db[len(db)-len(msg)-1] = 0x01
+	copy(db[len(db)-len(msg):], msg)
+	seed := make([]byte, h.Size())
+	mgf1(db, mgfHash, seed)
+	mgf1(seed, mgfHash, db)
+	em := append(append([]byte{0x00}, seed...), db...)
+	p3, err := DecryptOAEPWithPublicKey(opts, &key.PublicKey, pkcs1CiphertextFromEM(key, em))
+	if err != nil || !bytes.Equal(p3, msg) {
+		t.Fatalf("public decrypt: %v", err)
+	}
+	if _, err := DecryptOAEPWithPublicKey(&rsa.OAEPOptions{Hash: crypto.SHA256, MGFHash: crypto.SHA1}, &key.PublicKey, pkcs1CiphertextFromEM(key, em)); !errors.Is(err, rsa.ErrDecryption) {
+		t.Fatalf("expected label mismatch error")
+	}
+
+	if _, err := EncryptOAEPWithPublicKey(nil, rand.Reader, &key.PublicKey, msg); err == nil {
+		t.Fatalf("expected nil options error")
+	}
+	if _, err := EncryptOAEPWithPublicKey(opts, rand.Reader, &rsa.PublicKey{}, msg); err == nil {
+		t.Fatalf("expected invalid pub error")
+	}
+	if _, err := EncryptOAEPWithPublicKey(opts, rand.Reader, &key.PublicKey, make([]byte, k)); !errors.Is(err, rsa.ErrMessageTooLong) {
+		t.Fatalf("expected message too long error")
+	}
+	if _, err := EncryptOAEPWithPublicKey(opts, failingReader{err: errSaltFailure}, &key.PublicKey, msg); !errors.Is(err, errSaltFailure) {
+		t.Fatalf("expected random failure")
+	}
+}
+
 func TestSignPKCS1v15WithPublicKey(t *testing.T) {
 	key := mustKey(t, 1024)
 	msg := []byte("public sign")
@@ -547,3 +620,30 @@ func TestEmsaPSSEncode(t *testing.T) {
 		t.Fatalf("missing trailer field")
 	}
 }
+
+func TestCheckSign(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := sha256.Sum256([]byte("message"))
+
+	for _, pss := range []bool{false, true} {
+		var sign []byte
+		if pss {
+			sign, err = SignPSSWithPrivateKey(rand.Reader, key, crypto.SHA256, hashed[:])
+		} else {
+			sign, err = SignPKCS1v15WithPrivateKey(rand.Reader, key, crypto.SHA256, hashed[:])
+		}
+		if err != nil {
+			t.Fatalf("sign failed: %v", err)
+		}
+		if _, err = CheckSign(&key.PublicKey, pss, crypto.SHA256, hashed[:], sign); err != nil {
+			t.Fatalf("CheckSign() = %v", err)
+		}
+		sign[0] ^= 0x01
+		if _, err = CheckSign(&key.PublicKey, pss, crypto.SHA256, hashed[:], sign); err != ErrFault {
+			t.Fatalf("expected %v, got %v", ErrFault, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package secp256k1 provides the secp256k1 curve from SEC 2 as an
+// elliptic.Curve. Its scalar multiplication is constant time, point addition
+// and doubling are not and suit public points only.
+package secp256k1
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+
+	"github.com/dromara/dongle/crypto/internal/weierstrass"
+)
+
+var (
+	once     sync.Once
+	instance elliptic.Curve
+)
+
+// Curve returns the secp256k1 curve, y² = x³ + 7.
+func Curve() elliptic.Curve {
+	once.Do(func() {
+		p, _ := new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+		n, _ := new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+		gx, _ := new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+		gy, _ := new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+		instance = weierstrass.New(&elliptic.CurveParams{
+			P: p, N: n, B: big.NewInt(7), Gx: gx, Gy: gy, BitSize: 256, Name: "secp256k1",
+		}, new(big.Int))
+	})
+	return instance
+}
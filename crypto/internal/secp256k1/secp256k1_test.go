@@ -0,0 +1,61 @@
+package secp256k1
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hexInt(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+func TestCurve(t *testing.T) {
+	c := Curve()
+	params := c.Params()
+	assert.Same(t, c, Curve())
+	assert.Equal(t, "secp256k1", params.Name)
+	assert.True(t, c.IsOnCurve(params.Gx, params.Gy))
+	assert.False(t, c.IsOnCurve(params.Gx, new(big.Int).Add(params.Gy, big.NewInt(1))))
+	assert.False(t, c.IsOnCurve(new(big.Int).Add(params.Gx, params.P), params.Gy))
+
+	t.Run("known multiples", func(t *testing.T) {
+		// Test vectors from https://crypto.stackexchange.com/a/21206
+		vectors := []struct {
+			k    int64
+			x, y string
+		}{
+			{2, "C6047F9441ED7D6D3045406E95C07CD85C778E4B8CEF3CA7ABAC09B95C709EE5", "1AE168FEA63DC339A3C58419466CEAEEF7F632653266D0E1236431A950CFE52A"},
+			{3, "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9", "388F7B0F632DE8140FE337E62A37F3566500A99934C2231B6CB9FD7584B8E672"},
+			{20, "4CE119C96E2FA357200B559B2F7DD5A5F02D5290AFF74B03F3E471B273211C97", "12BA26DCB10EC1625DA61FA10A844C676162948271D96967450288EE9233DC3A"},
+		}
+		for _, v := range vectors {
+			x, y := c.ScalarBaseMult(big.NewInt(v.k).Bytes())
+			assert.Equal(t, hexInt(v.x), x, v.k)
+			assert.Equal(t, hexInt(v.y), y, v.k)
+		}
+	})
+
+	t.Run("group law", func(t *testing.T) {
+		x2, y2 := c.Double(params.Gx, params.Gy)
+		x3, y3 := c.Add(x2, y2, params.Gx, params.Gy)
+		ex, ey := c.ScalarBaseMult([]byte{3})
+		assert.Equal(t, ex, x3)
+		assert.Equal(t, ey, y3)
+		sx, sy := c.Add(params.Gx, params.Gy, params.Gx, params.Gy)
+		assert.Equal(t, x2, sx)
+		assert.Equal(t, y2, sy)
+
+		// n * G and G + (-G) are the point at infinity.
+		ix, iy := c.ScalarBaseMult(params.N.Bytes())
+		assert.Zero(t, ix.Sign()+iy.Sign())
+		ix, iy = c.Add(params.Gx, params.Gy, params.Gx, new(big.Int).Sub(params.P, params.Gy))
+		assert.Zero(t, ix.Sign()+iy.Sign())
+		ix, iy = c.Add(ix, iy, params.Gx, params.Gy)
+		assert.Equal(t, params.Gx, ix)
+		assert.Equal(t, params.Gy, iy)
+		assert.Zero(t, c.(interface{ A() *big.Int }).A().Sign())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	encodingAsn1 "encoding/asn1"
 	"math/big"
 
+	"github.com/dromara/dongle/crypto/internal/ecpoint"
 	"golang.org/x/crypto/cryptobyte"
 	cryptoAsn1 "golang.org/x/crypto/cryptobyte/asn1"
 )
@@ -133,18 +134,14 @@ func ParsePKCS8PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
 }
 
 // ParseBitStringPublicKey parses a BIT_STRING PublicKeyInfo and returns an SM2 public key.
+// Both the uncompressed (04) and compressed (02/03) point encodings are accepted.
 //
 //go:inline
 func ParseBitStringPublicKey(key []byte) (*ecdsa.PublicKey, error) {
 	cv := NewCurve()
-	pLen := (cv.Params().BitSize + 7) / 8
-	if len(key) != 1+2*pLen || key[0] != 0x04 {
-		return nil, encodingAsn1.SyntaxError{Msg: "unsupported or invalid EC point"}
-	}
-	x := new(big.Int).SetBytes(key[1 : 1+pLen])
-	y := new(big.Int).SetBytes(key[1+pLen:])
-	if !cv.IsOnCurve(x, y) {
-		return nil, encodingAsn1.StructuralError{Msg: "point not on curve"}
+	x, y, err := ecpoint.Unmarshal(cv, key)
+	if err != nil {
+		return nil, encodingAsn1.SyntaxError{Msg: err.Error()}
 	}
 	return &ecdsa.PublicKey{Curve: cv, X: x, Y: y}, nil
 }
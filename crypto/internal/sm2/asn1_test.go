@@ -68,7 +68,7 @@ func TestASN1_ParseSPKIPublicKey_CompressedAndErrors(t *testing.T) {
 		t.Fatalf("expect algo OID error")
 	}
 
-	// Compressed encoding is not supported
+	// Compressed encoding of the base point
 	xb := make([]byte, coordLen)
 	copy(xb[coordLen-len(p.Gx.Bytes()):], p.Gx.Bytes())
 	comp := append([]byte{0x02}, xb...)
@@ -81,8 +81,8 @@ func TestASN1_ParseSPKIPublicKey_CompressedAndErrors(t *testing.T) {
 		b.AddASN1(cryptoAsn1.BIT_STRING, func(b *cryptobyte.Builder) { b.AddUint8(0); b.AddBytes(comp) })
 	})
 	der, _ := bc.Bytes()
-	if _, err := ParseSPKIPublicKey(der); err == nil {
-		t.Fatalf("expect unsupported or invalid EC point")
+	if pub, err := ParseSPKIPublicKey(der); err != nil || pub.X.Cmp(p.Gx) != 0 || pub.Y.Cmp(p.Gy) != 0 {
+		t.Fatalf("expect compressed base point, got err %v", err)
 	}
 
 	// Unsupported first byte
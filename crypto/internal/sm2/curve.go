@@ -2,11 +2,12 @@ package sm2
 
 import (
 	"crypto/elliptic"
-	"crypto/rand"
 	"encoding/asn1"
 	"io"
 	"math/big"
 	"sync"
+
+	"github.com/dromara/dongle/internal/utils"
 )
 
 // Ensure *sm2Curve implements elliptic.Curve interface.
@@ -456,7 +457,7 @@ func SetWindow(cv elliptic.Curve, w int) {
 // RandScalar generates random scalar in [1, N-1] using rejection sampling.
 func RandScalar(curve elliptic.Curve, random io.Reader) (*big.Int, error) {
 	if random == nil {
-		random = rand.Reader
+		random = utils.Rand()
 	}
 	params := curve.Params()
 	byteLen := (params.BitSize + 7) / 8
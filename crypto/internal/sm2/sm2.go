@@ -2,7 +2,7 @@ package sm2
 
 import (
 	"crypto/ecdsa"
-	"crypto/rand"
+	"crypto/elliptic"
 	"errors"
 	"io"
 	"math/big"
@@ -13,9 +13,20 @@ import (
 	cryptoAsn1 "golang.org/x/crypto/cryptobyte/asn1"
 )
 
+// maxUIDSize is the largest UID whose bit length fits the 16-bit ENTLA.
+const maxUIDSize = 8191
+
 var (
 	// defaultUID is the default user identifier as specified in GM/T 0009-2012
 	defaultUID = []byte("1234567812345678")
+
+	// ErrInvalidPoint is returned when the C1 point of a ciphertext is not a
+	// point of the curve, or is the identity.
+	ErrInvalidPoint = errors.New("C1 is not a valid curve point")
+
+	// ErrMacMismatch is returned when the C3 hash of a ciphertext does not
+	// match the decrypted plaintext.
+	ErrMacMismatch = errors.New("C3 hash mismatch")
 )
 
 const (
@@ -184,7 +195,7 @@ func EncryptWithPublicKey(pub *ecdsa.PublicKey, plaintext []byte, window int, mo
 	}
 	coordLen := (curve.Params().BitSize + 7) / 8
 
-	k, err := RandScalar(curve, rand.Reader)
+	k, err := RandScalar(curve, utils.Rand())
 	if err != nil {
 		return nil, err
 	}
@@ -241,15 +252,24 @@ func DecryptWithPrivateKey(pri *ecdsa.PrivateKey, ciphertext []byte, window int,
 		return nil, err
 	}
 
+	// Reject C1 before multiplying it by the private key
+	if !isValidPoint(curve, src.keyX, src.keyY) {
+		return nil, ErrInvalidPoint
+	}
 	x2, y2 := curve.ScalarMult(src.keyX, src.keyY, pri.D.Bytes())
+	if x2 == nil || y2 == nil || (x2.Sign() == 0 && y2.Sign() == 0) {
+		return nil, ErrInvalidPoint
+	}
 	x2b := padLeft(x2.Bytes(), coordLen)
 	y2b := padLeft(y2.Bytes(), coordLen)
 
-	// Decrypt C2
+	// Decrypt C2 into a copy, C2 may alias the caller's ciphertext
 	mask, _ := sm3KDF(len(src.text), x2b, y2b)
+	text := make([]byte, len(src.text))
 	for i := range src.text {
-		src.text[i] ^= mask[i]
+		text[i] = src.text[i] ^ mask[i]
 	}
+	src.text = text
 
 	// Verify C3
 	macInput := make([]byte, 0, len(x2b)+len(src.text)+len(y2b))
@@ -259,12 +279,26 @@ func DecryptWithPrivateKey(pri *ecdsa.PrivateKey, ciphertext []byte, window int,
 	hh := sm3.New()
 	hh.Write(macInput)
 	if !bytesEqual(hh.Sum(nil), src.hash) {
-		return nil, io.ErrUnexpectedEOF
+		return nil, ErrMacMismatch
 	}
 
 	return src.text, nil
 }
 
+// isValidPoint reports whether (x, y) is a point of curve other than the
+// identity, with both coordinates reduced modulo the field prime. SM2 has a
+// cofactor of 1, so any such point lies in the prime order subgroup.
+func isValidPoint(curve elliptic.Curve, x, y *big.Int) bool {
+	p := curve.Params().P
+	if x == nil || y == nil || x.Sign() < 0 || y.Sign() < 0 || x.Cmp(p) >= 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return false
+	}
+	return curve.IsOnCurve(x, y)
+}
+
 // SignWithPrivateKey generates an SM2 signature for the given message
 // It internally calculates ZA and digest (e = SM3(ZA || M))
 func SignWithPrivateKey(pri *ecdsa.PrivateKey, message []byte, uid []byte, mode uint8) ([]byte, error) {
@@ -275,6 +309,9 @@ func SignWithPrivateKey(pri *ecdsa.PrivateKey, message []byte, uid []byte, mode
 	if pri.D.Sign() == 0 || pri.D.Cmp(n) >= 0 {
 		return nil, errors.New("invalid private key")
 	}
+	if len(uid) > maxUIDSize {
+		return nil, errors.New("uid too long")
+	}
 
 	// Calculate ZA = SM3(ENTLA || IDA || a || b || xG || yG || xA || yA)
 	zaInput := getZA(&pri.PublicKey, uid)
@@ -300,7 +337,7 @@ func SignWithPrivateKey(pri *ecdsa.PrivateKey, message []byte, uid []byte, mode
 	// If an invalid signature is really generated, then you absolutely have to buy a lottery ticket!!!
 
 	// Generate random k ∈ [1, n-1]
-	k, err := RandScalar(curve, rand.Reader)
+	k, err := RandScalar(curve, utils.Rand())
 	if err != nil {
 		return nil, err
 	}
@@ -343,6 +380,10 @@ func VerifyWithPublicKey(pub *ecdsa.PublicKey, message []byte, uid []byte, sig [
 	params := curve.Params()
 	n := params.N
 
+	if len(uid) > maxUIDSize {
+		return false
+	}
+
 	sign, err := sm2SignFromBytes(mode, sig, (params.BitSize+7)/8)
 	if err != nil {
 		return false
@@ -269,8 +269,80 @@ func TestDecryptWithPrivateKey(t *testing.T) {
 		}
 		ciphertext[len(ciphertext)-1] ^= 0x01
 		_, err = DecryptWithPrivateKey(pri, ciphertext, 4, c1c2c3)
-		if !errors.Is(err, io.ErrUnexpectedEOF) {
-			t.Fatalf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+		if !errors.Is(err, ErrMacMismatch) {
+			t.Fatalf("expected %v, got %v", ErrMacMismatch, err)
+		}
+	})
+
+	t.Run("C2 mismatch", func(t *testing.T) {
+		ciphertext, err := EncryptWithPublicKey(pub, plaintext, 4, c1c3c2)
+		if err != nil {
+			t.Fatalf("encrypt failed: %v", err)
+		}
+		ciphertext[len(ciphertext)-1] ^= 0x01
+		_, err = DecryptWithPrivateKey(pri, ciphertext, 4, c1c3c2)
+		if !errors.Is(err, ErrMacMismatch) {
+			t.Fatalf("expected %v, got %v", ErrMacMismatch, err)
+		}
+	})
+
+	t.Run("C1 not on curve", func(t *testing.T) {
+		ciphertext, err := EncryptWithPublicKey(pub, plaintext, 4, c1c2c3)
+		if err != nil {
+			t.Fatalf("encrypt failed: %v", err)
+		}
+		ciphertext[1+32+31] ^= 0x01
+		_, err = DecryptWithPrivateKey(pri, ciphertext, 4, c1c2c3)
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Fatalf("expected %v, got %v", ErrInvalidPoint, err)
+		}
+	})
+
+	t.Run("C1 identity", func(t *testing.T) {
+		ciphertext, err := EncryptWithPublicKey(pub, plaintext, 4, c1c2c3)
+		if err != nil {
+			t.Fatalf("encrypt failed: %v", err)
+		}
+		clear(ciphertext[1:65])
+		_, err = DecryptWithPrivateKey(pri, ciphertext, 4, c1c2c3)
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Fatalf("expected %v, got %v", ErrInvalidPoint, err)
+		}
+	})
+
+	t.Run("C1 coordinate not reduced", func(t *testing.T) {
+		ciphertext, err := EncryptWithPublicKey(pub, plaintext, 4, asn1_c1c2c3)
+		if err != nil {
+			t.Fatalf("encrypt failed: %v", err)
+		}
+		src, err := sm2CipherFromBytes(asn1_c1c2c3, ciphertext, 32)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		src.keyX.Add(src.keyX, NewCurve().Params().P)
+		forged, err := src.toBytes(asn1_c1c2c3, 32)
+		if err != nil {
+			t.Fatalf("encode failed: %v", err)
+		}
+		_, err = DecryptWithPrivateKey(pri, forged, 4, asn1_c1c2c3)
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Fatalf("expected %v, got %v", ErrInvalidPoint, err)
+		}
+	})
+
+	t.Run("ciphertext not modified", func(t *testing.T) {
+		for _, mode := range []string{c1c2c3, c1c3c2} {
+			ciphertext, err := EncryptWithPublicKey(pub, plaintext, 4, mode)
+			if err != nil {
+				t.Fatalf("encrypt failed: %v", err)
+			}
+			orig := bytes.Clone(ciphertext)
+			if _, err = DecryptWithPrivateKey(pri, ciphertext, 4, mode); err != nil {
+				t.Fatalf("decrypt failed: %v", err)
+			}
+			if !bytes.Equal(ciphertext, orig) {
+				t.Fatalf("ciphertext modified in %s mode", mode)
+			}
 		}
 	})
 }
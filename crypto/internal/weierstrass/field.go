@@ -0,0 +1,187 @@
+package weierstrass
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// field implements arithmetic modulo an odd prime p in the Montgomery domain,
+// on fixed-width little-endian 64-bit limbs. Every operation runs the same
+// instructions for all operands of the field, so its timing does not depend
+// on secret values.
+type field struct {
+	n    int      // Number of limbs
+	mod  *big.Int // Modulus
+	p    []uint64 // Modulus limbs
+	pInv uint64   // -p⁻¹ mod 2⁶⁴
+	rr   []uint64 // R² mod p, where R = 2^(64n)
+	exp  []byte   // p - 2, the exponent of the inverse, big-endian
+}
+
+// element is a field element in Montgomery form, x·R mod p.
+type element []uint64
+
+// newField returns the field modulo p.
+func newField(p *big.Int) *field {
+	n := (p.BitLen() + 63) / 64
+	f := &field{n: n, mod: p, p: f64(p, n)}
+	// Newton iteration for p⁻¹ mod 2⁶⁴, each step doubles the correct bits
+	inv := f.p[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - f.p[0]*inv
+	}
+	f.pInv = -inv
+	rr := new(big.Int).Lsh(big.NewInt(1), uint(128*n))
+	f.rr = f64(rr.Mod(rr, p), n)
+	f.exp = new(big.Int).Sub(p, big.NewInt(2)).Bytes()
+	return f
+}
+
+// f64 returns the n little-endian limbs of x, which must fit.
+func f64(x *big.Int, n int) []uint64 {
+	buf := x.FillBytes(make([]byte, 8*n))
+	out := make([]uint64, n)
+	for i := range out {
+		for _, b := range buf[len(buf)-8*(i+1) : len(buf)-8*i] {
+			out[i] = out[i]<<8 | uint64(b)
+		}
+	}
+	return out
+}
+
+// zero returns the element 0.
+func (f *field) zero() element {
+	return make(element, f.n)
+}
+
+// one returns the element 1.
+func (f *field) one() element {
+	return f.fromBig(big.NewInt(1))
+}
+
+// fromBig returns the element of x, which is reduced modulo p first.
+func (f *field) fromBig(x *big.Int) element {
+	z := f.zero()
+	f.mul(z, f64(new(big.Int).Mod(x, f.mod), f.n), f.rr)
+	return z
+}
+
+// toBig returns x as an integer in [0, p).
+func (f *field) toBig(x element) *big.Int {
+	one := make([]uint64, f.n)
+	one[0] = 1
+	z := f.zero()
+	f.mul(z, x, one)
+	out := new(big.Int)
+	for i := f.n - 1; i >= 0; i-- {
+		out.Lsh(out, 64).Or(out, new(big.Int).SetUint64(z[i]))
+	}
+	return out
+}
+
+// add sets z = x + y mod p.
+func (f *field) add(z, x, y element) {
+	t := make([]uint64, f.n)
+	var carry uint64
+	for i := range t {
+		t[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	f.reduce(z, t, carry)
+}
+
+// sub sets z = x - y mod p.
+func (f *field) sub(z, x, y element) {
+	t := make([]uint64, f.n)
+	var borrow uint64
+	for i := range t {
+		t[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	// Add p back when the subtraction wrapped
+	mask := -borrow
+	var carry uint64
+	for i := range t {
+		z[i], carry = bits.Add64(t[i], f.p[i]&mask, carry)
+	}
+}
+
+// mul sets z = x·y·R⁻¹ mod p with the CIOS Montgomery multiplication.
+func (f *field) mul(z, x, y []uint64) {
+	n := f.n
+	t := make([]uint64, n+2)
+	for i := 0; i < n; i++ {
+		var c, cc, hi, lo uint64
+		for j := 0; j < n; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[n], cc = bits.Add64(t[n], c, 0)
+		t[n+1] = cc
+
+		m := t[0] * f.pInv
+		hi, lo = bits.Mul64(m, f.p[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(m, f.p[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[n-1], cc = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + cc
+	}
+	f.reduce(z, t[:n], t[n])
+}
+
+// reduce sets z to t + carry·2^(64n), less than 2p, reduced modulo p.
+func (f *field) reduce(z, t []uint64, carry uint64) {
+	u := make([]uint64, f.n)
+	var borrow uint64
+	for i := range u {
+		u[i], borrow = bits.Sub64(t[i], f.p[i], borrow)
+	}
+	// Keep t - p unless t < p, that is unless it borrowed without a carry
+	mask := -(carry | (borrow ^ 1))
+	for i := range z {
+		z[i] = u[i]&mask | t[i]&^mask
+	}
+}
+
+// inv sets z = x⁻¹ mod p, or 0 for x = 0, by raising x to the public p - 2.
+func (f *field) inv(z, x element) {
+	r := f.one()
+	for _, b := range f.exp {
+		for bit := 7; bit >= 0; bit-- {
+			f.mul(r, r, r)
+			if b>>bit&1 == 1 {
+				f.mul(r, r, x)
+			}
+		}
+	}
+	copy(z, r)
+}
+
+// isZero returns 1 if x is 0 and 0 otherwise.
+func (f *field) isZero(x element) uint64 {
+	var acc uint64
+	for _, v := range x {
+		acc |= v
+	}
+	return 1 ^ (acc|-acc)>>63
+}
+
+// swap exchanges x and y if bit is 1 and leaves them otherwise.
+func swap(x, y element, bit uint64) {
+	mask := -bit
+	for i := range x {
+		t := (x[i] ^ y[i]) & mask
+		x[i] ^= t
+		y[i] ^= t
+	}
+}
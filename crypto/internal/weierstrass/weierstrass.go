@@ -0,0 +1,323 @@
+// Package weierstrass implements elliptic curves in short Weierstrass form,
+// y² = x³ + ax + b, as elliptic.Curve values. The standard library only covers
+// curves with a = -3, this package also handles curves such as secp256k1 and
+// the Brainpool curves. Add and Double use Jacobian coordinates and math/big
+// and are not constant time, they suit public points only. ScalarMult and
+// ScalarBaseMult, which handle private keys and nonces, run a Montgomery
+// ladder on fixed-width field elements whose timing does not depend on the
+// scalar.
+package weierstrass
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// Ensure *curve implements elliptic.Curve interface.
+var _ elliptic.Curve = (*curve)(nil)
+
+// curve implements a short Weierstrass curve.
+type curve struct {
+	params *elliptic.CurveParams
+	a      *big.Int // Curve coefficient a, reduced modulo p
+	field  *field   // Field of the constant-time ladder
+	fa     element  // Coefficient a in the field
+	fb3    element  // 3b in the field
+}
+
+// New returns the curve with the given parameters and coefficient a.
+func New(params *elliptic.CurveParams, a *big.Int) elliptic.Curve {
+	f := newField(params.P)
+	return &curve{
+		params: params,
+		a:      new(big.Int).Mod(a, params.P),
+		field:  f,
+		fa:     f.fromBig(a),
+		fb3:    f.fromBig(new(big.Int).Mul(params.B, big.NewInt(3))),
+	}
+}
+
+// Params returns the curve parameters.
+func (c *curve) Params() *elliptic.CurveParams { return c.params }
+
+// A returns the curve coefficient a.
+func (c *curve) A() *big.Int { return new(big.Int).Set(c.a) }
+
+// IsOnCurve reports whether (x, y) satisfies y² = x³ + ax + b with reduced
+// coordinates.
+func (c *curve) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	if x == nil || y == nil || x.Sign() < 0 || y.Sign() < 0 || x.Cmp(p) >= 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, p)
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Add(rhs, c.a)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, c.params.B)
+	rhs.Mod(rhs, p)
+	return lhs.Cmp(rhs) == 0
+}
+
+// Add returns (x1, y1) + (x2, y2). The point at infinity is (0, 0).
+func (c *curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return c.affine(c.add(c.jacobian(x1, y1), c.jacobian(x2, y2)))
+}
+
+// Double returns 2 * (x1, y1).
+func (c *curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return c.affine(c.double(c.jacobian(x1, y1)))
+}
+
+// ScalarMult returns k * (x1, y1), where k is a big-endian integer. The ladder
+// runs over the bits of k left padded to the size of the group order, with
+// one complete addition and one doubling per bit, so its timing depends on
+// neither the value nor the bit length of k.
+func (c *curve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	f := c.field
+	r0 := c.infinity()
+	r1 := c.infinity()
+	if x1.Sign() != 0 || y1.Sign() != 0 {
+		r1 = projective{f.fromBig(x1), f.fromBig(y1), f.one()}
+	}
+	if size := (c.params.N.BitLen() + 7) / 8; len(k) < size {
+		k = append(make([]byte, size-len(k)), k...)
+	}
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			s := uint64(b>>bit) & 1
+			r0.swap(r1, s)
+			r1 = c.complete(r0, r1)
+			r0 = c.complete(r0, r0)
+			r0.swap(r1, s)
+		}
+	}
+	return c.projectiveAffine(r0)
+}
+
+// ScalarBaseMult returns k * G, where k is a big-endian integer.
+func (c *curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
+
+// projective is a point in projective coordinates, (x, y) = (X/Z, Y/Z), on
+// field elements. The point at infinity is (0 : 1 : 0).
+type projective struct {
+	x, y, z element
+}
+
+// swap exchanges q and r if bit is 1 and leaves them otherwise.
+func (q projective) swap(r projective, bit uint64) {
+	swap(q.x, r.x, bit)
+	swap(q.y, r.y, bit)
+	swap(q.z, r.z, bit)
+}
+
+// infinity returns the point at infinity.
+func (c *curve) infinity() projective {
+	f := c.field
+	return projective{f.zero(), f.one(), f.zero()}
+}
+
+// complete returns q1 + q2 with the complete formulas of Renes, Costello and
+// Batina (Algorithm 1 of ePrint 2015/1060), which hold for all points of a
+// prime order curve, doublings and the point at infinity included, without
+// branches.
+func (c *curve) complete(q1, q2 projective) projective {
+	f := c.field
+	t0, t1, t2, t3, t4, t5 := f.zero(), f.zero(), f.zero(), f.zero(), f.zero(), f.zero()
+	x3, y3, z3 := f.zero(), f.zero(), f.zero()
+	f.mul(t0, q1.x, q2.x)
+	f.mul(t1, q1.y, q2.y)
+	f.mul(t2, q1.z, q2.z)
+	f.add(t3, q1.x, q1.y)
+	f.add(t4, q2.x, q2.y)
+	f.mul(t3, t3, t4)
+	f.add(t4, t0, t1)
+	f.sub(t3, t3, t4)
+	f.add(t4, q1.x, q1.z)
+	f.add(t5, q2.x, q2.z)
+	f.mul(t4, t4, t5)
+	f.add(t5, t0, t2)
+	f.sub(t4, t4, t5)
+	f.add(t5, q1.y, q1.z)
+	f.add(x3, q2.y, q2.z)
+	f.mul(t5, t5, x3)
+	f.add(x3, t1, t2)
+	f.sub(t5, t5, x3)
+	f.mul(z3, c.fa, t4)
+	f.mul(x3, c.fb3, t2)
+	f.add(z3, x3, z3)
+	f.sub(x3, t1, z3)
+	f.add(z3, t1, z3)
+	f.mul(y3, x3, z3)
+	f.add(t1, t0, t0)
+	f.add(t1, t1, t0)
+	f.mul(t2, c.fa, t2)
+	f.mul(t4, c.fb3, t4)
+	f.add(t1, t1, t2)
+	f.sub(t2, t0, t2)
+	f.mul(t2, c.fa, t2)
+	f.add(t4, t4, t2)
+	f.mul(t2, t1, t4)
+	f.add(y3, y3, t2)
+	f.mul(t2, t5, t4)
+	f.mul(x3, x3, t3)
+	f.sub(x3, x3, t2)
+	f.mul(t2, t3, t1)
+	f.mul(z3, z3, t5)
+	f.add(z3, z3, t2)
+	return projective{x3, y3, z3}
+}
+
+// projectiveAffine converts a projective point, mapping infinity to (0, 0).
+func (c *curve) projectiveAffine(q projective) (*big.Int, *big.Int) {
+	f := c.field
+	if f.isZero(q.z) == 1 {
+		return new(big.Int), new(big.Int)
+	}
+	zinv := f.zero()
+	f.inv(zinv, q.z)
+	f.mul(q.x, q.x, zinv)
+	f.mul(q.y, q.y, zinv)
+	return f.toBig(q.x), f.toBig(q.y)
+}
+
+// point is a point in Jacobian coordinates, (x, y) = (X/Z², Y/Z³).
+// Z = 0 is the point at infinity.
+type point struct {
+	x, y, z *big.Int
+}
+
+// jacobian converts an affine point, mapping (0, 0) to infinity.
+func (c *curve) jacobian(x, y *big.Int) point {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return point{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return point{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+// affine converts a Jacobian point, mapping infinity to (0, 0).
+func (c *curve) affine(q point) (*big.Int, *big.Int) {
+	if q.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	p := c.params.P
+	zinv := new(big.Int).ModInverse(q.z, p)
+	zinv2 := new(big.Int).Mul(zinv, zinv)
+	x := new(big.Int).Mul(q.x, zinv2)
+	x.Mod(x, p)
+	zinv2.Mul(zinv2, zinv)
+	y := new(big.Int).Mul(q.y, zinv2)
+	y.Mod(y, p)
+	return x, y
+}
+
+// double returns 2q using the dbl-2007-bl formulas.
+func (c *curve) double(q point) point {
+	p := c.params.P
+	if q.z.Sign() == 0 || q.y.Sign() == 0 {
+		return point{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	xx := new(big.Int).Mul(q.x, q.x)
+	xx.Mod(xx, p)
+	yy := new(big.Int).Mul(q.y, q.y)
+	yy.Mod(yy, p)
+	yyyy := new(big.Int).Mul(yy, yy)
+	yyyy.Mod(yyyy, p)
+	zz := new(big.Int).Mul(q.z, q.z)
+	zz.Mod(zz, p)
+	// s = 2 * ((x + yy)² - xx - yyyy)
+	s := new(big.Int).Add(q.x, yy)
+	s.Mul(s, s)
+	s.Sub(s, xx)
+	s.Sub(s, yyyy)
+	s.Lsh(s, 1)
+	s.Mod(s, p)
+	// m = 3xx + a * zz²
+	m := new(big.Int).Mul(zz, zz)
+	m.Mul(m, c.a)
+	m.Add(m, new(big.Int).Lsh(xx, 1))
+	m.Add(m, xx)
+	m.Mod(m, p)
+	// x3 = m² - 2s
+	x3 := new(big.Int).Mul(m, m)
+	x3.Sub(x3, new(big.Int).Lsh(s, 1))
+	x3.Mod(x3, p)
+	// y3 = m * (s - x3) - 8yyyy
+	y3 := new(big.Int).Sub(s, x3)
+	y3.Mul(y3, m)
+	y3.Sub(y3, new(big.Int).Lsh(yyyy, 3))
+	y3.Mod(y3, p)
+	// z3 = (y + z)² - yy - zz
+	z3 := new(big.Int).Add(q.y, q.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, yy)
+	z3.Sub(z3, zz)
+	z3.Mod(z3, p)
+	return point{x3, y3, z3}
+}
+
+// add returns q1 + q2 using the add-2007-bl formulas.
+func (c *curve) add(q1, q2 point) point {
+	p := c.params.P
+	if q1.z.Sign() == 0 {
+		return q2
+	}
+	if q2.z.Sign() == 0 {
+		return q1
+	}
+	z1z1 := new(big.Int).Mul(q1.z, q1.z)
+	z1z1.Mod(z1z1, p)
+	z2z2 := new(big.Int).Mul(q2.z, q2.z)
+	z2z2.Mod(z2z2, p)
+	u1 := new(big.Int).Mul(q1.x, z2z2)
+	u1.Mod(u1, p)
+	u2 := new(big.Int).Mul(q2.x, z1z1)
+	u2.Mod(u2, p)
+	s1 := new(big.Int).Mul(q1.y, q2.z)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, p)
+	s2 := new(big.Int).Mul(q2.y, q1.z)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, p)
+	if u1.Cmp(u2) == 0 {
+		if s1.Cmp(s2) == 0 {
+			return c.double(q1)
+		}
+		return point{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	h := new(big.Int).Sub(u2, u1)
+	h.Mod(h, p)
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i)
+	i.Mod(i, p)
+	j := new(big.Int).Mul(h, i)
+	j.Mod(j, p)
+	r := new(big.Int).Sub(s2, s1)
+	r.Lsh(r, 1)
+	r.Mod(r, p)
+	v := new(big.Int).Mul(u1, i)
+	v.Mod(v, p)
+	// x3 = r² - j - 2v
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	x3.Mod(x3, p)
+	// y3 = r * (v - x3) - 2 * s1 * j
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r)
+	s1.Mul(s1, j)
+	s1.Lsh(s1, 1)
+	y3.Sub(y3, s1)
+	y3.Mod(y3, p)
+	// z3 = ((z1 + z2)² - z1z1 - z2z2) * h
+	z3 := new(big.Int).Add(q1.z, q2.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	z3.Mod(z3, p)
+	return point{x3, y3, z3}
+}
@@ -0,0 +1,89 @@
+package weierstrass
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurve(t *testing.T) {
+	// P-256 has a = -3, so the generic arithmetic can be checked against the
+	// standard library.
+	std := elliptic.P256()
+	c := New(std.Params(), big.NewInt(-3))
+	params := c.Params()
+	assert.Equal(t, new(big.Int).Sub(params.P, big.NewInt(3)), c.(*curve).A())
+	assert.True(t, c.IsOnCurve(params.Gx, params.Gy))
+	assert.False(t, c.IsOnCurve(params.Gx, params.Gx))
+	assert.False(t, c.IsOnCurve(nil, params.Gy))
+
+	for i := 0; i < 8; i++ {
+		k, err := rand.Int(rand.Reader, params.N)
+		require.NoError(t, err)
+		x, y := c.ScalarBaseMult(k.Bytes())
+		ex, ey := std.ScalarBaseMult(k.Bytes())
+		assert.Equal(t, ex, x)
+		assert.Equal(t, ey, y)
+
+		dx, dy := c.Double(x, y)
+		ex, ey = std.Double(ex, ey)
+		assert.Equal(t, ex, dx)
+		assert.Equal(t, ey, dy)
+
+		sx, sy := c.Add(x, y, params.Gx, params.Gy)
+		ex, ey = std.Add(x, y, params.Gx, params.Gy)
+		assert.Equal(t, ex, sx)
+		assert.Equal(t, ey, sy)
+	}
+
+	t.Run("standard curves", func(t *testing.T) {
+		// The ladder works on limbs, so check it on moduli of every width.
+		for _, std := range []elliptic.Curve{elliptic.P224(), elliptic.P384(), elliptic.P521()} {
+			params := std.Params()
+			c := New(params, big.NewInt(-3))
+			for i := 0; i < 4; i++ {
+				k, err := rand.Int(rand.Reader, params.N)
+				require.NoError(t, err)
+				x, y := c.ScalarBaseMult(k.Bytes())
+				ex, ey := std.ScalarBaseMult(k.Bytes())
+				assert.Equal(t, ex, x, params.Name)
+				assert.Equal(t, ey, y, params.Name)
+
+				x, y = c.ScalarMult(ex, ey, k.Bytes())
+				ex, ey = std.ScalarMult(ex, ey, k.Bytes())
+				assert.Equal(t, ex, x, params.Name)
+				assert.Equal(t, ey, y, params.Name)
+			}
+		}
+	})
+
+	t.Run("scalar width", func(t *testing.T) {
+		x, y := c.ScalarBaseMult([]byte{1})
+		assert.Equal(t, params.Gx, x)
+		assert.Equal(t, params.Gy, y)
+		x, y = c.ScalarBaseMult(append(make([]byte, 40), 2))
+		ex, ey := std.Double(params.Gx, params.Gy)
+		assert.Equal(t, ex, x)
+		assert.Equal(t, ey, y)
+		x, y = c.ScalarBaseMult(nil)
+		assert.Zero(t, x.Sign()+y.Sign())
+		x, y = c.ScalarMult(new(big.Int), new(big.Int), []byte{5})
+		assert.Zero(t, x.Sign()+y.Sign())
+	})
+
+	t.Run("point at infinity", func(t *testing.T) {
+		x, y := c.ScalarBaseMult(params.N.Bytes())
+		assert.Zero(t, x.Sign()+y.Sign())
+		x, y = c.Add(params.Gx, params.Gy, params.Gx, new(big.Int).Sub(params.P, params.Gy))
+		assert.Zero(t, x.Sign()+y.Sign())
+		x, y = c.Add(x, y, params.Gx, params.Gy)
+		assert.Equal(t, params.Gx, x)
+		assert.Equal(t, params.Gy, y)
+		x, y = c.Double(new(big.Int), new(big.Int))
+		assert.Zero(t, x.Sign()+y.Sign())
+	})
+}
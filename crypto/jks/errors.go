@@ -0,0 +1,188 @@
+package jks
+
+import (
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedFormatError represents an error when data is not a supported key store format.
+type UnsupportedFormatError struct {
+	Format string // The detected format, empty when unrecognized
+}
+
+// Error returns a formatted error message describing the unsupported format.
+func (e UnsupportedFormatError) Error() string {
+	if e.Format == "" {
+		return "crypto/jks: unrecognized key store format"
+	}
+	return fmt.Sprintf("crypto/jks: unsupported key store format %s", e.Format)
+}
+
+// Code returns the stable error code DGL-JKS-001.
+func (e UnsupportedFormatError) Code() string {
+	return "DGL-JKS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedFormatError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "", "format", e.Format)
+}
+
+// InvalidFormatError represents an error when a key store is malformed.
+type InvalidFormatError struct {
+	Err error // Underlying error from parsing
+}
+
+// Error returns a formatted error message describing the malformed key store.
+func (e InvalidFormatError) Error() string {
+	return fmt.Sprintf("crypto/jks: invalid key store: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-JKS-002.
+func (e InvalidFormatError) Code() string {
+	return "DGL-JKS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidFormatError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "", errcode.FieldCause, e.Err)
+}
+
+// IntegrityError represents an error when the key store integrity check fails.
+type IntegrityError struct{}
+
+// Error returns a formatted error message describing the integrity failure.
+func (e IntegrityError) Error() string {
+	return "crypto/jks: integrity check failed, wrong password or tampered key store"
+}
+
+// Code returns the stable error code DGL-JKS-003.
+func (e IntegrityError) Code() string {
+	return "DGL-JKS-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e IntegrityError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "")
+}
+
+// UnsupportedAlgorithmError represents an error when a protection algorithm is not supported.
+type UnsupportedAlgorithmError struct {
+	Algorithm asn1.ObjectIdentifier // The unsupported algorithm identifier
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/jks: unsupported algorithm %s", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-JKS-004.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-JKS-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "", errcode.FieldAlgorithm, e.Algorithm.String())
+}
+
+// AliasNotFoundError represents an error when no entry has the requested alias.
+type AliasNotFoundError struct {
+	Alias string // The missing alias
+}
+
+// Error returns a formatted error message describing the missing alias.
+func (e AliasNotFoundError) Error() string {
+	return fmt.Sprintf("crypto/jks: alias %q not found", e.Alias)
+}
+
+// Code returns the stable error code DGL-JKS-005.
+func (e AliasNotFoundError) Code() string {
+	return "DGL-JKS-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AliasNotFoundError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "", "alias", e.Alias)
+}
+
+// EntryTypeError represents an error when an entry is not a private key entry.
+type EntryTypeError struct {
+	Alias string    // The entry alias
+	Type  EntryType // The actual entry type
+}
+
+// Error returns a formatted error message describing the type mismatch.
+func (e EntryTypeError) Error() string {
+	return fmt.Sprintf("crypto/jks: alias %q is a %s entry", e.Alias, e.Type)
+}
+
+// Code returns the stable error code DGL-JKS-006.
+func (e EntryTypeError) Code() string {
+	return "DGL-JKS-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EntryTypeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "", "alias", e.Alias, "type", e.Type)
+}
+
+// DecryptError represents an error when a private key cannot be decrypted with the given password.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return "crypto/jks: failed to decrypt private key, wrong password"
+}
+
+// Code returns the stable error code DGL-JKS-007.
+func (e DecryptError) Code() string {
+	return "DGL-JKS-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "decrypt")
+}
+
+// InvalidKeyError represents an error when a decrypted private key cannot be parsed.
+type InvalidKeyError struct {
+	Err error // Underlying error from PKCS#8 parsing
+}
+
+// Error returns a formatted error message describing the invalid key.
+func (e InvalidKeyError) Error() string {
+	return fmt.Sprintf("crypto/jks: invalid private key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-JKS-008.
+func (e InvalidKeyError) Code() string {
+	return "DGL-JKS-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "key", errcode.FieldCause, e.Err)
+}
+
+// CertificateError represents an error when a stored certificate cannot be parsed.
+type CertificateError struct {
+	Err error // Underlying error from X.509 parsing
+}
+
+// Error returns a formatted error message describing the certificate error.
+func (e CertificateError) Error() string {
+	return fmt.Sprintf("crypto/jks: invalid certificate: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-JKS-009.
+func (e CertificateError) Code() string {
+	return "DGL-JKS-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CertificateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jks", "JKS", "", errcode.FieldCause, e.Err)
+}
@@ -0,0 +1,245 @@
+package jks
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// JKS layout, all integers big endian:
+//
+//	magic uint32, version uint32, count uint32
+//	entries: tag uint32, alias UTF, date int64 in milliseconds, then
+//	  tag 1: key uint32+bytes, chain count uint32, certificates
+//	  tag 2: one certificate
+//	  where a certificate is a type UTF (version 2 only) and uint32+DER bytes
+//	digest [20]byte SHA-1 of password UTF-16BE, "Mighty Aphrodite" and all prior bytes
+//
+// UTF strings are a uint16 length and Java modified UTF-8.
+const (
+	jksMagic   = 0xFEEDFEED
+	jceksMagic = 0xCECECECE
+
+	tagPrivateKey  = 1
+	tagTrustedCert = 2
+
+	// maxEntries bounds the work spent on a hostile key store.
+	maxEntries = 1 << 16
+)
+
+// whitener is mixed into the JKS integrity digest.
+const whitener = "Mighty Aphrodite"
+
+// oidJKSKeyProtector identifies the proprietary Sun key protection algorithm.
+var oidJKSKeyProtector = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+var (
+	errTruncated     = errors.New("truncated data")
+	errNoCertificate = errors.New("entry has no certificate")
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 EncryptedPrivateKeyInfo structure.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// jksReader reads the big endian fields of a JKS stream.
+type jksReader struct {
+	data []byte
+	err  error
+}
+
+// next returns the next n bytes.
+func (r *jksReader) next(n int) []byte {
+	if r.err != nil || n < 0 || len(r.data) < n {
+		r.err = errTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// uint32 reads a big endian uint32.
+func (r *jksReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+// utf reads a Java modified UTF-8 string.
+func (r *jksReader) utf() string {
+	b := r.next(2)
+	if b == nil {
+		return ""
+	}
+	s, ok := decodeModifiedUTF8(r.next(int(binary.BigEndian.Uint16(b))))
+	if !ok && r.err == nil {
+		r.err = errors.New("invalid modified UTF-8 string")
+	}
+	return s
+}
+
+// certificate reads a certificate, preceded by its type in version 2.
+func (r *jksReader) certificate(version uint32) *x509.Certificate {
+	if version == 2 && r.utf() != "X.509" && r.err == nil {
+		r.err = errors.New("unsupported certificate type")
+	}
+	der := r.next(int(r.uint32()))
+	if r.err != nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		r.err = CertificateError{Err: err}
+	}
+	return cert
+}
+
+// decodeJKS decodes a JKS key store and verifies its digest.
+func decodeJKS(data []byte, password string) (*KeyStore, error) {
+	if len(data) < 12+sha1.Size {
+		return nil, InvalidFormatError{Err: errTruncated}
+	}
+	body, digest := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+	h := sha1.New()
+	h.Write(utf16BE(password))
+	h.Write([]byte(whitener))
+	h.Write(body)
+	if !utils.ConstantTimeEqual(h.Sum(nil), digest) {
+		return nil, IntegrityError{}
+	}
+
+	r := &jksReader{data: body[4:]}
+	version, count := r.uint32(), r.uint32()
+	if version != 1 && version != 2 {
+		return nil, InvalidFormatError{Err: errors.New("unsupported JKS version")}
+	}
+	if count > maxEntries {
+		return nil, InvalidFormatError{Err: errors.New("too many entries")}
+	}
+	ks := &KeyStore{Format: FormatJKS}
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		tag := r.uint32()
+		e := &entry{Entry: Entry{Alias: r.utf()}}
+		if b := r.next(8); b != nil {
+			e.Date = time.UnixMilli(int64(binary.BigEndian.Uint64(b)))
+		}
+		switch tag {
+		case tagPrivateKey:
+			e.Type = PrivateKeyEntry
+			e.key = append([]byte(nil), r.next(int(r.uint32()))...)
+			n := r.uint32()
+			if n > maxEntries {
+				return nil, InvalidFormatError{Err: errors.New("certificate chain too long")}
+			}
+			for j := uint32(0); j < n && r.err == nil; j++ {
+				e.Certificates = append(e.Certificates, r.certificate(version))
+			}
+		case tagTrustedCert:
+			e.Type = TrustedCertificateEntry
+			e.Certificates = []*x509.Certificate{r.certificate(version)}
+		default:
+			if r.err == nil {
+				r.err = errors.New("unknown entry tag")
+			}
+		}
+		ks.entries = append(ks.entries, e)
+	}
+	if r.err == nil && len(r.data) > 0 {
+		r.err = errors.New("trailing data")
+	}
+	if r.err != nil {
+		if err, ok := r.err.(CertificateError); ok {
+			return nil, err
+		}
+		return nil, InvalidFormatError{Err: r.err}
+	}
+	return ks, nil
+}
+
+// decryptJKSKey removes the proprietary JKS key protection, which XORs the key
+// with a SHA-1 keystream seeded by a salt and checks a SHA-1 digest of the result.
+func decryptJKSKey(data []byte, password string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(data, &info); err != nil || len(rest) > 0 {
+		return nil, InvalidFormatError{Err: errors.New("malformed protected key")}
+	}
+	if !info.Algorithm.Algorithm.Equal(oidJKSKeyProtector) {
+		return nil, UnsupportedAlgorithmError{Algorithm: info.Algorithm.Algorithm}
+	}
+	enc := info.EncryptedData
+	if len(enc) < 2*sha1.Size {
+		return nil, InvalidFormatError{Err: errors.New("protected key too short")}
+	}
+	salt, check := enc[:sha1.Size], enc[len(enc)-sha1.Size:]
+	enc = enc[sha1.Size : len(enc)-sha1.Size]
+	pass := utf16BE(password)
+	key := make([]byte, len(enc))
+	block := salt
+	for i := 0; i < len(enc); i += sha1.Size {
+		h := sha1.New()
+		h.Write(pass)
+		h.Write(block)
+		block = h.Sum(nil)
+		subtle.XORBytes(key[i:], enc[i:], block)
+	}
+	h := sha1.New()
+	h.Write(pass)
+	h.Write(key)
+	if !utils.ConstantTimeEqual(h.Sum(nil), check) {
+		utils.SecureWipe(key)
+		return nil, DecryptError{}
+	}
+	return key, nil
+}
+
+// utf16BE encodes a password the way Java encodes char arrays.
+func utf16BE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.BigEndian.PutUint16(out[2*i:], u)
+	}
+	return out
+}
+
+// decodeModifiedUTF8 decodes Java modified UTF-8, where NUL is two bytes and
+// supplementary characters are encoded surrogate pairs.
+func decodeModifiedUTF8(b []byte) (string, bool) {
+	units := make([]uint16, 0, len(b))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c < 0x80:
+			units = append(units, uint16(c))
+			i++
+		case c&0xe0 == 0xc0 && i+1 < len(b) && b[i+1]&0xc0 == 0x80:
+			units = append(units, uint16(c&0x1f)<<6|uint16(b[i+1]&0x3f))
+			i += 2
+		case c&0xf0 == 0xe0 && i+2 < len(b) && b[i+1]&0xc0 == 0x80 && b[i+2]&0xc0 == 0x80:
+			units = append(units, uint16(c&0x0f)<<12|uint16(b[i+1]&0x3f)<<6|uint16(b[i+2]&0x3f))
+			i += 3
+		default:
+			return "", false
+		}
+	}
+	runes := utf16.Decode(units)
+	for _, r := range runes {
+		if r == utf8.RuneError {
+			return "", false
+		}
+	}
+	return string(runes), true
+}
@@ -0,0 +1,453 @@
+package jks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixture decodes a wrapped base64 test key store.
+func fixture(s string) []byte {
+	b, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(s, "\n", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// jksEntry is an entry written by buildJKS.
+type jksEntry struct {
+	alias       string
+	date        time.Time
+	key         []byte // PKCS#8, nil for a trusted certificate
+	keyPassword string
+	certs       []*x509.Certificate
+}
+
+// buildJKS writes a JKS key store the way Java's sun.security.provider.JavaKeyStore does.
+func buildJKS(t *testing.T, password string, version uint32, entries ...jksEntry) []byte {
+	utf := func(out []byte, s string) []byte {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(s)))
+		return append(out, s...)
+	}
+	cert := func(out []byte, c *x509.Certificate) []byte {
+		if version == 2 {
+			out = utf(out, "X.509")
+		}
+		out = binary.BigEndian.AppendUint32(out, uint32(len(c.Raw)))
+		return append(out, c.Raw...)
+	}
+	out := binary.BigEndian.AppendUint32(nil, jksMagic)
+	out = binary.BigEndian.AppendUint32(out, version)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(entries)))
+	for _, e := range entries {
+		if e.key == nil {
+			out = binary.BigEndian.AppendUint32(out, tagTrustedCert)
+			out = utf(out, e.alias)
+			out = binary.BigEndian.AppendUint64(out, uint64(e.date.UnixMilli()))
+			out = cert(out, e.certs[0])
+			continue
+		}
+		out = binary.BigEndian.AppendUint32(out, tagPrivateKey)
+		out = utf(out, e.alias)
+		out = binary.BigEndian.AppendUint64(out, uint64(e.date.UnixMilli()))
+		protected := protectJKSKey(t, e.key, e.keyPassword)
+		out = binary.BigEndian.AppendUint32(out, uint32(len(protected)))
+		out = append(out, protected...)
+		out = binary.BigEndian.AppendUint32(out, uint32(len(e.certs)))
+		for _, c := range e.certs {
+			out = cert(out, c)
+		}
+	}
+	h := sha1.New()
+	h.Write(utf16BE(password))
+	h.Write([]byte(whitener))
+	h.Write(out)
+	return h.Sum(out)
+}
+
+// protectJKSKey applies the proprietary JKS key protection.
+func protectJKSKey(t *testing.T, key []byte, password string) []byte {
+	pass := utf16BE(password)
+	salt := make([]byte, sha1.Size)
+	_, _ = rand.Read(salt)
+	enc := append([]byte(nil), salt...)
+	block := salt
+	for i := 0; i < len(key); i += sha1.Size {
+		h := sha1.New()
+		h.Write(pass)
+		h.Write(block)
+		block = h.Sum(nil)
+		for j := 0; j < sha1.Size && i+j < len(key); j++ {
+			enc = append(enc, key[i+j]^block[j])
+		}
+	}
+	h := sha1.New()
+	h.Write(pass)
+	h.Write(key)
+	der, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidJKSKeyProtector, Parameters: asn1.NullRawValue},
+		EncryptedData: h.Sum(enc),
+	})
+	require.NoError(t, err)
+	return der
+}
+
+// newChain creates a CA and a leaf certificate with the leaf key.
+func newChain(t *testing.T) (*ecdsa.PrivateKey, *x509.Certificate, *x509.Certificate) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Dongle Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, caKey.Public(), caKey)
+	require.NoError(t, err)
+	ca, _ := x509.ParseCertificate(der)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl = &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "dongle"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	require.NoError(t, err)
+	leaf, _ := x509.ParseCertificate(der)
+	return key, leaf, ca
+}
+
+func TestDecode_JKS(t *testing.T) {
+	key, leaf, ca := newChain(t)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+	date := time.UnixMilli(1700000000123)
+
+	for _, version := range []uint32{1, 2} {
+		data := buildJKS(t, "changeit", version,
+			jksEntry{alias: "server", date: date, key: pkcs8, keyPassword: "keypass", certs: []*x509.Certificate{leaf, ca}},
+			jksEntry{alias: "root ca", date: date, certs: []*x509.Certificate{ca}},
+		)
+		ks, err := Decode(data, "changeit")
+		require.NoError(t, err)
+		assert.Equal(t, FormatJKS, ks.Format)
+
+		entries := ks.Entries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, Entry{Alias: "server", Type: PrivateKeyEntry, Date: date, Certificates: []*x509.Certificate{leaf, ca}}, entries[0])
+		assert.Equal(t, Entry{Alias: "root ca", Type: TrustedCertificateEntry, Date: date, Certificates: []*x509.Certificate{ca}}, entries[1])
+
+		got, chain, err := ks.PrivateKey("SERVER", "keypass")
+		require.NoError(t, err)
+		assert.True(t, key.Equal(got))
+		assert.Equal(t, []*x509.Certificate{leaf, ca}, chain)
+
+		_, _, err = ks.PrivateKey("server", "changeit")
+		assert.Equal(t, DecryptError{}, err)
+		_, _, err = ks.PrivateKey("root ca", "changeit")
+		assert.Equal(t, EntryTypeError{Alias: "root ca", Type: TrustedCertificateEntry}, err)
+		assert.Equal(t, `crypto/jks: alias "root ca" is a trusted certificate entry`, err.Error())
+		_, _, err = ks.PrivateKey("missing", "changeit")
+		assert.Equal(t, AliasNotFoundError{Alias: "missing"}, err)
+
+		cert, err := ks.Certificate("root ca")
+		require.NoError(t, err)
+		assert.Equal(t, ca, cert)
+		cert, err = ks.Certificate("server")
+		require.NoError(t, err)
+		assert.Equal(t, leaf, cert)
+		_, err = ks.Certificate("missing")
+		assert.Equal(t, AliasNotFoundError{Alias: "missing"}, err)
+
+		_, err = Decode(data, "wrong")
+		assert.Equal(t, IntegrityError{}, err)
+		data[20] ^= 1
+		_, err = Decode(data, "changeit")
+		assert.Equal(t, IntegrityError{}, err)
+	}
+}
+
+func TestDecode_JKSInvalid(t *testing.T) {
+	_, leaf, _ := newChain(t)
+	sign := func(body []byte) []byte {
+		h := sha1.New()
+		h.Write(utf16BE("changeit"))
+		h.Write([]byte(whitener))
+		h.Write(body)
+		return h.Sum(body)
+	}
+	header := func(version, count uint32) []byte {
+		out := binary.BigEndian.AppendUint32(nil, jksMagic)
+		out = binary.BigEndian.AppendUint32(out, version)
+		return binary.BigEndian.AppendUint32(out, count)
+	}
+	valid := buildJKS(t, "changeit", 2, jksEntry{alias: "ca", certs: []*x509.Certificate{leaf}})
+	body := valid[:len(valid)-sha1.Size]
+
+	for name, data := range map[string][]byte{
+		"version":   sign(header(3, 0)),
+		"count":     sign(header(2, maxEntries+1)),
+		"truncated": sign(body[:len(body)-1]),
+		"trailing":  sign(append(append([]byte(nil), body...), 0)),
+		"tag":       sign(append(header(2, 1), 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)),
+		"cert type": sign(append(header(2, 1), 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 5, 'X', '.', '5', '0', '0')),
+		"alias":     sign(append(header(2, 1), 0, 0, 0, 2, 0, 1, 0xff)),
+		"short":     binary.BigEndian.AppendUint32(nil, jksMagic),
+	} {
+		_, err := Decode(data, "changeit")
+		assert.IsType(t, InvalidFormatError{}, err, name)
+	}
+
+	bad := sign(append(header(1, 1), append([]byte{0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3}, 1, 2, 3)...))
+	_, err := Decode(bad, "changeit")
+	assert.IsType(t, CertificateError{}, err)
+
+	_, err = Decode([]byte{0xce, 0xce, 0xce, 0xce, 0, 0, 0, 2}, "changeit")
+	assert.Equal(t, UnsupportedFormatError{Format: "JCEKS"}, err)
+	assert.Equal(t, "crypto/jks: unsupported key store format JCEKS", err.Error())
+	_, err = Decode([]byte("not a key store"), "changeit")
+	assert.Equal(t, UnsupportedFormatError{}, err)
+	_, err = Decode([]byte{0x30, 0x00}, "changeit")
+	assert.Equal(t, UnsupportedFormatError{}, err)
+}
+
+func TestDecryptJKSKey(t *testing.T) {
+	protected := protectJKSKey(t, []byte("key material longer than one SHA-1 block"), "pass")
+	key, err := decryptJKSKey(protected, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key material longer than one SHA-1 block"), key)
+
+	der, _ := asn1.Marshal(encryptedPrivateKeyInfo{Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPBES2}, EncryptedData: make([]byte, 40)})
+	_, err = decryptJKSKey(der, "pass")
+	assert.Equal(t, UnsupportedAlgorithmError{Algorithm: oidPBES2}, err)
+	der, _ = asn1.Marshal(encryptedPrivateKeyInfo{Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidJKSKeyProtector}, EncryptedData: make([]byte, 39)})
+	_, err = decryptJKSKey(der, "pass")
+	assert.IsType(t, InvalidFormatError{}, err)
+	_, err = decryptJKSKey([]byte{1}, "pass")
+	assert.IsType(t, InvalidFormatError{}, err)
+}
+
+func TestDecode_PKCS12(t *testing.T) {
+	for name, data := range map[string]string{"modern": modernP12, "legacy": legacyP12} {
+		t.Run(name, func(t *testing.T) {
+			ks, err := Decode(fixture(data), "changeit")
+			require.NoError(t, err)
+			assert.Equal(t, FormatPKCS12, ks.Format)
+
+			entries := ks.Entries()
+			require.Len(t, entries, 1)
+			assert.Equal(t, "dongle", entries[0].Alias)
+			assert.Equal(t, PrivateKeyEntry, entries[0].Type)
+			assert.True(t, entries[0].Date.IsZero())
+
+			key, chain, err := ks.PrivateKey("dongle", "changeit")
+			require.NoError(t, err)
+			require.Len(t, chain, 2)
+			assert.Equal(t, "dongle", chain[0].Subject.CommonName)
+			assert.Equal(t, "Dongle Test CA", chain[1].Subject.CommonName)
+			assert.True(t, key.(*ecdsa.PrivateKey).PublicKey.Equal(chain[0].PublicKey))
+			assert.NoError(t, chain[0].CheckSignatureFrom(chain[1]))
+
+			_, _, err = ks.PrivateKey("dongle", "wrong")
+			assert.Error(t, err)
+			_, err = Decode(fixture(data), "wrong")
+			assert.Equal(t, IntegrityError{}, err)
+		})
+	}
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		ks, err := Decode(fixture(trustP12), "changeit")
+		require.NoError(t, err)
+		entries := ks.Entries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "ca", entries[0].Alias)
+		assert.Equal(t, TrustedCertificateEntry, entries[0].Type)
+		cert, err := ks.Certificate("ca")
+		require.NoError(t, err)
+		assert.Equal(t, "Dongle Test CA", cert.Subject.CommonName)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		data := fixture(modernP12)
+		data[100] ^= 1
+		_, err := Decode(data, "changeit")
+		assert.Error(t, err)
+	})
+}
+
+func TestPKCS12KDF(t *testing.T) {
+	// The SHA-1 vectors published with the BouncyCastle PKCS#12 test suite.
+	pass := bmpPassword("smeg")
+	salt, _ := hex.DecodeString("0a58cf64530d823f")
+	assert.Equal(t, "8aaae6297b6cb04642ab5b077851284eb7128f1a2a7fbca3", hex.EncodeToString(pkcs12KDF(sha1.New, 64, 1, pass, salt, 1, 24)))
+	assert.Equal(t, "79993dfe048d3b76", hex.EncodeToString(pkcs12KDF(sha1.New, 64, 2, pass, salt, 1, 8)))
+}
+
+func TestRC2(t *testing.T) {
+	// Test vectors from RFC 2268 section 5.
+	tests := []struct {
+		key, plaintext, ciphertext string
+		bits                       int
+	}{
+		{"0000000000000000", "0000000000000000", "ebb773f993278eff", 63},
+		{"ffffffffffffffff", "ffffffffffffffff", "278b27e42e2f0d49", 64},
+		{"3000000000000000", "1000000000000001", "30649edf9be7d2c2", 64},
+		{"88", "0000000000000000", "61a8a244adacccf0", 64},
+		{"88bca90e90875a", "0000000000000000", "6ccf4308974c267f", 64},
+		{"88bca90e90875a7f0f79c384627bafb2", "0000000000000000", "1a807d272bbe5db1", 64},
+		{"88bca90e90875a7f0f79c384627bafb2", "0000000000000000", "2269552ab0f85ca6", 128},
+	}
+	for _, tt := range tests {
+		key, _ := hex.DecodeString(tt.key)
+		plaintext, _ := hex.DecodeString(tt.plaintext)
+		block := newRC2(key, tt.bits)
+		assert.Equal(t, rc2BlockSize, block.BlockSize())
+		out := make([]byte, 8)
+		block.Encrypt(out, plaintext)
+		assert.Equal(t, tt.ciphertext, hex.EncodeToString(out))
+		block.Decrypt(out, out)
+		assert.Equal(t, tt.plaintext, hex.EncodeToString(out))
+	}
+}
+
+func TestDecodeModifiedUTF8(t *testing.T) {
+	// NUL as two bytes, a two byte, a three byte and a surrogate pair character.
+	s, ok := decodeModifiedUTF8([]byte{'a', 0xc0, 0x80, 0xc3, 0xa9, 0xe4, 0xb8, 0xad, 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80})
+	assert.True(t, ok)
+	assert.Equal(t, "a\x00é中😀", s)
+
+	for _, b := range [][]byte{{0xff}, {0xc3}, {0xe4, 0xb8}, {0xed, 0xa0, 0xbd}} {
+		_, ok = decodeModifiedUTF8(b)
+		assert.False(t, ok)
+	}
+}
+
+func TestDecryptPBE_Invalid(t *testing.T) {
+	params := func(v any) asn1.RawValue {
+		der, _ := asn1.Marshal(v)
+		return asn1.RawValue{FullBytes: der}
+	}
+	kdf := func(prf asn1.ObjectIdentifier, iterations, keyLen int) pkix.AlgorithmIdentifier {
+		return pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: params(pbkdf2Params{Salt: []byte("salt"), Iterations: iterations, KeyLength: keyLen, PRF: pkix.AlgorithmIdentifier{Algorithm: prf, Parameters: asn1.NullRawValue}})}
+	}
+	iv := params(make([]byte, 16))
+	pbes2 := func(k pkix.AlgorithmIdentifier, scheme asn1.ObjectIdentifier, iv asn1.RawValue) pkix.AlgorithmIdentifier {
+		return pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: params(pbes2Params{KeyDerivationFunc: k, EncryptionScheme: pkix.AlgorithmIdentifier{Algorithm: scheme, Parameters: iv}})}
+	}
+
+	tests := []struct {
+		name string
+		alg  pkix.AlgorithmIdentifier
+		err  error
+	}{
+		{"unknown scheme", pkix.AlgorithmIdentifier{Algorithm: oidData}, UnsupportedAlgorithmError{Algorithm: oidData}},
+		{"unknown kdf", pbes2(pkix.AlgorithmIdentifier{Algorithm: oidData}, oidAES256CBC, iv), UnsupportedAlgorithmError{Algorithm: oidData}},
+		{"unknown prf", pbes2(kdf(oidSHA256, 1, 0), oidAES256CBC, iv), UnsupportedAlgorithmError{Algorithm: oidSHA256}},
+		{"unknown cipher", pbes2(kdf(oidHMACWithSHA256, 1, 0), oidData, iv), UnsupportedAlgorithmError{Algorithm: oidData}},
+		{"iterations", pbes2(kdf(oidHMACWithSHA256, maxIterations+1, 0), oidAES256CBC, iv), InvalidFormatError{Err: errInvalidPBEParameters}},
+		{"key length", pbes2(kdf(oidHMACWithSHA256, 1, 16), oidAES256CBC, iv), InvalidFormatError{Err: errInvalidPBEParameters}},
+		{"iv length", pbes2(kdf(oidHMACWithSHA256, 1, 0), oidAES256CBC, params(make([]byte, 8))), InvalidFormatError{Err: errInvalidPBEParameters}},
+		{"legacy iterations", pkix.AlgorithmIdentifier{Algorithm: oidPBEWithSHAAnd3DES, Parameters: params(pbeParams{Salt: []byte("salt")})}, InvalidFormatError{Err: errInvalidPBEParameters}},
+	}
+	for _, tt := range tests {
+		_, err := decryptPBE(tt.alg, "pass", make([]byte, 16))
+		assert.Equal(t, tt.err, err, tt.name)
+	}
+
+	alg := pbes2(kdf(oidHMACWithSHA256, 1, 32), oidAES256CBC, iv)
+	_, err := decryptPBE(alg, "pass", make([]byte, 15))
+	assert.Equal(t, DecryptError{}, err)
+	legacy := pkix.AlgorithmIdentifier{Algorithm: oidPBEWithSHAAnd128RC2, Parameters: params(pbeParams{Salt: []byte("salt"), Iterations: 1})}
+	_, err = decryptPBE(legacy, "pass", make([]byte, 8))
+	assert.Equal(t, DecryptError{}, err)
+}
+
+// modernP12 was created by OpenSSL 3.0 with "openssl pkcs12 -export -name dongle -certfile ca.crt", PBES2 AES-256-CBC and an HMAC-SHA256 MAC.
+const modernP12 = `
+MIIFqQIBAzCCBV8GCSqGSIb3DQEHAaCCBVAEggVMMIIFSDCCA+IGCSqGSIb3DQEHBqCCA9MwggPP
+AgEAMIIDyAYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAgd9Ha16pGZ
+1wICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEES84jhh74FBS1zyEMyzhO6AggNg6VNn
+95KIXqdzwOlcF3q2jeUA0U/AUZRiC3U5MyhEv7o5dF8jUs7kjurwIwsmoI2fM4UJM43xO7ay/Ewv
+sjXiEBr5Nahr3Rv9Mru8bu+l1U5YH2C5Qa3tTdd7fnzDIA4Gbl+N4QSMrh/RdtokEHB+yAFe/ozx
+9QxolUjWmDT1s9w/EF+Nqq3oaEzzIzGqza6+BnMausIEyBFgBs5u0wuWmEWBxFhnvMHKPbj99KoK
+02OwXTCGIix49/LNsY3wzRIBDRkImjN6t9flLOA3gNnqqCWAv8eVFoEsz1iIkil0imEDr0oF9+bE
+kbrv6BZqYnCyBn/TA+Fj1m0bdKChcpHDuiiJV7f3GoeSLAc2KCpRw5RkWqdVdjHgxb1Htlx3h45x
+OCPcXKxBM1wMFpKTHdQ/NErZinaOCQEqsDJvLCY1HH6H4RWNmkTUv6ML4e0/Xa07puGPjfM0aXEg
+LGLSdQXUNJ6kPgGR25qlWR8V84JUMGmWNMpKITcseJ9TT99b3vEysyrDXJDI0uF28Y94RXro1QNC
+Knx/WTZp+CRcxE57JPnUqZ7DiN0Lr3mtpmkyMbUIW+mRYthoq+bKYKZcMV2JbNREH7lEfGta/QFZ
+oCmFa8dzHNbB1MITM5WdFCOsDgqE6oAAoreiOMB7rT3fkSnVUg90dpSDomI1BECZzJhnbhTkPMgG
+0CUCbvsWV8UfEd4+l7WqiwtjJlWQQMMTZTH4ywfSMXefg6o+Zy1/jLZ7g0RLftPpIb4rFIUhj87t
+e1jCO4sA8JiOJgMkTvmQv+8kyiqSNfwOCwSl9+8fGb5ccE3zD8nGgxVfablJfuJfQJJmG5ShJauM
+ccZ6rEMCjaz1tPUk79pWuTLVX/0SS5lMZLLkGViEOyTX8u7tHn/pBnZ1PkCBRgoCpqMiBg5SGt+X
+vX/2QwA9sskHf7HwHKjyFhm+XVLlv67YHXVse8raS3IV3XAXebLQ7jAAsDacev7Khb1IbeJj/8N0
+e+FIhXRSV2NlQCebVuOg2qK8uJneCa9lKbMtaijoAup//BE0bc/uW5Jh4V7rbKbfN3+OoAF7Vj5W
+aGuAoLDpLU/tnIsZqSQ0zVY2d4V2fs4yeGkkRlDW0LKpnHmixv0MKJ+gZxfswypLSwzv1Y3XX//v
+nzWS5thgMIIBXgYJKoZIhvcNAQcBoIIBTwSCAUswggFHMIIBQwYLKoZIhvcNAQwKAQKgge8wgeww
+VwYJKoZIhvcNAQUNMEowKQYJKoZIhvcNAQUMMBwECHlCiSIvENmmAgIIADAMBggqhkiG9w0CCQUA
+MB0GCWCGSAFlAwQBKgQQ7NCgr6tj2Uxgoc26Ceb8SASBkKE7KbWNkobPfEMZIHIo55ZCJ6X8UCRv
+W96yG7plMyUBMBLsBh4D+6LXusKd28+1zhTFgi13mLFXECKoCZ5t2OMizu4r0tyqGDgNw70HNfyu
+0P1UQojZev65zePQdg7yduwGcJG+XF+RyncSw1SRpb5onlxCrmfAahkO9bzZ+ss5DtZWgM9h0VFS
+EsmVXbYtczFCMBsGCSqGSIb3DQEJFDEOHgwAZABvAG4AZwBsAGUwIwYJKoZIhvcNAQkVMRYEFB2l
+hkIrMJ/GfdO0oPm98ffoCziYMEEwMTANBglghkgBZQMEAgEFAAQgCwSvS71nfFnflGQvJt7K4Ihc
+GCRslApgeT6yO97cWGgECGF4X/piaaTzAgIIAA==`
+
+// legacyP12 was created by OpenSSL 3.0 with "openssl pkcs12 -export -legacy -name dongle -certfile ca.crt", RC2-40 certificates, a 3DES key and an HMAC-SHA1 MAC.
+const legacyP12 = `
+MIIFIwIBAzCCBOkGCSqGSIb3DQEHAaCCBNoEggTWMIIE0jCCA6cGCSqGSIb3DQEHBqCCA5gwggOU
+AgEAMIIDjQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQYwDgQIw4VGo3RMWiwCAggAgIIDYP14j5G4
+ODgkvdXDYHLKOW8ZyiOwbr2vcfFxa1gdnn6CD/z3pEptJmwL76jVY9DU0JCw7Ph0XER9e1g9NJzh
+f+gcjbE8HI8KwGI8Eho7ww0BiRYM+9Xm1WcDX3vf3sqiKFnKvzIV7SJjjMMGrBswHcxgZRTYHaWS
+qpUjQ2nBDAr33d0pooSFMJ4KhpWnb+tX7C9yG5Tk4rYdIyOrF6/f6XPpnjavE3ImyxIXi9l0Gk8o
+vxvF7LeDQV6cQunG6fLr735RUm8pxXWY8R6V6HqBQRJEEuhI0OXwhcPr4t4fXuF605a6tOoTZwgu
+lUQ4lxrnJbkZSAW1oJQZ3yJbCMyKj3POJ4cYaWAp+y3JBDiHLtzBRoqCqKCqXWrUW+STdIp3pife
+UwIW4sHAmlrPWe75XCYCsJQ0+EaSSqpLkX/natHP7HKz+ikSarloI4H1cRPDjpAQ4NGo9Zu7Yxdx
+AbJBswEmHhb6JwISYlrJl4tBAjn6qMtlxERRdPfCJBHWjLnfqkPohayUBeA9sqY8yC6qN9l/Bt/M
+2CopUIUaYxswHHd43jZ7Jx2n+bWeFf0rvhuecuZ8zEPdLGEE8H5sk2oeKq5gI5dxauDcMmt6FmR4
+WtCW3rR46M5HaXQ5i0ifUrAPvfuHbu8DfLBbcjYUSDOflmJkasW/mslBPeo57lwlKar8NbL6kMuH
+JRSTdhOPkSKLHUYRcenyxFOhqO/ZShBB3l9RciZ75V7zKciKGY8tVw8oR8yN5jyexQSW7h41VSoH
+js5j3dZQUvREZEfZCknqXcnpBWpaWSIyEptcRvzbDXMDgRLEJL5RbMDQgtpuNvaASVbVAfCCb1Ey
+RBvjxC7wjpT1Jx57SRrlNpfPd1ganhNBCc8b3RPUF20MsECKWzWcXZylPmf9pc1PMZQMqaoBwF49
+LDJyf2nWeqlmVpXCpMkdJZCwtrSuyBoUVQKXXYdE5F7OvDLG53thp0Wp05bSol42OOf0rgYSIEIQ
+cf3onAXWwouTeuX9T6OXDbiZtCHZk2QEFaIhMnng1m9CZ7nkKWzFlYr5XdCK06YUnqLA5GtzAM++
+qvd55lnqeHJvRhIOjnxgp/RRoA7vzDSt7GqiTMoc6nR4pLHONPgpnMEwZMavaOpYEypwPBddM8ki
+7KpQpjCCASMGCSqGSIb3DQEHAaCCARQEggEQMIIBDDCCAQgGCyqGSIb3DQEMCgECoIG0MIGxMBwG
+CiqGSIb3DQEMAQMwDgQIW22NYWm509QCAggABIGQ/rvxaiJXdKxHzlSxKfnUG1rmsPyXFt0JWCTo
+xTfW0oLil01LaPhF7Y6VhPFpEOXKgwsCeDSXzO9caESgtIMbCOmE4zM3ICX5E1s4FpWZ7S5LJKp+
+LmJm8jlAhrPQNdcjNBgUd7lD3NpqumdELTgOQIbg/UDXePU7jMyExexM5osWrT0LHvsntPT9Urlv
+imXoMUIwGwYJKoZIhvcNAQkUMQ4eDABkAG8AbgBnAGwAZTAjBgkqhkiG9w0BCRUxFgQUHaWGQisw
+n8Z907Sg+b3x9+gLOJgwMTAhMAkGBSsOAwIaBQAEFOxTzlUDKlVlbelv1Heiee/EFFODBAieh51f
+tEgLVAICCAA=`
+
+// trustP12 was created by OpenSSL 3.0 with "openssl pkcs12 -export -nokeys -name ca", a named CA certificate only.
+const trustP12 = `
+MIICxwIBAzCCAn0GCSqGSIb3DQEHAaCCAm4EggJqMIICZjCCAmIGCSqGSIb3DQEHBqCCAlMwggJP
+AgEAMIICSAYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAgWiLMuwKit
+cAICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEECC1jMDz6sfLb4OHuJ7CAgmAggHgzLZN
+Fkj9Z/r4JgZ3EdWTtKq5pwWeNoUyAvtum2YFIcH88754w/9W+Y4crPK6sPYB6y4Puefa2ElXa5P+
+1h3SN7HH/SXECs8Ez3FgB6P0OOH3TQZ+5CoWtolzoo1fhrECPQZYvkJrBLdJMNylDtQ09SGDIFLI
+ygqhKq0RXNg1Va3uuY8CSiHHyvqtPoIW4wB5Pf5GBeROlxRUoMiadeYwjqxGYzJRZrVm+x1i5sew
+cFZZjYb3PYQtPX3RCLNbmI04tadr86b+yrTw+Hio3uqV8eBO9gxFvy5wVtVkwbizRuNKX3LXoJg/
+HSYwfMYFFIw8AiJtZae/DYO1REr4k86ed5HB4kAFvj+ERUUyxZ+hBm//fww461CZf0yYeT0TNjhI
+IBadU5Kts/omVKWJykz71mwbzxAmUNYD6Ey2jPdPU9ObeHuYvB5vdtLafRKwNPVKhSCK7JqSV6Z5
+JiUgg2bSZb6X6caSltAmXpAn+d1H+6B7fdAGv8CvuBr2rF3fT+vrHqLO1fpZuEBINZ/+mH2XITV8
+9qE+IlG6MibXAgtGsP6XA8vMZMJ/d6t4V19hte+wA2YIfzc5V6QAnYnokY48xZkLVNmaVdaNN12X
+7cri5xjMHmfu4shDhBgwnM7yPkziMEEwMTANBglghkgBZQMEAgEFAAQgWq5nqRLbqku3e8E5Effz
+NKd7cqItYnbU/wC4Eqds/BcECA9TE2iHhErCAgIIAA==`
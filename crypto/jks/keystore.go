@@ -0,0 +1,192 @@
+// Package jks reads Java key stores so that credentials exported from Java
+// applications can be used directly. Both the legacy proprietary JKS format,
+// including its SHA-1 based key protection, and the PKCS#12 format that Java uses
+// by default since version 9 are supported. Key stores are read only.
+package jks
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Format is the encoding of a key store.
+type Format int
+
+// The supported formats.
+const (
+	FormatJKS    Format = iota + 1 // Sun JKS, magic 0xFEEDFEED
+	FormatPKCS12                   // PKCS#12 as written by Java and OpenSSL
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatJKS:
+		return "JKS"
+	case FormatPKCS12:
+		return "PKCS12"
+	}
+	return "unknown"
+}
+
+// EntryType is the kind of a key store entry.
+type EntryType int
+
+// The entry types.
+const (
+	PrivateKeyEntry         EntryType = iota + 1 // A private key with its certificate chain
+	TrustedCertificateEntry                      // A trusted certificate
+)
+
+// String returns a readable name of the entry type.
+func (t EntryType) String() string {
+	switch t {
+	case PrivateKeyEntry:
+		return "private key"
+	case TrustedCertificateEntry:
+		return "trusted certificate"
+	}
+	return "unknown"
+}
+
+// Entry describes a key store entry.
+type Entry struct {
+	Alias        string              // Entry alias
+	Type         EntryType           // Kind of entry
+	Date         time.Time           // Creation date, zero for PKCS#12
+	Certificates []*x509.Certificate // Certificate chain, leaf first, or the trusted certificate
+}
+
+// entry is an entry with its still protected private key.
+type entry struct {
+	Entry
+	key []byte // JKS or PKCS#12 EncryptedPrivateKeyInfo, or plain PKCS#8 for PKCS#12 key bags
+	raw bool   // key is not encrypted
+}
+
+// KeyStore is a decoded key store. Private keys stay encrypted until requested.
+type KeyStore struct {
+	Format  Format
+	entries []*entry
+}
+
+// Decode decodes a JKS or PKCS#12 key store, detected from its contents, and
+// verifies its integrity with the store password.
+func Decode(data []byte, password string) (*KeyStore, error) {
+	if len(data) >= 4 {
+		switch binary.BigEndian.Uint32(data) {
+		case jksMagic:
+			return decodeJKS(data, password)
+		case jceksMagic:
+			return nil, UnsupportedFormatError{Format: "JCEKS"}
+		}
+	}
+	// A PKCS#12 PFX is a DER SEQUENCE.
+	if len(data) > 0 && data[0] == 0x30 {
+		return decodePKCS12(data, password)
+	}
+	return nil, UnsupportedFormatError{}
+}
+
+// Entries returns the entries in key store order.
+func (ks *KeyStore) Entries() []Entry {
+	list := make([]Entry, len(ks.entries))
+	for i, e := range ks.entries {
+		list[i] = e.Entry
+	}
+	return list
+}
+
+// Certificate returns the trusted certificate of an entry, or the leaf certificate
+// of a private key entry.
+func (ks *KeyStore) Certificate(alias string) (*x509.Certificate, error) {
+	e := ks.find(alias)
+	if e == nil {
+		return nil, AliasNotFoundError{Alias: alias}
+	}
+	if len(e.Certificates) == 0 {
+		return nil, CertificateError{Err: errNoCertificate}
+	}
+	return e.Certificates[0], nil
+}
+
+// PrivateKey decrypts the private key of an entry with the key password, which
+// is usually the store password, and returns it with its certificate chain.
+func (ks *KeyStore) PrivateKey(alias, password string) (crypto.PrivateKey, []*x509.Certificate, error) {
+	e := ks.find(alias)
+	if e == nil {
+		return nil, nil, AliasNotFoundError{Alias: alias}
+	}
+	if e.Type != PrivateKeyEntry {
+		return nil, nil, EntryTypeError{Alias: e.Alias, Type: e.Type}
+	}
+	der := e.key
+	if !e.raw {
+		var err error
+		if ks.Format == FormatJKS {
+			der, err = decryptJKSKey(e.key, password)
+		} else {
+			der, err = decryptPKCS12Key(e.key, password)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		defer utils.SecureWipe(der)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, nil, InvalidKeyError{Err: err}
+	}
+	return key, e.Certificates, nil
+}
+
+// find looks up an alias, exactly first and then ignoring case as Java does.
+func (ks *KeyStore) find(alias string) *entry {
+	for _, e := range ks.entries {
+		if e.Alias == alias {
+			return e
+		}
+	}
+	for _, e := range ks.entries {
+		if strings.EqualFold(e.Alias, alias) {
+			return e
+		}
+	}
+	return nil
+}
+
+// buildChain orders certificates from leaf to root by following issuers.
+func buildChain(leaf *x509.Certificate, pool []*x509.Certificate) []*x509.Certificate {
+	chain := []*x509.Certificate{leaf}
+	for cur := leaf; len(chain) <= len(pool); {
+		if bytes.Equal(cur.RawIssuer, cur.RawSubject) {
+			break
+		}
+		// Prefer an issuer whose signature verifies, but fall back to the name alone
+		// for chains signed with algorithms crypto/x509 no longer accepts, like SHA-1.
+		var next *x509.Certificate
+		for _, c := range pool {
+			if !bytes.Equal(c.RawSubject, cur.RawIssuer) {
+				continue
+			}
+			if c.CheckSignature(cur.SignatureAlgorithm, cur.RawTBSCertificate, cur.Signature) == nil {
+				next = c
+				break
+			}
+			if next == nil {
+				next = c
+			}
+		}
+		if next == nil {
+			break
+		}
+		chain, cur = append(chain, next), next
+	}
+	return chain
+}
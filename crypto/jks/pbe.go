@@ -0,0 +1,252 @@
+package jks
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"hash"
+
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// maxIterations bounds the key derivation work a hostile key store can demand.
+const maxIterations = 10_000_000
+
+// Password based encryption and MAC algorithm identifiers.
+var (
+	oidPBES2                = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidPBEWithSHAAnd3DES    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd128RC2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 5}
+	oidPBEWithSHAAnd40RC2   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+	oidHMACWithSHA1         = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224       = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256       = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384       = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512       = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC           = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+	oidSHA1                 = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA224               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4}
+	oidSHA256               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	errInvalidPBEParameters = errors.New("invalid password based encryption parameters")
+)
+
+// pbeParams are the parameters of the PKCS#12 password based encryption schemes.
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// pbes2Params are the parameters of PBES2.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of PBKDF2.
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// hashes lists the supported hash functions with their digest and HMAC
+// identifiers and their block size, which the PKCS#12 KDF needs.
+var hashes = []struct {
+	digest, hmac asn1.ObjectIdentifier
+	new          func() hash.Hash
+	blockSize    int
+}{
+	{oidSHA1, oidHMACWithSHA1, sha1.New, 64},
+	{oidSHA224, oidHMACWithSHA224, sha256.New224, 64},
+	{oidSHA256, oidHMACWithSHA256, sha256.New, 64},
+	{oidSHA384, oidHMACWithSHA384, sha512.New384, 128},
+	{oidSHA512, oidHMACWithSHA512, sha512.New, 128},
+}
+
+// digestFunc returns the hash function and block size of a digest algorithm.
+func digestFunc(oid asn1.ObjectIdentifier) (func() hash.Hash, int) {
+	for _, h := range hashes {
+		if h.digest.Equal(oid) {
+			return h.new, h.blockSize
+		}
+	}
+	return nil, 0
+}
+
+// hmacFunc returns the hash function of an HMAC algorithm.
+func hmacFunc(oid asn1.ObjectIdentifier) func() hash.Hash {
+	for _, h := range hashes {
+		if h.hmac.Equal(oid) {
+			return h.new
+		}
+	}
+	return nil
+}
+
+// decryptPBE decrypts data protected by a PKCS#12 or PBES2 password based scheme.
+func decryptPBE(alg pkix.AlgorithmIdentifier, password string, data []byte) ([]byte, error) {
+	var block cipher.Block
+	var iv []byte
+	switch {
+	case alg.Algorithm.Equal(oidPBES2):
+		var err error
+		if block, iv, err = pbes2Cipher(alg.Parameters.FullBytes, password); err != nil {
+			return nil, err
+		}
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd3DES), alg.Algorithm.Equal(oidPBEWithSHAAnd128RC2), alg.Algorithm.Equal(oidPBEWithSHAAnd40RC2):
+		var params pbeParams
+		if rest, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil || len(rest) > 0 || params.Iterations < 1 || params.Iterations > maxIterations {
+			return nil, InvalidFormatError{Err: errInvalidPBEParameters}
+		}
+		pass := bmpPassword(password)
+		iv = pkcs12KDF(sha1.New, 64, 2, pass, params.Salt, params.Iterations, 8)
+		switch {
+		case alg.Algorithm.Equal(oidPBEWithSHAAnd3DES):
+			block, _ = des.NewTripleDESCipher(pkcs12KDF(sha1.New, 64, 1, pass, params.Salt, params.Iterations, 24))
+		case alg.Algorithm.Equal(oidPBEWithSHAAnd128RC2):
+			block = newRC2(pkcs12KDF(sha1.New, 64, 1, pass, params.Salt, params.Iterations, 16), 128)
+		default:
+			block = newRC2(pkcs12KDF(sha1.New, 64, 1, pass, params.Salt, params.Iterations, 5), 40)
+		}
+	default:
+		return nil, UnsupportedAlgorithmError{Algorithm: alg.Algorithm}
+	}
+	size := block.BlockSize()
+	if len(data) == 0 || len(data)%size != 0 {
+		return nil, DecryptError{}
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	n := int(out[len(out)-1])
+	if n == 0 || n > size {
+		return nil, DecryptError{}
+	}
+	for _, b := range out[len(out)-n:] {
+		if int(b) != n {
+			return nil, DecryptError{}
+		}
+	}
+	return out[:len(out)-n], nil
+}
+
+// pbes2Cipher derives the cipher and IV of PBES2 with PBKDF2. The password is
+// used as UTF-8 bytes, as Java and OpenSSL do.
+func pbes2Cipher(der []byte, password string) (cipher.Block, []byte, error) {
+	var params pbes2Params
+	if rest, err := asn1.Unmarshal(der, &params); err != nil || len(rest) > 0 {
+		return nil, nil, InvalidFormatError{Err: errInvalidPBEParameters}
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, UnsupportedAlgorithmError{Algorithm: params.KeyDerivationFunc.Algorithm}
+	}
+	var kdf pbkdf2Params
+	if rest, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil || len(rest) > 0 || kdf.Iterations < 1 || kdf.Iterations > maxIterations {
+		return nil, nil, InvalidFormatError{Err: errInvalidPBEParameters}
+	}
+	prf := sha1.New
+	if len(kdf.PRF.Algorithm) > 0 {
+		if prf = hmacFunc(kdf.PRF.Algorithm); prf == nil {
+			return nil, nil, UnsupportedAlgorithmError{Algorithm: kdf.PRF.Algorithm}
+		}
+	}
+	scheme := params.EncryptionScheme.Algorithm
+	var keyLen int
+	switch {
+	case scheme.Equal(oidAES128CBC):
+		keyLen = 16
+	case scheme.Equal(oidAES192CBC):
+		keyLen = 24
+	case scheme.Equal(oidAES256CBC):
+		keyLen = 32
+	case scheme.Equal(oidDESEDE3CBC):
+		keyLen = 24
+	default:
+		return nil, nil, UnsupportedAlgorithmError{Algorithm: scheme}
+	}
+	if kdf.KeyLength != 0 && kdf.KeyLength != keyLen {
+		return nil, nil, InvalidFormatError{Err: errInvalidPBEParameters}
+	}
+	var iv []byte
+	if rest, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(rest) > 0 {
+		return nil, nil, InvalidFormatError{Err: errInvalidPBEParameters}
+	}
+	key := pbkdf2.Key([]byte(password), kdf.Salt, kdf.Iterations, keyLen, prf)
+	defer utils.SecureWipe(key)
+	var block cipher.Block
+	if scheme.Equal(oidDESEDE3CBC) {
+		block, _ = des.NewTripleDESCipher(key)
+	} else {
+		block, _ = aes.NewCipher(key)
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, nil, InvalidFormatError{Err: errInvalidPBEParameters}
+	}
+	return block, iv, nil
+}
+
+// pkcs12KDF derives n bytes with the PKCS#12 key derivation function of RFC 7292
+// appendix B.2, where v is the block size of the hash and id selects the purpose:
+// 1 for keys, 2 for IVs and 3 for MAC keys.
+func pkcs12KDF(h func() hash.Hash, v int, id byte, password, salt []byte, iterations, n int) []byte {
+	fill := func(in []byte) []byte {
+		if len(in) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(in)+v-1)/v))
+		for i := range out {
+			out[i] = in[i%len(in)]
+		}
+		return out
+	}
+	d := make([]byte, v)
+	for i := range d {
+		d[i] = id
+	}
+	in := append(fill(salt), fill(password)...)
+	var out []byte
+	for len(out) < n {
+		a := h()
+		a.Write(d)
+		a.Write(in)
+		sum := a.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			a.Reset()
+			a.Write(sum)
+			sum = a.Sum(sum[:0])
+		}
+		out = append(out, sum...)
+		if len(out) >= n {
+			break
+		}
+		// Add B+1 to each v byte block of I, with B the hash repeated to v bytes.
+		b := fill(sum)[:v]
+		for j := 0; j < len(in); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				carry += int(in[j+k]) + int(b[k])
+				in[j+k] = byte(carry)
+				carry >>= 8
+			}
+		}
+	}
+	return out[:n]
+}
+
+// bmpPassword encodes a password as a NUL terminated BMPString for PKCS#12.
+func bmpPassword(password string) []byte {
+	return append(utf16BE(password), 0, 0)
+}
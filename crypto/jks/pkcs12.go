@@ -0,0 +1,261 @@
+package jks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// PKCS#12 content, bag and attribute identifiers.
+var (
+	oidData                = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidShroudedKeyBag      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Certificate     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidFriendlyName        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidLocalKeyID          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidJavaTrustedKeyUsage = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+)
+
+// pfx is the PKCS#12 PFX structure.
+type pfx struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+// contentInfo is the PKCS#7 ContentInfo structure.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+// macData is the PKCS#12 MacData structure.
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+// digestInfo is the PKCS#1 DigestInfo structure.
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+// encryptedData is the PKCS#7 EncryptedData structure.
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+// encryptedContentInfo is the PKCS#7 EncryptedContentInfo structure.
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+// safeBag is the PKCS#12 SafeBag structure.
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+// pkcs12Attribute is a bag attribute with its single value.
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+// certBag is the PKCS#12 CertBag structure.
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+// pkcs12Key is a key bag waiting to be matched with its certificate.
+type pkcs12Key struct {
+	alias   string
+	localID []byte
+	der     []byte
+	raw     bool
+}
+
+// pkcs12Cert is a certificate bag with its attributes.
+type pkcs12Cert struct {
+	cert    *x509.Certificate
+	alias   string
+	localID []byte
+	trusted bool
+}
+
+// decodePKCS12 decodes a DER encoded PKCS#12 key store, verifying its MAC when
+// present and decrypting the certificates. Private keys stay encrypted.
+func decodePKCS12(data []byte, password string) (*KeyStore, error) {
+	var p pfx
+	if rest, err := asn1.Unmarshal(data, &p); err != nil || len(rest) > 0 {
+		return nil, UnsupportedFormatError{}
+	}
+	if p.Version != 3 || !p.AuthSafe.ContentType.Equal(oidData) {
+		return nil, InvalidFormatError{Err: errors.New("unsupported PFX version or content type")}
+	}
+	var authSafe []byte
+	if _, err := asn1.Unmarshal(p.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return nil, InvalidFormatError{Err: err}
+	}
+	if len(p.MacData.Mac.Algorithm.Algorithm) > 0 {
+		if err := verifyMAC(p.MacData, authSafe, password); err != nil {
+			return nil, err
+		}
+	}
+	var contents []contentInfo
+	if rest, err := asn1.Unmarshal(authSafe, &contents); err != nil || len(rest) > 0 {
+		return nil, InvalidFormatError{Err: errors.New("malformed authenticated safe")}
+	}
+
+	var keys []pkcs12Key
+	var certs []pkcs12Cert
+	for _, ci := range contents {
+		var safe []byte
+		switch {
+		case ci.ContentType.Equal(oidData):
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &safe); err != nil {
+				return nil, InvalidFormatError{Err: err}
+			}
+		case ci.ContentType.Equal(oidEncryptedData):
+			var ed encryptedData
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+				return nil, InvalidFormatError{Err: err}
+			}
+			var err error
+			if safe, err = decryptPBE(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, password, ed.EncryptedContentInfo.EncryptedContent); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, UnsupportedAlgorithmError{Algorithm: ci.ContentType}
+		}
+		var bags []safeBag
+		if rest, err := asn1.Unmarshal(safe, &bags); err != nil || len(rest) > 0 {
+			return nil, InvalidFormatError{Err: errors.New("malformed safe contents")}
+		}
+		for _, bag := range bags {
+			alias, localID, trusted, err := bagAttributes(bag.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case bag.ID.Equal(oidKeyBag), bag.ID.Equal(oidShroudedKeyBag):
+				keys = append(keys, pkcs12Key{alias: alias, localID: localID, der: bag.Value.Bytes, raw: bag.ID.Equal(oidKeyBag)})
+			case bag.ID.Equal(oidCertBag):
+				var cb certBag
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil {
+					return nil, InvalidFormatError{Err: err}
+				}
+				if !cb.ID.Equal(oidX509Certificate) {
+					continue
+				}
+				cert, err := x509.ParseCertificate(cb.Data)
+				if err != nil {
+					return nil, CertificateError{Err: err}
+				}
+				certs = append(certs, pkcs12Cert{cert: cert, alias: alias, localID: localID, trusted: trusted})
+			}
+		}
+	}
+
+	ks := &KeyStore{Format: FormatPKCS12}
+	pool := make([]*x509.Certificate, len(certs))
+	for i, c := range certs {
+		pool[i] = c.cert
+	}
+	for _, k := range keys {
+		e := &entry{Entry: Entry{Alias: k.alias, Type: PrivateKeyEntry}, key: bytes.Clone(k.der), raw: k.raw}
+		for _, c := range certs {
+			if k.localID != nil && bytes.Equal(c.localID, k.localID) {
+				e.Certificates = buildChain(c.cert, pool)
+				if e.Alias == "" {
+					e.Alias = c.alias
+				}
+				break
+			}
+		}
+		ks.entries = append(ks.entries, e)
+	}
+	// Java marks trusted certificates with an attribute; other tools only name them.
+	for _, c := range certs {
+		if c.trusted || (c.alias != "" && c.localID == nil) {
+			ks.entries = append(ks.entries, &entry{Entry: Entry{Alias: c.alias, Type: TrustedCertificateEntry, Certificates: []*x509.Certificate{c.cert}}})
+		}
+	}
+	return ks, nil
+}
+
+// verifyMAC checks the PKCS#12 MAC over the authenticated safe. An empty password
+// is tried both as an empty BMPString and as no password at all, since tools differ.
+func verifyMAC(m macData, content []byte, password string) error {
+	h, v := digestFunc(m.Mac.Algorithm.Algorithm)
+	if h == nil {
+		return UnsupportedAlgorithmError{Algorithm: m.Mac.Algorithm.Algorithm}
+	}
+	if m.Iterations < 1 || m.Iterations > maxIterations {
+		return InvalidFormatError{Err: errors.New("invalid MAC iteration count")}
+	}
+	passwords := [][]byte{bmpPassword(password)}
+	if password == "" {
+		passwords = append(passwords, nil)
+	}
+	for _, pass := range passwords {
+		key := pkcs12KDF(h, v, 3, pass, m.MacSalt, m.Iterations, h().Size())
+		mac := hmac.New(h, key)
+		mac.Write(content)
+		if utils.ConstantTimeEqual(mac.Sum(nil), m.Mac.Digest) {
+			return nil
+		}
+	}
+	return IntegrityError{}
+}
+
+// decryptPKCS12Key decrypts a shrouded key bag with the key password.
+func decryptPKCS12Key(der []byte, password string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) > 0 {
+		return nil, InvalidFormatError{Err: errors.New("malformed shrouded key bag")}
+	}
+	return decryptPBE(info.Algorithm, password, info.EncryptedData)
+}
+
+// bagAttributes extracts the friendly name, local key ID and Java trust marker.
+func bagAttributes(attrs []pkcs12Attribute) (alias string, localID []byte, trusted bool, err error) {
+	for _, a := range attrs {
+		switch {
+		case a.ID.Equal(oidFriendlyName):
+			var raw asn1.RawValue
+			if _, err = asn1.Unmarshal(a.Value.Bytes, &raw); err != nil || raw.Tag != asn1.TagBMPString || len(raw.Bytes)%2 != 0 {
+				return "", nil, false, InvalidFormatError{Err: errors.New("malformed friendly name")}
+			}
+			units := make([]uint16, len(raw.Bytes)/2)
+			for i := range units {
+				units[i] = binary.BigEndian.Uint16(raw.Bytes[2*i:])
+			}
+			alias = string(utf16.Decode(units))
+		case a.ID.Equal(oidLocalKeyID):
+			if _, err = asn1.Unmarshal(a.Value.Bytes, &localID); err != nil {
+				return "", nil, false, InvalidFormatError{Err: errors.New("malformed local key ID")}
+			}
+		case a.ID.Equal(oidJavaTrustedKeyUsage):
+			trusted = true
+		}
+	}
+	return alias, localID, trusted, nil
+}
@@ -0,0 +1,138 @@
+package jws
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"math/big"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Algorithm is a JWS signature algorithm as registered by RFC 7518 and RFC 8037.
+type Algorithm string
+
+// The supported algorithms. The "none" algorithm is deliberately absent.
+const (
+	HS256 Algorithm = "HS256" // HMAC with SHA-256, key is []byte
+	HS384 Algorithm = "HS384" // HMAC with SHA-384, key is []byte
+	HS512 Algorithm = "HS512" // HMAC with SHA-512, key is []byte
+	RS256 Algorithm = "RS256" // RSASSA-PKCS1-v1_5 with SHA-256
+	RS384 Algorithm = "RS384" // RSASSA-PKCS1-v1_5 with SHA-384
+	RS512 Algorithm = "RS512" // RSASSA-PKCS1-v1_5 with SHA-512
+	PS256 Algorithm = "PS256" // RSASSA-PSS with SHA-256
+	PS384 Algorithm = "PS384" // RSASSA-PSS with SHA-384
+	PS512 Algorithm = "PS512" // RSASSA-PSS with SHA-512
+	ES256 Algorithm = "ES256" // ECDSA with P-256 and SHA-256
+	ES384 Algorithm = "ES384" // ECDSA with P-384 and SHA-384
+	ES512 Algorithm = "ES512" // ECDSA with P-521 and SHA-512
+	EdDSA Algorithm = "EdDSA" // Ed25519
+)
+
+// MinRSAKeySize is the minimum RSA modulus size in bits required by RFC 7518.
+const MinRSAKeySize = 2048
+
+// hashes maps each algorithm to its hash, Ed25519 hashes internally.
+var hashes = map[Algorithm]crypto.Hash{
+	HS256: crypto.SHA256, HS384: crypto.SHA384, HS512: crypto.SHA512,
+	RS256: crypto.SHA256, RS384: crypto.SHA384, RS512: crypto.SHA512,
+	PS256: crypto.SHA256, PS384: crypto.SHA384, PS512: crypto.SHA512,
+	ES256: crypto.SHA256, ES384: crypto.SHA384, ES512: crypto.SHA512,
+	EdDSA: 0,
+}
+
+// curves maps each ECDSA algorithm to its curve.
+var curves = map[Algorithm]elliptic.Curve{ES256: elliptic.P256(), ES384: elliptic.P384(), ES512: elliptic.P521()}
+
+// sign signs input with alg. Keys are []byte for HMAC, *rsa.PrivateKey,
+// *ecdsa.PrivateKey on the algorithm curve or ed25519.PrivateKey.
+func (alg Algorithm) sign(key any, input []byte) ([]byte, error) {
+	hash, ok := hashes[alg]
+	if !ok {
+		return nil, UnsupportedAlgorithmError{Algorithm: string(alg)}
+	}
+	switch k := key.(type) {
+	case []byte:
+		if alg[0] != 'H' || len(k) < hash.Size() {
+			break
+		}
+		mac := hmac.New(hash.New, k)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case *rsa.PrivateKey:
+		if (alg[0] != 'R' && alg[0] != 'P') || k.N.BitLen() < MinRSAKeySize {
+			break
+		}
+		if alg[0] == 'P' {
+			return rsa.SignPSS(utils.Rand(), k, hash, digest(hash, input), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.SignPKCS1v15(utils.Rand(), k, hash, digest(hash, input))
+	case *ecdsa.PrivateKey:
+		if curves[alg] != k.Curve {
+			break
+		}
+		r, s, err := ecdsa.Sign(utils.Rand(), k, digest(hash, input))
+		if err != nil {
+			return nil, err
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig, nil
+	case ed25519.PrivateKey:
+		if alg != EdDSA || len(k) != ed25519.PrivateKeySize {
+			break
+		}
+		return ed25519.Sign(k, input), nil
+	}
+	return nil, InvalidKeyError{Algorithm: alg, Key: key}
+}
+
+// verify reports whether sig is a valid alg signature over input. Keys are
+// []byte for HMAC, *rsa.PublicKey, *ecdsa.PublicKey on the algorithm curve or
+// ed25519.PublicKey. A key of the wrong type never verifies, which rules out
+// algorithm confusion such as HMAC keyed with an RSA public key.
+func (alg Algorithm) verify(key any, input, sig []byte) bool {
+	hash, ok := hashes[alg]
+	if !ok {
+		return false
+	}
+	switch k := key.(type) {
+	case []byte:
+		if alg[0] != 'H' || len(k) < hash.Size() {
+			return false
+		}
+		mac := hmac.New(hash.New, k)
+		mac.Write(input)
+		return utils.ConstantTimeEqual(mac.Sum(nil), sig)
+	case *rsa.PublicKey:
+		switch alg[0] {
+		case 'R':
+			return rsa.VerifyPKCS1v15(k, hash, digest(hash, input), sig) == nil
+		case 'P':
+			return rsa.VerifyPSS(k, hash, digest(hash, input), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if curves[alg] != k.Curve || len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest(hash, input), r, s)
+	case ed25519.PublicKey:
+		return alg == EdDSA && len(k) == ed25519.PublicKeySize && ed25519.Verify(k, input, sig)
+	}
+	return false
+}
+
+// digest hashes input with hash.
+func digest(hash crypto.Hash, input []byte) []byte {
+	h := hash.New()
+	h.Write(input)
+	return h.Sum(nil)
+}
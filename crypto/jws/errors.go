@@ -0,0 +1,164 @@
+package jws
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedAlgorithmError represents an error when a JWS algorithm is not supported.
+type UnsupportedAlgorithmError struct {
+	Algorithm string // The unsupported algorithm name
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/jws: unsupported algorithm %q", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-JWS-001.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-JWS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// InvalidKeyError represents an error when a key cannot be used with an algorithm.
+type InvalidKeyError struct {
+	Algorithm Algorithm // The algorithm the key was used with
+	Key       any       // The rejected key
+}
+
+// Error returns a formatted error message describing the invalid key.
+func (e InvalidKeyError) Error() string {
+	return fmt.Sprintf("crypto/jws: invalid key %T for algorithm %s", e.Key, e.Algorithm)
+}
+
+// Code returns the stable error code DGL-JWS-002.
+func (e InvalidKeyError) Code() string {
+	return "DGL-JWS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "key", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// InvalidMessageError represents an error when a JWS serialization cannot be decoded.
+type InvalidMessageError struct {
+	Err error // Underlying error from base64 or JSON decoding
+}
+
+// Error returns a formatted error message describing the invalid serialization.
+func (e InvalidMessageError) Error() string {
+	return fmt.Sprintf("crypto/jws: invalid message: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-JWS-003.
+func (e InvalidMessageError) Code() string {
+	return "DGL-JWS-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidMessageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedCriticalHeaderError represents an error when a header marks a parameter critical that is not understood.
+type UnsupportedCriticalHeaderError struct {
+	Name string // The critical header parameter name
+}
+
+// Error returns a formatted error message describing the unsupported critical header.
+func (e UnsupportedCriticalHeaderError) Error() string {
+	return fmt.Sprintf("crypto/jws: unsupported critical header parameter %q", e.Name)
+}
+
+// Code returns the stable error code DGL-JWS-004.
+func (e UnsupportedCriticalHeaderError) Code() string {
+	return "DGL-JWS-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedCriticalHeaderError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "", "name", e.Name)
+}
+
+// InvalidPayloadError represents an error when an unencoded payload cannot be serialized as requested.
+type InvalidPayloadError struct {
+	Reason string // Why the payload is rejected
+}
+
+// Error returns a formatted error message describing the invalid payload.
+func (e InvalidPayloadError) Error() string {
+	return fmt.Sprintf("crypto/jws: invalid unencoded payload: %s", e.Reason)
+}
+
+// Code returns the stable error code DGL-JWS-005.
+func (e InvalidPayloadError) Code() string {
+	return "DGL-JWS-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPayloadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "", "reason", e.Reason)
+}
+
+// SerializationError represents an error when a message cannot use the requested serialization.
+type SerializationError struct {
+	Reason string // Why the serialization is unavailable
+}
+
+// Error returns a formatted error message describing why the serialization is unavailable.
+func (e SerializationError) Error() string {
+	return fmt.Sprintf("crypto/jws: cannot serialize message: %s", e.Reason)
+}
+
+// Code returns the stable error code DGL-JWS-006.
+func (e SerializationError) Code() string {
+	return "DGL-JWS-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SerializationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "", "reason", e.Reason)
+}
+
+// EmptySignaturesError represents an error when a message has no signatures.
+type EmptySignaturesError struct{}
+
+// Error returns a formatted error message describing the missing signatures.
+func (e EmptySignaturesError) Error() string {
+	return "crypto/jws: message has no signatures"
+}
+
+// Code returns the stable error code DGL-JWS-007.
+func (e EmptySignaturesError) Code() string {
+	return "DGL-JWS-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptySignaturesError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "verify")
+}
+
+// SignatureVerificationError represents an error when no signature verifies with the given key.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/jws: signature verification failed"
+}
+
+// Code returns the stable error code DGL-JWS-008.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-JWS-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/jws", "JWS", "verify")
+}
@@ -0,0 +1,352 @@
+// Package jws implements JSON Web Signatures as specified by RFC 7515, including
+// the general and flattened JSON serializations with multiple signatures,
+// detached content and the unencoded payload option of RFC 7797. API gateways
+// such as open banking require detached, unencoded signatures that compact only
+// JWT libraries cannot produce.
+package jws
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Signer describes one signature to add to a message.
+type Signer struct {
+	Algorithm Algorithm      // Signature algorithm
+	Key       any            // Signing key, see Algorithm for the accepted types
+	KeyID     string         // Optional "kid" protected header parameter
+	Protected map[string]any // Additional protected header parameters
+	Header    map[string]any // Unprotected header parameters, JSON serialization only
+}
+
+// Signature is one signature of a message.
+type Signature struct {
+	Protected map[string]any // Decoded protected header
+	Header    map[string]any // Unprotected header
+	Signature []byte         // Signature value
+
+	protected string // Protected header exactly as signed, base64url encoded
+}
+
+// Algorithm returns the "alg" header parameter of the signature.
+func (s Signature) Algorithm() Algorithm {
+	alg, _ := s.param("alg").(string)
+	return Algorithm(alg)
+}
+
+// KeyID returns the "kid" header parameter of the signature.
+func (s Signature) KeyID() string {
+	kid, _ := s.param("kid").(string)
+	return kid
+}
+
+// param returns a header parameter, from the protected header first.
+func (s Signature) param(name string) any {
+	if v, ok := s.Protected[name]; ok {
+		return v
+	}
+	return s.Header[name]
+}
+
+// Message is a JWS with one or more signatures over a payload.
+type Message struct {
+	Payload    []byte      // Signed payload, set it before Verify for detached messages
+	Detached   bool        // Leave the payload out of serializations
+	Unencoded  bool        // Sign the payload without base64url encoding as defined by RFC 7797
+	Signatures []Signature // Signatures over the payload
+}
+
+// Sign adds a signature over the payload. The "alg", "kid", "b64" and "crit"
+// protected header parameters are set by Sign. All signatures of a message share
+// the Unencoded setting.
+func (m *Message) Sign(s Signer) error {
+	for _, sig := range m.Signatures {
+		if unencoded, err := checkHeader(sig.Protected, sig.Header); err != nil || unencoded != m.Unencoded {
+			return InvalidPayloadError{Reason: "all signatures must share the b64 header parameter"}
+		}
+	}
+	header := make(map[string]any, len(s.Protected)+4)
+	for k, v := range s.Protected {
+		header[k] = v
+	}
+	header["alg"] = string(s.Algorithm)
+	if s.KeyID != "" {
+		header["kid"] = s.KeyID
+	}
+	delete(header, "b64")
+	delete(header, "crit")
+	if m.Unencoded {
+		header["b64"] = false
+		header["crit"] = []string{"b64"}
+	}
+	raw, err := json.Marshal(header)
+	if err != nil {
+		return InvalidMessageError{Err: err}
+	}
+	// Round trip the header so the signature holds the same values a parser would.
+	var decoded map[string]any
+	json.Unmarshal(raw, &decoded)
+
+	protected := base64.RawURLEncoding.EncodeToString(raw)
+	value, err := s.Algorithm.sign(s.Key, m.signingInput(protected, m.Unencoded))
+	if err != nil {
+		return err
+	}
+	m.Signatures = append(m.Signatures, Signature{Protected: decoded, Header: s.Header, Signature: value, protected: protected})
+	return nil
+}
+
+// Verify verifies the signatures using key and returns the first one that is
+// valid. Only signatures whose algorithm matches the key type are considered.
+func (m *Message) Verify(key any) (*Signature, error) {
+	if len(m.Signatures) == 0 {
+		return nil, EmptySignaturesError{}
+	}
+	for i := range m.Signatures {
+		sig := &m.Signatures[i]
+		unencoded, err := checkHeader(sig.Protected, sig.Header)
+		if err != nil {
+			return nil, err
+		}
+		if sig.Algorithm().verify(key, m.signingInput(sig.protected, unencoded), sig.Signature) {
+			return sig, nil
+		}
+	}
+	return nil, SignatureVerificationError{}
+}
+
+// Compact returns the compact serialization. It requires exactly one signature
+// without an unprotected header. Detached messages have an empty payload part.
+func (m *Message) Compact() (string, error) {
+	if len(m.Signatures) != 1 {
+		return "", SerializationError{Reason: "compact serialization requires exactly one signature"}
+	}
+	sig := m.Signatures[0]
+	if len(sig.Header) > 0 {
+		return "", SerializationError{Reason: "compact serialization cannot hold an unprotected header"}
+	}
+	payload := ""
+	if !m.Detached {
+		if m.Unencoded && bytes.IndexByte(m.Payload, '.') >= 0 {
+			return "", InvalidPayloadError{Reason: "contains '.', detach it for compact serialization"}
+		}
+		payload = m.encodePayload()
+	}
+	return sig.protected + "." + payload + "." + base64.RawURLEncoding.EncodeToString(sig.Signature), nil
+}
+
+// jsonSignature is a signature of the JSON serializations.
+type jsonSignature struct {
+	Protected string         `json:"protected,omitempty"`
+	Header    map[string]any `json:"header,omitempty"`
+	Signature string         `json:"signature,omitempty"`
+}
+
+// jsonMessage is the general or flattened JSON serialization.
+type jsonMessage struct {
+	Payload    *string         `json:"payload,omitempty"`
+	Signatures []jsonSignature `json:"signatures,omitempty"`
+	jsonSignature
+}
+
+// MarshalJSON returns the general JSON serialization.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	if len(m.Signatures) == 0 {
+		return nil, EmptySignaturesError{}
+	}
+	out, err := m.jsonMessage()
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range m.Signatures {
+		out.Signatures = append(out.Signatures, sig.json())
+	}
+	return json.Marshal(out)
+}
+
+// Flattened returns the flattened JSON serialization, which requires exactly one signature.
+func (m *Message) Flattened() ([]byte, error) {
+	if len(m.Signatures) != 1 {
+		return nil, SerializationError{Reason: "flattened serialization requires exactly one signature"}
+	}
+	out, err := m.jsonMessage()
+	if err != nil {
+		return nil, err
+	}
+	out.jsonSignature = m.Signatures[0].json()
+	return json.Marshal(out)
+}
+
+// jsonMessage returns the JSON serialization without signatures.
+func (m *Message) jsonMessage() (*jsonMessage, error) {
+	out := &jsonMessage{}
+	if !m.Detached {
+		if m.Unencoded && !utf8.Valid(m.Payload) {
+			return nil, InvalidPayloadError{Reason: "must be valid UTF-8 to be attached to a JSON serialization"}
+		}
+		payload := m.encodePayload()
+		out.Payload = &payload
+	}
+	return out, nil
+}
+
+// json returns the JSON serialization of the signature.
+func (s Signature) json() jsonSignature {
+	return jsonSignature{Protected: s.protected, Header: s.Header, Signature: base64.RawURLEncoding.EncodeToString(s.Signature)}
+}
+
+// ParseCompact parses a compact serialization. An empty payload part marks
+// detached content, set Payload before calling Verify.
+func ParseCompact(s string) (*Message, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil, InvalidMessageError{Err: errors.New("compact serialization must have three parts")}
+	}
+	sig, err := parseSignature(jsonSignature{Protected: parts[0], Signature: parts[2]})
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{Signatures: []Signature{sig}}
+	if err = m.setPayload(&parts[1], parts[1] == ""); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseJSON parses a general or flattened JSON serialization. A missing payload
+// marks detached content, set Payload before calling Verify.
+func ParseJSON(data []byte) (*Message, error) {
+	var in jsonMessage
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	sigs := in.Signatures
+	if in.Signature != "" {
+		if len(sigs) > 0 {
+			return nil, InvalidMessageError{Err: errors.New("mixed general and flattened serialization")}
+		}
+		sigs = []jsonSignature{in.jsonSignature}
+	}
+	if len(sigs) == 0 {
+		return nil, EmptySignaturesError{}
+	}
+	m := &Message{}
+	for _, js := range sigs {
+		sig, err := parseSignature(js)
+		if err != nil {
+			return nil, err
+		}
+		m.Signatures = append(m.Signatures, sig)
+	}
+	if err := m.setPayload(in.Payload, in.Payload == nil); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// setPayload records the serialized payload and the b64 setting shared by all signatures.
+func (m *Message) setPayload(payload *string, detached bool) error {
+	for i, sig := range m.Signatures {
+		unencoded, err := checkHeader(sig.Protected, sig.Header)
+		if err != nil {
+			return err
+		}
+		if i > 0 && unencoded != m.Unencoded {
+			return InvalidMessageError{Err: errors.New("signatures disagree on the b64 header parameter")}
+		}
+		m.Unencoded = unencoded
+	}
+	m.Detached = detached
+	if detached {
+		return nil
+	}
+	if m.Unencoded {
+		m.Payload = []byte(*payload)
+		return nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(*payload)
+	if err != nil {
+		return InvalidMessageError{Err: err}
+	}
+	m.Payload = decoded
+	return nil
+}
+
+// parseSignature decodes a serialized signature.
+func parseSignature(js jsonSignature) (Signature, error) {
+	sig := Signature{Header: js.Header, protected: js.Protected}
+	raw, err := base64.RawURLEncoding.DecodeString(js.Protected)
+	if err != nil {
+		return sig, InvalidMessageError{Err: err}
+	}
+	if len(raw) > 0 {
+		if err = json.Unmarshal(raw, &sig.Protected); err != nil {
+			return sig, InvalidMessageError{Err: err}
+		}
+	}
+	for name := range sig.Header {
+		if _, ok := sig.Protected[name]; ok {
+			return sig, InvalidMessageError{Err: errors.New("header parameter " + name + " is both protected and unprotected")}
+		}
+	}
+	if sig.Signature, err = base64.RawURLEncoding.DecodeString(js.Signature); err != nil {
+		return sig, InvalidMessageError{Err: err}
+	}
+	if _, ok := hashes[sig.Algorithm()]; !ok {
+		return sig, UnsupportedAlgorithmError{Algorithm: string(sig.Algorithm())}
+	}
+	return sig, nil
+}
+
+// checkHeader validates the critical header parameters and reports whether the
+// payload is unencoded. Only "b64" is understood and it must be protected.
+func checkHeader(protected, header map[string]any) (unencoded bool, err error) {
+	if _, ok := header["crit"]; ok {
+		return false, InvalidMessageError{Err: errors.New("crit must be protected")}
+	}
+	if _, ok := header["b64"]; ok {
+		return false, InvalidMessageError{Err: errors.New("b64 must be protected")}
+	}
+	crit, ok := protected["crit"]
+	if ok {
+		list, _ := crit.([]any)
+		if len(list) == 0 {
+			return false, InvalidMessageError{Err: errors.New("crit must be a non-empty list")}
+		}
+		for _, v := range list {
+			if name, _ := v.(string); name != "b64" {
+				return false, UnsupportedCriticalHeaderError{Name: name}
+			}
+		}
+	}
+	b64, ok := protected["b64"]
+	if !ok {
+		return false, nil
+	}
+	encoded, isBool := b64.(bool)
+	if !isBool || crit == nil {
+		return false, InvalidMessageError{Err: errors.New("b64 must be a boolean listed in crit")}
+	}
+	return !encoded, nil
+}
+
+// signingInput returns the JWS signing input for a protected header.
+func (m *Message) signingInput(protected string, unencoded bool) []byte {
+	input := []byte(protected + ".")
+	if unencoded {
+		return append(input, m.Payload...)
+	}
+	return append(input, base64.RawURLEncoding.EncodeToString(m.Payload)...)
+}
+
+// encodePayload returns the payload as it appears in serializations.
+func (m *Message) encodePayload() string {
+	if m.Unencoded {
+		return string(m.Payload)
+	}
+	return base64.RawURLEncoding.EncodeToString(m.Payload)
+}
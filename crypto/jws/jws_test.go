@@ -0,0 +1,243 @@
+package jws
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hmacKey is the HS256 key of RFC 7515 appendix A.1, also used by RFC 7797.
+var hmacKey, _ = base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+
+var rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+func TestRFC7515(t *testing.T) {
+	m, err := ParseCompact("eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	require.NoError(t, err)
+	sig, err := m.Verify(hmacKey)
+	require.NoError(t, err)
+	assert.Equal(t, HS256, sig.Algorithm())
+	assert.Equal(t, "JWT", sig.Protected["typ"])
+	assert.Contains(t, string(m.Payload), `"iss":"joe"`)
+}
+
+func TestRFC7797(t *testing.T) {
+	t.Run("encoded", func(t *testing.T) {
+		m := &Message{Payload: []byte("$.02")}
+		require.NoError(t, m.Sign(Signer{Algorithm: HS256, Key: hmacKey}))
+		s, err := m.Compact()
+		require.NoError(t, err)
+		assert.Equal(t, "eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ", s)
+	})
+
+	t.Run("unencoded detached", func(t *testing.T) {
+		m := &Message{Payload: []byte("$.02"), Unencoded: true, Detached: true}
+		require.NoError(t, m.Sign(Signer{Algorithm: HS256, Key: hmacKey}))
+		s, err := m.Compact()
+		require.NoError(t, err)
+		assert.Equal(t, "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY", s)
+
+		parsed, err := ParseCompact(s)
+		require.NoError(t, err)
+		assert.True(t, parsed.Detached)
+		assert.True(t, parsed.Unencoded)
+		_, err = parsed.Verify(hmacKey)
+		assert.Equal(t, SignatureVerificationError{}, err)
+		parsed.Payload = []byte("$.02")
+		_, err = parsed.Verify(hmacKey)
+		require.NoError(t, err)
+	})
+
+	t.Run("unencoded attached", func(t *testing.T) {
+		m := &Message{Payload: []byte("$.02"), Unencoded: true}
+		require.NoError(t, m.Sign(Signer{Algorithm: HS256, Key: hmacKey}))
+		_, err := m.Compact()
+		assert.Equal(t, InvalidPayloadError{Reason: "contains '.', detach it for compact serialization"}, err)
+
+		data, err := m.Flattened()
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"payload":"$.02"`)
+		parsed, err := ParseJSON(data)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("$.02"), parsed.Payload)
+		_, err = parsed.Verify(hmacKey)
+		require.NoError(t, err)
+
+		m.Payload = []byte{0xff}
+		_, err = m.MarshalJSON()
+		assert.IsType(t, InvalidPayloadError{}, err)
+	})
+}
+
+func TestAlgorithms(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	long := make([]byte, 64)
+
+	tests := []struct {
+		alg         Algorithm
+		key, public any
+		wrongVerify any
+	}{
+		{HS256, hmacKey, hmacKey, long},
+		{HS384, long, long, hmacKey},
+		{HS512, long, long, append([]byte{1}, long...)},
+		{RS256, rsaKey, &rsaKey.PublicKey, &p256.PublicKey},
+		{RS384, rsaKey, &rsaKey.PublicKey, hmacKey},
+		{RS512, rsaKey, &rsaKey.PublicKey, edPub},
+		{PS256, rsaKey, &rsaKey.PublicKey, hmacKey},
+		{PS384, rsaKey, &rsaKey.PublicKey, hmacKey},
+		{PS512, rsaKey, &rsaKey.PublicKey, hmacKey},
+		{ES256, p256, &p256.PublicKey, &p384.PublicKey},
+		{ES384, p384, &p384.PublicKey, &p256.PublicKey},
+		{ES512, p521, &p521.PublicKey, &rsaKey.PublicKey},
+		{EdDSA, edKey, edPub, &p256.PublicKey},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.alg), func(t *testing.T) {
+			m := &Message{Payload: []byte(`{"amount":"10.00"}`)}
+			require.NoError(t, m.Sign(Signer{Algorithm: tt.alg, Key: tt.key, KeyID: "k1"}))
+			s, err := m.Compact()
+			require.NoError(t, err)
+			parsed, err := ParseCompact(s)
+			require.NoError(t, err)
+			sig, err := parsed.Verify(tt.public)
+			require.NoError(t, err)
+			assert.Equal(t, "k1", sig.KeyID())
+			_, err = parsed.Verify(tt.wrongVerify)
+			assert.Equal(t, SignatureVerificationError{}, err)
+		})
+	}
+}
+
+func TestGeneralSerialization(t *testing.T) {
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	m := &Message{Payload: []byte(`{"amount":"10.00"}`), Unencoded: true, Detached: true}
+	require.NoError(t, m.Sign(Signer{Algorithm: PS256, Key: rsaKey, KeyID: "bank", Header: map[string]any{"x-id": "1"}}))
+	require.NoError(t, m.Sign(Signer{Algorithm: EdDSA, Key: edKey, KeyID: "tpp", Protected: map[string]any{"iat": 1700000000}}))
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "payload")
+
+	parsed, err := ParseJSON(data)
+	require.NoError(t, err)
+	require.Len(t, parsed.Signatures, 2)
+	assert.Equal(t, "1", parsed.Signatures[0].Header["x-id"])
+	assert.Equal(t, float64(1700000000), parsed.Signatures[1].Protected["iat"])
+
+	parsed.Payload = m.Payload
+	sig, err := parsed.Verify(edPub)
+	require.NoError(t, err)
+	assert.Equal(t, "tpp", sig.KeyID())
+	sig, err = parsed.Verify(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "bank", sig.KeyID())
+
+	_, err = m.Compact()
+	assert.Equal(t, SerializationError{Reason: "compact serialization requires exactly one signature"}, err)
+	_, err = m.Flattened()
+	assert.IsType(t, SerializationError{}, err)
+
+	t.Run("unprotected header", func(t *testing.T) {
+		m := &Message{Payload: []byte("x")}
+		require.NoError(t, m.Sign(Signer{Algorithm: HS256, Key: hmacKey, Header: map[string]any{"kid": "a"}}))
+		_, err := m.Compact()
+		assert.Equal(t, "crypto/jws: cannot serialize message: compact serialization cannot hold an unprotected header", err.Error())
+	})
+
+	t.Run("mixed b64", func(t *testing.T) {
+		m := &Message{Payload: []byte("x")}
+		require.NoError(t, m.Sign(Signer{Algorithm: HS256, Key: hmacKey}))
+		m.Unencoded = true
+		err := m.Sign(Signer{Algorithm: HS256, Key: hmacKey})
+		assert.IsType(t, InvalidPayloadError{}, err)
+	})
+}
+
+func TestSign_Error(t *testing.T) {
+	m := &Message{Payload: []byte("x")}
+	err := m.Sign(Signer{Algorithm: "none"})
+	assert.Equal(t, UnsupportedAlgorithmError{Algorithm: "none"}, err)
+	assert.Equal(t, `crypto/jws: unsupported algorithm "none"`, err.Error())
+
+	err = m.Sign(Signer{Algorithm: HS256, Key: hmacKey[:31]})
+	assert.Equal(t, InvalidKeyError{Algorithm: HS256, Key: hmacKey[:31]}, err)
+	assert.Equal(t, "crypto/jws: invalid key []uint8 for algorithm HS256", err.Error())
+
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.IsType(t, InvalidKeyError{}, m.Sign(Signer{Algorithm: ES384, Key: p256}))
+	assert.IsType(t, InvalidKeyError{}, m.Sign(Signer{Algorithm: RS256, Key: p256}))
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	assert.IsType(t, InvalidKeyError{}, m.Sign(Signer{Algorithm: RS256, Key: small}))
+	assert.Empty(t, m.Signatures)
+
+	_, err = m.Verify(hmacKey)
+	assert.Equal(t, EmptySignaturesError{}, err)
+	_, err = m.MarshalJSON()
+	assert.Equal(t, "crypto/jws: message has no signatures", err.Error())
+}
+
+func TestParse_Error(t *testing.T) {
+	encode := func(header string) string { return base64.RawURLEncoding.EncodeToString([]byte(header)) }
+
+	tests := []struct {
+		name string
+		in   string
+		err  error
+	}{
+		{"parts", "a.b", InvalidMessageError{}},
+		{"header base64", "!!.e30.c2ln", InvalidMessageError{}},
+		{"header json", encode("[") + ".e30.c2ln", InvalidMessageError{}},
+		{"none", encode(`{"alg":"none"}`) + ".e30.", UnsupportedAlgorithmError{Algorithm: "none"}},
+		{"signature base64", encode(`{"alg":"HS256"}`) + ".e30.!!", InvalidMessageError{}},
+		{"payload base64", encode(`{"alg":"HS256"}`) + ".!!.c2ln", InvalidMessageError{}},
+		{"unknown crit", encode(`{"alg":"HS256","crit":["exp"]}`) + ".e30.c2ln", UnsupportedCriticalHeaderError{Name: "exp"}},
+		{"empty crit", encode(`{"alg":"HS256","crit":[]}`) + ".e30.c2ln", InvalidMessageError{}},
+		{"b64 not crit", encode(`{"alg":"HS256","b64":false}`) + ".e30.c2ln", InvalidMessageError{}},
+		{"b64 not bool", encode(`{"alg":"HS256","b64":"no","crit":["b64"]}`) + ".e30.c2ln", InvalidMessageError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCompact(tt.in)
+			require.Error(t, err)
+			assert.IsType(t, tt.err, err)
+		})
+	}
+
+	t.Run("unsupported crit message", func(t *testing.T) {
+		_, err := ParseCompact(encode(`{"alg":"HS256","crit":["exp"]}`) + ".e30.c2ln")
+		assert.Equal(t, `crypto/jws: unsupported critical header parameter "exp"`, err.Error())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		_, err := ParseJSON([]byte("{"))
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = ParseJSON([]byte(`{"payload":"e30"}`))
+		assert.Equal(t, EmptySignaturesError{}, err)
+		_, err = ParseJSON([]byte(`{"payload":"e30","signature":"c2ln","signatures":[{"signature":"c2ln"}]}`))
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = ParseJSON([]byte(`{"payload":"e30","protected":"` + encode(`{"alg":"HS256"}`) + `","header":{"alg":"HS256"},"signature":"c2ln"}`))
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = ParseJSON([]byte(`{"payload":"e30","header":{"alg":"HS256","b64":false},"signature":"c2ln"}`))
+		assert.Equal(t, "crypto/jws: invalid message: b64 must be protected", err.Error())
+		_, err = ParseJSON([]byte(`{"payload":"e30","header":{"alg":"HS256","crit":["b64"]},"signature":"c2ln"}`))
+		assert.IsType(t, InvalidMessageError{}, err)
+		_, err = ParseJSON([]byte(`{"payload":"e30","signatures":[` +
+			`{"protected":"` + encode(`{"alg":"HS256"}`) + `","signature":"c2ln"},` +
+			`{"protected":"` + encode(`{"alg":"HS256","b64":false,"crit":["b64"]}`) + `","signature":"c2ln"}]}`))
+		assert.Equal(t, "crypto/jws: invalid message: signatures disagree on the b64 header parameter", err.Error())
+	})
+}
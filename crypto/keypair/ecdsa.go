@@ -0,0 +1,393 @@
+package keypair
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	encodingAsn1 "encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/crypto/internal/brainpool"
+	"github.com/dromara/dongle/crypto/internal/ecpoint"
+	"github.com/dromara/dongle/crypto/internal/secp256k1"
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/cryptobyte"
+	cryptoAsn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// oidEcPublicKey is the id-ecPublicKey algorithm identifier from RFC 5480.
+var oidEcPublicKey = encodingAsn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+// ecdsaCurves lists the supported ECDSA curves with their named curve OIDs.
+var ecdsaCurves = []struct {
+	curve func() elliptic.Curve
+	oid   encodingAsn1.ObjectIdentifier
+}{
+	{elliptic.P224, encodingAsn1.ObjectIdentifier{1, 3, 132, 0, 33}},
+	{elliptic.P256, encodingAsn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}},
+	{elliptic.P384, encodingAsn1.ObjectIdentifier{1, 3, 132, 0, 34}},
+	{elliptic.P521, encodingAsn1.ObjectIdentifier{1, 3, 132, 0, 35}},
+	{brainpool.P256r1, encodingAsn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 7}},
+	{brainpool.P384r1, encodingAsn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 11}},
+	{secp256k1.Curve, encodingAsn1.ObjectIdentifier{1, 3, 132, 0, 10}},
+}
+
+// BrainpoolP256r1 returns the brainpoolP256r1 curve from RFC 5639.
+// Only its scalar multiplication is constant time.
+func BrainpoolP256r1() elliptic.Curve {
+	return brainpool.P256r1()
+}
+
+// BrainpoolP384r1 returns the brainpoolP384r1 curve from RFC 5639.
+// Only its scalar multiplication is constant time.
+func BrainpoolP384r1() elliptic.Curve {
+	return brainpool.P384r1()
+}
+
+// EcdsaKeyPair represents an ECDSA key pair with public and private keys.
+// Keys are handled in PKCS8 or SEC 1 (for private) and PKIX (for public) PEM
+// formats. The NIST curves P-224, P-256, P-384 and P-521, brainpoolP256r1,
+// brainpoolP384r1 and secp256k1 are supported.
+type EcdsaKeyPair struct {
+	// PublicKey contains the PEM-encoded public key
+	PublicKey []byte
+
+	// PrivateKey contains the PEM-encoded private key
+	PrivateKey []byte
+
+	// Curve is the curve used to generate keys and to parse raw public points.
+	// Default is P-256.
+	Curve elliptic.Curve
+
+	// Hash is the hash function applied to messages before signing.
+	// Default is SHA-256.
+	Hash crypto.Hash
+
+	// Validity is the window in which the key pair may sign and encrypt.
+	Validity
+
+	// signer is the external key set by SetSigner, such as a key held in an HSM.
+	signer crypto.Signer
+}
+
+// NewEcdsaKeyPair returns a new EcdsaKeyPair with defaults
+// (Curve=P-256, Hash=SHA256).
+func NewEcdsaKeyPair() *EcdsaKeyPair {
+	return &EcdsaKeyPair{
+		Curve: elliptic.P256(),
+		Hash:  crypto.SHA256,
+	}
+}
+
+// SetCurve sets the curve used to generate keys.
+func (k *EcdsaKeyPair) SetCurve(curve elliptic.Curve) {
+	k.Curve = curve
+}
+
+// SetHash sets the hash function applied to messages before signing.
+func (k *EcdsaKeyPair) SetHash(hash crypto.Hash) {
+	k.Hash = hash
+}
+
+// GenKeyPair generates a new ECDSA key pair on the configured curve and fills
+// PublicKey/PrivateKey. Private key is PKCS#8 (PEM "PRIVATE KEY"), public key
+// is SPKI/PKIX (PEM "PUBLIC KEY").
+func (k *EcdsaKeyPair) GenKeyPair() error {
+	oid, err := curveOID(k.Curve)
+	if err != nil {
+		return err
+	}
+	params := k.Curve.Params()
+	d, err := randScalar(params.N, utils.Rand())
+	if err != nil {
+		return err
+	}
+	x, y := k.Curve.ScalarBaseMult(d.Bytes())
+	point := ecpoint.Marshal(k.Curve, x, y, false)
+	k.PrivateKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: marshalEcdsaPKCS8(oid, d, params.N, point)})
+	k.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: marshalEcdsaSPKI(oid, point)})
+	return nil
+}
+
+// SetPublicKey sets the public key after formatting to PEM.
+// Accepts base64-encoded DER of SubjectPublicKeyInfo.
+func (k *EcdsaKeyPair) SetPublicKey(publicKey []byte) error {
+	key, err := k.FormatPublicKey(publicKey)
+	if err == nil {
+		k.PublicKey = key
+	}
+	return err
+}
+
+// SetPrivateKey sets the private key after formatting to PEM.
+// Accepts base64-encoded DER of PKCS#8 PrivateKeyInfo.
+func (k *EcdsaKeyPair) SetPrivateKey(privateKey []byte) error {
+	key, err := k.FormatPrivateKey(privateKey)
+	if err == nil {
+		k.PrivateKey = key
+	}
+	return err
+}
+
+// ParsePublicKey parses the PEM-encoded public key and returns *ecdsa.PublicKey.
+// A raw point in the uncompressed (04) or compressed (02/03) form on the
+// configured curve is accepted too.
+func (k *EcdsaKeyPair) ParsePublicKey() (*ecdsa.PublicKey, error) {
+	publicKey := k.PublicKey
+	if len(publicKey) == 0 {
+		return nil, EmptyPublicKeyError{}
+	}
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		if k.Curve == nil {
+			return nil, InvalidPublicKeyError{}
+		}
+		return ParsePoint(k.Curve, publicKey)
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, InvalidPublicKeyError{}
+	}
+	pub, err := parseEcdsaSPKI(block.Bytes)
+	if err != nil {
+		return nil, InvalidPublicKeyError{Err: err}
+	}
+	return pub, nil
+}
+
+// ParsePrivateKey parses the PEM-encoded private key and returns *ecdsa.PrivateKey.
+// Both PKCS#8 ("PRIVATE KEY") and SEC 1 ("EC PRIVATE KEY") keys are accepted.
+func (k *EcdsaKeyPair) ParsePrivateKey() (*ecdsa.PrivateKey, error) {
+	privateKey := k.PrivateKey
+	if len(privateKey) == 0 {
+		return nil, EmptyPrivateKeyError{}
+	}
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, InvalidPrivateKeyError{}
+	}
+	var (
+		pri *ecdsa.PrivateKey
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		pri, err = parseEcdsaPKCS8(block.Bytes)
+	case "EC PRIVATE KEY":
+		pri, err = parseEcdsaSEC1(block.Bytes, nil)
+	default:
+		return nil, InvalidPrivateKeyError{}
+	}
+	if err != nil {
+		return nil, InvalidPrivateKeyError{Err: err}
+	}
+	return pri, nil
+}
+
+// FormatPublicKey formats base64-encoded der public key into the specified PEM format.
+func (k *EcdsaKeyPair) FormatPublicKey(publicKey []byte) ([]byte, error) {
+	if len(publicKey) == 0 {
+		return []byte{}, EmptyPublicKeyError{}
+	}
+	decoder := coding.NewDecoder().FromBytes(publicKey).ByBase64()
+	if decoder.Error != nil {
+		return []byte{}, InvalidPublicKeyError{Err: decoder.Error}
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: decoder.ToBytes(),
+	}), nil
+}
+
+// FormatPrivateKey formats base64-encoded der private key into the specified PEM format.
+func (k *EcdsaKeyPair) FormatPrivateKey(privateKey []byte) ([]byte, error) {
+	if len(privateKey) == 0 {
+		return []byte{}, EmptyPrivateKeyError{}
+	}
+	decoder := coding.NewDecoder().FromBytes(privateKey).ByBase64()
+	if decoder.Error != nil {
+		return []byte{}, InvalidPrivateKeyError{Err: decoder.Error}
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: decoder.ToBytes(),
+	}), nil
+}
+
+// SharedSecret performs ECDH between the private key and a peer public key
+// on the same curve and returns the x coordinate of the shared point, as
+// described in SEC 1, section 3.3.1. Derive keys from it with a KDF rather
+// than using it directly.
+func (k *EcdsaKeyPair) SharedSecret(peer *ecdsa.PublicKey) ([]byte, error) {
+	pri, err := k.ParsePrivateKey()
+	if err != nil {
+		return []byte{}, err
+	}
+	if peer == nil || peer.Curve != pri.Curve || !peer.Curve.IsOnCurve(peer.X, peer.Y) {
+		return []byte{}, InvalidPublicKeyError{Err: errors.New("peer key is not on the private key curve")}
+	}
+	x, y := pri.Curve.ScalarMult(peer.X, peer.Y, pri.D.Bytes())
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return []byte{}, InvalidPublicKeyError{Err: errors.New("shared point is at infinity")}
+	}
+	return x.FillBytes(make([]byte, (pri.Curve.Params().BitSize+7)/8)), nil
+}
+
+// curveOID returns the named curve OID of curve.
+func curveOID(curve elliptic.Curve) (encodingAsn1.ObjectIdentifier, error) {
+	if curve == nil {
+		return nil, UnsupportedCurveError{}
+	}
+	for _, c := range ecdsaCurves {
+		if c.curve() == curve {
+			return c.oid, nil
+		}
+	}
+	return nil, UnsupportedCurveError{Curve: curve.Params().Name}
+}
+
+// curveByOID returns the curve named by oid.
+func curveByOID(oid encodingAsn1.ObjectIdentifier) (elliptic.Curve, error) {
+	for _, c := range ecdsaCurves {
+		if c.oid.Equal(oid) {
+			return c.curve(), nil
+		}
+	}
+	return nil, UnsupportedCurveError{Curve: oid.String()}
+}
+
+// randScalar returns a uniformly random scalar in [1, n-1].
+func randScalar(n *big.Int, random io.Reader) (*big.Int, error) {
+	d, err := rand.Int(random, new(big.Int).Sub(n, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return d.Add(d, big.NewInt(1)), nil
+}
+
+// marshalEcdsaSPKI encodes a SubjectPublicKeyInfo for an EC point.
+func marshalEcdsaSPKI(oid encodingAsn1.ObjectIdentifier, point []byte) []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(cryptoAsn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptoAsn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(oidEcPublicKey)
+			b.AddASN1ObjectIdentifier(oid)
+		})
+		b.AddASN1BitString(point)
+	})
+	return b.BytesOrPanic()
+}
+
+// marshalEcdsaPKCS8 encodes a PKCS#8 PrivateKeyInfo wrapping a SEC 1
+// ECPrivateKey with the public point.
+func marshalEcdsaPKCS8(oid encodingAsn1.ObjectIdentifier, d, n *big.Int, point []byte) []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(cryptoAsn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0)
+		b.AddASN1(cryptoAsn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(oidEcPublicKey)
+			b.AddASN1ObjectIdentifier(oid)
+		})
+		b.AddASN1(cryptoAsn1.OCTET_STRING, func(b *cryptobyte.Builder) {
+			b.AddASN1(cryptoAsn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1Int64(1)
+				b.AddASN1OctetString(d.FillBytes(make([]byte, (n.BitLen()+7)/8)))
+				b.AddASN1(cryptoAsn1.Tag(1).ContextSpecific().Constructed(), func(b *cryptobyte.Builder) {
+					b.AddASN1BitString(point)
+				})
+			})
+		})
+	})
+	return b.BytesOrPanic()
+}
+
+// parseEcdsaSPKI parses a SubjectPublicKeyInfo holding an EC public key.
+func parseEcdsaSPKI(der []byte) (*ecdsa.PublicKey, error) {
+	in := cryptobyte.String(der)
+	var spki, ai cryptobyte.String
+	var alg, oid encodingAsn1.ObjectIdentifier
+	var point encodingAsn1.BitString
+	if !in.ReadASN1(&spki, cryptoAsn1.SEQUENCE) || !in.Empty() ||
+		!spki.ReadASN1(&ai, cryptoAsn1.SEQUENCE) ||
+		!ai.ReadASN1ObjectIdentifier(&alg) || !alg.Equal(oidEcPublicKey) ||
+		!ai.ReadASN1ObjectIdentifier(&oid) ||
+		!spki.ReadASN1BitString(&point) || !spki.Empty() {
+		return nil, encodingAsn1.SyntaxError{Msg: "invalid SubjectPublicKeyInfo"}
+	}
+	curve, err := curveByOID(oid)
+	if err != nil {
+		return nil, err
+	}
+	x, y, err := ecpoint.Unmarshal(curve, point.RightAlign())
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// parseEcdsaPKCS8 parses a PKCS#8 PrivateKeyInfo holding an EC private key.
+func parseEcdsaPKCS8(der []byte) (*ecdsa.PrivateKey, error) {
+	in := cryptobyte.String(der)
+	var p8, ai, key cryptobyte.String
+	var version int64
+	var alg, oid encodingAsn1.ObjectIdentifier
+	if !in.ReadASN1(&p8, cryptoAsn1.SEQUENCE) || !in.Empty() ||
+		!p8.ReadASN1Int64WithTag(&version, cryptoAsn1.INTEGER) ||
+		!p8.ReadASN1(&ai, cryptoAsn1.SEQUENCE) ||
+		!ai.ReadASN1ObjectIdentifier(&alg) || !alg.Equal(oidEcPublicKey) ||
+		!ai.ReadASN1ObjectIdentifier(&oid) ||
+		!p8.ReadASN1(&key, cryptoAsn1.OCTET_STRING) {
+		return nil, encodingAsn1.SyntaxError{Msg: "invalid PKCS#8 PrivateKeyInfo"}
+	}
+	curve, err := curveByOID(oid)
+	if err != nil {
+		return nil, err
+	}
+	return parseEcdsaSEC1(key, curve)
+}
+
+// parseEcdsaSEC1 parses a SEC 1 ECPrivateKey. The curve comes from the
+// enclosing PKCS#8 structure when given, and from the key parameters otherwise.
+func parseEcdsaSEC1(der []byte, curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	in := cryptobyte.String(der)
+	var seq cryptobyte.String
+	var version int64
+	var scalar []byte
+	if !in.ReadASN1(&seq, cryptoAsn1.SEQUENCE) || !in.Empty() ||
+		!seq.ReadASN1Int64WithTag(&version, cryptoAsn1.INTEGER) || version != 1 ||
+		!seq.ReadASN1Bytes(&scalar, cryptoAsn1.OCTET_STRING) {
+		return nil, encodingAsn1.SyntaxError{Msg: "invalid ECPrivateKey"}
+	}
+	var params cryptobyte.String
+	var hasParams bool
+	if !seq.ReadOptionalASN1(&params, &hasParams, cryptoAsn1.Tag(0).ContextSpecific().Constructed()) {
+		return nil, encodingAsn1.SyntaxError{Msg: "invalid ECPrivateKey parameters"}
+	}
+	if hasParams {
+		var oid encodingAsn1.ObjectIdentifier
+		if !params.ReadASN1ObjectIdentifier(&oid) {
+			return nil, encodingAsn1.SyntaxError{Msg: "invalid ECPrivateKey parameters"}
+		}
+		named, err := curveByOID(oid)
+		if err != nil {
+			return nil, err
+		}
+		if curve != nil && curve != named {
+			return nil, encodingAsn1.StructuralError{Msg: "mismatched curve parameters"}
+		}
+		curve = named
+	}
+	if curve == nil {
+		return nil, encodingAsn1.StructuralError{Msg: "missing curve parameters"}
+	}
+	d := new(big.Int).SetBytes(scalar)
+	if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, encodingAsn1.StructuralError{Msg: "private scalar out of range"}
+	}
+	x, y := curve.ScalarBaseMult(scalar)
+	return &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: curve, X: x, Y: y}, D: d}, nil
+}
@@ -1,16 +1,32 @@
 package keypair
 
 import (
+	"crypto"
 	"crypto/ed25519"
-	"crypto/rand"
+	"crypto/sha512"
 	"crypto/x509"
 	"encoding/pem"
+	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/dromara/dongle/coding"
 	"github.com/dromara/dongle/internal/utils"
 )
 
+// Ed25519Variant selects the RFC 8032 signature scheme used with an Ed25519 key.
+type Ed25519Variant string
+
+// Ed25519 signature variants.
+const (
+	// Ed25519 is plain Ed25519, signing the message itself without a context.
+	Ed25519 Ed25519Variant = "Ed25519"
+	// Ed25519ph signs the SHA-512 digest of the message with an optional context.
+	Ed25519ph Ed25519Variant = "Ed25519ph"
+	// Ed25519ctx signs the message itself with a mandatory, non-empty context.
+	Ed25519ctx Ed25519Variant = "Ed25519ctx"
+)
+
 // Ed25519KeyPair represents an ED25519 key pair with public and private keys.
 // It supports PKCS8 format and provides methods for key generation,
 // formatting, and parsing.
@@ -23,6 +39,20 @@ type Ed25519KeyPair struct {
 
 	// Signature contains the signature bytes for verification
 	Signature []byte
+
+	// Variant selects plain Ed25519, Ed25519ph or Ed25519ctx.
+	// Default is plain Ed25519.
+	Variant Ed25519Variant
+
+	// Context is the domain separation string of Ed25519ph and Ed25519ctx,
+	// at most 255 bytes. It is ignored by plain Ed25519.
+	Context []byte
+
+	// Validity is the window in which the key pair may sign and encrypt.
+	Validity
+
+	// signer is the external key set by SetSigner, such as a key held in an HSM.
+	signer crypto.Signer
 }
 
 // NewEd25519KeyPair returns a new Ed25519KeyPair instance.
@@ -35,7 +65,7 @@ func NewEd25519KeyPair() *Ed25519KeyPair {
 //
 // Note: The generated keys are automatically formatted in PEM format using PKCS8 format.
 func (k *Ed25519KeyPair) GenKeyPair() error {
-	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	publicKey, privateKey, err := ed25519.GenerateKey(utils.Rand())
 	if err != nil {
 		return err
 	}
@@ -58,6 +88,16 @@ func (k *Ed25519KeyPair) GenKeyPair() error {
 	return nil
 }
 
+// SetVariant sets the signature variant to Ed25519, Ed25519ph or Ed25519ctx.
+func (k *Ed25519KeyPair) SetVariant(variant Ed25519Variant) {
+	k.Variant = variant
+}
+
+// SetContext sets the context of Ed25519ph and Ed25519ctx signatures.
+func (k *Ed25519KeyPair) SetContext(context []byte) {
+	k.Context = context
+}
+
 // SetPublicKey sets the public key and formats it in PKCS8 format.
 // The input key is expected to be in PEM format and will be reformatted if necessary.
 func (k *Ed25519KeyPair) SetPublicKey(publicKey []byte) error {
@@ -190,7 +230,7 @@ func (k *Ed25519KeyPair) CompressPublicKey(publicKey []byte) []byte {
 	// Remove any remaining whitespace that might be present
 	keyStr = strings.TrimSpace(keyStr)
 
-	return utils.String2Bytes(keyStr)
+	return utils.String2BytesCopy(keyStr)
 }
 
 // CompressPrivateKey removes the PEM headers and footers from the private key.
@@ -215,5 +255,48 @@ func (k *Ed25519KeyPair) CompressPrivateKey(privateKey []byte) []byte {
 	// Remove any remaining whitespace that might be present
 	keyStr = strings.TrimSpace(keyStr)
 
-	return utils.String2Bytes(keyStr)
+	return utils.String2BytesCopy(keyStr)
+}
+
+// X25519PublicKey converts the Ed25519 public key to the equivalent X25519
+// public key, mapping the Edwards y coordinate to the Montgomery u coordinate
+// with u = (1 + y) / (1 - y) as described in RFC 7748.
+func (k *Ed25519KeyPair) X25519PublicKey() ([]byte, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+	// The encoding is little endian with the sign of x in the top bit.
+	enc := slices.Clone(pub)
+	enc[31] &= 0x7f
+	slices.Reverse(enc)
+	y := new(big.Int).SetBytes(enc)
+	if y.Cmp(p) >= 0 {
+		return nil, InvalidPublicKeyError{}
+	}
+
+	den := new(big.Int).Sub(big.NewInt(1), y)
+	if den.Mod(den, p).Sign() == 0 {
+		return nil, InvalidPublicKeyError{}
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, den.ModInverse(den, p)).Mod(u, p)
+
+	out := u.FillBytes(make([]byte, 32))
+	slices.Reverse(out)
+	return out, nil
+}
+
+// X25519PrivateKey converts the Ed25519 private key to the equivalent X25519
+// private key, the first half of the SHA-512 hash of the seed. X25519 clamps
+// the scalar itself, so the result can be used with crypto/ecdh as is.
+func (k *Ed25519KeyPair) X25519PrivateKey() ([]byte, error) {
+	pri, err := k.ParsePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	h := sha512.Sum512(pri.Seed())
+	return h[:32], nil
 }
@@ -1,12 +1,17 @@
 package keypair
 
 import (
+	"crypto/ecdh"
+	"crypto/ed25519"
 	stdRand "crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"testing"
 
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewEd25519KeyPair(t *testing.T) {
@@ -271,3 +276,47 @@ func TestEd25519_Compress(t *testing.T) {
 	assert.NotContains(t, string(priBody), "BEGIN")
 	assert.NotContains(t, string(priBody), "\n")
 }
+
+func TestEd25519KeyPairVariant(t *testing.T) {
+	kp := NewEd25519KeyPair()
+	assert.Empty(t, kp.Variant)
+	kp.SetVariant(Ed25519ph)
+	kp.SetContext([]byte("context"))
+	assert.Equal(t, Ed25519ph, kp.Variant)
+	assert.Equal(t, []byte("context"), kp.Context)
+}
+
+func TestEd25519KeyPairX25519(t *testing.T) {
+	t.Run("converted keys match", func(t *testing.T) {
+		for i := 0; i < 8; i++ {
+			kp := NewEd25519KeyPair()
+			require.NoError(t, kp.GenKeyPair())
+
+			priv, err := kp.X25519PrivateKey()
+			require.NoError(t, err)
+			pub, err := kp.X25519PublicKey()
+			require.NoError(t, err)
+
+			key, err := ecdh.X25519().NewPrivateKey(priv)
+			require.NoError(t, err)
+			assert.Equal(t, key.PublicKey().Bytes(), pub)
+		}
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		kp := NewEd25519KeyPair()
+		_, err := kp.X25519PublicKey()
+		assert.IsType(t, EmptyPublicKeyError{}, err)
+		_, err = kp.X25519PrivateKey()
+		assert.IsType(t, EmptyPrivateKeyError{}, err)
+
+		// The identity point has y = 1 and no Montgomery equivalent.
+		identity := make([]byte, 32)
+		identity[0] = 1
+		der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(identity))
+		require.NoError(t, err)
+		kp.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		_, err = kp.X25519PublicKey()
+		assert.Equal(t, InvalidPublicKeyError{}, err)
+	})
+}
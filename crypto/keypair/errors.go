@@ -1,6 +1,11 @@
 package keypair
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 type EmptyPublicKeyError struct {
 }
@@ -9,6 +14,16 @@ func (e EmptyPublicKeyError) Error() string {
 	return "public key cannot be empty"
 }
 
+// Code returns the stable error code DGL-KEYPAIR-001.
+func (e EmptyPublicKeyError) Code() string {
+	return "DGL-KEYPAIR-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyPublicKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key")
+}
+
 type InvalidPublicKeyError struct {
 	Err error
 }
@@ -17,6 +32,16 @@ func (e InvalidPublicKeyError) Error() string {
 	return fmt.Sprintf("invalid public key: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-KEYPAIR-002.
+func (e InvalidPublicKeyError) Code() string {
+	return "DGL-KEYPAIR-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPublicKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key", errcode.FieldCause, e.Err)
+}
+
 type EmptyPrivateKeyError struct {
 }
 
@@ -24,6 +49,16 @@ func (e EmptyPrivateKeyError) Error() string {
 	return "private key cannot be empty"
 }
 
+// Code returns the stable error code DGL-KEYPAIR-003.
+func (e EmptyPrivateKeyError) Code() string {
+	return "DGL-KEYPAIR-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyPrivateKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key")
+}
+
 type InvalidPrivateKeyError struct {
 	Err error
 }
@@ -32,6 +67,16 @@ func (e InvalidPrivateKeyError) Error() string {
 	return fmt.Sprintf(" invalid private key: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-KEYPAIR-004.
+func (e InvalidPrivateKeyError) Code() string {
+	return "DGL-KEYPAIR-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPrivateKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key", errcode.FieldCause, e.Err)
+}
+
 type EmptyFormatError struct {
 }
 
@@ -39,6 +84,16 @@ func (e EmptyFormatError) Error() string {
 	return "key format cannot be empty, please call SetFormat() to set key format (PKCS1/PKCS8)"
 }
 
+// Code returns the stable error code DGL-KEYPAIR-005.
+func (e EmptyFormatError) Code() string {
+	return "DGL-KEYPAIR-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyFormatError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "")
+}
+
 type UnsupportedKeyFormatError struct {
 }
 
@@ -46,6 +101,16 @@ func (e UnsupportedKeyFormatError) Error() string {
 	return "unsupported key format, only PKCS1 and PKCS8 are supported"
 }
 
+// Code returns the stable error code DGL-KEYPAIR-006.
+func (e UnsupportedKeyFormatError) Code() string {
+	return "DGL-KEYPAIR-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedKeyFormatError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key")
+}
+
 type EmptyPaddingError struct {
 }
 
@@ -53,6 +118,16 @@ func (e EmptyPaddingError) Error() string {
 	return "padding scheme cannot be empty, please call SetPadding() to set padding scheme (PKCS1v15/OAEP/PSS)"
 }
 
+// Code returns the stable error code DGL-KEYPAIR-007.
+func (e EmptyPaddingError) Code() string {
+	return "DGL-KEYPAIR-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyPaddingError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "")
+}
+
 type UnsupportedPaddingSchemeError struct {
 	Padding string
 }
@@ -61,9 +136,191 @@ func (e UnsupportedPaddingSchemeError) Error() string {
 	return fmt.Sprintf("unsupported padding scheme: %s, only PKCS1v15, OAEP, and PSS are supported", e.Padding)
 }
 
+// Code returns the stable error code DGL-KEYPAIR-008.
+func (e UnsupportedPaddingSchemeError) Code() string {
+	return "DGL-KEYPAIR-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedPaddingSchemeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "", "padding", e.Padding)
+}
+
 type EmptySignatureError struct {
 }
 
 func (e EmptySignatureError) Error() string {
 	return "no signature provided for verification"
 }
+
+// Code returns the stable error code DGL-KEYPAIR-009.
+func (e EmptySignatureError) Code() string {
+	return "DGL-KEYPAIR-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptySignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "verify")
+}
+
+type InvalidSignatureError struct {
+	Err error
+}
+
+func (e InvalidSignatureError) Error() string {
+	return fmt.Sprintf("invalid signature: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-010.
+func (e InvalidSignatureError) Code() string {
+	return "DGL-KEYPAIR-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "signature", errcode.FieldCause, e.Err)
+}
+
+type UnsupportedCurveError struct {
+	Curve string
+}
+
+func (e UnsupportedCurveError) Error() string {
+	return fmt.Sprintf("unsupported curve: %s", e.Curve)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-011.
+func (e UnsupportedCurveError) Code() string {
+	return "DGL-KEYPAIR-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedCurveError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "signature", "curve", e.Curve)
+}
+
+// UnsupportedSignerError represents an error when an external signer or
+// decrypter holds a key of the wrong type for the key pair.
+type UnsupportedSignerError struct {
+	Key string // Go type of the public key
+}
+
+func (e UnsupportedSignerError) Error() string {
+	return fmt.Sprintf("unsupported signer key type: %s", e.Key)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-012.
+func (e UnsupportedSignerError) Code() string {
+	return "DGL-KEYPAIR-012"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedSignerError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key", "key", e.Key)
+}
+
+// UnsupportedHashError represents an error when a signer is asked to sign a
+// digest it cannot accept, such as an SM2 signer given a pre-hashed message.
+type UnsupportedHashError struct {
+	Hash string
+}
+
+func (e UnsupportedHashError) Error() string {
+	return fmt.Sprintf("unsupported hash: %s, pass crypto.Hash(0) and the message itself", e.Hash)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-013.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-KEYPAIR-013"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "signature", "hash", e.Hash)
+}
+
+// KeyUsageError represents an error when a key pair is used for an operation
+// its usage does not allow, such as signing with an encrypt-only key.
+type KeyUsageError struct {
+	Usage     KeyUsage // Usage set on the key pair
+	Operation KeyUsage // Operation attempted, Sign or Encrypt
+}
+
+func (e KeyUsageError) Error() string {
+	return fmt.Sprintf("key usage '%s' does not allow %s operations", e.Usage, e.Operation)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-014.
+func (e KeyUsageError) Code() string {
+	return "DGL-KEYPAIR-014"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyUsageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", string(e.Operation), "usage", e.Usage)
+}
+
+// KeyExpiredError represents an error when a key pair is used to sign or
+// encrypt outside its validity window, before NotBefore or after NotAfter.
+type KeyExpiredError struct {
+	NotBefore time.Time // Start of the window, zero if open
+	NotAfter  time.Time // End of the window, zero if open
+	Now       time.Time // Time the key was used
+}
+
+func (e KeyExpiredError) Error() string {
+	if !e.NotBefore.IsZero() && e.Now.Before(e.NotBefore) {
+		return fmt.Sprintf("key not valid before %s", e.NotBefore.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("key expired at %s", e.NotAfter.Format(time.RFC3339))
+}
+
+// Code returns the stable error code DGL-KEYPAIR-015.
+func (e KeyExpiredError) Code() string {
+	return "DGL-KEYPAIR-015"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyExpiredError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "", "key", "not_before", e.NotBefore, "not_after", e.NotAfter)
+}
+
+// InvalidUIDError represents an error when an SM2 UID is too long for the
+// 16-bit ENTL field of the ZA calculation.
+type InvalidUIDError struct {
+	Size int // Size of the UID in bytes
+}
+
+func (e InvalidUIDError) Error() string {
+	return fmt.Sprintf("invalid sm2 uid size %d, must be at most %d bytes", e.Size, MaxSm2UIDSize)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-016.
+func (e InvalidUIDError) Code() string {
+	return "DGL-KEYPAIR-016"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidUIDError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "SM2", "uid", "size", e.Size)
+}
+
+// InvalidCommentError represents an error when an OpenSSH key comment holds
+// control characters, such as line breaks.
+type InvalidCommentError struct {
+	Comment string // The rejected comment
+}
+
+func (e InvalidCommentError) Error() string {
+	return fmt.Sprintf("invalid openssh key comment %q, control characters are not allowed", e.Comment)
+}
+
+// Code returns the stable error code DGL-KEYPAIR-017.
+func (e InvalidCommentError) Code() string {
+	return "DGL-KEYPAIR-017"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCommentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keypair", "OpenSSH", "marshal", "comment", e.Comment)
+}
@@ -78,3 +78,43 @@ func TestEmptySignatureError_Error(t *testing.T) {
 		t.Errorf("EmptySignatureError.Error() = %q, want %q", err.Error(), expected)
 	}
 }
+
+func TestInvalidSignatureError_Error(t *testing.T) {
+	err := InvalidSignatureError{Err: errors.New("test error")}
+	expected := "invalid signature: test error"
+	if err.Error() != expected {
+		t.Errorf("InvalidSignatureError.Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestUnsupportedCurveError_Error(t *testing.T) {
+	err := UnsupportedCurveError{Curve: "SM2-P-256"}
+	expected := "unsupported curve: SM2-P-256"
+	if err.Error() != expected {
+		t.Errorf("UnsupportedCurveError.Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestUnsupportedSignerError_Error(t *testing.T) {
+	err := UnsupportedSignerError{Key: "*ecdsa.PublicKey"}
+	expected := "unsupported signer key type: *ecdsa.PublicKey"
+	if err.Error() != expected {
+		t.Errorf("UnsupportedSignerError.Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestUnsupportedHashError_Error(t *testing.T) {
+	err := UnsupportedHashError{Hash: "SHA-256"}
+	expected := "unsupported hash: SHA-256, pass crypto.Hash(0) and the message itself"
+	if err.Error() != expected {
+		t.Errorf("UnsupportedHashError.Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestInvalidUIDError_Error(t *testing.T) {
+	err := InvalidUIDError{Size: 8192}
+	expected := "invalid sm2 uid size 8192, must be at most 8191 bytes"
+	if err.Error() != expected {
+		t.Errorf("InvalidUIDError.Error() = %q, want %q", err.Error(), expected)
+	}
+}
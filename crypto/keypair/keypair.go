@@ -4,12 +4,19 @@
 //   - RSA: Supports PKCS1 and PKCS8 key formats, with configurable padding schemes
 //   - SM2: Supports PKCS8/PKIX formats with configurable ciphertext order
 //   - Ed25519: Supports PKCS8 format
+//   - ECDSA: Supports PKCS8/SEC1/PKIX formats on NIST, Brainpool and secp256k1 curves
 //
 // Each key pair type provides methods for:
 //   - Generating new key pairs
 //   - Parsing keys from PEM format
 //   - Formatting keys to PEM format
 //   - Setting algorithm-specific parameters
+//   - Exposing private keys as crypto.Signer and crypto.Decrypter, and
+//     accepting external ones such as HSM or KMS keys through SetSigner
+//   - Restricting signing and encryption to a validity window, and RSA and
+//     SM2 keys to a single usage
+//   - Exporting RSA, ECDSA and Ed25519 keys in the OpenSSH formats, with the
+//     private key optionally protected by a bcrypt derived passphrase key
 package keypair
 
 // KeyType represents the type of cryptographic key (public or private).
@@ -21,3 +28,61 @@ const (
 	PublicKey  KeyType = "publicKey"
 	PrivateKey KeyType = "privateKey"
 )
+
+// KeyUsage restricts the operations an RSA or SM2 key pair may be used for,
+// catching one key being reused for both signing and encryption. The zero
+// value allows both.
+type KeyUsage string
+
+const (
+	// Sign allows signing and verification only.
+	Sign KeyUsage = "sign"
+	// Encrypt allows encryption and decryption only.
+	Encrypt KeyUsage = "encrypt"
+	// Both allows every operation, the default.
+	Both KeyUsage = "both"
+)
+
+// Check returns a KeyUsageError unless the usage allows operation, which is
+// Sign for signing and verification or Encrypt for encryption and decryption.
+func (u KeyUsage) Check(operation KeyUsage) error {
+	if u == "" || u == Both || u == operation {
+		return nil
+	}
+	return KeyUsageError{Usage: u, Operation: operation}
+}
+
+// Operation is a use of an RSA or SM2 key pair, checked by CheckUse.
+type Operation string
+
+const (
+	// SignOp is signing, allowed by the Sign usage within the validity window.
+	SignOp Operation = "sign"
+	// VerifyOp is verification, allowed by the Sign usage at any time.
+	VerifyOp Operation = "verify"
+	// EncryptOp is encryption, allowed by the Encrypt usage within the validity window.
+	EncryptOp Operation = "encrypt"
+	// DecryptOp is decryption, allowed by the Encrypt usage at any time.
+	DecryptOp Operation = "decrypt"
+)
+
+// checkUse returns an error unless a key pair with usage and validity v may
+// perform op now. Signing and encryption also need the current time to be
+// within the validity window, verification and decryption do not, see
+// Validity.
+func checkUse(usage KeyUsage, v Validity, op Operation) error {
+	switch op {
+	case SignOp, VerifyOp:
+		if err := usage.Check(Sign); err != nil {
+			return err
+		}
+	default:
+		if err := usage.Check(Encrypt); err != nil {
+			return err
+		}
+	}
+	if op == SignOp || op == EncryptOp {
+		return v.CheckValidity()
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package keypair
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dromara/dongle/hash/visual"
+	"golang.org/x/crypto/ssh"
+)
+
+// OpenSSHPrivateKey returns the private key in the "openssh-key-v1" format
+// written by ssh-keygen, ready to be saved as ~/.ssh/id_rsa. With a passphrase
+// the key is encrypted with aes256-ctr under a key derived by bcrypt_pbkdf,
+// as ssh-keygen -p does; an empty passphrase writes it unencrypted.
+func (k *RsaKeyPair) OpenSSHPrivateKey(passphrase []byte, comment string) ([]byte, error) {
+	pri, err := k.ParsePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return marshalOpenSSH(pri, passphrase, comment)
+}
+
+// OpenSSHPublicKey returns the public key as an authorized_keys line, ready to
+// be saved as ~/.ssh/id_rsa.pub.
+func (k *RsaKeyPair) OpenSSHPublicKey(comment string) ([]byte, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return marshalAuthorizedKey(pub, comment)
+}
+
+// OpenSSHFingerprint returns the SHA256 fingerprint of the public key as
+// ssh-keygen -l prints it, such as "SHA256:XhrD98Yg01vqYB+MZNFtqr7oaemMUPcms+QkvPLephs".
+func (k *RsaKeyPair) OpenSSHFingerprint() (string, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return "", err
+	}
+	return openSSHFingerprint(pub)
+}
+
+// OpenSSHRandomArt returns the randomart of the public key as ssh-keygen -lv
+// prints it below the fingerprint, for comparing keys by eye.
+func (k *RsaKeyPair) OpenSSHRandomArt() (string, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return "", err
+	}
+	return openSSHRandomArt(pub, "RSA", pub.N.BitLen())
+}
+
+// OpenSSHPrivateKey returns the private key in the "openssh-key-v1" format,
+// ready to be saved as ~/.ssh/id_ecdsa, encrypted as for RsaKeyPair.
+func (k *EcdsaKeyPair) OpenSSHPrivateKey(passphrase []byte, comment string) ([]byte, error) {
+	pri, err := k.ParsePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return marshalOpenSSH(pri, passphrase, comment)
+}
+
+// OpenSSHPublicKey returns the public key as an authorized_keys line, ready to
+// be saved as ~/.ssh/id_ecdsa.pub. Only the NIST P-256, P-384 and P-521 curves
+// are supported by OpenSSH.
+func (k *EcdsaKeyPair) OpenSSHPublicKey(comment string) ([]byte, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return marshalAuthorizedKey(pub, comment)
+}
+
+// OpenSSHFingerprint returns the SHA256 fingerprint of the public key as
+// ssh-keygen -l prints it.
+func (k *EcdsaKeyPair) OpenSSHFingerprint() (string, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return "", err
+	}
+	return openSSHFingerprint(pub)
+}
+
+// OpenSSHRandomArt returns the randomart of the public key as ssh-keygen -lv
+// prints it below the fingerprint, for comparing keys by eye.
+func (k *EcdsaKeyPair) OpenSSHRandomArt() (string, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return "", err
+	}
+	return openSSHRandomArt(pub, "ECDSA", pub.Curve.Params().BitSize)
+}
+
+// OpenSSHPrivateKey returns the private key in the "openssh-key-v1" format,
+// ready to be saved as ~/.ssh/id_ed25519, encrypted as for RsaKeyPair.
+func (k *Ed25519KeyPair) OpenSSHPrivateKey(passphrase []byte, comment string) ([]byte, error) {
+	pri, err := k.ParsePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return marshalOpenSSH(pri, passphrase, comment)
+}
+
+// OpenSSHPublicKey returns the public key as an authorized_keys line, ready to
+// be saved as ~/.ssh/id_ed25519.pub.
+func (k *Ed25519KeyPair) OpenSSHPublicKey(comment string) ([]byte, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return marshalAuthorizedKey(pub, comment)
+}
+
+// OpenSSHFingerprint returns the SHA256 fingerprint of the public key as
+// ssh-keygen -l prints it.
+func (k *Ed25519KeyPair) OpenSSHFingerprint() (string, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return "", err
+	}
+	return openSSHFingerprint(pub)
+}
+
+// OpenSSHRandomArt returns the randomart of the public key as ssh-keygen -lv
+// prints it below the fingerprint, for comparing keys by eye.
+func (k *Ed25519KeyPair) OpenSSHRandomArt() (string, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return "", err
+	}
+	return openSSHRandomArt(pub, "ED25519", 256)
+}
+
+// marshalOpenSSH encodes a private key in the PEM armored OpenSSH format.
+func marshalOpenSSH(key crypto.PrivateKey, passphrase []byte, comment string) ([]byte, error) {
+	if err := checkComment(comment); err != nil {
+		return nil, err
+	}
+	var (
+		block *pem.Block
+		err   error
+	)
+	if len(passphrase) == 0 {
+		block, err = ssh.MarshalPrivateKey(key, comment)
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, comment, passphrase)
+	}
+	if err != nil {
+		return nil, InvalidPrivateKeyError{Err: err}
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// marshalAuthorizedKey encodes a public key as an authorized_keys line with an
+// optional trailing comment.
+func marshalAuthorizedKey(key crypto.PublicKey, comment string) ([]byte, error) {
+	if err := checkComment(comment); err != nil {
+		return nil, err
+	}
+	pub, err := newSSHPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	line := ssh.MarshalAuthorizedKey(pub)
+	if comment != "" {
+		line = append(line[:len(line)-1], ' ')
+		line = append(line, comment...)
+		line = append(line, '\n')
+	}
+	return line, nil
+}
+
+// checkComment rejects comments with control characters. A line break in the
+// comment of an authorized_keys line would start a new line, which may carry
+// a key and options of its own.
+func checkComment(comment string) error {
+	if strings.IndexFunc(comment, unicode.IsControl) >= 0 {
+		return InvalidCommentError{Comment: comment}
+	}
+	return nil
+}
+
+// openSSHFingerprint returns the SHA256 fingerprint of a public key.
+func openSSHFingerprint(key crypto.PublicKey) (string, error) {
+	pub, err := newSSHPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(pub), nil
+}
+
+// openSSHRandomArt returns the randomart of a public key of the given OpenSSH
+// type and size, titled as ssh-keygen titles it.
+func openSSHRandomArt(key crypto.PublicKey, typ string, bits int) (string, error) {
+	pub, err := newSSHPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(pub.Marshal())
+	return visual.RandomArt(sum[:], fmt.Sprintf("[%s %d]", typ, bits), "[SHA256]"), nil
+}
+
+// newSSHPublicKey converts a public key to its OpenSSH form.
+func newSSHPublicKey(key crypto.PublicKey) (ssh.PublicKey, error) {
+	pub, err := ssh.NewPublicKey(key)
+	if err != nil {
+		return nil, InvalidPublicKeyError{Err: err}
+	}
+	return pub, nil
+}
@@ -0,0 +1,41 @@
+package keypair
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+
+	"github.com/dromara/dongle/crypto/internal/ecpoint"
+)
+
+// ParsePoint parses a raw EC public key point in the uncompressed (04) or
+// compressed (02/03) form and checks that it lies on curve.
+func ParsePoint(curve elliptic.Curve, point []byte) (*ecdsa.PublicKey, error) {
+	if len(point) == 0 {
+		return nil, EmptyPublicKeyError{}
+	}
+	x, y, err := ecpoint.Unmarshal(curve, point)
+	if err != nil {
+		return nil, InvalidPublicKeyError{Err: err}
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// CompressPoint converts a raw EC public key point to the compressed (02/03)
+// form. Points that are already compressed are validated and returned as is.
+func CompressPoint(curve elliptic.Curve, point []byte) ([]byte, error) {
+	pub, err := ParsePoint(curve, point)
+	if err != nil {
+		return []byte{}, err
+	}
+	return ecpoint.Marshal(curve, pub.X, pub.Y, true), nil
+}
+
+// DecompressPoint converts a raw EC public key point to the uncompressed (04)
+// form, recovering the y coordinate of compressed points.
+func DecompressPoint(curve elliptic.Curve, point []byte) ([]byte, error) {
+	pub, err := ParsePoint(curve, point)
+	if err != nil {
+		return []byte{}, err
+	}
+	return ecpoint.Marshal(curve, pub.X, pub.Y, false), nil
+}
@@ -0,0 +1,75 @@
+package keypair
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressPoint(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), sm2.NewCurve()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			key, err := ecdsa.GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+			size := (curve.Params().BitSize + 7) / 8
+			full := append([]byte{0x04}, append(key.X.FillBytes(make([]byte, size)), key.Y.FillBytes(make([]byte, size))...)...)
+
+			compressed, err := CompressPoint(curve, full)
+			require.NoError(t, err)
+			assert.Len(t, compressed, 1+size)
+			assert.Equal(t, byte(0x02|key.Y.Bit(0)), compressed[0])
+
+			again, err := CompressPoint(curve, compressed)
+			require.NoError(t, err)
+			assert.Equal(t, compressed, again)
+
+			decompressed, err := DecompressPoint(curve, compressed)
+			require.NoError(t, err)
+			assert.Equal(t, full, decompressed)
+
+			pub, err := ParsePoint(curve, compressed)
+			require.NoError(t, err)
+			assert.True(t, key.PublicKey.Equal(pub))
+		})
+	}
+
+	t.Run("invalid point", func(t *testing.T) {
+		_, err := ParsePoint(elliptic.P256(), nil)
+		assert.IsType(t, EmptyPublicKeyError{}, err)
+		_, err = CompressPoint(elliptic.P256(), []byte{0x02, 0x01})
+		assert.IsType(t, InvalidPublicKeyError{}, err)
+		_, err = DecompressPoint(elliptic.P256(), make([]byte, 65))
+		assert.IsType(t, InvalidPublicKeyError{}, err)
+	})
+}
+
+func TestSm2KeyPair_PublicKeyPoint(t *testing.T) {
+	kp := NewSm2KeyPair()
+	require.NoError(t, kp.GenKeyPair())
+	want, err := kp.ParsePublicKey()
+	require.NoError(t, err)
+
+	compressed, err := kp.PublicKeyPoint(true)
+	require.NoError(t, err)
+	assert.Len(t, compressed, 33)
+	full, err := kp.PublicKeyPoint(false)
+	require.NoError(t, err)
+	assert.Len(t, full, 65)
+
+	for _, point := range [][]byte{compressed, full} {
+		other := NewSm2KeyPair()
+		other.PublicKey = point
+		pub, err := other.ParsePublicKey()
+		require.NoError(t, err)
+		assert.Equal(t, want.X, pub.X)
+		assert.Equal(t, want.Y, pub.Y)
+	}
+
+	_, err = NewSm2KeyPair().PublicKeyPoint(true)
+	assert.IsType(t, EmptyPublicKeyError{}, err)
+}
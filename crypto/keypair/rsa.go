@@ -2,7 +2,6 @@ package keypair
 
 import (
 	"crypto"
-	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -113,9 +112,38 @@ type RsaKeyPair struct {
 	// Hash specifies the hash function used for RSA cryptographic operations.
 	// Usage depends on the Padding scheme:
 	// - PKCS1v15: Used for hashing message data before signing
-	// - OAEP: Used for mask generation in encryption/decryption
+	// - OAEP: Used for the label hash and, unless MGFHash is set, mask generation
 	// - PSS: Used for mask generation in signing/verification
 	Hash crypto.Hash
+
+	// MGFHash specifies the hash function of the OAEP mask generation function
+	// when it differs from Hash, zero meaning Hash. Java's
+	// OAEPWithSHA-256AndMGF1Padding hashes the label with SHA-256 but masks
+	// with MGF1 SHA-1 by default, while .NET's OaepSHA256 uses SHA-256 for both.
+	MGFHash crypto.Hash
+
+	// Label specifies the OAEP label, bound to the ciphertext without being
+	// encrypted. Decryption fails unless the same label is set.
+	Label []byte
+
+	// Chunked makes the standard RSA encrypters split plaintexts longer than
+	// one block into blocks of the largest size the padding allows and
+	// concatenate their ciphertexts, and the standard decrypters split
+	// ciphertexts back into blocks of the key size. Stream encrypters and
+	// decrypters always work on such blocks. Blocks are not bound to each
+	// other, see SetChunked.
+	Chunked bool
+
+	// Usage restricts the key pair to signing or encryption, see SetUsage.
+	Usage KeyUsage
+
+	// Validity is the window in which the key pair may sign and encrypt.
+	Validity
+
+	// signer and decrypter are the external keys set by SetSigner and
+	// SetDecrypter, such as keys held in an HSM.
+	signer    crypto.Signer
+	decrypter crypto.Decrypter
 }
 
 // NewRsaKeyPair returns a new RsaKeyPair instance with default settings.
@@ -137,7 +165,7 @@ func NewRsaKeyPair() *RsaKeyPair {
 // according to the current Format setting (PKCS1 or PKCS8).
 func (k *RsaKeyPair) GenKeyPair(size int) error {
 	// Generate a new RSA private key
-	key, err := rsa.GenerateKey(rand.Reader, size)
+	key, err := rsa.GenerateKey(utils.Rand(), size)
 	if err != nil {
 		return err
 	}
@@ -227,6 +255,45 @@ func (k *RsaKeyPair) SetHash(hash crypto.Hash) {
 	k.Hash = hash
 }
 
+// SetMGFHash sets the hash function of the OAEP mask generation function
+// (MGF1) when it differs from the hash set by SetHash, such as crypto.SHA1
+// to interoperate with Java's OAEPWithSHA-256AndMGF1Padding.
+func (k *RsaKeyPair) SetMGFHash(hash crypto.Hash) {
+	k.MGFHash = hash
+}
+
+// SetLabel sets the OAEP label, which must match on encryption and decryption.
+func (k *RsaKeyPair) SetLabel(label []byte) {
+	k.Label = label
+}
+
+// SetChunked enables or disables chunked encryption and decryption of data
+// longer than one RSA block, as required by many payment gateways.
+//
+// Chunking does not protect the integrity of the message: every block is
+// encrypted on its own, so blocks can be reordered, dropped or duplicated and
+// the result still decrypts without error. Only use it where a peer requires
+// it, and prefer hybrid encryption otherwise, which encrypts the data with an
+// AEAD such as AES-GCM under a random key transported with RSA-OAEP.
+func (k *RsaKeyPair) SetChunked(chunked bool) {
+	k.Chunked = chunked
+}
+
+// CheckUse returns a KeyUsageError unless the usage of the key pair allows op,
+// and a KeyExpiredError when op is SignOp or EncryptOp outside the validity
+// window. Signers and encrypters call it for every operation, so a key that
+// expires while one is in use stops signing and encrypting.
+func (k *RsaKeyPair) CheckUse(op Operation) error {
+	return checkUse(k.Usage, k.Validity, op)
+}
+
+// SetUsage restricts the key pair to Sign or Encrypt operations, or allows
+// Both. RSA signers and verifiers then refuse an Encrypt key, and encrypters
+// and decrypters refuse a Sign key.
+func (k *RsaKeyPair) SetUsage(usage KeyUsage) {
+	k.Usage = usage
+}
+
 // ParsePublicKey parses the public key from PEM format.
 // It supports both PKCS1 and PKCS8 formats automatically.
 //
@@ -375,7 +442,7 @@ func (k *RsaKeyPair) CompressPublicKey(publicKey []byte) []byte {
 	// Remove any remaining whitespace that might be present
 	keyStr = strings.TrimSpace(keyStr)
 
-	return utils.String2Bytes(keyStr)
+	return utils.String2BytesCopy(keyStr)
 }
 
 // CompressPrivateKey removes the PEM headers and footers from the private key.
@@ -402,5 +469,5 @@ func (k *RsaKeyPair) CompressPrivateKey(privateKey []byte) []byte {
 	// Remove any remaining whitespace that might be present
 	keyStr = strings.TrimSpace(keyStr)
 
-	return utils.String2Bytes(keyStr)
+	return utils.String2BytesCopy(keyStr)
 }
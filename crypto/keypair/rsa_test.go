@@ -26,11 +26,17 @@ func TestRSA_Setters(t *testing.T) {
 	kp.SetFormat(PKCS1)
 	kp.SetPadding(OAEP)
 	kp.SetHash(crypto.SHA512)
+	kp.SetMGFHash(crypto.SHA1)
+	kp.SetLabel([]byte("label"))
+	kp.SetChunked(true)
 	kp.SetType(PrivateKey)
 
 	assert.Equal(t, PKCS1, kp.Format)
 	assert.Equal(t, OAEP, kp.Padding)
 	assert.Equal(t, crypto.SHA512, kp.Hash)
+	assert.Equal(t, crypto.SHA1, kp.MGFHash)
+	assert.Equal(t, []byte("label"), kp.Label)
+	assert.True(t, kp.Chunked)
 	assert.Equal(t, PrivateKey, kp.Type)
 }
 
@@ -0,0 +1,119 @@
+package keypair
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptoAsn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// sm2CurveName is the name of the SM2 curve parameters.
+const sm2CurveName = "SM2-P-256"
+
+// ParseSignature parses an ECDSA or SM2 signature in the given mode, ASN1 for
+// a DER encoded SEQUENCE of two INTEGERs and Bytes for the fixed size r||s
+// form, and checks that both components are in the range [1, n-1].
+func ParseSignature(curve elliptic.Curve, sign []byte, mode Sm2SingMode) (r, s *big.Int, err error) {
+	if len(sign) == 0 {
+		return nil, nil, EmptySignatureError{}
+	}
+	size := (curve.Params().N.BitLen() + 7) / 8
+	switch mode {
+	case ASN1:
+		r, s = new(big.Int), new(big.Int)
+		input, inner := cryptobyte.String(sign), cryptobyte.String(nil)
+		if !input.ReadASN1(&inner, cryptoAsn1.SEQUENCE) || !input.Empty() ||
+			!inner.ReadASN1Integer(r) || !inner.ReadASN1Integer(s) || !inner.Empty() {
+			return nil, nil, InvalidSignatureError{Err: errors.New("malformed DER signature")}
+		}
+	case Bytes:
+		if len(sign) != 2*size {
+			return nil, nil, InvalidSignatureError{Err: errors.New("invalid signature length")}
+		}
+		r, s = new(big.Int).SetBytes(sign[:size]), new(big.Int).SetBytes(sign[size:])
+	default:
+		return nil, nil, InvalidSignatureError{Err: errors.New("unsupported signature mode")}
+	}
+	n := curve.Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return nil, nil, InvalidSignatureError{Err: errors.New("signature component out of range")}
+	}
+	return r, s, nil
+}
+
+// MarshalSignature encodes r and s in the given mode, see ParseSignature.
+func MarshalSignature(curve elliptic.Curve, r, s *big.Int, mode Sm2SingMode) ([]byte, error) {
+	switch mode {
+	case ASN1:
+		var b cryptobyte.Builder
+		b.AddASN1(cryptoAsn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1BigInt(r)
+			b.AddASN1BigInt(s)
+		})
+		return b.Bytes()
+	case Bytes:
+		size := (curve.Params().N.BitLen() + 7) / 8
+		if r.BitLen() > 8*size || s.BitLen() > 8*size {
+			return []byte{}, InvalidSignatureError{Err: errors.New("signature component out of range")}
+		}
+		out := make([]byte, 2*size)
+		r.FillBytes(out[:size])
+		s.FillBytes(out[size:])
+		return out, nil
+	default:
+		return []byte{}, InvalidSignatureError{Err: errors.New("unsupported signature mode")}
+	}
+}
+
+// SignatureToCompact converts a DER encoded signature to the r||s form.
+func SignatureToCompact(curve elliptic.Curve, der []byte) ([]byte, error) {
+	r, s, err := ParseSignature(curve, der, ASN1)
+	if err != nil {
+		return []byte{}, err
+	}
+	return MarshalSignature(curve, r, s, Bytes)
+}
+
+// SignatureToDER converts an r||s signature to the DER encoded form.
+func SignatureToDER(curve elliptic.Curve, compact []byte) ([]byte, error) {
+	r, s, err := ParseSignature(curve, compact, Bytes)
+	if err != nil {
+		return []byte{}, err
+	}
+	return MarshalSignature(curve, r, s, ASN1)
+}
+
+// IsLowS reports whether the s component of a valid ECDSA signature is at most
+// n/2, as required by verifiers that reject malleable signatures.
+func IsLowS(curve elliptic.Curve, sign []byte, mode Sm2SingMode) (bool, error) {
+	_, s, err := ParseSignature(curve, sign, mode)
+	if err != nil {
+		return false, err
+	}
+	return s.Cmp(halfOrder(curve)) <= 0, nil
+}
+
+// NormalizeLowS returns an ECDSA signature with s replaced by n-s when s is
+// larger than n/2. Both signatures verify, so this only removes malleability.
+// SM2 signatures bind s to r and cannot be normalized, an UnsupportedCurveError
+// is returned for them.
+func NormalizeLowS(curve elliptic.Curve, sign []byte, mode Sm2SingMode) ([]byte, error) {
+	if curve.Params().Name == sm2CurveName {
+		return []byte{}, UnsupportedCurveError{Curve: sm2CurveName}
+	}
+	r, s, err := ParseSignature(curve, sign, mode)
+	if err != nil {
+		return []byte{}, err
+	}
+	if s.Cmp(halfOrder(curve)) > 0 {
+		s.Sub(curve.Params().N, s)
+	}
+	return MarshalSignature(curve, r, s, mode)
+}
+
+// halfOrder returns n/2 for curve.
+func halfOrder(curve elliptic.Curve) *big.Int {
+	return new(big.Int).Rsh(curve.Params().N, 1)
+}
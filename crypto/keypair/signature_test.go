@@ -0,0 +1,101 @@
+package keypair
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureConversion(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello world"))
+	der, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	compact, err := SignatureToCompact(elliptic.P256(), der)
+	require.NoError(t, err)
+	assert.Len(t, compact, 64)
+	back, err := SignatureToDER(elliptic.P256(), compact)
+	require.NoError(t, err)
+	assert.Equal(t, der, back)
+
+	t.Run("small components are padded", func(t *testing.T) {
+		compact, err := MarshalSignature(elliptic.P256(), big.NewInt(1), big.NewInt(2), Bytes)
+		require.NoError(t, err)
+		assert.Len(t, compact, 64)
+		assert.Equal(t, byte(1), compact[31])
+		assert.Equal(t, byte(2), compact[63])
+		der, err := SignatureToDER(elliptic.P256(), compact)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}, der)
+	})
+
+	t.Run("invalid signatures", func(t *testing.T) {
+		_, err := SignatureToCompact(elliptic.P256(), nil)
+		assert.IsType(t, EmptySignatureError{}, err)
+		for _, bad := range [][]byte{
+			append(append([]byte{}, der...), 0x00),                 // trailing data
+			{0x30, 0x06, 0x02, 0x01, 0x00, 0x02, 0x01, 0x01},       // r = 0
+			{0x30, 0x06, 0x02, 0x01, 0xff, 0x02, 0x01, 0x01},       // negative r
+			{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01}, // non-minimal r
+		} {
+			_, err := SignatureToCompact(elliptic.P256(), bad)
+			assert.IsType(t, InvalidSignatureError{}, err, "%x", bad)
+		}
+		_, err = SignatureToDER(elliptic.P256(), compact[:63])
+		assert.IsType(t, InvalidSignatureError{}, err)
+		n := elliptic.P256().Params().N
+		_, err = SignatureToDER(elliptic.P256(), append(n.FillBytes(make([]byte, 32)), compact[32:]...))
+		assert.IsType(t, InvalidSignatureError{}, err)
+		_, _, err = ParseSignature(elliptic.P256(), compact, Sm2SingMode(9))
+		assert.IsType(t, InvalidSignatureError{}, err)
+	})
+}
+
+func TestNormalizeLowS(t *testing.T) {
+	curve := elliptic.P256()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello world"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	high := new(big.Int).Set(s)
+	if high.Cmp(halfOrder(curve)) <= 0 {
+		high.Sub(curve.Params().N, high)
+	}
+
+	for _, mode := range []Sm2SingMode{ASN1, Bytes} {
+		sign, err := MarshalSignature(curve, r, high, mode)
+		require.NoError(t, err)
+		low, err := IsLowS(curve, sign, mode)
+		require.NoError(t, err)
+		assert.False(t, low)
+
+		normalized, err := NormalizeLowS(curve, sign, mode)
+		require.NoError(t, err)
+		low, err = IsLowS(curve, normalized, mode)
+		require.NoError(t, err)
+		assert.True(t, low)
+
+		nr, ns, err := ParseSignature(curve, normalized, mode)
+		require.NoError(t, err)
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], nr, ns))
+
+		again, err := NormalizeLowS(curve, normalized, mode)
+		require.NoError(t, err)
+		assert.Equal(t, normalized, again)
+	}
+
+	_, err = NormalizeLowS(sm2.NewCurve(), []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}, ASN1)
+	assert.Equal(t, UnsupportedCurveError{Curve: "SM2-P-256"}, err)
+	_, err = IsLowS(curve, nil, ASN1)
+	assert.IsType(t, EmptySignatureError{}, err)
+}
@@ -0,0 +1,210 @@
+package keypair
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	"github.com/dromara/dongle/crypto/internal/ecpoint"
+	"github.com/dromara/dongle/crypto/internal/sm2"
+)
+
+// Signer returns the private key as a crypto.Signer, for use with crypto/tls,
+// crypto/x509 and other libraries expecting standard library interfaces. It is
+// the external signer set by SetSigner if any, and the parsed *rsa.PrivateKey
+// otherwise. It fails with a KeyUsageError for an Encrypt key, and with a
+// KeyExpiredError outside the validity window.
+func (k *RsaKeyPair) Signer() (crypto.Signer, error) {
+	if err := k.CheckUse(SignOp); err != nil {
+		return nil, err
+	}
+	if k.signer != nil {
+		return k.signer, nil
+	}
+	return k.ParsePrivateKey()
+}
+
+// Decrypter returns the private key as a crypto.Decrypter. It is the external
+// decrypter set by SetDecrypter if any, and the parsed *rsa.PrivateKey
+// otherwise. It fails with a KeyUsageError for a Sign key.
+func (k *RsaKeyPair) Decrypter() (crypto.Decrypter, error) {
+	if err := k.CheckUse(DecryptOp); err != nil {
+		return nil, err
+	}
+	if k.decrypter != nil {
+		return k.decrypter, nil
+	}
+	return k.ParsePrivateKey()
+}
+
+// SetSigner sets an external RSA private key, such as a key held in an HSM or a
+// cloud KMS, and fills PublicKey from it. The RSA signer then signs with it when
+// PrivateKey is empty.
+func (k *RsaKeyPair) SetSigner(signer crypto.Signer) error {
+	if err := k.setExternalPublicKey(signer.Public()); err != nil {
+		return err
+	}
+	k.signer = signer
+	return nil
+}
+
+// SetDecrypter sets an external RSA private key and fills PublicKey from it.
+// The RSA decrypter then decrypts with it when PrivateKey is empty.
+func (k *RsaKeyPair) SetDecrypter(decrypter crypto.Decrypter) error {
+	if err := k.setExternalPublicKey(decrypter.Public()); err != nil {
+		return err
+	}
+	k.decrypter = decrypter
+	return nil
+}
+
+// setExternalPublicKey fills PublicKey with the public key of an external key
+// in the current format.
+func (k *RsaKeyPair) setExternalPublicKey(public crypto.PublicKey) error {
+	pub, ok := public.(*rsa.PublicKey)
+	if !ok {
+		return UnsupportedSignerError{Key: fmt.Sprintf("%T", public)}
+	}
+	if k.Format == PKCS1 {
+		k.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(pub)})
+		return nil
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return InvalidPublicKeyError{Err: err}
+	}
+	k.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return nil
+}
+
+// Signer returns the private key as a crypto.Signer. It is the external signer
+// set by SetSigner if any, and the parsed *ecdsa.PrivateKey otherwise. It
+// fails with a KeyExpiredError outside the validity window.
+func (k *EcdsaKeyPair) Signer() (crypto.Signer, error) {
+	if err := k.CheckValidity(); err != nil {
+		return nil, err
+	}
+	if k.signer != nil {
+		return k.signer, nil
+	}
+	return k.ParsePrivateKey()
+}
+
+// SetSigner sets an external ECDSA private key, such as a key held in an HSM or
+// a cloud KMS, and fills PublicKey and Curve from it. The ECDSA signer then
+// signs with it when PrivateKey is empty.
+func (k *EcdsaKeyPair) SetSigner(signer crypto.Signer) error {
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return UnsupportedSignerError{Key: fmt.Sprintf("%T", signer.Public())}
+	}
+	oid, err := curveOID(pub.Curve)
+	if err != nil {
+		return err
+	}
+	point := ecpoint.Marshal(pub.Curve, pub.X, pub.Y, false)
+	k.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: marshalEcdsaSPKI(oid, point)})
+	k.Curve = pub.Curve
+	k.signer = signer
+	return nil
+}
+
+// Signer returns the private key as a crypto.Signer. It is the external signer
+// set by SetSigner if any, and the parsed ed25519.PrivateKey otherwise. Pass
+// crypto.Hash(0) as options to sign a message with plain Ed25519, or an
+// *ed25519.Options for Ed25519ph and Ed25519ctx. It fails with a
+// KeyExpiredError outside the validity window.
+func (k *Ed25519KeyPair) Signer() (crypto.Signer, error) {
+	if err := k.CheckValidity(); err != nil {
+		return nil, err
+	}
+	if k.signer != nil {
+		return k.signer, nil
+	}
+	return k.ParsePrivateKey()
+}
+
+// SetSigner sets an external Ed25519 private key, such as a key held in an HSM,
+// and fills PublicKey from it. The Ed25519 signer then signs with it when
+// PrivateKey is empty.
+func (k *Ed25519KeyPair) SetSigner(signer crypto.Signer) error {
+	pub, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return UnsupportedSignerError{Key: fmt.Sprintf("%T", signer.Public())}
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return InvalidPublicKeyError{Err: err}
+	}
+	k.PublicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	k.signer = signer
+	return nil
+}
+
+// Signer returns the private key as a crypto.Signer that signs with the UID
+// and SingMode of the key pair. As SM2 hashes the signer identity together with
+// the message, Sign expects the message itself rather than a digest, and
+// crypto.Hash(0) as options, like Ed25519. It fails with a KeyUsageError for an
+// Encrypt key, with a KeyExpiredError outside the validity window and with an
+// InvalidUIDError for a UID that is too long.
+func (k *Sm2KeyPair) Signer() (crypto.Signer, error) {
+	if err := k.CheckUse(SignOp); err != nil {
+		return nil, err
+	}
+	if err := k.CheckUID(); err != nil {
+		return nil, err
+	}
+	return k.sm2Key()
+}
+
+// Decrypter returns the private key as a crypto.Decrypter that decrypts
+// ciphertexts in the Mode of the key pair. It fails with a KeyUsageError for a
+// Sign key.
+func (k *Sm2KeyPair) Decrypter() (crypto.Decrypter, error) {
+	if err := k.CheckUse(DecryptOp); err != nil {
+		return nil, err
+	}
+	return k.sm2Key()
+}
+
+// sm2Key returns the parsed private key with the settings of the key pair.
+func (k *Sm2KeyPair) sm2Key() (*sm2Key, error) {
+	pri, err := k.ParsePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &sm2Key{pri: pri, uid: k.UID, singMode: k.SingMode, mode: k.Mode, window: k.Window}, nil
+}
+
+// sm2Key adapts an SM2 private key to crypto.Signer and crypto.Decrypter.
+type sm2Key struct {
+	pri      *ecdsa.PrivateKey
+	uid      []byte
+	singMode Sm2SingMode
+	mode     Sm2CipherMode
+	window   int
+}
+
+// Public returns the *ecdsa.PublicKey on the SM2 curve.
+func (k *sm2Key) Public() crypto.PublicKey {
+	return &k.pri.PublicKey
+}
+
+// Sign signs message, opts must be crypto.Hash(0) as SM2 computes its own
+// digest.
+func (k *sm2Key) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != 0 {
+		return nil, UnsupportedHashError{Hash: opts.HashFunc().String()}
+	}
+	return sm2.SignWithPrivateKey(k.pri, message, k.uid, uint8(k.singMode))
+}
+
+// Decrypt decrypts ciphertext, opts are ignored.
+func (k *sm2Key) Decrypt(_ io.Reader, ciphertext []byte, _ crypto.DecrypterOpts) ([]byte, error) {
+	return sm2.DecryptWithPrivateKey(k.pri, ciphertext, k.window, string(k.mode))
+}
@@ -0,0 +1,240 @@
+package keypair
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/internal/sm2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRsaKeyPair_SignerAndDecrypter(t *testing.T) {
+	digest := sha256.Sum256([]byte("hello world"))
+
+	t.Run("parsed key", func(t *testing.T) {
+		kp, _, _ := genPair(t, PKCS8)
+		signer, err := kp.Signer()
+		assert.NoError(t, err)
+		sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		assert.NoError(t, err)
+		pub, _ := kp.ParsePublicKey()
+		assert.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig))
+
+		decrypter, err := kp.Decrypter()
+		assert.NoError(t, err)
+		ciphertext, _ := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, []byte("secret"), nil)
+		plaintext, err := decrypter.Decrypt(rand.Reader, ciphertext, &rsa.OAEPOptions{Hash: crypto.SHA256})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("secret"), plaintext)
+	})
+
+	t.Run("empty private key", func(t *testing.T) {
+		kp := NewRsaKeyPair()
+		_, err := kp.Signer()
+		assert.Equal(t, EmptyPrivateKeyError{}, err)
+		_, err = kp.Decrypter()
+		assert.Equal(t, EmptyPrivateKeyError{}, err)
+	})
+
+	t.Run("external key", func(t *testing.T) {
+		key, _ := rsa.GenerateKey(rand.Reader, 1024)
+		for _, format := range []RsaKeyFormat{PKCS1, PKCS8} {
+			kp := NewRsaKeyPair()
+			kp.SetFormat(format)
+			assert.NoError(t, kp.SetSigner(key))
+			assert.NoError(t, kp.SetDecrypter(key))
+			pub, err := kp.ParsePublicKey()
+			assert.NoError(t, err)
+			assert.True(t, pub.Equal(&key.PublicKey))
+
+			signer, _ := kp.Signer()
+			assert.Same(t, key, signer)
+			decrypter, _ := kp.Decrypter()
+			assert.Same(t, key, decrypter)
+		}
+	})
+
+	t.Run("wrong key type", func(t *testing.T) {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		kp := NewRsaKeyPair()
+		assert.Equal(t, UnsupportedSignerError{Key: "*ecdsa.PublicKey"}, kp.SetSigner(key))
+		_, priv, _ := ed25519.GenerateKey(rand.Reader)
+		assert.Equal(t, UnsupportedSignerError{Key: "ed25519.PrivateKey"}, kp.SetDecrypter(mockDecrypter{priv}))
+	})
+}
+
+func TestEcdsaKeyPair_Signer(t *testing.T) {
+	digest := sha256.Sum256([]byte("hello world"))
+
+	t.Run("parsed key", func(t *testing.T) {
+		kp := NewEcdsaKeyPair()
+		assert.NoError(t, kp.GenKeyPair())
+		signer, err := kp.Signer()
+		assert.NoError(t, err)
+		sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		assert.NoError(t, err)
+		pub, _ := kp.ParsePublicKey()
+		assert.True(t, ecdsa.VerifyASN1(pub, digest[:], sig))
+	})
+
+	t.Run("external key", func(t *testing.T) {
+		key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		kp := NewEcdsaKeyPair()
+		assert.NoError(t, kp.SetSigner(key))
+		assert.Equal(t, elliptic.P384(), kp.Curve)
+		pub, err := kp.ParsePublicKey()
+		assert.NoError(t, err)
+		assert.True(t, pub.Equal(&key.PublicKey))
+		signer, _ := kp.Signer()
+		assert.Same(t, key, signer)
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		key, _ := ecdsa.GenerateKey(sm2.NewCurve(), rand.Reader)
+		kp := NewEcdsaKeyPair()
+		assert.Error(t, kp.SetSigner(key))
+	})
+
+	t.Run("wrong key type", func(t *testing.T) {
+		key, _ := rsa.GenerateKey(rand.Reader, 1024)
+		kp := NewEcdsaKeyPair()
+		assert.Equal(t, UnsupportedSignerError{Key: "*rsa.PublicKey"}, kp.SetSigner(key))
+	})
+}
+
+func TestEd25519KeyPair_Signer(t *testing.T) {
+	t.Run("parsed key", func(t *testing.T) {
+		kp := NewEd25519KeyPair()
+		assert.NoError(t, kp.GenKeyPair())
+		signer, err := kp.Signer()
+		assert.NoError(t, err)
+		sig, err := signer.Sign(nil, []byte("hello world"), crypto.Hash(0))
+		assert.NoError(t, err)
+		pub, _ := kp.ParsePublicKey()
+		assert.True(t, ed25519.Verify(pub, []byte("hello world"), sig))
+	})
+
+	t.Run("external key", func(t *testing.T) {
+		_, key, _ := ed25519.GenerateKey(rand.Reader)
+		kp := NewEd25519KeyPair()
+		assert.NoError(t, kp.SetSigner(key))
+		pub, err := kp.ParsePublicKey()
+		assert.NoError(t, err)
+		assert.Equal(t, key.Public(), pub)
+		signer, _ := kp.Signer()
+		assert.Equal(t, key, signer)
+	})
+
+	t.Run("wrong key type", func(t *testing.T) {
+		key, _ := rsa.GenerateKey(rand.Reader, 1024)
+		kp := NewEd25519KeyPair()
+		assert.Equal(t, UnsupportedSignerError{Key: "*rsa.PublicKey"}, kp.SetSigner(key))
+	})
+}
+
+func TestSm2KeyPair_SignerAndDecrypter(t *testing.T) {
+	kp := NewSm2KeyPair()
+	assert.NoError(t, kp.GenKeyPair())
+	kp.SetUID([]byte("alice@example.com"))
+	pub, _ := kp.ParsePublicKey()
+
+	t.Run("sign", func(t *testing.T) {
+		signer, err := kp.Signer()
+		assert.NoError(t, err)
+		assert.Equal(t, pub, signer.Public())
+		sig, err := signer.Sign(rand.Reader, []byte("hello world"), crypto.Hash(0))
+		assert.NoError(t, err)
+		assert.True(t, sm2.VerifyWithPublicKey(pub, []byte("hello world"), kp.UID, sig, uint8(kp.SingMode)))
+		assert.False(t, sm2.VerifyWithPublicKey(pub, []byte("hello world"), nil, sig, uint8(kp.SingMode)))
+	})
+
+	t.Run("sign digest", func(t *testing.T) {
+		signer, _ := kp.Signer()
+		_, err := signer.Sign(rand.Reader, []byte("digest"), crypto.SHA256)
+		assert.Equal(t, UnsupportedHashError{Hash: "SHA-256"}, err)
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		ciphertext, err := sm2.EncryptWithPublicKey(pub, []byte("secret"), kp.Window, string(kp.Mode))
+		assert.NoError(t, err)
+		decrypter, err := kp.Decrypter()
+		assert.NoError(t, err)
+		plaintext, err := decrypter.Decrypt(rand.Reader, ciphertext, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("secret"), plaintext)
+	})
+
+	t.Run("empty private key", func(t *testing.T) {
+		_, err := NewSm2KeyPair().Signer()
+		assert.Equal(t, EmptyPrivateKeyError{}, err)
+		_, err = NewSm2KeyPair().Decrypter()
+		assert.Equal(t, EmptyPrivateKeyError{}, err)
+	})
+}
+
+// mockDecrypter is a crypto.Decrypter exposing an arbitrary public key.
+type mockDecrypter struct {
+	key crypto.PrivateKey
+}
+
+func (m mockDecrypter) Public() crypto.PublicKey {
+	return m.key
+}
+
+func (m mockDecrypter) Decrypt(_ io.Reader, _ []byte, _ crypto.DecrypterOpts) ([]byte, error) {
+	return nil, nil
+}
+
+func TestKeyUsage(t *testing.T) {
+	t.Run("check", func(t *testing.T) {
+		for _, usage := range []KeyUsage{"", Both} {
+			assert.NoError(t, usage.Check(Sign))
+			assert.NoError(t, usage.Check(Encrypt))
+		}
+		assert.NoError(t, Sign.Check(Sign))
+		assert.Equal(t, KeyUsageError{Usage: Sign, Operation: Encrypt}, Sign.Check(Encrypt))
+		assert.NoError(t, Encrypt.Check(Encrypt))
+		assert.Equal(t, KeyUsageError{Usage: Encrypt, Operation: Sign}, Encrypt.Check(Sign))
+		assert.Equal(t, "key usage 'encrypt' does not allow sign operations", Encrypt.Check(Sign).Error())
+	})
+
+	t.Run("rsa", func(t *testing.T) {
+		kp := NewRsaKeyPair()
+		assert.NoError(t, kp.GenKeyPair(1024))
+		kp.SetUsage(Encrypt)
+		assert.Equal(t, Encrypt, kp.Usage)
+		_, err := kp.Signer()
+		assert.IsType(t, KeyUsageError{}, err)
+		_, err = kp.Decrypter()
+		assert.NoError(t, err)
+
+		kp.SetUsage(Sign)
+		_, err = kp.Signer()
+		assert.NoError(t, err)
+		_, err = kp.Decrypter()
+		assert.IsType(t, KeyUsageError{}, err)
+	})
+
+	t.Run("sm2", func(t *testing.T) {
+		kp := NewSm2KeyPair()
+		assert.NoError(t, kp.GenKeyPair())
+		kp.SetUsage(Encrypt)
+		_, err := kp.Signer()
+		assert.IsType(t, KeyUsageError{}, err)
+		_, err = kp.Decrypter()
+		assert.NoError(t, err)
+
+		kp.SetUsage(Sign)
+		_, err = kp.Signer()
+		assert.NoError(t, err)
+		_, err = kp.Decrypter()
+		assert.IsType(t, KeyUsageError{}, err)
+	})
+}
@@ -2,11 +2,11 @@ package keypair
 
 import (
 	"crypto/ecdsa"
-	"crypto/rand"
 	"encoding/pem"
 	"strings"
 
 	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/crypto/internal/ecpoint"
 	"github.com/dromara/dongle/crypto/internal/sm2"
 	"github.com/dromara/dongle/internal/utils"
 )
@@ -33,15 +33,21 @@ const (
 	ASN1C1C3C2 Sm2CipherMode = "asn1_c1c3c2"
 )
 
+// Sm2SingMode is the encoding of SM2 signatures.
 type Sm2SingMode uint8
 
 const (
-	// Digital signature in ASN1 format
+	// Digital signature in ASN1 format, a DER encoded SEQUENCE of r and s
 	ASN1 Sm2SingMode = iota
-	// Digital signature in bytes format
+	// Digital signature in bytes format, the 64-byte raw r||s with each
+	// component left padded to 32 bytes
 	Bytes
 )
 
+// MaxSm2UIDSize is the largest UID in bytes whose bit length fits the 16-bit
+// ENTL field of the ZA calculation.
+const MaxSm2UIDSize = 8191
+
 var (
 	bitStringPublicKeyParser  = sm2.ParseBitStringPublicKey
 	bitStringPrivateKeyParser = sm2.ParseBitStringPrivateKey
@@ -75,6 +81,12 @@ type Sm2KeyPair struct {
 	// UID is the user identifier for SM2 signature operations.
 	// If empty, the default UID "1234567812345678" will be used (per GM/T 0009-2012).
 	UID []byte
+
+	// Usage restricts the key pair to signing or encryption, see SetUsage.
+	Usage KeyUsage
+
+	// Validity is the window in which the key pair may sign and encrypt.
+	Validity
 }
 
 // NewSm2KeyPair returns a new Sm2KeyPair with defaults
@@ -92,7 +104,7 @@ func (k *Sm2KeyPair) GenKeyPair() error {
 	c := sm2.NewCurve()
 
 	// Generate unbiased scalar d in range [1, n-1]
-	d, err := sm2.RandScalar(c, rand.Reader)
+	d, err := sm2.RandScalar(c, utils.Rand())
 	if err != nil {
 		return err
 	}
@@ -122,7 +134,9 @@ func (k *Sm2KeyPair) SetMode(mode Sm2CipherMode) {
 	k.Mode = mode
 }
 
-// SetSingMode sets the mode for SM2 Sign and Verify
+// SetSingMode sets the mode for SM2 Sign and Verify, ASN1 for DER or Bytes
+// for the raw r||s form. Both sides must use the same mode; SignatureToCompact
+// and SignatureToDER convert between them.
 func (k *Sm2KeyPair) SetSingMode(mode Sm2SingMode) {
 	k.SingMode = mode
 }
@@ -141,10 +155,35 @@ func (k *Sm2KeyPair) SetWindow(window int) {
 
 // SetUID sets the user identifier for SM2 signature operations.
 // If uid is nil or empty, the default UID "1234567812345678" will be used.
+// The UID is hashed into ZA with the public key, so signer and verifier must
+// use the same one, and it may be at most MaxSm2UIDSize bytes long.
 func (k *Sm2KeyPair) SetUID(uid []byte) {
 	k.UID = uid
 }
 
+// CheckUID returns an InvalidUIDError when the UID is longer than
+// MaxSm2UIDSize bytes.
+func (k *Sm2KeyPair) CheckUID() error {
+	if len(k.UID) > MaxSm2UIDSize {
+		return InvalidUIDError{Size: len(k.UID)}
+	}
+	return nil
+}
+
+// CheckUse returns a KeyUsageError unless the usage of the key pair allows op,
+// and a KeyExpiredError when op is SignOp or EncryptOp outside the validity
+// window, as for RsaKeyPair.
+func (k *Sm2KeyPair) CheckUse(op Operation) error {
+	return checkUse(k.Usage, k.Validity, op)
+}
+
+// SetUsage restricts the key pair to Sign or Encrypt operations, or allows
+// Both. SM2 signers and verifiers then refuse an Encrypt key, and encrypters
+// and decrypters refuse a Sign key.
+func (k *Sm2KeyPair) SetUsage(usage KeyUsage) {
+	k.Usage = usage
+}
+
 // SetPublicKey sets the public key after formatting to PEM.
 // Accepts base64-encoded DER of SubjectPublicKeyInfo.
 func (k *Sm2KeyPair) SetPublicKey(publicKey []byte) error {
@@ -166,12 +205,13 @@ func (k *Sm2KeyPair) SetPrivateKey(privateKey []byte) error {
 }
 
 // ParsePublicKey parses the PEM-encoded public key and returns *sm2.PublicKey.
+// A raw point in the uncompressed (04) or compressed (02/03) form is accepted too.
 func (k *Sm2KeyPair) ParsePublicKey() (*ecdsa.PublicKey, error) {
 	publicKey := k.PublicKey
 	if len(publicKey) == 0 {
 		return nil, EmptyPublicKeyError{}
 	}
-	if len(publicKey) == 65 {
+	if len(publicKey) == 65 || len(publicKey) == 33 {
 		pub, err := bitStringPublicKeyParser(publicKey)
 		if err != nil {
 			return nil, InvalidPublicKeyError{Err: err}
@@ -213,6 +253,17 @@ func (k *Sm2KeyPair) ParsePrivateKey() (*ecdsa.PrivateKey, error) {
 	return pri, nil
 }
 
+// PublicKeyPoint returns the public key as a raw point, in the compressed
+// (02/03) form when compressed is true and in the uncompressed (04) form
+// otherwise.
+func (k *Sm2KeyPair) PublicKeyPoint(compressed bool) ([]byte, error) {
+	pub, err := k.ParsePublicKey()
+	if err != nil {
+		return []byte{}, err
+	}
+	return ecpoint.Marshal(pub.Curve, pub.X, pub.Y, compressed), nil
+}
+
 // FormatPublicKey formats base64-encoded der public key into the specified PEM format.
 func (k *Sm2KeyPair) FormatPublicKey(publicKey []byte) ([]byte, error) {
 	if len(publicKey) == 0 {
@@ -253,7 +304,7 @@ func (k *Sm2KeyPair) CompressPublicKey(publicKey []byte) []byte {
 	keyStr = strings.ReplaceAll(keyStr, " ", "")
 	keyStr = strings.ReplaceAll(keyStr, "\t", "")
 	keyStr = strings.TrimSpace(keyStr)
-	return utils.String2Bytes(keyStr)
+	return utils.String2BytesCopy(keyStr)
 }
 
 // CompressPrivateKey strips headers/footers and whitespace from the PEM private key.
@@ -268,5 +319,5 @@ func (k *Sm2KeyPair) CompressPrivateKey(privateKey []byte) []byte {
 	keyStr = strings.ReplaceAll(keyStr, " ", "")
 	keyStr = strings.ReplaceAll(keyStr, "\t", "")
 	keyStr = strings.TrimSpace(keyStr)
-	return utils.String2Bytes(keyStr)
+	return utils.String2BytesCopy(keyStr)
 }
@@ -50,7 +50,7 @@ func TestSetOrderAndModeAndWindow_Clamp(t *testing.T) {
 	}
 
 	kp.SetSingMode(Bytes)
-	if kp.SingMode != ASN1 {
+	if kp.SingMode != Bytes {
 		t.Fatalf("sing mode not set")
 	}
 
@@ -348,3 +348,23 @@ func TestSetUID(t *testing.T) {
 		t.Fatalf("SetUID: expected %v, got %v", uid2, kp.UID)
 	}
 }
+
+func TestCheckUID(t *testing.T) {
+	kp := NewSm2KeyPair()
+	if err := kp.CheckUID(); err != nil {
+		t.Fatalf("CheckUID: unexpected error %v", err)
+	}
+
+	kp.SetUID(make([]byte, MaxSm2UIDSize))
+	if err := kp.CheckUID(); err != nil {
+		t.Fatalf("CheckUID: unexpected error %v", err)
+	}
+
+	kp.SetUID(make([]byte, MaxSm2UIDSize+1))
+	if err := kp.CheckUID(); err != (InvalidUIDError{Size: MaxSm2UIDSize + 1}) {
+		t.Fatalf("CheckUID: expected InvalidUIDError, got %v", err)
+	}
+	if _, err := kp.Signer(); err != (InvalidUIDError{Size: MaxSm2UIDSize + 1}) {
+		t.Fatalf("Signer: expected InvalidUIDError, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package keypair
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Validity is the window in which a key pair may sign and encrypt, embedded in
+// every key pair to enforce rotation. Signers and encrypters refuse a key
+// outside its window with a KeyExpiredError, while verifiers and decrypters
+// always accept it, so that data protected while the key was valid stays
+// readable after it expires.
+type Validity struct {
+	// NotBefore is the start of the window, the zero time leaving it open.
+	NotBefore time.Time
+
+	// NotAfter is the end of the window, the zero time leaving it open.
+	NotAfter time.Time
+
+	// AllowExpired lets signers and encrypters use the key outside the window,
+	// for example to re-sign data during a migration.
+	AllowExpired bool
+}
+
+// SetValidity sets the window in which the key may sign and encrypt. Pass the
+// zero time to leave either side open.
+func (v *Validity) SetValidity(notBefore, notAfter time.Time) {
+	v.NotBefore, v.NotAfter = notBefore, notAfter
+}
+
+// SetValidityFromCertificate sets the window to the validity period of the
+// certificate issued for the key.
+func (v *Validity) SetValidityFromCertificate(cert *x509.Certificate) {
+	v.SetValidity(cert.NotBefore, cert.NotAfter)
+}
+
+// SetAllowExpired overrides the window, letting signers and encrypters use the
+// key outside it.
+func (v *Validity) SetAllowExpired(allow bool) {
+	v.AllowExpired = allow
+}
+
+// CheckValidity returns a KeyExpiredError when the current time of the package
+// clock is outside the window, unless AllowExpired is set.
+func (v Validity) CheckValidity() error {
+	if v.AllowExpired {
+		return nil
+	}
+	now := utils.Now()
+	if (!v.NotBefore.IsZero() && now.Before(v.NotBefore)) || (!v.NotAfter.IsZero() && now.After(v.NotAfter)) {
+		return KeyExpiredError{NotBefore: v.NotBefore, NotAfter: v.NotAfter, Now: now}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package keypair
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidity_CheckValidity(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(utils.SetClock(func() time.Time { return now }))
+	day := 24 * time.Hour
+
+	t.Run("open window", func(t *testing.T) {
+		assert.NoError(t, Validity{}.CheckValidity())
+		assert.NoError(t, Validity{NotBefore: now.Add(-day)}.CheckValidity())
+		assert.NoError(t, Validity{NotAfter: now.Add(day)}.CheckValidity())
+	})
+
+	t.Run("inside window", func(t *testing.T) {
+		var v Validity
+		v.SetValidity(now.Add(-day), now.Add(day))
+		assert.NoError(t, v.CheckValidity())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		var v Validity
+		v.SetValidity(now.Add(-2*day), now.Add(-day))
+		err := v.CheckValidity()
+		assert.Equal(t, KeyExpiredError{NotBefore: now.Add(-2 * day), NotAfter: now.Add(-day), Now: now}, err)
+		assert.Equal(t, "key expired at 2024-05-31T00:00:00Z", err.Error())
+
+		v.SetAllowExpired(true)
+		assert.NoError(t, v.CheckValidity())
+	})
+
+	t.Run("not yet valid", func(t *testing.T) {
+		var v Validity
+		v.SetValidity(now.Add(day), time.Time{})
+		err := v.CheckValidity()
+		assert.IsType(t, KeyExpiredError{}, err)
+		assert.Equal(t, "key not valid before 2024-06-02T00:00:00Z", err.Error())
+	})
+
+	t.Run("from certificate", func(t *testing.T) {
+		var v Validity
+		v.SetValidityFromCertificate(&x509.Certificate{NotBefore: now.Add(-day), NotAfter: now.Add(-time.Second)})
+		assert.Equal(t, now.Add(-day), v.NotBefore)
+		assert.IsType(t, KeyExpiredError{}, v.CheckValidity())
+	})
+
+	t.Run("signers", func(t *testing.T) {
+		expired := Validity{NotAfter: now.Add(-day)}
+
+		rsaKey := NewRsaKeyPair()
+		assert.NoError(t, rsaKey.GenKeyPair(1024))
+		rsaKey.Validity = expired
+		_, err := rsaKey.Signer()
+		assert.IsType(t, KeyExpiredError{}, err)
+		_, err = rsaKey.Decrypter()
+		assert.NoError(t, err)
+
+		ecdsaKey := NewEcdsaKeyPair()
+		assert.NoError(t, ecdsaKey.GenKeyPair())
+		ecdsaKey.Validity = expired
+		_, err = ecdsaKey.Signer()
+		assert.IsType(t, KeyExpiredError{}, err)
+
+		ed25519Key := NewEd25519KeyPair()
+		assert.NoError(t, ed25519Key.GenKeyPair())
+		ed25519Key.Validity = expired
+		_, err = ed25519Key.Signer()
+		assert.IsType(t, KeyExpiredError{}, err)
+
+		sm2Key := NewSm2KeyPair()
+		assert.NoError(t, sm2Key.GenKeyPair())
+		sm2Key.Validity = expired
+		_, err = sm2Key.Signer()
+		assert.IsType(t, KeyExpiredError{}, err)
+		sm2Key.SetAllowExpired(true)
+		_, err = sm2Key.Signer()
+		assert.NoError(t, err)
+	})
+}
+
+func TestCheckUse(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	t.Cleanup(utils.SetClock(func() time.Time { return now }))
+
+	t.Run("usage", func(t *testing.T) {
+		kp := NewRsaKeyPair()
+		kp.SetUsage(Sign)
+		assert.NoError(t, kp.CheckUse(SignOp))
+		assert.NoError(t, kp.CheckUse(VerifyOp))
+		assert.Equal(t, KeyUsageError{Usage: Sign, Operation: Encrypt}, kp.CheckUse(EncryptOp))
+		assert.Equal(t, KeyUsageError{Usage: Sign, Operation: Encrypt}, kp.CheckUse(DecryptOp))
+	})
+
+	t.Run("validity", func(t *testing.T) {
+		kp := NewSm2KeyPair()
+		kp.SetValidity(time.Time{}, now.Add(-time.Hour))
+		assert.IsType(t, KeyExpiredError{}, kp.CheckUse(SignOp))
+		assert.IsType(t, KeyExpiredError{}, kp.CheckUse(EncryptOp))
+		assert.NoError(t, kp.CheckUse(VerifyOp))
+		assert.NoError(t, kp.CheckUse(DecryptOp))
+	})
+}
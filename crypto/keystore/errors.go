@@ -0,0 +1,225 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidPasswordError represents an error when a keystore cannot be decrypted,
+// either because the password is wrong or because the file was modified.
+type InvalidPasswordError struct{}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e InvalidPasswordError) Error() string {
+	return "crypto/keystore: wrong password or corrupted keystore"
+}
+
+// Code returns the stable error code DGL-KEYSTORE-001.
+func (e InvalidPasswordError) Code() string {
+	return "DGL-KEYSTORE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPasswordError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "key")
+}
+
+// InvalidFormatError represents an error when a keystore file is malformed.
+type InvalidFormatError struct {
+	Err error // Underlying error from parsing
+}
+
+// Error returns a formatted error message describing the malformed keystore.
+func (e InvalidFormatError) Error() string {
+	return fmt.Sprintf("crypto/keystore: invalid keystore format: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-002.
+func (e InvalidFormatError) Code() string {
+	return "DGL-KEYSTORE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidFormatError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "", errcode.FieldCause, e.Err)
+}
+
+// InvalidParamsError represents an error when Argon2id parameters are out of range.
+type InvalidParamsError struct {
+	Params Params // The rejected parameters
+}
+
+// Error returns a formatted error message describing the invalid parameters.
+func (e InvalidParamsError) Error() string {
+	return fmt.Sprintf("crypto/keystore: invalid argon2id parameters: time=%d memory=%d threads=%d", e.Params.Time, e.Params.Memory, e.Params.Threads)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-003.
+func (e InvalidParamsError) Code() string {
+	return "DGL-KEYSTORE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidParamsError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "")
+}
+
+// InvalidNameError represents an error when an entry name is empty or too long.
+type InvalidNameError struct {
+	Name string // The rejected name
+}
+
+// Error returns a formatted error message describing the invalid name.
+func (e InvalidNameError) Error() string {
+	return fmt.Sprintf("crypto/keystore: invalid entry name %q", e.Name)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-004.
+func (e InvalidNameError) Code() string {
+	return "DGL-KEYSTORE-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidNameError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "", "name", e.Name)
+}
+
+// EntryNotFoundError represents an error when a named entry does not exist.
+type EntryNotFoundError struct {
+	Name string // The missing entry name
+}
+
+// Error returns a formatted error message describing the missing entry.
+func (e EntryNotFoundError) Error() string {
+	return fmt.Sprintf("crypto/keystore: entry %q not found", e.Name)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-005.
+func (e EntryNotFoundError) Code() string {
+	return "DGL-KEYSTORE-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EntryNotFoundError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "", "name", e.Name)
+}
+
+// EntryTypeError represents an error when an entry holds a different kind of key than requested.
+type EntryTypeError struct {
+	Name string    // The entry name
+	Type EntryType // The stored entry type
+}
+
+// Error returns a formatted error message describing the type mismatch.
+func (e EntryTypeError) Error() string {
+	return fmt.Sprintf("crypto/keystore: entry %q holds a %s", e.Name, e.Type)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-006.
+func (e EntryTypeError) Code() string {
+	return "DGL-KEYSTORE-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EntryTypeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "", "name", e.Name, "type", e.Type)
+}
+
+// KeyError represents an error when a private key cannot be encoded or decoded.
+type KeyError struct {
+	Err error // Underlying error from PKCS#8 marshaling or parsing
+}
+
+// Error returns a formatted error message describing the key error.
+func (e KeyError) Error() string {
+	return fmt.Sprintf("crypto/keystore: invalid private key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-007.
+func (e KeyError) Code() string {
+	return "DGL-KEYSTORE-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "key", errcode.FieldCause, e.Err)
+}
+
+// ExistsError represents an error when creating a keystore over an existing file.
+type ExistsError struct {
+	Path string // The existing file path
+}
+
+// Error returns a formatted error message describing the existing file.
+func (e ExistsError) Error() string {
+	return fmt.Sprintf("crypto/keystore: %s already exists", e.Path)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-008.
+func (e ExistsError) Code() string {
+	return "DGL-KEYSTORE-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ExistsError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "", "path", e.Path)
+}
+
+// ReadError represents an error when reading a keystore file fails.
+type ReadError struct {
+	Err error // Underlying error from the file system
+}
+
+// Error returns a formatted error message describing the read failure.
+func (e ReadError) Error() string {
+	return fmt.Sprintf("crypto/keystore: failed to read keystore: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-009.
+func (e ReadError) Code() string {
+	return "DGL-KEYSTORE-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "read", errcode.FieldCause, e.Err)
+}
+
+// WriteError represents an error when writing a keystore file fails.
+type WriteError struct {
+	Err error // Underlying error from the file system
+}
+
+// Error returns a formatted error message describing the write failure.
+func (e WriteError) Error() string {
+	return fmt.Sprintf("crypto/keystore: failed to write keystore: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-KEYSTORE-010.
+func (e WriteError) Code() string {
+	return "DGL-KEYSTORE-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WriteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "write", errcode.FieldCause, e.Err)
+}
+
+// ClosedError represents an error when using a keystore after Close.
+type ClosedError struct{}
+
+// Error returns a formatted error message describing the closed keystore.
+func (e ClosedError) Error() string {
+	return "crypto/keystore: keystore is closed"
+}
+
+// Code returns the stable error code DGL-KEYSTORE-011.
+func (e ClosedError) Code() string {
+	return "DGL-KEYSTORE-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ClosedError) Fields() map[string]any {
+	return errcode.NewFields("crypto/keystore", "AES-256-GCM", "")
+}
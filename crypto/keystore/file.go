@@ -0,0 +1,184 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/argon2"
+)
+
+// File layout: the header below, authenticated as additional data, followed by
+// the AES-256-GCM encryption of the entries.
+//
+//	magic   [8]byte  "DONGLEKS"
+//	version uint8
+//	time    uint32   Argon2id passes
+//	memory  uint32   Argon2id memory in KiB
+//	threads uint8    Argon2id parallelism
+//	salt    [16]byte
+//	nonce   [12]byte
+//
+// Entries are a uint32 count followed by, for each entry, a uint8 type, a uint16
+// name length and name, an int64 creation time in Unix seconds and a uint32 data
+// length and data. All integers are big endian.
+const (
+	magic      = "DONGLEKS"
+	version    = 1
+	saltSize   = 16
+	nonceSize  = 12
+	headerSize = len(magic) + 1 + 4 + 4 + 1 + saltSize + nonceSize
+	keySize    = 32
+)
+
+// header is the authenticated file header.
+type header struct {
+	params Params
+	salt   []byte
+	nonce  []byte
+}
+
+// marshal encodes the header.
+func (h header) marshal() []byte {
+	out := make([]byte, 0, headerSize)
+	out = append(out, magic...)
+	out = append(out, version)
+	out = binary.BigEndian.AppendUint32(out, h.params.Time)
+	out = binary.BigEndian.AppendUint32(out, h.params.Memory)
+	out = append(out, h.params.Threads)
+	out = append(out, h.salt...)
+	return append(out, h.nonce...)
+}
+
+// parseHeader decodes the header at the start of data.
+func parseHeader(data []byte) (header, error) {
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return header{}, InvalidFormatError{Err: errors.New("not a dongle keystore")}
+	}
+	data = data[len(magic):]
+	if data[0] != version {
+		return header{}, InvalidFormatError{Err: errors.New("unsupported version")}
+	}
+	h := header{params: Params{
+		Time:    binary.BigEndian.Uint32(data[1:5]),
+		Memory:  binary.BigEndian.Uint32(data[5:9]),
+		Threads: data[9],
+	}}
+	if err := h.params.validate(); err != nil {
+		return header{}, err
+	}
+	h.salt = bytes.Clone(data[10 : 10+saltSize])
+	h.nonce = bytes.Clone(data[10+saltSize : 10+saltSize+nonceSize])
+	return h, nil
+}
+
+// deriveKey derives the AES-256 master key from the password.
+func deriveKey(password, salt []byte, p Params) []byte {
+	return argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, keySize)
+}
+
+// seal encrypts the entries under key with a fresh nonce and returns the file contents.
+func seal(key []byte, h header, entries map[string]*entry) ([]byte, error) {
+	h.nonce = make([]byte, nonceSize)
+	if _, err := io.ReadFull(utils.Rand(), h.nonce); err != nil {
+		return nil, WriteError{Err: err}
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	plaintext := binary.BigEndian.AppendUint32(nil, uint32(len(names)))
+	for _, name := range names {
+		e := entries[name]
+		plaintext = append(plaintext, byte(e.typ))
+		plaintext = binary.BigEndian.AppendUint16(plaintext, uint16(len(name)))
+		plaintext = append(plaintext, name...)
+		plaintext = binary.BigEndian.AppendUint64(plaintext, uint64(e.created.Unix()))
+		plaintext = binary.BigEndian.AppendUint32(plaintext, uint32(len(e.data)))
+		plaintext = append(plaintext, e.data...)
+	}
+	defer utils.SecureWipe(plaintext)
+	aad := h.marshal()
+	return newGCM(key).Seal(aad, h.nonce, plaintext, aad), nil
+}
+
+// open decrypts file contents under the key derived from password.
+func open(data, password []byte) (header, []byte, map[string]*entry, error) {
+	h, err := parseHeader(data)
+	if err != nil {
+		return header{}, nil, nil, err
+	}
+	key := deriveKey(password, h.salt, h.params)
+	plaintext, err := newGCM(key).Open(nil, h.nonce, data[headerSize:], data[:headerSize])
+	if err != nil {
+		utils.SecureWipe(key)
+		return header{}, nil, nil, InvalidPasswordError{}
+	}
+	defer utils.SecureWipe(plaintext)
+	entries, err := parseEntries(plaintext)
+	if err != nil {
+		utils.SecureWipe(key)
+		return header{}, nil, nil, err
+	}
+	return h, key, entries, nil
+}
+
+// parseEntries decodes the decrypted entries.
+func parseEntries(data []byte) (map[string]*entry, error) {
+	malformed := InvalidFormatError{Err: errors.New("malformed entries")}
+	if len(data) < 4 {
+		return nil, malformed
+	}
+	count := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	entries := make(map[string]*entry)
+	for i := uint32(0); i < count; i++ {
+		if len(data) < 3 {
+			return nil, malformed
+		}
+		typ, n := EntryType(data[0]), int(binary.BigEndian.Uint16(data[1:3]))
+		data = data[3:]
+		if len(data) < n+12 {
+			return nil, malformed
+		}
+		name := string(data[:n])
+		created := time.Unix(int64(binary.BigEndian.Uint64(data[n:n+8])), 0)
+		size := int(binary.BigEndian.Uint32(data[n+8 : n+12]))
+		data = data[n+12:]
+		if len(data) < size || validName(name) != nil || (typ != TypeSecret && typ != TypePrivateKey) {
+			return nil, malformed
+		}
+		if _, dup := entries[name]; dup {
+			return nil, malformed
+		}
+		entries[name] = &entry{typ: typ, created: created, data: bytes.Clone(data[:size])}
+		data = data[size:]
+	}
+	if len(data) > 0 {
+		return nil, malformed
+	}
+	return entries, nil
+}
+
+// newGCM returns AES-256-GCM for a 32-byte key.
+func newGCM(key []byte) cipher.AEAD {
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
+
+// writeFile atomically replaces path with data, readable by the owner only, so
+// readers see either the old or the new keystore and never a partial one.
+func writeFile(path string, data []byte) error {
+	if err := utils.WriteFileAtomic(path, data, 0o600); err != nil {
+		return WriteError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+// Package keystore implements a password protected file of named keys for command
+// line tools and services built on dongle. The whole file is encrypted with
+// AES-256-GCM under a master key derived from the password with Argon2id, and the
+// header carrying the Argon2id parameters is authenticated along with it. Every
+// change is written atomically, so an interrupted write leaves the previous
+// keystore intact.
+package keystore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// EntryType is the kind of key held by an entry.
+type EntryType uint8
+
+// The entry types.
+const (
+	TypeSecret     EntryType = 1 // Raw secret bytes, such as a symmetric key
+	TypePrivateKey EntryType = 2 // Private key stored as PKCS#8
+)
+
+// String returns a readable name of the entry type.
+func (t EntryType) String() string {
+	switch t {
+	case TypeSecret:
+		return "secret"
+	case TypePrivateKey:
+		return "private key"
+	}
+	return "unknown entry"
+}
+
+// maxNameSize is the longest accepted entry name in bytes.
+const maxNameSize = 255
+
+// Params are the Argon2id parameters used to derive the master key.
+type Params struct {
+	Time    uint32 // Number of passes
+	Memory  uint32 // Memory in KiB
+	Threads uint8  // Degree of parallelism
+}
+
+// DefaultParams follow the second recommended option of RFC 9106: three passes
+// over 64 MiB with four lanes.
+var DefaultParams = Params{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// validate checks the parameters, bounding the work a hostile file can demand.
+func (p Params) validate() error {
+	if p.Time < 1 || p.Time > 64 || p.Threads < 1 || p.Memory < 8*uint32(p.Threads) || p.Memory > 4*1024*1024 {
+		return InvalidParamsError{Params: p}
+	}
+	return nil
+}
+
+// Entry describes a stored entry without its key material.
+type Entry struct {
+	Name    string    // Entry name
+	Type    EntryType // Kind of key
+	Created time.Time // Time the entry was stored
+}
+
+// entry is a decrypted entry.
+type entry struct {
+	typ     EntryType
+	created time.Time
+	data    []byte
+}
+
+// KeyStore is an open keystore file. It is safe for concurrent use by multiple
+// goroutines, but not by multiple processes.
+type KeyStore struct {
+	mu      sync.Mutex
+	path    string
+	header  header
+	key     []byte
+	entries map[string]*entry
+}
+
+// Create creates an empty keystore at path protected by password. It fails if
+// the file already exists.
+func Create(path string, password []byte, params Params) (*KeyStore, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return nil, ExistsError{Path: path}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, WriteError{Err: err}
+	}
+	h := header{params: params, salt: make([]byte, saltSize)}
+	if _, err := io.ReadFull(utils.Rand(), h.salt); err != nil {
+		return nil, WriteError{Err: err}
+	}
+	ks := &KeyStore{path: path, header: h, key: deriveKey(password, h.salt, params), entries: map[string]*entry{}}
+	if err := ks.save(); err != nil {
+		ks.Close()
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Open opens and decrypts the keystore at path with password.
+func Open(path string, password []byte) (*KeyStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ReadError{Err: err}
+	}
+	h, key, entries, err := open(data, password)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyStore{path: path, header: h, key: key, entries: entries}, nil
+}
+
+// Put stores a secret under name, replacing any existing entry, and saves the keystore.
+func (ks *KeyStore) Put(name string, secret []byte) error {
+	return ks.put(name, TypeSecret, bytes.Clone(secret))
+}
+
+// PutPrivateKey stores a private key under name as PKCS#8, replacing any existing
+// entry, and saves the keystore. Keys supported by x509.MarshalPKCS8PrivateKey are
+// accepted.
+func (ks *KeyStore) PutPrivateKey(name string, key crypto.PrivateKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return KeyError{Err: err}
+	}
+	return ks.put(name, TypePrivateKey, der)
+}
+
+// Get returns a copy of the secret stored under name.
+func (ks *KeyStore) Get(name string) ([]byte, error) {
+	return ks.get(name, TypeSecret)
+}
+
+// GetPrivateKey returns the private key stored under name.
+func (ks *KeyStore) GetPrivateKey(name string) (crypto.PrivateKey, error) {
+	der, err := ks.get(name, TypePrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.SecureWipe(der)
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, KeyError{Err: err}
+	}
+	return key, nil
+}
+
+// Delete removes the entry stored under name and saves the keystore.
+func (ks *KeyStore) Delete(name string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return ClosedError{}
+	}
+	e, ok := ks.entries[name]
+	if !ok {
+		return EntryNotFoundError{Name: name}
+	}
+	delete(ks.entries, name)
+	if err := ks.save(); err != nil {
+		ks.entries[name] = e
+		return err
+	}
+	utils.SecureWipe(e.data)
+	return nil
+}
+
+// List returns the entries sorted by name.
+func (ks *KeyStore) List() []Entry {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	list := make([]Entry, 0, len(ks.entries))
+	for name, e := range ks.entries {
+		list = append(list, Entry{Name: name, Type: e.typ, Created: e.created})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ChangePassword re-encrypts the keystore under a new password with a new salt
+// and the given Argon2id parameters.
+func (ks *KeyStore) ChangePassword(password []byte, params Params) error {
+	if err := params.validate(); err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return ClosedError{}
+	}
+	h := header{params: params, salt: make([]byte, saltSize)}
+	if _, err := io.ReadFull(utils.Rand(), h.salt); err != nil {
+		return WriteError{Err: err}
+	}
+	oldHeader, oldKey := ks.header, ks.key
+	ks.header, ks.key = h, deriveKey(password, h.salt, params)
+	if err := ks.save(); err != nil {
+		utils.SecureWipe(ks.key)
+		ks.header, ks.key = oldHeader, oldKey
+		return err
+	}
+	utils.SecureWipe(oldKey)
+	return nil
+}
+
+// Close wipes the master key and the decrypted entries from memory. The
+// keystore cannot be used afterwards.
+func (ks *KeyStore) Close() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	utils.SecureWipe(ks.key)
+	for _, e := range ks.entries {
+		utils.SecureWipe(e.data)
+	}
+	ks.key, ks.entries = nil, nil
+}
+
+// put stores an entry and saves the keystore, restoring the previous entry on failure.
+func (ks *KeyStore) put(name string, typ EntryType, data []byte) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return ClosedError{}
+	}
+	old, existed := ks.entries[name]
+	ks.entries[name] = &entry{typ: typ, created: time.Unix(utils.Now().Unix(), 0), data: data}
+	if err := ks.save(); err != nil {
+		if existed {
+			ks.entries[name] = old
+		} else {
+			delete(ks.entries, name)
+		}
+		return err
+	}
+	if existed {
+		utils.SecureWipe(old.data)
+	}
+	return nil
+}
+
+// get returns a copy of the data of an entry of the given type.
+func (ks *KeyStore) get(name string, typ EntryType) ([]byte, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return nil, ClosedError{}
+	}
+	e, ok := ks.entries[name]
+	if !ok {
+		return nil, EntryNotFoundError{Name: name}
+	}
+	if e.typ != typ {
+		return nil, EntryTypeError{Name: name, Type: e.typ}
+	}
+	return bytes.Clone(e.data), nil
+}
+
+// save encrypts the entries and atomically replaces the keystore file.
+func (ks *KeyStore) save() error {
+	data, err := seal(ks.key, ks.header, ks.entries)
+	if err != nil {
+		return err
+	}
+	return writeFile(ks.path, data)
+}
+
+// validName checks that an entry name is non-empty and fits the file format.
+func validName(name string) error {
+	if name == "" || len(name) > maxNameSize {
+		return InvalidNameError{Name: name}
+	}
+	return nil
+}
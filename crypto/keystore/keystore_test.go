@@ -0,0 +1,257 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testParams keeps key derivation fast in tests.
+var testParams = Params{Time: 1, Memory: 64, Threads: 1}
+
+var password = []byte("correct horse battery staple")
+
+func newKeyStore(t *testing.T) (*KeyStore, string) {
+	path := filepath.Join(t.TempDir(), "keys.dks")
+	ks, err := Create(path, password, testParams)
+	require.NoError(t, err)
+	return ks, path
+}
+
+func TestKeyStore(t *testing.T) {
+	ks, path := newKeyStore(t)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	require.NoError(t, ks.Put("aes", []byte("0123456789abcdef")))
+	require.NoError(t, ks.Put("empty", nil))
+	require.NoError(t, ks.PutPrivateKey("ecdsa", ecKey))
+	require.NoError(t, ks.PutPrivateKey("rsa", rsaKey))
+	require.NoError(t, ks.PutPrivateKey("ed25519", edKey))
+	ks.Close()
+
+	ks, err := Open(path, password)
+	require.NoError(t, err)
+	defer ks.Close()
+
+	secret, err := ks.Get("aes")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0123456789abcdef"), secret)
+	secret[0] = 'x'
+	secret, _ = ks.Get("aes")
+	assert.Equal(t, byte('0'), secret[0])
+	secret, err = ks.Get("empty")
+	require.NoError(t, err)
+	assert.Empty(t, secret)
+
+	key, err := ks.GetPrivateKey("ecdsa")
+	require.NoError(t, err)
+	assert.True(t, ecKey.Equal(key))
+	key, err = ks.GetPrivateKey("rsa")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.Equal(key))
+	key, err = ks.GetPrivateKey("ed25519")
+	require.NoError(t, err)
+	assert.True(t, edKey.Equal(key))
+
+	list := ks.List()
+	require.Len(t, list, 5)
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = e.Name
+		assert.False(t, e.Created.IsZero())
+	}
+	assert.Equal(t, []string{"aes", "ecdsa", "ed25519", "empty", "rsa"}, names)
+	assert.Equal(t, TypeSecret, list[0].Type)
+	assert.Equal(t, TypePrivateKey, list[1].Type)
+
+	require.NoError(t, ks.Put("aes", []byte("replaced")))
+	require.NoError(t, ks.Delete("rsa"))
+	assert.Equal(t, EntryNotFoundError{Name: "rsa"}, ks.Delete("rsa"))
+
+	reopened, err := Open(path, password)
+	require.NoError(t, err)
+	defer reopened.Close()
+	secret, _ = reopened.Get("aes")
+	assert.Equal(t, []byte("replaced"), secret)
+	_, err = reopened.GetPrivateKey("rsa")
+	assert.Equal(t, EntryNotFoundError{Name: "rsa"}, err)
+	assert.Equal(t, `crypto/keystore: entry "rsa" not found`, err.Error())
+	assert.Len(t, reopened.List(), 4)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	files, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, files, 1)
+}
+
+func TestKeyStore_Errors(t *testing.T) {
+	ks, path := newKeyStore(t)
+	require.NoError(t, ks.Put("secret", []byte("value")))
+	require.NoError(t, ks.PutPrivateKey("key", ed25519.NewKeyFromSeed(make([]byte, 32))))
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := Open(path, []byte("wrong"))
+		assert.Equal(t, InvalidPasswordError{}, err)
+	})
+
+	t.Run("tampered file", func(t *testing.T) {
+		data, _ := os.ReadFile(path)
+		for _, i := range []int{len(magic) + 10, headerSize - 1, len(data) - 1} {
+			tampered := append([]byte(nil), data...)
+			tampered[i] ^= 1
+			bad := filepath.Join(t.TempDir(), "bad.dks")
+			require.NoError(t, os.WriteFile(bad, tampered, 0o600))
+			_, err := Open(bad, password)
+			assert.Equal(t, InvalidPasswordError{}, err)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		data, _ := os.ReadFile(path)
+		for name, contents := range map[string][]byte{
+			"magic":   append([]byte("NOTADKS!"), data[len(magic):]...),
+			"version": append(append([]byte(magic), 2), data[len(magic)+1:]...),
+			"short":   data[:headerSize-1],
+		} {
+			bad := filepath.Join(t.TempDir(), "bad.dks")
+			require.NoError(t, os.WriteFile(bad, contents, 0o600))
+			_, err := Open(bad, password)
+			assert.IsType(t, InvalidFormatError{}, err, name)
+		}
+
+		params := append([]byte(nil), data...)
+		params[len(magic)+5] = 0xff
+		bad := filepath.Join(t.TempDir(), "bad.dks")
+		require.NoError(t, os.WriteFile(bad, params, 0o600))
+		_, err := Open(bad, password)
+		assert.IsType(t, InvalidParamsError{}, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Open(filepath.Join(t.TempDir(), "missing.dks"), password)
+		assert.IsType(t, ReadError{}, err)
+	})
+
+	t.Run("existing file", func(t *testing.T) {
+		_, err := Create(path, password, testParams)
+		assert.Equal(t, ExistsError{Path: path}, err)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, p := range []Params{{}, {Time: 1, Memory: 7, Threads: 1}, {Time: 65, Memory: 64, Threads: 1}, {Time: 1, Memory: 1 << 30, Threads: 1}} {
+			_, err := Create(filepath.Join(t.TempDir(), "keys.dks"), password, p)
+			assert.Equal(t, InvalidParamsError{Params: p}, err)
+		}
+	})
+
+	t.Run("entry type", func(t *testing.T) {
+		_, err := ks.GetPrivateKey("secret")
+		assert.Equal(t, EntryTypeError{Name: "secret", Type: TypeSecret}, err)
+		assert.Equal(t, `crypto/keystore: entry "secret" holds a secret`, err.Error())
+		_, err = ks.Get("key")
+		assert.Equal(t, EntryTypeError{Name: "key", Type: TypePrivateKey}, err)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		assert.Equal(t, InvalidNameError{Name: ""}, ks.Put("", nil))
+		long := string(make([]byte, maxNameSize+1))
+		assert.Equal(t, InvalidNameError{Name: long}, ks.Put(long, nil))
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		assert.IsType(t, KeyError{}, ks.PutPrivateKey("bad", "not a key"))
+	})
+
+	t.Run("write failure", func(t *testing.T) {
+		dir := t.TempDir()
+		other, err := Create(filepath.Join(dir, "keys.dks"), password, testParams)
+		require.NoError(t, err)
+		require.NoError(t, other.Put("kept", []byte("value")))
+		require.NoError(t, os.RemoveAll(dir))
+
+		assert.IsType(t, WriteError{}, other.Put("lost", []byte("value")))
+		assert.IsType(t, WriteError{}, other.Delete("kept"))
+		assert.IsType(t, WriteError{}, other.ChangePassword([]byte("new"), testParams))
+		assert.Len(t, other.List(), 1)
+		secret, err := other.Get("kept")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("value"), secret)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		other, _ := newKeyStore(t)
+		other.Close()
+		assert.Equal(t, ClosedError{}, other.Put("a", nil))
+		assert.Equal(t, ClosedError{}, other.Delete("a"))
+		assert.Equal(t, ClosedError{}, other.ChangePassword(password, testParams))
+		_, err := other.Get("a")
+		assert.Equal(t, ClosedError{}, err)
+		assert.Empty(t, other.List())
+	})
+}
+
+func TestKeyStore_ChangePassword(t *testing.T) {
+	ks, path := newKeyStore(t)
+	require.NoError(t, ks.Put("secret", []byte("value")))
+	require.NoError(t, ks.ChangePassword([]byte("new password"), Params{Time: 2, Memory: 128, Threads: 2}))
+	require.NoError(t, ks.Put("after", []byte("change")))
+	ks.Close()
+
+	_, err := Open(path, password)
+	assert.Equal(t, InvalidPasswordError{}, err)
+	ks, err = Open(path, []byte("new password"))
+	require.NoError(t, err)
+	defer ks.Close()
+	assert.Equal(t, Params{Time: 2, Memory: 128, Threads: 2}, ks.header.params)
+	secret, err := ks.Get("after")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("change"), secret)
+}
+
+func TestKeyStore_Concurrent(t *testing.T) {
+	ks, path := newKeyStore(t)
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ks.Put(name, []byte(name)))
+		}()
+	}
+	wg.Wait()
+
+	reopened, err := Open(path, password)
+	require.NoError(t, err)
+	assert.Len(t, reopened.List(), 4)
+}
+
+func TestParseEntries(t *testing.T) {
+	valid := []byte{0, 0, 0, 1, 1, 0, 1, 'a', 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 'x'}
+	entries, err := parseEntries(valid)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("x"), entries["a"].data)
+
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"truncated": valid[:len(valid)-1],
+		"trailing":  append(append([]byte(nil), valid...), 0),
+		"type":      append([]byte{0, 0, 0, 1, 9}, valid[5:]...),
+		"name":      append([]byte{0, 0, 0, 1, 1, 0, 0}, valid[8:]...),
+		"duplicate": append(append([]byte{0, 0, 0, 2}, valid[4:]...), valid[4:]...),
+	} {
+		_, err := parseEntries(data)
+		assert.IsType(t, InvalidFormatError{}, err, name)
+	}
+}
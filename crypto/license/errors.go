@@ -0,0 +1,131 @@
+package license
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidLicenseError represents an error when a license cannot be issued
+// because of its claims, or when a license key is malformed.
+type InvalidLicenseError struct {
+	Reason string // Why the license is invalid
+}
+
+// Error returns a formatted error message describing the invalid license.
+func (e InvalidLicenseError) Error() string {
+	return "crypto/license: invalid license: " + e.Reason
+}
+
+// Code returns the stable error code DGL-LICENSE-001.
+func (e InvalidLicenseError) Code() string {
+	return "DGL-LICENSE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidLicenseError) Fields() map[string]any {
+	return errcode.NewFields("crypto/license", "", "", "reason", e.Reason)
+}
+
+// SignError represents an error when a license cannot be signed, including
+// when the issuer key pair is unusable.
+type SignError struct {
+	Err error // The underlying error
+}
+
+// Error returns a formatted error message describing the signing failure.
+func (e SignError) Error() string {
+	return fmt.Sprintf("crypto/license: failed to sign license: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-LICENSE-002.
+func (e SignError) Code() string {
+	return "DGL-LICENSE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/license", "", "sign", errcode.FieldCause, e.Err)
+}
+
+// KeyError represents an error when a public key cannot be used for
+// validation, or when no key was added to the validator.
+type KeyError struct {
+	Err error // The underlying error
+}
+
+// Error returns a formatted error message describing the unusable key.
+func (e KeyError) Error() string {
+	return fmt.Sprintf("crypto/license: invalid public key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-LICENSE-003.
+func (e KeyError) Code() string {
+	return "DGL-LICENSE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/license", "", "verify", errcode.FieldCause, e.Err)
+}
+
+// SignatureError represents an error when a license was not signed by any key
+// of the validator, or was modified after signing.
+type SignatureError struct{}
+
+// Error returns a formatted error message describing the signature mismatch.
+func (e SignatureError) Error() string {
+	return "crypto/license: invalid license signature"
+}
+
+// Code returns the stable error code DGL-LICENSE-004.
+func (e SignatureError) Code() string {
+	return "DGL-LICENSE-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/license", "", "verify")
+}
+
+// ProductError represents an error when a validly signed license was issued
+// for another product.
+type ProductError struct {
+	Product string // The product of the license
+}
+
+// Error returns a formatted error message describing the product mismatch.
+func (e ProductError) Error() string {
+	return fmt.Sprintf("crypto/license: license issued for product %q", e.Product)
+}
+
+// Code returns the stable error code DGL-LICENSE-005.
+func (e ProductError) Code() string {
+	return "DGL-LICENSE-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ProductError) Fields() map[string]any {
+	return errcode.NewFields("crypto/license", "", "verify", "product", e.Product)
+}
+
+// ExpiredError represents an error when a license is validated after its expiry.
+type ExpiredError struct {
+	Expiry time.Time // When the license expired
+}
+
+// Error returns a formatted error message describing the expired license.
+func (e ExpiredError) Error() string {
+	return fmt.Sprintf("crypto/license: license expired at %s", e.Expiry.UTC().Format(time.RFC3339))
+}
+
+// Code returns the stable error code DGL-LICENSE-006.
+func (e ExpiredError) Code() string {
+	return "DGL-LICENSE-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ExpiredError) Fields() map[string]any {
+	return errcode.NewFields("crypto/license", "", "verify", "expiry", e.Expiry.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,233 @@
+package license
+
+import (
+	"crypto"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/coding/base32"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/errcode"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var issued = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+var claims = License{
+	Product:  "acme-editor",
+	Licensee: "Example Corp",
+	Serial:   "AE-2026-0042",
+	Issued:   issued,
+	Expires:  issued.AddDate(1, 0, 0),
+	Features: []string{"export", "cloud-sync"},
+}
+
+func newEd25519(t *testing.T) (*keypair.Ed25519KeyPair, *keypair.Ed25519KeyPair) {
+	t.Helper()
+	kp := keypair.NewEd25519KeyPair()
+	require.NoError(t, kp.GenKeyPair())
+	pub := keypair.NewEd25519KeyPair()
+	pub.PublicKey = kp.PublicKey
+	return kp, pub
+}
+
+func newValidator(now time.Time, keys ...*keypair.Ed25519KeyPair) *Validator {
+	v := NewValidator()
+	for _, kp := range keys {
+		v.AddEd25519Key(kp)
+	}
+	v.SetClock(mock.NewClock(now).Now)
+	return v
+}
+
+func TestIssueValidate(t *testing.T) {
+	kp, pub := newEd25519(t)
+	key, err := NewEd25519Issuer(kp).Issue(claims)
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-Z2-7]{5}(-[A-Z2-7]{1,5})+$`), key)
+
+	v := newValidator(issued.Add(time.Hour), pub)
+	lic, err := v.Validate(key)
+	require.NoError(t, err)
+	assert.Equal(t, claims.Product, lic.Product)
+	assert.Equal(t, claims.Licensee, lic.Licensee)
+	assert.Equal(t, claims.Serial, lic.Serial)
+	assert.True(t, claims.Issued.Equal(lic.Issued))
+	assert.True(t, claims.Expires.Equal(lic.Expires))
+	assert.Equal(t, claims.Features, lic.Features)
+	assert.Equal(t, Ed25519, lic.Algorithm)
+	assert.True(t, lic.HasFeature("export"))
+	assert.False(t, lic.HasFeature("admin"))
+	assert.False(t, lic.Perpetual())
+
+	t.Run("typed by hand", func(t *testing.T) {
+		typed := strings.ToLower(strings.ReplaceAll(key, "-", " "))
+		typed = strings.ReplaceAll(typed, "o", "0")
+		_, err := v.Validate(typed)
+		assert.NoError(t, err)
+	})
+
+	t.Run("perpetual", func(t *testing.T) {
+		perpetual := claims
+		perpetual.Expires = time.Time{}
+		perpetual.Features = nil
+		key, err := NewEd25519Issuer(kp).Issue(perpetual)
+		require.NoError(t, err)
+		lic, err := newValidator(issued.AddDate(50, 0, 0), pub).Validate(key)
+		require.NoError(t, err)
+		assert.True(t, lic.Perpetual())
+		assert.Empty(t, lic.Features)
+	})
+
+	t.Run("issued now", func(t *testing.T) {
+		unset := claims
+		unset.Issued = time.Time{}
+		unset.Expires = time.Now().Add(time.Hour)
+		key, err := NewEd25519Issuer(kp).Issue(unset)
+		require.NoError(t, err)
+		v := NewValidator()
+		v.AddEd25519Key(pub)
+		lic, err := v.Validate(key)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), lic.Issued, time.Minute)
+	})
+}
+
+func TestValidate_Errors(t *testing.T) {
+	kp, pub := newEd25519(t)
+	key, err := NewEd25519Issuer(kp).Issue(claims)
+	require.NoError(t, err)
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := newValidator(claims.Expires, pub).Validate(key)
+		assert.Equal(t, ExpiredError{Expiry: claims.Expires}, err)
+		assert.Equal(t, "crypto/license: license expired at 2027-10-16T12:00:00Z", err.Error())
+	})
+
+	t.Run("product", func(t *testing.T) {
+		v := newValidator(issued, pub)
+		v.SetProduct("acme-editor")
+		_, err := v.Validate(key)
+		assert.NoError(t, err)
+		v.SetProduct("acme-viewer")
+		_, err = v.Validate(key)
+		assert.Equal(t, ProductError{Product: "acme-editor"}, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		forged := claims
+		forged.Features = append(forged.Features, "admin")
+		other, _ := newEd25519(t)
+		forgedKey, err := NewEd25519Issuer(other).Issue(forged)
+		require.NoError(t, err)
+		_, err = newValidator(issued, pub).Validate(forgedKey)
+		assert.Equal(t, SignatureError{}, err)
+
+		// Flip a bit of the product name
+		blob, _, err := base32.NewStdDecoder(base32.StdAlphabet).DecodeTolerant([]byte(key), false)
+		require.NoError(t, err)
+		blob[5] ^= 1
+		_, err = newValidator(issued, pub).Validate(render(blob))
+		assert.Equal(t, SignatureError{}, err)
+	})
+
+	t.Run("rotated keys", func(t *testing.T) {
+		_, next := newEd25519(t)
+		lic, err := newValidator(issued, next, pub).Validate(key)
+		require.NoError(t, err)
+		assert.Equal(t, "AE-2026-0042", lic.Serial)
+		_, err = newValidator(issued, next).Validate(key)
+		assert.Equal(t, SignatureError{}, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		v := newValidator(issued, pub)
+		for _, bad := range []string{"", "!!!!!", "AAAAA-AAA", key[:20], render([]byte{0x05, 2, 1, 0, 0, 0})} {
+			_, err := v.Validate(bad)
+			assert.IsType(t, InvalidLicenseError{}, err, bad)
+		}
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := NewValidator().Validate(key)
+		assert.Equal(t, KeyError{Err: keypair.EmptyPublicKeyError{}}, err)
+
+		v := NewValidator()
+		v.AddEd25519Key(keypair.NewEd25519KeyPair())
+		assert.IsType(t, KeyError{}, v.Error)
+		_, err = v.Validate(key)
+		assert.IsType(t, KeyError{}, err)
+
+		v = NewValidator()
+		v.AddRsaKey(keypair.NewRsaKeyPair())
+		assert.IsType(t, KeyError{}, v.Error)
+	})
+}
+
+func TestIssue_Errors(t *testing.T) {
+	kp, _ := newEd25519(t)
+
+	t.Run("claims", func(t *testing.T) {
+		_, err := NewEd25519Issuer(kp).Issue(License{Issued: issued})
+		assert.Equal(t, InvalidLicenseError{Reason: "product is empty"}, err)
+		_, err = NewEd25519Issuer(kp).Issue(License{Product: "p", Issued: issued, Expires: issued})
+		assert.Equal(t, InvalidLicenseError{Reason: "expires before it is issued"}, err)
+	})
+
+	t.Run("no private key", func(t *testing.T) {
+		issuer := NewEd25519Issuer(keypair.NewEd25519KeyPair())
+		assert.IsType(t, SignError{}, issuer.Error)
+		_, err := issuer.Issue(claims)
+		assert.IsType(t, SignError{}, err)
+
+		issuer = NewRsaIssuer(keypair.NewRsaKeyPair())
+		assert.IsType(t, SignError{}, issuer.Error)
+	})
+}
+
+func TestRSA(t *testing.T) {
+	kp := keypair.NewRsaKeyPair()
+	kp.SetHash(crypto.SHA256)
+	require.NoError(t, kp.GenKeyPair(2048))
+	key, err := NewRsaIssuer(kp).Issue(claims)
+	require.NoError(t, err)
+	assert.Greater(t, len(key), 500)
+
+	pub := keypair.NewRsaKeyPair()
+	pub.PublicKey = kp.PublicKey
+	v := NewValidator()
+	v.AddRsaKey(pub)
+	v.SetClock(mock.NewClock(issued).Now)
+	lic, err := v.Validate(key)
+	require.NoError(t, err)
+	assert.Equal(t, RSA, lic.Algorithm)
+	assert.Equal(t, claims.Features, lic.Features)
+
+	// An Ed25519 key does not validate RSA licenses
+	_, edPub := newEd25519(t)
+	_, err = newValidator(issued, edPub).Validate(key)
+	assert.Equal(t, SignatureError{}, err)
+}
+
+func TestErrors(t *testing.T) {
+	assert.Equal(t, "Ed25519", Ed25519.String())
+	assert.Equal(t, "RSA", RSA.String())
+	assert.Equal(t, "Algorithm(9)", Algorithm(9).String())
+
+	for code, err := range map[string]errcode.Coder{
+		"DGL-LICENSE-001": InvalidLicenseError{},
+		"DGL-LICENSE-002": SignError{},
+		"DGL-LICENSE-003": KeyError{},
+		"DGL-LICENSE-004": SignatureError{},
+		"DGL-LICENSE-005": ProductError{},
+		"DGL-LICENSE-006": ExpiredError{},
+	} {
+		assert.Equal(t, code, err.Code())
+		assert.Equal(t, "crypto/license", err.Fields()[errcode.FieldPackage])
+		assert.NotEmpty(t, err.Error())
+	}
+}
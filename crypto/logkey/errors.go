@@ -0,0 +1,127 @@
+package logkey
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidKeyError represents an error when the master key or ratchet seed is too short.
+type InvalidKeyError struct {
+	Size int // Size of the provided key
+}
+
+// Error returns a formatted error message describing the short key.
+func (e InvalidKeyError) Error() string {
+	return fmt.Sprintf("crypto/logkey: invalid key size %d, must be at least %d bytes", e.Size, MinKeySize)
+}
+
+// Code returns the stable error code DGL-LOGKEY-001.
+func (e InvalidKeyError) Code() string {
+	return "DGL-LOGKEY-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/logkey", "", "key", errcode.FieldKeySize, e.Size)
+}
+
+// ExpiredPeriodError represents an error when a ratchet is asked for a period whose key was discarded.
+type ExpiredPeriodError struct {
+	Period  uint64 // The requested period
+	Current uint64 // The earliest period the ratchet can still derive
+}
+
+// Error returns a formatted error message describing the discarded period.
+func (e ExpiredPeriodError) Error() string {
+	return fmt.Sprintf("crypto/logkey: key for period %d was discarded, ratchet is at period %d", e.Period, e.Current)
+}
+
+// Code returns the stable error code DGL-LOGKEY-002.
+func (e ExpiredPeriodError) Code() string {
+	return "DGL-LOGKEY-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ExpiredPeriodError) Fields() map[string]any {
+	return errcode.NewFields("crypto/logkey", "", "", "period", e.Period, "current", e.Current)
+}
+
+// InvalidCiphertextError represents an error when a ciphertext is too short or has an unknown version.
+type InvalidCiphertextError struct{}
+
+// Error returns a formatted error message describing the malformed ciphertext.
+func (e InvalidCiphertextError) Error() string {
+	return "crypto/logkey: invalid ciphertext"
+}
+
+// Code returns the stable error code DGL-LOGKEY-003.
+func (e InvalidCiphertextError) Code() string {
+	return "DGL-LOGKEY-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCiphertextError) Fields() map[string]any {
+	return errcode.NewFields("crypto/logkey", "", "")
+}
+
+// InvalidStateError represents an error when a serialized ratchet state is malformed.
+type InvalidStateError struct{}
+
+// Error returns a formatted error message describing the malformed state.
+func (e InvalidStateError) Error() string {
+	return "crypto/logkey: invalid ratchet state"
+}
+
+// Code returns the stable error code DGL-LOGKEY-004.
+func (e InvalidStateError) Code() string {
+	return "DGL-LOGKEY-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidStateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/logkey", "", "")
+}
+
+// DecryptError represents an error when a ciphertext fails authentication.
+type DecryptError struct {
+	Period uint64 // Period tagged on the ciphertext
+}
+
+// Error returns a formatted error message describing the authentication failure.
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("crypto/logkey: failed to decrypt ciphertext of period %d", e.Period)
+}
+
+// Code returns the stable error code DGL-LOGKEY-005.
+func (e DecryptError) Code() string {
+	return "DGL-LOGKEY-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/logkey", "", "decrypt", "period", e.Period)
+}
+
+// FuturePeriodError represents an error when a ratchet is asked for a period
+// further ahead than its MaxAhead.
+type FuturePeriodError struct {
+	Period   uint64 // The requested period
+	Current  uint64 // The earliest period the ratchet can still derive
+	MaxAhead uint64 // The number of periods the ratchet derives ahead
+}
+
+// Error returns a formatted error message describing the far-future period.
+func (e FuturePeriodError) Error() string {
+	return fmt.Sprintf("crypto/logkey: period %d is more than %d periods ahead of period %d", e.Period, e.MaxAhead, e.Current)
+}
+
+// Code returns the stable error code DGL-LOGKEY-006.
+func (e FuturePeriodError) Code() string {
+	return "DGL-LOGKEY-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e FuturePeriodError) Fields() map[string]any {
+	return errcode.NewFields("crypto/logkey", "", "", "period", e.Period, "current", e.Current, "max_ahead", e.MaxAhead)
+}
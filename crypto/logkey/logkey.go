@@ -0,0 +1,143 @@
+// Package logkey derives rotating encryption keys for encrypted logs.
+// Every log period, such as a day, an hour or a size based rotation, gets its own
+// key derived with HKDF and labeled info, and ciphertexts are tagged with their
+// period so readers know which key to use. A Schedule derives keys from a master key
+// on demand, while a Ratchet evolves a one-way chain so that keys of past periods
+// can be discarded for forward secrecy.
+package logkey
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+const (
+	KeySize    = 32 // Size of the derived period keys, used with AES-256-GCM
+	MinKeySize = 32 // Minimum size of a master key or ratchet seed
+)
+
+// version is the ciphertext format version.
+const version = 1
+
+// headerSize is the size of the version byte and the period tag.
+const headerSize = 1 + 8
+
+// KeySource provides the encryption key of a period.
+type KeySource interface {
+	Key(period uint64) ([]byte, error)
+}
+
+// Daily returns the period of t for daily rotation, the number of UTC days since the Unix epoch.
+func Daily(t time.Time) uint64 {
+	return uint64(t.Unix() / 86400)
+}
+
+// Hourly returns the period of t for hourly rotation, the number of hours since the Unix epoch.
+func Hourly(t time.Time) uint64 {
+	return uint64(t.Unix() / 3600)
+}
+
+// Schedule derives period keys from a master key. Keys of any period can be
+// derived at any time, so the master key must be protected for as long as logs
+// are kept; use a Ratchet when old keys must become unrecoverable.
+type Schedule struct {
+	master []byte
+	label  string
+	Error  error // Error field for storing key errors
+}
+
+// NewSchedule returns a new Schedule for master. The label separates key
+// schedules that share a master key, for example one per log stream.
+func NewSchedule(master []byte, label string) *Schedule {
+	s := &Schedule{label: label}
+	if len(master) < MinKeySize {
+		s.Error = InvalidKeyError{Size: len(master)}
+		return s
+	}
+	s.master = append([]byte{}, master...)
+	return s
+}
+
+// Key returns the key of period.
+func (s *Schedule) Key(period uint64) ([]byte, error) {
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	return expand(hkdf.Extract(sha256.New, s.master, nil), "dongle/logkey/schedule", s.label, period)
+}
+
+// Seal encrypts plaintext with the key of period and tags the result with period.
+func Seal(ks KeySource, period uint64, plaintext []byte) ([]byte, error) {
+	key, err := ks.Key(period)
+	if err != nil {
+		return nil, err
+	}
+	aead := newAEAD(key)
+	out := make([]byte, headerSize+aead.NonceSize(), headerSize+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out[0] = version
+	binary.BigEndian.PutUint64(out[1:headerSize], period)
+	if _, err = io.ReadFull(utils.Rand(), out[headerSize:]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out[headerSize:], plaintext, out[:headerSize]), nil
+}
+
+// Open decrypts a ciphertext produced by Seal with the key of its tagged period.
+func Open(ks KeySource, ciphertext []byte) (period uint64, plaintext []byte, err error) {
+	period, err = Period(ciphertext)
+	if err != nil {
+		return 0, nil, err
+	}
+	key, err := ks.Key(period)
+	if err != nil {
+		return period, nil, err
+	}
+	aead := newAEAD(key)
+	if len(ciphertext) < headerSize+aead.NonceSize()+aead.Overhead() {
+		return period, nil, InvalidCiphertextError{}
+	}
+	nonce := ciphertext[headerSize : headerSize+aead.NonceSize()]
+	plaintext, err = aead.Open(nil, nonce, ciphertext[headerSize+aead.NonceSize():], ciphertext[:headerSize])
+	if err != nil {
+		return period, nil, DecryptError{Period: period}
+	}
+	return period, plaintext, nil
+}
+
+// Period returns the period tag of a ciphertext without decrypting it.
+func Period(ciphertext []byte) (uint64, error) {
+	if len(ciphertext) < headerSize || ciphertext[0] != version {
+		return 0, InvalidCiphertextError{}
+	}
+	return binary.BigEndian.Uint64(ciphertext[1:headerSize]), nil
+}
+
+// expand derives a KeySize key from prk with info made of the context, label and period.
+func expand(prk []byte, context, label string, period uint64) ([]byte, error) {
+	info := make([]byte, 0, len(context)+len(label)+10)
+	info = append(info, context...)
+	info = append(info, 0)
+	info = append(info, label...)
+	info = append(info, 0)
+	info = binary.BigEndian.AppendUint64(info, period)
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// newAEAD returns AES-256-GCM for a KeySize key.
+func newAEAD(key []byte) cipher.AEAD {
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
@@ -0,0 +1,97 @@
+package logkey
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var master = bytes.Repeat([]byte{0x5a}, 32)
+
+func TestPeriods(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 13, 45, 0, 0, time.UTC)
+	assert.Equal(t, uint64(20742), Daily(ts))
+	assert.Equal(t, Daily(ts), Daily(ts.Add(10*time.Hour)))
+	assert.Equal(t, Daily(ts)+1, Daily(ts.Add(11*time.Hour)))
+	assert.Equal(t, uint64(20742*24+13), Hourly(ts))
+	assert.Equal(t, Daily(ts), Daily(ts.In(time.FixedZone("CST", 8*3600))))
+}
+
+func TestSchedule(t *testing.T) {
+	s := NewSchedule(master, "access")
+	require.NoError(t, s.Error)
+
+	k1, err := s.Key(1)
+	require.NoError(t, err)
+	assert.Len(t, k1, KeySize)
+	again, err := s.Key(1)
+	require.NoError(t, err)
+	assert.Equal(t, k1, again)
+	k2, err := s.Key(2)
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k2)
+
+	other, err := NewSchedule(master, "audit").Key(1)
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, other)
+
+	t.Run("short master", func(t *testing.T) {
+		s := NewSchedule(master[:16], "access")
+		assert.Equal(t, InvalidKeyError{Size: 16}, s.Error)
+		assert.Equal(t, "crypto/logkey: invalid key size 16, must be at least 32 bytes", s.Error.Error())
+		_, err := s.Key(1)
+		assert.Equal(t, s.Error, err)
+	})
+}
+
+func TestSealOpen(t *testing.T) {
+	s := NewSchedule(master, "access")
+	line := []byte(`{"level":"info","msg":"user login"}`)
+
+	ct, err := Seal(s, 20742, line)
+	require.NoError(t, err)
+	period, err := Period(ct)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20742), period)
+
+	period, pt, err := Open(s, ct)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20742), period)
+	assert.Equal(t, line, pt)
+
+	t.Run("tampered period", func(t *testing.T) {
+		bad := append([]byte{}, ct...)
+		bad[8] ^= 1
+		period, _, err := Open(s, bad)
+		assert.Equal(t, DecryptError{Period: 20743}, err)
+		assert.Equal(t, "crypto/logkey: failed to decrypt ciphertext of period 20743", err.Error())
+		assert.Equal(t, uint64(20743), period)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, _, err := Open(NewSchedule(master, "audit"), ct)
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("invalid ciphertext", func(t *testing.T) {
+		_, err := Period(ct[:8])
+		assert.Equal(t, InvalidCiphertextError{}, err)
+		assert.Equal(t, "crypto/logkey: invalid ciphertext", err.Error())
+		bad := append([]byte{2}, ct[1:]...)
+		_, _, err = Open(s, bad)
+		assert.Equal(t, InvalidCiphertextError{}, err)
+		_, _, err = Open(s, ct[:20])
+		assert.Equal(t, InvalidCiphertextError{}, err)
+	})
+
+	t.Run("key errors", func(t *testing.T) {
+		bad := NewSchedule(nil, "")
+		_, err := Seal(bad, 1, line)
+		assert.Equal(t, bad.Error, err)
+		_, _, err = Open(bad, ct)
+		assert.Equal(t, bad.Error, err)
+	})
+}
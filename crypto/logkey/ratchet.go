@@ -0,0 +1,136 @@
+package logkey
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/hkdf"
+)
+
+// DefaultMaxAhead is the default number of periods past its current period a
+// Ratchet derives keys for.
+const DefaultMaxAhead = 1 << 16
+
+// Ratchet derives period keys from a one-way chain. Each period has a chain key,
+// the next chain key is derived from the current one and the period key is derived
+// beside it. Advancing the ratchet wipes the earlier chain keys, so the keys of past
+// periods can no longer be derived even if the ratchet state later leaks.
+// It is safe for concurrent use.
+type Ratchet struct {
+	mu     sync.Mutex
+	label  string
+	period uint64 // Earliest period the ratchet can derive
+	chain  []byte // Chain key of period
+	// MaxAhead bounds how many periods past Period the ratchet derives keys
+	// for, DefaultMaxAhead if zero. Open reads the period from the unauthenticated
+	// ciphertext header, and each period ahead costs a chain step under the lock.
+	MaxAhead uint64
+	Error    error // Error field for storing key errors
+}
+
+// NewRatchet returns a new Ratchet seeded for the start period.
+func NewRatchet(seed []byte, label string, start uint64) *Ratchet {
+	r := &Ratchet{label: label, period: start, MaxAhead: DefaultMaxAhead}
+	if len(seed) < MinKeySize {
+		r.Error = InvalidKeyError{Size: len(seed)}
+		return r
+	}
+	r.chain, r.Error = expand(hkdf.Extract(sha256.New, seed, nil), "dongle/logkey/chain", label, start)
+	return r
+}
+
+// Period returns the earliest period the ratchet can still derive.
+func (r *Ratchet) Period() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.period
+}
+
+// Key returns the key of period, which must not be earlier than Period nor
+// more than MaxAhead periods later. Deriving a later key does not advance the
+// ratchet.
+func (r *Ratchet) Key(period uint64) ([]byte, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if period < r.period {
+		return nil, ExpiredPeriodError{Period: period, Current: r.period}
+	}
+	ahead := r.MaxAhead
+	if ahead == 0 {
+		ahead = DefaultMaxAhead
+	}
+	if period-r.period > ahead {
+		return nil, FuturePeriodError{Period: period, Current: r.period, MaxAhead: ahead}
+	}
+	chain := append([]byte{}, r.chain...)
+	defer func() { utils.SecureWipe(chain) }()
+	for p := r.period; p < period; p++ {
+		next, err := r.next(chain, p)
+		if err != nil {
+			return nil, err
+		}
+		utils.SecureWipe(chain)
+		chain = next
+	}
+	return expand(chain, "dongle/logkey/ratchet", r.label, period)
+}
+
+// Advance moves the ratchet to period and wipes the chain keys of earlier periods.
+// Advancing to the current or an earlier period is a no-op.
+func (r *Ratchet) Advance(period uint64) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.period < period {
+		next, err := r.next(r.chain, r.period)
+		if err != nil {
+			return err
+		}
+		utils.SecureWipe(r.chain)
+		r.chain = next
+		r.period++
+	}
+	return nil
+}
+
+// MarshalBinary encodes the ratchet state: its current period, chain key and label.
+// The state is secret, anyone holding it can derive the keys of the current and later periods.
+func (r *Ratchet) MarshalBinary() ([]byte, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]byte, 0, 1+8+KeySize+len(r.label))
+	out = append(out, version)
+	out = binary.BigEndian.AppendUint64(out, r.period)
+	out = append(out, r.chain...)
+	return append(out, r.label...), nil
+}
+
+// UnmarshalBinary restores a ratchet state encoded by MarshalBinary.
+func (r *Ratchet) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+8+KeySize || data[0] != version {
+		return InvalidStateError{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	utils.SecureWipe(r.chain)
+	r.period = binary.BigEndian.Uint64(data[1:9])
+	r.chain = append([]byte{}, data[9:9+KeySize]...)
+	r.label = string(data[9+KeySize:])
+	r.Error = nil
+	return nil
+}
+
+// next derives the chain key that follows chain at period.
+func (r *Ratchet) next(chain []byte, period uint64) ([]byte, error) {
+	return expand(chain, "dongle/logkey/next", r.label, period)
+}
@@ -0,0 +1,123 @@
+package logkey
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatchet(t *testing.T) {
+	r := NewRatchet(master, "access", 100)
+	require.NoError(t, r.Error)
+	assert.Equal(t, uint64(100), r.Period())
+
+	k100, err := r.Key(100)
+	require.NoError(t, err)
+	k105, err := r.Key(105)
+	require.NoError(t, err)
+	assert.NotEqual(t, k100, k105)
+	assert.Equal(t, uint64(100), r.Period())
+
+	// The chain does not depend on how the ratchet got to a period.
+	require.NoError(t, r.Advance(103))
+	assert.Equal(t, uint64(103), r.Period())
+	again, err := r.Key(105)
+	require.NoError(t, err)
+	assert.Equal(t, k105, again)
+
+	_, err = r.Key(100)
+	assert.Equal(t, ExpiredPeriodError{Period: 100, Current: 103}, err)
+	assert.Equal(t, "crypto/logkey: key for period 100 was discarded, ratchet is at period 103", err.Error())
+
+	require.NoError(t, r.Advance(101))
+	assert.Equal(t, uint64(103), r.Period())
+
+	t.Run("independent of schedule", func(t *testing.T) {
+		k, err := NewSchedule(master, "access").Key(105)
+		require.NoError(t, err)
+		assert.NotEqual(t, k105, k)
+	})
+
+	t.Run("short seed", func(t *testing.T) {
+		r := NewRatchet(master[:31], "access", 0)
+		assert.Equal(t, InvalidKeyError{Size: 31}, r.Error)
+		_, err := r.Key(0)
+		assert.Equal(t, r.Error, err)
+		assert.Equal(t, r.Error, r.Advance(1))
+		_, err = r.MarshalBinary()
+		assert.Equal(t, r.Error, err)
+	})
+}
+
+func TestRatchet_SealOpen(t *testing.T) {
+	writer := NewRatchet(master, "access", 0)
+	ct, err := Seal(writer, 2, []byte("day two"))
+	require.NoError(t, err)
+
+	reader := NewRatchet(master, "access", 0)
+	_, pt, err := Open(reader, ct)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("day two"), pt)
+
+	require.NoError(t, reader.Advance(3))
+	_, _, err = Open(reader, ct)
+	assert.Equal(t, ExpiredPeriodError{Period: 2, Current: 3}, err)
+
+	t.Run("far future period", func(t *testing.T) {
+		forged := append([]byte{}, ct...)
+		binary.BigEndian.PutUint64(forged[1:headerSize], 1<<63)
+		_, _, err := Open(reader, forged)
+		assert.Equal(t, FuturePeriodError{Period: 1 << 63, Current: 3, MaxAhead: DefaultMaxAhead}, err)
+		assert.Equal(t, "crypto/logkey: period 9223372036854775808 is more than 65536 periods ahead of period 3", err.Error())
+
+		reader.MaxAhead = 10
+		_, err = reader.Key(13)
+		assert.NoError(t, err)
+		_, err = reader.Key(14)
+		assert.Equal(t, FuturePeriodError{Period: 14, Current: 3, MaxAhead: 10}, err)
+	})
+}
+
+func TestRatchet_Marshal(t *testing.T) {
+	r := NewRatchet(master, "access", 7)
+	require.NoError(t, r.Advance(9))
+	state, err := r.MarshalBinary()
+	require.NoError(t, err)
+
+	restored := &Ratchet{}
+	require.NoError(t, restored.UnmarshalBinary(state))
+	assert.Equal(t, uint64(9), restored.Period())
+	a, err := r.Key(12)
+	require.NoError(t, err)
+	b, err := restored.Key(12)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	bad := NewRatchet(nil, "", 0)
+	require.NoError(t, bad.UnmarshalBinary(state))
+	assert.NoError(t, bad.Error)
+
+	assert.Equal(t, InvalidStateError{}, restored.UnmarshalBinary(state[:40]))
+	state[0] = 2
+	err = restored.UnmarshalBinary(state)
+	assert.Equal(t, InvalidStateError{}, err)
+	assert.Equal(t, "crypto/logkey: invalid ratchet state", err.Error())
+}
+
+func TestRatchet_Concurrent(t *testing.T) {
+	r := NewRatchet(master, "access", 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _ = r.Key(uint64(10 + i))
+			assert.NoError(t, r.Advance(uint64(i)))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(7), r.Period())
+}
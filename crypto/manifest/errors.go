@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedDigestError represents an error when a manifest uses a digest
+// algorithm that is not supported.
+type UnsupportedDigestError struct {
+	Algorithm string // The unsupported digest algorithm name
+}
+
+// Error returns a formatted error message describing the unsupported digest.
+func (e UnsupportedDigestError) Error() string {
+	return fmt.Sprintf("crypto/manifest: unsupported digest algorithm %q", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-MANIFEST-001.
+func (e UnsupportedDigestError) Code() string {
+	return "DGL-MANIFEST-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedDigestError) Fields() map[string]any {
+	return errcode.NewFields("crypto/manifest", "Manifest", "", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// InvalidPathError represents an error when a path is not a valid, unrooted
+// slash-separated path as accepted by io/fs, or is listed twice.
+type InvalidPathError struct {
+	Path string // The rejected path
+}
+
+// Error returns a formatted error message describing the invalid path.
+func (e InvalidPathError) Error() string {
+	return fmt.Sprintf("crypto/manifest: invalid or duplicate path %q", e.Path)
+}
+
+// Code returns the stable error code DGL-MANIFEST-002.
+func (e InvalidPathError) Code() string {
+	return "DGL-MANIFEST-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPathError) Fields() map[string]any {
+	return errcode.NewFields("crypto/manifest", "Manifest", "", "path", e.Path)
+}
+
+// InvalidManifestError represents an error when a signed manifest cannot be
+// parsed or its signature does not verify.
+type InvalidManifestError struct {
+	Err error // Underlying error from parsing or verification
+}
+
+// Error returns a formatted error message describing the invalid manifest.
+func (e InvalidManifestError) Error() string {
+	return fmt.Sprintf("crypto/manifest: invalid manifest: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-MANIFEST-003.
+func (e InvalidManifestError) Code() string {
+	return "DGL-MANIFEST-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidManifestError) Fields() map[string]any {
+	return errcode.NewFields("crypto/manifest", "Manifest", "open", errcode.FieldCause, e.Err)
+}
+
+// MismatchError represents an error when a file tree does not match its
+// manifest. The paths are sorted.
+type MismatchError struct {
+	Modified   []string // Files whose size or digest differs
+	Missing    []string // Files in the manifest but not in the tree
+	Unexpected []string // Files in the tree but not in the manifest
+}
+
+// Error returns a formatted error message summarizing the differences.
+func (e MismatchError) Error() string {
+	var parts []string
+	if len(e.Modified) > 0 {
+		parts = append(parts, fmt.Sprintf("modified %s", strings.Join(e.Modified, ", ")))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing %s", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Unexpected) > 0 {
+		parts = append(parts, fmt.Sprintf("unexpected %s", strings.Join(e.Unexpected, ", ")))
+	}
+	return fmt.Sprintf("crypto/manifest: file tree does not match manifest: %s", strings.Join(parts, "; "))
+}
+
+// Code returns the stable error code DGL-MANIFEST-004.
+func (e MismatchError) Code() string {
+	return "DGL-MANIFEST-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/manifest", "Manifest", "verify", "modified", len(e.Modified), "missing", len(e.Missing), "unexpected", len(e.Unexpected))
+}
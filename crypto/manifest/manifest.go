@@ -0,0 +1,228 @@
+// Package manifest records the paths, sizes and digests of a set of files in a
+// signed manifest and verifies file trees against it, in the spirit of in-toto
+// link metadata. Manifests are canonicalized with RFC 8785 and signed as JSON
+// Web Signatures, so the signature is detached from the files it covers and can
+// be shipped next to a release archive or directory.
+package manifest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dromara/dongle/coding/jcs"
+	"github.com/dromara/dongle/crypto/jws"
+	"github.com/dromara/dongle/hash"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Version is the manifest format version written by this package.
+const Version = 1
+
+// The supported digest algorithms.
+const (
+	SHA256 = "sha256"
+	SHA384 = "sha384"
+	SHA512 = "sha512"
+	SM3    = "sm3"
+)
+
+// File is a file recorded in a manifest.
+type File struct {
+	Path   string `json:"path"`   // Slash-separated path relative to the tree root
+	Size   int64  `json:"size"`   // Size in bytes
+	Digest string `json:"digest"` // Hex encoded digest of the content
+}
+
+// Manifest lists files with their sizes and digests.
+type Manifest struct {
+	Version   int       `json:"version"`   // Format version, see Version
+	Algorithm string    `json:"algorithm"` // Digest algorithm, e.g. SHA256
+	Created   time.Time `json:"created"`   // When the manifest was created
+	Files     []File    `json:"files"`     // Files sorted by path
+}
+
+// New returns an empty manifest using the given digest algorithm.
+func New(algorithm string) *Manifest {
+	return &Manifest{Version: Version, Algorithm: algorithm, Created: utils.Now().UTC().Truncate(time.Second)}
+}
+
+// Create returns a SHA-256 manifest of the named files in fsys, or of every
+// regular file in fsys when no paths are given.
+func Create(fsys fs.FS, paths ...string) (*Manifest, error) {
+	m := New(SHA256)
+	if err := m.Add(fsys, paths...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Add hashes the named files in fsys, or every regular file in fsys when no
+// paths are given, and records them in the manifest.
+func (m *Manifest) Add(fsys fs.FS, paths ...string) error {
+	if len(paths) == 0 {
+		var err error
+		if paths, err = walk(fsys); err != nil {
+			return err
+		}
+	}
+	for _, path := range paths {
+		if !fs.ValidPath(path) || path == "." || m.index(path) >= 0 {
+			return InvalidPathError{Path: path}
+		}
+		f, err := m.hash(fsys, path)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, f)
+	}
+	slices.SortFunc(m.Files, func(a, b File) int { return strings.Compare(a.Path, b.Path) })
+	return nil
+}
+
+// Sign returns the manifest signed by each of the signers, in the general JWS
+// JSON serialization with the canonical manifest as payload.
+func (m *Manifest) Sign(signers ...jws.Signer) ([]byte, error) {
+	payload, err := jcs.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	msg := &jws.Message{Payload: payload}
+	for _, s := range signers {
+		if err := msg.Sign(s); err != nil {
+			return nil, err
+		}
+	}
+	return msg.MarshalJSON()
+}
+
+// Open verifies a signed manifest produced by Sign with key and returns the
+// manifest. The signature only covers the manifest; call Verify or VerifyTree
+// to check the files themselves.
+func Open(data []byte, key any) (*Manifest, error) {
+	msg, err := jws.ParseJSON(data)
+	if err != nil {
+		return nil, InvalidManifestError{Err: err}
+	}
+	if _, err = msg.Verify(key); err != nil {
+		return nil, InvalidManifestError{Err: err}
+	}
+	m := new(Manifest)
+	if err = json.Unmarshal(msg.Payload, m); err != nil {
+		return nil, InvalidManifestError{Err: err}
+	}
+	if m.Version != Version {
+		return nil, InvalidManifestError{Err: errors.New("unsupported version")}
+	}
+	return m, nil
+}
+
+// Verify checks that every file in the manifest exists in fsys with the
+// recorded size and digest. Files in fsys that are not listed are ignored.
+func (m *Manifest) Verify(fsys fs.FS) error {
+	return m.verify(fsys, false)
+}
+
+// VerifyTree is like Verify but also rejects regular files in fsys that are
+// not listed in the manifest.
+func (m *Manifest) VerifyTree(fsys fs.FS) error {
+	return m.verify(fsys, true)
+}
+
+// verify compares the files in fsys with the manifest, collecting every
+// difference into a MismatchError.
+func (m *Manifest) verify(fsys fs.FS, strict bool) error {
+	var mismatch MismatchError
+	for _, want := range m.Files {
+		if !fs.ValidPath(want.Path) || want.Path == "." {
+			return InvalidPathError{Path: want.Path}
+		}
+		got, err := m.hash(fsys, want.Path)
+		var pathErr InvalidPathError
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			mismatch.Missing = append(mismatch.Missing, want.Path)
+		case errors.As(err, &pathErr):
+			// The path exists but is no longer a regular file.
+			mismatch.Modified = append(mismatch.Modified, want.Path)
+		case err != nil:
+			return err
+		case got != want:
+			mismatch.Modified = append(mismatch.Modified, want.Path)
+		}
+	}
+	if strict {
+		paths, err := walk(fsys)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if m.index(path) < 0 {
+				mismatch.Unexpected = append(mismatch.Unexpected, path)
+			}
+		}
+	}
+	if len(mismatch.Modified)+len(mismatch.Missing)+len(mismatch.Unexpected) > 0 {
+		return mismatch
+	}
+	return nil
+}
+
+// hash returns the manifest entry of the regular file at path.
+func (m *Manifest) hash(fsys fs.FS, path string) (File, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return File{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return File{}, InvalidPathError{Path: path}
+	}
+	var hasher hash.Hasher
+	switch m.Algorithm {
+	case SHA256:
+		hasher = hash.NewHasher().FromFile(file).BySha2(256)
+	case SHA384:
+		hasher = hash.NewHasher().FromFile(file).BySha2(384)
+	case SHA512:
+		hasher = hash.NewHasher().FromFile(file).BySha2(512)
+	case SM3:
+		hasher = hash.NewHasher().FromFile(file).BySm3()
+	default:
+		return File{}, UnsupportedDigestError{Algorithm: m.Algorithm}
+	}
+	if hasher.Error != nil {
+		return File{}, hasher.Error
+	}
+	// Hashers yield no digest for empty input, record the empty string so that
+	// empty files still round trip.
+	return File{Path: path, Size: info.Size(), Digest: hex.EncodeToString(hasher.ToRawBytes())}, nil
+}
+
+// index returns the position of path in the manifest, or -1.
+func (m *Manifest) index(path string) int {
+	return slices.IndexFunc(m.Files, func(f File) bool { return f.Path == path })
+}
+
+// walk returns the paths of all regular files in fsys.
+func walk(fsys fs.FS) ([]string, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
@@ -0,0 +1,159 @@
+package manifest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dromara/dongle/crypto/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTree() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md":      {Data: []byte("hello world")},
+		"bin/app":        {Data: []byte("binary"), Mode: 0o755},
+		"bin/empty":      {Data: []byte{}},
+		"docs/guide.txt": {Data: []byte("guide")},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("all files", func(t *testing.T) {
+		m, err := Create(newTree())
+		require.NoError(t, err)
+		assert.Equal(t, Version, m.Version)
+		assert.Equal(t, SHA256, m.Algorithm)
+		assert.False(t, m.Created.IsZero())
+		require.Len(t, m.Files, 4)
+		assert.Equal(t, File{Path: "README.md", Size: 11, Digest: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}, m.Files[0])
+		assert.Equal(t, "bin/app", m.Files[1].Path)
+		assert.Equal(t, File{Path: "bin/empty"}, m.Files[2])
+		assert.Equal(t, "docs/guide.txt", m.Files[3].Path)
+	})
+
+	t.Run("named files", func(t *testing.T) {
+		m, err := Create(newTree(), "docs/guide.txt", "README.md")
+		require.NoError(t, err)
+		require.Len(t, m.Files, 2)
+		assert.Equal(t, "README.md", m.Files[0].Path)
+		assert.Equal(t, "docs/guide.txt", m.Files[1].Path)
+	})
+
+	t.Run("other digests", func(t *testing.T) {
+		m := New(SM3)
+		require.NoError(t, m.Add(newTree(), "README.md"))
+		assert.Len(t, m.Files[0].Digest, 64)
+		m = New(SHA512)
+		require.NoError(t, m.Add(newTree(), "README.md"))
+		assert.Len(t, m.Files[0].Digest, 128)
+		m = New("md5")
+		assert.Equal(t, UnsupportedDigestError{Algorithm: "md5"}, m.Add(newTree(), "README.md"))
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		for _, path := range []string{"/README.md", "../README.md", ".", "bin"} {
+			_, err := Create(newTree(), path)
+			assert.Equal(t, InvalidPathError{Path: path}, err, path)
+		}
+		_, err := Create(newTree(), "README.md", "README.md")
+		assert.Equal(t, InvalidPathError{Path: "README.md"}, err)
+		_, err = Create(newTree(), "nope")
+		assert.Error(t, err)
+	})
+}
+
+func TestSignOpen(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	m, err := Create(newTree())
+	require.NoError(t, err)
+
+	data, err := m.Sign(jws.Signer{Algorithm: jws.EdDSA, Key: priv, KeyID: "release"})
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := Open(data, pub)
+		require.NoError(t, err)
+		assert.Equal(t, m.Files, got.Files)
+		assert.True(t, m.Created.Equal(got.Created))
+		assert.NoError(t, got.VerifyTree(newTree()))
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, _, _ := ed25519.GenerateKey(rand.Reader)
+		_, err := Open(data, other)
+		assert.IsType(t, InvalidManifestError{}, err)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		msg, err := jws.ParseJSON(data)
+		require.NoError(t, err)
+		msg.Payload = []byte(`{"version":1,"algorithm":"sha256","created":"2025-01-01T00:00:00Z","files":[]}`)
+		tampered, err := msg.MarshalJSON()
+		require.NoError(t, err)
+		_, err = Open(tampered, pub)
+		assert.IsType(t, InvalidManifestError{}, err)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		future := *m
+		future.Version = 2
+		signed, err := future.Sign(jws.Signer{Algorithm: jws.EdDSA, Key: priv})
+		require.NoError(t, err)
+		_, err = Open(signed, pub)
+		assert.IsType(t, InvalidManifestError{}, err)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := Open([]byte("{"), pub)
+		assert.IsType(t, InvalidManifestError{}, err)
+	})
+
+	t.Run("canonical payload", func(t *testing.T) {
+		msg, err := jws.ParseJSON(data)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(msg.Payload), `{"algorithm":"sha256","created":`))
+	})
+}
+
+func TestVerify(t *testing.T) {
+	m, err := Create(newTree())
+	require.NoError(t, err)
+
+	t.Run("unchanged", func(t *testing.T) {
+		assert.NoError(t, m.Verify(newTree()))
+		assert.NoError(t, m.VerifyTree(newTree()))
+	})
+
+	t.Run("differences", func(t *testing.T) {
+		tree := newTree()
+		tree["README.md"] = &fstest.MapFile{Data: []byte("hello World")}
+		tree["bin/empty"] = &fstest.MapFile{Data: []byte("x")}
+		delete(tree, "docs/guide.txt")
+		tree["extra.sh"] = &fstest.MapFile{Data: []byte("#!/bin/sh")}
+
+		err := m.Verify(tree)
+		assert.Equal(t, MismatchError{Modified: []string{"README.md", "bin/empty"}, Missing: []string{"docs/guide.txt"}}, err)
+
+		err = m.VerifyTree(tree)
+		assert.Equal(t, MismatchError{Modified: []string{"README.md", "bin/empty"}, Missing: []string{"docs/guide.txt"}, Unexpected: []string{"extra.sh"}}, err)
+		assert.Equal(t, "crypto/manifest: file tree does not match manifest: modified README.md, bin/empty; missing docs/guide.txt; unexpected extra.sh", err.Error())
+	})
+
+	t.Run("file replaced by directory", func(t *testing.T) {
+		tree := newTree()
+		delete(tree, "README.md")
+		tree["README.md/index"] = &fstest.MapFile{Data: []byte("x")}
+		err := m.Verify(tree)
+		assert.Equal(t, MismatchError{Modified: []string{"README.md"}}, err)
+	})
+
+	t.Run("invalid manifest path", func(t *testing.T) {
+		bad := &Manifest{Version: Version, Algorithm: SHA256, Files: []File{{Path: "../etc/passwd"}}}
+		assert.Equal(t, InvalidPathError{Path: "../etc/passwd"}, bad.Verify(newTree()))
+	})
+}
@@ -0,0 +1,132 @@
+// Package memo memoizes the results of expensive deterministic operations,
+// such as verifying the same RSA signed token on every request or computing
+// identical HMACs when fanning a webhook out to many subscribers. Results are
+// kept in a bounded LRU cache keyed by the algorithm, a fingerprint of the key
+// and a digest of the input, so neither keys nor inputs are retained.
+package memo
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/dromara/dongle/hash/domain"
+)
+
+// DefaultCapacity is the capacity used when a non-positive capacity is given.
+const DefaultCapacity = 1024
+
+// Key identifies an operation by its algorithm, key and input.
+type Key [sha256.Size]byte
+
+// NewKey returns the cache key of running algorithm with key over the input
+// parts, for example the message and the signature of a verification. Every
+// field is length prefixed, so different splits of the same bytes differ.
+func NewKey(algorithm string, key []byte, input ...[]byte) Key {
+	fingerprint := sha256.Sum256(key)
+	h := sha256.New()
+	domain.Write(h, []byte(algorithm))
+	domain.Write(h, fingerprint[:])
+	for _, part := range input {
+		domain.Write(h, part)
+	}
+	var k Key
+	h.Sum(k[:0])
+	return k
+}
+
+// Stats reports how a cache has been used.
+type Stats struct {
+	Hits   uint64 // Lookups answered from the cache
+	Misses uint64 // Lookups that ran the operation
+	Len    int    // Number of cached results
+}
+
+// Cache is a bounded LRU cache of operation results. It is safe for
+// concurrent use. Cached values are shared between callers and must not be
+// modified.
+type Cache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Most recently used first
+	entries  map[Key]*list.Element
+	stats    Stats
+}
+
+// entry is a cached result.
+type entry[V any] struct {
+	key   Key
+	value V
+}
+
+// New returns a cache holding up to capacity results.
+func New[V any](capacity int) *Cache[V] {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Cache[V]{capacity: capacity, order: list.New(), entries: make(map[Key]*list.Element)}
+}
+
+// Do returns the cached result of k, or runs fn and caches its result when it
+// succeeds. Errors are not cached, so failed operations run again. Concurrent
+// misses of the same key may each run fn.
+func (c *Cache[V]) Do(k Key, fn func() (V, error)) (V, error) {
+	if v, ok := c.Get(k); ok {
+		return v, nil
+	}
+	v, err := fn()
+	if err != nil {
+		return v, err
+	}
+	c.Add(k, v)
+	return v, nil
+}
+
+// Get returns the cached result of k.
+func (c *Cache[V]) Get(k Key) (v V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[k]
+	if !ok {
+		c.stats.Misses++
+		return v, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(e)
+	return e.Value.(*entry[V]).value, true
+}
+
+// Add caches v as the result of k, evicting the least recently used result
+// when the cache is full.
+func (c *Cache[V]) Add(k Key, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[k]; ok {
+		e.Value.(*entry[V]).value = v
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[k] = c.order.PushFront(&entry[V]{key: k, value: v})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry[V]).key)
+	}
+}
+
+// Purge removes all cached results, for example after rotating keys.
+func (c *Cache[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+// Stats returns the usage statistics of the cache.
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Len = c.order.Len()
+	return s
+}
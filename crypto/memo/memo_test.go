@@ -0,0 +1,99 @@
+package memo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dromara/dongle/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKey(t *testing.T) {
+	k := NewKey("HMAC-SHA256", []byte("key"), []byte("payload"))
+	assert.Equal(t, k, NewKey("HMAC-SHA256", []byte("key"), []byte("payload")))
+	assert.NotEqual(t, k, NewKey("HMAC-SHA512", []byte("key"), []byte("payload")))
+	assert.NotEqual(t, k, NewKey("HMAC-SHA256", []byte("other"), []byte("payload")))
+	assert.NotEqual(t, k, NewKey("HMAC-SHA256", []byte("key"), []byte("payload!")))
+	assert.NotEqual(t, NewKey("RS256", nil, []byte("ab"), []byte("c")), NewKey("RS256", nil, []byte("a"), []byte("bc")))
+}
+
+func TestCache(t *testing.T) {
+	t.Run("memoizes hmac", func(t *testing.T) {
+		c := New[[]byte](8)
+		key, payload := []byte("webhook secret"), []byte(`{"event":"paid"}`)
+		calls := 0
+		mac := func() ([]byte, error) {
+			calls++
+			h := hash.NewHasher().FromBytes(payload).WithKey(key).BySha2(256)
+			return h.ToRawBytes(), h.Error
+		}
+		k := NewKey("HMAC-SHA256", key, payload)
+		for i := 0; i < 5; i++ {
+			v, err := c.Do(k, mac)
+			require.NoError(t, err)
+			assert.Len(t, v, 32)
+		}
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, Stats{Hits: 4, Misses: 1, Len: 1}, c.Stats())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		c := New[bool](8)
+		k := NewKey("RS256", nil, []byte("token"))
+		_, err := c.Do(k, func() (bool, error) { return false, errors.New("boom") })
+		assert.EqualError(t, err, "boom")
+		v, err := c.Do(k, func() (bool, error) { return true, nil })
+		require.NoError(t, err)
+		assert.True(t, v)
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		c := New[int](2)
+		a, b, d := NewKey("a", nil), NewKey("b", nil), NewKey("d", nil)
+		c.Add(a, 1)
+		c.Add(b, 2)
+		c.Get(a)
+		c.Add(d, 3)
+		_, ok := c.Get(b)
+		assert.False(t, ok)
+		v, ok := c.Get(a)
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+		c.Add(a, 4)
+		v, _ = c.Get(a)
+		assert.Equal(t, 4, v)
+		assert.Equal(t, 2, c.Stats().Len)
+
+		c.Purge()
+		assert.Zero(t, c.Stats().Len)
+		_, ok = c.Get(a)
+		assert.False(t, ok)
+	})
+
+	t.Run("default capacity", func(t *testing.T) {
+		c := New[int](0)
+		for i := 0; i < DefaultCapacity+10; i++ {
+			c.Add(NewKey(fmt.Sprint(i), nil), i)
+		}
+		assert.Equal(t, DefaultCapacity, c.Stats().Len)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		c := New[int](16)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					c.Do(NewKey("op", nil, []byte{byte(j % 32)}), func() (int, error) { return j % 32, nil })
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, uint64(800), c.Stats().Hits+c.Stats().Misses)
+	})
+}
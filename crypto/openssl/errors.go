@@ -0,0 +1,105 @@
+package openssl
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedCipherError represents an error when a cipher name is not supported.
+type UnsupportedCipherError struct {
+	Cipher Cipher // The unsupported cipher name
+}
+
+// Error returns a formatted error message describing the unsupported cipher.
+func (e UnsupportedCipherError) Error() string {
+	return fmt.Sprintf("crypto/openssl: unsupported cipher %q", string(e.Cipher))
+}
+
+// Code returns the stable error code DGL-OPENSSL-001.
+func (e UnsupportedCipherError) Code() string {
+	return "DGL-OPENSSL-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedCipherError) Fields() map[string]any {
+	return errcode.NewFields("crypto/openssl", "", "", "cipher", e.Cipher)
+}
+
+// UnsupportedDigestError represents an error when a key derivation digest is not available.
+type UnsupportedDigestError struct {
+	Digest crypto.Hash // The unavailable digest
+}
+
+// Error returns a formatted error message describing the unsupported digest.
+func (e UnsupportedDigestError) Error() string {
+	return fmt.Sprintf("crypto/openssl: unsupported digest %s", e.Digest)
+}
+
+// Code returns the stable error code DGL-OPENSSL-002.
+func (e UnsupportedDigestError) Code() string {
+	return "DGL-OPENSSL-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedDigestError) Fields() map[string]any {
+	return errcode.NewFields("crypto/openssl", "", "", "digest", e.Digest)
+}
+
+// InvalidSaltError represents an error when a salt is not 8 bytes long.
+type InvalidSaltError struct {
+	Size int // The invalid salt size
+}
+
+// Error returns a formatted error message describing the invalid salt.
+func (e InvalidSaltError) Error() string {
+	return fmt.Sprintf("crypto/openssl: invalid salt size %d, must be 8 bytes", e.Size)
+}
+
+// Code returns the stable error code DGL-OPENSSL-003.
+func (e InvalidSaltError) Code() string {
+	return "DGL-OPENSSL-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSaltError) Fields() map[string]any {
+	return errcode.NewFields("crypto/openssl", "", "", "size", e.Size)
+}
+
+// InvalidHeaderError represents an error when data does not start with the "Salted__" header.
+type InvalidHeaderError struct{}
+
+// Error returns a formatted error message describing the missing header.
+func (e InvalidHeaderError) Error() string {
+	return `crypto/openssl: missing "Salted__" header`
+}
+
+// Code returns the stable error code DGL-OPENSSL-004.
+func (e InvalidHeaderError) Code() string {
+	return "DGL-OPENSSL-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidHeaderError) Fields() map[string]any {
+	return errcode.NewFields("crypto/openssl", "", "")
+}
+
+// DecryptError represents an error when decryption fails, usually because of a
+// wrong password, digest or iteration count.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return "crypto/openssl: bad decrypt, wrong password or key derivation options"
+}
+
+// Code returns the stable error code DGL-OPENSSL-005.
+func (e DecryptError) Code() string {
+	return "DGL-OPENSSL-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/openssl", "", "decrypt")
+}
@@ -0,0 +1,259 @@
+// Package openssl reads and writes the output of the "openssl enc" command: the
+// "Salted__" magic, an 8-byte salt and the ciphertext, with the key and IV
+// derived from a password by EVP_BytesToKey or, with -pbkdf2, by PBKDF2. It lets
+// files and strings be exchanged with shell scripts built on the OpenSSL CLI.
+//
+// Note that EVP_BytesToKey is a weak key derivation function. Prefer PBKDF2 with a
+// high iteration count when both sides support it.
+package openssl
+
+import (
+	"crypto"
+	_ "crypto/md5"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"io"
+
+	tripledes "github.com/dromara/dongle/crypto/3des"
+	"github.com/dromara/dongle/crypto/aes"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/padding"
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Cipher is an "openssl enc" cipher name.
+type Cipher string
+
+// The supported ciphers.
+const (
+	AES128CBC  Cipher = "aes-128-cbc"
+	AES192CBC  Cipher = "aes-192-cbc"
+	AES256CBC  Cipher = "aes-256-cbc"
+	AES128ECB  Cipher = "aes-128-ecb"
+	AES192ECB  Cipher = "aes-192-ecb"
+	AES256ECB  Cipher = "aes-256-ecb"
+	AES128CTR  Cipher = "aes-128-ctr"
+	AES192CTR  Cipher = "aes-192-ctr"
+	AES256CTR  Cipher = "aes-256-ctr"
+	AES128CFB  Cipher = "aes-128-cfb"
+	AES192CFB  Cipher = "aes-192-cfb"
+	AES256CFB  Cipher = "aes-256-cfb"
+	AES128OFB  Cipher = "aes-128-ofb"
+	AES192OFB  Cipher = "aes-192-ofb"
+	AES256OFB  Cipher = "aes-256-ofb"
+	DESEDE3CBC Cipher = "des-ede3-cbc"
+)
+
+// Magic is the header "openssl enc" writes before the salt.
+const Magic = "Salted__"
+
+// SaltSize is the size of the salt following the magic.
+const SaltSize = 8
+
+// DefaultIterations is the PBKDF2 iteration count of "openssl enc -pbkdf2".
+const DefaultIterations = 10000
+
+// Options select the cipher and key derivation, mirroring the "openssl enc"
+// flags. The zero value matches "openssl enc -aes-256-cbc" of OpenSSL 1.1.0 and
+// later, which derives the key with EVP_BytesToKey and SHA-256.
+type Options struct {
+	Cipher     Cipher      // Cipher name, AES256CBC when empty
+	Digest     crypto.Hash // Digest of -md, SHA-256 when zero; use MD5 for OpenSSL 1.0.x
+	PBKDF2     bool        // Derive the key with PBKDF2 as -pbkdf2 does
+	Iterations int         // PBKDF2 iteration count of -iter, DefaultIterations when zero
+	Salt       []byte      // Salt to use when encrypting, random when nil
+}
+
+// spec describes a cipher.
+type spec struct {
+	keySize int
+	mode    cipher.BlockMode
+	des     bool // Triple DES rather than AES
+}
+
+// ciphers maps the supported cipher names to their parameters.
+var ciphers = map[Cipher]spec{
+	AES128CBC:  {16, cipher.CBC, false},
+	AES192CBC:  {24, cipher.CBC, false},
+	AES256CBC:  {32, cipher.CBC, false},
+	AES128ECB:  {16, cipher.ECB, false},
+	AES192ECB:  {24, cipher.ECB, false},
+	AES256ECB:  {32, cipher.ECB, false},
+	AES128CTR:  {16, cipher.CTR, false},
+	AES192CTR:  {24, cipher.CTR, false},
+	AES256CTR:  {32, cipher.CTR, false},
+	AES128CFB:  {16, cipher.CFB, false},
+	AES192CFB:  {24, cipher.CFB, false},
+	AES256CFB:  {32, cipher.CFB, false},
+	AES128OFB:  {16, cipher.OFB, false},
+	AES192OFB:  {24, cipher.OFB, false},
+	AES256OFB:  {32, cipher.OFB, false},
+	DESEDE3CBC: {24, cipher.CBC, true},
+}
+
+// BytesToKey implements OpenSSL's EVP_BytesToKey with an iteration count of one:
+// D_i = H(D_{i-1} || password || salt), concatenated until keyLen+ivLen bytes exist.
+func BytesToKey(h crypto.Hash, password, salt []byte, keyLen, ivLen int) (key, iv []byte, err error) {
+	if !h.Available() {
+		return nil, nil, UnsupportedDigestError{Digest: h}
+	}
+	var out, prev []byte
+	for len(out) < keyLen+ivLen {
+		d := h.New()
+		d.Write(prev)
+		d.Write(password)
+		d.Write(salt)
+		prev = d.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:keyLen], out[keyLen : keyLen+ivLen], nil
+}
+
+// Encrypt encrypts plaintext with password and returns the "Salted__" formatted
+// output of "openssl enc", without base64 encoding.
+func Encrypt(plaintext, password []byte, opts Options) ([]byte, error) {
+	salt := opts.Salt
+	if salt == nil {
+		salt = make([]byte, SaltSize)
+		if _, err := io.ReadFull(utils.Rand(), salt); err != nil {
+			return nil, err
+		}
+	}
+	if len(salt) != SaltSize {
+		return nil, InvalidSaltError{Size: len(salt)}
+	}
+	s, key, iv, err := opts.derive(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.SecureWipe(key)
+	dst, err := s.encrypt(key, iv, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(Magic)+SaltSize+len(dst))
+	out = append(append(out, Magic...), salt...)
+	return append(out, dst...), nil
+}
+
+// Decrypt decrypts the "Salted__" formatted output of "openssl enc", after any
+// base64 decoding, with password. The options must match those used to encrypt,
+// apart from Salt which is read from the data.
+func Decrypt(data, password []byte, opts Options) ([]byte, error) {
+	if len(data) < len(Magic)+SaltSize || string(data[:len(Magic)]) != Magic {
+		return nil, InvalidHeaderError{}
+	}
+	salt, data := data[len(Magic):len(Magic)+SaltSize], data[len(Magic)+SaltSize:]
+	s, key, iv, err := opts.derive(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.SecureWipe(key)
+	// An empty ciphertext in a stream mode holds an empty plaintext
+	if len(data) == 0 && !s.padded() {
+		return []byte{}, nil
+	}
+	dst, err := s.decrypt(key, iv, data)
+	// Padding left in place is malformed, as it is under a wrong key
+	if err != nil || s.padded() && (len(data) == 0 || len(dst) == len(data)) {
+		return nil, DecryptError{}
+	}
+	return dst, nil
+}
+
+// derive derives the key and IV of the cipher from password and salt.
+func (opts Options) derive(password, salt []byte) (s spec, key, iv []byte, err error) {
+	name := opts.Cipher
+	if name == "" {
+		name = AES256CBC
+	}
+	s, ok := ciphers[name]
+	if !ok {
+		return spec{}, nil, nil, UnsupportedCipherError{Cipher: name}
+	}
+	h := opts.Digest
+	if h == 0 {
+		h = crypto.SHA256
+	}
+	if !h.Available() {
+		return spec{}, nil, nil, UnsupportedDigestError{Digest: h}
+	}
+	// The block size is the IV size for every supported mode except ECB, which has none.
+	ivLen := s.blockSize()
+	if s.mode == cipher.ECB {
+		ivLen = 0
+	}
+	if opts.PBKDF2 {
+		iterations := opts.Iterations
+		if iterations <= 0 {
+			iterations = DefaultIterations
+		}
+		out := pbkdf2.Key(password, salt, iterations, s.keySize+ivLen, h.New)
+		key, iv = out[:s.keySize], out[s.keySize:]
+	} else {
+		key, iv, _ = BytesToKey(h, password, salt, s.keySize, ivLen)
+	}
+	return s, key, iv, nil
+}
+
+// blockSize returns the block size of the cipher.
+func (s spec) blockSize() int {
+	if s.des {
+		return 8
+	}
+	return 16
+}
+
+// padded reports whether the mode pads the plaintext, with PKCS7 as
+// "openssl enc" does.
+func (s spec) padded() bool {
+	return s.mode == cipher.CBC || s.mode == cipher.ECB
+}
+
+// encrypt encrypts src under key and iv.
+func (s spec) encrypt(key, iv, src []byte) ([]byte, error) {
+	mode := cipher.No
+	if s.padded() {
+		mode = cipher.PKCS7
+		// The encrypters return nothing for empty input, where "openssl enc"
+		// writes a block of padding
+		if len(src) == 0 {
+			src, _ = padding.Pad(padding.PKCS7, nil, s.blockSize())
+			mode = cipher.No
+		}
+	}
+	if s.des {
+		c := cipher.New3DesCipher(s.mode)
+		c.SetKey(key)
+		c.SetIV(iv)
+		c.SetPadding(mode)
+		return tripledes.NewStdEncrypter(c).Encrypt(src)
+	}
+	c := cipher.NewAesCipher(s.mode)
+	c.SetKey(key)
+	c.SetIV(iv)
+	c.SetPadding(mode)
+	return aes.NewStdEncrypter(c).Encrypt(src)
+}
+
+// decrypt decrypts src under key and iv.
+func (s spec) decrypt(key, iv, src []byte) ([]byte, error) {
+	mode := cipher.No
+	if s.padded() {
+		mode = cipher.PKCS7
+	}
+	if s.des {
+		c := cipher.New3DesCipher(s.mode)
+		c.SetKey(key)
+		c.SetIV(iv)
+		c.SetPadding(mode)
+		return tripledes.NewStdDecrypter(c).Decrypt(src)
+	}
+	c := cipher.NewAesCipher(s.mode)
+	c.SetKey(key)
+	c.SetIV(iv)
+	c.SetPadding(mode)
+	return aes.NewStdDecrypter(c).Decrypt(src)
+}
@@ -0,0 +1,142 @@
+package openssl
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The vectors were produced by OpenSSL 3.0 with
+//
+//	printf 'hello dongle, from openssl enc\n' | openssl enc <flags> -S 0102030405060708 -pass pass:secret
+//
+// with the "Salted__" header prepended, which OpenSSL 3.0 omits when -S is given.
+var (
+	plaintext = []byte("hello dongle, from openssl enc\n")
+	password  = []byte("secret")
+	salt, _   = hex.DecodeString("0102030405060708")
+)
+
+var vectors = []struct {
+	flags string
+	opts  Options
+	data  string
+}{
+	{"-aes-256-cbc -md md5", Options{Digest: crypto.MD5}, "U2FsdGVkX18BAgMEBQYHCNo6LvsqvR3uehrnq4unYmdz243cg1b+3USdSQTsDdFl"},
+	{"-aes-256-cbc -md sha256", Options{}, "U2FsdGVkX18BAgMEBQYHCAY38U0Wqt8twkSrakadlpXCoSMbJ3qruhMOVtRLcxCl"},
+	{"-aes-256-cbc -pbkdf2 -iter 10000", Options{PBKDF2: true, Iterations: 10000}, "U2FsdGVkX18BAgMEBQYHCFgKzv6G0ZGgh6PFAngmKIUvJbUYgWscc6lbfb0WE2Bn"},
+	{"-aes-128-ctr", Options{Cipher: AES128CTR}, "U2FsdGVkX18BAgMEBQYHCBh0VbE0N12tFOPJrmjweJ6aMWEObt7cGKUDlk238To="},
+	{"-des-ede3-cbc -md md5", Options{Cipher: DESEDE3CBC, Digest: crypto.MD5}, "U2FsdGVkX18BAgMEBQYHCI+ypNHtWkEDyriktV2c6IE27AIY5bNXIArbo0XyqUtL"},
+	{"-aes-192-ecb -md sha1", Options{Cipher: AES192ECB, Digest: crypto.SHA1}, "U2FsdGVkX18BAgMEBQYHCJrYsE3/41ntzQpriarh246ovUwUcZsMtwtiK9Z1aUbu"},
+}
+
+func TestDecrypt_OpenSSLVectors(t *testing.T) {
+	for _, v := range vectors {
+		t.Run(v.flags, func(t *testing.T) {
+			data, err := base64.StdEncoding.DecodeString(v.data)
+			require.NoError(t, err)
+			out, err := Decrypt(data, password, v.opts)
+			require.NoError(t, err)
+			assert.Equal(t, plaintext, out)
+		})
+	}
+}
+
+func TestEncrypt_OpenSSLVectors(t *testing.T) {
+	for _, v := range vectors {
+		t.Run(v.flags, func(t *testing.T) {
+			opts := v.opts
+			opts.Salt = salt
+			out, err := Encrypt(plaintext, password, opts)
+			require.NoError(t, err)
+			assert.Equal(t, v.data, base64.StdEncoding.EncodeToString(out))
+		})
+	}
+}
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	for name := range ciphers {
+		for _, size := range []int{0, 1, 15, 16, 17, 100} {
+			msg := make([]byte, size)
+			for i := range msg {
+				msg[i] = byte(i)
+			}
+			opts := Options{Cipher: name, PBKDF2: true, Iterations: 10}
+			out, err := Encrypt(msg, password, opts)
+			require.NoError(t, err, name)
+			assert.Equal(t, Magic, string(out[:len(Magic)]))
+			back, err := Decrypt(out, password, opts)
+			require.NoError(t, err, name)
+			assert.Equal(t, msg, back, name)
+		}
+	}
+
+	t.Run("random salt", func(t *testing.T) {
+		a, err := Encrypt(plaintext, password, Options{})
+		require.NoError(t, err)
+		b, err := Encrypt(plaintext, password, Options{})
+		require.NoError(t, err)
+		assert.NotEqual(t, a[len(Magic):len(Magic)+SaltSize], b[len(Magic):len(Magic)+SaltSize])
+	})
+}
+
+func TestBytesToKey(t *testing.T) {
+	// openssl enc -aes-256-cbc -md md5 -S 0102030405060708 -pass pass:secret -P
+	key, iv, err := BytesToKey(crypto.MD5, password, salt, 32, 16)
+	require.NoError(t, err)
+	assert.Equal(t, "c9e5a1bd216dbe1317e230cef48f38ee7f0e17ad64022144bccec4a1aa2879ab", hex.EncodeToString(key))
+	assert.Equal(t, "e24b32bbbc4ef02ecbcb6576523ad893", hex.EncodeToString(iv))
+
+	_, _, err = BytesToKey(crypto.Hash(0), password, salt, 32, 16)
+	assert.IsType(t, UnsupportedDigestError{}, err)
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(vectors[1].data)
+	require.NoError(t, err)
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := Decrypt(data, []byte("wrong"), Options{})
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("wrong digest", func(t *testing.T) {
+		_, err := Decrypt(data, password, Options{Digest: crypto.MD5})
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		_, err := Decrypt(data[8:], password, Options{})
+		assert.Equal(t, InvalidHeaderError{}, err)
+		_, err = Decrypt([]byte(Magic), password, Options{})
+		assert.Equal(t, InvalidHeaderError{}, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := Decrypt(data[:len(data)-1], password, Options{})
+		assert.IsType(t, DecryptError{}, err)
+		_, err = Decrypt(data[:16], password, Options{})
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("unsupported cipher", func(t *testing.T) {
+		_, err := Decrypt(data, password, Options{Cipher: "bf-cbc"})
+		assert.Equal(t, UnsupportedCipherError{Cipher: "bf-cbc"}, err)
+		assert.Contains(t, err.Error(), "bf-cbc")
+	})
+
+	t.Run("unsupported digest", func(t *testing.T) {
+		_, err := Decrypt(data, password, Options{Digest: crypto.MD4})
+		assert.IsType(t, UnsupportedDigestError{}, err)
+	})
+
+	t.Run("invalid salt", func(t *testing.T) {
+		_, err := Encrypt(plaintext, password, Options{Salt: []byte{1, 2, 3}})
+		assert.Equal(t, InvalidSaltError{Size: 3}, err)
+		assert.Contains(t, err.Error(), "3")
+	})
+}
@@ -0,0 +1,53 @@
+package padding
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedModeError represents an error when a padding mode is unknown.
+type UnsupportedModeError struct {
+	Mode Mode // The unknown padding mode
+}
+
+// Error returns a formatted error message describing the unknown mode.
+func (e UnsupportedModeError) Error() string {
+	return fmt.Sprintf("crypto/padding: unsupported padding mode %q", string(e.Mode))
+}
+
+// Code returns the stable error code DGL-PADDING-001.
+func (e UnsupportedModeError) Code() string {
+	return "DGL-PADDING-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/padding", "", "", "mode", string(e.Mode))
+}
+
+// BlockSizeError represents an error when a block size cannot be used with a
+// padding mode: every mode needs 1 to 255 bytes, and PKCS5Strict needs exactly
+// 8 bytes.
+type BlockSizeError struct {
+	Mode Mode // The padding mode
+	Size int  // The rejected block size in bytes
+}
+
+// Error returns a formatted error message describing the invalid block size.
+func (e BlockSizeError) Error() string {
+	if e.Mode == PKCS5Strict {
+		return fmt.Sprintf("crypto/padding: invalid block size %d for PKCS5Strict padding, must be 8 bytes", e.Size)
+	}
+	return fmt.Sprintf("crypto/padding: invalid block size %d for %s padding, must be between 1 and 255 bytes", e.Size, e.Mode)
+}
+
+// Code returns the stable error code DGL-PADDING-002.
+func (e BlockSizeError) Code() string {
+	return "DGL-PADDING-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BlockSizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/padding", "", "", "mode", string(e.Mode), "block_size", e.Size)
+}
@@ -0,0 +1,149 @@
+// Package padding implements the block cipher padding schemes used by dongle
+// for any block size from 1 to 255 bytes, so that ciphers with 8, 16 or 32 byte
+// blocks share a single implementation:
+//
+//	padded, err := padding.Pad(padding.PKCS7, src, 16)
+//	src, err = padding.Unpad(padding.PKCS7, padded, 16)
+//
+// Unpad is lenient like the cipher layer built on it: data whose padding is
+// malformed is returned unchanged rather than rejected, since a padding error
+// returned after decryption is a padding oracle. Authenticate ciphertexts, or
+// use an AEAD mode, to detect tampering.
+package padding
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Mode defines a padding scheme.
+type Mode string
+
+// Supported padding modes.
+const (
+	No          Mode = "No"          // No padding - data must be exact block size
+	Zero        Mode = "Zero"        // Zero padding - fills with zeros, always adds padding
+	PKCS5       Mode = "PKCS5"       // PKCS5 padding - pads to any block size like PKCS7
+	PKCS5Strict Mode = "PKCS5Strict" // PKCS5 padding - RFC 8018, 8-byte blocks only
+	PKCS7       Mode = "PKCS7"       // PKCS7 padding - RFC 5652, variable block size
+	AnsiX923    Mode = "AnsiX.923"   // ANSI X.923 padding - zeros + length byte
+	ISO97971    Mode = "ISO9797-1"   // ISO/IEC 9797-1 padding method 1
+	ISO10126    Mode = "ISO10126"    // ISO/IEC 10126 padding - random + length byte
+	ISO78164    Mode = "ISO7816-4"   // ISO/IEC 7816-4 padding - same as ISO9797-1
+	Bit         Mode = "Bit"         // Bit padding - 0x80 + zeros
+	TBC         Mode = "TBC"         // TBC padding - 0x00 if last byte MSB=0, else 0xFF
+)
+
+// Modes lists every supported padding mode.
+//
+// PKCS5 pads to the block size of the cipher like PKCS7, which is what Java's
+// "PKCS5Padding" and OpenSSL do for AES. PKCS5Strict pads the same way but
+// rejects block sizes other than the 8 bytes RFC 8018 defines PKCS5 for.
+var Modes = []Mode{No, Zero, PKCS5, PKCS5Strict, PKCS7, AnsiX923, ISO97971, ISO10126, ISO78164, Bit, TBC}
+
+// Pad returns src padded with mode to a multiple of blockSize. The padding is
+// appended to src, so callers that keep using src must pass a copy.
+func Pad(mode Mode, src []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(mode, blockSize); err != nil {
+		return nil, err
+	}
+	size := blockSize - len(src)%blockSize
+	switch mode {
+	case No:
+		return src, nil
+	case Zero:
+		if size == blockSize && len(src) > 0 {
+			return src, nil
+		}
+		return append(src, make([]byte, size)...), nil
+	case PKCS5, PKCS5Strict, PKCS7:
+		return append(src, bytes.Repeat([]byte{byte(size)}, size)...), nil
+	case AnsiX923:
+		pad := make([]byte, size)
+		pad[size-1] = byte(size)
+		return append(src, pad...), nil
+	case ISO10126:
+		pad := make([]byte, size)
+		_, _ = io.ReadFull(utils.Rand(), pad[:size-1])
+		pad[size-1] = byte(size)
+		return append(src, pad...), nil
+	case ISO97971, ISO78164, Bit:
+		pad := make([]byte, size)
+		pad[0] = 0x80
+		return append(src, pad...), nil
+	default: // TBC
+		b := byte(0x00)
+		if len(src) > 0 && src[len(src)-1]&0x80 != 0 {
+			b = 0xFF
+		}
+		return append(src, bytes.Repeat([]byte{b}, size)...), nil
+	}
+}
+
+// Unpad returns src with the mode padding for blockSize removed. Malformed
+// padding leaves src unchanged. Zero and TBC padding cannot be told apart from
+// data ending in the padding byte, which is removed as well.
+func Unpad(mode Mode, src []byte, blockSize int) ([]byte, error) {
+	if err := checkBlockSize(mode, blockSize); err != nil {
+		return nil, err
+	}
+	if len(src) == 0 {
+		return src, nil
+	}
+	last := src[len(src)-1]
+	switch mode {
+	case No:
+		return src, nil
+	case Zero:
+		return bytes.TrimRight(src, "\x00"), nil
+	case PKCS5, PKCS5Strict, PKCS7:
+		return trimCounted(src, blockSize, func(b byte) bool { return b == last }), nil
+	case AnsiX923:
+		return trimCounted(src, blockSize, func(b byte) bool { return b == 0 }), nil
+	case ISO10126:
+		return trimCounted(src, blockSize, func(byte) bool { return true }), nil
+	case ISO97971, ISO78164, Bit:
+		i := bytes.LastIndexByte(src, 0x80)
+		if i < 0 || len(bytes.TrimRight(src[i+1:], "\x00")) > 0 {
+			return src, nil
+		}
+		return src[:i], nil
+	default: // TBC
+		i := len(src)
+		for i > 0 && src[i-1] == last {
+			i--
+		}
+		return src[:i], nil
+	}
+}
+
+// trimCounted removes the padding whose length is given by the last byte of
+// src, provided it fits in a block and every padding byte but the last
+// satisfies valid.
+func trimCounted(src []byte, blockSize int, valid func(b byte) bool) []byte {
+	n := int(src[len(src)-1])
+	if n == 0 || n > blockSize || n > len(src) {
+		return src
+	}
+	for _, b := range src[len(src)-n : len(src)-1] {
+		if !valid(b) {
+			return src
+		}
+	}
+	return src[:len(src)-n]
+}
+
+// checkBlockSize returns an error when blockSize cannot be used with mode.
+func checkBlockSize(mode Mode, blockSize int) error {
+	switch mode {
+	case No, Zero, PKCS5, PKCS5Strict, PKCS7, AnsiX923, ISO97971, ISO10126, ISO78164, Bit, TBC:
+	default:
+		return UnsupportedModeError{Mode: mode}
+	}
+	if blockSize < 1 || blockSize > 255 || mode == PKCS5Strict && blockSize != 8 {
+		return BlockSizeError{Mode: mode, Size: blockSize}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package padding
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTrips reports whether unpadding is expected to restore src exactly.
+// Zero and TBC padding cannot be told apart from data ending in the padding
+// byte, and No padding only applies to whole blocks.
+func roundTrips(mode Mode, src []byte, blockSize int) bool {
+	if len(src) == 0 {
+		return mode != No
+	}
+	last := src[len(src)-1]
+	switch mode {
+	case No:
+		return len(src)%blockSize == 0
+	case Zero:
+		return last != 0x00
+	case TBC:
+		return last != 0x00 && last != 0xFF
+	}
+	return true
+}
+
+func TestPadUnpad_Property(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, mode := range Modes {
+		for blockSize := 1; blockSize <= 255; blockSize++ {
+			if mode == PKCS5Strict && blockSize != 8 {
+				continue
+			}
+			for n := 0; n < 3*blockSize && n < 80; n++ {
+				src := make([]byte, n)
+				rng.Read(src)
+				padded, err := Pad(mode, bytes.Clone(src), blockSize)
+				require.NoError(t, err)
+				if mode == No || mode == Zero && n > 0 && n%blockSize == 0 {
+					assert.Equal(t, src, padded)
+				} else {
+					assert.Zero(t, len(padded)%blockSize, "%s/%d/%d", mode, blockSize, n)
+					assert.Greater(t, len(padded), n, "%s/%d/%d", mode, blockSize, n)
+				}
+				if !roundTrips(mode, src, blockSize) {
+					continue
+				}
+				got, err := Unpad(mode, padded, blockSize)
+				require.NoError(t, err)
+				assert.Equal(t, src, got, "%s/%d/%d", mode, blockSize, n)
+			}
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	src := []byte("hello world")
+
+	t.Run("known answers", func(t *testing.T) {
+		cases := map[Mode]string{
+			Zero:     "hello world\x00\x00\x00\x00\x00",
+			PKCS5:    "hello world\x05\x05\x05\x05\x05",
+			PKCS7:    "hello world\x05\x05\x05\x05\x05",
+			AnsiX923: "hello world\x00\x00\x00\x00\x05",
+			ISO97971: "hello world\x80\x00\x00\x00\x00",
+			ISO78164: "hello world\x80\x00\x00\x00\x00",
+			Bit:      "hello world\x80\x00\x00\x00\x00",
+			TBC:      "hello world\x00\x00\x00\x00\x00",
+		}
+		for mode, want := range cases {
+			got, err := Pad(mode, bytes.Clone(src), 16)
+			assert.NoError(t, err)
+			assert.Equal(t, want, string(got), mode)
+		}
+	})
+
+	t.Run("iso10126 random bytes", func(t *testing.T) {
+		got, err := Pad(ISO10126, bytes.Clone(src), 16)
+		assert.NoError(t, err)
+		assert.Len(t, got, 16)
+		assert.Equal(t, byte(5), got[15])
+	})
+
+	t.Run("tbc after high bit", func(t *testing.T) {
+		got, err := Pad(TBC, []byte{0x01, 0x81}, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x01, 0x81, 0xFF, 0xFF}, got)
+	})
+
+	t.Run("256-bit blocks", func(t *testing.T) {
+		got, err := Pad(PKCS7, bytes.Clone(src), 32)
+		assert.NoError(t, err)
+		assert.Len(t, got, 32)
+		assert.Equal(t, bytes.Repeat([]byte{21}, 21), got[11:])
+	})
+
+	t.Run("invalid block size", func(t *testing.T) {
+		for _, size := range []int{0, -1, 256} {
+			got, err := Pad(PKCS7, src, size)
+			assert.Nil(t, got)
+			assert.Equal(t, BlockSizeError{Mode: PKCS7, Size: size}, err)
+		}
+		_, err := Pad(PKCS7, src, 0)
+		assert.Equal(t, "crypto/padding: invalid block size 0 for PKCS7 padding, must be between 1 and 255 bytes", err.Error())
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		got, err := Pad("PKCS11", src, 16)
+		assert.Nil(t, got)
+		assert.Equal(t, UnsupportedModeError{Mode: "PKCS11"}, err)
+		assert.Equal(t, `crypto/padding: unsupported padding mode "PKCS11"`, err.Error())
+	})
+}
+
+func TestPKCS5Strict(t *testing.T) {
+	src := []byte("hello world, sixteen")
+
+	got, err := Pad(PKCS5, bytes.Clone(src), 16)
+	assert.NoError(t, err)
+	assert.Len(t, got, 32)
+
+	got, err = Pad(PKCS5Strict, bytes.Clone(src), 8)
+	assert.NoError(t, err)
+	assert.Len(t, got, 24)
+
+	_, err = Pad(PKCS5Strict, src, 16)
+	assert.Equal(t, BlockSizeError{Mode: PKCS5Strict, Size: 16}, err)
+	assert.Equal(t, "crypto/padding: invalid block size 16 for PKCS5Strict padding, must be 8 bytes", err.Error())
+	_, err = Unpad(PKCS5Strict, got, 16)
+	assert.IsType(t, BlockSizeError{}, err)
+}
+
+func TestUnpad(t *testing.T) {
+	t.Run("malformed padding is kept", func(t *testing.T) {
+		cases := map[Mode][]byte{
+			PKCS7:    []byte("hello\x03\x02\x03"),
+			AnsiX923: []byte("hello\x01\x00\x03"),
+			ISO97971: []byte("hello\x80\x00\x01"),
+			Bit:      []byte("hello"),
+		}
+		for mode, src := range cases {
+			got, err := Unpad(mode, src, 8)
+			assert.NoError(t, err)
+			assert.Equal(t, src, got, mode)
+		}
+	})
+
+	t.Run("count larger than block", func(t *testing.T) {
+		src := append([]byte("x"), bytes.Repeat([]byte{9}, 9)...)
+		got, err := Unpad(PKCS7, src, 8)
+		assert.NoError(t, err)
+		assert.Equal(t, src, got)
+		got, _ = Unpad(PKCS7, src, 16)
+		assert.Equal(t, []byte("x"), got)
+	})
+
+	t.Run("zero count", func(t *testing.T) {
+		src := []byte("hello\x00")
+		got, err := Unpad(ISO10126, src, 8)
+		assert.NoError(t, err)
+		assert.Equal(t, src, got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for _, mode := range Modes {
+			got, err := Unpad(mode, []byte{}, 8)
+			assert.NoError(t, err)
+			assert.Empty(t, got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Unpad("PKCS11", []byte("x"), 16)
+		assert.IsType(t, UnsupportedModeError{}, err)
+		_, err = Unpad(PKCS7, []byte("x"), 0)
+		assert.IsType(t, BlockSizeError{}, err)
+	})
+}
+
+func TestErrorFields(t *testing.T) {
+	assert.Equal(t, "DGL-PADDING-001", UnsupportedModeError{}.Code())
+	assert.Equal(t, "x", UnsupportedModeError{Mode: "x"}.Fields()["mode"])
+	assert.Equal(t, "DGL-PADDING-002", BlockSizeError{}.Code())
+	assert.Equal(t, 7, BlockSizeError{Mode: PKCS7, Size: 7}.Fields()["block_size"])
+}
@@ -0,0 +1,124 @@
+package pinning
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// KeyMismatchError represents an error when a peer presents a public key that
+// differs from the key pinned to it. This may be a legitimate key rotation, to
+// be confirmed out of band before calling Repin, or an impersonation attempt.
+type KeyMismatchError struct {
+	Peer      string // The peer name
+	Pinned    string // The pinned fingerprint
+	Presented string // The fingerprint of the presented key
+}
+
+// Error returns a formatted error message describing the key change.
+func (e KeyMismatchError) Error() string {
+	return fmt.Sprintf("crypto/pinning: public key of peer '%s' changed: pinned %s, presented %s", e.Peer, e.Pinned, e.Presented)
+}
+
+// Code returns the stable error code DGL-PINNING-001.
+func (e KeyMismatchError) Code() string {
+	return "DGL-PINNING-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyMismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/pinning", "", "check", "peer", e.Peer, "pinned", e.Pinned, "presented", e.Presented)
+}
+
+// InvalidPublicKeyError represents an error when a public key is empty or
+// cannot be encoded as a SubjectPublicKeyInfo.
+type InvalidPublicKeyError struct {
+	Err error // The underlying error, if any
+}
+
+// Error returns a formatted error message describing the invalid public key.
+func (e InvalidPublicKeyError) Error() string {
+	if e.Err == nil {
+		return "crypto/pinning: public key cannot be empty"
+	}
+	return fmt.Sprintf("crypto/pinning: invalid public key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-PINNING-002.
+func (e InvalidPublicKeyError) Code() string {
+	return "DGL-PINNING-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPublicKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/pinning", "", "fingerprint", errcode.FieldCause, e.Err)
+}
+
+// InvalidPeerError represents an error when a peer name is empty or contains
+// spaces or control characters.
+type InvalidPeerError struct {
+	Peer string // The invalid peer name
+}
+
+// Error returns a formatted error message describing the invalid peer name.
+func (e InvalidPeerError) Error() string {
+	return fmt.Sprintf("crypto/pinning: invalid peer name %q", e.Peer)
+}
+
+// Code returns the stable error code DGL-PINNING-003.
+func (e InvalidPeerError) Code() string {
+	return "DGL-PINNING-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPeerError) Fields() map[string]any {
+	return errcode.NewFields("crypto/pinning", "", "", "peer", e.Peer)
+}
+
+// StoreError represents an error when the pin store fails to load or save a
+// fingerprint.
+type StoreError struct {
+	Err error // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the store failure.
+func (e StoreError) Error() string {
+	return fmt.Sprintf("crypto/pinning: pin store failed: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e StoreError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code DGL-PINNING-004.
+func (e StoreError) Code() string {
+	return "DGL-PINNING-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e StoreError) Fields() map[string]any {
+	return errcode.NewFields("crypto/pinning", "", "", errcode.FieldCause, e.Err)
+}
+
+// MalformedPinError represents an error when a line of a FileStore file is not
+// a "peer fingerprint" pair.
+type MalformedPinError struct {
+	Path string // The path of the pin file
+	Line int    // The line number of the malformed pin, from 1
+}
+
+// Error returns a formatted error message describing the malformed pin.
+func (e MalformedPinError) Error() string {
+	return fmt.Sprintf("crypto/pinning: malformed pin at %s:%d", e.Path, e.Line)
+}
+
+// Code returns the stable error code DGL-PINNING-005.
+func (e MalformedPinError) Code() string {
+	return "DGL-PINNING-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MalformedPinError) Fields() map[string]any {
+	return errcode.NewFields("crypto/pinning", "", "load", "path", e.Path, "line", e.Line)
+}
@@ -0,0 +1,153 @@
+// Package pinning pins the public keys of peers on first use (TOFU), for
+// command line tools and service-to-service channels that verify signatures
+// with dongle but have no certificate authority to vouch for the peer keys.
+// The first key presented by a peer is recorded in a Store, and any later key
+// with a different fingerprint is rejected until it is explicitly repinned:
+//
+//	p := pinning.NewPinner(pinning.NewFileStore("known_keys"))
+//	if err := p.Check("billing", kp.PublicKey); err != nil { ... }
+//
+// Fingerprints are the SHA-256 digest of the DER encoded SubjectPublicKeyInfo,
+// written as "SHA256:" followed by the unpadded base64 digest like OpenSSH, so
+// the same key pins to the same fingerprint whether it is given in PEM or DER.
+package pinning
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"sync"
+	"unicode"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// fingerprintPrefix names the digest algorithm of a fingerprint.
+const fingerprintPrefix = "SHA256:"
+
+// Fingerprint returns the fingerprint of a PEM or DER encoded public key, such
+// as the PublicKey field of a dongle key pair.
+func Fingerprint(publicKey []byte) (string, error) {
+	der := publicKey
+	if block, _ := pem.Decode(publicKey); block != nil {
+		der = block.Bytes
+	}
+	if len(der) == 0 {
+		return "", InvalidPublicKeyError{}
+	}
+	sum := sha256.Sum256(der)
+	return fingerprintPrefix + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
+// KeyFingerprint returns the fingerprint of a public key supported by
+// x509.MarshalPKIXPublicKey, such as *rsa.PublicKey or ed25519.PublicKey.
+func KeyFingerprint(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", InvalidPublicKeyError{Err: err}
+	}
+	return Fingerprint(der)
+}
+
+// Pinner checks the public keys presented by peers against their pinned
+// fingerprints. It is safe for concurrent use by multiple goroutines, provided
+// no other Pinner shares its store.
+type Pinner struct {
+	mu    sync.Mutex
+	store Store
+}
+
+// NewPinner returns a new Pinner recording fingerprints in store.
+func NewPinner(store Store) *Pinner {
+	return &Pinner{store: store}
+}
+
+// Check pins the PEM or DER encoded public key to peer if peer has no pinned
+// key yet, and otherwise returns a KeyMismatchError unless the key matches the
+// pinned one.
+func (p *Pinner) Check(peer string, publicKey []byte) error {
+	fingerprint, err := Fingerprint(publicKey)
+	if err != nil {
+		return err
+	}
+	return p.CheckFingerprint(peer, fingerprint)
+}
+
+// CheckKey is like Check for a public key supported by x509.MarshalPKIXPublicKey.
+func (p *Pinner) CheckKey(peer string, publicKey crypto.PublicKey) error {
+	fingerprint, err := KeyFingerprint(publicKey)
+	if err != nil {
+		return err
+	}
+	return p.CheckFingerprint(peer, fingerprint)
+}
+
+// CheckFingerprint is like Check for an already computed fingerprint.
+func (p *Pinner) CheckFingerprint(peer, fingerprint string) error {
+	if err := validatePeer(peer); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pinned, ok, err := p.store.Load(peer)
+	if err != nil {
+		return StoreError{Err: err}
+	}
+	if !ok {
+		return p.save(peer, fingerprint)
+	}
+	if !utils.ConstantTimeEqual([]byte(pinned), []byte(fingerprint)) {
+		return KeyMismatchError{Peer: peer, Pinned: pinned, Presented: fingerprint}
+	}
+	return nil
+}
+
+// Repin replaces the key pinned to peer with the PEM or DER encoded public
+// key, after the key rotation has been confirmed out of band.
+func (p *Pinner) Repin(peer string, publicKey []byte) error {
+	fingerprint, err := Fingerprint(publicKey)
+	if err != nil {
+		return err
+	}
+	if err = validatePeer(peer); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.save(peer, fingerprint)
+}
+
+// Pinned returns the fingerprint pinned to peer, and whether there is one.
+func (p *Pinner) Pinned(peer string) (string, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fingerprint, ok, err := p.store.Load(peer)
+	if err != nil {
+		return "", false, StoreError{Err: err}
+	}
+	return fingerprint, ok, nil
+}
+
+// save records fingerprint for peer.
+func (p *Pinner) save(peer, fingerprint string) error {
+	if err := p.store.Save(peer, fingerprint); err != nil {
+		return StoreError{Err: err}
+	}
+	return nil
+}
+
+// validatePeer rejects peer names that cannot be stored on a single line of a
+// file store: empty names and names containing spaces or control characters.
+func validatePeer(peer string) error {
+	if peer == "" {
+		return InvalidPeerError{Peer: peer}
+	}
+	for _, r := range peer {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return InvalidPeerError{Peer: peer}
+		}
+	}
+	return nil
+}
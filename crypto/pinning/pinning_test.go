@@ -0,0 +1,205 @@
+package pinning
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPublicKey(t *testing.T) (ed25519.PublicKey, []byte) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	return pub, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestFingerprint(t *testing.T) {
+	pub, pemKey := newPublicKey(t)
+	block, _ := pem.Decode(pemKey)
+
+	t.Run("pem and der match", func(t *testing.T) {
+		fromPem, err := Fingerprint(pemKey)
+		require.NoError(t, err)
+		fromDer, err := Fingerprint(block.Bytes)
+		require.NoError(t, err)
+		fromKey, err := KeyFingerprint(pub)
+		require.NoError(t, err)
+		assert.Equal(t, fromPem, fromDer)
+		assert.Equal(t, fromPem, fromKey)
+		assert.Regexp(t, `^SHA256:[A-Za-z0-9+/]{43}$`, fromPem)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := Fingerprint(nil)
+		assert.ErrorAs(t, err, &InvalidPublicKeyError{})
+		_, err = KeyFingerprint("not a key")
+		assert.ErrorAs(t, err, &InvalidPublicKeyError{})
+		assert.Contains(t, err.Error(), "invalid public key")
+	})
+}
+
+func TestPinner(t *testing.T) {
+	pub, key := newPublicKey(t)
+	_, other := newPublicKey(t)
+
+	t.Run("trust on first use", func(t *testing.T) {
+		p := NewPinner(NewMemoryStore())
+		_, ok, err := p.Pinned("peer")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, p.Check("peer", key))
+		require.NoError(t, p.Check("peer", key))
+		require.NoError(t, p.CheckKey("peer", pub))
+		require.NoError(t, p.Check("another", other))
+
+		fingerprint, ok, err := p.Pinned("peer")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		want, _ := Fingerprint(key)
+		assert.Equal(t, want, fingerprint)
+	})
+
+	t.Run("changed key", func(t *testing.T) {
+		p := NewPinner(NewMemoryStore())
+		require.NoError(t, p.Check("peer", key))
+
+		err := p.Check("peer", other)
+		var mismatch KeyMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		assert.Equal(t, "peer", mismatch.Peer)
+		pinned, _ := Fingerprint(key)
+		presented, _ := Fingerprint(other)
+		assert.Equal(t, pinned, mismatch.Pinned)
+		assert.Equal(t, presented, mismatch.Presented)
+		assert.Contains(t, err.Error(), "changed")
+
+		require.NoError(t, p.Repin("peer", other))
+		assert.NoError(t, p.Check("peer", other))
+		assert.ErrorAs(t, p.Check("peer", key), &KeyMismatchError{})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		p := NewPinner(NewMemoryStore())
+		for _, peer := range []string{"", "two words", "line\nbreak"} {
+			assert.ErrorAs(t, p.Check(peer, key), &InvalidPeerError{}, peer)
+			assert.ErrorAs(t, p.Repin(peer, key), &InvalidPeerError{}, peer)
+		}
+		assert.ErrorAs(t, p.Check("peer", nil), &InvalidPublicKeyError{})
+		assert.ErrorAs(t, p.Repin("peer", nil), &InvalidPublicKeyError{})
+		assert.ErrorAs(t, p.CheckKey("peer", 42), &InvalidPublicKeyError{})
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		failure := errors.New("store down")
+		p := NewPinner(failingStore{err: failure})
+		err := p.Check("peer", key)
+		assert.ErrorAs(t, err, &StoreError{})
+		assert.ErrorIs(t, err, failure)
+		_, _, err = p.Pinned("peer")
+		assert.ErrorIs(t, err, failure)
+
+		p = NewPinner(failingStore{saveErr: failure})
+		assert.ErrorIs(t, p.Check("peer", key), failure)
+	})
+
+	t.Run("concurrent first use", func(t *testing.T) {
+		p := NewPinner(NewMemoryStore())
+		keys := [][]byte{key, other}
+		errs := make([]error, 16)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = p.Check("peer", keys[i%2])
+			}(i)
+		}
+		wg.Wait()
+		mismatches := 0
+		for _, err := range errs {
+			if err != nil {
+				assert.ErrorAs(t, err, &KeyMismatchError{})
+				mismatches++
+			}
+		}
+		assert.Equal(t, len(errs)/2, mismatches)
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	_, key := newPublicKey(t)
+	_, other := newPublicKey(t)
+	path := filepath.Join(t.TempDir(), "known_keys")
+
+	p := NewPinner(NewFileStore(path))
+	require.NoError(t, p.Check("billing", key))
+	require.NoError(t, p.Check("audit", other))
+
+	// A new store over the same file keeps the pins.
+	p = NewPinner(NewFileStore(path))
+	assert.NoError(t, p.Check("billing", key))
+	assert.ErrorAs(t, p.Check("billing", other), &KeyMismatchError{})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	billing, _ := Fingerprint(key)
+	audit, _ := Fingerprint(other)
+	assert.Equal(t, "audit "+audit+"\nbilling "+billing+"\n", string(data))
+
+	t.Run("comments and blank lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "known_keys")
+		require.NoError(t, os.WriteFile(path, []byte("# pins\n\nbilling "+billing+"\n"), 0o600))
+		fingerprint, ok, err := NewFileStore(path).Load("billing")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, billing, fingerprint)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "known_keys")
+		require.NoError(t, os.WriteFile(path, []byte("billing\n"), 0o600))
+		err := NewPinner(NewFileStore(path)).Check("billing", key)
+		assert.ErrorAs(t, err, &StoreError{})
+		var malformed MalformedPinError
+		require.ErrorAs(t, err, &malformed)
+		assert.Equal(t, MalformedPinError{Path: path, Line: 1}, malformed)
+		assert.Equal(t, "DGL-PINNING-005", malformed.Code())
+		assert.Contains(t, err.Error(), "malformed pin")
+		assert.Error(t, NewFileStore(path).Save("audit", audit))
+	})
+
+	t.Run("unwritable directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "known_keys")
+		assert.ErrorAs(t, NewPinner(NewFileStore(path)).Check("billing", key), &StoreError{})
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		_, _, err := NewFileStore(t.TempDir()).Load("billing")
+		assert.Error(t, err)
+	})
+}
+
+// failingStore is a Store whose operations fail.
+type failingStore struct {
+	err     error
+	saveErr error
+}
+
+func (s failingStore) Load(string) (string, bool, error) {
+	return "", false, s.err
+}
+
+func (s failingStore) Save(string, string) error {
+	return s.saveErr
+}
@@ -0,0 +1,128 @@
+package pinning
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Store records the fingerprint pinned to each peer. Implementations backed by
+// a database or a secrets service can be plugged into a Pinner in place of the
+// stores of this package.
+type Store interface {
+	// Load returns the fingerprint pinned to peer, and false if there is none.
+	Load(peer string) (fingerprint string, ok bool, err error)
+	// Save pins fingerprint to peer, replacing any previous fingerprint.
+	Save(peer, fingerprint string) error
+}
+
+// MemoryStore is a Store kept in memory, for tests and for processes that only
+// need pins to last as long as they run. It is safe for concurrent use.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	pins map[string]string
+}
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{pins: make(map[string]string)}
+}
+
+// Load implements the Store interface.
+func (s *MemoryStore) Load(peer string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fingerprint, ok := s.pins[peer]
+	return fingerprint, ok, nil
+}
+
+// Save implements the Store interface.
+func (s *MemoryStore) Save(peer, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pins[peer] = fingerprint
+	return nil
+}
+
+// FileStore is a Store kept in a text file with one "peer fingerprint" line per
+// peer, in the spirit of the OpenSSH known_hosts file. Blank lines and lines
+// starting with '#' are ignored. Every save rewrites the file atomically, so an
+// interrupted write leaves the previous pins intact. It is safe for concurrent
+// use within a process.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore returns a FileStore kept at path. The file is created on the
+// first save, a missing file holds no pins.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load implements the Store interface.
+func (s *FileStore) Load(peer string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.read()
+	if err != nil {
+		return "", false, err
+	}
+	fingerprint, ok := pins[peer]
+	return fingerprint, ok, nil
+}
+
+// Save implements the Store interface.
+func (s *FileStore) Save(peer, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.read()
+	if err != nil {
+		return err
+	}
+	pins[peer] = fingerprint
+
+	peers := make([]string, 0, len(pins))
+	for p := range pins {
+		peers = append(peers, p)
+	}
+	sort.Strings(peers)
+	var buf bytes.Buffer
+	for _, p := range peers {
+		fmt.Fprintf(&buf, "%s %s\n", p, pins[p])
+	}
+	return utils.WriteFileAtomic(s.path, buf.Bytes(), 0o600)
+}
+
+// read parses the pins in the file.
+func (s *FileStore) read() (map[string]string, error) {
+	pins := make(map[string]string)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, MalformedPinError{Path: s.path, Line: n}
+		}
+		pins[fields[0]] = fields[1]
+	}
+	return pins, scanner.Err()
+}
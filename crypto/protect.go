@@ -0,0 +1,353 @@
+package crypto
+
+import (
+	"encoding/binary"
+
+	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// protectMagic identifies protected messages and their format version.
+const protectMagic = "dgp\x01"
+
+// Signed parts of a protected message.
+const (
+	signedCiphertext byte = 0 // Encrypt-then-sign, verified before decrypting
+	signedPlaintext  byte = 1 // Sign-then-encrypt, verified before returning the plaintext
+)
+
+// protectHeaderSize is the size of the magic and signed part byte, which are
+// covered by the signature along with the signed data.
+const protectHeaderSize = len(protectMagic) + 1
+
+// Protector encrypts a payload and signs the result in one chain, producing a
+// single protected message for an Unprotector:
+//
+//	msg := dongle.Protect.FromBytes(b).EncryptByAes(c).SignByRsa(kp).ToRawBytes()
+//
+// The message is the magic "dgp\x01", a byte telling which part is signed, the
+// uvarint length of the ciphertext, the ciphertext and the signature. The
+// signature covers the magic, the signed part byte and the ciphertext, or the
+// plaintext after SignPlaintext.
+type Protector struct {
+	src        []byte
+	ciphertext []byte
+	dst        []byte
+	signed     byte
+	encrypted  bool
+	Error      error
+}
+
+// NewProtector returns a new Protector instance.
+func NewProtector() Protector {
+	return Protector{}
+}
+
+// FromString protects from string.
+func (p Protector) FromString(s string) Protector {
+	utils.AuditShared()
+	p.src = utils.String2Bytes(s)
+	return p
+}
+
+// FromBytes protects from byte slice.
+func (p Protector) FromBytes(b []byte) Protector {
+	utils.AuditShared()
+	p.src = b
+	return p
+}
+
+// SignPlaintext signs the plaintext instead of the ciphertext, to prove the
+// signer saw the plaintext rather than an opaque blob. The signature travels
+// in the clear, so anyone holding the public key can check guesses of a low
+// entropy plaintext against it; prefer the default unless that proof matters.
+func (p Protector) SignPlaintext() Protector {
+	p.signed = signedPlaintext
+	return p
+}
+
+// EncryptByAes encrypts by aes.
+func (p Protector) EncryptByAes(c *cipher.AesCipher) Protector {
+	return p.encrypt(func(e Encrypter) Encrypter { return e.ByAes(c) })
+}
+
+// EncryptBySm4 encrypts by sm4.
+func (p Protector) EncryptBySm4(c *cipher.Sm4Cipher) Protector {
+	return p.encrypt(func(e Encrypter) Encrypter { return e.BySm4(c) })
+}
+
+// EncryptByChaCha20Poly1305 encrypts by chacha20-poly1305.
+func (p Protector) EncryptByChaCha20Poly1305(c *cipher.ChaCha20Poly1305Cipher) Protector {
+	return p.encrypt(func(e Encrypter) Encrypter { return e.ByChaCha20Poly1305(c) })
+}
+
+// EncryptByXChaCha20Poly1305 encrypts by xchacha20-poly1305.
+func (p Protector) EncryptByXChaCha20Poly1305(c *cipher.XChaCha20Poly1305Cipher) Protector {
+	return p.encrypt(func(e Encrypter) Encrypter { return e.ByXChaCha20Poly1305(c) })
+}
+
+// SignByRsa signs by rsa.
+func (p Protector) SignByRsa(kp *keypair.RsaKeyPair) Protector {
+	return p.sign("SignByRsa", func(s Signer) Signer { return s.ByRsa(kp) })
+}
+
+// SignByEcdsa signs by ecdsa.
+func (p Protector) SignByEcdsa(kp *keypair.EcdsaKeyPair) Protector {
+	return p.sign("SignByEcdsa", func(s Signer) Signer { return s.ByEcdsa(kp) })
+}
+
+// SignByEd25519 signs by ed25519.
+func (p Protector) SignByEd25519(kp *keypair.Ed25519KeyPair) Protector {
+	return p.sign("SignByEd25519", func(s Signer) Signer { return s.ByEd25519(kp) })
+}
+
+// SignBySm2 signs by sm2.
+func (p Protector) SignBySm2(kp *keypair.Sm2KeyPair) Protector {
+	return p.sign("SignBySm2", func(s Signer) Signer { return s.BySm2(kp) })
+}
+
+// ToRawString outputs as raw string.
+func (p Protector) ToRawString() string {
+	return utils.Bytes2String(p.ToRawBytes())
+}
+
+// ToRawBytes outputs as raw byte slice.
+func (p Protector) ToRawBytes() []byte {
+	if len(p.dst) == 0 || p.Error != nil {
+		return []byte{}
+	}
+	return p.dst
+}
+
+// To outputs as a Result exposing the protected message in several encodings.
+func (p Protector) To() coding.Result {
+	return coding.NewResult(p.ToRawBytes(), p.Error)
+}
+
+// ToBase64String outputs as base64 string.
+func (p Protector) ToBase64String() string {
+	return coding.NewEncoder().FromBytes(p.ToRawBytes()).ByBase64().ToString()
+}
+
+// ToHexString outputs as hex string.
+func (p Protector) ToHexString() string {
+	return coding.NewEncoder().FromBytes(p.ToRawBytes()).ByHex().ToString()
+}
+
+// encrypt encrypts the source with the Encrypter step fn.
+func (p Protector) encrypt(fn func(e Encrypter) Encrypter) Protector {
+	if p.Error != nil {
+		return p
+	}
+	e := fn(NewEncrypter().FromBytes(p.src))
+	p.ciphertext, p.Error = e.dst, e.Error
+	p.encrypted = true
+	return p
+}
+
+// sign signs the ciphertext or plaintext with the Signer step fn and builds
+// the protected message.
+func (p Protector) sign(step string, fn func(s Signer) Signer) Protector {
+	if p.Error != nil {
+		return p
+	}
+	if !p.encrypted {
+		p.Error = OrderError{Step: step, Before: "EncryptBy"}
+		return p
+	}
+	if len(p.ciphertext) == 0 {
+		return p
+	}
+	data := p.ciphertext
+	if p.signed == signedPlaintext {
+		data = p.src
+	}
+	header := []byte{protectMagic[0], protectMagic[1], protectMagic[2], protectMagic[3], p.signed}
+	s := fn(NewSigner().FromBytes(append(header, data...)))
+	if s.Error != nil {
+		p.Error = s.Error
+		return p
+	}
+	dst := binary.AppendUvarint(header, uint64(len(p.ciphertext)))
+	dst = append(dst, p.ciphertext...)
+	p.dst = append(dst, s.sign...)
+	return p
+}
+
+// Unprotector verifies and decrypts a message made by a Protector, in that
+// order: decrypting before a VerifyBy step fails with an OrderError.
+//
+//	b := dongle.Unprotect.FromRawBytes(msg).VerifyByRsa(kp).DecryptByAes(c).ToRawBytes()
+//
+// Encrypt-then-sign messages are verified by the VerifyBy step, before any
+// decryption. Messages signed with SignPlaintext are verified by the DecryptBy
+// step, which only returns the plaintext once its signature checks out.
+type Unprotector struct {
+	src        []byte
+	ciphertext []byte
+	signature  []byte
+	signed     byte
+	verify     func(data []byte) error // Set by VerifyBy
+	dst        []byte
+	Error      error
+}
+
+// NewUnprotector returns a new Unprotector instance.
+func NewUnprotector() Unprotector {
+	return Unprotector{}
+}
+
+// FromRawString unprotects from raw string.
+func (u Unprotector) FromRawString(s string) Unprotector {
+	utils.AuditShared()
+	u.src = utils.String2Bytes(s)
+	return u
+}
+
+// FromRawBytes unprotects from raw byte slice.
+func (u Unprotector) FromRawBytes(b []byte) Unprotector {
+	utils.AuditShared()
+	u.src = b
+	return u
+}
+
+// FromBase64String unprotects from base64 string.
+func (u Unprotector) FromBase64String(s string) Unprotector {
+	utils.AuditShared()
+	decode := coding.NewDecoder().FromString(s).ByBase64()
+	u.src, u.Error = decode.ToBytes(), decode.Error
+	return u
+}
+
+// FromHexString unprotects from hex string.
+func (u Unprotector) FromHexString(s string) Unprotector {
+	utils.AuditShared()
+	decode := coding.NewDecoder().FromString(s).ByHex()
+	u.src, u.Error = decode.ToBytes(), decode.Error
+	return u
+}
+
+// VerifyByRsa verifies by rsa.
+func (u Unprotector) VerifyByRsa(kp *keypair.RsaKeyPair) Unprotector {
+	return u.verifyBy(func(v Verifier) Verifier { return v.ByRsa(kp) })
+}
+
+// VerifyByEcdsa verifies by ecdsa.
+func (u Unprotector) VerifyByEcdsa(kp *keypair.EcdsaKeyPair) Unprotector {
+	return u.verifyBy(func(v Verifier) Verifier { return v.ByEcdsa(kp) })
+}
+
+// VerifyByEd25519 verifies by ed25519.
+func (u Unprotector) VerifyByEd25519(kp *keypair.Ed25519KeyPair) Unprotector {
+	return u.verifyBy(func(v Verifier) Verifier { return v.ByEd25519(kp) })
+}
+
+// VerifyBySm2 verifies by sm2.
+func (u Unprotector) VerifyBySm2(kp *keypair.Sm2KeyPair) Unprotector {
+	return u.verifyBy(func(v Verifier) Verifier { return v.BySm2(kp) })
+}
+
+// DecryptByAes decrypts by aes.
+func (u Unprotector) DecryptByAes(c *cipher.AesCipher) Unprotector {
+	return u.decrypt("DecryptByAes", func(d Decrypter) Decrypter { return d.ByAes(c) })
+}
+
+// DecryptBySm4 decrypts by sm4.
+func (u Unprotector) DecryptBySm4(c *cipher.Sm4Cipher) Unprotector {
+	return u.decrypt("DecryptBySm4", func(d Decrypter) Decrypter { return d.BySm4(c) })
+}
+
+// DecryptByChaCha20Poly1305 decrypts by chacha20-poly1305.
+func (u Unprotector) DecryptByChaCha20Poly1305(c *cipher.ChaCha20Poly1305Cipher) Unprotector {
+	return u.decrypt("DecryptByChaCha20Poly1305", func(d Decrypter) Decrypter { return d.ByChaCha20Poly1305(c) })
+}
+
+// DecryptByXChaCha20Poly1305 decrypts by xchacha20-poly1305.
+func (u Unprotector) DecryptByXChaCha20Poly1305(c *cipher.XChaCha20Poly1305Cipher) Unprotector {
+	return u.decrypt("DecryptByXChaCha20Poly1305", func(d Decrypter) Decrypter { return d.ByXChaCha20Poly1305(c) })
+}
+
+// ToRawString outputs as raw string.
+func (u Unprotector) ToRawString() string {
+	return utils.Bytes2String(u.ToRawBytes())
+}
+
+// ToRawBytes outputs as raw byte slice.
+func (u Unprotector) ToRawBytes() []byte {
+	if len(u.dst) == 0 || u.Error != nil {
+		return []byte{}
+	}
+	return u.dst
+}
+
+// To outputs as a Result exposing the plaintext in several encodings.
+func (u Unprotector) To() coding.Result {
+	return coding.NewResult(u.ToRawBytes(), u.Error)
+}
+
+// verifyBy parses the message and checks its signature with the Verifier step
+// fn, deferring the check to decrypt for plaintext signatures.
+func (u Unprotector) verifyBy(fn func(v Verifier) Verifier) Unprotector {
+	if u.Error != nil || len(u.src) == 0 {
+		return u
+	}
+	if u.Error = u.parse(); u.Error != nil {
+		return u
+	}
+	header := u.src[:protectHeaderSize:protectHeaderSize]
+	u.verify = func(data []byte) error {
+		v := fn(NewVerifier().FromBytes(append(header, data...)).WithRawSign(u.signature))
+		if !v.ToBool() {
+			return SignatureError{Err: v.Error}
+		}
+		return nil
+	}
+	if u.signed == signedCiphertext {
+		u.Error = u.verify(u.ciphertext)
+	}
+	return u
+}
+
+// decrypt decrypts the ciphertext with the Decrypter step fn once verified.
+func (u Unprotector) decrypt(step string, fn func(d Decrypter) Decrypter) Unprotector {
+	if u.Error != nil || len(u.src) == 0 {
+		return u
+	}
+	if u.verify == nil {
+		u.Error = OrderError{Step: step, Before: "VerifyBy"}
+		return u
+	}
+	d := fn(NewDecrypter().FromRawBytes(u.ciphertext))
+	if d.Error != nil {
+		u.Error = d.Error
+		return u
+	}
+	if u.signed == signedPlaintext {
+		if u.Error = u.verify(d.dst); u.Error != nil {
+			return u
+		}
+	}
+	u.dst = d.dst
+	return u
+}
+
+// parse splits the protected message into its parts.
+func (u *Unprotector) parse() error {
+	if len(u.src) < protectHeaderSize || string(u.src[:len(protectMagic)]) != protectMagic {
+		return InvalidProtectedError{Reason: "missing magic"}
+	}
+	u.signed = u.src[len(protectMagic)]
+	if u.signed != signedCiphertext && u.signed != signedPlaintext {
+		return InvalidProtectedError{Reason: "unknown signed part"}
+	}
+	rest := u.src[protectHeaderSize:]
+	size, n := binary.Uvarint(rest)
+	if n <= 0 || size == 0 || size >= uint64(len(rest)-n) {
+		return InvalidProtectedError{Reason: "truncated ciphertext or signature"}
+	}
+	u.ciphertext = rest[n : n+int(size)]
+	u.signature = rest[n+int(size):]
+	return nil
+}
@@ -0,0 +1,174 @@
+package crypto
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtect(t *testing.T) {
+	aesGcm := cipher.NewAesCipher(cipher.GCM)
+	aesGcm.SetKey([]byte("1234567890123456"))
+	aesGcm.SetNonce([]byte("123456789012"))
+
+	sm4Cbc := cipher.NewSm4Cipher(cipher.CBC)
+	sm4Cbc.SetKey([]byte("1234567890123456"))
+	sm4Cbc.SetIV([]byte("1234567890123456"))
+	sm4Cbc.SetPadding(cipher.PKCS7)
+
+	ed := keypair.NewEd25519KeyPair()
+	require.NoError(t, ed.GenKeyPair())
+	other := keypair.NewEd25519KeyPair()
+	require.NoError(t, other.GenKeyPair())
+
+	t.Run("encrypt then sign", func(t *testing.T) {
+		p := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEd25519(ed)
+		require.NoError(t, p.Error)
+		msg := p.ToRawBytes()
+		assert.Equal(t, protectMagic, string(msg[:4]))
+		assert.Equal(t, signedCiphertext, msg[4])
+
+		u := NewUnprotector().FromRawBytes(msg).VerifyByEd25519(ed).DecryptByAes(aesGcm)
+		assert.NoError(t, u.Error)
+		assert.Equal(t, "hello world", u.ToRawString())
+	})
+
+	t.Run("sign plaintext", func(t *testing.T) {
+		p := NewProtector().FromBytes([]byte("hello world")).SignPlaintext().EncryptBySm4(sm4Cbc).SignByEd25519(ed)
+		require.NoError(t, p.Error)
+		assert.Equal(t, signedPlaintext, p.ToRawBytes()[4])
+
+		u := NewUnprotector().FromBase64String(p.ToBase64String()).VerifyByEd25519(ed).DecryptBySm4(sm4Cbc)
+		assert.NoError(t, u.Error)
+		assert.Equal(t, []byte("hello world"), u.ToRawBytes())
+
+		// The plaintext signature is only checked after decryption, and the
+		// plaintext is withheld when it fails.
+		u = NewUnprotector().FromRawBytes(p.ToRawBytes()).VerifyByEd25519(other)
+		assert.NoError(t, u.Error)
+		u = u.DecryptBySm4(sm4Cbc)
+		assert.IsType(t, SignatureError{}, u.Error)
+		assert.Empty(t, u.ToRawBytes())
+	})
+
+	t.Run("rsa and chacha20-poly1305", func(t *testing.T) {
+		kp := keypair.NewRsaKeyPair()
+		kp.SetFormat(keypair.PKCS1)
+		kp.SetHash(crypto.SHA256)
+		require.NoError(t, kp.GenKeyPair(1024))
+		c := cipher.NewChaCha20Poly1305Cipher()
+		c.SetKey([]byte("12345678901234567890123456789012"))
+		c.SetNonce([]byte("123456789012"))
+
+		hexMsg := NewProtector().FromString("hello world").EncryptByChaCha20Poly1305(c).SignByRsa(kp).ToHexString()
+		u := NewUnprotector().FromHexString(hexMsg).VerifyByRsa(kp).DecryptByChaCha20Poly1305(c)
+		assert.NoError(t, u.Error)
+		assert.Equal(t, "hello world", u.To().String())
+	})
+
+	t.Run("rsa and xchacha20-poly1305", func(t *testing.T) {
+		kp := keypair.NewRsaKeyPair()
+		kp.SetFormat(keypair.PKCS1)
+		kp.SetHash(crypto.SHA256)
+		require.NoError(t, kp.GenKeyPair(1024))
+		c := cipher.NewXChaCha20Poly1305Cipher()
+		c.SetKey([]byte("12345678901234567890123456789012"))
+		c.SetNonce([]byte("123456789012345678901234"))
+
+		hexMsg := NewProtector().FromString("hello world").EncryptByXChaCha20Poly1305(c).SignByRsa(kp).ToHexString()
+		u := NewUnprotector().FromHexString(hexMsg).VerifyByRsa(kp).DecryptByXChaCha20Poly1305(c)
+		assert.NoError(t, u.Error)
+		assert.Equal(t, "hello world", u.To().String())
+	})
+
+	t.Run("ecdsa and sm2", func(t *testing.T) {
+		ec := keypair.NewEcdsaKeyPair()
+		require.NoError(t, ec.GenKeyPair())
+		msg := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEcdsa(ec).ToRawString()
+		u := NewUnprotector().FromRawString(msg).VerifyByEcdsa(ec).DecryptByAes(aesGcm)
+		assert.NoError(t, u.Error)
+		assert.Equal(t, "hello world", u.ToRawString())
+
+		sm2 := keypair.NewSm2KeyPair()
+		require.NoError(t, sm2.GenKeyPair())
+		p := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignBySm2(sm2)
+		require.NoError(t, p.Error)
+		u = NewUnprotector().FromRawBytes(p.ToRawBytes()).VerifyBySm2(sm2).DecryptByAes(aesGcm)
+		assert.NoError(t, u.Error)
+		assert.Equal(t, "hello world", u.ToRawString())
+	})
+
+	t.Run("tampered ciphertext is rejected before decryption", func(t *testing.T) {
+		msg := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEd25519(ed).ToRawBytes()
+		msg[7] ^= 1
+		u := NewUnprotector().FromRawBytes(msg).VerifyByEd25519(ed)
+		assert.IsType(t, SignatureError{}, u.Error)
+		assert.Contains(t, u.Error.Error(), "signature verification failed")
+		assert.Empty(t, u.DecryptByAes(aesGcm).ToRawBytes())
+	})
+
+	t.Run("signed part cannot be switched", func(t *testing.T) {
+		msg := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEd25519(ed).ToRawBytes()
+		msg[4] = signedPlaintext
+		u := NewUnprotector().FromRawBytes(msg).VerifyByEd25519(ed).DecryptByAes(aesGcm)
+		assert.IsType(t, SignatureError{}, u.Error)
+	})
+
+	t.Run("order is enforced", func(t *testing.T) {
+		p := NewProtector().FromString("hello world").SignByEd25519(ed)
+		assert.Equal(t, OrderError{Step: "SignByEd25519", Before: "EncryptBy"}, p.Error)
+		assert.Equal(t, "crypto: EncryptBy must be called before SignByEd25519", p.Error.Error())
+		assert.Empty(t, p.ToRawBytes())
+
+		msg := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEd25519(ed).ToRawBytes()
+		u := NewUnprotector().FromRawBytes(msg).DecryptByAes(aesGcm)
+		assert.Equal(t, OrderError{Step: "DecryptByAes", Before: "VerifyBy"}, u.Error)
+		assert.Empty(t, u.ToRawBytes())
+	})
+
+	t.Run("malformed messages", func(t *testing.T) {
+		for name, msg := range map[string]string{
+			"no magic":        "hello world",
+			"short":           "dgp",
+			"signed part":     "dgp\x01\x07\x01ab",
+			"no signature":    "dgp\x01\x00\x02ab",
+			"bad length":      "dgp\x01\x00\xff",
+			"zero ciphertext": "dgp\x01\x00\x00sig",
+		} {
+			u := NewUnprotector().FromRawString(msg).VerifyByEd25519(ed)
+			assert.IsType(t, InvalidProtectedError{}, u.Error, name)
+		}
+		u := NewUnprotector().FromHexString("zz")
+		assert.Error(t, u.Error)
+	})
+
+	t.Run("errors propagate", func(t *testing.T) {
+		bad := cipher.NewAesCipher(cipher.GCM)
+		bad.SetKey([]byte("short"))
+		p := NewProtector().FromString("hello world").EncryptByAes(bad).SignByEd25519(ed)
+		assert.Error(t, p.Error)
+		assert.Empty(t, p.ToRawString())
+
+		p = NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEd25519(keypair.NewEd25519KeyPair())
+		assert.Error(t, p.Error)
+		assert.Error(t, p.To().Error())
+
+		msg := NewProtector().FromString("hello world").EncryptByAes(aesGcm).SignByEd25519(ed).ToRawBytes()
+		u := NewUnprotector().FromRawBytes(msg).VerifyByEd25519(ed).DecryptByAes(bad)
+		assert.Error(t, u.Error)
+		assert.Empty(t, u.ToRawString())
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		p := NewProtector().FromString("").EncryptByAes(aesGcm).SignByEd25519(ed)
+		assert.NoError(t, p.Error)
+		assert.Empty(t, p.ToRawBytes())
+		u := NewUnprotector().FromRawBytes(nil).VerifyByEd25519(ed).DecryptByAes(aesGcm)
+		assert.NoError(t, u.Error)
+		assert.Empty(t, u.ToRawBytes())
+	})
+}
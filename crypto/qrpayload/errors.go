@@ -0,0 +1,90 @@
+package qrpayload
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// KeySizeError represents an error when the key size does not suit the
+// algorithm of a codec.
+type KeySizeError struct {
+	Algorithm Algorithm // The algorithm of the codec
+	Size      int       // The rejected key size in bytes
+}
+
+// Error returns a formatted error message describing the invalid key size.
+func (e KeySizeError) Error() string {
+	return fmt.Sprintf("crypto/qrpayload: invalid %s key size %d", e.Algorithm, e.Size)
+}
+
+// Code returns the stable error code DGL-QRPAYLOAD-001.
+func (e KeySizeError) Code() string {
+	return "DGL-QRPAYLOAD-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/qrpayload", e.Algorithm.String(), "", "size", e.Size)
+}
+
+// InvalidPayloadError represents an error when a payload is not Base45, is
+// truncated or was written in another format or with another algorithm.
+type InvalidPayloadError struct {
+	Reason string // Why the payload is invalid
+}
+
+// Error returns a formatted error message describing the invalid payload.
+func (e InvalidPayloadError) Error() string {
+	return "crypto/qrpayload: invalid payload: " + e.Reason
+}
+
+// Code returns the stable error code DGL-QRPAYLOAD-002.
+func (e InvalidPayloadError) Code() string {
+	return "DGL-QRPAYLOAD-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPayloadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/qrpayload", "", "decode", "reason", e.Reason)
+}
+
+// DecryptError represents an error when a payload fails authentication, because
+// the key or the additional data is wrong or the payload was modified.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return "crypto/qrpayload: message authentication failed"
+}
+
+// Code returns the stable error code DGL-QRPAYLOAD-003.
+func (e DecryptError) Code() string {
+	return "DGL-QRPAYLOAD-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/qrpayload", "", "decode")
+}
+
+// TooLargeError represents an error when a decoded plaintext exceeds the
+// maximum size of a codec.
+type TooLargeError struct {
+	MaxSize int // The maximum plaintext size in bytes
+}
+
+// Error returns a formatted error message describing the oversized plaintext.
+func (e TooLargeError) Error() string {
+	return fmt.Sprintf("crypto/qrpayload: plaintext exceeds %d bytes", e.MaxSize)
+}
+
+// Code returns the stable error code DGL-QRPAYLOAD-004.
+func (e TooLargeError) Code() string {
+	return "DGL-QRPAYLOAD-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e TooLargeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/qrpayload", "", "decode", "max_size", e.MaxSize)
+}
@@ -0,0 +1,204 @@
+// Package qrpayload turns data into encrypted payloads ready to be printed as
+// QR codes, and back, in one call. Encode compresses the data with raw
+// DEFLATE, encrypts it with AES-GCM or SM4-GCM, prepends a small header and
+// encodes the result with Base45, whose alphabet is exactly the alphanumeric
+// mode of QR codes, so that the payload packs 5.5 bits per character instead
+// of the 8 of byte mode:
+//
+//	c := qrpayload.NewAesGcm(key)
+//	payload, err := c.Encode(ticket, nil) // pass payload to a QR code generator
+//	ticket, err = c.Decode(scanned, nil)
+//
+// Compression runs before encryption, since ciphertexts do not compress. The
+// payload is the version, the algorithm, a flags byte, the nonce and the
+// ciphertext; the header is authenticated as additional data.
+package qrpayload
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+
+	"github.com/dromara/dongle/coding/base45"
+	"github.com/dromara/dongle/crypto/internal/sm4"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Version is the payload format version written by this package.
+const Version = 1
+
+// headerSize is the size of the version, algorithm and flags bytes.
+const headerSize = 3
+
+// flagCompressed marks payloads whose plaintext was compressed before
+// encryption.
+const flagCompressed byte = 1
+
+// DefaultMaxSize is the default limit on the size of decoded plaintexts,
+// guarding Decode against payloads that decompress to huge outputs.
+const DefaultMaxSize = 64 << 10
+
+// Algorithm identifies the AEAD a payload is encrypted with.
+type Algorithm byte
+
+// The supported algorithms.
+const (
+	AesGcm Algorithm = 1 // AES-GCM with a 16, 24 or 32-byte key
+	Sm4Gcm Algorithm = 2 // SM4-GCM with a 16-byte key
+)
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case AesGcm:
+		return "AES-GCM"
+	case Sm4Gcm:
+		return "SM4-GCM"
+	}
+	return fmt.Sprintf("Algorithm(%d)", byte(a))
+}
+
+// Codec encodes and decodes QR payloads with one key.
+type Codec struct {
+	algorithm Algorithm   // AEAD algorithm
+	aead      cipher.AEAD // AEAD keyed with the codec key
+	compress  bool        // Whether Encode compresses plaintexts
+	maxSize   int         // Limit on the size of decoded plaintexts
+	Error     error       // Error field for storing configuration errors
+}
+
+// NewAesGcm returns a Codec encrypting with AES-GCM under key, which must be
+// 16, 24 or 32 bytes long.
+func NewAesGcm(key []byte) *Codec {
+	c := &Codec{algorithm: AesGcm, compress: true, maxSize: DefaultMaxSize}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		c.Error = KeySizeError{Algorithm: AesGcm, Size: len(key)}
+		return c
+	}
+	c.aead, _ = cipher.NewGCM(block)
+	return c
+}
+
+// NewSm4Gcm returns a Codec encrypting with SM4-GCM under key, which must be
+// 16 bytes long.
+func NewSm4Gcm(key []byte) *Codec {
+	c := &Codec{algorithm: Sm4Gcm, compress: true, maxSize: DefaultMaxSize}
+	if len(key) != sm4.KeySize {
+		c.Error = KeySizeError{Algorithm: Sm4Gcm, Size: len(key)}
+		return c
+	}
+	c.aead, _ = cipher.NewGCM(sm4.NewCipher(key))
+	return c
+}
+
+// SetCompression enables or disables compression, which is on by default.
+// Even when enabled, a plaintext is stored uncompressed if compressing it
+// does not make it smaller. Disable compression when plaintexts mix secrets
+// with data an attacker controls, as the compressed length then leaks how
+// much of the two match.
+func (c *Codec) SetCompression(compress bool) {
+	c.compress = compress
+}
+
+// SetMaxSize sets the limit on the size of decoded plaintexts, DefaultMaxSize
+// by default.
+func (c *Codec) SetMaxSize(size int) {
+	c.maxSize = size
+}
+
+// Encode compresses and encrypts plaintext and returns the Base45 payload.
+// The optional additional data must be passed to Decode unchanged.
+func (c *Codec) Encode(plaintext, additionalData []byte) (string, error) {
+	if c.Error != nil {
+		return "", c.Error
+	}
+	header := []byte{Version, byte(c.algorithm), 0}
+	if c.compress {
+		if compressed := deflate(plaintext); len(compressed) < len(plaintext) {
+			header[2] |= flagCompressed
+			plaintext = compressed
+		}
+	}
+	nonceSize := c.aead.NonceSize()
+	out := make([]byte, headerSize+nonceSize, headerSize+nonceSize+len(plaintext)+c.aead.Overhead())
+	copy(out, header)
+	if _, err := io.ReadFull(utils.Rand(), out[headerSize:]); err != nil {
+		return "", err
+	}
+	out = c.aead.Seal(out, out[headerSize:], plaintext, bind(header, additionalData))
+	return string(base45.NewStdEncoder().Encode(out)), nil
+}
+
+// Decode decodes, decrypts and decompresses a payload produced by Encode.
+// Decode returns an InvalidPayloadError for payloads that are not Base45 or
+// not in this format, a DecryptError when the key or additional data is wrong
+// or the payload was modified, and a TooLargeError when the plaintext exceeds
+// the maximum size.
+func (c *Codec) Decode(payload string, additionalData []byte) ([]byte, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+	raw, err := base45.NewStdDecoder().Decode([]byte(payload))
+	if err != nil {
+		return nil, InvalidPayloadError{Reason: "not base45"}
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(raw) < headerSize+nonceSize+c.aead.Overhead() {
+		return nil, InvalidPayloadError{Reason: "truncated"}
+	}
+	header := raw[:headerSize]
+	if header[0] != Version {
+		return nil, InvalidPayloadError{Reason: "unsupported version"}
+	}
+	if Algorithm(header[1]) != c.algorithm {
+		return nil, InvalidPayloadError{Reason: "encrypted with " + Algorithm(header[1]).String()}
+	}
+	if header[2]&^flagCompressed != 0 {
+		return nil, InvalidPayloadError{Reason: "unknown flags"}
+	}
+	nonce := raw[headerSize : headerSize+nonceSize]
+	plaintext, err := c.aead.Open(nil, nonce, raw[headerSize+nonceSize:], bind(header, additionalData))
+	if err != nil {
+		return nil, DecryptError{}
+	}
+	if header[2]&flagCompressed != 0 {
+		return inflate(plaintext, c.maxSize)
+	}
+	if len(plaintext) > c.maxSize {
+		return nil, TooLargeError{MaxSize: c.maxSize}
+	}
+	return plaintext, nil
+}
+
+// deflate compresses src with raw DEFLATE at the best compression level.
+func deflate(src []byte) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	_, _ = w.Write(src)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+// inflate decompresses src, refusing outputs larger than maxSize.
+func inflate(src []byte, maxSize int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+	dst, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, InvalidPayloadError{Reason: "corrupt compressed data"}
+	}
+	if len(dst) > maxSize {
+		return nil, TooLargeError{MaxSize: maxSize}
+	}
+	return dst, nil
+}
+
+// bind returns the AEAD additional data made of the header followed by the
+// caller's additional data.
+func bind(header, additionalData []byte) []byte {
+	return append(append(make([]byte, 0, len(header)+len(additionalData)), header...), additionalData...)
+}
@@ -0,0 +1,179 @@
+package qrpayload
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/coding/base45"
+	"github.com/dromara/dongle/errcode"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	aesKey = bytes.Repeat([]byte{0x5a}, 32)
+	sm4Key = bytes.Repeat([]byte{0xa5}, 16)
+	ticket = []byte(`{"event":"Go Conference 2026","seat":"B-17","holder":"Example Person","valid":"2026-10-16"}`)
+)
+
+// qrAlphanumeric is the character set of the QR code alphanumeric mode.
+const qrAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+func TestEncodeDecode(t *testing.T) {
+	for name, c := range map[string]*Codec{
+		"aes-gcm": NewAesGcm(aesKey),
+		"sm4-gcm": NewSm4Gcm(sm4Key),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Error)
+			payload, err := c.Encode(ticket, []byte("gate-3"))
+			require.NoError(t, err)
+			assert.Empty(t, strings.Trim(payload, qrAlphanumeric))
+
+			plaintext, err := c.Decode(payload, []byte("gate-3"))
+			require.NoError(t, err)
+			assert.Equal(t, ticket, plaintext)
+
+			_, err = c.Decode(payload, []byte("gate-4"))
+			assert.Equal(t, DecryptError{}, err)
+		})
+	}
+
+	t.Run("compression", func(t *testing.T) {
+		c := NewAesGcm(aesKey)
+		repetitive := bytes.Repeat(ticket, 10)
+		compressed, err := c.Encode(repetitive, nil)
+		require.NoError(t, err)
+		c.SetCompression(false)
+		uncompressed, err := c.Encode(repetitive, nil)
+		require.NoError(t, err)
+		assert.Less(t, len(compressed), len(uncompressed)/4)
+
+		for _, payload := range []string{compressed, uncompressed} {
+			plaintext, err := c.Decode(payload, nil)
+			require.NoError(t, err)
+			assert.Equal(t, repetitive, plaintext)
+		}
+	})
+
+	t.Run("incompressible", func(t *testing.T) {
+		c := NewAesGcm(aesKey)
+		payload, err := c.Encode([]byte("x"), nil)
+		require.NoError(t, err)
+		raw, err := base45.NewStdDecoder().Decode([]byte(payload))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{Version, byte(AesGcm), 0}, raw[:headerSize])
+		assert.Len(t, raw, headerSize+12+1+16)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		c := NewSm4Gcm(sm4Key)
+		payload, err := c.Encode(nil, nil)
+		require.NoError(t, err)
+		plaintext, err := c.Decode(payload, nil)
+		require.NoError(t, err)
+		assert.Empty(t, plaintext)
+	})
+
+	t.Run("fresh nonce", func(t *testing.T) {
+		c := NewAesGcm(aesKey)
+		first, err := c.Encode(ticket, nil)
+		require.NoError(t, err)
+		second, err := c.Encode(ticket, nil)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestDecode_Errors(t *testing.T) {
+	c := NewAesGcm(aesKey)
+	payload, err := c.Encode(ticket, nil)
+	require.NoError(t, err)
+	raw, err := base45.NewStdDecoder().Decode([]byte(payload))
+	require.NoError(t, err)
+	encode := func(b []byte) string { return string(base45.NewStdEncoder().Encode(b)) }
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := c.Decode("hello", nil)
+		assert.Equal(t, InvalidPayloadError{Reason: "not base45"}, err)
+		_, err = c.Decode(encode(raw[:20]), nil)
+		assert.Equal(t, InvalidPayloadError{Reason: "truncated"}, err)
+	})
+
+	t.Run("header", func(t *testing.T) {
+		for reason, i := range map[string]int{"unsupported version": 0, "encrypted with SM4-GCM": 1, "unknown flags": 2} {
+			forged := append([]byte{}, raw...)
+			forged[i] = 2
+			_, err := c.Decode(encode(forged), nil)
+			assert.Equal(t, InvalidPayloadError{Reason: reason}, err)
+		}
+
+		// Clearing the compressed flag breaks authentication
+		forged := append([]byte{}, raw...)
+		forged[2] = 0
+		_, err := c.Decode(encode(forged), nil)
+		assert.Equal(t, DecryptError{}, err)
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, err := NewAesGcm(bytes.Repeat([]byte{0x5b}, 32)).Decode(payload, nil)
+		assert.Equal(t, DecryptError{}, err)
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		bomb, err := c.Encode(make([]byte, 1<<20), nil)
+		require.NoError(t, err)
+		assert.Less(t, len(bomb), 4096)
+		_, err = c.Decode(bomb, nil)
+		assert.Equal(t, TooLargeError{MaxSize: DefaultMaxSize}, err)
+
+		small := NewAesGcm(aesKey)
+		small.SetMaxSize(16)
+		small.SetCompression(false)
+		payload, err := small.Encode(ticket, nil)
+		require.NoError(t, err)
+		_, err = small.Decode(payload, nil)
+		assert.Equal(t, TooLargeError{MaxSize: 16}, err)
+	})
+}
+
+func TestCodec_Errors(t *testing.T) {
+	t.Run("key size", func(t *testing.T) {
+		c := NewAesGcm([]byte("short"))
+		assert.Equal(t, KeySizeError{Algorithm: AesGcm, Size: 5}, c.Error)
+		_, err := c.Encode(ticket, nil)
+		assert.Equal(t, c.Error, err)
+		_, err = c.Decode("", nil)
+		assert.Equal(t, c.Error, err)
+
+		c = NewSm4Gcm(aesKey)
+		assert.Equal(t, KeySizeError{Algorithm: Sm4Gcm, Size: 32}, c.Error)
+		assert.Equal(t, "crypto/qrpayload: invalid SM4-GCM key size 32", c.Error.Error())
+	})
+
+	t.Run("random source", func(t *testing.T) {
+		readErr := errors.New("read error")
+		restore := utils.SetRand(mock.NewErrorFile(readErr))
+		defer restore()
+		_, err := NewAesGcm(aesKey).Encode(ticket, nil)
+		assert.Equal(t, readErr, err)
+	})
+
+	t.Run("codes", func(t *testing.T) {
+		assert.Equal(t, "Algorithm(9)", Algorithm(9).String())
+		for code, err := range map[string]errcode.Coder{
+			"DGL-QRPAYLOAD-001": KeySizeError{},
+			"DGL-QRPAYLOAD-002": InvalidPayloadError{},
+			"DGL-QRPAYLOAD-003": DecryptError{},
+			"DGL-QRPAYLOAD-004": TooLargeError{},
+		} {
+			assert.Equal(t, code, err.Code())
+			assert.Equal(t, "crypto/qrpayload", err.Fields()[errcode.FieldPackage])
+			assert.NotEmpty(t, err.Error())
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package ratchet
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidSecretError represents an error when the shared secret is too short.
+type InvalidSecretError struct {
+	Size int // Size of the provided secret
+}
+
+// Error returns a formatted error message describing the short secret.
+func (e InvalidSecretError) Error() string {
+	return fmt.Sprintf("crypto/ratchet: invalid shared secret size %d, must be at least %d bytes", e.Size, MinSecretSize)
+}
+
+// Code returns the stable error code DGL-RATCHET-001.
+func (e InvalidSecretError) Code() string {
+	return "DGL-RATCHET-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSecretError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "", "size", e.Size)
+}
+
+// InvalidKeyError represents an error when an X25519 key cannot be decoded.
+type InvalidKeyError struct {
+	Err error // Underlying error from key decoding
+}
+
+// Error returns a formatted error message describing the invalid key.
+func (e InvalidKeyError) Error() string {
+	return fmt.Sprintf("crypto/ratchet: invalid key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-RATCHET-002.
+func (e InvalidKeyError) Code() string {
+	return "DGL-RATCHET-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "key", errcode.FieldCause, e.Err)
+}
+
+// NoSendingChainError represents an error when a responder tries to send before receiving a message.
+type NoSendingChainError struct{}
+
+// Error returns a formatted error message describing the missing sending chain.
+func (e NoSendingChainError) Error() string {
+	return "crypto/ratchet: responder must receive a message before sending"
+}
+
+// Code returns the stable error code DGL-RATCHET-003.
+func (e NoSendingChainError) Code() string {
+	return "DGL-RATCHET-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e NoSendingChainError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "")
+}
+
+// InvalidMessageError represents an error when a message is too short to hold a header.
+type InvalidMessageError struct{}
+
+// Error returns a formatted error message describing the malformed message.
+func (e InvalidMessageError) Error() string {
+	return "crypto/ratchet: invalid message"
+}
+
+// Code returns the stable error code DGL-RATCHET-004.
+func (e InvalidMessageError) Code() string {
+	return "DGL-RATCHET-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidMessageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "")
+}
+
+// TooManySkippedError represents an error when a message would require skipping too many message keys.
+type TooManySkippedError struct {
+	Skipped int // Number of message keys that would be skipped
+}
+
+// Error returns a formatted error message describing the excessive skip.
+func (e TooManySkippedError) Error() string {
+	return fmt.Sprintf("crypto/ratchet: message requires skipping %d keys, at most %d are allowed", e.Skipped, MaxSkip)
+}
+
+// Code returns the stable error code DGL-RATCHET-005.
+func (e TooManySkippedError) Code() string {
+	return "DGL-RATCHET-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e TooManySkippedError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "", "skipped", e.Skipped)
+}
+
+// DecryptError represents an error when a message fails authentication.
+// The session state is left unchanged.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the authentication failure.
+func (e DecryptError) Error() string {
+	return "crypto/ratchet: failed to decrypt message"
+}
+
+// Code returns the stable error code DGL-RATCHET-006.
+func (e DecryptError) Code() string {
+	return "DGL-RATCHET-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "decrypt")
+}
+
+// InvalidStateError represents an error when a serialized session state is malformed.
+type InvalidStateError struct{}
+
+// Error returns a formatted error message describing the malformed state.
+func (e InvalidStateError) Error() string {
+	return "crypto/ratchet: invalid session state"
+}
+
+// Code returns the stable error code DGL-RATCHET-007.
+func (e InvalidStateError) Code() string {
+	return "DGL-RATCHET-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidStateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/ratchet", "Double Ratchet", "")
+}
@@ -0,0 +1,310 @@
+// Package ratchet implements a minimal Double Ratchet session for secure messaging,
+// following the Signal specification. Every message is encrypted with a fresh key
+// from a symmetric HMAC chain, and every reply turn performs an X25519 ratchet step,
+// giving forward secrecy and post-compromise security. Messages are encrypted with
+// AES-256-GCM and the session state can be serialized between messages.
+//
+// The initial shared secret and the responder's ratchet public key must be agreed
+// out of band, for example with an authenticated key exchange.
+package ratchet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+const (
+	KeySize       = 32   // Size of X25519 keys, root keys and chain keys
+	MinSecretSize = 32   // Minimum size of the initial shared secret
+	HeaderSize    = 40   // Size of a message header
+	MaxSkip       = 1000 // Maximum number of message keys skipped in one chain step, and kept in total
+)
+
+// skippedKey identifies a message key kept for an out of order message.
+type skippedKey struct {
+	dh [KeySize]byte
+	n  uint32
+}
+
+// header is the cleartext message header.
+type header struct {
+	dh [KeySize]byte // Sender ratchet public key
+	pn uint32        // Number of messages in the sender's previous sending chain
+	n  uint32        // Message number in the current sending chain
+}
+
+// Session is one party of a Double Ratchet conversation. It is not safe for concurrent use.
+type Session struct {
+	dhs     *ecdh.PrivateKey // Own ratchet key pair
+	dhr     *ecdh.PublicKey  // Remote ratchet public key
+	rk      []byte           // Root key
+	cks     []byte           // Sending chain key
+	ckr     []byte           // Receiving chain key
+	ns      uint32           // Next sending message number
+	nr      uint32           // Next receiving message number
+	pn      uint32           // Number of messages in the previous sending chain
+	skipped map[skippedKey][]byte
+	order   []skippedKey // Skipped keys, oldest first
+
+	Error error // Error field for storing initialization errors
+}
+
+// GenerateKeyPair generates an X25519 ratchet key pair for a responder.
+func GenerateKeyPair() (privateKey, publicKey []byte, err error) {
+	key, err := ecdh.X25519().GenerateKey(utils.Rand())
+	if err != nil {
+		return nil, nil, err
+	}
+	return key.Bytes(), key.PublicKey().Bytes(), nil
+}
+
+// NewInitiator returns the session of the party that sends first, given the
+// shared secret and the responder's ratchet public key.
+func NewInitiator(secret, remotePublicKey []byte) *Session {
+	s := &Session{skipped: make(map[skippedKey][]byte)}
+	if len(secret) < MinSecretSize {
+		s.Error = InvalidSecretError{Size: len(secret)}
+		return s
+	}
+	dhr, err := ecdh.X25519().NewPublicKey(remotePublicKey)
+	if err != nil {
+		s.Error = InvalidKeyError{Err: err}
+		return s
+	}
+	if s.dhs, err = ecdh.X25519().GenerateKey(utils.Rand()); err != nil {
+		s.Error = err
+		return s
+	}
+	s.dhr = dhr
+	s.rk, s.cks, s.Error = s.rootStep(rootKey(secret))
+	return s
+}
+
+// NewResponder returns the session of the party that receives first, given the
+// shared secret and its ratchet private key.
+func NewResponder(secret, privateKey []byte) *Session {
+	s := &Session{skipped: make(map[skippedKey][]byte)}
+	if len(secret) < MinSecretSize {
+		s.Error = InvalidSecretError{Size: len(secret)}
+		return s
+	}
+	dhs, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		s.Error = InvalidKeyError{Err: err}
+		return s
+	}
+	s.dhs = dhs
+	s.rk = rootKey(secret)
+	return s
+}
+
+// Encrypt encrypts plaintext and authenticates it together with ad, which should
+// bind the identities of both parties. The result is the header followed by the ciphertext.
+func (s *Session) Encrypt(plaintext, ad []byte) ([]byte, error) {
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	if s.cks == nil {
+		return nil, NoSendingChainError{}
+	}
+	var mk []byte
+	s.cks, mk = chainStep(s.cks)
+	h := header{pn: s.pn, n: s.ns}
+	copy(h.dh[:], s.dhs.PublicKey().Bytes())
+	s.ns++
+
+	out := h.marshal()
+	aead, nonce := messageCipher(mk)
+	return aead.Seal(out, nonce, plaintext, append(append([]byte{}, ad...), out...)), nil
+}
+
+// Decrypt authenticates and decrypts a message produced by the remote Encrypt
+// with the same ad. Out of order messages are supported through skipped message
+// keys. On failure the session state is left unchanged.
+func (s *Session) Decrypt(message, ad []byte) ([]byte, error) {
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	if len(message) < HeaderSize {
+		return nil, InvalidMessageError{}
+	}
+	var h header
+	h.unmarshal(message[:HeaderSize])
+	aad := append(append([]byte{}, ad...), message[:HeaderSize]...)
+
+	id := skippedKey{dh: h.dh, n: h.n}
+	if mk, ok := s.skipped[id]; ok {
+		plaintext, err := open(mk, message[HeaderSize:], aad)
+		if err != nil {
+			return nil, err
+		}
+		s.forget(id)
+		return plaintext, nil
+	}
+
+	next := s.clone()
+	if next.dhr == nil || string(h.dh[:]) != string(next.dhr.Bytes()) {
+		if err := next.skip(h.pn); err != nil {
+			return nil, err
+		}
+		if err := next.dhRatchet(h.dh[:]); err != nil {
+			return nil, err
+		}
+	}
+	if err := next.skip(h.n); err != nil {
+		return nil, err
+	}
+	var mk []byte
+	next.ckr, mk = chainStep(next.ckr)
+	next.nr++
+	plaintext, err := open(mk, message[HeaderSize:], aad)
+	if err != nil {
+		return nil, err
+	}
+	*s = *next
+	return plaintext, nil
+}
+
+// skip stores the message keys of the receiving chain up to message number until.
+// At most MaxSkip keys are skipped at once, and once MaxSkip keys are stored the
+// oldest are evicted, so that messages lost long ago do not block later gaps.
+func (s *Session) skip(until uint32) error {
+	if s.ckr == nil || until <= s.nr {
+		return nil
+	}
+	if until-s.nr > MaxSkip {
+		return TooManySkippedError{Skipped: int(until - s.nr)}
+	}
+	id := skippedKey{}
+	copy(id.dh[:], s.dhr.Bytes())
+	for ; s.nr < until; s.nr++ {
+		var mk []byte
+		id.n = s.nr
+		s.ckr, mk = chainStep(s.ckr)
+		s.store(id, mk)
+	}
+	return nil
+}
+
+// store keeps the message key of a skipped message, evicting the oldest stored
+// key once MaxSkip are kept.
+func (s *Session) store(id skippedKey, mk []byte) {
+	if len(s.order) >= MaxSkip {
+		delete(s.skipped, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.skipped[id] = mk
+	s.order = append(s.order, id)
+}
+
+// forget drops the message key of a skipped message once it has been used.
+func (s *Session) forget(id skippedKey) {
+	delete(s.skipped, id)
+	s.order = slices.DeleteFunc(s.order, func(k skippedKey) bool { return k == id })
+}
+
+// dhRatchet performs a DH ratchet step on receiving a new remote ratchet key.
+func (s *Session) dhRatchet(remote []byte) error {
+	dhr, err := ecdh.X25519().NewPublicKey(remote)
+	if err != nil {
+		return InvalidKeyError{Err: err}
+	}
+	s.pn, s.ns, s.nr = s.ns, 0, 0
+	s.dhr = dhr
+	if s.rk, s.ckr, err = s.rootStep(s.rk); err != nil {
+		return err
+	}
+	if s.dhs, err = ecdh.X25519().GenerateKey(utils.Rand()); err != nil {
+		return err
+	}
+	s.rk, s.cks, err = s.rootStep(s.rk)
+	return err
+}
+
+// rootStep mixes DH(dhs, dhr) into the root key rk, returning the new root key and a chain key.
+func (s *Session) rootStep(rk []byte) (root, chain []byte, err error) {
+	shared, err := s.dhs.ECDH(s.dhr)
+	if err != nil {
+		return nil, nil, InvalidKeyError{Err: err}
+	}
+	out := make([]byte, 2*KeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, shared, rk, []byte("dongle/ratchet/root")), out); err != nil {
+		return nil, nil, err
+	}
+	return out[:KeySize:KeySize], out[KeySize:], nil
+}
+
+// rootKey derives the initial KeySize root key from a shared secret of any
+// length, so that the root key always fits the serialized state.
+func rootKey(secret []byte) []byte {
+	rk := make([]byte, KeySize)
+	io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte("dongle/ratchet/secret")), rk)
+	return rk
+}
+
+// clone returns a deep copy of the session so a failed decryption leaves s untouched.
+func (s *Session) clone() *Session {
+	c := *s
+	c.skipped = make(map[skippedKey][]byte, len(s.skipped))
+	for k, v := range s.skipped {
+		c.skipped[k] = v
+	}
+	c.order = slices.Clone(s.order)
+	return &c
+}
+
+// chainStep advances a symmetric chain, returning the next chain key and a message key.
+func chainStep(ck []byte) (next, mk []byte) {
+	h := hmac.New(sha256.New, ck)
+	h.Write([]byte{0x01})
+	mk = h.Sum(nil)
+	h.Reset()
+	h.Write([]byte{0x02})
+	return h.Sum(nil), mk
+}
+
+// messageCipher derives the AES-256-GCM cipher and nonce of a message key.
+// Each message key is used once, so the derived nonce never repeats under a key.
+func messageCipher(mk []byte) (cipher.AEAD, []byte) {
+	out := make([]byte, KeySize+12)
+	io.ReadFull(hkdf.New(sha256.New, mk, nil, []byte("dongle/ratchet/message")), out)
+	block, _ := aes.NewCipher(out[:KeySize])
+	aead, _ := cipher.NewGCM(block)
+	return aead, out[KeySize:]
+}
+
+// open decrypts a ciphertext with a message key.
+func open(mk, ciphertext, aad []byte) ([]byte, error) {
+	aead, nonce := messageCipher(mk)
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, DecryptError{}
+	}
+	return plaintext, nil
+}
+
+// marshal encodes the header.
+func (h header) marshal() []byte {
+	out := make([]byte, HeaderSize)
+	copy(out, h.dh[:])
+	binary.BigEndian.PutUint32(out[KeySize:], h.pn)
+	binary.BigEndian.PutUint32(out[KeySize+4:], h.n)
+	return out
+}
+
+// unmarshal decodes a HeaderSize byte header.
+func (h *header) unmarshal(b []byte) {
+	copy(h.dh[:], b)
+	h.pn = binary.BigEndian.Uint32(b[KeySize:])
+	h.n = binary.BigEndian.Uint32(b[KeySize+4:])
+}
@@ -0,0 +1,271 @@
+package ratchet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	secret = bytes.Repeat([]byte{0x5a}, MinSecretSize)
+	ad     = []byte("alice|bob")
+)
+
+func newPair(t *testing.T) (alice, bob *Session) {
+	priv, pub, err := GenerateKeyPair()
+	require.NoError(t, err)
+	alice = NewInitiator(secret, pub)
+	require.NoError(t, alice.Error)
+	bob = NewResponder(secret, priv)
+	require.NoError(t, bob.Error)
+	return alice, bob
+}
+
+func send(t *testing.T, from, to *Session, msg string) {
+	ct, err := from.Encrypt([]byte(msg), ad)
+	require.NoError(t, err)
+	pt, err := to.Decrypt(ct, ad)
+	require.NoError(t, err)
+	assert.Equal(t, msg, string(pt))
+}
+
+func TestSession(t *testing.T) {
+	alice, bob := newPair(t)
+
+	_, err := bob.Encrypt([]byte("too early"), ad)
+	assert.Equal(t, NoSendingChainError{}, err)
+
+	send(t, alice, bob, "hello bob")
+	send(t, alice, bob, "are you there")
+	send(t, bob, alice, "hi alice")
+	send(t, alice, bob, "new turn")
+	send(t, bob, alice, "another turn")
+	send(t, bob, alice, "twice")
+
+	t.Run("fresh keys", func(t *testing.T) {
+		c1, err := alice.Encrypt([]byte("same"), ad)
+		require.NoError(t, err)
+		c2, err := alice.Encrypt([]byte("same"), ad)
+		require.NoError(t, err)
+		assert.NotEqual(t, c1[HeaderSize:], c2[HeaderSize:])
+		assert.Len(t, c1, HeaderSize+len("same")+16)
+	})
+}
+
+func TestSession_OutOfOrder(t *testing.T) {
+	alice, bob := newPair(t)
+
+	var msgs [][]byte
+	for _, m := range []string{"one", "two", "three"} {
+		ct, err := alice.Encrypt([]byte(m), ad)
+		require.NoError(t, err)
+		msgs = append(msgs, ct)
+	}
+	pt, err := bob.Decrypt(msgs[2], ad)
+	require.NoError(t, err)
+	assert.Equal(t, "three", string(pt))
+	assert.Len(t, bob.skipped, 2)
+
+	// Messages of a previous chain still decrypt after a DH ratchet step.
+	send(t, bob, alice, "reply")
+	ct, err := alice.Encrypt([]byte("four"), ad)
+	require.NoError(t, err)
+	pt, err = bob.Decrypt(ct, ad)
+	require.NoError(t, err)
+	assert.Equal(t, "four", string(pt))
+
+	pt, err = bob.Decrypt(msgs[0], ad)
+	require.NoError(t, err)
+	assert.Equal(t, "one", string(pt))
+	pt, err = bob.Decrypt(msgs[1], ad)
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(pt))
+	assert.Empty(t, bob.skipped)
+
+	// Replays fail once the skipped key is consumed.
+	_, err = bob.Decrypt(msgs[1], ad)
+	assert.Equal(t, DecryptError{}, err)
+}
+
+func TestSession_DecryptError(t *testing.T) {
+	alice, bob := newPair(t)
+	ct, err := alice.Encrypt([]byte("hello"), ad)
+	require.NoError(t, err)
+
+	t.Run("tampered", func(t *testing.T) {
+		bad := append([]byte{}, ct...)
+		bad[len(bad)-1] ^= 1
+		_, err := bob.Decrypt(bad, ad)
+		assert.Equal(t, DecryptError{}, err)
+		assert.Equal(t, "crypto/ratchet: failed to decrypt message", err.Error())
+	})
+
+	t.Run("tampered header", func(t *testing.T) {
+		bad := append([]byte{}, ct...)
+		bad[KeySize+7] ^= 1
+		_, err := bob.Decrypt(bad, ad)
+		assert.Equal(t, DecryptError{}, err)
+	})
+
+	t.Run("wrong ad", func(t *testing.T) {
+		_, err := bob.Decrypt(ct, []byte("mallory|bob"))
+		assert.Equal(t, DecryptError{}, err)
+	})
+
+	t.Run("short", func(t *testing.T) {
+		_, err := bob.Decrypt(ct[:HeaderSize-1], ad)
+		assert.Equal(t, InvalidMessageError{}, err)
+		assert.Equal(t, "crypto/ratchet: invalid message", err.Error())
+	})
+
+	t.Run("too many skipped", func(t *testing.T) {
+		bad := append([]byte{}, ct...)
+		bad[KeySize+7] = 0xff
+		bad[KeySize+6] = 0xff
+		_, err := bob.Decrypt(bad, ad)
+		assert.Equal(t, TooManySkippedError{Skipped: 0xffff}, err)
+		assert.Equal(t, "crypto/ratchet: message requires skipping 65535 keys, at most 1000 are allowed", err.Error())
+	})
+
+	// Failed attempts leave the session usable.
+	pt, err := bob.Decrypt(ct, ad)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(pt))
+	assert.Empty(t, bob.skipped)
+}
+
+func TestSession_SkippedEviction(t *testing.T) {
+	alice, bob := newPair(t)
+	send(t, alice, bob, "start")
+
+	// Lose MaxSkip messages, then one more gap: the oldest key is evicted
+	// instead of every later gap being rejected.
+	var lost [][]byte
+	for i := 0; i < MaxSkip+2; i++ {
+		ct, err := alice.Encrypt([]byte("lost"), ad)
+		require.NoError(t, err)
+		lost = append(lost, ct)
+	}
+	ct, err := alice.Encrypt([]byte("next gap"), ad)
+	require.NoError(t, err)
+	// Deliver the message after the first MaxSkip lost ones
+	pt, err := bob.Decrypt(lost[MaxSkip], ad)
+	require.NoError(t, err)
+	assert.Equal(t, "lost", string(pt))
+	assert.Len(t, bob.skipped, MaxSkip)
+
+	pt, err = bob.Decrypt(ct, ad)
+	require.NoError(t, err)
+	assert.Equal(t, "next gap", string(pt))
+	assert.Len(t, bob.skipped, MaxSkip)
+	assert.Len(t, bob.order, MaxSkip)
+
+	// The oldest skipped key was evicted, the newest are still kept
+	_, err = bob.Decrypt(lost[0], ad)
+	assert.Error(t, err)
+	pt, err = bob.Decrypt(lost[MaxSkip+1], ad)
+	require.NoError(t, err)
+	assert.Equal(t, "lost", string(pt))
+	assert.Len(t, bob.skipped, MaxSkip-1)
+
+	state, err := bob.MarshalBinary()
+	require.NoError(t, err)
+	restored := &Session{}
+	require.NoError(t, restored.UnmarshalBinary(state))
+	assert.Equal(t, bob.order, restored.order)
+	pt, err = restored.Decrypt(lost[MaxSkip-1], ad)
+	require.NoError(t, err)
+	assert.Equal(t, "lost", string(pt))
+}
+
+func TestSession_MarshalBinary(t *testing.T) {
+	alice, bob := newPair(t)
+
+	state, err := bob.MarshalBinary()
+	require.NoError(t, err)
+	restored := &Session{}
+	require.NoError(t, restored.UnmarshalBinary(state))
+	send(t, alice, restored, "to restored responder")
+
+	skippedMsg, err := alice.Encrypt([]byte("skipped"), ad)
+	require.NoError(t, err)
+	send(t, alice, restored, "after skip")
+	send(t, restored, alice, "reply")
+
+	state, err = restored.MarshalBinary()
+	require.NoError(t, err)
+	again := &Session{}
+	require.NoError(t, again.UnmarshalBinary(state))
+	encoded, err := again.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, state, encoded)
+
+	pt, err := again.Decrypt(skippedMsg, ad)
+	require.NoError(t, err)
+	assert.Equal(t, "skipped", string(pt))
+	send(t, again, alice, "still in sync")
+	send(t, alice, again, "both ways")
+
+	t.Run("long secret", func(t *testing.T) {
+		long := bytes.Repeat([]byte{0xa5}, 64)
+		priv, pub, err := GenerateKeyPair()
+		require.NoError(t, err)
+		alice, bob := NewInitiator(long, pub), NewResponder(long, priv)
+
+		state, err := bob.MarshalBinary()
+		require.NoError(t, err)
+		restored := &Session{}
+		require.NoError(t, restored.UnmarshalBinary(state))
+		send(t, alice, restored, "long secret")
+		send(t, restored, alice, "reply")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		s := &Session{}
+		assert.Equal(t, InvalidStateError{}, s.UnmarshalBinary(nil))
+		assert.Equal(t, InvalidStateError{}, s.UnmarshalBinary(state[:len(state)-1]))
+		assert.Equal(t, InvalidStateError{}, s.UnmarshalBinary(append(append([]byte{}, state...), 0)))
+
+		bad := append([]byte{}, state...)
+		bad[0] = 2
+		err := s.UnmarshalBinary(bad)
+		assert.Equal(t, InvalidStateError{}, err)
+		assert.Equal(t, "crypto/ratchet: invalid session state", err.Error())
+
+		bad = append([]byte{}, state...)
+		bad[1+KeySize] = 0x80
+		assert.Equal(t, InvalidStateError{}, s.UnmarshalBinary(bad))
+		assert.Nil(t, s.dhs)
+	})
+}
+
+func TestSession_InvalidInput(t *testing.T) {
+	priv, pub, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	s := NewInitiator(secret[:16], pub)
+	assert.Equal(t, InvalidSecretError{Size: 16}, s.Error)
+	assert.Equal(t, "crypto/ratchet: invalid shared secret size 16, must be at least 32 bytes", s.Error.Error())
+	_, err = s.Encrypt([]byte("x"), ad)
+	assert.Equal(t, s.Error, err)
+	_, err = s.Decrypt(make([]byte, HeaderSize+16), ad)
+	assert.Equal(t, s.Error, err)
+	_, err = s.MarshalBinary()
+	assert.Equal(t, s.Error, err)
+
+	s = NewResponder(secret[:31], priv)
+	assert.Equal(t, InvalidSecretError{Size: 31}, s.Error)
+
+	s = NewInitiator(secret, pub[:31])
+	assert.IsType(t, InvalidKeyError{}, s.Error)
+	assert.Contains(t, s.Error.Error(), "crypto/ratchet: invalid key: ")
+
+	s = NewResponder(secret, priv[:31])
+	assert.IsType(t, InvalidKeyError{}, s.Error)
+
+	// The all-zero point yields a zero shared secret and is rejected.
+	s = NewInitiator(secret, make([]byte, KeySize))
+	assert.IsType(t, InvalidKeyError{}, s.Error)
+}
@@ -0,0 +1,139 @@
+package ratchet
+
+import (
+	"crypto/ecdh"
+	"encoding/binary"
+)
+
+// stateVersion is the serialized session state format version.
+const stateVersion = 1
+
+// Flags marking the optional parts of a serialized state.
+const (
+	hasRemote = 1 << iota
+	hasSending
+	hasReceiving
+)
+
+// MarshalBinary encodes the session state, including skipped message keys.
+// The state holds secret keys and must be stored encrypted.
+func (s *Session) MarshalBinary() ([]byte, error) {
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	var flags byte
+	out := []byte{stateVersion}
+	out = append(out, s.dhs.Bytes()...)
+	if s.dhr != nil {
+		flags |= hasRemote
+	}
+	if s.cks != nil {
+		flags |= hasSending
+	}
+	if s.ckr != nil {
+		flags |= hasReceiving
+	}
+	out = append(out, flags)
+	if s.dhr != nil {
+		out = append(out, s.dhr.Bytes()...)
+	}
+	out = append(out, s.rk...)
+	out = append(out, s.cks...)
+	out = append(out, s.ckr...)
+	out = binary.BigEndian.AppendUint32(out, s.ns)
+	out = binary.BigEndian.AppendUint32(out, s.nr)
+	out = binary.BigEndian.AppendUint32(out, s.pn)
+
+	// Skipped keys are kept oldest first, so that eviction resumes in order.
+	out = binary.BigEndian.AppendUint32(out, uint32(len(s.order)))
+	for _, id := range s.order {
+		out = append(out, id.dh[:]...)
+		out = binary.BigEndian.AppendUint32(out, id.n)
+		out = append(out, s.skipped[id]...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary restores a session state encoded by MarshalBinary.
+func (s *Session) UnmarshalBinary(data []byte) error {
+	r := &reader{data: data}
+	if v := r.next(1); v == nil || v[0] != stateVersion {
+		return InvalidStateError{}
+	}
+	dhs, err := ecdh.X25519().NewPrivateKey(r.next(KeySize))
+	if err != nil {
+		return InvalidStateError{}
+	}
+	n := &Session{dhs: dhs, skipped: make(map[skippedKey][]byte)}
+	flags := r.next(1)
+	if flags == nil || flags[0]&^(hasRemote|hasSending|hasReceiving) != 0 {
+		return InvalidStateError{}
+	}
+	if flags[0]&hasRemote != 0 {
+		if n.dhr, err = ecdh.X25519().NewPublicKey(r.next(KeySize)); err != nil {
+			return InvalidStateError{}
+		}
+	}
+	n.rk = r.key()
+	if flags[0]&hasSending != 0 {
+		n.cks = r.key()
+	}
+	if flags[0]&hasReceiving != 0 {
+		n.ckr = r.key()
+	}
+	n.ns, n.nr, n.pn = r.uint32(), r.uint32(), r.uint32()
+	count := r.uint32()
+	if count > MaxSkip {
+		return InvalidStateError{}
+	}
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		var id skippedKey
+		copy(id.dh[:], r.next(KeySize))
+		id.n = r.uint32()
+		if _, ok := n.skipped[id]; ok {
+			return InvalidStateError{}
+		}
+		n.skipped[id] = r.key()
+		n.order = append(n.order, id)
+	}
+	if r.err != nil || len(r.data) != 0 || (n.ckr != nil && n.dhr == nil) {
+		return InvalidStateError{}
+	}
+	*s = *n
+	return nil
+}
+
+// reader consumes a serialized state, remembering the first short read.
+type reader struct {
+	data []byte
+	err  error
+}
+
+// next returns the next size bytes, or nil once the data runs out.
+func (r *reader) next(size int) []byte {
+	if r.err != nil || len(r.data) < size {
+		r.err = InvalidStateError{}
+		return nil
+	}
+	b := r.data[:size:size]
+	r.data = r.data[size:]
+	return b
+}
+
+// key returns a copy of the next KeySize bytes.
+func (r *reader) key() []byte {
+	b := r.next(KeySize)
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// uint32 returns the next big-endian uint32.
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
@@ -13,6 +13,13 @@ func (e Encrypter) ByRc4(c *cipher.Rc4Cipher) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "RC4", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("RC4"); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -35,6 +42,13 @@ func (d Decrypter) ByRc4(c *cipher.Rc4Cipher) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "RC4", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("RC4"); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -45,7 +59,7 @@ func (d Decrypter) ByRc4(c *cipher.Rc4Cipher) Decrypter {
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = rc4.NewStdDecrypter(c.Key).Decrypt(d.src)
+		d.dst, d.Error = d.limit(rc4.NewStdDecrypter(c.Key).Decrypt(d.src))
 	}
 
 	return d
@@ -2,6 +2,8 @@ package rc4
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the RC4 key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/rc4: invalid key size %d, must be between 1 and 256 bytes", k)
 }
 
+// Code returns the stable error code DGL-RC4-001.
+func (k KeySizeError) Code() string {
+	return "DGL-RC4-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rc4", "RC4", "key", errcode.FieldKeySize, int(k))
+}
+
 // WriteError represents an error when writing encrypted data fails.
 // This error occurs when writing encrypted data to the underlying writer fails.
 // The error includes the underlying error for detailed debugging.
@@ -28,6 +40,16 @@ func (e WriteError) Error() string {
 	return fmt.Sprintf("crypto/rc4: failed to write encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-RC4-002.
+func (e WriteError) Code() string {
+	return "DGL-RC4-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WriteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rc4", "RC4", "write", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -40,3 +62,13 @@ type ReadError struct {
 func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/rc4: failed to read encrypted data: %v", e.Err)
 }
+
+// Code returns the stable error code DGL-RC4-003.
+func (e ReadError) Code() string {
+	return "DGL-RC4-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rc4", "RC4", "read", errcode.FieldCause, e.Err)
+}
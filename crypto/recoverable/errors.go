@@ -0,0 +1,85 @@
+package recoverable
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedCurveError represents an error when a curve is not supported for
+// recoverable signatures.
+type UnsupportedCurveError struct {
+	Curve string // The name of the rejected curve
+}
+
+// Error returns a formatted error message describing the unsupported curve.
+func (e UnsupportedCurveError) Error() string {
+	return fmt.Sprintf("crypto/recoverable: unsupported curve %q", e.Curve)
+}
+
+// Code returns the stable error code DGL-RECOVERABLE-001.
+func (e UnsupportedCurveError) Code() string {
+	return "DGL-RECOVERABLE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedCurveError) Fields() map[string]any {
+	return errcode.NewFields("crypto/recoverable", "ECDSA", "", "curve", e.Curve)
+}
+
+// InvalidKeyError represents an error when a private key is missing or its
+// scalar is out of range.
+type InvalidKeyError struct{}
+
+// Error returns a formatted error message describing the invalid key.
+func (e InvalidKeyError) Error() string {
+	return "crypto/recoverable: invalid private key"
+}
+
+// Code returns the stable error code DGL-RECOVERABLE-002.
+func (e InvalidKeyError) Code() string {
+	return "DGL-RECOVERABLE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/recoverable", "ECDSA", "sign")
+}
+
+// InvalidSignatureError represents an error when a recoverable signature is
+// malformed, such as a wrong length, an out of range component or recovery ID.
+type InvalidSignatureError struct{}
+
+// Error returns a formatted error message describing the invalid signature.
+func (e InvalidSignatureError) Error() string {
+	return "crypto/recoverable: invalid signature"
+}
+
+// Code returns the stable error code DGL-RECOVERABLE-003.
+func (e InvalidSignatureError) Code() string {
+	return "DGL-RECOVERABLE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidSignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/recoverable", "ECDSA", "recover")
+}
+
+// RecoverError represents an error when no public key can be recovered from a
+// well-formed signature.
+type RecoverError struct{}
+
+// Error returns a formatted error message describing the failed recovery.
+func (e RecoverError) Error() string {
+	return "crypto/recoverable: public key cannot be recovered"
+}
+
+// Code returns the stable error code DGL-RECOVERABLE-004.
+func (e RecoverError) Code() string {
+	return "DGL-RECOVERABLE-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RecoverError) Fields() map[string]any {
+	return errcode.NewFields("crypto/recoverable", "ECDSA", "recover")
+}
@@ -0,0 +1,257 @@
+// Package recoverable implements ECDSA signatures carrying a recovery ID, from
+// which the signer's public key can be recovered as described in SEC 1,
+// section 4.1.6. This is the format used by Bitcoin and Ethereum on secp256k1,
+// and it works on the NIST curves as well.
+//
+// A signature is r || s || v, with r and s as big-endian integers of the size
+// of the curve order and v the recovery ID in the range 0 to 3. Signatures are
+// produced with deterministic nonces (RFC 6979) and a low s.
+package recoverable
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"io"
+	"math/big"
+
+	"github.com/dromara/dongle/crypto/internal/ecpoint"
+	"github.com/dromara/dongle/crypto/internal/secp256k1"
+)
+
+// legacyOffset is added to recovery IDs by Bitcoin and pre-EIP-155 Ethereum
+// signatures, so that v is 27 or 28.
+const legacyOffset = 27
+
+// Secp256k1 returns the secp256k1 curve. Its arithmetic is not constant time.
+func Secp256k1() elliptic.Curve {
+	return secp256k1.Curve()
+}
+
+// supported reports whether curve can be used for recoverable signatures.
+func supported(curve elliptic.Curve) error {
+	if curve == nil {
+		return UnsupportedCurveError{}
+	}
+	switch curve {
+	case secp256k1.Curve(), elliptic.P256(), elliptic.P384(), elliptic.P521():
+		return nil
+	}
+	return UnsupportedCurveError{Curve: curve.Params().Name}
+}
+
+// GenerateKey generates a private key on curve using random.
+func GenerateKey(curve elliptic.Curve, random io.Reader) (*ecdsa.PrivateKey, error) {
+	if err := supported(curve); err != nil {
+		return nil, err
+	}
+	params := curve.Params()
+	buf := make([]byte, (params.N.BitLen()+7)/8)
+	for {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return nil, err
+		}
+		d := new(big.Int).SetBytes(buf)
+		if d.Sign() == 0 || d.Cmp(params.N) >= 0 {
+			continue
+		}
+		key := &ecdsa.PrivateKey{D: d}
+		key.Curve = curve
+		key.X, key.Y = curve.ScalarBaseMult(buf)
+		return key, nil
+	}
+}
+
+// Size returns the size of a recoverable signature on curve.
+func Size(curve elliptic.Curve) int {
+	return 2*orderSize(curve) + 1
+}
+
+// Sign signs hash, the digest of a message, with key and returns r || s || v.
+// The nonce is derived from the key and hash as described in RFC 6979 with
+// HMAC-SHA256, and s is normalized to the lower half of the curve order.
+func Sign(key *ecdsa.PrivateKey, hash []byte) ([]byte, error) {
+	if key == nil {
+		return nil, InvalidKeyError{}
+	}
+	if err := supported(key.Curve); err != nil {
+		return nil, err
+	}
+	curve := key.Curve
+	n := curve.Params().N
+	if key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(n) >= 0 {
+		return nil, InvalidKeyError{}
+	}
+	e := hashToInt(hash, n)
+	nonces := newNonceGenerator(key.D, hash, n)
+	for {
+		k := nonces.next()
+		rx, ry := curve.ScalarBaseMult(k.Bytes())
+		r := new(big.Int).Mod(rx, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = k⁻¹ (e + r d) mod n
+		s := new(big.Int).Mul(r, key.D)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, n))
+		s.Mod(s, n)
+		if s.Sign() == 0 {
+			continue
+		}
+		v := byte(ry.Bit(0))
+		if rx.Cmp(n) >= 0 {
+			v |= 2
+		}
+		// Negating s signs with -R, whose y coordinate has the other parity.
+		if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+			s.Sub(n, s)
+			v ^= 1
+		}
+		size := orderSize(curve)
+		sig := make([]byte, 2*size+1)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size : 2*size])
+		sig[2*size] = v
+		return sig, nil
+	}
+}
+
+// RecoverPublicKey returns the public key that produced sig over hash on
+// curve. A recovery ID of 27 to 30 is accepted as well and treated as 0 to 3.
+func RecoverPublicKey(curve elliptic.Curve, hash, sig []byte) (*ecdsa.PublicKey, error) {
+	if err := supported(curve); err != nil {
+		return nil, err
+	}
+	params := curve.Params()
+	n := params.N
+	size := orderSize(curve)
+	if len(sig) != 2*size+1 {
+		return nil, InvalidSignatureError{}
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size : 2*size])
+	v := sig[2*size]
+	if v >= legacyOffset {
+		v -= legacyOffset
+	}
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 || v > 3 {
+		return nil, InvalidSignatureError{}
+	}
+
+	// R is the point whose x coordinate is r, or r + n, with the parity of v.
+	x := new(big.Int).Set(r)
+	if v&2 != 0 {
+		x.Add(x, n)
+	}
+	if x.Cmp(params.P) >= 0 {
+		return nil, RecoverError{}
+	}
+	point := make([]byte, 1+(params.BitSize+7)/8)
+	point[0] = 0x02 | v&1
+	x.FillBytes(point[1:])
+	rx, ry, err := ecpoint.Unmarshal(curve, point)
+	if err != nil {
+		return nil, RecoverError{}
+	}
+
+	// Q = r⁻¹ (s R - e G)
+	rinv := new(big.Int).ModInverse(r, n)
+	u1 := hashToInt(hash, n)
+	u1.Neg(u1)
+	u1.Mul(u1, rinv)
+	u1.Mod(u1, n)
+	u2 := new(big.Int).Mul(s, rinv)
+	u2.Mod(u2, n)
+	x1, y1 := curve.ScalarBaseMult(u1.Bytes())
+	x2, y2 := curve.ScalarMult(rx, ry, u2.Bytes())
+	qx, qy := curve.Add(x1, y1, x2, y2)
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, RecoverError{}
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: qx, Y: qy}, nil
+}
+
+// Verify reports whether sig is a valid recoverable signature of hash by pub.
+func Verify(pub *ecdsa.PublicKey, hash, sig []byte) bool {
+	if pub == nil {
+		return false
+	}
+	got, err := RecoverPublicKey(pub.Curve, hash, sig)
+	if err != nil {
+		return false
+	}
+	return got.X.Cmp(pub.X) == 0 && got.Y.Cmp(pub.Y) == 0
+}
+
+// orderSize returns the size in bytes of the order of curve.
+func orderSize(curve elliptic.Curve) int {
+	return (curve.Params().N.BitLen() + 7) / 8
+}
+
+// hashToInt converts a hash to an integer as described in SEC 1, keeping its
+// leftmost bits up to the bit length of n. This is bits2int from RFC 6979.
+func hashToInt(hash []byte, n *big.Int) *big.Int {
+	bits := n.BitLen()
+	if size := (bits + 7) / 8; len(hash) > size {
+		hash = hash[:size]
+	}
+	e := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - bits; excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return e
+}
+
+// nonceGenerator derives nonces as described in RFC 6979, section 3.2.
+type nonceGenerator struct {
+	k, v []byte
+	n    *big.Int
+}
+
+// newNonceGenerator seeds a generator with the private scalar d and the hash.
+func newNonceGenerator(d *big.Int, hash []byte, n *big.Int) *nonceGenerator {
+	size := (n.BitLen() + 7) / 8
+	x := d.FillBytes(make([]byte, size))
+	h := hashToInt(hash, n)
+	h.Mod(h, n)
+	h1 := h.FillBytes(make([]byte, size))
+
+	g := &nonceGenerator{k: make([]byte, sha256.Size), v: make([]byte, sha256.Size), n: n}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = g.mac(g.v, []byte{0x00}, x, h1)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, x, h1)
+	g.v = g.mac(g.v)
+	return g
+}
+
+// next returns the next candidate nonce in [1, n-1]. Each call after the first
+// reseeds as required when a candidate is rejected.
+func (g *nonceGenerator) next() *big.Int {
+	for {
+		var t []byte
+		for len(t)*8 < g.n.BitLen() {
+			g.v = g.mac(g.v)
+			t = append(t, g.v...)
+		}
+		k := hashToInt(t, g.n)
+		g.k = g.mac(g.v, []byte{0x00})
+		g.v = g.mac(g.v)
+		if k.Sign() > 0 && k.Cmp(g.n) < 0 {
+			return k
+		}
+	}
+}
+
+// mac returns HMAC-SHA256 keyed with the current K over the concatenated data.
+func (g *nonceGenerator) mac(data ...[]byte) []byte {
+	h := hmac.New(sha256.New, g.k)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
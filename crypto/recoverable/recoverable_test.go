@@ -0,0 +1,138 @@
+package recoverable
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hexInt(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+func newKey(curve elliptic.Curve, d string) *ecdsa.PrivateKey {
+	key := &ecdsa.PrivateKey{D: hexInt(d)}
+	key.Curve = curve
+	key.X, key.Y = curve.ScalarBaseMult(key.D.Bytes())
+	return key
+}
+
+func TestSign(t *testing.T) {
+	t.Run("rfc 6979 p-256 sample", func(t *testing.T) {
+		key := newKey(elliptic.P256(), "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")
+		hash := sha256.Sum256([]byte("sample"))
+		sig, err := Sign(key, hash[:])
+		require.NoError(t, err)
+		require.Len(t, sig, Size(elliptic.P256()))
+		assert.Equal(t, "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716", hex.EncodeToString(sig[:32]))
+		// RFC 6979 gives a high s, which is normalized to n - s.
+		s := new(big.Int).Sub(elliptic.P256().Params().N, hexInt("F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"))
+		assert.Equal(t, s, new(big.Int).SetBytes(sig[32:64]))
+		assert.True(t, ecdsa.Verify(&key.PublicKey, hash[:], new(big.Int).SetBytes(sig[:32]), s))
+	})
+
+	t.Run("secp256k1 deterministic", func(t *testing.T) {
+		key := newKey(Secp256k1(), "01")
+		hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+		sig, err := Sign(key, hash[:])
+		require.NoError(t, err)
+		assert.Equal(t, "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8"+
+			"2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5", hex.EncodeToString(sig[:64]))
+		again, err := Sign(key, hash[:])
+		require.NoError(t, err)
+		assert.Equal(t, sig, again)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := Sign(nil, []byte("hash"))
+		assert.Equal(t, InvalidKeyError{}, err)
+		key := newKey(Secp256k1(), "01")
+		key.D = new(big.Int).Set(key.Curve.Params().N)
+		_, err = Sign(key, []byte("hash"))
+		assert.Equal(t, InvalidKeyError{}, err)
+		key.Curve = elliptic.P224()
+		_, err = Sign(key, []byte("hash"))
+		assert.Equal(t, UnsupportedCurveError{Curve: "P-224"}, err)
+	})
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	for _, curve := range []elliptic.Curve{Secp256k1(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			key, err := GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+			for _, msg := range []string{"hello world", "dongle", ""} {
+				hash := sha256.Sum256([]byte(msg))
+				sig, err := Sign(key, hash[:])
+				require.NoError(t, err)
+				pub, err := RecoverPublicKey(curve, hash[:], sig)
+				require.NoError(t, err)
+				assert.True(t, key.PublicKey.Equal(pub))
+				assert.True(t, Verify(&key.PublicKey, hash[:], sig))
+
+				hash[0] ^= 1
+				assert.False(t, Verify(&key.PublicKey, hash[:], sig))
+			}
+		})
+	}
+
+	t.Run("openssl secp256k1 signature", func(t *testing.T) {
+		// Produced with: openssl dgst -sha256 -sign key.pem, on "hello world".
+		pub, _ := hex.DecodeString("042db1c77dfec6400cf1776f14ab906f79147d371972f68d879a2a152b4e444b820a0b1bed814364c642aa25155d6b9eab002c419e128d42b237b5c2eaa4425bab")
+		sig, _ := hex.DecodeString("c450c944930f5236c605dc2aedebdce4657f7f54a2f56048c9d2c82dbbc973be31635a0bca72ad0a2b61190276a863c868324aaeb53b5a0838a63a3f2328b2c1")
+		hash := sha256.Sum256([]byte("hello world"))
+		var found []byte
+		for v := byte(0); v < 2; v++ {
+			got, err := RecoverPublicKey(Secp256k1(), hash[:], append(sig, legacyOffset+v))
+			require.NoError(t, err)
+			point := elliptic.Marshal(Secp256k1(), got.X, got.Y)
+			if string(point) == string(pub) {
+				found = point
+			}
+		}
+		assert.Equal(t, pub, found)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		key, err := GenerateKey(Secp256k1(), rand.Reader)
+		require.NoError(t, err)
+		hash := sha256.Sum256([]byte("hello world"))
+		sig, err := Sign(key, hash[:])
+		require.NoError(t, err)
+
+		_, err = RecoverPublicKey(Secp256k1(), hash[:], sig[:64])
+		assert.Equal(t, InvalidSignatureError{}, err)
+		bad := append([]byte{}, sig...)
+		bad[64] = 4
+		_, err = RecoverPublicKey(Secp256k1(), hash[:], bad)
+		assert.Equal(t, InvalidSignatureError{}, err)
+		bad = append(make([]byte, 32), sig[32:]...)
+		_, err = RecoverPublicKey(Secp256k1(), hash[:], bad)
+		assert.Equal(t, InvalidSignatureError{}, err)
+		// r + n exceeds p for almost every r.
+		bad = append([]byte{}, sig...)
+		bad[64] |= 2
+		_, err = RecoverPublicKey(Secp256k1(), hash[:], bad)
+		assert.Equal(t, RecoverError{}, err)
+		_, err = RecoverPublicKey(nil, hash[:], sig)
+		assert.IsType(t, UnsupportedCurveError{}, err)
+		assert.False(t, Verify(nil, hash[:], sig))
+		assert.False(t, Verify(&key.PublicKey, hash[:], sig[:10]))
+	})
+}
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey(Secp256k1(), rand.Reader)
+	require.NoError(t, err)
+	assert.True(t, key.Curve.IsOnCurve(key.X, key.Y))
+	_, err = GenerateKey(elliptic.P224(), rand.Reader)
+	assert.Equal(t, UnsupportedCurveError{Curve: "P-224"}, err)
+}
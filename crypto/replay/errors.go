@@ -0,0 +1,50 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// ReplayedSequenceError represents an error when a sequence number has already been accepted.
+// This error indicates that the message is a duplicate and must be discarded.
+type ReplayedSequenceError struct {
+	Seq uint64 // The replayed sequence number
+}
+
+// Error returns a formatted error message describing the replayed sequence number.
+func (e ReplayedSequenceError) Error() string {
+	return fmt.Sprintf("crypto/replay: sequence number %d has already been received", e.Seq)
+}
+
+// Code returns the stable error code DGL-REPLAY-001.
+func (e ReplayedSequenceError) Code() string {
+	return "DGL-REPLAY-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReplayedSequenceError) Fields() map[string]any {
+	return errcode.NewFields("crypto/replay", "", "", "seq", e.Seq)
+}
+
+// StaleSequenceError represents an error when a sequence number falls behind the window.
+// Such messages can no longer be checked for duplication and must be discarded.
+type StaleSequenceError struct {
+	Seq uint64 // The stale sequence number
+	Top uint64 // The highest sequence number accepted so far
+}
+
+// Error returns a formatted error message describing the stale sequence number.
+func (e StaleSequenceError) Error() string {
+	return fmt.Sprintf("crypto/replay: sequence number %d is outside the window (highest accepted %d)", e.Seq, e.Top)
+}
+
+// Code returns the stable error code DGL-REPLAY-002.
+func (e StaleSequenceError) Code() string {
+	return "DGL-REPLAY-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e StaleSequenceError) Fields() map[string]any {
+	return errcode.NewFields("crypto/replay", "", "", "seq", e.Seq, "top", e.Top)
+}
@@ -0,0 +1,128 @@
+// Package replay implements a sliding-window anti-replay checker.
+// It follows the sequence number bitmap approach used by IPsec (RFC 4303, RFC 6479),
+// so datagram-based protocols built on dongle AEAD ciphers can reject duplicated
+// or too old messages while still tolerating reordering inside the window.
+package replay
+
+import "sync"
+
+// DefaultSize is the default window size in sequence numbers.
+const DefaultSize = 64
+
+// Window represents a sliding anti-replay window.
+// The window tracks the highest accepted sequence number and a bitmap of the
+// sequence numbers seen below it. It is safe for concurrent use.
+type Window struct {
+	mu     sync.Mutex
+	size   uint64   // Window size in sequence numbers, always a multiple of 64
+	top    uint64   // Highest accepted sequence number
+	seen   bool     // Whether any sequence number has been accepted yet
+	bitmap []uint64 // Ring bitmap of accepted sequence numbers, one word larger than the window
+}
+
+// NewWindow returns a new Window able to track size sequence numbers.
+// The size is rounded up to a multiple of 64, non-positive values use DefaultSize.
+func NewWindow(size int) *Window {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	words := (size + 63) / 64
+	// Keep one spare word so that the word being recycled never aliases
+	// a sequence number that is still inside the window (RFC 6479).
+	return &Window{
+		size:   uint64(words * 64),
+		bitmap: make([]uint64, words+1),
+	}
+}
+
+// Size returns the number of sequence numbers tracked by the window.
+func (w *Window) Size() int {
+	return int(w.size)
+}
+
+// Top returns the highest sequence number accepted so far.
+func (w *Window) Top() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.top
+}
+
+// Check reports whether seq would be accepted without updating the window.
+// It should be called before authenticating a message, followed by Accept
+// once the message has been authenticated successfully.
+func (w *Window) Check(seq uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.check(seq)
+}
+
+// Accept checks seq and marks it as seen when it is acceptable.
+// It returns a StaleSequenceError if seq fell behind the window and a
+// ReplayedSequenceError if seq has already been accepted.
+func (w *Window) Accept(seq uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.check(seq); err != nil {
+		return err
+	}
+
+	if !w.seen || seq > w.top {
+		w.slide(seq)
+	}
+	w.set(seq)
+	return nil
+}
+
+// Reset clears the window so that any sequence number is accepted again.
+func (w *Window) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := range w.bitmap {
+		w.bitmap[i] = 0
+	}
+	w.top = 0
+	w.seen = false
+}
+
+// check reports whether seq is acceptable, the caller must hold the lock.
+func (w *Window) check(seq uint64) error {
+	if !w.seen || seq > w.top {
+		return nil
+	}
+	if w.top-seq >= w.size {
+		return StaleSequenceError{Seq: seq, Top: w.top}
+	}
+	if w.isSet(seq) {
+		return ReplayedSequenceError{Seq: seq}
+	}
+	return nil
+}
+
+// slide advances the window so that seq becomes the new top,
+// clearing the bitmap words that now describe unseen sequence numbers.
+func (w *Window) slide(seq uint64) {
+	words := uint64(len(w.bitmap))
+	if !w.seen || seq-w.top >= w.size {
+		for i := range w.bitmap {
+			w.bitmap[i] = 0
+		}
+	} else {
+		for i := w.top/64 + 1; i <= seq/64; i++ {
+			w.bitmap[i%words] = 0
+		}
+	}
+	w.top = seq
+	w.seen = true
+}
+
+// isSet reports whether the bit for seq is set.
+func (w *Window) isSet(seq uint64) bool {
+	word := (seq / 64) % uint64(len(w.bitmap))
+	return w.bitmap[word]&(1<<(seq%64)) != 0
+}
+
+// set marks the bit for seq.
+func (w *Window) set(seq uint64) {
+	word := (seq / 64) % uint64(len(w.bitmap))
+	w.bitmap[word] |= 1 << (seq % 64)
+}
@@ -0,0 +1,111 @@
+package replay
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWindow(t *testing.T) {
+	t.Run("default size", func(t *testing.T) {
+		assert.Equal(t, DefaultSize, NewWindow(0).Size())
+		assert.Equal(t, DefaultSize, NewWindow(-1).Size())
+	})
+
+	t.Run("round up size", func(t *testing.T) {
+		assert.Equal(t, 64, NewWindow(1).Size())
+		assert.Equal(t, 128, NewWindow(65).Size())
+		assert.Equal(t, 1024, NewWindow(1024).Size())
+	})
+}
+
+func TestWindow_Accept(t *testing.T) {
+	t.Run("in order", func(t *testing.T) {
+		w := NewWindow(64)
+		for seq := uint64(0); seq < 200; seq++ {
+			assert.Nil(t, w.Accept(seq))
+		}
+		assert.Equal(t, uint64(199), w.Top())
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		w := NewWindow(64)
+		assert.Nil(t, w.Accept(1))
+		err := w.Accept(1)
+		assert.IsType(t, ReplayedSequenceError{}, err)
+		assert.Contains(t, err.Error(), "sequence number 1 has already been received")
+	})
+
+	t.Run("reordered inside window", func(t *testing.T) {
+		w := NewWindow(64)
+		assert.Nil(t, w.Accept(100))
+		assert.Nil(t, w.Accept(90))
+		assert.Nil(t, w.Accept(37))
+		assert.IsType(t, ReplayedSequenceError{}, w.Accept(90))
+		assert.IsType(t, ReplayedSequenceError{}, w.Accept(100))
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		w := NewWindow(64)
+		assert.Nil(t, w.Accept(100))
+		err := w.Accept(36)
+		assert.IsType(t, StaleSequenceError{}, err)
+		assert.Contains(t, err.Error(), "sequence number 36 is outside the window (highest accepted 100)")
+	})
+
+	t.Run("large jump clears window", func(t *testing.T) {
+		w := NewWindow(128)
+		assert.Nil(t, w.Accept(5))
+		assert.Nil(t, w.Accept(10_000))
+		assert.Nil(t, w.Accept(9_900))
+		assert.IsType(t, StaleSequenceError{}, w.Accept(5))
+	})
+
+	t.Run("no aliasing across recycled words", func(t *testing.T) {
+		w := NewWindow(64)
+		assert.Nil(t, w.Accept(10))
+		assert.Nil(t, w.Accept(70))
+		assert.IsType(t, ReplayedSequenceError{}, w.Accept(10))
+		assert.Nil(t, w.Accept(74))
+	})
+}
+
+func TestWindow_Check(t *testing.T) {
+	w := NewWindow(64)
+	assert.Nil(t, w.Check(1))
+	assert.Nil(t, w.Check(1))
+	assert.Nil(t, w.Accept(1))
+	assert.IsType(t, ReplayedSequenceError{}, w.Check(1))
+	assert.Nil(t, w.Check(2))
+}
+
+func TestWindow_Reset(t *testing.T) {
+	w := NewWindow(64)
+	assert.Nil(t, w.Accept(42))
+	w.Reset()
+	assert.Equal(t, uint64(0), w.Top())
+	assert.Nil(t, w.Accept(42))
+}
+
+func TestWindow_Concurrent(t *testing.T) {
+	w := NewWindow(1024)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seq := uint64(0); seq < 512; seq++ {
+				if w.Accept(seq) == nil {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 512, accepted)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dromara/dongle/crypto/keypair"
 	"github.com/dromara/dongle/crypto/rsa"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 // ByRsa encrypts by rsa.
@@ -13,6 +14,13 @@ func (e Encrypter) ByRsa(kp *keypair.RsaKeyPair) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "RSA", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("RSA"); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -35,6 +43,13 @@ func (d Decrypter) ByRsa(kp *keypair.RsaKeyPair) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "RSA", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("RSA"); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -45,7 +60,7 @@ func (d Decrypter) ByRsa(kp *keypair.RsaKeyPair) Decrypter {
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = rsa.NewStdDecrypter(kp).Decrypt(d.src)
+		d.dst, d.Error = d.limit(rsa.NewStdDecrypter(kp).Decrypt(d.src))
 	}
 
 	return d
@@ -57,6 +72,13 @@ func (s Signer) ByRsa(kp *keypair.RsaKeyPair) Signer {
 		return s
 	}
 
+	o := observe(OperationSign, "RSA", s.data, s.reader)
+	defer o.stop(&s.sign, &s.Error)
+
+	if s.Error = deprecated("RSA"); s.Error != nil {
+		return s
+	}
+
 	// Streaming signing mode
 	if s.reader != nil {
 		s.sign, s.Error = s.stream(func(w io.Writer) io.WriteCloser {
@@ -79,8 +101,17 @@ func (v Verifier) ByRsa(kp *keypair.RsaKeyPair) Verifier {
 		return v
 	}
 
+	o := observe(OperationVerify, "RSA", v.data, v.reader)
+	defer o.stop(nil, &v.Error)
+
+	if v.Error = deprecated("RSA"); v.Error != nil {
+		return v
+	}
+
 	// Streaming verification mode
 	if v.reader != nil {
+		defer utils.CloseFS(v.reader)
+
 		verifier := rsa.NewStreamVerifier(v.reader, kp)
 
 		// Write the data to be verified
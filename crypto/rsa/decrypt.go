@@ -1,12 +1,13 @@
 package rsa
 
 import (
-	"crypto/rand"
+	stdRsa "crypto/rsa"
 	"errors"
 	"io"
 
 	"github.com/dromara/dongle/crypto/internal/rsa"
 	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 type StdDecrypter struct {
@@ -19,6 +20,10 @@ func NewStdDecrypter(kp *keypair.RsaKeyPair) *StdDecrypter {
 	d := &StdDecrypter{
 		keypair: *kp,
 	}
+	if err := kp.CheckUse(keypair.DecryptOp); err != nil {
+		d.Error = DecryptError{Err: err}
+		return d
+	}
 	if d.keypair.Type == "" {
 		d.keypair.Type = keypair.PrivateKey
 	}
@@ -35,11 +40,21 @@ func NewStdDecrypter(kp *keypair.RsaKeyPair) *StdDecrypter {
 		d.cache.pubKey = pubKey
 	}
 
-	if d.keypair.Type == keypair.PrivateKey {
-		if len(d.keypair.PrivateKey) == 0 {
-			d.Error = DecryptError{Err: keypair.EmptyPrivateKeyError{}}
+	if d.keypair.Type == keypair.PrivateKey && len(d.keypair.PrivateKey) == 0 {
+		// Without a private key, use the external decrypter set on the key pair
+		decrypter, err := d.keypair.Decrypter()
+		if err != nil {
+			d.Error = DecryptError{Err: err}
 			return d
 		}
+		d.cache.decrypter = decrypter
+		pubKey, err := d.keypair.ParsePublicKey()
+		if err != nil {
+			d.Error = DecryptError{Err: err}
+			return d
+		}
+		d.cache.pubKey = pubKey
+	} else if d.keypair.Type == keypair.PrivateKey {
 		priKey, err := d.keypair.ParsePrivateKey()
 		if err != nil {
 			d.Error = DecryptError{Err: err}
@@ -59,7 +74,7 @@ func NewStdDecrypter(kp *keypair.RsaKeyPair) *StdDecrypter {
 		return d
 	}
 	if d.keypair.Padding == keypair.OAEP {
-		d.cache.hash = kp.Hash.New()
+		d.cache.oaep = oaepOptions(kp)
 	}
 	if d.keypair.Padding == keypair.PSS {
 		d.Error = DecryptError{Err: keypair.UnsupportedPaddingSchemeError{Padding: string(d.keypair.Padding)}}
@@ -76,15 +91,37 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 	if len(src) == 0 {
 		return
 	}
+	if !d.keypair.Chunked {
+		return d.decrypt(src)
+	}
+	// Decrypt every block of the key size and concatenate the plaintexts
+	size := d.cache.keySize()
+	if len(src)%size != 0 {
+		return nil, DecryptError{Err: stdRsa.ErrDecryption}
+	}
+	for len(src) > 0 {
+		block, err := d.decrypt(src[:size])
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, block...)
+		src = src[size:]
+	}
+	return dst, nil
+}
+
+func (d *StdDecrypter) decrypt(src []byte) (dst []byte, err error) {
 	switch {
+	case d.cache.decrypter != nil:
+		dst, err = d.cache.decrypter.Decrypt(utils.Rand(), src, decrypterOpts(&d.keypair))
 	case d.keypair.Type == keypair.PublicKey && d.keypair.Padding == keypair.PKCS1v15:
 		dst, err = rsa.DecryptPKCS1v15WithPublicKey(d.cache.pubKey, src)
 	case d.keypair.Type == keypair.PublicKey && d.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.DecryptOAEPWithPublicKey(d.cache.hash, d.cache.pubKey, src)
+		dst, err = rsa.DecryptOAEPWithPublicKey(d.cache.oaep, d.cache.pubKey, src)
 	case d.keypair.Type == keypair.PrivateKey && d.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.DecryptPKCS1v15WithPrivateKey(rand.Reader, d.cache.priKey, src)
+		dst, err = rsa.DecryptPKCS1v15WithPrivateKey(utils.Rand(), d.cache.priKey, src)
 	case d.keypair.Type == keypair.PrivateKey && d.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.DecryptOAEPWithPrivateKey(d.cache.hash, rand.Reader, d.cache.priKey, src)
+		dst, err = rsa.DecryptOAEPWithPrivateKey(d.cache.oaep, utils.Rand(), d.cache.priKey, src)
 	default:
 		err = keypair.UnsupportedPaddingSchemeError{Padding: string(d.keypair.Padding)}
 	}
@@ -110,6 +147,10 @@ func NewStreamDecrypter(r io.Reader, kp *keypair.RsaKeyPair) io.Reader {
 		reader:   r,
 		position: 0,
 	}
+	if err := kp.CheckUse(keypair.DecryptOp); err != nil {
+		d.Error = DecryptError{Err: err}
+		return d
+	}
 	if d.keypair.Type == "" {
 		d.keypair.Type = keypair.PrivateKey
 	}
@@ -126,11 +167,21 @@ func NewStreamDecrypter(r io.Reader, kp *keypair.RsaKeyPair) io.Reader {
 		d.cache.pubKey = pubKey
 	}
 
-	if d.keypair.Type == keypair.PrivateKey {
-		if len(d.keypair.PrivateKey) == 0 {
-			d.Error = DecryptError{Err: keypair.EmptyPrivateKeyError{}}
+	if d.keypair.Type == keypair.PrivateKey && len(d.keypair.PrivateKey) == 0 {
+		// Without a private key, use the external decrypter set on the key pair
+		decrypter, err := d.keypair.Decrypter()
+		if err != nil {
+			d.Error = DecryptError{Err: err}
+			return d
+		}
+		d.cache.decrypter = decrypter
+		pubKey, err := d.keypair.ParsePublicKey()
+		if err != nil {
+			d.Error = DecryptError{Err: err}
 			return d
 		}
+		d.cache.pubKey = pubKey
+	} else if d.keypair.Type == keypair.PrivateKey {
 		priKey, err := d.keypair.ParsePrivateKey()
 		if err != nil {
 			d.Error = DecryptError{Err: err}
@@ -150,7 +201,7 @@ func NewStreamDecrypter(r io.Reader, kp *keypair.RsaKeyPair) io.Reader {
 		return d
 	}
 	if d.keypair.Padding == keypair.OAEP {
-		d.cache.hash = kp.Hash.New()
+		d.cache.oaep = oaepOptions(kp)
 	}
 	if d.keypair.Padding == keypair.PSS {
 		d.Error = DecryptError{Err: keypair.UnsupportedPaddingSchemeError{Padding: string(d.keypair.Padding)}}
@@ -169,14 +220,16 @@ func (d *StreamDecrypter) decrypt(data []byte) (dst []byte, err error) {
 		return
 	}
 	switch {
+	case d.cache.decrypter != nil:
+		dst, err = d.cache.decrypter.Decrypt(utils.Rand(), data, decrypterOpts(&d.keypair))
 	case d.keypair.Type == keypair.PublicKey && d.keypair.Padding == keypair.PKCS1v15:
 		dst, err = rsa.DecryptPKCS1v15WithPublicKey(d.cache.pubKey, data)
 	case d.keypair.Type == keypair.PublicKey && d.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.DecryptOAEPWithPublicKey(d.cache.hash, d.cache.pubKey, data)
+		dst, err = rsa.DecryptOAEPWithPublicKey(d.cache.oaep, d.cache.pubKey, data)
 	case d.keypair.Type == keypair.PrivateKey && d.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.DecryptPKCS1v15WithPrivateKey(rand.Reader, d.cache.priKey, data)
+		dst, err = rsa.DecryptPKCS1v15WithPrivateKey(utils.Rand(), d.cache.priKey, data)
 	case d.keypair.Type == keypair.PrivateKey && d.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.DecryptOAEPWithPrivateKey(d.cache.hash, rand.Reader, d.cache.priKey, data)
+		dst, err = rsa.DecryptOAEPWithPrivateKey(d.cache.oaep, utils.Rand(), d.cache.priKey, data)
 	default:
 		err = keypair.UnsupportedPaddingSchemeError{Padding: string(d.keypair.Padding)}
 	}
@@ -206,9 +259,12 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 		if d.keypair.Type == keypair.PublicKey {
 			blockSize = d.cache.pubKey.Size()
 		}
-		if d.keypair.Type == keypair.PrivateKey {
+		if d.keypair.Type == keypair.PrivateKey && d.cache.priKey != nil {
 			blockSize = d.cache.priKey.Size()
 		}
+		if d.cache.decrypter != nil {
+			blockSize = d.cache.pubKey.Size()
+		}
 
 		// Read one encrypted block from the underlying reader
 		encryptedBlock := make([]byte, blockSize)
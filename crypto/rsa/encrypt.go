@@ -1,11 +1,11 @@
 package rsa
 
 import (
-	"crypto/rand"
 	"io"
 
 	"github.com/dromara/dongle/crypto/internal/rsa"
 	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 type StdEncrypter struct {
@@ -18,6 +18,10 @@ func NewStdEncrypter(kp *keypair.RsaKeyPair) *StdEncrypter {
 	e := &StdEncrypter{
 		keypair: *kp,
 	}
+	if err := kp.CheckUse(keypair.EncryptOp); err != nil {
+		e.Error = EncryptError{Err: err}
+		return e
+	}
 	if e.keypair.Type == "" {
 		e.keypair.Type = keypair.PublicKey
 	}
@@ -58,7 +62,7 @@ func NewStdEncrypter(kp *keypair.RsaKeyPair) *StdEncrypter {
 		return e
 	}
 	if e.keypair.Padding == keypair.OAEP {
-		e.cache.hash = kp.Hash.New()
+		e.cache.oaep = oaepOptions(kp)
 	}
 	if e.keypair.Padding == keypair.PSS {
 		e.Error = EncryptError{Err: keypair.UnsupportedPaddingSchemeError{Padding: string(e.keypair.Padding)}}
@@ -72,18 +76,42 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		err = e.Error
 		return
 	}
+	// The key may have expired since the encrypter was created
+	if err = e.keypair.CheckUse(keypair.EncryptOp); err != nil {
+		err = EncryptError{Err: err}
+		return
+	}
 	if len(src) == 0 {
 		return
 	}
+	if !e.keypair.Chunked {
+		return e.encrypt(src)
+	}
+	// Encrypt every block of at most one chunk and concatenate the ciphertexts
+	size := chunkSize(&e.keypair, e.cache.keySize())
+	dst = make([]byte, 0, (len(src)+size-1)/size*e.cache.keySize())
+	for len(src) > 0 {
+		n := min(size, len(src))
+		block, err := e.encrypt(src[:n])
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, block...)
+		src = src[n:]
+	}
+	return dst, nil
+}
+
+func (e *StdEncrypter) encrypt(src []byte) (dst []byte, err error) {
 	switch {
 	case e.keypair.Type == keypair.PublicKey && e.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.EncryptPKCS1v15WithPublicKey(rand.Reader, e.cache.pubKey, src)
+		dst, err = rsa.EncryptPKCS1v15WithPublicKey(utils.Rand(), e.cache.pubKey, src)
 	case e.keypair.Type == keypair.PublicKey && e.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.EncryptOAEPWithPublicKey(e.cache.hash, rand.Reader, e.cache.pubKey, src)
+		dst, err = rsa.EncryptOAEPWithPublicKey(e.cache.oaep, utils.Rand(), e.cache.pubKey, src)
 	case e.keypair.Type == keypair.PrivateKey && e.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.EncryptPKCS1v15WithPrivateKey(rand.Reader, e.cache.priKey, src)
+		dst, err = rsa.EncryptPKCS1v15WithPrivateKey(utils.Rand(), e.cache.priKey, src)
 	case e.keypair.Type == keypair.PrivateKey && e.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.EncryptOAEPWithPrivateKey(e.cache.hash, rand.Reader, e.cache.priKey, src)
+		dst, err = rsa.EncryptOAEPWithPrivateKey(e.cache.oaep, utils.Rand(), e.cache.priKey, src)
 	default:
 		err = keypair.UnsupportedPaddingSchemeError{Padding: string(e.keypair.Padding)}
 	}
@@ -108,6 +136,10 @@ func NewStreamEncrypter(w io.Writer, kp *keypair.RsaKeyPair) io.WriteCloser {
 		writer:  w,
 		keypair: *kp,
 	}
+	if err := kp.CheckUse(keypair.EncryptOp); err != nil {
+		e.Error = EncryptError{Err: err}
+		return e
+	}
 	if e.keypair.Type == "" {
 		e.keypair.Type = keypair.PublicKey
 	}
@@ -148,7 +180,7 @@ func NewStreamEncrypter(w io.Writer, kp *keypair.RsaKeyPair) io.WriteCloser {
 		return e
 	}
 	if e.keypair.Padding == keypair.OAEP {
-		e.cache.hash = kp.Hash.New()
+		e.cache.oaep = oaepOptions(kp)
 	}
 	if e.keypair.Padding == keypair.PSS {
 		e.Error = EncryptError{Err: keypair.UnsupportedPaddingSchemeError{Padding: string(e.keypair.Padding)}}
@@ -163,12 +195,8 @@ func NewStreamEncrypter(w io.Writer, kp *keypair.RsaKeyPair) io.WriteCloser {
 		keySize = e.cache.priKey.Size()
 	}
 	switch e.keypair.Padding {
-	case keypair.PKCS1v15:
-		e.chunkSize = keySize - 11
-	case keypair.OAEP:
-		// OAEP padding overhead: 2*hashSize + 2
-		hashSize := kp.Hash.Size()
-		e.chunkSize = keySize - 2*hashSize - 2
+	case keypair.PKCS1v15, keypair.OAEP:
+		e.chunkSize = chunkSize(&e.keypair, keySize)
 	default:
 		e.Error = EncryptError{Err: keypair.UnsupportedPaddingSchemeError{Padding: string(e.keypair.Padding)}}
 		return e
@@ -182,18 +210,23 @@ func (e *StreamEncrypter) encrypt(data []byte) (dst []byte, err error) {
 		err = e.Error
 		return
 	}
+	// The key may have expired since the encrypter was created
+	if err = e.keypair.CheckUse(keypair.EncryptOp); err != nil {
+		err = EncryptError{Err: err}
+		return
+	}
 	if len(data) == 0 {
 		return
 	}
 	switch {
 	case e.keypair.Type == keypair.PublicKey && e.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.EncryptPKCS1v15WithPublicKey(rand.Reader, e.cache.pubKey, data)
+		dst, err = rsa.EncryptPKCS1v15WithPublicKey(utils.Rand(), e.cache.pubKey, data)
 	case e.keypair.Type == keypair.PublicKey && e.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.EncryptOAEPWithPublicKey(e.cache.hash, rand.Reader, e.cache.pubKey, data)
+		dst, err = rsa.EncryptOAEPWithPublicKey(e.cache.oaep, utils.Rand(), e.cache.pubKey, data)
 	case e.keypair.Type == keypair.PrivateKey && e.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.EncryptPKCS1v15WithPrivateKey(rand.Reader, e.cache.priKey, data)
+		dst, err = rsa.EncryptPKCS1v15WithPrivateKey(utils.Rand(), e.cache.priKey, data)
 	case e.keypair.Type == keypair.PrivateKey && e.keypair.Padding == keypair.OAEP:
-		dst, err = rsa.EncryptOAEPWithPrivateKey(e.cache.hash, rand.Reader, e.cache.priKey, data)
+		dst, err = rsa.EncryptOAEPWithPrivateKey(e.cache.oaep, utils.Rand(), e.cache.priKey, data)
 	default:
 		err = keypair.UnsupportedPaddingSchemeError{Padding: string(e.keypair.Padding)}
 	}
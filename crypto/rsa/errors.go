@@ -1,6 +1,10 @@
 package rsa
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 type EncryptError struct {
 	Err error
@@ -10,6 +14,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/rsa: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-RSA-001.
+func (e EncryptError) Code() string {
+	return "DGL-RSA-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rsa", "RSA", "encrypt", errcode.FieldCause, e.Err)
+}
+
 type DecryptError struct {
 	Err error
 }
@@ -18,6 +32,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/rsa: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-RSA-002.
+func (e DecryptError) Code() string {
+	return "DGL-RSA-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rsa", "RSA", "decrypt", errcode.FieldCause, e.Err)
+}
+
 type SignError struct {
 	Err error
 }
@@ -26,6 +50,16 @@ func (e SignError) Error() string {
 	return fmt.Sprintf("crypto/rsa: failed to sign data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-RSA-003.
+func (e SignError) Code() string {
+	return "DGL-RSA-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rsa", "RSA", "sign", errcode.FieldCause, e.Err)
+}
+
 type VerifyError struct {
 	Err error
 }
@@ -34,6 +68,16 @@ func (e VerifyError) Error() string {
 	return fmt.Sprintf("crypto/rsa: failed to verify signature: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-RSA-004.
+func (e VerifyError) Code() string {
+	return "DGL-RSA-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e VerifyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rsa", "RSA", "verify", errcode.FieldCause, e.Err)
+}
+
 type ReadError struct {
 	Err error
 }
@@ -41,3 +85,36 @@ type ReadError struct {
 func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/rsa: failed to read encrypted data: %v", e.Err)
 }
+
+// Code returns the stable error code DGL-RSA-005.
+func (e ReadError) Code() string {
+	return "DGL-RSA-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rsa", "RSA", "read", errcode.FieldCause, e.Err)
+}
+
+// FaultError is returned when a signature fails its fault check, that is does
+// not verify against the public key. The faulty signature is discarded, since
+// it could reveal a factor of the modulus; retrying usually succeeds, repeated
+// faults point at failing hardware or a fault injection attack. Decryption is
+// checked by the standard library, which re-encrypts the CRT result.
+type FaultError struct {
+	Operation string // "sign"
+}
+
+func (e FaultError) Error() string {
+	return fmt.Sprintf("crypto/rsa: failed to %s data: private key operation failed its fault check", e.Operation)
+}
+
+// Code returns the stable error code DGL-RSA-006.
+func (e FaultError) Code() string {
+	return "DGL-RSA-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e FaultError) Fields() map[string]any {
+	return errcode.NewFields("crypto/rsa", "RSA", e.Operation)
+}
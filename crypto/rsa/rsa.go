@@ -1,15 +1,92 @@
 // Package rsa implements RSA encryption, decryption, signing, and verification with streaming support.
 // It provides RSA operations using the standard RSA algorithm with support
 // for different key sizes and padding schemes.
+//
+// Private key operations run on the constant time implementation of the
+// standard library, which needs no blinding, and always receive the package
+// randomness source for the implementations that still blind. Their results
+// are checked for faults before release: signatures, including those made by an
+// external signer, are verified against the public key, and decryptions are
+// computed twice and compared. A failed check returns a FaultError.
 package rsa
 
 import (
+	"crypto"
 	"crypto/rsa"
+	"errors"
 	"hash"
+
+	internalRsa "github.com/dromara/dongle/crypto/internal/rsa"
+	"github.com/dromara/dongle/crypto/keypair"
 )
 
 type cache struct {
-	pubKey *rsa.PublicKey  // Cached public key for better performance
-	priKey *rsa.PrivateKey // Cached private key for better performance
-	hash   hash.Hash       // Cached hash function for OAEP padding
+	pubKey    *rsa.PublicKey   // Cached public key for better performance
+	priKey    *rsa.PrivateKey  // Cached private key for better performance
+	hash      hash.Hash        // Cached hash function for signing and verification
+	oaep      *rsa.OAEPOptions // Cached hash, MGF1 hash and label for OAEP padding
+	signer    crypto.Signer    // External signer used when there is no private key
+	decrypter crypto.Decrypter // External decrypter used when there is no private key
+}
+
+// keySize returns the modulus size in bytes of the cached key.
+func (c *cache) keySize() int {
+	if c.priKey != nil {
+		return c.priKey.Size()
+	}
+	return c.pubKey.Size()
+}
+
+// chunkSize returns the largest plaintext that fits one RSA block of keySize
+// bytes with the padding of the key pair.
+func chunkSize(kp *keypair.RsaKeyPair, keySize int) int {
+	if kp.Padding == keypair.OAEP {
+		// OAEP padding overhead: 2*hashSize + 2
+		return keySize - 2*kp.Hash.Size() - 2
+	}
+	return keySize - 11
+}
+
+// signerOpts returns the options selecting the padding of the key pair for an
+// external signer.
+func signerOpts(kp *keypair.RsaKeyPair) crypto.SignerOpts {
+	if kp.Padding == keypair.PSS {
+		return &rsa.PSSOptions{Hash: kp.Hash}
+	}
+	return kp.Hash
+}
+
+// oaepOptions returns the OAEP hash, MGF1 hash and label of the key pair.
+func oaepOptions(kp *keypair.RsaKeyPair) *rsa.OAEPOptions {
+	return &rsa.OAEPOptions{Hash: kp.Hash, MGFHash: kp.MGFHash, Label: kp.Label}
+}
+
+// decrypterOpts returns the options selecting the padding of the key pair for
+// an external decrypter, nil meaning PKCS#1 v1.5.
+func decrypterOpts(kp *keypair.RsaKeyPair) crypto.DecrypterOpts {
+	if kp.Padding == keypair.OAEP {
+		return oaepOptions(kp)
+	}
+	return nil
+}
+
+// checkSigner verifies a signature made by an external signer against the
+// signer's public key before it is released, as the private key paths do, so
+// that a faulty HSM or KMS cannot leak a signature revealing a factor of the
+// modulus.
+func checkSigner(signer crypto.Signer, kp *keypair.RsaKeyPair, hashed, sign []byte) ([]byte, error) {
+	pub, ok := signer.Public().(*rsa.PublicKey)
+	if !ok {
+		return nil, internalRsa.ErrFault
+	}
+	return internalRsa.CheckSign(pub, kp.Padding == keypair.PSS, kp.Hash, hashed, sign)
+}
+
+// signError wraps a signing failure in SignError, or FaultError when the
+// signature failed its fault check.
+func signError(err error) error {
+	if errors.Is(err, internalRsa.ErrFault) {
+		return FaultError{Operation: "sign"}
+	}
+	return SignError{Err: err}
 }
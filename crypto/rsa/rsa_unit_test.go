@@ -3,12 +3,16 @@ package rsa
 import (
 	"bytes"
 	"crypto"
+	"crypto/rand"
 	stdRsa "crypto/rsa"
 	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/dromara/dongle/internal/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -149,7 +153,7 @@ func TestNewStdEncrypter(t *testing.T) {
 		require.Equal(t, keypair.PublicKey, e.keypair.Type)
 		require.Equal(t, keypair.OAEP, e.keypair.Padding)
 		require.NotNil(t, e.cache.pubKey)
-		require.NotNil(t, e.cache.hash)
+		require.NotNil(t, e.cache.oaep)
 	})
 
 	t.Run("pkcs1 default padding", func(t *testing.T) {
@@ -286,7 +290,7 @@ func TestStreamEncrypterNewAndEncrypt(t *testing.T) {
 		e := streamEncrypter(t, &bytes.Buffer{}, kp)
 		require.NoError(t, e.Error)
 		require.Equal(t, e.cache.pubKey.Size()-2*kp.Hash.Size()-2, e.chunkSize)
-		require.NotNil(t, e.cache.hash)
+		require.NotNil(t, e.cache.oaep)
 	})
 
 	t.Run("private key chunk size", func(t *testing.T) {
@@ -502,7 +506,7 @@ func TestNewStdDecrypter(t *testing.T) {
 		kp := mustKeyPair(t, keypair.PKCS8)
 		d := NewStdDecrypter(kp)
 		require.NoError(t, d.Error)
-		require.NotNil(t, d.cache.hash)
+		require.NotNil(t, d.cache.oaep)
 	})
 
 	t.Run("missing keys and invalid keys", func(t *testing.T) {
@@ -615,7 +619,7 @@ func TestStreamDecrypterNew(t *testing.T) {
 		kp := mustKeyPair(t, keypair.PKCS8)
 		d := streamDecrypter(t, bytes.NewReader(nil), kp)
 		require.NoError(t, d.Error)
-		require.NotNil(t, d.cache.hash)
+		require.NotNil(t, d.cache.oaep)
 	})
 
 	t.Run("errors", func(t *testing.T) {
@@ -1208,4 +1212,315 @@ func TestErrorMessages(t *testing.T) {
 	require.Contains(t, SignError{Err: base}.Error(), "boom")
 	require.Contains(t, VerifyError{Err: base}.Error(), "boom")
 	require.Contains(t, ReadError{Err: base}.Error(), "boom")
+	require.Contains(t, FaultError{Operation: "sign"}.Error(), "failed to sign data")
+}
+
+// faultySigner flips a bit of every signature, as a faulty HSM would.
+type faultySigner struct {
+	crypto.Signer
+}
+
+func (s faultySigner) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	sign, err := s.Signer.Sign(random, digest, opts)
+	if err == nil {
+		sign[len(sign)-1] ^= 0x01
+	}
+	return sign, err
+}
+
+func TestExternalKey(t *testing.T) {
+	key, err := stdRsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	data := []byte("hello world")
+
+	for _, padding := range []keypair.RsaPaddingScheme{keypair.PKCS1v15, keypair.PSS} {
+		t.Run("sign "+string(padding), func(t *testing.T) {
+			signer := mock.NewSigner(key)
+			kp := keypair.NewRsaKeyPair()
+			kp.SetPadding(padding)
+			require.NoError(t, kp.SetSigner(signer))
+
+			sign, err := mustStdSigner(t, kp).Sign(data)
+			require.NoError(t, err)
+			valid, err := mustStdVerifier(t, kp).Verify(data, sign)
+			require.NoError(t, err)
+			require.True(t, valid)
+
+			var out bytes.Buffer
+			ss := streamSigner(t, &out, kp)
+			_, err = ss.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, ss.Close())
+			valid, err = mustStdVerifier(t, kp).Verify(data, out.Bytes())
+			require.NoError(t, err)
+			require.True(t, valid)
+
+			signs, _ := signer.Calls()
+			require.Equal(t, 2, signs)
+		})
+	}
+
+	for _, padding := range []keypair.RsaPaddingScheme{keypair.PKCS1v15, keypair.OAEP} {
+		t.Run("decrypt "+string(padding), func(t *testing.T) {
+			decrypter := mock.NewSigner(key)
+			kp := keypair.NewRsaKeyPair()
+			kp.SetPadding(padding)
+			require.NoError(t, kp.SetDecrypter(decrypter))
+			enc := keypair.NewRsaKeyPair()
+			enc.SetPadding(padding)
+			enc.PublicKey = kp.PublicKey
+			enc.SetType(keypair.PublicKey)
+			ciphertext := encryptWith(t, enc, data)
+
+			plaintext, err := mustStdDecrypter(t, kp).Decrypt(ciphertext)
+			require.NoError(t, err)
+			require.Equal(t, data, plaintext)
+
+			plaintext, err = io.ReadAll(streamDecrypter(t, bytes.NewReader(ciphertext), kp))
+			require.NoError(t, err)
+			require.Equal(t, data, plaintext)
+
+			_, decrypts := decrypter.Calls()
+			require.Equal(t, 2, decrypts)
+		})
+	}
+
+	t.Run("external failure", func(t *testing.T) {
+		kp := keypair.NewRsaKeyPair()
+		kp.SetPadding(keypair.PKCS1v15)
+		require.NoError(t, kp.SetSigner(mock.NewSigner(key).FailWith(errors.New("hsm unavailable"))))
+		_, err := mustStdSigner(t, kp).Sign(data)
+		require.Equal(t, SignError{Err: errors.New("hsm unavailable")}, err)
+	})
+
+	t.Run("faulty signature", func(t *testing.T) {
+		for _, padding := range []keypair.RsaPaddingScheme{keypair.PKCS1v15, keypair.PSS} {
+			kp := keypair.NewRsaKeyPair()
+			kp.SetPadding(padding)
+			require.NoError(t, kp.SetSigner(faultySigner{mock.NewSigner(key)}))
+			sign, err := mustStdSigner(t, kp).Sign(data)
+			require.Equal(t, FaultError{Operation: "sign"}, err)
+			require.Empty(t, sign)
+		}
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		kp := keypair.NewRsaKeyPair()
+		require.Equal(t, SignError{Err: keypair.EmptyPrivateKeyError{}}, NewStdSigner(kp).Error)
+		require.Equal(t, DecryptError{Err: keypair.EmptyPrivateKeyError{}}, NewStdDecrypter(kp).Error)
+	})
+}
+
+func TestOAEPOptions(t *testing.T) {
+	kp := mustKeyPair(t, keypair.PKCS8)
+	kp.SetPadding(keypair.OAEP)
+	kp.SetMGFHash(crypto.SHA1)
+	kp.SetLabel([]byte("order:42"))
+	data := []byte("hello world")
+	key, err := kp.ParsePrivateKey()
+	require.NoError(t, err)
+	opts := &stdRsa.OAEPOptions{Hash: crypto.SHA256, MGFHash: crypto.SHA1, Label: []byte("order:42")}
+
+	t.Run("interoperates with OAEPWithSHA-256AndMGF1Padding", func(t *testing.T) {
+		ciphertext := encryptWith(t, kp, data)
+		plaintext, err := key.Decrypt(nil, ciphertext, opts)
+		require.NoError(t, err)
+		require.Equal(t, data, plaintext)
+
+		ciphertext, err = stdRsa.EncryptOAEP(crypto.SHA1.New(), rand.Reader, &key.PublicKey, data, opts.Label)
+		require.NoError(t, err)
+		_, err = mustStdDecrypter(t, kp).Decrypt(ciphertext)
+		require.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var out bytes.Buffer
+		e := streamEncrypter(t, &out, kp)
+		_, err := e.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, e.Close())
+		plaintext, err := io.ReadAll(streamDecrypter(t, bytes.NewReader(out.Bytes()), kp))
+		require.NoError(t, err)
+		require.Equal(t, data, plaintext)
+	})
+
+	t.Run("label mismatch", func(t *testing.T) {
+		ciphertext := encryptWith(t, kp, data)
+		other := *kp
+		other.SetLabel([]byte("order:43"))
+		_, err := mustStdDecrypter(t, &other).Decrypt(ciphertext)
+		require.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("external decrypter", func(t *testing.T) {
+		ext := keypair.NewRsaKeyPair()
+		ext.SetPadding(keypair.OAEP)
+		ext.SetMGFHash(crypto.SHA1)
+		ext.SetLabel([]byte("order:42"))
+		require.NoError(t, ext.SetDecrypter(mock.NewSigner(key)))
+		plaintext, err := mustStdDecrypter(t, ext).Decrypt(encryptWith(t, kp, data))
+		require.NoError(t, err)
+		require.Equal(t, data, plaintext)
+	})
+}
+
+func TestChunked(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 60)
+
+	for _, padding := range []keypair.RsaPaddingScheme{keypair.PKCS1v15, keypair.OAEP} {
+		t.Run(string(padding), func(t *testing.T) {
+			kp := mustSizedKeyPair(t, 1024, keypair.PKCS8)
+			kp.SetPadding(padding)
+
+			_, err := mustStdEncrypter(t, kp).Encrypt(data)
+			require.IsType(t, EncryptError{}, err)
+
+			kp.SetChunked(true)
+			ciphertext := encryptWith(t, kp, data)
+			size := chunkSize(kp, 128)
+			require.Len(t, ciphertext, (len(data)+size-1)/size*128)
+
+			plaintext, err := mustStdDecrypter(t, kp).Decrypt(ciphertext)
+			require.NoError(t, err)
+			require.Equal(t, data, plaintext)
+
+			// Chunked ciphertexts are what the stream encrypter writes
+			plaintext, err = io.ReadAll(streamDecrypter(t, bytes.NewReader(ciphertext), kp))
+			require.NoError(t, err)
+			require.Equal(t, data, plaintext)
+
+			var out bytes.Buffer
+			e := streamEncrypter(t, &out, kp)
+			_, err = e.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, e.Close())
+			plaintext, err = mustStdDecrypter(t, kp).Decrypt(out.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, data, plaintext)
+		})
+	}
+
+	t.Run("short block", func(t *testing.T) {
+		kp := mustSizedKeyPair(t, 1024, keypair.PKCS8)
+		kp.SetChunked(true)
+		plaintext := []byte("hello world")
+		ciphertext := encryptWith(t, kp, plaintext)
+		require.Len(t, ciphertext, 128)
+		decrypted, err := mustStdDecrypter(t, kp).Decrypt(ciphertext)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+
+		_, err = mustStdDecrypter(t, kp).Decrypt(ciphertext[:100])
+		require.Equal(t, DecryptError{Err: stdRsa.ErrDecryption}, err)
+		_, err = mustStdDecrypter(t, kp).Decrypt(append(ciphertext, make([]byte, 128)...))
+		require.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("blocks are not bound together", func(t *testing.T) {
+		kp := mustSizedKeyPair(t, 1024, keypair.PKCS8)
+		kp.SetPadding(keypair.OAEP)
+		kp.SetChunked(true)
+		size := chunkSize(kp, 128)
+		data := append(bytes.Repeat([]byte("a"), size), bytes.Repeat([]byte("b"), size)...)
+		ciphertext := encryptWith(t, kp, data)
+		require.Len(t, ciphertext, 256)
+
+		// Swapped blocks decrypt without error to a different plaintext
+		swapped := append(bytes.Clone(ciphertext[128:]), ciphertext[:128]...)
+		plaintext, err := mustStdDecrypter(t, kp).Decrypt(swapped)
+		require.NoError(t, err)
+		require.Equal(t, append(data[size:], data[:size]...), plaintext)
+
+		// So do dropped and duplicated blocks
+		plaintext, err = mustStdDecrypter(t, kp).Decrypt(ciphertext[:128])
+		require.NoError(t, err)
+		require.Equal(t, data[:size], plaintext)
+		plaintext, err = mustStdDecrypter(t, kp).Decrypt(append(bytes.Clone(ciphertext), ciphertext[:128]...))
+		require.NoError(t, err)
+		require.Equal(t, append(bytes.Clone(data), data[:size]...), plaintext)
+	})
+}
+
+func TestKeyUsage(t *testing.T) {
+	kp := mustKeyPair(t, keypair.PKCS8)
+	data := []byte("hello world")
+
+	t.Run("sign only", func(t *testing.T) {
+		kp := *kp
+		kp.SetUsage(keypair.Sign)
+		kp.SetPadding(keypair.PSS)
+		sign, err := mustStdSigner(t, &kp).Sign(data)
+		require.NoError(t, err)
+		valid, err := mustStdVerifier(t, &kp).Verify(data, sign)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		want := keypair.KeyUsageError{Usage: keypair.Sign, Operation: keypair.Encrypt}
+		require.Equal(t, EncryptError{Err: want}, NewStdEncrypter(&kp).Error)
+		require.Equal(t, DecryptError{Err: want}, NewStdDecrypter(&kp).Error)
+		_, err = NewStreamEncrypter(io.Discard, &kp).Write(data)
+		require.Equal(t, EncryptError{Err: want}, err)
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(data), &kp))
+		require.Equal(t, DecryptError{Err: want}, err)
+	})
+
+	t.Run("encrypt only", func(t *testing.T) {
+		kp := *kp
+		kp.SetUsage(keypair.Encrypt)
+		kp.SetPadding(keypair.OAEP)
+		plaintext, err := mustStdDecrypter(t, &kp).Decrypt(encryptWith(t, &kp, data))
+		require.NoError(t, err)
+		require.Equal(t, data, plaintext)
+
+		want := keypair.KeyUsageError{Usage: keypair.Encrypt, Operation: keypair.Sign}
+		require.Equal(t, SignError{Err: want}, NewStdSigner(&kp).Error)
+		require.Equal(t, VerifyError{Err: want}, NewStdVerifier(&kp).Error)
+		_, err = NewStreamSigner(io.Discard, &kp).Write(data)
+		require.Equal(t, SignError{Err: want}, err)
+		_, err = NewStreamVerifier(bytes.NewReader(nil), &kp).Write(data)
+		require.Equal(t, VerifyError{Err: want}, err)
+	})
+}
+
+func TestKeyValidity(t *testing.T) {
+	kp := mustKeyPair(t, keypair.PKCS8)
+	kp.SetPadding(keypair.PKCS1v15)
+	data := []byte("hello world")
+	sign, err := mustStdSigner(t, kp).Sign(data)
+	require.NoError(t, err)
+	ciphertext := encryptWith(t, kp, data)
+
+	kp.SetValidity(time.Time{}, time.Now().Add(-time.Hour))
+	var expired keypair.KeyExpiredError
+	require.ErrorAs(t, NewStdSigner(kp).Error.(SignError).Err, &expired)
+	require.ErrorAs(t, NewStdEncrypter(kp).Error.(EncryptError).Err, &expired)
+	_, err = NewStreamSigner(io.Discard, kp).Write(data)
+	require.IsType(t, SignError{}, err)
+	_, err = NewStreamEncrypter(io.Discard, kp).Write(data)
+	require.IsType(t, EncryptError{}, err)
+
+	// Data protected while the key was valid stays readable
+	valid, err := mustStdVerifier(t, kp).Verify(data, sign)
+	require.NoError(t, err)
+	require.True(t, valid)
+	plaintext, err := mustStdDecrypter(t, kp).Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, data, plaintext)
+
+	kp.SetAllowExpired(true)
+	_, err = mustStdSigner(t, kp).Sign(data)
+	require.NoError(t, err)
+
+	// A key that expires after the signer was created stops signing
+	kp.SetAllowExpired(false)
+	now := time.Now()
+	kp.SetValidity(time.Time{}, now.Add(time.Hour))
+	signer := mustStdSigner(t, kp)
+	encrypter := NewStdEncrypter(kp)
+	require.NoError(t, encrypter.Error)
+	t.Cleanup(utils.SetClock(func() time.Time { return now.Add(2 * time.Hour) }))
+	_, err = signer.Sign(data)
+	require.ErrorAs(t, err.(SignError).Err, &expired)
+	_, err = encrypter.Encrypt(data)
+	require.ErrorAs(t, err.(EncryptError).Err, &expired)
 }
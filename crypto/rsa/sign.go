@@ -1,11 +1,11 @@
 package rsa
 
 import (
-	"crypto/rand"
 	"io"
 
 	"github.com/dromara/dongle/crypto/internal/rsa"
 	"github.com/dromara/dongle/crypto/keypair"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 type StdSigner struct {
@@ -18,6 +18,10 @@ func NewStdSigner(kp *keypair.RsaKeyPair) *StdSigner {
 	s := &StdSigner{
 		keypair: *kp,
 	}
+	if err := kp.CheckUse(keypair.SignOp); err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
 	if s.keypair.Type == "" {
 		s.keypair.Type = keypair.PrivateKey
 	}
@@ -34,11 +38,15 @@ func NewStdSigner(kp *keypair.RsaKeyPair) *StdSigner {
 		s.cache.pubKey = pubKey
 	}
 
-	if s.keypair.Type == keypair.PrivateKey {
-		if len(s.keypair.PrivateKey) == 0 {
-			s.Error = SignError{Err: keypair.EmptyPrivateKeyError{}}
+	if s.keypair.Type == keypair.PrivateKey && len(s.keypair.PrivateKey) == 0 {
+		// Without a private key, use the external signer set on the key pair
+		signer, err := s.keypair.Signer()
+		if err != nil {
+			s.Error = SignError{Err: err}
 			return s
 		}
+		s.cache.signer = signer
+	} else if s.keypair.Type == keypair.PrivateKey {
 		priKey, err := s.keypair.ParsePrivateKey()
 		if err != nil {
 			s.Error = SignError{Err: err}
@@ -70,6 +78,11 @@ func (s *StdSigner) Sign(src []byte) (sign []byte, err error) {
 		err = s.Error
 		return
 	}
+	// The key may have expired since the signer was created
+	if err = s.keypair.CheckUse(keypair.SignOp); err != nil {
+		err = SignError{Err: err}
+		return
+	}
 	if len(src) == 0 {
 		return
 	}
@@ -77,19 +90,24 @@ func (s *StdSigner) Sign(src []byte) (sign []byte, err error) {
 	hasher.Write(src)
 	hashed := hasher.Sum(nil)
 	switch {
+	case s.cache.signer != nil:
+		sign, err = s.cache.signer.Sign(utils.Rand(), hashed, signerOpts(&s.keypair))
+		if err == nil {
+			sign, err = checkSigner(s.cache.signer, &s.keypair, hashed, sign)
+		}
 	case s.keypair.Type == keypair.PublicKey && s.keypair.Padding == keypair.PKCS1v15:
 		sign, err = rsa.SignPKCS1v15WithPublicKey(s.cache.pubKey, s.keypair.Hash, hashed)
 	case s.keypair.Type == keypair.PublicKey && s.keypair.Padding == keypair.PSS:
-		sign, err = rsa.SignPSSWithPublicKey(rand.Reader, s.cache.pubKey, s.keypair.Hash, hashed)
+		sign, err = rsa.SignPSSWithPublicKey(utils.Rand(), s.cache.pubKey, s.keypair.Hash, hashed)
 	case s.keypair.Type == keypair.PrivateKey && s.keypair.Padding == keypair.PKCS1v15:
-		sign, err = rsa.SignPKCS1v15WithPrivateKey(rand.Reader, s.cache.priKey, s.keypair.Hash, hashed)
+		sign, err = rsa.SignPKCS1v15WithPrivateKey(utils.Rand(), s.cache.priKey, s.keypair.Hash, hashed)
 	case s.keypair.Type == keypair.PrivateKey && s.keypair.Padding == keypair.PSS:
-		sign, err = rsa.SignPSSWithPrivateKey(rand.Reader, s.cache.priKey, s.keypair.Hash, hashed)
+		sign, err = rsa.SignPSSWithPrivateKey(utils.Rand(), s.cache.priKey, s.keypair.Hash, hashed)
 	default:
 		err = keypair.UnsupportedPaddingSchemeError{Padding: string(s.keypair.Padding)}
 	}
 	if err != nil {
-		err = SignError{Err: err}
+		err = signError(err)
 		return
 	}
 	return
@@ -107,6 +125,10 @@ func NewStreamSigner(w io.Writer, kp *keypair.RsaKeyPair) io.WriteCloser {
 		keypair: *kp,
 		writer:  w,
 	}
+	if err := kp.CheckUse(keypair.SignOp); err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
 	if s.keypair.Type == "" {
 		s.keypair.Type = keypair.PrivateKey
 	}
@@ -123,11 +145,15 @@ func NewStreamSigner(w io.Writer, kp *keypair.RsaKeyPair) io.WriteCloser {
 		s.cache.pubKey = pubKey
 	}
 
-	if s.keypair.Type == keypair.PrivateKey {
-		if len(s.keypair.PrivateKey) == 0 {
-			s.Error = SignError{Err: keypair.EmptyPrivateKeyError{}}
+	if s.keypair.Type == keypair.PrivateKey && len(s.keypair.PrivateKey) == 0 {
+		// Without a private key, use the external signer set on the key pair
+		signer, err := s.keypair.Signer()
+		if err != nil {
+			s.Error = SignError{Err: err}
 			return s
 		}
+		s.cache.signer = signer
+	} else if s.keypair.Type == keypair.PrivateKey {
 		priKey, err := s.keypair.ParsePrivateKey()
 		if err != nil {
 			s.Error = SignError{Err: err}
@@ -159,23 +185,33 @@ func (s *StreamSigner) sign(data []byte) (dst []byte, err error) {
 		err = s.Error
 		return
 	}
+	// The key may have expired since the signer was created
+	if err = s.keypair.CheckUse(keypair.SignOp); err != nil {
+		err = SignError{Err: err}
+		return
+	}
 	if len(data) == 0 {
 		return
 	}
 	switch {
+	case s.cache.signer != nil:
+		dst, err = s.cache.signer.Sign(utils.Rand(), data, signerOpts(&s.keypair))
+		if err == nil {
+			dst, err = checkSigner(s.cache.signer, &s.keypair, data, dst)
+		}
 	case s.keypair.Type == keypair.PublicKey && s.keypair.Padding == keypair.PKCS1v15:
 		dst, err = rsa.SignPKCS1v15WithPublicKey(s.cache.pubKey, s.keypair.Hash, data)
 	case s.keypair.Type == keypair.PublicKey && s.keypair.Padding == keypair.PSS:
-		dst, err = rsa.SignPSSWithPublicKey(rand.Reader, s.cache.pubKey, s.keypair.Hash, data)
+		dst, err = rsa.SignPSSWithPublicKey(utils.Rand(), s.cache.pubKey, s.keypair.Hash, data)
 	case s.keypair.Type == keypair.PrivateKey && s.keypair.Padding == keypair.PKCS1v15:
-		dst, err = rsa.SignPKCS1v15WithPrivateKey(rand.Reader, s.cache.priKey, s.keypair.Hash, data)
+		dst, err = rsa.SignPKCS1v15WithPrivateKey(utils.Rand(), s.cache.priKey, s.keypair.Hash, data)
 	case s.keypair.Type == keypair.PrivateKey && s.keypair.Padding == keypair.PSS:
-		dst, err = rsa.SignPSSWithPrivateKey(rand.Reader, s.cache.priKey, s.keypair.Hash, data)
+		dst, err = rsa.SignPSSWithPrivateKey(utils.Rand(), s.cache.priKey, s.keypair.Hash, data)
 	default:
 		err = keypair.UnsupportedPaddingSchemeError{Padding: string(s.keypair.Padding)}
 	}
 	if err != nil {
-		err = SignError{Err: err}
+		err = signError(err)
 		return
 	}
 	return
@@ -17,6 +17,10 @@ func NewStdVerifier(kp *keypair.RsaKeyPair) *StdVerifier {
 	v := &StdVerifier{
 		keypair: *kp,
 	}
+	if err := kp.CheckUse(keypair.VerifyOp); err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
 	if v.keypair.Type == "" {
 		v.keypair.Type = keypair.PublicKey
 	}
@@ -96,6 +100,10 @@ func NewStreamVerifier(r io.Reader, kp *keypair.RsaKeyPair) io.WriteCloser {
 		keypair: *kp,
 		reader:  r,
 	}
+	if err := kp.CheckUse(keypair.VerifyOp); err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
 	if v.keypair.Type == "" {
 		v.keypair.Type = keypair.PublicKey
 	}
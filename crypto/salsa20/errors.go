@@ -2,6 +2,8 @@ package salsa20
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the Salsa20 key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/salsa20: invalid key size %d, must be exactly 32 bytes", k)
 }
 
+// Code returns the stable error code DGL-SALSA20-001.
+func (k KeySizeError) Code() string {
+	return "DGL-SALSA20-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/salsa20", "Salsa20", "key", errcode.FieldKeySize, int(k))
+}
+
 // NonceSizeError represents an error when the Salsa20 nonce size is invalid.
 // Salsa20 nonces must be exactly 8 bytes (64 bits) long.
 // This error occurs when the provided nonce does not meet this size requirement.
@@ -26,6 +38,16 @@ func (n NonceSizeError) Error() string {
 	return fmt.Sprintf("crypto/salsa20: invalid nonce size %d, must be exactly 8 bytes", n)
 }
 
+// Code returns the stable error code DGL-SALSA20-002.
+func (n NonceSizeError) Code() string {
+	return "DGL-SALSA20-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (n NonceSizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/salsa20", "Salsa20", "", "nonce_size", int(n))
+}
+
 // EncryptError represents an error when Salsa20 encryption fails.
 // This error occurs when the underlying Salsa20 encryption operation fails.
 // The error includes the underlying error for detailed debugging.
@@ -39,6 +61,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/salsa20: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SALSA20-003.
+func (e EncryptError) Code() string {
+	return "DGL-SALSA20-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/salsa20", "Salsa20", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when Salsa20 decryption fails.
 // This error occurs when the underlying Salsa20 decryption operation fails.
 // The error includes the underlying error for detailed debugging.
@@ -52,6 +84,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/salsa20: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SALSA20-004.
+func (e DecryptError) Code() string {
+	return "DGL-SALSA20-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/salsa20", "Salsa20", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // WriteError represents an error when writing encrypted data fails.
 // This error occurs when writing encrypted data to the underlying writer fails.
 // The error includes the underlying error for detailed debugging.
@@ -65,6 +107,16 @@ func (e WriteError) Error() string {
 	return fmt.Sprintf("crypto/salsa20: failed to write encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SALSA20-005.
+func (e WriteError) Code() string {
+	return "DGL-SALSA20-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WriteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/salsa20", "Salsa20", "write", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -77,3 +129,13 @@ type ReadError struct {
 func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/salsa20: failed to read encrypted data: %v", e.Err)
 }
+
+// Code returns the stable error code DGL-SALSA20-006.
+func (e ReadError) Code() string {
+	return "DGL-SALSA20-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/salsa20", "Salsa20", "read", errcode.FieldCause, e.Err)
+}
@@ -0,0 +1,178 @@
+package signedurl
+
+import (
+	"crypto"
+	"fmt"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidKeySizeError represents an error when the HMAC key is shorter than
+// MinKeySize.
+type InvalidKeySizeError struct {
+	Size int // The rejected key size in bytes
+}
+
+// Error returns a formatted error message describing the invalid key size.
+func (e InvalidKeySizeError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: invalid key size %d, must be at least %d bytes", e.Size, MinKeySize)
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-001.
+func (e InvalidKeySizeError) Code() string {
+	return "DGL-SIGNEDURL-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidKeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "", "size", e.Size)
+}
+
+// UnsupportedHashError represents an error when the HMAC hash function is not
+// linked into the binary.
+type UnsupportedHashError struct {
+	Hash crypto.Hash // The unavailable hash function
+}
+
+// Error returns a formatted error message describing the unavailable hash.
+func (e UnsupportedHashError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: unsupported hash %s", e.Hash)
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-002.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-SIGNEDURL-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "", "hash", e.Hash.String())
+}
+
+// InvalidTTLError represents an error when a URL would already be expired
+// when signed.
+type InvalidTTLError struct {
+	TTL time.Duration // The rejected time to live
+}
+
+// Error returns a formatted error message describing the invalid time to live.
+func (e InvalidTTLError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: invalid ttl %s, must be positive", e.TTL)
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-003.
+func (e InvalidTTLError) Code() string {
+	return "DGL-SIGNEDURL-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidTTLError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "sign", "ttl", e.TTL.String())
+}
+
+// InvalidURLError represents an error when a URL cannot be parsed, or when a
+// signed URL has no valid expiry.
+type InvalidURLError struct {
+	URL string // The rejected URL
+}
+
+// Error returns a formatted error message describing the invalid URL.
+func (e InvalidURLError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: invalid url %q", e.URL)
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-004.
+func (e InvalidURLError) Code() string {
+	return "DGL-SIGNEDURL-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidURLError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "")
+}
+
+// InvalidScopeError represents an error when a URL cannot be signed with a
+// scope, because the URL already carries one of the signed parameters, the IP
+// is not an address or the prefix does not match the path.
+type InvalidScopeError struct {
+	Param string // The offending query parameter
+	Value string // The rejected value
+}
+
+// Error returns a formatted error message describing the invalid scope.
+func (e InvalidScopeError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: invalid %s %q", e.Param, e.Value)
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-005.
+func (e InvalidScopeError) Code() string {
+	return "DGL-SIGNEDURL-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidScopeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "sign", "param", e.Param)
+}
+
+// SignatureError represents an error when a URL has no signature, was signed
+// with another key or was modified after signing.
+type SignatureError struct{}
+
+// Error returns a formatted error message describing the signature mismatch.
+func (e SignatureError) Error() string {
+	return "crypto/signedurl: invalid signature"
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-006.
+func (e SignatureError) Code() string {
+	return "DGL-SIGNEDURL-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "verify")
+}
+
+// ExpiredError represents an error when a URL is used after its expiry and the
+// skew tolerance.
+type ExpiredError struct {
+	Expiry time.Time // When the URL expired
+}
+
+// Error returns a formatted error message describing the expired URL.
+func (e ExpiredError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: url expired at %s", e.Expiry.UTC().Format(time.RFC3339))
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-007.
+func (e ExpiredError) Code() string {
+	return "DGL-SIGNEDURL-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ExpiredError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "verify", "expiry", e.Expiry.UTC().Format(time.RFC3339))
+}
+
+// ScopeError represents an error when a validly signed URL is used with
+// another method, from another client or for a path outside its prefix.
+type ScopeError struct {
+	Param string // The scope parameter that did not match
+	Value string // The method, client IP or path in use
+}
+
+// Error returns a formatted error message describing the scope mismatch.
+func (e ScopeError) Error() string {
+	return fmt.Sprintf("crypto/signedurl: %s %q out of scope", e.Param, e.Value)
+}
+
+// Code returns the stable error code DGL-SIGNEDURL-008.
+func (e ScopeError) Code() string {
+	return "DGL-SIGNEDURL-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ScopeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/signedurl", "", "verify", "param", e.Param)
+}
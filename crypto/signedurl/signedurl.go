@@ -0,0 +1,228 @@
+// Package signedurl mints and validates HMAC-signed URLs. A signed URL carries
+// its expiry, and optionally the HTTP method, client IP and path prefix it is
+// restricted to, as query parameters covered by an HMAC over the path and the
+// query. Validation checks the signature first, in constant time, then the
+// expiry with a tolerance for clock skew between the minting and validating
+// servers, then the scope.
+//
+// Only the path and query are signed, so a URL minted for one host validates
+// on every host sharing the key, and a server can validate the request URI it
+// received without knowing the public host name.
+package signedurl
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"encoding/base64"
+	"net/netip"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Query parameters added to signed URLs.
+const (
+	ExpiresParam   = "expires"
+	MethodParam    = "method"
+	IPParam        = "ip"
+	PrefixParam    = "prefix"
+	SignatureParam = "signature"
+)
+
+// MinKeySize is the minimum HMAC key size in bytes.
+const MinKeySize = 16
+
+// DefaultSkew is the default tolerance for clock skew when checking expiries.
+const DefaultSkew = 30 * time.Second
+
+// Scope restricts what a signed URL may be used for. The zero Scope allows
+// any method, any client and only the exact signed path.
+type Scope struct {
+	// Method is the only HTTP method the URL may be used with, such as GET.
+	Method string
+	// IP is the only client address the URL may be used from.
+	IP string
+	// Prefix makes the URL valid for every path below it instead of only the
+	// signed path, such as "/downloads/" for a whole directory. It must be a
+	// prefix of the signed path; end it with a slash to stop "/downloads"
+	// from also covering "/downloads-private".
+	Prefix string
+}
+
+// Signer mints and validates signed URLs with a shared HMAC key.
+type Signer struct {
+	key   []byte           // HMAC key
+	hash  crypto.Hash      // HMAC hash function, SHA-256 by default
+	skew  time.Duration    // Tolerance for clock skew when checking expiries
+	now   func() time.Time // Clock returning the current time
+	Error error            // Error field for storing configuration errors
+}
+
+// NewSigner returns a new Signer using HMAC-SHA256 with key, which must be at
+// least MinKeySize bytes long and should be random.
+func NewSigner(key []byte) *Signer {
+	s := &Signer{
+		key:  append([]byte(nil), key...),
+		hash: crypto.SHA256,
+		skew: DefaultSkew,
+		now:  utils.Now,
+	}
+	if len(key) < MinKeySize {
+		s.Error = InvalidKeySizeError{Size: len(key)}
+	}
+	return s
+}
+
+// SetHash sets the HMAC hash function, such as crypto.SHA512.
+func (s *Signer) SetHash(hash crypto.Hash) {
+	if !hash.Available() {
+		s.Error = UnsupportedHashError{Hash: hash}
+		return
+	}
+	s.hash = hash
+}
+
+// SetSkew sets the tolerance for clock skew: a URL is still accepted for skew
+// after its expiry. Negative values are treated as zero.
+func (s *Signer) SetSkew(skew time.Duration) {
+	s.skew = max(skew, 0)
+}
+
+// SetClock sets the function returning the current time.
+func (s *Signer) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// Sign returns rawURL signed so that it expires ttl from now and may only be
+// used within scope. rawURL may be absolute or a bare path with a query, and
+// must not already carry any of the parameters added by Sign.
+func (s *Signer) Sign(rawURL string, ttl time.Duration, scope Scope) (string, error) {
+	if ttl <= 0 {
+		return "", InvalidTTLError{TTL: ttl}
+	}
+	return s.SignUntil(rawURL, s.now().Add(ttl), scope)
+}
+
+// SignUntil returns rawURL signed so that it expires at expiry, truncated to
+// the second, and may only be used within scope.
+func (s *Signer) SignUntil(rawURL string, expiry time.Time, scope Scope) (string, error) {
+	if s.Error != nil {
+		return "", s.Error
+	}
+	if !expiry.After(s.now()) {
+		return "", InvalidTTLError{TTL: expiry.Sub(s.now())}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", InvalidURLError{URL: rawURL}
+	}
+	query := u.Query()
+	for _, param := range []string{ExpiresParam, MethodParam, IPParam, PrefixParam, SignatureParam} {
+		if query.Has(param) {
+			return "", InvalidScopeError{Param: param, Value: query.Get(param)}
+		}
+	}
+
+	query.Set(ExpiresParam, strconv.FormatInt(expiry.Unix(), 10))
+	if scope.Method != "" {
+		query.Set(MethodParam, strings.ToUpper(scope.Method))
+	}
+	if scope.IP != "" {
+		addr, err := netip.ParseAddr(scope.IP)
+		if err != nil {
+			return "", InvalidScopeError{Param: IPParam, Value: scope.IP}
+		}
+		query.Set(IPParam, addr.Unmap().String())
+	}
+	if scope.Prefix != "" {
+		if !strings.HasPrefix(cleanPath(u.Path), scope.Prefix) {
+			return "", InvalidScopeError{Param: PrefixParam, Value: scope.Prefix}
+		}
+		query.Set(PrefixParam, scope.Prefix)
+	}
+
+	query.Set(SignatureParam, base64.RawURLEncoding.EncodeToString(s.mac(u, query)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Verify validates a signed URL used with method from clientIP. rawURL may be
+// absolute or the request URI received by the server. The client IP is only
+// checked when the URL is bound to one; behind a proxy it must be the address
+// of the client, not of the proxy.
+//
+// Verify returns a SignatureError when the URL was not signed with the key or
+// was modified, an ExpiredError when it expired more than the skew ago, and a
+// ScopeError when it is used outside its scope.
+func (s *Signer) Verify(rawURL, method, clientIP string) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return InvalidURLError{URL: rawURL}
+	}
+	query := u.Query()
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get(SignatureParam))
+	if err != nil || len(signature) == 0 || !utils.ConstantTimeEqual(signature, s.mac(u, query)) {
+		return SignatureError{}
+	}
+
+	seconds, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return InvalidURLError{URL: rawURL}
+	}
+	expiry := time.Unix(seconds, 0).UTC()
+	if s.now().After(expiry.Add(s.skew)) {
+		return ExpiredError{Expiry: expiry}
+	}
+
+	if want := query.Get(MethodParam); want != "" && !strings.EqualFold(want, method) {
+		return ScopeError{Param: MethodParam, Value: method}
+	}
+	if want := query.Get(IPParam); want != "" {
+		addr, err := netip.ParseAddr(clientIP)
+		if err != nil || addr.Unmap().String() != want {
+			return ScopeError{Param: IPParam, Value: clientIP}
+		}
+	}
+	if want := query.Get(PrefixParam); want != "" && !strings.HasPrefix(cleanPath(u.Path), want) {
+		return ScopeError{Param: PrefixParam, Value: u.Path}
+	}
+	return nil
+}
+
+// mac returns the HMAC of the URL's path, or of its prefix for URLs scoped to
+// one, and of its query without the signature, in canonical order.
+func (s *Signer) mac(u *url.URL, query url.Values) []byte {
+	signed := make(url.Values, len(query))
+	for k, v := range query {
+		if k != SignatureParam {
+			signed[k] = v
+		}
+	}
+	target := u.EscapedPath()
+	if prefix := query.Get(PrefixParam); prefix != "" {
+		target = prefix
+	}
+	h := hmac.New(s.hash.New, s.key)
+	h.Write([]byte(target))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(signed.Encode()))
+	return h.Sum(nil)
+}
+
+// cleanPath returns p with dot segments resolved, so that a path such as
+// "/downloads/../admin" is not mistaken for one below "/downloads/". A
+// trailing slash is kept.
+func cleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
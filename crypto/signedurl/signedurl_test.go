@@ -0,0 +1,202 @@
+package signedurl
+
+import (
+	"crypto"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var key = []byte("0123456789abcdef0123456789abcdef")
+
+func newSigner(clock *mock.Clock) *Signer {
+	s := NewSigner(key)
+	s.SetClock(clock.Now)
+	return s
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	clock := mock.NewClock(now)
+	s := newSigner(clock)
+
+	signed, err := s.Sign("https://cdn.example.com/files/report.pdf?v=2", time.Hour, Scope{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "https://cdn.example.com/files/report.pdf?"))
+	u, _ := url.Parse(signed)
+	assert.Equal(t, "1792155600", u.Query().Get(ExpiresParam))
+	assert.Equal(t, "2", u.Query().Get("v"))
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, s.Verify(signed, "GET", "203.0.113.7"))
+		// Servers see the request URI only
+		assert.NoError(t, s.Verify(u.RequestURI(), "POST", ""))
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		again, err := s.Sign("https://cdn.example.com/files/report.pdf?v=2", time.Hour, Scope{})
+		require.NoError(t, err)
+		assert.Equal(t, signed, again)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		for _, forged := range []string{
+			strings.Replace(signed, "report.pdf", "secret.pdf", 1),
+			strings.Replace(signed, "v=2", "v=3", 1),
+			strings.Replace(signed, "expires=1792155600", "expires=1792159200", 1),
+			signed + "&extra=1",
+		} {
+			assert.Equal(t, SignatureError{}, s.Verify(forged, "GET", ""), forged)
+		}
+	})
+
+	t.Run("missing or malformed signature", func(t *testing.T) {
+		assert.Equal(t, SignatureError{}, s.Verify("https://cdn.example.com/files/report.pdf?expires=1792155600", "GET", ""))
+		query := u.Query()
+		query.Set(SignatureParam, "not base64!")
+		assert.Equal(t, SignatureError{}, s.Verify("/files/report.pdf?"+query.Encode(), "GET", ""))
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		other := NewSigner([]byte("fedcba9876543210fedcba9876543210"))
+		assert.Equal(t, SignatureError{}, other.Verify(signed, "GET", ""))
+	})
+
+	t.Run("expiry and skew", func(t *testing.T) {
+		clock.Set(now.Add(time.Hour + DefaultSkew))
+		assert.NoError(t, s.Verify(signed, "GET", ""))
+
+		clock.Set(now.Add(time.Hour + DefaultSkew + time.Second))
+		err := s.Verify(signed, "GET", "")
+		assert.Equal(t, ExpiredError{Expiry: now.Add(time.Hour)}, err)
+		assert.Equal(t, "crypto/signedurl: url expired at 2026-10-16T13:00:00Z", err.Error())
+
+		s.SetSkew(2 * time.Minute)
+		assert.NoError(t, s.Verify(signed, "GET", ""))
+		s.SetSkew(-time.Minute)
+		clock.Set(now.Add(time.Hour + time.Second))
+		assert.IsType(t, ExpiredError{}, s.Verify(signed, "GET", ""))
+		s.SetSkew(DefaultSkew)
+		clock.Set(now)
+	})
+}
+
+func TestScope(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := newSigner(mock.NewClock(now))
+
+	t.Run("method", func(t *testing.T) {
+		signed, err := s.Sign("/upload/avatar.png", time.Minute, Scope{Method: "put"})
+		require.NoError(t, err)
+		assert.Contains(t, signed, "method=PUT")
+		assert.NoError(t, s.Verify(signed, "PUT", ""))
+		assert.NoError(t, s.Verify(signed, "put", ""))
+		assert.Equal(t, ScopeError{Param: MethodParam, Value: "GET"}, s.Verify(signed, "GET", ""))
+		assert.Equal(t, SignatureError{}, s.Verify(strings.Replace(signed, "method=PUT", "method=GET", 1), "GET", ""))
+	})
+
+	t.Run("ip", func(t *testing.T) {
+		signed, err := s.Sign("/files/a.txt", time.Minute, Scope{IP: "::ffff:203.0.113.7"})
+		require.NoError(t, err)
+		assert.Contains(t, signed, "ip=203.0.113.7")
+		assert.NoError(t, s.Verify(signed, "GET", "203.0.113.7"))
+		assert.NoError(t, s.Verify(signed, "GET", "::ffff:203.0.113.7"))
+		assert.Equal(t, ScopeError{Param: IPParam, Value: "203.0.113.8"}, s.Verify(signed, "GET", "203.0.113.8"))
+		assert.Equal(t, ScopeError{Param: IPParam, Value: ""}, s.Verify(signed, "GET", ""))
+
+		signed, err = s.Sign("/files/a.txt", time.Minute, Scope{IP: "2001:DB8::1"})
+		require.NoError(t, err)
+		assert.NoError(t, s.Verify(signed, "GET", "2001:db8:0::1"))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		signed, err := s.Sign("/downloads/album/01.mp3", time.Minute, Scope{Prefix: "/downloads/album/"})
+		require.NoError(t, err)
+		query := signed[strings.Index(signed, "?"):]
+		assert.NoError(t, s.Verify(signed, "GET", ""))
+		assert.NoError(t, s.Verify("https://cdn.example.com/downloads/album/02.mp3"+query, "GET", ""))
+		assert.Equal(t, ScopeError{Param: PrefixParam, Value: "/downloads/other/01.mp3"}, s.Verify("/downloads/other/01.mp3"+query, "GET", ""))
+		assert.Equal(t, ScopeError{Param: PrefixParam, Value: "/downloads/album/../../admin"}, s.Verify("/downloads/album/../../admin"+query, "GET", ""))
+		assert.Equal(t, SignatureError{}, s.Verify("/downloads/album/01.mp3"+strings.Replace(query, "album", "other", 1), "GET", ""))
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		signed, err := s.Sign("/api/export?format=csv", time.Minute, Scope{Method: "GET", IP: "10.0.0.1"})
+		require.NoError(t, err)
+		assert.NoError(t, s.Verify(signed, "GET", "10.0.0.1"))
+		assert.IsType(t, ScopeError{}, s.Verify(signed, "DELETE", "10.0.0.1"))
+		assert.IsType(t, ScopeError{}, s.Verify(signed, "GET", "10.0.0.2"))
+	})
+}
+
+func TestSigner_Errors(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := newSigner(mock.NewClock(now))
+
+	t.Run("key size", func(t *testing.T) {
+		short := NewSigner([]byte("short"))
+		assert.Equal(t, InvalidKeySizeError{Size: 5}, short.Error)
+		_, err := short.Sign("/a", time.Minute, Scope{})
+		assert.Equal(t, InvalidKeySizeError{Size: 5}, err)
+		assert.Equal(t, InvalidKeySizeError{Size: 5}, short.Verify("/a", "GET", ""))
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		sha512 := newSigner(mock.NewClock(now))
+		sha512.SetHash(crypto.SHA512)
+		signed, err := sha512.Sign("/a", time.Minute, Scope{})
+		require.NoError(t, err)
+		assert.NoError(t, sha512.Verify(signed, "GET", ""))
+		assert.Equal(t, SignatureError{}, s.Verify(signed, "GET", ""))
+
+		sha512.SetHash(crypto.Hash(0))
+		assert.Equal(t, UnsupportedHashError{Hash: crypto.Hash(0)}, sha512.Error)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		_, err := s.Sign("/a", 0, Scope{})
+		assert.Equal(t, InvalidTTLError{TTL: 0}, err)
+		_, err = s.SignUntil("/a", now.Add(-time.Second), Scope{})
+		assert.Equal(t, InvalidTTLError{TTL: -time.Second}, err)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		_, err := s.Sign("http://[::1", time.Minute, Scope{})
+		assert.Equal(t, InvalidURLError{URL: "http://[::1"}, err)
+		assert.Equal(t, InvalidURLError{URL: "http://[::1"}, s.Verify("http://[::1", "GET", ""))
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		_, err := s.Sign("/a?expires=1", time.Minute, Scope{})
+		assert.Equal(t, InvalidScopeError{Param: ExpiresParam, Value: "1"}, err)
+		_, err = s.Sign("/a", time.Minute, Scope{IP: "localhost"})
+		assert.Equal(t, InvalidScopeError{Param: IPParam, Value: "localhost"}, err)
+		_, err = s.Sign("/a/b", time.Minute, Scope{Prefix: "/c/"})
+		assert.Equal(t, InvalidScopeError{Param: PrefixParam, Value: "/c/"}, err)
+		_, err = s.Sign("/a/../c/b", time.Minute, Scope{Prefix: "/a/"})
+		assert.Equal(t, InvalidScopeError{Param: PrefixParam, Value: "/a/"}, err)
+	})
+
+	t.Run("codes", func(t *testing.T) {
+		for code, err := range map[string]errcode.Coder{
+			"DGL-SIGNEDURL-001": InvalidKeySizeError{},
+			"DGL-SIGNEDURL-002": UnsupportedHashError{},
+			"DGL-SIGNEDURL-003": InvalidTTLError{},
+			"DGL-SIGNEDURL-004": InvalidURLError{},
+			"DGL-SIGNEDURL-005": InvalidScopeError{},
+			"DGL-SIGNEDURL-006": SignatureError{},
+			"DGL-SIGNEDURL-007": ExpiredError{},
+			"DGL-SIGNEDURL-008": ScopeError{},
+		} {
+			assert.Equal(t, code, err.Code())
+			assert.Equal(t, "crypto/signedurl", err.Fields()[errcode.FieldPackage])
+			assert.NotEmpty(t, err.Error())
+		}
+	})
+}
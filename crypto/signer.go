@@ -6,6 +6,10 @@ import (
 	"io/fs"
 
 	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/jcs"
+	"github.com/dromara/dongle/coding/pem"
+	"github.com/dromara/dongle/coding/text"
 	"github.com/dromara/dongle/internal/utils"
 )
 
@@ -14,6 +18,7 @@ type Signer struct {
 	data   []byte
 	sign   []byte
 	reader io.Reader
+	text   text.Option
 	Error  error
 }
 
@@ -24,22 +29,78 @@ func NewSigner() Signer {
 
 // FromString signs from string.
 func (s Signer) FromString(str string) Signer {
-	s.data = utils.String2Bytes(str)
+	utils.AuditShared()
+	s.data = text.Canonicalize(utils.String2Bytes(str), s.text)
 	return s
 }
 
 // FromBytes signs from byte slice.
 func (s Signer) FromBytes(b []byte) Signer {
-	s.data = b
+	utils.AuditShared()
+	s.data = text.Canonicalize(b, s.text)
 	return s
 }
 
 // FromFile signs from file.
 func (s Signer) FromFile(f fs.File) Signer {
-	s.reader = f
+	utils.AuditShared()
+	s.reader = text.NewReader(f, s.text)
 	return s
 }
 
+// FromFS signs from the named file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (s Signer) FromFS(fsys fs.FS, name string) Signer {
+	utils.AuditShared()
+	if s.Error != nil {
+		return s
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		s.Error = err
+		return s
+	}
+	return s.FromFile(f)
+}
+
+// FromJson signs the RFC 8785 canonical JSON encoding of doc, so that
+// documents serialized with different whitespace or member order give the same
+// result. Raw JSON can be passed as json.RawMessage.
+func (s Signer) FromJson(doc any) Signer {
+	utils.AuditShared()
+	data, err := jcs.Marshal(doc)
+	if err != nil {
+		s.Error = err
+		return s
+	}
+	s.data = data
+	return s
+}
+
+// WithCanonicalText canonicalizes string, byte slice and file input as text
+// before signing, so that a text file checked out on another platform still
+// verifies. It applies to input given before and after the call, and adds to
+// the options of earlier calls.
+func (s Signer) WithCanonicalText(opts text.Option) Signer {
+	if s.data != nil {
+		s.data = text.Canonicalize(s.data, opts)
+	}
+	if s.reader != nil {
+		s.reader = text.NewReader(s.reader, opts)
+	}
+	s.text |= opts
+	return s
+}
+
+// WithUnicodeNormalization normalizes string, byte slice and file input to
+// text.NFC or text.NFKC before signing, so that user entered text such as a
+// password or a name verifies on every platform, whichever normalization form
+// the keyboard produced. Other options in form are ignored.
+func (s Signer) WithUnicodeNormalization(form text.Option) Signer {
+	return s.WithCanonicalText(form & text.Forms)
+}
+
 // ToRawString outputs as raw string.
 func (s Signer) ToRawString() string {
 	if len(s.data) == 0 || s.Error != nil {
@@ -56,6 +117,11 @@ func (s Signer) ToRawBytes() []byte {
 	return s.sign
 }
 
+// To outputs as a Result exposing the signature in several encodings.
+func (s Signer) To() coding.Result {
+	return coding.NewResult(s.ToRawBytes(), s.Error)
+}
+
 // ToBase64String outputs as base64 string.
 func (s Signer) ToBase64String() string {
 	return coding.NewEncoder().FromBytes(s.sign).ByBase64().ToString()
@@ -76,7 +142,28 @@ func (s Signer) ToHexBytes() []byte {
 	return coding.NewEncoder().FromBytes(s.sign).ByHex().ToBytes()
 }
 
+// ToHexFormat outputs as hex string in the given format.
+func (s Signer) ToHexFormat(f hex.Format) string {
+	return f.Encode(s.sign)
+}
+
+// ToPemString outputs as PEM armored string with the given block type,
+// "DONGLE SIGNATURE" when empty. It returns an empty string for an invalid type.
+func (s Signer) ToPemString(blockType string) string {
+	return utils.Bytes2String(s.ToPemBytes(blockType))
+}
+
+// ToPemBytes outputs as PEM armored byte slice with the given block type,
+// "DONGLE SIGNATURE" when empty. It returns an empty slice for an invalid type.
+func (s Signer) ToPemBytes(blockType string) []byte {
+	if len(s.data) == 0 || s.Error != nil {
+		return []byte{}
+	}
+	return armor(s.sign, blockType, pem.SignatureType)
+}
+
 func (s Signer) stream(fn func(io.Writer) io.WriteCloser) ([]byte, error) {
+	defer utils.CloseFS(s.reader)
 	var buf bytes.Buffer
 	signer := fn(&buf)
 
@@ -1,11 +1,18 @@
 package crypto
 
 import (
+	"encoding/json"
 	"io"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/text"
+	"github.com/dromara/dongle/crypto/keypair"
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSigner_FromString(t *testing.T) {
@@ -112,6 +119,96 @@ func TestSigner_FromFile(t *testing.T) {
 	})
 }
 
+func TestSigner_FromJson(t *testing.T) {
+	t.Run("from struct", func(t *testing.T) {
+		doc := struct {
+			B int    `json:"b"`
+			A string `json:"a"`
+		}{B: 1, A: "x"}
+		signer := NewSigner().FromJson(doc)
+		assert.Nil(t, signer.Error)
+		assert.Equal(t, []byte(`{"a":"x","b":1}`), signer.data)
+	})
+
+	t.Run("from raw message", func(t *testing.T) {
+		signer := NewSigner().FromJson(json.RawMessage(` { "b" : 1.0 , "a" : "x" } `))
+		assert.Nil(t, signer.Error)
+		assert.Equal(t, []byte(`{"a":"x","b":1}`), signer.data)
+	})
+
+	t.Run("from invalid json", func(t *testing.T) {
+		signer := NewSigner().FromJson(json.RawMessage(`{"a":1,"a":2}`))
+		assert.Error(t, signer.Error)
+		assert.Nil(t, signer.data)
+	})
+
+	t.Run("sign and verify reordered document", func(t *testing.T) {
+		kp := keypair.NewEd25519KeyPair()
+		kp.GenKeyPair()
+		sign := NewSigner().FromJson(map[string]any{"amount": 100, "to": "alice"}).ByEd25519(kp).ToRawBytes()
+		verifier := NewVerifier().FromJson(json.RawMessage("{\n  \"to\": \"alice\",\n  \"amount\": 1e2\n}")).WithRawSign(sign).ByEd25519(kp)
+		assert.True(t, verifier.ToBool())
+
+		verifier = NewVerifier().FromJson(json.RawMessage(`{"to":"bob","amount":100}`)).WithRawSign(sign).ByEd25519(kp)
+		assert.False(t, verifier.ToBool())
+	})
+}
+
+func TestSigner_WithCanonicalText(t *testing.T) {
+	kp := keypair.NewEd25519KeyPair()
+	require.NoError(t, kp.GenKeyPair())
+	sign := NewSigner().WithCanonicalText(text.Canonical).FromString("\xef\xbb\xbfline one  \r\nline two\r\n").ByEd25519(kp).ToRawBytes()
+	require.NotEmpty(t, sign)
+
+	t.Run("verify canonical string", func(t *testing.T) {
+		verifier := NewVerifier().FromString("line one\nline two\n").WithRawSign(sign).ByEd25519(kp)
+		assert.True(t, verifier.ToBool())
+	})
+
+	t.Run("verify crlf file", func(t *testing.T) {
+		file := mock.NewFile([]byte("line one\r\nline two \r\n"), "crlf.txt")
+		verifier := NewVerifier().WithCanonicalText(text.Canonical).FromFile(file).WithRawSign(sign).ByEd25519(kp)
+		assert.Nil(t, verifier.Error)
+		assert.True(t, verifier.verify)
+
+		file = mock.NewFile([]byte("line one\r\nline two \r\n"), "crlf.txt")
+		verifier = NewVerifier().FromFile(file).WithCanonicalText(text.Canonical).WithRawSign(sign).ByEd25519(kp)
+		assert.Nil(t, verifier.Error)
+		assert.True(t, verifier.verify)
+	})
+
+	t.Run("verify after input", func(t *testing.T) {
+		verifier := NewVerifier().FromBytes([]byte("line one\rline two\r")).WithCanonicalText(text.NormalizeNewlines).WithRawSign(sign).ByEd25519(kp)
+		assert.True(t, verifier.ToBool())
+	})
+
+	t.Run("without canonicalization", func(t *testing.T) {
+		verifier := NewVerifier().FromString("line one\r\nline two\r\n").WithRawSign(sign).ByEd25519(kp)
+		assert.False(t, verifier.ToBool())
+	})
+
+	t.Run("sign file", func(t *testing.T) {
+		file := mock.NewFile([]byte("line one\r\nline two\r\n"), "crlf.txt")
+		signer := NewSigner().FromFile(file).WithCanonicalText(text.Canonical)
+		assert.NotEqual(t, file, signer.reader)
+		assert.Equal(t, sign, signer.ByEd25519(kp).sign)
+	})
+}
+
+func TestSigner_WithUnicodeNormalization(t *testing.T) {
+	kp := keypair.NewEd25519KeyPair()
+	require.NoError(t, kp.GenKeyPair())
+	sign := NewSigner().WithUnicodeNormalization(text.NFC).FromString("Jos\u00e9").ByEd25519(kp).ToRawBytes()
+	require.NotEmpty(t, sign)
+
+	verifier := NewVerifier().WithUnicodeNormalization(text.NFC).FromString("Jose\u0301").WithRawSign(sign).ByEd25519(kp)
+	assert.True(t, verifier.ToBool())
+	verifier = NewVerifier().FromString("Jose\u0301").WithUnicodeNormalization(text.NFC).WithRawSign(sign).ByEd25519(kp)
+	assert.True(t, verifier.ToBool())
+	verifier = NewVerifier().FromString("Jose\u0301").WithRawSign(sign).ByEd25519(kp)
+	assert.False(t, verifier.ToBool())
+}
+
 func TestSigner_ToRawString(t *testing.T) {
 	t.Run("to raw string with valid data", func(t *testing.T) {
 		signer := NewSigner()
@@ -362,6 +459,44 @@ func TestSigner_ToHexBytes(t *testing.T) {
 	})
 }
 
+func TestSigner_ToPemString(t *testing.T) {
+	t.Run("to pem string", func(t *testing.T) {
+		signer := NewSigner().FromString("data")
+		signer.sign = []byte("hello world")
+		result := signer.ToPemString("")
+		assert.Equal(t, "-----BEGIN DONGLE SIGNATURE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE SIGNATURE-----\n", result)
+	})
+
+	t.Run("to pem string custom type", func(t *testing.T) {
+		signer := NewSigner().FromString("data")
+		signer.sign = []byte("hello world")
+		result := signer.ToPemString("ED25519 SIGNATURE")
+		assert.Equal(t, "-----BEGIN ED25519 SIGNATURE-----\naGVsbG8gd29ybGQ=\n-----END ED25519 SIGNATURE-----\n", result)
+	})
+
+	t.Run("to pem string with error", func(t *testing.T) {
+		signer := NewSigner().FromString("data")
+		signer.sign = []byte("hello world")
+		signer.Error = assert.AnError
+		assert.Equal(t, "", signer.ToPemString(""))
+	})
+}
+
+func TestSigner_ToPemBytes(t *testing.T) {
+	t.Run("to pem bytes", func(t *testing.T) {
+		signer := NewSigner().FromString("data")
+		signer.sign = []byte("hello world")
+		result := signer.ToPemBytes("")
+		assert.Equal(t, []byte("-----BEGIN DONGLE SIGNATURE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE SIGNATURE-----\n"), result)
+	})
+
+	t.Run("to pem bytes without data", func(t *testing.T) {
+		signer := NewSigner()
+		signer.sign = []byte("hello world")
+		assert.Equal(t, []byte{}, signer.ToPemBytes(""))
+	})
+}
+
 func TestSigner_Stream(t *testing.T) {
 	t.Run("stream with success", func(t *testing.T) {
 		signer := NewSigner()
@@ -451,3 +586,54 @@ func TestSigner_Stream(t *testing.T) {
 		assert.Equal(t, []byte{}, result)
 	})
 }
+
+func TestSigner_ToHexFormat(t *testing.T) {
+	kp := keypair.NewEd25519KeyPair()
+	kp.GenKeyPair()
+	signer := NewSigner().FromString("license:acme").ByEd25519(kp)
+	require.NoError(t, signer.Error)
+
+	code := signer.ToHexFormat(hex.Format{Uppercase: true, GroupSize: 8})
+	assert.Equal(t, strings.ToUpper(signer.ToHexString()), strings.ReplaceAll(code, "-", ""))
+	assert.Equal(t, 8, strings.Index(code, "-"))
+
+	typed := strings.ToLower(strings.ReplaceAll(code, "-", " "))
+	assert.True(t, NewVerifier().FromString("license:acme").WithHexFormatSign([]byte(typed)).ByEd25519(kp).ToBool())
+
+	verifier := NewVerifier().FromString("license:acme").WithHexFormatSign([]byte("XYZ"))
+	assert.Equal(t, hex.CorruptInputError(0), verifier.Error)
+}
+
+func TestSigner_To(t *testing.T) {
+	kp := keypair.NewEd25519KeyPair()
+	kp.GenKeyPair()
+	signer := NewSigner().FromString("hello world").ByEd25519(kp)
+	r := signer.To()
+	require.NoError(t, r.Error())
+	assert.Equal(t, signer.ToHexString(), r.Hex())
+	assert.Equal(t, signer.ToBase64String(), r.Base64())
+	assert.Equal(t, 64, r.Len())
+	assert.True(t, NewVerifier().FromString("hello world").WithRawSign(r.Bytes()).ByEd25519(kp).ToBool())
+}
+
+func TestSigner_FromFS(t *testing.T) {
+	kp := keypair.NewEd25519KeyPair()
+	require.NoError(t, kp.GenKeyPair())
+	fsys := fstest.MapFS{"hello.txt": {Data: []byte("hello world")}}
+
+	signer := NewSigner().FromFS(fsys, "hello.txt").ByEd25519(kp)
+	require.Nil(t, signer.Error)
+	assert.Equal(t, NewSigner().FromString("hello world").ByEd25519(kp).sign, signer.sign)
+
+	fsys["hello.sig"] = &fstest.MapFile{Data: signer.sign}
+	verifier := NewVerifier().FromFS(fsys, "hello.sig")
+	verifier.data = []byte("hello world")
+	verifier = verifier.ByEd25519(kp)
+	assert.Nil(t, verifier.Error)
+	assert.True(t, verifier.verify)
+
+	signer = NewSigner().FromFS(fsys, "missing.txt").ByEd25519(kp)
+	assert.Error(t, signer.Error)
+	verifier = NewVerifier().FromFS(fsys, "missing.sig").ByEd25519(kp)
+	assert.Error(t, verifier.Error)
+}
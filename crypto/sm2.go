@@ -5,6 +5,7 @@ import (
 
 	"github.com/dromara/dongle/crypto/keypair"
 	"github.com/dromara/dongle/crypto/sm2"
+	"github.com/dromara/dongle/internal/utils"
 )
 
 // BySm2 encrypts by SM2.
@@ -12,6 +13,13 @@ func (e Encrypter) BySm2(kp *keypair.Sm2KeyPair) Encrypter {
 	if e.Error != nil {
 		return e
 	}
+	o := observe(OperationEncrypt, "SM2", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("SM2"); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -31,6 +39,13 @@ func (d Decrypter) BySm2(kp *keypair.Sm2KeyPair) Decrypter {
 	if d.Error != nil {
 		return d
 	}
+	o := observe(OperationDecrypt, "SM2", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("SM2"); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -40,7 +55,7 @@ func (d Decrypter) BySm2(kp *keypair.Sm2KeyPair) Decrypter {
 	}
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = sm2.NewStdDecrypter(kp).Decrypt(d.src)
+		d.dst, d.Error = d.limit(sm2.NewStdDecrypter(kp).Decrypt(d.src))
 	}
 	return d
 }
@@ -51,6 +66,13 @@ func (s Signer) BySm2(kp *keypair.Sm2KeyPair) Signer {
 		return s
 	}
 
+	o := observe(OperationSign, "SM2", s.data, s.reader)
+	defer o.stop(&s.sign, &s.Error)
+
+	if s.Error = deprecated("SM2"); s.Error != nil {
+		return s
+	}
+
 	// Streaming signing mode
 	if s.reader != nil {
 		s.sign, s.Error = s.stream(func(w io.Writer) io.WriteCloser {
@@ -73,8 +95,17 @@ func (v Verifier) BySm2(kp *keypair.Sm2KeyPair) Verifier {
 		return v
 	}
 
+	o := observe(OperationVerify, "SM2", v.data, v.reader)
+	defer o.stop(nil, &v.Error)
+
+	if v.Error = deprecated("SM2"); v.Error != nil {
+		return v
+	}
+
 	// Streaming verification mode
 	if v.reader != nil {
+		defer utils.CloseFS(v.reader)
+
 		verifier := sm2.NewStreamVerifier(v.reader, kp)
 
 		// Write the data to be verified
@@ -1,6 +1,7 @@
 package sm2
 
 import (
+	"errors"
 	"io"
 
 	"github.com/dromara/dongle/crypto/internal/sm2"
@@ -17,6 +18,10 @@ type StdDecrypter struct {
 // NewStdDecrypter creates a new SM2 decrypter bound to the given key pair.
 func NewStdDecrypter(kp *keypair.Sm2KeyPair) *StdDecrypter {
 	d := &StdDecrypter{keypair: *kp}
+	if err := kp.CheckUse(keypair.DecryptOp); err != nil {
+		d.Error = DecryptError{Err: err}
+		return d
+	}
 	if len(kp.PrivateKey) == 0 {
 		d.Error = DecryptError{Err: keypair.EmptyPrivateKeyError{}}
 		return d
@@ -41,7 +46,7 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 	}
 	dst, err = sm2.DecryptWithPrivateKey(d.cache.priKey, src, d.keypair.Window, string(d.keypair.Mode))
 	if err != nil {
-		err = DecryptError{Err: err}
+		err = decryptError(err)
 		return
 	}
 	return
@@ -66,6 +71,10 @@ func NewStreamDecrypter(r io.Reader, kp *keypair.Sm2KeyPair) io.Reader {
 		keypair:  *kp,
 		position: 0,
 	}
+	if err := kp.CheckUse(keypair.DecryptOp); err != nil {
+		d.Error = DecryptError{Err: err}
+		return d
+	}
 	if len(kp.PrivateKey) == 0 {
 		d.Error = DecryptError{Err: keypair.EmptyPrivateKeyError{}}
 		return d
@@ -90,7 +99,7 @@ func (d *StreamDecrypter) decrypt(src []byte) (dst []byte, err error) {
 	}
 	dst, err = sm2.DecryptWithPrivateKey(d.cache.priKey, src, d.keypair.Window, string(d.keypair.Mode))
 	if err != nil {
-		err = DecryptError{Err: err}
+		err = decryptError(err)
 		return
 	}
 	return
@@ -135,3 +144,16 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 	}
 	return
 }
+
+// decryptError maps a failed decryption to InvalidPointError or
+// MacMismatchError when the ciphertext itself is rejected, and to DecryptError
+// otherwise.
+func decryptError(err error) error {
+	switch {
+	case errors.Is(err, sm2.ErrInvalidPoint):
+		return InvalidPointError{}
+	case errors.Is(err, sm2.ErrMacMismatch):
+		return MacMismatchError{}
+	}
+	return DecryptError{Err: err}
+}
@@ -18,6 +18,10 @@ type StdEncrypter struct {
 // NewStdEncrypter creates a new SM2 encrypter bound to the given key pair.
 func NewStdEncrypter(kp *keypair.Sm2KeyPair) *StdEncrypter {
 	e := &StdEncrypter{keypair: *kp}
+	if err := kp.CheckUse(keypair.EncryptOp); err != nil {
+		e.Error = EncryptError{Err: err}
+		return e
+	}
 	if len(kp.PublicKey) == 0 {
 		e.Error = EncryptError{Err: keypair.EmptyPublicKeyError{}}
 		return e
@@ -37,6 +41,11 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		err = e.Error
 		return
 	}
+	// The key may have expired since the encrypter was created
+	if err = e.keypair.CheckUse(keypair.EncryptOp); err != nil {
+		err = EncryptError{Err: err}
+		return
+	}
 	if len(src) == 0 {
 		return
 	}
@@ -65,6 +74,10 @@ func NewStreamEncrypter(w io.Writer, kp *keypair.Sm2KeyPair) io.WriteCloser {
 		keypair: *kp,
 		buffer:  make([]byte, 0),
 	}
+	if err := kp.CheckUse(keypair.EncryptOp); err != nil {
+		e.Error = EncryptError{Err: err}
+		return e
+	}
 	if len(kp.PublicKey) == 0 {
 		e.Error = EncryptError{Err: keypair.EmptyPublicKeyError{}}
 		return e
@@ -84,6 +97,11 @@ func (e *StreamEncrypter) encrypt(src []byte) (dst []byte, err error) {
 		err = e.Error
 		return
 	}
+	// The key may have expired since the encrypter was created
+	if err = e.keypair.CheckUse(keypair.EncryptOp); err != nil {
+		err = EncryptError{Err: err}
+		return
+	}
 	if len(src) == 0 {
 		return
 	}
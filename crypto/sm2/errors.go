@@ -1,6 +1,10 @@
 package sm2
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 type EncryptError struct {
 	Err error
@@ -10,6 +14,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/sm2: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SM2-001.
+func (e EncryptError) Code() string {
+	return "DGL-SM2-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "encrypt", errcode.FieldCause, e.Err)
+}
+
 type DecryptError struct {
 	Err error
 }
@@ -18,12 +32,32 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/sm2: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SM2-002.
+func (e DecryptError) Code() string {
+	return "DGL-SM2-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "decrypt", errcode.FieldCause, e.Err)
+}
+
 type ReadError struct{ Err error }
 
 func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/sm2: failed to read encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SM2-003.
+func (e ReadError) Code() string {
+	return "DGL-SM2-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "read", errcode.FieldCause, e.Err)
+}
+
 type SignError struct {
 	Err error
 }
@@ -32,6 +66,16 @@ func (e SignError) Error() string {
 	return fmt.Sprintf("crypto/sm2: failed to sign data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SM2-004.
+func (e SignError) Code() string {
+	return "DGL-SM2-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "sign", errcode.FieldCause, e.Err)
+}
+
 type VerifyError struct {
 	Err error
 }
@@ -39,3 +83,51 @@ type VerifyError struct {
 func (e VerifyError) Error() string {
 	return fmt.Sprintf("crypto/sm2: failed to verify signature: %v", e.Err)
 }
+
+// Code returns the stable error code DGL-SM2-005.
+func (e VerifyError) Code() string {
+	return "DGL-SM2-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e VerifyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "verify", errcode.FieldCause, e.Err)
+}
+
+// InvalidPointError is returned when the C1 point of a ciphertext is not a
+// valid point of the SM2 curve, which points at a corrupted or forged
+// ciphertext.
+type InvalidPointError struct{}
+
+func (e InvalidPointError) Error() string {
+	return "crypto/sm2: failed to decrypt data: C1 is not a valid curve point"
+}
+
+// Code returns the stable error code DGL-SM2-006.
+func (e InvalidPointError) Code() string {
+	return "DGL-SM2-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPointError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "decrypt")
+}
+
+// MacMismatchError is returned when the C3 hash of a ciphertext does not match
+// the decrypted plaintext, because the ciphertext was modified or was made for
+// another key.
+type MacMismatchError struct{}
+
+func (e MacMismatchError) Error() string {
+	return "crypto/sm2: failed to decrypt data: C3 hash mismatch"
+}
+
+// Code returns the stable error code DGL-SM2-007.
+func (e MacMismatchError) Code() string {
+	return "DGL-SM2-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MacMismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm2", "SM2", "decrypt")
+}
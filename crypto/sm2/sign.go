@@ -17,6 +17,14 @@ type StdSigner struct {
 // NewStdSigner creates a new SM2 signer bound to the given key pair.
 func NewStdSigner(kp *keypair.Sm2KeyPair) *StdSigner {
 	s := &StdSigner{keypair: *kp}
+	if err := kp.CheckUse(keypair.SignOp); err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
+	if err := kp.CheckUID(); err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
 	if len(kp.PrivateKey) == 0 {
 		s.Error = SignError{Err: keypair.EmptyPrivateKeyError{}}
 		return s
@@ -36,6 +44,11 @@ func (s *StdSigner) Sign(src []byte) (sign []byte, err error) {
 		err = s.Error
 		return
 	}
+	// The key may have expired since the signer was created
+	if err = s.keypair.CheckUse(keypair.SignOp); err != nil {
+		err = SignError{Err: err}
+		return
+	}
 	if len(src) == 0 {
 		return
 	}
@@ -64,6 +77,14 @@ func NewStreamSigner(w io.Writer, kp *keypair.Sm2KeyPair) io.WriteCloser {
 		keypair: *kp,
 		buffer:  make([]byte, 0),
 	}
+	if err := kp.CheckUse(keypair.SignOp); err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
+	if err := kp.CheckUID(); err != nil {
+		s.Error = SignError{Err: err}
+		return s
+	}
 	if len(kp.PrivateKey) == 0 {
 		s.Error = SignError{Err: keypair.EmptyPrivateKeyError{}}
 		return s
@@ -83,6 +104,11 @@ func (s *StreamSigner) sign(data []byte) (sign []byte, err error) {
 		err = s.Error
 		return
 	}
+	// The key may have expired since the signer was created
+	if err = s.keypair.CheckUse(keypair.SignOp); err != nil {
+		err = SignError{Err: err}
+		return
+	}
 	if len(data) == 0 {
 		return
 	}
@@ -75,6 +75,46 @@ func TestStdDecryptErrors(t *testing.T) {
 	assert.EqualError(t, err, "preset")
 }
 
+func TestDecryptMalformedCiphertext(t *testing.T) {
+	kp := mustKeyPair(t)
+	kp.SetMode(keypair.C1C3C2)
+	ciphertext, err := NewStdEncrypter(kp).Encrypt([]byte("hello"))
+	assert.NoError(t, err)
+
+	t.Run("invalid C1", func(t *testing.T) {
+		mutated := bytes.Clone(ciphertext)
+		mutated[64] ^= 0x01 // last byte of the C1 y coordinate
+		_, err := NewStdDecrypter(kp).Decrypt(mutated)
+		assert.IsType(t, InvalidPointError{}, err)
+		assert.Equal(t, "DGL-SM2-006", InvalidPointError{}.Code())
+
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(mutated), kp))
+		assert.IsType(t, InvalidPointError{}, err)
+	})
+
+	t.Run("C3 mismatch", func(t *testing.T) {
+		for _, i := range []int{65, len(ciphertext) - 1} { // first byte of C3, last byte of C2
+			mutated := bytes.Clone(ciphertext)
+			mutated[i] ^= 0x01
+			_, err := NewStdDecrypter(kp).Decrypt(mutated)
+			assert.IsType(t, MacMismatchError{}, err)
+		}
+		assert.Equal(t, "DGL-SM2-007", MacMismatchError{}.Code())
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, err := NewStdDecrypter(mustKeyPair(t)).Decrypt(ciphertext)
+		assert.IsType(t, MacMismatchError{}, err)
+	})
+
+	t.Run("ciphertext not modified", func(t *testing.T) {
+		orig := bytes.Clone(ciphertext)
+		_, err := NewStdDecrypter(kp).Decrypt(ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, orig, ciphertext)
+	})
+}
+
 func TestStreamEncrypterAndDecrypter(t *testing.T) {
 	kp := mustKeyPair(t)
 	writer := mock.NewFile(nil, "cipher")
@@ -381,6 +421,8 @@ func TestErrorTypes(t *testing.T) {
 		ReadError{Err: errors.New("r")},
 		SignError{Err: errors.New("s")},
 		VerifyError{Err: errors.New("v")},
+		InvalidPointError{},
+		MacMismatchError{},
 	}
 	for _, e := range errs {
 		assert.NotEmpty(t, e.Error())
@@ -430,3 +472,128 @@ func TestAdditionalBranches(t *testing.T) {
 	sv := NewStreamVerifier(closer, kp).(*StreamVerifier)
 	assert.EqualError(t, sv.Close(), ReadError{Err: errors.New("close fail")}.Error())
 }
+
+func TestKeyUsage(t *testing.T) {
+	kp := mustKeyPair(t)
+	data := []byte("hello")
+
+	t.Run("sign only", func(t *testing.T) {
+		kp := *kp
+		kp.SetUsage(keypair.Sign)
+		sign, err := NewStdSigner(&kp).Sign(data)
+		assert.NoError(t, err)
+		valid, err := NewStdVerifier(&kp).Verify(data, sign)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		want := keypair.KeyUsageError{Usage: keypair.Sign, Operation: keypair.Encrypt}
+		assert.Equal(t, EncryptError{Err: want}, NewStdEncrypter(&kp).Error)
+		assert.Equal(t, DecryptError{Err: want}, NewStdDecrypter(&kp).Error)
+		_, err = NewStreamEncrypter(io.Discard, &kp).Write(data)
+		assert.Equal(t, EncryptError{Err: want}, err)
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(data), &kp))
+		assert.Equal(t, DecryptError{Err: want}, err)
+	})
+
+	t.Run("encrypt only", func(t *testing.T) {
+		kp := *kp
+		kp.SetUsage(keypair.Encrypt)
+		ciphertext, err := NewStdEncrypter(&kp).Encrypt(data)
+		assert.NoError(t, err)
+		plaintext, err := NewStdDecrypter(&kp).Decrypt(ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, data, plaintext)
+
+		want := keypair.KeyUsageError{Usage: keypair.Encrypt, Operation: keypair.Sign}
+		assert.Equal(t, SignError{Err: want}, NewStdSigner(&kp).Error)
+		assert.Equal(t, VerifyError{Err: want}, NewStdVerifier(&kp).Error)
+		_, err = NewStreamSigner(io.Discard, &kp).Write(data)
+		assert.Equal(t, SignError{Err: want}, err)
+		_, err = NewStreamVerifier(bytes.NewReader(nil), &kp).Write(data)
+		assert.Equal(t, VerifyError{Err: want}, err)
+	})
+}
+
+func TestUIDAndSignMode(t *testing.T) {
+	kp := mustKeyPair(t)
+	data := []byte("hello")
+
+	t.Run("custom uid", func(t *testing.T) {
+		signKp := *kp
+		signKp.SetUID([]byte("bank-a@example.com"))
+		sign, err := NewStdSigner(&signKp).Sign(data)
+		assert.NoError(t, err)
+
+		valid, err := NewStdVerifier(&signKp).Verify(data, sign)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		// The default UID or another one yields another ZA
+		valid, err = NewStdVerifier(kp).Verify(data, sign)
+		assert.False(t, valid)
+		assert.IsType(t, VerifyError{}, err)
+		verifyKp := *kp
+		verifyKp.SetUID([]byte("bank-b@example.com"))
+		valid, _ = NewStdVerifier(&verifyKp).Verify(data, sign)
+		assert.False(t, valid)
+	})
+
+	t.Run("default uid", func(t *testing.T) {
+		signKp := *kp
+		signKp.SetUID([]byte("1234567812345678"))
+		sign, err := NewStdSigner(&signKp).Sign(data)
+		assert.NoError(t, err)
+		valid, err := NewStdVerifier(kp).Verify(data, sign)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("raw signature", func(t *testing.T) {
+		rawKp := *kp
+		rawKp.SetSingMode(keypair.Bytes)
+		for i := 0; i < 16; i++ {
+			sign, err := NewStdSigner(&rawKp).Sign(data)
+			assert.NoError(t, err)
+			assert.Len(t, sign, 64)
+			valid, err := NewStdVerifier(&rawKp).Verify(data, sign)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+
+			der, err := keypair.SignatureToDER(sm2.NewCurve(), sign)
+			assert.NoError(t, err)
+			valid, err = NewStdVerifier(kp).Verify(data, der)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		}
+	})
+
+	t.Run("der signature", func(t *testing.T) {
+		sign, err := NewStdSigner(kp).Sign(data)
+		assert.NoError(t, err)
+		raw, err := keypair.SignatureToCompact(sm2.NewCurve(), sign)
+		assert.NoError(t, err)
+
+		rawKp := *kp
+		rawKp.SetSingMode(keypair.Bytes)
+		valid, err := NewStdVerifier(&rawKp).Verify(data, raw)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		valid, _ = NewStdVerifier(&rawKp).Verify(data, sign)
+		assert.False(t, valid)
+	})
+
+	t.Run("uid too long", func(t *testing.T) {
+		longKp := *kp
+		longKp.SetUID(make([]byte, keypair.MaxSm2UIDSize+1))
+		want := keypair.InvalidUIDError{Size: keypair.MaxSm2UIDSize + 1}
+		assert.Equal(t, SignError{Err: want}, NewStdSigner(&longKp).Error)
+		assert.Equal(t, VerifyError{Err: want}, NewStdVerifier(&longKp).Error)
+		_, err := NewStreamSigner(io.Discard, &longKp).Write(data)
+		assert.Equal(t, SignError{Err: want}, err)
+		_, err = NewStreamVerifier(bytes.NewReader(nil), &longKp).Write(data)
+		assert.Equal(t, VerifyError{Err: want}, err)
+
+		_, err = sm2.SignWithPrivateKey(NewStdSigner(kp).cache.priKey, data, longKp.UID, 0)
+		assert.Error(t, err)
+	})
+}
@@ -17,6 +17,14 @@ type StdVerifier struct {
 // NewStdVerifier creates a new SM2 verifier bound to the given key pair.
 func NewStdVerifier(kp *keypair.Sm2KeyPair) *StdVerifier {
 	v := &StdVerifier{keypair: *kp}
+	if err := kp.CheckUse(keypair.VerifyOp); err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
+	if err := kp.CheckUID(); err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
 	if len(kp.PublicKey) == 0 {
 		v.Error = VerifyError{Err: keypair.EmptyPublicKeyError{}}
 		return v
@@ -74,6 +82,14 @@ func NewStreamVerifier(r io.Reader, kp *keypair.Sm2KeyPair) io.WriteCloser {
 		keypair: *kp,
 		buffer:  make([]byte, 0),
 	}
+	if err := kp.CheckUse(keypair.VerifyOp); err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
+	if err := kp.CheckUID(); err != nil {
+		v.Error = VerifyError{Err: err}
+		return v
+	}
 	if len(kp.PublicKey) == 0 {
 		v.Error = VerifyError{Err: keypair.EmptyPublicKeyError{}}
 		return v
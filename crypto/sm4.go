@@ -13,6 +13,13 @@ func (e Encrypter) BySm4(c *cipher.Sm4Cipher) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "SM4", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("SM4", string(c.Block)); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -39,6 +46,13 @@ func (d Decrypter) BySm4(c *cipher.Sm4Cipher) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "SM4", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("SM4", string(c.Block)); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -54,7 +68,7 @@ func (d Decrypter) BySm4(c *cipher.Sm4Cipher) Decrypter {
 			d.Error = decrypter.Error
 			return d
 		}
-		d.dst, d.Error = decrypter.Decrypt(d.src)
+		d.dst, d.Error = d.limit(decrypter.Decrypt(d.src))
 	}
 
 	return d
@@ -0,0 +1,51 @@
+package sm4
+
+import (
+	stdCipher "crypto/cipher"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/internal/sm4"
+)
+
+// NewBlock returns the SM4 block cipher keyed with c.Key as a standard
+// cipher.Block, for code written against the stdlib interfaces such as
+// cipher.NewCBCEncrypter or a custom protocol.
+func NewBlock(c *cipher.Sm4Cipher) (stdCipher.Block, error) {
+	if len(c.Key) != sm4.KeySize {
+		return nil, KeySizeError(len(c.Key))
+	}
+	return sm4.NewCipher(c.Key), nil
+}
+
+// NewAEAD returns SM4-GCM keyed with c.Key as a standard cipher.AEAD. The
+// cipher must be configured with the GCM block mode, its nonce size follows
+// c.Nonce as with NewStdEncrypter.
+func NewAEAD(c *cipher.Sm4Cipher) (stdCipher.AEAD, error) {
+	block, err := NewBlock(c)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewAEAD(block)
+}
+
+// NewEncryptStream returns SM4 in the CTR, CFB or OFB mode configured on c,
+// keyed with c.Key and started from c.IV, as a standard cipher.Stream for
+// encryption.
+func NewEncryptStream(c *cipher.Sm4Cipher) (stdCipher.Stream, error) {
+	block, err := NewBlock(c)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewEncryptStream(block)
+}
+
+// NewDecryptStream returns SM4 in the CTR, CFB or OFB mode configured on c,
+// keyed with c.Key and started from c.IV, as a standard cipher.Stream for
+// decryption.
+func NewDecryptStream(c *cipher.Sm4Cipher) (stdCipher.Stream, error) {
+	block, err := NewBlock(c)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewDecryptStream(block)
+}
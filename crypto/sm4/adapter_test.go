@@ -0,0 +1,132 @@
+package sm4
+
+import (
+	stdCipher "crypto/cipher"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBlock(t *testing.T) {
+	t.Run("matches ECB encrypter", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.ECB)
+		c.SetKey([]byte("1234567890123456"))
+		src := []byte("1234567890abcdef")
+		want, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+
+		block, err := NewBlock(c)
+		assert.NoError(t, err)
+		assert.Equal(t, 16, block.BlockSize())
+		got := make([]byte, len(src))
+		block.Encrypt(got, src)
+		assert.Equal(t, want, got)
+		block.Decrypt(got, got)
+		assert.Equal(t, src, got)
+	})
+
+	t.Run("works with stdlib modes", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.CBC)
+		c.SetKey([]byte("1234567890123456"))
+		c.SetIV([]byte("1234567890123456"))
+		src := []byte("1234567890abcdef1234567890abcdef")
+		want, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+
+		block, err := NewBlock(c)
+		assert.NoError(t, err)
+		got := make([]byte, len(src))
+		stdCipher.NewCBCEncrypter(block, c.IV).CryptBlocks(got, src)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.ECB)
+		c.SetKey([]byte("short"))
+		block, err := NewBlock(c)
+		assert.Nil(t, block)
+		assert.Equal(t, KeySizeError(5), err)
+	})
+}
+
+func TestNewAEAD(t *testing.T) {
+	t.Run("matches GCM encrypter", func(t *testing.T) {
+		for _, tc := range gcmTestCases {
+			if len(tc.plaintext) == 0 {
+				continue
+			}
+			c := cipher.NewSm4Cipher(cipher.GCM)
+			c.SetKey(tc.key)
+			c.SetNonce(tc.nonce)
+			c.SetAAD(tc.aad)
+			want, err := NewStdEncrypter(c).Encrypt(tc.plaintext)
+			assert.NoError(t, err)
+
+			aead, err := NewAEAD(c)
+			assert.NoError(t, err)
+			assert.Equal(t, want, aead.Seal(nil, tc.nonce, tc.plaintext, tc.aad))
+			got, err := aead.Open(nil, tc.nonce, want, tc.aad)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.plaintext, got)
+		}
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.GCM)
+		aead, err := NewAEAD(c)
+		assert.Nil(t, aead)
+		assert.Equal(t, KeySizeError(0), err)
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.CBC)
+		c.SetKey([]byte("1234567890123456"))
+		aead, err := NewAEAD(c)
+		assert.Nil(t, aead)
+		assert.IsType(t, cipher.UnsupportedBlockModeError{}, err)
+	})
+}
+
+func TestNewStream(t *testing.T) {
+	src := []byte("hello world, streaming through sm4")
+
+	t.Run("matches stream mode encrypters", func(t *testing.T) {
+		for _, mode := range []cipher.BlockMode{cipher.CTR, cipher.CFB, cipher.OFB} {
+			c := cipher.NewSm4Cipher(mode)
+			c.SetKey([]byte("1234567890123456"))
+			c.SetIV([]byte("1234567890123456"))
+			want, err := NewStdEncrypter(c).Encrypt(src)
+			assert.NoError(t, err)
+
+			enc, err := NewEncryptStream(c)
+			assert.NoError(t, err)
+			got := make([]byte, len(src))
+			enc.XORKeyStream(got, src)
+			assert.Equal(t, want, got, mode)
+
+			dec, err := NewDecryptStream(c)
+			assert.NoError(t, err)
+			dec.XORKeyStream(got, want)
+			assert.Equal(t, src, got, mode)
+		}
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.CTR)
+		enc, err := NewEncryptStream(c)
+		assert.Nil(t, enc)
+		assert.Equal(t, KeySizeError(0), err)
+		dec, err := NewDecryptStream(c)
+		assert.Nil(t, dec)
+		assert.Equal(t, KeySizeError(0), err)
+	})
+
+	t.Run("missing iv", func(t *testing.T) {
+		c := cipher.NewSm4Cipher(cipher.CFB)
+		c.SetKey([]byte("1234567890123456"))
+		enc, err := NewEncryptStream(c)
+		assert.Nil(t, enc)
+		assert.IsType(t, cipher.EmptyIVError{}, err)
+	})
+}
@@ -1,6 +1,10 @@
 package sm4
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
 
 // KeySizeError represents an error when the SM4 key size is invalid.
 // SM4 keys must be exactly 16 bytes (128 bits).
@@ -11,6 +15,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/sm4: invalid key size %d, key must be 16 bytes", int(k))
 }
 
+// Code returns the stable error code DGL-SM4-001.
+func (k KeySizeError) Code() string {
+	return "DGL-SM4-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm4", "SM4", "key", errcode.FieldKeySize, int(k))
+}
+
 // EncryptError represents an error during SM4 encryption.
 type EncryptError struct {
 	Err error
@@ -21,6 +35,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/sm4: encryption failed: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-SM4-002.
+func (e EncryptError) Code() string {
+	return "DGL-SM4-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm4", "SM4", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error during SM4 decryption.
 type DecryptError struct {
 	Err error
@@ -31,6 +55,16 @@ func (d DecryptError) Error() string {
 	return fmt.Sprintf("crypto/sm4: decryption failed: %v", d.Err)
 }
 
+// Code returns the stable error code DGL-SM4-003.
+func (d DecryptError) Code() string {
+	return "DGL-SM4-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (d DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm4", "SM4", "decrypt", errcode.FieldCause, d.Err)
+}
+
 // ReadError represents an error during data reading in streaming operations.
 type ReadError struct {
 	Err error
@@ -40,3 +74,13 @@ type ReadError struct {
 func (r ReadError) Error() string {
 	return fmt.Sprintf("crypto/sm4: read failed: %v", r.Err)
 }
+
+// Code returns the stable error code DGL-SM4-004.
+func (r ReadError) Code() string {
+	return "DGL-SM4-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (r ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/sm4", "SM4", "read", errcode.FieldCause, r.Err)
+}
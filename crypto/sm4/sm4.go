@@ -5,6 +5,7 @@ package sm4
 
 import (
 	stdCipher "crypto/cipher"
+	"errors"
 	"github.com/dromara/dongle/crypto/internal/sm4"
 	"io"
 
@@ -44,7 +45,37 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
-	dst, err = e.cipher.Encrypt(src, e.block)
+	// Prefix the commitment to the configured key
+	if e.cipher.KeyCommitment {
+		if dst, err = e.encrypt(src); err != nil {
+			return
+		}
+		if dst, err = e.cipher.CommitKey(dst); err != nil {
+			err = EncryptError{Err: err}
+		}
+		return
+	}
+	return e.encrypt(src)
+}
+
+// encrypt encrypts non-empty src as configured, apart from the key
+// commitment.
+func (e *StdEncrypter) encrypt(src []byte) (dst []byte, err error) {
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		dst, err = e.cipher.EncryptWithMessageKey(src, newBlock)
+		if err != nil {
+			err = EncryptError{Err: err}
+		}
+		return
+	}
+
+	// Derive the nonce from the plaintext and AAD
+	if e.cipher.DeterministicNonce {
+		dst, err = e.cipher.EncryptWithDeterministicNonce(src, e.block)
+	} else {
+		dst, err = e.cipher.Encrypt(src, e.block)
+	}
 	if err != nil {
 		err = EncryptError{Err: err}
 	}
@@ -84,7 +115,29 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
-	dst, err = d.cipher.Decrypt(src, d.block)
+	// Check the commitment to the configured key
+	if d.cipher.KeyCommitment {
+		if src, err = d.cipher.OpenKeyCommitment(src); err != nil {
+			err = DecryptError{Err: err}
+			return
+		}
+	}
+
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		dst, err = d.cipher.DecryptWithMessageKey(src, newBlock)
+		if err != nil {
+			err = DecryptError{Err: err}
+		}
+		return
+	}
+
+	// Derive the nonce from the plaintext and AAD
+	if d.cipher.DeterministicNonce {
+		dst, err = d.cipher.DecryptWithDeterministicNonce(src, d.block)
+	} else {
+		dst, err = d.cipher.Decrypt(src, d.block)
+	}
 	if err != nil {
 		err = DecryptError{Err: err}
 	}
@@ -113,6 +166,9 @@ func NewStreamEncrypter(w io.Writer, c *cipher.Sm4Cipher) io.WriteCloser {
 		e.Error = KeySizeError(len(c.Key))
 		return e
 	}
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
 	e.block = sm4.NewCipher(c.Key)
 	return e
 }
@@ -167,12 +223,16 @@ type StreamDecrypter struct {
 	buffer   []byte            // Buffer for decrypted data
 	position int               // Current position in the buffer
 	block    stdCipher.Block   // Reused cipher block for better performance
-	Error    error             // Error field for storing decryption errors
+	// GCM stream released before verification, see cipher.SetReleasePolicy
+	unverified *cipher.UnverifiedReader
+	Error      error // Error field for storing decryption errors
 }
 
 // NewStreamDecrypter creates a new streaming SM4 decrypter that reads encrypted data
 // from the provided io.Reader. The decrypter uses the specified cipher interface
-// and validates the key length for proper SM4 decryption.
+// and validates the key length for proper SM4 decryption. GCM ciphers return
+// plaintext before the tag is verified only under the ReleaseUnverified policy,
+// see SetReleasePolicy.
 func NewStreamDecrypter(r io.Reader, c *cipher.Sm4Cipher) io.Reader {
 	d := &StreamDecrypter{
 		reader:   r,
@@ -184,7 +244,11 @@ func NewStreamDecrypter(r io.Reader, c *cipher.Sm4Cipher) io.Reader {
 		d.Error = KeySizeError(len(c.Key))
 		return d
 	}
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
 	d.block = sm4.NewCipher(c.Key)
+	d.unverified, d.Error = c.NewUnverifiedReader(r, d.block)
 	return d
 }
 
@@ -194,6 +258,18 @@ func (d *StreamDecrypter) Read(dst []byte) (n int, err error) {
 		return 0, d.Error
 	}
 
+	if d.unverified != nil {
+		n, err = d.unverified.Read(dst)
+		var authErr cipher.StreamAuthError
+		switch {
+		case errors.As(err, &authErr):
+			err = DecryptError{Err: err}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
 	// If we haven't decrypted the data yet, do it now
 	if d.buffer == nil {
 		// Read all encrypted data from the underlying reader
@@ -231,3 +307,8 @@ func (d *StreamDecrypter) Read(dst []byte) (n int, err error) {
 
 	return copied, nil
 }
+
+// newBlock creates an SM4 cipher block from a 16-byte key.
+func newBlock(key []byte) (stdCipher.Block, error) {
+	return sm4.NewCipher(key), nil
+}
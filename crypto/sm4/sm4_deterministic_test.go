@@ -0,0 +1,60 @@
+package sm4
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeterministicNonce(t *testing.T) {
+	newCipher := func() *cipher.Sm4Cipher {
+		c := cipher.NewSm4Cipher(cipher.GCM)
+		c.SetKey([]byte("1234567890123456"))
+		c.SetAAD([]byte("record-1"))
+		c.SetDeterministicNonce(true)
+		return c
+	}
+	src := []byte("hello world")
+
+	t.Run("round trip", func(t *testing.T) {
+		c := newCipher()
+		dst1, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+		dst2, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+		assert.Equal(t, dst1, dst2)
+
+		got, err := NewStdDecrypter(c).Decrypt(dst1)
+		assert.NoError(t, err)
+		assert.Equal(t, src, got)
+
+		dst3, _ := NewStdEncrypter(c).Encrypt([]byte("hello world!"))
+		assert.NotEqual(t, dst1[:cipher.DeterministicNonceSize], dst3[:cipher.DeterministicNonceSize])
+	})
+
+	t.Run("invalid nonce", func(t *testing.T) {
+		_, err := NewStdDecrypter(newCipher()).Decrypt([]byte("short"))
+		assert.IsType(t, DecryptError{}, err)
+		assert.Contains(t, err.Error(), "deterministic nonce")
+	})
+
+	t.Run("unsupported mode", func(t *testing.T) {
+		c := newCipher()
+		c.Block = cipher.CBC
+		_, err := NewStdEncrypter(c).Encrypt(src)
+		assert.IsType(t, EncryptError{}, err)
+		assert.Contains(t, err.Error(), "not GCM")
+	})
+
+	t.Run("streams unsupported", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := NewStreamEncrypter(&buf, newCipher()).Write(src)
+		assert.IsType(t, cipher.UnsupportedDeterministicNonceError{}, err)
+
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(src), newCipher()))
+		assert.IsType(t, cipher.UnsupportedDeterministicNonceError{}, err)
+	})
+}
@@ -765,3 +765,53 @@ func TestStreamEncrypterWriteError(t *testing.T) {
 		assert.IsType(t, EncryptError{}, err)
 	})
 }
+
+// TestStreamCipherFaults tests the stream wrappers against a misbehaving block cipher
+func TestStreamCipherFaults(t *testing.T) {
+	newGCM := func() *cipher.Sm4Cipher {
+		c := cipher.NewSm4Cipher(cipher.GCM)
+		c.SetKey(key16Error)
+		c.SetNonce(iv16Error[:12])
+		return c
+	}
+	encrypted, err := NewStdEncrypter(newGCM()).Encrypt(testDataError)
+	assert.NoError(t, err)
+
+	t.Run("corrupted block in StreamEncrypter Write", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newGCM())
+		streamEncrypter := encrypter.(*StreamEncrypter)
+		block := mock.NewBlock(streamEncrypter.block).CorruptAll()
+		streamEncrypter.block = block
+
+		n, err := encrypter.Write(testDataError)
+		assert.NoError(t, err)
+		assert.Equal(t, len(testDataError), n)
+		assert.Positive(t, block.Calls())
+		assert.Len(t, buf.Bytes(), len(encrypted))
+		assert.NotEqual(t, encrypted, buf.Bytes())
+
+		_, err = NewStdDecrypter(newGCM()).Decrypt(buf.Bytes())
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("corrupted block in StreamDecrypter Read", func(t *testing.T) {
+		decrypter := NewStreamDecrypter(bytes.NewReader(encrypted), newGCM())
+		streamDecrypter := decrypter.(*StreamDecrypter)
+		streamDecrypter.block = mock.NewBlock(streamDecrypter.block).CorruptAt(1)
+
+		n, err := decrypter.Read(make([]byte, 32))
+		assert.Equal(t, 0, n)
+		assert.IsType(t, DecryptError{}, err)
+	})
+
+	t.Run("failing block in StreamEncrypter Write", func(t *testing.T) {
+		encrypter := NewStreamEncrypter(&bytes.Buffer{}, newGCM())
+		streamEncrypter := encrypter.(*StreamEncrypter)
+		streamEncrypter.block = mock.NewBlock(streamEncrypter.block).PanicAt(0, "block failure")
+
+		assert.PanicsWithValue(t, "block failure", func() {
+			_, _ = encrypter.Write(testDataError)
+		})
+	})
+}
@@ -320,3 +320,21 @@ func TestSM4GCMErrorHandling(t *testing.T) {
 		assert.Nil(t, decrypted, "Decrypted text should be nil when decryption fails")
 	})
 }
+
+func TestGCMReleasePolicy(t *testing.T) {
+	c := cipher.NewSm4Cipher(cipher.GCM)
+	c.SetKey([]byte("1234567890123456"))
+	c.SetNonce([]byte("123456789012"))
+	c.SetReleasePolicy(cipher.ReleaseUnverified)
+	data := bytes.Repeat([]byte("release policy "), 100)
+	sealed, err := NewStdEncrypter(c).Encrypt(data)
+	assert.NoError(t, err)
+
+	decrypted, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed), c))
+	assert.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed), c))
+	assert.Equal(t, DecryptError{Err: cipher.StreamAuthError{}}, err)
+}
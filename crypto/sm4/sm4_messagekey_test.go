@@ -0,0 +1,62 @@
+package sm4
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageKey(t *testing.T) {
+	newCipher := func() *cipher.Sm4Cipher {
+		c := cipher.NewSm4Cipher(cipher.CBC)
+		c.SetKey([]byte("1234567890123456"))
+		c.SetIV([]byte("1234567890123456"))
+		c.SetPadding(cipher.PKCS7)
+		c.SetMessageKey(true)
+		return c
+	}
+	src := []byte("hello world")
+
+	t.Run("round trip", func(t *testing.T) {
+		c := newCipher()
+		dst1, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+		dst2, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+		assert.NotEqual(t, dst1, dst2)
+
+		for _, dst := range [][]byte{dst1, dst2} {
+			got, err := NewStdDecrypter(c).Decrypt(dst)
+			assert.NoError(t, err)
+			assert.Equal(t, src, got)
+		}
+	})
+
+	t.Run("differs from the configured key", func(t *testing.T) {
+		c := newCipher()
+		dst, err := NewStdEncrypter(c).Encrypt(src)
+		assert.NoError(t, err)
+
+		c.SetMessageKey(false)
+		got, _ := NewStdDecrypter(c).Decrypt(dst[cipher.MessageKeySaltSize:])
+		assert.NotEqual(t, src, got)
+	})
+
+	t.Run("missing salt", func(t *testing.T) {
+		_, err := NewStdDecrypter(newCipher()).Decrypt([]byte("short"))
+		assert.IsType(t, DecryptError{}, err)
+		assert.Contains(t, err.Error(), "message key salt")
+	})
+
+	t.Run("streams unsupported", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := NewStreamEncrypter(&buf, newCipher()).Write(src)
+		assert.IsType(t, cipher.UnsupportedMessageKeyError{}, err)
+
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(src), newCipher()))
+		assert.IsType(t, cipher.UnsupportedMessageKeyError{}, err)
+	})
+}
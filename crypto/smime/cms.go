@@ -0,0 +1,553 @@
+package smime
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/dromara/dongle/coding/asn1util"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+var (
+	oidData          = asn1util.MustOID("Data")
+	oidSignedData    = asn1util.MustOID("SignedData")
+	oidEnvelopedData = asn1util.MustOID("EnvelopedData")
+
+	oidAttributeContentType   = asn1util.MustOID("ContentType")
+	oidAttributeMessageDigest = asn1util.MustOID("MessageDigest")
+	oidAttributeSigningTime   = asn1util.MustOID("SigningTime")
+
+	oidSHA256 = asn1util.MustOID("SHA256")
+	oidSHA384 = asn1util.MustOID("SHA384")
+	oidSHA512 = asn1util.MustOID("SHA512")
+
+	oidRSA             = asn1util.MustOID("RSA")
+	oidSHA256WithRSA   = asn1util.MustOID("SHA256-RSA")
+	oidSHA384WithRSA   = asn1util.MustOID("SHA384-RSA")
+	oidSHA512WithRSA   = asn1util.MustOID("SHA512-RSA")
+	oidECPublicKey     = asn1util.MustOID("EC")
+	oidECDSAWithSHA256 = asn1util.MustOID("ECDSA-SHA256")
+	oidECDSAWithSHA384 = asn1util.MustOID("ECDSA-SHA384")
+	oidECDSAWithSHA512 = asn1util.MustOID("ECDSA-SHA512")
+
+	oidAES128CBC  = asn1util.MustOID("AES-128-CBC")
+	oidAES192CBC  = asn1util.MustOID("AES-192-CBC")
+	oidAES256CBC  = asn1util.MustOID("AES-256-CBC")
+	oidDESEDE3CBC = asn1util.MustOID("DES-EDE3-CBC")
+)
+
+// digests maps the supported digest algorithms to their hashes.
+var digests = map[string]crypto.Hash{
+	oidSHA256.String(): crypto.SHA256,
+	oidSHA384.String(): crypto.SHA384,
+	oidSHA512.String(): crypto.SHA512,
+}
+
+// contentInfo mirrors the CMS ContentInfo structure, Content holds the
+// explicit [0] wrapper so its Bytes are the encoded inner content.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// signedData mirrors the CMS SignedData structure.
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo contentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// signerInfo mirrors the CMS SignerInfo structure.
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue // IssuerAndSerialNumber or [0] SubjectKeyIdentifier
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// envelopedData mirrors the CMS EnvelopedData structure.
+type envelopedData struct {
+	Version              int
+	OriginatorInfo       asn1.RawValue   `asn1:"optional,tag:0"`
+	RecipientInfos       []asn1.RawValue `asn1:"set"`
+	EncryptedContentInfo encryptedContentInfo
+	UnprotectedAttrs     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// keyTransRecipientInfo mirrors the CMS KeyTransRecipientInfo structure.
+type keyTransRecipientInfo struct {
+	Version                int
+	RID                    asn1.RawValue // IssuerAndSerialNumber or [0] SubjectKeyIdentifier
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+// encryptedContentInfo mirrors the CMS EncryptedContentInfo structure.
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// issuerAndSerial identifies a certificate by its issuer and serial number.
+type issuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// attribute mirrors the CMS Attribute structure.
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// attributeValue is a single valued attribute to be encoded.
+type attributeValue struct {
+	Type  asn1.ObjectIdentifier
+	Value any
+}
+
+// signedMessage is a parsed CMS signed data message.
+type signedMessage struct {
+	content      []byte // Encapsulated content, nil when detached
+	certificates []*x509.Certificate
+	signers      []signerInfo
+}
+
+// signContent creates a detached CMS signed data message over content,
+// signed with key through authenticated attributes.
+func signContent(content []byte, cert *x509.Certificate, key crypto.Signer, chain []*x509.Certificate) ([]byte, error) {
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(cert.PublicKey) {
+		return nil, KeyMismatchError{}
+	}
+	var sigAlg pkix.AlgorithmIdentifier
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidRSA, Parameters: asn1.NullRawValue}
+	case *ecdsa.PublicKey:
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}
+	default:
+		return nil, UnsupportedKeyError{Key: key.Public()}
+	}
+
+	digest := crypto.SHA256.New()
+	digest.Write(content)
+	attrs, err := marshalAttributes(
+		attributeValue{Type: oidAttributeContentType, Value: oidData},
+		attributeValue{Type: oidAttributeSigningTime, Value: utils.Now().UTC()},
+		attributeValue{Type: oidAttributeMessageDigest, Value: digest.Sum(nil)},
+	)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+	// The attributes are signed as an explicit SET OF and stored with an implicit [0] tag.
+	set, _ := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+	h := crypto.SHA256.New()
+	h.Write(set)
+	sig, err := key.Sign(utils.Rand(), h.Sum(nil), crypto.SHA256)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+
+	var certs []byte
+	for _, c := range append([]*x509.Certificate{cert}, chain...) {
+		certs = append(certs, c.Raw...)
+	}
+	digestAlg := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		EncapContentInfo: contentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                marshalIssuerAndSerial(cert),
+			DigestAlgorithm:    digestAlg,
+			SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+			SignatureAlgorithm: sigAlg,
+			Signature:          sig,
+		}},
+	}
+	return marshalContentInfo(oidSignedData, sd)
+}
+
+// parseSignedData parses a DER encoded CMS signed data message.
+func parseSignedData(der []byte) (*signedMessage, error) {
+	body, err := parseContentInfo(der, oidSignedData)
+	if err != nil {
+		return nil, err
+	}
+	var sd signedData
+	if _, err = asn1.Unmarshal(body, &sd); err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	if !sd.EncapContentInfo.ContentType.Equal(oidData) {
+		return nil, UnsupportedAlgorithmError{Algorithm: sd.EncapContentInfo.ContentType}
+	}
+	msg := &signedMessage{signers: sd.SignerInfos}
+	if len(sd.EncapContentInfo.Content.FullBytes) > 0 {
+		if msg.content, err = octets(sd.EncapContentInfo.Content.Bytes); err != nil {
+			return nil, err
+		}
+	}
+	if len(sd.Certificates.Bytes) > 0 {
+		if msg.certificates, err = x509.ParseCertificates(sd.Certificates.Bytes); err != nil {
+			return nil, InvalidMessageError{Err: err}
+		}
+	}
+	return msg, nil
+}
+
+// verify checks every signature over content against the bundled certificates
+// and returns the certificate of the first signer.
+func (m *signedMessage) verify(content []byte) (*x509.Certificate, error) {
+	if len(m.signers) == 0 {
+		return nil, SignerNotFoundError{}
+	}
+	var first *x509.Certificate
+	for _, si := range m.signers {
+		cert := findCertificate(m.certificates, si.SID)
+		if cert == nil {
+			return nil, SignerNotFoundError{}
+		}
+		if err := si.verify(cert, content); err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = cert
+		}
+	}
+	return first, nil
+}
+
+// verify checks the signature of si over content with cert.
+func (si signerInfo) verify(cert *x509.Certificate, content []byte) error {
+	hash, ok := digests[si.DigestAlgorithm.Algorithm.String()]
+	if !ok {
+		return UnsupportedAlgorithmError{Algorithm: si.DigestAlgorithm.Algorithm}
+	}
+	alg, err := signatureAlgorithm(si.SignatureAlgorithm.Algorithm, hash)
+	if err != nil {
+		return err
+	}
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		if cert.CheckSignature(alg, content, si.Signature) != nil {
+			return SignatureVerificationError{}
+		}
+		return nil
+	}
+
+	// The attributes are signed as an explicit SET OF, not with the implicit [0] tag.
+	signed := append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(signed, &attrs, "set"); err != nil {
+		return InvalidMessageError{Err: err}
+	}
+	h := hash.New()
+	h.Write(content)
+	digest := h.Sum(nil)
+	var digestOK, typeOK bool
+	for _, attr := range attrs {
+		switch {
+		case attr.Type.Equal(oidAttributeMessageDigest):
+			var value []byte
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+				return InvalidMessageError{Err: err}
+			}
+			digestOK = utils.ConstantTimeEqual(value, digest)
+		case attr.Type.Equal(oidAttributeContentType):
+			var value asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+				return InvalidMessageError{Err: err}
+			}
+			typeOK = value.Equal(oidData)
+		}
+	}
+	if !digestOK || !typeOK || cert.CheckSignature(alg, signed, si.Signature) != nil {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
+
+// signatureAlgorithm returns the x509 signature algorithm of a CMS signature
+// algorithm identifier combined with the signer digest.
+func signatureAlgorithm(oid asn1.ObjectIdentifier, hash crypto.Hash) (x509.SignatureAlgorithm, error) {
+	rsaAlgs := map[crypto.Hash]x509.SignatureAlgorithm{crypto.SHA256: x509.SHA256WithRSA, crypto.SHA384: x509.SHA384WithRSA, crypto.SHA512: x509.SHA512WithRSA}
+	ecAlgs := map[crypto.Hash]x509.SignatureAlgorithm{crypto.SHA256: x509.ECDSAWithSHA256, crypto.SHA384: x509.ECDSAWithSHA384, crypto.SHA512: x509.ECDSAWithSHA512}
+	switch {
+	case oid.Equal(oidRSA), oid.Equal(oidSHA256WithRSA), oid.Equal(oidSHA384WithRSA), oid.Equal(oidSHA512WithRSA):
+		return rsaAlgs[hash], nil
+	case oid.Equal(oidECPublicKey), oid.Equal(oidECDSAWithSHA256), oid.Equal(oidECDSAWithSHA384), oid.Equal(oidECDSAWithSHA512):
+		return ecAlgs[hash], nil
+	}
+	return x509.UnknownSignatureAlgorithm, UnsupportedAlgorithmError{Algorithm: oid}
+}
+
+// encryptContent creates a CMS enveloped data message of content encrypted with
+// a fresh AES-256-CBC key, transported to each recipient with RSA PKCS#1 v1.5
+// as OpenSSL does by default.
+func encryptContent(content []byte, recipients []*x509.Certificate) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, EmptyRecipientsError{}
+	}
+	key := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(utils.Rand(), key); err != nil {
+		return nil, EncryptError{Err: err}
+	}
+	if _, err := io.ReadFull(utils.Rand(), iv); err != nil {
+		return nil, EncryptError{Err: err}
+	}
+
+	infos := make([]asn1.RawValue, 0, len(recipients))
+	for _, cert := range recipients {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, UnsupportedKeyError{Key: cert.PublicKey}
+		}
+		encKey, err := rsa.EncryptPKCS1v15(utils.Rand(), pub, key)
+		if err != nil {
+			return nil, EncryptError{Err: err}
+		}
+		info, err := asn1.Marshal(keyTransRecipientInfo{
+			RID:                    marshalIssuerAndSerial(cert),
+			KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidRSA, Parameters: asn1.NullRawValue},
+			EncryptedKey:           encKey,
+		})
+		if err != nil {
+			return nil, EncryptError{Err: err}
+		}
+		infos = append(infos, asn1.RawValue{FullBytes: info})
+	}
+
+	padded := pkcs7Pad(content, aes.BlockSize)
+	block, _ := aes.NewCipher(key)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+	params, _ := asn1.Marshal(iv)
+	ed := envelopedData{
+		RecipientInfos: infos,
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidData,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: params}},
+			EncryptedContent:           asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: padded},
+		},
+	}
+	return marshalContentInfo(oidEnvelopedData, ed)
+}
+
+// decryptContent opens a DER encoded CMS enveloped data message for cert with key.
+func decryptContent(der []byte, cert *x509.Certificate, key crypto.Decrypter) ([]byte, error) {
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return nil, UnsupportedKeyError{Key: key.Public()}
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(cert.PublicKey) {
+		return nil, KeyMismatchError{}
+	}
+	body, err := parseContentInfo(der, oidEnvelopedData)
+	if err != nil {
+		return nil, err
+	}
+	var ed envelopedData
+	if _, err = asn1.Unmarshal(body, &ed); err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	eci := ed.EncryptedContentInfo
+	newCipher, keySize, err := contentCipher(eci.ContentEncryptionAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var info *keyTransRecipientInfo
+	for _, raw := range ed.RecipientInfos {
+		var ktri keyTransRecipientInfo
+		if raw.Tag != asn1.TagSequence || raw.Class != asn1.ClassUniversal {
+			continue
+		}
+		if _, err = asn1.Unmarshal(raw.FullBytes, &ktri); err != nil {
+			return nil, InvalidMessageError{Err: err}
+		}
+		if findCertificate([]*x509.Certificate{cert}, ktri.RID) != nil {
+			info = &ktri
+			break
+		}
+	}
+	if info == nil {
+		return nil, RecipientNotFoundError{}
+	}
+	if !info.KeyEncryptionAlgorithm.Algorithm.Equal(oidRSA) {
+		return nil, UnsupportedAlgorithmError{Algorithm: info.KeyEncryptionAlgorithm.Algorithm}
+	}
+	// A wrong padding yields a random key instead of an error, so the failure
+	// only shows after content decryption and leaks no padding oracle.
+	cek, err := key.Decrypt(utils.Rand(), info.EncryptedKey, &rsa.PKCS1v15DecryptOptions{SessionKeyLen: keySize})
+	if err != nil || len(cek) != keySize {
+		return nil, DecryptError{}
+	}
+
+	var iv []byte
+	if _, err = asn1.Unmarshal(eci.ContentEncryptionAlgorithm.Parameters.FullBytes, &iv); err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	ciphertext, err := octets(eci.EncryptedContent.FullBytes)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := newCipher(cek)
+	if len(iv) != block.BlockSize() || len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, DecryptError{}
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	plaintext, ok := pkcs7Unpad(plaintext, block.BlockSize())
+	if !ok {
+		return nil, DecryptError{}
+	}
+	return plaintext, nil
+}
+
+// contentCipher returns the block cipher constructor and key size of a content encryption algorithm.
+func contentCipher(oid asn1.ObjectIdentifier) (func([]byte) (cipher.Block, error), int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, nil
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, nil
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, nil
+	case oid.Equal(oidDESEDE3CBC):
+		return des.NewTripleDESCipher, 24, nil
+	}
+	return nil, 0, UnsupportedAlgorithmError{Algorithm: oid}
+}
+
+// findCertificate returns the certificate identified by a CMS signer or recipient identifier.
+func findCertificate(certs []*x509.Certificate, id asn1.RawValue) *x509.Certificate {
+	var ias issuerAndSerial
+	isSerial := id.Class == asn1.ClassUniversal && id.Tag == asn1.TagSequence
+	if isSerial {
+		if _, err := asn1.Unmarshal(id.FullBytes, &ias); err != nil || ias.SerialNumber == nil {
+			return nil
+		}
+	} else if id.Class != asn1.ClassContextSpecific || id.Tag != 0 {
+		return nil
+	}
+	for _, cert := range certs {
+		if isSerial && bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) && cert.SerialNumber.Cmp(ias.SerialNumber) == 0 {
+			return cert
+		}
+		if !isSerial && len(cert.SubjectKeyId) > 0 && bytes.Equal(cert.SubjectKeyId, id.Bytes) {
+			return cert
+		}
+	}
+	return nil
+}
+
+// parseContentInfo parses a CMS ContentInfo of the expected type and returns its content.
+func parseContentInfo(der []byte, contentType asn1.ObjectIdentifier) ([]byte, error) {
+	var ci contentInfo
+	rest, err := asn1.Unmarshal(der, &ci)
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	if len(rest) > 0 {
+		return nil, InvalidMessageError{Err: asn1.SyntaxError{Msg: "trailing data"}}
+	}
+	if !ci.ContentType.Equal(contentType) {
+		return nil, UnsupportedAlgorithmError{Algorithm: ci.ContentType}
+	}
+	return ci.Content.Bytes, nil
+}
+
+// marshalContentInfo wraps content in a CMS ContentInfo of contentType.
+func marshalContentInfo(contentType asn1.ObjectIdentifier, content any) ([]byte, error) {
+	body, err := asn1.Marshal(content)
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: contentType,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: body},
+	})
+}
+
+// octets decodes an OCTET STRING, either primitive or constructed from primitive
+// segments as some producers emit, ignoring the tag which may be implicit.
+func octets(der []byte) ([]byte, error) {
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	if !raw.IsCompound {
+		return raw.Bytes, nil
+	}
+	var out []byte
+	for rest := raw.Bytes; len(rest) > 0; {
+		var segment []byte
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &segment); err != nil {
+			return nil, InvalidMessageError{Err: err}
+		}
+		out = append(out, segment...)
+	}
+	return out, nil
+}
+
+// marshalAttributes encodes single valued attributes as the contents of a DER
+// SET OF, with the encoded elements sorted.
+func marshalAttributes(values ...attributeValue) ([]byte, error) {
+	encoded := make([][]byte, len(values))
+	for i, v := range values {
+		value, err := asn1.Marshal(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		if encoded[i], err = asn1.Marshal(attribute{Type: v.Type, Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value}}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return bytes.Join(encoded, nil), nil
+}
+
+// marshalIssuerAndSerial encodes the issuer and serial number identifying cert.
+func marshalIssuerAndSerial(cert *x509.Certificate) asn1.RawValue {
+	der, _ := asn1.Marshal(issuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber})
+	return asn1.RawValue{FullBytes: der}
+}
+
+// pkcs7Pad pads src to a multiple of size as required by CMS.
+func pkcs7Pad(src []byte, size int) []byte {
+	n := size - len(src)%size
+	return append(append([]byte{}, src...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// pkcs7Unpad removes CMS padding, reporting false when it is malformed.
+func pkcs7Unpad(src []byte, size int) ([]byte, bool) {
+	n := int(src[len(src)-1])
+	if n == 0 || n > size || n > len(src) {
+		return nil, false
+	}
+	for _, b := range src[len(src)-n:] {
+		if int(b) != n {
+			return nil, false
+		}
+	}
+	return src[:len(src)-n], true
+}
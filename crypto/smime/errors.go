@@ -0,0 +1,236 @@
+package smime
+
+import (
+	"encoding/asn1"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidMessageError represents an error when an S/MIME message or its CMS content cannot be decoded.
+type InvalidMessageError struct {
+	Err error // Underlying error from MIME, base64 or ASN.1 decoding
+}
+
+// Error returns a formatted error message describing the invalid message.
+func (e InvalidMessageError) Error() string {
+	return fmt.Sprintf("crypto/smime: invalid message: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-SMIME-001.
+func (e InvalidMessageError) Code() string {
+	return "DGL-SMIME-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidMessageError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedAlgorithmError represents an error when a message uses an unsupported algorithm or content type.
+type UnsupportedAlgorithmError struct {
+	Algorithm asn1.ObjectIdentifier // The unsupported object identifier
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/smime: unsupported algorithm or content type %s", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-SMIME-002.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-SMIME-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "", errcode.FieldAlgorithm, e.Algorithm.String())
+}
+
+// UnsupportedKeyError represents an error when a key type cannot be used for the operation.
+type UnsupportedKeyError struct {
+	Key any // The unsupported key
+}
+
+// Error returns a formatted error message describing the unsupported key.
+func (e UnsupportedKeyError) Error() string {
+	return fmt.Sprintf("crypto/smime: unsupported key type %T", e.Key)
+}
+
+// Code returns the stable error code DGL-SMIME-003.
+func (e UnsupportedKeyError) Code() string {
+	return "DGL-SMIME-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "key")
+}
+
+// KeyMismatchError represents an error when a private key does not match its certificate.
+type KeyMismatchError struct{}
+
+// Error returns a formatted error message describing the key mismatch.
+func (e KeyMismatchError) Error() string {
+	return "crypto/smime: private key does not match the certificate"
+}
+
+// Code returns the stable error code DGL-SMIME-004.
+func (e KeyMismatchError) Code() string {
+	return "DGL-SMIME-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeyMismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "key")
+}
+
+// EmptyRecipientsError represents an error when a message is encrypted for no recipients.
+type EmptyRecipientsError struct{}
+
+// Error returns a formatted error message describing the missing recipients.
+func (e EmptyRecipientsError) Error() string {
+	return "crypto/smime: at least one recipient is required"
+}
+
+// Code returns the stable error code DGL-SMIME-005.
+func (e EmptyRecipientsError) Code() string {
+	return "DGL-SMIME-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyRecipientsError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "")
+}
+
+// RecipientNotFoundError represents an error when a message is not encrypted for the given certificate.
+type RecipientNotFoundError struct{}
+
+// Error returns a formatted error message describing the missing recipient.
+func (e RecipientNotFoundError) Error() string {
+	return "crypto/smime: message is not encrypted for this certificate"
+}
+
+// Code returns the stable error code DGL-SMIME-006.
+func (e RecipientNotFoundError) Code() string {
+	return "DGL-SMIME-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RecipientNotFoundError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "")
+}
+
+// SignError represents an error when creating a signature fails.
+type SignError struct {
+	Err error // Underlying error from the signer
+}
+
+// Error returns a formatted error message describing the signing failure.
+func (e SignError) Error() string {
+	return fmt.Sprintf("crypto/smime: failed to sign: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-SMIME-007.
+func (e SignError) Code() string {
+	return "DGL-SMIME-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "sign", errcode.FieldCause, e.Err)
+}
+
+// EncryptError represents an error when encrypting a message fails.
+type EncryptError struct {
+	Err error // Underlying error from encryption
+}
+
+// Error returns a formatted error message describing the encryption failure.
+func (e EncryptError) Error() string {
+	return fmt.Sprintf("crypto/smime: failed to encrypt: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-SMIME-008.
+func (e EncryptError) Code() string {
+	return "DGL-SMIME-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "encrypt", errcode.FieldCause, e.Err)
+}
+
+// DecryptError represents an error when decrypting a message fails.
+type DecryptError struct{}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return "crypto/smime: failed to decrypt message"
+}
+
+// Code returns the stable error code DGL-SMIME-009.
+func (e DecryptError) Code() string {
+	return "DGL-SMIME-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "decrypt")
+}
+
+// SignerNotFoundError represents an error when a signed message carries no usable signer certificate.
+type SignerNotFoundError struct{}
+
+// Error returns a formatted error message describing the missing signer certificate.
+func (e SignerNotFoundError) Error() string {
+	return "crypto/smime: signer certificate not found"
+}
+
+// Code returns the stable error code DGL-SMIME-010.
+func (e SignerNotFoundError) Code() string {
+	return "DGL-SMIME-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignerNotFoundError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "sign")
+}
+
+// SignatureVerificationError represents an error when a message signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/smime: signature verification failed"
+}
+
+// Code returns the stable error code DGL-SMIME-011.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-SMIME-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "verify")
+}
+
+// CertificateError represents an error when the signer certificate does not chain to a trusted root.
+type CertificateError struct {
+	Err error // Underlying error from certificate verification
+}
+
+// Error returns a formatted error message describing the untrusted certificate.
+func (e CertificateError) Error() string {
+	return fmt.Sprintf("crypto/smime: untrusted signer certificate: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-SMIME-012.
+func (e CertificateError) Code() string {
+	return "DGL-SMIME-012"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CertificateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/smime", "S/MIME", "", errcode.FieldCause, e.Err)
+}
@@ -0,0 +1,268 @@
+// Package smime implements S/MIME message signing and encryption as specified by
+// RFC 8551. Sign produces a multipart/signed message with a detached CMS signature
+// and Encrypt produces an application/pkcs7-mime enveloped message, both readable
+// by mail clients and by openssl smime, so mail sending services no longer need to
+// shell out to OpenSSL.
+//
+// Messages are MIME entities, a header block followed by a blank line and the body,
+// such as "Content-Type: text/plain\r\n\r\nHello". Line endings are canonicalized
+// to CRLF before signing. The outer headers of the results, such as From, To and
+// Subject, are left to the caller to prepend. CMS content must be DER or definite
+// length BER, as produced by openssl smime without the -stream option.
+package smime
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/mail"
+	"strings"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// lineLength is the length of base64 lines in generated messages.
+const lineLength = 76
+
+// Sign signs a MIME entity with the private key of cert and returns a
+// multipart/signed message. The signer certificate and the optional chain of
+// intermediate certificates are embedded so recipients can verify the message.
+// RSA and ECDSA keys are supported and the signature uses SHA-256.
+func Sign(entity []byte, cert *x509.Certificate, key crypto.Signer, chain ...*x509.Certificate) ([]byte, error) {
+	if cert == nil || key == nil {
+		return nil, KeyMismatchError{}
+	}
+	entity = canonicalize(entity)
+	der, err := signContent(entity, cert, key, chain)
+	if err != nil {
+		return nil, err
+	}
+	boundary, err := newBoundary(entity)
+	if err != nil {
+		return nil, SignError{Err: err}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString(`Content-Type: multipart/signed; protocol="application/pkcs7-signature"; micalg=sha-256; boundary="` + boundary + "\"\r\n\r\n")
+	b.WriteString("This is an S/MIME signed message\r\n\r\n")
+	b.WriteString("--" + boundary + "\r\n")
+	b.Write(entity)
+	b.WriteString("\r\n--" + boundary + "\r\n")
+	b.WriteString("Content-Type: application/pkcs7-signature; name=\"smime.p7s\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("Content-Disposition: attachment; filename=\"smime.p7s\"\r\n\r\n")
+	writeBase64(&b, der)
+	b.WriteString("\r\n--" + boundary + "--\r\n")
+	return b.Bytes(), nil
+}
+
+// Verify verifies a signed message, either multipart/signed or opaque
+// application/pkcs7-mime signed data, and returns the signed MIME entity and the
+// signer certificate. The signer certificate must chain to opts.Roots, or to the
+// system roots when opts.Roots is nil, using the embedded certificates as
+// intermediates. Unless opts.KeyUsages is set the certificate must allow email protection.
+func Verify(message []byte, opts x509.VerifyOptions) (entity []byte, signer *x509.Certificate, err error) {
+	header, body, err := readMessage(message)
+	if err != nil {
+		return nil, nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, InvalidMessageError{Err: err}
+	}
+
+	var sd *signedMessage
+	switch mediaType {
+	case "multipart/signed":
+		parts, err := splitMultipart(body, params["boundary"])
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(parts) != 2 {
+			return nil, nil, InvalidMessageError{Err: errors.New("multipart/signed requires two parts")}
+		}
+		sigHeader, sigBody, err := readMessage(parts[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := decodeBody(sigHeader, sigBody)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sd, err = parseSignedData(der); err != nil {
+			return nil, nil, err
+		}
+		entity = parts[0]
+	case "application/pkcs7-mime", "application/x-pkcs7-mime":
+		der, err := decodeBody(header, body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sd, err = parseSignedData(der); err != nil {
+			return nil, nil, err
+		}
+		if sd.content == nil {
+			return nil, nil, InvalidMessageError{Err: errors.New("signed data has no content")}
+		}
+		entity = sd.content
+	default:
+		return nil, nil, InvalidMessageError{Err: errors.New("not a signed message: " + mediaType)}
+	}
+
+	if signer, err = sd.verify(entity); err != nil {
+		return nil, nil, err
+	}
+	if len(opts.KeyUsages) == 0 {
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection}
+	}
+	if opts.Intermediates == nil {
+		opts.Intermediates = x509.NewCertPool()
+	} else {
+		opts.Intermediates = opts.Intermediates.Clone()
+	}
+	for _, cert := range sd.certificates {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err = signer.Verify(opts); err != nil {
+		return nil, nil, CertificateError{Err: err}
+	}
+	return entity, signer, nil
+}
+
+// Encrypt encrypts a MIME entity for the recipient certificates and returns an
+// application/pkcs7-mime enveloped message. The content is encrypted with
+// AES-256-CBC and the content key is transported with each recipient's RSA key.
+func Encrypt(entity []byte, recipients ...*x509.Certificate) ([]byte, error) {
+	der, err := encryptContent(canonicalize(entity), recipients)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: application/pkcs7-mime; smime-type=enveloped-data; name=\"smime.p7m\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("Content-Disposition: attachment; filename=\"smime.p7m\"\r\n\r\n")
+	writeBase64(&b, der)
+	return b.Bytes(), nil
+}
+
+// Decrypt decrypts an enveloped message addressed to cert with its RSA private
+// key and returns the enclosed MIME entity, which may itself be a signed message.
+func Decrypt(message []byte, cert *x509.Certificate, key crypto.Decrypter) ([]byte, error) {
+	if cert == nil || key == nil {
+		return nil, KeyMismatchError{}
+	}
+	header, body, err := readMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	if mediaType != "application/pkcs7-mime" && mediaType != "application/x-pkcs7-mime" {
+		return nil, InvalidMessageError{Err: errors.New("not an encrypted message: " + mediaType)}
+	}
+	if t := params["smime-type"]; t != "" && t != "enveloped-data" {
+		return nil, InvalidMessageError{Err: errors.New("not an encrypted message: " + t)}
+	}
+	der, err := decodeBody(header, body)
+	if err != nil {
+		return nil, err
+	}
+	return decryptContent(der, cert, key)
+}
+
+// canonicalize converts all line endings of entity to CRLF.
+func canonicalize(entity []byte) []byte {
+	out := bytes.ReplaceAll(entity, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(out, []byte("\n"), []byte("\r\n"))
+}
+
+// newBoundary returns a random multipart boundary that does not occur in entity.
+func newBoundary(entity []byte) (string, error) {
+	buf := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(utils.Rand(), buf); err != nil {
+			return "", err
+		}
+		boundary := "----" + strings.ToUpper(hex.EncodeToString(buf))
+		if !bytes.Contains(entity, []byte(boundary)) {
+			return boundary, nil
+		}
+	}
+}
+
+// readMessage splits a MIME entity into its header and raw body, with line endings canonicalized.
+func readMessage(message []byte) (mail.Header, []byte, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(canonicalize(message)))
+	if err != nil {
+		return nil, nil, InvalidMessageError{Err: err}
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return nil, nil, InvalidMessageError{Err: err}
+	}
+	return msg.Header, body, nil
+}
+
+// splitMultipart returns the raw parts of a CRLF canonical multipart body, byte
+// for byte as they were signed, excluding the CRLF preceding each delimiter.
+func splitMultipart(body []byte, boundary string) ([][]byte, error) {
+	if boundary == "" {
+		return nil, InvalidMessageError{Err: errors.New("missing multipart boundary")}
+	}
+	delimiter := []byte("\r\n--" + boundary)
+	body = append([]byte("\r\n"), body...)
+	i := bytes.Index(body, delimiter)
+	if i < 0 {
+		return nil, InvalidMessageError{Err: errors.New("missing multipart delimiter")}
+	}
+	var parts [][]byte
+	for rest := body[i+len(delimiter):]; ; {
+		if bytes.HasPrefix(rest, []byte("--")) {
+			return parts, nil
+		}
+		// Skip transport padding after the delimiter.
+		eol := bytes.Index(rest, []byte("\r\n"))
+		if eol < 0 {
+			break
+		}
+		rest = rest[eol+2:]
+		end := bytes.Index(rest, delimiter)
+		if end < 0 {
+			break
+		}
+		parts = append(parts, rest[:end])
+		rest = rest[end+len(delimiter):]
+	}
+	return nil, InvalidMessageError{Err: errors.New("missing closing multipart delimiter")}
+}
+
+// decodeBody decodes a base64 encoded CMS part body.
+func decodeBody(header mail.Header, body []byte) ([]byte, error) {
+	if encoding := header.Get("Content-Transfer-Encoding"); !strings.EqualFold(strings.TrimSpace(encoding), "base64") {
+		return nil, InvalidMessageError{Err: errors.New("unsupported transfer encoding " + encoding)}
+	}
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+	if err != nil {
+		return nil, InvalidMessageError{Err: err}
+	}
+	return der, nil
+}
+
+// writeBase64 writes data base64 encoded in lines of lineLength, each ending with CRLF.
+func writeBase64(b *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > lineLength {
+		b.WriteString(encoded[:lineLength] + "\r\n")
+		encoded = encoded[lineLength:]
+	}
+	b.WriteString(encoded + "\r\n")
+}
@@ -0,0 +1,258 @@
+package smime
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var entity = []byte("Content-Type: text/plain; charset=utf-8\n\nHello S/MIME\nsecond line\n")
+
+type identity struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+var (
+	testCA    = newIdentity(nil, mustRSAKey(), "Test CA")
+	testAlice = newIdentity(testCA, mustRSAKey(), "alice@example.com")
+	testBob   = newIdentity(testCA, mustRSAKey(), "bob@example.com")
+)
+
+func mustRSAKey() crypto.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func newIdentity(issuer *identity, key crypto.Signer, name string) *identity {
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+	}
+	parent, signer := tmpl, key
+	if issuer == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		tmpl.ExtKeyUsage = nil
+	} else {
+		tmpl.EmailAddresses = []string{name}
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), signer)
+	if err != nil {
+		panic(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	return &identity{cert: cert, key: key}
+}
+
+func roots() x509.VerifyOptions {
+	pool := x509.NewCertPool()
+	pool.AddCert(testCA.cert)
+	return x509.VerifyOptions{Roots: pool}
+}
+
+func TestSignVerify(t *testing.T) {
+	signed, err := Sign(entity, testAlice.cert, testAlice.key)
+	require.NoError(t, err)
+	assert.Contains(t, string(signed), "Content-Type: multipart/signed; protocol=\"application/pkcs7-signature\"; micalg=sha-256;")
+	assert.Contains(t, string(signed), "\r\n\r\nHello S/MIME\r\nsecond line\r\n")
+
+	got, signer, err := Verify(signed, roots())
+	require.NoError(t, err)
+	assert.Equal(t, canonicalize(entity), got)
+	assert.Equal(t, testAlice.cert.Raw, signer.Raw)
+
+	t.Run("headers and lf line endings", func(t *testing.T) {
+		mail := "From: alice@example.com\nTo: bob@example.com\nSubject: signed\n" + strings.ReplaceAll(string(signed), "\r\n", "\n")
+		got, _, err := Verify([]byte(mail), roots())
+		require.NoError(t, err)
+		assert.Equal(t, canonicalize(entity), got)
+	})
+
+	t.Run("ecdsa", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		id := newIdentity(testCA, key, "carol@example.com")
+		signed, err := Sign(entity, id.cert, id.key)
+		require.NoError(t, err)
+		_, signer, err := Verify(signed, roots())
+		require.NoError(t, err)
+		assert.Equal(t, id.cert.Raw, signer.Raw)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		intermediate := newIdentity(testCA, mustRSAKey(), "Intermediate CA")
+		intermediate.cert.IsCA = true
+		tmpl := *intermediate.cert
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		tmpl.BasicConstraintsValid = true
+		tmpl.ExtKeyUsage = nil
+		der, err := x509.CreateCertificate(rand.Reader, &tmpl, testCA.cert, intermediate.key.Public(), testCA.key)
+		require.NoError(t, err)
+		intermediate.cert, err = x509.ParseCertificate(der)
+		require.NoError(t, err)
+		leaf := newIdentity(intermediate, mustRSAKey(), "dave@example.com")
+
+		signed, err := Sign(entity, leaf.cert, leaf.key, intermediate.cert)
+		require.NoError(t, err)
+		_, signer, err := Verify(signed, roots())
+		require.NoError(t, err)
+		assert.Equal(t, leaf.cert.Raw, signer.Raw)
+
+		signed, err = Sign(entity, leaf.cert, leaf.key)
+		require.NoError(t, err)
+		_, _, err = Verify(signed, roots())
+		assert.IsType(t, CertificateError{}, err)
+	})
+}
+
+func TestVerify_Error(t *testing.T) {
+	signed, err := Sign(entity, testAlice.cert, testAlice.key)
+	require.NoError(t, err)
+
+	t.Run("tampered content", func(t *testing.T) {
+		bad := bytes.Replace(signed, []byte("Hello S/MIME"), []byte("Hello S/MIMe"), 1)
+		_, _, err := Verify(bad, roots())
+		assert.Equal(t, SignatureVerificationError{}, err)
+		assert.Equal(t, "crypto/smime: signature verification failed", err.Error())
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		_, _, err := Verify(signed, x509.VerifyOptions{Roots: x509.NewCertPool()})
+		assert.IsType(t, CertificateError{}, err)
+		assert.Contains(t, err.Error(), "crypto/smime: untrusted signer certificate: ")
+	})
+
+	t.Run("not signed", func(t *testing.T) {
+		_, _, err := Verify(entity, roots())
+		assert.Equal(t, "crypto/smime: invalid message: not a signed message: text/plain", err.Error())
+	})
+
+	t.Run("missing boundary", func(t *testing.T) {
+		_, _, err := Verify([]byte("Content-Type: multipart/signed\r\n\r\nbody"), roots())
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, _, err := Verify(signed[:len(signed)-40], roots())
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+
+	t.Run("bad signature encoding", func(t *testing.T) {
+		bad := bytes.Replace(signed, []byte("Content-Transfer-Encoding: base64"), []byte("Content-Transfer-Encoding: quoted-printable"), 1)
+		_, _, err := Verify(bad, roots())
+		assert.Equal(t, "crypto/smime: invalid message: unsupported transfer encoding quoted-printable", err.Error())
+	})
+}
+
+func TestSign_Error(t *testing.T) {
+	_, err := Sign(entity, testAlice.cert, testBob.key)
+	assert.Equal(t, KeyMismatchError{}, err)
+	assert.Equal(t, "crypto/smime: private key does not match the certificate", err.Error())
+
+	_, err = Sign(entity, nil, testAlice.key)
+	assert.Equal(t, KeyMismatchError{}, err)
+
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	id := newIdentity(testCA, key, "erin@example.com")
+	_, err = Sign(entity, id.cert, id.key)
+	assert.IsType(t, UnsupportedKeyError{}, err)
+	assert.Equal(t, "crypto/smime: unsupported key type ed25519.PublicKey", err.Error())
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypted, err := Encrypt(entity, testAlice.cert, testBob.cert)
+	require.NoError(t, err)
+	assert.Contains(t, string(encrypted), "Content-Type: application/pkcs7-mime; smime-type=enveloped-data;")
+
+	for _, id := range []*identity{testAlice, testBob} {
+		got, err := Decrypt(encrypted, id.cert, id.key.(crypto.Decrypter))
+		require.NoError(t, err)
+		assert.Equal(t, canonicalize(entity), got)
+	}
+
+	t.Run("sign then encrypt", func(t *testing.T) {
+		signed, err := Sign(entity, testAlice.cert, testAlice.key)
+		require.NoError(t, err)
+		encrypted, err := Encrypt(signed, testBob.cert)
+		require.NoError(t, err)
+		decrypted, err := Decrypt(encrypted, testBob.cert, testBob.key.(crypto.Decrypter))
+		require.NoError(t, err)
+		got, _, err := Verify(decrypted, roots())
+		require.NoError(t, err)
+		assert.Equal(t, canonicalize(entity), got)
+	})
+}
+
+func TestDecrypt_Error(t *testing.T) {
+	encrypted, err := Encrypt(entity, testAlice.cert)
+	require.NoError(t, err)
+
+	t.Run("not a recipient", func(t *testing.T) {
+		_, err := Decrypt(encrypted, testBob.cert, testBob.key.(crypto.Decrypter))
+		assert.Equal(t, RecipientNotFoundError{}, err)
+		assert.Equal(t, "crypto/smime: message is not encrypted for this certificate", err.Error())
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		_, err := Decrypt(encrypted, testAlice.cert, testBob.key.(crypto.Decrypter))
+		assert.Equal(t, KeyMismatchError{}, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		header, body, err := readMessage(encrypted)
+		require.NoError(t, err)
+		der, err := decodeBody(header, body)
+		require.NoError(t, err)
+		der[len(der)-1] ^= 0xff
+		bad := "Content-Type: application/pkcs7-mime; smime-type=enveloped-data\r\nContent-Transfer-Encoding: base64\r\n\r\n" + base64.StdEncoding.EncodeToString(der)
+		_, err = Decrypt([]byte(bad), testAlice.cert, testAlice.key.(crypto.Decrypter))
+		assert.Equal(t, DecryptError{}, err)
+		assert.Equal(t, "crypto/smime: failed to decrypt message", err.Error())
+	})
+
+	t.Run("signed message", func(t *testing.T) {
+		signed, err := Sign(entity, testAlice.cert, testAlice.key)
+		require.NoError(t, err)
+		_, err = Decrypt(signed, testAlice.cert, testAlice.key.(crypto.Decrypter))
+		assert.IsType(t, InvalidMessageError{}, err)
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		_, err := Encrypt(entity)
+		assert.Equal(t, EmptyRecipientsError{}, err)
+		assert.Equal(t, "crypto/smime: at least one recipient is required", err.Error())
+	})
+
+	t.Run("ecdsa recipient", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		_, err = Encrypt(entity, newIdentity(testCA, key, "carol@example.com").cert)
+		assert.IsType(t, UnsupportedKeyError{}, err)
+	})
+}
@@ -2,6 +2,8 @@ package tea
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the TEA key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/tea: invalid key size %d, must be exactly 16 bytes", k)
 }
 
+// Code returns the stable error code DGL-TEA-001.
+func (k KeySizeError) Code() string {
+	return "DGL-TEA-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "key", errcode.FieldKeySize, int(k))
+}
+
 // EncryptError represents an error when TEA encryption fails.
 // This error occurs when the underlying TEA encryption operation fails.
 // The error includes the underlying error for detailed debugging.
@@ -28,6 +40,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/tea: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TEA-002.
+func (e EncryptError) Code() string {
+	return "DGL-TEA-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when TEA decryption fails.
 // This error occurs when the underlying TEA decryption operation fails.
 // The error includes the underlying error for detailed debugging.
@@ -41,6 +63,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/tea: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TEA-003.
+func (e DecryptError) Code() string {
+	return "DGL-TEA-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // WriteError represents an error when writing encrypted data fails.
 // This error occurs when writing encrypted data to the underlying writer fails.
 // The error includes the underlying error for detailed debugging.
@@ -54,6 +86,16 @@ func (e WriteError) Error() string {
 	return fmt.Sprintf("crypto/tea: failed to write encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TEA-004.
+func (e WriteError) Code() string {
+	return "DGL-TEA-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WriteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "write", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -67,6 +109,16 @@ func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/tea: failed to read encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TEA-005.
+func (e ReadError) Code() string {
+	return "DGL-TEA-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "read", errcode.FieldCause, e.Err)
+}
+
 // InvalidDataSizeError represents an error when the data size is invalid for TEA operations.
 // TEA requires data to be a multiple of 8 bytes (64 bits).
 type InvalidDataSizeError struct {
@@ -79,6 +131,16 @@ func (e InvalidDataSizeError) Error() string {
 	return fmt.Sprintf("crypto/tea: invalid data size %d, must be a multiple of 8 bytes", e.Size)
 }
 
+// Code returns the stable error code DGL-TEA-006.
+func (e InvalidDataSizeError) Code() string {
+	return "DGL-TEA-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidDataSizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "", "size", e.Size)
+}
+
 // UnsupportedBlockModeError represents an error when an unsupported block mode is used.
 type UnsupportedBlockModeError struct {
 	Mode string // The unsupported mode name
@@ -89,3 +151,13 @@ type UnsupportedBlockModeError struct {
 func (e UnsupportedBlockModeError) Error() string {
 	return fmt.Sprintf("crypto/tea: unsupported block mode '%s', tea only supports CBC, CTR, ECB, CFB, and OFB modes", e.Mode)
 }
+
+// Code returns the stable error code DGL-TEA-007.
+func (e UnsupportedBlockModeError) Code() string {
+	return "DGL-TEA-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedBlockModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/tea", "TEA", "", "mode", e.Mode)
+}
@@ -36,6 +36,9 @@ func NewStdEncrypter(c *cipher.TeaCipher) *StdEncrypter {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckGCMOptions(); e.Error != nil {
+		return e
+	}
 	e.block, e.Error = tea.NewCipherWithRounds(c.Key, c.Rounds)
 	return e
 }
@@ -56,6 +59,13 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, func(key []byte) (stdCipher.Block, error) {
+			return tea.NewCipherWithRounds(key, e.cipher.Rounds)
+		})
+	}
+
 	block, err := tea.NewCipherWithRounds(e.cipher.Key, e.cipher.Rounds)
 	if err != nil {
 		err = EncryptError{Err: err}
@@ -89,6 +99,9 @@ func NewStdDecrypter(c *cipher.TeaCipher) *StdDecrypter {
 		d.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return d
 	}
+	if d.Error = c.CheckGCMOptions(); d.Error != nil {
+		return d
+	}
 	block, err := tea.NewCipherWithRounds(c.Key, c.Rounds)
 	if err == nil {
 		d.block = block
@@ -112,6 +125,13 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, func(key []byte) (stdCipher.Block, error) {
+			return tea.NewCipherWithRounds(key, d.cipher.Rounds)
+		})
+	}
+
 	block, err := tea.NewCipherWithRounds(d.cipher.Key, d.cipher.Rounds)
 	if err != nil {
 		err = DecryptError{Err: err}
@@ -150,6 +170,9 @@ func NewStreamEncrypter(w io.Writer, c *cipher.TeaCipher) io.WriteCloser {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
 	e.block, e.Error = tea.NewCipherWithRounds(c.Key, c.Rounds)
 	return e
 }
@@ -242,6 +265,9 @@ func NewStreamDecrypter(r io.Reader, c *cipher.TeaCipher) io.Reader {
 		d.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return d
 	}
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
 	d.block, d.Error = tea.NewCipherWithRounds(c.Key, c.Rounds)
 	return d
 }
@@ -0,0 +1,112 @@
+package crypto
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// TempFile is a temporary file holding the output of EncryptToTempFile or
+// DecryptToTempFile, positioned at its start. Close removes the file; if the
+// caller forgets to, the file is removed when the TempFile is garbage collected.
+//
+// Where the platform supports it, the file is created without a name
+// (O_TMPFILE on Linux) so that even a crash cannot leave it behind. Otherwise
+// it is created with a random name, readable and writable by the owner only.
+type TempFile struct {
+	*os.File
+	path string // Name to remove on Close, empty for files without a name
+	once sync.Once
+	err  error
+}
+
+// newTempFile wraps f and arranges for it to be removed when it is collected.
+func newTempFile(f *os.File, path string) *TempFile {
+	t := &TempFile{File: f, path: path}
+	runtime.SetFinalizer(t, (*TempFile).Close)
+	return t
+}
+
+// Close closes and removes the file. Calling it again returns the result of the
+// first call.
+func (t *TempFile) Close() error {
+	t.once.Do(func() {
+		runtime.SetFinalizer(t, nil)
+		t.err = t.File.Close()
+		if t.path != "" {
+			if err := os.Remove(t.path); err != nil && t.err == nil {
+				t.err = err
+			}
+		}
+	})
+	return t.err
+}
+
+// EncryptToTempFile streams src through the encrypter created by fn into a
+// temporary file in dir, or in the default directory for temporary files when
+// dir is empty, for ciphertexts too large to hold in memory, for example
+//
+//	f, err := crypto.EncryptToTempFile("", file, func(w io.Writer) io.WriteCloser {
+//		return aes.NewStreamEncrypter(w, c)
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//
+// On error nothing is left behind.
+func EncryptToTempFile(dir string, src io.Reader, fn func(io.Writer) io.WriteCloser) (*TempFile, error) {
+	return toTempFile(dir, func(f io.Writer) error {
+		// The stream encrypters close the writer they wrap, which would close the
+		// file before it is handed out.
+		encrypter := fn(struct{ io.Writer }{f})
+		if _, err := io.CopyBuffer(encrypter, src, utils.Buffer(0, BufferSize)); err != nil {
+			encrypter.Close()
+			return err
+		}
+		return encrypter.Close()
+	})
+}
+
+// DecryptToTempFile streams src through the decrypter created by fn into a
+// temporary file, like EncryptToTempFile. The plaintext is only ever written to
+// the temporary file, which is removed when decryption fails.
+func DecryptToTempFile(dir string, src io.Reader, fn func(io.Reader) io.Reader) (*TempFile, error) {
+	return toTempFile(dir, func(f io.Writer) error {
+		_, err := io.CopyBuffer(f, fn(src), utils.Buffer(0, BufferSize))
+		return err
+	})
+}
+
+// toTempFile creates a temporary file, fills it with fn and rewinds it.
+func toTempFile(dir string, fn func(io.Writer) error) (*TempFile, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	f, path, err := createTemp(dir)
+	if err != nil {
+		return nil, err
+	}
+	t := newTempFile(f, path)
+	if err = fn(t.File); err == nil {
+		_, err = t.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// createTempFile creates a named temporary file in dir, readable and writable
+// by the owner only.
+func createTempFile(dir string) (*os.File, string, error) {
+	f, err := os.CreateTemp(dir, ".dongle.*.tmp")
+	if err != nil {
+		return nil, "", err
+	}
+	return f, f.Name(), nil
+}
@@ -0,0 +1,18 @@
+package crypto
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// createTemp creates an unnamed temporary file in dir, which the kernel removes
+// once it is closed, falling back to a named file on file systems and kernels
+// without O_TMPFILE. O_EXCL keeps the file from being linked into dir later.
+func createTemp(dir string) (*os.File, string, error) {
+	f, err := os.OpenFile(dir, os.O_RDWR|os.O_EXCL|unix.O_TMPFILE, 0o600)
+	if err != nil {
+		return createTempFile(dir)
+	}
+	return f, "", nil
+}
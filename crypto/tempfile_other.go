@@ -0,0 +1,10 @@
+//go:build !linux
+
+package crypto
+
+import "os"
+
+// createTemp creates a named temporary file in dir.
+func createTemp(dir string) (*os.File, string, error) {
+	return createTempFile(dir)
+}
@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/aes"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dirEntries returns the names of the files in dir.
+func dirEntries(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestEncryptToTempFile(t *testing.T) {
+	c := newAesCipher()
+	encrypter := func(w io.Writer) io.WriteCloser { return aes.NewStreamEncrypter(w, c) }
+	decrypter := func(r io.Reader) io.Reader { return aes.NewStreamDecrypter(r, c) }
+
+	t.Run("round trip", func(t *testing.T) {
+		dir := t.TempDir()
+		encrypted, err := EncryptToTempFile(dir, strings.NewReader("hello world"), encrypter)
+		require.NoError(t, err)
+		defer encrypted.Close()
+
+		raw, err := io.ReadAll(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, NewEncrypter().FromString("hello world").ByAes(c).ToRawBytes(), raw)
+
+		_, err = encrypted.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+		decrypted, err := DecryptToTempFile(dir, encrypted, decrypter)
+		require.NoError(t, err)
+		data, err := io.ReadAll(decrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), data)
+
+		require.NoError(t, decrypted.Close())
+		require.NoError(t, encrypted.Close())
+		assert.Empty(t, dirEntries(t, dir))
+	})
+
+	t.Run("default directory", func(t *testing.T) {
+		f, err := EncryptToTempFile("", strings.NewReader("hello"), encrypter)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+	})
+
+	t.Run("encryption error", func(t *testing.T) {
+		dir := t.TempDir()
+		c := newAesCipher()
+		c.SetKey([]byte("short"))
+		_, err := EncryptToTempFile(dir, strings.NewReader("hello"), func(w io.Writer) io.WriteCloser {
+			return aes.NewStreamEncrypter(w, c)
+		})
+		assert.Equal(t, aes.KeySizeError(5), err)
+		assert.Empty(t, dirEntries(t, dir))
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		dir := t.TempDir()
+		failure := errors.New("read failed")
+		_, err := EncryptToTempFile(dir, mock.NewErrorFile(failure), encrypter)
+		assert.Equal(t, failure, err)
+		assert.Empty(t, dirEntries(t, dir))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := EncryptToTempFile(filepath.Join(t.TempDir(), "missing"), strings.NewReader("hello"), encrypter)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestDecryptToTempFile(t *testing.T) {
+	c := newAesCipher()
+
+	t.Run("decryption error removes plaintext", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := DecryptToTempFile(dir, bytes.NewReader([]byte("short")), func(r io.Reader) io.Reader {
+			return aes.NewStreamDecrypter(r, c)
+		})
+		assert.Error(t, err)
+		assert.Empty(t, dirEntries(t, dir))
+	})
+}
+
+func TestTempFile_Close(t *testing.T) {
+	t.Run("named file", func(t *testing.T) {
+		dir := t.TempDir()
+		f, path, err := createTempFile(dir)
+		require.NoError(t, err)
+		temp := newTempFile(f, path)
+		assert.Len(t, dirEntries(t, dir), 1)
+		if runtime.GOOS != "windows" {
+			info, err := f.Stat()
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		}
+
+		assert.NoError(t, temp.Close())
+		assert.Empty(t, dirEntries(t, dir))
+		// Closing again returns the first result.
+		assert.NoError(t, temp.Close())
+	})
+
+	t.Run("removed by someone else", func(t *testing.T) {
+		f, path, err := createTempFile(t.TempDir())
+		require.NoError(t, err)
+		temp := newTempFile(f, path)
+		require.NoError(t, os.Remove(path))
+		assert.ErrorIs(t, temp.Close(), os.ErrNotExist)
+		assert.ErrorIs(t, temp.Close(), os.ErrNotExist)
+	})
+}
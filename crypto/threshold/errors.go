@@ -0,0 +1,262 @@
+package threshold
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidThresholdError represents an error when the threshold or number of players is out of range.
+type InvalidThresholdError struct {
+	Threshold int // Number of shares required to sign
+	Players   int // Total number of shares
+}
+
+// Error returns a formatted error message describing the invalid threshold.
+func (e InvalidThresholdError) Error() string {
+	return fmt.Sprintf("crypto/threshold: invalid threshold %d of %d players", e.Threshold, e.Players)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-001.
+func (e InvalidThresholdError) Code() string {
+	return "DGL-THRESHOLD-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidThresholdError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "threshold", e.Threshold, "players", e.Players)
+}
+
+// KeySizeError represents an error when the requested modulus size is too small.
+type KeySizeError struct {
+	Size int // Requested modulus size in bits
+}
+
+// Error returns a formatted error message describing the invalid key size.
+func (e KeySizeError) Error() string {
+	return fmt.Sprintf("crypto/threshold: invalid key size %d bits", e.Size)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-002.
+func (e KeySizeError) Code() string {
+	return "DGL-THRESHOLD-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "key", errcode.FieldKeySize, e.Size)
+}
+
+// InvalidPrimeError represents an error when a dealt prime is not a safe prime.
+type InvalidPrimeError struct{}
+
+// Error returns a formatted error message describing the invalid prime.
+func (e InvalidPrimeError) Error() string {
+	return "crypto/threshold: primes must be distinct safe primes"
+}
+
+// Code returns the stable error code DGL-THRESHOLD-003.
+func (e InvalidPrimeError) Code() string {
+	return "DGL-THRESHOLD-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPrimeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "")
+}
+
+// InvalidExponentError represents an error when the public exponent is not a prime larger than the number of players.
+type InvalidExponentError struct {
+	E int // The public exponent
+}
+
+// Error returns a formatted error message describing the invalid exponent.
+func (e InvalidExponentError) Error() string {
+	return fmt.Sprintf("crypto/threshold: invalid public exponent %d, must be a prime larger than the number of players", e.E)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-004.
+func (e InvalidExponentError) Code() string {
+	return "DGL-THRESHOLD-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidExponentError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "e", e.E)
+}
+
+// InvalidShareError represents an error when a key share does not match its verification key.
+type InvalidShareError struct {
+	Index int // Index of the share
+}
+
+// Error returns a formatted error message describing the invalid share.
+func (e InvalidShareError) Error() string {
+	return fmt.Sprintf("crypto/threshold: invalid key share %d", e.Index)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-005.
+func (e InvalidShareError) Code() string {
+	return "DGL-THRESHOLD-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidShareError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "index", e.Index)
+}
+
+// InvalidPartialSignatureError represents an error when a partial signature fails its proof of correctness.
+type InvalidPartialSignatureError struct {
+	Index int // Index of the share that produced the partial signature
+}
+
+// Error returns a formatted error message describing the invalid partial signature.
+func (e InvalidPartialSignatureError) Error() string {
+	return fmt.Sprintf("crypto/threshold: invalid partial signature from share %d", e.Index)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-006.
+func (e InvalidPartialSignatureError) Code() string {
+	return "DGL-THRESHOLD-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPartialSignatureError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "verify", "index", e.Index)
+}
+
+// DuplicateShareError represents an error when two partial signatures come from the same share.
+type DuplicateShareError struct {
+	Index int // The repeated share index
+}
+
+// Error returns a formatted error message describing the duplicate share.
+func (e DuplicateShareError) Error() string {
+	return fmt.Sprintf("crypto/threshold: duplicate partial signature from share %d", e.Index)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-007.
+func (e DuplicateShareError) Code() string {
+	return "DGL-THRESHOLD-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DuplicateShareError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "index", e.Index)
+}
+
+// InsufficientSharesError represents an error when fewer partial signatures than the threshold are combined.
+type InsufficientSharesError struct {
+	Have int // Number of partial signatures provided
+	Need int // Threshold
+}
+
+// Error returns a formatted error message describing the missing partial signatures.
+func (e InsufficientSharesError) Error() string {
+	return fmt.Sprintf("crypto/threshold: got %d partial signatures, need %d", e.Have, e.Need)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-008.
+func (e InsufficientSharesError) Code() string {
+	return "DGL-THRESHOLD-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InsufficientSharesError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "have", e.Have, "need", e.Need)
+}
+
+// UnsupportedHashError represents an error when a hash has no PKCS #1 v1.5 digest prefix.
+type UnsupportedHashError struct {
+	Hash crypto.Hash // The unsupported hash
+}
+
+// Error returns a formatted error message describing the unsupported hash.
+func (e UnsupportedHashError) Error() string {
+	return fmt.Sprintf("crypto/threshold: unsupported hash function %v", e.Hash)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-009.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-THRESHOLD-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "hash", e.Hash)
+}
+
+// InvalidDigestError represents an error when the digest size does not match the hash.
+type InvalidDigestError struct {
+	Size int // Size of the provided digest
+}
+
+// Error returns a formatted error message describing the invalid digest.
+func (e InvalidDigestError) Error() string {
+	return fmt.Sprintf("crypto/threshold: invalid digest size %d", e.Size)
+}
+
+// Code returns the stable error code DGL-THRESHOLD-010.
+func (e InvalidDigestError) Code() string {
+	return "DGL-THRESHOLD-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidDigestError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "", "size", e.Size)
+}
+
+// MessageTooLongError represents an error when the encoded digest does not fit the modulus.
+type MessageTooLongError struct{}
+
+// Error returns a formatted error message describing the oversized digest.
+func (e MessageTooLongError) Error() string {
+	return "crypto/threshold: digest too long for the key size"
+}
+
+// Code returns the stable error code DGL-THRESHOLD-011.
+func (e MessageTooLongError) Code() string {
+	return "DGL-THRESHOLD-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MessageTooLongError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "")
+}
+
+// SignatureVerificationError represents an error when the combined signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/threshold: combined signature verification failed"
+}
+
+// Code returns the stable error code DGL-THRESHOLD-012.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-THRESHOLD-012"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "verify")
+}
+
+// NotInvertibleError represents an error when a value shares a factor with the modulus.
+type NotInvertibleError struct{}
+
+// Error returns a formatted error message describing the non-invertible value.
+func (e NotInvertibleError) Error() string {
+	return "crypto/threshold: value is not invertible modulo N"
+}
+
+// Code returns the stable error code DGL-THRESHOLD-013.
+func (e NotInvertibleError) Code() string {
+	return "DGL-THRESHOLD-013"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e NotInvertibleError) Fields() map[string]any {
+	return errcode.NewFields("crypto/threshold", "Shamir", "")
+}
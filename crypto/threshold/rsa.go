@@ -0,0 +1,417 @@
+// Package threshold implements threshold RSA signatures following Shoup's
+// "Practical Threshold Signatures" (EUROCRYPT 2000). A dealer splits an RSA
+// signing key into shares so that any threshold of them can sign while fewer
+// learn nothing about the key. Every share has a public verification key, so
+// both the dealt shares and the partial signatures they produce can be checked
+// before combining. Combined signatures are plain RSA PKCS #1 v1.5 signatures
+// that verify with crypto/rsa.
+package threshold
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+)
+
+// DefaultExponent is the public exponent used by GenerateKey.
+const DefaultExponent = 65537
+
+// challengeBits is the size of the proof of correctness challenge.
+const challengeBits = 256
+
+// hashPrefixes holds the DER encoded DigestInfo prefixes of PKCS #1 v1.5.
+var hashPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA224: {0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x04, 0x05, 0x00, 0x04, 0x1c},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// PublicKey is the public part of a threshold RSA key.
+type PublicKey struct {
+	N *big.Int // RSA modulus, the product of two safe primes
+	E int      // Public exponent
+
+	Threshold int // Number of shares required to sign
+	Players   int // Total number of shares
+
+	V                *big.Int   // Generator of the squares modulo N
+	VerificationKeys []*big.Int // V raised to each share, VerificationKeys[i-1] belongs to share i
+}
+
+// KeyShare is one player's share of the private exponent.
+type KeyShare struct {
+	Index  int      // Share index, from 1 to Players
+	Secret *big.Int // The share of the private exponent
+}
+
+// PartialSignature is a signature share with its proof of correctness.
+type PartialSignature struct {
+	Index int      // Index of the share that produced it
+	X     *big.Int // The signature share
+	C     *big.Int // Proof challenge
+	Z     *big.Int // Proof response
+}
+
+// GenerateKey generates a fresh modulus of the given size from two safe primes
+// and deals it into players shares, any threshold of which can sign.
+// Safe prime generation is slow for production key sizes.
+func GenerateKey(random io.Reader, bits, threshold, players int) (*PublicKey, []*KeyShare, error) {
+	if bits < 64 {
+		return nil, nil, KeySizeError{Size: bits}
+	}
+	for {
+		p, err := safePrime(random, bits-bits/2)
+		if err != nil {
+			return nil, nil, err
+		}
+		q, err := safePrime(random, bits/2)
+		if err != nil {
+			return nil, nil, err
+		}
+		if p.Cmp(q) == 0 || new(big.Int).Mul(p, q).BitLen() != bits {
+			continue
+		}
+		return Deal(random, p, q, DefaultExponent, threshold, players)
+	}
+}
+
+// Deal splits the RSA key defined by the safe primes p and q and the public
+// exponent e into players shares, any threshold of which can sign.
+// e must be a prime larger than players.
+func Deal(random io.Reader, p, q *big.Int, e, threshold, players int) (*PublicKey, []*KeyShare, error) {
+	if threshold < 1 || threshold > players {
+		return nil, nil, InvalidThresholdError{Threshold: threshold, Players: players}
+	}
+	if e <= players || !big.NewInt(int64(e)).ProbablyPrime(20) {
+		return nil, nil, InvalidExponentError{E: e}
+	}
+	pp, ok := sophieGermain(p)
+	if !ok {
+		return nil, nil, InvalidPrimeError{}
+	}
+	qq, ok := sophieGermain(q)
+	if !ok || p.Cmp(q) == 0 {
+		return nil, nil, InvalidPrimeError{}
+	}
+	m := new(big.Int).Mul(pp, qq)
+	d := new(big.Int).ModInverse(big.NewInt(int64(e)), m)
+	if d == nil {
+		return nil, nil, InvalidExponentError{E: e}
+	}
+	n := new(big.Int).Mul(p, q)
+
+	// f(x) = d + a_1 x + ... + a_{k-1} x^{k-1} over Z_m, share i is f(i).
+	coeffs := []*big.Int{d}
+	for i := 1; i < threshold; i++ {
+		a, err := rand.Int(random, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs = append(coeffs, a)
+	}
+	r, err := randomUnit(random, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub := &PublicKey{
+		N:         n,
+		E:         e,
+		Threshold: threshold,
+		Players:   players,
+		V:         r.Mul(r, r).Mod(r, n),
+	}
+	shares := make([]*KeyShare, players)
+	for i := 1; i <= players; i++ {
+		s := new(big.Int)
+		x := big.NewInt(int64(i))
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			s.Mul(s, x).Add(s, coeffs[j]).Mod(s, m)
+		}
+		shares[i-1] = &KeyShare{Index: i, Secret: s}
+		pub.VerificationKeys = append(pub.VerificationKeys, new(big.Int).Exp(pub.V, s, n))
+	}
+	return pub, shares, nil
+}
+
+// RSA returns the public key as a standard RSA public key.
+func (pub *PublicKey) RSA() *rsa.PublicKey {
+	return &rsa.PublicKey{N: pub.N, E: pub.E}
+}
+
+// VerifyShare checks a dealt share against its verification key.
+func (pub *PublicKey) VerifyShare(share *KeyShare) error {
+	if share == nil {
+		return InvalidShareError{}
+	}
+	vk := pub.verificationKey(share.Index)
+	if vk == nil || share.Secret == nil || share.Secret.Sign() < 0 ||
+		new(big.Int).Exp(pub.V, share.Secret, pub.N).Cmp(vk) != 0 {
+		return InvalidShareError{Index: share.Index}
+	}
+	return nil
+}
+
+// Sign produces the partial signature of share over the hashed message digest,
+// together with a zero-knowledge proof that it was computed with the share.
+// The share is checked against its verification key first.
+//
+// The exponentiations by the share use math/big, which is not constant time,
+// and cannot be blinded since the order of the group is only known to the
+// dealer. Sign should therefore only run where an attacker cannot time it
+// closely, such as on a host that signs for authenticated requests only.
+func (share *KeyShare) Sign(random io.Reader, pub *PublicKey, hash crypto.Hash, digest []byte) (*PartialSignature, error) {
+	if err := pub.VerifyShare(share); err != nil {
+		return nil, err
+	}
+	vk := pub.verificationKey(share.Index)
+	x, err := pub.encode(hash, digest)
+	if err != nil {
+		return nil, err
+	}
+	delta := factorial(pub.Players)
+
+	// x_i = x^{2Δs_i}
+	exp := new(big.Int).Mul(delta, share.Secret)
+	exp.Lsh(exp, 1)
+	xi := new(big.Int).Exp(x, exp, pub.N)
+
+	// Prove log_v(v_i) = log_{x^{4Δ}}(x_i^2).
+	xt := new(big.Int).Exp(x, new(big.Int).Lsh(delta, 2), pub.N)
+	r, err := rand.Int(random, new(big.Int).Lsh(big.NewInt(1), uint(pub.N.BitLen()+2*challengeBits)))
+	if err != nil {
+		return nil, err
+	}
+	vr := new(big.Int).Exp(pub.V, r, pub.N)
+	xr := new(big.Int).Exp(xt, r, pub.N)
+	xi2 := new(big.Int).Exp(xi, big.NewInt(2), pub.N)
+	c := challenge(pub.V, xt, vk, xi2, vr, xr)
+	z := new(big.Int).Mul(share.Secret, c)
+	z.Add(z, r)
+	return &PartialSignature{Index: share.Index, X: xi, C: c, Z: z}, nil
+}
+
+// VerifyPartial checks the proof of correctness of a partial signature over digest.
+func (pub *PublicKey) VerifyPartial(hash crypto.Hash, digest []byte, ps *PartialSignature) error {
+	x, err := pub.encode(hash, digest)
+	if err != nil {
+		return err
+	}
+	return pub.verifyPartial(x, ps)
+}
+
+// Combine verifies the partial signatures over digest and combines the first
+// Threshold of them into a PKCS #1 v1.5 signature.
+func (pub *PublicKey) Combine(hash crypto.Hash, digest []byte, parts []*PartialSignature) ([]byte, error) {
+	if len(parts) < pub.Threshold {
+		return nil, InsufficientSharesError{Have: len(parts), Need: pub.Threshold}
+	}
+	x, err := pub.encode(hash, digest)
+	if err != nil {
+		return nil, err
+	}
+	parts = parts[:pub.Threshold]
+	seen := make(map[int]bool, len(parts))
+	for _, ps := range parts {
+		if seen[ps.Index] {
+			return nil, DuplicateShareError{Index: ps.Index}
+		}
+		seen[ps.Index] = true
+		if err = pub.verifyPartial(x, ps); err != nil {
+			return nil, err
+		}
+	}
+
+	// w = prod x_i^{2λ_i} satisfies w^e = x^{4Δ^2}.
+	delta := factorial(pub.Players)
+	w := big.NewInt(1)
+	for _, ps := range parts {
+		lambda := new(big.Int).Set(delta)
+		den := big.NewInt(1)
+		for _, other := range parts {
+			if other.Index == ps.Index {
+				continue
+			}
+			lambda.Mul(lambda, big.NewInt(int64(-other.Index)))
+			den.Mul(den, big.NewInt(int64(ps.Index-other.Index)))
+		}
+		lambda.Quo(lambda, den)
+		xl, err := expMod(ps.X, lambda.Lsh(lambda, 1), pub.N)
+		if err != nil {
+			return nil, err
+		}
+		w.Mul(w, xl).Mod(w, pub.N)
+	}
+
+	// With a 4Δ^2 + b e = 1 the signature is y = w^a x^b.
+	ep := new(big.Int).Mul(delta, delta)
+	ep.Lsh(ep, 2)
+	a, b := new(big.Int), new(big.Int)
+	new(big.Int).GCD(a, b, ep, big.NewInt(int64(pub.E)))
+	y, err := expMod(w, a, pub.N)
+	if err != nil {
+		return nil, err
+	}
+	xb, err := expMod(x, b, pub.N)
+	if err != nil {
+		return nil, err
+	}
+	y.Mul(y, xb).Mod(y, pub.N)
+
+	if new(big.Int).Exp(y, big.NewInt(int64(pub.E)), pub.N).Cmp(x) != 0 {
+		return nil, SignatureVerificationError{}
+	}
+	return y.FillBytes(make([]byte, (pub.N.BitLen()+7)/8)), nil
+}
+
+// Verify verifies a combined signature over digest.
+func (pub *PublicKey) Verify(hash crypto.Hash, digest, sig []byte) error {
+	if rsa.VerifyPKCS1v15(pub.RSA(), hash, digest, sig) != nil {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
+
+// verifyPartial checks a partial signature over the encoded message x.
+func (pub *PublicKey) verifyPartial(x *big.Int, ps *PartialSignature) error {
+	vk := pub.verificationKey(ps.Index)
+	if vk == nil || ps.X == nil || ps.C == nil || ps.Z == nil || ps.X.Sign() <= 0 || ps.X.Cmp(pub.N) >= 0 || ps.Z.Sign() < 0 {
+		return InvalidPartialSignatureError{Index: ps.Index}
+	}
+	// z = s c + r with s below N, c below 2^challengeBits and r below
+	// 2^(|N|+2 challengeBits), so honest responses never exceed this size and
+	// larger ones only make the exponentiations below expensive.
+	if ps.Z.BitLen() > pub.N.BitLen()+2*challengeBits+1 {
+		return InvalidPartialSignatureError{Index: ps.Index}
+	}
+	delta := factorial(pub.Players)
+	xt := new(big.Int).Exp(x, new(big.Int).Lsh(delta, 2), pub.N)
+	xi2 := new(big.Int).Exp(ps.X, big.NewInt(2), pub.N)
+	negC := new(big.Int).Neg(ps.C)
+
+	// v' = v^z v_i^{-c}, x' = x~^z x_i^{-2c}
+	vkc, err := expMod(vk, negC, pub.N)
+	if err != nil {
+		return InvalidPartialSignatureError{Index: ps.Index}
+	}
+	xic, err := expMod(xi2, negC, pub.N)
+	if err != nil {
+		return InvalidPartialSignatureError{Index: ps.Index}
+	}
+	vr := new(big.Int).Exp(pub.V, ps.Z, pub.N)
+	vr.Mul(vr, vkc).Mod(vr, pub.N)
+	xr := new(big.Int).Exp(xt, ps.Z, pub.N)
+	xr.Mul(xr, xic).Mod(xr, pub.N)
+	if challenge(pub.V, xt, vk, xi2, vr, xr).Cmp(ps.C) != 0 {
+		return InvalidPartialSignatureError{Index: ps.Index}
+	}
+	return nil
+}
+
+// verificationKey returns the verification key of share index, or nil if out of range.
+func (pub *PublicKey) verificationKey(index int) *big.Int {
+	if index < 1 || index > len(pub.VerificationKeys) {
+		return nil
+	}
+	return pub.VerificationKeys[index-1]
+}
+
+// encode applies the EMSA-PKCS1-v1_5 encoding to digest. A zero hash signs the digest directly.
+func (pub *PublicKey) encode(hash crypto.Hash, digest []byte) (*big.Int, error) {
+	var prefix []byte
+	if hash != 0 {
+		var ok bool
+		if prefix, ok = hashPrefixes[hash]; !ok {
+			return nil, UnsupportedHashError{Hash: hash}
+		}
+		if len(digest) != hash.Size() {
+			return nil, InvalidDigestError{Size: len(digest)}
+		}
+	}
+	k := (pub.N.BitLen() + 7) / 8
+	tLen := len(prefix) + len(digest)
+	if k < tLen+11 {
+		return nil, MessageTooLongError{}
+	}
+	em := make([]byte, k)
+	em[1] = 1
+	copy(em[2:k-tLen-1], bytes.Repeat([]byte{0xff}, k-tLen-3))
+	copy(em[k-tLen:], prefix)
+	copy(em[k-len(digest):], digest)
+	return new(big.Int).SetBytes(em), nil
+}
+
+// challenge hashes the proof transcript into a challengeBits sized integer.
+func challenge(values ...*big.Int) *big.Int {
+	h := sha256.New()
+	var size [4]byte
+	for _, v := range values {
+		b := v.Bytes()
+		binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+		h.Write(size[:])
+		h.Write(b)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// expMod returns b^e mod n, inverting b for negative exponents. It fails when
+// b has no inverse, which only happens if b shares a factor with n.
+func expMod(b, e, n *big.Int) (*big.Int, error) {
+	if e.Sign() < 0 {
+		inv := new(big.Int).ModInverse(b, n)
+		if inv == nil {
+			return nil, NotInvertibleError{}
+		}
+		return inv.Exp(inv, new(big.Int).Neg(e), n), nil
+	}
+	return new(big.Int).Exp(b, e, n), nil
+}
+
+// factorial returns n!.
+func factorial(n int) *big.Int {
+	return new(big.Int).MulRange(1, int64(n))
+}
+
+// safePrime returns a random prime p of the given size such that (p-1)/2 is also prime.
+func safePrime(random io.Reader, bits int) (*big.Int, error) {
+	for {
+		pp, err := rand.Prime(random, bits-1)
+		if err != nil {
+			return nil, err
+		}
+		p := new(big.Int).Lsh(pp, 1)
+		p.Add(p, big.NewInt(1))
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// sophieGermain returns (p-1)/2 if p is a safe prime.
+func sophieGermain(p *big.Int) (*big.Int, bool) {
+	if p == nil || p.Cmp(big.NewInt(5)) < 0 || !p.ProbablyPrime(20) {
+		return nil, false
+	}
+	pp := new(big.Int).Rsh(p, 1)
+	return pp, pp.ProbablyPrime(20)
+}
+
+// randomUnit returns a random element of the multiplicative group modulo n.
+func randomUnit(random io.Reader, n *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	for {
+		r, err := rand.Int(random, n)
+		if err != nil {
+			return nil, err
+		}
+		if r.Cmp(one) > 0 && new(big.Int).GCD(nil, nil, r, n).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package threshold
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Two 256-bit safe primes, giving a 512-bit modulus large enough for SHA-256 PKCS #1 v1.5.
+var (
+	testP, _ = new(big.Int).SetString("cb1fced3e355e4d58fc44b58d677c190c1da41e34b6226195a9918dea5a4991f", 16)
+	testQ, _ = new(big.Int).SetString("f7edc53c23c7a164392fbd44bb96ce9dabea3d880a8b18fb09622ac7de8f9c37", 16)
+)
+
+func deal(t *testing.T, threshold, players int) (*PublicKey, []*KeyShare) {
+	pub, shares, err := Deal(rand.Reader, testP, testQ, DefaultExponent, threshold, players)
+	require.NoError(t, err)
+	return pub, shares
+}
+
+func partials(t *testing.T, pub *PublicKey, shares []*KeyShare, digest []byte) []*PartialSignature {
+	parts := make([]*PartialSignature, len(shares))
+	for i, share := range shares {
+		ps, err := share.Sign(rand.Reader, pub, crypto.SHA256, digest)
+		require.NoError(t, err)
+		parts[i] = ps
+	}
+	return parts
+}
+
+func TestDeal(t *testing.T) {
+	pub, shares := deal(t, 3, 5)
+	assert.Len(t, shares, 5)
+	assert.Len(t, pub.VerificationKeys, 5)
+	for i, share := range shares {
+		assert.Equal(t, i+1, share.Index)
+		assert.NoError(t, pub.VerifyShare(share))
+	}
+
+	tampered := &KeyShare{Index: 2, Secret: new(big.Int).Add(shares[1].Secret, big.NewInt(1))}
+	assert.Equal(t, InvalidShareError{Index: 2}, pub.VerifyShare(tampered))
+	assert.Equal(t, InvalidShareError{Index: 6}, pub.VerifyShare(&KeyShare{Index: 6, Secret: big.NewInt(1)}))
+	assert.Equal(t, "crypto/threshold: invalid key share 6", InvalidShareError{Index: 6}.Error())
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, _, err := Deal(rand.Reader, testP, testQ, DefaultExponent, 0, 5)
+		assert.Equal(t, InvalidThresholdError{Threshold: 0, Players: 5}, err)
+		assert.Equal(t, "crypto/threshold: invalid threshold 0 of 5 players", err.Error())
+		_, _, err = Deal(rand.Reader, testP, testQ, DefaultExponent, 6, 5)
+		assert.Equal(t, InvalidThresholdError{Threshold: 6, Players: 5}, err)
+
+		_, _, err = Deal(rand.Reader, testP, testQ, 5, 3, 5)
+		assert.Equal(t, InvalidExponentError{E: 5}, err)
+		assert.Equal(t, "crypto/threshold: invalid public exponent 5, must be a prime larger than the number of players", err.Error())
+		_, _, err = Deal(rand.Reader, testP, testQ, 65535, 3, 5)
+		assert.Equal(t, InvalidExponentError{E: 65535}, err)
+
+		notSafe := new(big.Int).Add(testP, big.NewInt(2))
+		_, _, err = Deal(rand.Reader, notSafe, testQ, DefaultExponent, 3, 5)
+		assert.Equal(t, InvalidPrimeError{}, err)
+		assert.Equal(t, "crypto/threshold: primes must be distinct safe primes", err.Error())
+		_, _, err = Deal(rand.Reader, testP, testP, DefaultExponent, 3, 5)
+		assert.Equal(t, InvalidPrimeError{}, err)
+		_, _, err = Deal(rand.Reader, testP, big.NewInt(23), DefaultExponent, 3, 5)
+		assert.NoError(t, err)
+		_, _, err = Deal(rand.Reader, testP, big.NewInt(13), DefaultExponent, 3, 5)
+		assert.Equal(t, InvalidPrimeError{}, err)
+	})
+
+	t.Run("random error", func(t *testing.T) {
+		_, _, err := Deal(mock.NewErrorFile(errors.New("read error")), testP, testQ, DefaultExponent, 3, 5)
+		assert.Error(t, err)
+		_, _, err = Deal(mock.NewErrorFile(errors.New("read error")), testP, testQ, DefaultExponent, 1, 5)
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerateKey(t *testing.T) {
+	pub, shares, err := GenerateKey(rand.Reader, 128, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 128, pub.N.BitLen())
+	assert.Equal(t, DefaultExponent, pub.E)
+	for _, share := range shares {
+		assert.NoError(t, pub.VerifyShare(share))
+	}
+
+	_, _, err = GenerateKey(rand.Reader, 32, 2, 3)
+	assert.Equal(t, KeySizeError{Size: 32}, err)
+	assert.Equal(t, "crypto/threshold: invalid key size 32 bits", err.Error())
+	_, _, err = GenerateKey(mock.NewErrorFile(errors.New("read error")), 128, 2, 3)
+	assert.Error(t, err)
+}
+
+func TestCombine(t *testing.T) {
+	pub, shares := deal(t, 3, 5)
+	digest := sha256.Sum256([]byte("release v1.0.0"))
+	parts := partials(t, pub, shares, digest[:])
+	for _, ps := range parts {
+		assert.NoError(t, pub.VerifyPartial(crypto.SHA256, digest[:], ps))
+	}
+
+	for _, subset := range [][]*PartialSignature{
+		{parts[0], parts[1], parts[2]},
+		{parts[4], parts[2], parts[0]},
+		{parts[1], parts[3], parts[4]},
+		parts,
+	} {
+		sig, err := pub.Combine(crypto.SHA256, digest[:], subset)
+		require.NoError(t, err)
+		assert.Len(t, sig, 64)
+		assert.NoError(t, rsa.VerifyPKCS1v15(pub.RSA(), crypto.SHA256, digest[:], sig))
+		assert.NoError(t, pub.Verify(crypto.SHA256, digest[:], sig))
+	}
+
+	t.Run("threshold of one", func(t *testing.T) {
+		pub, shares := deal(t, 1, 3)
+		sig, err := pub.Combine(crypto.SHA256, digest[:], partials(t, pub, shares[2:], digest[:]))
+		require.NoError(t, err)
+		assert.NoError(t, pub.Verify(crypto.SHA256, digest[:], sig))
+	})
+
+	t.Run("insufficient shares", func(t *testing.T) {
+		_, err := pub.Combine(crypto.SHA256, digest[:], parts[:2])
+		assert.Equal(t, InsufficientSharesError{Have: 2, Need: 3}, err)
+		assert.Equal(t, "crypto/threshold: got 2 partial signatures, need 3", err.Error())
+	})
+
+	t.Run("duplicate share", func(t *testing.T) {
+		_, err := pub.Combine(crypto.SHA256, digest[:], []*PartialSignature{parts[0], parts[1], parts[0]})
+		assert.Equal(t, DuplicateShareError{Index: 1}, err)
+		assert.Equal(t, "crypto/threshold: duplicate partial signature from share 1", err.Error())
+	})
+
+	t.Run("invalid partial signature", func(t *testing.T) {
+		forged := *parts[1]
+		forged.X = new(big.Int).Add(forged.X, big.NewInt(1))
+		_, err := pub.Combine(crypto.SHA256, digest[:], []*PartialSignature{parts[0], &forged, parts[2]})
+		assert.Equal(t, InvalidPartialSignatureError{Index: 2}, err)
+		assert.Equal(t, "crypto/threshold: invalid partial signature from share 2", err.Error())
+
+		other := sha256.Sum256([]byte("other"))
+		assert.Equal(t, InvalidPartialSignatureError{Index: 1}, pub.VerifyPartial(crypto.SHA256, other[:], parts[0]))
+		assert.Equal(t, InvalidPartialSignatureError{Index: 1}, pub.VerifyPartial(crypto.SHA256, digest[:], &PartialSignature{Index: 1}))
+		assert.Equal(t, InvalidPartialSignatureError{Index: 9}, pub.VerifyPartial(crypto.SHA256, digest[:], &PartialSignature{Index: 9}))
+
+		oversized := *parts[0]
+		oversized.Z = new(big.Int).Lsh(big.NewInt(1), uint(pub.N.BitLen()+2*challengeBits+1))
+		assert.Equal(t, InvalidPartialSignatureError{Index: 1}, pub.VerifyPartial(crypto.SHA256, digest[:], &oversized))
+	})
+
+	t.Run("not invertible", func(t *testing.T) {
+		_, err := expMod(big.NewInt(6), big.NewInt(-1), big.NewInt(9))
+		assert.Equal(t, NotInvertibleError{}, err)
+		assert.Equal(t, "crypto/threshold: value is not invertible modulo N", err.Error())
+		assert.Equal(t, "DGL-THRESHOLD-013", NotInvertibleError{}.Code())
+
+		y, err := expMod(big.NewInt(2), big.NewInt(-1), big.NewInt(9))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), y)
+	})
+
+	t.Run("invalid share", func(t *testing.T) {
+		for _, share := range []*KeyShare{
+			{Index: 1},
+			{Index: 1, Secret: new(big.Int).Neg(shares[0].Secret)},
+			{Index: 1, Secret: new(big.Int).Add(shares[0].Secret, big.NewInt(1))},
+			{Index: 6, Secret: shares[0].Secret},
+		} {
+			_, err := share.Sign(rand.Reader, pub, crypto.SHA256, digest[:])
+			assert.Equal(t, InvalidShareError{Index: share.Index}, err)
+		}
+		var share *KeyShare
+		_, err := share.Sign(rand.Reader, pub, crypto.SHA256, digest[:])
+		assert.Equal(t, InvalidShareError{}, err)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		sig, err := pub.Combine(crypto.SHA256, digest[:], parts)
+		require.NoError(t, err)
+		sig[0] ^= 1
+		assert.Equal(t, SignatureVerificationError{}, pub.Verify(crypto.SHA256, digest[:], sig))
+		assert.Equal(t, "crypto/threshold: combined signature verification failed", SignatureVerificationError{}.Error())
+	})
+}
+
+func TestEncode(t *testing.T) {
+	pub, shares := deal(t, 2, 3)
+
+	t.Run("raw digest", func(t *testing.T) {
+		msg := []byte("prehashed")
+		parts := make([]*PartialSignature, 2)
+		for i := range parts {
+			ps, err := shares[i].Sign(rand.Reader, pub, 0, msg)
+			require.NoError(t, err)
+			parts[i] = ps
+		}
+		sig, err := pub.Combine(0, msg, parts)
+		require.NoError(t, err)
+		assert.NoError(t, rsa.VerifyPKCS1v15(pub.RSA(), 0, msg, sig))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := shares[0].Sign(rand.Reader, pub, crypto.MD5, make([]byte, 16))
+		assert.Equal(t, UnsupportedHashError{Hash: crypto.MD5}, err)
+		assert.Equal(t, "crypto/threshold: unsupported hash function MD5", err.Error())
+
+		_, err = shares[0].Sign(rand.Reader, pub, crypto.SHA256, make([]byte, 31))
+		assert.Equal(t, InvalidDigestError{Size: 31}, err)
+		assert.Equal(t, "crypto/threshold: invalid digest size 31", err.Error())
+
+		_, err = shares[0].Sign(rand.Reader, pub, crypto.SHA512, make([]byte, 64))
+		assert.Equal(t, MessageTooLongError{}, err)
+		assert.Equal(t, "crypto/threshold: digest too long for the key size", err.Error())
+
+		assert.Error(t, pub.VerifyPartial(crypto.MD5, nil, nil))
+		_, err = pub.Combine(crypto.MD5, nil, make([]*PartialSignature, 2))
+		assert.Error(t, err)
+
+		_, err = (&KeyShare{Index: 4, Secret: big.NewInt(1)}).Sign(rand.Reader, pub, crypto.SHA256, make([]byte, 32))
+		assert.Equal(t, InvalidShareError{Index: 4}, err)
+		_, err = shares[0].Sign(mock.NewErrorFile(errors.New("read error")), pub, crypto.SHA256, make([]byte, 32))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,47 @@
+package throttle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// ThrottledError represents an error when an identifier has used up its
+// attempts. The verification was not run.
+type ThrottledError struct {
+	RetryAfter time.Duration // How long until the next attempt is allowed
+}
+
+// Error returns a formatted error message describing when to retry.
+func (e ThrottledError) Error() string {
+	return fmt.Sprintf("crypto/throttle: too many attempts, retry after %s", e.RetryAfter)
+}
+
+// Code returns the stable error code DGL-THROTTLE-001.
+func (e ThrottledError) Code() string {
+	return "DGL-THROTTLE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ThrottledError) Fields() map[string]any {
+	return errcode.NewFields("crypto/throttle", "", "verify", "retry_after", e.RetryAfter.String())
+}
+
+// VerifyError represents an error when the wrapped verification failed.
+type VerifyError struct{}
+
+// Error returns a formatted error message describing the failed verification.
+func (e VerifyError) Error() string {
+	return "crypto/throttle: verification failed"
+}
+
+// Code returns the stable error code DGL-THROTTLE-002.
+func (e VerifyError) Code() string {
+	return "DGL-THROTTLE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e VerifyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/throttle", "", "verify")
+}
@@ -0,0 +1,198 @@
+// Package throttle limits brute-force attempts against verification APIs such
+// as password, MAC or signature checks. Each identifier, typically a client IP
+// or a user name, owns a token bucket: every attempt takes a token, a success
+// refills the bucket and tokens otherwise come back at a fixed rate. Failed
+// and throttled attempts can be padded to the same minimum duration so that
+// response times do not reveal which of the two happened.
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// DefaultBurst is the default number of attempts allowed in a row.
+const DefaultBurst = 5
+
+// DefaultInterval is the default time it takes for one attempt to come back.
+const DefaultInterval = time.Minute
+
+// minPrune is the bucket count below which full buckets are not pruned.
+const minPrune = 1024
+
+// bucket holds the attempts left for an identifier.
+type bucket struct {
+	tokens float64   // Attempts left, possibly fractional while refilling
+	last   time.Time // When tokens was last updated
+}
+
+// Throttler limits verification attempts per identifier.
+// It is safe for concurrent use.
+type Throttler struct {
+	mu       sync.Mutex
+	burst    float64               // Maximum number of tokens in a bucket
+	interval time.Duration         // Time it takes for one token to come back
+	delay    time.Duration         // Minimum duration of a failed attempt
+	buckets  map[string]*bucket    // Buckets of identifiers that failed recently
+	prune    int                   // Bucket count that triggers the next prune
+	now      func() time.Time      // Clock returning the current time
+	sleep    func(d time.Duration) // Used to pad failed attempts
+}
+
+// NewThrottler returns a new Throttler allowing burst attempts in a row per
+// identifier, with one more attempt allowed every interval. Non-positive values
+// use DefaultBurst and DefaultInterval.
+func NewThrottler(burst int, interval time.Duration) *Throttler {
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Throttler{
+		burst:    float64(burst),
+		interval: interval,
+		buckets:  make(map[string]*bucket),
+		prune:    minPrune,
+		now:      utils.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// SetClock sets the function returning the current time.
+func (t *Throttler) SetClock(now func() time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = now
+}
+
+// SetDelay sets the minimum duration of a failed or throttled Verify call.
+// Padding both failure paths to the same duration keeps an attacker from
+// telling them apart, and from learning how long the verification took. It
+// should be larger than the slowest verification, zero disables padding.
+func (t *Throttler) SetDelay(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delay = d
+}
+
+// Verify runs verify for the identifier key unless it is throttled.
+// The attempt is taken before verify runs, so concurrent attempts cannot exceed
+// the burst. It returns nil and resets the identifier when verify succeeds, a
+// VerifyError when it fails and a ThrottledError, without running verify, when
+// no attempt is left.
+func (t *Throttler) Verify(key string, verify func() bool) error {
+	t.mu.Lock()
+	start, delay := t.now(), t.delay
+	retry := t.take(key, start)
+	t.mu.Unlock()
+
+	var err error
+	switch {
+	case retry > 0:
+		err = ThrottledError{RetryAfter: retry}
+	case verify():
+		t.Reset(key)
+		return nil
+	default:
+		err = VerifyError{}
+	}
+	if wait := delay - t.since(start); wait > 0 {
+		t.sleep(wait)
+	}
+	return err
+}
+
+// Allow reports whether the identifier key has an attempt left, without
+// taking it. It returns a ThrottledError when it has none.
+func (t *Throttler) Allow(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.buckets[key]
+	if !ok {
+		return nil
+	}
+	if tokens := t.refill(b, t.now()); tokens < 1 {
+		return ThrottledError{RetryAfter: t.wait(tokens)}
+	}
+	return nil
+}
+
+// Remaining returns the number of attempts left for the identifier key.
+func (t *Throttler) Remaining(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.buckets[key]
+	if !ok {
+		return int(t.burst)
+	}
+	return int(t.refill(b, t.now()))
+}
+
+// Reset forgets the failed attempts of the identifier key.
+func (t *Throttler) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.buckets, key)
+}
+
+// Len returns the number of identifiers currently tracked.
+func (t *Throttler) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.buckets)
+}
+
+// take takes an attempt from the bucket of key and returns zero, or returns
+// how long to wait when none is left. The caller must hold the lock.
+func (t *Throttler) take(key string, now time.Time) time.Duration {
+	b, ok := t.buckets[key]
+	if !ok {
+		if len(t.buckets) >= t.prune {
+			t.pruneFull(now)
+		}
+		b = &bucket{tokens: t.burst, last: now}
+		t.buckets[key] = b
+	}
+	tokens := t.refill(b, now)
+	if tokens < 1 {
+		return t.wait(tokens)
+	}
+	b.tokens--
+	return 0
+}
+
+// refill adds the tokens that came back since the last update of b and returns
+// the tokens available. The caller must hold the lock.
+func (t *Throttler) refill(b *bucket, now time.Time) float64 {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(t.burst, b.tokens+float64(elapsed)/float64(t.interval))
+		b.last = now
+	}
+	return b.tokens
+}
+
+// wait returns how long it takes for a bucket holding tokens to get one back.
+func (t *Throttler) wait(tokens float64) time.Duration {
+	return time.Duration((1 - tokens) * float64(t.interval))
+}
+
+// pruneFull drops buckets that refilled completely, as they behave like
+// missing ones, and schedules the next prune. The caller must hold the lock.
+func (t *Throttler) pruneFull(now time.Time) {
+	for key, b := range t.buckets {
+		if t.refill(b, now) >= t.burst {
+			delete(t.buckets, key)
+		}
+	}
+	t.prune = max(minPrune, 2*len(t.buckets))
+}
+
+// since returns the time elapsed since start on the throttler clock.
+func (t *Throttler) since(start time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.now().Sub(start)
+}
@@ -0,0 +1,131 @@
+package throttle
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock whose sleep moves time forward.
+type fakeClock struct {
+	mu    sync.Mutex
+	t     time.Time
+	slept time.Duration
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func (c *fakeClock) sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slept += d
+	c.t = c.t.Add(d)
+}
+
+func newThrottler(burst int, interval time.Duration) (*Throttler, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	th := NewThrottler(burst, interval)
+	th.SetClock(clock.now)
+	th.sleep = clock.sleep
+	return th, clock
+}
+
+func TestNewThrottler(t *testing.T) {
+	th := NewThrottler(0, 0)
+	assert.Equal(t, DefaultBurst, th.Remaining("alice"))
+	assert.Equal(t, DefaultInterval, th.interval)
+}
+
+func TestThrottler_Verify(t *testing.T) {
+	fail := func() bool { return false }
+	pass := func() bool { return true }
+
+	t.Run("burst then throttled", func(t *testing.T) {
+		th, _ := newThrottler(3, time.Minute)
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, VerifyError{}, th.Verify("alice", fail))
+		}
+		called := false
+		err := th.Verify("alice", func() bool { called = true; return true })
+		assert.Equal(t, ThrottledError{RetryAfter: time.Minute}, err)
+		assert.False(t, called)
+		assert.Contains(t, err.Error(), "retry after 1m0s")
+		assert.Nil(t, th.Verify("bob", pass))
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		th, clock := newThrottler(2, time.Minute)
+		th.Verify("alice", fail)
+		th.Verify("alice", fail)
+		clock.advance(45 * time.Second)
+		assert.Equal(t, ThrottledError{RetryAfter: 15 * time.Second}, th.Allow("alice"))
+		clock.advance(15 * time.Second)
+		assert.Nil(t, th.Allow("alice"))
+		assert.Equal(t, 1, th.Remaining("alice"))
+		clock.advance(time.Hour)
+		assert.Equal(t, 2, th.Remaining("alice"))
+	})
+
+	t.Run("success resets", func(t *testing.T) {
+		th, _ := newThrottler(2, time.Minute)
+		th.Verify("alice", fail)
+		assert.Equal(t, 1, th.Remaining("alice"))
+		assert.Nil(t, th.Verify("alice", pass))
+		assert.Equal(t, 2, th.Remaining("alice"))
+		assert.Zero(t, th.Len())
+	})
+
+	t.Run("padded failure paths", func(t *testing.T) {
+		th, clock := newThrottler(1, time.Minute)
+		th.SetDelay(time.Second)
+		assert.Equal(t, VerifyError{}, th.Verify("alice", func() bool {
+			clock.advance(300 * time.Millisecond)
+			return false
+		}))
+		assert.Equal(t, 700*time.Millisecond, clock.slept)
+		assert.IsType(t, ThrottledError{}, th.Verify("alice", fail))
+		assert.Equal(t, 1700*time.Millisecond, clock.slept)
+		assert.Nil(t, th.Verify("bob", pass))
+		assert.Equal(t, 1700*time.Millisecond, clock.slept)
+	})
+
+	t.Run("concurrent attempts", func(t *testing.T) {
+		th := NewThrottler(5, time.Hour)
+		var calls atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				th.Verify("alice", func() bool { calls.Add(1); return false })
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(5), calls.Load())
+	})
+}
+
+func TestThrottler_Prune(t *testing.T) {
+	th, clock := newThrottler(1, time.Minute)
+	for i := 0; i < minPrune; i++ {
+		th.Verify(strconv.Itoa(i), func() bool { return false })
+	}
+	assert.Equal(t, minPrune, th.Len())
+	clock.advance(time.Minute)
+	th.Verify("fresh", func() bool { return false })
+	assert.Equal(t, 1, th.Len())
+}
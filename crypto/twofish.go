@@ -13,6 +13,13 @@ func (e Encrypter) ByTwofish(c *cipher.TwofishCipher) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "Twofish", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("Twofish", string(c.Block)); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -34,6 +41,13 @@ func (d Decrypter) ByTwofish(c *cipher.TwofishCipher) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "Twofish", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("Twofish", string(c.Block)); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -44,7 +58,7 @@ func (d Decrypter) ByTwofish(c *cipher.TwofishCipher) Decrypter {
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = twofish.NewStdDecrypter(c).Decrypt(d.src)
+		d.dst, d.Error = d.limit(twofish.NewStdDecrypter(c).Decrypt(d.src))
 	}
 	return d
 }
@@ -2,6 +2,8 @@ package twofish
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the Twofish key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/twofish: invalid key size %d, must be 16, 24, or 32 bytes", k)
 }
 
+// Code returns the stable error code DGL-TWOFISH-001.
+func (k KeySizeError) Code() string {
+	return "DGL-TWOFISH-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/twofish", "Twofish", "key", errcode.FieldKeySize, int(k))
+}
+
 // EncryptError represents an error when Twofish encryption operation fails.
 // This error occurs when the encryption process fails due to various reasons.
 type EncryptError struct {
@@ -25,6 +37,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/twofish: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TWOFISH-002.
+func (e EncryptError) Code() string {
+	return "DGL-TWOFISH-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/twofish", "Twofish", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when Twofish decryption operation fails.
 // This error occurs when the decryption process fails due to various reasons.
 // The error includes the underlying error for detailed debugging.
@@ -38,6 +60,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/twofish: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TWOFISH-003.
+func (e DecryptError) Code() string {
+	return "DGL-TWOFISH-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/twofish", "Twofish", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -51,6 +83,16 @@ func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/twofish: failed to read encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-TWOFISH-004.
+func (e ReadError) Code() string {
+	return "DGL-TWOFISH-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/twofish", "Twofish", "read", errcode.FieldCause, e.Err)
+}
+
 // BufferError represents an error when the buffer size is too small.
 // This error occurs when the provided buffer is too small to hold the decrypted data.
 // The error includes both buffer size and data size for detailed debugging.
@@ -64,3 +106,13 @@ type BufferError struct {
 func (e BufferError) Error() string {
 	return fmt.Sprintf("crypto/twofish: buffer size %d is too small for data size %d", e.bufferSize, e.dataSize)
 }
+
+// Code returns the stable error code DGL-TWOFISH-005.
+func (e BufferError) Code() string {
+	return "DGL-TWOFISH-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BufferError) Fields() map[string]any {
+	return errcode.NewFields("crypto/twofish", "Twofish", "", "buffer_size", e.bufferSize, "data_size", e.dataSize)
+}
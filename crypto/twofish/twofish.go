@@ -5,6 +5,7 @@ package twofish
 
 import (
 	stdCipher "crypto/cipher"
+	"errors"
 	"io"
 
 	"github.com/dromara/dongle/crypto/cipher"
@@ -48,11 +49,33 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Prefix the commitment to the configured key
+	if e.cipher.KeyCommitment {
+		if dst, err = e.encrypt(src); err != nil {
+			return
+		}
+		return e.cipher.CommitKey(dst)
+	}
+	return e.encrypt(src)
+}
+
+// encrypt encrypts non-empty src as configured, apart from the key
+// commitment.
+func (e *StdEncrypter) encrypt(src []byte) (dst []byte, err error) {
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, newBlock)
+	}
+
 	block, err := twofish.NewCipher(e.cipher.Key)
 	if err != nil {
 		err = EncryptError{Err: err}
 		return
 	}
+	// Derive the nonce from the plaintext and AAD
+	if e.cipher.DeterministicNonce {
+		return e.cipher.EncryptWithDeterministicNonce(src, block)
+	}
 	return e.cipher.Encrypt(src, block)
 }
 
@@ -93,11 +116,27 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Check the commitment to the configured key
+	if d.cipher.KeyCommitment {
+		if src, err = d.cipher.OpenKeyCommitment(src); err != nil {
+			return
+		}
+	}
+
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, newBlock)
+	}
+
 	block, err := twofish.NewCipher(d.cipher.Key)
 	if err != nil {
 		err = DecryptError{Err: err}
 		return
 	}
+	// Derive the nonce from the plaintext and AAD
+	if d.cipher.DeterministicNonce {
+		return d.cipher.DecryptWithDeterministicNonce(src, block)
+	}
 	return d.cipher.Decrypt(src, block)
 }
 
@@ -127,6 +166,10 @@ func NewStreamEncrypter(w io.Writer, c *cipher.TwofishCipher) io.WriteCloser {
 		return e
 	}
 
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
+
 	e.block, e.Error = twofish.NewCipher(c.Key)
 	return e
 }
@@ -196,12 +239,16 @@ type StreamDecrypter struct {
 	buffer   []byte                // Buffer for decrypted data
 	position int                   // Current position in the buffer
 	block    stdCipher.Block       // Reused cipher block for better performance
-	Error    error                 // Error field for storing decryption errors
+	// GCM stream released before verification, see cipher.SetReleasePolicy
+	unverified *cipher.UnverifiedReader
+	Error      error // Error field for storing decryption errors
 }
 
 // NewStreamDecrypter creates a new streaming Twofish decrypter that reads encrypted data
 // from the provided io.Reader. The decrypter uses the specified cipher interface
-// and validates the key length for proper Twofish decryption.
+// and validates the key length for proper Twofish decryption. GCM ciphers return
+// plaintext before the tag is verified only under the ReleaseUnverified policy,
+// see SetReleasePolicy.
 func NewStreamDecrypter(r io.Reader, c *cipher.TwofishCipher) io.Reader {
 	d := &StreamDecrypter{
 		reader:   r,
@@ -215,7 +262,14 @@ func NewStreamDecrypter(r io.Reader, c *cipher.TwofishCipher) io.Reader {
 		return d
 	}
 
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+
 	d.block, d.Error = twofish.NewCipher(d.cipher.Key)
+	if d.Error == nil {
+		d.unverified, d.Error = c.NewUnverifiedReader(r, d.block)
+	}
 	return d
 }
 
@@ -228,6 +282,18 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 		return 0, d.Error
 	}
 
+	if d.unverified != nil {
+		n, err = d.unverified.Read(p)
+		var authErr cipher.StreamAuthError
+		switch {
+		case errors.As(err, &authErr):
+			err = DecryptError{Err: err}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
 	// If we haven't decrypted the data yet, do it now
 	if d.buffer == nil {
 		// Read all encrypted data from the underlying reader
@@ -272,3 +338,8 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 
 	return copied, nil
 }
+
+// newBlock creates a Twofish cipher block from key.
+func newBlock(key []byte) (stdCipher.Block, error) {
+	return twofish.NewCipher(key)
+}
@@ -6,6 +6,10 @@ import (
 	"io/fs"
 
 	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/jcs"
+	"github.com/dromara/dongle/coding/pem"
+	"github.com/dromara/dongle/coding/text"
 	"github.com/dromara/dongle/internal/utils"
 )
 
@@ -15,6 +19,7 @@ type Verifier struct {
 	sign   []byte
 	verify bool
 	reader io.Reader
+	text   text.Option
 	Error  error
 }
 
@@ -25,22 +30,78 @@ func NewVerifier() Verifier {
 
 // FromString verifies from string.
 func (v Verifier) FromString(s string) Verifier {
-	v.data = utils.String2Bytes(s)
+	utils.AuditShared()
+	v.data = text.Canonicalize(utils.String2Bytes(s), v.text)
 	return v
 }
 
 // FromBytes verifies from byte slice.
 func (v Verifier) FromBytes(b []byte) Verifier {
-	v.data = b
+	utils.AuditShared()
+	v.data = text.Canonicalize(b, v.text)
 	return v
 }
 
 // FromFile verifies from file.
 func (v Verifier) FromFile(f fs.File) Verifier {
-	v.reader = f
+	utils.AuditShared()
+	v.reader = text.NewReader(f, v.text)
 	return v
 }
 
+// FromFS verifies from the named file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (v Verifier) FromFS(fsys fs.FS, name string) Verifier {
+	utils.AuditShared()
+	if v.Error != nil {
+		return v
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		v.Error = err
+		return v
+	}
+	return v.FromFile(f)
+}
+
+// FromJson verifies the RFC 8785 canonical JSON encoding of doc, so that
+// documents serialized with different whitespace or member order give the same
+// result. Raw JSON can be passed as json.RawMessage.
+func (v Verifier) FromJson(doc any) Verifier {
+	utils.AuditShared()
+	data, err := jcs.Marshal(doc)
+	if err != nil {
+		v.Error = err
+		return v
+	}
+	v.data = data
+	return v
+}
+
+// WithCanonicalText canonicalizes string, byte slice and file input as text
+// before verifying, so that a text file checked out on another platform still
+// verifies. It applies to input given before and after the call, and adds to
+// the options of earlier calls.
+func (v Verifier) WithCanonicalText(opts text.Option) Verifier {
+	if v.data != nil {
+		v.data = text.Canonicalize(v.data, opts)
+	}
+	if v.reader != nil {
+		v.reader = text.NewReader(v.reader, opts)
+	}
+	v.text |= opts
+	return v
+}
+
+// WithUnicodeNormalization normalizes string, byte slice and file input to
+// text.NFC or text.NFKC before verifying, so that user entered text such as a
+// password or a name verifies on every platform, whichever normalization form
+// the keyboard produced. Other options in form are ignored.
+func (v Verifier) WithUnicodeNormalization(form text.Option) Verifier {
+	return v.WithCanonicalText(form & text.Forms)
+}
+
 // WithHexSign verifies with hex sign.
 func (v Verifier) WithHexSign(s []byte) Verifier {
 	decode := coding.NewDecoder().FromBytes(s).ByHex()
@@ -52,6 +113,18 @@ func (v Verifier) WithHexSign(s []byte) Verifier {
 	return v
 }
 
+// WithHexFormatSign verifies with hex sign typed or pasted by users, ignoring
+// case, whitespace and group separators such as the output of ToHexFormat.
+func (v Verifier) WithHexFormatSign(s []byte) Verifier {
+	sign, err := hex.ParseLenient(string(s))
+	if err != nil {
+		v.Error = err
+		return v
+	}
+	v.sign = sign
+	return v
+}
+
 // WithBase64Sign verifies with base64 sign.
 func (v Verifier) WithBase64Sign(s []byte) Verifier {
 	decode := coding.NewDecoder().FromBytes(s).ByBase64()
@@ -63,6 +136,17 @@ func (v Verifier) WithBase64Sign(s []byte) Verifier {
 	return v
 }
 
+// WithPemSign verifies with the first PEM block in sign, whatever its type.
+func (v Verifier) WithPemSign(s []byte) Verifier {
+	block, _, err := pem.Decode(s)
+	if err != nil {
+		v.Error = err
+		return v
+	}
+	v.sign = block.Bytes
+	return v
+}
+
 // WithRawSign verifies with raw sign.
 func (v Verifier) WithRawSign(s []byte) Verifier {
 	v.sign = s
@@ -78,6 +162,7 @@ func (v Verifier) ToBool() bool {
 }
 
 func (v Verifier) stream(fn func(io.Writer) io.WriteCloser) ([]byte, error) {
+	defer utils.CloseFS(v.reader)
 	var buf bytes.Buffer
 	verifier := fn(&buf)
 
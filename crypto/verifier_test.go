@@ -440,3 +440,31 @@ func TestVerifier_WithRawSign(t *testing.T) {
 		assert.Nil(t, verifier.Error)
 	})
 }
+
+func TestVerifier_WithPemSign(t *testing.T) {
+	t.Run("with pem sign", func(t *testing.T) {
+		verifier := NewVerifier().WithPemSign([]byte("-----BEGIN DONGLE SIGNATURE-----\naGVsbG8gd29ybGQ=\n-----END DONGLE SIGNATURE-----\n"))
+		assert.Nil(t, verifier.Error)
+		assert.Equal(t, []byte("hello world"), verifier.sign)
+	})
+
+	t.Run("with invalid pem sign", func(t *testing.T) {
+		verifier := NewVerifier().WithPemSign([]byte("aGVsbG8gd29ybGQ="))
+		assert.Error(t, verifier.Error)
+		assert.Nil(t, verifier.sign)
+	})
+}
+
+func TestVerifier_FromJson(t *testing.T) {
+	t.Run("from map", func(t *testing.T) {
+		verifier := NewVerifier().FromJson(map[string]int{"b": 2, "a": 1})
+		assert.Nil(t, verifier.Error)
+		assert.Equal(t, []byte(`{"a":1,"b":2}`), verifier.data)
+	})
+
+	t.Run("from unsupported value", func(t *testing.T) {
+		verifier := NewVerifier().FromJson(make(chan int))
+		assert.Error(t, verifier.Error)
+		assert.False(t, verifier.ToBool())
+	})
+}
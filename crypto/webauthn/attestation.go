@@ -0,0 +1,180 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+)
+
+// AttestationType is the kind of attestation a verified statement provides.
+type AttestationType string
+
+// The attestation types produced by the supported formats.
+const (
+	AttestationNone  AttestationType = "none"  // No attestation
+	AttestationSelf  AttestationType = "self"  // Signed by the credential key itself
+	AttestationBasic AttestationType = "basic" // Signed by an attestation certificate, basic or attestation CA
+)
+
+// oidAAGUID is the id-fido-gen-ce-aaguid certificate extension.
+var oidAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+// AttestationObject is a parsed attestation object returned by navigator.credentials.create.
+type AttestationObject struct {
+	Format      string             // Attestation statement format, "packed" or "none" are supported
+	AuthData    *AuthenticatorData // Parsed authenticator data, always with a credential
+	RawAuthData []byte             // Authenticator data as signed
+	Statement   map[any]any        // Attestation statement
+}
+
+// ParseAttestationObject parses a CBOR attestation object. The authenticator data
+// must contain attested credential data.
+func ParseAttestationObject(data []byte) (*AttestationObject, error) {
+	v, err := cbor.Unmarshal(data)
+	if err != nil {
+		return nil, InvalidAttestationError{Err: err}
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil, InvalidAttestationError{Err: errors.New("attestation object must be a map")}
+	}
+	o := &AttestationObject{}
+	format, ok1 := m["fmt"].(string)
+	stmt, ok2 := m["attStmt"].(map[any]any)
+	raw, ok3 := m["authData"].([]byte)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, InvalidAttestationError{Err: errors.New("missing fmt, attStmt or authData")}
+	}
+	o.Format, o.Statement, o.RawAuthData = format, stmt, raw
+	if o.AuthData, err = ParseAuthenticatorData(raw); err != nil {
+		return nil, err
+	}
+	if o.AuthData.Credential == nil {
+		return nil, MissingFlagError{Flag: FlagAttestedCredential}
+	}
+	return o, nil
+}
+
+// Verify verifies the attestation statement over the authenticator data and the
+// SHA-256 hash of the client data JSON. For certificate based attestation the
+// certificates are returned leaf first. When roots is not nil the attestation
+// must be trusted: the chain must verify up to one of the roots, and none and
+// self attestation fail with an UntrustedAttestationError. Relying parties that
+// accept any authenticator may pass nil roots and ignore the certificates.
+func (o *AttestationObject) Verify(clientDataHash []byte, roots *x509.CertPool) (typ AttestationType, certs []*x509.Certificate, err error) {
+	switch o.Format {
+	case "none":
+		if len(o.Statement) != 0 {
+			return "", nil, InvalidAttestationError{Err: errors.New("none attestation statement must be empty")}
+		}
+		typ = AttestationNone
+	case "packed":
+		if typ, certs, err = o.verifyPacked(clientDataHash, roots); err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, UnsupportedFormatError{Format: o.Format}
+	}
+	if roots != nil && (typ == AttestationNone || typ == AttestationSelf) {
+		return "", nil, UntrustedAttestationError{Type: typ}
+	}
+	return typ, certs, nil
+}
+
+// verifyPacked verifies a packed attestation statement.
+func (o *AttestationObject) verifyPacked(clientDataHash []byte, roots *x509.CertPool) (AttestationType, []*x509.Certificate, error) {
+	n, ok1 := o.Statement["alg"].(int64)
+	sig, ok2 := o.Statement["sig"].([]byte)
+	if !ok1 || !ok2 {
+		return "", nil, InvalidAttestationError{Err: errors.New("packed statement needs alg and sig")}
+	}
+	if _, ok := o.Statement["ecdaaKeyId"]; ok {
+		return "", nil, InvalidAttestationError{Err: errors.New("ECDAA attestation is not supported")}
+	}
+	alg := Algorithm(n)
+	signed := append(append([]byte(nil), o.RawAuthData...), clientDataHash...)
+	x5c, ok := o.Statement["x5c"]
+	if !ok {
+		c := o.AuthData.Credential
+		if alg != c.Algorithm {
+			return "", nil, InvalidAttestationError{Err: errors.New("self attestation algorithm differs from the credential algorithm")}
+		}
+		if err := VerifySignature(c.PublicKey, alg, signed, sig); err != nil {
+			return "", nil, err
+		}
+		return AttestationSelf, nil, nil
+	}
+	certs, err := parseChain(x5c)
+	if err != nil {
+		return "", nil, err
+	}
+	if err = VerifySignature(certs[0].PublicKey, alg, signed, sig); err != nil {
+		return "", nil, err
+	}
+	if err = checkPackedCertificate(certs[0], o.AuthData.Credential.AAGUID); err != nil {
+		return "", nil, err
+	}
+	if roots != nil {
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool(), KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		if _, err = certs[0].Verify(opts); err != nil {
+			return "", nil, CertificateError{Err: err}
+		}
+	}
+	return AttestationBasic, certs, nil
+}
+
+// parseChain parses a non-empty x5c array of DER certificates.
+func parseChain(x5c any) ([]*x509.Certificate, error) {
+	items, _ := x5c.([]any)
+	if len(items) == 0 {
+		return nil, InvalidAttestationError{Err: errors.New("x5c must be a non-empty array")}
+	}
+	certs := make([]*x509.Certificate, len(items))
+	for i, item := range items {
+		der, ok := item.([]byte)
+		if !ok {
+			return nil, InvalidAttestationError{Err: errors.New("x5c entries must be byte strings")}
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, CertificateError{Err: err}
+		}
+		certs[i] = cert
+	}
+	return certs, nil
+}
+
+// checkPackedCertificate checks the packed attestation certificate requirements
+// of WebAuthn section 8.2.1.
+func checkPackedCertificate(cert *x509.Certificate, aaguid [16]byte) error {
+	subject := cert.Subject
+	switch {
+	case cert.Version != 3:
+		return CertificateError{Err: errors.New("version must be 3")}
+	case len(subject.Country) == 0 || len(subject.Organization) == 0 || subject.CommonName == "":
+		return CertificateError{Err: errors.New("subject must name a country, organization and common name")}
+	case len(subject.OrganizationalUnit) != 1 || subject.OrganizationalUnit[0] != "Authenticator Attestation":
+		return CertificateError{Err: errors.New(`subject organizational unit must be "Authenticator Attestation"`)}
+	case !cert.BasicConstraintsValid || cert.IsCA:
+		return CertificateError{Err: errors.New("basic constraints must mark the certificate as not a CA")}
+	}
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidAAGUID) {
+			continue
+		}
+		var value []byte
+		if rest, err := asn1.Unmarshal(ext.Value, &value); err != nil || len(rest) > 0 || ext.Critical {
+			return CertificateError{Err: errors.New("malformed AAGUID extension")}
+		}
+		if !bytes.Equal(value, aaguid[:]) {
+			return MismatchError{Field: "aaguid"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package webauthn
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+)
+
+// Authenticator data flag bits.
+const (
+	FlagUserPresent        byte = 0x01 // UP, the user was present
+	FlagUserVerified       byte = 0x04 // UV, the user was verified
+	FlagBackupEligible     byte = 0x08 // BE, the credential may be backed up
+	FlagBackupState        byte = 0x10 // BS, the credential is backed up
+	FlagAttestedCredential byte = 0x40 // AT, attested credential data follows
+	FlagExtensions         byte = 0x80 // ED, extension outputs follow
+)
+
+// AuthenticatorData is the parsed authenticator data of a registration or assertion.
+type AuthenticatorData struct {
+	RPIDHash   [32]byte    // SHA-256 of the relying party identifier
+	Flags      byte        // Flag bits, see the Flag constants
+	SignCount  uint32      // Signature counter, zero when the authenticator has none
+	Credential *Credential // Attested credential data, present during registration
+	Extensions map[any]any // Authenticator extension outputs
+}
+
+// Credential is the attested credential data of a newly registered credential.
+type Credential struct {
+	AAGUID    [16]byte         // Authenticator model identifier
+	ID        []byte           // Credential identifier
+	PublicKey crypto.PublicKey // Parsed credential public key
+	Algorithm Algorithm        // Algorithm of the credential public key
+	RawKey    []byte           // COSE_Key encoding, store it to verify later assertions
+}
+
+// Has reports whether all bits of flag are set.
+func (a *AuthenticatorData) Has(flag byte) bool {
+	return a.Flags&flag == flag
+}
+
+// ParseAuthenticatorData parses the authenticator data byte array.
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, InvalidAuthenticatorDataError{Err: errors.New("too short")}
+	}
+	a := &AuthenticatorData{Flags: data[32], SignCount: binary.BigEndian.Uint32(data[33:37])}
+	copy(a.RPIDHash[:], data)
+	rest := data[37:]
+	if a.Has(FlagAttestedCredential) {
+		if len(rest) < 18 {
+			return nil, InvalidAuthenticatorDataError{Err: errors.New("truncated attested credential data")}
+		}
+		c := &Credential{}
+		copy(c.AAGUID[:], rest)
+		n := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if n == 0 || n > 1023 || len(rest) < n {
+			return nil, InvalidAuthenticatorDataError{Err: errors.New("invalid credential ID length")}
+		}
+		c.ID, rest = append([]byte(nil), rest[:n]...), rest[n:]
+		v, after, err := cbor.Decode(rest)
+		if err != nil {
+			return nil, InvalidAuthenticatorDataError{Err: err}
+		}
+		c.RawKey = append([]byte(nil), rest[:len(rest)-len(after)]...)
+		if c.PublicKey, c.Algorithm, err = parsePublicKey(v); err != nil {
+			return nil, err
+		}
+		a.Credential, rest = c, after
+	}
+	if a.Has(FlagExtensions) {
+		v, after, err := cbor.Decode(rest)
+		if err != nil {
+			return nil, InvalidAuthenticatorDataError{Err: err}
+		}
+		var ok bool
+		if a.Extensions, ok = v.(map[any]any); !ok {
+			return nil, InvalidAuthenticatorDataError{Err: errors.New("extensions must be a map")}
+		}
+		rest = after
+	}
+	if len(rest) > 0 {
+		return nil, InvalidAuthenticatorDataError{Err: errors.New("trailing data")}
+	}
+	return a, nil
+}
@@ -0,0 +1,257 @@
+package webauthn
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidAuthenticatorDataError represents an error when authenticator data cannot be parsed.
+type InvalidAuthenticatorDataError struct {
+	Err error // Underlying error from parsing
+}
+
+// Error returns a formatted error message describing the invalid authenticator data.
+func (e InvalidAuthenticatorDataError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: invalid authenticator data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-001.
+func (e InvalidAuthenticatorDataError) Code() string {
+	return "DGL-WEBAUTHN-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidAuthenticatorDataError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", errcode.FieldCause, e.Err)
+}
+
+// InvalidAttestationError represents an error when an attestation object or statement is malformed.
+type InvalidAttestationError struct {
+	Err error // Underlying error from parsing or structure checks
+}
+
+// Error returns a formatted error message describing the invalid attestation.
+func (e InvalidAttestationError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: invalid attestation: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-002.
+func (e InvalidAttestationError) Code() string {
+	return "DGL-WEBAUTHN-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidAttestationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedFormatError represents an error when an attestation statement format is not supported.
+type UnsupportedFormatError struct {
+	Format string // The attestation statement format identifier
+}
+
+// Error returns a formatted error message describing the unsupported format.
+func (e UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: unsupported attestation format %q", e.Format)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-003.
+func (e UnsupportedFormatError) Code() string {
+	return "DGL-WEBAUTHN-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedFormatError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", "format", e.Format)
+}
+
+// InvalidPublicKeyError represents an error when a COSE public key cannot be parsed.
+type InvalidPublicKeyError struct {
+	Err error // Underlying error from parsing
+}
+
+// Error returns a formatted error message describing the invalid public key.
+func (e InvalidPublicKeyError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: invalid public key: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-004.
+func (e InvalidPublicKeyError) Code() string {
+	return "DGL-WEBAUTHN-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidPublicKeyError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "key", errcode.FieldCause, e.Err)
+}
+
+// UnsupportedAlgorithmError represents an error when a COSE algorithm is not supported.
+type UnsupportedAlgorithmError struct {
+	Algorithm Algorithm // The unsupported algorithm identifier
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: unsupported algorithm %s", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-005.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-WEBAUTHN-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// InvalidClientDataError represents an error when client data JSON cannot be parsed.
+type InvalidClientDataError struct {
+	Err error // Underlying error from parsing
+}
+
+// Error returns a formatted error message describing the invalid client data.
+func (e InvalidClientDataError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: invalid client data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-006.
+func (e InvalidClientDataError) Code() string {
+	return "DGL-WEBAUTHN-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidClientDataError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", errcode.FieldCause, e.Err)
+}
+
+// MismatchError represents an error when a ceremony value differs from the expected one.
+type MismatchError struct {
+	Field string // The mismatching field, such as "type", "challenge", "origin" or "rpIdHash"
+}
+
+// Error returns a formatted error message describing the mismatching field.
+func (e MismatchError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: %s mismatch", e.Field)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-007.
+func (e MismatchError) Code() string {
+	return "DGL-WEBAUTHN-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MismatchError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", "field", e.Field)
+}
+
+// MissingFlagError represents an error when a required authenticator data flag is not set.
+type MissingFlagError struct {
+	Flag byte // The required flag bit
+}
+
+// Error returns a formatted error message describing the missing flag.
+func (e MissingFlagError) Error() string {
+	switch e.Flag {
+	case FlagUserPresent:
+		return "crypto/webauthn: user presence flag not set"
+	case FlagUserVerified:
+		return "crypto/webauthn: user verification flag not set"
+	case FlagAttestedCredential:
+		return "crypto/webauthn: attested credential data flag not set"
+	}
+	return fmt.Sprintf("crypto/webauthn: flag %#02x not set", e.Flag)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-008.
+func (e MissingFlagError) Code() string {
+	return "DGL-WEBAUTHN-008"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MissingFlagError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", "flag", e.Flag)
+}
+
+// CounterError represents an error when a signature counter did not increase,
+// which indicates a cloned authenticator.
+type CounterError struct {
+	Stored   uint32 // The counter stored by the relying party
+	Received uint32 // The counter reported by the authenticator
+}
+
+// Error returns a formatted error message describing the counter regression.
+func (e CounterError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: signature counter %d not greater than stored counter %d", e.Received, e.Stored)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-009.
+func (e CounterError) Code() string {
+	return "DGL-WEBAUTHN-009"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CounterError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", "stored", e.Stored, "received", e.Received)
+}
+
+// CertificateError represents an error when an attestation certificate is unacceptable.
+type CertificateError struct {
+	Err error // Underlying error from parsing, requirement checks or chain verification
+}
+
+// Error returns a formatted error message describing the certificate error.
+func (e CertificateError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: invalid attestation certificate: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-010.
+func (e CertificateError) Code() string {
+	return "DGL-WEBAUTHN-010"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CertificateError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "", errcode.FieldCause, e.Err)
+}
+
+// SignatureVerificationError represents an error when an attestation or assertion signature does not verify.
+type SignatureVerificationError struct{}
+
+// Error returns a formatted error message describing the verification failure.
+func (e SignatureVerificationError) Error() string {
+	return "crypto/webauthn: signature verification failed"
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-011.
+func (e SignatureVerificationError) Code() string {
+	return "DGL-WEBAUTHN-011"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SignatureVerificationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "verify")
+}
+
+// UntrustedAttestationError represents an error when trusted attestation is
+// required but the statement carries no certificate chain, as with none and
+// self attestation.
+type UntrustedAttestationError struct {
+	Type AttestationType // Kind of attestation provided
+}
+
+// Error returns a formatted error message describing the untrusted attestation.
+func (e UntrustedAttestationError) Error() string {
+	return fmt.Sprintf("crypto/webauthn: %s attestation cannot be verified against the attestation roots", e.Type)
+}
+
+// Code returns the stable error code DGL-WEBAUTHN-012.
+func (e UntrustedAttestationError) Code() string {
+	return "DGL-WEBAUTHN-012"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UntrustedAttestationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/webauthn", "WebAuthn", "verify", "type", string(e.Type))
+}
@@ -0,0 +1,215 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+)
+
+// Algorithm is a COSE algorithm identifier as used in credential public keys and
+// attestation statements.
+type Algorithm int64
+
+// The supported algorithms.
+const (
+	ES256 Algorithm = -7   // ECDSA with P-256 and SHA-256
+	ES384 Algorithm = -35  // ECDSA with P-384 and SHA-384
+	ES512 Algorithm = -36  // ECDSA with P-521 and SHA-512
+	EdDSA Algorithm = -8   // Ed25519
+	PS256 Algorithm = -37  // RSASSA-PSS with SHA-256
+	PS384 Algorithm = -38  // RSASSA-PSS with SHA-384
+	PS512 Algorithm = -39  // RSASSA-PSS with SHA-512
+	RS256 Algorithm = -257 // RSASSA-PKCS1-v1_5 with SHA-256
+	RS384 Algorithm = -258 // RSASSA-PKCS1-v1_5 with SHA-384
+	RS512 Algorithm = -259 // RSASSA-PKCS1-v1_5 with SHA-512
+)
+
+// MinRSAKeySize is the smallest accepted RSA modulus in bits.
+const MinRSAKeySize = 2048
+
+// COSE_Key labels and values of RFC 9053.
+const (
+	keyType  int64 = 1
+	keyAlg   int64 = 3
+	keyCurve int64 = -1 // Also the RSA modulus
+	keyX     int64 = -2 // Also the RSA public exponent
+	keyY     int64 = -3
+
+	ktyOKP int64 = 1
+	ktyEC2 int64 = 2
+	ktyRSA int64 = 3
+
+	crvP256    int64 = 1
+	crvP384    int64 = 2
+	crvP521    int64 = 3
+	crvEd25519 int64 = 6
+)
+
+// String returns the registered name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case ES256:
+		return "ES256"
+	case ES384:
+		return "ES384"
+	case ES512:
+		return "ES512"
+	case EdDSA:
+		return "EdDSA"
+	case PS256:
+		return "PS256"
+	case PS384:
+		return "PS384"
+	case PS512:
+		return "PS512"
+	case RS256:
+		return "RS256"
+	case RS384:
+		return "RS384"
+	case RS512:
+		return "RS512"
+	}
+	return strconv.FormatInt(int64(a), 10)
+}
+
+// hash returns the digest algorithm of a, zero for EdDSA and unknown algorithms.
+func (a Algorithm) hash() crypto.Hash {
+	switch a {
+	case ES256, PS256, RS256:
+		return crypto.SHA256
+	case ES384, PS384, RS384:
+		return crypto.SHA384
+	case ES512, PS512, RS512:
+		return crypto.SHA512
+	}
+	return 0
+}
+
+// curves maps each ECDSA algorithm to its curves and COSE curve identifier.
+var curves = map[Algorithm]struct {
+	crv   int64
+	curve elliptic.Curve
+	ecdh  ecdh.Curve
+}{
+	ES256: {crvP256, elliptic.P256(), ecdh.P256()},
+	ES384: {crvP384, elliptic.P384(), ecdh.P384()},
+	ES512: {crvP521, elliptic.P521(), ecdh.P521()},
+}
+
+// ParsePublicKey decodes a COSE_Key credential public key, as stored by a relying
+// party after registration, and returns the key with its algorithm. EC2 keys yield
+// *ecdsa.PublicKey, OKP keys ed25519.PublicKey and RSA keys *rsa.PublicKey.
+func ParsePublicKey(data []byte) (crypto.PublicKey, Algorithm, error) {
+	v, err := cbor.Unmarshal(data)
+	if err != nil {
+		return nil, 0, InvalidPublicKeyError{Err: err}
+	}
+	return parsePublicKey(v)
+}
+
+// parsePublicKey converts a decoded COSE_Key to a public key.
+func parsePublicKey(v any) (crypto.PublicKey, Algorithm, error) {
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil, 0, InvalidPublicKeyError{Err: errors.New("key must be a map")}
+	}
+	kty, _ := m[keyType].(int64)
+	n, ok := m[keyAlg].(int64)
+	if !ok {
+		return nil, 0, InvalidPublicKeyError{Err: errors.New("missing algorithm")}
+	}
+	alg := Algorithm(n)
+	param := func(label int64) []byte {
+		b, _ := m[label].([]byte)
+		return b
+	}
+	switch alg {
+	case ES256, ES384, ES512:
+		c := curves[alg]
+		if kty != ktyEC2 || m[keyCurve] != c.crv {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("key type or curve does not match algorithm")}
+		}
+		x, y := param(keyX), param(keyY)
+		size := (c.curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("invalid coordinate length")}
+		}
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := c.ecdh.NewPublicKey(point); err != nil {
+			return nil, 0, InvalidPublicKeyError{Err: err}
+		}
+		return &ecdsa.PublicKey{Curve: c.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, alg, nil
+	case EdDSA:
+		if kty != ktyOKP || m[keyCurve] != crvEd25519 {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("key type or curve does not match algorithm")}
+		}
+		x := param(keyX)
+		if len(x) != ed25519.PublicKeySize {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("invalid Ed25519 key length")}
+		}
+		return ed25519.PublicKey(x), alg, nil
+	case PS256, PS384, PS512, RS256, RS384, RS512:
+		if kty != ktyRSA {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("key type does not match algorithm")}
+		}
+		modulus, exponent := param(keyCurve), param(keyX)
+		if len(modulus) == 0 || modulus[0] == 0 || len(exponent) == 0 || len(exponent) > 4 {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("invalid RSA parameters")}
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(new(big.Int).SetBytes(exponent).Int64())}
+		if key.N.BitLen() < MinRSAKeySize || key.E < 3 || key.E&1 == 0 {
+			return nil, 0, InvalidPublicKeyError{Err: errors.New("RSA key is too small or has an invalid exponent")}
+		}
+		return key, alg, nil
+	}
+	return nil, 0, UnsupportedAlgorithmError{Algorithm: alg}
+}
+
+// VerifySignature verifies a WebAuthn signature over data. ECDSA signatures are
+// ASN.1 DER encoded as produced by authenticators. The key type must match alg.
+func VerifySignature(key crypto.PublicKey, alg Algorithm, data, sig []byte) error {
+	h := alg.hash()
+	var digest []byte
+	if h != 0 {
+		w := h.New()
+		w.Write(data)
+		digest = w.Sum(nil)
+	}
+	var ok bool
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		c, supported := curves[alg]
+		if !supported || k.Curve != c.curve {
+			return UnsupportedAlgorithmError{Algorithm: alg}
+		}
+		ok = ecdsa.VerifyASN1(k, digest, sig)
+	case ed25519.PublicKey:
+		if alg != EdDSA {
+			return UnsupportedAlgorithmError{Algorithm: alg}
+		}
+		ok = len(k) == ed25519.PublicKeySize && ed25519.Verify(k, data, sig)
+	case *rsa.PublicKey:
+		switch alg {
+		case RS256, RS384, RS512:
+			ok = rsa.VerifyPKCS1v15(k, h, digest, sig) == nil
+		case PS256, PS384, PS512:
+			ok = rsa.VerifyPSS(k, h, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		default:
+			return UnsupportedAlgorithmError{Algorithm: alg}
+		}
+	default:
+		return InvalidPublicKeyError{Err: errors.New("unsupported key type")}
+	}
+	if !ok {
+		return SignatureVerificationError{}
+	}
+	return nil
+}
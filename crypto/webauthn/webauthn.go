@@ -0,0 +1,145 @@
+// Package webauthn implements the cryptographic checks of a WebAuthn relying
+// party: parsing authenticator data and COSE credential public keys, verifying
+// packed and none attestation statements during registration, and verifying
+// assertion signatures against stored credential public keys.
+//
+// Session handling, challenge generation and credential storage are left to the
+// caller. Challenges should be at least 16 random bytes and used only once.
+package webauthn
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Client data types of the two ceremonies.
+const (
+	TypeCreate = "webauthn.create"
+	TypeGet    = "webauthn.get"
+)
+
+// ClientData is the parsed client data JSON collected by the browser.
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"` // Base64url challenge without padding
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+	TopOrigin   string `json:"topOrigin,omitempty"`
+}
+
+// ParseClientData parses client data JSON.
+func ParseClientData(data []byte) (*ClientData, error) {
+	c := &ClientData{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, InvalidClientDataError{Err: err}
+	}
+	return c, nil
+}
+
+// Expectation holds the values a ceremony response is checked against.
+type Expectation struct {
+	RPID                    string         // Relying party identifier, usually the domain
+	Origins                 []string       // Accepted origins, such as "https://example.com"
+	Challenge               []byte         // Challenge sent to the client
+	RequireUserVerification bool           // Require the UV flag, not only UP
+	SignCount               uint32         // Stored signature counter, checked by assertions
+	Roots                   *x509.CertPool // Attestation roots requiring trusted attestation, nil also accepts none and self attestation
+}
+
+// Registration is the result of a verified registration ceremony.
+type Registration struct {
+	AuthData     *AuthenticatorData  // Parsed authenticator data
+	Credential   *Credential         // New credential, store ID, RawKey and AuthData.SignCount
+	Attestation  AttestationType     // Kind of attestation provided
+	Certificates []*x509.Certificate // Attestation certificates, leaf first
+}
+
+// VerifyRegistration verifies the response to navigator.credentials.create: the
+// client data, the authenticator data and the attestation statement.
+func VerifyRegistration(attestationObject, clientDataJSON []byte, exp Expectation) (*Registration, error) {
+	if err := exp.checkClientData(clientDataJSON, TypeCreate); err != nil {
+		return nil, err
+	}
+	o, err := ParseAttestationObject(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	if err = exp.checkAuthData(o.AuthData); err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(clientDataJSON)
+	typ, certs, err := o.Verify(hash[:], exp.Roots)
+	if err != nil {
+		return nil, err
+	}
+	return &Registration{AuthData: o.AuthData, Credential: o.AuthData.Credential, Attestation: typ, Certificates: certs}, nil
+}
+
+// VerifyAssertion verifies the response to navigator.credentials.get against the
+// COSE_Key public key stored at registration. The returned authenticator data
+// carries the new signature counter to store.
+func VerifyAssertion(publicKey, authenticatorData, clientDataJSON, signature []byte, exp Expectation) (*AuthenticatorData, error) {
+	if err := exp.checkClientData(clientDataJSON, TypeGet); err != nil {
+		return nil, err
+	}
+	key, alg, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	a, err := ParseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	if err = exp.checkAuthData(a); err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authenticatorData...), hash[:]...)
+	if err = VerifySignature(key, alg, signed, signature); err != nil {
+		return nil, err
+	}
+	if (a.SignCount != 0 || exp.SignCount != 0) && a.SignCount <= exp.SignCount {
+		return nil, CounterError{Stored: exp.SignCount, Received: a.SignCount}
+	}
+	return a, nil
+}
+
+// checkClientData checks the type, challenge and origin of client data JSON.
+func (exp Expectation) checkClientData(data []byte, typ string) error {
+	c, err := ParseClientData(data)
+	if err != nil {
+		return err
+	}
+	if c.Type != typ {
+		return MismatchError{Field: "type"}
+	}
+	challenge, err := base64.RawURLEncoding.DecodeString(c.Challenge)
+	if err != nil || len(exp.Challenge) == 0 || !utils.ConstantTimeEqual(challenge, exp.Challenge) {
+		return MismatchError{Field: "challenge"}
+	}
+	for _, origin := range exp.Origins {
+		if c.Origin == origin {
+			return nil
+		}
+	}
+	return MismatchError{Field: "origin"}
+}
+
+// checkAuthData checks the relying party identifier hash and the user flags.
+func (exp Expectation) checkAuthData(a *AuthenticatorData) error {
+	hash := sha256.Sum256([]byte(exp.RPID))
+	if !utils.ConstantTimeEqual(hash[:], a.RPIDHash[:]) {
+		return MismatchError{Field: "rpIdHash"}
+	}
+	if !a.Has(FlagUserPresent) {
+		return MissingFlagError{Flag: FlagUserPresent}
+	}
+	if exp.RequireUserVerification && !a.Has(FlagUserVerified) {
+		return MissingFlagError{Flag: FlagUserVerified}
+	}
+	return nil
+}
@@ -0,0 +1,433 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/crypto/internal/cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rpID   = "example.com"
+	origin = "https://example.com"
+)
+
+var (
+	challenge = []byte("0123456789abcdef")
+	aaguid    = [16]byte{0xf8, 0xa0, 0x11, 0xf3, 0x8c, 0x0a, 0x4d, 0x15, 0x80, 0x06, 0x17, 0x11, 0x1f, 0x9e, 0xdc, 0x7d}
+	expect    = Expectation{RPID: rpID, Origins: []string{origin}, Challenge: challenge}
+)
+
+// coseKey encodes a public key as a COSE_Key.
+func coseKey(t *testing.T, pub crypto.PublicKey, alg Algorithm) []byte {
+	var m map[any]any
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		m = map[any]any{keyType: ktyEC2, keyAlg: int64(alg), keyCurve: curves[alg].crv, keyX: k.X.FillBytes(make([]byte, size)), keyY: k.Y.FillBytes(make([]byte, size))}
+	case ed25519.PublicKey:
+		m = map[any]any{keyType: ktyOKP, keyAlg: int64(alg), keyCurve: crvEd25519, keyX: []byte(k)}
+	case *rsa.PublicKey:
+		m = map[any]any{keyType: ktyRSA, keyAlg: int64(alg), keyCurve: k.N.Bytes(), keyX: big.NewInt(int64(k.E)).Bytes()}
+	}
+	out, err := cbor.Marshal(m)
+	require.NoError(t, err)
+	return out
+}
+
+// authData builds authenticator data, with attested credential data when key is not nil.
+func authData(flags byte, count uint32, key []byte) []byte {
+	hash := sha256.Sum256([]byte(rpID))
+	out := append(hash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(out[33:], count)
+	if key != nil {
+		out = append(out, aaguid[:]...)
+		out = append(out, 0, 4, 'c', 'r', 'e', 'd')
+		out = append(out, key...)
+	}
+	return out
+}
+
+// clientData builds client data JSON.
+func clientData(typ string) []byte {
+	out, _ := json.Marshal(ClientData{Type: typ, Challenge: base64.RawURLEncoding.EncodeToString(challenge), Origin: origin})
+	return out
+}
+
+// sign signs data the way an authenticator does.
+func sign(t *testing.T, key crypto.Signer, alg Algorithm, data []byte) []byte {
+	var opts crypto.SignerOpts = crypto.Hash(0)
+	digest := data
+	if h := alg.hash(); h != 0 {
+		w := h.New()
+		w.Write(data)
+		digest, opts = w.Sum(nil), h
+		if alg == PS256 || alg == PS384 || alg == PS512 {
+			opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: h}
+		}
+	}
+	sig, err := key.Sign(rand.Reader, digest, opts)
+	require.NoError(t, err)
+	return sig
+}
+
+// attestationObject builds a packed attestation object, self attested when certs is empty.
+func attestationObject(t *testing.T, key crypto.Signer, alg Algorithm, auth, clientDataJSON []byte, certs ...[]byte) []byte {
+	hash := sha256.Sum256(clientDataJSON)
+	stmt := map[any]any{"alg": int64(alg), "sig": sign(t, key, alg, append(append([]byte(nil), auth...), hash[:]...))}
+	if len(certs) > 0 {
+		x5c := make([]any, len(certs))
+		for i, c := range certs {
+			x5c[i] = c
+		}
+		stmt["x5c"] = x5c
+	}
+	out, err := cbor.Marshal(map[any]any{"fmt": "packed", "attStmt": stmt, "authData": auth})
+	require.NoError(t, err)
+	return out
+}
+
+// attestationCA creates a root and an attestation certificate for key.
+func attestationCA(t *testing.T, key crypto.PublicKey, modify func(*x509.Certificate)) (*x509.CertPool, []byte) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Dongle Attestation Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, caKey.Public(), caKey)
+	require.NoError(t, err)
+	ca, _ = x509.ParseCertificate(caDER)
+
+	ext, _ := asn1.Marshal(aaguid[:])
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject: pkix.Name{
+			Country:            []string{"CN"},
+			Organization:       []string{"Dongle"},
+			OrganizationalUnit: []string{"Authenticator Attestation"},
+			CommonName:         "Dongle Authenticator",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		ExtraExtensions:       []pkix.Extension{{Id: oidAAGUID, Value: ext}},
+	}
+	if modify != nil {
+		modify(leaf)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, leaf, ca, key, caKey)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return roots, der
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, ed, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		alg Algorithm
+		key crypto.Signer
+	}{
+		{ES256, p256},
+		{ES384, p384},
+		{ES512, p521},
+		{EdDSA, ed},
+		{RS256, rsaKey},
+		{PS256, rsaKey},
+		{RS512, rsaKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg.String(), func(t *testing.T) {
+			rawKey := coseKey(t, tt.key.Public(), tt.alg)
+			created := clientData(TypeCreate)
+			auth := authData(FlagUserPresent|FlagUserVerified|FlagAttestedCredential, 0, rawKey)
+
+			reg, err := VerifyRegistration(attestationObject(t, tt.key, tt.alg, auth, created), created, expect)
+			require.NoError(t, err)
+			assert.Equal(t, AttestationSelf, reg.Attestation)
+			assert.Equal(t, []byte("cred"), reg.Credential.ID)
+			assert.Equal(t, aaguid, reg.Credential.AAGUID)
+			assert.Equal(t, tt.alg, reg.Credential.Algorithm)
+			assert.Equal(t, rawKey, reg.Credential.RawKey)
+			assert.True(t, reg.AuthData.Has(FlagUserVerified))
+
+			got := clientData(TypeGet)
+			assertion := authData(FlagUserPresent, 5, nil)
+			hash := sha256.Sum256(got)
+			sig := sign(t, tt.key, tt.alg, append(append([]byte(nil), assertion...), hash[:]...))
+			a, err := VerifyAssertion(reg.Credential.RawKey, assertion, got, sig, expect)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(5), a.SignCount)
+
+			sig[len(sig)/2] ^= 1
+			_, err = VerifyAssertion(reg.Credential.RawKey, assertion, got, sig, expect)
+			assert.Equal(t, SignatureVerificationError{}, err)
+		})
+	}
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rawKey := coseKey(t, key.Public(), ES256)
+	created := clientData(TypeCreate)
+	auth := authData(FlagUserPresent|FlagAttestedCredential, 0, rawKey)
+
+	t.Run("none attestation", func(t *testing.T) {
+		obj, _ := cbor.Marshal(map[any]any{"fmt": "none", "attStmt": map[any]any{}, "authData": auth})
+		reg, err := VerifyRegistration(obj, created, expect)
+		require.NoError(t, err)
+		assert.Equal(t, AttestationNone, reg.Attestation)
+		assert.Equal(t, key.Public(), reg.Credential.PublicKey)
+
+		obj, _ = cbor.Marshal(map[any]any{"fmt": "none", "attStmt": map[any]any{"sig": []byte{1}}, "authData": auth})
+		_, err = VerifyRegistration(obj, created, expect)
+		assert.IsType(t, InvalidAttestationError{}, err)
+	})
+
+	t.Run("basic attestation", func(t *testing.T) {
+		attKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		roots, cert := attestationCA(t, attKey.Public(), nil)
+		obj := attestationObject(t, attKey, ES256, auth, created, cert)
+
+		exp := expect
+		exp.Roots = roots
+		reg, err := VerifyRegistration(obj, created, exp)
+		require.NoError(t, err)
+		assert.Equal(t, AttestationBasic, reg.Attestation)
+		require.Len(t, reg.Certificates, 1)
+		assert.Equal(t, "Dongle Authenticator", reg.Certificates[0].Subject.CommonName)
+
+		exp.Roots = x509.NewCertPool()
+		_, err = VerifyRegistration(obj, created, exp)
+		assert.IsType(t, CertificateError{}, err)
+
+		_, err = VerifyRegistration(attestationObject(t, key, ES256, auth, created, cert), created, expect)
+		assert.Equal(t, SignatureVerificationError{}, err)
+	})
+
+	t.Run("certificate requirements", func(t *testing.T) {
+		attKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		for name, modify := range map[string]func(*x509.Certificate){
+			"organizational unit": func(c *x509.Certificate) { c.Subject.OrganizationalUnit = []string{"Other"} },
+			"country":             func(c *x509.Certificate) { c.Subject.Country = nil },
+			"ca":                  func(c *x509.Certificate) { c.IsCA = true },
+		} {
+			_, cert := attestationCA(t, attKey.Public(), modify)
+			_, err := VerifyRegistration(attestationObject(t, attKey, ES256, auth, created, cert), created, expect)
+			assert.IsType(t, CertificateError{}, err, name)
+		}
+		_, cert := attestationCA(t, attKey.Public(), func(c *x509.Certificate) {
+			ext, _ := asn1.Marshal(make([]byte, 16))
+			c.ExtraExtensions = []pkix.Extension{{Id: oidAAGUID, Value: ext}}
+		})
+		_, err := VerifyRegistration(attestationObject(t, attKey, ES256, auth, created, cert), created, expect)
+		assert.Equal(t, MismatchError{Field: "aaguid"}, err)
+	})
+
+	t.Run("trusted attestation required", func(t *testing.T) {
+		exp := expect
+		exp.Roots = x509.NewCertPool()
+
+		obj, _ := cbor.Marshal(map[any]any{"fmt": "none", "attStmt": map[any]any{}, "authData": auth})
+		_, err := VerifyRegistration(obj, created, exp)
+		assert.Equal(t, UntrustedAttestationError{Type: AttestationNone}, err)
+		assert.Equal(t, "crypto/webauthn: none attestation cannot be verified against the attestation roots", err.Error())
+
+		self := attestationObject(t, key, ES256, auth, created)
+		reg, err := VerifyRegistration(self, created, expect)
+		require.NoError(t, err)
+		assert.Equal(t, AttestationSelf, reg.Attestation)
+		_, err = VerifyRegistration(self, created, exp)
+		assert.Equal(t, UntrustedAttestationError{Type: AttestationSelf}, err)
+	})
+
+	t.Run("self attestation algorithm mismatch", func(t *testing.T) {
+		_, err := VerifyRegistration(attestationObject(t, key, ES384, auth, created), created, expect)
+		assert.IsType(t, InvalidAttestationError{}, err)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		obj, _ := cbor.Marshal(map[any]any{"fmt": "tpm", "attStmt": map[any]any{}, "authData": auth})
+		_, err := VerifyRegistration(obj, created, expect)
+		assert.Equal(t, UnsupportedFormatError{Format: "tpm"}, err)
+		assert.Equal(t, `crypto/webauthn: unsupported attestation format "tpm"`, err.Error())
+	})
+
+	t.Run("missing credential", func(t *testing.T) {
+		obj, _ := cbor.Marshal(map[any]any{"fmt": "none", "attStmt": map[any]any{}, "authData": authData(FlagUserPresent, 0, nil)})
+		_, err := VerifyRegistration(obj, created, expect)
+		assert.Equal(t, MissingFlagError{Flag: FlagAttestedCredential}, err)
+	})
+
+	t.Run("ceremony checks", func(t *testing.T) {
+		obj := attestationObject(t, key, ES256, auth, created)
+		_, err := VerifyRegistration(obj, clientData(TypeGet), expect)
+		assert.Equal(t, MismatchError{Field: "type"}, err)
+
+		exp := expect
+		exp.Challenge = []byte("another challenge")
+		_, err = VerifyRegistration(obj, created, exp)
+		assert.Equal(t, MismatchError{Field: "challenge"}, err)
+
+		exp = expect
+		exp.Origins = []string{"https://evil.example"}
+		_, err = VerifyRegistration(obj, created, exp)
+		assert.Equal(t, MismatchError{Field: "origin"}, err)
+		assert.Equal(t, "crypto/webauthn: origin mismatch", err.Error())
+
+		exp = expect
+		exp.RPID = "evil.example"
+		_, err = VerifyRegistration(obj, created, exp)
+		assert.Equal(t, MismatchError{Field: "rpIdHash"}, err)
+
+		exp = expect
+		exp.RequireUserVerification = true
+		_, err = VerifyRegistration(obj, created, exp)
+		assert.Equal(t, MissingFlagError{Flag: FlagUserVerified}, err)
+		assert.Equal(t, "crypto/webauthn: user verification flag not set", err.Error())
+
+		_, err = VerifyRegistration(obj, []byte("{"), expect)
+		assert.IsType(t, InvalidClientDataError{}, err)
+		_, err = VerifyRegistration([]byte{0xff}, created, expect)
+		assert.IsType(t, InvalidAttestationError{}, err)
+	})
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rawKey := coseKey(t, key.Public(), ES256)
+	got := clientData(TypeGet)
+	hash := sha256.Sum256(got)
+	assertion := func(flags byte, count uint32) ([]byte, []byte) {
+		auth := authData(flags, count, nil)
+		return auth, sign(t, key, ES256, append(append([]byte(nil), auth...), hash[:]...))
+	}
+
+	t.Run("counter", func(t *testing.T) {
+		auth, sig := assertion(FlagUserPresent, 7)
+		exp := expect
+		exp.SignCount = 7
+		_, err := VerifyAssertion(rawKey, auth, got, sig, exp)
+		assert.Equal(t, CounterError{Stored: 7, Received: 7}, err)
+		assert.Equal(t, "crypto/webauthn: signature counter 7 not greater than stored counter 7", err.Error())
+
+		auth, sig = assertion(FlagUserPresent, 0)
+		_, err = VerifyAssertion(rawKey, auth, got, sig, expect)
+		assert.NoError(t, err)
+	})
+
+	t.Run("user presence", func(t *testing.T) {
+		auth, sig := assertion(0, 1)
+		_, err := VerifyAssertion(rawKey, auth, got, sig, expect)
+		assert.Equal(t, MissingFlagError{Flag: FlagUserPresent}, err)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		auth, sig := assertion(FlagUserPresent, 1)
+		_, err := VerifyAssertion(coseKey(t, other.Public(), ES256), auth, got, sig, expect)
+		assert.Equal(t, SignatureVerificationError{}, err)
+	})
+
+	t.Run("registration client data", func(t *testing.T) {
+		auth, sig := assertion(FlagUserPresent, 1)
+		_, err := VerifyAssertion(rawKey, auth, clientData(TypeCreate), sig, expect)
+		assert.Equal(t, MismatchError{Field: "type"}, err)
+	})
+}
+
+func TestParseAuthenticatorData(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rawKey := coseKey(t, key.Public(), ES256)
+
+	ext, _ := cbor.Marshal(map[any]any{"credProtect": int64(2)})
+	a, err := ParseAuthenticatorData(append(authData(FlagUserPresent|FlagAttestedCredential|FlagExtensions, 1, rawKey), ext...))
+	require.NoError(t, err)
+	assert.Equal(t, map[any]any{"credProtect": int64(2)}, a.Extensions)
+	assert.Equal(t, rawKey, a.Credential.RawKey)
+
+	for name, data := range map[string][]byte{
+		"short":             make([]byte, 36),
+		"trailing":          append(authData(FlagUserPresent, 1, nil), 0),
+		"truncated":         authData(FlagUserPresent|FlagAttestedCredential, 1, nil),
+		"credential id":     append(authData(FlagUserPresent|FlagAttestedCredential, 1, nil), append(aaguid[:], 0, 9, 1)...),
+		"extensions":        append(authData(FlagUserPresent|FlagExtensions, 1, nil), 0x01),
+		"key":               authData(FlagUserPresent|FlagAttestedCredential, 1, []byte{0xff}),
+		"missing extension": authData(FlagUserPresent|FlagExtensions, 1, nil),
+	} {
+		_, err := ParseAuthenticatorData(data)
+		assert.IsType(t, InvalidAuthenticatorDataError{}, err, name)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, ed, _ := ed25519.GenerateKey(rand.Reader)
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	x := key.X.FillBytes(make([]byte, 32))
+
+	pub, alg, err := ParsePublicKey(coseKey(t, ed.Public(), EdDSA))
+	require.NoError(t, err)
+	assert.Equal(t, EdDSA, alg)
+	assert.Equal(t, ed.Public(), pub)
+
+	for name, m := range map[string]any{
+		"not a map":      []any{},
+		"no algorithm":   map[any]any{keyType: ktyEC2},
+		"wrong curve":    map[any]any{keyType: ktyEC2, keyAlg: int64(ES256), keyCurve: crvP384, keyX: x, keyY: x},
+		"wrong type":     map[any]any{keyType: ktyOKP, keyAlg: int64(ES256), keyCurve: crvP256, keyX: x, keyY: x},
+		"off curve":      map[any]any{keyType: ktyEC2, keyAlg: int64(ES256), keyCurve: crvP256, keyX: x, keyY: x},
+		"short x":        map[any]any{keyType: ktyEC2, keyAlg: int64(ES256), keyCurve: crvP256, keyX: x[1:], keyY: x},
+		"ed25519 length": map[any]any{keyType: ktyOKP, keyAlg: int64(EdDSA), keyCurve: crvEd25519, keyX: x[1:]},
+		"rsa type":       map[any]any{keyType: ktyEC2, keyAlg: int64(RS256)},
+		"small rsa":      map[any]any{keyType: ktyRSA, keyAlg: int64(RS256), keyCurve: small.N.Bytes(), keyX: []byte{1, 0, 1}},
+		"even exponent":  map[any]any{keyType: ktyRSA, keyAlg: int64(RS256), keyCurve: make([]byte, 256), keyX: []byte{2}},
+	} {
+		data, _ := cbor.Marshal(m)
+		_, _, err := ParsePublicKey(data)
+		assert.IsType(t, InvalidPublicKeyError{}, err, name)
+	}
+
+	data, _ := cbor.Marshal(map[any]any{keyType: ktyEC2, keyAlg: int64(-65535)})
+	_, _, err = ParsePublicKey(data)
+	assert.Equal(t, UnsupportedAlgorithmError{Algorithm: -65535}, err)
+	assert.Equal(t, "crypto/webauthn: unsupported algorithm -65535", err.Error())
+	_, _, err = ParsePublicKey([]byte{0xff})
+	assert.IsType(t, InvalidPublicKeyError{}, err)
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sig := sign(t, key, ES256, []byte("data"))
+	assert.NoError(t, VerifySignature(key.Public(), ES256, []byte("data"), sig))
+	assert.Equal(t, UnsupportedAlgorithmError{Algorithm: ES384}, VerifySignature(key.Public(), ES384, []byte("data"), sig))
+	assert.Equal(t, UnsupportedAlgorithmError{Algorithm: ES256}, VerifySignature(ed25519.PublicKey(make([]byte, 32)), ES256, nil, nil))
+	assert.Equal(t, UnsupportedAlgorithmError{Algorithm: EdDSA}, VerifySignature(&rsa.PublicKey{}, EdDSA, nil, nil))
+	assert.IsType(t, InvalidPublicKeyError{}, VerifySignature("key", ES256, nil, nil))
+}
@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"io"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/xchacha20poly1305"
+)
+
+// ByXChaCha20Poly1305 encrypts by xchacha20-poly1305.
+func (e Encrypter) ByXChaCha20Poly1305(c *cipher.XChaCha20Poly1305Cipher) Encrypter {
+	if e.Error != nil {
+		return e
+	}
+
+	o := observe(OperationEncrypt, "XChaCha20-Poly1305", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("XChaCha20-Poly1305"); e.Error != nil {
+		return e
+	}
+
+	// Streaming encryption mode
+	if e.reader != nil {
+		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
+			return xchacha20poly1305.NewStreamEncrypter(w, c)
+		})
+		return e
+	}
+
+	// Standard encryption mode
+	if len(e.src) > 0 {
+		e.dst, e.Error = xchacha20poly1305.NewStdEncrypter(c).Encrypt(e.src)
+	}
+
+	return e
+}
+
+// ByXChaCha20Poly1305 decrypts by xchacha20-poly1305.
+func (d Decrypter) ByXChaCha20Poly1305(c *cipher.XChaCha20Poly1305Cipher) Decrypter {
+	if d.Error != nil {
+		return d
+	}
+
+	o := observe(OperationDecrypt, "XChaCha20-Poly1305", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("XChaCha20-Poly1305"); d.Error != nil {
+		return d
+	}
+
+	// Streaming decryption mode
+	if d.reader != nil {
+		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
+			return xchacha20poly1305.NewStreamDecrypter(r, c)
+		})
+		return d
+	}
+
+	// Standard decryption mode
+	if len(d.src) > 0 {
+		d.dst, d.Error = d.limit(xchacha20poly1305.NewStdDecrypter(c).Decrypt(d.src))
+	}
+
+	return d
+}
@@ -0,0 +1,163 @@
+package xchacha20poly1305
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// KeySizeError represents an error when the XChaCha20-Poly1305 key size is invalid.
+// XChaCha20-Poly1305 keys must be exactly 32 bytes (256 bits) long.
+// This error occurs when the provided key does not meet this size requirement.
+type KeySizeError int
+
+// Error returns a formatted error message describing the invalid key size.
+// The message includes the actual key size and the required size for debugging.
+func (k KeySizeError) Error() string {
+	return fmt.Sprintf("crypto/xchacha20poly1305: invalid key size %d, must be exactly 32 bytes", k)
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-001.
+func (k KeySizeError) Code() string {
+	return "DGL-XCHACHA20POLY1305-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "key", errcode.FieldKeySize, int(k))
+}
+
+// InvalidNonceSizeError represents an error when the XChaCha20-Poly1305 nonce size is invalid.
+// XChaCha20-Poly1305 nonces must be exactly 24 bytes long.
+// This error occurs when the provided nonce does not meet this size requirement.
+type InvalidNonceSizeError struct {
+	Size int
+}
+
+// Error returns a formatted error message describing the invalid nonce size.
+// The message includes the actual nonce size and the required size for debugging.
+func (e InvalidNonceSizeError) Error() string {
+	return fmt.Sprintf("crypto/xchacha20poly1305: invalid nonce size %d, must be exactly 24 bytes", e.Size)
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-002.
+func (e InvalidNonceSizeError) Code() string {
+	return "DGL-XCHACHA20POLY1305-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidNonceSizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "", "nonce_size", e.Size)
+}
+
+// EncryptError represents an error when XChaCha20-Poly1305 encryption fails.
+// This error occurs when the underlying XChaCha20-Poly1305 encryption operation fails.
+// The error includes the underlying error for detailed debugging.
+type EncryptError struct {
+	Err error
+}
+
+// Error returns a formatted error message describing the encryption failure.
+// The message includes the underlying error for debugging.
+func (e EncryptError) Error() string {
+	return fmt.Sprintf("crypto/xchacha20poly1305: failed to encrypt data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-003.
+func (e EncryptError) Code() string {
+	return "DGL-XCHACHA20POLY1305-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "encrypt", errcode.FieldCause, e.Err)
+}
+
+// DecryptError represents an error when XChaCha20-Poly1305 decryption fails.
+// This error occurs when the underlying XChaCha20-Poly1305 decryption operation fails.
+// The error includes the underlying error for detailed debugging.
+type DecryptError struct {
+	Err error
+}
+
+// Error returns a formatted error message describing the decryption failure.
+// The message includes the underlying error for debugging.
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("crypto/xchacha20poly1305: failed to decrypt data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-004.
+func (e DecryptError) Code() string {
+	return "DGL-XCHACHA20POLY1305-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "decrypt", errcode.FieldCause, e.Err)
+}
+
+// WriteError represents an error when writing encrypted data fails.
+// This error occurs when writing encrypted data to the underlying writer fails.
+// The error includes the underlying error for detailed debugging.
+type WriteError struct {
+	Err error
+}
+
+// Error returns a formatted error message describing the write failure.
+// The message includes the underlying error for debugging.
+func (e WriteError) Error() string {
+	return fmt.Sprintf("crypto/xchacha20poly1305: failed to write encrypted data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-005.
+func (e WriteError) Code() string {
+	return "DGL-XCHACHA20POLY1305-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e WriteError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "write", errcode.FieldCause, e.Err)
+}
+
+// ReadError represents an error when reading encrypted data fails.
+// This error occurs when reading encrypted data from the underlying reader fails.
+// The error includes the underlying error for detailed debugging.
+type ReadError struct {
+	Err error
+}
+
+// Error returns a formatted error message describing the read failure.
+// The message includes the underlying error for debugging.
+func (e ReadError) Error() string {
+	return fmt.Sprintf("crypto/xchacha20poly1305: failed to read encrypted data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-006.
+func (e ReadError) Code() string {
+	return "DGL-XCHACHA20POLY1305-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "read", errcode.FieldCause, e.Err)
+}
+
+// AuthenticationError represents an error when XChaCha20-Poly1305 authentication fails.
+// This occurs when the computed MAC doesn't match the expected MAC during decryption.
+// This error indicates that the data has been tampered with or corrupted.
+type AuthenticationError struct{}
+
+// Error returns a formatted error message describing the authentication failure.
+func (e AuthenticationError) Error() string {
+	return "crypto/xchacha20poly1305: message authentication failed"
+}
+
+// Code returns the stable error code DGL-XCHACHA20POLY1305-007.
+func (e AuthenticationError) Code() string {
+	return "DGL-XCHACHA20POLY1305-007"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e AuthenticationError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xchacha20poly1305", "XChaCha20-Poly1305", "")
+}
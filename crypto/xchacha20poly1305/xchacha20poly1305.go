@@ -0,0 +1,286 @@
+// Package xchacha20poly1305 implements XChaCha20-Poly1305 authenticated encryption and decryption with streaming support.
+// XChaCha20-Poly1305 is ChaCha20-Poly1305 with a 192-bit nonce, long enough to be generated at random for every
+// message, with support for 256-bit keys and optional associated data.
+package xchacha20poly1305
+
+import (
+	stdCipher "crypto/cipher"
+	"errors"
+	"io"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// newAEAD validates the key and nonce of the cipher and returns its AEAD.
+func newAEAD(c *cipher.XChaCha20Poly1305Cipher) (stdCipher.AEAD, error) {
+	if len(c.Key) != chacha20poly1305.KeySize {
+		return nil, KeySizeError(len(c.Key))
+	}
+	if len(c.Nonce) != chacha20poly1305.NonceSizeX {
+		return nil, InvalidNonceSizeError{Size: len(c.Nonce)}
+	}
+	return chacha20poly1305.NewX(c.Key)
+}
+
+// StdEncrypter represents a XChaCha20-Poly1305 encrypter for standard encryption operations.
+// It implements XChaCha20-Poly1305 AEAD encryption with support for 256-bit keys, 192-bit nonces, and optional AAD.
+type StdEncrypter struct {
+	cipher cipher.XChaCha20Poly1305Cipher // The cipher interface for encryption operations
+	aead   stdCipher.AEAD                 // AEAD cipher created from the key
+	Error  error                          // Error field for storing encryption errors
+}
+
+// NewStdEncrypter creates a new XChaCha20-Poly1305 encrypter with the specified cipher.
+// The key must be exactly 32 bytes (256 bits) and nonce must be 24 bytes (192 bits).
+func NewStdEncrypter(c *cipher.XChaCha20Poly1305Cipher) *StdEncrypter {
+	e := &StdEncrypter{
+		cipher: *c,
+	}
+	e.aead, e.Error = newAEAD(c)
+	return e
+}
+
+// Encrypt encrypts the given byte slice using XChaCha20-Poly1305 encryption,
+// returning the ciphertext followed by its 16-byte authentication tag.
+// Returns empty data when input is empty.
+func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
+	// Check for existing errors from initialization
+	if e.Error != nil {
+		err = e.Error
+		return
+	}
+
+	// Return empty data for empty input
+	if len(src) == 0 {
+		return
+	}
+
+	// Prefix the commitment to the key
+	if e.cipher.KeyCommitment {
+		return e.aead.Seal(cipher.KeyCommitment(e.cipher.Key, e.cipher.Nonce), e.cipher.Nonce, src, e.cipher.AAD), nil
+	}
+	return e.aead.Seal(nil, e.cipher.Nonce, src, e.cipher.AAD), nil
+}
+
+// StdDecrypter represents a XChaCha20-Poly1305 decrypter for standard decryption operations.
+// It implements XChaCha20-Poly1305 AEAD decryption with authentication verification.
+type StdDecrypter struct {
+	cipher cipher.XChaCha20Poly1305Cipher // The cipher interface for decryption operations
+	aead   stdCipher.AEAD                 // AEAD cipher created from the key
+	Error  error                          // Error field for storing decryption errors
+}
+
+// NewStdDecrypter creates a new XChaCha20-Poly1305 decrypter with the specified cipher.
+// The key must be exactly 32 bytes (256 bits) and nonce must be 24 bytes (192 bits).
+func NewStdDecrypter(c *cipher.XChaCha20Poly1305Cipher) *StdDecrypter {
+	d := &StdDecrypter{
+		cipher: *c,
+	}
+	d.aead, d.Error = newAEAD(c)
+	return d
+}
+
+// Decrypt decrypts the given byte slice using XChaCha20-Poly1305 decryption.
+// The input must include the authentication tag; an AuthenticationError is
+// returned when it does not match the ciphertext, nonce, key or AAD.
+// Returns empty data when input is empty.
+func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
+	// Check for existing errors from initialization
+	if d.Error != nil {
+		err = d.Error
+		return
+	}
+
+	// Return empty data for empty input
+	if len(src) == 0 {
+		return
+	}
+
+	// Check the commitment to the key
+	if d.cipher.KeyCommitment {
+		if src, err = cipher.OpenKeyCommitment(src, d.cipher.Key, d.cipher.Nonce); err != nil {
+			return nil, AuthenticationError{}
+		}
+	}
+
+	dst, err = d.aead.Open(nil, d.cipher.Nonce, src, d.cipher.AAD)
+	if err != nil {
+		return nil, AuthenticationError{}
+	}
+	return dst, nil
+}
+
+// StreamEncrypter represents a streaming XChaCha20-Poly1305 encrypter that implements io.WriteCloser.
+// Without a segment size the stream is sealed as a single message on Close, with the same
+// output as StdEncrypter; with one it is sealed segment by segment, see cipher.SetSegmentSize.
+type StreamEncrypter struct {
+	writer io.Writer                      // Underlying writer for encrypted output
+	cipher cipher.XChaCha20Poly1305Cipher // The cipher interface for encryption operations
+	aead   stdCipher.AEAD                 // AEAD cipher created from the key
+	buffer []byte                         // Plaintext of a stream without segments
+	// Segmented stream, see cipher.SetSegmentSize
+	segments *cipher.SegmentWriter
+	Error    error // Error field for storing encryption errors
+}
+
+// NewStreamEncrypter creates a new streaming XChaCha20-Poly1305 encrypter that writes encrypted data
+// to the provided io.Writer. Close must be called to write the ciphertext, or the final segment.
+// The key must be exactly 32 bytes (256 bits) and nonce must be 24 bytes (192 bits).
+func NewStreamEncrypter(w io.Writer, c *cipher.XChaCha20Poly1305Cipher) io.WriteCloser {
+	e := &StreamEncrypter{
+		writer: w,
+		cipher: *c,
+	}
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
+	e.aead, e.Error = newAEAD(c)
+	if e.Error == nil && c.SegmentSize > 0 {
+		e.segments, e.Error = cipher.NewSegmentWriter(w, e.aead, c.Nonce, c.AAD, c.SegmentSize)
+	}
+	return e
+}
+
+// Write implements io.Writer interface for streaming XChaCha20-Poly1305 encryption.
+func (e *StreamEncrypter) Write(p []byte) (n int, err error) {
+	if e.Error != nil {
+		return 0, e.Error
+	}
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if e.segments != nil {
+		n, err = e.segments.Write(p)
+		if err != nil {
+			e.Error = WriteError{Err: err}
+			return n, e.Error
+		}
+		return n, nil
+	}
+
+	e.buffer = append(e.buffer, p...)
+	return len(p), nil
+}
+
+// Close implements io.Closer interface for streaming XChaCha20-Poly1305 encryption.
+// Writes the sealed stream, or its final segment, and closes the underlying writer
+// if it implements io.Closer.
+func (e *StreamEncrypter) Close() error {
+	if e.Error != nil {
+		return e.Error
+	}
+
+	var err error
+	if e.segments != nil {
+		err = e.segments.Close()
+	} else if len(e.buffer) > 0 {
+		_, err = e.writer.Write(e.aead.Seal(nil, e.cipher.Nonce, e.buffer, e.cipher.AAD))
+		e.buffer = nil
+	}
+	if err != nil {
+		e.Error = WriteError{Err: err}
+		return e.Error
+	}
+
+	if closer, ok := e.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// StreamDecrypter represents a streaming XChaCha20-Poly1305 decrypter that implements io.Reader.
+// Without a segment size the whole stream is read and authenticated on the first Read; with one
+// it is decrypted segment by segment. Either way only authenticated plaintext is returned, unless
+// the cipher uses the ReleaseUnverified policy, see SetReleasePolicy.
+type StreamDecrypter struct {
+	reader   io.Reader                      // Underlying reader for encrypted input
+	cipher   cipher.XChaCha20Poly1305Cipher // The cipher interface for decryption operations
+	aead     stdCipher.AEAD                 // AEAD cipher created from the key
+	buffer   []byte                         // Decrypted data of a stream without segments
+	position int                            // Current position in the buffer
+	// Segmented stream, see cipher.SetSegmentSize
+	segments *cipher.SegmentReader
+	// Stream released before verification, see cipher.SetReleasePolicy
+	unverified *cipher.UnverifiedReader
+	Error      error // Error field for storing decryption errors
+}
+
+// NewStreamDecrypter creates a new streaming XChaCha20-Poly1305 decrypter that reads encrypted data
+// from the provided io.Reader.
+// The key must be exactly 32 bytes (256 bits) and nonce must be 24 bytes (192 bits).
+func NewStreamDecrypter(r io.Reader, c *cipher.XChaCha20Poly1305Cipher) io.Reader {
+	d := &StreamDecrypter{
+		reader: r,
+		cipher: *c,
+	}
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+	d.aead, d.Error = newAEAD(c)
+	if d.Error == nil && c.SegmentSize > 0 {
+		d.segments, d.Error = cipher.NewSegmentReader(r, d.aead, c.Nonce, c.AAD, c.SegmentSize)
+	}
+	if d.Error == nil {
+		d.unverified, d.Error = c.NewUnverifiedReader(r, d.aead)
+	}
+	return d
+}
+
+// Read implements io.Reader interface for streaming XChaCha20-Poly1305 decryption.
+func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
+	if d.Error != nil {
+		return 0, d.Error
+	}
+
+	if d.segments != nil {
+		n, err = d.segments.Read(p)
+		var (
+			authErr    cipher.SegmentAuthError
+			segmentErr cipher.InvalidSegmentError
+		)
+		switch {
+		case errors.As(err, &authErr), errors.As(err, &segmentErr):
+			err = AuthenticationError{}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
+	if d.unverified != nil {
+		n, err = d.unverified.Read(p)
+		var authErr cipher.StreamAuthError
+		switch {
+		case errors.As(err, &authErr):
+			err = AuthenticationError{}
+		case err != nil && err != io.EOF:
+			err = ReadError{Err: err}
+		}
+		return n, err
+	}
+
+	if d.buffer == nil {
+		encrypted, err := io.ReadAll(d.reader)
+		if err != nil {
+			d.Error = ReadError{Err: err}
+			return 0, d.Error
+		}
+		if len(encrypted) == 0 {
+			return 0, io.EOF
+		}
+		if d.buffer, err = d.aead.Open([]byte{}, d.cipher.Nonce, encrypted, d.cipher.AAD); err != nil {
+			d.Error = AuthenticationError{}
+			return 0, d.Error
+		}
+	}
+
+	if d.position >= len(d.buffer) {
+		return 0, io.EOF
+	}
+	n = copy(p, d.buffer[d.position:])
+	d.position += n
+	return n, nil
+}
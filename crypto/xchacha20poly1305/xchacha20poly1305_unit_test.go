@@ -0,0 +1,227 @@
+package xchacha20poly1305
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vector from draft-irtf-cfrg-xchacha-03, appendix A.3.1
+var (
+	vectorKey, _        = hex.DecodeString("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	vectorNonce, _      = hex.DecodeString("404142434445464748494a4b4c4d4e4f5051525354555657")
+	vectorAAD, _        = hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	vectorPlaintext     = []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	vectorCiphertext, _ = hex.DecodeString("bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb" +
+		"731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b452" +
+		"2f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff9" +
+		"21f9664c97637da9768812f615c68b13b52e" +
+		"c0875924c1c7987947deafd8780acf49")
+)
+
+func newCipher() *cipher.XChaCha20Poly1305Cipher {
+	c := cipher.NewXChaCha20Poly1305Cipher()
+	c.SetKey(vectorKey)
+	c.SetNonce(vectorNonce)
+	c.SetAAD(vectorAAD)
+	return c
+}
+
+func TestStdEncrypter_Encrypt(t *testing.T) {
+	t.Run("test vector", func(t *testing.T) {
+		dst, err := NewStdEncrypter(newCipher()).Encrypt(vectorPlaintext)
+		assert.Nil(t, err)
+		assert.Equal(t, vectorCiphertext, dst)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		dst, err := NewStdEncrypter(newCipher()).Encrypt(nil)
+		assert.Nil(t, err)
+		assert.Empty(t, dst)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		c := newCipher()
+		c.SetKey([]byte("short"))
+		_, err := NewStdEncrypter(c).Encrypt(vectorPlaintext)
+		assert.Equal(t, KeySizeError(5), err)
+	})
+
+	t.Run("invalid nonce", func(t *testing.T) {
+		c := newCipher()
+		c.SetNonce(vectorNonce[:12])
+		_, err := NewStdEncrypter(c).Encrypt(vectorPlaintext)
+		assert.Equal(t, InvalidNonceSizeError{Size: 12}, err)
+		assert.Equal(t, "crypto/xchacha20poly1305: invalid nonce size 12, must be exactly 24 bytes", err.Error())
+	})
+}
+
+func TestStdDecrypter_Decrypt(t *testing.T) {
+	t.Run("test vector", func(t *testing.T) {
+		dst, err := NewStdDecrypter(newCipher()).Decrypt(vectorCiphertext)
+		assert.Nil(t, err)
+		assert.Equal(t, vectorPlaintext, dst)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		dst, err := NewStdDecrypter(newCipher()).Decrypt(nil)
+		assert.Nil(t, err)
+		assert.Empty(t, dst)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		c := newCipher()
+		c.SetAAD([]byte("other"))
+		_, err := NewStdDecrypter(c).Decrypt(vectorCiphertext)
+		assert.Equal(t, AuthenticationError{}, err)
+
+		tampered := bytes.Clone(vectorCiphertext)
+		tampered[0] ^= 1
+		_, err = NewStdDecrypter(newCipher()).Decrypt(tampered)
+		assert.Equal(t, AuthenticationError{}, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		c := newCipher()
+		c.SetKey(nil)
+		_, err := NewStdDecrypter(c).Decrypt(vectorCiphertext)
+		assert.Equal(t, KeySizeError(0), err)
+	})
+}
+
+func TestStream(t *testing.T) {
+	t.Run("single message", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		for _, chunk := range [][]byte{vectorPlaintext[:10], nil, vectorPlaintext[10:]} {
+			n, err := encrypter.Write(chunk)
+			assert.Nil(t, err)
+			assert.Equal(t, len(chunk), n)
+		}
+		assert.Nil(t, encrypter.Close())
+		assert.Equal(t, vectorCiphertext, buf.Bytes())
+
+		decrypter := NewStreamDecrypter(mock.NewScriptedReader(bytes.NewReader(buf.Bytes())).Then(7).Repeat(), newCipher())
+		got, err := io.ReadAll(decrypter)
+		assert.Nil(t, err)
+		assert.Equal(t, vectorPlaintext, got)
+	})
+
+	t.Run("segmented", func(t *testing.T) {
+		newSegmented := func() *cipher.XChaCha20Poly1305Cipher {
+			c := newCipher()
+			c.SetSegmentSize(64)
+			return c
+		}
+		data := bytes.Repeat(vectorPlaintext, 10)
+
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newSegmented())
+		_, err := encrypter.Write(data)
+		assert.Nil(t, err)
+		assert.Nil(t, encrypter.Close())
+		assert.Len(t, buf.Bytes(), len(data)+(len(data)/64+1)*16)
+
+		got, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(buf.Bytes()), newSegmented()))
+		assert.Nil(t, err)
+		assert.Equal(t, data, got)
+
+		sealed := buf.Bytes()
+		sealed[100] ^= 1
+		_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(sealed), newSegmented()))
+		assert.Equal(t, AuthenticationError{}, err)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		encrypter := NewStreamEncrypter(&buf, newCipher())
+		assert.Nil(t, encrypter.Close())
+		assert.Empty(t, buf.Bytes())
+
+		got, err := io.ReadAll(NewStreamDecrypter(bytes.NewReader(nil), newCipher()))
+		assert.Nil(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Clone(vectorCiphertext)
+		tampered[len(tampered)-1] ^= 1
+		decrypter := NewStreamDecrypter(bytes.NewReader(tampered), newCipher())
+		_, err := decrypter.Read(make([]byte, 16))
+		assert.Equal(t, AuthenticationError{}, err)
+		_, err = decrypter.Read(make([]byte, 16))
+		assert.Equal(t, AuthenticationError{}, err)
+	})
+
+	t.Run("invalid nonce", func(t *testing.T) {
+		c := newCipher()
+		c.SetNonce(nil)
+		encrypter := NewStreamEncrypter(io.Discard, c)
+		_, err := encrypter.Write(vectorPlaintext)
+		assert.Equal(t, InvalidNonceSizeError{Size: 0}, err)
+		assert.Equal(t, InvalidNonceSizeError{Size: 0}, encrypter.Close())
+		_, err = NewStreamDecrypter(bytes.NewReader(vectorCiphertext), c).Read(make([]byte, 16))
+		assert.Equal(t, InvalidNonceSizeError{Size: 0}, err)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		encrypter := NewStreamEncrypter(mock.NewErrorWriteCloser(writeErr), newCipher())
+		encrypter.Write(vectorPlaintext)
+		assert.Equal(t, WriteError{Err: writeErr}, encrypter.Close())
+
+		c := newCipher()
+		c.SetSegmentSize(16)
+		encrypter = NewStreamEncrypter(mock.NewErrorWriteCloser(writeErr), c)
+		_, err := encrypter.Write(vectorPlaintext)
+		assert.Equal(t, WriteError{Err: writeErr}, err)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("read error")
+		_, err := NewStreamDecrypter(mock.NewErrorFile(readErr), newCipher()).Read(make([]byte, 16))
+		assert.Equal(t, ReadError{Err: readErr}, err)
+
+		c := newCipher()
+		c.SetSegmentSize(16)
+		_, err = NewStreamDecrypter(mock.NewErrorFile(readErr), c).Read(make([]byte, 16))
+		assert.Equal(t, ReadError{Err: readErr}, err)
+	})
+
+	t.Run("close underlying writer", func(t *testing.T) {
+		file := mock.NewFile(nil, "out")
+		encrypter := NewStreamEncrypter(file, newCipher())
+		encrypter.Write(vectorPlaintext)
+		assert.Nil(t, encrypter.Close())
+	})
+}
+
+func TestKeyCommitment(t *testing.T) {
+	c := newCipher()
+	c.SetKeyCommitment(true)
+	dst, err := NewStdEncrypter(c).Encrypt(vectorPlaintext)
+	assert.NoError(t, err)
+	assert.Equal(t, cipher.KeyCommitment(vectorKey, vectorNonce), dst[:cipher.KeyCommitmentSize])
+	assert.Equal(t, vectorCiphertext, dst[cipher.KeyCommitmentSize:])
+
+	got, err := NewStdDecrypter(c).Decrypt(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, vectorPlaintext, got)
+
+	other := newCipher()
+	other.SetKey(bytes.Repeat([]byte{1}, 32))
+	other.SetKeyCommitment(true)
+	_, err = NewStdDecrypter(other).Decrypt(dst)
+	assert.Equal(t, AuthenticationError{}, err)
+
+	_, err = NewStreamEncrypter(io.Discard, c).Write(vectorPlaintext)
+	assert.Equal(t, cipher.UnsupportedKeyCommitmentError{}, err)
+	_, err = io.ReadAll(NewStreamDecrypter(bytes.NewReader(dst), c))
+	assert.Equal(t, cipher.UnsupportedKeyCommitmentError{}, err)
+}
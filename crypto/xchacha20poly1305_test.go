@@ -0,0 +1,123 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/xchacha20poly1305"
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+var xChaCha20Poly1305Data = []byte("hello world from xchacha20poly1305")
+
+func newXChaCha20Poly1305Cipher(nonce []byte) *cipher.XChaCha20Poly1305Cipher {
+	c := cipher.NewXChaCha20Poly1305Cipher()
+	c.SetKey([]byte("dongle1234567890abcdef123456789x")) // 32 bytes
+	c.SetNonce(nonce)
+	c.SetAAD([]byte("additional authenticated data"))
+	return c
+}
+
+func TestEncrypter_ByXChaCha20Poly1305(t *testing.T) {
+	nonce := make([]byte, 24)
+	_, _ = rand.Read(nonce)
+	c := newXChaCha20Poly1305Cipher(nonce)
+
+	t.Run("normal encrypt", func(t *testing.T) {
+		encrypted := NewEncrypter().FromBytes(xChaCha20Poly1305Data).ByXChaCha20Poly1305(c).ToRawBytes()
+		assert.NotEqual(t, xChaCha20Poly1305Data, encrypted)
+		// XChaCha20-Poly1305 adds 16-byte authentication tag
+		assert.Equal(t, len(xChaCha20Poly1305Data)+16, len(encrypted))
+	})
+
+	t.Run("empty data encrypt", func(t *testing.T) {
+		result := NewEncrypter().FromBytes([]byte{}).ByXChaCha20Poly1305(c)
+		assert.Nil(t, result.Error)
+		assert.Empty(t, result.ToRawBytes())
+	})
+
+	t.Run("encrypt from file", func(t *testing.T) {
+		expected := NewEncrypter().FromBytes(xChaCha20Poly1305Data).ByXChaCha20Poly1305(c).ToRawBytes()
+		result := NewEncrypter().FromFile(mock.NewFile(xChaCha20Poly1305Data, "src")).ByXChaCha20Poly1305(c)
+		assert.Nil(t, result.Error)
+		assert.Equal(t, expected, result.ToRawBytes())
+	})
+
+	t.Run("encrypt with 12-byte nonce", func(t *testing.T) {
+		result := NewEncrypter().FromBytes(xChaCha20Poly1305Data).ByXChaCha20Poly1305(newXChaCha20Poly1305Cipher(nonce[:12]))
+		assert.Equal(t, xchacha20poly1305.InvalidNonceSizeError{Size: 12}, result.Error)
+
+		result = NewEncrypter().FromFile(mock.NewFile(xChaCha20Poly1305Data, "src")).ByXChaCha20Poly1305(newXChaCha20Poly1305Cipher(nonce[:12]))
+		assert.Equal(t, xchacha20poly1305.InvalidNonceSizeError{Size: 12}, result.Error)
+	})
+
+	t.Run("encrypt with invalid key", func(t *testing.T) {
+		invalidCipher := newXChaCha20Poly1305Cipher(nonce)
+		invalidCipher.SetKey([]byte("short"))
+		result := NewEncrypter().FromBytes(xChaCha20Poly1305Data).ByXChaCha20Poly1305(invalidCipher)
+		assert.Equal(t, xchacha20poly1305.KeySizeError(5), result.Error)
+	})
+
+	t.Run("encrypt with error state", func(t *testing.T) {
+		e := NewEncrypter().FromBytes(xChaCha20Poly1305Data)
+		e.Error = assert.AnError
+		assert.Equal(t, assert.AnError, e.ByXChaCha20Poly1305(c).Error)
+	})
+}
+
+func TestDecrypter_ByXChaCha20Poly1305(t *testing.T) {
+	nonce := make([]byte, 24)
+	_, _ = rand.Read(nonce)
+	c := newXChaCha20Poly1305Cipher(nonce)
+	encrypted := NewEncrypter().FromBytes(xChaCha20Poly1305Data).ByXChaCha20Poly1305(c).ToRawBytes()
+
+	t.Run("normal decrypt", func(t *testing.T) {
+		decrypted := NewDecrypter().FromRawBytes(encrypted).ByXChaCha20Poly1305(newXChaCha20Poly1305Cipher(nonce)).ToBytes()
+		assert.Equal(t, xChaCha20Poly1305Data, decrypted)
+	})
+
+	t.Run("decrypt from string", func(t *testing.T) {
+		decrypted := NewDecrypter().FromRawString(string(encrypted)).ByXChaCha20Poly1305(c).ToString()
+		assert.Equal(t, string(xChaCha20Poly1305Data), decrypted)
+	})
+
+	t.Run("decrypt from file", func(t *testing.T) {
+		result := NewDecrypter().FromRawFile(mock.NewFile(encrypted, "src")).ByXChaCha20Poly1305(c)
+		assert.Nil(t, result.Error)
+		assert.Equal(t, xChaCha20Poly1305Data, result.ToBytes())
+	})
+
+	t.Run("empty data decrypt", func(t *testing.T) {
+		result := NewDecrypter().FromRawBytes([]byte{}).ByXChaCha20Poly1305(c)
+		assert.Nil(t, result.Error)
+		assert.Empty(t, result.ToBytes())
+	})
+
+	t.Run("decrypt tampered data", func(t *testing.T) {
+		tampered := bytes.Clone(encrypted)
+		tampered[0] ^= 1
+		result := NewDecrypter().FromRawBytes(tampered).ByXChaCha20Poly1305(c)
+		assert.Equal(t, xchacha20poly1305.AuthenticationError{}, result.Error)
+		assert.Empty(t, result.ToBytes())
+
+		result = NewDecrypter().FromRawFile(mock.NewFile(tampered, "src")).ByXChaCha20Poly1305(c)
+		assert.Equal(t, xchacha20poly1305.AuthenticationError{}, result.Error)
+		assert.Empty(t, result.ToBytes())
+	})
+
+	t.Run("decrypt with different nonce", func(t *testing.T) {
+		other := bytes.Clone(nonce)
+		other[23] ^= 1
+		result := NewDecrypter().FromRawBytes(encrypted).ByXChaCha20Poly1305(newXChaCha20Poly1305Cipher(other))
+		assert.Equal(t, xchacha20poly1305.AuthenticationError{}, result.Error)
+	})
+
+	t.Run("decrypt with error state", func(t *testing.T) {
+		d := NewDecrypter()
+		d.Error = assert.AnError
+		assert.Equal(t, assert.AnError, d.ByXChaCha20Poly1305(c).Error)
+	})
+}
@@ -13,6 +13,13 @@ func (e Encrypter) ByXtea(c *cipher.XteaCipher) Encrypter {
 		return e
 	}
 
+	o := observe(OperationEncrypt, "XTEA", e.src, e.reader)
+	defer o.stop(&e.dst, &e.Error)
+
+	if e.Error = deprecated("XTEA", string(c.Block)); e.Error != nil {
+		return e
+	}
+
 	// Streaming encryption mode
 	if e.reader != nil {
 		e.dst, e.Error = e.stream(func(w io.Writer) io.WriteCloser {
@@ -35,6 +42,13 @@ func (d Decrypter) ByXtea(c *cipher.XteaCipher) Decrypter {
 		return d
 	}
 
+	o := observe(OperationDecrypt, "XTEA", d.src, d.reader)
+	defer o.stop(&d.dst, &d.Error)
+
+	if d.Error = deprecated("XTEA", string(c.Block)); d.Error != nil {
+		return d
+	}
+
 	// Streaming decryption mode
 	if d.reader != nil {
 		d.dst, d.Error = d.stream(func(r io.Reader) io.Reader {
@@ -45,7 +59,7 @@ func (d Decrypter) ByXtea(c *cipher.XteaCipher) Decrypter {
 
 	// Standard decryption mode
 	if len(d.src) > 0 {
-		d.dst, d.Error = xtea.NewStdDecrypter(c).Decrypt(d.src)
+		d.dst, d.Error = d.limit(xtea.NewStdDecrypter(c).Decrypt(d.src))
 	}
 
 	return d
@@ -2,6 +2,8 @@ package xtea
 
 import (
 	"fmt"
+
+	"github.com/dromara/dongle/errcode"
 )
 
 // KeySizeError represents an error when the XTEA key size is invalid.
@@ -15,6 +17,16 @@ func (k KeySizeError) Error() string {
 	return fmt.Sprintf("crypto/xtea: invalid key size %d, must be 16 bytes", k)
 }
 
+// Code returns the stable error code DGL-XTEA-001.
+func (k KeySizeError) Code() string {
+	return "DGL-XTEA-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (k KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xtea", "XTEA", "key", errcode.FieldKeySize, int(k))
+}
+
 // EncryptError represents an error when XTEA encryption operation fails.
 // This error occurs when the encryption process fails due to various reasons.
 type EncryptError struct {
@@ -25,6 +37,16 @@ func (e EncryptError) Error() string {
 	return fmt.Sprintf("crypto/xtea: failed to encrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-XTEA-002.
+func (e EncryptError) Code() string {
+	return "DGL-XTEA-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EncryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xtea", "XTEA", "encrypt", errcode.FieldCause, e.Err)
+}
+
 // DecryptError represents an error when XTEA decryption operation fails.
 // This error occurs when the decryption process fails due to various reasons.
 // The error includes the underlying error for detailed debugging.
@@ -38,6 +60,16 @@ func (e DecryptError) Error() string {
 	return fmt.Sprintf("crypto/xtea: failed to decrypt data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-XTEA-003.
+func (e DecryptError) Code() string {
+	return "DGL-XTEA-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xtea", "XTEA", "decrypt", errcode.FieldCause, e.Err)
+}
+
 // ReadError represents an error when reading encrypted data fails.
 // This error occurs when reading encrypted data from the underlying reader fails.
 // The error includes the underlying error for detailed debugging.
@@ -51,6 +83,16 @@ func (e ReadError) Error() string {
 	return fmt.Sprintf("crypto/xtea: failed to read encrypted data: %v", e.Err)
 }
 
+// Code returns the stable error code DGL-XTEA-004.
+func (e ReadError) Code() string {
+	return "DGL-XTEA-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xtea", "XTEA", "read", errcode.FieldCause, e.Err)
+}
+
 // BufferError represents an error when the buffer size is too small.
 // This error occurs when the provided buffer is too small to hold the decrypted data.
 // The error includes both buffer size and data size for detailed debugging.
@@ -65,6 +107,16 @@ func (e BufferError) Error() string {
 	return fmt.Sprintf("crypto/xtea: buffer size %d is too small for data size %d", e.bufferSize, e.dataSize)
 }
 
+// Code returns the stable error code DGL-XTEA-005.
+func (e BufferError) Code() string {
+	return "DGL-XTEA-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e BufferError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xtea", "XTEA", "", "buffer_size", e.bufferSize, "data_size", e.dataSize)
+}
+
 // UnsupportedBlockModeError represents an error when an unsupported block mode is used.
 type UnsupportedBlockModeError struct {
 	Mode string // The unsupported mode name
@@ -75,3 +127,13 @@ type UnsupportedBlockModeError struct {
 func (e UnsupportedBlockModeError) Error() string {
 	return fmt.Sprintf("crypto/xtea: unsupported block mode '%s', xtea only supports CBC, CTR, ECB, CFB, and OFB modes", e.Mode)
 }
+
+// Code returns the stable error code DGL-XTEA-006.
+func (e UnsupportedBlockModeError) Code() string {
+	return "DGL-XTEA-006"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedBlockModeError) Fields() map[string]any {
+	return errcode.NewFields("crypto/xtea", "XTEA", "", "mode", e.Mode)
+}
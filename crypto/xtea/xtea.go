@@ -28,12 +28,16 @@ func NewStdEncrypter(c *cipher.XteaCipher) *StdEncrypter {
 	}
 	if len(c.Key) != 16 {
 		e.Error = KeySizeError(len(c.Key))
+		return e
 	}
 	// Check for unsupported block mode
 	if c.Block == cipher.GCM {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckGCMOptions(); e.Error != nil {
+		return e
+	}
 	return e
 }
 
@@ -53,6 +57,11 @@ func (e *StdEncrypter) Encrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if e.cipher.MessageKey {
+		return e.cipher.EncryptWithMessageKey(src, newBlock)
+	}
+
 	block, err := xtea.NewCipher(e.cipher.Key)
 	if err != nil {
 		err = EncryptError{Err: err}
@@ -78,12 +87,16 @@ func NewStdDecrypter(c *cipher.XteaCipher) *StdDecrypter {
 	}
 	if len(c.Key) != 16 {
 		d.Error = KeySizeError(len(c.Key))
+		return d
 	}
 	// Check for unsupported block mode
 	if c.Block == cipher.GCM {
 		d.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return d
 	}
+	if d.Error = c.CheckGCMOptions(); d.Error != nil {
+		return d
+	}
 	return d
 }
 
@@ -103,6 +116,11 @@ func (d *StdDecrypter) Decrypt(src []byte) (dst []byte, err error) {
 		return
 	}
 
+	// Use a one-time key derived from the configured key
+	if d.cipher.MessageKey {
+		return d.cipher.DecryptWithMessageKey(src, newBlock)
+	}
+
 	block, err := xtea.NewCipher(d.cipher.Key)
 	if err != nil {
 		err = DecryptError{Err: err}
@@ -141,6 +159,9 @@ func NewStreamEncrypter(w io.Writer, c *cipher.XteaCipher) io.WriteCloser {
 		e.Error = UnsupportedBlockModeError{Mode: "GCM"}
 		return e
 	}
+	if e.Error = c.CheckStreamOptions(); e.Error != nil {
+		return e
+	}
 	e.block, e.Error = xtea.NewCipher(c.Key)
 	return e
 }
@@ -235,6 +256,10 @@ func NewStreamDecrypter(r io.Reader, c *cipher.XteaCipher) io.Reader {
 		return d
 	}
 
+	if d.Error = c.CheckStreamOptions(); d.Error != nil {
+		return d
+	}
+
 	d.block, d.Error = xtea.NewCipher(d.cipher.Key)
 	return d
 }
@@ -292,3 +317,8 @@ func (d *StreamDecrypter) Read(p []byte) (n int, err error) {
 
 	return copied, nil
 }
+
+// newBlock creates a XTEA cipher block from key.
+func newBlock(key []byte) (stdCipher.Block, error) {
+	return xtea.NewCipher(key)
+}
@@ -0,0 +1,119 @@
+// Package deprecation reports the use of deprecated algorithms and block modes
+// by the dongle builders, helping teams find and migrate legacy call sites.
+//
+// MD2, MD4, SHA1, DES and the ECB block mode are deprecated by default. Every
+// time a builder uses one of them, the registered handler receives a Warning:
+//
+//	deprecation.SetHandler(func(w deprecation.Warning) {
+//		log.Warn("deprecated crypto", "algorithm", w.Algorithm, "use", w.Replacement)
+//	})
+//
+// In strict mode the builder fails with the Warning as its error instead of
+// running, which is useful in tests and CI to prevent new uses.
+package deprecation
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	mu         sync.RWMutex
+	handler    func(w Warning)
+	strict     bool
+	deprecated = defaults()
+)
+
+// defaults returns the algorithms deprecated out of the box.
+func defaults() map[string]Warning {
+	list := []Warning{
+		{Algorithm: "MD2", Reason: "practical preimage and collision attacks", Replacement: "SHA-256"},
+		{Algorithm: "MD4", Reason: "practical collision attacks", Replacement: "SHA-256"},
+		{Algorithm: "SHA1", Reason: "practical collision attacks", Replacement: "SHA-256"},
+		{Algorithm: "DES", Reason: "56-bit keys can be brute forced", Replacement: "AES"},
+		{Algorithm: "ECB", Reason: "identical blocks encrypt to identical ciphertext", Replacement: "GCM"},
+	}
+	m := make(map[string]Warning, len(list))
+	for _, w := range list {
+		m[strings.ToUpper(w.Algorithm)] = w
+	}
+	return m
+}
+
+// SetHandler registers fn to receive a Warning every time a deprecated
+// algorithm is used, replacing any previous handler. A nil fn removes it.
+// The handler is called synchronously and must be safe for concurrent use.
+func SetHandler(fn func(w Warning)) {
+	mu.Lock()
+	defer mu.Unlock()
+	handler = fn
+}
+
+// SetStrict enables or disables strict mode, in which using a deprecated
+// algorithm fails with a Warning error.
+func SetStrict(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	strict = enabled
+}
+
+// Deprecate marks an algorithm or block mode as deprecated, such as
+// Warning{Algorithm: "MD5", Replacement: "SHA-256"}. Names are matched case
+// insensitively against the names used by the builders, e.g. "MD5", "RC4",
+// "3DES" or "CBC".
+func Deprecate(w Warning) {
+	mu.Lock()
+	defer mu.Unlock()
+	deprecated[strings.ToUpper(w.Algorithm)] = w
+}
+
+// Allow removes the deprecation of an algorithm or block mode, for example to
+// silence warnings about SHA1 in a system that must keep using it.
+func Allow(algorithm string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(deprecated, strings.ToUpper(algorithm))
+}
+
+// IsDeprecated reports whether an algorithm or block mode is deprecated.
+func IsDeprecated(algorithm string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := deprecated[strings.ToUpper(algorithm)]
+	return ok
+}
+
+// Reset restores the default deprecations and removes the handler and strict
+// mode.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	handler = nil
+	strict = false
+	deprecated = defaults()
+}
+
+// Check reports the use of the named algorithms or block modes. For each one
+// that is deprecated it calls the handler, and in strict mode it returns the
+// Warning of the first one. Builders call it before running an algorithm.
+func Check(algorithms ...string) error {
+	mu.RLock()
+	fn, isStrict := handler, strict
+	var warnings []Warning
+	for _, name := range algorithms {
+		if w, ok := deprecated[strings.ToUpper(name)]; ok && name != "" {
+			warnings = append(warnings, w)
+		}
+	}
+	mu.RUnlock()
+
+	if fn != nil {
+		for _, w := range warnings {
+			fn(w)
+		}
+	}
+	if isStrict && len(warnings) > 0 {
+		return warnings[0]
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package deprecation
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Cleanup(Reset)
+
+	t.Run("defaults", func(t *testing.T) {
+		for _, name := range []string{"MD2", "MD4", "SHA1", "DES", "ECB", "sha1", "ecb"} {
+			assert.True(t, IsDeprecated(name), name)
+		}
+		for _, name := range []string{"SHA256", "AES", "CBC", ""} {
+			assert.False(t, IsDeprecated(name), name)
+		}
+	})
+
+	t.Run("no handler", func(t *testing.T) {
+		assert.NoError(t, Check("SHA1"))
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		t.Cleanup(Reset)
+		var got []Warning
+		SetHandler(func(w Warning) { got = append(got, w) })
+
+		assert.NoError(t, Check("AES", "ECB"))
+		assert.NoError(t, Check("SHA256"))
+		assert.NoError(t, Check("DES", "CBC"))
+		assert.Equal(t, []string{"ECB", "DES"}, []string{got[0].Algorithm, got[1].Algorithm})
+		assert.Len(t, got, 2)
+		assert.Equal(t, "GCM", got[0].Replacement)
+
+		SetHandler(nil)
+		assert.NoError(t, Check("DES"))
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		t.Cleanup(Reset)
+		calls := 0
+		SetHandler(func(w Warning) { calls++ })
+		SetStrict(true)
+
+		err := Check("DES", "ECB")
+		assert.Equal(t, "DES", err.(Warning).Algorithm)
+		assert.Equal(t, 2, calls)
+		assert.NoError(t, Check("AES", "GCM"))
+
+		SetStrict(false)
+		assert.NoError(t, Check("DES"))
+	})
+
+	t.Run("deprecate and allow", func(t *testing.T) {
+		t.Cleanup(Reset)
+		SetStrict(true)
+
+		Deprecate(Warning{Algorithm: "md5", Replacement: "SHA-256"})
+		assert.True(t, IsDeprecated("MD5"))
+		assert.Equal(t, Warning{Algorithm: "md5", Replacement: "SHA-256"}, Check("MD5"))
+
+		Allow("sha1")
+		assert.False(t, IsDeprecated("SHA1"))
+		assert.NoError(t, Check("SHA1"))
+
+		Reset()
+		assert.False(t, IsDeprecated("MD5"))
+		assert.True(t, IsDeprecated("SHA1"))
+		assert.NoError(t, Check("SHA1"))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Cleanup(Reset)
+		var mu sync.Mutex
+		calls := 0
+		SetHandler(func(w Warning) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+		})
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = Check("SHA1")
+				Deprecate(Warning{Algorithm: "MD5"})
+				_ = IsDeprecated("MD5")
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 8, calls)
+	})
+}
+
+func TestWarning(t *testing.T) {
+	w := Warning{Algorithm: "SHA1", Reason: "practical collision attacks", Replacement: "SHA-256"}
+	assert.Equal(t, "deprecation: SHA1 is deprecated (practical collision attacks), use SHA-256 instead", w.Error())
+	assert.Equal(t, "deprecation: RC4 is deprecated", Warning{Algorithm: "RC4"}.Error())
+	assert.Equal(t, "DGL-DEPRECATION-001", w.Code())
+	assert.Equal(t, "SHA1", w.Fields()["algorithm"])
+	assert.Equal(t, "SHA-256", w.Fields()["replacement"])
+}
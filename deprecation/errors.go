@@ -0,0 +1,36 @@
+package deprecation
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// Warning describes a deprecated algorithm. It is passed to the handler and,
+// in strict mode, returned as the error of the builder that used it.
+type Warning struct {
+	Algorithm   string // Algorithm or block mode, e.g. "SHA1" or "ECB"
+	Reason      string // Why it is deprecated
+	Replacement string // Suggested replacement, e.g. "SHA-256"
+}
+
+func (w Warning) Error() string {
+	msg := fmt.Sprintf("deprecation: %s is deprecated", w.Algorithm)
+	if w.Reason != "" {
+		msg += " (" + w.Reason + ")"
+	}
+	if w.Replacement != "" {
+		msg += ", use " + w.Replacement + " instead"
+	}
+	return msg
+}
+
+// Code returns the stable error code DGL-DEPRECATION-001.
+func (w Warning) Code() string {
+	return "DGL-DEPRECATION-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (w Warning) Fields() map[string]any {
+	return errcode.NewFields("deprecation", w.Algorithm, "", "replacement", w.Replacement)
+}
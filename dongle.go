@@ -12,10 +12,17 @@ import (
 	"github.com/dromara/dongle/coding"
 	"github.com/dromara/dongle/crypto"
 	"github.com/dromara/dongle/hash"
+	"github.com/dromara/dongle/internal/utils"
+	"github.com/dromara/dongle/kdf"
 )
 
 const Version = "1.2.3"
 
+// The instances below are shared by every goroutine. Their methods return
+// configured copies, so they must never be assigned to, as in
+// dongle.Hash = dongle.Hash.WithKey(key), which would configure them for every
+// caller. Builds with the dongle_debug tag panic on the first use of a
+// modified instance.
 var (
 	// Encode defines an Encoder instance.
 	Encode = coding.NewEncoder()
@@ -25,6 +32,9 @@ var (
 	// Hash defines a Hasher instance.
 	Hash = hash.NewHasher()
 
+	// Kdf defines a Deriver instance.
+	Kdf = kdf.NewDeriver()
+
 	// Encrypt defines an Encrypter instance.
 	Encrypt = crypto.NewEncrypter()
 	// Decrypt defines a Decrypter instance.
@@ -34,4 +44,34 @@ var (
 	Sign = crypto.NewSigner()
 	// Verify defines a Verifier instance.
 	Verify = crypto.NewVerifier()
+
+	// Protect defines a Protector instance.
+	Protect = crypto.NewProtector()
+	// Unprotect defines an Unprotector instance.
+	Unprotect = crypto.NewUnprotector()
 )
+
+// ConstantTimeEqual reports whether a and b are equal in time independent of
+// their contents, for comparing MACs, digests and other secret values.
+func ConstantTimeEqual(a, b []byte) bool {
+	return utils.ConstantTimeEqual(a, b)
+}
+
+// SecureWipe overwrites b with zeros, for discarding keys and other secrets.
+func SecureWipe(b []byte) {
+	utils.SecureWipe(b)
+}
+
+// Redact masks s except for its first and last n characters, for logging
+// API keys, card numbers and similar identifiers.
+func Redact(s string, n int) string {
+	return utils.Redact(s, n)
+}
+
+// CheckZeroCopy reports whether a byte slice shared with a string by dongle was
+// modified, such as the slice returned by ToRawBytes after calling ToRawString.
+// Conversions are only tracked when built with the dongle_debug tag, so run
+// tests with -tags dongle_debug to catch such misuse.
+func CheckZeroCopy() error {
+	return utils.CheckZeroCopy()
+}
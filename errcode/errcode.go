@@ -0,0 +1,82 @@
+// Package errcode gives dongle errors stable, machine readable codes and metadata.
+//
+// Every error type in dongle implements Coder. Code returns an identifier of the
+// form DGL-<PACKAGE>-<NNN>, e.g. "DGL-AES-001" for crypto/aes.KeySizeError, that
+// never changes once released, and Fields returns a map with the package,
+// algorithm, operation and any sizes or names carried by the error. Services can
+// use them for structured logs and metrics instead of matching on messages:
+//
+//	if _, err := aes.NewStdEncrypter(c).Encrypt(src); err != nil {
+//		log.Error("encrypt failed", "code", errcode.Code(err), "fields", errcode.Fields(err))
+//	}
+//
+// Fields never contain key material, plaintext or other input data.
+package errcode
+
+import "errors"
+
+// Common Fields keys.
+const (
+	FieldPackage   = "package"   // Import path relative to the module, e.g. "crypto/aes"
+	FieldAlgorithm = "algorithm" // Algorithm name, e.g. "AES"
+	FieldOperation = "operation" // Failed operation, e.g. "encrypt", "decrypt", "sign"
+	FieldKeySize   = "key_size"  // Key size in bytes
+	FieldCause     = "cause"     // Message of the underlying error
+)
+
+// Coder is implemented by all dongle error types.
+type Coder interface {
+	error
+	Code() string           // Stable error code, e.g. "DGL-RSA-001"
+	Fields() map[string]any // Metadata for structured logging
+}
+
+// Code returns the code of the first Coder in err's chain, or an empty string
+// when err is not a dongle error.
+func Code(err error) string {
+	var c Coder
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	return ""
+}
+
+// Fields returns the metadata of the first Coder in err's chain, or nil when
+// err is not a dongle error.
+func Fields(err error) map[string]any {
+	var c Coder
+	if errors.As(err, &c) {
+		return c.Fields()
+	}
+	return nil
+}
+
+// NewFields builds the Fields map of an error type from its package, algorithm,
+// operation and further key value pairs. Empty strings, nil values and nil
+// errors are omitted, and errors are stored as their message.
+func NewFields(pkg, algorithm, operation string, kv ...any) map[string]any {
+	fields := make(map[string]any, 3+len(kv)/2)
+	for _, f := range [...][2]string{{FieldPackage, pkg}, {FieldAlgorithm, algorithm}, {FieldOperation, operation}} {
+		if f[1] != "" {
+			fields[f[0]] = f[1]
+		}
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+		switch v := kv[i+1].(type) {
+		case nil:
+		case string:
+			if v != "" {
+				fields[key] = v
+			}
+		case error:
+			fields[key] = v.Error()
+		default:
+			fields[key] = v
+		}
+	}
+	return fields
+}
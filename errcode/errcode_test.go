@@ -0,0 +1,137 @@
+package errcode_test
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/coding/base64"
+	"github.com/dromara/dongle/crypto/aes"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/rsa"
+	"github.com/dromara/dongle/errcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode(t *testing.T) {
+	t.Run("dongle error", func(t *testing.T) {
+		assert.Equal(t, "DGL-AES-001", errcode.Code(aes.KeySizeError(7)))
+		assert.Equal(t, "DGL-RSA-001", errcode.Code(rsa.EncryptError{Err: errors.New("boom")}))
+	})
+
+	t.Run("wrapped error", func(t *testing.T) {
+		err := fmt.Errorf("service: %w", base64.CorruptInputError(3))
+		assert.Equal(t, "DGL-BASE64-002", errcode.Code(err))
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		assert.Equal(t, "", errcode.Code(errors.New("boom")))
+		assert.Equal(t, "", errcode.Code(nil))
+	})
+}
+
+func TestFields(t *testing.T) {
+	t.Run("key size", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			errcode.FieldPackage:   "crypto/aes",
+			errcode.FieldAlgorithm: "AES",
+			errcode.FieldOperation: "key",
+			errcode.FieldKeySize:   7,
+		}, errcode.Fields(aes.KeySizeError(7)))
+	})
+
+	t.Run("cause", func(t *testing.T) {
+		fields := errcode.Fields(fmt.Errorf("wrapped: %w", rsa.DecryptError{Err: errors.New("boom")}))
+		assert.Equal(t, "RSA", fields[errcode.FieldAlgorithm])
+		assert.Equal(t, "decrypt", fields[errcode.FieldOperation])
+		assert.Equal(t, "boom", fields[errcode.FieldCause])
+
+		fields = errcode.Fields(rsa.DecryptError{})
+		assert.NotContains(t, fields, errcode.FieldCause)
+	})
+
+	t.Run("no algorithm", func(t *testing.T) {
+		fields := errcode.Fields(cipher.UnsupportedBlockModeError{})
+		assert.Equal(t, "crypto/cipher", fields[errcode.FieldPackage])
+		assert.NotContains(t, fields, errcode.FieldAlgorithm)
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		assert.Nil(t, errcode.Fields(errors.New("boom")))
+	})
+}
+
+func TestNewFields(t *testing.T) {
+	var nilErr error
+	fields := errcode.NewFields("crypto/x", "", "sign", "size", 32, "name", "", errcode.FieldCause, nilErr, 42, "ignored", "odd")
+	assert.Equal(t, map[string]any{errcode.FieldPackage: "crypto/x", errcode.FieldOperation: "sign", "size": 32}, fields)
+}
+
+// TestAllErrorsHaveCodes checks every error type in the module has Code and
+// Fields methods and that codes are well formed and unique.
+func TestAllErrorsHaveCodes(t *testing.T) {
+	pattern := regexp.MustCompile(`^DGL-[0-9A-Z]+-[0-9]{3}$`)
+	seen := map[string]string{}
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		methods := map[string]map[string]*ast.FuncDecl{}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil {
+				continue
+			}
+			ident, ok := fd.Recv.List[0].Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if methods[ident.Name] == nil {
+				methods[ident.Name] = map[string]*ast.FuncDecl{}
+			}
+			methods[ident.Name][fd.Name.Name] = fd
+		}
+		for name, m := range methods {
+			if !isErrorMethod(m["Error"]) {
+				continue
+			}
+			typ := filepath.Dir(path) + "." + name
+			require.NotNil(t, m["Fields"], "%s has no Fields method", typ)
+			code := m["Code"]
+			require.NotNil(t, code, "%s has no Code method", typ)
+			ret := code.Body.List[0].(*ast.ReturnStmt).Results[0].(*ast.BasicLit)
+			value, _ := strconv.Unquote(ret.Value)
+			assert.Regexp(t, pattern, value, typ)
+			if other, ok := seen[value]; ok {
+				t.Errorf("%s and %s share code %s", other, typ, value)
+			}
+			seen[value] = typ
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, len(seen), 250)
+}
+
+// isErrorMethod reports whether fd is an Error method satisfying the error
+// interface, as opposed to an accessor such as coding.Result.Error.
+func isErrorMethod(fd *ast.FuncDecl) bool {
+	if fd == nil || fd.Type.Results == nil || len(fd.Type.Results.List) != 1 {
+		return false
+	}
+	ident, ok := fd.Type.Results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "string"
+}
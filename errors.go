@@ -0,0 +1,47 @@
+package dongle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// SelfTestError represents an error when one or more self-tests failed.
+type SelfTestError struct {
+	Failed []string // Names of the algorithms that failed
+}
+
+func (e SelfTestError) Error() string {
+	return fmt.Sprintf("dongle: self-test failed for %s", strings.Join(e.Failed, ", "))
+}
+
+// Code returns the stable error code DGL-DONGLE-001.
+func (e SelfTestError) Code() string {
+	return "DGL-DONGLE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SelfTestError) Fields() map[string]any {
+	return errcode.NewFields("dongle", "", "selftest", "failed", strings.Join(e.Failed, ","))
+}
+
+// KnownAnswerError represents an error when an algorithm did not produce the
+// expected output of its known-answer test.
+type KnownAnswerError struct {
+	Algorithm string
+}
+
+func (e KnownAnswerError) Error() string {
+	return fmt.Sprintf("dongle: known-answer test failed for %s", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-DONGLE-002.
+func (e KnownAnswerError) Code() string {
+	return "DGL-DONGLE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KnownAnswerError) Fields() map[string]any {
+	return errcode.NewFields("dongle", e.Algorithm, "selftest")
+}
@@ -0,0 +1,164 @@
+package dongle
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/crypto"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate go run ./internal/cmd/fixtures -out testdata/fixtures
+
+// hashFixture mirrors the hash fixtures written by internal/cmd/fixtures.
+type hashFixture struct {
+	Algorithm string `json:"algorithm"`
+	Reference string `json:"reference"`
+	Key       string `json:"key"`
+	Input     string `json:"input"`
+	Output    string `json:"output"`
+}
+
+// cipherFixture mirrors the cipher fixtures written by internal/cmd/fixtures.
+type cipherFixture struct {
+	Algorithm string `json:"algorithm"`
+	Reference string `json:"reference"`
+	Padding   string `json:"padding"`
+	Key       string `json:"key"`
+	IV        string `json:"iv"`
+	AAD       string `json:"aad"`
+	Input     string `json:"input"`
+	Output    string `json:"output"`
+}
+
+// fixtureHashes maps the fixture algorithm names to hashers.
+var fixtureHashes = map[string]func(h hash.Hasher) hash.Hasher{
+	"md4":         hash.Hasher.ByMd4,
+	"md5":         hash.Hasher.ByMd5,
+	"sha1":        hash.Hasher.BySha1,
+	"sha224":      func(h hash.Hasher) hash.Hasher { return h.BySha2(224) },
+	"sha256":      func(h hash.Hasher) hash.Hasher { return h.BySha2(256) },
+	"sha384":      func(h hash.Hasher) hash.Hasher { return h.BySha2(384) },
+	"sha512":      func(h hash.Hasher) hash.Hasher { return h.BySha2(512) },
+	"sha3-224":    func(h hash.Hasher) hash.Hasher { return h.BySha3(224) },
+	"sha3-256":    func(h hash.Hasher) hash.Hasher { return h.BySha3(256) },
+	"sha3-384":    func(h hash.Hasher) hash.Hasher { return h.BySha3(384) },
+	"sha3-512":    func(h hash.Hasher) hash.Hasher { return h.BySha3(512) },
+	"ripemd160":   hash.Hasher.ByRipemd160,
+	"sm3":         hash.Hasher.BySm3,
+	"blake2b-512": func(h hash.Hasher) hash.Hasher { return h.ByBlake2b(512) },
+	"blake2s-256": func(h hash.Hasher) hash.Hasher { return h.ByBlake2s(256) },
+}
+
+// fixtureCipher is a cipher of the fixtures configured from a fixture.
+type fixtureCipher struct {
+	encrypt func(e crypto.Encrypter) crypto.Encrypter
+	decrypt func(d crypto.Decrypter) crypto.Decrypter
+}
+
+// newFixtureCipher returns the dongle cipher for fixture f.
+func newFixtureCipher(t *testing.T, f cipherFixture) fixtureCipher {
+	name := f.Algorithm
+	mode := cipher.BlockMode(strings.ToUpper(name[strings.LastIndex(name, "-")+1:]))
+	key, iv, aad := unhexFixture(t, f.Key), unhexFixture(t, f.IV), unhexFixture(t, f.AAD)
+	padding := cipher.No
+	if f.Padding == "pkcs7" {
+		padding = cipher.PKCS7
+	}
+	configure := func(c interface {
+		SetKey([]byte)
+		SetIV([]byte)
+		SetNonce([]byte)
+		SetAAD([]byte)
+		SetPadding(cipher.PaddingMode)
+	}) {
+		c.SetKey(key)
+		c.SetPadding(padding)
+		if mode == cipher.GCM {
+			c.SetNonce(iv)
+			c.SetAAD(aad)
+		} else {
+			c.SetIV(iv)
+		}
+	}
+	switch {
+	case strings.HasPrefix(name, "aes-"):
+		c := cipher.NewAesCipher(mode)
+		configure(c)
+		return fixtureCipher{func(e crypto.Encrypter) crypto.Encrypter { return e.ByAes(c) }, func(d crypto.Decrypter) crypto.Decrypter { return d.ByAes(c) }}
+	case strings.HasPrefix(name, "des-ede3-"):
+		c := cipher.New3DesCipher(mode)
+		configure(c)
+		return fixtureCipher{func(e crypto.Encrypter) crypto.Encrypter { return e.By3Des(c) }, func(d crypto.Decrypter) crypto.Decrypter { return d.By3Des(c) }}
+	case strings.HasPrefix(name, "des-"):
+		c := cipher.NewDesCipher(mode)
+		configure(c)
+		return fixtureCipher{func(e crypto.Encrypter) crypto.Encrypter { return e.ByDes(c) }, func(d crypto.Decrypter) crypto.Decrypter { return d.ByDes(c) }}
+	case strings.HasPrefix(name, "sm4-"):
+		c := cipher.NewSm4Cipher(mode)
+		configure(c)
+		return fixtureCipher{func(e crypto.Encrypter) crypto.Encrypter { return e.BySm4(c) }, func(d crypto.Decrypter) crypto.Decrypter { return d.BySm4(c) }}
+	}
+	t.Fatalf("no cipher for fixture algorithm %q", name)
+	return fixtureCipher{}
+}
+
+func TestFixtures_Hash(t *testing.T) {
+	var fixtures []hashFixture
+	loadFixtures(t, "hash.json", &fixtures)
+
+	for _, f := range fixtures {
+		name := strings.TrimPrefix(f.Algorithm, "hmac-")
+		by, ok := fixtureHashes[name]
+		require.True(t, ok, "no hasher for fixture algorithm %q", f.Algorithm)
+
+		hasher := hash.NewHasher().FromBytes(unhexFixture(t, f.Input))
+		if f.Key != "" {
+			hasher = hasher.WithKey(unhexFixture(t, f.Key))
+		}
+		hasher = by(hasher)
+		require.NoError(t, hasher.Error, f.Algorithm)
+		assert.Equal(t, f.Output, hasher.ToHexString(), "%s of %s (%s)", f.Algorithm, f.Input, f.Reference)
+	}
+}
+
+func TestFixtures_Cipher(t *testing.T) {
+	var fixtures []cipherFixture
+	loadFixtures(t, "cipher.json", &fixtures)
+
+	for _, f := range fixtures {
+		c := newFixtureCipher(t, f)
+		input, output := unhexFixture(t, f.Input), unhexFixture(t, f.Output)
+
+		encrypter := c.encrypt(crypto.NewEncrypter().FromBytes(input))
+		require.NoError(t, encrypter.Error, f.Algorithm)
+		assert.Equal(t, f.Output, hex.EncodeToString(encrypter.ToRawBytes()), "%s of %s (%s)", f.Algorithm, f.Input, f.Reference)
+
+		decrypter := c.decrypt(crypto.NewDecrypter().FromRawBytes(output))
+		require.NoError(t, decrypter.Error, f.Algorithm)
+		assert.Equal(t, input, decrypter.ToBytes(), f.Algorithm)
+	}
+}
+
+// loadFixtures decodes the fixture file name into v.
+func loadFixtures(t *testing.T, name string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "fixtures", name))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
+
+// unhexFixture decodes a hex string of a fixture.
+func unhexFixture(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,39 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"hash"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// HashBatch returns the digest of every item, computed with hashes created by
+// fn such as sha256.New. Each worker creates one hash and resets it between
+// items, so hashing millions of short items avoids the allocations of a
+// Hasher chain per item. With workers of 1 or less the items are hashed on the
+// calling goroutine.
+func HashBatch(fn func() hash.Hash, items [][]byte, workers int) [][]byte {
+	return batch(fn, items, workers)
+}
+
+// HmacBatch is like HashBatch but returns the HMAC of every item with key.
+func HmacBatch(fn func() hash.Hash, key []byte, items [][]byte, workers int) [][]byte {
+	return batch(func() hash.Hash { return hmac.New(fn, key) }, items, workers)
+}
+
+// batch sums items with one hash per worker.
+func batch(fn func() hash.Hash, items [][]byte, workers int) [][]byte {
+	sums := make([][]byte, len(items))
+	hashers := make([]hash.Hash, max(workers, 1))
+	utils.Batch(len(items), workers, func(worker, i int) {
+		h := hashers[worker]
+		if h == nil {
+			h = fn()
+			hashers[worker] = h
+		}
+		h.Reset()
+		h.Write(items[i])
+		sums[i] = h.Sum(nil)
+	})
+	return sums
+}
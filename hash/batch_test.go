@@ -0,0 +1,46 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashBatch(t *testing.T) {
+	items := make([][]byte, 100)
+	for i := range items {
+		items[i] = []byte(fmt.Sprintf("event-%d", i))
+	}
+	items[7] = nil
+
+	for _, workers := range []int{0, 1, 8} {
+		sums := HashBatch(sha256.New, items, workers)
+		assert.Len(t, sums, len(items))
+		for i, item := range items {
+			want := sha256.Sum256(item)
+			assert.Equal(t, want[:], sums[i])
+		}
+	}
+
+	sums := HashBatch(sm3.New, items[:1], 1)
+	assert.Equal(t, NewHasher().FromBytes(items[0]).BySm3().ToRawBytes(), sums[0])
+	assert.Empty(t, HashBatch(md5.New, nil, 4))
+}
+
+func TestHmacBatch(t *testing.T) {
+	key := []byte("webhook secret")
+	items := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	for _, workers := range []int{1, 3} {
+		macs := HmacBatch(sha256.New, key, items, workers)
+		for i, item := range items {
+			mac := hmac.New(sha256.New, key)
+			mac.Write(item)
+			assert.Equal(t, mac.Sum(nil), macs[i])
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package bloom implements a Bloom filter whose probe positions are derived from dongle hashes.
+// Positions are computed with enhanced double hashing over a single digest, the digest
+// function is configurable and can be keyed with HMAC so that an attacker who does not
+// know the key cannot craft inputs that saturate the filter.
+package bloom
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"hash"
+	"math"
+	"math/bits"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// magic identifies the serialized filter format.
+var magic = [4]byte{'D', 'B', 'F', '1'}
+
+// headerSize is the size of the serialized header: magic, k, m and count.
+const headerSize = 4 + 4 + 8 + 8
+
+// Filter represents a Bloom filter.
+// A Filter is not safe for concurrent mutation.
+type Filter struct {
+	m     uint64           // Number of bits
+	k     uint64           // Number of probe positions per element
+	n     uint64           // Number of added elements
+	bits  []uint64         // Bit array
+	hash  func() hash.Hash // Digest function used to derive positions
+	key   []byte           // Optional HMAC key
+	Error error            // Error field for storing configuration errors
+}
+
+// New returns a Filter sized for capacity elements at the target false positive rate.
+func New(capacity int, rate float64) *Filter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if rate <= 0 || rate >= 1 {
+		f := NewWithSize(1, 1)
+		f.Error = InvalidRateError(rate)
+		return f
+	}
+	m, k := EstimateParameters(uint64(capacity), rate)
+	return NewWithSize(m, k)
+}
+
+// NewWithSize returns a Filter with m bits and k probe positions per element.
+func NewWithSize(m, k uint64) *Filter {
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	}
+	return &Filter{
+		m:    m,
+		k:    k,
+		bits: make([]uint64, (m+63)/64),
+		hash: defaultHash,
+	}
+}
+
+// EstimateParameters returns the optimal number of bits m and probe
+// positions k for n elements at the false positive rate p.
+func EstimateParameters(n uint64, p float64) (m, k uint64) {
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	}
+	return
+}
+
+// SetHash sets the digest function used to derive probe positions.
+// The digest must be at least 16 bytes long, the default is BLAKE2b-256.
+func (f *Filter) SetHash(fn func() hash.Hash) {
+	if fn == nil || fn().Size() < 16 {
+		f.Error = UnsupportedHashError{}
+		return
+	}
+	f.hash = fn
+}
+
+// SetKey sets the HMAC key used to derive probe positions.
+func (f *Filter) SetKey(key []byte) {
+	f.key = key
+}
+
+// Add adds data to the filter.
+func (f *Filter) Add(data []byte) {
+	h1, h2 := f.digest(data)
+	for i := uint64(0); i < f.k; i++ {
+		pos := f.position(h1, h2, i)
+		f.bits[pos/64] |= 1 << (pos % 64)
+	}
+	f.n++
+}
+
+// AddString adds a string to the filter.
+func (f *Filter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Contains reports whether data may have been added to the filter.
+// False positives are possible, false negatives are not.
+func (f *Filter) Contains(data []byte) bool {
+	h1, h2 := f.digest(data)
+	for i := uint64(0); i < f.k; i++ {
+		pos := f.position(h1, h2, i)
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsString reports whether a string may have been added to the filter.
+func (f *Filter) ContainsString(s string) bool {
+	return f.Contains([]byte(s))
+}
+
+// TestAndAdd reports whether data may have been added before, then adds it.
+func (f *Filter) TestAndAdd(data []byte) bool {
+	present := f.Contains(data)
+	f.Add(data)
+	return present
+}
+
+// Bits returns the number of bits of the filter.
+func (f *Filter) Bits() uint64 { return f.m }
+
+// Hashes returns the number of probe positions per element.
+func (f *Filter) Hashes() uint64 { return f.k }
+
+// Count returns the number of elements added to the filter.
+func (f *Filter) Count() uint64 { return f.n }
+
+// FalsePositiveRate returns the estimated false positive rate for the current count.
+func (f *Filter) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(f.k)*float64(f.n)/float64(f.m)), float64(f.k))
+}
+
+// Merge adds all elements of other into the filter.
+// Both filters must share the same size, probe count, digest function and key.
+func (f *Filter) Merge(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return IncompatibleFilterError{}
+	}
+	for i := range f.bits {
+		f.bits[i] |= other.bits[i]
+	}
+	f.n += other.n
+	return nil
+}
+
+// Reset clears the filter.
+func (f *Filter) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+	f.n = 0
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The digest function and key are not serialized, the receiving side must
+// configure them identically.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	dst := make([]byte, headerSize, headerSize+len(f.bits)*8)
+	copy(dst, magic[:])
+	binary.BigEndian.PutUint32(dst[4:], uint32(f.k))
+	binary.BigEndian.PutUint64(dst[8:], f.m)
+	binary.BigEndian.PutUint64(dst[16:], f.n)
+	for _, word := range f.bits {
+		dst = binary.BigEndian.AppendUint64(dst, word)
+	}
+	return dst, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// The digest function and key of the receiver are kept.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize || [4]byte(data[:4]) != magic {
+		return InvalidDataError{}
+	}
+	k := uint64(binary.BigEndian.Uint32(data[4:]))
+	m := binary.BigEndian.Uint64(data[8:])
+	n := binary.BigEndian.Uint64(data[16:])
+	// Bound m by the payload before rounding it up, which would overflow
+	if k == 0 || m == 0 || m > uint64(len(data)-headerSize)*8 {
+		return InvalidDataError{}
+	}
+	words := (m + 63) / 64
+	if uint64(len(data)-headerSize) != words*8 {
+		return InvalidDataError{}
+	}
+
+	f.k, f.m, f.n = k, m, n
+	f.bits = make([]uint64, words)
+	for i := range f.bits {
+		f.bits[i] = binary.BigEndian.Uint64(data[headerSize+i*8:])
+	}
+	if f.hash == nil {
+		f.hash = defaultHash
+	}
+	return nil
+}
+
+// digest returns the two 64-bit values the probe positions are derived from.
+func (f *Filter) digest(data []byte) (h1, h2 uint64) {
+	var h hash.Hash
+	if len(f.key) > 0 {
+		h = hmac.New(f.hash, f.key)
+	} else {
+		h = f.hash()
+	}
+	h.Write(data)
+	sum := h.Sum(nil)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16]) | 1
+}
+
+// position returns the i-th probe position using enhanced double hashing.
+func (f *Filter) position(h1, h2, i uint64) uint64 {
+	_, rem := bits.Div64(0, h1+i*h2+(i*i*i-i)/6, f.m)
+	return rem
+}
+
+// defaultHash returns a BLAKE2b-256 hash.
+func defaultHash() hash.Hash {
+	h, _ := blake2b.New256(nil)
+	return h
+}
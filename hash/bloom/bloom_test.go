@@ -0,0 +1,157 @@
+package bloom
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	stdhash "hash"
+	"testing"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("estimated parameters", func(t *testing.T) {
+		f := New(1000, 0.01)
+		assert.Nil(t, f.Error)
+		assert.Equal(t, uint64(9586), f.Bits())
+		assert.Equal(t, uint64(7), f.Hashes())
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		f := New(1000, 0)
+		assert.IsType(t, InvalidRateError(0), f.Error)
+		assert.Contains(t, New(1000, 1).Error.Error(), "invalid false positive rate 1")
+	})
+
+	t.Run("minimum size", func(t *testing.T) {
+		f := NewWithSize(0, 0)
+		assert.Equal(t, uint64(1), f.Bits())
+		assert.Equal(t, uint64(1), f.Hashes())
+		assert.NotNil(t, New(0, 0.5))
+	})
+}
+
+func TestFilter_AddContains(t *testing.T) {
+	t.Run("no false negatives", func(t *testing.T) {
+		f := New(1000, 0.01)
+		for i := 0; i < 1000; i++ {
+			f.AddString(fmt.Sprintf("item-%d", i))
+		}
+		for i := 0; i < 1000; i++ {
+			assert.True(t, f.ContainsString(fmt.Sprintf("item-%d", i)))
+		}
+		assert.Equal(t, uint64(1000), f.Count())
+	})
+
+	t.Run("false positive rate", func(t *testing.T) {
+		f := New(1000, 0.01)
+		for i := 0; i < 1000; i++ {
+			f.AddString(fmt.Sprintf("item-%d", i))
+		}
+		positives := 0
+		for i := 0; i < 10000; i++ {
+			if f.ContainsString(fmt.Sprintf("other-%d", i)) {
+				positives++
+			}
+		}
+		assert.Less(t, positives, 300)
+		assert.InDelta(t, 0.01, f.FalsePositiveRate(), 0.005)
+	})
+
+	t.Run("test and add", func(t *testing.T) {
+		f := New(10, 0.01)
+		assert.False(t, f.TestAndAdd([]byte("a")))
+		assert.True(t, f.TestAndAdd([]byte("a")))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		f := New(10, 0.01)
+		f.AddString("a")
+		f.Reset()
+		assert.False(t, f.ContainsString("a"))
+		assert.Equal(t, uint64(0), f.Count())
+	})
+}
+
+func TestFilter_SetHash(t *testing.T) {
+	t.Run("custom hashes", func(t *testing.T) {
+		for _, fn := range []func() stdhash.Hash{sha256.New, sha1.New, md5.New, sm3.New} {
+			f := New(100, 0.01)
+			f.SetHash(fn)
+			assert.Nil(t, f.Error)
+			f.AddString("dongle")
+			assert.True(t, f.ContainsString("dongle"))
+		}
+	})
+
+	t.Run("unsupported hash", func(t *testing.T) {
+		f := New(100, 0.01)
+		f.SetHash(nil)
+		assert.IsType(t, UnsupportedHashError{}, f.Error)
+		assert.Equal(t, "hash/bloom: hash function must produce at least 16 bytes", f.Error.Error())
+	})
+}
+
+func TestFilter_SetKey(t *testing.T) {
+	plain := NewWithSize(1024, 3)
+	keyed := NewWithSize(1024, 3)
+	keyed.SetKey([]byte("dongle"))
+	plain.AddString("hello")
+	keyed.AddString("hello")
+	assert.True(t, keyed.ContainsString("hello"))
+	assert.NotEqual(t, plain.bits, keyed.bits)
+}
+
+func TestFilter_Merge(t *testing.T) {
+	a, b := New(100, 0.01), New(100, 0.01)
+	a.AddString("a")
+	b.AddString("b")
+	assert.Nil(t, a.Merge(b))
+	assert.True(t, a.ContainsString("a"))
+	assert.True(t, a.ContainsString("b"))
+	assert.Equal(t, uint64(2), a.Count())
+
+	err := a.Merge(New(1000, 0.01))
+	assert.IsType(t, IncompatibleFilterError{}, err)
+	assert.Equal(t, "hash/bloom: filters must have the same number of bits and hashes", err.Error())
+}
+
+func TestFilter_Binary(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		f := New(100, 0.01)
+		f.AddString("dongle")
+		data, err := f.MarshalBinary()
+		assert.Nil(t, err)
+
+		restored := &Filter{}
+		assert.Nil(t, restored.UnmarshalBinary(data))
+		assert.True(t, restored.ContainsString("dongle"))
+		assert.Equal(t, f.Bits(), restored.Bits())
+		assert.Equal(t, f.Hashes(), restored.Hashes())
+		assert.Equal(t, f.Count(), restored.Count())
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		f := New(100, 0.01)
+		data, _ := f.MarshalBinary()
+		for _, bad := range [][]byte{nil, data[:10], append([]byte("XXXX"), data[4:]...), data[:len(data)-1]} {
+			err := f.UnmarshalBinary(bad)
+			assert.IsType(t, InvalidDataError{}, err)
+		}
+		assert.Equal(t, "hash/bloom: invalid serialized filter data", InvalidDataError{}.Error())
+	})
+
+	t.Run("oversized bit count", func(t *testing.T) {
+		header := make([]byte, headerSize)
+		copy(header, magic[:])
+		binary.BigEndian.PutUint32(header[4:], 3)
+		binary.BigEndian.PutUint64(header[8:], ^uint64(0))
+		f := &Filter{}
+		assert.IsType(t, InvalidDataError{}, f.UnmarshalBinary(header))
+		assert.IsType(t, InvalidDataError{}, f.UnmarshalBinary(append(header, make([]byte, 8)...)))
+	})
+}
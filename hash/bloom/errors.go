@@ -0,0 +1,81 @@
+package bloom
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidRateError represents an error when the target false positive rate is out of range.
+// The rate must be strictly between 0 and 1.
+type InvalidRateError float64
+
+// Error returns a formatted error message describing the invalid rate.
+func (e InvalidRateError) Error() string {
+	return fmt.Sprintf("hash/bloom: invalid false positive rate %v, must be between 0 and 1", float64(e))
+}
+
+// Code returns the stable error code DGL-BLOOM-001.
+func (e InvalidRateError) Code() string {
+	return "DGL-BLOOM-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidRateError) Fields() map[string]any {
+	return errcode.NewFields("hash/bloom", "Bloom", "", "rate", float64(e))
+}
+
+// UnsupportedHashError represents an error when the digest function is unusable.
+// Probe positions need at least 16 bytes of digest output.
+type UnsupportedHashError struct{}
+
+// Error returns a formatted error message describing the unusable digest function.
+func (e UnsupportedHashError) Error() string {
+	return "hash/bloom: hash function must produce at least 16 bytes"
+}
+
+// Code returns the stable error code DGL-BLOOM-002.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-BLOOM-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("hash/bloom", "Bloom", "")
+}
+
+// IncompatibleFilterError represents an error when merging filters of different shapes.
+type IncompatibleFilterError struct{}
+
+// Error returns a formatted error message describing the incompatible filters.
+func (e IncompatibleFilterError) Error() string {
+	return "hash/bloom: filters must have the same number of bits and hashes"
+}
+
+// Code returns the stable error code DGL-BLOOM-003.
+func (e IncompatibleFilterError) Code() string {
+	return "DGL-BLOOM-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e IncompatibleFilterError) Fields() map[string]any {
+	return errcode.NewFields("hash/bloom", "Bloom", "")
+}
+
+// InvalidDataError represents an error when serialized filter data is malformed.
+type InvalidDataError struct{}
+
+// Error returns a formatted error message describing the malformed data.
+func (e InvalidDataError) Error() string {
+	return "hash/bloom: invalid serialized filter data"
+}
+
+// Code returns the stable error code DGL-BLOOM-004.
+func (e InvalidDataError) Code() string {
+	return "DGL-BLOOM-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidDataError) Fields() map[string]any {
+	return errcode.NewFields("hash/bloom", "Bloom", "")
+}
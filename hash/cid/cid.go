@@ -0,0 +1,252 @@
+// Package cid derives self-describing content identifiers in the format of
+// IPFS CIDs, so storage systems built on dongle can address content with
+// identifiers that name their own hash algorithm and stay readable after the
+// algorithm is replaced.
+//
+// A multihash is the multicodec code of the hash algorithm and the digest size,
+// both as unsigned varints, followed by the digest. A version 1 CID is the
+// version, the multicodec code of the content type and the multihash, written
+// with a multibase prefix: 'b' for lowercase unpadded base32, the default, or
+// 'z' for base58btc:
+//
+//	c, err := cid.FromBytes([]byte("hello world"), cid.SHA256)
+//	c.String() // "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e"
+//
+// Version 0 CIDs, the bare base58btc SHA-256 multihashes starting with "Qm",
+// are parsed as well.
+package cid
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/dromara/dongle/coding/base58"
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// Algorithm describes a hash algorithm by its multicodec code.
+type Algorithm struct {
+	Name string           // Multicodec name
+	Code uint64           // Multicodec code
+	New  func() hash.Hash // Constructor of the underlying hash
+}
+
+// Supported hash algorithms.
+var (
+	SHA1       = Algorithm{Name: "sha1", Code: 0x11, New: sha1.New}
+	SHA256     = Algorithm{Name: "sha2-256", Code: 0x12, New: sha256.New}
+	SHA512     = Algorithm{Name: "sha2-512", Code: 0x13, New: sha512.New}
+	SHA3_256   = Algorithm{Name: "sha3-256", Code: 0x16, New: sha3.New256}
+	SHA3_512   = Algorithm{Name: "sha3-512", Code: 0x14, New: sha3.New512}
+	BLAKE2b256 = Algorithm{Name: "blake2b-256", Code: 0xb220, New: func() hash.Hash {
+		h, _ := blake2b.New256(nil)
+		return h
+	}}
+	SM3 = Algorithm{Name: "sm3-256", Code: 0x534d, New: sm3.New}
+)
+
+// algorithms maps multicodec codes to algorithms for verification.
+var algorithms = map[uint64]Algorithm{
+	SHA1.Code:       SHA1,
+	SHA256.Code:     SHA256,
+	SHA512.Code:     SHA512,
+	SHA3_256.Code:   SHA3_256,
+	SHA3_512.Code:   SHA3_512,
+	BLAKE2b256.Code: BLAKE2b256,
+	SM3.Code:        SM3,
+}
+
+// Register makes an algorithm available to Verify under its code.
+func Register(a Algorithm) {
+	algorithms[a.Code] = a
+}
+
+// Content type codes of the multicodec table.
+const (
+	Raw     uint64 = 0x55 // Raw bytes, the content type of FromReader
+	DagPB   uint64 = 0x70 // MerkleDAG protobuf, the content type of version 0 CIDs
+	DagCBOR uint64 = 0x71 // MerkleDAG CBOR
+)
+
+// base32Encoding is the lowercase unpadded base32 of the 'b' multibase.
+var base32Encoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// CID is a content identifier.
+type CID struct {
+	Version   int    // 0 or 1
+	Codec     uint64 // Multicodec code of the content type
+	Multihash []byte // Multihash of the content
+}
+
+// FromBytes returns the version 1 CID of raw content.
+func FromBytes(b []byte, a Algorithm) (CID, error) {
+	return FromReader(bytes.NewReader(b), a)
+}
+
+// FromReader returns the version 1 CID of the raw content read from r.
+func FromReader(r io.Reader, a Algorithm) (CID, error) {
+	digest, err := sum(r, a)
+	if err != nil {
+		return CID{}, err
+	}
+	return CID{Version: 1, Codec: Raw, Multihash: Multihash(a, digest)}, nil
+}
+
+// Multihash returns the multihash of a digest computed by the algorithm.
+func Multihash(a Algorithm, digest []byte) []byte {
+	mh := binary.AppendUvarint(nil, a.Code)
+	mh = binary.AppendUvarint(mh, uint64(len(digest)))
+	return append(mh, digest...)
+}
+
+// DecodeMultihash splits a multihash into the code of its algorithm and its digest.
+func DecodeMultihash(mh []byte) (code uint64, digest []byte, err error) {
+	code, n := binary.Uvarint(mh)
+	if n <= 0 {
+		return 0, nil, InvalidCIDError{Reason: "truncated multihash code"}
+	}
+	size, m := binary.Uvarint(mh[n:])
+	if m <= 0 || size != uint64(len(mh)-n-m) {
+		return 0, nil, InvalidCIDError{Reason: "multihash size does not match its digest"}
+	}
+	return code, mh[n+m:], nil
+}
+
+// Bytes returns the binary form of the CID, without multibase prefix.
+func (c CID) Bytes() []byte {
+	if c.Version == 0 {
+		return append([]byte(nil), c.Multihash...)
+	}
+	b := binary.AppendUvarint(nil, uint64(c.Version))
+	b = binary.AppendUvarint(b, c.Codec)
+	return append(b, c.Multihash...)
+}
+
+// String returns the CID in base32 with the 'b' multibase prefix, or in bare
+// base58btc for version 0.
+func (c CID) String() string {
+	if c.Version == 0 {
+		return string(base58.NewStdEncoder().Encode(c.Multihash))
+	}
+	return "b" + base32Encoding.EncodeToString(c.Bytes())
+}
+
+// Base58 returns the CID in base58btc with the 'z' multibase prefix, or in bare
+// base58btc for version 0.
+func (c CID) Base58() string {
+	if c.Version == 0 {
+		return c.String()
+	}
+	return "z" + string(base58.NewStdEncoder().Encode(c.Bytes()))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c CID) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *CID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// Equal reports whether both CIDs identify the same content in the same way.
+func (c CID) Equal(o CID) bool {
+	return c.Version == o.Version && c.Codec == o.Codec && bytes.Equal(c.Multihash, o.Multihash)
+}
+
+// Verify reads r to the end and checks that its digest matches the multihash
+// of the CID. It returns an UnsupportedAlgorithmError when the algorithm of the
+// multihash is not registered and a MismatchError when the content differs.
+func (c CID) Verify(r io.Reader) error {
+	code, digest, err := DecodeMultihash(c.Multihash)
+	if err != nil {
+		return err
+	}
+	a, ok := algorithms[code]
+	if !ok {
+		return UnsupportedAlgorithmError{Multicodec: code}
+	}
+	actual, err := sum(r, a)
+	if err != nil {
+		return err
+	}
+	if len(actual) != len(digest) || !utils.ConstantTimeEqual(actual, digest) {
+		return MismatchError{CID: c.String()}
+	}
+	return nil
+}
+
+// Parse parses a CID in any of the forms written by String and Base58.
+func Parse(s string) (CID, error) {
+	if len(s) == 46 && strings.HasPrefix(s, "Qm") {
+		mh, err := base58.NewStdDecoder().Decode([]byte(s))
+		if err != nil {
+			return CID{}, InvalidCIDError{Reason: "invalid base58btc"}
+		}
+		if code, digest, err := DecodeMultihash(mh); err != nil || code != SHA256.Code || len(digest) != sha256.Size {
+			return CID{}, InvalidCIDError{Reason: "version 0 requires a sha2-256 multihash"}
+		}
+		return CID{Version: 0, Codec: DagPB, Multihash: mh}, nil
+	}
+	if s == "" {
+		return CID{}, InvalidCIDError{Reason: "empty"}
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	switch s[0] {
+	case 'b':
+		b, err = base32Encoding.DecodeString(s[1:])
+	case 'z':
+		b, err = base58.NewStdDecoder().Decode([]byte(s[1:]))
+	default:
+		return CID{}, InvalidCIDError{Reason: "unsupported multibase prefix " + s[:1]}
+	}
+	if err != nil || len(b) == 0 {
+		return CID{}, InvalidCIDError{Reason: "invalid multibase encoding"}
+	}
+
+	version, n := binary.Uvarint(b)
+	if n <= 0 || version != 1 {
+		return CID{}, InvalidCIDError{Reason: "unsupported version"}
+	}
+	codec, m := binary.Uvarint(b[n:])
+	if m <= 0 {
+		return CID{}, InvalidCIDError{Reason: "truncated codec"}
+	}
+	mh := b[n+m:]
+	if _, _, err = DecodeMultihash(mh); err != nil {
+		return CID{}, err
+	}
+	return CID{Version: 1, Codec: codec, Multihash: mh}, nil
+}
+
+// sum returns the digest of the content read from r.
+func sum(r io.Reader, a Algorithm) ([]byte, error) {
+	if a.New == nil {
+		return nil, UnsupportedAlgorithmError{Multicodec: a.Code}
+	}
+	h := a.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, ReadError{Err: err}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,111 @@
+package cid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	cidSrc    = []byte("hello world")
+	cidBase32 = "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e"
+	cidBase58 = "zb2rhj7crUKTQYRGCRATFaQ6YFLTde2YzdqbbhAASkL9uRDXn"
+	cidV0     = "QmaozNR7DZHQK1ZcU9p7QdrshMvXqWK6gpu5rmrkPdT3L4"
+)
+
+func TestFromReader(t *testing.T) {
+	c, err := FromBytes(cidSrc, SHA256)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Version)
+	assert.Equal(t, Raw, c.Codec)
+	assert.Equal(t, cidBase32, c.String())
+	assert.Equal(t, cidBase58, c.Base58())
+
+	for _, a := range []Algorithm{SHA1, SHA512, SHA3_256, SHA3_512, BLAKE2b256, SM3} {
+		c, err = FromReader(bytes.NewReader(cidSrc), a)
+		require.NoError(t, err, a.Name)
+		code, digest, err := DecodeMultihash(c.Multihash)
+		require.NoError(t, err)
+		assert.Equal(t, a.Code, code)
+		assert.Len(t, digest, a.New().Size())
+		assert.NoError(t, c.Verify(bytes.NewReader(cidSrc)), a.Name)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := FromBytes(cidSrc, Algorithm{Code: 0x99})
+		assert.Equal(t, UnsupportedAlgorithmError{Multicodec: 0x99}, err)
+		failure := errors.New("read failed")
+		_, err = FromReader(mock.NewErrorFile(failure), SHA256)
+		assert.Equal(t, ReadError{Err: failure}, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	want, err := FromBytes(cidSrc, SHA256)
+	require.NoError(t, err)
+
+	for _, s := range []string{cidBase32, cidBase58} {
+		c, err := Parse(s)
+		require.NoError(t, err, s)
+		assert.True(t, want.Equal(c), s)
+	}
+
+	v0, err := Parse(cidV0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, v0.Version)
+	assert.Equal(t, DagPB, v0.Codec)
+	assert.Equal(t, want.Multihash, v0.Multihash)
+	assert.Equal(t, cidV0, v0.String())
+	assert.Equal(t, cidV0, v0.Base58())
+	assert.Equal(t, want.Multihash, v0.Bytes())
+	assert.False(t, want.Equal(v0))
+
+	for _, s := range []string{"", "x123", "b", "b!!!", "z0OIl", "bahaaa", "bafkrei", cidBase32[:len(cidBase32)-4], "Qm" + strings.Repeat("0", 44)} {
+		_, err = Parse(s)
+		assert.ErrorAs(t, err, &InvalidCIDError{}, s)
+	}
+	assert.Equal(t, "hash/cid: invalid cid: empty", InvalidCIDError{Reason: "empty"}.Error())
+}
+
+func TestVerify(t *testing.T) {
+	c, err := Parse(cidBase32)
+	require.NoError(t, err)
+	assert.NoError(t, c.Verify(bytes.NewReader(cidSrc)))
+
+	err = c.Verify(bytes.NewReader([]byte("hello world!")))
+	assert.Equal(t, MismatchError{CID: cidBase32}, err)
+	assert.Equal(t, "hash/cid: content does not match "+cidBase32, err.Error())
+
+	failure := errors.New("read failed")
+	assert.Equal(t, ReadError{Err: failure}, c.Verify(mock.NewErrorFile(failure)))
+
+	unknown := CID{Version: 1, Codec: Raw, Multihash: Multihash(Algorithm{Code: 0x1e}, make([]byte, 32))}
+	assert.Equal(t, UnsupportedAlgorithmError{Multicodec: 0x1e}, unknown.Verify(bytes.NewReader(cidSrc)))
+	assert.Equal(t, "hash/cid: unsupported hash algorithm 0x1e", UnsupportedAlgorithmError{Multicodec: 0x1e}.Error())
+
+	Register(Algorithm{Name: "identity-sha256", Code: 0x1e, New: SHA256.New})
+	defer delete(algorithms, 0x1e)
+	assert.Equal(t, MismatchError{CID: unknown.String()}, unknown.Verify(bytes.NewReader(cidSrc)))
+
+	broken := CID{Version: 1, Codec: Raw, Multihash: []byte{0x12, 0x20, 1}}
+	assert.ErrorAs(t, broken.Verify(bytes.NewReader(cidSrc)), &InvalidCIDError{})
+}
+
+func TestJSON(t *testing.T) {
+	c, err := FromBytes(cidSrc, SHA256)
+	require.NoError(t, err)
+	b, err := json.Marshal(map[string]CID{"cid": c})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cid":"`+cidBase32+`"}`, string(b))
+
+	var out map[string]CID
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.True(t, c.Equal(out["cid"]))
+	assert.Error(t, json.Unmarshal([]byte(`{"cid":"nope"}`), &out))
+}
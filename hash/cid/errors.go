@@ -0,0 +1,88 @@
+package cid
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedAlgorithmError represents an error when a hash algorithm has no
+// constructor or its multicodec code was not registered.
+type UnsupportedAlgorithmError struct {
+	Multicodec uint64 // The multicodec code of the algorithm
+}
+
+// Error returns a formatted error message describing the unknown algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("hash/cid: unsupported hash algorithm 0x%x", e.Multicodec)
+}
+
+// Code returns the stable error code DGL-CID-001.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-CID-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("hash/cid", "", "", "multicodec", e.Multicodec)
+}
+
+// InvalidCIDError represents an error when a CID or multihash is malformed.
+type InvalidCIDError struct {
+	Reason string // What is wrong with the input
+}
+
+// Error returns a formatted error message describing the malformed CID.
+func (e InvalidCIDError) Error() string {
+	return fmt.Sprintf("hash/cid: invalid cid: %s", e.Reason)
+}
+
+// Code returns the stable error code DGL-CID-002.
+func (e InvalidCIDError) Code() string {
+	return "DGL-CID-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidCIDError) Fields() map[string]any {
+	return errcode.NewFields("hash/cid", "", "parse", "reason", e.Reason)
+}
+
+// MismatchError represents an error when content does not match its CID.
+type MismatchError struct {
+	CID string // The expected CID
+}
+
+// Error returns a formatted error message describing the mismatch.
+func (e MismatchError) Error() string {
+	return fmt.Sprintf("hash/cid: content does not match %s", e.CID)
+}
+
+// Code returns the stable error code DGL-CID-003.
+func (e MismatchError) Code() string {
+	return "DGL-CID-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MismatchError) Fields() map[string]any {
+	return errcode.NewFields("hash/cid", "", "verify", "cid", e.CID)
+}
+
+// ReadError represents an error when reading the content fails.
+type ReadError struct {
+	Err error // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the read failure.
+func (e ReadError) Error() string {
+	return fmt.Sprintf("hash/cid: failed to read data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-CID-004.
+func (e ReadError) Code() string {
+	return "DGL-CID-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("hash/cid", "", "read", errcode.FieldCause, e.Err)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 2, 'a', 'b'}, Append(nil, []byte("ab")))
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0}, Append(nil, nil))
+
+	enc := Encode("label", []byte("ab"), []byte("c"))
+	assert.Equal(t, Append(Append(Append(nil, []byte("label")), []byte("ab")), []byte("c")), enc)
+	assert.Equal(t, 8+5+8+2+8+1, cap(enc))
+
+	// The same bytes split differently or under another label never collide
+	assert.NotEqual(t, enc, Encode("label", []byte("a"), []byte("bc")))
+	assert.NotEqual(t, enc, Encode("label", []byte("abc")))
+	assert.NotEqual(t, Encode("ab", []byte("c")), Encode("a", []byte("bc")))
+	assert.NotEqual(t, Encode("label"), Encode("label", nil))
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, []byte("ab")))
+	assert.Equal(t, Append(nil, []byte("ab")), buf.Bytes())
+
+	failure := errors.New("write failed")
+	assert.Equal(t, failure, Write(mock.NewErrorWriteCloser(failure), []byte("ab")))
+	assert.Equal(t, failure, Write(mock.NewErrorWriteAfterN(1, failure), []byte("ab")))
+}
+
+func TestSum(t *testing.T) {
+	want := sha256.Sum256(Encode("label", []byte("ab")))
+	assert.Equal(t, want[:], Sum(sha256.New, "label", []byte("ab")))
+	assert.NotEqual(t, want[:], Sum(sha256.New, "other", []byte("ab")))
+}
+
+func TestNewHash(t *testing.T) {
+	want := sha256.Sum256(append(Append(nil, []byte("label")), "stream data"...))
+
+	h := NewHash(sha256.New, "label")
+	h.Write([]byte("stream "))
+	h.Write([]byte("data"))
+	assert.Equal(t, want[:], h.Sum(nil))
+	assert.Equal(t, sha256.Size, h.Size())
+
+	h.Reset()
+	h.Write([]byte("stream data"))
+	assert.Equal(t, want[:], h.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedAlgorithmError represents an error when a hash algorithm is
+// unknown or cannot be checkpointed.
+type UnsupportedAlgorithmError struct {
+	Algorithm string // The unsupported algorithm name
+}
+
+// Error returns a formatted error message describing the unsupported algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("hash: unsupported resumable hash algorithm %q", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-HASH-001.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-HASH-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("hash", "", "", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// InvalidStateError represents an error when a checkpoint is malformed or was
+// taken with a different algorithm.
+type InvalidStateError struct {
+	Err error // Underlying error from restoring the hash state
+}
+
+// Error returns a formatted error message describing the invalid checkpoint.
+func (e InvalidStateError) Error() string {
+	if e.Err == nil {
+		return "hash: invalid hash state"
+	}
+	return fmt.Sprintf("hash: invalid hash state: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-HASH-002.
+func (e InvalidStateError) Code() string {
+	return "DGL-HASH-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidStateError) Fields() map[string]any {
+	return errcode.NewFields("hash", "", "", errcode.FieldCause, e.Err)
+}
+
+// UnknownAlgorithmError represents an error when NewHash or NewHMAC is given
+// an algorithm name it does not know.
+type UnknownAlgorithmError struct {
+	Algorithm string // The unknown algorithm name
+}
+
+// Error returns a formatted error message describing the unknown algorithm.
+func (e UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("hash: unknown hash algorithm %q", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-HASH-003.
+func (e UnknownAlgorithmError) Code() string {
+	return "DGL-HASH-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnknownAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("hash", "", "", errcode.FieldAlgorithm, e.Algorithm)
+}
+
+// ChecksumMismatchError represents an error when the digest of the data read
+// through a VerifyingReader differs from the expected checksum.
+type ChecksumMismatchError struct {
+	Algorithm string // The hash algorithm
+	Expected  []byte // The expected digest
+	Actual    []byte // The digest of the data read
+}
+
+// Error returns a formatted error message with both digests in hex.
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("hash: %s checksum mismatch, expected %x, got %x", e.Algorithm, e.Expected, e.Actual)
+}
+
+// Code returns the stable error code DGL-HASH-004.
+func (e ChecksumMismatchError) Code() string {
+	return "DGL-HASH-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ChecksumMismatchError) Fields() map[string]any {
+	return errcode.NewFields("hash", e.Algorithm, "verify")
+}
+
+// TruncationError represents an error when a digest is truncated below the
+// minimum output length or beyond its own length.
+type TruncationError struct {
+	Bits int // The requested length in bits
+	Min  int // The shortest accepted length, MinTruncateBits
+	Max  int // The length of the digest in bits
+}
+
+// Error returns a formatted error message describing the rejected length.
+func (e TruncationError) Error() string {
+	return fmt.Sprintf("hash: cannot truncate digest to %d bits, must be %d-%d bits", e.Bits, e.Min, e.Max)
+}
+
+// Code returns the stable error code DGL-HASH-005.
+func (e TruncationError) Code() string {
+	return "DGL-HASH-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e TruncationError) Fields() map[string]any {
+	return errcode.NewFields("hash", "", "truncate", "bits", e.Bits, "min", e.Min, "max", e.Max)
+}
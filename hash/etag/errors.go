@@ -0,0 +1,71 @@
+package etag
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidETagError represents an error when an ETag is not an MD5 digest,
+// optionally followed by a dash and a number of parts between 1 and 10000.
+type InvalidETagError struct {
+	ETag string // The malformed ETag
+}
+
+// Error returns a formatted error message describing the malformed ETag.
+func (e InvalidETagError) Error() string {
+	return fmt.Sprintf("hash/etag: invalid etag '%s'", e.ETag)
+}
+
+// Code returns the stable error code DGL-ETAG-001.
+func (e InvalidETagError) Code() string {
+	return "DGL-ETAG-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidETagError) Fields() map[string]any {
+	return errcode.NewFields("hash/etag", "MD5", "", "etag", e.ETag)
+}
+
+// ETagMismatchError represents an error when the ETag of the content differs
+// from the expected one. Besides corrupt content, this happens when the part
+// size differs from the one used by the uploader.
+type ETagMismatchError struct {
+	Expected string // The expected ETag
+	Actual   string // The ETag of the content read
+}
+
+// Error returns a formatted error message with both ETags.
+func (e ETagMismatchError) Error() string {
+	return fmt.Sprintf("hash/etag: etag mismatch, expected %s, got %s", e.Expected, e.Actual)
+}
+
+// Code returns the stable error code DGL-ETAG-002.
+func (e ETagMismatchError) Code() string {
+	return "DGL-ETAG-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ETagMismatchError) Fields() map[string]any {
+	return errcode.NewFields("hash/etag", "MD5", "verify", "expected", e.Expected, "actual", e.Actual)
+}
+
+// ReadError represents an error when reading the content to verify fails.
+type ReadError struct {
+	Err error // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the read failure.
+func (e ReadError) Error() string {
+	return fmt.Sprintf("hash/etag: failed to read data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ETAG-003.
+func (e ReadError) Code() string {
+	return "DGL-ETAG-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("hash/etag", "MD5", "read", errcode.FieldCause, e.Err)
+}
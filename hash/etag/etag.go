@@ -0,0 +1,185 @@
+// Package etag computes and verifies the entity tags Amazon S3 and compatible
+// object stores assign to uploaded objects, so a multipart upload can be
+// checked end to end against the ETag returned by CompleteMultipartUpload.
+//
+// An object uploaded in a single request has the hex MD5 digest of its content
+// as ETag. A multipart object has the hex MD5 digest of the concatenated binary
+// MD5 digests of its parts, followed by a dash and the number of parts:
+//
+//	h := etag.New(etag.DefaultPartSize)
+//	io.Copy(h, file)
+//	h.Sum() // "9b2cf535f27731c974343645a3985328-3"
+//
+// Reproducing a multipart ETag requires the part size used by the uploader,
+// 8 MiB by default for the AWS CLI and SDK transfer managers. ETags of objects
+// encrypted with SSE-C or SSE-KMS are not MD5 digests and cannot be verified.
+package etag
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// DefaultPartSize is the part size of the AWS CLI and SDK transfer managers.
+const DefaultPartSize = 8 << 20
+
+// maxParts is the largest number of parts of a multipart upload.
+const maxParts = 10000
+
+// Hasher computes the multipart ETag of the data written to it, splitting it
+// into parts of a fixed size as an uploader does.
+type Hasher struct {
+	partSize int64
+	part     hash.Hash // Digest of the current part
+	written  int64     // Bytes written to the current part
+	sums     []byte    // Concatenated digests of the completed parts
+}
+
+// New returns a Hasher splitting data into parts of partSize bytes, or of
+// DefaultPartSize when partSize is not positive.
+func New(partSize int64) *Hasher {
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+	return &Hasher{partSize: partSize, part: md5.New()}
+}
+
+// Write implements the io.Writer interface. It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		chunk := min(int64(len(p)), h.partSize-h.written)
+		h.part.Write(p[:chunk])
+		h.written += chunk
+		p = p[chunk:]
+		if h.written == h.partSize {
+			h.sums = h.part.Sum(h.sums)
+			h.part.Reset()
+			h.written = 0
+		}
+	}
+	return n, nil
+}
+
+// Parts returns the number of parts written so far, counting a partial last part.
+func (h *Hasher) Parts() int {
+	n := len(h.sums) / md5.Size
+	if h.written > 0 {
+		n++
+	}
+	return n
+}
+
+// PartETags returns the ETag of every part written so far, as returned by
+// UploadPart, for checking the parts of an upload one by one.
+func (h *Hasher) PartETags() []string {
+	sums := h.partSums()
+	tags := make([]string, 0, len(sums)/md5.Size)
+	for i := 0; i < len(sums); i += md5.Size {
+		tags = append(tags, hex.EncodeToString(sums[i:i+md5.Size]))
+	}
+	return tags
+}
+
+// Sum returns the multipart ETag of the data written so far. It does not
+// change the state of the Hasher, so more data can be written afterwards.
+// No data yields the ETag of an empty single request upload, since a multipart
+// upload has at least one part.
+func (h *Hasher) Sum() string {
+	if h.Parts() == 0 {
+		sum := md5.Sum(nil)
+		return hex.EncodeToString(sum[:])
+	}
+	return compose(h.partSums())
+}
+
+// partSums returns the digests of the completed parts and of the partial last part.
+func (h *Hasher) partSums() []byte {
+	sums := append([]byte(nil), h.sums...)
+	if h.written > 0 {
+		sums = h.part.Sum(sums)
+	}
+	return sums
+}
+
+// Compose returns the multipart ETag of an upload from the ETags of its parts,
+// in part number order, as returned by UploadPart. Quotes around the part ETags
+// are ignored.
+func Compose(partETags ...string) (string, error) {
+	if len(partETags) == 0 || len(partETags) > maxParts {
+		return "", InvalidETagError{ETag: strings.Join(partETags, ",")}
+	}
+	sums := make([]byte, 0, len(partETags)*md5.Size)
+	for _, tag := range partETags {
+		sum, parts, err := Parse(tag)
+		if err != nil || parts != 0 {
+			return "", InvalidETagError{ETag: tag}
+		}
+		sums = append(sums, sum...)
+	}
+	return compose(sums), nil
+}
+
+// compose returns the multipart ETag of the concatenated part digests.
+func compose(sums []byte) string {
+	sum := md5.Sum(sums)
+	return hex.EncodeToString(sum[:]) + "-" + strconv.Itoa(len(sums)/md5.Size)
+}
+
+// Parse splits an ETag into its MD5 digest and number of parts, 0 for a single
+// request upload. Surrounding quotes and a weak "W/" prefix are ignored.
+func Parse(etag string) (digest []byte, parts int, err error) {
+	tag := strings.TrimPrefix(etag, "W/")
+	tag = strings.TrimSuffix(strings.TrimPrefix(tag, `"`), `"`)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		parts, err = strconv.Atoi(tag[i+1:])
+		if err != nil || parts < 1 || parts > maxParts || tag[i+1] == '+' || tag[i+1] == '0' {
+			return nil, 0, InvalidETagError{ETag: etag}
+		}
+		tag = tag[:i]
+	}
+	digest, err = hex.DecodeString(tag)
+	if err != nil || len(digest) != md5.Size {
+		return nil, 0, InvalidETagError{ETag: etag}
+	}
+	return digest, parts, nil
+}
+
+// Verify reads r to the end and checks its content against etag, the ETag of
+// a single request upload or of a multipart upload made with parts of partSize
+// bytes, DefaultPartSize when not positive. It returns an ETagMismatchError if
+// the content differs.
+func Verify(r io.Reader, etag string, partSize int64) error {
+	expected, parts, err := Parse(etag)
+	if err != nil {
+		return err
+	}
+	var actual string
+	if parts == 0 {
+		h := md5.New()
+		if _, err = io.Copy(h, r); err != nil {
+			return ReadError{Err: err}
+		}
+		actual = hex.EncodeToString(h.Sum(nil))
+	} else {
+		h := New(partSize)
+		if _, err = io.Copy(h, r); err != nil {
+			return ReadError{Err: err}
+		}
+		actual = h.Sum()
+	}
+	want := hex.EncodeToString(expected)
+	if parts > 0 {
+		want += "-" + strconv.Itoa(parts)
+	}
+	if !utils.ConstantTimeEqual([]byte(want), []byte(actual)) {
+		return ETagMismatchError{Expected: want, Actual: actual}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package etag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	etagSrc       = []byte("The quick brown fox jumps over the lazy dog")
+	etagSingle    = "9e107d9d372bb6826bd81d3542a419d6"
+	etagMultipart = "07f49c9b2ef71fdd9908e9322307edc2-3"
+	etagParts     = []string{"b0e45b65d9e1d88169c40dc47605e2d9", "eb95d2042eb0a424541098f42607590a", "707fb34197808db6dcd342aa81fb55ec"}
+)
+
+func TestHasher(t *testing.T) {
+	t.Run("multipart", func(t *testing.T) {
+		h := New(16)
+		_, err := h.Write(etagSrc)
+		require.NoError(t, err)
+		assert.Equal(t, 3, h.Parts())
+		assert.Equal(t, etagParts, h.PartETags())
+		assert.Equal(t, etagMultipart, h.Sum())
+		// Sum does not change the state
+		assert.Equal(t, etagMultipart, h.Sum())
+	})
+
+	t.Run("split writes", func(t *testing.T) {
+		h := New(16)
+		for _, b := range etagSrc {
+			h.Write([]byte{b})
+		}
+		assert.Equal(t, etagMultipart, h.Sum())
+	})
+
+	t.Run("exact parts", func(t *testing.T) {
+		h := New(16)
+		h.Write(etagSrc[:32])
+		assert.Equal(t, 2, h.Parts())
+		assert.Equal(t, etagParts[:2], h.PartETags())
+	})
+
+	t.Run("single part", func(t *testing.T) {
+		h := New(0)
+		h.Write(etagSrc)
+		assert.Equal(t, 1, h.Parts())
+		assert.Equal(t, []string{etagSingle}, h.PartETags())
+		tag, err := Compose(etagSingle)
+		require.NoError(t, err)
+		assert.Equal(t, tag, h.Sum())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		h := New(16)
+		assert.Equal(t, 0, h.Parts())
+		assert.Empty(t, h.PartETags())
+		assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", h.Sum())
+	})
+}
+
+func TestCompose(t *testing.T) {
+	tag, err := Compose(`"`+etagParts[0]+`"`, etagParts[1], etagParts[2])
+	require.NoError(t, err)
+	assert.Equal(t, etagMultipart, tag)
+
+	_, err = Compose()
+	assert.ErrorAs(t, err, &InvalidETagError{})
+	_, err = Compose(etagMultipart)
+	assert.ErrorAs(t, err, &InvalidETagError{})
+	_, err = Compose("zz")
+	assert.Equal(t, InvalidETagError{ETag: "zz"}, err)
+}
+
+func TestParse(t *testing.T) {
+	digest, parts, err := Parse(`W/"` + etagMultipart + `"`)
+	require.NoError(t, err)
+	assert.Equal(t, 3, parts)
+	assert.Len(t, digest, 16)
+
+	_, parts, err = Parse(etagSingle)
+	require.NoError(t, err)
+	assert.Equal(t, 0, parts)
+
+	for _, tag := range []string{"", "abc", etagSingle + "-", etagSingle + "-0", etagSingle + "-+3", etagSingle + "-03", etagSingle + "-10001", etagSingle + "-x", etagSingle[:30] + "-2"} {
+		_, _, err = Parse(tag)
+		assert.ErrorAs(t, err, &InvalidETagError{}, tag)
+	}
+	assert.Equal(t, "hash/etag: invalid etag 'abc'", InvalidETagError{ETag: "abc"}.Error())
+}
+
+func TestVerify(t *testing.T) {
+	assert.NoError(t, Verify(bytes.NewReader(etagSrc), etagSingle, 16))
+	assert.NoError(t, Verify(bytes.NewReader(etagSrc), `"`+etagMultipart+`"`, 16))
+
+	t.Run("mismatch", func(t *testing.T) {
+		err := Verify(bytes.NewReader(etagSrc), etagMultipart, 0)
+		var mismatch ETagMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		assert.Equal(t, etagMultipart, mismatch.Expected)
+		assert.Equal(t, "a5f6bc8c547364db2a98ccb9386ea241-1", mismatch.Actual)
+		assert.Contains(t, err.Error(), "etag mismatch")
+
+		err = Verify(bytes.NewReader(etagSrc[1:]), etagSingle, 16)
+		assert.ErrorAs(t, err, &ETagMismatchError{})
+	})
+
+	t.Run("invalid etag", func(t *testing.T) {
+		assert.ErrorAs(t, Verify(bytes.NewReader(etagSrc), "nope", 16), &InvalidETagError{})
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		failure := errors.New("read failed")
+		err := Verify(mock.NewErrorFile(failure), etagSingle, 16)
+		assert.Equal(t, ReadError{Err: failure}, err)
+		err = Verify(mock.NewErrorFile(failure), etagMultipart, 16)
+		assert.Equal(t, ReadError{Err: failure}, err)
+		assert.Equal(t, "hash/etag: failed to read data: read failed", err.Error())
+	})
+}
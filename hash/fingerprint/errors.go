@@ -0,0 +1,109 @@
+package fingerprint
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// UnsupportedAlgorithmError represents an error when a fingerprint algorithm is unknown.
+// This error occurs when an algorithm has no constructor or was not registered for parsing.
+type UnsupportedAlgorithmError struct {
+	Name string // The unknown algorithm name
+}
+
+// Error returns a formatted error message describing the unknown algorithm.
+func (e UnsupportedAlgorithmError) Error() string {
+	return fmt.Sprintf("hash/fingerprint: unsupported algorithm '%s'", e.Name)
+}
+
+// Code returns the stable error code DGL-FINGERPRINT-001.
+func (e UnsupportedAlgorithmError) Code() string {
+	return "DGL-FINGERPRINT-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedAlgorithmError) Fields() map[string]any {
+	return errcode.NewFields("hash/fingerprint", "", "", "name", e.Name)
+}
+
+// InvalidFingerprintError represents an error when a serialized fingerprint is malformed.
+type InvalidFingerprintError struct {
+	Input string // The malformed input
+}
+
+// Error returns a formatted error message describing the malformed fingerprint.
+func (e InvalidFingerprintError) Error() string {
+	return fmt.Sprintf("hash/fingerprint: invalid fingerprint '%s'", e.Input)
+}
+
+// Code returns the stable error code DGL-FINGERPRINT-002.
+func (e InvalidFingerprintError) Code() string {
+	return "DGL-FINGERPRINT-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidFingerprintError) Fields() map[string]any {
+	return errcode.NewFields("hash/fingerprint", "", "")
+}
+
+// ReadError represents an error when reading the content to fingerprint fails.
+type ReadError struct {
+	Err error // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the read failure.
+func (e ReadError) Error() string {
+	return fmt.Sprintf("hash/fingerprint: failed to read data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-FINGERPRINT-003.
+func (e ReadError) Code() string {
+	return "DGL-FINGERPRINT-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ReadError) Fields() map[string]any {
+	return errcode.NewFields("hash/fingerprint", "", "read", errcode.FieldCause, e.Err)
+}
+
+// CollisionError represents a detected collision: both fingerprints share size and
+// primary digest while their secondary digests differ.
+type CollisionError struct {
+	Existing  Fingerprint // The recorded fingerprint
+	Candidate Fingerprint // The colliding fingerprint
+}
+
+// Error returns a formatted error message describing the collision.
+func (e CollisionError) Error() string {
+	return fmt.Sprintf("hash/fingerprint: primary digest collision detected for %s", e.Existing.Key())
+}
+
+// Code returns the stable error code DGL-FINGERPRINT-004.
+func (e CollisionError) Code() string {
+	return "DGL-FINGERPRINT-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e CollisionError) Fields() map[string]any {
+	return errcode.NewFields("hash/fingerprint", "", "")
+}
+
+// MissingSecondaryError represents an error when a policy requires comparable
+// secondary digests but at least one fingerprint does not carry one.
+type MissingSecondaryError struct{}
+
+// Error returns a formatted error message describing the missing secondary digest.
+func (e MissingSecondaryError) Error() string {
+	return "hash/fingerprint: comparable secondary digests are required"
+}
+
+// Code returns the stable error code DGL-FINGERPRINT-005.
+func (e MissingSecondaryError) Code() string {
+	return "DGL-FINGERPRINT-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e MissingSecondaryError) Fields() map[string]any {
+	return errcode.NewFields("hash/fingerprint", "", "")
+}
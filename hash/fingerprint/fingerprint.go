@@ -0,0 +1,232 @@
+// Package fingerprint implements content fingerprints for deduplication.
+// A fingerprint combines the content size with a strong digest and an optional
+// secondary digest computed by a different algorithm, and a pluggable policy
+// decides how to treat fingerprints whose size and primary digest collide.
+package fingerprint
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/dromara/dongle/internal/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Algorithm describes a hash algorithm that can be used to build fingerprints.
+type Algorithm struct {
+	Name string           // Algorithm name used in the serialized form
+	New  func() hash.Hash // Constructor of the underlying hash
+}
+
+// Supported fingerprint algorithms.
+var (
+	SHA1    = Algorithm{Name: "sha1", New: sha1.New}
+	SHA256  = Algorithm{Name: "sha256", New: sha256.New}
+	SHA512  = Algorithm{Name: "sha512", New: sha512.New}
+	SM3     = Algorithm{Name: "sm3", New: sm3.New}
+	BLAKE2b = Algorithm{Name: "blake2b", New: func() hash.Hash {
+		h, _ := blake2b.New256(nil)
+		return h
+	}}
+)
+
+// algorithms maps algorithm names to algorithms for parsing.
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[string]Algorithm{
+		SHA1.Name:    SHA1,
+		SHA256.Name:  SHA256,
+		SHA512.Name:  SHA512,
+		SM3.Name:     SM3,
+		BLAKE2b.Name: BLAKE2b,
+	}
+)
+
+// Register makes an algorithm available to Parse under its name.
+// It is safe to call concurrently with Parse.
+func Register(a Algorithm) {
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	algorithms[a.Name] = a
+}
+
+// registered reports whether an algorithm is registered under name.
+func registered(name string) bool {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	_, ok := algorithms[name]
+	return ok
+}
+
+// Fingerprint identifies content by its size and digests.
+type Fingerprint struct {
+	Size      int64  // Content size in bytes
+	Primary   string // Primary algorithm name
+	Digest    []byte // Primary digest
+	Secondary string // Secondary algorithm name, empty if not computed
+	Extra     []byte // Secondary digest, empty if not computed
+}
+
+// Generator computes fingerprints with a primary and an optional secondary algorithm.
+type Generator struct {
+	primary   Algorithm
+	secondary *Algorithm
+	Error     error
+}
+
+// NewGenerator returns a new Generator using the primary algorithm.
+func NewGenerator(primary Algorithm) *Generator {
+	g := &Generator{primary: primary}
+	if primary.New == nil {
+		g.Error = UnsupportedAlgorithmError{Name: primary.Name}
+	}
+	return g
+}
+
+// WithSecondary also computes a secondary digest with the given algorithm.
+// The secondary digest lets policies tell a genuine duplicate from a primary collision.
+func (g *Generator) WithSecondary(secondary Algorithm) *Generator {
+	if secondary.New == nil {
+		g.Error = UnsupportedAlgorithmError{Name: secondary.Name}
+		return g
+	}
+	g.secondary = &secondary
+	return g
+}
+
+// FromBytes computes the fingerprint of a byte slice.
+func (g *Generator) FromBytes(b []byte) (f Fingerprint, err error) {
+	return g.FromReader(bytes.NewReader(b))
+}
+
+// FromReader computes the fingerprint of all data read from r.
+func (g *Generator) FromReader(r io.Reader) (f Fingerprint, err error) {
+	if g.Error != nil {
+		err = g.Error
+		return
+	}
+
+	primary := g.primary.New()
+	writers := []io.Writer{primary}
+	var secondary hash.Hash
+	if g.secondary != nil {
+		secondary = g.secondary.New()
+		writers = append(writers, secondary)
+	}
+
+	size, err := io.Copy(io.MultiWriter(writers...), r)
+	if err != nil {
+		err = ReadError{Err: err}
+		return
+	}
+
+	f.Size = size
+	f.Primary = g.primary.Name
+	f.Digest = primary.Sum(nil)
+	if secondary != nil {
+		f.Secondary = g.secondary.Name
+		f.Extra = secondary.Sum(nil)
+	}
+	return
+}
+
+// Key returns the index key of the fingerprint, made of the primary algorithm,
+// size and primary digest. Fingerprints with the same key are candidate duplicates.
+func (f Fingerprint) Key() string {
+	return f.Primary + ":" + strconv.FormatInt(f.Size, 10) + ":" + hex.EncodeToString(f.Digest)
+}
+
+// Matches reports whether both fingerprints share the same size and primary digest.
+func (f Fingerprint) Matches(o Fingerprint) bool {
+	return f.Size == o.Size && f.Primary == o.Primary &&
+		utils.ConstantTimeEqual(f.Digest, o.Digest)
+}
+
+// Equal reports whether both fingerprints are identical, including the secondary digest.
+func (f Fingerprint) Equal(o Fingerprint) bool {
+	return f.Matches(o) && f.Secondary == o.Secondary &&
+		utils.ConstantTimeEqual(f.Extra, o.Extra)
+}
+
+// IsDuplicate reports whether o describes the same content as f according to the policy.
+// Fingerprints whose size or primary digest differ are never duplicates.
+func (f Fingerprint) IsDuplicate(o Fingerprint, p Policy) (bool, error) {
+	if !f.Matches(o) {
+		return false, nil
+	}
+	if p == nil {
+		p = TrustPrimary
+	}
+	return p.Resolve(f, o)
+}
+
+// String serializes the fingerprint as "algorithm:size:hexdigest",
+// followed by ":algorithm:hexdigest" when a secondary digest is present.
+func (f Fingerprint) String() string {
+	s := f.Key()
+	if f.Secondary != "" {
+		s += ":" + f.Secondary + ":" + hex.EncodeToString(f.Extra)
+	}
+	return s
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (f Fingerprint) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (f *Fingerprint) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
+}
+
+// Parse parses a fingerprint serialized by String.
+func Parse(s string) (f Fingerprint, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 && len(parts) != 5 {
+		err = InvalidFingerprintError{Input: s}
+		return
+	}
+
+	if !registered(parts[0]) {
+		err = UnsupportedAlgorithmError{Name: parts[0]}
+		return
+	}
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || size < 0 {
+		err = InvalidFingerprintError{Input: s}
+		return
+	}
+	digest, err := hex.DecodeString(parts[2])
+	if err != nil || len(digest) == 0 {
+		err = InvalidFingerprintError{Input: s}
+		return
+	}
+	f = Fingerprint{Size: size, Primary: parts[0], Digest: digest}
+
+	if len(parts) == 5 {
+		if !registered(parts[3]) {
+			return Fingerprint{}, UnsupportedAlgorithmError{Name: parts[3]}
+		}
+		extra, decodeErr := hex.DecodeString(parts[4])
+		if decodeErr != nil || len(extra) == 0 {
+			return Fingerprint{}, InvalidFingerprintError{Input: s}
+		}
+		f.Secondary, f.Extra = parts[3], extra
+	}
+	return f, nil
+}
@@ -0,0 +1,119 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	fingerprintSrc       = []byte("hello world")
+	fingerprintSha256Hex = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	fingerprintSha1Hex   = "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"
+)
+
+func TestGenerator(t *testing.T) {
+	t.Run("primary only", func(t *testing.T) {
+		f, err := NewGenerator(SHA256).FromBytes(fingerprintSrc)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(11), f.Size)
+		assert.Equal(t, "sha256:11:"+fingerprintSha256Hex, f.String())
+	})
+
+	t.Run("with secondary", func(t *testing.T) {
+		f, err := NewGenerator(SHA256).WithSecondary(SHA1).FromBytes(fingerprintSrc)
+		assert.Nil(t, err)
+		assert.Equal(t, "sha256:11:"+fingerprintSha256Hex+":sha1:"+fingerprintSha1Hex, f.String())
+	})
+
+	t.Run("from reader", func(t *testing.T) {
+		file := mock.NewFile(fingerprintSrc, "test.txt")
+		f, err := NewGenerator(SM3).FromReader(file)
+		assert.Nil(t, err)
+		assert.Equal(t, "sm3", f.Primary)
+		assert.Len(t, f.Digest, 32)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := NewGenerator(SHA256).FromReader(mock.NewErrorFile(errors.New("read failed")))
+		assert.IsType(t, ReadError{}, err)
+		assert.Contains(t, err.Error(), "read failed")
+	})
+
+	t.Run("invalid algorithm", func(t *testing.T) {
+		_, err := NewGenerator(Algorithm{Name: "none"}).FromBytes(fingerprintSrc)
+		assert.IsType(t, UnsupportedAlgorithmError{}, err)
+		_, err = NewGenerator(SHA256).WithSecondary(Algorithm{Name: "none"}).FromBytes(fingerprintSrc)
+		assert.IsType(t, UnsupportedAlgorithmError{}, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		for _, g := range []*Generator{NewGenerator(SHA512), NewGenerator(BLAKE2b).WithSecondary(SM3)} {
+			f, _ := g.FromBytes(fingerprintSrc)
+			parsed, err := Parse(f.String())
+			assert.Nil(t, err)
+			assert.True(t, f.Equal(parsed))
+		}
+	})
+
+	t.Run("text marshaling", func(t *testing.T) {
+		f, _ := NewGenerator(SHA256).WithSecondary(SHA1).FromBytes(fingerprintSrc)
+		b, err := json.Marshal(f)
+		assert.Nil(t, err)
+		var decoded Fingerprint
+		assert.Nil(t, json.Unmarshal(b, &decoded))
+		assert.True(t, f.Equal(decoded))
+		assert.NotNil(t, decoded.UnmarshalText([]byte("bad")))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, s := range []string{
+			"", "sha256:11", "sha256:x:00", "sha256:-1:00", "sha256:11:zz", "sha256:11:",
+			"sha256:11:00:sha1:zz", "sha256:11:00:sha1",
+		} {
+			_, err := Parse(s)
+			assert.IsType(t, InvalidFingerprintError{}, err, s)
+		}
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, err := Parse("md5:11:00")
+		assert.IsType(t, UnsupportedAlgorithmError{}, err)
+		_, err = Parse("sha256:11:00:md5:00")
+		assert.IsType(t, UnsupportedAlgorithmError{}, err)
+	})
+
+	t.Run("registered algorithm", func(t *testing.T) {
+		Register(Algorithm{Name: "custom", New: SHA256.New})
+		defer delete(algorithms, "custom")
+		f, err := Parse("custom:1:00")
+		assert.Nil(t, err)
+		assert.Equal(t, "custom", f.Primary)
+	})
+
+	t.Run("concurrent register", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			name := fmt.Sprintf("custom-%d", i)
+			defer delete(algorithms, name)
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				Register(Algorithm{Name: name, New: SHA256.New})
+			}()
+			go func() {
+				defer wg.Done()
+				_, err := Parse("sha256:1:00")
+				assert.Nil(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+}
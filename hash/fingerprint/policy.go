@@ -0,0 +1,120 @@
+package fingerprint
+
+import (
+	"sync"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Policy decides whether two fingerprints sharing size and primary digest
+// describe the same content.
+type Policy interface {
+	// Resolve returns true when both fingerprints are duplicates.
+	// It may return a CollisionError to report a detected primary collision.
+	Resolve(existing, candidate Fingerprint) (bool, error)
+}
+
+// PolicyFunc adapts an ordinary function to the Policy interface.
+type PolicyFunc func(existing, candidate Fingerprint) (bool, error)
+
+// Resolve calls f(existing, candidate).
+func (f PolicyFunc) Resolve(existing, candidate Fingerprint) (bool, error) {
+	return f(existing, candidate)
+}
+
+var (
+	// TrustPrimary treats matching size and primary digest as a duplicate.
+	TrustPrimary Policy = PolicyFunc(func(existing, candidate Fingerprint) (bool, error) {
+		return true, nil
+	})
+
+	// PreferSecondary compares secondary digests when both fingerprints carry one
+	// produced by the same algorithm, and trusts the primary digest otherwise.
+	// A secondary mismatch is reported as a CollisionError.
+	PreferSecondary Policy = PolicyFunc(func(existing, candidate Fingerprint) (bool, error) {
+		if existing.Secondary == "" || existing.Secondary != candidate.Secondary {
+			return true, nil
+		}
+		return compareSecondary(existing, candidate)
+	})
+
+	// RequireSecondary only accepts duplicates confirmed by a secondary digest.
+	// Fingerprints without comparable secondary digests are reported as a
+	// MissingSecondaryError, a secondary mismatch as a CollisionError.
+	RequireSecondary Policy = PolicyFunc(func(existing, candidate Fingerprint) (bool, error) {
+		if existing.Secondary == "" || existing.Secondary != candidate.Secondary {
+			return false, MissingSecondaryError{}
+		}
+		return compareSecondary(existing, candidate)
+	})
+)
+
+// compareSecondary compares secondary digests of fingerprints produced by the same algorithm.
+func compareSecondary(existing, candidate Fingerprint) (bool, error) {
+	if utils.ConstantTimeEqual(existing.Extra, candidate.Extra) {
+		return true, nil
+	}
+	return false, CollisionError{Existing: existing, Candidate: candidate}
+}
+
+// Index records fingerprints and reports duplicates according to a policy.
+// It is safe for concurrent use.
+type Index struct {
+	mu      sync.RWMutex
+	policy  Policy
+	entries map[string][]Fingerprint
+}
+
+// NewIndex returns a new Index using the policy, nil means TrustPrimary.
+func NewIndex(p Policy) *Index {
+	if p == nil {
+		p = TrustPrimary
+	}
+	return &Index{policy: p, entries: make(map[string][]Fingerprint)}
+}
+
+// Lookup returns the recorded fingerprint that duplicates f, if any.
+func (i *Index) Lookup(f Fingerprint) (existing Fingerprint, found bool, err error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.lookup(f)
+}
+
+// Add records f unless it duplicates a recorded fingerprint.
+// It returns the recorded duplicate and true when f was not added.
+func (i *Index) Add(f Fingerprint) (existing Fingerprint, duplicate bool, err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	existing, duplicate, err = i.lookup(f)
+	if err != nil || duplicate {
+		return
+	}
+	key := f.Key()
+	i.entries[key] = append(i.entries[key], f)
+	return
+}
+
+// Len returns the number of recorded fingerprints.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	n := 0
+	for _, list := range i.entries {
+		n += len(list)
+	}
+	return n
+}
+
+// lookup searches for a duplicate of f, the caller must hold the lock.
+func (i *Index) lookup(f Fingerprint) (Fingerprint, bool, error) {
+	for _, existing := range i.entries[f.Key()] {
+		same, err := i.policy.Resolve(existing, f)
+		if err != nil {
+			return existing, false, err
+		}
+		if same {
+			return existing, true, nil
+		}
+	}
+	return Fingerprint{}, false, nil
+}
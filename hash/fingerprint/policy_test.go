@@ -0,0 +1,88 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint_IsDuplicate(t *testing.T) {
+	a := Fingerprint{Size: 1, Primary: "sha256", Digest: []byte{1}, Secondary: "sha1", Extra: []byte{2}}
+	b := Fingerprint{Size: 1, Primary: "sha256", Digest: []byte{1}, Secondary: "sha1", Extra: []byte{3}}
+	c := Fingerprint{Size: 1, Primary: "sha256", Digest: []byte{1}}
+	d := Fingerprint{Size: 2, Primary: "sha256", Digest: []byte{1}}
+
+	t.Run("different content", func(t *testing.T) {
+		dup, err := a.IsDuplicate(d, RequireSecondary)
+		assert.Nil(t, err)
+		assert.False(t, dup)
+	})
+
+	t.Run("trust primary", func(t *testing.T) {
+		dup, err := a.IsDuplicate(b, nil)
+		assert.Nil(t, err)
+		assert.True(t, dup)
+	})
+
+	t.Run("prefer secondary", func(t *testing.T) {
+		dup, err := a.IsDuplicate(a, PreferSecondary)
+		assert.Nil(t, err)
+		assert.True(t, dup)
+
+		dup, err = a.IsDuplicate(b, PreferSecondary)
+		assert.False(t, dup)
+		assert.IsType(t, CollisionError{}, err)
+		assert.Contains(t, err.Error(), "collision detected for sha256:1:01")
+
+		dup, err = c.IsDuplicate(a, PreferSecondary)
+		assert.Nil(t, err)
+		assert.True(t, dup)
+	})
+
+	t.Run("require secondary", func(t *testing.T) {
+		dup, err := a.IsDuplicate(a, RequireSecondary)
+		assert.Nil(t, err)
+		assert.True(t, dup)
+
+		_, err = a.IsDuplicate(b, RequireSecondary)
+		assert.IsType(t, CollisionError{}, err)
+
+		_, err = c.IsDuplicate(a, RequireSecondary)
+		assert.IsType(t, MissingSecondaryError{}, err)
+		assert.Equal(t, "hash/fingerprint: comparable secondary digests are required", err.Error())
+	})
+}
+
+func TestIndex(t *testing.T) {
+	g := NewGenerator(SHA256).WithSecondary(SHA1)
+	hello, _ := g.FromBytes([]byte("hello"))
+	world, _ := g.FromBytes([]byte("world"))
+
+	t.Run("add and lookup", func(t *testing.T) {
+		index := NewIndex(nil)
+		_, dup, err := index.Add(hello)
+		assert.Nil(t, err)
+		assert.False(t, dup)
+
+		existing, dup, err := index.Add(hello)
+		assert.Nil(t, err)
+		assert.True(t, dup)
+		assert.True(t, existing.Equal(hello))
+
+		_, found, _ := index.Lookup(world)
+		assert.False(t, found)
+		index.Add(world)
+		assert.Equal(t, 2, index.Len())
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		index := NewIndex(RequireSecondary)
+		index.Add(hello)
+		forged := hello
+		forged.Extra = []byte{0}
+		_, dup, err := index.Add(forged)
+		assert.False(t, dup)
+		assert.IsType(t, CollisionError{}, err)
+		assert.Equal(t, 1, index.Len())
+	})
+}
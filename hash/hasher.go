@@ -1,6 +1,7 @@
 // Package hash provides cryptographic hash and hmac functions.
 // It supports multiple hash algorithms including MD2, MD4, MD5, SHA1, SHA2, SHA3,
 // BLAKE2b, BLAKE2s, RIPEMD160, SM3 and so on, with both standard and streaming modes.
+// NewHash and NewHMAC return the same algorithms as standard hash.Hash values.
 package hash
 
 import (
@@ -11,6 +12,9 @@ import (
 	"io/fs"
 
 	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/text"
+	"github.com/dromara/dongle/hash/visual"
 	"github.com/dromara/dongle/internal/utils"
 )
 
@@ -19,11 +23,14 @@ var BufferSize = 64 * 1024
 
 // Hasher defines a Hasher struct.
 type Hasher struct {
-	src    []byte
-	dst    []byte
-	key    []byte
-	reader io.Reader
-	Error  error
+	src        []byte
+	dst        []byte
+	key        []byte
+	reader     io.Reader
+	bufferSize int
+	progress   func(done, total int64)
+	text       text.Option
+	Error      error
 }
 
 // NewHasher returns a new Hasher instance.
@@ -33,22 +40,41 @@ func NewHasher() Hasher {
 
 // FromString encrypts from string.
 func (h Hasher) FromString(s string) Hasher {
-	h.src = utils.String2Bytes(s)
+	utils.AuditShared()
+	h.src = text.Canonicalize(utils.String2Bytes(s), h.text)
 	return h
 }
 
 // FromBytes encrypts from byte slice.
 func (h Hasher) FromBytes(b []byte) Hasher {
-	h.src = b
+	utils.AuditShared()
+	h.src = text.Canonicalize(b, h.text)
 	return h
 }
 
 // FromFile encrypts from file.
 func (h Hasher) FromFile(f fs.File) Hasher {
-	h.reader = f
+	utils.AuditShared()
+	h.reader = text.NewReader(f, h.text)
 	return h
 }
 
+// FromFS hashes from the named file in fsys, such as an embed.FS, a zip
+// archive or a test fixture. The file is only opened once the chain reads it,
+// and is closed when reading stops, whether or not the chain succeeds.
+func (h Hasher) FromFS(fsys fs.FS, name string) Hasher {
+	utils.AuditShared()
+	if h.Error != nil {
+		return h
+	}
+	f, err := utils.OpenFS(fsys, name)
+	if err != nil {
+		h.Error = err
+		return h
+	}
+	return h.FromFile(f)
+}
+
 // WithKey sets the key for HMAC calculation from byte slice.
 func (h Hasher) WithKey(key []byte) Hasher {
 	if len(key) == 0 {
@@ -59,6 +85,44 @@ func (h Hasher) WithKey(key []byte) Hasher {
 	return h
 }
 
+// WithProgress sets a callback receiving the number of bytes hashed so far and
+// the total size of the file, or -1 when unknown, while hashing from a file.
+func (h Hasher) WithProgress(fn func(done, total int64)) Hasher {
+	h.progress = fn
+	return h
+}
+
+// WithCanonicalText canonicalizes string, byte slice and file input as text
+// before hashing, so that the digest of a text file does not depend on the line
+// endings, byte order mark or trailing blanks left by the platform that wrote
+// it. It applies to input given before and after the call, and adds to the
+// options of earlier calls.
+func (h Hasher) WithCanonicalText(opts text.Option) Hasher {
+	if h.src != nil {
+		h.src = text.Canonicalize(h.src, opts)
+	}
+	if h.reader != nil {
+		h.reader = text.NewReader(h.reader, opts)
+	}
+	h.text |= opts
+	return h
+}
+
+// WithUnicodeNormalization normalizes string, byte slice and file input to
+// text.NFC or text.NFKC before hashing, so that user entered text such as a
+// password or a name hashes the same on every platform, whichever normalization
+// form the keyboard produced. Other options in form are ignored.
+func (h Hasher) WithUnicodeNormalization(form text.Option) Hasher {
+	return h.WithCanonicalText(form & text.Forms)
+}
+
+// WithBufferSize sets the read buffer size used when hashing from a file,
+// BufferSize by default.
+func (h Hasher) WithBufferSize(n int) Hasher {
+	h.bufferSize = n
+	return h
+}
+
 // ToRawString outputs as raw string without encoding.
 func (h Hasher) ToRawString() string {
 	return utils.Bytes2String(h.dst)
@@ -72,6 +136,11 @@ func (h Hasher) ToRawBytes() []byte {
 	return h.dst
 }
 
+// To outputs as a Result exposing the digest in several encodings.
+func (h Hasher) To() coding.Result {
+	return coding.NewResult(h.ToRawBytes(), h.Error)
+}
+
 // ToBase64String outputs as base64 string.
 func (h Hasher) ToBase64String() string {
 	if len(h.dst) == 0 || h.Error != nil {
@@ -104,7 +173,35 @@ func (h Hasher) ToHexBytes() []byte {
 	return coding.NewEncoder().FromBytes(h.dst).ByHex().ToBytes()
 }
 
+// ToHexFormat outputs as hex string in the given format, such as uppercase
+// digits grouped in fours for user-facing fingerprints.
+func (h Hasher) ToHexFormat(f hex.Format) string {
+	if len(h.dst) == 0 || h.Error != nil {
+		return ""
+	}
+	return f.Encode(h.dst)
+}
+
+// ToRandomArt outputs as OpenSSH style randomart, a picture for comparing
+// digests by eye, see visual.RandomArt.
+func (h Hasher) ToRandomArt() string {
+	if len(h.dst) == 0 || h.Error != nil {
+		return ""
+	}
+	return visual.RandomArt(h.dst, "", "")
+}
+
+// ToEmojiFingerprint outputs as emojis separated by spaces, six bits per emoji,
+// see visual.Emoji.
+func (h Hasher) ToEmojiFingerprint() string {
+	if len(h.dst) == 0 || h.Error != nil {
+		return ""
+	}
+	return visual.Emoji(h.dst)
+}
+
 func (h Hasher) stream(fn func() hash.Hash) ([]byte, error) {
+	defer utils.CloseFS(h.reader)
 	hasher := fn()
 	defer hasher.Reset()
 
@@ -113,7 +210,7 @@ func (h Hasher) stream(fn func() hash.Hash) ([]byte, error) {
 		seeker.Seek(0, io.SeekStart)
 	}
 
-	copiedN, err := io.CopyBuffer(hasher, h.reader, make([]byte, BufferSize))
+	copiedN, err := io.CopyBuffer(hasher, utils.ProgressReader(h.reader, h.progress), utils.Buffer(h.bufferSize, BufferSize))
 	if err != nil && err != io.EOF {
 		return []byte{}, fmt.Errorf("hash: stream copy error: %w", err)
 	}
@@ -137,12 +234,14 @@ func (h Hasher) hmac(fn func() hash.Hash) Hasher {
 
 	// Streaming mode
 	if h.reader != nil {
+		defer utils.CloseFS(h.reader)
+
 		// Try to reset the reader position if it's a seeker
 		if seeker, ok := h.reader.(io.Seeker); ok {
 			seeker.Seek(0, io.SeekStart)
 		}
 
-		copiedN, err := io.CopyBuffer(hasher, h.reader, make([]byte, BufferSize))
+		copiedN, err := io.CopyBuffer(hasher, utils.ProgressReader(h.reader, h.progress), utils.Buffer(h.bufferSize, BufferSize))
 		if err != nil && err != io.EOF {
 			h.Error = fmt.Errorf("hmac: stream copy error: %w", err)
 			return h
@@ -3,10 +3,15 @@ package hash
 import (
 	"errors"
 	"hash"
+	"io/fs"
 	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/dromara/dongle/coding/hex"
+	"github.com/dromara/dongle/coding/text"
 	"github.com/dromara/dongle/hash/md2"
+	"github.com/dromara/dongle/hash/visual"
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -84,6 +89,75 @@ func TestHasher_WithKey(t *testing.T) {
 	})
 }
 
+func TestHasher_WithCanonicalText(t *testing.T) {
+	want := NewHasher().FromString("line one\nline two\n").BySha2(256).ToHexString()
+
+	t.Run("before input", func(t *testing.T) {
+		hasher := NewHasher().WithCanonicalText(text.Canonical).FromString("\xef\xbb\xbfline one \r\nline two\r\n").BySha2(256)
+		assert.Equal(t, want, hasher.ToHexString())
+		hasher = NewHasher().WithCanonicalText(text.Canonical).FromBytes([]byte("line one\t\rline two\r")).BySha2(256)
+		assert.Equal(t, want, hasher.ToHexString())
+	})
+
+	t.Run("after input", func(t *testing.T) {
+		hasher := NewHasher().FromString("line one\r\nline two\r\n").WithCanonicalText(text.NormalizeNewlines).BySha2(256)
+		assert.Equal(t, want, hasher.ToHexString())
+	})
+
+	t.Run("from file", func(t *testing.T) {
+		file := mock.NewFile([]byte("line one  \r\nline two\r\n"), "crlf.txt")
+		hasher := NewHasher().WithCanonicalText(text.Canonical).FromFile(file).BySha2(256)
+		assert.Equal(t, want, hasher.ToHexString())
+
+		file = mock.NewFile([]byte("line one  \r\nline two\r\n"), "crlf.txt")
+		hasher = NewHasher().FromFile(file).WithCanonicalText(text.Canonical).BySha2(256)
+		assert.Equal(t, want, hasher.ToHexString())
+	})
+
+	t.Run("hmac", func(t *testing.T) {
+		key := []byte("secret")
+		hmac := NewHasher().FromString("a\n").WithKey(key).BySha2(256).ToHexString()
+		assert.Equal(t, hmac, NewHasher().WithCanonicalText(text.Canonical).FromString("a \r\n").WithKey(key).BySha2(256).ToHexString())
+	})
+
+	t.Run("without options", func(t *testing.T) {
+		hasher := NewHasher().FromString("a\r\n").WithCanonicalText(0)
+		assert.Equal(t, []byte("a\r\n"), hasher.src)
+		hasher = NewHasher().WithCanonicalText(text.Canonical).FromFile(nil)
+		assert.Nil(t, hasher.reader)
+	})
+}
+
+func TestHasher_WithUnicodeNormalization(t *testing.T) {
+	key := []byte("secret")
+	composed := NewHasher().FromString("Caf\u00e9").WithKey(key).BySha2(256).ToHexString()
+
+	t.Run("nfc", func(t *testing.T) {
+		hasher := NewHasher().WithUnicodeNormalization(text.NFC).FromString("Cafe\u0301").WithKey(key).BySha2(256)
+		assert.Equal(t, composed, hasher.ToHexString())
+		hasher = NewHasher().FromBytes([]byte("Cafe\u0301")).WithUnicodeNormalization(text.NFC).WithKey(key).BySha2(256)
+		assert.Equal(t, composed, hasher.ToHexString())
+		assert.NotEqual(t, composed, NewHasher().FromString("Cafe\u0301").WithKey(key).BySha2(256).ToHexString())
+	})
+
+	t.Run("nfkc", func(t *testing.T) {
+		want := NewHasher().FromString("fi").BySha2(256).ToHexString()
+		assert.Equal(t, want, NewHasher().WithUnicodeNormalization(text.NFKC).FromString("\ufb01").BySha2(256).ToHexString())
+		assert.NotEqual(t, want, NewHasher().WithUnicodeNormalization(text.NFC).FromString("\ufb01").BySha2(256).ToHexString())
+	})
+
+	t.Run("from file", func(t *testing.T) {
+		file := mock.NewFile([]byte("Cafe\u0301 \r\n"), "name.txt")
+		hasher := NewHasher().WithUnicodeNormalization(text.NFC).WithCanonicalText(text.Canonical).FromFile(file).BySha2(256)
+		assert.Equal(t, NewHasher().FromString("Caf\u00e9\n").BySha2(256).ToHexString(), hasher.ToHexString())
+	})
+
+	t.Run("other options ignored", func(t *testing.T) {
+		hasher := NewHasher().WithUnicodeNormalization(text.Canonical).FromString("a \r\n")
+		assert.Equal(t, []byte("a \r\n"), hasher.src)
+	})
+}
+
 func TestHasher_ToRawString(t *testing.T) {
 	t.Run("normal data", func(t *testing.T) {
 		hasher := &Hasher{dst: []byte("hello")}
@@ -554,3 +628,106 @@ func TestHasher_Error(t *testing.T) {
 	})
 
 }
+
+func TestHasher_ToHexFormat(t *testing.T) {
+	h := NewHasher().FromString("hello world").BySha2(256)
+	assert.Equal(t, "B94D-27B9-934D-3E08", h.ToHexFormat(hex.Format{Uppercase: true, GroupSize: 4})[:19])
+	assert.Equal(t, h.ToHexString(), h.ToHexFormat(hex.Format{}))
+	assert.Empty(t, NewHasher().FromString("").BySha2(256).ToHexFormat(hex.Format{}))
+}
+
+func TestHasher_ToRandomArt(t *testing.T) {
+	h := NewHasher().FromString("hello world").BySha2(256)
+	assert.Equal(t, visual.RandomArt(h.ToRawBytes(), "", ""), h.ToRandomArt())
+	assert.Len(t, strings.Split(h.ToRandomArt(), "\n"), visual.Height+2)
+	assert.Empty(t, NewHasher().FromString("").BySha2(256).ToRandomArt())
+
+	h.Error = errors.New("existing error")
+	assert.Empty(t, h.ToRandomArt())
+}
+
+func TestHasher_ToEmojiFingerprint(t *testing.T) {
+	h := NewHasher().FromString("hello world").BySha2(256)
+	// b94d27... starts with the bits 101110 010100 110100 100111, 46 20 52 39
+	assert.True(t, strings.HasPrefix(h.ToEmojiFingerprint(), "🔒 🌙 🚲 ⏰ "))
+	assert.Len(t, strings.Fields(h.ToEmojiFingerprint()), 43)
+	assert.Empty(t, NewHasher().FromString("").BySha2(256).ToEmojiFingerprint())
+
+	h.Error = errors.New("existing error")
+	assert.Empty(t, h.ToEmojiFingerprint())
+}
+
+func TestHasher_To(t *testing.T) {
+	h := NewHasher().FromString("hello world").BySha2(256)
+	r := h.To()
+	assert.NoError(t, r.Error())
+	assert.Equal(t, h.ToRawBytes(), r.Bytes())
+	assert.Equal(t, h.ToHexString(), r.Hex())
+	assert.Equal(t, h.ToBase64String(), r.Base64())
+	assert.Equal(t, 32, r.Len())
+
+	r = NewHasher().FromString("hello world").WithKey(nil).ByMd5().To()
+	assert.Error(t, r.Error())
+	assert.Empty(t, r.Hex())
+}
+
+func TestHasher_WithProgress(t *testing.T) {
+	data := []byte(strings.Repeat("hello world", 100))
+
+	t.Run("hash from file", func(t *testing.T) {
+		file := mock.NewFile(data, "test.txt")
+		var calls int
+		var done, total int64
+		hasher := NewHasher().FromFile(file).WithBufferSize(100).WithProgress(func(d, t int64) {
+			calls++
+			done, total = d, t
+		}).BySha2(256)
+		assert.Nil(t, hasher.Error)
+		assert.Equal(t, NewHasher().FromBytes(data).BySha2(256).ToHexString(), hasher.ToHexString())
+		assert.Equal(t, 11, calls)
+		assert.Equal(t, int64(len(data)), done)
+		assert.Equal(t, int64(len(data)), total)
+	})
+
+	t.Run("hmac from file", func(t *testing.T) {
+		file := mock.NewFile(data, "test.txt")
+		var done int64
+		hasher := NewHasher().FromFile(file).WithKey([]byte("key")).WithProgress(func(d, _ int64) {
+			done = d
+		}).BySha2(256)
+		assert.Nil(t, hasher.Error)
+		assert.Equal(t, NewHasher().FromBytes(data).WithKey([]byte("key")).BySha2(256).ToHexString(), hasher.ToHexString())
+		assert.Equal(t, int64(len(data)), done)
+	})
+
+	t.Run("not called for bytes", func(t *testing.T) {
+		hasher := NewHasher().FromBytes(data).WithProgress(func(_, _ int64) {
+			t.Fatal("unexpected progress")
+		}).BySha2(256)
+		assert.Nil(t, hasher.Error)
+	})
+}
+
+func TestHasher_FromFS(t *testing.T) {
+	fsys := fstest.MapFS{"testdata/hello.txt": {Data: []byte("hello world")}}
+
+	t.Run("hash file", func(t *testing.T) {
+		hasher := NewHasher().FromFS(fsys, "testdata/hello.txt").ByMd5()
+		assert.Nil(t, hasher.Error)
+		assert.Equal(t, "5eb63bbbe01eeed093cb22bb8f5acdc3", hasher.ToHexString())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		hasher := NewHasher().FromFS(fsys, "missing.txt").ByMd5()
+		assert.ErrorIs(t, hasher.Error, fs.ErrNotExist)
+		assert.Empty(t, hasher.ToHexString())
+	})
+
+	t.Run("with existing error", func(t *testing.T) {
+		hasher := NewHasher()
+		hasher.Error = errors.New("existing error")
+		hasher = hasher.FromFS(fsys, "testdata/hello.txt")
+		assert.Equal(t, errors.New("existing error"), hasher.Error)
+		assert.Nil(t, hasher.reader)
+	})
+}
@@ -3,6 +3,7 @@ package hash
 import (
 	"hash"
 
+	"github.com/dromara/dongle/deprecation"
 	"github.com/dromara/dongle/hash/md2"
 )
 
@@ -11,6 +12,9 @@ func (h Hasher) ByMd2() Hasher {
 	if h.Error != nil {
 		return h
 	}
+	if h.Error = deprecation.Check("MD2"); h.Error != nil {
+		return h
+	}
 	hasher := md2.New
 
 	// Hmac mode
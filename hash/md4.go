@@ -3,6 +3,7 @@ package hash
 import (
 	"hash"
 
+	"github.com/dromara/dongle/deprecation"
 	"golang.org/x/crypto/md4"
 )
 
@@ -11,6 +12,9 @@ func (h Hasher) ByMd4() Hasher {
 	if h.Error != nil {
 		return h
 	}
+	if h.Error = deprecation.Check("MD4"); h.Error != nil {
+		return h
+	}
 	hasher := md4.New
 
 	// Hmac mode
@@ -0,0 +1,91 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dromara/dongle/deprecation"
+	"github.com/dromara/dongle/hash/md2"
+	"github.com/dromara/dongle/hash/sm3"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/md4"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+// hashes lists the algorithms NewHash and NewHMAC know, by lower case name.
+// BLAKE2s-128 is missing as it is only defined with a key.
+var hashes = map[string]func() hash.Hash{
+	"md2":         md2.New,
+	"md4":         md4.New,
+	"md5":         md5.New,
+	"sha1":        sha1.New,
+	"sha224":      sha256.New224,
+	"sha256":      sha256.New,
+	"sha384":      sha512.New384,
+	"sha512":      sha512.New,
+	"sha512/224":  sha512.New512_224,
+	"sha512/256":  sha512.New512_256,
+	"sha3-224":    sha3.New224,
+	"sha3-256":    sha3.New256,
+	"sha3-384":    sha3.New384,
+	"sha3-512":    sha3.New512,
+	"ripemd160":   ripemd160.New,
+	"blake2b-256": func() hash.Hash { h, _ := blake2b.New256(nil); return h },
+	"blake2b-384": func() hash.Hash { h, _ := blake2b.New384(nil); return h },
+	"blake2b-512": func() hash.Hash { h, _ := blake2b.New512(nil); return h },
+	"blake2s-256": func() hash.Hash { h, _ := blake2s.New256(nil); return h },
+	"sm3":         sm3.New,
+}
+
+// Hashes returns the names accepted by NewHash and NewHMAC in sorted order.
+func Hashes() []string {
+	return slices.Sorted(maps.Keys(hashes))
+}
+
+// NewHash returns a new standard hash.Hash computing the named algorithm, so
+// dongle's hashes can be used with crypto/hmac, io.TeeReader or any library
+// taking a hash.Hash. Names are case insensitive and listed by Hashes. Using a
+// deprecated algorithm is reported as by the Hasher.
+func NewHash(name string) (hash.Hash, error) {
+	fn, err := lookupHash(name)
+	if err != nil {
+		return nil, err
+	}
+	return fn(), nil
+}
+
+// NewHMAC returns a new standard hash.Hash computing the HMAC of the named
+// algorithm with key, as Hasher.WithKey does.
+func NewHMAC(name string, key []byte) (hash.Hash, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("hmac: key cannot be empty")
+	}
+	fn, err := lookupHash(name)
+	if err != nil {
+		return nil, err
+	}
+	return hmac.New(fn, key), nil
+}
+
+// lookupHash returns the constructor of the named algorithm after checking
+// whether it is deprecated.
+func lookupHash(name string) (func() hash.Hash, error) {
+	fn, ok := hashes[strings.ToLower(name)]
+	if !ok {
+		return nil, UnknownAlgorithmError{Algorithm: name}
+	}
+	if err := deprecation.Check(name); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
@@ -0,0 +1,110 @@
+package hash
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/deprecation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// builders maps the names of NewHash to the matching Hasher methods.
+var builders = map[string]func(h Hasher) Hasher{
+	"md2":         Hasher.ByMd2,
+	"md4":         Hasher.ByMd4,
+	"md5":         Hasher.ByMd5,
+	"sha1":        Hasher.BySha1,
+	"sha224":      func(h Hasher) Hasher { return h.BySha2(224) },
+	"sha256":      func(h Hasher) Hasher { return h.BySha2(256) },
+	"sha384":      func(h Hasher) Hasher { return h.BySha2(384) },
+	"sha512":      func(h Hasher) Hasher { return h.BySha2(512) },
+	"sha3-224":    func(h Hasher) Hasher { return h.BySha3(224) },
+	"sha3-256":    func(h Hasher) Hasher { return h.BySha3(256) },
+	"sha3-384":    func(h Hasher) Hasher { return h.BySha3(384) },
+	"sha3-512":    func(h Hasher) Hasher { return h.BySha3(512) },
+	"ripemd160":   Hasher.ByRipemd160,
+	"blake2b-256": func(h Hasher) Hasher { return h.ByBlake2b(256) },
+	"blake2b-384": func(h Hasher) Hasher { return h.ByBlake2b(384) },
+	"blake2b-512": func(h Hasher) Hasher { return h.ByBlake2b(512) },
+	"blake2s-256": func(h Hasher) Hasher { return h.ByBlake2s(256) },
+	"sm3":         Hasher.BySm3,
+}
+
+func TestNewHash(t *testing.T) {
+	data := []byte("hash.Hash compliance")
+
+	t.Run("implements hash.Hash", func(t *testing.T) {
+		for _, name := range Hashes() {
+			h, err := NewHash(name)
+			require.NoError(t, err, name)
+			h.Write(data[:5])
+			h.Write(data[5:])
+			sum := h.Sum([]byte("prefix"))
+			assert.Equal(t, "prefix", string(sum[:6]), name)
+			assert.Len(t, sum[6:], h.Size(), name)
+			assert.Positive(t, h.BlockSize(), name)
+
+			// Sum leaves the state unchanged and Reset clears it.
+			assert.Equal(t, sum[6:], h.Sum(nil), name)
+			h.Reset()
+			fresh, _ := NewHash(name)
+			assert.Equal(t, fresh.Sum(nil), h.Sum(nil), name)
+
+			if fn, ok := builders[name]; ok {
+				assert.Equal(t, fn(NewHasher().FromBytes(data)).ToRawBytes(), sum[6:], name)
+			}
+		}
+	})
+
+	t.Run("composes with io", func(t *testing.T) {
+		h, _ := NewHash("SM3")
+		_, err := io.Copy(io.Discard, io.TeeReader(bytes.NewReader(data), h))
+		require.NoError(t, err)
+		assert.Equal(t, NewHasher().FromBytes(data).BySm3().ToRawBytes(), h.Sum(nil))
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		h, err := NewHash("blake3")
+		assert.Nil(t, h)
+		assert.Equal(t, UnknownAlgorithmError{Algorithm: "blake3"}, err)
+		assert.Equal(t, `hash: unknown hash algorithm "blake3"`, err.Error())
+	})
+
+	t.Run("deprecated algorithm in strict mode", func(t *testing.T) {
+		defer deprecation.Reset()
+		deprecation.SetStrict(true)
+		h, err := NewHash("md4")
+		assert.Nil(t, h)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewHMAC(t *testing.T) {
+	key, data := []byte("secret"), []byte("hello world")
+
+	t.Run("matches hasher", func(t *testing.T) {
+		for _, name := range []string{"md5", "sha256", "sha3-512", "blake2b-256", "ripemd160", "sm3"} {
+			h, err := NewHMAC(strings.ToUpper(name), key)
+			require.NoError(t, err, name)
+			h.Write(data)
+			want := builders[name](NewHasher().FromBytes(data).WithKey(key)).ToRawBytes()
+			assert.Equal(t, want, h.Sum(nil), name)
+			assert.Equal(t, len(want), h.Size(), name)
+		}
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		h, err := NewHMAC("sha256", nil)
+		assert.Nil(t, h)
+		assert.EqualError(t, err, "hmac: key cannot be empty")
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		h, err := NewHMAC("sha0", []byte("key"))
+		assert.Nil(t, h)
+		assert.IsType(t, UnknownAlgorithmError{}, err)
+	})
+}
@@ -0,0 +1,143 @@
+package hash
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding"
+	"encoding/binary"
+	"hash"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/sha3"
+)
+
+// resumables lists the algorithms whose state can be checkpointed.
+var resumables = map[string]func() hash.Hash{
+	"md5":         md5.New,
+	"sha1":        sha1.New,
+	"sha224":      sha256.New224,
+	"sha256":      sha256.New,
+	"sha384":      sha512.New384,
+	"sha512":      sha512.New,
+	"sha512/224":  sha512.New512_224,
+	"sha512/256":  sha512.New512_256,
+	"sha3-224":    sha3.New224,
+	"sha3-256":    sha3.New256,
+	"sha3-384":    sha3.New384,
+	"sha3-512":    sha3.New512,
+	"blake2b-256": func() hash.Hash { h, _ := blake2b.New256(nil); return h },
+	"blake2b-384": func() hash.Hash { h, _ := blake2b.New384(nil); return h },
+	"blake2b-512": func() hash.Hash { h, _ := blake2b.New512(nil); return h },
+	"blake2s-256": func() hash.Hash { h, _ := blake2s.New256(nil); return h },
+	"sm3":         sm3.New,
+}
+
+// checkpointMagic identifies serialized Resumable states.
+const checkpointMagic = "dgh\x01"
+
+// Resumable is a streaming hash whose state can be saved with MarshalBinary
+// and restored with UnmarshalBinary, even by another process, so hashing a
+// multi-hour upload can continue after a restart. It implements hash.Hash.
+type Resumable struct {
+	algorithm string
+	hash      hash.Hash
+	written   int64
+}
+
+// NewResumable returns a Resumable for algorithm, one of md5, sha1, sha224,
+// sha256, sha384, sha512, sha512/224, sha512/256, sha3-224, sha3-256, sha3-384,
+// sha3-512, blake2b-256, blake2b-384, blake2b-512, blake2s-256 and sm3.
+func NewResumable(algorithm string) (*Resumable, error) {
+	fn, ok := resumables[algorithm]
+	if !ok {
+		return nil, UnsupportedAlgorithmError{Algorithm: algorithm}
+	}
+	return &Resumable{algorithm: algorithm, hash: fn()}, nil
+}
+
+// Algorithm returns the algorithm name.
+func (r *Resumable) Algorithm() string {
+	return r.algorithm
+}
+
+// Written returns the number of bytes hashed so far, which is the offset to
+// resume reading the input from after restoring a checkpoint.
+func (r *Resumable) Written() int64 {
+	return r.written
+}
+
+// Write adds more data to the running hash.
+func (r *Resumable) Write(p []byte) (int, error) {
+	n, err := r.hash.Write(p)
+	r.written += int64(n)
+	return n, err
+}
+
+// Sum appends the current hash to b without changing the state.
+func (r *Resumable) Sum(b []byte) []byte {
+	return r.hash.Sum(b)
+}
+
+// Reset resets the hash to its initial state.
+func (r *Resumable) Reset() {
+	r.hash.Reset()
+	r.written = 0
+}
+
+// Size returns the number of bytes Sum will return.
+func (r *Resumable) Size() int {
+	return r.hash.Size()
+}
+
+// BlockSize returns the hash's underlying block size.
+func (r *Resumable) BlockSize() int {
+	return r.hash.BlockSize()
+}
+
+// MarshalBinary returns a checkpoint of the hash state, made of the algorithm
+// name, the number of bytes written and the state of the underlying hash.
+func (r *Resumable) MarshalBinary() ([]byte, error) {
+	state, err := r.hash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, len(checkpointMagic)+1+len(r.algorithm)+8+len(state))
+	b = append(b, checkpointMagic...)
+	b = append(b, byte(len(r.algorithm)))
+	b = append(b, r.algorithm...)
+	b = binary.BigEndian.AppendUint64(b, uint64(r.written))
+	return append(b, state...), nil
+}
+
+// UnmarshalBinary restores a checkpoint taken with MarshalBinary. A zero
+// Resumable takes the algorithm of the checkpoint, otherwise the algorithms
+// must match.
+func (r *Resumable) UnmarshalBinary(data []byte) error {
+	if len(data) < len(checkpointMagic)+1 || string(data[:len(checkpointMagic)]) != checkpointMagic {
+		return InvalidStateError{}
+	}
+	data = data[len(checkpointMagic):]
+	n := int(data[0])
+	if len(data) < 1+n+8 {
+		return InvalidStateError{}
+	}
+	algorithm := string(data[1 : 1+n])
+	written := int64(binary.BigEndian.Uint64(data[1+n:]))
+	fn, ok := resumables[algorithm]
+	if !ok {
+		return UnsupportedAlgorithmError{Algorithm: algorithm}
+	}
+	if written < 0 || r.algorithm != "" && r.algorithm != algorithm {
+		return InvalidStateError{}
+	}
+	h := fn()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(data[1+n+8:]); err != nil {
+		return InvalidStateError{Err: err}
+	}
+	r.algorithm, r.hash, r.written = algorithm, h, written
+	return nil
+}
@@ -0,0 +1,79 @@
+package hash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumable(t *testing.T) {
+	data := bytes.Repeat([]byte("resumable upload chunk "), 1000)
+
+	t.Run("checkpoint and resume", func(t *testing.T) {
+		for name, fn := range resumables {
+			r, err := NewResumable(name)
+			require.NoError(t, err)
+			assert.Equal(t, name, r.Algorithm())
+			r.Write(data[:12345])
+			checkpoint, err := r.MarshalBinary()
+			require.NoError(t, err, name)
+
+			// A new process restores the checkpoint and continues at Written.
+			resumed := new(Resumable)
+			require.NoError(t, resumed.UnmarshalBinary(checkpoint), name)
+			assert.Equal(t, int64(12345), resumed.Written())
+			_, err = io.Copy(resumed, bytes.NewReader(data[resumed.Written():]))
+			require.NoError(t, err)
+
+			want := fn()
+			want.Write(data)
+			assert.Equal(t, want.Sum(nil), resumed.Sum(nil), name)
+			assert.Equal(t, want.Size(), resumed.Size())
+			assert.Equal(t, want.BlockSize(), resumed.BlockSize())
+		}
+	})
+
+	t.Run("matches hasher", func(t *testing.T) {
+		r, _ := NewResumable("sm3")
+		r.Write(data)
+		assert.Equal(t, NewHasher().FromBytes(data).BySm3().ToRawBytes(), r.Sum(nil))
+		r.Reset()
+		assert.Zero(t, r.Written())
+		assert.Equal(t, sm3.New().Sum(nil), r.Sum(nil))
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := NewResumable("md4")
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: "md4"}, err)
+		assert.Equal(t, `hash: unsupported resumable hash algorithm "md4"`, err.Error())
+	})
+
+	t.Run("invalid checkpoint", func(t *testing.T) {
+		r, _ := NewResumable("sha256")
+		r.Write(data[:100])
+		checkpoint, _ := r.MarshalBinary()
+
+		other, _ := NewResumable("sha512")
+		assert.Equal(t, InvalidStateError{}, other.UnmarshalBinary(checkpoint))
+		assert.Equal(t, InvalidStateError{}, new(Resumable).UnmarshalBinary(checkpoint[:6]))
+		assert.Equal(t, InvalidStateError{}, new(Resumable).UnmarshalBinary([]byte("nope")))
+		err := new(Resumable).UnmarshalBinary(checkpoint[:len(checkpoint)-1])
+		assert.IsType(t, InvalidStateError{}, err)
+		assert.Contains(t, err.Error(), "hash: invalid hash state: ")
+
+		unknown := bytes.Replace(checkpoint, []byte("sha256"), []byte("sha999"), 1)
+		assert.Equal(t, UnsupportedAlgorithmError{Algorithm: "sha999"}, new(Resumable).UnmarshalBinary(unknown))
+	})
+
+	t.Run("implements hash", func(t *testing.T) {
+		r, _ := NewResumable("sha256")
+		want := sha256.Sum256(data)
+		io.Copy(r, bytes.NewReader(data))
+		assert.Equal(t, want[:], r.Sum(nil))
+	})
+}
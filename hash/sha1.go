@@ -3,6 +3,8 @@ package hash
 import (
 	"crypto/sha1"
 	"hash"
+
+	"github.com/dromara/dongle/deprecation"
 )
 
 // BySha1 computes the SHA1 hash or hmac of the input data.
@@ -10,6 +12,9 @@ func (h Hasher) BySha1() Hasher {
 	if h.Error != nil {
 		return h
 	}
+	if h.Error = deprecation.Check("SHA1"); h.Error != nil {
+		return h
+	}
 	hasher := sha1.New
 
 	// Hmac mode
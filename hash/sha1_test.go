@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dromara/dongle/deprecation"
 	"github.com/dromara/dongle/internal/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -192,3 +193,25 @@ func TestHasher_BySha1_Error(t *testing.T) {
 		assert.Equal(t, errors.New("existing error"), result.Error)
 	})
 }
+
+func TestHasher_BySha1_Deprecation(t *testing.T) {
+	t.Cleanup(deprecation.Reset)
+	var warnings []deprecation.Warning
+	deprecation.SetHandler(func(w deprecation.Warning) { warnings = append(warnings, w) })
+
+	hasher := NewHasher().FromBytes(sha1HashSrc).BySha1()
+	assert.NoError(t, hasher.Error)
+	assert.Equal(t, sha1HashHexDst, hasher.ToHexString())
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, "SHA1", warnings[0].Algorithm)
+
+	NewHasher().FromBytes(sha1HashSrc).BySha2(256)
+	assert.Len(t, warnings, 1)
+
+	deprecation.SetStrict(true)
+	hasher = NewHasher().FromBytes(sha1HashSrc).BySha1()
+	assert.IsType(t, deprecation.Warning{}, hasher.Error)
+	assert.Empty(t, hasher.ToHexString())
+	assert.IsType(t, deprecation.Warning{}, NewHasher().FromBytes(sha1HashSrc).ByMd4().Error)
+	assert.IsType(t, deprecation.Warning{}, NewHasher().FromBytes(sha1HashSrc).ByMd2().Error)
+}
@@ -0,0 +1,62 @@
+package shard
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidBucketsError represents an error when the number of buckets is not positive.
+type InvalidBucketsError int
+
+// Error returns a formatted error message describing the invalid number of buckets.
+func (e InvalidBucketsError) Error() string {
+	return fmt.Sprintf("hash/shard: invalid number of buckets %d, must be positive", int(e))
+}
+
+// Code returns the stable error code DGL-SHARD-001.
+func (e InvalidBucketsError) Code() string {
+	return "DGL-SHARD-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidBucketsError) Fields() map[string]any {
+	return errcode.NewFields("hash/shard", "", "", "buckets", int(e))
+}
+
+// UnsupportedHashError represents an error when the digest function is unusable.
+// Shard placement needs at least 8 bytes of digest output.
+type UnsupportedHashError struct{}
+
+// Error returns a formatted error message describing the unusable digest function.
+func (e UnsupportedHashError) Error() string {
+	return "hash/shard: hash function must produce at least 8 bytes"
+}
+
+// Code returns the stable error code DGL-SHARD-002.
+func (e UnsupportedHashError) Code() string {
+	return "DGL-SHARD-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e UnsupportedHashError) Fields() map[string]any {
+	return errcode.NewFields("hash/shard", "", "")
+}
+
+// EmptyNodesError represents an error when picking a node from an empty set.
+type EmptyNodesError struct{}
+
+// Error returns a formatted error message describing the empty node set.
+func (e EmptyNodesError) Error() string {
+	return "hash/shard: no nodes available"
+}
+
+// Code returns the stable error code DGL-SHARD-003.
+func (e EmptyNodesError) Code() string {
+	return "DGL-SHARD-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyNodesError) Fields() map[string]any {
+	return errcode.NewFields("hash/shard", "", "")
+}
@@ -0,0 +1,118 @@
+package shard
+
+import (
+	"hash"
+	"sort"
+	"sync"
+)
+
+// Rendezvous assigns data to named nodes using highest random weight hashing.
+// Every node scores every key and the highest score wins, so removing a node only
+// moves the keys it owned. It is safe for concurrent use.
+type Rendezvous struct {
+	hasher
+	mu    sync.RWMutex
+	nodes []string
+	Error error
+}
+
+// NewRendezvous returns a new Rendezvous over the given nodes.
+func NewRendezvous(nodes ...string) *Rendezvous {
+	r := &Rendezvous{}
+	for _, node := range nodes {
+		r.Add(node)
+	}
+	return r
+}
+
+// SetHash sets the digest function, the default is SHA-256.
+func (r *Rendezvous) SetHash(fn func() hash.Hash) {
+	if err := r.hasher.SetHash(fn); err != nil {
+		r.Error = err
+	}
+}
+
+// Add adds a node, adding an existing node has no effect.
+func (r *Rendezvous) Add(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range r.nodes {
+		if n == node {
+			return
+		}
+	}
+	r.nodes = append(r.nodes, node)
+}
+
+// Remove removes a node.
+func (r *Rendezvous) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, n := range r.nodes {
+		if n == node {
+			r.nodes = append(r.nodes[:i], r.nodes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Nodes returns a copy of the current nodes.
+func (r *Rendezvous) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.nodes...)
+}
+
+// Pick returns the node owning data.
+func (r *Rendezvous) Pick(data []byte) (string, error) {
+	nodes, err := r.PickN(data, 1)
+	if err != nil {
+		return "", err
+	}
+	return nodes[0], nil
+}
+
+// PickString returns the node owning a string.
+func (r *Rendezvous) PickString(s string) (string, error) {
+	return r.Pick([]byte(s))
+}
+
+// PickN returns up to n nodes ordered by decreasing score, which is
+// convenient to choose replicas for data. It returns nil for n <= 0.
+func (r *Rendezvous) PickN(data []byte, n int) ([]string, error) {
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.nodes) == 0 {
+		return nil, EmptyNodesError{}
+	}
+
+	type scored struct {
+		node  string
+		score uint64
+	}
+	scores := make([]scored, len(r.nodes))
+	for i, node := range r.nodes {
+		scores[i] = scored{node: node, score: r.sum64([]byte(node), []byte{0}, data)}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].node < scores[j].node
+	})
+
+	if n > len(scores) {
+		n = len(scores)
+	}
+	picked := make([]string, 0, n)
+	for _, s := range scores[:n] {
+		picked = append(picked, s.node)
+	}
+	return picked, nil
+}
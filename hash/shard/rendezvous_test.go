@@ -0,0 +1,104 @@
+package shard
+
+import (
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRendezvous_Nodes(t *testing.T) {
+	r := NewRendezvous("a", "b", "a")
+	assert.Equal(t, []string{"a", "b"}, r.Nodes())
+	r.Add("c")
+	r.Remove("a")
+	r.Remove("missing")
+	assert.Equal(t, []string{"b", "c"}, r.Nodes())
+}
+
+func TestRendezvous_Pick(t *testing.T) {
+	t.Run("deterministic", func(t *testing.T) {
+		r1 := NewRendezvous("a", "b", "c")
+		r2 := NewRendezvous("c", "b", "a")
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("key-%d", i)
+			n1, err := r1.PickString(key)
+			assert.Nil(t, err)
+			n2, _ := r2.PickString(key)
+			assert.Equal(t, n1, n2)
+		}
+	})
+
+	t.Run("removal only moves owned keys", func(t *testing.T) {
+		r := NewRendezvous("a", "b", "c", "d")
+		before := make(map[string]string)
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("key-%d", i)
+			before[key], _ = r.PickString(key)
+		}
+		r.Remove("c")
+		for key, owner := range before {
+			after, _ := r.PickString(key)
+			if owner != "c" {
+				assert.Equal(t, owner, after)
+			} else {
+				assert.NotEqual(t, "c", after)
+			}
+		}
+	})
+
+	t.Run("pick n", func(t *testing.T) {
+		r := NewRendezvous("a", "b", "c")
+		first, _ := r.Pick([]byte("dongle"))
+		for _, tt := range []struct {
+			n    int
+			want int
+		}{
+			{n: -1, want: 0},
+			{n: 0, want: 0},
+			{n: 1, want: 1},
+			{n: 2, want: 2},
+			{n: 10, want: 3},
+		} {
+			nodes, err := r.PickN([]byte("dongle"), tt.n)
+			assert.Nil(t, err)
+			assert.Len(t, nodes, tt.want, "n = %d", tt.n)
+			if tt.want > 0 {
+				assert.Equal(t, first, nodes[0])
+			} else {
+				assert.Nil(t, nodes)
+			}
+		}
+		all, _ := r.PickN([]byte("dongle"), 10)
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, all)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		plain := NewRendezvous("a", "b", "c", "d")
+		keyed := NewRendezvous("a", "b", "c", "d")
+		keyed.SetKey([]byte("dongle"))
+		differ := 0
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("key-%d", i)
+			p, _ := plain.PickString(key)
+			k, _ := keyed.PickString(key)
+			if p != k {
+				differ++
+			}
+		}
+		assert.Greater(t, differ, 40)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewRendezvous().PickString("dongle")
+		assert.IsType(t, EmptyNodesError{}, err)
+		assert.Equal(t, "hash/shard: no nodes available", err.Error())
+
+		r := NewRendezvous("a")
+		r.SetHash(func() hash.Hash { return crc32.NewIEEE() })
+		_, err = r.PickString("dongle")
+		assert.IsType(t, UnsupportedHashError{}, err)
+	})
+}
@@ -0,0 +1,110 @@
+// Package shard implements consistent sharding helpers keyed by dongle hashes.
+// It provides Jump consistent hashing for numbered buckets and Rendezvous
+// (highest random weight) hashing for named nodes. Both derive their input from a
+// configurable digest that can be keyed with HMAC, so shard placement cannot be
+// predicted or skewed by clients that do not know the key.
+package shard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+)
+
+// hasher derives 64-bit values from data using a configurable, optionally keyed digest.
+type hasher struct {
+	hash func() hash.Hash // Digest function, at least 8 bytes long
+	key  []byte           // Optional HMAC key
+}
+
+// SetHash sets the digest function, the default is SHA-256.
+// The digest must be at least 8 bytes long.
+func (h *hasher) SetHash(fn func() hash.Hash) error {
+	if fn == nil || fn().Size() < 8 {
+		return UnsupportedHashError{}
+	}
+	h.hash = fn
+	return nil
+}
+
+// SetKey sets the HMAC key used to derive shard placement.
+func (h *hasher) SetKey(key []byte) {
+	h.key = key
+}
+
+// sum64 returns the first 8 bytes of the digest of the concatenated parts.
+func (h *hasher) sum64(parts ...[]byte) uint64 {
+	fn := h.hash
+	if fn == nil {
+		fn = sha256.New
+	}
+	var d hash.Hash
+	if len(h.key) > 0 {
+		d = hmac.New(fn, h.key)
+	} else {
+		d = fn()
+	}
+	for _, part := range parts {
+		d.Write(part)
+	}
+	return binary.BigEndian.Uint64(d.Sum(nil))
+}
+
+// JumpHash maps a 64-bit key to a bucket in [0, buckets) with the
+// jump consistent hash algorithm by Lamping and Veach.
+// It returns -1 when buckets is not positive.
+func JumpHash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		return -1
+	}
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// Jump assigns data to numbered buckets using jump consistent hashing.
+// When the number of buckets grows from n to n+1, only 1/(n+1) of the keys move.
+type Jump struct {
+	hasher
+	buckets int
+	Error   error
+}
+
+// NewJump returns a new Jump for the given number of buckets.
+func NewJump(buckets int) *Jump {
+	j := &Jump{buckets: buckets}
+	if buckets <= 0 {
+		j.Error = InvalidBucketsError(buckets)
+	}
+	return j
+}
+
+// SetHash sets the digest function, the default is SHA-256.
+func (j *Jump) SetHash(fn func() hash.Hash) {
+	if err := j.hasher.SetHash(fn); err != nil {
+		j.Error = err
+	}
+}
+
+// Buckets returns the number of buckets.
+func (j *Jump) Buckets() int {
+	return j.buckets
+}
+
+// Shard returns the bucket assigned to data, or -1 if the Jump is misconfigured.
+func (j *Jump) Shard(data []byte) int {
+	if j.Error != nil {
+		return -1
+	}
+	return JumpHash(j.sum64(data), j.buckets)
+}
+
+// ShardString returns the bucket assigned to a string.
+func (j *Jump) ShardString(s string) int {
+	return j.Shard([]byte(s))
+}
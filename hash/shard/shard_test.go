@@ -0,0 +1,97 @@
+package shard
+
+import (
+	"crypto/md5"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJumpHash(t *testing.T) {
+	t.Run("invalid buckets", func(t *testing.T) {
+		assert.Equal(t, -1, JumpHash(1, 0))
+		assert.Equal(t, -1, JumpHash(1, -3))
+	})
+
+	t.Run("single bucket", func(t *testing.T) {
+		for key := uint64(0); key < 100; key++ {
+			assert.Equal(t, 0, JumpHash(key, 1))
+		}
+	})
+
+	t.Run("range and stability", func(t *testing.T) {
+		for key := uint64(0); key < 1000; key++ {
+			prev := JumpHash(key, 10)
+			assert.True(t, prev >= 0 && prev < 10)
+			next := JumpHash(key, 11)
+			// keys either stay or move to the new bucket
+			assert.True(t, next == prev || next == 10)
+		}
+	})
+}
+
+func TestJump(t *testing.T) {
+	t.Run("distribution", func(t *testing.T) {
+		j := NewJump(8)
+		assert.Nil(t, j.Error)
+		assert.Equal(t, 8, j.Buckets())
+		counts := make([]int, 8)
+		for i := 0; i < 8000; i++ {
+			counts[j.ShardString(fmt.Sprintf("key-%d", i))]++
+		}
+		for _, c := range counts {
+			assert.InDelta(t, 1000, c, 200)
+		}
+	})
+
+	t.Run("minimal movement", func(t *testing.T) {
+		a, b := NewJump(10), NewJump(11)
+		moved := 0
+		for i := 0; i < 10000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if a.Shard(key) != b.Shard(key) {
+				moved++
+			}
+		}
+		assert.InDelta(t, 10000/11, moved, 200)
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		a, b := NewJump(1000), NewJump(1000)
+		b.SetKey([]byte("dongle"))
+		differ := 0
+		for i := 0; i < 100; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if a.Shard(key) != b.Shard(key) {
+				differ++
+			}
+		}
+		assert.Greater(t, differ, 90)
+	})
+
+	t.Run("custom hash", func(t *testing.T) {
+		for _, fn := range []func() hash.Hash{md5.New, sha512.New} {
+			j := NewJump(4)
+			j.SetHash(fn)
+			assert.Nil(t, j.Error)
+			s := j.ShardString("dongle")
+			assert.True(t, s >= 0 && s < 4)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		j := NewJump(0)
+		assert.IsType(t, InvalidBucketsError(0), j.Error)
+		assert.Equal(t, "hash/shard: invalid number of buckets 0, must be positive", j.Error.Error())
+		assert.Equal(t, -1, j.ShardString("dongle"))
+
+		j = NewJump(4)
+		j.SetHash(func() hash.Hash { return crc32.NewIEEE() })
+		assert.IsType(t, UnsupportedHashError{}, j.Error)
+		assert.Equal(t, -1, j.ShardString("dongle"))
+	})
+}
@@ -0,0 +1,21 @@
+package sm3
+
+import "github.com/dromara/dongle/errcode"
+
+// InvalidStateError represents an error when a serialized hash state is malformed.
+type InvalidStateError struct{}
+
+// Error returns a formatted error message describing the malformed state.
+func (e InvalidStateError) Error() string {
+	return "hash/sm3: invalid hash state"
+}
+
+// Code returns the stable error code DGL-SM3-001.
+func (e InvalidStateError) Code() string {
+	return "DGL-SM3-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidStateError) Fields() map[string]any {
+	return errcode.NewFields("hash/sm3", "SM3", "")
+}
@@ -109,7 +109,43 @@ func (d *digest) Sum(in []byte) []byte {
 	for i := range 8 {
 		binary.BigEndian.PutUint32(out[i*4:], data[i])
 	}
-	return out
+	return in[:len(in)+needed]
+}
+
+// magic identifies serialized SM3 states.
+const magic = "sm3\x01"
+
+// marshaledSize is the size of a serialized state: the magic, the hash values,
+// the buffer and the message length in bits.
+const marshaledSize = len(magic) + 8*4 + BlockSize + 8
+
+// MarshalBinary serializes the state of the running hash, so that a long
+// computation can be checkpointed and resumed with UnmarshalBinary.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	for _, v := range d.h {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	b = append(b, d.x[:d.nx]...)
+	b = append(b, make([]byte, BlockSize-int(d.nx))...)
+	return binary.BigEndian.AppendUint64(b, d.length), nil
+}
+
+// UnmarshalBinary restores a state serialized with MarshalBinary.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) != marshaledSize || string(b[:len(magic)]) != magic {
+		return InvalidStateError{}
+	}
+	b = b[len(magic):]
+	for i := range d.h {
+		d.h[i] = binary.BigEndian.Uint32(b)
+		b = b[4:]
+	}
+	copy(d.x[:], b[:BlockSize])
+	d.length = binary.BigEndian.Uint64(b[BlockSize:])
+	d.nx = uint8(d.length / 8 % BlockSize)
+	return nil
 }
 
 // pad performs message padding according to SM3 standard.
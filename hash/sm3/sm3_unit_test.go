@@ -1,7 +1,9 @@
 package sm3
 
 import (
+	"encoding"
 	"encoding/hex"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -501,6 +503,21 @@ func TestSM3MultipleSums(t *testing.T) {
 	assert.Equal(t, hash1, hash3, "Multiple Sum calls should produce identical results")
 }
 
+// TestSM3SumAppends tests that Sum appends the digest to the given slice
+func TestSM3SumAppends(t *testing.T) {
+	hasher := New()
+	hasher.Write([]byte("test data"))
+	digest := hasher.Sum(nil)
+
+	prefix := []byte("prefix")
+	result := hasher.Sum(prefix)
+	assert.Equal(t, append([]byte("prefix"), digest...), result)
+
+	buf := make([]byte, 3, 64)
+	result = hasher.Sum(buf)
+	assert.Equal(t, append(make([]byte, 3), digest...), result)
+}
+
 // TestSM3WriteAfterSumMultiple tests writing after multiple Sum calls
 func TestSM3WriteAfterSumMultiple(t *testing.T) {
 	hasher := New()
@@ -621,3 +638,24 @@ func TestSM3ProcessBlocksEmptyMessage(t *testing.T) {
 	}
 	assert.Equal(t, expected, result, "processBlocks should return initial hash values for empty message")
 }
+
+func TestSM3MarshalBinary(t *testing.T) {
+	data := []byte(strings.Repeat("abc", 100))
+	whole := New()
+	whole.Write(data)
+	for _, split := range []int{0, 1, 63, 64, 65, 150, len(data)} {
+		h := New()
+		h.Write(data[:split])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		assert.NoError(t, err)
+
+		resumed := New()
+		assert.NoError(t, resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state))
+		resumed.Write(data[split:])
+		assert.Equal(t, whole.Sum(nil), resumed.Sum(nil), "split %d", split)
+	}
+
+	err := New().(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("sm3\x01"))
+	assert.Equal(t, InvalidStateError{}, err)
+	assert.Equal(t, "hash/sm3: invalid hash state", err.Error())
+}
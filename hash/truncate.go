@@ -0,0 +1,61 @@
+package hash
+
+import (
+	"encoding/binary"
+
+	"github.com/dromara/dongle/hash/domain"
+)
+
+// MinTruncateBits is the shortest output TruncateDigest accepts. Below 64 bits
+// a truncated digest collides after about 2^(bits/2) inputs, a few tens of
+// thousands for the 32 bits of eight hex characters. Lower it only for values
+// that are checked against a short list and never used as unique keys.
+var MinTruncateBits = 64
+
+// TruncateDigest returns the leftmost bits of digest, as NIST SP 800-107 does
+// for truncated hash outputs. The result holds bits rounded up to whole bytes,
+// with the unused low bits of the last byte cleared. It returns a
+// TruncationError when bits is below MinTruncateBits or longer than digest.
+func TruncateDigest(digest []byte, bits int) ([]byte, error) {
+	if bits < MinTruncateBits || bits > len(digest)*8 {
+		return nil, TruncationError{Bits: bits, Min: MinTruncateBits, Max: len(digest) * 8}
+	}
+	out := append([]byte(nil), digest[:(bits+7)/8]...)
+	if r := bits % 8; r != 0 {
+		out[len(out)-1] &= 0xff << (8 - r)
+	}
+	return out, nil
+}
+
+// TruncatedSum hashes data with the named algorithm, one of Hashes, and
+// returns the leftmost bits of the digest like TruncateDigest. The label and
+// the output length are framed as by domain.Encode and hashed before the data,
+// so short identifiers derived for different purposes, or with different
+// lengths, are unrelated even for the same data, and a short identifier is
+// never the prefix of a longer one:
+//
+//	id, err := hash.TruncatedSum("sha256", "invoice-id", number, 64)
+func TruncatedSum(algorithm, label string, data []byte, bits int) ([]byte, error) {
+	h, err := NewHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if bits < MinTruncateBits || bits > h.Size()*8 {
+		return nil, TruncationError{Bits: bits, Min: MinTruncateBits, Max: h.Size() * 8}
+	}
+	h.Write(domain.Encode(label, binary.BigEndian.AppendUint64(nil, uint64(bits))))
+	h.Write(data)
+	return TruncateDigest(h.Sum(nil), bits)
+}
+
+// Truncate keeps the leftmost bits of the digest like TruncateDigest, for
+// short identifiers such as
+//
+//	hash.NewHasher().FromString(s).BySha2(256).Truncate(64).ToHexString()
+func (h Hasher) Truncate(bits int) Hasher {
+	if h.Error != nil || len(h.dst) == 0 {
+		return h
+	}
+	h.dst, h.Error = TruncateDigest(h.dst, bits)
+	return h
+}
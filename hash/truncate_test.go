@@ -0,0 +1,77 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateDigest(t *testing.T) {
+	digest := sha256.Sum256([]byte("hello world"))
+
+	out, err := TruncateDigest(digest[:], 64)
+	require.NoError(t, err)
+	assert.Equal(t, digest[:8], out)
+
+	out, err = TruncateDigest(digest[:], 68)
+	require.NoError(t, err)
+	assert.Equal(t, append(digest[:8:8], digest[8]&0xf0), out)
+
+	out, err = TruncateDigest(digest[:], 256)
+	require.NoError(t, err)
+	assert.Equal(t, digest[:], out)
+	out[0] ^= 1
+	assert.NotEqual(t, digest[0], out[0], "the result must not alias the digest")
+
+	for _, bits := range []int{0, 32, 63, 257} {
+		_, err = TruncateDigest(digest[:], bits)
+		assert.Equal(t, TruncationError{Bits: bits, Min: 64, Max: 256}, err)
+	}
+	assert.Equal(t, "hash: cannot truncate digest to 32 bits, must be 64-256 bits", TruncationError{Bits: 32, Min: 64, Max: 256}.Error())
+
+	t.Run("lowered minimum", func(t *testing.T) {
+		defer func(min int) { MinTruncateBits = min }(MinTruncateBits)
+		MinTruncateBits = 32
+		out, err := TruncateDigest(digest[:], 32)
+		require.NoError(t, err)
+		assert.Equal(t, digest[:4], out)
+	})
+}
+
+func TestTruncatedSum(t *testing.T) {
+	data := []byte("hello world")
+	id, err := TruncatedSum("sha256", "invoice-id", data, 64)
+	require.NoError(t, err)
+	assert.Equal(t, "53b51592160dc0e2", hex.EncodeToString(id))
+
+	other, err := TruncatedSum("sha256", "order-id", data, 64)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+	longer, err := TruncatedSum("sha256", "invoice-id", data, 128)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, longer[:8])
+
+	_, err = TruncatedSum("sha256", "invoice-id", data, 32)
+	assert.Equal(t, TruncationError{Bits: 32, Min: 64, Max: 256}, err)
+	_, err = TruncatedSum("md5", "invoice-id", data, 256)
+	assert.Equal(t, TruncationError{Bits: 256, Min: 64, Max: 128}, err)
+	_, err = TruncatedSum("nope", "invoice-id", data, 64)
+	assert.ErrorAs(t, err, &UnknownAlgorithmError{})
+}
+
+func TestHasher_Truncate(t *testing.T) {
+	digest := sha256.Sum256([]byte("hello world"))
+	h := NewHasher().FromString("hello world").BySha2(256).Truncate(64)
+	require.NoError(t, h.Error)
+	assert.Equal(t, digest[:8], h.ToRawBytes())
+	assert.Len(t, h.ToHexString(), 16)
+
+	h = NewHasher().FromString("hello world").BySha2(256).Truncate(32)
+	assert.Equal(t, TruncationError{Bits: 32, Min: 64, Max: 256}, h.Error)
+	assert.Empty(t, h.ToHexString())
+
+	assert.Empty(t, NewHasher().BySha2(256).Truncate(64).ToRawBytes())
+}
@@ -0,0 +1,97 @@
+package hash
+
+import (
+	"hash"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// VerifyingReader is an io.ReadCloser hashing the data read through it and
+// checking the digest against an expected checksum once the underlying reader
+// is exhausted, so a download can be verified while it is written to disk,
+// without buffering it or reading it twice:
+//
+//	r, err := hash.NewVerifyingReader(resp.Body, "sha256", checksum)
+//	if err != nil { ... }
+//	defer r.Close()
+//	if _, err = io.Copy(file, r); err != nil {
+//		// err is a ChecksumMismatchError when the download is corrupt
+//	}
+//
+// The final Read returns a ChecksumMismatchError in place of io.EOF when the
+// digests differ, so io.Copy and io.ReadAll report it. Data already returned
+// by earlier reads must not be trusted until then.
+type VerifyingReader struct {
+	r         io.Reader
+	hash      hash.Hash
+	algorithm string
+	expected  []byte
+	err       error // Result of the verification once done
+	done      bool  // Whether the digest has been verified
+}
+
+// NewVerifyingReader returns a VerifyingReader reading from r and comparing the
+// digest of the named algorithm, one of Hashes, with expected.
+func NewVerifyingReader(r io.Reader, algorithm string, expected []byte) (*VerifyingReader, error) {
+	h, err := NewHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &VerifyingReader{r: r, hash: h, algorithm: algorithm, expected: expected}, nil
+}
+
+// Read implements the io.Reader interface. At the end of the input it returns
+// io.EOF if the digest matches the expected checksum, or a
+// ChecksumMismatchError otherwise.
+func (v *VerifyingReader) Read(p []byte) (int, error) {
+	if v.done {
+		return 0, v.result()
+	}
+	n, err := v.r.Read(p)
+	v.hash.Write(p[:n])
+	if err == io.EOF {
+		v.verify()
+		return n, v.result()
+	}
+	return n, err
+}
+
+// Close verifies the digest of the data read so far if the end of the input
+// was not reached, so that a partial read is reported as a mismatch, and
+// closes the underlying reader if it is an io.Closer. The verification error
+// takes precedence over the close error.
+func (v *VerifyingReader) Close() error {
+	if !v.done {
+		v.verify()
+	}
+	var err error
+	if c, ok := v.r.(io.Closer); ok {
+		err = c.Close()
+	}
+	if v.err != nil {
+		return v.err
+	}
+	return err
+}
+
+// Sum returns the digest of the data read so far.
+func (v *VerifyingReader) Sum() []byte {
+	return v.hash.Sum(nil)
+}
+
+// verify compares the digest with the expected checksum in constant time.
+func (v *VerifyingReader) verify() {
+	v.done = true
+	if actual := v.hash.Sum(nil); !utils.ConstantTimeEqual(actual, v.expected) {
+		v.err = ChecksumMismatchError{Algorithm: v.algorithm, Expected: v.expected, Actual: actual}
+	}
+}
+
+// result returns the verification error, or io.EOF when the digest matched.
+func (v *VerifyingReader) result() error {
+	if v.err != nil {
+		return v.err
+	}
+	return io.EOF
+}
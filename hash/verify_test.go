@@ -0,0 +1,80 @@
+package hash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyingReader(t *testing.T) {
+	data := bytes.Repeat([]byte("download chunk "), 10000)
+	sum := sha256.Sum256(data)
+
+	t.Run("matching checksum", func(t *testing.T) {
+		r, err := NewVerifyingReader(mock.NewScriptedReader(bytes.NewReader(data)).Then(7).Then(0).Then(4096), "sha256", sum[:])
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		n, err := io.Copy(&buf, r)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+		assert.Equal(t, data, buf.Bytes())
+		assert.Equal(t, sum[:], r.Sum())
+
+		// Reads after the end keep returning io.EOF.
+		_, err = r.Read(make([]byte, 1))
+		assert.Equal(t, io.EOF, err)
+		assert.NoError(t, r.Close())
+	})
+
+	t.Run("corrupt data", func(t *testing.T) {
+		corrupt := bytes.Clone(data)
+		corrupt[len(corrupt)/2] ^= 1
+		r, _ := NewVerifyingReader(bytes.NewReader(corrupt), "SHA256", sum[:])
+		_, err := io.ReadAll(r)
+		var mismatch ChecksumMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		assert.Equal(t, sum[:], mismatch.Expected)
+		assert.Equal(t, r.Sum(), mismatch.Actual)
+		assert.True(t, strings.HasPrefix(err.Error(), "hash: SHA256 checksum mismatch, expected "))
+
+		_, err = r.Read(make([]byte, 1))
+		assert.Equal(t, mismatch, err)
+		assert.Equal(t, mismatch, r.Close())
+	})
+
+	t.Run("truncated download", func(t *testing.T) {
+		closer := mock.NewCloseErrorReadCloser(bytes.NewReader(data[:100]), nil)
+		r, _ := NewVerifyingReader(closer, "sha256", sum[:])
+		_, err := r.Read(make([]byte, 10))
+		assert.NoError(t, err)
+		assert.IsType(t, ChecksumMismatchError{}, r.Close())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		boom := errors.New("connection reset")
+		r, _ := NewVerifyingReader(mock.NewErrorReadAfterN(data, 50, boom), "sha256", sum[:])
+		_, err := io.ReadAll(r)
+		assert.Equal(t, boom, err)
+	})
+
+	t.Run("close error", func(t *testing.T) {
+		boom := errors.New("close failed")
+		r, _ := NewVerifyingReader(mock.NewCloseErrorReadCloser(bytes.NewReader(data), boom), "sha256", sum[:])
+		_, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, boom, r.Close())
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		r, err := NewVerifyingReader(bytes.NewReader(data), "crc32", sum[:])
+		assert.Nil(t, r)
+		assert.IsType(t, UnknownAlgorithmError{}, err)
+	})
+}
@@ -0,0 +1,145 @@
+// Package visual renders digests as pictures people compare at a glance, for
+// checking key fingerprints and file checksums by eye rather than digit by
+// digit:
+//
+//	visual.RandomArt(digest, "[ED25519 256]", "[SHA256]") // as ssh-keygen -lv
+//	visual.Emoji(digest)                                   // "🐶 🔑 🚀 ..."
+//
+// Neither rendering can be decoded back to the digest, and both are only as
+// strong as the bits they show: truncate a digest with hash.TruncateDigest for
+// a shorter rendering, not below hash.MinTruncateBits.
+package visual
+
+import "strings"
+
+// Width and Height are the size of the RandomArt field, those of OpenSSH.
+const (
+	Width  = 17
+	Height = 9
+)
+
+// symbols are the characters of the RandomArt field by number of visits, the
+// last two marking where the walk starts and ends.
+const symbols = " .o+=*BOX@%&#/^SE"
+
+// RandomArt returns the OpenSSH "drunken bishop" randomart of digest, the
+// picture ssh-keygen -lv prints next to a key fingerprint. The bishop starts in
+// the middle of the field and moves diagonally once per two bits of digest,
+// each field character counting how often it was visited. header and footer,
+// such as "[RSA 3072]" and "[SHA256]", are centered in the top and bottom
+// borders and cut to the field width. Lines are separated by newlines and the
+// result has no trailing newline.
+func RandomArt(digest []byte, header, footer string) string {
+	var field [Width][Height]int
+	x, y := Width/2, Height/2
+	maxVisits := len(symbols) - 3
+
+	for _, b := range digest {
+		for range 4 {
+			x += int(b&1)*2 - 1
+			y += int(b&2) - 1
+			x = min(max(x, 0), Width-1)
+			y = min(max(y, 0), Height-1)
+			if field[x][y] < maxVisits {
+				field[x][y]++
+			}
+			b >>= 2
+		}
+	}
+	field[Width/2][Height/2] = len(symbols) - 2
+	field[x][y] = len(symbols) - 1
+
+	var sb strings.Builder
+	sb.Grow((Width + 3) * (Height + 2))
+	border(&sb, header)
+	for j := range Height {
+		sb.WriteByte('|')
+		for i := range Width {
+			sb.WriteByte(symbols[field[i][j]])
+		}
+		sb.WriteString("|\n")
+	}
+	border(&sb, footer)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// border writes a horizontal border of the field with title centered in it.
+func border(sb *strings.Builder, title string) {
+	if len(title) > Width {
+		title = title[:Width]
+	}
+	pad := (Width - len(title)) / 2
+	sb.WriteByte('+')
+	sb.WriteString(strings.Repeat("-", pad))
+	sb.WriteString(title)
+	sb.WriteString(strings.Repeat("-", Width-pad-len(title)))
+	sb.WriteString("+\n")
+}
+
+// Emojis are the 64 emojis of Emoji, those of the Matrix short authentication
+// strings, chosen to be told apart and named easily. EmojiNames holds the name
+// of each, for reading a fingerprint aloud.
+var (
+	Emojis = [64]string{
+		"🐶", "🐱", "🦁", "🐎", "🦄", "🐷", "🐘", "🐰",
+		"🐼", "🐓", "🐧", "🐢", "🐟", "🐙", "🦋", "🌷",
+		"🌳", "🌵", "🍄", "🌏", "🌙", "☁️", "🔥", "🍌",
+		"🍎", "🍓", "🌽", "🍕", "🎂", "❤️", "😀", "🤖",
+		"🎩", "👓", "🔧", "🎅", "👍", "☂️", "⌛", "⏰",
+		"🎁", "💡", "📕", "✏️", "📎", "✂️", "🔒", "🔑",
+		"🔨", "☎️", "🏁", "🚂", "🚲", "✈️", "🚀", "🏆",
+		"⚽", "🎸", "🎺", "🔔", "⚓", "🎧", "📁", "📌",
+	}
+	EmojiNames = [64]string{
+		"Dog", "Cat", "Lion", "Horse", "Unicorn", "Pig", "Elephant", "Rabbit",
+		"Panda", "Rooster", "Penguin", "Turtle", "Fish", "Octopus", "Butterfly", "Flower",
+		"Tree", "Cactus", "Mushroom", "Globe", "Moon", "Cloud", "Fire", "Banana",
+		"Apple", "Strawberry", "Corn", "Pizza", "Cake", "Heart", "Smiley", "Robot",
+		"Hat", "Glasses", "Spanner", "Santa", "Thumbs Up", "Umbrella", "Hourglass", "Clock",
+		"Gift", "Light Bulb", "Book", "Pencil", "Paperclip", "Scissors", "Lock", "Key",
+		"Hammer", "Telephone", "Flag", "Train", "Bicycle", "Aeroplane", "Rocket", "Trophy",
+		"Ball", "Guitar", "Trumpet", "Bell", "Anchor", "Headphones", "Folder", "Pin",
+	}
+)
+
+// Emoji returns digest as emojis separated by spaces, one of Emojis for every
+// six bits, most significant first. The last emoji is padded with zero bits,
+// so a 256-bit digest gives 43 emojis.
+func Emoji(digest []byte) string {
+	indexes := emojiIndexes(digest)
+	var sb strings.Builder
+	for i, k := range indexes {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(Emojis[k])
+	}
+	return sb.String()
+}
+
+// EmojiText returns the names of the emojis of Emoji separated by commas, such
+// as "Dog, Key, Rocket".
+func EmojiText(digest []byte) string {
+	indexes := emojiIndexes(digest)
+	names := make([]string, len(indexes))
+	for i, k := range indexes {
+		names[i] = EmojiNames[k]
+	}
+	return strings.Join(names, ", ")
+}
+
+// emojiIndexes splits digest into six bit groups.
+func emojiIndexes(digest []byte) []int {
+	n := (len(digest)*8 + 5) / 6
+	indexes := make([]int, n)
+	for i := range n {
+		for j := range 6 {
+			bit := i*6 + j
+			indexes[i] <<= 1
+			if bit/8 < len(digest) {
+				indexes[i] |= int(digest[bit/8] >> (7 - bit%8) & 1)
+			}
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,80 @@
+package visual
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sshDigest is the SHA-256 digest of the OpenSSH public key
+// AAAAC3NzaC1lZDI1NTE5AAAAIMQHicVEx/ABH5I9XDeRydnhN2USfc/9n0Ie4bUqoAeP,
+// and sshArt its randomart as printed by ssh-keygen -lv.
+var (
+	sshDigest, _ = hex.DecodeString("f2138a59228eb5079e04e0df9a0b005fa5a85242d767c9365230dfe0e2487a88")
+	sshArt       = `+--[ED25519 256]--+
+|.. ..o=o.        |
+|+ .. =+Bo        |
+|+.o.o.=o..       |
+|o*=.+ .          |
+|Eo*+.oo S        |
+|o*.=o= + .       |
+|..=o+ . o        |
+|  ...    .       |
+|   .             |
++----[SHA256]-----+`
+)
+
+func TestRandomArt(t *testing.T) {
+	t.Run("ssh-keygen", func(t *testing.T) {
+		assert.Equal(t, sshArt, RandomArt(sshDigest, "[ED25519 256]", "[SHA256]"))
+	})
+
+	t.Run("empty digest", func(t *testing.T) {
+		art := strings.Split(RandomArt(nil, "", ""), "\n")
+		assert.Len(t, art, Height+2)
+		assert.Equal(t, "+"+strings.Repeat("-", Width)+"+", art[0])
+		assert.Equal(t, "|        E        |", art[5])
+	})
+
+	t.Run("long titles", func(t *testing.T) {
+		art := strings.Split(RandomArt(sshDigest, "[ED25519-CERT 256 bits]", "x"), "\n")
+		assert.Equal(t, "+[ED25519-CERT 256+", art[0])
+		assert.Equal(t, "+--------x--------+", art[Height+1])
+	})
+
+	t.Run("visits saturate", func(t *testing.T) {
+		// The walk steps down right and back, ending where it started
+		art := strings.Split(RandomArt(bytes.Repeat([]byte{0x33}, 64), "", ""), "\n")
+		assert.Equal(t, "|        E        |", art[5])
+		assert.Equal(t, "|         ^       |", art[6])
+	})
+}
+
+func TestEmoji(t *testing.T) {
+	assert.Equal(t, "", Emoji(nil))
+	assert.Equal(t, "🐶 🐱 🦁 🐎", Emoji([]byte{0x00, 0x10, 0x83}))
+	assert.Equal(t, "📌 📌 📌 📌 📌 🔨", Emoji([]byte{0xff, 0xff, 0xff, 0xff}))
+
+	emoji := Emoji(sshDigest)
+	assert.Len(t, strings.Fields(emoji), 43)
+	assert.True(t, utf8.ValidString(emoji))
+}
+
+func TestEmojiText(t *testing.T) {
+	assert.Equal(t, "", EmojiText(nil))
+	assert.Equal(t, "Dog, Cat, Lion, Horse", EmojiText([]byte{0x00, 0x10, 0x83}))
+	assert.Equal(t, "Pin, Pin, Pin, Pin, Pin, Hammer", EmojiText([]byte{0xff, 0xff, 0xff, 0xff}))
+}
+
+func TestEmojis(t *testing.T) {
+	seen := map[string]bool{}
+	for i, e := range Emojis {
+		assert.False(t, seen[e], "duplicate emoji %s", e)
+		seen[e] = true
+		assert.NotEmpty(t, EmojiNames[i])
+	}
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidWorkerError represents an error when the worker ID is out of range.
+type InvalidWorkerError int64
+
+// Error returns a formatted error message describing the invalid worker ID.
+func (e InvalidWorkerError) Error() string {
+	return fmt.Sprintf("id/snowflake: invalid worker id %d, must be between 0 and %d", int64(e), MaxWorker)
+}
+
+// Code returns the stable error code DGL-SNOWFLAKE-001.
+func (e InvalidWorkerError) Code() string {
+	return "DGL-SNOWFLAKE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidWorkerError) Fields() map[string]any {
+	return errcode.NewFields("id/snowflake", "Snowflake", "", "worker_id", int64(e))
+}
+
+// ClockError represents an error when the clock is before the configured epoch.
+type ClockError struct{}
+
+// Error returns a formatted error message describing the clock failure.
+func (e ClockError) Error() string {
+	return "id/snowflake: current time is before the epoch"
+}
+
+// Code returns the stable error code DGL-SNOWFLAKE-002.
+func (e ClockError) Code() string {
+	return "DGL-SNOWFLAKE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ClockError) Fields() map[string]any {
+	return errcode.NewFields("id/snowflake", "Snowflake", "")
+}
+
+// TimeOverflowError represents an error when the timestamp no longer fits in 41 bits.
+type TimeOverflowError struct{}
+
+// Error returns a formatted error message describing the timestamp overflow.
+func (e TimeOverflowError) Error() string {
+	return "id/snowflake: timestamp exceeds the maximum of 41 bits"
+}
+
+// Code returns the stable error code DGL-SNOWFLAKE-003.
+func (e TimeOverflowError) Code() string {
+	return "DGL-SNOWFLAKE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e TimeOverflowError) Fields() map[string]any {
+	return errcode.NewFields("id/snowflake", "Snowflake", "")
+}
+
+// EmptyKeyError represents an error when the obfuscation key is empty.
+type EmptyKeyError struct{}
+
+// Error returns a formatted error message describing the empty key.
+func (e EmptyKeyError) Error() string {
+	return "id/snowflake: obfuscation key cannot be empty"
+}
+
+// Code returns the stable error code DGL-SNOWFLAKE-004.
+func (e EmptyKeyError) Code() string {
+	return "DGL-SNOWFLAKE-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptyKeyError) Fields() map[string]any {
+	return errcode.NewFields("id/snowflake", "Snowflake", "key")
+}
+
+// NegativeIDError represents an error when an identifier is negative.
+type NegativeIDError int64
+
+// Error returns a formatted error message describing the negative identifier.
+func (e NegativeIDError) Error() string {
+	return fmt.Sprintf("id/snowflake: invalid id %d, must not be negative", int64(e))
+}
+
+// Code returns the stable error code DGL-SNOWFLAKE-005.
+func (e NegativeIDError) Code() string {
+	return "DGL-SNOWFLAKE-005"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e NegativeIDError) Fields() map[string]any {
+	return errcode.NewFields("id/snowflake", "Snowflake", "", "id", int64(e))
+}
@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sync"
+)
+
+// rounds is the number of Feistel rounds.
+const rounds = 8
+
+// Obfuscator maps identifiers to opaque public IDs with a keyed permutation.
+// The permutation is a Feistel network over 64 bits using HMAC-SHA256 as round
+// function, restricted to non-negative int64 values with cycle walking, so every
+// identifier maps to exactly one public ID and back. It is safe for concurrent use.
+type Obfuscator struct {
+	mu    sync.Mutex
+	mac   hash.Hash
+	Error error
+}
+
+// NewObfuscator returns a new Obfuscator keyed with key.
+func NewObfuscator(key []byte) *Obfuscator {
+	o := &Obfuscator{}
+	if len(key) == 0 {
+		o.Error = EmptyKeyError{}
+		return o
+	}
+	o.mac = hmac.New(sha256.New, key)
+	return o
+}
+
+// Obfuscate returns the public ID of a non-negative identifier.
+func (o *Obfuscator) Obfuscate(id int64) (int64, error) {
+	if o.Error != nil {
+		return 0, o.Error
+	}
+	if id < 0 {
+		return 0, NegativeIDError(id)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	x := uint64(id)
+	for {
+		x = o.encrypt(x)
+		if x>>63 == 0 {
+			return int64(x), nil
+		}
+	}
+}
+
+// Deobfuscate returns the identifier of a public ID.
+func (o *Obfuscator) Deobfuscate(id int64) (int64, error) {
+	if o.Error != nil {
+		return 0, o.Error
+	}
+	if id < 0 {
+		return 0, NegativeIDError(id)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	x := uint64(id)
+	for {
+		x = o.decrypt(x)
+		if x>>63 == 0 {
+			return int64(x), nil
+		}
+	}
+}
+
+// encrypt applies the Feistel network to x.
+func (o *Obfuscator) encrypt(x uint64) uint64 {
+	l, r := uint32(x>>32), uint32(x)
+	for i := 0; i < rounds; i++ {
+		l, r = r, l^o.round(i, r)
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// decrypt inverts the Feistel network applied by encrypt.
+func (o *Obfuscator) decrypt(x uint64) uint64 {
+	l, r := uint32(x>>32), uint32(x)
+	for i := rounds - 1; i >= 0; i-- {
+		l, r = r^o.round(i, l), l
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// round computes the keyed round function for round i.
+func (o *Obfuscator) round(i int, v uint32) uint32 {
+	var buf [5]byte
+	buf[0] = byte(i)
+	binary.BigEndian.PutUint32(buf[1:], v)
+	o.mac.Reset()
+	o.mac.Write(buf[:])
+	return binary.BigEndian.Uint32(o.mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObfuscator(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		o := NewObfuscator([]byte("dongle"))
+		for _, id := range []int64{0, 1, 2, 4096, 1 << 40, 1<<63 - 1} {
+			public, err := o.Obfuscate(id)
+			assert.Nil(t, err)
+			assert.GreaterOrEqual(t, public, int64(0))
+			back, err := o.Deobfuscate(public)
+			assert.Nil(t, err)
+			assert.Equal(t, id, back)
+		}
+	})
+
+	t.Run("hides ordering", func(t *testing.T) {
+		o := NewObfuscator([]byte("dongle"))
+		a, _ := o.Obfuscate(100)
+		b, _ := o.Obfuscate(101)
+		assert.NotEqual(t, a+1, b)
+	})
+
+	t.Run("key dependent", func(t *testing.T) {
+		a, _ := NewObfuscator([]byte("key-a")).Obfuscate(42)
+		b, _ := NewObfuscator([]byte("key-b")).Obfuscate(42)
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("bijective on a range", func(t *testing.T) {
+		o := NewObfuscator([]byte("dongle"))
+		seen := make(map[int64]bool)
+		for id := int64(0); id < 2000; id++ {
+			public, _ := o.Obfuscate(id)
+			seen[public] = true
+		}
+		assert.Len(t, seen, 2000)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		o := NewObfuscator([]byte("dongle"))
+		want, _ := o.Obfuscate(7)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					got, _ := o.Obfuscate(7)
+					assert.Equal(t, want, got)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		o := NewObfuscator(nil)
+		assert.IsType(t, EmptyKeyError{}, o.Error)
+		assert.Equal(t, "id/snowflake: obfuscation key cannot be empty", o.Error.Error())
+		_, err := o.Obfuscate(1)
+		assert.Equal(t, o.Error, err)
+		_, err = o.Deobfuscate(1)
+		assert.Equal(t, o.Error, err)
+
+		o = NewObfuscator([]byte("dongle"))
+		_, err = o.Obfuscate(-1)
+		assert.IsType(t, NegativeIDError(0), err)
+		assert.Equal(t, "id/snowflake: invalid id -1, must not be negative", err.Error())
+		_, err = o.Deobfuscate(-1)
+		assert.IsType(t, NegativeIDError(0), err)
+	})
+}
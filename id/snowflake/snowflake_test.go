@@ -0,0 +1,120 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestNewGenerator(t *testing.T) {
+	assert.Nil(t, NewGenerator(0).Error)
+	assert.Nil(t, NewGenerator(MaxWorker).Error)
+
+	g := NewGenerator(MaxWorker + 1)
+	assert.IsType(t, InvalidWorkerError(0), g.Error)
+	assert.Equal(t, "id/snowflake: invalid worker id 1024, must be between 0 and 1023", g.Error.Error())
+	_, err := g.Next()
+	assert.Equal(t, g.Error, err)
+	assert.NotNil(t, NewGenerator(-1).Error)
+}
+
+func TestGenerator_Next(t *testing.T) {
+	now := DefaultEpoch.Add(time.Hour)
+
+	t.Run("layout", func(t *testing.T) {
+		g := NewGenerator(7)
+		g.SetClock(fixedClock(now))
+		id, err := g.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3600000)<<22|7<<12, id)
+
+		created, worker, seq := Decompose(id, DefaultEpoch)
+		assert.True(t, now.Equal(created))
+		assert.Equal(t, int64(7), worker)
+		assert.Equal(t, int64(0), seq)
+	})
+
+	t.Run("sequence within millisecond", func(t *testing.T) {
+		g := NewGenerator(1)
+		g.SetClock(fixedClock(now))
+		a, _ := g.Next()
+		b, _ := g.Next()
+		assert.Equal(t, a+1, b)
+	})
+
+	t.Run("sequence exhaustion borrows next millisecond", func(t *testing.T) {
+		g := NewGenerator(1)
+		g.SetClock(fixedClock(now))
+		var last int64
+		for i := 0; i <= MaxSequence+1; i++ {
+			id, err := g.Next()
+			assert.Nil(t, err)
+			assert.Greater(t, id, last)
+			last = id
+		}
+		created, _, seq := Decompose(last, DefaultEpoch)
+		assert.Equal(t, now.Add(time.Millisecond), created)
+		assert.Equal(t, int64(0), seq)
+	})
+
+	t.Run("clock going backwards", func(t *testing.T) {
+		g := NewGenerator(1)
+		g.SetClock(fixedClock(now))
+		a, _ := g.Next()
+		g.SetClock(fixedClock(now.Add(-time.Second)))
+		b, err := g.Next()
+		assert.Nil(t, err)
+		assert.Greater(t, b, a)
+	})
+
+	t.Run("custom epoch", func(t *testing.T) {
+		epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		g := NewGenerator(1)
+		g.SetEpoch(epoch)
+		g.SetClock(fixedClock(epoch.Add(time.Second)))
+		id, _ := g.Next()
+		created, _, _ := Decompose(id, epoch)
+		assert.Equal(t, epoch.Add(time.Second), created)
+	})
+
+	t.Run("clock errors", func(t *testing.T) {
+		g := NewGenerator(1)
+		g.SetClock(fixedClock(DefaultEpoch.Add(-time.Millisecond)))
+		_, err := g.Next()
+		assert.IsType(t, ClockError{}, err)
+		assert.Equal(t, "id/snowflake: current time is before the epoch", err.Error())
+
+		g.SetClock(fixedClock(DefaultEpoch.Add((MaxTime + 1) * time.Millisecond)))
+		_, err = g.Next()
+		assert.IsType(t, TimeOverflowError{}, err)
+		assert.Equal(t, "id/snowflake: timestamp exceeds the maximum of 41 bits", err.Error())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		g := NewGenerator(3)
+		var mu sync.Mutex
+		seen := make(map[int64]bool)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					id, err := g.Next()
+					assert.Nil(t, err)
+					mu.Lock()
+					seen[id] = true
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, seen, 4000)
+	})
+}
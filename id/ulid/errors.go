@@ -0,0 +1,84 @@
+package ulid
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidULIDError represents an error when a string is not a valid ULID.
+type InvalidULIDError struct {
+	Input string // The malformed input
+}
+
+// Error returns a formatted error message describing the malformed ULID.
+func (e InvalidULIDError) Error() string {
+	return fmt.Sprintf("id/ulid: invalid ULID '%s'", e.Input)
+}
+
+// Code returns the stable error code DGL-ULID-001.
+func (e InvalidULIDError) Code() string {
+	return "DGL-ULID-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidULIDError) Fields() map[string]any {
+	return errcode.NewFields("id/ulid", "ULID", "")
+}
+
+// RandomError represents an error when reading randomness fails.
+type RandomError struct {
+	Err error // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the randomness failure.
+func (e RandomError) Error() string {
+	return fmt.Sprintf("id/ulid: failed to read random data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-ULID-002.
+func (e RandomError) Code() string {
+	return "DGL-ULID-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RandomError) Fields() map[string]any {
+	return errcode.NewFields("id/ulid", "ULID", "random", errcode.FieldCause, e.Err)
+}
+
+// OverflowError represents an error when the random part cannot be incremented
+// any further within the same millisecond.
+type OverflowError struct{}
+
+// Error returns a formatted error message describing the monotonic overflow.
+func (e OverflowError) Error() string {
+	return "id/ulid: monotonic entropy overflow"
+}
+
+// Code returns the stable error code DGL-ULID-003.
+func (e OverflowError) Code() string {
+	return "DGL-ULID-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e OverflowError) Fields() map[string]any {
+	return errcode.NewFields("id/ulid", "ULID", "")
+}
+
+// TimeOverflowError represents an error when the timestamp does not fit in 48 bits.
+type TimeOverflowError uint64
+
+// Error returns a formatted error message describing the timestamp overflow.
+func (e TimeOverflowError) Error() string {
+	return fmt.Sprintf("id/ulid: timestamp %d exceeds the maximum of 48 bits", uint64(e))
+}
+
+// Code returns the stable error code DGL-ULID-004.
+func (e TimeOverflowError) Code() string {
+	return "DGL-ULID-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e TimeOverflowError) Fields() map[string]any {
+	return errcode.NewFields("id/ulid", "ULID", "", "timestamp", uint64(e))
+}
@@ -0,0 +1,226 @@
+// Package ulid implements universally unique lexicographically sortable identifiers.
+// A ULID is made of a 48-bit millisecond timestamp followed by 80 bits of randomness
+// read from crypto/rand, encoded as 26 Crockford base32 characters. Generators are
+// monotonic: identifiers created within the same millisecond increment the random part
+// so they keep sorting in creation order.
+package ulid
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Size is the size of a ULID in bytes.
+const Size = 16
+
+// EncodedSize is the length of the string form of a ULID.
+const EncodedSize = 26
+
+// MaxTime is the maximum timestamp in milliseconds a ULID can hold.
+const MaxTime = 1<<48 - 1
+
+// Alphabet is the Crockford base32 alphabet used to encode ULIDs.
+const Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// decodeMap maps characters to their Crockford base32 values, 0xFF marks invalid characters.
+var decodeMap [256]byte
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = 0xFF
+	}
+	for i := 0; i < len(Alphabet); i++ {
+		decodeMap[Alphabet[i]] = byte(i)
+		decodeMap[strings.ToLower(Alphabet)[i]] = byte(i)
+	}
+}
+
+// ULID represents a universally unique lexicographically sortable identifier.
+type ULID [Size]byte
+
+// Generator creates monotonic ULIDs. It is safe for concurrent use.
+type Generator struct {
+	mu      sync.Mutex
+	entropy io.Reader        // Source of randomness
+	now     func() time.Time // Clock returning the current time
+	lastMs  uint64           // Timestamp of the last generated ULID
+	last    [10]byte         // Random part of the last generated ULID
+}
+
+// NewGenerator returns a new Generator reading randomness from crypto/rand.
+func NewGenerator() *Generator {
+	return &Generator{entropy: utils.Rand(), now: utils.Now}
+}
+
+// SetEntropy sets the source of randomness.
+func (g *Generator) SetEntropy(r io.Reader) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.entropy = r
+}
+
+// SetClock sets the function returning the current time.
+func (g *Generator) SetClock(now func() time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.now = now
+}
+
+// New returns a new ULID for the current time.
+// Within the same millisecond the random part of the previous ULID is incremented,
+// an OverflowError is returned if it cannot be incremented any further. The
+// generator is left unchanged on errors, so it stays monotonic.
+func (g *Generator) New() (u ULID, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms > MaxTime {
+		return u, TimeOverflowError(ms)
+	}
+
+	next := g.last
+	if ms <= g.lastMs && g.lastMs != 0 {
+		// Same (or earlier) millisecond: increment the previous random part
+		ms = g.lastMs
+		if !increment(next[:]) {
+			return u, OverflowError{}
+		}
+	} else if _, err = io.ReadFull(g.entropy, next[:]); err != nil {
+		return u, RandomError{Err: err}
+	}
+	g.lastMs, g.last = ms, next
+
+	u.setTime(ms)
+	copy(u[6:], next[:])
+	return u, nil
+}
+
+// defaultGenerator is the package level generator used by New.
+var defaultGenerator = NewGenerator()
+
+// New returns a new monotonic ULID from the package level generator.
+func New() (ULID, error) {
+	return defaultGenerator.New()
+}
+
+// Make returns a ULID with the given time and entropy, without monotonicity.
+func Make(t time.Time, entropy io.Reader) (u ULID, err error) {
+	ms := uint64(t.UnixMilli())
+	if ms > MaxTime {
+		return u, TimeOverflowError(ms)
+	}
+	if _, err = io.ReadFull(entropy, u[6:]); err != nil {
+		return u, RandomError{Err: err}
+	}
+	u.setTime(ms)
+	return u, nil
+}
+
+// Parse parses a ULID from its 26 character string form, case-insensitively.
+func Parse(s string) (u ULID, err error) {
+	if len(s) != EncodedSize {
+		return u, InvalidULIDError{Input: s}
+	}
+	// The first character only carries 3 bits
+	if decodeMap[s[0]] > 7 {
+		return u, InvalidULIDError{Input: s}
+	}
+
+	var hi, lo uint64 // 128-bit accumulator
+	for i := 0; i < EncodedSize; i++ {
+		v := decodeMap[s[i]]
+		if v == 0xFF {
+			return u, InvalidULIDError{Input: s}
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	for i := 0; i < 8; i++ {
+		u[i] = byte(hi >> (56 - 8*i))
+		u[8+i] = byte(lo >> (56 - 8*i))
+	}
+	return u, nil
+}
+
+// String returns the 26 character Crockford base32 form of the ULID.
+func (u ULID) String() string {
+	var hi, lo uint64
+	for i := 0; i < 8; i++ {
+		hi = hi<<8 | uint64(u[i])
+		lo = lo<<8 | uint64(u[8+i])
+	}
+	var buf [EncodedSize]byte
+	for i := EncodedSize - 1; i >= 0; i-- {
+		buf[i] = Alphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+// Timestamp returns the timestamp of the ULID in milliseconds since the Unix epoch.
+func (u ULID) Timestamp() uint64 {
+	return uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(u[2])<<24 |
+		uint64(u[3])<<16 | uint64(u[4])<<8 | uint64(u[5])
+}
+
+// Time returns the timestamp of the ULID as a time.Time.
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(int64(u.Timestamp()))
+}
+
+// Entropy returns the random part of the ULID.
+func (u ULID) Entropy() []byte {
+	return append([]byte(nil), u[6:]...)
+}
+
+// Compare returns -1, 0 or 1 if u sorts before, equal to or after o.
+func (u ULID) Compare(o ULID) int {
+	for i := range u {
+		if u[i] != o[i] {
+			if u[i] < o[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (u *ULID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// setTime stores the millisecond timestamp in the first 6 bytes.
+func (u *ULID) setTime(ms uint64) {
+	for i := 0; i < 6; i++ {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+}
+
+// increment adds one to a big-endian number, reporting false on overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
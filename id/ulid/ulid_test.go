@@ -0,0 +1,176 @@
+package ulid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test data from the ULID specification
+var (
+	ulidTime   = time.UnixMilli(1469918176385)
+	ulidPrefix = "01ARYZ6S41"
+)
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestMake(t *testing.T) {
+	t.Run("zero entropy", func(t *testing.T) {
+		u, err := Make(ulidTime, bytes.NewReader(make([]byte, 10)))
+		assert.Nil(t, err)
+		assert.Equal(t, ulidPrefix+"0000000000000000", u.String())
+		assert.Equal(t, uint64(1469918176385), u.Timestamp())
+		assert.True(t, ulidTime.Equal(u.Time()))
+		assert.Equal(t, make([]byte, 10), u.Entropy())
+	})
+
+	t.Run("max value", func(t *testing.T) {
+		u, err := Make(time.UnixMilli(MaxTime), bytes.NewReader(bytes.Repeat([]byte{0xFF}, 10)))
+		assert.Nil(t, err)
+		assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", u.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Make(time.UnixMilli(MaxTime+1), bytes.NewReader(make([]byte, 10)))
+		assert.IsType(t, TimeOverflowError(0), err)
+		assert.Contains(t, err.Error(), "exceeds the maximum of 48 bits")
+
+		_, err = Make(ulidTime, mock.NewErrorFile(errors.New("read failed")))
+		assert.IsType(t, RandomError{}, err)
+		assert.Contains(t, err.Error(), "read failed")
+	})
+}
+
+func TestGenerator_New(t *testing.T) {
+	t.Run("monotonic within millisecond", func(t *testing.T) {
+		g := NewGenerator()
+		g.SetClock(fixedClock(ulidTime))
+		g.SetEntropy(bytes.NewReader(make([]byte, 10)))
+		a, err := g.New()
+		assert.Nil(t, err)
+		b, err := g.New()
+		assert.Nil(t, err)
+		assert.Equal(t, ulidPrefix+"0000000000000000", a.String())
+		assert.Equal(t, ulidPrefix+"0000000000000001", b.String())
+		assert.Equal(t, -1, a.Compare(b))
+	})
+
+	t.Run("clock going backwards", func(t *testing.T) {
+		g := NewGenerator()
+		g.SetClock(fixedClock(ulidTime))
+		a, _ := g.New()
+		g.SetClock(fixedClock(ulidTime.Add(-time.Second)))
+		b, err := g.New()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, b.Compare(a))
+		assert.Equal(t, a.Timestamp(), b.Timestamp())
+	})
+
+	t.Run("new millisecond draws fresh entropy", func(t *testing.T) {
+		g := NewGenerator()
+		g.SetClock(fixedClock(ulidTime))
+		a, _ := g.New()
+		g.SetClock(fixedClock(ulidTime.Add(time.Millisecond)))
+		b, _ := g.New()
+		assert.Equal(t, a.Timestamp()+1, b.Timestamp())
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		g := NewGenerator()
+		g.SetClock(fixedClock(ulidTime))
+		g.SetEntropy(bytes.NewReader(bytes.Repeat([]byte{0xFF}, 10)))
+		_, err := g.New()
+		assert.Nil(t, err)
+		_, err = g.New()
+		assert.IsType(t, OverflowError{}, err)
+		assert.Equal(t, "id/ulid: monotonic entropy overflow", err.Error())
+
+		// The overflow does not wrap the random part around to zero
+		_, err = g.New()
+		assert.IsType(t, OverflowError{}, err)
+		g.SetClock(fixedClock(ulidTime.Add(time.Millisecond)))
+		g.SetEntropy(bytes.NewReader(make([]byte, 10)))
+		u, err := g.New()
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(ulidTime.UnixMilli())+1, u.Timestamp())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		g := NewGenerator()
+		g.SetClock(fixedClock(time.UnixMilli(MaxTime + 1)))
+		_, err := g.New()
+		assert.IsType(t, TimeOverflowError(0), err)
+
+		g = NewGenerator()
+		g.SetEntropy(mock.NewErrorFile(errors.New("read failed")))
+		_, err = g.New()
+		assert.IsType(t, RandomError{}, err)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var mu sync.Mutex
+		var ids []string
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					u, err := New()
+					assert.Nil(t, err)
+					mu.Lock()
+					ids = append(ids, u.String())
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		unique := make(map[string]bool)
+		for _, id := range ids {
+			unique[id] = true
+		}
+		assert.Len(t, unique, 400)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		u, _ := New()
+		parsed, err := Parse(u.String())
+		assert.Nil(t, err)
+		assert.Equal(t, u, parsed)
+		assert.Equal(t, 0, u.Compare(parsed))
+	})
+
+	t.Run("case insensitive", func(t *testing.T) {
+		u, err := Parse("01aryz6s41tsv4rrffq69g5fav")
+		assert.Nil(t, err)
+		assert.Equal(t, "01ARYZ6S41TSV4RRFFQ69G5FAV", u.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"", "01ARYZ6S41", "81ARYZ6S41TSV4RRFFQ69G5FAV", "01ARYZ6S41TSV4RRFFQ69G5FAU"} {
+			_, err := Parse(s)
+			assert.IsType(t, InvalidULIDError{}, err, s)
+		}
+	})
+
+	t.Run("text marshaling", func(t *testing.T) {
+		u, _ := New()
+		b, err := json.Marshal(u)
+		assert.Nil(t, err)
+		var decoded ULID
+		assert.Nil(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, u, decoded)
+		err = decoded.UnmarshalText([]byte("bad"))
+		assert.Equal(t, "id/ulid: invalid ULID 'bad'", err.Error())
+	})
+}
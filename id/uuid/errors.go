@@ -0,0 +1,47 @@
+package uuid
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// InvalidUUIDError represents an error when a string is not a valid UUID.
+type InvalidUUIDError struct {
+	Input string // The malformed input
+}
+
+// Error returns a formatted error message describing the malformed UUID.
+func (e InvalidUUIDError) Error() string {
+	return fmt.Sprintf("id/uuid: invalid UUID '%s'", e.Input)
+}
+
+// Code returns the stable error code DGL-UUID-001.
+func (e InvalidUUIDError) Code() string {
+	return "DGL-UUID-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidUUIDError) Fields() map[string]any {
+	return errcode.NewFields("id/uuid", "UUID", "")
+}
+
+// RandomError represents an error when reading randomness fails.
+type RandomError struct {
+	Err error // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the randomness failure.
+func (e RandomError) Error() string {
+	return fmt.Sprintf("id/uuid: failed to read random data: %v", e.Err)
+}
+
+// Code returns the stable error code DGL-UUID-002.
+func (e RandomError) Code() string {
+	return "DGL-UUID-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e RandomError) Fields() map[string]any {
+	return errcode.NewFields("id/uuid", "UUID", "random", errcode.FieldCause, e.Err)
+}
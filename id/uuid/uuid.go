@@ -0,0 +1,148 @@
+// Package uuid implements RFC 9562 universally unique identifiers.
+// It provides name-based UUIDs derived from a namespace and a name with MD5 (version 3)
+// or SHA-1 (version 5), and random UUIDs (version 4) backed by crypto/rand.
+package uuid
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// Size is the size of a UUID in bytes.
+const Size = 16
+
+// UUID represents a universally unique identifier.
+type UUID [Size]byte
+
+// Nil is the UUID with all bits set to zero.
+var Nil UUID
+
+// Well-known namespaces defined in RFC 9562 Section 6.6.
+var (
+	NamespaceDNS  = MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceURL  = MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceOID  = MustParse("6ba7b812-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceX500 = MustParse("6ba7b814-9dad-11d1-80b4-00c04fd430c8")
+)
+
+// NewV3 returns a version 3 UUID derived from the namespace and name with MD5.
+func NewV3(namespace UUID, name []byte) UUID {
+	return newHashed(md5.New(), 3, namespace, name)
+}
+
+// NewV5 returns a version 5 UUID derived from the namespace and name with SHA-1.
+func NewV5(namespace UUID, name []byte) UUID {
+	return newHashed(sha1.New(), 5, namespace, name)
+}
+
+// NewV4 returns a random version 4 UUID read from crypto/rand.
+func NewV4() (UUID, error) {
+	return NewV4FromReader(utils.Rand())
+}
+
+// NewV4FromReader returns a random version 4 UUID read from r.
+func NewV4FromReader(r io.Reader) (u UUID, err error) {
+	if _, err = io.ReadFull(r, u[:]); err != nil {
+		return Nil, RandomError{Err: err}
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+// Parse parses a UUID in the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form,
+// optionally wrapped in braces or prefixed with "urn:uuid:", or as 32 hex digits.
+func Parse(s string) (u UUID, err error) {
+	src := s
+	switch {
+	case len(s) == 45 && s[:9] == "urn:uuid:":
+		s = s[9:]
+	case len(s) == 38 && s[0] == '{' && s[37] == '}':
+		s = s[1:37]
+	}
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return Nil, InvalidUUIDError{Input: src}
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return Nil, InvalidUUIDError{Input: src}
+	}
+
+	if _, err = hex.Decode(u[:], []byte(s)); err != nil {
+		return Nil, InvalidUUIDError{Input: src}
+	}
+	return u, nil
+}
+
+// MustParse is like Parse but panics if the string cannot be parsed.
+// It simplifies safe initialization of global variables holding UUIDs.
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// String returns the canonical form of the UUID.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Version returns the version of the UUID.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// IsRFC9562 reports whether the UUID uses the RFC 9562 (formerly RFC 4122) variant.
+func (u UUID) IsRFC9562() bool {
+	return u[8]&0xc0 == 0x80
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// newHashed returns a name-based UUID using the given hash and version.
+func newHashed(h hash.Hash, version byte, namespace UUID, name []byte) (u UUID) {
+	h.Write(namespace[:])
+	h.Write(name)
+	copy(u[:], h.Sum(nil))
+	u.setVersion(version)
+	return u
+}
+
+// setVersion sets the version and the RFC 9562 variant bits.
+func (u *UUID) setVersion(version byte) {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+}
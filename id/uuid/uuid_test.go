@@ -0,0 +1,98 @@
+package uuid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test data for name-based uuid (generated using Python uuid module)
+var (
+	uuidV3DnsDst = "6fa459ea-ee8a-3ca4-894e-db77e160355e"
+	uuidV5DnsDst = "886313e1-3b8a-5372-9b90-0c9aee199e5d"
+	uuidV5UrlDst = "e0a1216a-0784-5ba1-9a3a-9f3409d4d748"
+	uuidV3OidDst = "62d7cefe-a097-3ab0-af17-8c44cdc5b4d9"
+)
+
+func TestNewV3(t *testing.T) {
+	u := NewV3(NamespaceDNS, []byte("python.org"))
+	assert.Equal(t, uuidV3DnsDst, u.String())
+	assert.Equal(t, 3, u.Version())
+	assert.True(t, u.IsRFC9562())
+	assert.Equal(t, uuidV3OidDst, NewV3(NamespaceOID, []byte("1.2.156.10197.1.301")).String())
+}
+
+func TestNewV5(t *testing.T) {
+	u := NewV5(NamespaceDNS, []byte("python.org"))
+	assert.Equal(t, uuidV5DnsDst, u.String())
+	assert.Equal(t, 5, u.Version())
+	assert.True(t, u.IsRFC9562())
+	assert.Equal(t, uuidV5UrlDst, NewV5(NamespaceURL, []byte("https://github.com/dromara/dongle")).String())
+}
+
+func TestNewV4(t *testing.T) {
+	t.Run("random", func(t *testing.T) {
+		a, err := NewV4()
+		assert.Nil(t, err)
+		b, _ := NewV4()
+		assert.NotEqual(t, a, b)
+		assert.Equal(t, 4, a.Version())
+		assert.True(t, a.IsRFC9562())
+	})
+
+	t.Run("from reader", func(t *testing.T) {
+		u, err := NewV4FromReader(bytes.NewReader(make([]byte, 16)))
+		assert.Nil(t, err)
+		assert.Equal(t, "00000000-0000-4000-8000-000000000000", u.String())
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		_, err := NewV4FromReader(mock.NewErrorFile(errors.New("read failed")))
+		assert.IsType(t, RandomError{}, err)
+		assert.Contains(t, err.Error(), "read failed")
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("formats", func(t *testing.T) {
+		want := MustParse(uuidV5DnsDst)
+		for _, s := range []string{
+			"886313E1-3B8A-5372-9B90-0C9AEE199E5D",
+			"{886313e1-3b8a-5372-9b90-0c9aee199e5d}",
+			"urn:uuid:886313e1-3b8a-5372-9b90-0c9aee199e5d",
+			"886313e13b8a53729b900c9aee199e5d",
+		} {
+			u, err := Parse(s)
+			assert.Nil(t, err, s)
+			assert.Equal(t, want, u)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{
+			"", "886313e1", "886313e1_3b8a-5372-9b90-0c9aee199e5d",
+			"886313e1-3b8a-5372-9b90-0c9aee199e5z", "886313e13b8a53729b900c9aee199e5z",
+		} {
+			_, err := Parse(s)
+			assert.IsType(t, InvalidUUIDError{}, err, s)
+		}
+		assert.Panics(t, func() { MustParse("bad") })
+	})
+
+	t.Run("text marshaling", func(t *testing.T) {
+		u := NewV5(NamespaceDNS, []byte("python.org"))
+		b, err := json.Marshal(u)
+		assert.Nil(t, err)
+		assert.Equal(t, `"`+uuidV5DnsDst+`"`, string(b))
+
+		var decoded UUID
+		assert.Nil(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, u, decoded)
+		err = decoded.UnmarshalText([]byte("bad"))
+		assert.Equal(t, "id/uuid: invalid UUID 'bad'", err.Error())
+	})
+}
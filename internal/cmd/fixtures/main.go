@@ -0,0 +1,115 @@
+// Command fixtures generates the known-answer fixtures under testdata/fixtures
+// by running every vector through an independent reference implementation: the
+// Go standard library where it implements the algorithm, and the openssl
+// command line tool otherwise. The fixture tests then check that dongle
+// reproduces every recorded output.
+//
+// Regenerate the fixtures from the module root with
+//
+//	go generate .
+//
+// or run the command directly:
+//
+//	go run ./internal/cmd/fixtures -out testdata/fixtures
+//
+// The -check flag compares freshly generated fixtures with the files on disk
+// instead of writing them, failing when they differ. Adding a new algorithm or
+// mode means adding its vectors to hashVectors or cipherVectors below together
+// with a reference, and mapping its name in the fixture test.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// HashFixture is a known answer for a hash or hmac.
+type HashFixture struct {
+	Algorithm string `json:"algorithm"`     // Algorithm name, e.g. "sha256" or "hmac-sha256"
+	Reference string `json:"reference"`     // Implementation that produced the output
+	Key       string `json:"key,omitempty"` // Hex encoded hmac key
+	Input     string `json:"input"`         // Hex encoded input
+	Output    string `json:"output"`        // Hex encoded digest
+}
+
+// CipherFixture is a known answer for a block or AEAD cipher.
+type CipherFixture struct {
+	Algorithm string `json:"algorithm"`     // Algorithm name, e.g. "aes-128-cbc"
+	Reference string `json:"reference"`     // Implementation that produced the output
+	Padding   string `json:"padding"`       // Padding mode, "pkcs7" or "none"
+	Key       string `json:"key"`           // Hex encoded key
+	IV        string `json:"iv,omitempty"`  // Hex encoded IV or nonce
+	AAD       string `json:"aad,omitempty"` // Hex encoded additional data
+	Input     string `json:"input"`         // Hex encoded plaintext
+	Output    string `json:"output"`        // Hex encoded ciphertext, including any tag
+}
+
+func main() {
+	out := flag.String("out", filepath.Join("testdata", "fixtures"), "output directory")
+	openssl := flag.String("openssl", envOr("OPENSSL", "openssl"), "path of the openssl binary")
+	check := flag.Bool("check", false, "compare with the existing fixtures instead of writing them")
+	flag.Parse()
+
+	if err := run(*out, *openssl, *check); err != nil {
+		fmt.Fprintln(os.Stderr, "fixtures:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the fixtures and writes or checks them.
+func run(out, openssl string, check bool) error {
+	path, err := exec.LookPath(openssl)
+	if err != nil {
+		return fmt.Errorf("openssl not found, set -openssl or $OPENSSL: %w", err)
+	}
+	ref := &reference{openssl: path}
+
+	hashes, err := generateHashes(ref)
+	if err != nil {
+		return err
+	}
+	ciphers, err := generateCiphers(ref)
+	if err != nil {
+		return err
+	}
+	files := map[string]any{"hash.json": hashes, "cipher.json": ciphers}
+	for _, name := range []string{"hash.json", "cipher.json"} {
+		data, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		file := filepath.Join(out, name)
+		if check {
+			old, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(old, data) {
+				return fmt.Errorf("%s is out of date, run go generate", file)
+			}
+			continue
+		}
+		if err = os.MkdirAll(out, 0o755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(file, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", file)
+	}
+	return nil
+}
+
+// envOr returns the environment variable key, or def when it is empty.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
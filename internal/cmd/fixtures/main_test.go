@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	openssl := envOr("OPENSSL", "openssl")
+	if _, err := exec.LookPath(openssl); err != nil {
+		t.Skip("openssl not found")
+	}
+	dir := t.TempDir()
+
+	require.NoError(t, run(dir, openssl, false))
+	assert.FileExists(t, filepath.Join(dir, "hash.json"))
+	assert.FileExists(t, filepath.Join(dir, "cipher.json"))
+	assert.NoError(t, run(dir, openssl, true))
+
+	// The committed fixtures are up to date.
+	assert.NoError(t, run(filepath.Join("..", "..", "..", "testdata", "fixtures"), openssl, true))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hash.json"), []byte("[]\n"), 0o644))
+	assert.ErrorContains(t, run(dir, openssl, true), "out of date")
+
+	assert.ErrorContains(t, run(dir, filepath.Join(dir, "no-openssl"), false), "openssl not found")
+}
+
+func TestHelpers(t *testing.T) {
+	assert.Equal(t, []byte{0, 1, 2}, pattern(3))
+	assert.Equal(t, []byte{2, 1, 0}, reversed(pattern(3)))
+	assert.Equal(t, []byte{'a', 'b', 'c', 5, 5, 5, 5, 5}, pkcs7([]byte("abc"), 8))
+	assert.Len(t, pkcs7(pattern(8), 8), 16)
+	assert.Equal(t, []string{"dgst", "-md5"}, providers(false, "dgst", "-md5"))
+	assert.Equal(t, []string{"dgst", "-provider", "default", "-provider", "legacy", "-md4"}, providers(true, "dgst", "-md4"))
+	t.Setenv("FIXTURES_TEST", "x")
+	assert.Equal(t, "x", envOr("FIXTURES_TEST", "y"))
+	assert.Equal(t, "y", envOr("FIXTURES_TEST_UNSET", "y"))
+}
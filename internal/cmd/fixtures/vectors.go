@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os/exec"
+	"strings"
+)
+
+// messages are the inputs of the hash vectors and the plaintexts of the cipher
+// vectors. None is empty, since dongle yields no output for empty input.
+var messages = [][]byte{
+	[]byte("abc"),
+	[]byte("hello world"),
+	[]byte("The quick brown fox jumps over the lazy dog"),
+	pattern(1000),
+}
+
+// hashVector describes the hash vectors of one algorithm.
+type hashVector struct {
+	name    string           // Algorithm name
+	std     func() hash.Hash // Standard library implementation, if any
+	openssl string           // Name for openssl dgst otherwise
+	legacy  bool             // Whether openssl needs the legacy provider
+	hmac    bool             // Whether to also generate hmac vectors
+}
+
+var hashVectors = []hashVector{
+	{name: "md4", openssl: "md4", legacy: true},
+	{name: "md5", std: md5.New, hmac: true},
+	{name: "sha1", std: sha1.New, hmac: true},
+	{name: "sha224", std: sha256.New224},
+	{name: "sha256", std: sha256.New, hmac: true},
+	{name: "sha384", std: sha512.New384},
+	{name: "sha512", std: sha512.New, hmac: true},
+	{name: "sha3-224", openssl: "sha3-224"},
+	{name: "sha3-256", openssl: "sha3-256"},
+	{name: "sha3-384", openssl: "sha3-384"},
+	{name: "sha3-512", openssl: "sha3-512"},
+	{name: "ripemd160", openssl: "ripemd160"},
+	{name: "sm3", openssl: "sm3"},
+	{name: "blake2b-512", openssl: "blake2b512"},
+	{name: "blake2s-256", openssl: "blake2s256"},
+}
+
+// cipherVector describes the cipher vectors of one algorithm and mode.
+type cipherVector struct {
+	name    string // Algorithm name
+	key     []byte // Key
+	iv      []byte // IV or nonce, nil for ECB
+	aad     []byte // Additional data for AEAD modes
+	padding string // "pkcs7" or "none"
+	std     func(key, iv, aad, src []byte) ([]byte, error)
+	openssl string // Name for openssl enc otherwise
+	legacy  bool   // Whether openssl needs the legacy provider
+}
+
+var (
+	key16 = pattern(16)
+	key24 = pattern(24)
+	key32 = pattern(32)
+	iv8   = reversed(pattern(8))
+	iv12  = reversed(pattern(12))
+	iv16  = reversed(pattern(16))
+	aad   = []byte("dongle fixtures")
+)
+
+var cipherVectors = []cipherVector{
+	{name: "aes-128-cbc", key: key16, iv: iv16, padding: "pkcs7", std: stdCBC(aesBlock)},
+	{name: "aes-192-cbc", key: key24, iv: iv16, padding: "pkcs7", std: stdCBC(aesBlock)},
+	{name: "aes-256-cbc", key: key32, iv: iv16, padding: "pkcs7", std: stdCBC(aesBlock)},
+	{name: "aes-128-ctr", key: key16, iv: iv16, padding: "none", std: stdStream(aesBlock, cipher.NewCTR)},
+	{name: "aes-128-cfb", key: key16, iv: iv16, padding: "none", std: stdStream(aesBlock, cipher.NewCFBEncrypter)},
+	{name: "aes-128-ofb", key: key16, iv: iv16, padding: "none", std: stdStream(aesBlock, cipher.NewOFB)},
+	{name: "aes-128-gcm", key: key16, iv: iv12, aad: aad, padding: "none", std: stdGCM},
+	{name: "aes-256-gcm", key: key32, iv: iv12, aad: aad, padding: "none", std: stdGCM},
+	{name: "aes-128-ecb", key: key16, padding: "pkcs7", openssl: "aes-128-ecb"},
+	{name: "des-cbc", key: pattern(8), iv: iv8, padding: "pkcs7", std: stdCBC(des.NewCipher)},
+	{name: "des-ecb", key: pattern(8), padding: "pkcs7", openssl: "des-ecb", legacy: true},
+	{name: "des-ede3-cbc", key: key24, iv: iv8, padding: "pkcs7", std: stdCBC(des.NewTripleDESCipher)},
+	{name: "sm4-cbc", key: key16, iv: iv16, padding: "pkcs7", openssl: "sm4-cbc"},
+	{name: "sm4-ecb", key: key16, padding: "pkcs7", openssl: "sm4-ecb"},
+	{name: "sm4-ctr", key: key16, iv: iv16, padding: "none", openssl: "sm4-ctr"},
+}
+
+// generateHashes returns the hash fixtures.
+func generateHashes(ref *reference) ([]HashFixture, error) {
+	var fixtures []HashFixture
+	for _, v := range hashVectors {
+		for _, msg := range messages {
+			f := HashFixture{Algorithm: v.name, Input: hex.EncodeToString(msg)}
+			if v.std != nil {
+				h := v.std()
+				h.Write(msg)
+				f.Reference, f.Output = "stdlib", hex.EncodeToString(h.Sum(nil))
+			} else {
+				out, err := ref.dgst(v.openssl, v.legacy, msg)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", v.name, err)
+				}
+				f.Reference, f.Output = "openssl", hex.EncodeToString(out)
+			}
+			fixtures = append(fixtures, f)
+		}
+		if !v.hmac {
+			continue
+		}
+		for _, msg := range messages {
+			h := hmac.New(v.std, key32)
+			h.Write(msg)
+			fixtures = append(fixtures, HashFixture{
+				Algorithm: "hmac-" + v.name,
+				Reference: "stdlib",
+				Key:       hex.EncodeToString(key32),
+				Input:     hex.EncodeToString(msg),
+				Output:    hex.EncodeToString(h.Sum(nil)),
+			})
+		}
+	}
+	return fixtures, nil
+}
+
+// generateCiphers returns the cipher fixtures.
+func generateCiphers(ref *reference) ([]CipherFixture, error) {
+	var fixtures []CipherFixture
+	for _, v := range cipherVectors {
+		for _, msg := range messages {
+			f := CipherFixture{
+				Algorithm: v.name,
+				Padding:   v.padding,
+				Key:       hex.EncodeToString(v.key),
+				IV:        hex.EncodeToString(v.iv),
+				AAD:       hex.EncodeToString(v.aad),
+				Input:     hex.EncodeToString(msg),
+			}
+			var (
+				out []byte
+				err error
+			)
+			if v.std != nil {
+				src := msg
+				if v.padding == "pkcs7" {
+					src = pkcs7(msg, 16)
+					if strings.HasPrefix(v.name, "des") {
+						src = pkcs7(msg, 8)
+					}
+				}
+				f.Reference = "stdlib"
+				out, err = v.std(v.key, v.iv, v.aad, src)
+			} else {
+				f.Reference = "openssl"
+				out, err = ref.enc(v.openssl, v.legacy, v.key, v.iv, v.padding == "pkcs7", msg)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", v.name, err)
+			}
+			f.Output = hex.EncodeToString(out)
+			fixtures = append(fixtures, f)
+		}
+	}
+	return fixtures, nil
+}
+
+// reference runs the openssl command line tool.
+type reference struct {
+	openssl string // Path of the openssl binary
+}
+
+// dgst returns the openssl digest of msg.
+func (r *reference) dgst(name string, legacy bool, msg []byte) ([]byte, error) {
+	return r.run(msg, providers(legacy, "dgst", "-"+name, "-binary")...)
+}
+
+// enc returns the openssl encryption of msg.
+func (r *reference) enc(name string, legacy bool, key, iv []byte, pad bool, msg []byte) ([]byte, error) {
+	args := providers(legacy, "enc", "-e", "-"+name, "-nosalt", "-K", hex.EncodeToString(key))
+	if iv != nil {
+		args = append(args, "-iv", hex.EncodeToString(iv))
+	}
+	if !pad {
+		args = append(args, "-nopad")
+	}
+	return r.run(msg, args...)
+}
+
+// run runs openssl with args, feeding it stdin and returning its output.
+func (r *reference) run(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command(r.openssl, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("openssl %s: %w: %s", strings.Join(args, " "), err, stderr.Bytes())
+	}
+	return out, nil
+}
+
+// providers inserts the openssl 3 provider options enabling legacy algorithms
+// after the subcommand when legacy is set.
+func providers(legacy bool, cmd string, args ...string) []string {
+	if legacy {
+		args = append([]string{"-provider", "default", "-provider", "legacy"}, args...)
+	}
+	return append([]string{cmd}, args...)
+}
+
+// aesBlock is aes.NewCipher.
+var aesBlock = aes.NewCipher
+
+// stdCBC returns a CBC reference for the block cipher constructor newBlock.
+func stdCBC(newBlock func(key []byte) (cipher.Block, error)) func(key, iv, aad, src []byte) ([]byte, error) {
+	return func(key, iv, _, src []byte) ([]byte, error) {
+		block, err := newBlock(key)
+		if err != nil {
+			return nil, err
+		}
+		dst := make([]byte, len(src))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(dst, src)
+		return dst, nil
+	}
+}
+
+// stdStream returns a stream mode reference for the block cipher constructor
+// newBlock and the mode constructor newStream.
+func stdStream(newBlock func(key []byte) (cipher.Block, error), newStream func(cipher.Block, []byte) cipher.Stream) func(key, iv, aad, src []byte) ([]byte, error) {
+	return func(key, iv, _, src []byte) ([]byte, error) {
+		block, err := newBlock(key)
+		if err != nil {
+			return nil, err
+		}
+		dst := make([]byte, len(src))
+		newStream(block, iv).XORKeyStream(dst, src)
+		return dst, nil
+	}
+}
+
+// stdGCM is the AES-GCM reference.
+func stdGCM(key, nonce, aad, src []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nil, nonce, src, aad), nil
+}
+
+// pkcs7 pads src to a multiple of size.
+func pkcs7(src []byte, size int) []byte {
+	n := size - len(src)%size
+	return append(append([]byte(nil), src...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// pattern returns n bytes counting up from 0.
+func pattern(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+// reversed returns b in reverse order.
+func reversed(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+// Command interop generates the cross-language fixtures in interop/fixtures.json:
+// fresh RSA and SM2 key pairs, and the outputs of dongle for AES, SM4, RSA and
+// SM2 over a few messages. The reference scripts next to the fixtures decrypt
+// and verify these outputs with Java, Python and Node.js, and the interop test
+// checks that dongle still reproduces or accepts every one of them.
+//
+// Randomized outputs, such as RSA-OAEP and SM2 ciphertexts or PSS signatures,
+// and the key pairs change on every run, so regenerate the fixtures only when
+// adding vectors, from the module root with
+//
+//	go run ./internal/cmd/interop -out interop
+//
+// and rerun the reference scripts before committing them.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Fixtures is the content of fixtures.json.
+type Fixtures struct {
+	Keys    map[string]Key `json:"keys"`    // Asymmetric key pairs by name
+	Vectors []Vector       `json:"vectors"` // Outputs of dongle
+}
+
+// Key is an asymmetric key pair.
+type Key struct {
+	Public  string `json:"public"`  // PEM encoded PKIX public key
+	Private string `json:"private"` // PEM encoded PKCS#8 private key
+}
+
+// Vector is an output of dongle to decrypt or verify in another language.
+type Vector struct {
+	Algorithm string `json:"algorithm"`     // Algorithm name, e.g. "aes-256-gcm" or "rsa-pss-sha256"
+	Operation string `json:"operation"`     // "encrypt" or "sign"
+	Key       string `json:"key"`           // Hex encoded symmetric key, or the name of a key pair
+	IV        string `json:"iv,omitempty"`  // Hex encoded IV or nonce
+	AAD       string `json:"aad,omitempty"` // Hex encoded additional data
+	Input     string `json:"input"`         // Hex encoded plaintext or signed message
+	Output    string `json:"output"`        // Hex encoded ciphertext, including any tag, or signature
+}
+
+func main() {
+	out := flag.String("out", "interop", "output directory")
+	flag.Parse()
+
+	if err := run(*out); err != nil {
+		fmt.Fprintln(os.Stderr, "interop:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the fixtures and writes them to out.
+func run(out string) error {
+	fixtures, err := generate()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	file := filepath.Join(out, "fixtures.json")
+	if err = os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", file)
+	return nil
+}
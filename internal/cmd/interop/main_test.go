@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, run(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "fixtures.json"))
+	require.NoError(t, err)
+	var fixtures Fixtures
+	require.NoError(t, json.Unmarshal(data, &fixtures))
+	assert.Contains(t, fixtures.Keys, "rsa-2048")
+	assert.Contains(t, fixtures.Keys, "sm2")
+	assert.Len(t, fixtures.Vectors, (len(blockVectors)+7)*len(messages))
+
+	// Vectors of the committed fixtures keep the order of generation.
+	data, err = os.ReadFile(filepath.Join("..", "..", "..", "interop", "fixtures.json"))
+	require.NoError(t, err)
+	var committed Fixtures
+	require.NoError(t, json.Unmarshal(data, &committed))
+	require.Len(t, committed.Vectors, len(fixtures.Vectors))
+	for i, v := range committed.Vectors {
+		assert.Equal(t, fixtures.Vectors[i].Algorithm, v.Algorithm)
+		assert.Equal(t, fixtures.Vectors[i].Input, v.Input)
+	}
+
+	assert.Error(t, run(filepath.Join(dir, "fixtures.json", "sub")))
+}
+
+func TestHelpers(t *testing.T) {
+	assert.Equal(t, []byte{0, 1, 2}, pattern(3))
+	assert.Equal(t, []byte{2, 1, 0}, reversed(pattern(3)))
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto"
+	"encoding/hex"
+	"fmt"
+
+	dongle "github.com/dromara/dongle/crypto"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/keypair"
+)
+
+// messages are the plaintexts and signed messages of the vectors, including
+// non-ASCII text to catch charset mistakes in the reference scripts.
+var messages = [][]byte{
+	[]byte("hello world"),
+	[]byte("dongle interop: 你好，世界 / こんにちは"),
+}
+
+var (
+	key16 = pattern(16)
+	key32 = pattern(32)
+	iv12  = reversed(pattern(12))
+	iv16  = reversed(pattern(16))
+	aad   = []byte("dongle interop")
+)
+
+// blockVector describes the vectors of a symmetric cipher.
+type blockVector struct {
+	name string                                                    // Algorithm name
+	key  []byte                                                    // Key
+	iv   []byte                                                    // IV or nonce
+	aad  []byte                                                    // Additional data for GCM
+	by   func(e dongle.Encrypter, key, iv []byte) dongle.Encrypter // Encrypts with dongle
+}
+
+var blockVectors = []blockVector{
+	{name: "aes-128-cbc", key: key16, iv: iv16, by: byAes(cipher.CBC)},
+	{name: "aes-256-cbc", key: key32, iv: iv16, by: byAes(cipher.CBC)},
+	{name: "aes-256-gcm", key: key32, iv: iv12, aad: aad, by: byAes(cipher.GCM)},
+	{name: "sm4-cbc", key: key16, iv: iv16, by: bySm4(cipher.CBC)},
+	{name: "sm4-gcm", key: key16, iv: iv12, aad: aad, by: bySm4(cipher.GCM)},
+}
+
+// generate returns fixtures with fresh key pairs.
+func generate() (*Fixtures, error) {
+	rsaKey := keypair.NewRsaKeyPair()
+	if err := rsaKey.GenKeyPair(2048); err != nil {
+		return nil, err
+	}
+	sm2Key := keypair.NewSm2KeyPair()
+	if err := sm2Key.GenKeyPair(); err != nil {
+		return nil, err
+	}
+	f := &Fixtures{Keys: map[string]Key{
+		"rsa-2048": {Public: string(rsaKey.PublicKey), Private: string(rsaKey.PrivateKey)},
+		"sm2":      {Public: string(sm2Key.PublicKey), Private: string(sm2Key.PrivateKey)},
+	}}
+
+	for _, v := range blockVectors {
+		for _, m := range messages {
+			e := v.by(dongle.NewEncrypter().FromBytes(m), v.key, v.iv)
+			if e.Error != nil {
+				return nil, fmt.Errorf("%s: %w", v.name, e.Error)
+			}
+			f.add(v.name, "encrypt", hex.EncodeToString(v.key), v.iv, v.aad, m, e.ToRawBytes())
+		}
+	}
+
+	rsaVectors := map[string]keypair.RsaPaddingScheme{
+		"rsa-pkcs1v15":        keypair.PKCS1v15,
+		"rsa-oaep-sha256":     keypair.OAEP,
+		"rsa-pkcs1v15-sha256": keypair.PKCS1v15,
+		"rsa-pss-sha256":      keypair.PSS,
+	}
+	for _, name := range []string{"rsa-pkcs1v15", "rsa-oaep-sha256"} {
+		kp := *rsaKey
+		kp.SetPadding(rsaVectors[name])
+		for _, m := range messages {
+			e := dongle.NewEncrypter().FromBytes(m).ByRsa(&kp)
+			if e.Error != nil {
+				return nil, fmt.Errorf("%s: %w", name, e.Error)
+			}
+			f.add(name, "encrypt", "rsa-2048", nil, nil, m, e.ToRawBytes())
+		}
+	}
+	for _, name := range []string{"rsa-pkcs1v15-sha256", "rsa-pss-sha256"} {
+		kp := *rsaKey
+		kp.SetPadding(rsaVectors[name])
+		kp.SetHash(crypto.SHA256)
+		for _, m := range messages {
+			s := dongle.NewSigner().FromBytes(m).ByRsa(&kp)
+			if s.Error != nil {
+				return nil, fmt.Errorf("%s: %w", name, s.Error)
+			}
+			f.add(name, "sign", "rsa-2048", nil, nil, m, s.ToRawBytes())
+		}
+	}
+
+	sm2Modes := map[string]keypair.Sm2CipherMode{"sm2-c1c3c2": keypair.C1C3C2, "sm2-asn1-c1c3c2": keypair.ASN1C1C3C2}
+	for _, name := range []string{"sm2-c1c3c2", "sm2-asn1-c1c3c2"} {
+		kp := *sm2Key
+		kp.SetMode(sm2Modes[name])
+		for _, m := range messages {
+			e := dongle.NewEncrypter().FromBytes(m).BySm2(&kp)
+			if e.Error != nil {
+				return nil, fmt.Errorf("%s: %w", name, e.Error)
+			}
+			f.add(name, "encrypt", "sm2", nil, nil, m, e.ToRawBytes())
+		}
+	}
+	for _, m := range messages {
+		s := dongle.NewSigner().FromBytes(m).BySm2(sm2Key)
+		if s.Error != nil {
+			return nil, fmt.Errorf("sm2-sm3: %w", s.Error)
+		}
+		f.add("sm2-sm3", "sign", "sm2", nil, nil, m, s.ToRawBytes())
+	}
+	return f, nil
+}
+
+// add appends a vector to f.
+func (f *Fixtures) add(algorithm, operation, key string, iv, aad, input, output []byte) {
+	f.Vectors = append(f.Vectors, Vector{
+		Algorithm: algorithm,
+		Operation: operation,
+		Key:       key,
+		IV:        hex.EncodeToString(iv),
+		AAD:       hex.EncodeToString(aad),
+		Input:     hex.EncodeToString(input),
+		Output:    hex.EncodeToString(output),
+	})
+}
+
+// byAes returns an AES encryption in mode with PKCS#7 padding, or none for GCM.
+func byAes(mode cipher.BlockMode) func(e dongle.Encrypter, key, iv []byte) dongle.Encrypter {
+	return func(e dongle.Encrypter, key, iv []byte) dongle.Encrypter {
+		c := cipher.NewAesCipher(mode)
+		configure(c, mode, key, iv)
+		return e.ByAes(c)
+	}
+}
+
+// bySm4 returns an SM4 encryption in mode with PKCS#7 padding, or none for GCM.
+func bySm4(mode cipher.BlockMode) func(e dongle.Encrypter, key, iv []byte) dongle.Encrypter {
+	return func(e dongle.Encrypter, key, iv []byte) dongle.Encrypter {
+		c := cipher.NewSm4Cipher(mode)
+		configure(c, mode, key, iv)
+		return e.BySm4(c)
+	}
+}
+
+// configure sets the key, IV or nonce, additional data and padding of c.
+func configure(c interface {
+	SetKey([]byte)
+	SetIV([]byte)
+	SetNonce([]byte)
+	SetAAD([]byte)
+	SetPadding(cipher.PaddingMode)
+}, mode cipher.BlockMode, key, iv []byte) {
+	c.SetKey(key)
+	if mode == cipher.GCM {
+		c.SetNonce(iv)
+		c.SetAAD(aad)
+		c.SetPadding(cipher.No)
+		return
+	}
+	c.SetIV(iv)
+	c.SetPadding(cipher.PKCS7)
+}
+
+// pattern returns n bytes counting up from 0.
+func pattern(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+// reversed returns b in reverse order.
+func reversed(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
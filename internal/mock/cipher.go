@@ -0,0 +1,197 @@
+package mock
+
+import (
+	"crypto/cipher"
+	"sync"
+)
+
+// Block is a mock implementation of the cipher.Block interface wrapping a real
+// block cipher with injectable faults. Operations are numbered from 0 in call
+// order, counting Encrypt and Decrypt together, so that a fault can target a
+// specific block of a message. This is useful for testing how block modes and
+// stream wrappers react to a misbehaving cipher.
+type Block struct {
+	mu      sync.Mutex
+	block   cipher.Block // Underlying block cipher
+	corrupt map[int]bool // Operations whose output is corrupted
+	all     bool         // Whether every output is corrupted
+	panics  map[int]any  // Operations that panic with the given value
+	calls   int          // Number of block operations made
+}
+
+// NewBlock creates a new mock Block delegating to block.
+func NewBlock(block cipher.Block) *Block {
+	return &Block{block: block, corrupt: map[int]bool{}, panics: map[int]any{}}
+}
+
+// CorruptAt flips the bits of the first output byte of the given operations
+// and returns the block for chaining.
+func (b *Block) CorruptAt(indexes ...int) *Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, i := range indexes {
+		b.corrupt[i] = true
+	}
+	return b
+}
+
+// CorruptAll flips the bits of the first output byte of every operation and
+// returns the block for chaining.
+func (b *Block) CorruptAll() *Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = true
+	return b
+}
+
+// PanicAt makes the given operation panic with v and returns the block for
+// chaining. As cipher.Block methods cannot return errors, a panic is the only
+// way a block cipher can fail outright.
+func (b *Block) PanicAt(index int, v any) *Block {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.panics[index] = v
+	return b
+}
+
+// BlockSize implements the cipher.Block interface by delegating to the
+// underlying block cipher.
+func (b *Block) BlockSize() int {
+	return b.block.BlockSize()
+}
+
+// Encrypt implements the cipher.Block interface, applying the faults
+// configured for this operation.
+func (b *Block) Encrypt(dst, src []byte) {
+	b.do(b.block.Encrypt, dst, src)
+}
+
+// Decrypt implements the cipher.Block interface, applying the faults
+// configured for this operation.
+func (b *Block) Decrypt(dst, src []byte) {
+	b.do(b.block.Decrypt, dst, src)
+}
+
+// Calls returns the number of block operations made (for testing).
+func (b *Block) Calls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls
+}
+
+// do runs one block operation with its faults.
+func (b *Block) do(fn func(dst, src []byte), dst, src []byte) {
+	b.mu.Lock()
+	index := b.calls
+	b.calls++
+	v, panics := b.panics[index]
+	corrupt := b.all || b.corrupt[index]
+	b.mu.Unlock()
+
+	if panics {
+		panic(v)
+	}
+	fn(dst, src)
+	if corrupt {
+		dst[0] ^= 0xff
+	}
+}
+
+// AEAD is a mock implementation of the cipher.AEAD interface wrapping a real
+// AEAD with injectable faults: corrupted or truncated Seal output and failing
+// Open calls. Seal and Open calls are numbered separately from 0.
+type AEAD struct {
+	mu       sync.Mutex
+	aead     cipher.AEAD   // Underlying AEAD
+	corrupt  bool          // Whether Seal output is corrupted
+	tagSize  int           // Size Seal truncates tags to, or -1
+	openErrs map[int]error // Open calls that fail with the given error
+	seals    int           // Number of Seal calls made
+	opens    int           // Number of Open calls made
+}
+
+// NewAEAD creates a new mock AEAD delegating to aead.
+func NewAEAD(aead cipher.AEAD) *AEAD {
+	return &AEAD{aead: aead, tagSize: -1, openErrs: map[int]error{}}
+}
+
+// CorruptSeal flips the bits of the first ciphertext byte produced by every
+// Seal call, or of the tag when the plaintext is empty, and returns the AEAD
+// for chaining.
+func (a *AEAD) CorruptSeal() *AEAD {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.corrupt = true
+	return a
+}
+
+// ShortTag makes Seal truncate the authentication tag to n bytes while
+// Overhead still reports the full size, and returns the AEAD for chaining.
+func (a *AEAD) ShortTag(n int) *AEAD {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tagSize = n
+	return a
+}
+
+// FailOpenAt makes the given Open call fail with err without decrypting, and
+// returns the AEAD for chaining.
+func (a *AEAD) FailOpenAt(index int, err error) *AEAD {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.openErrs[index] = err
+	return a
+}
+
+// NonceSize implements the cipher.AEAD interface by delegating to the
+// underlying AEAD.
+func (a *AEAD) NonceSize() int {
+	return a.aead.NonceSize()
+}
+
+// Overhead implements the cipher.AEAD interface by delegating to the
+// underlying AEAD.
+func (a *AEAD) Overhead() int {
+	return a.aead.Overhead()
+}
+
+// Seal implements the cipher.AEAD interface, applying the configured faults
+// to the sealed output.
+func (a *AEAD) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	a.mu.Lock()
+	a.seals++
+	corrupt, tagSize := a.corrupt, a.tagSize
+	a.mu.Unlock()
+
+	out := a.aead.Seal(dst, nonce, plaintext, additionalData)
+	sealed := out[len(dst):]
+	if corrupt {
+		sealed[0] ^= 0xff
+	}
+	if tagSize >= 0 && tagSize < a.aead.Overhead() {
+		out = out[:len(out)-a.aead.Overhead()+tagSize]
+	}
+	return out
+}
+
+// Open implements the cipher.AEAD interface, failing the configured calls and
+// delegating the others to the underlying AEAD.
+func (a *AEAD) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	a.mu.Lock()
+	index := a.opens
+	a.opens++
+	err, fails := a.openErrs[index]
+	a.mu.Unlock()
+
+	if fails {
+		return nil, err
+	}
+	return a.aead.Open(dst, nonce, ciphertext, additionalData)
+}
+
+// Calls returns the number of Seal and Open calls made (for testing).
+func (a *AEAD) Calls() (seals, opens int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.seals, a.opens
+}
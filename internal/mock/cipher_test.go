@@ -0,0 +1,130 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAesBlock(t *testing.T) cipher.Block {
+	block, err := aes.NewCipher(bytes.Repeat([]byte{1}, 16))
+	require.NoError(t, err)
+	return block
+}
+
+func TestBlock(t *testing.T) {
+	real := newAesBlock(t)
+	src := bytes.Repeat([]byte{2}, 16)
+	want := make([]byte, 16)
+	real.Encrypt(want, src)
+
+	t.Run("delegates", func(t *testing.T) {
+		b := NewBlock(real)
+		assert.Equal(t, 16, b.BlockSize())
+		dst := make([]byte, 16)
+		b.Encrypt(dst, src)
+		assert.Equal(t, want, dst)
+		b.Decrypt(dst, dst)
+		assert.Equal(t, src, dst)
+		assert.Equal(t, 2, b.Calls())
+	})
+
+	t.Run("corrupt at", func(t *testing.T) {
+		b := NewBlock(real).CorruptAt(1)
+		dst := make([]byte, 16)
+		b.Encrypt(dst, src)
+		assert.Equal(t, want, dst)
+		b.Encrypt(dst, src)
+		assert.Equal(t, want[0]^0xff, dst[0])
+		assert.Equal(t, want[1:], dst[1:])
+		b.Encrypt(dst, src)
+		assert.Equal(t, want, dst)
+	})
+
+	t.Run("corrupt all", func(t *testing.T) {
+		b := NewBlock(real).CorruptAll()
+		dst := make([]byte, 16)
+		for i := 0; i < 3; i++ {
+			b.Encrypt(dst, src)
+			assert.NotEqual(t, want, dst)
+		}
+	})
+
+	t.Run("panic at", func(t *testing.T) {
+		b := NewBlock(real).PanicAt(1, "block failure")
+		dst := make([]byte, 16)
+		assert.NotPanics(t, func() { b.Encrypt(dst, src) })
+		assert.PanicsWithValue(t, "block failure", func() { b.Decrypt(dst, src) })
+	})
+
+	t.Run("in a block mode", func(t *testing.T) {
+		iv := make([]byte, 16)
+		plaintext := bytes.Repeat([]byte("0123456789abcdef"), 3)
+		ciphertext := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(real, iv).CryptBlocks(ciphertext, plaintext)
+
+		// Corrupting the second block decryption only damages that block.
+		out := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(NewBlock(real).CorruptAt(1), iv).CryptBlocks(out, ciphertext)
+		assert.Equal(t, plaintext[:16], out[:16])
+		assert.NotEqual(t, plaintext[16:32], out[16:32])
+		assert.Equal(t, plaintext[32:], out[32:])
+	})
+}
+
+func TestAEAD(t *testing.T) {
+	gcm, err := cipher.NewGCM(newAesBlock(t))
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	plaintext := []byte("hello world")
+
+	t.Run("delegates", func(t *testing.T) {
+		a := NewAEAD(gcm)
+		assert.Equal(t, 12, a.NonceSize())
+		assert.Equal(t, 16, a.Overhead())
+		sealed := a.Seal([]byte("prefix"), nonce, plaintext, nil)
+		assert.Equal(t, gcm.Seal([]byte("prefix"), nonce, plaintext, nil), sealed)
+		opened, err := a.Open(nil, nonce, sealed[6:], nil)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, opened)
+		seals, opens := a.Calls()
+		assert.Equal(t, 1, seals)
+		assert.Equal(t, 1, opens)
+	})
+
+	t.Run("corrupt seal", func(t *testing.T) {
+		a := NewAEAD(gcm).CorruptSeal()
+		sealed := a.Seal([]byte("prefix"), nonce, plaintext, nil)
+		assert.Equal(t, []byte("prefix"), sealed[:6])
+		_, err := gcm.Open(nil, nonce, sealed[6:], nil)
+		assert.Error(t, err)
+		_, err = gcm.Open(nil, nonce, a.Seal(nil, nonce, nil, nil), nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("short tag", func(t *testing.T) {
+		a := NewAEAD(gcm).ShortTag(4)
+		sealed := a.Seal(nil, nonce, plaintext, nil)
+		assert.Len(t, sealed, len(plaintext)+4)
+		assert.Equal(t, 16, a.Overhead())
+		_, err := a.Open(nil, nonce, sealed, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("fail open at", func(t *testing.T) {
+		openErr := errors.New("open failed")
+		a := NewAEAD(gcm).FailOpenAt(1, openErr)
+		sealed := a.Seal(nil, nonce, plaintext, nil)
+		_, err := a.Open(nil, nonce, sealed, nil)
+		assert.NoError(t, err)
+		_, err = a.Open(nil, nonce, sealed, nil)
+		assert.Equal(t, openErr, err)
+		_, err = a.Open(nil, nonce, sealed, nil)
+		assert.NoError(t, err)
+	})
+}
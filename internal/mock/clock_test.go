@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := NewClock(start)
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), c.Now())
+	c.Advance(-2 * time.Hour)
+	assert.Equal(t, start.Add(-time.Hour), c.Now())
+
+	c.Set(start)
+	now := c.Now
+	assert.Equal(t, start, now())
+}
+
+func TestRand(t *testing.T) {
+	a := make([]byte, 50)
+	_, err := io.ReadFull(NewRand([]byte("seed")), a)
+	assert.NoError(t, err)
+
+	// Reads of any size yield the same stream.
+	r := NewRand([]byte("seed"))
+	b := make([]byte, 50)
+	off := 0
+	for _, n := range []int{1, 31, 0, 18} {
+		m, err := r.Read(b[off : off+n])
+		assert.NoError(t, err)
+		assert.Equal(t, n, m)
+		off += n
+	}
+	assert.Equal(t, a, b)
+	assert.Equal(t, 50, r.Total())
+
+	// The first block is SHA-256(seed || 0x0000000000000000).
+	assert.Equal(t, "1a30d3c0635d49b5", hex.EncodeToString(a[:8]))
+
+	c := make([]byte, 50)
+	_, _ = NewRand([]byte("other")).Read(c)
+	assert.NotEqual(t, a, c)
+}
@@ -0,0 +1,252 @@
+package mock
+
+import (
+	"io"
+	"time"
+)
+
+// Step is one scripted result of a Read or Write call on a ScriptedReader or
+// ScriptedWriter.
+type Step struct {
+	N     int           // Maximum bytes to transfer, a negative value means the whole buffer
+	Err   error         // Error to return from the call
+	Delay time.Duration // Latency to inject before the call
+}
+
+// ScriptedReader is a mock io.Reader that plays a sequence of steps, one per
+// Read call, on top of an underlying reader. Once the script is exhausted, reads
+// are delegated to the underlying reader unchanged. This is useful for testing
+// code that must cope with short reads, slow sources and errors in the middle
+// of a stream.
+type ScriptedReader struct {
+	r      io.Reader // Underlying reader providing the data
+	steps  []Step    // Remaining steps of the script
+	repeat []Step    // Steps to start over with once the script is exhausted
+	calls  int       // Number of Read calls made
+}
+
+// NewScriptedReader creates a new ScriptedReader reading from r according to
+// steps.
+func NewScriptedReader(r io.Reader, steps ...Step) *ScriptedReader {
+	return &ScriptedReader{r: r, steps: steps}
+}
+
+// Then appends a step reading at most n bytes, a negative n meaning the whole
+// buffer, and returns the reader for chaining.
+func (s *ScriptedReader) Then(n int) *ScriptedReader {
+	s.steps = append(s.steps, Step{N: n})
+	return s
+}
+
+// ThenError appends a step reading at most n bytes and returning err, and
+// returns the reader for chaining.
+func (s *ScriptedReader) ThenError(n int, err error) *ScriptedReader {
+	s.steps = append(s.steps, Step{N: n, Err: err})
+	return s
+}
+
+// ThenDelay appends a step reading the whole buffer after sleeping d, and
+// returns the reader for chaining.
+func (s *ScriptedReader) ThenDelay(d time.Duration) *ScriptedReader {
+	s.steps = append(s.steps, Step{N: -1, Delay: d})
+	return s
+}
+
+// Repeat makes the reader start the steps appended so far over each time they
+// are exhausted instead of delegating to the underlying reader, so that a whole
+// stream is read with the same pattern of read boundaries. It returns the
+// reader for chaining.
+func (s *ScriptedReader) Repeat() *ScriptedReader {
+	s.repeat = append([]Step(nil), s.steps...)
+	return s
+}
+
+// Read implements the io.Reader interface by playing the next step of the
+// script, or by delegating to the underlying reader once it is exhausted.
+func (s *ScriptedReader) Read(p []byte) (int, error) {
+	s.calls++
+	if len(s.steps) == 0 {
+		s.steps = s.repeat
+	}
+	if len(s.steps) == 0 {
+		return s.r.Read(p)
+	}
+	step := s.steps[0]
+	s.steps = s.steps[1:]
+	time.Sleep(step.Delay)
+	if step.N >= 0 && step.N < len(p) {
+		p = p[:step.N]
+	}
+	n := 0
+	if len(p) > 0 {
+		var err error
+		if n, err = s.r.Read(p); step.Err == nil {
+			return n, err
+		}
+	}
+	return n, step.Err
+}
+
+// Calls returns the number of Read calls made (for testing).
+func (s *ScriptedReader) Calls() int {
+	return s.calls
+}
+
+// ScriptedWriter is a mock io.Writer that plays a sequence of steps, one per
+// Write call, on top of an underlying writer. A step with N smaller than the
+// buffer simulates a short write. Once the script is exhausted, writes are
+// delegated to the underlying writer unchanged.
+type ScriptedWriter struct {
+	w     io.Writer // Underlying writer receiving the data
+	steps []Step    // Remaining steps of the script
+	calls int       // Number of Write calls made
+}
+
+// NewScriptedWriter creates a new ScriptedWriter writing to w according to
+// steps.
+func NewScriptedWriter(w io.Writer, steps ...Step) *ScriptedWriter {
+	return &ScriptedWriter{w: w, steps: steps}
+}
+
+// Then appends a step writing at most n bytes, a negative n meaning the whole
+// buffer, and returns the writer for chaining. A short write made by a step
+// without error reports the short count with a nil error, as a misbehaving
+// writer would.
+func (s *ScriptedWriter) Then(n int) *ScriptedWriter {
+	s.steps = append(s.steps, Step{N: n})
+	return s
+}
+
+// ThenError appends a step writing at most n bytes and returning err, and
+// returns the writer for chaining.
+func (s *ScriptedWriter) ThenError(n int, err error) *ScriptedWriter {
+	s.steps = append(s.steps, Step{N: n, Err: err})
+	return s
+}
+
+// ThenDelay appends a step writing the whole buffer after sleeping d, and
+// returns the writer for chaining.
+func (s *ScriptedWriter) ThenDelay(d time.Duration) *ScriptedWriter {
+	s.steps = append(s.steps, Step{N: -1, Delay: d})
+	return s
+}
+
+// Write implements the io.Writer interface by playing the next step of the
+// script, or by delegating to the underlying writer once it is exhausted.
+func (s *ScriptedWriter) Write(p []byte) (int, error) {
+	s.calls++
+	if len(s.steps) == 0 {
+		return s.w.Write(p)
+	}
+	step := s.steps[0]
+	s.steps = s.steps[1:]
+	time.Sleep(step.Delay)
+	if step.N >= 0 && step.N < len(p) {
+		p = p[:step.N]
+	}
+	n, err := s.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, step.Err
+}
+
+// Calls returns the number of Write calls made (for testing).
+func (s *ScriptedWriter) Calls() int {
+	return s.calls
+}
+
+// NewErrorReadAfterN creates a reader returning the first n bytes of data,
+// possibly over several Read calls, and then err for all subsequent reads.
+// This simulates a source failing part way through, such as a dropped
+// connection.
+func NewErrorReadAfterN(data []byte, n int, err error) io.Reader {
+	if n > len(data) {
+		n = len(data)
+	}
+	return io.MultiReader(&byteReader{data: data[:n]}, NewErrorReadWriteCloser(err))
+}
+
+// NewErrorWriteOnCall creates a writer delegating to w that fails with err on
+// the k-th Write call only, counting from 1.
+func NewErrorWriteOnCall(w io.Writer, k int, err error) *ScriptedWriter {
+	s := NewScriptedWriter(w)
+	for i := 1; i < k; i++ {
+		s.Then(-1)
+	}
+	return s.ThenError(0, err)
+}
+
+// NewShortWriter creates a writer delegating to w that writes at most n bytes
+// per call and returns err whenever it writes less than asked. A nil err
+// simulates a misbehaving writer reporting short writes without an error.
+func NewShortWriter(w io.Writer, n int, err error) io.Writer {
+	return &shortWriter{w: w, n: n, err: err}
+}
+
+// NewLatencyReader creates a reader delegating to r that sleeps d before each
+// Read call, simulating a slow source.
+func NewLatencyReader(r io.Reader, d time.Duration) io.Reader {
+	return &latencyReader{r: r, d: d}
+}
+
+// NewLatencyWriter creates a writer delegating to w that sleeps d before each
+// Write call, simulating a slow sink.
+func NewLatencyWriter(w io.Writer, d time.Duration) io.Writer {
+	return &latencyWriter{w: w, d: d}
+}
+
+// byteReader reads from a byte slice, unlike bytes.Reader it has no WriteTo
+// method, so that io.Copy exercises the caller's buffer.
+type byteReader struct {
+	data []byte
+}
+
+func (b *byteReader) Read(p []byte) (int, error) {
+	if len(b.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, b.data)
+	b.data = b.data[n:]
+	return n, nil
+}
+
+// shortWriter writes at most n bytes per call.
+type shortWriter struct {
+	w   io.Writer
+	n   int
+	err error
+}
+
+func (s *shortWriter) Write(p []byte) (int, error) {
+	if len(p) <= s.n {
+		return s.w.Write(p)
+	}
+	n, err := s.w.Write(p[:s.n])
+	if err != nil {
+		return n, err
+	}
+	return n, s.err
+}
+
+// latencyReader sleeps before each read.
+type latencyReader struct {
+	r io.Reader
+	d time.Duration
+}
+
+func (l *latencyReader) Read(p []byte) (int, error) {
+	time.Sleep(l.d)
+	return l.r.Read(p)
+}
+
+// latencyWriter sleeps before each write.
+type latencyWriter struct {
+	w io.Writer
+	d time.Duration
+}
+
+func (l *latencyWriter) Write(p []byte) (int, error) {
+	time.Sleep(l.d)
+	return l.w.Write(p)
+}
@@ -0,0 +1,193 @@
+package mock
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptedReader(t *testing.T) {
+	t.Run("short reads then delegate", func(t *testing.T) {
+		r := NewScriptedReader(bytes.NewReader([]byte("hello world"))).Then(2).Then(0).Then(3)
+		buf := make([]byte, 16)
+
+		n, err := r.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "he", string(buf[:n]))
+		n, err = r.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		n, err = r.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "llo", string(buf[:n]))
+		n, err = r.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, " world", string(buf[:n]))
+		_, err = r.Read(buf)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, 5, r.Calls())
+	})
+
+	t.Run("error mid stream", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		r := NewScriptedReader(bytes.NewReader([]byte("hello world")), Step{N: 5}, Step{N: 1, Err: readErr})
+		data, err := io.ReadAll(r)
+		assert.Equal(t, readErr, err)
+		assert.Equal(t, "hello ", string(data))
+	})
+
+	t.Run("error overrides eof", func(t *testing.T) {
+		readErr := errors.New("read failed")
+		r := NewScriptedReader(bytes.NewReader(nil)).ThenError(-1, readErr)
+		n, err := r.Read(make([]byte, 4))
+		assert.Equal(t, 0, n)
+		assert.Equal(t, readErr, err)
+	})
+
+	t.Run("repeat", func(t *testing.T) {
+		r := NewScriptedReader(bytes.NewReader([]byte("hello world"))).Then(2).Then(1).Then(5).Repeat()
+		var chunks []string
+		buf := make([]byte, 4)
+		for {
+			n, err := r.Read(buf)
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, string(buf[:n]))
+		}
+		// The read buffer caps the step of five bytes.
+		assert.Equal(t, []string{"he", "l", "lo w", "or", "l", "d"}, chunks)
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		r := NewScriptedReader(bytes.NewReader([]byte("hi"))).ThenDelay(10 * time.Millisecond)
+		start := time.Now()
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", string(data))
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+}
+
+func TestScriptedWriter(t *testing.T) {
+	t.Run("short write without error", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewScriptedWriter(&buf).Then(3)
+		n, err := w.Write([]byte("hello"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		n, err = w.Write([]byte("lo"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, "hello", buf.String())
+		assert.Equal(t, 2, w.Calls())
+	})
+
+	t.Run("partial write with error", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewScriptedWriter(&buf).ThenError(2, io.ErrShortWrite)
+		n, err := w.Write([]byte("hello"))
+		assert.Equal(t, io.ErrShortWrite, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, "he", buf.String())
+	})
+
+	t.Run("underlying error", func(t *testing.T) {
+		writeErr := errors.New("disk full")
+		w := NewScriptedWriter(NewErrorWriteCloser(writeErr), Step{N: -1, Err: io.ErrShortWrite})
+		_, err := w.Write([]byte("hello"))
+		assert.Equal(t, writeErr, err)
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewScriptedWriter(&buf).ThenDelay(10 * time.Millisecond)
+		start := time.Now()
+		_, err := w.Write([]byte("hi"))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+}
+
+func TestNewErrorReadAfterN(t *testing.T) {
+	readErr := errors.New("read failed")
+	r := NewErrorReadAfterN([]byte("hello world"), 7, readErr)
+	buf := make([]byte, 4)
+
+	n, err := r.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "hell", string(buf[:n]))
+	n, err = r.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "o w", string(buf[:n]))
+	_, err = r.Read(buf)
+	assert.Equal(t, readErr, err)
+	_, err = r.Read(buf)
+	assert.Equal(t, readErr, err)
+
+	data, err := io.ReadAll(NewErrorReadAfterN([]byte("hi"), 10, readErr))
+	assert.Equal(t, readErr, err)
+	assert.Equal(t, "hi", string(data))
+}
+
+func TestNewErrorWriteOnCall(t *testing.T) {
+	writeErr := errors.New("write failed")
+	var buf bytes.Buffer
+	w := NewErrorWriteOnCall(&buf, 3, writeErr)
+
+	for i := 1; i <= 4; i++ {
+		_, err := w.Write([]byte{byte('0' + i)})
+		if i == 3 {
+			assert.Equal(t, writeErr, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, "124", buf.String())
+	assert.Equal(t, 4, w.Calls())
+}
+
+func TestNewShortWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewShortWriter(&buf, 4, io.ErrShortWrite)
+	n, err := w.Write([]byte("hello"))
+	assert.Equal(t, io.ErrShortWrite, err)
+	assert.Equal(t, 4, n)
+	n, err = w.Write([]byte("o"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "hello", buf.String())
+
+	n, err = NewShortWriter(io.Discard, 1, nil).Write([]byte("ab"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	writeErr := errors.New("write failed")
+	_, err = NewShortWriter(NewErrorWriteCloser(writeErr), 1, io.ErrShortWrite).Write([]byte("ab"))
+	assert.Equal(t, writeErr, err)
+
+	// io.Copy reports short writes that a writer does not.
+	_, err = io.Copy(NewShortWriter(io.Discard, 1, nil), NewErrorReadAfterN([]byte("ab"), 2, io.EOF))
+	assert.Equal(t, io.ErrShortWrite, err)
+}
+
+func TestLatency(t *testing.T) {
+	start := time.Now()
+	data, err := io.ReadAll(NewLatencyReader(bytes.NewReader([]byte("hi")), 5*time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", string(data))
+	// One read returns the data and another returns io.EOF.
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	var buf bytes.Buffer
+	start = time.Now()
+	_, err = NewLatencyWriter(&buf, 5*time.Millisecond).Write([]byte("hi"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", buf.String())
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+}
@@ -0,0 +1,80 @@
+package mock
+
+import (
+	"crypto"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Signer is a mock implementation of the crypto.Signer and crypto.Decrypter
+// interfaces wrapping a real private key, standing in for a key held in an
+// HSM or a cloud KMS. It counts the calls made and can be made to fail, which
+// is useful for testing that code really delegates to an external key.
+type Signer struct {
+	mu    sync.Mutex
+	key   crypto.Signer // Underlying private key
+	err   error         // Error returned by every call, if any
+	signs int           // Number of Sign calls made
+	decs  int           // Number of Decrypt calls made
+}
+
+// NewSigner creates a new mock Signer delegating to key. The key must also
+// implement crypto.Decrypter for Decrypt to succeed.
+func NewSigner(key crypto.Signer) *Signer {
+	return &Signer{key: key}
+}
+
+// FailWith makes every later Sign and Decrypt call fail with err and returns
+// the signer for chaining.
+func (s *Signer) FailWith(err error) *Signer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	return s
+}
+
+// Public implements the crypto.Signer interface by delegating to the
+// underlying key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// Sign implements the crypto.Signer interface by delegating to the underlying
+// key.
+func (s *Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.mu.Lock()
+	s.signs++
+	err := s.err
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return s.key.Sign(rand, digest, opts)
+}
+
+// Decrypt implements the crypto.Decrypter interface by delegating to the
+// underlying key.
+func (s *Signer) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	s.mu.Lock()
+	s.decs++
+	err := s.err
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	decrypter, ok := s.key.(crypto.Decrypter)
+	if !ok {
+		return nil, errors.New("mock: key does not implement crypto.Decrypter")
+	}
+	return decrypter.Decrypt(rand, msg, opts)
+}
+
+// Calls returns the number of Sign and Decrypt calls made (for testing).
+func (s *Signer) Calls() (signs, decrypts int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.signs, s.decs
+}
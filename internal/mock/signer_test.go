@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("hello world"))
+
+	t.Run("delegates", func(t *testing.T) {
+		s := NewSigner(key)
+		assert.Equal(t, key.Public(), s.Public())
+		sig, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+		assert.NoError(t, err)
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+
+		ciphertext, _ := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, []byte("secret"))
+		plaintext, err := s.Decrypt(rand.Reader, ciphertext, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("secret"), plaintext)
+
+		signs, decrypts := s.Calls()
+		assert.Equal(t, 1, signs)
+		assert.Equal(t, 1, decrypts)
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		want := errors.New("hsm unavailable")
+		s := NewSigner(key).FailWith(want)
+		_, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+		assert.Equal(t, want, err)
+		_, err = s.Decrypt(rand.Reader, nil, nil)
+		assert.Equal(t, want, err)
+		signs, decrypts := s.Calls()
+		assert.Equal(t, 1, signs)
+		assert.Equal(t, 1, decrypts)
+	})
+
+	t.Run("not a decrypter", func(t *testing.T) {
+		_, priv, _ := ed25519.GenerateKey(rand.Reader)
+		_, err := NewSigner(priv).Decrypt(rand.Reader, nil, nil)
+		assert.EqualError(t, err, "mock: key does not implement crypto.Decrypter")
+	})
+}
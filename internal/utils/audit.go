@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// sharedInstance is an instance shared by every goroutine, such as dongle.Hash,
+// with the value it must keep.
+type sharedInstance struct {
+	name string
+	ptr  reflect.Value
+	want any
+}
+
+var shared struct {
+	mu   sync.Mutex
+	list []sharedInstance
+}
+
+// Share registers the instance ptr points to as shared by every goroutine under
+// name. Builds with the dongle_debug tag check on each AuditShared call that it
+// still holds the value it had when registered.
+func Share(name string, ptr any) {
+	v := reflect.ValueOf(ptr)
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	shared.list = append(shared.list, sharedInstance{name: name, ptr: v, want: v.Elem().Interface()})
+}
+
+// AuditShared panics with a SharedInstanceError when an instance registered
+// with Share was modified, which happens when a configured instance is assigned
+// to it, as in
+//
+//	dongle.Hash = dongle.Hash.WithKey(key)
+//
+// after which every goroutine using dongle.Hash silently computes HMACs with
+// that key. The fluent builders call it as they are given their input, so the
+// first use of a modified instance fails, naming the call site using it. Run
+// with -race to also see the assignment, which the race detector reports as a
+// write racing with this check when it happens on another goroutine.
+//
+// It does nothing in builds without the dongle_debug tag.
+func AuditShared() {
+	if !debug {
+		return
+	}
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	for _, s := range shared.list {
+		if err := s.check(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// check returns a SharedInstanceError if the instance no longer has its value.
+func (s sharedInstance) check() error {
+	got := s.ptr.Elem()
+	if reflect.DeepEqual(got.Interface(), s.want) {
+		return nil
+	}
+	var fields []string
+	want := reflect.ValueOf(s.want)
+	if got.Kind() == reflect.Struct {
+		for i := 0; i < got.NumField(); i++ {
+			if !reflect.DeepEqual(fieldValue(got.Field(i)), fieldValue(want.Field(i))) {
+				fields = append(fields, got.Type().Field(i).Name)
+			}
+		}
+	}
+	return SharedInstanceError{Name: s.name, Modified: fields, Caller: callerOutside()}
+}
+
+// fieldValue returns the value of a struct field, exported or not, for
+// comparison. Functions are only comparable to nil, so they are reduced to
+// whether they are set.
+func fieldValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Func:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map, reflect.Pointer, reflect.Interface, reflect.Chan:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if !v.CanInterface() {
+		// Unexported fields cannot be read through Interface, compare their
+		// textual form instead.
+		return fmt.Sprintf("%v", v)
+	}
+	return v.Interface()
+}
+
+// root is the directory of the module sources, derived from this file.
+var root = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(filepath.Dir(filepath.Dir(file))) + string(filepath.Separator)
+}()
+
+// callerOutside returns the file and line of the innermost caller outside the
+// module, or of a test of the module.
+func callerOutside() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		file := filepath.FromSlash(frame.File)
+		if !strings.HasPrefix(file, root) || strings.HasSuffix(file, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// instance stands in for a fluent builder shared by every goroutine.
+type instance struct {
+	src      []byte
+	progress func()
+	Error    error
+}
+
+var sharedInstanceForTest instance
+
+func TestAuditShared(t *testing.T) {
+	Share("sharedInstanceForTest", &sharedInstanceForTest)
+	assert.NotPanics(t, AuditShared)
+
+	sharedInstanceForTest = instance{src: []byte("polluted"), progress: func() {}}
+	t.Cleanup(func() { sharedInstanceForTest = instance{} })
+	if !debug {
+		assert.NotPanics(t, AuditShared)
+		t.Skip("shared instances are only audited with the dongle_debug build tag")
+	}
+
+	defer func() {
+		err, ok := recover().(SharedInstanceError)
+		require.True(t, ok)
+		assert.Equal(t, "sharedInstanceForTest", err.Name)
+		assert.Equal(t, []string{"src", "progress"}, err.Modified)
+		assert.Contains(t, err.Caller, "audit_test.go")
+		assert.Contains(t, err.Error(), "utils: shared instance sharedInstanceForTest was modified (src, progress) and is used at ")
+		assert.Equal(t, "DGL-UTILS-002", err.Code())
+	}()
+	AuditShared()
+}
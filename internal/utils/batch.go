@@ -0,0 +1,34 @@
+package utils
+
+import "sync"
+
+// Batch calls fn for every index in [0, n) on up to workers goroutines. Each
+// goroutine passes its own worker number in [0, workers) so that fn can reuse
+// per-worker state. With workers of 1 or less, fn runs on the calling goroutine.
+func Batch(n, workers int, fn func(worker, i int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(0, i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := range next {
+				fn(worker, i)
+			}
+		}(w)
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	for _, workers := range []int{-1, 0, 1, 4, 100} {
+		var calls atomic.Int64
+		seen := make([]int, 50)
+		Batch(len(seen), workers, func(worker, i int) {
+			assert.GreaterOrEqual(t, worker, 0)
+			assert.Less(t, worker, max(workers, 1))
+			seen[i]++
+			calls.Add(1)
+		})
+		assert.Equal(t, int64(50), calls.Load())
+		for i := range seen {
+			assert.Equal(t, 1, seen[i])
+		}
+	}
+
+	Batch(0, 4, func(worker, i int) { t.Fatal("called for empty batch") })
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	sourceMu sync.RWMutex
+	now      = time.Now
+	random   io.Reader // nil means crypto/rand.Reader
+)
+
+// Now returns the current time from the package clock, which is time.Now
+// unless replaced by SetClock.
+func Now() time.Time {
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+	return now()
+}
+
+// Rand returns a reader drawing from the package randomness source, which is
+// crypto/rand.Reader unless replaced by SetRand. The source is looked up on
+// every read, so readers obtained before a replacement follow it.
+func Rand() io.Reader {
+	return randReader{}
+}
+
+// SetClock replaces the package clock used by key generation, expiring tokens
+// and timestamps, and returns a function restoring the previous clock. It is
+// meant for tests, such as t.Cleanup(utils.SetClock(clock.Now)).
+func SetClock(fn func() time.Time) (restore func()) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	prev := now
+	now = fn
+	return func() {
+		sourceMu.Lock()
+		defer sourceMu.Unlock()
+		now = prev
+	}
+}
+
+// SetRand replaces the package randomness source used for key generation,
+// nonces, IVs and salts, and returns a function restoring the previous source.
+// It is meant for tests and for crypto/entropy.Install, and must never be used
+// with a predictable source in production. A nil r restores crypto/rand.Reader.
+func SetRand(r io.Reader) (restore func()) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	prev := random
+	random = r
+	return func() {
+		sourceMu.Lock()
+		defer sourceMu.Unlock()
+		random = prev
+	}
+}
+
+// randReader reads from the current package randomness source.
+type randReader struct{}
+
+func (randReader) Read(p []byte) (int, error) {
+	sourceMu.RLock()
+	r := random
+	sourceMu.RUnlock()
+	if r == nil {
+		return rand.Reader.Read(p)
+	}
+	return r.Read(p)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClock(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	restore := SetClock(func() time.Time { return fixed })
+	assert.Equal(t, fixed, Now())
+
+	restore()
+	assert.WithinDuration(t, time.Now(), Now(), time.Minute)
+}
+
+func TestSetRand(t *testing.T) {
+	r := Rand()
+	restore := SetRand(bytes.NewReader([]byte{1, 2, 3, 4}))
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(r, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, buf)
+	_, err = io.ReadFull(Rand(), buf)
+	assert.Equal(t, io.EOF, err)
+
+	restore()
+	_, err = io.ReadFull(r, buf)
+	assert.NoError(t, err)
+	assert.NotEqual(t, []byte{1, 2, 3, 4}, buf)
+}
+
+func TestRand_Default(t *testing.T) {
+	// Without SetRand, reads follow crypto/rand.Reader even when it is replaced.
+	readErr := errors.New("rand read error")
+	old := rand.Reader
+	rand.Reader = mock.NewErrorReadWriteCloser(readErr)
+	defer func() { rand.Reader = old }()
+
+	_, err := Rand().Read(make([]byte, 4))
+	assert.Equal(t, readErr, err)
+}
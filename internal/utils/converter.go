@@ -12,11 +12,18 @@ import (
 // This method uses unsafe tricks and relies on Go's current runtime implementation.
 // It is not guaranteed to be safe across all Go versions.
 // Use only when you are sure the []byte will not be modified.
-// For safety, prefer []byte(s) if you need a writable copy.
+// For safety, prefer String2BytesCopy if you need a writable copy.
+//
+// Builds with the dongle_debug tag return a guarded copy instead, see CheckZeroCopy.
 func String2Bytes(s string) []byte {
 	if len(s) == 0 {
 		return []byte{}
 	}
+	if debug {
+		b := []byte(s)
+		guard(b, s)
+		return b
+	}
 	return *(*[]byte)(unsafe.Pointer(
 		&struct {
 			string
@@ -30,14 +37,32 @@ func String2Bytes(s string) []byte {
 // WARNING: The input []byte must not be modified after conversion, as strings in Go are immutable.
 // This method uses unsafe tricks and relies on Go's current runtime implementation.
 // It is not guaranteed to be safe across all Go versions.
-// For safety, prefer string(b) if you need a copy.
+// For safety, prefer Bytes2StringCopy if you need a copy.
+//
+// Builds with the dongle_debug tag return a guarded copy instead, see CheckZeroCopy.
 func Bytes2String(b []byte) string {
 	if len(b) == 0 {
 		return ""
 	}
+	if debug {
+		s := string(b)
+		guard(b, s)
+		return s
+	}
 	return *(*string)(unsafe.Pointer(&b))
 }
 
+// String2BytesCopy converts string to a newly allocated byte slice that is safe to modify.
+func String2BytesCopy(s string) []byte {
+	return []byte(s)
+}
+
+// Bytes2StringCopy converts a byte slice to a newly allocated string that does not
+// change when b is modified afterwards.
+func Bytes2StringCopy(b []byte) string {
+	return string(b)
+}
+
 // Int2Bytes converts int to byte slice encoded as a 4-byte big-endian slice.
 func Int2Bytes(i int) []byte {
 	var buf [4]byte
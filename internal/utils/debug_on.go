@@ -0,0 +1,6 @@
+//go:build dongle_debug
+
+package utils
+
+// debug reports whether the dongle_debug build tag is set.
+const debug = true
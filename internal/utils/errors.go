@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// ZeroCopyMutationError represents an error when a byte slice shared with a
+// string by a zero-copy conversion was modified, detected in debug builds.
+type ZeroCopyMutationError struct {
+	Caller string // File and line of the conversion
+}
+
+// Error returns a formatted error message describing the mutated conversion.
+func (e ZeroCopyMutationError) Error() string {
+	return fmt.Sprintf("utils: byte slice of zero-copy conversion at %s was modified", e.Caller)
+}
+
+// Code returns the stable error code DGL-UTILS-001.
+func (e ZeroCopyMutationError) Code() string {
+	return "DGL-UTILS-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e ZeroCopyMutationError) Fields() map[string]any {
+	return errcode.NewFields("internal/utils", "", "", "caller", e.Caller)
+}
+
+// SharedInstanceError represents an error when an instance shared by every
+// goroutine, such as dongle.Hash, was modified, detected in debug builds.
+type SharedInstanceError struct {
+	Name     string   // Name of the shared instance
+	Modified []string // Fields holding a different value
+	Caller   string   // File and line of the call using the modified instance
+}
+
+// Error returns a formatted error message describing the modified instance.
+func (e SharedInstanceError) Error() string {
+	msg := fmt.Sprintf("utils: shared instance %s was modified", e.Name)
+	if len(e.Modified) > 0 {
+		msg += fmt.Sprintf(" (%s)", strings.Join(e.Modified, ", "))
+	}
+	return msg + fmt.Sprintf(" and is used at %s, assign configured instances to a variable of their own", e.Caller)
+}
+
+// Code returns the stable error code DGL-UTILS-002.
+func (e SharedInstanceError) Code() string {
+	return "DGL-UTILS-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e SharedInstanceError) Fields() map[string]any {
+	return errcode.NewFields("internal/utils", "", "", "instance", e.Name, "caller", e.Caller)
+}
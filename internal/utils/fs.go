@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// fsFile opens the named file on the first read and closes it once it has
+// been read to the end.
+type fsFile struct {
+	fsys   fs.FS
+	name   string
+	file   fs.File // Open file, nil before the first read
+	closed bool
+}
+
+// OpenFS returns the named file in fsys for a single pass of reading. The file
+// must exist, but it is only opened by the first read and closes itself once a
+// read returns io.EOF or an error, so builders that take ownership of it do not
+// need to track it, and a chain failing before it reads leaves nothing open.
+// Builders stopping halfway release it with CloseFS.
+func OpenFS(fsys fs.FS, name string) (fs.File, error) {
+	if fsys == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, err := fs.Stat(fsys, name); err != nil {
+		return nil, err
+	}
+	return &fsFile{fsys: fsys, name: name}, nil
+}
+
+// CloseFS closes r if it was returned by OpenFS. Files supplied by callers are
+// left open, since callers own them.
+func CloseFS(r io.Reader) {
+	if f, ok := r.(*fsFile); ok {
+		f.Close()
+	}
+}
+
+// Stat returns the file information, without opening the file.
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	if f.file != nil {
+		return f.file.Stat()
+	}
+	return fs.Stat(f.fsys, f.name)
+}
+
+// Read opens the file if needed, reads from it and closes it at the end of the
+// input.
+func (f *fsFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, io.EOF
+	}
+	if f.file == nil {
+		file, err := f.fsys.Open(f.name)
+		if err != nil {
+			f.closed = true
+			return 0, err
+		}
+		f.file = file
+	}
+	n, err := f.file.Read(p)
+	if err != nil {
+		f.Close()
+	}
+	return n, err
+}
+
+// Close closes the underlying file once, if it was opened.
+func (f *fsFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// WriteFileAtomic replaces the named file with data atomically. The data is
+// written to a temporary file in the same directory, synced to disk and renamed
+// over path, so readers and crashes see either the old file or the complete new
+// one, never a partial write. The file is created with perm.
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) (err error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+
+	// Sync the directory so that the rename itself survives a crash. Not every
+	// platform supports syncing directories, so failures are ignored.
+	if d, derr := os.Open(dir); derr == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeCountFS counts how many times files are opened from it and closed.
+type closeCountFS struct {
+	fstest.MapFS
+	opens  int
+	closes int
+}
+
+type closeCountFile struct {
+	fs.File
+	fsys *closeCountFS
+}
+
+func (c *closeCountFS) Open(name string) (fs.File, error) {
+	f, err := c.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	c.opens++
+	return &closeCountFile{File: f, fsys: c}, nil
+}
+
+func (f *closeCountFile) Close() error {
+	f.fsys.closes++
+	return f.File.Close()
+}
+
+func TestOpenFS(t *testing.T) {
+	t.Run("reads and closes at eof", func(t *testing.T) {
+		fsys := &closeCountFS{MapFS: fstest.MapFS{"a/b.txt": {Data: []byte("hello")}}}
+		f, err := OpenFS(fsys, "a/b.txt")
+		require.NoError(t, err)
+
+		info, err := f.Stat()
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), info.Size())
+
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello"), data)
+		assert.Equal(t, 1, fsys.closes)
+
+		n, err := f.Read(make([]byte, 1))
+		assert.Zero(t, n)
+		assert.Equal(t, io.EOF, err)
+		assert.NoError(t, f.Close())
+		assert.Equal(t, 1, fsys.closes)
+	})
+
+	t.Run("opens on the first read", func(t *testing.T) {
+		fsys := &closeCountFS{MapFS: fstest.MapFS{"a.txt": {Data: []byte("hello")}}}
+		f, err := OpenFS(fsys, "a.txt")
+		require.NoError(t, err)
+		info, err := f.Stat()
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), info.Size())
+		assert.NoError(t, f.Close())
+		assert.Zero(t, fsys.opens)
+
+		// CloseFS releases a file read halfway, but not other readers
+		f, err = OpenFS(fsys, "a.txt")
+		require.NoError(t, err)
+		_, err = f.Read(make([]byte, 2))
+		require.NoError(t, err)
+		CloseFS(f)
+		assert.Equal(t, 1, fsys.opens)
+		assert.Equal(t, 1, fsys.closes)
+		own, err := fsys.Open("a.txt")
+		require.NoError(t, err)
+		CloseFS(own)
+		assert.Equal(t, 1, fsys.closes)
+	})
+
+	t.Run("removed before reading", func(t *testing.T) {
+		fsys := fstest.MapFS{"a.txt": {Data: []byte("hello")}}
+		f, err := OpenFS(fsys, "a.txt")
+		require.NoError(t, err)
+		delete(fsys, "a.txt")
+		_, err = f.Read(make([]byte, 1))
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		_, err = f.Read(make([]byte, 1))
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := OpenFS(fstest.MapFS{}, "missing.txt")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+	})
+
+	t.Run("nil fs", func(t *testing.T) {
+		_, err := OpenFS(nil, "missing.txt")
+		assert.True(t, errors.Is(err, fs.ErrInvalid))
+	})
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("creates and replaces", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.bin")
+		require.NoError(t, WriteFileAtomic(path, []byte("old"), 0o600))
+		require.NoError(t, WriteFileAtomic(path, []byte("new"), 0o600))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("new"), data)
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+		}
+	})
+
+	t.Run("failed rename removes temp file", func(t *testing.T) {
+		// A non-empty directory cannot be replaced by a file
+		dir := t.TempDir()
+		path := filepath.Join(dir, "data.bin")
+		require.NoError(t, os.MkdirAll(filepath.Join(path, "child"), 0o700))
+
+		assert.Error(t, WriteFileAtomic(path, []byte("new"), 0o600))
+		matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "data.bin")
+		assert.ErrorIs(t, WriteFileAtomic(path, []byte("new"), 0o600), fs.ErrNotExist)
+	})
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// maxGuards is the number of zero-copy conversions tracked at once in debug
+// builds. Older conversions are checked and dropped as new ones are made.
+const maxGuards = 4096
+
+// guarded is a zero-copy conversion made in a debug build: the byte slice
+// shared with the caller and the string it must keep matching.
+type guarded struct {
+	data   []byte
+	want   string
+	caller string
+}
+
+var guards struct {
+	mu   sync.Mutex
+	list []guarded
+}
+
+// guard records that data must keep the contents of want. When the oldest
+// conversion is dropped to make room, it is checked and a mutation panics.
+func guard(data []byte, want string) {
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		caller = fmt.Sprintf("%s:%d", file, line)
+	}
+	guards.mu.Lock()
+	defer guards.mu.Unlock()
+	if len(guards.list) == maxGuards {
+		oldest := guards.list[0]
+		guards.list = guards.list[1:]
+		if err := oldest.check(); err != nil {
+			panic(err)
+		}
+	}
+	guards.list = append(guards.list, guarded{data: data, want: want, caller: caller})
+}
+
+// check returns a ZeroCopyMutationError if the slice no longer matches the string.
+func (g guarded) check() error {
+	if !bytes.Equal(g.data, []byte(g.want)) {
+		return ZeroCopyMutationError{Caller: g.caller}
+	}
+	return nil
+}
+
+// CheckZeroCopy reports whether any byte slice passed to or returned from
+// String2Bytes or Bytes2String was modified after the conversion, and forgets
+// the conversions checked. It only tracks conversions in builds with the
+// dongle_debug tag, where those functions copy instead of aliasing memory, so
+// run tests with -tags dongle_debug and call it at the end of a test to catch
+// misuse before it corrupts strings in production builds.
+func CheckZeroCopy() error {
+	guards.mu.Lock()
+	defer guards.mu.Unlock()
+	list := guards.list
+	guards.list = nil
+	for _, g := range list {
+		if err := g.check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyConversions(t *testing.T) {
+	s := "hello"
+	b := String2BytesCopy(s)
+	b[0] = 'j'
+	assert.Equal(t, "hello", s)
+	assert.Equal(t, []byte{}, String2BytesCopy(""))
+
+	src := []byte("world")
+	str := Bytes2StringCopy(src)
+	src[0] = 'W'
+	assert.Equal(t, "world", str)
+}
+
+func TestCheckZeroCopy(t *testing.T) {
+	require.NoError(t, CheckZeroCopy())
+
+	s := Bytes2String([]byte("shared"))
+	assert.Equal(t, "shared", s)
+	assert.NoError(t, CheckZeroCopy())
+
+	if !debug {
+		t.Skip("conversions are only guarded with the dongle_debug build tag")
+	}
+	b := String2Bytes("read only")
+	b[0] = 'R'
+	err := CheckZeroCopy()
+	require.IsType(t, ZeroCopyMutationError{}, err)
+	assert.Contains(t, err.Error(), "guard_test.go")
+	assert.NoError(t, CheckZeroCopy())
+
+	src := []byte("mutable")
+	Bytes2String(src)
+	src[0] = 'M'
+	assert.IsType(t, ZeroCopyMutationError{}, CheckZeroCopy())
+}
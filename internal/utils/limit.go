@@ -0,0 +1,26 @@
+package utils
+
+import "io"
+
+// limitWriter fails with err once more than n bytes have been written.
+type limitWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// LimitWriter returns a writer that writes to w until n bytes have been written
+// and then fails with err without writing the write that would exceed n.
+func LimitWriter(w io.Writer, n int64, err error) io.Writer {
+	return &limitWriter{w: w, n: n, err: err}
+}
+
+// Write writes p to the underlying writer if it fits within the limit.
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.n {
+		return 0, l.err
+	}
+	n, err := l.w.Write(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitWriter(t *testing.T) {
+	errLimit := errors.New("limit")
+
+	t.Run("within limit", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := LimitWriter(&buf, 5, errLimit)
+		n, err := w.Write([]byte("hel"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		n, err = w.Write([]byte("lo"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, "hello", buf.String())
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := LimitWriter(&buf, 4, errLimit)
+		_, err := w.Write([]byte("hel"))
+		assert.NoError(t, err)
+		n, err := w.Write([]byte("lo"))
+		assert.Equal(t, errLimit, err)
+		assert.Zero(t, n)
+		assert.Equal(t, "hel", buf.String())
+	})
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"io"
+	"io/fs"
+)
+
+// progressReader reports the number of bytes read so far after every read.
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	fn    func(done, total int64)
+}
+
+// ProgressReader returns a reader that reads from r and calls fn with the
+// number of bytes read so far and the total size after every non-empty read.
+// The total is the size of r when it is a regular file, or -1 when unknown.
+// It returns r itself when fn is nil.
+func ProgressReader(r io.Reader, fn func(done, total int64)) io.Reader {
+	if fn == nil {
+		return r
+	}
+	return &progressReader{r: r, total: size(r), fn: fn}
+}
+
+// Read reads from the underlying reader and reports the progress.
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
+
+// size returns the size of r if it is a regular file, or -1.
+func size(r io.Reader) int64 {
+	f, ok := r.(interface{ Stat() (fs.FileInfo, error) })
+	if !ok {
+		return -1
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return -1
+	}
+	return info.Size()
+}
+
+// Buffer returns a copy buffer of n bytes, or of def bytes when n is not positive.
+func Buffer(n, def int) []byte {
+	if n <= 0 {
+		n = def
+	}
+	return make([]byte, n)
+}
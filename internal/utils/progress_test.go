@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dromara/dongle/internal/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReader(t *testing.T) {
+	t.Run("regular file", func(t *testing.T) {
+		fsys := fstest.MapFS{"data.bin": {Data: bytes.Repeat([]byte("x"), 10)}}
+		f, err := fsys.Open("data.bin")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var calls [][2]int64
+		r := ProgressReader(f, func(done, total int64) {
+			calls = append(calls, [2]int64{done, total})
+		})
+		_, err = io.CopyBuffer(struct{ io.Writer }{io.Discard}, r, make([]byte, 4))
+		require.NoError(t, err)
+		assert.Equal(t, [][2]int64{{4, 10}, {8, 10}, {10, 10}}, calls)
+	})
+
+	t.Run("unknown size", func(t *testing.T) {
+		var done, total int64
+		r := ProgressReader(bytes.NewReader([]byte("hello")), func(d, t int64) {
+			done, total = d, t
+		})
+		_, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), done)
+		assert.Equal(t, int64(-1), total)
+	})
+
+	t.Run("stat error", func(t *testing.T) {
+		var total int64
+		r := ProgressReader(mock.NewErrorFile(errors.New("read error")), func(_, t int64) { total = t })
+		_, err := io.ReadAll(r)
+		assert.Error(t, err)
+		assert.Zero(t, total)
+		assert.Equal(t, int64(-1), size(mock.NewErrorFile(errors.New("read error"))))
+	})
+
+	t.Run("nil callback", func(t *testing.T) {
+		src := bytes.NewReader(nil)
+		assert.Same(t, src, ProgressReader(src, nil))
+	})
+}
+
+func TestBuffer(t *testing.T) {
+	assert.Len(t, Buffer(0, 16), 16)
+	assert.Len(t, Buffer(-1, 16), 16)
+	assert.Len(t, Buffer(8, 16), 8)
+}
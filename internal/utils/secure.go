@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ConstantTimeEqual reports whether a and b are equal. The time taken depends
+// only on the lengths of the slices, not on their contents, so it is safe for
+// comparing MACs, digests and other secret values.
+func ConstantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// SecureWipe overwrites b with zeros so that keys and other secrets do not
+// linger in memory after use. Copies made elsewhere, for example by the
+// garbage collector moving a stack, are not affected.
+func SecureWipe(b []byte) {
+	clear(b)
+	// Keep b alive so the compiler cannot drop the stores as dead.
+	runtime.KeepAlive(b)
+}
+
+// Redact masks s with asterisks except for its first and last n characters,
+// for logging identifiers such as API keys and card numbers. Strings of at
+// most 2n characters are masked completely so that nothing is revealed.
+func Redact(s string, n int) string {
+	count := utf8.RuneCountInString(s)
+	if n < 0 {
+		n = 0
+	}
+	if count <= 2*n {
+		return strings.Repeat("*", count)
+	}
+	runes := []rune(s)
+	return string(runes[:n]) + strings.Repeat("*", count-2*n) + string(runes[count-n:])
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantTimeEqual(t *testing.T) {
+	assert.True(t, ConstantTimeEqual([]byte("secret"), []byte("secret")))
+	assert.True(t, ConstantTimeEqual(nil, []byte{}))
+	assert.False(t, ConstantTimeEqual([]byte("secret"), []byte("Secret")))
+	assert.False(t, ConstantTimeEqual([]byte("secret"), []byte("secret!")))
+}
+
+func TestSecureWipe(t *testing.T) {
+	b := []byte("secret key")
+	SecureWipe(b)
+	assert.Equal(t, make([]byte, 10), b)
+	SecureWipe(nil)
+}
+
+func TestRedact(t *testing.T) {
+	t.Run("ascii", func(t *testing.T) {
+		assert.Equal(t, "sk_l******************Xy9z", Redact("sk_live_1234567890abcdXy9z", 4))
+		assert.Equal(t, "4**************1", Redact("4111111111111111", 1))
+		assert.Equal(t, "******", Redact("secret", 0))
+	})
+
+	t.Run("short strings", func(t *testing.T) {
+		assert.Equal(t, "********", Redact("password", 4))
+		assert.Equal(t, "***", Redact("abc", 2))
+		assert.Equal(t, "", Redact("", 2))
+		assert.Equal(t, "***", Redact("abc", -1))
+	})
+
+	t.Run("unicode", func(t *testing.T) {
+		assert.Equal(t, "你****界", Redact("你好世界呀界", 1))
+	})
+}
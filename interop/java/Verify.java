@@ -0,0 +1,270 @@
+import java.math.BigInteger;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.security.KeyFactory;
+import java.security.PrivateKey;
+import java.security.PublicKey;
+import java.security.Security;
+import java.security.Signature;
+import java.security.interfaces.RSAPublicKey;
+import java.security.spec.MGF1ParameterSpec;
+import java.security.spec.PKCS8EncodedKeySpec;
+import java.security.spec.PSSParameterSpec;
+import java.security.spec.X509EncodedKeySpec;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Base64;
+import java.util.HexFormat;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+import javax.crypto.Cipher;
+import javax.crypto.spec.GCMParameterSpec;
+import javax.crypto.spec.IvParameterSpec;
+import javax.crypto.spec.OAEPParameterSpec;
+import javax.crypto.spec.PSource;
+import javax.crypto.spec.SecretKeySpec;
+import org.bouncycastle.asn1.ASN1Integer;
+import org.bouncycastle.asn1.ASN1OctetString;
+import org.bouncycastle.asn1.ASN1Sequence;
+import org.bouncycastle.crypto.engines.SM2Engine;
+import org.bouncycastle.jcajce.provider.asymmetric.util.ECUtil;
+import org.bouncycastle.jce.provider.BouncyCastleProvider;
+
+/**
+ * Decrypts and verifies the dongle outputs of ../fixtures.json with the JCE,
+ * using Bouncy Castle for SM2 and SM4. Requires Java 17 or later:
+ *
+ * <pre>
+ * javac -cp bcprov-jdk18on.jar Verify.java
+ * java -cp bcprov-jdk18on.jar:. Verify [path/to/fixtures.json]
+ * </pre>
+ */
+public class Verify {
+    private static final HexFormat HEX = HexFormat.of();
+
+    private static PrivateKey rsaPrivate;
+    private static PublicKey rsaPublic;
+    private static PrivateKey sm2Private;
+    private static PublicKey sm2Public;
+
+    @SuppressWarnings("unchecked")
+    public static void main(String[] args) throws Exception {
+        Security.addProvider(new BouncyCastleProvider());
+        String file = args.length > 0 ? args[0] : "../fixtures.json";
+        Map<String, Object> fixtures = (Map<String, Object>) new Json(Files.readString(Path.of(file))).parse();
+        Map<String, Map<String, String>> keys = (Map<String, Map<String, String>>) fixtures.get("keys");
+
+        KeyFactory rsa = KeyFactory.getInstance("RSA");
+        rsaPrivate = rsa.generatePrivate(new PKCS8EncodedKeySpec(pem(keys.get("rsa-2048").get("private"))));
+        rsaPublic = rsa.generatePublic(new X509EncodedKeySpec(pem(keys.get("rsa-2048").get("public"))));
+        KeyFactory ec = KeyFactory.getInstance("EC", "BC");
+        sm2Private = ec.generatePrivate(new PKCS8EncodedKeySpec(pem(keys.get("sm2").get("private"))));
+        sm2Public = ec.generatePublic(new X509EncodedKeySpec(pem(keys.get("sm2").get("public"))));
+
+        int failed = 0;
+        for (Object o : (List<Object>) fixtures.get("vectors")) {
+            Map<String, String> v = (Map<String, String>) o;
+            byte[] input = HEX.parseHex(v.get("input"));
+            String label = v.get("algorithm") + " " + v.get("operation") + " " + new String(input, StandardCharsets.UTF_8);
+            Boolean ok;
+            try {
+                ok = check(v, input);
+            } catch (Exception e) {
+                System.out.println("     " + e);
+                ok = false;
+            }
+            if (ok == null) {
+                System.out.println("SKIP " + label);
+                continue;
+            }
+            System.out.println((ok ? "OK   " : "FAIL ") + label);
+            if (!ok) {
+                failed++;
+            }
+        }
+        System.exit(failed > 0 ? 1 : 0);
+    }
+
+    /** Decrypts or verifies a vector, returning null for unknown algorithms. */
+    private static Boolean check(Map<String, String> v, byte[] input) throws Exception {
+        byte[] output = HEX.parseHex(v.get("output"));
+        switch (v.get("algorithm")) {
+            case "aes-128-cbc":
+            case "aes-256-cbc":
+                return Arrays.equals(input, decrypt("AES/CBC/PKCS5Padding", "AES", v, output));
+            case "aes-256-gcm":
+                return Arrays.equals(input, decrypt("AES/GCM/NoPadding", "AES", v, output));
+            case "sm4-cbc":
+                return Arrays.equals(input, decrypt("SM4/CBC/PKCS5Padding", "SM4", v, output));
+            case "sm4-gcm":
+                return Arrays.equals(input, decrypt("SM4/GCM/NoPadding", "SM4", v, output));
+            case "rsa-pkcs1v15": {
+                Cipher c = Cipher.getInstance("RSA/ECB/PKCS1Padding");
+                c.init(Cipher.DECRYPT_MODE, rsaPrivate);
+                return Arrays.equals(input, c.doFinal(output));
+            }
+            case "rsa-oaep-sha256": {
+                // OAEPWithSHA-256AndMGF1Padding would use MGF1 with SHA-1, dongle uses SHA-256 for both
+                Cipher c = Cipher.getInstance("RSA/ECB/OAEPPadding");
+                c.init(Cipher.DECRYPT_MODE, rsaPrivate,
+                        new OAEPParameterSpec("SHA-256", "MGF1", MGF1ParameterSpec.SHA256, PSource.PSpecified.DEFAULT));
+                return Arrays.equals(input, c.doFinal(output));
+            }
+            case "rsa-pkcs1v15-sha256":
+                return verify(Signature.getInstance("SHA256withRSA"), rsaPublic, input, output);
+            case "rsa-pss-sha256": {
+                // dongle signs with the largest salt the key allows
+                int bits = ((RSAPublicKey) rsaPublic).getModulus().bitLength();
+                Signature s = Signature.getInstance("RSASSA-PSS");
+                s.setParameter(new PSSParameterSpec("SHA-256", "MGF1", MGF1ParameterSpec.SHA256, (bits + 6) / 8 - 32 - 2, 1));
+                return verify(s, rsaPublic, input, output);
+            }
+            case "sm2-c1c3c2":
+                return Arrays.equals(input, decryptSm2(output));
+            case "sm2-asn1-c1c3c2": {
+                ASN1Sequence seq = ASN1Sequence.getInstance(output);
+                byte[] raw = concat(new byte[] {4},
+                        coordinate(ASN1Integer.getInstance(seq.getObjectAt(0)).getValue()),
+                        coordinate(ASN1Integer.getInstance(seq.getObjectAt(1)).getValue()),
+                        ASN1OctetString.getInstance(seq.getObjectAt(2)).getOctets(),
+                        ASN1OctetString.getInstance(seq.getObjectAt(3)).getOctets());
+                return Arrays.equals(input, decryptSm2(raw));
+            }
+            case "sm2-sm3":
+                // Bouncy Castle uses the default user identifier 1234567812345678, as dongle
+                return verify(Signature.getInstance("SM3withSM2", "BC"), sm2Public, input, output);
+            default:
+                return null;
+        }
+    }
+
+    private static byte[] decrypt(String transformation, String algorithm, Map<String, String> v, byte[] output) throws Exception {
+        Cipher c = Cipher.getInstance(transformation, algorithm.equals("SM4") ? "BC" : "SunJCE");
+        SecretKeySpec key = new SecretKeySpec(HEX.parseHex(v.get("key")), algorithm);
+        byte[] iv = HEX.parseHex(v.get("iv"));
+        if (transformation.contains("/GCM/")) {
+            c.init(Cipher.DECRYPT_MODE, key, new GCMParameterSpec(128, iv));
+            c.updateAAD(HEX.parseHex(v.get("aad")));
+        } else {
+            c.init(Cipher.DECRYPT_MODE, key, new IvParameterSpec(iv));
+        }
+        return c.doFinal(output);
+    }
+
+    private static byte[] decryptSm2(byte[] c1c3c2) throws Exception {
+        SM2Engine engine = new SM2Engine(SM2Engine.Mode.C1C3C2);
+        engine.init(false, ECUtil.generatePrivateKeyParameter(sm2Private));
+        return engine.processBlock(c1c3c2, 0, c1c3c2.length);
+    }
+
+    private static boolean verify(Signature s, PublicKey key, byte[] message, byte[] signature) throws Exception {
+        s.initVerify(key);
+        s.update(message);
+        return s.verify(signature);
+    }
+
+    private static byte[] pem(String pem) {
+        return Base64.getMimeDecoder().decode(pem.replaceAll("-----[A-Z ]+-----", ""));
+    }
+
+    /** Returns v as a 32-byte big-endian coordinate. */
+    private static byte[] coordinate(BigInteger v) {
+        byte[] b = v.toByteArray();
+        byte[] out = new byte[32];
+        int n = Math.min(b.length, 32);
+        System.arraycopy(b, b.length - n, out, 32 - n, n);
+        return out;
+    }
+
+    private static byte[] concat(byte[]... parts) {
+        int size = 0;
+        for (byte[] p : parts) {
+            size += p.length;
+        }
+        byte[] out = new byte[size];
+        int offset = 0;
+        for (byte[] p : parts) {
+            System.arraycopy(p, 0, out, offset, p.length);
+            offset += p.length;
+        }
+        return out;
+    }
+
+    /** A minimal JSON parser for the objects, arrays and strings of the fixtures. */
+    private static final class Json {
+        private final String s;
+        private int i;
+
+        Json(String s) {
+            this.s = s;
+        }
+
+        Object parse() {
+            skip();
+            char c = s.charAt(i);
+            if (c == '{') {
+                Map<String, Object> m = new LinkedHashMap<>();
+                i++;
+                for (skip(); s.charAt(i) != '}'; skip()) {
+                    String k = string();
+                    skip();
+                    expect(':');
+                    m.put(k, parse());
+                    skip();
+                    if (s.charAt(i) == ',') {
+                        i++;
+                    }
+                }
+                i++;
+                return m;
+            }
+            if (c == '[') {
+                List<Object> l = new ArrayList<>();
+                i++;
+                for (skip(); s.charAt(i) != ']'; skip()) {
+                    l.add(parse());
+                    skip();
+                    if (s.charAt(i) == ',') {
+                        i++;
+                    }
+                }
+                i++;
+                return l;
+            }
+            return string();
+        }
+
+        private String string() {
+            expect('"');
+            StringBuilder sb = new StringBuilder();
+            for (char c = s.charAt(i++); c != '"'; c = s.charAt(i++)) {
+                if (c == '\\') {
+                    c = s.charAt(i++);
+                    switch (c) {
+                        case 'n': c = '\n'; break;
+                        case 'r': c = '\r'; break;
+                        case 't': c = '\t'; break;
+                        case 'u': c = (char) Integer.parseInt(s.substring(i, i + 4), 16); i += 4; break;
+                        default: break;
+                    }
+                }
+                sb.append(c);
+            }
+            return sb.toString();
+        }
+
+        private void expect(char c) {
+            if (s.charAt(i++) != c) {
+                throw new IllegalArgumentException("expected " + c + " at offset " + (i - 1));
+            }
+        }
+
+        private void skip() {
+            while (Character.isWhitespace(s.charAt(i))) {
+                i++;
+            }
+        }
+    }
+}
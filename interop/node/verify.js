@@ -0,0 +1,76 @@
+// Decrypts and verifies the dongle outputs of ../fixtures.json with the
+// Node.js crypto module. SM2 and SM4-GCM are not available in Node.js and are
+// skipped.
+//
+//	node verify.js [path/to/fixtures.json]
+'use strict';
+
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+
+const file = process.argv[2] || path.join(__dirname, '..', 'fixtures.json');
+const fixtures = JSON.parse(fs.readFileSync(file, 'utf8'));
+const hex = (s) => Buffer.from(s || '', 'hex');
+
+function decryptBlock(v) {
+  const key = hex(v.key);
+  const out = hex(v.output);
+  if (v.algorithm.endsWith('-gcm')) {
+    const decipher = crypto.createDecipheriv(v.algorithm, key, hex(v.iv));
+    decipher.setAAD(hex(v.aad));
+    decipher.setAuthTag(out.subarray(out.length - 16));
+    return Buffer.concat([decipher.update(out.subarray(0, out.length - 16)), decipher.final()]);
+  }
+  const decipher = crypto.createDecipheriv(v.algorithm, key, hex(v.iv));
+  return Buffer.concat([decipher.update(out), decipher.final()]);
+}
+
+// Node.js refuses PKCS#1 v1.5 decryption since CVE-2023-46809, so decrypt
+// without padding and remove the 00 02 <nonzero random> 00 prefix by hand.
+function decryptPKCS1v15(v) {
+  const em = crypto.privateDecrypt(
+    { key: fixtures.keys[v.key].private, padding: crypto.constants.RSA_NO_PADDING }, hex(v.output));
+  const sep = em.indexOf(0, 2);
+  if (em[0] !== 0 || em[1] !== 2 || sep < 10) {
+    throw new Error('invalid PKCS#1 v1.5 padding');
+  }
+  return em.subarray(sep + 1);
+}
+
+const handlers = {
+  'aes-128-cbc': decryptBlock,
+  'aes-256-cbc': decryptBlock,
+  'aes-256-gcm': decryptBlock,
+  'sm4-cbc': decryptBlock,
+  'rsa-pkcs1v15': decryptPKCS1v15,
+  'rsa-oaep-sha256': (v) => crypto.privateDecrypt(
+    { key: fixtures.keys[v.key].private, padding: crypto.constants.RSA_PKCS1_OAEP_PADDING, oaepHash: 'sha256' },
+    hex(v.output)),
+  'rsa-pkcs1v15-sha256': (v) => crypto.verify('sha256', hex(v.input),
+    { key: fixtures.keys[v.key].public, padding: crypto.constants.RSA_PKCS1_PADDING }, hex(v.output)),
+  'rsa-pss-sha256': (v) => crypto.verify('sha256', hex(v.input),
+    { key: fixtures.keys[v.key].public, padding: crypto.constants.RSA_PKCS1_PSS_PADDING, saltLength: crypto.constants.RSA_PSS_SALTLEN_AUTO },
+    hex(v.output)),
+};
+
+let failed = 0;
+for (const v of fixtures.vectors) {
+  const label = `${v.algorithm} ${v.operation} ${Buffer.from(v.input, 'hex').toString('utf8')}`;
+  const handler = handlers[v.algorithm];
+  if (!handler) {
+    console.log(`SKIP ${label}`);
+    continue;
+  }
+  let ok;
+  try {
+    const result = handler(v);
+    ok = v.operation === 'sign' ? result === true : result.equals(hex(v.input));
+  } catch (err) {
+    ok = false;
+    console.log(`     ${err.message}`);
+  }
+  console.log(`${ok ? 'OK  ' : 'FAIL'} ${label}`);
+  if (!ok) failed++;
+}
+process.exit(failed ? 1 : 0);
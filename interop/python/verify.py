@@ -0,0 +1,143 @@
+"""Decrypts and verifies the dongle outputs of ../fixtures.json in Python.
+
+AES, SM4 and RSA use the cryptography package, SM2 uses gmssl:
+
+    pip install cryptography gmssl
+    python verify.py [path/to/fixtures.json]
+"""
+
+import base64
+import json
+import os
+import sys
+
+from cryptography.exceptions import InvalidSignature
+from cryptography.hazmat.primitives import hashes, padding, serialization
+from cryptography.hazmat.primitives.asymmetric import padding as rsa_padding
+from cryptography.hazmat.primitives.asymmetric.utils import decode_dss_signature
+from cryptography.hazmat.primitives.ciphers import Cipher, algorithms, modes
+from gmssl import sm2
+
+
+def der_read(data, offset):
+    """Returns the tag, the content and the end offset of a DER TLV."""
+    tag, size = data[offset], data[offset + 1]
+    offset += 2
+    if size & 0x80:
+        n = size & 0x7F
+        size = int.from_bytes(data[offset:offset + n], "big")
+        offset += n
+    return tag, data[offset:offset + size], offset + size
+
+
+def der_children(data):
+    """Returns the (tag, content) pairs of a DER constructed value."""
+    children, offset = [], 0
+    while offset < len(data):
+        tag, content, offset = der_read(data, offset)
+        children.append((tag, content))
+    return children
+
+
+def pem_body(pem):
+    """Returns the DER content of a PEM block."""
+    lines = [line for line in pem.strip().splitlines() if not line.startswith("-----")]
+    return base64.b64decode("".join(lines))
+
+
+def sm2_keys(key):
+    """Returns gmssl hex keys from the PKIX public and PKCS#8 private PEM."""
+    _, spki, _ = der_read(pem_body(key["public"]), 0)
+    point = der_children(spki)[1][1][1:]  # BIT STRING without unused bits byte
+    _, pkcs8, _ = der_read(pem_body(key["private"]), 0)
+    ec_key = der_children(der_children(pkcs8)[2][1])[0][1]  # OCTET STRING > ECPrivateKey
+    d = der_children(ec_key)[1][1]
+    return point[1:].hex(), d.hex()
+
+
+def sm2_c1c3c2_from_asn1(der):
+    """Converts SEQUENCE {x, y, hash, ciphertext} to raw C1 || C3 || C2."""
+    _, seq, _ = der_read(der, 0)
+    x, y, c3, c2 = (content for _, content in der_children(seq))
+    coord = lambda v: v.lstrip(b"\x00").rjust(32, b"\x00")
+    return coord(x) + coord(y) + c3 + c2
+
+
+def decrypt_block(v):
+    """Decrypts an AES or SM4 vector in CBC or GCM mode."""
+    key, iv, out = bytes.fromhex(v["key"]), bytes.fromhex(v["iv"]), bytes.fromhex(v["output"])
+    algorithm = algorithms.AES(key) if v["algorithm"].startswith("aes") else algorithms.SM4(key)
+    if v["algorithm"].endswith("-gcm"):
+        decryptor = Cipher(algorithm, modes.GCM(iv, out[-16:])).decryptor()
+        decryptor.authenticate_additional_data(bytes.fromhex(v["aad"]))
+        return decryptor.update(out[:-16]) + decryptor.finalize()
+    decryptor = Cipher(algorithm, modes.CBC(iv)).decryptor()
+    unpadder = padding.PKCS7(128).unpadder()
+    return unpadder.update(decryptor.update(out) + decryptor.finalize()) + unpadder.finalize()
+
+
+def main():
+    path = sys.argv[1] if len(sys.argv) > 1 else os.path.join(os.path.dirname(__file__), "..", "fixtures.json")
+    with open(path, encoding="utf-8") as f:
+        fixtures = json.load(f)
+
+    rsa_key = fixtures["keys"]["rsa-2048"]
+    rsa_private = serialization.load_pem_private_key(rsa_key["private"].encode(), None)
+    rsa_public = serialization.load_pem_public_key(rsa_key["public"].encode())
+    sm2_public, sm2_private = sm2_keys(fixtures["keys"]["sm2"])
+    # mode=1 selects the C1 || C3 || C2 order
+    sm2_crypt = sm2.CryptSM2(public_key=sm2_public, private_key=sm2_private, mode=1)
+    oaep = rsa_padding.OAEP(mgf=rsa_padding.MGF1(hashes.SHA256()), algorithm=hashes.SHA256(), label=None)
+    pss = rsa_padding.PSS(mgf=rsa_padding.MGF1(hashes.SHA256()), salt_length=rsa_padding.PSS.AUTO)
+
+    def verify_rsa(pad):
+        def verify(v):
+            try:
+                rsa_public.verify(bytes.fromhex(v["output"]), bytes.fromhex(v["input"]), pad, hashes.SHA256())
+                return True
+            except InvalidSignature:
+                return False
+
+        return verify
+
+    def verify_sm2(v):
+        # gmssl hashes with the default user identifier 1234567812345678, as dongle
+        r, s = decode_dss_signature(bytes.fromhex(v["output"]))
+        return sm2_crypt.verify_with_sm3("%064x%064x" % (r, s), bytes.fromhex(v["input"]))
+
+    handlers = {
+        "aes-128-cbc": decrypt_block,
+        "aes-256-cbc": decrypt_block,
+        "aes-256-gcm": decrypt_block,
+        "sm4-cbc": decrypt_block,
+        "sm4-gcm": decrypt_block,
+        "rsa-pkcs1v15": lambda v: rsa_private.decrypt(bytes.fromhex(v["output"]), rsa_padding.PKCS1v15()),
+        "rsa-oaep-sha256": lambda v: rsa_private.decrypt(bytes.fromhex(v["output"]), oaep),
+        "rsa-pkcs1v15-sha256": verify_rsa(rsa_padding.PKCS1v15()),
+        "rsa-pss-sha256": verify_rsa(pss),
+        # gmssl expects C1 without the 04 point prefix
+        "sm2-c1c3c2": lambda v: sm2_crypt.decrypt(bytes.fromhex(v["output"])[1:]),
+        "sm2-asn1-c1c3c2": lambda v: sm2_crypt.decrypt(sm2_c1c3c2_from_asn1(bytes.fromhex(v["output"]))),
+        "sm2-sm3": verify_sm2,
+    }
+
+    failed = 0
+    for v in fixtures["vectors"]:
+        label = "%s %s %s" % (v["algorithm"], v["operation"], bytes.fromhex(v["input"]).decode("utf-8"))
+        handler = handlers.get(v["algorithm"])
+        if handler is None:
+            print("SKIP", label)
+            continue
+        try:
+            result = handler(v)
+            ok = result is True if v["operation"] == "sign" else result == bytes.fromhex(v["input"])
+        except Exception as err:  # report every vector rather than stopping at the first failure
+            print("    ", err)
+            ok = False
+        print("OK  " if ok else "FAIL", label)
+        failed += not ok
+    sys.exit(1 if failed else 0)
+
+
+if __name__ == "__main__":
+    main()
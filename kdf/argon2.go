@@ -0,0 +1,25 @@
+package kdf
+
+import (
+	"golang.org/x/crypto/argon2"
+)
+
+// ByArgon2id derives the key with Argon2id (RFC 9106) using time passes over
+// memory KiB with the given number of threads. RFC 9106 recommends time=1,
+// memory=2097152 (2 GiB) and threads=4, or time=3, memory=65536 (64 MiB) and
+// threads=4 where memory is constrained.
+func (d Deriver) ByArgon2id(time, memory uint32, threads uint8) Deriver {
+	if d.Error = d.check("Argon2id", true); d.Error != nil {
+		return d
+	}
+	switch {
+	case time < 1:
+		d.Error = InvalidParamError{Algorithm: "Argon2id", Param: "time", Value: int(time)}
+		return d
+	case threads < 1:
+		d.Error = InvalidParamError{Algorithm: "Argon2id", Param: "threads", Value: int(threads)}
+		return d
+	}
+	d.dst = argon2.IDKey(d.src, d.salt, time, memory, threads, uint32(d.keyLength()))
+	return d
+}
@@ -0,0 +1,31 @@
+package kdf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByArgon2id(t *testing.T) {
+	salt := []byte("somesalt")
+	// Generated with the CLI of the reference implementation, phc-winner-argon2
+	for _, tc := range []struct {
+		time, memory uint32
+		threads      uint8
+		want         string
+	}{
+		{2, 64, 1, "068d62b26455936aa6ebe60060b0a65870dbfa3ddf8d41f7"},
+		{2, 64, 2, "350ac37222f436ccb5c0972f1ebd3bf6b958bf2071841362"},
+	} {
+		d := NewDeriver().FromPassword("password").WithSalt(salt).WithLength(24).ByArgon2id(tc.time, tc.memory, tc.threads)
+		assert.NoError(t, d.Error)
+		assert.Equal(t, tc.want, d.ToHexString())
+	}
+
+	d := NewDeriver().FromPassword("password").WithSalt(salt).ByArgon2id(0, 64, 1)
+	assert.Equal(t, InvalidParamError{Algorithm: "Argon2id", Param: "time", Value: 0}, d.Error)
+	d = NewDeriver().FromPassword("password").WithSalt(salt).ByArgon2id(1, 64, 0)
+	assert.Equal(t, InvalidParamError{Algorithm: "Argon2id", Param: "threads", Value: 0}, d.Error)
+	d = NewDeriver().FromPassword("password").ByArgon2id(1, 64, 1)
+	assert.Equal(t, EmptySaltError{Algorithm: "Argon2id"}, d.Error)
+}
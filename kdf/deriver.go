@@ -0,0 +1,140 @@
+// Package kdf derives keys from passwords and other secrets with PBKDF2, HKDF,
+// scrypt and Argon2id, so that the keys handed to dongle's ciphers can be
+// derived with dongle too:
+//
+//	key := kdf.NewDeriver().FromPassword("secret").WithSalt(salt).ByPbkdf2(600000, sha256.New).ToRawBytes()
+//
+// Keys are DefaultLength bytes long unless WithLength sets another length.
+package kdf
+
+import (
+	"github.com/dromara/dongle/coding"
+	"github.com/dromara/dongle/internal/utils"
+)
+
+// DefaultLength is the size in bytes of derived keys unless WithLength sets
+// it, which suits AES-256 and ChaCha20.
+const DefaultLength = 32
+
+// Deriver defines a Deriver struct.
+type Deriver struct {
+	src    []byte
+	salt   []byte
+	info   []byte
+	length int
+	dst    []byte
+	Error  error
+}
+
+// NewDeriver returns a new Deriver instance.
+func NewDeriver() Deriver {
+	return Deriver{}
+}
+
+// FromPassword derives from a password string.
+func (d Deriver) FromPassword(s string) Deriver {
+	utils.AuditShared()
+	d.src = utils.String2Bytes(s)
+	return d
+}
+
+// FromBytes derives from a byte slice, such as a password or the input key
+// material of HKDF.
+func (d Deriver) FromBytes(b []byte) Deriver {
+	utils.AuditShared()
+	d.src = b
+	return d
+}
+
+// WithSalt sets the salt. PBKDF2, scrypt and Argon2id require one, which should
+// be random and unique per password; it is optional for HKDF.
+func (d Deriver) WithSalt(salt []byte) Deriver {
+	d.salt = salt
+	return d
+}
+
+// WithInfo sets the context and application specific information of HKDF,
+// which binds the derived key to its use.
+func (d Deriver) WithInfo(info []byte) Deriver {
+	d.info = info
+	return d
+}
+
+// WithLength sets the size in bytes of the derived key, DefaultLength by
+// default.
+func (d Deriver) WithLength(n int) Deriver {
+	if n <= 0 {
+		d.Error = InvalidParamError{Param: "length", Value: n}
+		return d
+	}
+	d.length = n
+	return d
+}
+
+// ToRawBytes outputs as raw byte slice without encoding.
+func (d Deriver) ToRawBytes() []byte {
+	if len(d.dst) == 0 || d.Error != nil {
+		return []byte{}
+	}
+	return d.dst
+}
+
+// To outputs as a Result exposing the key in several encodings.
+func (d Deriver) To() coding.Result {
+	return coding.NewResult(d.ToRawBytes(), d.Error)
+}
+
+// ToBase64String outputs as base64 string.
+func (d Deriver) ToBase64String() string {
+	if len(d.dst) == 0 || d.Error != nil {
+		return ""
+	}
+	return coding.NewEncoder().FromBytes(d.dst).ByBase64().ToString()
+}
+
+// ToBase64Bytes outputs as base64 byte slice.
+func (d Deriver) ToBase64Bytes() []byte {
+	if len(d.dst) == 0 || d.Error != nil {
+		return []byte{}
+	}
+	return coding.NewEncoder().FromBytes(d.dst).ByBase64().ToBytes()
+}
+
+// ToHexString outputs as hex string.
+func (d Deriver) ToHexString() string {
+	if len(d.dst) == 0 || d.Error != nil {
+		return ""
+	}
+	return coding.NewEncoder().FromBytes(d.dst).ByHex().ToString()
+}
+
+// ToHexBytes outputs as hex byte slice.
+func (d Deriver) ToHexBytes() []byte {
+	if len(d.dst) == 0 || d.Error != nil {
+		return []byte{}
+	}
+	return coding.NewEncoder().FromBytes(d.dst).ByHex().ToBytes()
+}
+
+// keyLength returns the size of the key to derive.
+func (d Deriver) keyLength() int {
+	if d.length == 0 {
+		return DefaultLength
+	}
+	return d.length
+}
+
+// check returns the error that prevents deriving a key with algorithm, if
+// any. A salt is required when salted is true.
+func (d Deriver) check(algorithm string, salted bool) error {
+	if d.Error != nil {
+		return d.Error
+	}
+	if len(d.src) == 0 {
+		return EmptySrcError{Algorithm: algorithm}
+	}
+	if salted && len(d.salt) == 0 {
+		return EmptySaltError{Algorithm: algorithm}
+	}
+	return nil
+}
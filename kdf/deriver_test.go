@@ -0,0 +1,54 @@
+package kdf
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/errcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriver(t *testing.T) {
+	d := NewDeriver().FromPassword("passwd").WithSalt([]byte("salt")).ByPbkdf2(1, sha256.New)
+	assert.NoError(t, d.Error)
+	assert.Len(t, d.ToRawBytes(), DefaultLength)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc", d.ToHexString())
+	assert.Equal(t, []byte(d.ToHexString()), d.ToHexBytes())
+	assert.Equal(t, "VawEblbjCJ/sFpHCJUS2BflBhSFt3gRl5oudV8INrLw=", d.ToBase64String())
+	assert.Equal(t, []byte(d.ToBase64String()), d.ToBase64Bytes())
+	assert.Equal(t, d.ToHexString(), d.To().Hex())
+
+	assert.Equal(t, d.ToRawBytes(), NewDeriver().FromBytes([]byte("passwd")).WithSalt([]byte("salt")).ByPbkdf2(1, sha256.New).ToRawBytes())
+	assert.Len(t, NewDeriver().FromPassword("passwd").WithSalt([]byte("salt")).WithLength(16).ByPbkdf2(1, sha256.New).ToRawBytes(), 16)
+
+	t.Run("errors", func(t *testing.T) {
+		d := NewDeriver().FromPassword("passwd").WithLength(0).WithSalt([]byte("salt")).ByPbkdf2(1, sha256.New)
+		assert.Equal(t, InvalidParamError{Param: "length", Value: 0}, d.Error)
+		assert.Empty(t, d.ToRawBytes())
+		assert.Empty(t, d.ToHexString())
+		assert.Empty(t, d.ToHexBytes())
+		assert.Empty(t, d.ToBase64String())
+		assert.Empty(t, d.ToBase64Bytes())
+		assert.Equal(t, d.Error, d.To().Error())
+
+		d = NewDeriver().FromPassword("").WithSalt([]byte("salt")).ByPbkdf2(1, sha256.New)
+		assert.Equal(t, EmptySrcError{Algorithm: "PBKDF2"}, d.Error)
+		d = NewDeriver().FromPassword("passwd").ByPbkdf2(1, sha256.New)
+		assert.Equal(t, EmptySaltError{Algorithm: "PBKDF2"}, d.Error)
+		assert.Contains(t, d.Error.Error(), "WithSalt")
+	})
+
+	t.Run("error codes", func(t *testing.T) {
+		for code, err := range map[string]errcode.Coder{
+			"DGL-KDF-001": EmptySrcError{},
+			"DGL-KDF-002": EmptySaltError{},
+			"DGL-KDF-003": InvalidParamError{Algorithm: "scrypt", Param: "n"},
+			"DGL-KDF-004": DeriveError{Algorithm: "scrypt", Err: errors.New("too large")},
+		} {
+			assert.Equal(t, code, err.Code())
+			assert.Equal(t, "kdf", err.Fields()[errcode.FieldPackage])
+			assert.NotEmpty(t, err.Error())
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package kdf
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// EmptySrcError represents an error when no password or input key material
+// was given to derive a key from.
+type EmptySrcError struct {
+	Algorithm string // The key derivation function
+}
+
+// Error returns a formatted error message describing the missing input.
+func (e EmptySrcError) Error() string {
+	return fmt.Sprintf("kdf: %s password or key material cannot be empty", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-KDF-001.
+func (e EmptySrcError) Code() string {
+	return "DGL-KDF-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptySrcError) Fields() map[string]any {
+	return errcode.NewFields("kdf", e.Algorithm, "derive")
+}
+
+// EmptySaltError represents an error when a password based key derivation
+// function is used without a salt.
+type EmptySaltError struct {
+	Algorithm string // The key derivation function
+}
+
+// Error returns a formatted error message describing the missing salt.
+func (e EmptySaltError) Error() string {
+	return fmt.Sprintf("kdf: %s salt cannot be empty, please call WithSalt() first", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-KDF-002.
+func (e EmptySaltError) Code() string {
+	return "DGL-KDF-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e EmptySaltError) Fields() map[string]any {
+	return errcode.NewFields("kdf", e.Algorithm, "derive")
+}
+
+// InvalidParamError represents an error when a key length or a cost parameter
+// is out of the range the key derivation function accepts.
+type InvalidParamError struct {
+	Algorithm string // The key derivation function, empty for the key length
+	Param     string // The parameter name
+	Value     int    // The rejected value
+}
+
+// Error returns a formatted error message describing the invalid parameter.
+func (e InvalidParamError) Error() string {
+	if e.Algorithm == "" {
+		return fmt.Sprintf("kdf: invalid %s %d", e.Param, e.Value)
+	}
+	return fmt.Sprintf("kdf: invalid %s %s %d", e.Algorithm, e.Param, e.Value)
+}
+
+// Code returns the stable error code DGL-KDF-003.
+func (e InvalidParamError) Code() string {
+	return "DGL-KDF-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e InvalidParamError) Fields() map[string]any {
+	return errcode.NewFields("kdf", e.Algorithm, "derive", "param", e.Param, "value", e.Value)
+}
+
+// DeriveError represents an error when the key derivation function rejects
+// its parameters for a reason not covered by InvalidParamError, such as a
+// memory requirement too large for the platform.
+type DeriveError struct {
+	Algorithm string // The key derivation function
+	Err       error  // The underlying error that caused the failure
+}
+
+// Error returns a formatted error message describing the derivation failure.
+func (e DeriveError) Error() string {
+	return fmt.Sprintf("kdf: %s derivation failed: %v", e.Algorithm, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e DeriveError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code DGL-KDF-004.
+func (e DeriveError) Code() string {
+	return "DGL-KDF-004"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DeriveError) Fields() map[string]any {
+	return errcode.NewFields("kdf", e.Algorithm, "derive")
+}
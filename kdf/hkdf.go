@@ -0,0 +1,55 @@
+package kdf
+
+import (
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// ByHkdf derives the key with HKDF (RFC 5869) using HMAC with h, extracting a
+// pseudorandom key from the input key material and salt and expanding it with
+// the info. HKDF suits secrets that are already random, such as a master key
+// or a shared secret, not passwords.
+func (d Deriver) ByHkdf(h func() hash.Hash) Deriver {
+	if d.Error = d.check("HKDF", false); d.Error != nil {
+		return d
+	}
+	d.dst, d.Error = expand(hkdf.New(h, d.src, d.salt, d.info), h, d.keyLength())
+	return d
+}
+
+// ByHkdfExtract runs the extract step of HKDF alone, returning the
+// pseudorandom key of the input key material and salt. Its length is the size
+// of h, whatever WithLength sets.
+func (d Deriver) ByHkdfExtract(h func() hash.Hash) Deriver {
+	if d.Error = d.check("HKDF", false); d.Error != nil {
+		return d
+	}
+	d.dst = hkdf.Extract(h, d.src, d.salt)
+	return d
+}
+
+// ByHkdfExpand runs the expand step of HKDF alone, deriving the key from the
+// input, which must be a pseudorandom key such as the output of ByHkdfExtract,
+// and the info.
+func (d Deriver) ByHkdfExpand(h func() hash.Hash) Deriver {
+	if d.Error = d.check("HKDF", false); d.Error != nil {
+		return d
+	}
+	d.dst, d.Error = expand(hkdf.Expand(h, d.src, d.info), h, d.keyLength())
+	return d
+}
+
+// expand reads a key of length bytes from r, which HKDF limits to 255 times
+// the size of h.
+func expand(r io.Reader, h func() hash.Hash, length int) ([]byte, error) {
+	if length > 255*h().Size() {
+		return nil, InvalidParamError{Algorithm: "HKDF", Param: "length", Value: length}
+	}
+	key := make([]byte, length)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,47 @@
+package kdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByHkdf(t *testing.T) {
+	// RFC 5869 test case 1
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	prk := "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5"
+	okm := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+	d := NewDeriver().FromBytes(ikm).WithSalt(salt).WithInfo(info).WithLength(42).ByHkdf(sha256.New)
+	assert.Equal(t, okm, d.ToHexString())
+
+	t.Run("extract and expand", func(t *testing.T) {
+		d := NewDeriver().FromBytes(ikm).WithSalt(salt).WithLength(42).ByHkdfExtract(sha256.New)
+		assert.Equal(t, prk, d.ToHexString())
+
+		d = NewDeriver().FromBytes(d.ToRawBytes()).WithInfo(info).WithLength(42).ByHkdfExpand(sha256.New)
+		assert.Equal(t, okm, d.ToHexString())
+	})
+
+	t.Run("no salt", func(t *testing.T) {
+		d := NewDeriver().FromBytes(ikm).ByHkdf(sha256.New)
+		assert.NoError(t, d.Error)
+		assert.Len(t, d.ToRawBytes(), DefaultLength)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		d := NewDeriver().FromBytes(ikm).WithLength(255*32 + 1).ByHkdf(sha256.New)
+		assert.Equal(t, InvalidParamError{Algorithm: "HKDF", Param: "length", Value: 255*32 + 1}, d.Error)
+		d = NewDeriver().ByHkdf(sha256.New)
+		assert.Equal(t, EmptySrcError{Algorithm: "HKDF"}, d.Error)
+		d = NewDeriver().ByHkdfExtract(sha256.New)
+		assert.Equal(t, EmptySrcError{Algorithm: "HKDF"}, d.Error)
+		d = NewDeriver().ByHkdfExpand(sha256.New)
+		assert.Equal(t, EmptySrcError{Algorithm: "HKDF"}, d.Error)
+	})
+}
@@ -0,0 +1,22 @@
+package kdf
+
+import (
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ByPbkdf2 derives the key with PBKDF2 (RFC 8018) using HMAC with h, such as
+// sha256.New, and the given number of iterations. OWASP recommends at least
+// 600000 iterations with SHA-256.
+func (d Deriver) ByPbkdf2(iterations int, h func() hash.Hash) Deriver {
+	if d.Error = d.check("PBKDF2", true); d.Error != nil {
+		return d
+	}
+	if iterations < 1 {
+		d.Error = InvalidParamError{Algorithm: "PBKDF2", Param: "iterations", Value: iterations}
+		return d
+	}
+	d.dst = pbkdf2.Key(d.src, d.salt, iterations, d.keyLength(), h)
+	return d
+}
@@ -0,0 +1,26 @@
+package kdf
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/dromara/dongle/hash/sm3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByPbkdf2(t *testing.T) {
+	// RFC 6070 and RFC 7914 section 11
+	d := NewDeriver().FromPassword("password").WithSalt([]byte("salt")).WithLength(20).ByPbkdf2(4096, sha1.New)
+	assert.Equal(t, "4b007901b765489abead49d926f721d065a429c1", d.ToHexString())
+
+	d = NewDeriver().FromPassword("passwd").WithSalt([]byte("salt")).WithLength(64).ByPbkdf2(1, sha256.New)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783", d.ToHexString())
+
+	d = NewDeriver().FromPassword("passwd").WithSalt([]byte("salt")).ByPbkdf2(2, sm3.New)
+	assert.NoError(t, d.Error)
+	assert.Len(t, d.ToRawBytes(), DefaultLength)
+
+	d = NewDeriver().FromPassword("passwd").WithSalt([]byte("salt")).ByPbkdf2(0, sha256.New)
+	assert.Equal(t, InvalidParamError{Algorithm: "PBKDF2", Param: "iterations", Value: 0}, d.Error)
+}
@@ -0,0 +1,33 @@
+package kdf
+
+import (
+	"golang.org/x/crypto/scrypt"
+)
+
+// ByScrypt derives the key with scrypt (RFC 7914) using the CPU/memory cost n,
+// a power of two greater than 1, the block size r and the parallelization p.
+// n=32768, r=8, p=1 is a common interactive setting using 32 MiB of memory.
+func (d Deriver) ByScrypt(n, r, p int) Deriver {
+	if d.Error = d.check("scrypt", true); d.Error != nil {
+		return d
+	}
+	switch {
+	case n <= 1 || n&(n-1) != 0:
+		d.Error = InvalidParamError{Algorithm: "scrypt", Param: "n", Value: n}
+		return d
+	case r < 1:
+		d.Error = InvalidParamError{Algorithm: "scrypt", Param: "r", Value: r}
+		return d
+	case p < 1:
+		d.Error = InvalidParamError{Algorithm: "scrypt", Param: "p", Value: p}
+		return d
+	}
+	key, err := scrypt.Key(d.src, d.salt, n, r, p, d.keyLength())
+	if err != nil {
+		// The remaining limits bound r*p and the memory of n and r
+		d.Error = DeriveError{Algorithm: "scrypt", Err: err}
+		return d
+	}
+	d.dst = key
+	return d
+}
@@ -0,0 +1,40 @@
+package kdf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByScrypt(t *testing.T) {
+	// RFC 7914 section 12
+	d := NewDeriver().FromPassword("password").WithSalt([]byte("NaCl")).WithLength(64).ByScrypt(1024, 8, 16)
+	assert.Equal(t, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640", d.ToHexString())
+
+	for _, tc := range []struct {
+		n, r, p int
+		want    InvalidParamError
+	}{
+		{1, 8, 1, InvalidParamError{Algorithm: "scrypt", Param: "n", Value: 1}},
+		{1000, 8, 1, InvalidParamError{Algorithm: "scrypt", Param: "n", Value: 1000}},
+		{1024, 0, 1, InvalidParamError{Algorithm: "scrypt", Param: "r", Value: 0}},
+		{1024, 8, 0, InvalidParamError{Algorithm: "scrypt", Param: "p", Value: 0}},
+	} {
+		d := NewDeriver().FromPassword("password").WithSalt([]byte("NaCl")).ByScrypt(tc.n, tc.r, tc.p)
+		assert.Equal(t, tc.want, d.Error)
+	}
+
+	// Limits enforced by scrypt itself are reported with its reason
+	for _, tc := range []struct{ n, r, p int }{
+		{1024, 1 << 15, 1 << 15}, // r*p too large
+		{1 << 62, 8, 1},          // n*r too large
+	} {
+		d := NewDeriver().FromPassword("password").WithSalt([]byte("NaCl")).ByScrypt(tc.n, tc.r, tc.p)
+		assert.IsType(t, DeriveError{}, d.Error)
+		assert.Equal(t, "kdf: scrypt derivation failed: scrypt: parameters are too large", d.Error.Error())
+		assert.Equal(t, "DGL-KDF-004", d.Error.(DeriveError).Code())
+	}
+
+	d = NewDeriver().FromPassword("password").ByScrypt(1024, 8, 1)
+	assert.Equal(t, EmptySaltError{Algorithm: "scrypt"}, d.Error)
+}
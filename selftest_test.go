@@ -0,0 +1,56 @@
+package dongle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dromara/dongle/deprecation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	t.Run("all pass", func(t *testing.T) {
+		report := SelfTest()
+		require.Len(t, report.Results, len(selfTests))
+		for _, result := range report.Results {
+			assert.NoError(t, result.Err, result.Algorithm)
+		}
+		assert.True(t, report.Passed())
+		assert.NoError(t, report.Err())
+	})
+
+	t.Run("deprecation strict mode", func(t *testing.T) {
+		defer deprecation.Reset()
+		var warnings []deprecation.Warning
+		deprecation.SetHandler(func(w deprecation.Warning) { warnings = append(warnings, w) })
+		deprecation.SetStrict(true)
+
+		report := SelfTest()
+		assert.NoError(t, report.Err())
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("failures are reported", func(t *testing.T) {
+		old := selfTests
+		defer func() { selfTests = old }()
+		selfTests = []selfTest{
+			{"good", "616263", func() ([]byte, error) { return []byte("abc"), nil }},
+			{"wrong", "616263", func() ([]byte, error) { return []byte("abd"), nil }},
+			{"broken", "", func() ([]byte, error) { return nil, errors.New("broken") }},
+		}
+
+		report := SelfTest()
+		assert.False(t, report.Passed())
+		assert.NoError(t, report.Results[0].Err)
+		assert.Equal(t, KnownAnswerError{Algorithm: "wrong"}, report.Results[1].Err)
+		assert.EqualError(t, report.Results[2].Err, "broken")
+		assert.Equal(t, SelfTestError{Failed: []string{"wrong", "broken"}}, report.Err())
+	})
+}
+
+func TestSelfTestErrors(t *testing.T) {
+	assert.Equal(t, "dongle: self-test failed for MD5, SM2", SelfTestError{Failed: []string{"MD5", "SM2"}}.Error())
+	assert.Equal(t, "dongle: known-answer test failed for MD5", KnownAnswerError{Algorithm: "MD5"}.Error())
+}
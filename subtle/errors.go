@@ -0,0 +1,72 @@
+package subtle
+
+import (
+	"fmt"
+
+	"github.com/dromara/dongle/errcode"
+)
+
+// KeySizeError represents an error when a key does not have a size the
+// algorithm accepts.
+type KeySizeError struct {
+	Algorithm string // The algorithm name
+	Size      int    // The rejected key size in bytes
+}
+
+// Error returns a formatted error message describing the invalid key size.
+func (e KeySizeError) Error() string {
+	return fmt.Sprintf("subtle: invalid %s key size %d", e.Algorithm, e.Size)
+}
+
+// Code returns the stable error code DGL-SUBTLE-001.
+func (e KeySizeError) Code() string {
+	return "DGL-SUBTLE-001"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e KeySizeError) Fields() map[string]any {
+	return errcode.NewFields("subtle", e.Algorithm, "key", errcode.FieldKeySize, e.Size)
+}
+
+// NonceSizeError represents an error when a nonce does not have the size the
+// algorithm requires.
+type NonceSizeError struct {
+	Algorithm string // The algorithm name
+	Size      int    // The rejected nonce size in bytes
+}
+
+// Error returns a formatted error message describing the invalid nonce size.
+func (e NonceSizeError) Error() string {
+	return fmt.Sprintf("subtle: invalid %s nonce size %d", e.Algorithm, e.Size)
+}
+
+// Code returns the stable error code DGL-SUBTLE-002.
+func (e NonceSizeError) Code() string {
+	return "DGL-SUBTLE-002"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e NonceSizeError) Fields() map[string]any {
+	return errcode.NewFields("subtle", e.Algorithm, "", "nonce_size", e.Size)
+}
+
+// DecryptError represents an error when a ciphertext fails authentication,
+// because the key, nonce or additional data is wrong or it was modified.
+type DecryptError struct {
+	Algorithm string // The algorithm name
+}
+
+// Error returns a formatted error message describing the decryption failure.
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("subtle: %s message authentication failed", e.Algorithm)
+}
+
+// Code returns the stable error code DGL-SUBTLE-003.
+func (e DecryptError) Code() string {
+	return "DGL-SUBTLE-003"
+}
+
+// Fields returns the error metadata for structured logging.
+func (e DecryptError) Fields() map[string]any {
+	return errcode.NewFields("subtle", e.Algorithm, "decrypt")
+}
@@ -0,0 +1,167 @@
+// Package subtle gives direct access to the primitives behind the fluent API,
+// for hot paths where its allocations and string conversions dominate
+// profiles. The functions take and return byte slices only, append their
+// output to dst like the standard library AEADs and hashes, and allocate
+// nothing beyond the primitive itself when dst has enough capacity:
+//
+//	buf = subtle.Sha256Sum(buf[:0], data)
+//	ct, err := subtle.AesGcmSeal(key, nonce, aad, plaintext, ct[:0])
+//
+// They do no encoding, padding, key derivation or nonce generation: callers
+// must supply correctly sized keys and never reuse a nonce with the same key.
+// The fluent layer stays the ergonomic default.
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+
+	dongleCipher "github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/crypto/sm4"
+	"github.com/dromara/dongle/hash/sm3"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// GcmNonceSize is the nonce size of AES-GCM and SM4-GCM.
+const GcmNonceSize = 12
+
+// AesGcmSeal encrypts and authenticates plaintext and aad with AES-GCM under
+// a 16, 24 or 32-byte key and a 12-byte nonce, appends the ciphertext and tag
+// to dst and returns the result. dst and plaintext may overlap exactly.
+func AesGcmSeal(key, nonce, aad, plaintext, dst []byte) ([]byte, error) {
+	aead, err := aesGcm(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(dst, nonce, plaintext, aad), nil
+}
+
+// AesGcmOpen authenticates and decrypts a ciphertext of AesGcmSeal, appends
+// the plaintext to dst and returns the result.
+func AesGcmOpen(key, nonce, aad, ciphertext, dst []byte) ([]byte, error) {
+	aead, err := aesGcm(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, "AES-GCM", nonce, aad, ciphertext, dst)
+}
+
+// Sm4GcmSeal encrypts and authenticates plaintext and aad with SM4-GCM under
+// a 16-byte key and a 12-byte nonce, appends the ciphertext and tag to dst and
+// returns the result.
+func Sm4GcmSeal(key, nonce, aad, plaintext, dst []byte) ([]byte, error) {
+	aead, err := sm4Gcm(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(dst, nonce, plaintext, aad), nil
+}
+
+// Sm4GcmOpen authenticates and decrypts a ciphertext of Sm4GcmSeal, appends
+// the plaintext to dst and returns the result.
+func Sm4GcmOpen(key, nonce, aad, ciphertext, dst []byte) ([]byte, error) {
+	aead, err := sm4Gcm(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, "SM4-GCM", nonce, aad, ciphertext, dst)
+}
+
+// ChaCha20Poly1305Seal encrypts and authenticates plaintext and aad with
+// ChaCha20-Poly1305 under a 32-byte key and a 12-byte nonce, appends the
+// ciphertext and tag to dst and returns the result.
+func ChaCha20Poly1305Seal(key, nonce, aad, plaintext, dst []byte) ([]byte, error) {
+	aead, err := chaCha20Poly1305(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(dst, nonce, plaintext, aad), nil
+}
+
+// ChaCha20Poly1305Open authenticates and decrypts a ciphertext of
+// ChaCha20Poly1305Seal, appends the plaintext to dst and returns the result.
+func ChaCha20Poly1305Open(key, nonce, aad, ciphertext, dst []byte) ([]byte, error) {
+	aead, err := chaCha20Poly1305(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, "ChaCha20-Poly1305", nonce, aad, ciphertext, dst)
+}
+
+// Sha256Sum appends the SHA-256 digest of data to dst and returns the result.
+func Sha256Sum(dst, data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return append(dst, sum[:]...)
+}
+
+// Sha512Sum appends the SHA-512 digest of data to dst and returns the result.
+func Sha512Sum(dst, data []byte) []byte {
+	sum := sha512.Sum512(data)
+	return append(dst, sum[:]...)
+}
+
+// Sm3Sum appends the SM3 digest of data to dst and returns the result.
+func Sm3Sum(dst, data []byte) []byte {
+	h := sm3.New()
+	h.Write(data)
+	return h.Sum(dst)
+}
+
+// HmacSha256Sum appends the HMAC-SHA256 of data under key to dst and returns
+// the result.
+func HmacSha256Sum(dst, key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(dst)
+}
+
+// aesGcm returns AES-GCM under key after checking the key and nonce sizes.
+func aesGcm(key, nonce []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, KeySizeError{Algorithm: "AES-GCM", Size: len(key)}
+	}
+	if len(nonce) != GcmNonceSize {
+		return nil, NonceSizeError{Algorithm: "AES-GCM", Size: len(nonce)}
+	}
+	return cipher.NewGCM(block)
+}
+
+// sm4Gcm returns SM4-GCM under key after checking the key and nonce sizes.
+func sm4Gcm(key, nonce []byte) (cipher.AEAD, error) {
+	c := dongleCipher.NewSm4Cipher(dongleCipher.GCM)
+	c.SetKey(key)
+	block, err := sm4.NewBlock(c)
+	if err != nil {
+		return nil, KeySizeError{Algorithm: "SM4-GCM", Size: len(key)}
+	}
+	if len(nonce) != GcmNonceSize {
+		return nil, NonceSizeError{Algorithm: "SM4-GCM", Size: len(nonce)}
+	}
+	return cipher.NewGCM(block)
+}
+
+// chaCha20Poly1305 returns ChaCha20-Poly1305 under key after checking the key
+// and nonce sizes.
+func chaCha20Poly1305(key, nonce []byte) (cipher.AEAD, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, KeySizeError{Algorithm: "ChaCha20-Poly1305", Size: len(key)}
+	}
+	if len(nonce) != chacha20poly1305.NonceSize {
+		return nil, NonceSizeError{Algorithm: "ChaCha20-Poly1305", Size: len(nonce)}
+	}
+	return chacha20poly1305.New(key)
+}
+
+// open decrypts ciphertext with aead, reporting authentication failures as a
+// DecryptError.
+func open(aead cipher.AEAD, algorithm string, nonce, aad, ciphertext, dst []byte) ([]byte, error) {
+	out, err := aead.Open(dst, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, DecryptError{Algorithm: algorithm}
+	}
+	return out, nil
+}
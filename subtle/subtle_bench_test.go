@@ -0,0 +1,53 @@
+package subtle
+
+import (
+	"testing"
+
+	"github.com/dromara/dongle/crypto"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/hash"
+)
+
+func BenchmarkAesGcmSeal(b *testing.B) {
+	data := make([]byte, 1024)
+	dst := make([]byte, 0, len(data)+16)
+
+	b.Run("subtle", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			dst, _ = AesGcmSeal(key32, nonce, aad, data, dst[:0])
+		}
+	})
+
+	b.Run("fluent", func(b *testing.B) {
+		c := cipher.NewAesCipher(cipher.GCM)
+		c.SetKey(key32)
+		c.SetNonce(nonce)
+		c.SetAAD(aad)
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			crypto.NewEncrypter().FromBytes(data).ByAes(c).ToRawBytes()
+		}
+	})
+}
+
+func BenchmarkSha256Sum(b *testing.B) {
+	data := make([]byte, 64)
+	dst := make([]byte, 0, 32)
+
+	b.Run("subtle", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dst = Sha256Sum(dst[:0], data)
+		}
+	})
+
+	b.Run("fluent", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			hash.NewHasher().FromBytes(data).BySha2(256).ToHexString()
+		}
+	})
+}
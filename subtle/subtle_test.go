@@ -0,0 +1,117 @@
+package subtle
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dromara/dongle/crypto"
+	"github.com/dromara/dongle/crypto/cipher"
+	"github.com/dromara/dongle/errcode"
+	"github.com/dromara/dongle/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	key16     = bytes.Repeat([]byte{0x11}, 16)
+	key32     = bytes.Repeat([]byte{0x22}, 32)
+	nonce     = bytes.Repeat([]byte{0x33}, 12)
+	aad       = []byte("header")
+	plaintext = []byte("hello world")
+)
+
+func TestAEAD(t *testing.T) {
+	for name, tc := range map[string]struct {
+		key  []byte
+		seal func(key, nonce, aad, plaintext, dst []byte) ([]byte, error)
+		open func(key, nonce, aad, ciphertext, dst []byte) ([]byte, error)
+	}{
+		"AES-GCM":           {key32, AesGcmSeal, AesGcmOpen},
+		"SM4-GCM":           {key16, Sm4GcmSeal, Sm4GcmOpen},
+		"ChaCha20-Poly1305": {key32, ChaCha20Poly1305Seal, ChaCha20Poly1305Open},
+	} {
+		t.Run(name, func(t *testing.T) {
+			prefix := []byte("prefix")
+			ct, err := tc.seal(tc.key, nonce, aad, plaintext, prefix)
+			require.NoError(t, err)
+			assert.Equal(t, prefix, ct[:len(prefix)])
+			assert.Len(t, ct, len(prefix)+len(plaintext)+16)
+
+			pt, err := tc.open(tc.key, nonce, aad, ct[len(prefix):], nil)
+			require.NoError(t, err)
+			assert.Equal(t, plaintext, pt)
+
+			// In place, reusing the buffer of the ciphertext
+			buf := append([]byte{}, ct[len(prefix):]...)
+			pt, err = tc.open(tc.key, nonce, aad, buf, buf[:0])
+			require.NoError(t, err)
+			assert.Equal(t, plaintext, pt)
+
+			_, err = tc.open(tc.key, nonce, []byte("other"), ct[len(prefix):], nil)
+			assert.Equal(t, DecryptError{Algorithm: name}, err)
+
+			_, err = tc.seal(tc.key[:5], nonce, aad, plaintext, nil)
+			assert.Equal(t, KeySizeError{Algorithm: name, Size: 5}, err)
+			_, err = tc.open(tc.key[:5], nonce, aad, ct, nil)
+			assert.Equal(t, KeySizeError{Algorithm: name, Size: 5}, err)
+			_, err = tc.seal(tc.key, nonce[:8], aad, plaintext, nil)
+			assert.Equal(t, NonceSizeError{Algorithm: name, Size: 8}, err)
+		})
+	}
+
+	t.Run("matches the fluent api", func(t *testing.T) {
+		c := cipher.NewAesCipher(cipher.GCM)
+		c.SetKey(key32)
+		c.SetNonce(nonce)
+		c.SetAAD(aad)
+		want := crypto.NewEncrypter().FromBytes(plaintext).ByAes(c).ToRawBytes()
+		got, err := AesGcmSeal(key32, nonce, aad, plaintext, nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		s := cipher.NewSm4Cipher(cipher.GCM)
+		s.SetKey(key16)
+		s.SetNonce(nonce)
+		s.SetAAD(aad)
+		want = crypto.NewEncrypter().FromBytes(plaintext).BySm4(s).ToRawBytes()
+		got, err = Sm4GcmSeal(key16, nonce, aad, plaintext, nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", hex.EncodeToString(Sha256Sum(nil, plaintext)))
+	assert.Equal(t, hash.NewHasher().FromBytes(plaintext).BySha2(512).ToRawBytes(), Sha512Sum(nil, plaintext))
+	assert.Equal(t, hash.NewHasher().FromBytes(plaintext).BySm3().ToRawBytes(), Sm3Sum(nil, plaintext))
+
+	mac := hmac.New(sha256.New, key16)
+	mac.Write(plaintext)
+	assert.Equal(t, mac.Sum(nil), HmacSha256Sum(nil, key16, plaintext))
+
+	// The digest is appended to dst
+	dst := Sha256Sum([]byte("prefix"), plaintext)
+	assert.Equal(t, "prefix", string(dst[:6]))
+	assert.Len(t, dst, 6+32)
+}
+
+func TestAllocs(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	assert.Zero(t, testing.AllocsPerRun(100, func() { buf = Sha256Sum(buf[:0], plaintext) }))
+	assert.Zero(t, testing.AllocsPerRun(100, func() { buf = Sha512Sum(buf[:0], plaintext) }))
+}
+
+func TestErrors(t *testing.T) {
+	for code, err := range map[string]errcode.Coder{
+		"DGL-SUBTLE-001": KeySizeError{},
+		"DGL-SUBTLE-002": NonceSizeError{},
+		"DGL-SUBTLE-003": DecryptError{},
+	} {
+		assert.Equal(t, code, err.Code())
+		assert.Equal(t, "subtle", err.Fields()[errcode.FieldPackage])
+		assert.NotEmpty(t, err.Error())
+	}
+}